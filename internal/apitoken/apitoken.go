@@ -0,0 +1,180 @@
+// Package apitoken manages the tokens that scripts and editor plugins use
+// to authenticate against Tilt's external HTTP API.
+//
+// Only a hash of each token is stored. The token itself is shown once,
+// when it's created.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+const fileName = "api_tokens.json"
+
+// Tokens have a recognizable prefix, so that they're easy to spot in
+// scripts and secret scanners.
+const prefix = "tilt_"
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+type Token struct {
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// What the token may do, e.g., viewer. Empty for tokens created before
+	// tokens had roles, which have full control.
+	Role string `json:"role,omitempty"`
+}
+
+type Store struct {
+	base xdg.Base
+
+	// Guards reads and writes of the token file from this process.
+	mu sync.Mutex
+}
+
+func NewStore(base xdg.Base) *Store {
+	return &Store{base: base}
+}
+
+// Creates a new token with the given name and role, and returns its secret value.
+func (s *Store) Create(name string, role string, now time.Time) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid token name %q: must be 1-63 letters, digits, '_', '.', or '-', starting with a letter or digit", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tokens {
+		if t.Name == name {
+			return "", fmt.Errorf("token %q already exists", name)
+		}
+	}
+
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	secret := prefix + base64.RawURLEncoding.EncodeToString(b)
+
+	tokens = append(tokens, Token{Name: name, Hash: hash(secret), CreatedAt: now.UTC(), Role: role})
+	err = s.write(tokens)
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// Lists tokens, sorted by name.
+func (s *Store) List() ([]Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Name < tokens[j].Name })
+	return tokens, nil
+}
+
+func (s *Store) Revoke(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	for i, t := range tokens {
+		if t.Name == name {
+			return s.write(append(tokens[:i], tokens[i+1:]...))
+		}
+	}
+	return fmt.Errorf("no token named %q", name)
+}
+
+// Returns the token matching the secret, if there is one.
+//
+// Reads the token file on every call, so that tokens created or revoked
+// with the CLI take effect without restarting Tilt.
+func (s *Store) Verify(secret string) (Token, bool, error) {
+	if !strings.HasPrefix(secret, prefix) {
+		return Token{}, false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return Token{}, false, err
+	}
+
+	h := []byte(hash(secret))
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare(h, []byte(t.Hash)) == 1 {
+			return t, true, nil
+		}
+	}
+	return Token{}, false, nil
+}
+
+func (s *Store) path() (string, error) {
+	return s.base.ConfigFile(fileName)
+}
+
+func (s *Store) read() ([]Token, error) {
+	p, err := s.path()
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	_, err = xdg.ReadJSONFile(p, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *Store) write(tokens []Token) error {
+	p, err := s.path()
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically, so that a concurrent Verify never sees a partial file.
+	return xdg.WriteFileAtomic(p, contents, 0600)
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
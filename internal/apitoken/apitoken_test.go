@@ -0,0 +1,105 @@
+package apitoken
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+var now = time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func TestCreateAndVerify(t *testing.T) {
+	s := newStore(t)
+
+	secret, err := s.Create("vscode", "viewer", now)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "tilt_"))
+
+	token, ok, err := s.Verify(secret)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "vscode", token.Name)
+	assert.Equal(t, now, token.CreatedAt)
+	assert.Equal(t, "viewer", token.Role)
+
+	_, ok, err = s.Verify(secret + "x")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = s.Verify("")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSecretNotStored(t *testing.T) {
+	s := newStore(t)
+
+	secret, err := s.Create("ci", "", now)
+	require.NoError(t, err)
+
+	p, err := s.path()
+	require.NoError(t, err)
+	contents, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), secret)
+
+	info, err := os.Stat(p)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestCreateDuplicate(t *testing.T) {
+	s := newStore(t)
+
+	_, err := s.Create("ci", "", now)
+	require.NoError(t, err)
+	_, err = s.Create("ci", "", now)
+	require.EqualError(t, err, `token "ci" already exists`)
+}
+
+func TestCreateInvalidName(t *testing.T) {
+	s := newStore(t)
+
+	_, err := s.Create("my token", "", now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid token name "my token"`)
+}
+
+func TestListAndRevoke(t *testing.T) {
+	s := newStore(t)
+
+	_, err := s.Create("vscode", "", now)
+	require.NoError(t, err)
+	ciSecret, err := s.Create("ci", "", now)
+	require.NoError(t, err)
+
+	tokens, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "ci", tokens[0].Name)
+	assert.Equal(t, "vscode", tokens[1].Name)
+
+	require.NoError(t, s.Revoke("ci"))
+	_, ok, err := s.Verify(ciSecret)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	tokens, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "vscode", tokens[0].Name)
+
+	require.EqualError(t, s.Revoke("ci"), `no token named "ci"`)
+}
+
+func newStore(t *testing.T) *Store {
+	f := tempdir.NewTempDirFixture(t)
+	return NewStore(xdg.FakeBase{Dir: f.Path()})
+}
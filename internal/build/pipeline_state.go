@@ -3,9 +3,15 @@ package build
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -21,6 +27,8 @@ type PipelineStep struct {
 	Name      string // for logging
 	StartTime time.Time
 	Duration  time.Duration // not populated until end of the step
+
+	span trace.Span
 }
 
 type Clock interface {
@@ -58,6 +66,13 @@ func (ps *PipelineState) End(ctx context.Context, err error) {
 	ps.curBuildStep = 0
 
 	if err != nil {
+		// If we bailed out in the middle of a step, the step is what failed.
+		step := ps.curPipelineStep()
+		if step.span != nil && step.span.IsRecording() {
+			step.span.RecordError(err)
+			step.span.SetStatus(codes.Error, err.Error())
+			step.span.End()
+		}
 		return
 	}
 
@@ -88,9 +103,14 @@ func (ps *PipelineState) curPipelineStep() PipelineStep {
 func (ps *PipelineState) StartPipelineStep(ctx context.Context, format string, a ...interface{}) {
 	l := logger.Get(ctx)
 	stepName := fmt.Sprintf(format, a...)
+	startTime := ps.c.Now()
+	span := tracer.StartPipelineStep(ctx, stepSpanName(format),
+		trace.WithTimestamp(startTime),
+		trace.WithAttributes(attribute.String("tilt.step", stepName)))
 	ps.pipelineSteps = append(ps.pipelineSteps, PipelineStep{
 		Name:      stepName,
-		StartTime: ps.c.Now(),
+		StartTime: startTime,
+		span:      span,
 	})
 	line := logger.Blue(l).Sprintf("STEP %d/%d", ps.curPipelineIndex(), ps.totalPipelineStepCount)
 	l.Infof("%s — %s", line, stepName)
@@ -101,6 +121,18 @@ func (ps *PipelineState) EndPipelineStep(ctx context.Context) {
 	elapsed := ps.c.Now().Sub(ps.curPipelineStep().StartTime)
 	logger.Get(ctx).Infof("")
 	ps.pipelineSteps[len(ps.pipelineSteps)-1].Duration = elapsed
+	if span := ps.curPipelineStep().span; span != nil {
+		span.End()
+	}
+}
+
+var stepNameArgRe = regexp.MustCompile(`:?\s*\[?%[a-z]\]?`)
+
+// Step names include image names and tags, so name the step's span
+// after the format instead (e.g., "Pushing" rather than "Pushing fe:tilt-123"),
+// so that tracing tools can group steps of the same kind.
+func stepSpanName(format string) string {
+	return strings.TrimSpace(stepNameArgRe.ReplaceAllString(format, ""))
 }
 
 func (ps *PipelineState) StartBuildStep(ctx context.Context, format string, a ...interface{}) {
@@ -9,7 +9,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
 
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -76,6 +79,34 @@ func TestPipelineMultistep(t *testing.T) {
 	assertSnapshot(t, out.String())
 }
 
+func TestPipelineStepSpans(t *testing.T) {
+	pt, r := tracer.NewRecordingPipelineTracer()
+	_, root := pt.Start(context.Background(), "update")
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(&bytes.Buffer{}))
+	ctx = tracer.ContextWithPipelineSpan(ctx, root)
+
+	ps := NewPipelineState(ctx, 2, fakeClock{})
+	ps.StartPipelineStep(ctx, "Building Dockerfile: [%s]", "gcr.io/fe")
+	ps.EndPipelineStep(ctx)
+	ps.StartPipelineStep(ctx, "Pushing %s", "gcr.io/fe:tilt-123")
+	err := fmt.Errorf("unauthorized")
+	ps.End(ctx, err)
+
+	spans := r.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "Building Dockerfile", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].StatusCode)
+	assert.Equal(t, "Pushing", spans[1].Name)
+	assert.Equal(t, "Pushing gcr.io/fe:tilt-123", spans[1].Attributes[0].Value.AsString())
+	assert.Equal(t, codes.Error, spans[1].StatusCode)
+}
+
+func TestStepSpanName(t *testing.T) {
+	assert.Equal(t, "Building Dockerfile", stepSpanName("Building Dockerfile: [%s]"))
+	assert.Equal(t, "Pushing", stepSpanName("Pushing %s"))
+	assert.Equal(t, "Deploying", stepSpanName("Deploying"))
+}
+
 func assertSnapshot(t *testing.T, output string) {
 	d1 := []byte(output)
 	gmPath := fmt.Sprintf("testdata/%s_master", t.Name())
@@ -5,27 +5,39 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/kballard/go-shellquote"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/cmd/util/editor"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
+	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type argsCmd struct {
-	streams genericclioptions.IOStreams
-	clear   bool
+	streams  genericclioptions.IOStreams
+	clear    bool
+	set      []string
+	unset    []string
+	fromFile string
+	whenUp   whenUpFlags
 }
 
 func newArgsCmd(streams genericclioptions.IOStreams) *argsCmd {
@@ -52,10 +64,25 @@ the editor defined by your TILT_EDITOR or EDITOR environment variables, or fall
 an OS-appropriate default.
 
 Note that Tiltfile arguments do not affect built-in Tilt args (i.e., the things that show up in "tilt up --help", such as "--legacy", "--port"), and they
-are defined after built-in args, following a "--".`,
+are defined after built-in args, following a "--".
+
+--set and --unset change single settings defined with config.define_*(),
+and keep the rest of the current args. --from-file reads the args from a
+file, in the same format as the editor.
+
+Before changing the args, checks them against the settings the Tiltfile
+defines, so that a typo doesn't break the next Tiltfile reload.
+
+With --when-up, args set while Tilt is starting are retried until Tilt is up.`,
 		Example: `# Set new args
 tilt args frontend_service backend_service -- --debug on
 
+# Change one setting, and keep the rest
+tilt args --set env=staging --unset debug
+
+# Read the args from a file
+tilt args --from-file ./dev-args.txt
+
 # Edit the current args
 tilt args
 
@@ -65,6 +92,10 @@ EDITOR=nano tilt args`,
 
 	addConnectServerFlags(cmd)
 	cmd.Flags().BoolVar(&c.clear, "clear", false, "Clear the Tiltfile args, as if you'd run tilt with no args")
+	cmd.Flags().StringArrayVar(&c.set, "set", nil, "Set a config setting, as key=value, and keep the other args. May be repeated")
+	cmd.Flags().StringArrayVar(&c.unset, "unset", nil, "Remove a config setting, and keep the other args. May be repeated")
+	cmd.Flags().StringVar(&c.fromFile, "from-file", "", "Read the args from a file, or - for stdin")
+	c.whenUp.addFlags(cmd)
 
 	return cmd
 }
@@ -97,25 +128,55 @@ func parseEditResult(b []byte) ([]string, error) {
 func (c *argsCmd) run(ctx context.Context, args []string) error {
 	ctx = logger.WithLogger(ctx, logger.NewLogger(logger.Get(ctx).Level(), c.streams.ErrOut))
 
-	ctrlclient, err := newClient(ctx)
-	if err != nil {
-		return err
-	}
-
+	var ctrlclient client.Client
 	var tf v1alpha1.Tiltfile
-	err = ctrlclient.Get(ctx, types.NamespacedName{Name: model.MainTiltfileManifestName.String()}, &tf)
+	err := c.whenUp.retry(ctx, c.streams.ErrOut, func() error {
+		var err error
+		ctrlclient, err = newClient(ctx)
+		if err != nil {
+			// Tilt writes the apiserver config when it starts.
+			return notUpError{err}
+		}
+
+		err = ctrlclient.Get(ctx, types.NamespacedName{Name: model.MainTiltfileManifestName.String()}, &tf)
+		if utilnet.IsConnectionRefused(err) || apierrors.IsNotFound(err) {
+			return notUpError{err}
+		}
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
+	batch := len(c.set) > 0 || len(c.unset) > 0
 	tags := make(engineanalytics.CmdTags)
 	if c.clear {
-		if len(args) != 0 {
+		if len(args) != 0 || batch || c.fromFile != "" {
 			return errors.New("--clear cannot be specified with other values")
 		}
 		args = nil
 		tags["clear"] = "true"
-	} else if len(args) == 0 {
+	} else if c.fromFile != "" {
+		if len(args) != 0 {
+			return errors.New("--from-file cannot be specified with args")
+		}
+		b, err := c.readFromFile()
+		if err != nil {
+			return err
+		}
+		args, err = parseEditResult(b)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", c.fromFile)
+		}
+		tags["from-file"] = "true"
+	} else if len(args) != 0 {
+		if batch {
+			return errors.New("--set and --unset cannot be specified with args")
+		}
+		tags["set"] = "true"
+	} else if batch {
+		args = tf.Spec.Args
+	} else {
 		input := fmt.Sprintf("# edit args for the running Tilt here\n%s\n", shellquote.Join(tf.Spec.Args...))
 		e := editor.NewDefaultEditor([]string{"TILT_EDITOR", "EDITOR"})
 		b, _, err := e.LaunchTempFile("", "", strings.NewReader(input))
@@ -128,8 +189,29 @@ func (c *argsCmd) run(ctx context.Context, args []string) error {
 			return err
 		}
 		tags["edit"] = "true"
-	} else {
-		tags["set"] = "true"
+	}
+
+	schema, hasSchema, err := configmap.GetArgsSchema(ctx, ctrlclient, tf.Name)
+	if err != nil {
+		return err
+	}
+
+	if batch {
+		if !hasSchema || !schema.Parsed {
+			return errors.New("--set and --unset need a Tiltfile that calls config.parse()")
+		}
+		args, err = applyArgEdits(schema, args, c.set, c.unset)
+		if err != nil {
+			return err
+		}
+		tags["batch"] = "true"
+	}
+
+	if hasSchema {
+		err = c.validate(ctx, ctrlclient, schema, args)
+		if err != nil {
+			return fmt.Errorf("%v\nThe args were not changed", err)
+		}
 	}
 
 	a := analytics.Get(ctx)
@@ -151,3 +233,143 @@ func (c *argsCmd) run(ctx context.Context, args []string) error {
 
 	return nil
 }
+
+func (c *argsCmd) readFromFile() ([]byte, error) {
+	if c.fromFile == "-" {
+		return io.ReadAll(c.streams.In)
+	}
+	return os.ReadFile(c.fromFile)
+}
+
+// Checks the args the same way the Tiltfile will, so that a typo
+// doesn't make the next Tiltfile reload fail.
+func (c *argsCmd) validate(ctx context.Context, ctrlclient client.Client, schema config.ArgsSchema, args []string) error {
+	if schema.Parsed {
+		return schema.Validate(args)
+	}
+
+	// Without config.parse(), the args are the resources to enable.
+	var list v1alpha1.UIResourceList
+	err := ctrlclient.List(ctx, &list)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	var names []string
+	for _, r := range list.Items {
+		if r.Name == model.MainTiltfileManifestName.String() {
+			continue
+		}
+		known[r.Name] = true
+		names = append(names, r.Name)
+	}
+
+	var unknown []string
+	for _, arg := range args {
+		if !known[arg] {
+			unknown = append(unknown, arg)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(names)
+		return fmt.Errorf("You specified some resources that could not be found: %s\nIs this a typo? Existing resources in Tiltfile: %s",
+			sliceutils.QuotedStringList(unknown), sliceutils.QuotedStringList(names))
+	}
+	return nil
+}
+
+// One setting in a list of args, with the tokens that set it.
+type argEntry struct {
+	// Empty for positional args.
+	name   string
+	tokens []string
+}
+
+// Applies --set and --unset to the args, keeping everything else.
+func applyArgEdits(schema config.ArgsSchema, args []string, set []string, unset []string) ([]string, error) {
+	positional, hasPositional := schema.PositionalSetting()
+
+	remove := make(map[string]bool)
+	var newFlags, newPositional []string
+	for _, kv := range set {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: must be key=value", kv)
+		}
+		if _, ok := schema.Setting(name); !ok {
+			return nil, unknownSettingError(schema, name)
+		}
+		remove[name] = true
+		if hasPositional && name == positional.Name {
+			newPositional = append(newPositional, value)
+		} else {
+			newFlags = append(newFlags, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	for _, name := range unset {
+		if _, ok := schema.Setting(name); !ok {
+			return nil, unknownSettingError(schema, name)
+		}
+		remove[name] = true
+	}
+
+	var result []string
+	var positionalArgs []string
+	for _, e := range splitArgs(schema, args) {
+		if e.name == "" {
+			if !hasPositional || !remove[positional.Name] {
+				positionalArgs = append(positionalArgs, e.tokens...)
+			}
+			continue
+		}
+		if !remove[e.name] {
+			result = append(result, e.tokens...)
+		}
+	}
+	result = append(result, newFlags...)
+
+	positionalArgs = append(positionalArgs, newPositional...)
+	for _, arg := range positionalArgs {
+		if strings.HasPrefix(arg, "-") {
+			result = append(result, "--")
+			break
+		}
+	}
+	return append(result, positionalArgs...), nil
+}
+
+// Splits args into settings, the same way config.parse() reads them.
+func splitArgs(schema config.ArgsSchema, args []string) []argEntry {
+	var result []argEntry
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			for _, rest := range args[i+1:] {
+				result = append(result, argEntry{tokens: []string{rest}})
+			}
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			result = append(result, argEntry{tokens: []string{arg}})
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		e := argEntry{name: name, tokens: []string{arg}}
+		setting, ok := schema.Setting(name)
+		if !hasValue && ok && setting.Type != "bool" && i+1 < len(args) {
+			i++
+			e.tokens = append(e.tokens, args[i])
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func unknownSettingError(schema config.ArgsSchema, name string) error {
+	var names []string
+	for _, s := range schema.Settings {
+		names = append(names, s.Name)
+	}
+	return fmt.Errorf("unknown setting %q. The Tiltfile defines: %s", name, sliceutils.QuotedStringList(names))
+}
@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alessio/shellescape"
 	"github.com/stretchr/testify/require"
@@ -13,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
+	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
@@ -55,6 +59,26 @@ func TestArgsNewValue(t *testing.T) {
 
 }
 
+func TestArgsWhenUp(t *testing.T) {
+	f := newServerFixture(t)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		createTiltfile(f, []string{"foo", "bar"})
+	}()
+
+	streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+	cmd := newArgsCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--when-up", "--", "--foo", "bar"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"--foo", "bar"}, getTiltfile(f).Spec.Args)
+	require.Contains(t, errOut.String(), "Waiting for Tilt")
+}
+
 func TestArgsClearAndNewValue(t *testing.T) {
 	f := newServerFixture(t)
 
@@ -178,6 +202,196 @@ func TestArgsEdit(t *testing.T) {
 	}
 }
 
+func TestArgsSet(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"--env", "dev", "--debug", "frontend"})
+	createArgsSchema(f, testArgsSchema)
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--set", "env=staging", "--unset", "debug"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"--env=staging", "frontend"}, getTiltfile(f).Spec.Args)
+	require.Equal(t, []analytics.CountEvent{
+		{Name: "cmd.args", Tags: map[string]string{"batch": "true"}, N: 1},
+	}, f.analytics.Counts)
+}
+
+func TestArgsSetUnknownSetting(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"--env", "dev"})
+	createArgsSchema(f, testArgsSchema)
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--set", "enf=staging"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown setting "enf"`)
+	require.Equal(t, []string{"--env", "dev"}, getTiltfile(f).Spec.Args)
+}
+
+func TestArgsSetWithoutConfigParse(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"foo"})
+	createArgsSchema(f, config.ArgsSchema{})
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--set", "env=staging"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "need a Tiltfile that calls config.parse()")
+}
+
+func TestArgsSetAndNewValue(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"foo"})
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--set", "env=staging", "--", "bar"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--set and --unset cannot be specified with args")
+}
+
+func TestArgsNewValueInvalid(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"--env", "dev"})
+	createArgsSchema(f, testArgsSchema)
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--", "--enf", "staging"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown flag: --enf")
+	require.Contains(t, err.Error(), "The args were not changed")
+	require.Equal(t, []string{"--env", "dev"}, getTiltfile(f).Spec.Args)
+}
+
+func TestArgsUnknownResource(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"frontend"})
+	createArgsSchema(f, config.ArgsSchema{})
+	for _, name := range []string{"frontend", "backend"} {
+		err := f.client.Create(f.ctx, &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		require.NoError(t, err)
+	}
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"frontend", "bakend"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `You specified some resources that could not be found: "bakend"`)
+	require.Equal(t, []string{"frontend"}, getTiltfile(f).Spec.Args)
+}
+
+func TestArgsFromFile(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"--env", "dev"})
+	createArgsSchema(f, testArgsSchema)
+
+	path := filepath.Join(t.TempDir(), "args.txt")
+	err := os.WriteFile(path, []byte("# staging args\n--env staging 'front end'\n"), 0600)
+	require.NoError(t, err)
+
+	cmd := newArgsCmd(genericclioptions.NewTestIOStreamsDiscard())
+	c := cmd.register()
+	err = c.Flags().Parse([]string{"--from-file", path, "--set", "debug=true"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"--env", "staging", "--debug=true", "front end"}, getTiltfile(f).Spec.Args)
+	require.Equal(t, []analytics.CountEvent{
+		{Name: "cmd.args", Tags: map[string]string{"from-file": "true", "batch": "true"}, N: 1},
+	}, f.analytics.Counts)
+}
+
+func TestArgsFromStdin(t *testing.T) {
+	f := newServerFixture(t)
+
+	createTiltfile(f, []string{"foo"})
+
+	streams, in, _, _ := genericclioptions.NewTestIOStreams()
+	in.WriteString("bar baz\n")
+	cmd := newArgsCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--from-file", "-"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"bar", "baz"}, getTiltfile(f).Spec.Args)
+}
+
+func TestApplyArgEdits(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		set      []string
+		unset    []string
+		expected []string
+		err      string
+	}{
+		{"set new", nil, []string{"env=prod"}, nil, []string{"--env=prod"}, ""},
+		{"set replaces", []string{"--env", "dev", "fe"}, []string{"env=prod"}, nil, []string{"--env=prod", "fe"}, ""},
+		{"set replaces equals", []string{"--env=dev"}, []string{"env=prod"}, nil, []string{"--env=prod"}, ""},
+		{"set list", []string{"--groups", "a", "--groups", "b"}, []string{"groups=c", "groups=d"}, nil, []string{"--groups=c", "--groups=d"}, ""},
+		{"unset bool", []string{"--debug", "fe"}, nil, []string{"debug"}, []string{"fe"}, ""},
+		{"unset keeps next arg", []string{"--debug", "fe", "--env", "dev"}, nil, []string{"env"}, []string{"--debug", "fe"}, ""},
+		{"set positional", []string{"fe", "be", "--debug"}, []string{"to-run=db"}, nil, []string{"--debug", "db"}, ""},
+		{"unset positional", []string{"fe", "--debug"}, nil, []string{"to-run"}, []string{"--debug"}, ""},
+		{"positional with dash", []string{"--", "-fe"}, []string{"env=prod"}, nil, []string{"--env=prod", "--", "-fe"}, ""},
+		{"missing equals", nil, []string{"env"}, nil, nil, `invalid --set "env": must be key=value`},
+		{"unknown setting", nil, nil, []string{"enf"}, nil, `unknown setting "enf"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := applyArgEdits(testArgsSchema, tc.args, tc.set, tc.unset)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+var testArgsSchema = config.ArgsSchema{
+	Parsed: true,
+	Settings: []config.ArgSetting{
+		{Name: "debug", Type: "bool"},
+		{Name: "env", Type: "string"},
+		{Name: "groups", Type: "list[string]"},
+		{Name: "to-run", Type: "list[string]", Positional: true},
+	},
+}
+
+func createArgsSchema(f *serverFixture, schema config.ArgsSchema) {
+	err := f.client.Create(f.ctx, configmap.NewArgsSchemaConfigMap(model.MainTiltfileManifestName.String(), schema))
+	require.NoError(f.T(), err)
+}
+
 func createTiltfile(f *serverFixture, args []string) {
 	tf := v1alpha1.Tiltfile{
 		ObjectMeta: metav1.ObjectMeta{
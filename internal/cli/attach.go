@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type attachCmd struct {
+	streams genericclioptions.IOStreams
+
+	host  string
+	token string
+	port  int
+}
+
+var _ tiltCmd = &attachCmd{}
+
+func newAttachCmd(streams genericclioptions.IOStreams) *attachCmd {
+	return &attachCmd{streams: streams}
+}
+
+func (c *attachCmd) name() model.TiltSubcommand { return "attach" }
+
+func (c *attachCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach --host HOST[:PORT]",
+		Short: "Attach to a Tilt session running on another machine",
+		Long: `Attach to a Tilt session running on another machine.
+
+Serves the remote session on a local port, as if Tilt were running here.
+The web UI, and CLI commands like 'tilt get', 'tilt logs', and
+'tilt trigger', all work against the remote session until you stop
+'tilt attach' with Ctrl-C.
+
+On the remote machine, start Tilt with 'tilt up --host 0.0.0.0', and
+create a token for this machine with 'tilt token create'. The token's
+role decides what you can do in the attached session.`,
+		Example: `# On the devbox
+tilt up --host 0.0.0.0
+tilt token create laptop
+
+# On the laptop
+tilt attach --host devbox:10350 --token tilt_...`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&c.host, "host", "", "Host of the remote Tilt session, as host:port or an http(s):// URL. The port defaults to 10350")
+	cmd.Flags().StringVar(&c.token, "token", os.Getenv("TILT_API_TOKEN"), "API token created on the remote machine with 'tilt token create'. Defaults to the TILT_API_TOKEN env variable")
+	cmd.Flags().IntVar(&c.port, "port", defaultWebPort, "Local port to serve the remote session on. Overrides TILT_PORT env variable.")
+	return cmd
+}
+
+func (c *attachCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.attach", make(engineanalytics.CmdTags).AsMap())
+	defer a.Flush(time.Second)
+
+	if c.host == "" {
+		return fmt.Errorf("--host is required")
+	}
+	if c.token == "" {
+		return fmt.Errorf("--token is required. Create one on the remote machine with 'tilt token create'")
+	}
+
+	remote, err := parseAttachHost(c.host)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkRemote(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	l, err := server.ProvideWebListener("localhost", model.WebPort(c.port))
+	if err != nil {
+		return err
+	}
+
+	localPort := l.Addr().(*net.TCPAddr).Port
+	localURL := fmt.Sprintf("http://localhost:%d", localPort)
+	s := &http.Server{
+		Handler: newAttachProxy(remote, c.token),
+
+		// blackhole any server errors
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// Point CLI commands at the remote apiserver. The local proxy adds the
+	// token, so the config doesn't need to store it.
+	tiltDevDir, err := dirs.UseTiltDevDir()
+	if err != nil {
+		return err
+	}
+	configAccess := server.ProvideConfigAccess(tiltDevDir)
+	apiServerName := model.ProvideAPIServerName(model.WebPort(localPort))
+	err = server.AddToAPIServerConfig(configAccess, apiServerName, &rest.Config{
+		Host: localURL + server.ExternalAPIPrefix + "/apiserver",
+	})
+	if err != nil {
+		return fmt.Errorf("writing tilt api configs: %v", err)
+	}
+	defer func() {
+		_ = server.RemoveFromAPIServerConfig(configAccess, apiServerName)
+	}()
+
+	_, _ = fmt.Fprintf(c.streams.Out, "Attached to Tilt at %s\n", remote)
+	_, _ = fmt.Fprintf(c.streams.Out, "Web UI: %s/\n", localURL)
+	if localPort != model.DefaultWebPort {
+		_, _ = fmt.Fprintf(c.streams.Out, "Run CLI commands with --port=%d to use the remote session.\n", localPort)
+	}
+	_, _ = fmt.Fprintln(c.streams.Out, "Press Ctrl-C to detach.")
+
+	<-ctx.Done()
+	return nil
+}
+
+// Makes sure the remote session is up and accepts the token before
+// pretending to be it.
+func (c *attachCmd) checkRemote(ctx context.Context, remote *url.URL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remote.String()+server.ExternalAPIPrefix+"/resources", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Could not connect to Tilt at %s: %v", remote, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("Tilt at %s didn't accept the token. Create one on that machine with 'tilt token create'", remote)
+	default:
+		return fmt.Errorf("Tilt at %s: unexpected status %s", remote, resp.Status)
+	}
+}
+
+// Parses --host, which may leave out the scheme or the port.
+func parseAttachHost(host string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --host %q: %v", host, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid --host %q: must be http or https", host)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid --host %q: missing host name", host)
+	}
+	if u.Port() == "" && u.Scheme == "http" {
+		u.Host = net.JoinHostPort(u.Hostname(), fmt.Sprintf("%d", model.DefaultWebPort))
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// Forwards every request to the remote session, signed with the token.
+//
+// Sends the remote Host header, in case the session is behind a reverse
+// proxy. The web UI's websocket passes a CSRF token, so it doesn't need
+// the origin to match.
+func newAttachProxy(remote *url.URL, token string) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(remote)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = remote.Host
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	proxy.ErrorLog = log.New(io.Discard, "", 0)
+	return proxy
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestAttach(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	t.Setenv("TILT_CONFIG", configPath)
+
+	var mu sync.Mutex
+	var auths []string
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		auths = append(auths, req.Header.Get("Authorization"))
+		mu.Unlock()
+		_, _ = fmt.Fprintf(w, "remote %s", req.URL.Path)
+	}))
+	defer remote.Close()
+
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newAttachCmd(streams)
+	cmd.register()
+	cmd.host = remote.URL
+	cmd.token = "tilt_secret"
+	cmd.port = port
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		done <- cmd.run(ctx, nil)
+	}()
+
+	localURL := fmt.Sprintf("http://localhost:%d/api/view", port)
+	var body string
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(localURL)
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body = string(b)
+		return true
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "remote /api/view", body)
+
+	mu.Lock()
+	assert.Equal(t, []string{"Bearer tilt_secret", "Bearer tilt_secret"}, auths)
+	mu.Unlock()
+
+	name := string(model.ProvideAPIServerName(model.WebPort(port)))
+	config, err := clientcmd.LoadFromFile(configPath)
+	require.NoError(t, err)
+	if assert.Contains(t, config.Clusters, name) {
+		assert.Equal(t, fmt.Sprintf("http://localhost:%d/api/ext/v1/apiserver", port), config.Clusters[name].Server)
+	}
+	assert.Contains(t, out.String(), "Attached to Tilt at "+remote.URL)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	config, err = clientcmd.LoadFromFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, config.Clusters, name)
+}
+
+func TestAttachBadToken(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer remote.Close()
+
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	cmd := newAttachCmd(streams)
+	cmd.register()
+	cmd.host = remote.URL
+	cmd.token = "tilt_wrong"
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	err := cmd.run(ctx, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "didn't accept the token")
+	}
+}
+
+func TestParseAttachHost(t *testing.T) {
+	for _, tc := range []struct {
+		host     string
+		expected string
+	}{
+		{"devbox", "http://devbox:10350"},
+		{"devbox:10351", "http://devbox:10351"},
+		{"https://tilt.example.com/", "https://tilt.example.com"},
+	} {
+		u, err := parseAttachHost(tc.host)
+		if assert.NoError(t, err, tc.host) {
+			assert.Equal(t, tc.expected, u.String())
+		}
+	}
+
+	_, err := parseAttachHost("ftp://devbox")
+	assert.Error(t, err)
+}
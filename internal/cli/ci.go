@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -11,17 +12,33 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
 type ciCmd struct {
 	fileName             string
 	outputSnapshotOnExit string
+	recordPath           string
+	report               runReportFlags
+	resourceTimeouts     []string
+	failFast             bool
+	teardown             string
 }
 
+// When 'tilt ci' runs 'tilt down' before exiting.
+const (
+	ciTeardownNever     = "never"
+	ciTeardownAlways    = "always"
+	ciTeardownOnSuccess = "on-success"
+	ciTeardownOnFailure = "on-failure"
+)
+
 func (c *ciCmd) name() model.TiltSubcommand { return "ci" }
 
 func (c *ciCmd) register() *cobra.Command {
@@ -37,6 +54,9 @@ Exits with failure if any build fails or any server crashes.
 Exits with success if all tasks have completed successfully
 and all servers are healthy.
 
+By default, exits as soon as anything fails. With --fail-fast=false,
+waits until every resource is ready or has failed, and reports all the failures.
+
 While Tilt is running, you can view the UI at %s:%d
 (configurable with --host and --port).
 
@@ -46,15 +66,32 @@ See blog post for additional information: https://blog.tilt.dev/2020/04/16/how-t
 
 	addStartServerFlags(cmd)
 	addDevServerFlags(cmd)
+	addOTLPEndpointFlag(cmd)
+	addLogFormatFlag(cmd)
 	addTiltfileFlag(cmd, &c.fileName)
 	addKubeContextFlag(cmd)
+	addDevEnvFlag(cmd)
 
 	cmd.Flags().BoolVar(&logActionsFlag, "logactions", false, "log all actions and state changes")
 	cmd.Flags().Lookup("logactions").Hidden = true
+	cmd.Flags().BoolVar(&logHistoryFlag, "log-history", true,
+		"If true, Tilt saves this session's logs to disk, so that you can read them after Tilt exits with 'tilt logs --previous'.")
+	addControllerWorkersFlag(cmd)
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "",
 		"If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().StringVar(&c.recordPath, "record", "",
+		"If specified, Tilt will record every update to its resources and logs to the specified path, to play back later with 'tilt replay'")
 	cmd.Flags().DurationVar(&ciTimeout, "timeout", model.CITimeoutDefault,
 		"Timeout to wait for CI to pass. Set to 0 for no timeout.")
+	cmd.Flags().DurationVar(&ciSettingsFlags.ReadinessTimeout, "readiness-timeout", 0,
+		"Timeout for each resource to become ready, measured from when its first update starts. Set to 0 for no timeout.")
+	cmd.Flags().StringArrayVar(&c.resourceTimeouts, "resource-timeout", nil,
+		"Readiness timeout for a single resource, as RESOURCE=DURATION. Overrides --readiness-timeout. May be repeated.")
+	cmd.Flags().BoolVar(&c.failFast, "fail-fast", true,
+		"Exit as soon as a resource fails. If false, wait for every resource and report all failures.")
+	cmd.Flags().StringVar(&c.teardown, "teardown", ciTeardownNever,
+		"When to run 'tilt down' before exiting: never, always, on-success, or on-failure")
+	c.report.addFlags(cmd)
 
 	return cmd
 }
@@ -64,6 +101,15 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 	a.Incr("cmd.ci", nil)
 	defer a.Flush(time.Second)
 
+	err := c.report.validate()
+	if err != nil {
+		return err
+	}
+	err = c.validateSettings()
+	if err != nil {
+		return err
+	}
+
 	deferred := logger.NewDeferredLogger(ctx)
 	ctx = redirectLogs(ctx, deferred)
 
@@ -79,6 +125,12 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
+	err = upDevEnv(ctx, xdg.NewTiltDevBase(), ctrltiltfile.ResolveFilename(c.fileName))
+	if err != nil {
+		deferred.SetOutput(deferred.Original())
+		return err
+	}
+
 	cmdCIDeps, err := wireCmdCI(ctx, a, "ci")
 	if err != nil {
 		deferred.SetOutput(deferred.Original())
@@ -93,15 +145,105 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 	if c.outputSnapshotOnExit != "" {
 		defer cmdCIDeps.Snapshotter.WriteSnapshot(ctx, c.outputSnapshotOnExit)
 	}
+	if c.recordPath != "" {
+		stopRecording, err := cmdCIDeps.Recorder.Start(ctx, c.recordPath)
+		if err != nil {
+			return err
+		}
+		defer stopRecording()
+	}
 
 	err = upper.Start(ctx, args, cmdCIDeps.TiltBuild,
 		c.fileName, store.TerminalModeStream, a.UserOpt(), cmdCIDeps.Token,
-		string(cmdCIDeps.CloudAddress))
+		string(cmdCIDeps.CloudAddress), logstore.Retention{})
 	if err == nil {
 		_, _ = fmt.Fprintln(colorable.NewColorableStdout(),
 			color.GreenString("SUCCESS. All workloads are healthy."))
 	}
+
+	reportErr := c.report.write(ctx, cmdCIDeps.Reporter, err)
+	if err == nil {
+		err = reportErr
+	}
+
+	if c.shouldTeardown(err) {
+		// The run's context may be canceled, and its logs go to the
+		// engine, which has stopped.
+		downCtx := logger.WithLogger(context.Background(), deferred.Original())
+		downErr := c.down(downCtx, a, args)
+		if err == nil {
+			err = downErr
+		} else if downErr != nil {
+			logger.Get(downCtx).Errorf("Error tearing down: %v", downErr)
+		}
+	}
 	return err
 }
 
+// Validates the flags and stores the settings for the engine.
+func (c *ciCmd) validateSettings() error {
+	switch c.teardown {
+	case ciTeardownNever, ciTeardownAlways, ciTeardownOnSuccess, ciTeardownOnFailure:
+	default:
+		return fmt.Errorf("invalid --teardown %q: must be one of %s, %s, %s, %s", c.teardown,
+			ciTeardownNever, ciTeardownAlways, ciTeardownOnSuccess, ciTeardownOnFailure)
+	}
+
+	timeouts, err := parseResourceTimeouts(c.resourceTimeouts)
+	if err != nil {
+		return err
+	}
+	ciSettingsFlags.ResourceTimeouts = timeouts
+	ciSettingsFlags.CollectAllFailures = !c.failFast
+	return nil
+}
+
+func (c *ciCmd) shouldTeardown(runErr error) bool {
+	switch c.teardown {
+	case ciTeardownAlways:
+		return true
+	case ciTeardownOnSuccess:
+		return runErr == nil
+	case ciTeardownOnFailure:
+		return runErr != nil
+	}
+	return false
+}
+
+func (c *ciCmd) down(ctx context.Context, a *analytics.TiltAnalytics, args []string) error {
+	logger.Get(ctx).Infof("Tearing down resources...")
+	downDeps, err := wireDownDeps(ctx, a, "ci")
+	if err != nil {
+		return err
+	}
+	down := &downCmd{fileName: c.fileName}
+	err = down.down(ctx, downDeps, args)
+	if err != nil || devEnvFlag == "" {
+		return err
+	}
+	return downDevEnv(ctx, xdg.NewTiltDevBase(), ctrltiltfile.ResolveFilename(c.fileName))
+}
+
+// Parses RESOURCE=DURATION pairs from --resource-timeout.
+func parseResourceTimeouts(specs []string) (map[string]time.Duration, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	timeouts := make(map[string]time.Duration, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --resource-timeout %q: must be RESOURCE=DURATION", spec)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --resource-timeout %q: %v", spec, err)
+		}
+		timeouts[name] = d
+	}
+	return timeouts, nil
+}
+
 var ciTimeout time.Duration
+var ciSettingsFlags model.CISettingsFlags
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestCIValidateSettings(t *testing.T) {
+	t.Cleanup(func() { ciSettingsFlags = model.CISettingsFlags{} })
+
+	c := &ciCmd{
+		teardown:         ciTeardownOnFailure,
+		resourceTimeouts: []string{"db=5m", "api=90s"},
+	}
+	require.NoError(t, c.validateSettings())
+	assert.Equal(t, map[string]time.Duration{
+		"db":  5 * time.Minute,
+		"api": 90 * time.Second,
+	}, ciSettingsFlags.ResourceTimeouts)
+	assert.True(t, ciSettingsFlags.CollectAllFailures)
+}
+
+func TestCIValidateSettingsErrors(t *testing.T) {
+	t.Cleanup(func() { ciSettingsFlags = model.CISettingsFlags{} })
+
+	c := &ciCmd{teardown: "sometimes", failFast: true}
+	assert.EqualError(t, c.validateSettings(),
+		`invalid --teardown "sometimes": must be one of never, always, on-success, on-failure`)
+
+	c = &ciCmd{teardown: ciTeardownNever, resourceTimeouts: []string{"db"}}
+	assert.EqualError(t, c.validateSettings(),
+		`invalid --resource-timeout "db": must be RESOURCE=DURATION`)
+
+	c = &ciCmd{teardown: ciTeardownNever, resourceTimeouts: []string{"db=soon"}}
+	assert.EqualError(t, c.validateSettings(),
+		`invalid --resource-timeout "db=soon": time: invalid duration "soon"`)
+}
+
+func TestCIShouldTeardown(t *testing.T) {
+	runErr := errors.New("fe: does not compile")
+	for _, tc := range []struct {
+		teardown  string
+		onSuccess bool
+		onFailure bool
+	}{
+		{ciTeardownNever, false, false},
+		{ciTeardownAlways, true, true},
+		{ciTeardownOnSuccess, true, false},
+		{ciTeardownOnFailure, false, true},
+	} {
+		t.Run(tc.teardown, func(t *testing.T) {
+			c := &ciCmd{teardown: tc.teardown}
+			assert.Equal(t, tc.onSuccess, c.shouldTeardown(nil))
+			assert.Equal(t, tc.onFailure, c.shouldTeardown(runErr))
+		})
+	}
+}
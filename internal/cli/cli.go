@@ -64,8 +64,12 @@ up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compos
 	addCommand(rootCmd, &verifyInstallCmd{})
 	addCommand(rootCmd, &dockerPruneCmd{})
 	addCommand(rootCmd, newArgsCmd(streams))
+	addCommand(rootCmd, newAttachCmd(streams))
 	addCommand(rootCmd, &logsCmd{})
+	addCommand(rootCmd, newReplayCmd(streams))
 	addCommand(rootCmd, newDescribeCmd(streams))
+	addCommand(rootCmd, newExecCmd())
+	addCommand(rootCmd, newPortForwardCmd(streams))
 	addCommand(rootCmd, newGetCmd(streams))
 	addCommand(rootCmd, newExplainCmd(streams))
 	addCommand(rootCmd, newEditCmd(streams))
@@ -79,12 +83,22 @@ up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compos
 	addCommand(rootCmd, newEnableCmd())
 	addCommand(rootCmd, newDisableCmd())
 	addCommand(rootCmd, newTriggerCmd(streams))
+	addCommand(rootCmd, newRestartCmd(streams))
+	addCommand(rootCmd, newInputCmd(streams))
+	addCommand(rootCmd, newStatusCmd(streams))
+	addCommand(rootCmd, newLintCmd(streams))
+	addCommand(rootCmd, newVerifyCmd(streams))
+	addCommand(rootCmd, newInitCmd(streams))
+	addCommand(rootCmd, newStatsCmd(streams))
 
 	rootCmd.AddCommand(analytics.NewCommand())
 	rootCmd.AddCommand(newDumpCmd(rootCmd, streams))
 	rootCmd.AddCommand(newAlphaCmd(streams))
+	rootCmd.AddCommand(newTokenCmd(streams))
 	rootCmd.AddCommand(newLspCmd())
 	rootCmd.AddCommand(newSnapshotCmd())
+	rootCmd.AddCommand(newUpdateCmd(streams))
+	rootCmd.AddCommand(newGrantCmd(streams))
 
 	globalFlags := rootCmd.PersistentFlags()
 	globalFlags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
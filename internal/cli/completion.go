@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/completion"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Completes the names of the resources in the running Tilt.
+//
+// Commands that take one resource should set maxArgs to 1;
+// commands that take a list should set it to 0 (no limit).
+// Names already on the command line aren't suggested again.
+func resourceNameCompletion(maxArgs int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		client, err := newClient(cmd.Context())
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names, err := completeResourceNames(cmd.Context(), client, args, toComplete)
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeResourceNames(ctx context.Context, client ctrlclient.Client, args []string, toComplete string) ([]string, error) {
+	var list v1alpha1.UIResourceList
+	err := client.List(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := sets.NewString(args...)
+	var names []string
+	for _, r := range list.Items {
+		if seen.Has(r.Name) || !strings.HasPrefix(r.Name, toComplete) {
+			continue
+		}
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Completes API types and object names, the same way kubectl does,
+// against the running Tilt's apiserver.
+func apiObjectCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	getter, err := wireClientGetter(cmd.Context())
+	if err != nil {
+		cobra.CompDebugln(err.Error(), false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	f := cmdutil.NewFactory(getter)
+	return completion.ResourceTypeAndNameCompletionFunc(f)(cmd, args, toComplete)
+}
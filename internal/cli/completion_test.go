@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestCompleteResourceNames(t *testing.T) {
+	f := newServerFixture(t)
+	for _, name := range []string{"frontend", "backend", "fe-db"} {
+		err := f.client.Create(f.ctx, &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		require.NoError(t, err)
+	}
+
+	names, err := completeResourceNames(f.ctx, f.client, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend", "fe-db", "frontend"}, names)
+
+	names, err = completeResourceNames(f.ctx, f.client, nil, "f")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fe-db", "frontend"}, names)
+
+	names, err = completeResourceNames(f.ctx, f.client, []string{"frontend"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend", "fe-db"}, names)
+}
@@ -21,6 +21,8 @@ type createExtCmd struct {
 
 	repoName string
 	repoPath string
+	version  string
+	sha256   string
 }
 
 var _ tiltCmd = &createExtCmd{}
@@ -60,6 +62,9 @@ tilt create ext my-kubefwd --path=./kubefwd -- --namespaces=default
 
 # Installs the extension from the extension repo 'dev' under the path './cancel'
 tilt create ext cancel --repo=dev
+
+# Installs the extension only if its version is in the range
+tilt create ext cancel --version='>=1.0.0 <2.0.0'
 `,
 	}
 
@@ -67,6 +72,10 @@ tilt create ext cancel --repo=dev
 		"The name of the extension repo (list existing repos with 'tilt get repo')")
 	cmd.Flags().StringVar(&c.repoPath, "path", "",
 		"The path of the extension. If not specified, defaults to the extension name.")
+	cmd.Flags().StringVar(&c.version, "version", "",
+		"A semantic version range that the extension must satisfy, like '>=1.2.0 <2.0.0'.")
+	cmd.Flags().StringVar(&c.sha256, "sha256", "",
+		"The SHA-256 digest, in hex, that the extension directory must match.")
 
 	c.helper.addFlags(cmd)
 	c.cmd = cmd
@@ -108,6 +117,8 @@ func (c *createExtCmd) object(name string, extArgs []string) *v1alpha1.Extension
 			RepoName: repoName,
 			RepoPath: repoPath,
 			Args:     extArgs,
+			Version:  c.version,
+			Sha256:   c.sha256,
 		},
 	}
 }
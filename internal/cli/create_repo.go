@@ -18,7 +18,8 @@ import (
 type createRepoCmd struct {
 	helper *createHelper
 
-	ref string
+	ref         string
+	trustedKeys []string
 }
 
 var _ tiltCmd = &createRepoCmd{}
@@ -46,11 +47,14 @@ Tilt supports both git-hosted and local filesystem repositories.
 tilt create repo default https://github.com/tilt-dev/tilt-extensions
 tilt create repo default file:///home/user/src/tilt-extensions
 tilt create repo default https://github.com/tilt-dev/tilt-extensions --ref=SHA
+tilt create repo private https://github.com/my-org/tilt-extensions --trusted-key=BASE64_PUBLIC_KEY
 `,
 	}
 
 	cmd.Flags().StringVar(&c.ref, "ref", "",
 		"Git reference to sync the repository to.")
+	cmd.Flags().StringSliceVar(&c.trustedKeys, "trusted-key", nil,
+		"Ed25519 public key, in base64, that extensions in the repository must be signed with. May be repeated.")
 
 	c.helper.addFlags(cmd)
 
@@ -79,8 +83,9 @@ func (c *createRepoCmd) object(args []string) *v1alpha1.ExtensionRepo {
 			Name: name,
 		},
 		Spec: v1alpha1.ExtensionRepoSpec{
-			URL: url,
-			Ref: c.ref,
+			URL:         url,
+			Ref:         c.ref,
+			TrustedKeys: c.trustedKeys,
 		},
 	}
 }
@@ -58,6 +58,7 @@ func (c *deleteCmd) register() *cobra.Command {
 		Use:                   "delete ([-f FILENAME] | [-k DIRECTORY] | TYPE [(NAME | -l label | --all)])",
 		DisableFlagsInUseLine: true,
 		Short:                 "Delete resources by filenames, stdin, resources and names, or by resources and label selector",
+		ValidArgsFunction:     apiObjectCompletion,
 	}
 	c.cmd = cmd
 	c.deleteFlags.AddFlags(cmd)
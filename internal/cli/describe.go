@@ -23,9 +23,14 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/kubectl/pkg/cmd/describe"
@@ -34,12 +39,15 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type describeCmd struct {
 	options *describe.DescribeOptions
 	cmd     *cobra.Command
+
+	fetchWarnings func(name string) ([]resourceWarning, error)
 }
 
 var _ tiltCmd = &describeCmd{}
@@ -55,7 +63,8 @@ func newDescribeCmd(streams genericclioptions.IOStreams) *describeCmd {
 		IOStreams: streams,
 	}
 	return &describeCmd{
-		options: o,
+		options:       o,
+		fetchWarnings: fetchResourceWarnings,
 	}
 }
 
@@ -63,9 +72,22 @@ func (c *describeCmd) name() model.TiltSubcommand { return "describe" }
 
 func (c *describeCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                   "describe (-f FILENAME | TYPE [NAME_PREFIX | -l label] | TYPE/NAME)",
+		Use:                   "describe (RESOURCE_NAME | -f FILENAME | TYPE [NAME_PREFIX | -l label] | TYPE/NAME)",
 		DisableFlagsInUseLine: true,
 		Short:                 "Show details of a specific resource or group of resources",
+		Long: `Show details of a specific resource or group of resources.
+
+Given the name of a resource in your Tiltfile, shows everything Tilt knows
+about it in one place: its targets, recent builds, runtime status, pods and
+containers, port forwards, watched files, and recent warnings.
+
+Given an API type, shows the API objects of that type, like kubectl describe.
+`,
+		Example: `  # Show everything about the frontend resource
+  tilt describe frontend
+
+  # Show the Cmd API objects
+  tilt describe cmd`,
 	}
 	c.cmd = cmd
 	o := c.options
@@ -89,8 +111,57 @@ func (c *describeCmd) run(ctx context.Context, args []string) error {
 	}
 
 	f := cmdutil.NewFactory(getter)
+	if len(args) == 1 && len(o.FilenameOptions.Filenames) == 0 && o.Selector == "" {
+		ok, err := c.describeTiltResource(ctx, f, args[0])
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+
 	cmd := c.cmd
 	cmdutil.CheckErr(o.Complete(f, cmd, args))
 	cmdutil.CheckErr(o.Run())
 	return nil
 }
+
+// Describes the resource with the given name, if there is one.
+//
+// API types come first, so that names like `cmd` still describe
+// every object of that type.
+func (c *describeCmd) describeTiltResource(ctx context.Context, f cmdutil.Factory, name string) (bool, error) {
+	if strings.Contains(name, "/") {
+		return false, nil
+	}
+
+	mapper, err := f.ToRESTMapper()
+	if err != nil {
+		return false, err
+	}
+	_, err = mapper.ResourceFor(schema.GroupVersionResource{Resource: name})
+	if err == nil {
+		return false, nil
+	}
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var uir v1alpha1.UIResource
+	err = client.Get(ctx, types.NamespacedName{Name: name}, &uir)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	d, err := loadResourceDescription(ctx, client, uir, c.fetchWarnings)
+	if err != nil {
+		return false, err
+	}
+	_, err = fmt.Fprint(c.options.Out, d.String())
+	return true, err
+}
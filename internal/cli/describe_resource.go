@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgdescribe "k8s.io/kubectl/pkg/describe"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How many builds and warnings `tilt describe NAME` shows.
+const describeMaxBuilds = 5
+const describeMaxWarnings = 10
+
+// Everything Tilt knows about one resource, gathered from the objects
+// that make it up, for `tilt describe NAME`.
+type resourceDescription struct {
+	resource     v1alpha1.UIResource
+	discoveries  []v1alpha1.KubernetesDiscovery
+	portForwards []v1alpha1.PortForward
+	fileWatches  []v1alpha1.FileWatch
+
+	// Warning events and other warnings from the resource's logs.
+	warnings    []resourceWarning
+	warningsErr error
+}
+
+type resourceWarning struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+func loadResourceDescription(ctx context.Context, client ctrlclient.Client, uir v1alpha1.UIResource,
+	fetchWarnings func(name string) ([]resourceWarning, error)) (resourceDescription, error) {
+	d := resourceDescription{resource: uir}
+
+	var discoveries v1alpha1.KubernetesDiscoveryList
+	err := client.List(ctx, &discoveries)
+	if err != nil {
+		return d, err
+	}
+	for _, kd := range discoveries.Items {
+		if belongsToResource(&kd, uir.Name) {
+			d.discoveries = append(d.discoveries, kd)
+		}
+	}
+
+	var portForwards v1alpha1.PortForwardList
+	err = client.List(ctx, &portForwards)
+	if err != nil {
+		return d, err
+	}
+	for _, pf := range portForwards.Items {
+		if belongsToResource(&pf, uir.Name) {
+			d.portForwards = append(d.portForwards, pf)
+		}
+	}
+
+	var fileWatches v1alpha1.FileWatchList
+	err = client.List(ctx, &fileWatches)
+	if err != nil {
+		return d, err
+	}
+	for _, fw := range fileWatches.Items {
+		if belongsToResource(&fw, uir.Name) {
+			d.fileWatches = append(d.fileWatches, fw)
+		}
+	}
+
+	// The warnings come from the web server rather than the API server,
+	// so a failure leaves them out rather than failing the whole command.
+	d.warnings, d.warningsErr = fetchWarnings(uir.Name)
+	return d, nil
+}
+
+func belongsToResource(obj metav1.Object, name string) bool {
+	return obj.GetAnnotations()[v1alpha1.AnnotationManifest] == name
+}
+
+// Reads the most recent warnings in the resource's logs.
+// Kubernetes warning events are logged as warnings too.
+func fetchResourceWarnings(name string) ([]resourceWarning, error) {
+	query := url.Values{}
+	query.Set("resource", name)
+	query.Set("level", "warn")
+	query.Set("limit", fmt.Sprintf("%d", describeMaxWarnings))
+	res, err := http.Get(apiURL("logs/search?" + query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("searching logs: %s", res.Status)
+	}
+
+	var body struct {
+		Matches []resourceWarning `json:"matches"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	if err != nil {
+		return nil, fmt.Errorf("searching logs: %v", err)
+	}
+	return body.Matches, nil
+}
+
+func (d resourceDescription) String() string {
+	buf := &bytes.Buffer{}
+	out := tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	d.write(pkgdescribe.NewPrefixWriter(out))
+	_ = out.Flush()
+	return buf.String()
+}
+
+func (d resourceDescription) write(w pkgdescribe.PrefixWriter) {
+	uir := d.resource
+	s := uir.Status
+	readiness := webview.UIResourceReadiness(uir)
+
+	w.Write(pkgdescribe.LEVEL_0, "Name:\t%s\n", uir.Name)
+	w.Write(pkgdescribe.LEVEL_0, "Labels:\t%s\n", describeLabels(uir.Labels))
+	w.Write(pkgdescribe.LEVEL_0, "Status:\t%s\n", describeReadiness(readiness))
+	w.Write(pkgdescribe.LEVEL_0, "Update Status:\t%s\n", orNone(string(s.UpdateStatus)))
+	w.Write(pkgdescribe.LEVEL_0, "Runtime Status:\t%s\n", orNone(string(s.RuntimeStatus)))
+	w.Write(pkgdescribe.LEVEL_0, "Trigger Mode:\t%s\n", describeTriggerMode(model.TriggerMode(s.TriggerMode)))
+	if s.Waiting != nil {
+		w.Write(pkgdescribe.LEVEL_0, "Waiting:\t%s\n", s.Waiting.Reason)
+	}
+
+	w.Write(pkgdescribe.LEVEL_0, "Targets:\n")
+	if len(s.Specs) == 0 {
+		w.Write(pkgdescribe.LEVEL_1, "<none>\n")
+	}
+	for _, spec := range s.Specs {
+		liveUpdate := ""
+		if spec.HasLiveUpdate {
+			liveUpdate = "live update"
+		}
+		w.Write(pkgdescribe.LEVEL_1, "%s\t%s\t%s\n", spec.ID, spec.Type, liveUpdate)
+	}
+
+	if len(s.EndpointLinks) > 0 {
+		w.Write(pkgdescribe.LEVEL_0, "Endpoints:\n")
+		for _, link := range s.EndpointLinks {
+			w.Write(pkgdescribe.LEVEL_1, "%s\t%s\n", link.URL, link.Name)
+		}
+	}
+
+	d.writeBuilds(w)
+	d.writePods(w)
+	d.writePortForwards(w)
+	d.writeFileWatches(w)
+	d.writeWarnings(w)
+}
+
+func (d resourceDescription) writeBuilds(w pkgdescribe.PrefixWriter) {
+	s := d.resource.Status
+	w.Write(pkgdescribe.LEVEL_0, "Builds:\n")
+	if s.CurrentBuild != nil && !s.CurrentBuild.StartTime.IsZero() {
+		w.Write(pkgdescribe.LEVEL_1, "Current:\tstarted %s\n", describeTime(s.CurrentBuild.StartTime.Time))
+	}
+	if len(s.BuildHistory) == 0 {
+		w.Write(pkgdescribe.LEVEL_1, "<none>\n")
+		return
+	}
+
+	w.Write(pkgdescribe.LEVEL_1, "Start\tDuration\tResult\n")
+	w.Write(pkgdescribe.LEVEL_1, "-----\t--------\t------\n")
+	for i, b := range s.BuildHistory {
+		if i == describeMaxBuilds {
+			w.Write(pkgdescribe.LEVEL_1, "(%d more)\n", len(s.BuildHistory)-describeMaxBuilds)
+			break
+		}
+		result := "ok"
+		if b.Error != "" {
+			result = fmt.Sprintf("error: %s", firstLine(b.Error))
+		}
+		w.Write(pkgdescribe.LEVEL_1, "%s\t%s\t%s\n",
+			describeTime(b.StartTime.Time),
+			b.FinishTime.Sub(b.StartTime.Time).Round(time.Millisecond),
+			result)
+		for _, warning := range b.Warnings {
+			w.Write(pkgdescribe.LEVEL_2, "warning: %s\n", firstLine(warning))
+		}
+	}
+}
+
+func (d resourceDescription) writePods(w pkgdescribe.PrefixWriter) {
+	var pods []v1alpha1.Pod
+	for _, kd := range d.discoveries {
+		pods = append(pods, kd.Status.Pods...)
+	}
+	if len(pods) == 0 {
+		return
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreatedAt.After(pods[j].CreatedAt.Time)
+	})
+
+	w.Write(pkgdescribe.LEVEL_0, "Pods:\n")
+	for _, pod := range pods {
+		w.Write(pkgdescribe.LEVEL_1, "%s:\n", pod.Name)
+		w.Write(pkgdescribe.LEVEL_2, "Namespace:\t%s\n", pod.Namespace)
+		w.Write(pkgdescribe.LEVEL_2, "Status:\t%s\n", orNone(pod.Status))
+		w.Write(pkgdescribe.LEVEL_2, "Created:\t%s\n", describeTime(pod.CreatedAt.Time))
+		for _, e := range pod.Errors {
+			w.Write(pkgdescribe.LEVEL_2, "Error:\t%s\n", e)
+		}
+		w.Write(pkgdescribe.LEVEL_2, "Containers:\n")
+		for _, c := range pod.Containers {
+			w.Write(pkgdescribe.LEVEL_3, "%s:\n", c.Name)
+			w.Write(pkgdescribe.LEVEL_4, "Image:\t%s\n", c.Image)
+			w.Write(pkgdescribe.LEVEL_4, "State:\t%s\n", describeContainerState(c.State))
+			w.Write(pkgdescribe.LEVEL_4, "Ready:\t%t\n", c.Ready)
+			w.Write(pkgdescribe.LEVEL_4, "Restarts:\t%d\n", c.Restarts)
+		}
+	}
+}
+
+func (d resourceDescription) writePortForwards(w pkgdescribe.PrefixWriter) {
+	if len(d.portForwards) == 0 {
+		return
+	}
+	w.Write(pkgdescribe.LEVEL_0, "Port Forwards:\n")
+	for _, pf := range d.portForwards {
+		for _, f := range pf.Spec.Forwards {
+			host := f.Host
+			if host == "" {
+				host = "localhost"
+			}
+			w.Write(pkgdescribe.LEVEL_1, "%s:%d -> %d\tpod %s\n", host, f.LocalPort, f.ContainerPort, pf.Spec.PodName)
+		}
+	}
+}
+
+func (d resourceDescription) writeFileWatches(w pkgdescribe.PrefixWriter) {
+	if len(d.fileWatches) == 0 {
+		return
+	}
+	w.Write(pkgdescribe.LEVEL_0, "File Watches:\n")
+	for _, fw := range d.fileWatches {
+		w.Write(pkgdescribe.LEVEL_1, "%s:\n", fw.Name)
+		w.Write(pkgdescribe.LEVEL_2, "Paths:\n")
+		for _, p := range fw.Spec.WatchedPaths {
+			w.Write(pkgdescribe.LEVEL_3, "%s\n", p)
+		}
+		if len(fw.Spec.Ignores) > 0 {
+			w.Write(pkgdescribe.LEVEL_2, "Ignores:\n")
+			for _, ignore := range fw.Spec.Ignores {
+				patterns := "<all>"
+				if len(ignore.Patterns) > 0 {
+					patterns = strings.Join(ignore.Patterns, ", ")
+				}
+				w.Write(pkgdescribe.LEVEL_3, "%s:\t%s\n", ignore.BasePath, patterns)
+			}
+		}
+	}
+}
+
+func (d resourceDescription) writeWarnings(w pkgdescribe.PrefixWriter) {
+	w.Write(pkgdescribe.LEVEL_0, "Warnings:\n")
+	if d.warningsErr != nil {
+		w.Write(pkgdescribe.LEVEL_1, "<unavailable: %v>\n", d.warningsErr)
+		return
+	}
+	if len(d.warnings) == 0 {
+		w.Write(pkgdescribe.LEVEL_1, "<none>\n")
+		return
+	}
+	for _, warning := range d.warnings {
+		w.Write(pkgdescribe.LEVEL_1, "%s\t%s\n", describeTime(warning.Time), strings.TrimSpace(warning.Text))
+	}
+}
+
+func describeLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	var names []string
+	for l := range labels {
+		names = append(names, l)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func describeReadiness(r webview.ResourceReadiness) string {
+	if r.Reason == "" {
+		return string(r.Status)
+	}
+	if r.Message == "" {
+		return fmt.Sprintf("%s (%s)", r.Status, r.Reason)
+	}
+	return fmt.Sprintf("%s (%s: %s)", r.Status, r.Reason, firstLine(r.Message))
+}
+
+func describeTriggerMode(tm model.TriggerMode) string {
+	update := "manual"
+	if tm.AutoOnChange() {
+		update = "auto"
+	}
+	start := "manual"
+	if tm.AutoInitial() {
+		start = "auto"
+	}
+	return fmt.Sprintf("updates %s, starts %s", update, start)
+}
+
+func describeContainerState(state v1alpha1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return fmt.Sprintf("running since %s", describeTime(state.Running.StartedAt.Time))
+	case state.Waiting != nil:
+		return fmt.Sprintf("waiting (%s)", state.Waiting.Reason)
+	case state.Terminated != nil:
+		return fmt.Sprintf("terminated (%s, exit code %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	}
+	return "<unknown>"
+}
+
+func describeTime(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,3 +32,112 @@ func TestDescribe(t *testing.T) {
 
 	assert.Contains(t, out.String(), `Name:         my-sleep`)
 }
+
+func TestDescribeResource(t *testing.T) {
+	f := newServerFixture(t)
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	annotations := map[string]string{v1alpha1.AnnotationManifest: "fe"}
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe", Labels: map[string]string{"web": "web"}},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusError,
+			RuntimeStatus: v1alpha1.RuntimeStatusPending,
+			Specs: []v1alpha1.UIResourceTargetSpec{
+				{ID: "image:fe", Type: v1alpha1.UIResourceTargetTypeImage, HasLiveUpdate: true},
+			},
+			BuildHistory: []v1alpha1.UIBuildTerminated{{
+				StartTime:  metav1.NewMicroTime(start),
+				FinishTime: metav1.NewMicroTime(start.Add(1500 * time.Millisecond)),
+				Error:      "exit status 1",
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	err = f.client.Create(f.ctx, &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe", Annotations: annotations},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{{UID: "abc", Namespace: "default"}},
+		},
+		Status: v1alpha1.KubernetesDiscoveryStatus{
+			Pods: []v1alpha1.Pod{{
+				Name:      "fe-abc",
+				Namespace: "default",
+				Status:    "CrashLoopBackOff",
+				Containers: []v1alpha1.Container{{
+					Name:     "fe",
+					Image:    "fe:tilt-123",
+					Restarts: 3,
+					State: v1alpha1.ContainerState{
+						Waiting: &v1alpha1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+				}},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	err = f.client.Create(f.ctx, &v1alpha1.PortForward{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe", Annotations: annotations},
+		Spec: v1alpha1.PortForwardSpec{
+			PodName:  "fe-abc",
+			Forwards: []v1alpha1.Forward{{LocalPort: 8080, ContainerPort: 80}},
+		},
+	})
+	require.NoError(t, err)
+
+	err = f.client.Create(f.ctx, &v1alpha1.FileWatch{
+		ObjectMeta: metav1.ObjectMeta{Name: "image:fe", Annotations: annotations},
+		Spec: v1alpha1.FileWatchSpec{
+			WatchedPaths: []string{"/src/fe"},
+			Ignores:      []v1alpha1.IgnoreDef{{BasePath: "/src/fe", Patterns: []string{"*.md"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	describe := newDescribeCmd(streams)
+	describe.fetchWarnings = func(name string) ([]resourceWarning, error) {
+		return []resourceWarning{{Time: start, Text: "Back-off restarting failed container\n"}}, nil
+	}
+	describe.register()
+
+	err = describe.run(f.ctx, []string{"fe"})
+	require.NoError(t, err)
+
+	s := out.String()
+	assert.Contains(t, s, "Name:            fe\n")
+	assert.Contains(t, s, "Labels:          web\n")
+	assert.Contains(t, s, "Status:          error (UpdateError: exit status 1)\n")
+	assert.Contains(t, s, "  image:fe  image  live update\n")
+	assert.Contains(t, s, "1.5s")
+	assert.Contains(t, s, "error: exit status 1\n")
+	assert.Contains(t, s, "  fe-abc:\n")
+	assert.Contains(t, s, "waiting (CrashLoopBackOff)\n")
+	assert.Contains(t, s, "  localhost:8080 -> 80  pod fe-abc\n")
+	assert.Contains(t, s, "      /src/fe\n")
+	assert.Contains(t, s, "/src/fe:  *.md\n")
+	assert.Contains(t, s, "Back-off restarting failed container\n")
+}
+
+func TestDescribeTypeTakesPrecedence(t *testing.T) {
+	f := newServerFixture(t)
+
+	// A resource named like a type doesn't hide the type.
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "cmd"}})
+	require.NoError(t, err)
+	err = f.client.Create(f.ctx, &v1alpha1.Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Spec:       v1alpha1.CmdSpec{Args: []string{"sleep", "1"}},
+	})
+	require.NoError(t, err)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	describe := newDescribeCmd(streams)
+	describe.register()
+
+	err = describe.run(f.ctx, []string{"cmd"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `Name:         my-sleep`)
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tilt-dev/tilt/internal/devenv"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+var devEnvFlag string
+
+// The environment that --dev-env provisioned, if any.
+var devEnvironment devenv.Environment
+
+func addDevEnvFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&devEnvFlag, "dev-env", os.Getenv("TILT_DEV_ENV"),
+		fmt.Sprintf("Where containers run. %q gives each developer a namespace on the current cluster; "+
+			"%q runs a command that provisions an environment. Created on 'tilt up' and deleted on 'tilt down'. "+
+			"Overrides TILT_DEV_ENV env variable.", devenv.BackendNamespace, devenv.BackendExecPrefix+"<command>"))
+}
+
+func ProvideDevEnvironment() devenv.Environment {
+	return devEnvironment
+}
+
+// Provisions the environment chosen with --dev-env, and points Tilt at it.
+//
+// Must run before the command's dependencies are wired, because they read
+// the kube context and namespace.
+func upDevEnv(ctx context.Context, base xdg.Base, tiltfilePath string) error {
+	if devEnvFlag == "" {
+		return nil
+	}
+
+	backend, err := devenv.NewBackend(devEnvFlag)
+	if err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(tiltfilePath)
+	if err != nil {
+		return err
+	}
+	req, err := devenv.NewRequest(absPath, kubeContextOverride)
+	if err != nil {
+		return err
+	}
+
+	logger.Get(ctx).Infof("Provisioning dev environment (%s) for %s/%s...", devEnvFlag, req.User, req.Project)
+	env, err := backend.Up(ctx, req)
+	if err != nil {
+		return fmt.Errorf("provisioning dev environment: %v", err)
+	}
+
+	// Record the environment first, so that 'tilt down' can delete it
+	// even if Tilt crashes.
+	err = devenv.WriteState(base, devenv.State{
+		Tiltfile:    absPath,
+		Backend:     devEnvFlag,
+		Request:     req,
+		Environment: env,
+	})
+	if err != nil {
+		return fmt.Errorf("saving dev environment: %v", err)
+	}
+
+	for _, ep := range env.Endpoints {
+		logger.Get(ctx).Infof("Dev environment endpoint %s: %s", ep.Name, ep.URL)
+	}
+	return useDevEnv(env)
+}
+
+// Points Tilt at the environment, unless a flag already chose something else.
+func useDevEnv(env devenv.Environment) error {
+	devEnvironment = env
+	if kubeContextOverride == "" {
+		kubeContextOverride = env.KubeContext
+	}
+	if namespaceOverride == "" {
+		namespaceOverride = env.Namespace
+	}
+	if env.DockerHost != "" && os.Getenv("DOCKER_HOST") == "" {
+		err := os.Setenv("DOCKER_HOST", env.DockerHost)
+		if err != nil {
+			return err
+		}
+	}
+	return env.Setenv()
+}
+
+// Points Tilt at the environment that 'tilt up' provisioned for the project,
+// if any, so that 'tilt down' deletes resources from it.
+func useDevEnvForDown(base xdg.Base, tiltfilePath string) error {
+	state, err := readDevEnvState(base, tiltfilePath)
+	if err != nil || state == nil {
+		return err
+	}
+	return useDevEnv(state.Environment)
+}
+
+// Deletes the environment that 'tilt up' provisioned for the project.
+func downDevEnv(ctx context.Context, base xdg.Base, tiltfilePath string) error {
+	state, err := readDevEnvState(base, tiltfilePath)
+	if err != nil || state == nil {
+		return err
+	}
+
+	backend, err := devenv.NewBackend(state.Backend)
+	if err != nil {
+		return err
+	}
+
+	logger.Get(ctx).Infof("Deleting dev environment (%s)...", state.Backend)
+	err = backend.Down(ctx, state.Request, state.Environment)
+	if err != nil {
+		return fmt.Errorf("deleting dev environment: %v", err)
+	}
+	return devenv.RemoveState(base, state.Tiltfile)
+}
+
+func readDevEnvState(base xdg.Base, tiltfilePath string) (*devenv.State, error) {
+	absPath, err := filepath.Abs(tiltfilePath)
+	if err != nil {
+		return nil, err
+	}
+	return devenv.ReadState(base, absPath)
+}
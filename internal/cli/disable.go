@@ -36,6 +36,7 @@ tilt disable frontend backend
 
 # disables all resources
 tilt disable --all`,
+		ValidArgsFunction: resourceNameCompletion(0),
 	}
 
 	cmd.Flags().StringSliceVarP(&c.labels, "labels", "l", c.labels, "Disable all resources with the specified labels")
@@ -2,21 +2,30 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/doctor"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type doctorCmd struct {
+	output string
 }
 
 func (c *doctorCmd) name() model.TiltSubcommand { return "doctor" }
@@ -25,21 +34,89 @@ func (c *doctorCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Print diagnostic information about the Tilt environment, for filing bug reports",
+		Long: `Print diagnostic information about the Tilt environment, for filing bug reports.
+
+Also checks for common problems that keep Tilt from working: inotify limits,
+the Docker context and API version, expired Kubernetes credentials, an
+unreachable local registry, clock skew with the cluster, and a busy web UI port.
+Each check passes, warns, or fails, with a suggestion for how to fix it.
+
+Exits with failure if any check fails.
+`,
 	}
 	addKubeContextFlag(cmd)
+	addStartServerFlags(cmd)
+	cmd.Flags().StringVarP(&c.output, "output", "o", "",
+		"If set to json, print the diagnostics and check results as JSON")
 	return cmd
 }
 
+// A group of diagnostic info, like the Docker or Kubernetes settings.
+type doctorSection struct {
+	Name   string        `json:"name"`
+	Fields []doctorField `json:"fields"`
+}
+
+type doctorField struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func (s *doctorSection) add(name string, v interface{}, err error) {
+	f := doctorField{Name: name}
+	if err != nil {
+		f.Error = err.Error()
+	} else {
+		f.Value = fmt.Sprintf("%s", v)
+	}
+	s.Fields = append(s.Fields, f)
+}
+
+type doctorReport struct {
+	Sections []doctorSection `json:"sections"`
+	Checks   []doctor.Result `json:"checks"`
+}
+
 func (c *doctorCmd) run(ctx context.Context, args []string) error {
 	analytics.Get(ctx).Incr("cmd.doctor", map[string]string{})
 	defer analytics.Get(ctx).Flush(time.Second)
 
-	fmt.Printf("Tilt: %s\n", buildStamp())
-	fmt.Printf("System: %s-%s\n", runtime.GOOS, runtime.GOARCH)
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("invalid --output %q: must be json", c.output)
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	report := c.diagnose(ctx)
+	if c.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(report)
+		if err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(os.Stdout, report)
+	}
+
+	if doctor.Failed(report.Checks) {
+		return fmt.Errorf("some checks failed")
+	}
+	return nil
+}
+
+func (c *doctorCmd) diagnose(ctx context.Context) doctorReport {
+	var report doctorReport
+	var checks []doctor.Check
+
+	tilt := doctorSection{}
+	tilt.add("Tilt", buildStamp(), nil)
+	tilt.add("System", fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH), nil)
+	report.Sections = append(report.Sections, tilt)
+	checks = append(checks, doctor.InotifyCheck("/proc"))
+
 	var localDocker, clusterDocker docker.Client
 	var localDockerErr, clusterDockerErr error
 	multipleClients := false
@@ -54,52 +131,23 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 		multipleClients = (localDockerErr != nil) != (clusterDockerErr != nil)
 	}
 
-	fmt.Println("---")
-	if multipleClients {
-		fmt.Println("Docker (cluster)")
-	} else {
-		fmt.Println("Docker")
-	}
-
-	if clusterDockerErr != nil {
-		printField("Host", nil, clusterDockerErr)
-	} else {
-		dockerEnv := clusterDocker.Env()
-		host := dockerEnv.DaemonHost()
-		if host == "" {
-			host = "[default]"
-		}
-		printField("Host", host, nil)
+	homeDir, _ := os.UserHomeDir()
+	dockerContext := doctor.DockerContextName(os.Getenv, homeDir)
+	dockerHost := os.Getenv("DOCKER_HOST")
 
-		version := clusterDocker.ServerVersion()
-		printField("Server Version", version.Version, nil)
-		printField("API Version", version.APIVersion, nil)
-
-		builderVersion := clusterDocker.BuilderVersion()
-		printField("Builder", builderVersion, nil)
+	clusterSection := doctorSection{Name: "Docker"}
+	if multipleClients {
+		clusterSection.Name = "Docker (cluster)"
 	}
+	addDockerFields(&clusterSection, clusterDocker, clusterDockerErr)
+	checks = append(checks, doctor.DockerCheck(clusterSection.Name, clusterDocker, clusterDockerErr, dockerContext, dockerHost))
 
+	lastDockerSection := &clusterSection
+	localSection := doctorSection{Name: "Docker (local)"}
 	if multipleClients {
-		fmt.Println("---")
-		fmt.Println("Docker (local)")
-
-		if localDockerErr != nil {
-			printField("Host", nil, localDockerErr)
-		} else {
-			dockerEnv := localDocker.Env()
-			host := dockerEnv.DaemonHost()
-			if host == "" {
-				host = "[default]"
-			}
-			printField("Host", host, nil)
-
-			version := localDocker.ServerVersion()
-			printField("Server Version", version.Version, nil)
-			printField("Version", version.APIVersion, nil)
-
-			builderVersion := localDocker.BuilderVersion()
-			printField("Builder", builderVersion, nil)
-		}
+		addDockerFields(&localSection, localDocker, localDockerErr)
+		lastDockerSection = &localSection
+		checks = append(checks, doctor.DockerCheck(localSection.Name, localDocker, localDockerErr, dockerContext, dockerHost))
 	}
 
 	// in theory, the env shouldn't matter since we're just calling the version subcommand,
@@ -117,76 +165,167 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 		if composeBuild != "" {
 			composeField += fmt.Sprintf(" (build %s)", composeBuild)
 		}
-		printField("Compose Version", composeField, nil)
+		lastDockerSection.add("Compose Version", composeField, nil)
 	}
 
-	fmt.Println("---")
-	fmt.Println("Kubernetes")
+	report.Sections = append(report.Sections, clusterSection)
+	if multipleClients {
+		report.Sections = append(report.Sections, localSection)
+	}
 
+	k8sSection := doctorSection{Name: "Kubernetes"}
 	env, err := wireEnv(ctx)
-	printField("Env", env, err)
+	k8sSection.add("Env", env, err)
 
 	kContext, err := wireKubeContext(ctx)
-	printField("Context", kContext, err)
+	k8sSection.add("Context", kContext, err)
 	clusterName, err := wireClusterName(ctx)
 	if clusterName == "" {
 		clusterName = "Unknown"
 	}
-	printField("Cluster Name", clusterName, err)
+	k8sSection.add("Cluster Name", clusterName, err)
 
 	ns, err := wireNamespace(ctx)
-	printField("Namespace", ns, err)
-
-	runtime, err := wireRuntime(ctx)
-	printField("Container Runtime", runtime, err)
+	k8sSection.add("Namespace", ns, err)
 
-	kVersion, err := wireK8sVersion(ctx)
-	printField("Version", kVersion, err)
+	containerRuntime, err := wireRuntime(ctx)
+	k8sSection.add("Container Runtime", containerRuntime, err)
 
-	registryDisplay, err := clusterLocalRegistryDisplay(ctx)
-	printField("Cluster Local Registry", registryDisplay, err)
+	kVersion, versionErr := wireK8sVersion(ctx)
+	k8sSection.add("Version", kVersion, versionErr)
 
-	fmt.Println("---")
-	fmt.Println("Thanks for seeing the Tilt Doctor!")
-	fmt.Println("Please send the info above when filing bug reports. 💗")
+	registry, err := clusterLocalRegistry(ctx)
+	registryDisplay := "none"
+	if registry != nil && !container.IsEmptyRegistry(registry) {
+		registryDisplay = fmt.Sprintf("%+v", registry)
+	}
+	k8sSection.add("Cluster Local Registry", registryDisplay, err)
+	report.Sections = append(report.Sections, k8sSection)
+
+	apiConfig, _ := wireK8sAPIConfig(ctx)
+	checks = append(checks, doctor.KubeAuthCheck(apiConfig.Config, versionErr, time.Now))
+
+	httpClient := &http.Client{Timeout: 3 * time.Second}
+	checks = append(checks, doctor.RegistryCheck(httpClient, registry))
+
+	var clusterHTTPClient *http.Client
+	var clusterURL string
+	restConfig, _ := wireK8sRESTConfig(ctx)
+	if restConfig.Config != nil {
+		clusterHTTPClient, err = rest.HTTPClientFor(restConfig.Config)
+		if err == nil {
+			clusterURL = strings.TrimSuffix(restConfig.Config.Host, "/")
+		}
+	}
+	checks = append(checks, doctor.ClockSkewCheck(clusterHTTPClient, clusterURL, time.Now))
 
-	fmt.Println("")
-	fmt.Println("The info below helps us understand how you're using Tilt so we can improve,")
-	fmt.Println("but is not required to ask for help.")
+	if port := int(provideWebPort()); port != 0 {
+		checks = append(checks, doctor.PortCheck(httpClient, string(provideWebHost()), port))
+	}
 
-	fmt.Println("---")
-	fmt.Println("Analytics Settings")
-	fmt.Println("--> (These results reflect your personal opt in/out status and may be overridden by an `analytics_settings` call in your Tiltfile)")
+	report.Checks = doctor.Run(ctx, checks)
 
 	a := analytics.Get(ctx)
-	opt := a.UserOpt()
-	fmt.Printf("- User Mode: %s\n", opt)
+	analyticsSection := doctorSection{Name: "Analytics Settings"}
+	analyticsSection.add("User Mode", a.UserOpt(), nil)
+	analyticsSection.add("Machine", a.MachineHash(), nil)
+	analyticsSection.add("Repo", a.GitRepoHash(), nil)
+	report.Sections = append(report.Sections, analyticsSection)
 
-	fmt.Printf("- Machine: %s\n", a.MachineHash())
-	fmt.Printf("- Repo: %s\n", a.GitRepoHash())
+	return report
+}
 
-	return nil
+func addDockerFields(s *doctorSection, client docker.Client, err error) {
+	if err != nil {
+		s.add("Host", nil, err)
+		return
+	}
+
+	dockerEnv := client.Env()
+	host := dockerEnv.DaemonHost()
+	if host == "" {
+		host = "[default]"
+	}
+	s.add("Host", host, nil)
+
+	version := client.ServerVersion()
+	s.add("Server Version", version.Version, nil)
+	s.add("API Version", version.APIVersion, nil)
+
+	builderVersion := client.BuilderVersion()
+	s.add("Builder", builderVersion, nil)
+}
+
+func printDoctorReport(w io.Writer, report doctorReport) {
+	for _, s := range report.Sections {
+		switch s.Name {
+		case "":
+			for _, f := range s.Fields {
+				_, _ = fmt.Fprintf(w, "%s: %s\n", f.Name, f.Value)
+			}
+			continue
+
+		case "Analytics Settings":
+			printDoctorChecks(w, report.Checks)
+
+			_, _ = fmt.Fprintln(w, "---")
+			_, _ = fmt.Fprintln(w, "Thanks for seeing the Tilt Doctor!")
+			_, _ = fmt.Fprintln(w, "Please send the info above when filing bug reports. 💗")
+
+			_, _ = fmt.Fprintln(w, "")
+			_, _ = fmt.Fprintln(w, "The info below helps us understand how you're using Tilt so we can improve,")
+			_, _ = fmt.Fprintln(w, "but is not required to ask for help.")
+
+			_, _ = fmt.Fprintln(w, "---")
+			_, _ = fmt.Fprintln(w, s.Name)
+			_, _ = fmt.Fprintln(w, "--> (These results reflect your personal opt in/out status and may be overridden by an `analytics_settings` call in your Tiltfile)")
+
+		default:
+			_, _ = fmt.Fprintln(w, "---")
+			_, _ = fmt.Fprintln(w, s.Name)
+		}
+
+		for _, f := range s.Fields {
+			printField(w, f)
+		}
+	}
 }
 
-func clusterLocalRegistryDisplay(ctx context.Context) (string, error) {
+var doctorStatusIcons = map[doctor.Status]string{
+	doctor.StatusPass: "✓",
+	doctor.StatusWarn: "!",
+	doctor.StatusFail: "✗",
+	doctor.StatusSkip: "-",
+}
+
+func printDoctorChecks(w io.Writer, results []doctor.Result) {
+	_, _ = fmt.Fprintln(w, "---")
+	_, _ = fmt.Fprintln(w, "Checks")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%s %s: %s\n", doctorStatusIcons[r.Status], r.Name, r.Message)
+		if r.Remediation != "" {
+			for _, line := range strings.Split(r.Remediation, "\n") {
+				_, _ = fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+}
+
+func clusterLocalRegistry(ctx context.Context) (*v1alpha1.RegistryHosting, error) {
 	kClient, err := wireK8sClient(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// blackhole any warnings
 	newCtx := logger.WithLogger(ctx, logger.NewDeferredLogger(ctx))
-	registry := kClient.LocalRegistry(newCtx)
-	if container.IsEmptyRegistry(registry) {
-		return "none", nil
-	}
-	return fmt.Sprintf("%+v", registry), nil
+	return kClient.LocalRegistry(newCtx), nil
 }
 
-func printField(name string, v interface{}, err error) {
-	if err != nil {
-		fmt.Printf("- %s: Error: %v\n", name, err)
+func printField(w io.Writer, f doctorField) {
+	if f.Error != "" {
+		_, _ = fmt.Fprintf(w, "- %s: Error: %s\n", f.Name, f.Error)
 	} else {
-		fmt.Printf("- %s: %s\n", name, v)
+		_, _ = fmt.Fprintf(w, "- %s: %s\n", f.Name, f.Value)
 	}
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -12,8 +13,10 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -22,6 +25,12 @@ import (
 type downCmd struct {
 	fileName         string
 	deleteNamespaces bool
+	deleteVolumes    bool
+	removeOrphans    bool
+	stopTimeout      *time.Duration
+	labels           []string
+	keepDevEnv       bool
+	devEnvBase       xdg.Base
 	downDepsProvider func(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (DownDeps, error)
 }
 
@@ -32,14 +41,14 @@ type dependencyNode struct {
 }
 
 func newDownCmd() *downCmd {
-	return &downCmd{downDepsProvider: wireDownDeps}
+	return &downCmd{downDepsProvider: wireDownDeps, devEnvBase: xdg.NewTiltDevBase()}
 }
 
 func (c *downCmd) name() model.TiltSubcommand { return "down" }
 
 func (c *downCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                   "down [<tilt flags>] [-- <Tiltfile args>]",
+		Use:                   "down [<tilt flags>] [-l LABEL...] [-- <Tiltfile args>]",
 		DisableFlagsInUseLine: true,
 		Short:                 "Delete resources created by 'tilt up'",
 		Long: `
@@ -47,10 +56,28 @@ Deletes resources specified in the Tiltfile
 
 Specify additional flags and arguments to control which resources are deleted.
 
+Like 'tilt up', the arguments select resources by name, unless the Tiltfile
+parses its own arguments. Use --label to select resources by label instead.
+For example, to tear down just the workers and keep the databases running:
+
+    tilt down --label=workers
+
 Namespaces are not deleted by default. Use --delete-namespaces to change that.
 
 Kubernetes resources with the annotation 'tilt.dev/down-policy: keep' are not deleted.
 
+Docker Compose projects are taken down with 'docker compose down'. If only some
+of a project's services are selected, just those services are removed, and
+the rest of the project keeps running. Volumes are not deleted by default.
+Use --delete-volumes to change that. Use --remove-orphans to also remove the
+containers of services that aren't in the project anymore, and --stop-timeout
+to change how long to wait for containers to stop. The Tiltfile can turn these
+on by default with docker_compose().
+
+If 'tilt up --dev-env' provisioned an environment for the project, resources are
+deleted from it, and then the environment itself is deleted. Use --keep-dev-env
+to change that. The environment is also kept when --label selects only some resources.
+
 For more complex cases, the Tiltfile has APIs to add additional flags and arguments to the Tilt CLI.
 These arguments can be scripted to define custom subsets of resources to delete.
 See https://docs.tilt.dev/tiltfile_config.html for examples.
@@ -60,6 +87,11 @@ See https://docs.tilt.dev/tiltfile_config.html for examples.
 	addTiltfileFlag(cmd, &c.fileName)
 	addKubeContextFlag(cmd)
 	cmd.Flags().BoolVar(&c.deleteNamespaces, "delete-namespaces", false, "delete namespaces defined in the Tiltfile (by default, don't)")
+	cmd.Flags().BoolVar(&c.deleteVolumes, "delete-volumes", false, "delete Docker Compose volumes (by default, don't)")
+	cmd.Flags().BoolVar(&c.removeOrphans, "remove-orphans", false, "remove containers of Docker Compose services that aren't in the project anymore (by default, don't)")
+	cmd.Flags().Var(optionalDuration{&c.stopTimeout}, "stop-timeout", "how long Docker Compose waits for containers to stop before killing them (by default, 10s)")
+	cmd.Flags().BoolVar(&c.keepDevEnv, "keep-dev-env", false, "don't delete the environment that 'tilt up --dev-env' provisioned (by default, do)")
+	cmd.Flags().StringSliceVarP(&c.labels, "label", "l", nil, "only delete resources with one of these labels (may be repeated)")
 
 	return cmd
 }
@@ -69,11 +101,23 @@ func (c *downCmd) run(ctx context.Context, args []string) error {
 	a.Incr("cmd.down", map[string]string{})
 	defer a.Flush(time.Second)
 
+	tiltfilePath := ctrltiltfile.ResolveFilename(c.fileName)
+	if c.devEnvBase != nil {
+		err := useDevEnvForDown(c.devEnvBase, tiltfilePath)
+		if err != nil {
+			return err
+		}
+	}
+
 	downDeps, err := c.downDepsProvider(ctx, a, "down")
 	if err != nil {
 		return err
 	}
-	return c.down(ctx, downDeps, args)
+	err = c.down(ctx, downDeps, args)
+	if err != nil || c.devEnvBase == nil || c.keepDevEnv || len(c.labels) > 0 {
+		return err
+	}
+	return downDevEnv(ctx, c.devEnvBase, tiltfilePath)
 }
 
 func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) error {
@@ -84,34 +128,125 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 	}
 
 	sortedManifests := sortManifestsForDeletion(tlr.Manifests, tlr.EnabledManifests)
+	if len(c.labels) > 0 {
+		sortedManifests = filterManifestsByLabel(sortedManifests, c.labels)
+		if len(sortedManifests) == 0 {
+			return fmt.Errorf("no resources with labels: %s", strings.Join(c.labels, ", "))
+		}
+	}
 
 	if err := deleteK8sEntities(ctx, sortedManifests, tlr.UpdateSettings, downDeps, c.deleteNamespaces); err != nil {
 		return err
 	}
 
-	dcProjects := make(map[string]v1alpha1.DockerComposeProject)
-	for _, m := range sortedManifests {
+	return downDCServices(ctx, tlr.Manifests, sortedManifests, downDeps.dcClient, dockercompose.DownOptions{
+		DeleteVolumes: c.deleteVolumes,
+		RemoveOrphans: c.removeOrphans,
+		Timeout:       c.stopTimeout,
+	})
+}
+
+func filterManifestsByLabel(manifests []model.Manifest, labels []string) []model.Manifest {
+	var result []model.Manifest
+	for _, m := range manifests {
+		for _, l := range labels {
+			if _, ok := m.Labels[l]; ok {
+				result = append(result, m)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Takes down each Compose project whose services are all selected.
+//
+// If only some of a project's services are selected, removes those services
+// instead, because `docker compose down` would take down the whole project.
+//
+// The flags of `tilt down` add to the settings from docker_compose().
+func downDCServices(ctx context.Context, all []model.Manifest, selected []model.Manifest, dcc dockercompose.DockerComposeClient, flags dockercompose.DownOptions) error {
+	serviceCount := make(map[string]int)
+	for _, m := range all {
+		if m.IsDC() {
+			serviceCount[m.DockerComposeTarget().Spec.Project.Name]++
+		}
+	}
+
+	var projectNames []string
+	selectedServices := make(map[string][]v1alpha1.DockerComposeServiceSpec)
+	options := make(map[string]dockercompose.DownOptions)
+	for _, m := range selected {
 		if !m.IsDC() {
 			continue
 		}
-		proj := m.DockerComposeTarget().Spec.Project
-
-		if _, exists := dcProjects[proj.Name]; !exists {
-			dcProjects[proj.Name] = proj
+		target := m.DockerComposeTarget()
+		name := target.Spec.Project.Name
+		if _, exists := selectedServices[name]; !exists {
+			projectNames = append(projectNames, name)
+			options[name] = downOptions(target.Down, flags)
 		}
+		selectedServices[name] = append(selectedServices[name], target.Spec)
 	}
 
-	for _, dcProject := range dcProjects {
-		dcc := downDeps.dcClient
-		err = dcc.Down(ctx, dcProject, logger.Get(ctx).Writer(logger.InfoLvl), logger.Get(ctx).Writer(logger.InfoLvl))
+	out := logger.Get(ctx).Writer(logger.InfoLvl)
+	for _, name := range projectNames {
+		specs := selectedServices[name]
+		opts := options[name]
+		if len(specs) == serviceCount[name] {
+			err := dcc.Down(ctx, specs[0].Project, opts, out, out)
+			if err != nil {
+				return errors.Wrap(err, "Running `docker-compose down`")
+			}
+			continue
+		}
+
+		err := dcc.Rm(ctx, specs, opts.DeleteVolumes, out, out)
 		if err != nil {
-			return errors.Wrap(err, "Running `docker-compose down`")
+			return errors.Wrap(err, "Running `docker-compose rm`")
 		}
 	}
 
 	return nil
 }
 
+func downOptions(settings model.DockerComposeDownSettings, flags dockercompose.DownOptions) dockercompose.DownOptions {
+	opts := dockercompose.DownOptions{
+		DeleteVolumes: settings.DeleteVolumes || flags.DeleteVolumes,
+		RemoveOrphans: settings.RemoveOrphans || flags.RemoveOrphans,
+		Timeout:       settings.Timeout,
+	}
+	if flags.Timeout != nil {
+		opts.Timeout = flags.Timeout
+	}
+	return opts
+}
+
+// A duration flag that's nil until it's set.
+type optionalDuration struct {
+	d **time.Duration
+}
+
+func (o optionalDuration) String() string {
+	if o.d == nil || *o.d == nil {
+		return ""
+	}
+	return (*o.d).String()
+}
+
+func (o optionalDuration) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*o.d = &d
+	return nil
+}
+
+func (o optionalDuration) Type() string {
+	return "duration"
+}
+
 func sortManifestsForDeletion(manifests []model.Manifest, enabledManifests []model.ManifestName) []model.Manifest {
 	enabledNames := make(map[model.ManifestName]bool, len(enabledManifests))
 	for _, n := range enabledManifests {
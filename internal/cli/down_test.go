@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -278,6 +279,104 @@ func TestDownDCFails(t *testing.T) {
 	}
 }
 
+func TestDownByLabel(t *testing.T) {
+	f := newDownFixture(t)
+
+	f.tfl.Result = newTiltfileLoadResult(
+		newK8sConfigMapManifest("worker-1").WithLabels(map[string]string{"workers": "workers"}),
+		newK8sConfigMapManifest("worker-2").WithLabels(map[string]string{"workers": "workers"}),
+		newK8sConfigMapManifest("db").WithLabels(map[string]string{"databases": "databases"}))
+	f.cmd.labels = []string{"workers"}
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+	require.Contains(t, f.kCli.DeletedYaml, "worker-1")
+	require.Contains(t, f.kCli.DeletedYaml, "worker-2")
+	require.NotContains(t, f.kCli.DeletedYaml, "db")
+}
+
+func TestDownByLabelNoMatch(t *testing.T) {
+	f := newDownFixture(t)
+
+	f.tfl.Result = newTiltfileLoadResult(newK8sConfigMapManifest("db"))
+	f.cmd.labels = []string{"workers"}
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.EqualError(t, err, "no resources with labels: workers")
+	require.Equal(t, "", f.kCli.DeletedYaml)
+}
+
+func TestDownDCProject(t *testing.T) {
+	f := newDownFixture(t)
+
+	f.tfl.Result = newTiltfileLoadResult(newDCServiceManifest("web"), newDCServiceManifest("redis"))
+	f.cmd.deleteVolumes = true
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	downCalls := f.dcc.DownCalls()
+	require.Len(t, downCalls, 1)
+	assert.Equal(t, "my-project", downCalls[0].Proj.Name)
+	assert.True(t, downCalls[0].Options.DeleteVolumes)
+	assert.Empty(t, f.dcc.RmCalls())
+}
+
+func TestDownDCProjectSettings(t *testing.T) {
+	f := newDownFixture(t)
+
+	timeout := 5 * time.Second
+	web := newDCServiceManifest("web")
+	target := web.DockerComposeTarget()
+	target.Down = model.DockerComposeDownSettings{RemoveOrphans: true, Timeout: &timeout}
+	web = web.WithDeployTarget(target)
+	f.tfl.Result = newTiltfileLoadResult(web)
+
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	downCalls := f.dcc.DownCalls()
+	require.Len(t, downCalls, 1)
+	assert.Equal(t, dockercompose.DownOptions{RemoveOrphans: true, Timeout: &timeout}, downCalls[0].Options)
+}
+
+func TestDownDCFlagsOverrideSettings(t *testing.T) {
+	f := newDownFixture(t)
+
+	cmd := f.cmd.register()
+	require.NoError(t, cmd.ParseFlags([]string{"--delete-volumes", "--stop-timeout=0s"}))
+
+	timeout := 5 * time.Second
+	web := newDCServiceManifest("web")
+	target := web.DockerComposeTarget()
+	target.Down = model.DockerComposeDownSettings{Timeout: &timeout}
+	web = web.WithDeployTarget(target)
+	f.tfl.Result = newTiltfileLoadResult(web)
+
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	downCalls := f.dcc.DownCalls()
+	require.Len(t, downCalls, 1)
+	noTimeout := time.Duration(0)
+	assert.Equal(t, dockercompose.DownOptions{DeleteVolumes: true, Timeout: &noTimeout}, downCalls[0].Options)
+}
+
+func TestDownDCSomeServices(t *testing.T) {
+	f := newDownFixture(t)
+
+	tlr := newTiltfileLoadResult(newDCServiceManifest("web"), newDCServiceManifest("redis"))
+	tlr.EnabledManifests = []model.ManifestName{"web"}
+	f.tfl.Result = tlr
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	// The rest of the project keeps running.
+	assert.Empty(t, f.dcc.DownCalls())
+	rmCalls := f.dcc.RmCalls()
+	require.Len(t, rmCalls, 1)
+	require.Len(t, rmCalls[0].Specs, 1)
+	assert.Equal(t, "web", rmCalls[0].Specs[0].Service)
+	assert.False(t, rmCalls[0].DeleteVolumes)
+}
+
 func TestDownArgs(t *testing.T) {
 	f := newDownFixture(t)
 
@@ -388,6 +487,19 @@ func newDCManifest() model.Manifest {
 	})
 }
 
+func newDCServiceManifest(service string) model.Manifest {
+	return model.Manifest{Name: model.ManifestName(service)}.WithDeployTarget(model.DockerComposeTarget{
+		Name: model.TargetName(service),
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: service,
+			Project: v1alpha1.DockerComposeProject{
+				Name:        "my-project",
+				ConfigPaths: []string{"dc.yaml"},
+			},
+		},
+	})
+}
+
 func newK8sMultiEntityManifest() model.Manifest {
 	yaml := `
 apiVersion: v1
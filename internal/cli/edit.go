@@ -23,10 +23,15 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/cmd/util/editor"
 
@@ -39,6 +44,7 @@ type editCmd struct {
 	streams genericclioptions.IOStreams
 	options *editor.EditOptions
 	cmd     *cobra.Command
+	dryRun  bool
 }
 
 var _ tiltCmd = &editCmd{}
@@ -58,6 +64,16 @@ func (c *editCmd) register() *cobra.Command {
 		Use:                   "edit (RESOURCE/NAME | -f FILENAME)",
 		DisableFlagsInUseLine: true,
 		Short:                 "Edit a resource on the server",
+		Long: `Edit a resource on the server.
+
+Opens the resource in your editor, and saves it when you close the editor.
+If the server rejects the edit, the editor re-opens with the errors at the top.
+
+With --dry-run, the server validates the edit and prints the result, but
+doesn't save it. Combine with -o yaml to see the object as it would be saved.`,
+		Example: `  tilt edit cmd/my-server
+  tilt edit uibutton/my-button --dry-run -o yaml`,
+		ValidArgsFunction: apiObjectCompletion,
 	}
 
 	// bind flag structs
@@ -72,6 +88,8 @@ func (c *editCmd) register() *cobra.Command {
 		"Defaults to the line ending native to your platform.")
 	cmdutil.AddFieldManagerFlagVar(cmd, &o.FieldManager, "kubectl-edit")
 	cmdutil.AddApplyAnnotationVarFlags(cmd, &o.ApplyAnnotation)
+	cmd.Flags().BoolVar(&c.dryRun, "dry-run", false,
+		"Validate the edit on the server, without saving it.")
 
 	addConnectServerFlags(cmd)
 
@@ -92,8 +110,54 @@ func (c *editCmd) run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	f := cmdutil.NewFactory(getter)
+	var clientGetter genericclioptions.RESTClientGetter = getter
+	if c.dryRun {
+		clientGetter = dryRunGetter{RESTClientGetter: getter}
+	}
+
+	f := cmdutil.NewFactory(clientGetter)
 	cmdutil.CheckErr(o.Complete(f, args, c.cmd))
+	if c.dryRun {
+		toPrinter := o.ToPrinter
+		o.ToPrinter = func(operation string) (printers.ResourcePrinter, error) {
+			return toPrinter(fmt.Sprintf("%s (server dry run)", operation))
+		}
+	}
 	cmdutil.CheckErr(o.Run())
 	return nil
 }
+
+// Sends every write as a server-side dry run.
+//
+// kubectl's edit doesn't have a dry-run mode, so we add the query param
+// to its requests instead.
+type dryRunGetter struct {
+	genericclioptions.RESTClientGetter
+}
+
+func (g dryRunGetter) ToRESTConfig() (*rest.Config, error) {
+	config, err := g.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return dryRunRoundTripper{delegate: rt}
+	})
+	return config, nil
+}
+
+type dryRunRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	q := req.URL.Query()
+	q.Set("dryRun", metav1.DryRunAll)
+	req.URL.RawQuery = q.Encode()
+	return rt.delegate.RoundTrip(req)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -44,3 +45,38 @@ func TestEdit(t *testing.T) {
 
 	assert.Contains(t, out.String(), `Edit cancelled, no changes made`)
 }
+
+func TestEditDryRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip() // sed is not an editor on Windows
+	}
+
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Spec: v1alpha1.CmdSpec{
+			Args: []string{"sleep", "100"},
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	streams := genericclioptions.IOStreams{Out: out, ErrOut: out, In: os.Stdin}
+
+	cmd := newEditCmd(streams)
+	c := cmd.register()
+	err = c.Flags().Parse([]string{"--dry-run"})
+	require.NoError(t, err)
+
+	t.Setenv("EDITOR", `sed -i -e s/100/200/`)
+	err = cmd.run(f.ctx, []string{"cmd", "my-sleep"})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `cmd.tilt.dev/my-sleep edited (server dry run)`)
+
+	var current v1alpha1.Cmd
+	err = f.client.Get(f.ctx, types.NamespacedName{Name: "my-sleep"}, &current)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sleep", "100"}, current.Spec.Args)
+}
@@ -8,11 +8,10 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -46,6 +45,7 @@ tilt enable --only frontend backend
 # enables all resources
 tilt enable --all
 `,
+		ValidArgsFunction: resourceNameCompletion(0),
 	}
 
 	addConnectServerFlags(cmd)
@@ -159,21 +159,9 @@ func changeEnabledResources(
 			continue
 		}
 
-		for _, source := range uir.Status.DisableStatus.Sources {
-			if source.ConfigMap == nil {
-				return fmt.Errorf("internal error: resource %s's DisableSource does not have a ConfigMap'", uir.Name)
-			}
-			cm := &v1alpha1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: source.ConfigMap.Name}}
-			_, err := controllerutil.CreateOrUpdate(ctx, cli, cm, func() error {
-				if cm.Data == nil {
-					cm.Data = make(map[string]string)
-				}
-				cm.Data[source.ConfigMap.Key] = strconv.FormatBool(!enable)
-				return nil
-			})
-			if err != nil {
-				return err
-			}
+		err := configmap.SetResourceEnabled(ctx, cli, uir, enable)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type execCmd struct {
+	container string
+	stdin     bool
+	tty       bool
+}
+
+func newExecCmd() *execCmd {
+	return &execCmd{}
+}
+
+func (c *execCmd) name() model.TiltSubcommand { return "exec" }
+
+func (c *execCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "exec RESOURCE [-c CONTAINER] -- COMMAND [args...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Execute a command in a running resource",
+		Long: `Execute a command in a running resource.
+
+Where the command runs depends on the resource:
+- Kubernetes resources: in a container of the resource's newest pod, with kubectl exec.
+- Docker Compose resources: in the service's container, with docker exec.
+- Local resources: on this machine, in the working directory and environment of the resource's serve_cmd.
+`,
+		Example: `  # Open a shell in the frontend's pod
+  tilt exec -it frontend -- sh
+
+  # Run a command in the sidecar container
+  tilt exec frontend -c sidecar -- cat /etc/hosts`,
+		Args: cobra.MinimumNArgs(2),
+	}
+
+	cmd.Flags().StringVarP(&c.container, "container", "c", "",
+		"Container name. Defaults to the first container in the pod. Only applies to Kubernetes resources.")
+	cmd.Flags().BoolVarP(&c.stdin, "stdin", "i", false, "Pass stdin to the command")
+	cmd.Flags().BoolVarP(&c.tty, "tty", "t", false, "Allocate a TTY for the command")
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c *execCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.exec", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := c.resolve(ctx, client, args[0], args[1:])
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, plan.name, plan.args...)
+	cmd.Dir = plan.dir
+	if plan.env != nil {
+		cmd.Env = plan.env
+	}
+	if c.stdin {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// Exit the same way the command did, like kubectl exec.
+		os.Exit(exitErr.ExitCode())
+	}
+	return err
+}
+
+// The process to run for `tilt exec`.
+type execPlan struct {
+	name string
+	args []string
+
+	// Only set for local resources.
+	dir string
+	env []string
+}
+
+// Decides how to run the command, based on how the resource runs.
+func (c *execCmd) resolve(ctx context.Context, client ctrlclient.Client, resource string, command []string) (execPlan, error) {
+	var uir v1alpha1.UIResource
+	err := client.Get(ctx, types.NamespacedName{Name: resource}, &uir)
+	if apierrors.IsNotFound(err) {
+		return execPlan{}, fmt.Errorf("no resource named %q", resource)
+	} else if err != nil {
+		return execPlan{}, err
+	}
+
+	if c.container != "" && uir.Status.K8sResourceInfo == nil {
+		return execPlan{}, fmt.Errorf("--container only applies to Kubernetes resources")
+	}
+
+	switch {
+	case uir.Status.K8sResourceInfo != nil:
+		return c.resolveKubernetes(ctx, client, resource, command)
+	case uir.Status.LocalResourceInfo != nil:
+		return c.resolveLocal(ctx, client, resource, command)
+	}
+
+	var dcs v1alpha1.DockerComposeService
+	err = client.Get(ctx, types.NamespacedName{Name: resource}, &dcs)
+	if err == nil {
+		return c.resolveDockerCompose(dcs, command)
+	} else if !apierrors.IsNotFound(err) {
+		return execPlan{}, err
+	}
+	return execPlan{}, fmt.Errorf("resource %q doesn't run anything to exec in", resource)
+}
+
+func (c *execCmd) resolveKubernetes(ctx context.Context, client ctrlclient.Client, resource string, command []string) (execPlan, error) {
+	var discoveries v1alpha1.KubernetesDiscoveryList
+	err := client.List(ctx, &discoveries)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	var pods []v1alpha1.Pod
+	for _, kd := range discoveries.Items {
+		if belongsToResource(&kd, resource) {
+			for _, pod := range kd.Status.Pods {
+				if !pod.Deleting {
+					pods = append(pods, pod)
+				}
+			}
+		}
+	}
+	if len(pods) == 0 {
+		return execPlan{}, fmt.Errorf("resource %q has no pods", resource)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreatedAt.After(pods[j].CreatedAt.Time)
+	})
+	pod := pods[0]
+
+	container := c.container
+	if container == "" {
+		if len(pod.Containers) == 0 {
+			return execPlan{}, fmt.Errorf("pod %s has no containers", pod.Name)
+		}
+		container = pod.Containers[0].Name
+	} else if !podHasContainer(pod, container) {
+		return execPlan{}, fmt.Errorf("pod %s has no container %q", pod.Name, container)
+	}
+
+	args := []string{"exec"}
+	var cluster v1alpha1.Cluster
+	err = client.Get(ctx, types.NamespacedName{Name: v1alpha1.ClusterNameDefault}, &cluster)
+	if err == nil && cluster.Status.Connection != nil && cluster.Status.Connection.Kubernetes != nil &&
+		cluster.Status.Connection.Kubernetes.Context != "" {
+		// Talk to the same cluster as Tilt, even if the current context has changed since.
+		args = append(args, "--context", cluster.Status.Connection.Kubernetes.Context)
+	}
+	args = append(args, "-n", pod.Namespace, pod.Name, "-c", container)
+	args = append(args, c.ttyFlags()...)
+	args = append(args, "--")
+	args = append(args, command...)
+	return execPlan{name: "kubectl", args: args}, nil
+}
+
+func podHasContainer(pod v1alpha1.Pod, name string) bool {
+	for _, c := range pod.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *execCmd) resolveDockerCompose(dcs v1alpha1.DockerComposeService, command []string) (execPlan, error) {
+	if dcs.Status.ContainerID == "" {
+		return execPlan{}, fmt.Errorf("resource %q has no container", dcs.Name)
+	}
+
+	args := []string{"exec"}
+	args = append(args, c.ttyFlags()...)
+	args = append(args, dcs.Status.ContainerID)
+	args = append(args, command...)
+	return execPlan{name: "docker", args: args}, nil
+}
+
+// Local processes can't be attached to from the outside, so run the
+// command next to the resource's server instead, where it sees the same
+// files and environment.
+func (c *execCmd) resolveLocal(ctx context.Context, client ctrlclient.Client, resource string, command []string) (execPlan, error) {
+	var cmds v1alpha1.CmdList
+	err := client.List(ctx, &cmds)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	var latest *v1alpha1.Cmd
+	for i, cmd := range cmds.Items {
+		if !belongsToResource(&cmd, resource) {
+			continue
+		}
+		// Prefer the server that's running, then the newest one.
+		if latest == nil ||
+			(cmd.Status.Running != nil && latest.Status.Running == nil) ||
+			((cmd.Status.Running != nil) == (latest.Status.Running != nil) &&
+				latest.CreationTimestamp.Before(&cmd.CreationTimestamp)) {
+			latest = &cmds.Items[i]
+		}
+	}
+	if latest == nil {
+		return execPlan{}, fmt.Errorf("resource %q has no local process", resource)
+	}
+
+	return execPlan{
+		name: command[0],
+		args: command[1:],
+		dir:  latest.Spec.Dir,
+		env:  append(os.Environ(), latest.Spec.Env...),
+	}, nil
+}
+
+func (c *execCmd) ttyFlags() []string {
+	var flags []string
+	if c.stdin {
+		flags = append(flags, "-i")
+	}
+	if c.tty {
+		flags = append(flags, "-t")
+	}
+	return flags
+}
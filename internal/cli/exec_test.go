@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestExecKubernetes(t *testing.T) {
+	f := newServerFixture(t)
+	annotations := map[string]string{v1alpha1.AnnotationManifest: "fe"}
+	created := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe"},
+		Status: v1alpha1.UIResourceStatus{
+			K8sResourceInfo: &v1alpha1.UIResourceKubernetes{PodName: "fe-new"},
+		},
+	})
+	require.NoError(t, err)
+	err = f.client.Create(f.ctx, &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe", Annotations: annotations},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{{UID: "abc", Namespace: "default"}},
+		},
+		Status: v1alpha1.KubernetesDiscoveryStatus{
+			Pods: []v1alpha1.Pod{
+				{
+					Name:       "fe-old",
+					Namespace:  "default",
+					CreatedAt:  metav1.NewTime(created),
+					Containers: []v1alpha1.Container{{Name: "fe"}},
+				},
+				{
+					Name:       "fe-new",
+					Namespace:  "default",
+					CreatedAt:  metav1.NewTime(created.Add(time.Minute)),
+					Containers: []v1alpha1.Container{{Name: "fe"}, {Name: "sidecar"}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	c := &execCmd{stdin: true, tty: true}
+	plan, err := c.resolve(f.ctx, f.client, "fe", []string{"sh"})
+	require.NoError(t, err)
+	assert.Equal(t, "kubectl", plan.name)
+	assert.Equal(t, []string{"exec", "-n", "default", "fe-new", "-c", "fe", "-i", "-t", "--", "sh"}, plan.args)
+
+	c = &execCmd{container: "sidecar"}
+	plan, err = c.resolve(f.ctx, f.client, "fe", []string{"ls", "/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"exec", "-n", "default", "fe-new", "-c", "sidecar", "--", "ls", "/"}, plan.args)
+
+	c = &execCmd{container: "db"}
+	_, err = c.resolve(f.ctx, f.client, "fe", []string{"sh"})
+	assert.EqualError(t, err, `pod fe-new has no container "db"`)
+}
+
+func TestExecDockerCompose(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "db"}})
+	require.NoError(t, err)
+	err = f.client.Create(f.ctx, &v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: "db",
+			Project: v1alpha1.DockerComposeProject{YAML: "services:\n  db:\n    image: postgres\n"},
+		},
+		Status: v1alpha1.DockerComposeServiceStatus{ContainerID: "abc123"},
+	})
+	require.NoError(t, err)
+
+	c := &execCmd{tty: true}
+	plan, err := c.resolve(f.ctx, f.client, "db", []string{"psql"})
+	require.NoError(t, err)
+	assert.Equal(t, "docker", plan.name)
+	assert.Equal(t, []string{"exec", "-t", "abc123", "psql"}, plan.args)
+
+	c = &execCmd{container: "db"}
+	_, err = c.resolve(f.ctx, f.client, "db", []string{"psql"})
+	assert.EqualError(t, err, "--container only applies to Kubernetes resources")
+}
+
+func TestExecLocal(t *testing.T) {
+	f := newServerFixture(t)
+	annotations := map[string]string{v1alpha1.AnnotationManifest: "server"}
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "server"},
+		Status: v1alpha1.UIResourceStatus{
+			LocalResourceInfo: &v1alpha1.UIResourceLocal{PID: 1234},
+		},
+	})
+	require.NoError(t, err)
+	for _, cmd := range []*v1alpha1.Cmd{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-serve-1", Annotations: annotations},
+			Spec:       v1alpha1.CmdSpec{Args: []string{"./server"}, Dir: "/old", Env: []string{"PORT=1"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-serve-2", Annotations: annotations},
+			Spec:       v1alpha1.CmdSpec{Args: []string{"./server"}, Dir: "/src/server", Env: []string{"PORT=8000"}},
+			Status: v1alpha1.CmdStatus{
+				Running: &v1alpha1.CmdStateRunning{PID: 1234},
+			},
+		},
+	} {
+		require.NoError(t, f.client.Create(f.ctx, cmd))
+	}
+
+	c := &execCmd{}
+	plan, err := c.resolve(f.ctx, f.client, "server", []string{"env"})
+	require.NoError(t, err)
+	assert.Equal(t, "env", plan.name)
+	assert.Empty(t, plan.args)
+	assert.Equal(t, "/src/server", plan.dir)
+	assert.Equal(t, "PORT=8000", plan.env[len(plan.env)-1])
+}
+
+func TestExecNotFound(t *testing.T) {
+	f := newServerFixture(t)
+
+	c := &execCmd{}
+	_, err := c.resolve(f.ctx, f.client, "fe", []string{"sh"})
+	assert.EqualError(t, err, `no resource named "fe"`)
+
+	err = f.client.Create(f.ctx, &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}})
+	require.NoError(t, err)
+	_, err = c.resolve(f.ctx, f.client, "fe", []string{"sh"})
+	assert.EqualError(t, err, `resource "fe" doesn't run anything to exec in`)
+}
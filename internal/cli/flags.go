@@ -44,6 +44,14 @@ func addTiltfileFlag(cmd *cobra.Command, s *string) {
 	cmd.Flags().StringVarP(s, "file", "f", tiltfile.FileName, "Path to Tiltfile")
 }
 
+// For commands that run the controllers.
+func addControllerWorkersFlag(cmd *cobra.Command) {
+	cmd.Flags().StringToIntVar(&controllerWorkersFlag, "controller-workers", nil,
+		"How many objects each controller may reconcile at once (e.g., cmd=4,kubernetesapply=8). "+
+			"Controllers that aren't listed reconcile one object at a time. Raise it for sessions with hundreds of resources "+
+			"if the workqueue_depth metric of a controller stays high.")
+}
+
 func addKubeContextFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&kubeContextOverride, "context", "", "Kubernetes context override. Equivalent to kubectl --context")
 }
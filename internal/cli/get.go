@@ -56,6 +56,7 @@ func (c *getCmd) register() *cobra.Command {
 		Use:                   "get TYPE [NAME | -l label]",
 		DisableFlagsInUseLine: true,
 		Short:                 "Display one or many resources",
+		ValidArgsFunction:     apiObjectCompletion,
 	}
 	c.cmd = cmd
 	o := c.options
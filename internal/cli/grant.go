@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers/core/extension"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newGrantCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	result := &cobra.Command{
+		Use:   "grant",
+		Short: "Grant permissions on this machine",
+	}
+
+	addCommand(result, newGrantExtCmd(streams))
+
+	return result
+}
+
+// A CLI for granting permissions to sandboxed extensions.
+type grantExtCmd struct {
+	streams genericclioptions.IOStreams
+	base    xdg.Base
+
+	revoke bool
+}
+
+var _ tiltCmd = &grantExtCmd{}
+
+func newGrantExtCmd(streams genericclioptions.IOStreams) *grantExtCmd {
+	return &grantExtCmd{streams: streams, base: xdg.NewTiltDevBase()}
+}
+
+func (c *grantExtCmd) name() model.TiltSubcommand { return "grant" }
+
+func (c *grantExtCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "ext SOURCE [PERMISSION...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Grant permissions to an extension.",
+		Long: fmt.Sprintf(`Grant permissions to an extension, on this machine.
+
+Extensions that list the permissions they need in a %s file
+next to their Tiltfile are sandboxed: they can only use the builtins
+their permissions cover, and they won't load until you grant them.
+
+Permissions:
+  exec     Run commands on the host, like with local() or local_resource()
+  env      Read or change environment variables, like with os.getenv()
+  network  Download code, like with v1alpha1.extension_repo()
+
+An extension's SOURCE is the URL of its repo and its path in the repo,
+separated by //. When an extension needs permissions you haven't granted,
+Tilt prints the command to grant them.
+
+Extensions that don't list their permissions can use every builtin.
+To sandbox them too, set "sandboxUndeclared": true in the grants file.
+`, extension.PermissionsFileName),
+		Args: cobra.MinimumNArgs(1),
+		Example: `
+# Lets the extension restart_process run commands
+tilt grant ext https://github.com/tilt-dev/tilt-extensions//restart_process exec
+
+# Takes away all the permissions of the extension
+tilt grant ext https://github.com/tilt-dev/tilt-extensions//restart_process --revoke
+`,
+	}
+
+	cmd.Flags().BoolVar(&c.revoke, "revoke", false,
+		"Take away the permissions instead. With no permissions, takes away all of them.")
+
+	return cmd
+}
+
+func (c *grantExtCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.grant-ext", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	source := args[0]
+	perms := args[1:]
+	if !isExtensionSource(source) {
+		return fmt.Errorf("invalid extension source %q: must be a repo URL and a path, separated by //", source)
+	}
+	for _, p := range perms {
+		if !extension.IsPermission(p) {
+			return fmt.Errorf("unknown permission %q (must be one of %s)", p, strings.Join(extension.Permissions, ", "))
+		}
+	}
+	if len(perms) == 0 && !c.revoke {
+		return fmt.Errorf("must specify at least one permission")
+	}
+
+	grantsPath, err := tiltextension.GrantsPath(c.base)
+	if err != nil {
+		return err
+	}
+	grants, err := tiltextension.ReadGrants(grantsPath)
+	if err != nil {
+		return err
+	}
+
+	if c.revoke {
+		grants.Revoke(source, perms...)
+	} else {
+		grants.Grant(source, perms...)
+	}
+
+	err = tiltextension.WriteGrants(grantsPath, grants)
+	if err != nil {
+		return err
+	}
+
+	granted := grants.Permissions[source]
+	if len(granted) == 0 {
+		_, _ = fmt.Fprintf(c.streams.Out, "Extension %s has no permissions\n", source)
+	} else {
+		_, _ = fmt.Fprintf(c.streams.Out, "Extension %s has permissions: %s\n", source, strings.Join(granted, ", "))
+	}
+	return nil
+}
+
+// Whether s looks like an extension source, like
+// https://github.com/tilt-dev/tilt-extensions//restart_process
+func isExtensionSource(s string) bool {
+	i := strings.Index(s, "://")
+	if i == -1 {
+		return false
+	}
+	rest := s[i+len("://"):]
+	j := strings.Index(rest, "//")
+	return j > 0 && j+len("//") < len(rest)
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+const testExtSource = "https://github.com/tilt-dev/tilt-extensions//restart_process"
+
+func TestGrantExt(t *testing.T) {
+	f := newGrantFixture(t)
+
+	out := f.run(testExtSource, "exec")
+	assert.Equal(t, "Extension "+testExtSource+" has permissions: exec\n", out)
+
+	out = f.run(testExtSource, "env", "exec")
+	assert.Equal(t, "Extension "+testExtSource+" has permissions: env, exec\n", out)
+
+	grants := f.grants()
+	assert.True(t, grants.Granted(testExtSource, "exec"))
+	assert.True(t, grants.Granted(testExtSource, "env"))
+	assert.False(t, grants.Granted(testExtSource, "network"))
+}
+
+func TestGrantExtRevoke(t *testing.T) {
+	f := newGrantFixture(t)
+	f.run(testExtSource, "exec", "env")
+
+	out := f.run("--revoke", testExtSource, "exec")
+	assert.Equal(t, "Extension "+testExtSource+" has permissions: env\n", out)
+
+	out = f.run("--revoke", testExtSource)
+	assert.Equal(t, "Extension "+testExtSource+" has no permissions\n", out)
+	assert.Empty(t, f.grants().Permissions)
+}
+
+func TestGrantExtInvalid(t *testing.T) {
+	f := newGrantFixture(t)
+
+	assert.EqualError(t, f.runErr("restart_process", "exec"),
+		`invalid extension source "restart_process": must be a repo URL and a path, separated by //`)
+	assert.EqualError(t, f.runErr(testExtSource, "sudo"),
+		`unknown permission "sudo" (must be one of exec, env, network)`)
+	assert.EqualError(t, f.runErr(testExtSource),
+		"must specify at least one permission")
+}
+
+type grantFixture struct {
+	t    *testing.T
+	base xdg.Base
+}
+
+func newGrantFixture(t *testing.T) *grantFixture {
+	tmp := tempdir.NewTempDirFixture(t)
+	return &grantFixture{t: t, base: xdg.FakeBase{Dir: tmp.Path()}}
+}
+
+func (f *grantFixture) runErr(args ...string) error {
+	_, err := f.runWithOutput(args...)
+	return err
+}
+
+func (f *grantFixture) run(args ...string) string {
+	out, err := f.runWithOutput(args...)
+	require.NoError(f.t, err)
+	return out
+}
+
+func (f *grantFixture) runWithOutput(args ...string) (string, error) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newGrantExtCmd(streams)
+	cmd.base = f.base
+	c := cmd.register()
+	err := c.Flags().Parse(args)
+	require.NoError(f.t, err)
+	err = cmd.run(ctx, c.Flags().Args())
+	return out.String(), err
+}
+
+func (f *grantFixture) grants() tiltextension.Grants {
+	p, err := tiltextension.GrantsPath(f.base)
+	require.NoError(f.t, err)
+	grants, err := tiltextension.ReadGrants(p)
+	require.NoError(f.t, err)
+	return grants
+}
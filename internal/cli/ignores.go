@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type ignoresCmd struct {
+	streams genericclioptions.IOStreams
+}
+
+var _ tiltCmd = &ignoresCmd{}
+
+func newIgnoresCmd(streams genericclioptions.IOStreams) *ignoresCmd {
+	return &ignoresCmd{streams: streams}
+}
+
+func (c *ignoresCmd) name() model.TiltSubcommand { return "ignores" }
+
+func (c *ignoresCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ignores [PATH]",
+		Short: "Explain which file changes are ignored by Tilt",
+		Long: `Explain which file changes are ignored by Tilt.
+
+With no arguments, prints the effective ignore rules of every FileWatch
+in the running Tilt session, along with the reason each rule exists.
+
+With a path, prints every FileWatch that watches that path, and which
+ignore rule (if any) prevents a change to it from triggering an update.
+`,
+		Example: `
+# Print all ignore rules
+tilt alpha ignores
+
+# Why didn't editing this file trigger an update?
+tilt alpha ignores ./src/gen/api.pb.go
+`,
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c *ignoresCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.ignores", make(engineanalytics.CmdTags).AsMap())
+	defer a.Flush(time.Second)
+
+	query := url.Values{}
+	if len(args) > 0 {
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+		query.Set("path", path)
+	}
+
+	body := apiGet("ignores?" + query.Encode())
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var view server.IgnoresView
+	err := json.NewDecoder(body).Decode(&view)
+	if err != nil {
+		return fmt.Errorf("reading ignores: %v", err)
+	}
+
+	if len(args) == 0 {
+		printIgnores(c.streams.Out, view)
+		return nil
+	}
+	explainPathIgnores(c.streams.Out, view, query.Get("path"))
+	return nil
+}
+
+func printIgnores(w io.Writer, view server.IgnoresView) {
+	for _, fw := range view.FileWatches {
+		_, _ = fmt.Fprintf(w, "%s:\n", fw.Name)
+		if len(fw.Ignores) == 0 {
+			_, _ = fmt.Fprintf(w, "  (no ignores)\n")
+		}
+		for _, ig := range fw.Ignores {
+			_, _ = fmt.Fprintf(w, "  - %s\n", formatIgnoreDef(ig))
+		}
+	}
+}
+
+func explainPathIgnores(w io.Writer, view server.IgnoresView, path string) {
+	if len(view.FileWatches) == 0 {
+		_, _ = fmt.Fprintf(w, "%s is not watched by any FileWatch\n", path)
+		return
+	}
+
+	for _, fw := range view.FileWatches {
+		match := fw.Match
+		switch {
+		case match == nil || !match.Ignored:
+			_, _ = fmt.Fprintf(w, "%s: not ignored\n", fw.Name)
+		case match.Rule != nil:
+			_, _ = fmt.Fprintf(w, "%s: ignored by %s\n", fw.Name, formatIgnoreDef(*match.Rule))
+		case match.Ephemeral:
+			_, _ = fmt.Fprintf(w, "%s: ignored as an editor temp file\n", fw.Name)
+		}
+	}
+}
+
+func formatIgnoreDef(ig v1alpha1.IgnoreDef) string {
+	var sb strings.Builder
+	sb.WriteString(ig.BasePath)
+	if len(ig.Patterns) > 0 {
+		sb.WriteString(fmt.Sprintf(" %v", ig.Patterns))
+	}
+	if ig.Reason != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", ig.Reason))
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestPrintIgnores(t *testing.T) {
+	out := &bytes.Buffer{}
+	printIgnores(out, server.IgnoresView{FileWatches: []server.FileWatchIgnores{
+		{Name: "local-fe", Ignores: []v1alpha1.IgnoreDef{
+			{BasePath: "/src/fe", Patterns: []string{"**/*.pb.go"}, Reason: "watch_settings(): generated protobuf code"},
+		}},
+		{Name: "local-be", Ignores: []v1alpha1.IgnoreDef{}},
+	}})
+	assert.Equal(t, `local-fe:
+  - /src/fe [**/*.pb.go] (watch_settings(): generated protobuf code)
+local-be:
+  (no ignores)
+`, out.String())
+}
+
+func TestExplainPathIgnores(t *testing.T) {
+	rule := v1alpha1.IgnoreDef{BasePath: "/src/fe", Patterns: []string{"**/*.pb.go"}}
+
+	out := &bytes.Buffer{}
+	explainPathIgnores(out, server.IgnoresView{FileWatches: []server.FileWatchIgnores{
+		{Name: "local-fe", Match: &server.IgnoreMatch{Path: "/src/fe/api.pb.go", Ignored: true, Rule: &rule}},
+		{Name: "local-all", Match: &server.IgnoreMatch{Path: "/src/fe/api.pb.go"}},
+	}}, "/src/fe/api.pb.go")
+	assert.Equal(t, "local-fe: ignored by /src/fe [**/*.pb.go]\nlocal-all: not ignored\n", out.String())
+
+	out.Reset()
+	explainPathIgnores(out, server.IgnoresView{FileWatches: []server.FileWatchIgnores{
+		{Name: "local-fe", Match: &server.IgnoreMatch{Path: "/src/fe/.main.go.swp", Ignored: true, Ephemeral: true}},
+	}}, "/src/fe/.main.go.swp")
+	assert.Equal(t, "local-fe: ignored as an editor temp file\n", out.String())
+
+	out.Reset()
+	explainPathIgnores(out, server.IgnoresView{FileWatches: []server.FileWatchIgnores{}}, "/src/be/main.go")
+	assert.Equal(t, "/src/be/main.go is not watched by any FileWatch\n", out.String())
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/scaffold"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type initCmd struct {
+	streams genericclioptions.IOStreams
+	dir     string
+	list    bool
+	opts    scaffold.Options
+}
+
+var _ tiltCmd = &initCmd{}
+
+func newInitCmd(streams genericclioptions.IOStreams) *initCmd {
+	return &initCmd{streams: streams}
+}
+
+func (c *initCmd) name() model.TiltSubcommand { return "init" }
+
+func (c *initCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init [<template>]",
+		Short: "Generate a Tiltfile, Dockerfile, and deploy manifests for a project",
+		Long: `Generate a starter Tiltfile, Dockerfile, and deploy manifests for a project.
+
+Looks at the project (e.g., go.mod or package.json) to fill in the project
+name, the language version, and how to start the app.
+
+If no template is given, picks one based on the files in the project directory.
+Run 'tilt init --list' to see the available templates.
+
+Existing files are left alone, unless --force is set.
+`,
+		Example: `tilt init
+tilt init go-k8s
+tilt init node-compose --port=8000
+tilt init python-helm --dir=./services/api --name=api`,
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVar(&c.dir, "dir", ".", "Project directory to generate files in")
+	cmd.Flags().BoolVar(&c.list, "list", false, "List the available templates and exit")
+	cmd.Flags().StringVar(&c.opts.Name, "name", "", "Name for the service. Defaults to a name inferred from the project")
+	cmd.Flags().IntVar(&c.opts.Port, "port", 0, "Port the service listens on. Defaults to the template's usual port")
+	cmd.Flags().BoolVar(&c.opts.Force, "force", false, "Overwrite files that already exist")
+	return cmd
+}
+
+func (c *initCmd) run(ctx context.Context, args []string) error {
+	if c.list {
+		for _, t := range scaffold.Templates() {
+			_, _ = fmt.Fprintf(c.streams.Out, "%-14s %s\n", t.Name, t.Description)
+		}
+		return nil
+	}
+
+	dir, err := filepath.Abs(c.dir)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", c.dir)
+	}
+
+	var t scaffold.Template
+	if len(args) > 0 {
+		t, err = scaffold.Lookup(args[0])
+	} else {
+		t, err = scaffold.Detect(dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{
+		"template": t.Name,
+		"detected": fmt.Sprintf("%t", len(args) == 0),
+	})
+	a.Incr("cmd.init", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	result, err := scaffold.Generate(dir, t, c.opts)
+	for _, f := range result.Created {
+		_, _ = fmt.Fprintf(c.streams.Out, "Created %s\n", f)
+	}
+	for _, f := range result.Skipped {
+		_, _ = fmt.Fprintf(c.streams.Out, "Skipped %s (already exists; use --force to overwrite)\n", f)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(result.Created) > 0 {
+		_, _ = fmt.Fprintf(c.streams.Out, "\nGenerated a %s project. Run 'tilt up' to start it!\n", t.Name)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	analytics2 "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type inputCmd struct {
+	streams genericclioptions.IOStreams
+}
+
+var _ tiltCmd = &inputCmd{}
+
+func newInputCmd(streams genericclioptions.IOStreams) *inputCmd {
+	return &inputCmd{
+		streams: streams,
+	}
+}
+
+func (c *inputCmd) name() model.TiltSubcommand {
+	return "input"
+}
+
+func (c *inputCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "input RESOURCE_NAME [TEXT]",
+		Short: "Send input to the serve_cmd of a local resource",
+		Long: `Send input to the serve_cmd of a local resource, like a key that tells a dev server to reload.
+
+Sends TEXT, followed by a newline. Without TEXT, sends each line
+of stdin as it's read, until stdin ends.
+
+The serve_cmd needs serve_stdin=True. RESOURCE_NAME can also be
+the name of a Cmd with stdin enabled.
+`,
+		Example: `  tilt input frontend r
+  echo yes | tilt input migrations`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: resourceNameCompletion(1),
+	}
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c *inputCmd) run(ctx context.Context, args []string) error {
+	resource := args[0]
+
+	a := analytics.Get(ctx)
+	a.Incr("cmd.input", make(analytics2.CmdTags))
+	defer a.Flush(time.Second)
+
+	if len(args) == 2 {
+		return c.send(resource, args[1]+"\n")
+	}
+
+	r := bufio.NewReader(c.streams.In)
+	for {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			sendErr := c.send(resource, line)
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading stdin")
+		}
+	}
+}
+
+func (c *inputCmd) send(resource, input string) error {
+	payload, err := json.Marshal(map[string]string{"name": resource, "input": input})
+	if err != nil {
+		return err
+	}
+
+	r, status, err := tryAPIPostJson("cmd/input", payload)
+	if err != nil {
+		return err
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "error reading response from tilt api")
+	}
+	_ = r.Close()
+
+	body := strings.TrimSpace(string(b))
+	if status != http.StatusOK {
+		return fmt.Errorf("(%d): %s", status, body)
+	}
+	return nil
+}
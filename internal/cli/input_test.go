@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+)
+
+func TestInputText(t *testing.T) {
+	f := newInputFixture(t)
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newInputCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"foo", "r"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`{"input":"r\n","name":"foo"}`}, f.requestBodies)
+	assert.Equal(t, 0, out.Len())
+}
+
+func TestInputStdin(t *testing.T) {
+	f := newInputFixture(t)
+	streams, in, _, _ := genericclioptions.NewTestIOStreams()
+	in.WriteString("yes\nno")
+	cmd := newInputCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"foo"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`{"input":"yes\n","name":"foo"}`,
+		`{"input":"no","name":"foo"}`,
+	}, f.requestBodies)
+}
+
+func TestInputNotRunning(t *testing.T) {
+	f := newInputFixture(t)
+	f.responseStatus = http.StatusBadRequest
+	f.responseBody = `cmd "foo-serve-1" isn't running`
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	cmd := newInputCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"foo", "r"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+
+	assert.Equal(t, `(400): cmd "foo-serve-1" isn't running`, err.Error())
+}
+
+type inputFixture struct {
+	ctx            context.Context
+	requestBodies  []string
+	responseBody   string
+	responseStatus int
+}
+
+func newInputFixture(t *testing.T) *inputFixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	f := &inputFixture{ctx: ctx, responseStatus: http.StatusOK}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/cmd/input", func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		f.requestBodies = append(f.requestBodies, strings.TrimSpace(string(b)))
+		if f.responseStatus != http.StatusOK || f.responseBody != "" {
+			http.Error(w, f.responseBody, f.responseStatus)
+		}
+	})
+	serveFakeTilt(t, mux)
+	return f
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/lint"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type lintCmd struct {
+	streams  genericclioptions.IOStreams
+	fileName string
+}
+
+var _ tiltCmd = &lintCmd{}
+
+func newLintCmd(streams genericclioptions.IOStreams) *lintCmd {
+	return &lintCmd{streams: streams}
+}
+
+func (c *lintCmd) name() model.TiltSubcommand { return "lint" }
+
+func (c *lintCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check a Tiltfile for common mistakes without running it",
+		Long: `Check a Tiltfile for common mistakes without running it.
+
+Statically analyzes the Tiltfile (and any local Tiltfiles it loads) for:
+
+- Unknown keyword arguments and too many positional arguments to built-ins
+- Literal arguments that don't match the built-in's declared type
+- Resources that can never be registered (e.g., they come after fail())
+- Files referenced by docker_build() and k8s_yaml() that don't exist
+
+Does not need a cluster or a Docker daemon, so it's suitable as a pre-commit hook.
+
+Exits with status 1 if any errors are found. Warnings do not affect the exit status.
+`,
+		Args: cobra.NoArgs,
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+	return cmd
+}
+
+func (c *lintCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.lint", make(engineanalytics.CmdTags).AsMap())
+	defer a.Flush(time.Second)
+
+	linter, err := lint.ProvideLinter()
+	if err != nil {
+		return err
+	}
+
+	diags, err := linter.Lint(c.fileName)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range diags {
+		_, _ = fmt.Fprintln(c.streams.Out, d.String())
+	}
+
+	if lint.HasErrors(diags) {
+		return fmt.Errorf("%s has errors", c.fileName)
+	}
+	return nil
+}
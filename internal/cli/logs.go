@@ -2,19 +2,36 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
+	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 )
 
 type logsCmd struct {
-	follow bool // if true, follow logs (otherwise print current logs and exit)
+	follow   bool // if true, follow logs (otherwise print current logs and exit)
+	labels   []string
+	level    string
+	since    time.Duration
+	noPrefix bool
+	previous bool
+	format   string
+
+	timestamps string
+	timezone   string
 }
 
 func (c *logsCmd) name() model.TiltSubcommand { return "logs" }
@@ -28,12 +45,53 @@ func (c *logsCmd) register() *cobra.Command {
 
 By default, looks for a running Tilt instance on localhost:10350
 (this is configurable with the --port and --host flags).
+
+Each line starts with the name of the resource that logged it,
+so that output from several resources can be told apart (and grepped).
+
+With --previous, prints the logs saved from the last time Tilt ran instead.
+If Tilt is running, that's the session before this one. Otherwise,
+it's the session that exited last (even if it crashed).
 `,
+		Example: `  # Follow the logs of the frontend and backend
+  tilt logs -f frontend backend
+
+  # Print warnings and errors from the last 10 minutes
+  tilt logs --since=10m --level=warn
+
+  # Print the logs of every resource labeled "database"
+  tilt logs -l database
+
+  # Print the errors from before Tilt restarted
+  tilt logs --previous --level=error
+
+  # Print how long after Tilt started each line was logged
+  tilt logs --timestamps=relative
+
+  # Print each line as JSON, to filter with jq
+  tilt logs --log-format=json | jq 'select(.resource == "frontend")'`,
+		ValidArgsFunction: resourceNameCompletion(0),
 	}
 
 	cmd.Flags().BoolVarP(&c.follow, "follow", "f", false, "If true, stream the requested logs; otherwise, print the requested logs at the current moment in time, then exit.")
+	cmd.Flags().StringSliceVarP(&c.labels, "label", "l", nil,
+		"Only print logs from resources with this label. May be repeated. Combined with resource names, prints logs from either.")
+	cmd.Flags().StringVar(&c.level, "level", "",
+		"Only print logs at least this severe. One of: debug, verbose, info, warn, error.")
+	cmd.Flags().DurationVar(&c.since, "since", 0,
+		"Only print logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all the logs Tilt still has.")
+	cmd.Flags().BoolVar(&c.noPrefix, "no-prefix", false,
+		"If true, leave out the resource name at the start of each line.")
+	cmd.Flags().StringVar(&c.format, "log-format", string(hud.LogFormatText),
+		"How to print each line. One of: text, json. JSON lines have the resource, span, level, and time of each line.")
+	cmd.Flags().StringVar(&c.timestamps, "timestamps", "",
+		"Put a timestamp in front of each line. One of: none, absolute, relative. "+
+			"Relative timestamps count from when Tilt started. Only applies to the text log format.")
+	cmd.Flags().StringVar(&c.timezone, "timezone", "local",
+		"The time zone of absolute timestamps. One of: local, utc.")
+	cmd.Flags().BoolVar(&c.previous, "previous", false,
+		"If true, print the logs saved from the last time Tilt ran, rather than the logs of the running Tilt.")
 
-	// TODO: log level flags
 	addConnectServerFlags(cmd)
 	return cmd
 }
@@ -41,7 +99,11 @@ By default, looks for a running Tilt instance on localhost:10350
 func (c *logsCmd) run(ctx context.Context, args []string) error {
 	a := analytics.Get(ctx)
 
-	a.Incr("cmd.logs", nil)
+	tags := map[string]string{}
+	if c.previous {
+		tags["previous"] = "true"
+	}
+	a.Incr("cmd.logs", tags)
 	defer a.Flush(time.Second)
 
 	if ok, reason := analytics.IsAnalyticsDisabledFromEnv(); ok {
@@ -53,5 +115,106 @@ func (c *logsCmd) run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	return server.StreamLogs(ctx, c.follow, logDeps.url, args, logDeps.printer)
+	if c.since < 0 {
+		return fmt.Errorf("invalid --since %s: must be positive", c.since)
+	}
+
+	format, err := hud.ParseLogFormat(c.format)
+	if err != nil {
+		return err
+	}
+
+	if c.previous {
+		return c.printPrevious(xdg.NewTiltDevBase(), logDeps, format, args)
+	}
+
+	timestamps, err := logstore.ParseTimestamps(c.timestamps, c.timezone)
+	if err != nil {
+		return err
+	}
+
+	return server.StreamLogs(ctx, logDeps.url, server.LogStreamOptions{
+		Follow:     c.follow,
+		Resources:  args,
+		Labels:     c.labels,
+		Level:      c.level,
+		Since:      c.since,
+		NoPrefix:   c.noPrefix,
+		Timestamps: timestamps,
+		Format:     format,
+	}, logDeps.printer)
+}
+
+// Prints the logs saved from the last session from disk, so that
+// it works whether or not Tilt is running.
+func (c *logsCmd) printPrevious(base xdg.Base, deps LogsDeps, format hud.LogFormat, args []string) error {
+	if c.follow {
+		return fmt.Errorf("--previous can't be combined with --follow")
+	}
+	if len(c.labels) > 0 {
+		return fmt.Errorf("--previous can't be combined with --label")
+	}
+
+	timestamps, err := logstore.ParseTimestamps(c.timestamps, c.timezone)
+	if err != nil {
+		return err
+	}
+	opts := loghistory.PrintOptions{
+		Resources:  model.ManifestNameSet{},
+		NoPrefix:   c.noPrefix,
+		Timestamps: timestamps,
+	}
+	for _, r := range args {
+		opts.Resources[model.ManifestName(r)] = true
+	}
+	if c.level != "" {
+		level, err := logger.ParseLevel(c.level)
+		if err != nil {
+			return err
+		}
+		opts.MinLevel = level
+	}
+	if c.since > 0 {
+		opts.Since = time.Now().Add(-c.since)
+	}
+
+	// A running Tilt has already moved the last session's logs aside.
+	session := loghistory.SessionCurrent
+	if isTiltRunning(deps.url) {
+		session = loghistory.SessionPrevious
+	}
+	dir, err := loghistory.SessionDir(base, model.ProvideAPIServerName(provideWebPort()), session)
+	if err != nil {
+		return err
+	}
+	s, records, err := loghistory.ReadSession(dir)
+	if errors.Is(err, loghistory.ErrNoSession) {
+		return fmt.Errorf("No logs saved from the last time Tilt ran on port %d. "+
+			"Tilt saves them unless it's started with --log-history=false", provideWebPort())
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Logs from the Tilt session started at %s", s.StartTime.Format("2006-01-02 15:04:05"))
+	opts.Timestamps.Start = s.StartTime
+	if format == hud.LogFormatJSON {
+		p := hud.NewJSONLinePrinter(deps.printer.Stdout())
+		p.Print(loghistory.Segments(records, opts))
+		p.Flush()
+		return nil
+	}
+	loghistory.Print(records, opts, func(lines []logstore.LogLine) {
+		deps.printer.Print(lines)
+	})
+	return nil
+}
+
+func isTiltRunning(url model.WebURL) bool {
+	conn, err := net.DialTimeout("tcp", url.Host, time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
 }
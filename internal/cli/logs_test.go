@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
+	"github.com/tilt-dev/tilt/internal/hud"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestLogsPreviousWhenTiltIsRunning(t *testing.T) {
+	f := newLogsPreviousFixture(t)
+	f.writeSession(loghistory.SessionPrevious, "from the last session\n")
+	f.writeSession(loghistory.SessionCurrent, "from this session\n")
+
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	cmd := &logsCmd{previous: true, noPrefix: true}
+	err = cmd.printPrevious(f.base, f.deps(model.WebURL(*u)), hud.LogFormatText, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from the last session\n", f.out.String())
+}
+
+func TestLogsPreviousWhenTiltExited(t *testing.T) {
+	f := newLogsPreviousFixture(t)
+	f.writeSession(loghistory.SessionPrevious, "from the session before\n")
+	f.writeSession(loghistory.SessionCurrent, "from the session that exited\n")
+
+	cmd := &logsCmd{previous: true, noPrefix: true}
+	err := cmd.printPrevious(f.base, f.deps(f.unusedURL()), hud.LogFormatText, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from the session that exited\n", f.out.String())
+}
+
+func TestLogsPreviousNoSession(t *testing.T) {
+	f := newLogsPreviousFixture(t)
+	cmd := &logsCmd{previous: true}
+	err := cmd.printPrevious(f.base, f.deps(f.unusedURL()), hud.LogFormatText, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No logs saved from the last time Tilt ran")
+}
+
+func TestLogsPreviousFollow(t *testing.T) {
+	f := newLogsPreviousFixture(t)
+	cmd := &logsCmd{previous: true, follow: true}
+	err := cmd.printPrevious(f.base, f.deps(f.unusedURL()), hud.LogFormatText, nil)
+	require.EqualError(t, err, "--previous can't be combined with --follow")
+}
+
+type logsPreviousFixture struct {
+	t    *testing.T
+	base xdg.Base
+	out  *bytes.Buffer
+}
+
+func newLogsPreviousFixture(t *testing.T) *logsPreviousFixture {
+	return &logsPreviousFixture{
+		t:    t,
+		base: xdg.FakeBase{Dir: t.TempDir()},
+		out:  &bytes.Buffer{},
+	}
+}
+
+func (f *logsPreviousFixture) deps(u model.WebURL) LogsDeps {
+	return ProvideLogsDeps(u, hud.NewIncrementalPrinter(f.out))
+}
+
+// A URL that nothing listens on.
+func (f *logsPreviousFixture) unusedURL() model.WebURL {
+	s := httptest.NewServer(http.NotFoundHandler())
+	u, err := url.Parse(s.URL)
+	require.NoError(f.t, err)
+	s.Close()
+	return model.WebURL(*u)
+}
+
+func (f *logsPreviousFixture) writeSession(session string, text string) {
+	dir, err := loghistory.SessionDir(f.base, model.ProvideAPIServerName(provideWebPort()), session)
+	require.NoError(f.t, err)
+
+	b, err := json.Marshal(loghistory.Session{StartTime: time.Now()})
+	require.NoError(f.t, err)
+	require.NoError(f.t, os.WriteFile(filepath.Join(dir, "session.json"), b, 0600))
+
+	b, err = json.Marshal(loghistory.Record{Time: time.Now(), Resource: "fe", SpanID: "pod:fe", Level: "info", Text: text})
+	require.NoError(f.t, err)
+	require.NoError(f.t, os.WriteFile(filepath.Join(dir, "logs.jsonl"), append(b, '\n'), 0600))
+}
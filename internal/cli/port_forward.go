@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How long to wait for a new port forward to start listening.
+const portForwardStartTimeout = 30 * time.Second
+
+type portForwardCmd struct {
+	streams genericclioptions.IOStreams
+	detach  bool
+}
+
+var _ tiltCmd = &portForwardCmd{}
+
+func newPortForwardCmd(streams genericclioptions.IOStreams) *portForwardCmd {
+	return &portForwardCmd{streams: streams}
+}
+
+func (c *portForwardCmd) name() model.TiltSubcommand { return "port-forward" }
+
+func (c *portForwardCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "port-forward RESOURCE [LOCAL_PORT:]REMOTE_PORT [...[LOCAL_PORT_N:]REMOTE_PORT_N]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Forward local ports to a Kubernetes resource",
+		Long: `Forward one or more local ports to the pod of a Kubernetes resource.
+
+The port forward follows the resource's current pod across updates, without
+editing the Tiltfile. It lasts until this command exits, or with --detach,
+until it's removed in the web UI or Tilt exits.
+`,
+		Example: `  # Listen on port 8080 locally, forwarding to port 80 in the pod
+  tilt port-forward frontend 8080:80
+
+  # Listen on port 5000 locally, forwarding to port 5000 in the pod
+  tilt port-forward frontend 5000
+
+  # Listen on a random local port, forwarding to port 5000 in the pod
+  tilt port-forward frontend :5000
+
+  # Keep forwarding after this command exits
+  tilt port-forward --detach frontend 8080:80`,
+		Args: cobra.MinimumNArgs(2),
+	}
+
+	cmd.Flags().BoolVar(&c.detach, "detach", false,
+		"Leave the port forwards running in Tilt after this command exits")
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c *portForwardCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{
+		"detach": strconv.FormatBool(c.detach),
+	})
+	a.Incr("cmd.port-forward", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	resource := args[0]
+	forwards, err := parsePortForwardSpecs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	api := portForwardAPI{url: apiURL("port_forwards")}
+	return c.forward(ctx, client, api, resource, forwards)
+}
+
+func (c *portForwardCmd) forward(ctx context.Context, client ctrlclient.Client, api portForwardAPI, resource string, forwards []v1alpha1.Forward) error {
+	var created []string
+	cleanup := func() {
+		if c.detach {
+			return
+		}
+		for _, name := range created {
+			err := api.delete(name)
+			if err != nil {
+				logger.Get(ctx).Infof("Error removing port forward %s: %v", name, err)
+			}
+		}
+	}
+
+	for _, forward := range forwards {
+		pf, err := api.create(resource, forward)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		created = append(created, pf.Name)
+
+		status, err := waitForPortForward(ctx, client, pf.Name)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		_, _ = fmt.Fprintf(c.streams.Out, "Forwarding from %s -> %d\n",
+			portForwardAddress(status), status.ContainerPort)
+	}
+
+	if c.detach {
+		_, _ = fmt.Fprintf(c.streams.Out, "Port forwards will run until they're removed or Tilt exits\n")
+		return nil
+	}
+
+	<-ctx.Done()
+	cleanup()
+	return nil
+}
+
+// Parses kubectl-style port specs: "LOCAL:REMOTE", "PORT" for the
+// same port on both sides, or ":REMOTE" for a random local port.
+func parsePortForwardSpecs(specs []string) ([]v1alpha1.Forward, error) {
+	var forwards []v1alpha1.Forward
+	for _, spec := range specs {
+		local, remote, found := strings.Cut(spec, ":")
+		if !found {
+			local = spec
+			remote = spec
+		}
+
+		containerPort, err := parsePort(remote, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q: %v", spec, err)
+		}
+		localPort := int32(0)
+		if local != "" {
+			localPort, err = parsePort(local, true)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q: %v", spec, err)
+			}
+		}
+		forwards = append(forwards, v1alpha1.Forward{LocalPort: localPort, ContainerPort: containerPort})
+	}
+	return forwards, nil
+}
+
+func parsePort(s string, allowZero bool) (int32, error) {
+	port, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if port > 65535 || port < 0 || (port == 0 && !allowZero) {
+		return 0, fmt.Errorf("%d is out of range", port)
+	}
+	return int32(port), nil
+}
+
+// Waits until the port forward is listening, and returns its status.
+func waitForPortForward(ctx context.Context, client ctrlclient.Client, name string) (v1alpha1.ForwardStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, portForwardStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		var pf v1alpha1.PortForward
+		err := client.Get(ctx, types.NamespacedName{Name: name}, &pf)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return v1alpha1.ForwardStatus{}, err
+		}
+		if apierrors.IsNotFound(err) {
+			return v1alpha1.ForwardStatus{}, fmt.Errorf("port forward %s was removed", name)
+		}
+
+		for _, status := range pf.Status.ForwardStatuses {
+			if status.Error != "" {
+				return status, fmt.Errorf("port forward %s failed: %s", name, status.Error)
+			}
+			if !status.StartedAt.IsZero() {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return v1alpha1.ForwardStatus{}, fmt.Errorf("timed out waiting for port forward %s to start", name)
+		case <-ticker.C:
+		}
+	}
+}
+
+func portForwardAddress(status v1alpha1.ForwardStatus) string {
+	host := "localhost"
+	if len(status.Addresses) == 1 {
+		host = status.Addresses[0]
+	}
+	return fmt.Sprintf("%s:%d", host, status.LocalPort)
+}
+
+// A client for the ad-hoc port forward endpoints of the Tilt web server.
+type portForwardAPI struct {
+	url string
+}
+
+func (api portForwardAPI) create(resource string, forward v1alpha1.Forward) (*v1alpha1.PortForward, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"resource":      resource,
+		"containerPort": forward.ContainerPort,
+		"localPort":     forward.LocalPort,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.Post(api.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to Tilt at %s: %v", api.url, err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response from Tilt: %v", err)
+	}
+	if res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("creating port forward: %s", strings.TrimSpace(string(body)))
+	}
+
+	var pf v1alpha1.PortForward
+	err = json.Unmarshal(body, &pf)
+	if err != nil {
+		return nil, fmt.Errorf("decoding port forward: %v", err)
+	}
+	return &pf, nil
+}
+
+func (api portForwardAPI) delete(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, api.url+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not connect to Tilt at %s: %v", api.url, err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestParsePortForwardSpecs(t *testing.T) {
+	forwards, err := parsePortForwardSpecs([]string{"8080:80", "5000", ":6000"})
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Forward{
+		{LocalPort: 8080, ContainerPort: 80},
+		{LocalPort: 5000, ContainerPort: 5000},
+		{LocalPort: 0, ContainerPort: 6000},
+	}, forwards)
+
+	_, err = parsePortForwardSpecs([]string{"8080:"})
+	assert.EqualError(t, err, `invalid port "8080:": "" is not a number`)
+	_, err = parsePortForwardSpecs([]string{"0"})
+	assert.EqualError(t, err, `invalid port "0": 0 is out of range`)
+	_, err = parsePortForwardSpecs([]string{"70000:80"})
+	assert.EqualError(t, err, `invalid port "70000:80": 70000 is out of range`)
+}
+
+func TestPortForward(t *testing.T) {
+	f := newServerFixture(t)
+	api := newFakePortForwardAPI(t, f)
+
+	out := bufsync.NewThreadSafeBuffer()
+	cmd := &portForwardCmd{streams: genericclioptions.IOStreams{Out: out}}
+
+	ctx, cancel := context.WithCancel(f.ctx)
+	done := make(chan error)
+	go func() {
+		done <- cmd.forward(ctx, f.client, portForwardAPI{url: api.URL}, "frontend",
+			[]v1alpha1.Forward{{LocalPort: 0, ContainerPort: 80}})
+	}()
+
+	out.AssertEventuallyContains(t, "Forwarding from localhost:34567 -> 80\n", 5*time.Second)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	var pf v1alpha1.PortForward
+	err := f.client.Get(f.ctx, types.NamespacedName{Name: "frontend-adhoc-80-0"}, &pf)
+	assert.True(t, apierrors.IsNotFound(err), "port forward should be deleted on exit")
+}
+
+func TestPortForwardDetach(t *testing.T) {
+	f := newServerFixture(t)
+	api := newFakePortForwardAPI(t, f)
+
+	out := &bytes.Buffer{}
+	cmd := &portForwardCmd{streams: genericclioptions.IOStreams{Out: out}, detach: true}
+	err := cmd.forward(f.ctx, f.client, portForwardAPI{url: api.URL}, "frontend",
+		[]v1alpha1.Forward{{LocalPort: 8080, ContainerPort: 80}})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Forwarding from localhost:8080 -> 80\n")
+
+	var pf v1alpha1.PortForward
+	err = f.client.Get(f.ctx, types.NamespacedName{Name: "frontend-adhoc-80-8080"}, &pf)
+	assert.NoError(t, err)
+}
+
+func TestPortForwardCreateError(t *testing.T) {
+	f := newServerFixture(t)
+	api := newFakePortForwardAPI(t, f)
+
+	cmd := &portForwardCmd{streams: genericclioptions.IOStreams{Out: &bytes.Buffer{}}}
+	err := cmd.forward(f.ctx, f.client, portForwardAPI{url: api.URL}, "backend",
+		[]v1alpha1.Forward{{LocalPort: 8080, ContainerPort: 80}})
+	assert.EqualError(t, err, "creating port forward: resource backend is not a Kubernetes resource")
+}
+
+// Stands in for the web server's port forward endpoints, and plays the
+// part of the port forward reconciler by marking new forwards as started.
+func newFakePortForwardAPI(t *testing.T, f *serverFixture) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/port_forwards", func(w http.ResponseWriter, req *http.Request) {
+		var payload struct {
+			Resource      string
+			ContainerPort int32
+			LocalPort     int32
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+		if payload.Resource != "frontend" {
+			http.Error(w, "resource backend is not a Kubernetes resource", http.StatusNotFound)
+			return
+		}
+
+		localPort := payload.LocalPort
+		if localPort == 0 {
+			localPort = 34567
+		}
+		pf := &v1alpha1.PortForward{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("frontend-adhoc-%d-%d", payload.ContainerPort, payload.LocalPort),
+				Annotations: map[string]string{v1alpha1.AnnotationManifest: payload.Resource},
+			},
+			Spec: v1alpha1.PortForwardSpec{
+				PodName:  "frontend-pod",
+				Forwards: []v1alpha1.Forward{{ContainerPort: payload.ContainerPort, LocalPort: payload.LocalPort}},
+			},
+		}
+		require.NoError(t, f.client.Create(f.ctx, pf))
+		pf.Status.ForwardStatuses = []v1alpha1.ForwardStatus{{
+			LocalPort:     localPort,
+			ContainerPort: payload.ContainerPort,
+			StartedAt:     apis.NowMicro(),
+		}}
+		require.NoError(t, f.client.Status().Update(f.ctx, pf))
+
+		w.WriteHeader(http.StatusCreated)
+		require.NoError(t, json.NewEncoder(w).Encode(pf))
+	})
+	mux.HandleFunc("/api/port_forwards/", func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodDelete, req.Method)
+		name := strings.TrimPrefix(req.URL.Path, "/api/port_forwards/")
+		err := f.client.Delete(context.Background(), &v1alpha1.PortForward{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		assert.NoError(t, err)
+	})
+
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	s.URL += "/api/port_forwards"
+	return s
+}
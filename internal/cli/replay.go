@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/snapshots"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type replayCmd struct {
+	streams genericclioptions.IOStreams
+	noOpen  bool
+}
+
+func newReplayCmd(streams genericclioptions.IOStreams) *replayCmd {
+	return &replayCmd{streams: streams}
+}
+
+func (c *replayCmd) name() model.TiltSubcommand { return "replay" }
+
+func (c *replayCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay <path/to/recording>",
+		Short: "Play back a recorded Tilt session in the browser",
+		Long: `Serves a session recorded with 'tilt up --record' in a read-only web UI.
+
+The UI starts at the end of the session. Use the replay controls at the top
+of the page to step through every update Tilt sent during the session.
+
+The recording also holds every apiserver object. Fetch
+/api/replay/objects/FRAME from the replay server to see them.`,
+		Example: `
+# Record a session
+tilt up --record=session.ndjson
+# Play it back
+tilt replay session.ndjson
+`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().BoolVar(&c.noOpen, "no-open", false, "Do not automatically open the recording in the browser")
+	addStartSnapshotViewServerFlags(cmd)
+
+	return cmd
+}
+
+func (c *replayCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.replay", nil)
+	defer a.Flush(time.Second)
+
+	recording, err := readRecording(c.streams.In, args[0])
+	if err != nil {
+		return fmt.Errorf("reading %s: %v", args[0], err)
+	}
+
+	host := provideWebHost()
+	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, snapshotViewPortFlag))
+	if err != nil {
+		return fmt.Errorf("could not get a free port: %w", err)
+	}
+	defer l.Close()
+
+	port := l.Addr().(*net.TCPAddr).Port
+	url := fmt.Sprintf("http://%s:%d/snapshot/local",
+		strings.Replace(string(host), "0.0.0.0", "127.0.0.1", 1),
+		port)
+
+	_, _ = fmt.Fprintf(c.streams.Out, "Replaying %d updates at %s\n", len(recording.Frames), url)
+
+	wg, ctx := errgroup.WithContext(ctx)
+	wg.Go(func() error {
+		return snapshots.ServeReplay(ctx, l, recording)
+	})
+
+	// give the server a little bit of time to spin up
+	time.Sleep(200 * time.Millisecond)
+
+	if !c.noOpen {
+		err := browser.OpenURL(url)
+		if err != nil {
+			return err
+		}
+	}
+
+	keyPressed := errors.New("pressed key to exit")
+	wg.Go(func() error {
+		_, _ = fmt.Fprintln(c.streams.Out, "Press any key to exit")
+		err := waitForKey(ctx)
+		if err != nil {
+			return err
+		}
+		return keyPressed
+	})
+
+	err = wg.Wait()
+	if err != nil && err != keyPressed {
+		return err
+	}
+
+	return nil
+}
+
+func readRecording(stdin io.Reader, path string) (*snapshots.Recording, error) {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		r = f
+		defer func() { _ = f.Close() }()
+	}
+
+	return snapshots.ReadRecording(r)
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	analytics2 "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type restartCmd struct {
+	streams genericclioptions.IOStreams
+}
+
+var _ tiltCmd = &restartCmd{}
+
+func newRestartCmd(streams genericclioptions.IOStreams) *restartCmd {
+	return &restartCmd{
+		streams: streams,
+	}
+}
+
+func (c restartCmd) name() model.TiltSubcommand {
+	return "restart"
+}
+
+func (c restartCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart RESOURCE_NAME",
+		Short: "Restart what a resource is running, without rebuilding it",
+		Long: `Restart what a resource is running, without rebuilding it.
+
+For a local resource, restarts its serve_cmd. The update cmd doesn't run.
+
+For a Kubernetes resource, deletes its pods, so that their Deployment
+(or other controller) recreates them from the same image.
+Pods that aren't managed by a controller won't come back.
+
+For a Docker Compose resource, runs 'docker compose restart' on its service.
+
+To rebuild the resource instead, use 'tilt trigger'.
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: resourceNameCompletion(1),
+	}
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c restartCmd) run(ctx context.Context, args []string) error {
+	resource := args[0]
+
+	a := analytics.Get(ctx)
+	a.Incr("cmd.restart", make(analytics2.CmdTags))
+	defer a.Flush(time.Second)
+
+	payload := []byte(fmt.Sprintf(`{"manifest_names":[%q]}`, resource))
+	r, status := apiPostJson("restart", payload)
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "error reading response from tilt api")
+	}
+	_ = r.Close()
+
+	body := strings.TrimSpace(string(b))
+	if status != http.StatusOK {
+		return fmt.Errorf("(%d): %s", status, body)
+	}
+	if len(body) > 0 {
+		return errors.New(body)
+	}
+
+	_, _ = fmt.Fprintf(c.streams.Out, "Successfully restarted resource: %q\n", resource)
+	return nil
+}
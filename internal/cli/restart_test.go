@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+)
+
+func TestRestartSuccess(t *testing.T) {
+	f := newRestartFixture(t)
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newRestartCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"foo"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"manifest_names":["foo"]}`, f.requestBody)
+	assert.Equal(t, "Successfully restarted resource: \"foo\"\n", out.String())
+}
+
+func TestRestartNothingRunning(t *testing.T) {
+	f := newRestartFixture(t)
+	f.responseStatus = http.StatusBadRequest
+	f.responseBody = `resource "foo" has nothing running to restart`
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newRestartCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"foo"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+
+	assert.Equal(t, `(400): resource "foo" has nothing running to restart`, err.Error())
+	assert.Equal(t, 0, out.Len())
+}
+
+type restartFixture struct {
+	ctx            context.Context
+	requestBody    string
+	responseBody   string
+	responseStatus int
+}
+
+func newRestartFixture(t *testing.T) *restartFixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	f := &restartFixture{ctx: ctx, responseStatus: http.StatusOK}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/restart", func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		f.requestBody = string(b)
+		if f.responseStatus != http.StatusOK || f.responseBody != "" {
+			http.Error(w, f.responseBody, f.responseStatus)
+		}
+	})
+	serveFakeTilt(t, mux)
+	return f
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tilt-dev/tilt/internal/runreport"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Flags for a machine-readable report of how each resource did,
+// written when Tilt exits.
+type runReportFlags struct {
+	output     string
+	outputFile string
+	junitFile  string
+}
+
+func (f *runReportFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.output, "output", "o", "",
+		"If set to json, Tilt will print a report of each resource's status, build times, errors, and logs when it exits")
+	cmd.Flags().StringVar(&f.outputFile, "output-file", "",
+		"If specified, the --output report is written to the specified path instead of stdout")
+	cmd.Flags().StringVar(&f.junitFile, "junit-report", "",
+		"If specified, Tilt will write a JUnit XML report of each resource's status to the specified path when it exits")
+}
+
+func (f *runReportFlags) validate() error {
+	if f.output != "" && f.output != "json" {
+		return fmt.Errorf("invalid --output %q: must be json", f.output)
+	}
+	if f.outputFile != "" && f.output == "" {
+		return fmt.Errorf("--output-file requires --output=json")
+	}
+	return nil
+}
+
+func (f *runReportFlags) enabled() bool {
+	return f.output != "" || f.junitFile != ""
+}
+
+// Writes the reports for a run that ended with runErr.
+func (f *runReportFlags) write(ctx context.Context, reporter *runreport.Reporter, runErr error) error {
+	if !f.enabled() {
+		return nil
+	}
+
+	// The run may have ended because the context was canceled,
+	// but the report still needs to read the final state.
+	ctx = logger.WithLogger(context.Background(), logger.Get(ctx))
+	report, err := reporter.Report(ctx, runErr)
+	if err != nil {
+		return fmt.Errorf("creating report: %v", err)
+	}
+
+	if f.output == "json" {
+		err := writeRunReportTo(f.outputFile, func(w io.Writer) error {
+			return runreport.WriteJSON(w, report)
+		})
+		if err != nil {
+			return fmt.Errorf("writing --output report: %v", err)
+		}
+	}
+	if f.junitFile != "" {
+		err := writeRunReportTo(f.junitFile, func(w io.Writer) error {
+			return runreport.WriteJUnit(w, report)
+		})
+		if err != nil {
+			return fmt.Errorf("writing --junit-report: %v", err)
+		}
+	}
+	return nil
+}
+
+// Writes to the file at path, or to stdout if path is empty.
+func writeRunReportTo(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
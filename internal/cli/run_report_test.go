@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/runreport"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestRunReportFlagsValidate(t *testing.T) {
+	assert.NoError(t, (&runReportFlags{}).validate())
+	assert.NoError(t, (&runReportFlags{output: "json", outputFile: "report.json"}).validate())
+	assert.EqualError(t, (&runReportFlags{output: "yaml"}).validate(), `invalid --output "yaml": must be json`)
+	assert.EqualError(t, (&runReportFlags{outputFile: "report.json"}).validate(), "--output-file requires --output=json")
+}
+
+func TestRunReportWrite(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusError,
+			RuntimeStatus: v1alpha1.RuntimeStatusNotApplicable,
+			BuildHistory:  []v1alpha1.UIBuildTerminated{{Error: "exit status 1"}},
+		},
+	})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	flags := runReportFlags{
+		output:     "json",
+		outputFile: filepath.Join(dir, "report.json"),
+		junitFile:  filepath.Join(dir, "junit.xml"),
+	}
+	reporter := runreport.NewReporter(store.NewTestingStore(), f.client)
+	err = flags.write(f.ctx, reporter, errors.New("my-sleep: exit status 1"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(flags.outputFile)
+	require.NoError(t, err)
+	var report runreport.Report
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, runreport.ResultFailure, report.Result)
+	require.Len(t, report.Resources, 1)
+	assert.Equal(t, "UpdateError", report.Resources[0].Reason)
+
+	data, err = os.ReadFile(flags.junitFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<failure message="UpdateError: exit status 1" type="UpdateError">`)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/cloud"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/snapshots"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
@@ -175,13 +177,21 @@ func (c *serveCmd) serveSnapshot(snapshotPath string) error {
 	return nil
 }
 
+type createSnapshotCmd struct {
+	output string
+}
+
 func newCreateSnapshotCommand() *cobra.Command {
+	c := &createSnapshotCmd{}
 	result := &cobra.Command{
 		Use:   "create [file to save]",
 		Short: "Creates a snapshot file from a currently running Tilt instance",
-		Long:  "Creates a snapshot file that can be viewed with `tilt snapshot view`",
+		Long: `Creates a snapshot file that can be viewed with ` + "`tilt snapshot view`" + `.
+
+Tilt builds the snapshot itself, so this works without a browser,
+e.g., to save a snapshot when a CI job fails.`,
 		Example: `
-tilt snapshot create snapshot.json
+tilt snapshot create -o snapshot.json
 # or if no file is specified, it goes to stdout
 tilt snapshot create > snapshot.json
 
@@ -189,38 +199,83 @@ tilt snapshot create > snapshot.json
 tilt snapshot view snapshot.json
 `,
 		Args: cobra.MaximumNArgs(1),
-		Run:  createSnapshot,
+		Run:  c.run,
 	}
 
+	result.Flags().StringVarP(&c.output, "output", "o", "", "File to save the snapshot to (default: stdout)")
 	addConnectServerFlags(result)
 
 	return result
 }
 
-func createSnapshot(cmd *cobra.Command, args []string) {
-	body := apiGet("view")
-
-	snapshot := proto_webview.Snapshot{
-		View:      &proto_webview.View{},
-		CreatedAt: timestamppb.Now(),
+func (c *createSnapshotCmd) run(cmd *cobra.Command, args []string) {
+	path := c.output
+	if len(args) > 0 {
+		if path != "" && path != args[0] {
+			cmdFail(fmt.Errorf("specify the file to save with --output or as an argument, not both"))
+		}
+		path = args[0]
 	}
 
-	jsEncoder := &runtime.JSONPb{}
-	err := jsEncoder.NewDecoder(body).Decode(&snapshot.View)
+	snapshot, err := fetchSnapshot()
 	if err != nil {
-		cmdFail(fmt.Errorf("error reading snapshot from tilt: %v", err))
+		cmdFail(err)
 	}
 
 	out := os.Stdout
-	if len(args) > 0 {
-		out, err = os.Create(args[0])
+	if path != "" {
+		out, err = os.Create(path)
 		if err != nil {
-			cmdFail(fmt.Errorf("error creating %s: %v", args[0], err))
+			cmdFail(fmt.Errorf("error creating %s: %v", path, err))
 		}
+		defer func() { _ = out.Close() }()
 	}
 
-	err = jsEncoder.NewEncoder(out).Encode(&snapshot)
+	err = cloud.WriteSnapshotTo(cmd.Context(), snapshot, out)
 	if err != nil {
 		cmdFail(fmt.Errorf("error serializing snapshot: %v", err))
 	}
 }
+
+// Asks Tilt for a snapshot of the current session.
+//
+// Older versions of Tilt can't build snapshots themselves (they serve the
+// web UI for any unknown path), so we fall back to wrapping the view.
+func fetchSnapshot() (*proto_webview.Snapshot, error) {
+	url := apiURL("snapshot")
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to Tilt at %s: %v", url, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode == http.StatusNotFound ||
+		(res.StatusCode == http.StatusOK && !strings.HasPrefix(res.Header.Get("Content-Type"), "application/json")) {
+		return snapshotFromView()
+	}
+	if res.StatusCode != http.StatusOK {
+		failWithNonOKResponse(url, res)
+	}
+
+	snapshot := &proto_webview.Snapshot{}
+	err = (&runtime.JSONPb{}).NewDecoder(res.Body).Decode(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot from tilt: %v", err)
+	}
+	return snapshot, nil
+}
+
+func snapshotFromView() (*proto_webview.Snapshot, error) {
+	body := apiGet("view")
+	defer func() { _ = body.Close() }()
+
+	snapshot := &proto_webview.Snapshot{
+		View:      &proto_webview.View{},
+		CreatedAt: timestamppb.Now(),
+	}
+	err := (&runtime.JSONPb{}).NewDecoder(body).Decode(&snapshot.View)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot from tilt: %v", err)
+	}
+	return snapshot, nil
+}
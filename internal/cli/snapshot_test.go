@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchSnapshot(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/snapshot", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"view": {"uiResources": [{"metadata": {"name": "fe"}}]}, "createdAt": "2022-01-01T00:00:00Z"}`)
+	})
+	serveFakeTilt(t, mux)
+	registerSnapshotCreateFlags(t)
+
+	snapshot, err := fetchSnapshot()
+	require.NoError(t, err)
+	require.Len(t, snapshot.View.UiResources, 1)
+	assert.Equal(t, "fe", snapshot.View.UiResources[0].Name)
+	assert.Equal(t, int64(1640995200), snapshot.CreatedAt.Seconds)
+}
+
+func TestFetchSnapshotFromOlderServer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/snapshot", func(w http.ResponseWriter, req *http.Request) {
+		// Older servers serve the web UI for unknown paths.
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, "<html></html>")
+	})
+	mux.HandleFunc("/api/view", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"uiResources": [{"metadata": {"name": "fe"}}]}`)
+	})
+	serveFakeTilt(t, mux)
+	registerSnapshotCreateFlags(t)
+
+	snapshot, err := fetchSnapshot()
+	require.NoError(t, err)
+	require.Len(t, snapshot.View.UiResources, 1)
+	assert.Equal(t, "fe", snapshot.View.UiResources[0].Name)
+	assert.NotNil(t, snapshot.CreatedAt)
+}
+
+func registerSnapshotCreateFlags(t *testing.T) {
+	cmd := newCreateSnapshotCommand()
+	require.NoError(t, cmd.Flags().Parse(nil))
+}
+
+// Serves a fake Tilt API on the port that commands connect to.
+//
+// Listens before returning, so that commands can't race the server.
+func serveFakeTilt(t *testing.T, handler http.Handler) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	origPort := defaultWebPort
+	defaultWebPort = l.Addr().(*net.TCPAddr).Port
+	t.Cleanup(func() {
+		defaultWebPort = origPort
+	})
+
+	srv := &http.Server{Handler: handler}
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/buildhistory"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// A CLI for summarizing a project's build history on this machine.
+type statsCmd struct {
+	streams genericclioptions.IOStreams
+	base    xdg.Base
+
+	fileName string
+	resource string
+	weeks    int
+}
+
+var _ tiltCmd = &statsCmd{}
+
+func newStatsCmd(streams genericclioptions.IOStreams) *statsCmd {
+	return &statsCmd{streams: streams, base: xdg.NewTiltDevBase()}
+}
+
+func (c *statsCmd) name() model.TiltSubcommand { return "stats" }
+
+func (c *statsCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize how your dev loop has performed over the last few weeks",
+		Long: fmt.Sprintf(`Summarize how your dev loop has performed over the last few weeks.
+
+Tilt keeps the history of each resource's builds on this machine, across
+sessions (the last %d builds of each resource). This prints, for each week:
+
+- How many Tilt sessions built something
+- How many builds there were, and how many failed
+- The median and 90th percentile build time
+- How many builds did a live update, and their median time
+
+Doesn't need a running Tilt, and never sends the history anywhere.
+`, buildhistory.MaxRecords),
+		Args: cobra.NoArgs,
+		Example: `
+# Summarizes the last 4 weeks of the project in this directory
+tilt stats
+
+# Summarizes the last 12 weeks of the resource 'frontend'
+tilt stats --weeks 12 --resource frontend
+`,
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+	cmd.Flags().StringVar(&c.resource, "resource", "", "Only summarize the builds of this resource")
+	cmd.Flags().IntVar(&c.weeks, "weeks", 4, "Number of weeks to summarize")
+	return cmd
+}
+
+func (c *statsCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.stats", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	if c.weeks < 1 {
+		return fmt.Errorf("--weeks must be at least 1")
+	}
+
+	tiltfilePath := ctrltiltfile.ResolveFilename(c.fileName)
+	records, err := buildhistory.ReadHistory(c.base, tiltfilePath)
+	if err != nil {
+		return err
+	}
+	if c.resource != "" {
+		if _, ok := records[c.resource]; !ok {
+			return fmt.Errorf("no build history for resource %q", c.resource)
+		}
+	}
+	if len(records) == 0 {
+		_, _ = fmt.Fprintf(c.streams.Out, "No build history for %s yet. Run tilt up to start recording it.\n", tiltfilePath)
+		return nil
+	}
+
+	printStats(c.streams.Out, buildhistory.WeeklyStats(records, c.resource, c.weeks, time.Now()))
+	return nil
+}
+
+func printStats(out io.Writer, stats []buildhistory.WeekStats) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "WEEK\tSESSIONS\tBUILDS\tFAILED\tMEDIAN\tP90\tLIVE UPDATES\tMEDIAN LIVE UPDATE")
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d (%.0f%%)\t%s\t%s\t%d\t%s\n",
+			s.Start.Format("2006-01-02"), s.Sessions, s.Builds, s.Failures, 100*s.FailureRate(),
+			formatStatsDuration(s.MedianBuild), formatStatsDuration(s.P90Build),
+			s.LiveUpdates, formatStatsDuration(s.MedianLiveUpdate))
+	}
+	_ = w.Flush()
+}
+
+func formatStatsDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/engine/buildhistory"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+func TestPrintStats(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	printStats(out, []buildhistory.WeekStats{
+		{Start: time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local)},
+		{
+			Start:            time.Date(2024, 5, 13, 0, 0, 0, 0, time.Local),
+			Sessions:         2,
+			Builds:           4,
+			Failures:         1,
+			MedianBuild:      2345 * time.Millisecond,
+			P90Build:         10 * time.Second,
+			LiveUpdates:      2,
+			MedianLiveUpdate: 456 * time.Millisecond,
+		},
+	})
+
+	expected := `WEEK        SESSIONS  BUILDS  FAILED   MEDIAN  P90  LIVE UPDATES  MEDIAN LIVE UPDATE
+2024-05-06  0         0       0 (0%)   -       -    0             -
+2024-05-13  2         4       1 (25%)  2.3s    10s  2             456ms
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestStatsNoHistory(t *testing.T) {
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newStatsCmd(streams)
+	cmd.base = xdg.FakeBase{Dir: t.TempDir()}
+	cmd.fileName = "/project/Tiltfile"
+	cmd.weeks = 4
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	err := cmd.run(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "No build history for /project/Tiltfile yet. Run tilt up to start recording it.\n", out.String())
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Exit codes for `tilt status`, so that scripts can branch on the
+// state of the session without parsing the output.
+//
+// Any other failure (like not being able to reach Tilt) exits 1.
+const (
+	statusExitReady   = 0
+	statusExitPending = 2
+	statusExitError   = 3
+)
+
+type statusCmd struct {
+	streams genericclioptions.IOStreams
+	quiet   bool
+}
+
+var _ tiltCmd = &statusCmd{}
+
+func newStatusCmd(streams genericclioptions.IOStreams) *statusCmd {
+	return &statusCmd{streams: streams}
+}
+
+func (c *statusCmd) name() model.TiltSubcommand { return "status" }
+
+func (c *statusCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [RESOURCE_NAME...]",
+		Short: "Print whether resources are ready, and exit with a code that says the same",
+		Long: `Print whether resources are ready, and exit with a code that says the same.
+
+With no arguments, checks every resource. Disabled resources, and manual
+resources that haven't been triggered, are shown but don't count.
+
+Exit codes:
+  0  every resource is ready
+  1  couldn't get the status (e.g., Tilt isn't running)
+  2  some resources are still updating or starting
+  3  some resources have an error
+
+Use --quiet to only set the exit code, e.g., in a shell prompt.
+`,
+		Example: `  tilt status
+  tilt status frontend backend
+  tilt status -q && echo "all good"`,
+		ValidArgsFunction: resourceNameCompletion(0),
+	}
+
+	cmd.Flags().BoolVarP(&c.quiet, "quiet", "q", false, "Don't print anything, only exit with the status code")
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c *statusCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.status", map[string]string{})
+	defer a.Flush(time.Second)
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	var list v1alpha1.UIResourceList
+	err = client.List(ctx, &list)
+	if err != nil {
+		return err
+	}
+
+	resources, err := selectStatusResources(list.Items, args)
+	if err != nil {
+		return err
+	}
+
+	readiness := webview.AggregateReadiness(resources)
+	if !c.quiet {
+		err = printStatus(c.streams.Out, readiness)
+		if err != nil {
+			return err
+		}
+	}
+
+	code := statusExitCode(readiness)
+	if code != statusExitReady {
+		a.Flush(time.Second)
+		os.Exit(code)
+	}
+	return nil
+}
+
+// Picks out the named resources, or all of them if none are named.
+func selectStatusResources(resources []v1alpha1.UIResource, names []string) ([]v1alpha1.UIResource, error) {
+	if len(names) == 0 {
+		return resources, nil
+	}
+
+	byName := make(map[string]v1alpha1.UIResource, len(resources))
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	var result []v1alpha1.UIResource
+	var missing []string
+	for _, name := range names {
+		r, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		result = append(result, r)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no resources named: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+func printStatus(out io.Writer, readiness webview.Readiness) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSTATUS\tREASON")
+	for _, r := range readiness.Resources {
+		reason := r.Reason
+		if r.Message != "" {
+			reason = fmt.Sprintf("%s: %s", reason, r.Message)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, r.Status, reason)
+	}
+	return w.Flush()
+}
+
+func statusExitCode(readiness webview.Readiness) int {
+	switch readiness.Status {
+	case webview.ReadinessReady:
+		return statusExitReady
+	case webview.ReadinessError:
+		return statusExitError
+	}
+	return statusExitPending
+}
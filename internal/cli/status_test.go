@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestStatusReady(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusOK,
+			RuntimeStatus: v1alpha1.RuntimeStatusOK,
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	cmd := newStatusCmd(genericclioptions.IOStreams{Out: out})
+	c := cmd.register()
+	err = c.Flags().Parse(nil)
+	require.NoError(t, err)
+
+	err = cmd.run(f.ctx, []string{"fe"})
+	require.NoError(t, err)
+	assert.Equal(t, "NAME  STATUS  REASON\nfe    ready   \n", out.String())
+
+	err = cmd.run(f.ctx, []string{"fe", "be"})
+	require.EqualError(t, err, "no resources named: be")
+}
+
+func TestStatusExitCode(t *testing.T) {
+	resources := []v1alpha1.UIResource{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusOK,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "updating"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusInProgress,
+				RuntimeStatus: v1alpha1.RuntimeStatusPending,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus: v1alpha1.UpdateStatusError,
+				BuildHistory: []v1alpha1.UIBuildTerminated{{Error: "exit status 1"}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		names    []string
+		expected int
+	}{
+		{[]string{"ready"}, statusExitReady},
+		{[]string{"ready", "updating"}, statusExitPending},
+		{nil, statusExitError},
+	} {
+		selected, err := selectStatusResources(resources, tc.names)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, statusExitCode(webview.AggregateReadiness(selected)), "names: %v", tc.names)
+	}
+
+	// A session with no resources hasn't loaded yet.
+	assert.Equal(t, statusExitPending, statusExitCode(webview.AggregateReadiness(nil)))
+}
+
+func TestPrintStatus(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	err := printStatus(out, webview.Readiness{Resources: []webview.ResourceReadiness{
+		{Name: "broken", Status: webview.ReadinessError, Reason: "UpdateError", Message: "exit status 1"},
+		{Name: "manual", Status: webview.ReadinessSkipped, Reason: "NotTriggered"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, `NAME    STATUS   REASON
+broken  error    UpdateError: exit status 1
+manual  skipped  NotTriggered
+`, out.String())
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/apitoken"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newTokenCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	result := &cobra.Command{
+		Use:   "token",
+		Short: "Manage tokens for Tilt's external HTTP API",
+		Long: `Manage tokens for Tilt's external HTTP API.
+
+Scripts and editor plugins use these tokens to trigger resources,
+enable or disable them, query their status, and stream logs, at ` + server.ExternalAPIPrefix + `.
+
+Tokens are stored per-user, and apply to every Tilt session on this machine.
+`,
+	}
+
+	addCommand(result, &tokenCreateCmd{streams: streams})
+	addCommand(result, &tokenListCmd{streams: streams})
+	addCommand(result, &tokenRevokeCmd{streams: streams})
+
+	return result
+}
+
+func newTokenStore() *apitoken.Store {
+	return apitoken.NewStore(xdg.NewTiltDevBase())
+}
+
+func incrTokenCmd(ctx context.Context, action string) {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.token", engineanalytics.CmdTags{"action": action}.AsMap())
+	a.Flush(time.Second)
+}
+
+type tokenCreateCmd struct {
+	streams genericclioptions.IOStreams
+	role    string
+}
+
+var _ tiltCmd = &tokenCreateCmd{}
+
+func (c *tokenCreateCmd) name() model.TiltSubcommand { return "token-create" }
+
+func (c *tokenCreateCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create an API token",
+		Long: `Create an API token, and print it.
+
+The token is only printed once. Tilt only keeps a hash of it.
+
+The token's role decides what it may do:
+  viewer: query resources and stream logs
+  operator: also trigger resources
+  owner: everything, including enabling and disabling resources
+`,
+		Example: `tilt token create vscode
+
+tilt token create dashboard --role=viewer
+
+curl -H "Authorization: Bearer $TOKEN" http://localhost:10350` + server.ExternalAPIPrefix + `/resources`,
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&c.role, "role", string(server.TeamRoleOwner), "What the token may do: viewer, operator, or owner")
+	return cmd
+}
+
+func (c *tokenCreateCmd) run(ctx context.Context, args []string) error {
+	incrTokenCmd(ctx, "create")
+
+	role, err := server.ParseTeamRole(c.role)
+	if err != nil {
+		return err
+	}
+
+	secret, err := newTokenStore().Create(args[0], string(role), time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(c.streams.Out, secret)
+	_, _ = fmt.Fprintf(c.streams.ErrOut, "\nCreated token %q. Copy it now; it won't be shown again.\n", args[0])
+	_, _ = fmt.Fprintf(c.streams.ErrOut, "Send it in an 'Authorization: Bearer <token>' header to %s.\n", server.ExternalAPIPrefix)
+	return nil
+}
+
+type tokenListCmd struct {
+	streams genericclioptions.IOStreams
+}
+
+var _ tiltCmd = &tokenListCmd{}
+
+func (c *tokenListCmd) name() model.TiltSubcommand { return "token-list" }
+
+func (c *tokenListCmd) register() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens",
+		Args:  cobra.NoArgs,
+	}
+}
+
+func (c *tokenListCmd) run(ctx context.Context, args []string) error {
+	incrTokenCmd(ctx, "list")
+
+	tokens, err := newTokenStore().List()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.streams.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tROLE\tCREATED")
+	for _, t := range tokens {
+		role := t.Role
+		if role == "" {
+			role = string(server.TeamRoleOwner)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, role, t.CreatedAt.Local().Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+type tokenRevokeCmd struct {
+	streams genericclioptions.IOStreams
+}
+
+var _ tiltCmd = &tokenRevokeCmd{}
+
+func (c *tokenRevokeCmd) name() model.TiltSubcommand { return "token-revoke" }
+
+func (c *tokenRevokeCmd) register() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke NAME",
+		Short: "Revoke an API token",
+		Long: `Revoke an API token.
+
+Running Tilt sessions stop accepting the token immediately.
+`,
+		Args: cobra.ExactArgs(1),
+	}
+}
+
+func (c *tokenRevokeCmd) run(ctx context.Context, args []string) error {
+	incrTokenCmd(ctx, "revoke")
+
+	err := newTokenStore().Revoke(args[0])
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.streams.Out, "Revoked token %q\n", args[0])
+	return nil
+}
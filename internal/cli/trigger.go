@@ -19,6 +19,7 @@ import (
 
 type triggerCmd struct {
 	streams genericclioptions.IOStreams
+	whenUp  whenUpFlags
 }
 
 var _ tiltCmd = &triggerCmd{}
@@ -29,11 +30,11 @@ func newTriggerCmd(streams genericclioptions.IOStreams) *triggerCmd {
 	}
 }
 
-func (t triggerCmd) name() model.TiltSubcommand {
+func (t *triggerCmd) name() model.TiltSubcommand {
 	return "trigger"
 }
 
-func (t triggerCmd) register() *cobra.Command {
+func (t *triggerCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "trigger [RESOURCE_NAME]",
 		Short: "Trigger an update for the specified resource",
@@ -42,14 +43,19 @@ func (t triggerCmd) register() *cobra.Command {
 If the resource has Trigger Mode: Manual and has pending changes, this command will cause those pending changes to be applied.
 
 Otherwise, this command will force a full rebuild.
+
+With --when-up, a trigger sent while Tilt is starting or reloading its
+Tiltfile is retried until Tilt is up and the resource exists.
 `,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: resourceNameCompletion(1),
 	}
 	addConnectServerFlags(cmd)
+	t.whenUp.addFlags(cmd)
 	return cmd
 }
 
-func (t triggerCmd) run(ctx context.Context, args []string) error {
+func (t *triggerCmd) run(ctx context.Context, args []string) error {
 	resource := args[0]
 
 	a := analytics.Get(ctx)
@@ -60,7 +66,22 @@ func (t triggerCmd) run(ctx context.Context, args []string) error {
 	//   like a lot of code to move over (to avoid import cycles) for one call.
 	payload := []byte(fmt.Sprintf(`{"manifest_names":[%q], "build_reason": %d}`, resource, model.BuildReasonFlagTriggerCLI))
 
-	r, status := apiPostJson("trigger", payload)
+	err := t.whenUp.retry(ctx, t.streams.ErrOut, func() error {
+		return t.trigger(payload)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(t.streams.Out, "Successfully triggered update for resource: %q\n", resource)
+	return nil
+}
+
+func (t *triggerCmd) trigger(payload []byte) error {
+	r, status, err := tryAPIPostJson("trigger", payload)
+	if err != nil {
+		return err
+	}
 
 	b, err := io.ReadAll(r)
 	if err != nil {
@@ -69,13 +90,15 @@ func (t triggerCmd) run(ctx context.Context, args []string) error {
 	_ = r.Close()
 
 	body := strings.TrimSpace(string(b))
+	if status == http.StatusNotFound && t.whenUp.enabled {
+		// The Tiltfile may not have loaded the resource yet.
+		return notUpError{fmt.Errorf("(%d): %s", status, body)}
+	}
 	if status != http.StatusOK {
 		return fmt.Errorf("(%d): %s", status, body)
 	}
 	if len(body) > 0 {
 		return errors.New(body)
 	}
-
-	_, _ = fmt.Fprintf(t.streams.Out, "Successfully triggered update for resource: %q\n", resource)
 	return nil
 }
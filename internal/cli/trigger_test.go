@@ -2,9 +2,10 @@ package cli
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/tilt-dev/tilt/internal/testutils"
 
@@ -58,15 +59,29 @@ func TestTriggerNotFound(t *testing.T) {
 	require.Equal(t, 0, out.Len())
 }
 
-type triggerFixture struct {
-	responseBody   string
-	responseStatus int
-	ctx            context.Context
+func TestTriggerWhenUp(t *testing.T) {
+	f := newTriggerFixture(t)
+	f.responseStatus = http.StatusNotFound
+	f.responseBody = `resource "foo" does not exist`
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		f.setResponse(http.StatusOK, "")
+	}()
+
+	streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+	cmd := newTriggerCmd(streams)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{"--when-up", "foo"})
+	require.NoError(t, err)
+	err = cmd.run(f.ctx, c.Flags().Args())
+	require.NoError(t, err)
+
+	require.Equal(t, "Successfully triggered update for resource: \"foo\"\n", out.String())
+	require.Contains(t, errOut.String(), "Waiting for Tilt")
 }
 
-func newTriggerFixture(t *testing.T) *triggerFixture {
+func TestTriggerWhenUpTimeout(t *testing.T) {
 	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
-
 	port, err := freeport.GetFreePort()
 	require.NoError(t, err)
 	origPort := defaultWebPort
@@ -75,6 +90,28 @@ func newTriggerFixture(t *testing.T) *triggerFixture {
 		defaultWebPort = origPort
 	})
 
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newTriggerCmd(streams)
+	c := cmd.register()
+	err = c.Flags().Parse([]string{"--when-up", "--when-up-timeout=300ms", "foo"})
+	require.NoError(t, err)
+	err = cmd.run(ctx, c.Flags().Args())
+	require.Error(t, err)
+
+	require.Contains(t, err.Error(), "Tilt wasn't up after 300ms: Could not connect to Tilt")
+	require.Equal(t, 0, out.Len())
+}
+
+type triggerFixture struct {
+	mu             sync.Mutex
+	responseBody   string
+	responseStatus int
+	ctx            context.Context
+}
+
+func newTriggerFixture(t *testing.T) *triggerFixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
 	f := &triggerFixture{
 		ctx:            ctx,
 		responseStatus: http.StatusOK,
@@ -82,17 +119,18 @@ func newTriggerFixture(t *testing.T) *triggerFixture {
 
 	mux := &http.ServeMux{}
 	mux.HandleFunc("/api/trigger", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
 		http.Error(w, f.responseBody, f.responseStatus)
 	})
-
-	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%d", defaultWebPort),
-		Handler: mux,
-	}
-	go func() { _ = srv.ListenAndServe() }()
-	t.Cleanup(func() {
-		_ = srv.Shutdown(ctx)
-	})
+	serveFakeTilt(t, mux)
 
 	return f
 }
+
+func (f *triggerFixture) setResponse(status int, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responseStatus = status
+	f.responseBody = body
+}
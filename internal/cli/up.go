@@ -11,18 +11,27 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
+	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
+	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/tracer"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	"github.com/tilt-dev/tilt/web"
 )
 
@@ -33,6 +42,14 @@ const DefaultWebDevPort = 46764
 var updateModeFlag string = string(liveupdates.UpdateModeAuto)
 var webDevPort = 0
 var logActionsFlag bool = false
+var logHistoryFlag bool = true
+var controllerWorkersFlag map[string]int
+var logFormatFlag = string(hud.LogFormatText)
+var logTimestampsFlag string
+var logTimezoneFlag = "local"
+var oidcConfigFlags server.OIDCConfig
+var teamLocalOwnerFlag bool
+var otlpEndpointFlag string
 
 var userExitError = errors.New("user requested Tilt exit")
 
@@ -42,9 +59,16 @@ var starterTiltfile []byte
 type upCmd struct {
 	fileName             string
 	outputSnapshotOnExit string
+	recordPath           string
+	report               runReportFlags
 
 	legacy bool
 	stream bool
+
+	logMaxSize            string
+	logMaxSizePerResource string
+	logSpill              bool
+	logMaxLineLength      string
 }
 
 func (c *upCmd) name() model.TiltSubcommand { return "up" }
@@ -84,8 +108,26 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	addTiltfileFlag(cmd, &c.fileName)
 	addKubeContextFlag(cmd)
 	addNamespaceFlag(cmd)
+	addDevEnvFlag(cmd)
 	cmd.Flags().Lookup("logactions").Hidden = true
+	addTeamAuthFlags(cmd)
+	addOTLPEndpointFlag(cmd)
+	addLogFormatFlag(cmd)
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "", "If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().StringVar(&c.recordPath, "record", "",
+		"If specified, Tilt will record every update to its resources and logs to the specified path, to play back later with 'tilt replay'")
+	cmd.Flags().StringVar(&c.logMaxSize, "log-max-size", "2MB",
+		"How many logs to keep in memory across all resources (e.g., 500KB, 10MB). When logs grow past this, Tilt truncates the oldest ones.")
+	cmd.Flags().StringVar(&c.logMaxSizePerResource, "log-max-size-per-resource", "",
+		"How many logs to keep in memory for any one resource (e.g., 1MB). If not set, resources are only limited by --log-max-size.")
+	cmd.Flags().StringVar(&c.logMaxLineLength, "log-max-line-length", "10KB",
+		"The longest log line to keep (e.g., 64KB). Longer lines are cut short, with a note of how much was cut.")
+	cmd.Flags().BoolVar(&c.logSpill, "log-spill", true,
+		"If true, Tilt saves truncated logs to compressed files in a temp directory, so you can still load them from the web UI.")
+	cmd.Flags().BoolVar(&logHistoryFlag, "log-history", true,
+		"If true, Tilt saves this session's logs to disk, so that you can read them after Tilt restarts with 'tilt logs --previous'.")
+	addControllerWorkersFlag(cmd)
+	c.report.addFlags(cmd)
 
 	return cmd
 }
@@ -99,7 +141,7 @@ func (c *upCmd) initialTermMode(isTerminal bool) store.TerminalMode {
 		return store.TerminalModeHUD
 	}
 
-	if c.stream {
+	if c.stream || logFormatFlag == string(hud.LogFormatJSON) {
 		return store.TerminalModeStream
 	}
 
@@ -122,6 +164,21 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 		"term_mode":   strconv.Itoa(int(termMode)),
 	})
 
+	err := c.report.validate()
+	if err != nil {
+		return err
+	}
+
+	logRetention, err := c.logRetention()
+	if err != nil {
+		return err
+	}
+	if logRetention.SpillDir != "" {
+		defer func() {
+			_ = os.RemoveAll(logRetention.SpillDir)
+		}()
+	}
+
 	generateTiltfileResult, err := maybeGenerateTiltfile(c.fileName)
 	// N.B. report the command before handling the error; result enum is always valid
 	cmdUpTags["generate_tiltfile.result"] = string(generateTiltfileResult)
@@ -145,6 +202,12 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
+	err = upDevEnv(ctx, xdg.NewTiltDevBase(), ctrltiltfile.ResolveFilename(c.fileName))
+	if err != nil {
+		deferred.SetOutput(deferred.Original())
+		return err
+	}
+
 	cmdUpDeps, err := wireCmdUp(ctx, a, cmdUpTags, "up")
 	if err != nil {
 		deferred.SetOutput(deferred.Original())
@@ -164,14 +227,58 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 	if c.outputSnapshotOnExit != "" {
 		defer cmdUpDeps.Snapshotter.WriteSnapshot(ctx, c.outputSnapshotOnExit)
 	}
+	if c.recordPath != "" {
+		stopRecording, err := cmdUpDeps.Recorder.Start(ctx, c.recordPath)
+		if err != nil {
+			return err
+		}
+		defer stopRecording()
+	}
 
 	err = upper.Start(ctx, args, cmdUpDeps.TiltBuild,
-		c.fileName, termMode, a.UserOpt(), cmdUpDeps.Token, string(cmdUpDeps.CloudAddress))
-	if err != context.Canceled {
-		return err
-	} else {
-		return nil
+		c.fileName, termMode, a.UserOpt(), cmdUpDeps.Token, string(cmdUpDeps.CloudAddress), logRetention)
+	if err == context.Canceled {
+		err = nil
+	}
+
+	reportErr := c.report.write(ctx, cmdUpDeps.Reporter, err)
+	if err == nil {
+		err = reportErr
 	}
+	return err
+}
+
+// Parses the log retention flags, and creates the directory for truncated logs.
+func (c *upCmd) logRetention() (logstore.Retention, error) {
+	var r logstore.Retention
+	maxSize, err := units.FromHumanSize(c.logMaxSize)
+	if err != nil || maxSize <= 0 {
+		return r, fmt.Errorf("invalid --log-max-size %q: must be a size like 2MB", c.logMaxSize)
+	}
+	r.MaxBytes = int(maxSize)
+
+	if c.logMaxSizePerResource != "" {
+		maxSize, err := units.FromHumanSize(c.logMaxSizePerResource)
+		if err != nil || maxSize <= 0 {
+			return r, fmt.Errorf("invalid --log-max-size-per-resource %q: must be a size like 1MB", c.logMaxSizePerResource)
+		}
+		r.MaxBytesPerManifest = int(maxSize)
+	}
+
+	maxLineLength, err := units.FromHumanSize(c.logMaxLineLength)
+	if err != nil || maxLineLength <= 0 {
+		return r, fmt.Errorf("invalid --log-max-line-length %q: must be a size like 10KB", c.logMaxLineLength)
+	}
+	r.MaxLineLength = int(maxLineLength)
+
+	if c.logSpill {
+		dir, err := os.MkdirTemp("", "tilt-logs-")
+		if err != nil {
+			return r, fmt.Errorf("creating directory for truncated logs: %v", err)
+		}
+		r.SpillDir = dir
+	}
+	return r, nil
 }
 
 func redirectLogs(ctx context.Context, l logger.Logger) context.Context {
@@ -189,6 +296,33 @@ func provideLogActions() store.LogActionsFlag {
 	return store.LogActionsFlag(logActionsFlag)
 }
 
+func provideLogHistory() loghistory.Enabled {
+	return loghistory.Enabled(logHistoryFlag)
+}
+
+func provideControllerWorkers() controllers.ControllerWorkers {
+	return controllers.ControllerWorkers(controllerWorkersFlag)
+}
+
+func addLogFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&logFormatFlag, "log-format", string(hud.LogFormatText),
+		"How to print logs to stdout. One of: text, json. JSON lines have the resource, span, level, and time of each line, "+
+			"for tools like jq or a log shipper. Implies --stream.")
+	cmd.Flags().StringVar(&logTimestampsFlag, "log-timestamps", "",
+		"Put a timestamp in front of each log line printed to stdout. One of: none, absolute, relative. "+
+			"Relative timestamps count from when Tilt started. Only applies to the text log format.")
+	cmd.Flags().StringVar(&logTimezoneFlag, "log-timezone", "local",
+		"The time zone of absolute log timestamps. One of: local, utc.")
+}
+
+func provideLogFormat() (hud.LogFormat, error) {
+	return hud.ParseLogFormat(logFormatFlag)
+}
+
+func provideLogTimestamps() (logstore.Timestamps, error) {
+	return logstore.ParseTimestamps(logTimestampsFlag, logTimezoneFlag)
+}
+
 func provideWebMode(b model.TiltBuild) (model.WebMode, error) {
 	switch webModeFlag {
 	case model.LocalWebMode,
@@ -214,6 +348,50 @@ func provideWebMode(b model.TiltBuild) (model.WebMode, error) {
 	return "", model.UnrecognizedWebModeError(string(webModeFlag))
 }
 
+// Team mode secrets come from env variables, so that they don't show up in
+// the process list.
+func addTeamAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&oidcConfigFlags.IssuerURL, "oidc-issuer", "",
+		"OpenID Connect issuer URL. If set, people on other machines can sign in to the web UI with this provider. Requires --oidc-client-id and the TILT_OIDC_CLIENT_SECRET env variable.")
+	cmd.Flags().StringVar(&oidcConfigFlags.ClientID, "oidc-client-id", "", "OpenID Connect client ID")
+	cmd.Flags().StringVar(&oidcConfigFlags.RedirectURL, "oidc-redirect-url", "",
+		"OpenID Connect redirect URL. Only necessary behind a reverse proxy. Defaults to /auth/oidc/callback on the requested host.")
+	cmd.Flags().StringSliceVar(&oidcConfigFlags.ViewerEmails, "oidc-viewer", nil,
+		"Emails that may sign in with OpenID Connect and watch this session, read-only. Use @example.com to allow a whole domain.")
+	cmd.Flags().StringSliceVar(&oidcConfigFlags.OperatorEmails, "oidc-operator", nil,
+		"Emails that may sign in with OpenID Connect, watch this session, and trigger updates, but not change settings. Use @example.com to allow a whole domain.")
+	cmd.Flags().StringSliceVar(&oidcConfigFlags.OwnerEmails, "oidc-owner", nil,
+		"Emails that may sign in with OpenID Connect and get full control of this session. Use @example.com to allow a whole domain.")
+	cmd.Flags().BoolVar(&teamLocalOwnerFlag, "team-local-owner", false,
+		"If true, requests from this machine get full control without signing in, so that commands like 'tilt trigger' keep working when team mode is on. Don't use behind a reverse proxy on this machine that doesn't set X-Forwarded-For.")
+}
+
+// Team mode is on if there's any way for people on other machines to sign in.
+// Otherwise, the web server works as it always has.
+func provideTeamAuthConfig() (server.TeamAuthConfig, error) {
+	oidc := oidcConfigFlags
+	oidc.ClientSecret = os.Getenv("TILT_OIDC_CLIENT_SECRET")
+	config := server.TeamAuthConfig{
+		ViewerToken: os.Getenv("TILT_VIEWER_TOKEN"),
+		LocalOwner:  teamLocalOwnerFlag,
+		OIDC:        oidc,
+	}
+	err := config.OIDC.Validate()
+	if err != nil {
+		return server.TeamAuthConfig{}, err
+	}
+	return config, nil
+}
+
+func addOTLPEndpointFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&otlpEndpointFlag, "otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		"If set, export a trace of each update (from file change to build, push, deploy, and ready) to this OpenTelemetry collector, as host:port or an http(s):// URL. Uses gRPC. Defaults to the OTEL_EXPORTER_OTLP_ENDPOINT env variable.")
+}
+
+func providePipelineTraceEndpoint() tracer.PipelineTraceEndpoint {
+	return tracer.PipelineTraceEndpoint(otlpEndpointFlag)
+}
+
 func provideWebHost() model.WebHost {
 	return model.WebHost(webHostFlag)
 }
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newUpdateCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	result := &cobra.Command{
+		Use:   "update",
+		Short: "Fetch the latest version of objects that Tilt downloads",
+	}
+
+	addCommand(result, newUpdateRepoCmd(streams))
+
+	return result
+}
+
+// A CLI for asking a running Tilt to fetch its extension repos again.
+type updateRepoCmd struct {
+	streams genericclioptions.IOStreams
+}
+
+var _ tiltCmd = &updateRepoCmd{}
+
+func newUpdateRepoCmd(streams genericclioptions.IOStreams) *updateRepoCmd {
+	return &updateRepoCmd{streams: streams}
+}
+
+func (c *updateRepoCmd) name() model.TiltSubcommand { return "update" }
+
+func (c *updateRepoCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "repo [NAME...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Fetch the latest version of extension repos.",
+		Long: `Fetch the latest version of extension repos in a running Tilt instance.
+
+Tilt fetches each repo once per session. This fetches them again,
+so that extensions pick up changes made to the repo since.
+
+Repos pinned to a ref stay at that ref.
+
+Extensions from the repos are checked again against their version
+ranges, digests, and the repo's trusted keys.
+`,
+		Example: `
+# Updates all extension repos
+tilt update repo
+
+# Updates the extension repo 'default'
+tilt update repo default
+`,
+	}
+
+	addConnectServerFlags(cmd)
+
+	return cmd
+}
+
+func (c *updateRepoCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.update-repo", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	cli, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.update(ctx, cli, args)
+}
+
+func (c *updateRepoCmd) update(ctx context.Context, cli ctrlclient.Client, names []string) error {
+	var repos v1alpha1.ExtensionRepoList
+	err := cli.List(ctx, &repos)
+	if err != nil {
+		return err
+	}
+
+	// Before making any changes, validate that all the repos exist.
+	byName := make(map[string]v1alpha1.ExtensionRepo, len(repos.Items))
+	for _, repo := range repos.Items {
+		byName[repo.Name] = repo
+	}
+	for _, name := range names {
+		if _, ok := byName[name]; !ok {
+			return fmt.Errorf("no such extension repo %q", name)
+		}
+	}
+
+	selected := repos.Items
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			selected = append(selected, byName[name])
+		}
+	}
+
+	request := time.Now().Format(time.RFC3339Nano)
+	for _, repo := range selected {
+		repo := repo
+		patch := ctrlclient.MergeFrom(repo.DeepCopy())
+		metav1.SetMetaDataAnnotation(&repo.ObjectMeta, v1alpha1.AnnotationExtensionRepoUpdate, request)
+		err := cli.Patch(ctx, &repo, patch)
+		if err != nil {
+			return fmt.Errorf("updating extension repo %q: %v", repo.Name, err)
+		}
+		_, _ = fmt.Fprintf(c.streams.Out, "Requested update of extension repo %q\n", repo.Name)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestUpdateRepo(t *testing.T) {
+	f := newServerFixture(t)
+	f.createRepo("default")
+	f.createRepo("private")
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newUpdateRepoCmd(streams)
+	err := cmd.update(f.ctx, f.client, []string{"private"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", f.updateRequest("default"))
+	assert.NotEqual(t, "", f.updateRequest("private"))
+	assert.Equal(t, "Requested update of extension repo \"private\"\n", out.String())
+}
+
+func TestUpdateRepoAll(t *testing.T) {
+	f := newServerFixture(t)
+	f.createRepo("default")
+	f.createRepo("private")
+
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	cmd := newUpdateRepoCmd(streams)
+	err := cmd.update(f.ctx, f.client, nil)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, "", f.updateRequest("default"))
+	assert.NotEqual(t, "", f.updateRequest("private"))
+}
+
+func TestUpdateRepoMissing(t *testing.T) {
+	f := newServerFixture(t)
+	f.createRepo("default")
+
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	cmd := newUpdateRepoCmd(streams)
+	err := cmd.update(f.ctx, f.client, []string{"default", "other"})
+	require.EqualError(t, err, `no such extension repo "other"`)
+
+	// No repos are updated if any are missing.
+	assert.Equal(t, "", f.updateRequest("default"))
+}
+
+func (f *serverFixture) createRepo(name string) {
+	repo := v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.ExtensionRepoSpec{
+			URL: "https://github.com/tilt-dev/tilt-extensions",
+		},
+	}
+	err := f.client.Create(f.ctx, &repo)
+	require.NoError(f.T(), err)
+}
+
+func (f *serverFixture) updateRequest(name string) string {
+	var repo v1alpha1.ExtensionRepo
+	err := f.client.Get(f.ctx, types.NamespacedName{Name: name}, &repo)
+	require.NoError(f.T(), err)
+	return repo.Annotations[v1alpha1.AnnotationExtensionRepoUpdate]
+}
@@ -32,13 +32,23 @@ func apiGet(path string) (body io.ReadCloser) {
 }
 
 func apiPostJson(path string, payload []byte) (body io.ReadCloser, status int) {
+	body, status, err := tryAPIPostJson(path, payload)
+	if err != nil {
+		cmdFail(err)
+	}
+	return body, status
+}
+
+// Like apiPostJson, but returns a notUpError instead of exiting
+// if Tilt can't be reached.
+func tryAPIPostJson(path string, payload []byte) (body io.ReadCloser, status int, err error) {
 	url := apiURL(path)
 	res, err := http.Post(url, "application/json", bytes.NewBuffer(payload))
 	if err != nil {
-		cmdFail(fmt.Errorf("Could not connect to Tilt at %s: %v", url, err))
+		return nil, 0, notUpError{fmt.Errorf("Could not connect to Tilt at %s: %v", url, err)}
 	}
 
-	return res.Body, res.StatusCode
+	return res.Body, res.StatusCode, nil
 }
 
 func cmdFail(err error) {
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type verifyCmd struct {
+	streams genericclioptions.IOStreams
+	exit    func(code int)
+
+	fileName string
+}
+
+var _ tiltCmd = &verifyCmd{}
+
+func newVerifyCmd(streams genericclioptions.IOStreams) *verifyCmd {
+	return &verifyCmd{
+		streams: streams,
+		exit:    os.Exit,
+	}
+}
+
+func (c *verifyCmd) name() model.TiltSubcommand { return "verify" }
+
+func (c *verifyCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [-- <Tiltfile args>]",
+		Short: "Execute the Tiltfile and check the resulting config, without building or deploying anything",
+		Long: `Execute the Tiltfile and check the resulting config, without building or deploying anything.
+
+Unlike 'tilt lint', this runs the Tiltfile, so it catches problems that only
+show up at execution time. Reports:
+
+- Errors executing the Tiltfile
+- Kubernetes YAML with fields that don't match the schema of a built-in kind
+- Docker Compose files that Compose can't parse
+- Every other warning Tilt prints when it loads the Tiltfile
+
+Doesn't build images or deploy to the cluster, so it's fast enough for a
+pre-commit hook or a CI check.
+
+Exit code 0: no problems found
+Exit code 1: the Tiltfile ran, but with problems (printed to stdout)
+Exit code 5: error when evaluating the Tiltfile (printed to stdout)
+
+Run with -v | --verbose to print the Tiltfile execution logs on stderr.`,
+		Example: `tilt verify
+tilt verify -f deploy/Tiltfile -- --to-run=frontend`,
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+	addKubeContextFlag(cmd)
+	return cmd
+}
+
+func (c *verifyCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.verify", make(engineanalytics.CmdTags).AsMap())
+	defer a.Flush(time.Second)
+
+	showTiltfileLogs := logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl)
+
+	// Collect the warnings as problems.
+	var mu sync.Mutex
+	var problems []string
+	l := logger.NewFuncLogger(false, logger.Get(ctx).Level(), func(level logger.Level, fields logger.Fields, msg []byte) error {
+		if level == logger.WarnLvl {
+			mu.Lock()
+			problems = append(problems, string(bytes.TrimSpace(msg)))
+			mu.Unlock()
+		}
+		if showTiltfileLogs {
+			_, _ = c.streams.ErrOut.Write(msg)
+		}
+		return nil
+	})
+	ctx = logger.WithLogger(ctx, l)
+
+	deps, err := wireTiltfileResult(ctx, a, "verify")
+	if err != nil {
+		return errors.Wrap(err, "wiring dependencies")
+	}
+
+	tlr := deps.tfl.Load(ctx, ctrltiltfile.MainTiltfile(c.fileName, args), nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, p := range problems {
+		c.printProblem("Warning", p)
+	}
+	if tlr.Error != nil {
+		c.printProblem("Error", tlr.Error.Error())
+		c.exit(TiltfileErrExitCode)
+		return nil
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in %s", len(problems), c.fileName)
+	}
+	_, _ = fmt.Fprintf(c.streams.Out, "No problems found in %s\n", c.fileName)
+	return nil
+}
+
+// Prints a problem, indenting any lines after the first so that
+// multi-line messages stay readable.
+func (c *verifyCmd) printProblem(kind string, msg string) {
+	msg = strings.ReplaceAll(strings.TrimSpace(msg), "\n", "\n  ")
+	_, _ = fmt.Fprintf(c.streams.Out, "%s: %s\n", kind, msg)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestVerifyNoProblems(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("Tiltfile", `
+local_resource(name='hi', cmd='echo hi')
+`)
+
+	out, code, err := runVerify(t)
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "No problems found in Tiltfile\n", out)
+}
+
+func TestVerifyInvalidYAML(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("Tiltfile", `
+k8s_yaml(blob("""apiVersion: v1
+kind: Service
+metadata:
+  name: fe
+spec:
+  prots:
+  - port: 80
+"""))
+`)
+
+	out, code, err := runVerify(t)
+	if assert.Error(t, err) {
+		assert.Equal(t, "found 1 problem(s) in Tiltfile", err.Error())
+	}
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `Warning: Invalid YAML in Tiltfile blob() call: Service "fe": unknown field "spec.prots"`)
+}
+
+func TestVerifyTiltfileError(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("Tiltfile", `
+fail('oh no')
+`)
+
+	out, code, err := runVerify(t)
+	require.NoError(t, err)
+	assert.Equal(t, TiltfileErrExitCode, code)
+	assert.Contains(t, out, "Error: Traceback")
+	assert.Contains(t, out, "oh no")
+}
+
+func runVerify(t *testing.T) (string, int, error) {
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newVerifyCmd(streams)
+	cmd.register()
+	cmd.fileName = "Tiltfile"
+
+	code := 0
+	cmd.exit = func(x int) { code = x }
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	err := cmd.run(ctx, nil)
+	return out.String(), code, err
+}
@@ -23,8 +23,12 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -34,6 +38,8 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -41,19 +47,43 @@ var (
 	waitLong = templates.LongDesc(i18n.T(`
 		Experimental: Wait for a specific condition on one or many resources.
 
-		The command takes multiple resources and waits until the specified condition
-		is seen in the Status field of every given resource.
+		With --for=ready or --for=update-success, the arguments are Tilt resource
+		names, and the command waits for every named resource (or every enabled
+		resource, if none are named). It exits nonzero if the timeout expires,
+		or as soon as a resource fails: an update error when waiting for
+		update-success, or any build or runtime error when waiting for ready.
+
+		Otherwise, the command takes multiple API objects and waits until the
+		specified condition is seen in the Status field of every given object.
 
 		A successful message will be printed to stdout indicating when the specified
     condition has been met. You can use -o option to change to output destination.`))
 
 	waitExample = templates.Examples(i18n.T(`
+		# Wait for the frontend and backend to be ready, e.g., in a CI script.
+		tilt wait frontend backend --for=ready --timeout=5m
+
+		# Wait for every enabled resource to finish updating,
+		# and fail as soon as any update fails.
+		tilt wait --for=update-success --timeout=5m
+
 		# Wait for the tiltfile to load.
 		tilt wait --for=condition=Ready "uiresource/(Tiltfile)"
 
 		# When used with a Kubernetes resource, waits for the pod
     # to deploy, start running, and pass all readiness probes.
-		tilt wait --for=condition=Ready "uiresource/my-kubernetes-deployment"`))
+		tilt wait --for=condition=Ready "uiresource/my-kubernetes-deployment"
+
+		# Wait for every enabled resource to be ready, e.g., as a gate in CI.
+		# On timeout, prints the resources that aren't ready and why.
+		tilt wait --all --timeout=5m`))
+)
+
+// Conditions that take resource names as arguments,
+// and that Tilt checks itself rather than with the kubectl implementation.
+const (
+	waitForReady         = "ready"
+	waitForUpdateSuccess = "update-success"
 )
 
 type waitCmd struct {
@@ -73,7 +103,7 @@ func (c *waitCmd) name() model.TiltSubcommand { return "wait" }
 
 func (c *waitCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "wait ([-f FILENAME] | resource.group/resource.name | resource.group [(-l label | --all)]) [--for=delete|--for condition=available]",
+		Use:     "wait ([resource...] --for=ready|update-success | --all | [-f FILENAME] | resource.group/resource.name | resource.group [(-l label | --all)]) [--for=delete|--for condition=available]",
 		Short:   "Experimental: Wait for a specific condition on one or many resources",
 		Long:    waitLong,
 		Example: waitExample,
@@ -82,6 +112,8 @@ func (c *waitCmd) register() *cobra.Command {
 	}
 
 	c.flags.AddFlags(cmd)
+	cmd.Flags().Lookup("for").Usage = "The condition to wait on: [ready|update-success|delete|condition=condition-name[=condition-value]|jsonpath='{JSONPath expression}'=JSONPath Condition]. " +
+		"With ready or update-success, the arguments are resource names. The default condition-value is true."
 	addConnectServerFlags(cmd)
 
 	return cmd
@@ -93,6 +125,21 @@ func (c *waitCmd) run(ctx context.Context, args []string) error {
 	a.Incr("cmd.wait", cmdTags.AsMap())
 	defer a.Flush(time.Second)
 
+	all := c.flags.ResourceBuilderFlags.All != nil && *c.flags.ResourceBuilderFlags.All
+	switch condition := strings.ToLower(c.flags.ForCondition); condition {
+	case waitForReady, waitForUpdateSuccess:
+		if all && len(args) > 0 {
+			return fmt.Errorf("cannot wait for both --all and named resources")
+		}
+		return c.waitForResources(ctx, args, condition)
+	}
+
+	// Plain `tilt wait --all` means --for=ready. With any other condition,
+	// --all selects API objects, like kubectl.
+	if len(args) == 0 && all && c.flags.ForCondition == "" {
+		return c.waitForResources(ctx, nil, waitForReady)
+	}
+
 	getter, err := wireClientGetter(ctx)
 	if err != nil {
 		return err
@@ -106,3 +153,196 @@ func (c *waitCmd) run(ctx context.Context, args []string) error {
 
 	return nil
 }
+
+// How often `tilt wait --all` checks the resources.
+const waitAllPollInterval = time.Second
+
+// Waits until the named resources meet the condition. With no names,
+// waits for every enabled resource, using the same rules as the /ready
+// endpoint of the external API.
+func (c *waitCmd) waitForResources(ctx context.Context, names []string, condition string) error {
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Same as kubectl: a negative timeout waits for a week,
+	// and a zero timeout checks once.
+	timeout := c.flags.Timeout
+	if timeout < 0 {
+		timeout = 168 * time.Hour
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The timeout bounds each List too, so a hung server can't stall us
+	// past it. A zero timeout still gets its one check.
+	listCtx := waitCtx
+	if timeout == 0 {
+		listCtx = ctx
+	}
+
+	ticker := time.NewTicker(waitAllPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var list v1alpha1.UIResourceList
+		err := client.List(listCtx, &list)
+		if err != nil {
+			if waitCtx.Err() != nil && ctx.Err() == nil {
+				return waitError(fmt.Sprintf("timed out waiting for resources to %s", waitConditionDescription(condition)), nil)
+			}
+			return err
+		}
+
+		status := checkWaitCondition(list.Items, names, condition)
+		if len(status.failed) > 0 {
+			return waitError(waitFailedMessage(condition), status.failed)
+		}
+		if status.met {
+			_, _ = fmt.Fprintf(c.flags.Out, "%s\n", waitMetMessage(names, condition))
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return waitError(fmt.Sprintf("timed out waiting for resources to %s", waitConditionDescription(condition)), status.unmet)
+		case <-ticker.C:
+		}
+	}
+}
+
+type waitStatus struct {
+	met bool
+
+	// Resources that don't meet the condition yet.
+	unmet []webview.ResourceReadiness
+
+	// Resources that failed in a way that means the condition
+	// won't be met without the user stepping in.
+	failed []webview.ResourceReadiness
+}
+
+func checkWaitCondition(resources []v1alpha1.UIResource, names []string, condition string) waitStatus {
+	if len(names) == 0 && condition == waitForReady {
+		readiness := webview.AggregateReadiness(resources)
+		result := waitStatus{met: readiness.Ready()}
+		for _, rr := range readiness.NotReady() {
+			if rr.Status == webview.ReadinessError {
+				result.failed = append(result.failed, rr)
+			} else {
+				result.unmet = append(result.unmet, rr)
+			}
+		}
+		return result
+	}
+
+	byName := make(map[string]v1alpha1.UIResource, len(resources))
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	checkAll := len(names) == 0
+	if checkAll {
+		for _, r := range resources {
+			names = append(names, r.Name)
+		}
+		sort.Strings(names)
+	}
+
+	// Like the aggregate readiness, a session with no resources
+	// hasn't met any condition yet.
+	result := waitStatus{met: len(names) > 0}
+	for _, name := range names {
+		r, ok := byName[name]
+		if !ok {
+			result.met = false
+			result.unmet = append(result.unmet, webview.ResourceReadiness{Name: name, Reason: "NotFound"})
+			continue
+		}
+
+		rr := webview.UIResourceReadiness(r)
+		if checkAll && rr.Status == webview.ReadinessSkipped {
+			continue
+		}
+
+		if condition == waitForReady {
+			switch rr.Status {
+			case webview.ReadinessReady:
+			case webview.ReadinessError:
+				result.met = false
+				result.failed = append(result.failed, rr)
+			default:
+				result.met = false
+				result.unmet = append(result.unmet, rr)
+			}
+			continue
+		}
+
+		switch r.Status.UpdateStatus {
+		case v1alpha1.UpdateStatusOK, v1alpha1.UpdateStatusNotApplicable:
+		case v1alpha1.UpdateStatusError:
+			result.met = false
+			failed := webview.ResourceReadiness{Name: name, Status: webview.ReadinessError, Reason: "UpdateError"}
+			if len(r.Status.BuildHistory) > 0 {
+				failed.Message = r.Status.BuildHistory[0].Error
+			}
+			result.failed = append(result.failed, failed)
+		default:
+			result.met = false
+			result.unmet = append(result.unmet, webview.ResourceReadiness{
+				Name:   name,
+				Status: webview.ReadinessPending,
+				Reason: updatePendingReason(r.Status.UpdateStatus),
+			})
+		}
+	}
+	return result
+}
+
+func updatePendingReason(status v1alpha1.UpdateStatus) string {
+	switch status {
+	case v1alpha1.UpdateStatusInProgress:
+		return "Updating"
+	case v1alpha1.UpdateStatusNone:
+		return "NotTriggered"
+	}
+	return "UpdatePending"
+}
+
+func waitConditionDescription(condition string) string {
+	if condition == waitForUpdateSuccess {
+		return "update successfully"
+	}
+	return "be ready"
+}
+
+func waitFailedMessage(condition string) string {
+	if condition == waitForUpdateSuccess {
+		return "update failed"
+	}
+	return "resources failed"
+}
+
+func waitMetMessage(names []string, condition string) string {
+	verb := "ready"
+	if condition == waitForUpdateSuccess {
+		verb = "updated"
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("all resources %s", verb)
+	}
+	return fmt.Sprintf("%s: %s", verb, strings.Join(names, ", "))
+}
+
+func waitError(summary string, resources []webview.ResourceReadiness) error {
+	var sb strings.Builder
+	sb.WriteString(summary)
+	for _, r := range resources {
+		sb.WriteString(fmt.Sprintf("\n  %s: %s", r.Name, r.Reason))
+		if r.Message != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", r.Message))
+		}
+	}
+	return errors.New(sb.String())
+}
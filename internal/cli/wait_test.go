@@ -43,3 +43,205 @@ func TestWait(t *testing.T) {
 
 	assert.Contains(t, out.String(), `uiresource.tilt.dev/my-sleep condition met`)
 }
+
+func TestWaitAll(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusOK,
+			RuntimeStatus: v1alpha1.RuntimeStatusOK,
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	streams := genericclioptions.IOStreams{Out: out}
+	wait := newWaitCmd(streams)
+	cmd := wait.register()
+
+	err = cmd.Flags().Parse([]string{"--all"})
+	require.NoError(t, err)
+
+	err = wait.run(f.ctx, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `all resources ready`)
+}
+
+func TestWaitAllTimeout(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusInProgress,
+			RuntimeStatus: v1alpha1.RuntimeStatusPending,
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	streams := genericclioptions.IOStreams{Out: out}
+	wait := newWaitCmd(streams)
+	cmd := wait.register()
+
+	err = cmd.Flags().Parse([]string{"--all", "--timeout=0"})
+	require.NoError(t, err)
+
+	err = wait.run(f.ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out waiting for resources to be ready\n  my-sleep: ")
+}
+
+func TestWaitAllFailsFast(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusError,
+			RuntimeStatus: v1alpha1.RuntimeStatusNotApplicable,
+			BuildHistory:  []v1alpha1.UIBuildTerminated{{Error: "exit status 1"}},
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	streams := genericclioptions.IOStreams{Out: out}
+	wait := newWaitCmd(streams)
+	cmd := wait.register()
+
+	// Fails before the timeout.
+	err = cmd.Flags().Parse([]string{"--all", "--timeout=1h"})
+	require.NoError(t, err)
+
+	err = wait.run(f.ctx, nil)
+	require.Error(t, err)
+	assert.Equal(t, "resources failed\n  my-sleep: UpdateError (exit status 1)", err.Error())
+}
+
+func TestWaitForReadyNamed(t *testing.T) {
+	f := newServerFixture(t)
+
+	for _, name := range []string{"fe", "be"} {
+		err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusOK,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	streams := genericclioptions.IOStreams{Out: out}
+	wait := newWaitCmd(streams)
+	cmd := wait.register()
+
+	err := cmd.Flags().Parse([]string{"--for=ready", "--timeout=0"})
+	require.NoError(t, err)
+
+	err = wait.run(f.ctx, []string{"fe", "be"})
+	require.NoError(t, err)
+	assert.Equal(t, "ready: fe, be\n", out.String())
+
+	err = wait.run(f.ctx, []string{"fe", "db"})
+	require.Error(t, err)
+	assert.Equal(t, "timed out waiting for resources to be ready\n  db: NotFound", err.Error())
+}
+
+func TestWaitForUpdateSuccessFailsFast(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus: v1alpha1.UpdateStatusError,
+			BuildHistory: []v1alpha1.UIBuildTerminated{{Error: "exit status 1"}},
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	streams := genericclioptions.IOStreams{Out: out}
+	wait := newWaitCmd(streams)
+	cmd := wait.register()
+
+	// Fails before the timeout.
+	err = cmd.Flags().Parse([]string{"--for=update-success", "--timeout=1h"})
+	require.NoError(t, err)
+
+	err = wait.run(f.ctx, nil)
+	require.Error(t, err)
+	assert.Equal(t, "update failed\n  my-sleep: UpdateError (exit status 1)", err.Error())
+}
+
+func TestCheckWaitCondition(t *testing.T) {
+	resources := []v1alpha1.UIResource{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "building"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusInProgress,
+				RuntimeStatus: v1alpha1.RuntimeStatusPending,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "manual"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusNone,
+				RuntimeStatus: v1alpha1.RuntimeStatusNone,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "updated"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusPending,
+			},
+		},
+	}
+
+	// Resources that aren't expected to run are skipped when waiting for all of them.
+	status := checkWaitCondition(resources, nil, waitForUpdateSuccess)
+	assert.False(t, status.met)
+	require.Len(t, status.unmet, 1)
+	assert.Equal(t, "building", status.unmet[0].Name)
+	assert.Equal(t, "Updating", status.unmet[0].Reason)
+
+	// ...but not when they're named.
+	status = checkWaitCondition(resources, []string{"manual"}, waitForUpdateSuccess)
+	assert.False(t, status.met)
+	require.Len(t, status.unmet, 1)
+	assert.Equal(t, "NotTriggered", status.unmet[0].Reason)
+
+	status = checkWaitCondition(resources, []string{"updated"}, waitForUpdateSuccess)
+	assert.True(t, status.met)
+
+	status = checkWaitCondition(resources, []string{"updated"}, waitForReady)
+	assert.False(t, status.met)
+
+	status = checkWaitCondition(nil, nil, waitForUpdateSuccess)
+	assert.False(t, status.met)
+
+	// A resource with an error fails the ready condition, named or not.
+	failing := append(resources, v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "crashing"},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusOK,
+			RuntimeStatus: v1alpha1.RuntimeStatusError,
+		},
+	})
+	status = checkWaitCondition(failing, []string{"crashing"}, waitForReady)
+	assert.False(t, status.met)
+	require.Len(t, status.failed, 1)
+	assert.Equal(t, "crashing", status.failed[0].Name)
+
+	status = checkWaitCondition(failing, nil, waitForReady)
+	assert.False(t, status.met)
+	require.Len(t, status.failed, 1)
+	assert.Equal(t, "crashing", status.failed[0].Name)
+	assert.NotContains(t, status.unmet, status.failed[0])
+}
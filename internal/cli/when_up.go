@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Lets a command that talks to a running Tilt wait for Tilt to come up,
+// instead of failing a script that races `tilt up` or a Tiltfile reload.
+type whenUpFlags struct {
+	enabled bool
+	timeout time.Duration
+}
+
+func (f *whenUpFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.enabled, "when-up", false,
+		"If Tilt isn't up yet, keep retrying until it is, instead of failing")
+	cmd.Flags().DurationVar(&f.timeout, "when-up-timeout", time.Minute,
+		"How long --when-up waits for Tilt before giving up")
+}
+
+// An error that means Tilt isn't up yet, so the request is worth retrying.
+type notUpError struct {
+	err error
+}
+
+func (e notUpError) Error() string { return e.err.Error() }
+func (e notUpError) Unwrap() error { return e.err }
+
+// Calls fn until it returns something other than a notUpError.
+//
+// Without --when-up, calls fn once.
+func (f *whenUpFlags) retry(ctx context.Context, errOut io.Writer, fn func() error) error {
+	err := fn()
+	if !f.enabled {
+		return err
+	}
+
+	backoff := wait.Backoff{
+		Duration: 250 * time.Millisecond,
+		Factor:   2,
+		Steps:    20,
+		Cap:      5 * time.Second,
+	}
+	deadline := time.Now().Add(f.timeout)
+	printed := false
+	for {
+		var notUp notUpError
+		if !errors.As(err, &notUp) {
+			return err
+		}
+
+		if !printed {
+			_, _ = fmt.Fprintf(errOut, "Waiting for Tilt at %s: %v\n", apiHost(), notUp.err)
+			printed = true
+		}
+
+		delay := backoff.Step()
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("Tilt wasn't up after %s: %v", f.timeout, notUp.err)
+		}
+		if delay > remaining {
+			delay = remaining
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		err = fn()
+	}
+}
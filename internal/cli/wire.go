@@ -23,21 +23,26 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/apitoken"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/cloud"
 	"github.com/tilt-dev/tilt/internal/cloud/cloudurl"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers"
+	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesdiscovery"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/engine"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/buildhistory"
+	"github.com/tilt-dev/tilt/internal/engine/checkpoint"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -49,7 +54,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/openurl"
+	"github.com/tilt-dev/tilt/internal/runreport"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
 	"github.com/tilt-dev/tilt/internal/token"
@@ -97,8 +104,14 @@ var BaseWireSet = wire.NewSet(
 	k8swatch.NewEventWatchManager,
 	uisession.NewSubscriber,
 	uiresource.NewSubscriber,
+	buildhistory.NewSubscriber,
+	checkpoint.NewSubscriber,
+	loghistory.NewSubscriber,
+	loghistory.ProvidePreviousDir,
+	metrics.NewSubscriber,
 	configs.NewConfigsController,
 	configs.NewTriggerQueueSubscriber,
+	configs.NewRerunScheduler,
 	telemetry.NewController,
 	cloud.WireSet,
 	cloudurl.ProvideAddress,
@@ -113,9 +126,16 @@ var BaseWireSet = wire.NewSet(
 	wire.Value(openurl.OpenURL(openurl.BrowserOpen)),
 
 	provideLogActions,
+	provideLogHistory,
+	provideLogFormat,
+	provideLogTimestamps,
+	provideControllerWorkers,
+	ProvideDevEnvironment,
 	store.NewStore,
 	wire.Bind(new(store.RStore), new(*store.Store)),
 	wire.Bind(new(store.Dispatcher), new(*store.Store)),
+	wire.Bind(new(hud.ResourceRestarter), new(*server.HeadsUpServer)),
+	wire.Bind(new(server.CmdInputSender), new(*cmd.Controller)),
 
 	dockerprune.NewDockerPruner,
 
@@ -130,20 +150,25 @@ var BaseWireSet = wire.NewSet(
 	controllers.WireSet,
 
 	provideCITimeoutFlag,
+	provideCISettingsFlags,
 	provideWebVersion,
 	provideWebMode,
 	provideWebURL,
 	provideWebPort,
 	provideWebHost,
+	provideTeamAuthConfig,
 	server.WireSet,
 	provideAssetServer,
 
 	tracer.NewSpanCollector,
 	wire.Bind(new(sdktrace.SpanExporter), new(*tracer.SpanCollector)),
 	wire.Bind(new(tracer.SpanSource), new(*tracer.SpanCollector)),
+	providePipelineTraceEndpoint,
+	tracer.ProvidePipelineTracer,
 
 	dirs.UseTiltDevDir,
 	xdg.NewTiltDevBase,
+	apitoken.NewStore,
 	token.GetOrCreateToken,
 
 	build.NewKINDLoader,
@@ -174,6 +199,7 @@ func wireDockerPrune(ctx context.Context, analytics *analytics.TiltAnalytics, su
 func wireCmdUp(ctx context.Context, analytics *analytics.TiltAnalytics, cmdTags engineanalytics.CmdTags, subcommand model.TiltSubcommand) (CmdUpDeps, error) {
 	wire.Build(UpWireSet,
 		cloud.NewSnapshotter,
+		runreport.NewReporter,
 		wire.Value(store.EngineModeUp),
 		wire.Struct(new(CmdUpDeps), "*"))
 	return CmdUpDeps{}, nil
@@ -186,11 +212,14 @@ type CmdUpDeps struct {
 	CloudAddress cloudurl.Address
 	Prompt       *prompt.TerminalPrompt
 	Snapshotter  *cloud.Snapshotter
+	Recorder     *server.SessionRecorder
+	Reporter     *runreport.Reporter
 }
 
 func wireCmdCI(ctx context.Context, analytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (CmdCIDeps, error) {
 	wire.Build(UpWireSet,
 		cloud.NewSnapshotter,
+		runreport.NewReporter,
 		wire.Value(store.EngineModeCI),
 		wire.Value(engineanalytics.CmdTags(map[string]string{})),
 		wire.Struct(new(CmdCIDeps), "*"),
@@ -204,6 +233,8 @@ type CmdCIDeps struct {
 	Token        token.Token
 	CloudAddress cloudurl.Address
 	Snapshotter  *cloud.Snapshotter
+	Recorder     *server.SessionRecorder
+	Reporter     *runreport.Reporter
 }
 
 func wireCmdUpdog(ctx context.Context,
@@ -268,6 +299,16 @@ func wireK8sVersion(ctx context.Context) (*version.Info, error) {
 	return nil, nil
 }
 
+func wireK8sAPIConfig(ctx context.Context) (k8s.APIConfigOrError, error) {
+	wire.Build(K8sWireSet)
+	return k8s.APIConfigOrError{}, nil
+}
+
+func wireK8sRESTConfig(ctx context.Context) (k8s.RESTConfigOrError, error) {
+	wire.Build(K8sWireSet)
+	return k8s.RESTConfigOrError{}, nil
+}
+
 func wireDockerClusterClient(ctx context.Context) (docker.ClusterClient, error) {
 	wire.Build(UpWireSet)
 	return nil, nil
@@ -359,3 +400,7 @@ func wireLsp(ctx context.Context, l logger.Logger, subcommand model.TiltSubcomma
 func provideCITimeoutFlag() model.CITimeoutFlag {
 	return model.CITimeoutFlag(ciTimeout)
 }
+
+func provideCISettingsFlags() model.CISettingsFlags {
+	return ciSettingsFlags
+}
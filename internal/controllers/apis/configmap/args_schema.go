@@ -0,0 +1,52 @@
+package configmap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/config"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+const argsSchemaKey = "schema"
+
+// The name of the ConfigMap where a Tiltfile publishes the settings
+// it defines with config.define_*().
+func ArgsSchemaName(tiltfileName string) string {
+	return fmt.Sprintf("%s-args-schema", tiltfileName)
+}
+
+func NewArgsSchemaConfigMap(tiltfileName string, schema config.ArgsSchema) *v1alpha1.ConfigMap {
+	// The schema is only strings and bools, so it always encodes.
+	b, _ := json.Marshal(schema)
+	return &v1alpha1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ArgsSchemaName(tiltfileName)},
+		Data:       map[string]string{argsSchemaKey: string(b)},
+	}
+}
+
+// Returns the args schema the Tiltfile published, or false if it hasn't
+// published one (e.g., because it's never loaded).
+func GetArgsSchema(ctx context.Context, cli client.Client, tiltfileName string) (config.ArgsSchema, bool, error) {
+	var cm v1alpha1.ConfigMap
+	err := cli.Get(ctx, types.NamespacedName{Name: ArgsSchemaName(tiltfileName)}, &cm)
+	if apierrors.IsNotFound(err) {
+		return config.ArgsSchema{}, false, nil
+	}
+	if err != nil {
+		return config.ArgsSchema{}, false, err
+	}
+
+	var schema config.ArgsSchema
+	err = json.Unmarshal([]byte(cm.Data[argsSchemaKey]), &schema)
+	if err != nil {
+		return config.ArgsSchema{}, false, fmt.Errorf("parsing ConfigMap %q: %v", cm.Name, err)
+	}
+	return schema, true, nil
+}
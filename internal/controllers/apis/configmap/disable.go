@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -96,3 +97,88 @@ func MaybeNewDisableStatus(ctx context.Context, client client.Client, disableSou
 	}
 	return prevStatus, nil
 }
+
+// Enables or disables a resource by updating the ConfigMaps behind its DisableSources.
+func SetResourceEnabled(ctx context.Context, cli client.Client, uir v1alpha1.UIResource, enable bool) error {
+	return SetResourcesEnabled(ctx, cli, []v1alpha1.UIResource{uir}, enable)
+}
+
+// Enables or disables several resources, in order, as one change.
+//
+// Each resource usually has its own ConfigMap, so we can't do this in one
+// update. Instead, if any update fails, we put back the values we'd already
+// changed, so that a failure never leaves only some of the resources changed.
+func SetResourcesEnabled(ctx context.Context, cli client.Client, uirs []v1alpha1.UIResource, enable bool) error {
+	for _, uir := range uirs {
+		for _, source := range uir.Status.DisableStatus.Sources {
+			if source.ConfigMap == nil {
+				return fmt.Errorf("internal error: resource %s's DisableSource does not have a ConfigMap'", uir.Name)
+			}
+		}
+	}
+
+	var undos []func() error
+	for _, uir := range uirs {
+		for _, source := range uir.Status.DisableStatus.Sources {
+			undo, err := setConfigMapValue(ctx, cli, *source.ConfigMap, strconv.FormatBool(!enable))
+			if err != nil {
+				for i := len(undos) - 1; i >= 0; i-- {
+					// Best effort: the original error is the one worth reporting.
+					_ = undos[i]()
+				}
+				return fmt.Errorf("%s: %v", uir.Name, err)
+			}
+			undos = append(undos, undo)
+		}
+	}
+	return nil
+}
+
+// Sets the ConfigMap key behind a DisableSource, creating the ConfigMap if
+// needed, and returns a func that puts back what was there before.
+func setConfigMapValue(ctx context.Context, cli client.Client, source v1alpha1.ConfigMapDisableSource, value string) (func() error, error) {
+	nn := types.NamespacedName{Name: source.Name}
+	var cm v1alpha1.ConfigMap
+	err := cli.Get(ctx, nn, &cm)
+	if apierrors.IsNotFound(err) {
+		cm = v1alpha1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: source.Name},
+			Data:       map[string]string{source.Key: value},
+		}
+		err := cli.Create(ctx, &cm)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			return client.IgnoreNotFound(cli.Delete(ctx, &v1alpha1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: source.Name}}))
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	prev, hadPrev := cm.Data[source.Key]
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[source.Key] = value
+	err = cli.Update(ctx, &cm)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		var cm v1alpha1.ConfigMap
+		err := cli.Get(ctx, nn, &cm)
+		if err != nil {
+			return err
+		}
+		if hadPrev {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string)
+			}
+			cm.Data[source.Key] = prev
+		} else {
+			delete(cm.Data, source.Key)
+		}
+		return cli.Update(ctx, &cm)
+	}, nil
+}
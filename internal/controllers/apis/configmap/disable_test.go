@@ -2,9 +2,12 @@ package configmap
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
@@ -181,6 +184,13 @@ func (f *disableFixture) updateConfigMap(isDisabled *string) {
 	require.NoError(f.t, err)
 }
 
+func (f *disableFixture) configMapValue(name string) string {
+	var cm v1alpha1.ConfigMap
+	err := f.fc.Get(f.ctx, types.NamespacedName{Name: name}, &cm)
+	require.NoError(f.t, err)
+	return cm.Data[key]
+}
+
 func disableSource() *v1alpha1.DisableSource {
 	return &v1alpha1.DisableSource{
 		ConfigMap: &v1alpha1.ConfigMapDisableSource{
@@ -205,6 +215,62 @@ func everyDisableSource() *v1alpha1.DisableSource {
 	}
 }
 
+func TestSetResourcesEnabled(t *testing.T) {
+	f := newDisableFixture(t)
+	f.createConfigMap(pointer.StringPtr("false"))
+
+	err := SetResourcesEnabled(f.ctx, f.fc, []v1alpha1.UIResource{
+		uiResource("fe", configMapName),
+		uiResource("be", configMap2Name),
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "true", f.configMapValue(configMapName))
+	assert.Equal(t, "true", f.configMapValue(configMap2Name))
+}
+
+func TestSetResourcesEnabledRollsBackOnError(t *testing.T) {
+	f := newDisableFixture(t)
+	f.createConfigMap(pointer.StringPtr("false"))
+	fc := failingCreateClient{Client: f.fc, name: "db-disable"}
+
+	err := SetResourcesEnabled(f.ctx, fc, []v1alpha1.UIResource{
+		uiResource("fe", configMapName),
+		uiResource("be", configMap2Name),
+		uiResource("db", "db-disable"),
+	}, false)
+	require.EqualError(t, err, "db: create failed")
+
+	assert.Equal(t, "false", f.configMapValue(configMapName))
+	var cm v1alpha1.ConfigMap
+	err = f.fc.Get(f.ctx, types.NamespacedName{Name: configMap2Name}, &cm)
+	assert.True(t, apierrors.IsNotFound(err), "expected %s to be deleted, got: %v", configMap2Name, err)
+}
+
+type failingCreateClient struct {
+	ctrlclient.Client
+	name string
+}
+
+func (c failingCreateClient) Create(ctx context.Context, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+	if obj.GetName() == c.name {
+		return fmt.Errorf("create failed")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func uiResource(name string, cmName string) v1alpha1.UIResource {
+	return v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1alpha1.UIResourceStatus{
+			DisableStatus: v1alpha1.DisableResourceStatus{
+				Sources: []v1alpha1.DisableSource{
+					{ConfigMap: &v1alpha1.ConfigMapDisableSource{Name: cmName, Key: key}},
+				},
+			},
+		},
+	}
+}
+
 func newDisableFixture(t *testing.T) *disableFixture {
 	fc := fake.NewFakeTiltClient()
 	ctx := context.Background()
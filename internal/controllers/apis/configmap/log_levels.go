@@ -0,0 +1,63 @@
+package configmap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Records the levels the Tiltfile set, so that a Tiltfile reload
+// only overwrites levels changed at runtime if the Tiltfile's changed too.
+const AnnotationTiltfileLogLevels = "tilt.dev/tiltfile-log-levels"
+
+// The name of the ConfigMap with the minimum level of logs to keep
+// for a resource.
+//
+// Each key is a log source (build, runtime, or system), and each value
+// is a level (debug, verbose, info, warn, or error). Edit it to change
+// the levels while Tilt is running.
+func LogLevelsName(mn model.ManifestName) string {
+	return fmt.Sprintf("%s-log-levels", mn)
+}
+
+func NewLogLevelsConfigMap(mn model.ManifestName, levels model.LogLevels) *v1alpha1.ConfigMap {
+	data := make(map[string]string, len(levels))
+	var pairs []string
+	for source, level := range levels {
+		data[string(source)] = level.Name()
+		pairs = append(pairs, fmt.Sprintf("%s=%s", source, level.Name()))
+	}
+	sort.Strings(pairs)
+
+	return &v1alpha1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: LogLevelsName(mn),
+			Annotations: map[string]string{
+				AnnotationTiltfileLogLevels: strings.Join(pairs, ","),
+			},
+		},
+		Data: data,
+	}
+}
+
+// Returns the minimum level of logs to keep from the source.
+//
+// Returns NoneLvl (keep everything) if the level is missing or invalid,
+// so that a typo in the ConfigMap never hides logs.
+func LogLevelFor(cm *v1alpha1.ConfigMap, source model.LogSource) logger.Level {
+	name, ok := cm.Data[string(source)]
+	if !ok || name == "" {
+		return logger.NoneLvl
+	}
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		return logger.NoneLvl
+	}
+	return level
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -37,15 +42,36 @@ func (w ctrlWrapper) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return w.Reconciler.Reconcile(ctx, req)
 }
 
+// The number of objects each controller may reconcile at once, by
+// controller name (like "cmd" or "kubernetesapply").
+//
+// Controllers that aren't listed reconcile one object at a time.
+// A controller never reconciles the same object twice at once.
+type ControllerWorkers map[string]int
+
+// The name of a controller, which is also the name that controller-runtime
+// uses in its metrics: the lowercase kind of the objects it reconciles.
+//
+// Each controller lives in a package named after that kind.
+func ControllerName(c Controller) string {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath())
+}
+
 type ControllerBuilder struct {
 	tscm        *TiltServerControllerManager
 	controllers []Controller
+	workers     ControllerWorkers
 }
 
-func NewControllerBuilder(tscm *TiltServerControllerManager, controllers []Controller) *ControllerBuilder {
+func NewControllerBuilder(tscm *TiltServerControllerManager, controllers []Controller, workers ControllerWorkers) *ControllerBuilder {
 	return &ControllerBuilder{
 		tscm:        tscm,
 		controllers: controllers,
+		workers:     workers,
 	}
 }
 
@@ -65,6 +91,11 @@ func (c *ControllerBuilder) SetUp(ctx context.Context, st store.RStore) error {
 		return errors.New("controller manager not initialized")
 	}
 
+	err := c.validateWorkers()
+	if err != nil {
+		return err
+	}
+
 	// create all the builders and THEN start them all - if each builder is created + started,
 	// initialization will fail because indexes cannot be added to an Informer after start, and
 	// the builders register informers
@@ -74,6 +105,9 @@ func (c *ControllerBuilder) SetUp(ctx context.Context, st store.RStore) error {
 		if err != nil {
 			return fmt.Errorf("error creating builder: %v", err)
 		}
+		if n, ok := c.workers[ControllerName(controller)]; ok {
+			b = b.WithOptions(ctrlcontroller.Options{MaxConcurrentReconciles: n})
+		}
 		builders = append(builders, b)
 	}
 
@@ -95,6 +129,28 @@ func (c *ControllerBuilder) SetUp(ctx context.Context, st store.RStore) error {
 	return nil
 }
 
+func (c *ControllerBuilder) validateWorkers() error {
+	known := make(map[string]bool, len(c.controllers))
+	for _, controller := range c.controllers {
+		known[ControllerName(controller)] = true
+	}
+
+	for name, n := range c.workers {
+		if !known[name] {
+			names := make([]string, 0, len(known))
+			for k := range known {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown controller %q for workers (must be one of %s)", name, strings.Join(names, ", "))
+		}
+		if n < 1 {
+			return fmt.Errorf("controller %q must have at least 1 worker, got %d", name, n)
+		}
+	}
+	return nil
+}
+
 func (c *ControllerBuilder) TearDown(ctx context.Context) {
 	for _, controller := range c.controllers {
 		td, ok := controller.(store.TearDowner)
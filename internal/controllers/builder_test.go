@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
+	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
+)
+
+func TestControllerName(t *testing.T) {
+	assert.Equal(t, "cmd", ControllerName(&cmd.Controller{}))
+	assert.Equal(t, "filewatch", ControllerName(&filewatch.Controller{}))
+	assert.Equal(t, "kubernetesapply", ControllerName(&kubernetesapply.Reconciler{}))
+}
+
+func TestValidateWorkers(t *testing.T) {
+	controllers := []Controller{&cmd.Controller{}, &filewatch.Controller{}}
+
+	cb := NewControllerBuilder(nil, controllers, ControllerWorkers{"cmd": 4})
+	assert.NoError(t, cb.validateWorkers())
+
+	cb = NewControllerBuilder(nil, controllers, ControllerWorkers{"command": 4})
+	assert.EqualError(t, cb.validateWorkers(),
+		`unknown controller "command" for workers (must be one of cmd, filewatch)`)
+
+	cb = NewControllerBuilder(nil, controllers, ControllerWorkers{"filewatch": 0})
+	assert.EqualError(t, cb.validateWorkers(),
+		`controller "filewatch" must have at least 1 worker, got 0`)
+}
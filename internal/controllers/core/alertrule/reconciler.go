@@ -0,0 +1,348 @@
+package alertrule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/metrics"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Checks each AlertRule against the state of its resources, and records
+// which resources it's firing for in its status.
+type Reconciler struct {
+	client ctrlclient.Client
+	clock  clockwork.Clock
+
+	mu sync.Mutex
+
+	// The resources that each rule has a firing metric for.
+	firingMetrics map[string][]string
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func NewReconciler(client ctrlclient.Client, clock clockwork.Clock) *Reconciler {
+	return &Reconciler{
+		client:        client,
+		clock:         clock,
+		firingMetrics: make(map[string][]string),
+	}
+}
+
+func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AlertRule{}).
+		Watches(&source.Kind{Type: &v1alpha1.UIResource{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAll)).
+		Watches(&source.Kind{Type: &v1alpha1.ResourceEvent{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAll))
+
+	return b, nil
+}
+
+// Any resource change might make a rule start or stop firing.
+func (r *Reconciler) enqueueAll(obj ctrlclient.Object) []reconcile.Request {
+	var list v1alpha1.AlertRuleList
+	err := r.client.List(context.Background(), &list)
+	if err != nil {
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, rule := range list.Items {
+		result = append(result, reconcile.Request{NamespacedName: types.NamespacedName{Name: rule.Name}})
+	}
+	return result
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	rule := &v1alpha1.AlertRule{}
+	err := r.client.Get(ctx, req.NamespacedName, rule)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if apierrors.IsNotFound(err) || rule.ObjectMeta.DeletionTimestamp != nil {
+		r.updateFiringMetrics(req.Name, nil)
+		return ctrl.Result{}, nil
+	}
+
+	var uirs v1alpha1.UIResourceList
+	err = r.client.List(ctx, &uirs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var events []v1alpha1.ResourceEvent
+	if rule.Spec.Metric != v1alpha1.AlertMetricBuildDuration {
+		var list v1alpha1.ResourceEventList
+		err = r.client.List(ctx, &list)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		events = list.Items
+	}
+
+	status, requeueAfter := r.evaluate(rule, uirs.Items, events)
+	if !apicmp.DeepEqual(rule.Status, status) {
+		update := rule.DeepCopy()
+		update.Status = status
+		err := r.client.Status().Update(ctx, update)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	r.updateFiringMetrics(rule.Name, status.Firing)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// Gives each resource that the rule is firing for a series, and removes
+// the series of resources it stopped firing for.
+func (r *Reconciler) updateFiringMetrics(name string, firing []v1alpha1.AlertFiring) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := make(map[string]bool, len(firing))
+	var resources []string
+	for _, f := range firing {
+		current[f.Resource] = true
+		resources = append(resources, f.Resource)
+		metrics.AlertFiring.WithLabelValues(name, f.Resource).Set(1)
+	}
+	for _, resource := range r.firingMetrics[name] {
+		if !current[resource] {
+			metrics.AlertFiring.Delete(map[string]string{
+				metrics.LabelAlertRule: name,
+				metrics.LabelResource:  resource,
+			})
+		}
+	}
+
+	if len(resources) == 0 {
+		delete(r.firingMetrics, name)
+	} else {
+		r.firingMetrics[name] = resources
+	}
+}
+
+// Checks the rule against each resource it wants. Returns the new status,
+// and when the rule might change without any resource changing (like when
+// a running build crosses the threshold, or an old restart leaves the
+// window).
+func (r *Reconciler) evaluate(rule *v1alpha1.AlertRule, uirs []v1alpha1.UIResource, events []v1alpha1.ResourceEvent) (v1alpha1.AlertRuleStatus, time.Duration) {
+	now := r.clock.Now()
+	since := make(map[string]time.Time, len(rule.Status.Firing))
+	for _, f := range rule.Status.Firing {
+		since[f.Resource] = f.Since.Time
+	}
+
+	eventsByResource := make(map[string][]v1alpha1.ResourceEvent)
+	for _, e := range events {
+		eventsByResource[e.Spec.Resource] = append(eventsByResource[e.Spec.Resource], e)
+	}
+
+	var status v1alpha1.AlertRuleStatus
+	var requeueAfter time.Duration
+	for i := range uirs {
+		uir := &uirs[i]
+		if !rule.WantsResource(uir.Name) || uir.Status.DisableStatus.State == v1alpha1.DisableStateDisabled {
+			continue
+		}
+
+		var c check
+		switch rule.Spec.Metric {
+		case v1alpha1.AlertMetricBuildDuration:
+			c = checkBuildDuration(rule, uir, now)
+		case v1alpha1.AlertMetricPodRestarts:
+			c = checkCount(rule, eventsByResource[uir.Name], v1alpha1.ResourceEventTypePodRestarted, "restarts", now)
+		case v1alpha1.AlertMetricBuildFailures:
+			c = checkCount(rule, eventsByResource[uir.Name], v1alpha1.ResourceEventTypeBuildFailed, "failed builds", now)
+		case v1alpha1.AlertMetricTimeToReady:
+			c = checkTimeToReady(rule, uir, now)
+		}
+		if c.recheckAfter > 0 {
+			requeueAfter = earliest(requeueAfter, c.recheckAfter)
+		}
+		if !c.firing {
+			continue
+		}
+
+		start, ok := since[uir.Name]
+		if !ok {
+			start = now
+		}
+		message := c.reason
+		if rule.Spec.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, rule.Spec.Message)
+		}
+		status.Firing = append(status.Firing, v1alpha1.AlertFiring{
+			Resource: uir.Name,
+			Value:    c.value,
+			Message:  message,
+			Since:    apis.NewMicroTime(start),
+		})
+	}
+
+	sort.Slice(status.Firing, func(i, j int) bool {
+		return status.Firing[i].Resource < status.Firing[j].Resource
+	})
+	return status, requeueAfter
+}
+
+// The result of checking a rule against one resource.
+type check struct {
+	firing bool
+	value  string
+	reason string
+
+	// When the result might change on its own. 0 if it won't.
+	recheckAfter time.Duration
+}
+
+func checkBuildDuration(rule *v1alpha1.AlertRule, uir *v1alpha1.UIResource, now time.Time) check {
+	threshold := rule.Spec.Duration.Duration
+	var c check
+	if len(uir.Status.BuildHistory) > 0 {
+		b := uir.Status.BuildHistory[0]
+		if !b.StartTime.IsZero() && !b.FinishTime.IsZero() {
+			d := b.FinishTime.Sub(b.StartTime.Time)
+			if d > threshold {
+				c = check{
+					firing: true,
+					value:  d.Round(time.Second).String(),
+					reason: fmt.Sprintf("last build took %s, more than %s", d.Round(time.Second), threshold),
+				}
+			}
+		}
+	}
+
+	if uir.Status.CurrentBuild != nil && !uir.Status.CurrentBuild.StartTime.IsZero() {
+		d := now.Sub(uir.Status.CurrentBuild.StartTime.Time)
+		if d > threshold {
+			return check{
+				firing: true,
+				value:  d.Round(time.Second).String(),
+				reason: fmt.Sprintf("build running for %s, more than %s", d.Round(time.Second), threshold),
+			}
+		}
+		c.recheckAfter = threshold - d + time.Second
+	}
+	return c
+}
+
+// Measures from the start of the last build, when the resource started
+// changing, to when it became ready.
+//
+// A resource that failed isn't slow, so it doesn't fire. Other rules
+// cover failures.
+func checkTimeToReady(rule *v1alpha1.AlertRule, uir *v1alpha1.UIResource, now time.Time) check {
+	threshold := rule.Spec.Duration.Duration
+	var start time.Time
+	if uir.Status.CurrentBuild != nil && !uir.Status.CurrentBuild.StartTime.IsZero() {
+		start = uir.Status.CurrentBuild.StartTime.Time
+	} else if len(uir.Status.BuildHistory) > 0 {
+		start = uir.Status.BuildHistory[0].StartTime.Time
+	}
+	if start.IsZero() {
+		return check{}
+	}
+
+	var ready v1alpha1.UIResourceCondition
+	for _, c := range uir.Status.Conditions {
+		if c.Type == v1alpha1.UIResourceReady {
+			ready = c
+		}
+	}
+
+	if ready.Status == metav1.ConditionTrue {
+		// If it was ready before the build started, it never stopped
+		// being ready, like after a live update.
+		if ready.LastTransitionTime.Time.Before(start) {
+			return check{}
+		}
+		d := ready.LastTransitionTime.Sub(start)
+		if d <= threshold {
+			return check{}
+		}
+		return check{
+			firing: true,
+			value:  d.Round(time.Second).String(),
+			reason: fmt.Sprintf("took %s to become ready, more than %s", d.Round(time.Second), threshold),
+		}
+	}
+
+	if ready.Reason == "UpdateError" || ready.Reason == "RuntimeError" {
+		return check{}
+	}
+
+	d := now.Sub(start)
+	if d <= threshold {
+		return check{recheckAfter: threshold - d + time.Second}
+	}
+	return check{
+		firing: true,
+		value:  d.Round(time.Second).String(),
+		reason: fmt.Sprintf("not ready %s after the build started, more than %s", d.Round(time.Second), threshold),
+	}
+}
+
+// Counts events of one type in the rule's window.
+func checkCount(rule *v1alpha1.AlertRule, events []v1alpha1.ResourceEvent, t v1alpha1.ResourceEventType, noun string, now time.Time) check {
+	window := rule.Window().Duration
+	count := 0
+	var oldest time.Time
+	for _, e := range events {
+		if e.Spec.Type != t {
+			continue
+		}
+		eventTime := e.Spec.Time.Time
+		if eventTime.IsZero() {
+			eventTime = e.CreationTimestamp.Time
+		}
+		if now.Sub(eventTime) > window {
+			continue
+		}
+		count++
+		if oldest.IsZero() || eventTime.Before(oldest) {
+			oldest = eventTime
+		}
+	}
+
+	var c check
+	if count > 0 {
+		// The count goes down when the oldest event leaves the window.
+		c.recheckAfter = oldest.Add(window).Sub(now) + time.Second
+	}
+	if count > int(rule.Spec.Count) {
+		c.firing = true
+		c.value = strconv.Itoa(count)
+		c.reason = fmt.Sprintf("%d %s in %s, more than %d", count, noun, window, rule.Spec.Count)
+	}
+	return c
+}
+
+// The shorter of two wait times, where 0 means no wait is scheduled yet.
+func earliest(cur, d time.Duration) time.Duration {
+	if cur == 0 || d < cur {
+		return d
+	}
+	return cur
+}
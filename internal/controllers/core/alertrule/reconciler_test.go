@@ -0,0 +1,334 @@
+package alertrule
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/metrics"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+var ruleName = types.NamespacedName{Name: "slow-builds"}
+
+func TestSlowBuild(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createResource("be")
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric:   v1alpha1.AlertMetricBuildDuration,
+		Duration: &metav1.Duration{Duration: 2 * time.Minute},
+		Message:  "check the build cache",
+	})
+
+	f.finishBuild("fe", 3*time.Minute)
+	f.finishBuild("be", time.Minute)
+	f.MustReconcile(ruleName)
+
+	firing := f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "fe", firing[0].Resource)
+	assert.Equal(t, "3m0s", firing[0].Value)
+	assert.Equal(t, "last build took 3m0s, more than 2m0s: check the build cache", firing[0].Message)
+	assert.True(t, f.clock.Now().Equal(firing[0].Since.Time))
+
+	// The alert keeps its start time until it resolves.
+	f.clock.Advance(time.Minute)
+	f.finishBuild("fe", 4*time.Minute)
+	f.MustReconcile(ruleName)
+	firing = f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "4m0s", firing[0].Value)
+	assert.True(t, f.clock.Now().Add(-time.Minute).Equal(firing[0].Since.Time))
+
+	f.finishBuild("fe", time.Second)
+	f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+}
+
+func TestRunningBuild(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric:   v1alpha1.AlertMetricBuildDuration,
+		Duration: &metav1.Duration{Duration: 2 * time.Minute},
+	})
+
+	f.updateResource("fe", func(uir *v1alpha1.UIResource) {
+		uir.Status.CurrentBuild = &v1alpha1.UIBuildRunning{StartTime: apis.NewMicroTime(f.clock.Now())}
+	})
+	result := f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+	assert.Equal(t, 2*time.Minute+time.Second, result.RequeueAfter)
+
+	f.clock.Advance(result.RequeueAfter)
+	f.MustReconcile(ruleName)
+	firing := f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "build running for 2m1s, more than 2m0s", firing[0].Message)
+}
+
+func TestPodRestarts(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric: v1alpha1.AlertMetricPodRestarts,
+		Count:  3,
+	})
+
+	for i := 0; i < 4; i++ {
+		f.createEvent("fe", v1alpha1.ResourceEventTypePodRestarted)
+		f.clock.Advance(time.Minute)
+	}
+	f.createEvent("fe", v1alpha1.ResourceEventTypeBuildFailed)
+	result := f.MustReconcile(ruleName)
+
+	firing := f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "4", firing[0].Value)
+	assert.Equal(t, "4 restarts in 10m0s, more than 3", firing[0].Message)
+
+	// Once the first restart leaves the window, the alert resolves.
+	assert.Equal(t, 6*time.Minute+time.Second, result.RequeueAfter)
+	f.clock.Advance(result.RequeueAfter)
+	f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+}
+
+func TestBuildFailuresFilters(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createResource("be")
+	f.createResource("disabled")
+	f.updateResource("disabled", func(uir *v1alpha1.UIResource) {
+		uir.Status.DisableStatus.State = v1alpha1.DisableStateDisabled
+	})
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric:    v1alpha1.AlertMetricBuildFailures,
+		Resources: []string{"be", "disabled"},
+		Window:    &metav1.Duration{Duration: time.Hour},
+	})
+
+	f.createEvent("fe", v1alpha1.ResourceEventTypeBuildFailed)
+	f.createEvent("disabled", v1alpha1.ResourceEventTypeBuildFailed)
+	f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+
+	f.createEvent("be", v1alpha1.ResourceEventTypeBuildFailed)
+	f.MustReconcile(ruleName)
+	firing := f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "be", firing[0].Resource)
+	assert.Equal(t, "1 failed builds in 1h0m0s, more than 0", firing[0].Message)
+}
+
+func TestTimeToReady(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric:   v1alpha1.AlertMetricTimeToReady,
+		Duration: &metav1.Duration{Duration: time.Minute},
+	})
+
+	// Waiting to become ready.
+	f.finishBuild("fe", 10*time.Second)
+	f.setReady("fe", metav1.ConditionFalse, "RuntimePending")
+	result := f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+	assert.Equal(t, 51*time.Second, result.RequeueAfter)
+
+	f.clock.Advance(result.RequeueAfter)
+	f.MustReconcile(ruleName)
+	firing := f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "1m1s", firing[0].Value)
+	assert.Equal(t, "not ready 1m1s after the build started, more than 1m0s", firing[0].Message)
+
+	// Became ready, but too late.
+	f.clock.Advance(time.Minute)
+	f.setReady("fe", metav1.ConditionTrue, "")
+	f.MustReconcile(ruleName)
+	firing = f.firing()
+	require.Len(t, firing, 1)
+	assert.Equal(t, "took 2m1s to become ready, more than 1m0s", firing[0].Message)
+
+	// A faster deploy resolves the alert.
+	f.finishBuild("fe", time.Second)
+	f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+}
+
+func TestTimeToReadyIgnoresFailures(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric:   v1alpha1.AlertMetricTimeToReady,
+		Duration: &metav1.Duration{Duration: time.Minute},
+	})
+
+	f.finishBuild("fe", 10*time.Second)
+	f.setReady("fe", metav1.ConditionFalse, "RuntimeError")
+	f.clock.Advance(time.Hour)
+	f.MustReconcile(ruleName)
+	assert.Empty(t, f.firing())
+}
+
+func TestFiringMetric(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("metric-fe")
+	f.createRule(v1alpha1.AlertRuleSpec{
+		Metric:   v1alpha1.AlertMetricBuildDuration,
+		Duration: &metav1.Duration{Duration: time.Minute},
+	})
+
+	f.finishBuild("metric-fe", 2*time.Minute)
+	f.MustReconcile(ruleName)
+	assert.True(t, f.hasFiringSeries("metric-fe"))
+
+	f.finishBuild("metric-fe", time.Second)
+	f.MustReconcile(ruleName)
+	assert.False(t, f.hasFiringSeries("metric-fe"))
+
+	f.finishBuild("metric-fe", 2*time.Minute)
+	f.MustReconcile(ruleName)
+	assert.True(t, f.hasFiringSeries("metric-fe"))
+
+	var rule v1alpha1.AlertRule
+	f.MustGet(ruleName, &rule)
+	f.Delete(&rule)
+	assert.False(t, f.hasFiringSeries("metric-fe"))
+}
+
+func TestValidate(t *testing.T) {
+	r := &v1alpha1.AlertRule{Spec: v1alpha1.AlertRuleSpec{Metric: v1alpha1.AlertMetricBuildDuration}}
+	errs := r.Validate(nil)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "BuildDuration rules need a positive duration")
+
+	r = &v1alpha1.AlertRule{Spec: v1alpha1.AlertRuleSpec{
+		Metric: v1alpha1.AlertMetricPodRestarts,
+		Count:  -1,
+		Window: &metav1.Duration{},
+	}}
+	errs = r.Validate(nil)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "must not be negative")
+	assert.Contains(t, errs[1].Error(), "must be positive")
+
+	r = &v1alpha1.AlertRule{Spec: v1alpha1.AlertRuleSpec{Metric: "Latency"}}
+	errs = r.Validate(nil)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), `supported values: "BuildDuration", "PodRestarts", "BuildFailures", "TimeToReady"`)
+
+	r = &v1alpha1.AlertRule{Spec: v1alpha1.AlertRuleSpec{Metric: v1alpha1.AlertMetricTimeToReady}}
+	errs = r.Validate(nil)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "TimeToReady rules need a positive duration")
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	clock  clockwork.FakeClock
+	events int
+}
+
+func newFixture(t *testing.T) *fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	clock := clockwork.NewFakeClock()
+	r := NewReconciler(cfb.Client, clock)
+	return &fixture{
+		ControllerFixture: cfb.Build(r),
+		clock:             clock,
+	}
+}
+
+func (f *fixture) createRule(spec v1alpha1.AlertRuleSpec) {
+	f.Create(&v1alpha1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{Name: ruleName.Name},
+		Spec:       spec,
+	})
+}
+
+func (f *fixture) firing() []v1alpha1.AlertFiring {
+	var rule v1alpha1.AlertRule
+	f.MustGet(ruleName, &rule)
+	return rule.Status.Firing
+}
+
+func (f *fixture) createResource(name string) {
+	err := f.Client.Create(f.Context(), &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	require.NoError(f.T(), err)
+}
+
+func (f *fixture) updateResource(name string, update func(uir *v1alpha1.UIResource)) {
+	var uir v1alpha1.UIResource
+	f.MustGet(types.NamespacedName{Name: name}, &uir)
+	update(&uir)
+	err := f.Client.Status().Update(f.Context(), &uir)
+	require.NoError(f.T(), err)
+}
+
+func (f *fixture) finishBuild(name string, d time.Duration) {
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		now := f.clock.Now()
+		uir.Status.BuildHistory = append([]v1alpha1.UIBuildTerminated{{
+			StartTime:  apis.NewMicroTime(now.Add(-d)),
+			FinishTime: apis.NewMicroTime(now),
+		}}, uir.Status.BuildHistory...)
+	})
+}
+
+// Sets the Ready condition, as if it changed now.
+func (f *fixture) setReady(name string, status metav1.ConditionStatus, reason string) {
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		uir.Status.Conditions = []v1alpha1.UIResourceCondition{{
+			Type:               v1alpha1.UIResourceReady,
+			Status:             status,
+			Reason:             reason,
+			LastTransitionTime: apis.NewMicroTime(f.clock.Now()),
+		}}
+	})
+}
+
+func (f *fixture) hasFiringSeries(resource string) bool {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	require.NoError(f.T(), err)
+	for _, family := range families {
+		if family.GetName() != "tilt_alert_firing" {
+			continue
+		}
+		for _, m := range family.Metric {
+			if testutil.LabelsMatch(m, map[string]string{
+				metrics.LabelAlertRule: ruleName.Name,
+				metrics.LabelResource:  resource,
+			}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *fixture) createEvent(resource string, t v1alpha1.ResourceEventType) {
+	f.events++
+	err := f.Client.Create(f.Context(), &v1alpha1.ResourceEvent{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("event-%d", f.events)},
+		Spec: v1alpha1.ResourceEventSpec{
+			Type:     t,
+			Resource: resource,
+			Sequence: int64(f.events),
+			Time:     apis.NewMicroTime(f.clock.Now()),
+		},
+	})
+	require.NoError(f.T(), err)
+}
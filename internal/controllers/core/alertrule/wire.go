@@ -0,0 +1,7 @@
+package alertrule
+
+import "github.com/google/wire"
+
+var WireSet = wire.NewSet(
+	NewReconciler,
+)
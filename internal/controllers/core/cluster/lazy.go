@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Tracks the clusters that we haven't connected to yet, because every
+// resource that uses them is disabled.
+//
+// Connecting to a cluster starts health checks and reads its metadata,
+// which is wasted work in a big project where most resources are disabled.
+// Once we've connected, we stay connected, even if those resources are
+// disabled again.
+type deferredClusters struct {
+	mu    sync.Mutex
+	names map[types.NamespacedName]bool
+}
+
+func newDeferredClusters() *deferredClusters {
+	return &deferredClusters{names: make(map[types.NamespacedName]bool)}
+}
+
+func (d *deferredClusters) set(nn types.NamespacedName, deferred bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if deferred {
+		d.names[nn] = true
+	} else {
+		delete(d.names, nn)
+	}
+}
+
+// Enqueues every deferred cluster when a ConfigMap changes, because the
+// ConfigMap may enable a resource that uses it.
+func (d *deferredClusters) enqueueAll(obj ctrlclient.Object) []reconcile.Request {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	result := make([]reconcile.Request, 0, len(d.names))
+	for nn := range d.names {
+		result = append(result, reconcile.Request{NamespacedName: nn})
+	}
+	return result
+}
+
+// Enqueues the cluster that a resource uses, if it's deferred. Once we've
+// connected, there's no need to hear about the resource.
+func (d *deferredClusters) enqueueForResource(obj ctrlclient.Object) []reconcile.Request {
+	var name string
+	switch obj := obj.(type) {
+	case *v1alpha1.KubernetesApply:
+		name = kubernetesApplyCluster(obj)
+	case *v1alpha1.DockerComposeService:
+		name = v1alpha1.ClusterNameDocker
+	default:
+		return nil
+	}
+
+	nn := types.NamespacedName{Name: name}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.names[nn] {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: nn}}
+}
+
+func kubernetesApplyCluster(ka *v1alpha1.KubernetesApply) string {
+	if ka.Spec.Cluster == "" {
+		return v1alpha1.ClusterNameDefault
+	}
+	return ka.Spec.Cluster
+}
+
+// Whether we should wait to connect to the cluster, because no enabled
+// resource uses it.
+//
+// Only the clusters that the Tiltfile creates for its resources are
+// deferred. Other clusters may be used by objects that we don't know about.
+func (r *Reconciler) shouldDeferConnection(ctx context.Context, cluster *v1alpha1.Cluster) (bool, error) {
+	var sources []*v1alpha1.DisableSource
+	switch cluster.Name {
+	case v1alpha1.ClusterNameDefault:
+		var list v1alpha1.KubernetesApplyList
+		err := r.ctrlClient.List(ctx, &list)
+		if err != nil {
+			return false, err
+		}
+		for i := range list.Items {
+			if kubernetesApplyCluster(&list.Items[i]) == cluster.Name {
+				sources = append(sources, list.Items[i].Spec.DisableSource)
+			}
+		}
+	case v1alpha1.ClusterNameDocker:
+		var list v1alpha1.DockerComposeServiceList
+		err := r.ctrlClient.List(ctx, &list)
+		if err != nil {
+			return false, err
+		}
+		for _, dcs := range list.Items {
+			sources = append(sources, dcs.Spec.DisableSource)
+		}
+	default:
+		return false, nil
+	}
+
+	getCM := func(name string) (v1alpha1.ConfigMap, error) {
+		var cm v1alpha1.ConfigMap
+		err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: name}, &cm)
+		return cm, err
+	}
+	for _, source := range sources {
+		state, _, err := configmap.DisableStatus(getCM, source)
+		if err != nil {
+			return false, err
+		}
+
+		// A pending resource is waiting for its ConfigMap, so it's not
+		// enabled yet. But if we can't tell, connect.
+		if state != v1alpha1.DisableStateDisabled && state != v1alpha1.DisableStatePending {
+			return false, nil
+		}
+	}
+	return true, nil
+}
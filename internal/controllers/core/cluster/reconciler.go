@@ -21,6 +21,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/container"
@@ -62,12 +63,19 @@ type Reconciler struct {
 	wsList           *server.WebsocketList
 
 	clusterHealth *clusterHealthMonitor
+	deferred      *deferredClusters
 }
 
 func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Cluster{}).
-		Watches(r.requeuer, handler.Funcs{})
+		Watches(r.requeuer, handler.Funcs{}).
+		Watches(&source.Kind{Type: &v1alpha1.KubernetesApply{}},
+			handler.EnqueueRequestsFromMapFunc(r.deferred.enqueueForResource)).
+		Watches(&source.Kind{Type: &v1alpha1.DockerComposeService{}},
+			handler.EnqueueRequestsFromMapFunc(r.deferred.enqueueForResource)).
+		Watches(&source.Kind{Type: &v1alpha1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.deferred.enqueueAll))
 	return b, nil
 }
 
@@ -98,6 +106,7 @@ func NewReconciler(
 		k8sClientFactory:    k8sClientFactory,
 		wsList:              wsList,
 		clusterHealth:       newClusterHealthMonitor(globalCtx, clock, requeuer),
+		deferred:            newDeferredClusters(),
 		base:                base,
 		apiServerName:       apiServerName,
 	}
@@ -116,6 +125,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	if apierrors.IsNotFound(err) || !obj.ObjectMeta.DeletionTimestamp.IsZero() {
 		r.store.Dispatch(clusters.NewClusterDeleteAction(request.Name))
 		r.cleanup(nn)
+		r.deferred.set(nn, false)
 		r.wsList.ForEach(func(ws *server.WebsocketSubscriber) {
 			ws.SendClusterUpdate(ctx, nn, nil)
 		})
@@ -144,6 +154,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		}
 	}
 
+	if !hasConnection {
+		deferred, err := r.shouldDeferConnection(ctx, &obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.deferred.set(nn, deferred)
+		if deferred {
+			logger.Get(ctx).Debugf("Waiting to connect to cluster %q until a resource that uses it is enabled", nn.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	var requeueAfter time.Duration
 	if !hasConnection {
 		// Create the initial connection to the cluster.
@@ -3,6 +3,7 @@ package cluster
 import (
 	"errors"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -13,6 +14,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
@@ -292,7 +295,7 @@ func newFixture(t *testing.T) *fixture {
 		"tilt-default")
 	requeueChan := make(chan indexer.RequeueForTestResult, 1)
 	indexer.StartSourceForTesting(cfb.Context(), r.requeuer, r, requeueChan)
-	return &fixture{
+	f := &fixture{
 		ControllerFixture: cfb.Build(r),
 		r:                 r,
 		ma:                cfb.Analytics(),
@@ -301,6 +304,39 @@ func newFixture(t *testing.T) *fixture {
 		dockerClient:      dockerClient,
 		requeues:          requeueChan,
 	}
+
+	// Tilt only connects to a cluster once an enabled resource uses it.
+	f.createResource(&v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe"},
+		Spec:       v1alpha1.KubernetesApplySpec{YAML: "fake-yaml", Cluster: v1alpha1.ClusterNameDefault},
+	})
+	f.createResource(&v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec:       v1alpha1.DockerComposeServiceSpec{Service: "db"},
+	})
+	return f
+}
+
+// Creates an object without reconciling it, since it isn't a Cluster.
+func (f *fixture) createResource(o ctrlclient.Object) {
+	f.T().Helper()
+	require.NoError(f.T(), f.Client.Create(f.Context(), o))
+}
+
+func (f *fixture) setDisabled(cmName string, disabled bool) {
+	f.T().Helper()
+	cm := &v1alpha1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName},
+		Data:       map[string]string{"isDisabled": strconv.FormatBool(disabled)},
+	}
+	var existing v1alpha1.ConfigMap
+	err := f.Client.Get(f.Context(), types.NamespacedName{Name: cmName}, &existing)
+	if err == nil {
+		cm.ResourceVersion = existing.ResourceVersion
+		require.NoError(f.T(), f.Client.Update(f.Context(), cm))
+		return
+	}
+	f.createResource(cm)
 }
 
 func (f *fixture) assertSteadyState(o *v1alpha1.Cluster) {
@@ -312,3 +348,82 @@ func (f *fixture) assertSteadyState(o *v1alpha1.Cluster) {
 		"Cluster object should have been in steady state but changed: %s",
 		cmp.Diff(o, &o2))
 }
+
+func TestKubernetesWaitsForEnabledResource(t *testing.T) {
+	f := newFixture(t)
+
+	var ka v1alpha1.KubernetesApply
+	f.MustGet(types.NamespacedName{Name: "fe"}, &ka)
+	ka.Spec.DisableSource = &v1alpha1.DisableSource{
+		ConfigMap: &v1alpha1.ConfigMapDisableSource{Name: "fe-disable", Key: "isDisabled"},
+	}
+	require.NoError(t, f.Client.Update(f.Context(), &ka))
+	f.setDisabled("fe-disable", true)
+
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	}
+	nn := apis.Key(cluster)
+	f.r.k8sClientFactory = FakeKubernetesClientOrError(nil, errors.New("should not connect"))
+	f.Create(cluster)
+
+	f.MustGet(nn, cluster)
+	assert.Equal(t, v1alpha1.ClusterStatus{}, cluster.Status)
+	_, connected := f.r.connManager.load(nn)
+	assert.False(t, connected)
+
+	// Enabling the resource enqueues the cluster, which connects.
+	assert.Equal(t, []reconcile.Request{{NamespacedName: nn}},
+		f.r.deferred.enqueueAll(&v1alpha1.ConfigMap{}))
+	f.r.k8sClientFactory = FakeKubernetesClientOrError(f.k8sClient, nil)
+	f.setDisabled("fe-disable", false)
+	f.MustReconcile(nn)
+
+	f.MustGet(nn, cluster)
+	assert.Equal(t, "", cluster.Status.Error)
+	assert.NotNil(t, cluster.Status.ConnectedAt)
+	assert.Empty(t, f.r.deferred.enqueueAll(&v1alpha1.ConfigMap{}))
+
+	// Disabling the resource again doesn't disconnect.
+	f.setDisabled("fe-disable", true)
+	f.MustReconcile(nn)
+	f.MustGet(nn, cluster)
+	assert.NotNil(t, cluster.Status.ConnectedAt)
+}
+
+func TestOtherClustersConnectRightAway(t *testing.T) {
+	f := newFixture(t)
+
+	require.NoError(t, f.Client.Delete(f.Context(), &v1alpha1.KubernetesApply{ObjectMeta: metav1.ObjectMeta{Name: "fe"}}))
+
+	// The default cluster has no resources that use it yet.
+	defaultCluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	}
+	f.Create(defaultCluster)
+	f.MustGet(apis.Key(defaultCluster), defaultCluster)
+	assert.Nil(t, defaultCluster.Status.ConnectedAt)
+
+	// A cluster that the Tiltfile didn't create may be used by anything.
+	other := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	}
+	f.Create(other)
+	f.MustGet(apis.Key(other), other)
+	assert.NotNil(t, other.Status.ConnectedAt)
+}
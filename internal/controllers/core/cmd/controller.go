@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jonboulle/clockwork"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,6 +29,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/trigger"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/timecmp"
@@ -47,6 +50,7 @@ type Controller struct {
 	st            store.RStore
 	clock         clockwork.Clock
 	requeuer      *indexer.Requeuer
+	lifecycle     LifecycleObserver
 
 	mu sync.Mutex
 }
@@ -81,9 +85,18 @@ func NewController(ctx context.Context, execer Execer, proberManager ProberManag
 		client:        client,
 		st:            st,
 		requeuer:      indexer.NewRequeuer(),
+		lifecycle:     prometheusLifecycleObserver{},
 	}
 }
 
+// Sends the lifecycle of each command to the given observer, instead of
+// exporting it as Prometheus metrics.
+func (c *Controller) SetLifecycleObserver(o LifecycleObserver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lifecycle = o
+}
+
 // Stop the command, and wait for it to finish before continuing.
 func (c *Controller) stop(name types.NamespacedName) {
 	proc, ok := c.procs[name]
@@ -101,6 +114,34 @@ func (c *Controller) stop(name types.NamespacedName) {
 	proc.doneCh = nil
 }
 
+// Sends input to the stdin of a running Cmd.
+//
+// Blocks until the process reads it, if its input buffer is full.
+func (c *Controller) SendInput(name string, input []byte) error {
+	c.mu.Lock()
+	proc, ok := c.procs[types.NamespacedName{Name: name}]
+	var stdin *io.PipeWriter
+	running := false
+	if ok {
+		stdin = proc.stdin
+		running = proc.copyStatus().Running != nil
+	}
+	c.mu.Unlock()
+
+	if !running {
+		return fmt.Errorf("cmd %q isn't running", name)
+	}
+	if stdin == nil {
+		return fmt.Errorf("cmd %q doesn't read input. Set stdin on the Cmd to send it input", name)
+	}
+
+	_, err := stdin.Write(input)
+	if err == io.ErrClosedPipe {
+		return fmt.Errorf("cmd %q isn't running", name)
+	}
+	return err
+}
+
 func (c *Controller) TearDown(ctx context.Context) {
 	for name := range c.procs {
 		c.stop(name)
@@ -205,6 +246,8 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	startOnTriggered := timecmp.After(te.lastStartEventTime, lastStartOnEventTime)
 	execSpecChanged := !cmdExecEqual(lastSpec, cmd.Spec)
 
+	var result ctrl.Result
+
 	if !disabled {
 		// any change to the spec means we should stop the command immediately
 		if execSpecChanged {
@@ -225,7 +268,12 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		} else if execSpecChanged || restartOnTriggered || startOnTriggered {
 			// Otherwise, any change, new start event, or new restart event
 			// should restart the process to pick up changes.
-			_ = c.runInternal(ctx, cmd, te)
+			proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+				status.Restarts = nil
+			})
+			_ = c.runInternal(ctx, cmd, te, logger.WarnLvl)
+		} else {
+			result = c.maybeRestart(ctx, cmd, proc, te)
 		}
 	}
 
@@ -234,7 +282,78 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+const (
+	restartBackoffInitial = time.Second
+	restartBackoffMax     = time.Minute
+)
+
+// How long to wait before the given restart, counting from 0.
+func restartBackoff(count int32) time.Duration {
+	backoff := restartBackoffInitial
+	for i := int32(0); i < count && backoff < restartBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > restartBackoffMax {
+		backoff = restartBackoffMax
+	}
+	return backoff
+}
+
+func shouldRestart(policy v1alpha1.CmdRestartPolicy, exitCode int32) bool {
+	switch policy {
+	case v1alpha1.CmdRestartPolicyAlways:
+		return true
+	case v1alpha1.CmdRestartPolicyOnFailure:
+		return exitCode != 0
+	}
+	return false
+}
+
+// Restarts the command if it exited and its restart policy says to.
+//
+// The first reconcile after the exit schedules the restart, and a later one
+// runs it, so that the backoff shows up in the status while we wait.
+func (c *Controller) maybeRestart(ctx context.Context, cmd *v1alpha1.Cmd, proc *currentProcess, te triggerEvents) ctrl.Result {
+	status := proc.copyStatus()
+	if status.Terminated == nil || !shouldRestart(cmd.Spec.RestartPolicy, status.Terminated.ExitCode) {
+		return ctrl.Result{}
+	}
+
+	restarts := v1alpha1.CmdRestartStatus{}
+	if status.Restarts != nil {
+		restarts = *status.Restarts
+	}
+	if cmd.Spec.MaxRestarts > 0 && restarts.Count >= cmd.Spec.MaxRestarts {
+		return ctrl.Result{}
+	}
+
+	now := c.clock.Now()
+	if restarts.NextRestartTime.IsZero() {
+		backoff := restartBackoff(restarts.Count)
+		restarts.NextRestartTime = apis.NewMicroTime(now.Add(backoff))
+		proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+			status.Restarts = &restarts
+		})
+		logger.Get(store.MustObjectLogHandler(ctx, c.st, cmd)).Infof(
+			"Process exited with exit code %d. Restarting in %s", status.Terminated.ExitCode, backoff)
+		return ctrl.Result{RequeueAfter: backoff}
+	}
+
+	if wait := restarts.NextRestartTime.Time.Sub(now); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}
+	}
+
+	restarts.Count++
+	restarts.NextRestartTime = metav1.MicroTime{}
+	proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+		status.Restarts = &restarts
+	})
+	c.lifecycle.OnRestart(lifecycleKeyForCmd(cmd), restarts.Count)
+	_ = c.runInternal(ctx, cmd, te, logger.WarnLvl)
+	return ctrl.Result{}
 }
 
 func (c *Controller) maybeUpdateObjectStatus(ctx context.Context, cmd *v1alpha1.Cmd) error {
@@ -261,7 +380,9 @@ func (c *Controller) maybeUpdateObjectStatus(ctx context.Context, cmd *v1alpha1.
 // Blocks until the command is finished, then returns its status.
 func (c *Controller) ForceRun(ctx context.Context, cmd *v1alpha1.Cmd) (*v1alpha1.CmdStatus, error) {
 	c.mu.Lock()
-	doneCh := c.runInternal(ctx, cmd, triggerEvents{})
+	// Build tools print progress on stderr, so keep it out of the build's
+	// warnings.
+	doneCh := c.runInternal(ctx, cmd, triggerEvents{}, logger.InfoLvl)
 	c.mu.Unlock()
 
 	select {
@@ -327,16 +448,21 @@ func (c *Controller) ensureProc(name types.NamespacedName) *currentProcess {
 // The filewatches and buttons are needed for bookkeeping on how the command
 // was triggered.
 //
+// Stderr is logged at the given level, so that error output can stand out.
+//
 // Returns a channel that closes when the Cmd is finished.
 func (c *Controller) runInternal(ctx context.Context,
 	cmd *v1alpha1.Cmd,
-	te triggerEvents) chan struct{} {
+	te triggerEvents,
+	stderrLevel logger.Level) chan struct{} {
 	name := types.NamespacedName{Name: cmd.Name}
 	c.stop(name)
 
 	proc := c.ensureProc(name)
 	proc.spec = cmd.Spec
 	proc.isServer = cmd.ObjectMeta.Annotations[local.AnnotationOwnerKind] == "CmdServer"
+	proc.lifecycle = c.lifecycle
+	proc.lifecycleKey = lifecycleKeyForCmd(cmd)
 
 	proc.lastRestartOnEventTime = te.lastRestartEventTime
 	proc.lastStartOnEventTime = te.lastStartEventTime
@@ -363,7 +489,7 @@ func (c *Controller) runInternal(ctx context.Context,
 
 	if spec.ReadinessProbe != nil {
 		probeResultFunc := c.handleProbeResultFunc(ctx, name, proc)
-		probeWorker, err := probeWorkerFromSpec(
+		probeWorker, err := ProbeWorkerFromSpec(
 			c.proberManager,
 			spec.ReadinessProbe,
 			probeResultFunc)
@@ -384,6 +510,38 @@ func (c *Controller) runInternal(ctx context.Context,
 		proc.probeWorker = probeWorker
 	}
 
+	var matcher *readyMatcher
+	if spec.ReadyRegex != "" {
+		re, err := regexp.Compile(spec.ReadyRegex)
+		if err != nil {
+			logger.Get(ctx).Errorf("Invalid ready regex: %v", err)
+			status.Terminated = &CmdStateTerminated{
+				ExitCode: 1,
+				Reason:   fmt.Sprintf("Invalid ready regex: %v", err),
+			}
+			status.Waiting = nil
+
+			proc.doneCh = make(chan struct{})
+			close(proc.doneCh)
+			return proc.doneCh
+		}
+		matcher = newReadyMatcher(re)
+	}
+
+	target, err := c.resolveTarget(ctx, spec.Target)
+	if err != nil {
+		logger.Get(ctx).Errorf("Unable to find container: %v", err)
+		status.Terminated = &CmdStateTerminated{
+			ExitCode: 1,
+			Reason:   fmt.Sprintf("Unable to find container: %v", err),
+		}
+		status.Waiting = nil
+
+		proc.doneCh = make(chan struct{})
+		close(proc.doneCh)
+		return proc.doneCh
+	}
+
 	startedAt := apis.NewMicroTime(c.clock.Now())
 
 	env := append([]string{}, spec.Env...)
@@ -392,18 +550,76 @@ func (c *Controller) runInternal(ctx context.Context,
 	}
 
 	cmdModel := model.Cmd{
-		Argv: spec.Args,
-		Dir:  spec.Dir,
-		Env:  env,
+		Argv:              spec.Args,
+		Dir:               spec.Dir,
+		Env:               env,
+		TerminationSignal: spec.TerminationSignal,
+		PTY:               spec.PTY,
+		Stdin:             spec.Stdin,
+		EnvFiles:          spec.EnvFiles,
+		ExpandEnv:         spec.ExpandEnv,
+		Target:            target,
 	}
-	statusCh := c.execer.Start(ctx, cmdModel, logger.Get(ctx).Writer(logger.InfoLvl))
+	if spec.GracePeriod != nil {
+		gracePeriod := spec.GracePeriod.Duration
+		cmdModel.GracePeriod = &gracePeriod
+	}
+	if spec.Timeout != nil {
+		cmdModel.Timeout = spec.Timeout.Duration
+	}
+	// processStatuses closes stdin when the process exits.
+	var stdin io.Reader
+	proc.stdin = nil
+	if spec.Stdin {
+		stdin, proc.stdin = io.Pipe()
+	}
+
+	l := logger.Get(ctx)
+	stdout, stderr := l.Writer(logger.InfoLvl), l.Writer(stderrLevel)
+	var readyCh chan string
+	if matcher != nil {
+		stdout, stderr = matcher.writer(stdout), matcher.writer(stderr)
+		readyCh = matcher.matchCh
+	}
+	statusCh := c.execer.Start(ctx, cmdModel, stdin, stdout, stderr)
 	proc.doneCh = make(chan struct{})
 
-	go c.processStatuses(ctx, statusCh, proc, name, startedAt)
+	go c.processStatuses(ctx, statusCh, readyCh, proc, name, startedAt)
 
 	return proc.doneCh
 }
 
+// Finds the container of a target's Docker Compose service. Compose
+// replaces the container when the service changes, so this happens every
+// time the process starts.
+func (c *Controller) resolveTarget(ctx context.Context, target *v1alpha1.CmdTarget) (*v1alpha1.CmdTarget, error) {
+	if target == nil || target.Container == nil || target.Container.DockerComposeService == "" {
+		return target, nil
+	}
+
+	name := target.Container.DockerComposeService
+	var svc v1alpha1.DockerComposeService
+	err := c.client.Get(ctx, types.NamespacedName{Name: name}, &svc)
+	if err != nil {
+		return nil, fmt.Errorf("service %q: %v", name, err)
+	}
+	if dockercompose.HasDockerEndpoint(svc.Spec.Project) {
+		return nil, fmt.Errorf("service %q runs on its project's own Docker daemon, "+
+			"and commands can only run in containers on Tilt's", name)
+	}
+	cName := svc.Status.ContainerName
+	if cName == "" {
+		cName = svc.Status.ContainerID
+	}
+	if cName == "" {
+		return nil, fmt.Errorf("service %q has no container", name)
+	}
+
+	resolved := target.DeepCopy()
+	resolved.Container = &v1alpha1.CmdContainerTarget{Name: cName}
+	return resolved, nil
+}
+
 func (c *Controller) handleProbeResultFunc(ctx context.Context, name types.NamespacedName, proc *currentProcess) probe.ResultFunc {
 	return func(result prober.Result, statusChanged bool, output string, err error) {
 		if ctx.Err() != nil {
@@ -446,12 +662,28 @@ func (c *Controller) handleProbeResultFunc(ctx context.Context, name types.Names
 
 		status := &(proc.statusInternal)
 		if status.Ready != ready {
-			status.Ready = ready
+			if ready {
+				c.markReady(proc, status)
+			} else {
+				status.Ready = false
+			}
 			c.requeuer.Add(name)
 		}
 	}
 }
 
+// Marks the command ready, and records how long it took to become ready
+// the first time it's ready in a run. Caller must hold proc.statusMu.
+func (c *Controller) markReady(proc *currentProcess, status *v1alpha1.CmdStatus) {
+	status.Ready = true
+	running := status.Running
+	if running == nil || !running.ReadyAt.IsZero() {
+		return
+	}
+	running.ReadyAt = apis.NewMicroTime(c.clock.Now())
+	proc.lifecycle.OnReady(proc.lifecycleKey, running.ReadyAt.Sub(running.StartedAt.Time))
+}
+
 func logProbeOutput(ctx context.Context, level logger.Level, result prober.Result, output string, err error) {
 	l := logger.Get(ctx)
 	if level == logger.NoneLvl || !l.Level().ShouldDisplay(level) {
@@ -477,27 +709,81 @@ func logProbeOutput(ctx context.Context, level logger.Level, result prober.Resul
 
 const waitingOnStartOnReason = "cmd StartOn has not been triggered"
 
+// Updates the status of the process as it runs.
+//
+// readyCh reports the line that matched the ReadyRegex, if the spec has one.
+// Until then, the process isn't ready, and its readiness probe doesn't
+// start.
 func (c *Controller) processStatuses(
 	ctx context.Context,
 	statusCh chan statusAndMetadata,
+	readyCh chan string,
 	proc *currentProcess,
 	name types.NamespacedName,
 	startedAt metav1.MicroTime) {
 	defer close(proc.doneCh)
+	if stdin := proc.stdin; stdin != nil {
+		defer func() { _ = stdin.Close() }()
+	}
 
 	var initProbeWorker sync.Once
+	startProbeWorker := func() {
+		if proc.probeWorker != nil {
+			initProbeWorker.Do(func() {
+				go proc.probeWorker.Run(ctx)
+			})
+		}
+	}
+
+	matched := readyCh == nil
+	readyMatch := ""
+	running := false
+	started := false
+
+	for {
+		var sm statusAndMetadata
+		select {
+		case line := <-readyCh:
+			// Only the first match counts.
+			readyCh = nil
+			matched = true
+			readyMatch = line
+			if !running {
+				continue
+			}
+
+			startProbeWorker()
+			proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+				if status.Running != nil {
+					status.Running.ReadyMatch = readyMatch
+				}
+				if proc.probeWorker == nil {
+					c.markReady(proc, status)
+				}
+			})
+			c.requeuer.Add(name)
+			continue
+
+		case s, ok := <-statusCh:
+			if !ok {
+				return
+			}
+			sm = s
+		}
 
-	for sm := range statusCh {
 		if sm.status == Unknown {
 			continue
 		}
 
 		if sm.status == Error || sm.status == Done {
+			running = false
+
 			// This is a hack until CmdServer is a real object.
 			if proc.isServer && sm.exitCode == 0 {
 				logger.Get(ctx).Errorf("Server exited with exit code 0")
 			}
 
+			finishedAt := apis.NewMicroTime(c.clock.Now())
 			proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
 				status.Waiting = nil
 				status.Running = nil
@@ -506,27 +792,40 @@ func (c *Controller) processStatuses(
 					Reason:     sm.reason,
 					ExitCode:   int32(sm.exitCode),
 					StartedAt:  startedAt,
-					FinishedAt: apis.NewMicroTime(c.clock.Now()),
+					FinishedAt: finishedAt,
 				}
 			})
+			proc.lifecycle.OnExit(proc.lifecycleKey, sm.exitReason(started), finishedAt.Sub(startedAt.Time))
 			c.requeuer.Add(name)
 		} else if sm.status == Running {
-			if proc.probeWorker != nil {
-				initProbeWorker.Do(func() {
-					go proc.probeWorker.Run(ctx)
-				})
+			running = true
+			if matched {
+				startProbeWorker()
+			}
+			if !started {
+				started = true
+				proc.lifecycle.OnStart(proc.lifecycleKey, startedAt.Time)
 			}
 
 			proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+				// Usage samples replace the running state, but it only
+				// becomes ready once.
+				var readyAt metav1.MicroTime
+				if status.Running != nil {
+					readyAt = status.Running.ReadyAt
+				}
 				status.Waiting = nil
 				status.Terminated = nil
 				status.Running = &CmdStateRunning{
-					PID:       int32(sm.pid),
-					StartedAt: startedAt,
+					PID:        int32(sm.pid),
+					StartedAt:  startedAt,
+					Usage:      sm.usage,
+					ReadyMatch: readyMatch,
+					ReadyAt:    readyAt,
 				}
 
-				if proc.probeWorker == nil {
-					status.Ready = true
+				if proc.probeWorker == nil && matched {
+					c.markReady(proc, status)
 				}
 			})
 			c.requeuer.Add(name)
@@ -562,9 +861,16 @@ type currentProcess struct {
 	probeWorker *probe.Worker
 	isServer    bool
 
+	// How the current run's lifecycle is reported.
+	lifecycle    LifecycleObserver
+	lifecycleKey LifecycleKey
+
 	lastRestartOnEventTime metav1.MicroTime
 	lastStartOnEventTime   metav1.MicroTime
 
+	// Input for the process, if its spec asks for stdin.
+	stdin *io.PipeWriter
+
 	// We have a lock that ONLY protects the status.
 	statusMu       sync.Mutex
 	statusInternal v1alpha1.CmdStatus
@@ -587,6 +893,28 @@ type statusAndMetadata struct {
 	status   status
 	exitCode int
 	reason   string
+
+	// A sample of the resources that a running process uses.
+	usage *v1alpha1.CmdResourceUsage
+}
+
+// The reason that execers give for a process that they stopped because its
+// context was canceled.
+const reasonKilled = "killed"
+
+// Why the process stopped, given whether it ever started running.
+func (sm statusAndMetadata) exitReason(started bool) ExitReason {
+	switch {
+	case sm.reason == v1alpha1.CmdReasonTimedOut:
+		return ExitReasonTimedOut
+	case sm.reason == reasonKilled:
+		return ExitReasonKilled
+	case !started:
+		return ExitReasonStartFailed
+	case sm.status == Done && sm.exitCode == 0:
+		return ExitReasonSucceeded
+	}
+	return ExitReasonFailed
 }
 
 type status int
@@ -6,6 +6,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils/configmap"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -96,6 +98,160 @@ func TestUpdateWithCurrentBuild(t *testing.T) {
 	f.assertCmdDeleted("foo-serve-1")
 }
 
+func TestServeReadyRegex(t *testing.T) {
+	f := newFixture(t)
+
+	c := model.ToHostCmdInDir("sleep 60", "testdir")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil)
+	localTarget.ServeReadyRegex = "listening on [0-9]+"
+
+	f.resourceFromTarget("foo", localTarget, time.Unix(1, 0))
+	f.step()
+	cmd := f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+	assert.False(t, cmd.Status.Ready)
+
+	require.NoError(t, f.fe.writeStdout("sleep 60", "starting\n\x1b[32mlistening on 8080\x1b[0m\n"))
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Ready && cmd.Status.Running != nil &&
+			cmd.Status.Running.ReadyMatch == "listening on 8080"
+	})
+}
+
+func TestServeReadyRegexThenProbe(t *testing.T) {
+	f := newFixture(t)
+
+	c := model.ToHostCmdInDir("sleep 60", "testdir")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil)
+	localTarget.ServeReadyRegex = "listening"
+	localTarget.ReadinessProbe = &v1alpha1.Probe{
+		TimeoutSeconds: 5,
+		Handler: v1alpha1.Handler{
+			TCPSocket: &v1alpha1.TCPSocketAction{Port: 8080},
+		},
+	}
+
+	f.resourceFromTarget("foo", localTarget, time.Unix(1, 0))
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	// The probe only starts once the regex matches.
+	assert.Never(t, func() bool {
+		return strings.Contains(f.Stdout(), "[readiness probe")
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	require.NoError(t, f.fe.writeStderr("sleep 60", "listening\n"))
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Ready
+	})
+	f.assertLogMessage("foo", "[readiness probe: success] fake probe succeeded")
+	assert.Equal(t, 8080, f.fpm.tcpPort)
+}
+
+func TestInvalidReadyRegex(t *testing.T) {
+	f := newFixture(t)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:       []string{"myserver"},
+			ReadyRegex: "(",
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil &&
+			strings.Contains(cmd.Status.Terminated.Reason, "Invalid ready regex")
+	})
+	f.fe.RequireNoKnownProcess(t, "myserver")
+}
+
+func TestComposeServiceTarget(t *testing.T) {
+	f := newFixture(t)
+
+	svc := &v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       v1alpha1.DockerComposeServiceSpec{Service: "api"},
+	}
+	require.NoError(t, f.Client.Create(f.Context(), svc))
+	svc.Status.ContainerID = "abc123"
+	svc.Status.ContainerName = "myproj-api-1"
+	require.NoError(t, f.Client.Status().Update(f.Context(), svc))
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args: []string{"myserver"},
+			Target: &v1alpha1.CmdTarget{
+				Container: &v1alpha1.CmdContainerTarget{DockerComposeService: "api"},
+			},
+		},
+	}
+	require.NoError(t, f.Client.Create(f.Context(), cmd))
+	f.reconcileCmd("testcmd")
+
+	last := f.fe.requireLastStart(t, "myserver")
+	assert.Equal(t, &v1alpha1.CmdTarget{Container: &v1alpha1.CmdContainerTarget{Name: "myproj-api-1"}}, last.Target)
+}
+
+func TestComposeServiceTargetWithoutContainer(t *testing.T) {
+	f := newFixture(t)
+
+	svc := &v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       v1alpha1.DockerComposeServiceSpec{Service: "api"},
+	}
+	require.NoError(t, f.Client.Create(f.Context(), svc))
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args: []string{"myserver"},
+			Target: &v1alpha1.CmdTarget{
+				Container: &v1alpha1.CmdContainerTarget{DockerComposeService: "api"},
+			},
+		},
+	}
+	require.NoError(t, f.Client.Create(f.Context(), cmd))
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil &&
+			strings.Contains(cmd.Status.Terminated.Reason, `service "api" has no container`)
+	})
+	f.fe.RequireNoKnownProcess(t, "myserver")
+}
+
+func TestRestartServe(t *testing.T) {
+	f := newFixture(t)
+
+	t1 := time.Unix(1, 0)
+	f.resource("foo", "sleep 60", ".", t1)
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	f.st.Dispatch(local.RestartServeAction{ManifestName: "foo", Time: time.Unix(2, 0)})
+	f.step()
+	f.assertCmdDeleted("foo-serve-1")
+
+	f.step()
+	f.assertCmdMatches("foo-serve-2", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	// The restart doesn't run the update, so the build history is unchanged.
+	f.st.WithState(func(s *store.EngineState) {
+		assert.Len(t, s.ManifestTargets["foo"].State.BuildHistory, 1)
+	})
+	f.assertCmdCount(1)
+}
+
 func TestServe(t *testing.T) {
 	f := newFixture(t)
 
@@ -106,11 +262,26 @@ func TestServe(t *testing.T) {
 		return cmd.Status.Running != nil && cmd.Status.Ready
 	})
 
-	require.Equal(t, "testdir", f.fe.processes["sleep 60"].workdir)
+	f.fe.RequireWorkdir(t, "sleep 60", "testdir")
 
 	f.assertLogMessage("foo", "Starting cmd sleep 60")
 }
 
+func TestServeStderrIsWarning(t *testing.T) {
+	f := newFixture(t)
+
+	f.resource("foo", "sleep 60", "testdir", time.Unix(1, 0))
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	require.NoError(t, f.fe.writeStderr("sleep 60", "connection refused\n"))
+	le := f.waitForLogEventContaining("connection refused")
+	assert.Equal(t, logger.WarnLvl, le.Level())
+	assert.Equal(t, model.ManifestName("foo"), le.ManifestName())
+}
+
 func TestServeReadinessProbe(t *testing.T) {
 	f := newFixture(t)
 
@@ -183,6 +354,340 @@ func TestFailure(t *testing.T) {
 	f.assertLogMessage("foo", "cmd true exited with code 5")
 }
 
+func TestRestartPolicyBackoff(t *testing.T) {
+	f := newFixture(t)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:          []string{"myserver"},
+			RestartPolicy: v1alpha1.CmdRestartPolicyOnFailure,
+			MaxRestarts:   2,
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+
+	for i, backoff := range []time.Duration{time.Second, 2 * time.Second} {
+		f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+			return cmd.Status.Running != nil
+		})
+		require.NoError(t, f.fe.stop("myserver", 1))
+
+		nextRestartTime := f.clock.Now().Add(backoff)
+		f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+			restarts := cmd.Status.Restarts
+			return cmd.Status.Terminated != nil && restarts != nil &&
+				restarts.Count == int32(i) && restarts.NextRestartTime.Time.Equal(nextRestartTime)
+		})
+
+		// Not time yet.
+		f.reconcileCmd("testcmd")
+		f.fe.RequireNoKnownProcess(t, "myserver")
+
+		f.clock.Advance(backoff)
+		f.reconcileCmd("testcmd")
+		f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+			restarts := cmd.Status.Restarts
+			return cmd.Status.Running != nil && restarts != nil &&
+				restarts.Count == int32(i+1) && restarts.NextRestartTime.IsZero()
+		})
+	}
+
+	// Gives up after MaxRestarts.
+	require.NoError(t, f.fe.stop("myserver", 1))
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+	f.clock.Advance(time.Hour)
+	f.reconcileCmd("testcmd")
+	f.fe.RequireNoKnownProcess(t, "myserver")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil && cmd.Status.Restarts.Count == 2 &&
+			cmd.Status.Restarts.NextRestartTime.IsZero()
+	})
+
+	// Changing the spec starts over.
+	f.updateSpec("testcmd", func(spec *v1alpha1.CmdSpec) {
+		spec.Env = []string{"FOO=bar"}
+	})
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && cmd.Status.Restarts == nil
+	})
+}
+
+func TestRestartPolicyOnFailureIgnoresSuccess(t *testing.T) {
+	f := newFixture(t)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:          []string{"myserver"},
+			RestartPolicy: v1alpha1.CmdRestartPolicyOnFailure,
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	require.NoError(t, f.fe.stop("myserver", 0))
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+	f.clock.Advance(time.Minute)
+	f.reconcileCmd("testcmd")
+	f.fe.RequireNoKnownProcess(t, "myserver")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Restarts == nil
+	})
+}
+
+func TestScriptedOutputMatchesReadyRegex(t *testing.T) {
+	f := newFixture(t)
+
+	f.fe.Script("myserver", FakeScript{
+		StartDelay: time.Second,
+		Output: []FakeOutput{
+			{Delay: time.Second, Stdout: "compiling...\n"},
+			{Delay: 2 * time.Second, Stderr: "listening on 8080\n"},
+		},
+	})
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:       []string{"myserver"},
+			ReadyRegex: "listening on [0-9]+",
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+
+	// Still starting.
+	f.clock.BlockUntil(1)
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Waiting != nil && cmd.Status.Running == nil
+	})
+
+	f.clock.Advance(time.Second)
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && !cmd.Status.Ready
+	})
+
+	f.clock.BlockUntil(1)
+	f.clock.Advance(time.Second)
+	f.clock.BlockUntil(1)
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && !cmd.Status.Ready
+	})
+
+	f.clock.Advance(2 * time.Second)
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Ready && cmd.Status.Running.ReadyMatch == "listening on 8080"
+	})
+}
+
+func TestScriptedRestartFailsToStart(t *testing.T) {
+	f := newFixture(t)
+
+	f.fe.ExitCodes("myserver", 1, 2)
+	f.fe.Script("myserver", FakeScript{StartErr: fmt.Errorf("address already in use")})
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:          []string{"myserver"},
+			Dir:           "testdir",
+			Env:           []string{"PORT=8080"},
+			RestartPolicy: v1alpha1.CmdRestartPolicyOnFailure,
+			MaxRestarts:   5,
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+
+	for i, backoff := range []time.Duration{time.Second, 2 * time.Second} {
+		f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+			restarts := cmd.Status.Restarts
+			return cmd.Status.Terminated != nil && cmd.Status.Terminated.ExitCode == int32(i+1) &&
+				restarts != nil && restarts.Count == int32(i)
+		})
+		f.clock.Advance(backoff)
+		f.reconcileCmd("testcmd")
+	}
+
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil && cmd.Status.Terminated.Reason == "address already in use" &&
+			cmd.Status.Restarts.Count == 2
+	})
+	require.Equal(t, 3, f.fe.StartCount("myserver"))
+	f.fe.RequireWorkdir(t, "myserver", "testdir")
+	f.fe.RequireEnv(t, "myserver", []string{"PORT=8080"})
+}
+
+func TestSendInput(t *testing.T) {
+	f := newFixture(t)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:  []string{"myserver"},
+			Stdin: true,
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	stdin, err := f.fe.stdin("myserver")
+	require.NoError(t, err)
+	inputCh := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(stdin)
+		inputCh <- string(b)
+	}()
+
+	require.NoError(t, f.c.SendInput("testcmd", []byte("r\n")))
+	require.EqualError(t, f.c.SendInput("othercmd", []byte("r\n")), `cmd "othercmd" isn't running`)
+
+	// Stdin is closed when the process exits.
+	require.NoError(t, f.fe.stop("myserver", 1))
+	select {
+	case input := <-inputCh:
+		assert.Equal(t, "r\n", input)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for stdin to close")
+	}
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+	require.EqualError(t, f.c.SendInput("testcmd", []byte("r\n")), `cmd "testcmd" isn't running`)
+}
+
+func TestSendInputWithoutStdin(t *testing.T) {
+	f := newFixture(t)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec:       v1alpha1.CmdSpec{Args: []string{"myserver"}},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	err = f.c.SendInput("testcmd", []byte("r\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "doesn't read input")
+	_, err = f.fe.stdin("myserver")
+	assert.Error(t, err)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, restartBackoff(0))
+	assert.Equal(t, 8*time.Second, restartBackoff(3))
+	assert.Equal(t, time.Minute, restartBackoff(6))
+	assert.Equal(t, time.Minute, restartBackoff(1000))
+}
+
+func TestLifecycleServeReadyAndExit(t *testing.T) {
+	f := newFixture(t)
+	lo := newFakeLifecycleObserver()
+	f.c.SetLifecycleObserver(lo)
+
+	c := model.ToHostCmdInDir("sleep 60", "testdir")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil)
+	localTarget.ServeReadyRegex = "listening"
+
+	f.resourceFromTarget("foo", localTarget, time.Unix(1, 0))
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+	startedAt := f.clock.Now()
+
+	f.clock.Advance(2 * time.Second)
+	require.NoError(t, f.fe.writeStdout("sleep 60", "listening\n"))
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Ready && cmd.Status.Running.ReadyAt.Time.Equal(f.clock.Now())
+	})
+
+	f.clock.Advance(time.Minute)
+	require.NoError(t, f.fe.stop("sleep 60", 1))
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+
+	key := LifecycleKey{Resource: "foo", Kind: CmdKindServe}
+	assert.Equal(t, []string{
+		fmt.Sprintf("start %v %s", key, startedAt),
+		fmt.Sprintf("ready %v 2s", key),
+		fmt.Sprintf("exit %v failed 1m2s", key),
+	}, lo.eventList())
+}
+
+func TestLifecycleRestarts(t *testing.T) {
+	f := newFixture(t)
+	lo := newFakeLifecycleObserver()
+	f.c.SetLifecycleObserver(lo)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcmd"},
+		Spec: v1alpha1.CmdSpec{
+			Args:          []string{"myserver"},
+			RestartPolicy: v1alpha1.CmdRestartPolicyAlways,
+		},
+	}
+	require.NoError(t, f.Client.Create(f.Context(), cmd))
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	f.clock.Advance(time.Second)
+	require.NoError(t, f.fe.stop("myserver", 3))
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Restarts != nil
+	})
+	f.clock.Advance(time.Second)
+	f.reconcileCmd("testcmd")
+	f.requireCmdMatchesInAPI("testcmd", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && cmd.Status.Restarts.Count == 1
+	})
+
+	// Without a readiness check, the command is ready as soon as it starts.
+	key := LifecycleKey{Resource: "testcmd", Kind: CmdKindOther}
+	start := f.clock.Now().Add(-2 * time.Second)
+	assert.Equal(t, []string{
+		fmt.Sprintf("start %v %s", key, start),
+		fmt.Sprintf("ready %v 0s", key),
+		fmt.Sprintf("exit %v failed 1s", key),
+		fmt.Sprintf("restart %v 1", key),
+		fmt.Sprintf("start %v %s", key, f.clock.Now()),
+		fmt.Sprintf("ready %v 0s", key),
+	}, lo.eventList())
+}
+
+func TestExitReason(t *testing.T) {
+	assert.Equal(t, ExitReasonSucceeded, statusAndMetadata{status: Done}.exitReason(true))
+	assert.Equal(t, ExitReasonFailed, statusAndMetadata{status: Error, exitCode: 2}.exitReason(true))
+	assert.Equal(t, ExitReasonStartFailed, statusAndMetadata{status: Error, exitCode: 1}.exitReason(false))
+	assert.Equal(t, ExitReasonKilled, statusAndMetadata{status: Done, exitCode: 137, reason: reasonKilled}.exitReason(true))
+	assert.Equal(t, ExitReasonTimedOut, statusAndMetadata{status: Error, exitCode: 124, reason: v1alpha1.CmdReasonTimedOut}.exitReason(true))
+}
+
 func TestUniqueSpanIDs(t *testing.T) {
 	f := newFixture(t)
 
@@ -742,9 +1247,8 @@ func TestCmdUsesInputsFromButtonOnStart(t *testing.T) {
 	f.triggerButton("b-1", f.clock.Now())
 	f.reconcileCmd("testcmd")
 
-	actualEnv := f.fe.processes["myserver"].env
 	expectedEnv := []string{"foo=bar", "baz=wait what comes next"}
-	require.Equal(t, expectedEnv, actualEnv)
+	f.fe.RequireEnv(t, "myserver", expectedEnv)
 }
 
 func TestBoolInput(t *testing.T) {
@@ -773,9 +1277,8 @@ func TestBoolInput(t *testing.T) {
 			f.triggerButton("b-1", f.clock.Now())
 			f.reconcileCmd("testcmd")
 
-			actualEnv := f.fe.processes["myserver"].env
 			expectedEnv := []string{fmt.Sprintf("dry_run=%s", tc.expectedValue)}
-			require.Equal(t, expectedEnv, actualEnv)
+			f.fe.RequireEnv(t, "myserver", expectedEnv)
 		})
 	}
 }
@@ -795,9 +1298,8 @@ func TestHiddenInput(t *testing.T) {
 	f.triggerButton("b-1", f.clock.Now())
 	f.reconcileCmd("testcmd")
 
-	actualEnv := f.fe.processes["myserver"].env
 	expectedEnv := []string{fmt.Sprintf("foo=%s", val)}
-	require.Equal(t, expectedEnv, actualEnv)
+	f.fe.RequireEnv(t, "myserver", expectedEnv)
 }
 
 func TestChoiceInput(t *testing.T) {
@@ -825,9 +1327,8 @@ func TestChoiceInput(t *testing.T) {
 			f.triggerButton("b-1", f.clock.Now())
 			f.reconcileCmd("testcmd")
 
-			actualEnv := f.fe.processes["myserver"].env
 			expectedEnv := []string{fmt.Sprintf("dry_run=%s", tc.expectedValue)}
-			require.Equal(t, expectedEnv, actualEnv)
+			f.fe.RequireEnv(t, "myserver", expectedEnv)
 		})
 	}
 }
@@ -864,10 +1365,9 @@ func TestCmdOnlyUsesButtonThatStartedIt(t *testing.T) {
 	f.triggerButton("b-2", f.clock.Now())
 	f.reconcileCmd("testcmd")
 
-	actualEnv := f.fe.processes["myserver"].env
 	// b-1's env gets ignored since it was triggered by b-2
 	expectedEnv := []string{}
-	require.Equal(t, expectedEnv, actualEnv)
+	f.fe.RequireEnv(t, "myserver", expectedEnv)
 }
 
 type testStore struct {
@@ -924,9 +1424,49 @@ func (s *testStore) Dispatch(action store.Action) {
 	case local.CmdDeleteAction:
 		local.HandleCmdDeleteAction(st, action)
 		action.Summarize(&s.summary)
+
+	case local.RestartServeAction:
+		local.HandleRestartServeAction(st, action)
 	}
 }
 
+type fakeLifecycleObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func newFakeLifecycleObserver() *fakeLifecycleObserver {
+	return &fakeLifecycleObserver{}
+}
+
+func (o *fakeLifecycleObserver) record(format string, args ...interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *fakeLifecycleObserver) eventList() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string{}, o.events...)
+}
+
+func (o *fakeLifecycleObserver) OnStart(key LifecycleKey, startedAt time.Time) {
+	o.record("start %v %s", key, startedAt)
+}
+
+func (o *fakeLifecycleObserver) OnReady(key LifecycleKey, timeToReady time.Duration) {
+	o.record("ready %v %s", key, timeToReady)
+}
+
+func (o *fakeLifecycleObserver) OnRestart(key LifecycleKey, count int32) {
+	o.record("restart %v %d", key, count)
+}
+
+func (o *fakeLifecycleObserver) OnExit(key LifecycleKey, reason ExitReason, runtime time.Duration) {
+	o.record("exit %v %s %s", key, reason, runtime)
+}
+
 type fixture struct {
 	*fake.ControllerFixture
 	st    *testStore
@@ -945,6 +1485,7 @@ func newFixture(t *testing.T) *fixture {
 	fpm := NewFakeProberManager()
 	sc := local.NewServerController(f.Client)
 	clock := clockwork.NewFakeClock()
+	fe.SetClock(clock)
 	c := NewController(f.Context(), fe, fpm, f.Client, st, clock, v1alpha1.NewScheme())
 	indexer.StartSourceForTesting(f.Context(), c.requeuer, c, nil)
 
@@ -4,15 +4,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/procstats"
+	"github.com/tilt-dev/tilt/internal/pty"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/procutil"
@@ -20,33 +27,104 @@ import (
 
 var DefaultGracePeriod = 30 * time.Second
 
+// How often to measure the resources that a running process uses.
+var DefaultUsageInterval = 5 * time.Second
+
 type Execer interface {
 	// Returns a channel to pull status updates from. After the process exists
 	// (and transmits its final status), the channel is closed.
-	Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata
+	//
+	// The process's stdout and stderr go to separate writers, unless it runs
+	// in a pseudo-terminal, which merges them into stdout.
+	//
+	// If stdin isn't nil, the process reads its input from it until it
+	// returns EOF. The caller should close it when the process exits.
+	Start(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer) chan statusAndMetadata
 }
 
 type fakeExecProcess struct {
 	closeCh   chan bool
 	exitCh    chan int
-	workdir   string
-	env       []string
 	startTime time.Time
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+// A script for a fake process to follow, from when it starts until it exits.
+//
+// The zero value starts right away, writes nothing, and runs until it's
+// stopped or canceled.
+type FakeScript struct {
+	// If set, the process fails to start, with this error.
+	StartErr error
+
+	// How long the process takes to start running.
+	StartDelay time.Duration
+
+	// Output for the process to write once it's running, in order.
+	Output []FakeOutput
+
+	// If set, the process exits with ExitCode after it writes its output.
+	Exit     bool
+	ExitCode int
+}
+
+// A chunk of output from a fake process.
+type FakeOutput struct {
+	// How long to wait, after the process starts running or writes its
+	// previous chunk, before writing this one.
+	Delay time.Duration
+
+	Stdout string
+	Stderr string
 }
 
 type FakeExecer struct {
 	// really dumb/simple process management - key by the command string, and make duplicates an error
 	processes map[string]*fakeExecProcess
+	scripts   map[string][]FakeScript
+	starts    map[string][]model.Cmd
+	clock     clockwork.Clock
 	mu        sync.Mutex
 }
 
 func NewFakeExecer() *FakeExecer {
 	return &FakeExecer{
 		processes: make(map[string]*fakeExecProcess),
+		scripts:   make(map[string][]FakeScript),
+		starts:    make(map[string][]model.Cmd),
+		clock:     clockwork.NewRealClock(),
+	}
+}
+
+// Sets the clock that times the delays in scripts, so that a test can
+// advance it instead of sleeping.
+func (e *FakeExecer) SetClock(clock clockwork.Clock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clock = clock
+}
+
+// Queues scripts for the next starts of the given command, one per start.
+// After the scripts run out, the command runs until it's stopped.
+func (e *FakeExecer) Script(cmd string, scripts ...FakeScript) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.scripts[cmd] = append(e.scripts[cmd], scripts...)
+}
+
+// Queues a script for each exit code, so that the next starts of the
+// given command exit right away with those codes, in order.
+func (e *FakeExecer) ExitCodes(cmd string, exitCodes ...int) {
+	scripts := make([]FakeScript, 0, len(exitCodes))
+	for _, exitCode := range exitCodes {
+		scripts = append(scripts, FakeScript{Exit: true, ExitCode: exitCode})
 	}
+	e.Script(cmd, scripts...)
 }
 
-func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata {
+func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer) chan statusAndMetadata {
 	e.mu.Lock()
 	oldProcess, ok := e.processes[cmd.String()]
 	e.mu.Unlock()
@@ -59,6 +137,27 @@ func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan
 		}
 	}
 
+	statusCh := make(chan statusAndMetadata)
+
+	e.mu.Lock()
+	e.starts[cmd.String()] = append(e.starts[cmd.String()], cmd)
+	var script FakeScript
+	if scripts := e.scripts[cmd.String()]; len(scripts) > 0 {
+		script = scripts[0]
+		e.scripts[cmd.String()] = scripts[1:]
+	}
+	clock := e.clock
+	e.mu.Unlock()
+
+	if script.StartErr != nil {
+		go func() {
+			defer close(statusCh)
+			_, _ = fmt.Fprintf(stdout, "cmd %v failed to start: %v\n", cmd, script.StartErr)
+			statusCh <- statusAndMetadata{status: Error, exitCode: 1, reason: script.StartErr.Error()}
+		}()
+		return statusCh
+	}
+
 	exitCh := make(chan int)
 	closeCh := make(chan bool)
 
@@ -66,15 +165,15 @@ func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan
 	e.processes[cmd.String()] = &fakeExecProcess{
 		closeCh:   closeCh,
 		exitCh:    exitCh,
-		workdir:   cmd.Dir,
 		startTime: time.Now(),
-		env:       cmd.Env,
+		stdin:     stdin,
+		stdout:    stdout,
+		stderr:    stderr,
 	}
 	e.mu.Unlock()
 
-	statusCh := make(chan statusAndMetadata)
 	go func() {
-		fakeRun(ctx, cmd, w, statusCh, exitCh)
+		fakeRun(ctx, clock, cmd, script, stdout, stderr, statusCh, exitCh)
 
 		e.mu.Lock()
 		close(closeCh)
@@ -94,30 +193,137 @@ func (e *FakeExecer) stop(cmd string, exitCode int) error {
 		return fmt.Errorf("no such process %q", cmd)
 	}
 
-	p.exitCh <- exitCode
+	select {
+	case p.exitCh <- exitCode:
+	case <-p.closeCh:
+		return fmt.Errorf("process %q already exited", cmd)
+	}
 	e.mu.Lock()
 	delete(e.processes, cmd)
 	e.mu.Unlock()
 	return nil
 }
 
-func fakeRun(ctx context.Context, cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata, exitCh chan int) {
+// writes to the stdout of the process with the given command
+func (e *FakeExecer) writeStdout(cmd string, s string) error {
+	e.mu.Lock()
+	p, ok := e.processes[cmd]
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no such process %q", cmd)
+	}
+
+	_, err := p.stdout.Write([]byte(s))
+	return err
+}
+
+// writes to the stderr of the process with the given command
+func (e *FakeExecer) writeStderr(cmd string, s string) error {
+	e.mu.Lock()
+	p, ok := e.processes[cmd]
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no such process %q", cmd)
+	}
+
+	_, err := p.stderr.Write([]byte(s))
+	return err
+}
+
+// returns the stdin of the process with the given command
+func (e *FakeExecer) stdin(cmd string) (io.Reader, error) {
+	e.mu.Lock()
+	p, ok := e.processes[cmd]
+	e.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no such process %q", cmd)
+	}
+	if p.stdin == nil {
+		return nil, fmt.Errorf("process %q has no stdin", cmd)
+	}
+	return p.stdin, nil
+}
+
+func fakeRun(ctx context.Context, clock clockwork.Clock, cmd model.Cmd, script FakeScript, stdout, stderr io.Writer, statusCh chan statusAndMetadata, exitCh chan int) {
 	defer close(statusCh)
 
-	_, _ = fmt.Fprintf(w, "Starting cmd %v\n", cmd)
+	_, _ = fmt.Fprintf(stdout, "Starting cmd %v\n", cmd)
+
+	if script.StartDelay > 0 && !fakeWait(ctx, clock.After(script.StartDelay), cmd, stdout, statusCh, exitCh) {
+		return
+	}
 
 	statusCh <- statusAndMetadata{status: Running}
 
+	for _, out := range script.Output {
+		if out.Delay > 0 && !fakeWait(ctx, clock.After(out.Delay), cmd, stdout, statusCh, exitCh) {
+			return
+		}
+		_, _ = io.WriteString(stdout, out.Stdout)
+		_, _ = io.WriteString(stderr, out.Stderr)
+	}
+
+	if script.Exit {
+		fakeExit(cmd, stdout, statusCh, script.ExitCode)
+		return
+	}
+
+	fakeWait(ctx, nil, cmd, stdout, statusCh, exitCh)
+}
+
+// Waits for the timer and returns true, unless the process is stopped or
+// canceled first, in which case it reports why the process finished and
+// returns false. A nil timer waits forever.
+func fakeWait(ctx context.Context, timer <-chan time.Time, cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata, exitCh chan int) bool {
 	select {
+	case <-timer:
+		return true
 	case <-ctx.Done():
 		_, _ = fmt.Fprintf(w, "cmd %v canceled\n", cmd)
 		// this was cleaned up by the controller, so it's not an error
 		statusCh <- statusAndMetadata{status: Done, exitCode: 0}
 	case exitCode := <-exitCh:
-		_, _ = fmt.Fprintf(w, "cmd %v exited with code %d\n", cmd, exitCode)
-		// even an exit code of 0 is an error, because services aren't supposed to exit!
-		statusCh <- statusAndMetadata{status: Error, exitCode: exitCode}
+		fakeExit(cmd, w, statusCh, exitCode)
 	}
+	return false
+}
+
+func fakeExit(cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata, exitCode int) {
+	_, _ = fmt.Fprintf(w, "cmd %v exited with code %d\n", cmd, exitCode)
+	// even an exit code of 0 is an error, because services aren't supposed to exit!
+	statusCh <- statusAndMetadata{status: Error, exitCode: exitCode}
+}
+
+// Returns how many times the given command has started, including starts
+// that failed.
+func (fe *FakeExecer) StartCount(cmd string) int {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return len(fe.starts[cmd])
+}
+
+// Requires that the last start of the given command ran in dir.
+func (fe *FakeExecer) RequireWorkdir(t *testing.T, cmd string, dir string) {
+	t.Helper()
+	last := fe.requireLastStart(t, cmd)
+	require.Equal(t, dir, last.Dir, "workdir of %q", cmd)
+}
+
+// Requires that the last start of the given command had exactly the given env.
+func (fe *FakeExecer) RequireEnv(t *testing.T, cmd string, env []string) {
+	t.Helper()
+	last := fe.requireLastStart(t, cmd)
+	require.Equal(t, env, last.Env, "env of %q", cmd)
+}
+
+func (fe *FakeExecer) requireLastStart(t *testing.T, cmd string) model.Cmd {
+	t.Helper()
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+
+	starts := fe.starts[cmd]
+	require.NotEmpty(t, starts, "%T never started cmd %q", FakeExecer{}, cmd)
+	return starts[len(starts)-1]
 }
 
 func (fe *FakeExecer) RequireNoKnownProcess(t *testing.T, cmd string) {
@@ -130,33 +336,78 @@ func (fe *FakeExecer) RequireNoKnownProcess(t *testing.T, cmd string) {
 	require.False(t, ok, "%T should not be tracking any process with cmd %q, but it is", FakeExecer{}, cmd)
 }
 
-func ProvideExecer(localEnv *localexec.Env) Execer {
-	return NewProcessExecer(localEnv)
+// Runs each command where its target says, and on this machine when it
+// doesn't have one.
+func ProvideExecer(localEnv *localexec.Env, dCli docker.Client) Execer {
+	return newTargetExecer(NewProcessExecer(localEnv), NewContainerExecer(dCli))
+}
+
+type targetExecer struct {
+	local     Execer
+	container Execer
+
+	mu  sync.Mutex
+	ssh map[SSHConfig]*sshExecer
+}
+
+var _ Execer = &targetExecer{}
+
+func newTargetExecer(local Execer, container Execer) *targetExecer {
+	return &targetExecer{
+		local:     local,
+		container: container,
+		ssh:       make(map[SSHConfig]*sshExecer),
+	}
+}
+
+func (e *targetExecer) Start(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer) chan statusAndMetadata {
+	switch {
+	case cmd.Target != nil && cmd.Target.SSH != nil:
+		return e.sshExecer(sshConfigForTarget(*cmd.Target.SSH)).Start(ctx, cmd, stdin, stdout, stderr)
+	case cmd.Target != nil && cmd.Target.Container != nil:
+		return e.container.Start(ctx, cmd, stdin, stdout, stderr)
+	default:
+		return e.local.Start(ctx, cmd, stdin, stdout, stderr)
+	}
+}
+
+// Commands on the same host share a connection.
+func (e *targetExecer) sshExecer(config SSHConfig) *sshExecer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	execer, ok := e.ssh[config]
+	if !ok {
+		execer = NewSSHExecer(config)
+		e.ssh[config] = execer
+	}
+	return execer
 }
 
 type processExecer struct {
-	gracePeriod time.Duration
-	localEnv    *localexec.Env
+	gracePeriod   time.Duration
+	usageInterval time.Duration
+	localEnv      *localexec.Env
 }
 
 func NewProcessExecer(localEnv *localexec.Env) *processExecer {
 	return &processExecer{
-		gracePeriod: DefaultGracePeriod,
-		localEnv:    localEnv,
+		gracePeriod:   DefaultGracePeriod,
+		usageInterval: DefaultUsageInterval,
+		localEnv:      localEnv,
 	}
 }
 
-func (e *processExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata {
+func (e *processExecer) Start(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer) chan statusAndMetadata {
 	statusCh := make(chan statusAndMetadata)
 
 	go func() {
-		e.processRun(ctx, cmd, w, statusCh)
+		e.processRun(ctx, cmd, stdin, stdout, stderr, statusCh)
 	}()
 
 	return statusCh
 }
 
-func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata) {
+func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer, statusCh chan statusAndMetadata) {
 	defer close(statusCh)
 
 	logger.Get(ctx).Infof("Running cmd: %s", cmd.String())
@@ -172,11 +423,35 @@ func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writ
 	}
 
 	c.SysProcAttr = &syscall.SysProcAttr{}
-	procutil.SetOptNewProcessGroup(c.SysProcAttr)
-	c.Stderr = w
-	c.Stdout = w
 
-	err = c.Start()
+	usePTY := cmd.PTY
+	if usePTY && !pty.Supported {
+		logger.Get(ctx).Warnf("%s: running without a pseudo-terminal, because they're not supported on this OS", cmd.String())
+		usePTY = false
+	}
+
+	// When there's a pseudo-terminal, the output arrives on outputDone's
+	// goroutine, and we wait for it before reporting that the process exited.
+	var outputDone chan struct{}
+	if usePTY {
+		var ptmx *os.File
+		c.Env = withTerm(c.Env)
+		ptmx, err = pty.Start(c, pty.TerminalSize())
+		if err == nil {
+			stopResize := pty.NotifyResize(ptmx)
+			defer stopResize()
+			outputDone = copyPTYOutput(ptmx, stdout)
+			if stdin != nil {
+				go func() { _, _ = io.Copy(ptmx, stdin) }()
+			}
+		}
+	} else {
+		procutil.SetOptNewProcessGroup(c.SysProcAttr)
+		c.Stdin = stdin
+		c.Stdout = stdout
+		c.Stderr = stderr
+		err = c.Start()
+	}
 	if err != nil {
 		logger.Get(ctx).Errorf("%s failed to start: %v", cmd.String(), err)
 		statusCh <- statusAndMetadata{
@@ -214,66 +489,176 @@ func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writ
 		close(processExitCh)
 	}()
 
-	select {
-	case err := <-processExitCh:
-		exitCode := 0
-		reason := ""
-		status := Done
-		if err == nil {
-			// Use defaults
-		} else if ee, ok := err.(*exec.ExitError); ok {
-			status = Error
-			exitCode = ee.ExitCode()
-			reason = err.Error()
-			logger.Get(ctx).Errorf("%s exited with exit code %d", cmd.String(), ee.ExitCode())
-		} else {
-			status = Error
-			exitCode = 1
-			reason = err.Error()
-			logger.Get(ctx).Errorf("error execing %s: %v", cmd.String(), err)
+	var timeoutCh <-chan time.Time
+	if cmd.Timeout > 0 {
+		timer := time.NewTimer(cmd.Timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	// Report the process's resource usage while it runs. The first sample
+	// is the baseline for measuring CPU.
+	var usageCh <-chan time.Time
+	if e.usageInterval > 0 {
+		ticker := time.NewTicker(e.usageInterval)
+		defer ticker.Stop()
+		usageCh = ticker.C
+	}
+	sampler := procstats.NewSampler(pid)
+
+	for {
+		select {
+		case now := <-usageCh:
+			millicores, rss, ok, err := sampler.Sample(now)
+			if err == procstats.ErrUnsupported {
+				logger.Get(ctx).Debugf("Not measuring resource usage of %d: %v", pid, err)
+				usageCh = nil
+				continue
+			} else if err != nil {
+				// Most likely, the process just exited.
+				logger.Get(ctx).Debugf("Measuring resource usage of %d: %v", pid, err)
+				continue
+			} else if !ok {
+				continue
+			}
+			sm := statusAndMetadata{status: Running, pid: pid, usage: &v1alpha1.CmdResourceUsage{
+				CPUMillicores: millicores,
+				MemoryBytes:   int64(rss),
+			}}
+			// Don't let a slow reader hold up a stop.
+			select {
+			case statusCh <- sm:
+			case <-ctx.Done():
+			}
+		case err := <-processExitCh:
+			exitCode := 0
+			reason := ""
+			status := Done
+			if err == nil {
+				// Use defaults
+			} else if ee, ok := err.(*exec.ExitError); ok {
+				status = Error
+				exitCode = ee.ExitCode()
+				reason = err.Error()
+				logger.Get(ctx).Errorf("%s exited with exit code %d", cmd.String(), ee.ExitCode())
+			} else {
+				status = Error
+				exitCode = 1
+				reason = err.Error()
+				logger.Get(ctx).Errorf("error execing %s: %v", cmd.String(), err)
+			}
+			waitForOutput(outputDone)
+			statusCh <- statusAndMetadata{status: status, pid: pid, exitCode: exitCode, reason: reason}
+			return
+		case <-timeoutCh:
+			logger.Get(ctx).Errorf("%s timed out after %s", cmd.String(), cmd.Timeout)
+			e.killProcess(ctx, c, e.gracePeriodFor(cmd), cmd.TerminationSignal, processExitCh)
+			waitForOutput(outputDone)
+			// The same exit code as timeout(1).
+			statusCh <- statusAndMetadata{status: Error, pid: pid, reason: v1alpha1.CmdReasonTimedOut, exitCode: 124}
+			return
+		case <-ctx.Done():
+			e.killProcess(ctx, c, e.gracePeriodFor(cmd), cmd.TerminationSignal, processExitCh)
+			waitForOutput(outputDone)
+			statusCh <- statusAndMetadata{status: Done, pid: pid, reason: reasonKilled, exitCode: 137}
+			return
 		}
-		statusCh <- statusAndMetadata{status: status, pid: pid, exitCode: exitCode, reason: reason}
-	case <-ctx.Done():
-		e.killProcess(ctx, c, processExitCh)
-		statusCh <- statusAndMetadata{status: Done, pid: pid, reason: "killed", exitCode: 137}
 	}
 }
 
-func (e *processExecer) killProcess(ctx context.Context, c *exec.Cmd, processExitCh chan error) {
+func (e *processExecer) gracePeriodFor(cmd model.Cmd) time.Duration {
+	if cmd.GracePeriod != nil {
+		return *cmd.GracePeriod
+	}
+	return e.gracePeriod
+}
+
+func (e *processExecer) killProcess(ctx context.Context, c *exec.Cmd, gracePeriod time.Duration, terminationSignal string, processExitCh chan error) {
+	if gracePeriod <= 0 {
+		logger.Get(ctx).Debugf("No grace period, sending SIGKILL to the process group of %d", c.Process.Pid)
+		procutil.KillProcessGroup(c)
+		return
+	}
+
 	logger.Get(ctx).Debugf("About to gracefully shut down process %d", c.Process.Pid)
-	err := procutil.GracefullyShutdownProcess(c.Process)
+	err := procutil.GracefullyShutdownProcess(c.Process, terminationSignal)
 	if err != nil {
 		logger.Get(ctx).Debugf("Unable to gracefully kill process %d, sending SIGKILL to the process group: %v", c.Process.Pid, err)
 		procutil.KillProcessGroup(c)
 		return
 	}
 
-	// we wait 30 seconds to give the process enough time to finish doing any cleanup.
-	// this is the same timeout that Kubernetes uses
-	// TODO(dmiller): make this configurable via the Tiltfile
-	infoCh := time.After(e.gracePeriod / 20)
-	moreInfoCh := time.After(e.gracePeriod / 3)
-	finalCh := time.After(e.gracePeriod)
+	if !waitForExit(ctx, gracePeriod, c.Process.Pid, processExitCh) {
+		logger.Get(ctx).Infof("Time is up! Sending %d a kill signal", c.Process.Pid)
+		procutil.KillProcessGroup(c)
+	}
+}
+
+// Waits for a process that was asked to stop to exit. Returns false if the
+// grace period ran out first.
+func waitForExit(ctx context.Context, gracePeriod time.Duration, pid int, processExitCh chan error) bool {
+	// By default, we wait 30 seconds to give the process enough time to finish
+	// doing any cleanup. This is the same timeout that Kubernetes uses.
+	infoCh := time.After(gracePeriod / 20)
+	moreInfoCh := time.After(gracePeriod / 3)
+	finalCh := time.After(gracePeriod)
 
 	select {
 	case <-infoCh:
-		logger.Get(ctx).Infof("Waiting %s for process to exit... (pid: %d)", e.gracePeriod, c.Process.Pid)
+		logger.Get(ctx).Infof("Waiting %s for process to exit... (pid: %d)", gracePeriod, pid)
 	case <-processExitCh:
-		return
+		return true
 	}
 
 	select {
 	case <-moreInfoCh:
-		logger.Get(ctx).Infof("Still waiting on exit... (pid: %d)", c.Process.Pid)
+		logger.Get(ctx).Infof("Still waiting on exit... (pid: %d)", pid)
 	case <-processExitCh:
-		return
+		return true
 	}
 
 	select {
 	case <-finalCh:
-		logger.Get(ctx).Infof("Time is up! Sending %d a kill signal", c.Process.Pid)
-		procutil.KillProcessGroup(c)
+		return false
 	case <-processExitCh:
+		return true
+	}
+}
+
+// Copies the command's output from the pseudo-terminal until the command and
+// all its descendants have closed it.
+func copyPTYOutput(ptmx *os.File, w io.Writer) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() { _ = ptmx.Close() }()
+
+		// On Linux, reading returns EIO instead of EOF once the terminal
+		// is closed, so any error ends the output.
+		_, _ = io.Copy(w, ptmx)
+	}()
+	return done
+}
+
+// Waits for the rest of the output, but not forever, because a process that
+// escaped the process group may hold the terminal open.
+func waitForOutput(outputDone chan struct{}) {
+	if outputDone == nil {
 		return
 	}
+	select {
+	case <-outputDone:
+	case <-time.After(time.Second):
+	}
+}
+
+// Tools decide whether to print colors from TERM, so set it if Tilt wasn't
+// started from a terminal.
+func withTerm(env []string) []string {
+	for _, e := range env {
+		if strings.HasPrefix(e, "TERM=") {
+			return env
+		}
+	}
+	return append(env, "TERM=xterm-256color")
 }
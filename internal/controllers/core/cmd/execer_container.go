@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/pty"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Runs commands inside a running container with `docker exec`, so that they
+// see the same files, tools, and network as the service.
+//
+// The container needs sh. The command runs in the container's working
+// directory, in its own process group, and it's stopped by exec-ing kill(1)
+// in the container, because Docker can't signal an exec.
+type containerExecer struct {
+	dCli        docker.Client
+	gracePeriod time.Duration
+}
+
+var _ Execer = &containerExecer{}
+
+// Runs each command in the container named by its target, which must be a
+// container on Tilt's Docker daemon.
+func NewContainerExecer(dCli docker.Client) *containerExecer {
+	return &containerExecer{
+		dCli:        dCli,
+		gracePeriod: DefaultGracePeriod,
+	}
+}
+
+func (e *containerExecer) Start(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer) chan statusAndMetadata {
+	statusCh := make(chan statusAndMetadata)
+
+	go func() {
+		e.containerRun(ctx, cmd, stdin, stdout, stderr, statusCh)
+	}()
+
+	return statusCh
+}
+
+func (e *containerExecer) containerRun(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer, statusCh chan statusAndMetadata) {
+	defer close(statusCh)
+
+	if len(cmd.Argv) == 0 {
+		logger.Get(ctx).Errorf("%q invalid cmd: empty cmd", cmd.String())
+		statusCh <- statusAndMetadata{
+			status:   Error,
+			exitCode: 1,
+			reason:   "invalid cmd: empty cmd",
+		}
+		return
+	}
+
+	// Env files are read from this machine, not the container, and
+	// variables expand to Tilt's environment.
+	resolved, err := localexec.ResolveEnv(cmd, os.Environ())
+	if err != nil {
+		logger.Get(ctx).Errorf("%q invalid cmd: %v", cmd.String(), err)
+		statusCh <- statusAndMetadata{
+			status:   Error,
+			exitCode: 1,
+			reason:   fmt.Sprintf("invalid cmd: %v", err),
+		}
+		return
+	}
+	cmd = resolved
+
+	if cmd.Target == nil || cmd.Target.Container == nil || cmd.Target.Container.Name == "" {
+		logger.Get(ctx).Errorf("%q invalid cmd: no container", cmd.String())
+		statusCh <- statusAndMetadata{
+			status:   Error,
+			exitCode: 1,
+			reason:   "invalid cmd: no container",
+		}
+		return
+	}
+	cID := container.ID(cmd.Target.Container.Name)
+
+	// The dir is a path on this machine.
+	cmd.Dir = ""
+
+	logger.Get(ctx).Infof("Running cmd in container %s: %s", cID, cmd.String())
+
+	// The exec outlives ctx, so that the command can clean up after it's
+	// asked to stop, but not this function.
+	execCtx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.Get(ctx)))
+	defer cancel()
+
+	pidCh := make(chan int, 1)
+	processExitCh := make(chan error, 1)
+	go func() {
+		opts := docker.ExecOptions{
+			Stdin:  stdin,
+			Stdout: &pidWriter{w: stdout, pidCh: pidCh},
+			Stderr: stderr,
+		}
+		if cmd.PTY {
+			size := pty.TerminalSize()
+			opts.TTY = true
+			opts.Rows = uint(size.Rows)
+			opts.Cols = uint(size.Cols)
+		}
+		processExitCh <- e.dCli.Exec(execCtx, cID, model.Cmd{Argv: []string{"sh", "-c", remoteScript(cmd)}}, opts)
+		close(processExitCh)
+	}()
+
+	followRemoteProcess(ctx, cmd, e.gracePeriodFor(cmd), pidCh, processExitCh, func(pid int, signal string) {
+		e.signalProcessGroup(execCtx, cID, pid, signal)
+	}, statusCh)
+}
+
+func (e *containerExecer) gracePeriodFor(cmd model.Cmd) time.Duration {
+	if cmd.GracePeriod != nil {
+		return *cmd.GracePeriod
+	}
+	return e.gracePeriod
+}
+
+func (e *containerExecer) signalProcessGroup(ctx context.Context, cID container.ID, pid int, signal string) {
+	killCmd := model.Cmd{Argv: []string{"sh", "-c", remoteKillScript(pid, signal)}}
+	err := e.dCli.Exec(ctx, cID, killCmd, docker.ExecOptions{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		logger.Get(ctx).Debugf("Unable to send %s to the process group of %d: %v", signal, pid, err)
+	}
+}
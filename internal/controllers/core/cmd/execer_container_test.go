@@ -0,0 +1,190 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestContainerTrue(t *testing.T) {
+	f := newContainerExecFixture(t)
+
+	f.start(f.cmd("echo hello"))
+
+	f.waitForStatus(Running)
+	f.waitForStatus(Done)
+	f.assertLogContains("hello")
+	assert.Equal(t, []string{"my-container"}, f.dCli.containers())
+}
+
+func TestContainerWorkdirAndEnv(t *testing.T) {
+	f := newContainerExecFixture(t)
+	d := tempdir.NewTempDirFixture(t)
+
+	c := f.cmd(`pwd; echo "FOO is $FOO"`)
+	c.Dir = d.Path()
+	c.Env = []string{"FOO=bar"}
+	f.start(c)
+
+	f.waitForStatus(Done)
+	// The dir is on this machine, so it's not used in the container.
+	assert.NotContains(t, f.testWriter.String(), d.Path())
+	f.assertLogContains("FOO is bar")
+}
+
+func TestContainerStderr(t *testing.T) {
+	f := newContainerExecFixture(t)
+	stdout := bufsync.NewThreadSafeBuffer()
+	stderr := bufsync.NewThreadSafeBuffer()
+
+	c := f.cmd("echo out; echo err >&2")
+	f.statusCh = f.execer.Start(f.ctx, c, nil, stdout, stderr)
+
+	f.waitForStatus(Done)
+	assert.Equal(t, "out\n", stdout.String())
+	assert.Equal(t, "err\n", stderr.String())
+}
+
+func TestContainerStdin(t *testing.T) {
+	f := newContainerExecFixture(t)
+
+	c := f.cmd("read line; echo \"got $line\"")
+	f.statusCh = f.execer.Start(f.ctx, c, strings.NewReader("hello\n"), f.testWriter, f.testWriter)
+
+	f.waitForStatus(Done)
+	f.assertLogContains("got hello")
+}
+
+func TestContainerNoTarget(t *testing.T) {
+	f := newContainerExecFixture(t)
+
+	f.statusCh = f.execer.Start(f.ctx, model.ToUnixCmd("echo hello"), nil, f.testWriter, f.testWriter)
+
+	f.waitForError()
+	f.assertLogContains("no container")
+	assert.Empty(t, f.dCli.containers())
+}
+
+func TestContainerExitCode(t *testing.T) {
+	f := newContainerExecFixture(t)
+
+	f.start(f.cmd("exit 3"))
+
+	sm := f.waitForStatus(Error)
+	assert.Equal(t, 3, sm.exitCode)
+}
+
+func TestContainerShutdownOnCancel(t *testing.T) {
+	f := newContainerExecFixture(t)
+
+	f.start(f.cmd(`trap 'echo "cleanup time!"; exit 1' TERM; while true; do sleep 0.1; done`))
+
+	f.waitForStatus(Running)
+	f.cancel()
+	sm := f.waitForStatus(Done)
+	assert.Equal(t, 137, sm.exitCode)
+	f.assertLogContains("cleanup time!")
+
+	// The command was stopped by exec-ing kill in the same container.
+	assert.Equal(t, []string{"my-container", "my-container"}, f.dCli.containers())
+}
+
+func TestContainerTimeout(t *testing.T) {
+	f := newContainerExecFixture(t)
+
+	c := f.cmd("sleep 10")
+	c.Timeout = 500 * time.Millisecond
+	f.start(c)
+
+	f.waitForStatus(Running)
+	sm := f.waitForStatus(Error)
+	assert.Equal(t, v1alpha1.CmdReasonTimedOut, sm.reason)
+	assert.Equal(t, 124, sm.exitCode)
+}
+
+type containerExecFixture struct {
+	*processExecFixture
+	execer *containerExecer
+	dCli   *localExecDockerClient
+}
+
+func newContainerExecFixture(t *testing.T) *containerExecFixture {
+	dCli := &localExecDockerClient{FakeClient: docker.NewFakeClient()}
+	execer := NewContainerExecer(dCli)
+	execer.gracePeriod = time.Second
+
+	testWriter := bufsync.NewThreadSafeBuffer()
+	ctx, _, _ := testutils.ForkedCtxAndAnalyticsForTest(testWriter)
+	ctx, cancel := context.WithCancel(ctx)
+
+	f := &containerExecFixture{
+		processExecFixture: &processExecFixture{
+			t:          t,
+			ctx:        ctx,
+			cancel:     cancel,
+			testWriter: testWriter,
+		},
+		execer: execer,
+		dCli:   dCli,
+	}
+	t.Cleanup(f.tearDown)
+	return f
+}
+
+func (f *containerExecFixture) cmd(script string) model.Cmd {
+	c := model.ToUnixCmd(script)
+	c.Target = &v1alpha1.CmdTarget{Container: &v1alpha1.CmdContainerTarget{Name: "my-container"}}
+	return c
+}
+
+func (f *containerExecFixture) start(c model.Cmd) {
+	f.statusCh = f.execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
+}
+
+// A Docker client whose containers are all this machine.
+type localExecDockerClient struct {
+	*docker.FakeClient
+
+	mu    sync.Mutex
+	execs []string
+}
+
+func (c *localExecDockerClient) Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts docker.ExecOptions) error {
+	c.mu.Lock()
+	c.execs = append(c.execs, cID.String())
+	c.mu.Unlock()
+
+	ec := exec.CommandContext(ctx, cmd.Argv[0], cmd.Argv[1:]...)
+	ec.Stdin = opts.Stdin
+	ec.Stdout = opts.Stdout
+	ec.Stderr = opts.Stderr
+	err := ec.Run()
+	if ee, ok := err.(*exec.ExitError); ok {
+		return docker.ExitError{ExitCode: ee.ExitCode()}
+	}
+	return err
+}
+
+func (c *localExecDockerClient) containers() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.execs...)
+}
+
+var _ docker.Client = &localExecDockerClient{}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Execers that run commands somewhere Tilt can't signal a process directly,
+// like on a remote host or in a container, run them with remoteScript, and
+// stop them by running kill(1) next to them.
+
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// The sh script that runs the command.
+//
+// The command runs in its own process group, with setsid(1) if it's
+// available, or as a background job otherwise (which only gets a group in
+// shells that allow job control without a terminal, like bash). It prints
+// its pid, which is also its process group, before it starts. When it
+// exits, the rest of the group is killed.
+func remoteScript(cmd model.Cmd) string {
+	var sb strings.Builder
+	if cmd.Dir != "" {
+		fmt.Fprintf(&sb, "cd %s || exit 1\n", shellescape.Quote(cmd.Dir))
+	}
+	for _, kv := range cmd.Env {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !envNameRe.MatchString(k) {
+			continue
+		}
+		fmt.Fprintf(&sb, "export %s=%s\n", k, shellescape.Quote(v))
+	}
+	args := make([]string, len(cmd.Argv))
+	for i, arg := range cmd.Argv {
+		args[i] = shellescape.Quote(arg)
+	}
+	// Background jobs read from /dev/null unless they're given stdin
+	// explicitly, and some shells ignore an explicit <&0.
+	sb.WriteString("exec 3<&0\n")
+	run := `sh -c 'echo "$$"; exec "$@"' sh ` + strings.Join(args, " ") + " <&3 3<&- &\n"
+	sb.WriteString("if command -v setsid >/dev/null 2>&1; then\n")
+	sb.WriteString("setsid " + run)
+	sb.WriteString("else\n")
+	sb.WriteString("set -m\n")
+	sb.WriteString(run)
+	sb.WriteString("fi\n")
+	sb.WriteString("pid=$!\n")
+	sb.WriteString(`wait "$pid"` + "\n")
+	sb.WriteString("code=$?\n")
+	sb.WriteString(`kill -KILL "-$pid" 2>/dev/null` + "\n")
+	sb.WriteString(`exit "$code"` + "\n")
+	return sb.String()
+}
+
+// The command that sends a signal, like "SIGTERM", to a process group.
+func remoteKillScript(pid int, signal string) string {
+	return fmt.Sprintf("kill -%s -%d", strings.TrimPrefix(signal, "SIG"), pid)
+}
+
+// Passes output through, except for the line where remoteScript reports the
+// pid of the command. Lines before it are passed through too, in case
+// something else prints first.
+type pidWriter struct {
+	w     io.Writer
+	pidCh chan int
+	buf   []byte
+	done  bool
+
+	// Drop the lines before the pid instead.
+	skipHeader bool
+}
+
+func (w *pidWriter) Write(p []byte) (int, error) {
+	if w.done {
+		return w.w.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	for !w.done {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			return len(p), nil
+		}
+
+		line := w.buf[:i+1]
+		w.buf = w.buf[i+1:]
+		pid, err := strconv.Atoi(strings.TrimSpace(string(line)))
+		if err == nil {
+			w.done = true
+			w.pidCh <- pid
+			continue
+		}
+
+		if w.skipHeader {
+			continue
+		}
+		_, err = w.w.Write(line)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rest := w.buf
+	w.buf = nil
+	if len(rest) > 0 {
+		_, err := w.w.Write(rest)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Reports the status of a command that runs with remoteScript, until it
+// exits, and stops it with signal when it times out or ctx is done.
+func followRemoteProcess(ctx context.Context, cmd model.Cmd, gracePeriod time.Duration,
+	pidCh chan int, processExitCh chan error, signal func(pid int, signal string), statusCh chan statusAndMetadata) {
+	// The script reports the process group before it runs the command, so
+	// the command is running once we know it.
+	pid := 0
+	select {
+	case pid = <-pidCh:
+		statusCh <- statusAndMetadata{status: Running, pid: pid}
+	case err := <-processExitCh:
+		statusCh <- exitStatus(ctx, cmd, 0, err)
+		return
+	case <-ctx.Done():
+		// We can't stop the command without its pid, so hope that closing
+		// its output stops it.
+		statusCh <- statusAndMetadata{status: Done, reason: reasonKilled, exitCode: 137}
+		return
+	}
+
+	var timeoutCh <-chan time.Time
+	if cmd.Timeout > 0 {
+		timer := time.NewTimer(cmd.Timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	select {
+	case err := <-processExitCh:
+		statusCh <- exitStatus(ctx, cmd, pid, err)
+	case <-timeoutCh:
+		logger.Get(ctx).Errorf("%s timed out after %s", cmd.String(), cmd.Timeout)
+		killRemoteProcess(ctx, pid, gracePeriod, cmd.TerminationSignal, signal, processExitCh)
+		// The same exit code as timeout(1).
+		statusCh <- statusAndMetadata{status: Error, pid: pid, reason: v1alpha1.CmdReasonTimedOut, exitCode: 124}
+	case <-ctx.Done():
+		killRemoteProcess(ctx, pid, gracePeriod, cmd.TerminationSignal, signal, processExitCh)
+		statusCh <- statusAndMetadata{status: Done, pid: pid, reason: reasonKilled, exitCode: 137}
+	}
+}
+
+func exitStatus(ctx context.Context, cmd model.Cmd, pid int, err error) statusAndMetadata {
+	if err == nil {
+		return statusAndMetadata{status: Done, pid: pid}
+	}
+
+	exitCode := -1
+	switch ee := err.(type) {
+	case *ssh.ExitError:
+		exitCode = ee.ExitStatus()
+	case docker.ExitError:
+		exitCode = ee.ExitCode
+	}
+	if exitCode != -1 {
+		logger.Get(ctx).Errorf("%s exited with exit code %d", cmd.String(), exitCode)
+		return statusAndMetadata{status: Error, pid: pid, exitCode: exitCode, reason: err.Error()}
+	}
+	logger.Get(ctx).Errorf("error execing %s: %v", cmd.String(), err)
+	return statusAndMetadata{status: Error, pid: pid, exitCode: 1, reason: err.Error()}
+}
+
+// Stops the process group the same way processExecer stops a local one: the
+// termination signal, then SIGKILL once the grace period is up.
+func killRemoteProcess(ctx context.Context, pid int, gracePeriod time.Duration, terminationSignal string,
+	signal func(pid int, signal string), processExitCh chan error) {
+	if gracePeriod <= 0 {
+		logger.Get(ctx).Debugf("No grace period, sending SIGKILL to the process group of %d", pid)
+		signal(pid, "SIGKILL")
+		return
+	}
+
+	if terminationSignal == "" {
+		terminationSignal = "SIGTERM"
+	}
+	logger.Get(ctx).Debugf("About to gracefully shut down process %d", pid)
+	signal(pid, terminationSignal)
+
+	if !waitForExit(ctx, gracePeriod, pid, processExitCh) {
+		logger.Get(ctx).Infof("Time is up! Sending %d a kill signal", pid)
+		signal(pid, "SIGKILL")
+	}
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/pty"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// SSHConfig says how to reach the host that an SSH execer runs commands on.
+type SSHConfig struct {
+	// The host and port, like "devbox:22".
+	Addr string
+
+	// The user to log in as.
+	User string
+
+	// A private key to log in with. Keys in the local SSH agent are tried too.
+	KeyFile string
+
+	// The known_hosts file that verifies the host's key.
+	KnownHostsFile string
+
+	// Forward the local SSH agent, so that commands can use the local keys,
+	// like to pull from git.
+	ForwardAgent bool
+}
+
+// The SSH config for a Cmd's SSH target.
+func sshConfigForTarget(target v1alpha1.CmdSSHTarget) SSHConfig {
+	config := parseSSHHost(target.Host)
+	config.KeyFile = target.KeyFile
+	config.KnownHostsFile = target.KnownHostsFile
+	if config.KnownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			config.KnownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+		}
+	}
+	config.ForwardAgent = target.ForwardAgent
+	return config
+}
+
+// Parses a host like "alice@devbox:2222". The user defaults to the local
+// user, and the port to 22.
+func parseSSHHost(host string) SSHConfig {
+	config := SSHConfig{}
+	if i := strings.LastIndex(host, "@"); i != -1 {
+		config.User = host[:i]
+		host = host[i+1:]
+	} else if u, err := user.Current(); err == nil {
+		config.User = u.Username
+	}
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "22")
+	}
+	config.Addr = host
+	return config
+}
+
+// Runs commands on a remote host over SSH, for teams whose code lives on a
+// shared dev box.
+//
+// The command runs in the same directory as it would locally, so the code
+// should be at the same path on the remote host. Only the command's own env
+// is sent, not Tilt's.
+//
+// Each command runs in its own process group on the remote host, so that
+// stopping it stops everything it started, like processExecer does locally.
+type sshExecer struct {
+	config      SSHConfig
+	gracePeriod time.Duration
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+var _ Execer = &sshExecer{}
+
+func NewSSHExecer(config SSHConfig) *sshExecer {
+	return &sshExecer{
+		config:      config,
+		gracePeriod: DefaultGracePeriod,
+	}
+}
+
+func (e *sshExecer) Start(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer) chan statusAndMetadata {
+	statusCh := make(chan statusAndMetadata)
+
+	go func() {
+		e.sshRun(ctx, cmd, stdin, stdout, stderr, statusCh)
+	}()
+
+	return statusCh
+}
+
+func (e *sshExecer) sshRun(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout, stderr io.Writer, statusCh chan statusAndMetadata) {
+	defer close(statusCh)
+
+	logger.Get(ctx).Infof("Running cmd on %s: %s", e.config.Addr, cmd.String())
+	if len(cmd.Argv) == 0 {
+		logger.Get(ctx).Errorf("%q invalid cmd: empty cmd", cmd.String())
+		statusCh <- statusAndMetadata{
+			status:   Error,
+			exitCode: 1,
+			reason:   "invalid cmd: empty cmd",
+		}
+		return
+	}
+
+	// The env files are on this machine, and variables expand to Tilt's
+	// environment, like for a local command.
+	resolved, err := localexec.ResolveEnv(cmd, os.Environ())
+	if err != nil {
+		logger.Get(ctx).Errorf("%q invalid cmd: %v", cmd.String(), err)
+		statusCh <- statusAndMetadata{
+			status:   Error,
+			exitCode: 1,
+			reason:   fmt.Sprintf("invalid cmd: %v", err),
+		}
+		return
+	}
+	cmd = resolved
+
+	client, session, err := e.newSession(ctx)
+	if err == nil {
+		defer func() { _ = session.Close() }()
+		err = e.startSession(session, cmd, stdin, stderr)
+	}
+
+	pidCh := make(chan int, 1)
+	if err == nil {
+		session.Stdout = &pidWriter{w: stdout, pidCh: pidCh}
+		// Run the script with sh, whatever the user's login shell is.
+		err = session.Start("sh -c " + shellescape.Quote(remoteScript(cmd)))
+	}
+	if err != nil {
+		logger.Get(ctx).Errorf("%s failed to start on %s: %v", cmd.String(), e.config.Addr, err)
+		statusCh <- statusAndMetadata{
+			status:   Error,
+			exitCode: 1,
+			reason:   fmt.Sprintf("failed to start: %v", err),
+		}
+		return
+	}
+
+	processExitCh := make(chan error, 1)
+	go func() {
+		processExitCh <- session.Wait()
+		close(processExitCh)
+	}()
+
+	followRemoteProcess(ctx, cmd, e.gracePeriodFor(cmd), pidCh, processExitCh, func(pid int, signal string) {
+		e.signalProcessGroup(ctx, client, pid, signal)
+	}, statusCh)
+}
+
+func (e *sshExecer) startSession(session *ssh.Session, cmd model.Cmd, stdin io.Reader, stderr io.Writer) error {
+	if e.config.ForwardAgent {
+		err := agent.RequestAgentForwarding(session)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.PTY {
+		size := pty.TerminalSize()
+		err := session.RequestPty("xterm-256color", int(size.Rows), int(size.Cols), ssh.TerminalModes{})
+		if err != nil {
+			return err
+		}
+	} else {
+		session.Stderr = stderr
+	}
+
+	if stdin != nil {
+		// Copy it ourselves, because Wait() waits for the session to finish
+		// reading stdin, which may never end.
+		in, err := session.StdinPipe()
+		if err != nil {
+			return err
+		}
+		go func() {
+			_, _ = io.Copy(in, stdin)
+			_ = in.Close()
+		}()
+	}
+	return nil
+}
+
+func (e *sshExecer) gracePeriodFor(cmd model.Cmd) time.Duration {
+	if cmd.GracePeriod != nil {
+		return *cmd.GracePeriod
+	}
+	return e.gracePeriod
+}
+
+func (e *sshExecer) signalProcessGroup(ctx context.Context, client *ssh.Client, pid int, signal string) {
+	session, err := client.NewSession()
+	if err == nil {
+		defer func() { _ = session.Close() }()
+		err = session.Run(remoteKillScript(pid, signal))
+	}
+	if err != nil {
+		logger.Get(ctx).Debugf("Unable to send %s to the process group of %d: %v", signal, pid, err)
+	}
+}
+
+// Opens a session, connecting to the host if there's no connection yet or
+// the old one went away.
+func (e *sshExecer) newSession(ctx context.Context) (*ssh.Client, *ssh.Session, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client != nil {
+		session, err := e.client.NewSession()
+		if err == nil {
+			return e.client, session, nil
+		}
+		_ = e.client.Close()
+		e.client = nil
+	}
+
+	client, err := e.dial(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to %s: %v", e.config.Addr, err)
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+	e.client = client
+	return client, session, nil
+}
+
+func (e *sshExecer) dial(ctx context.Context) (*ssh.Client, error) {
+	hostKeyCallback, err := knownHostsCallback(e.config.KnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth []ssh.AuthMethod
+	if e.config.KeyFile != "" {
+		contents, err := os.ReadFile(e.config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(contents)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %v", e.config.KeyFile, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	agentSock := os.Getenv("SSH_AUTH_SOCK")
+	var agentConn net.Conn
+	if agentSock != "" {
+		conn, err := net.Dial("unix", agentSock)
+		if err == nil {
+			agentConn = conn
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+		}
+	}
+	closeAgent := func() {
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", e.config.Addr)
+	if err != nil {
+		closeAgent()
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, e.config.Addr, &ssh.ClientConfig{
+		User:            e.config.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		_ = conn.Close()
+		closeAgent()
+		return nil, err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+
+	// The agent signs for the client until it closes.
+	go func() {
+		_ = client.Wait()
+		closeAgent()
+	}()
+
+	if e.config.ForwardAgent {
+		if agentSock == "" {
+			_ = client.Close()
+			return nil, fmt.Errorf("can't forward the SSH agent: SSH_AUTH_SOCK isn't set")
+		}
+		err = agent.ForwardToRemote(client, agentSock)
+		if err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("forwarding the SSH agent: %v", err)
+		}
+	}
+	return client, nil
+}
+
+// Verifies host keys against a known_hosts file, and says which key to add
+// when the host isn't in it.
+func knownHostsCallback(file string) (ssh.HostKeyCallback, error) {
+	callback, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading known hosts: %v", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) == 0 {
+			return fmt.Errorf("the host key of %s isn't in %s (%s %s)",
+				knownhosts.Normalize(hostname), file, key.Type(), ssh.FingerprintSHA256(key))
+		}
+		want := keyErr.Want[0]
+		return fmt.Errorf("the host key of %s doesn't match the one at %s:%d (got %s %s)",
+			knownhosts.Normalize(hostname), want.Filename, want.Line, key.Type(), ssh.FingerprintSHA256(key))
+	}, nil
+}
@@ -0,0 +1,353 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestSSHTrue(t *testing.T) {
+	f := newSSHExecFixture(t)
+
+	f.start(model.ToUnixCmd("echo hello"))
+
+	f.waitForStatus(Running)
+	f.waitForStatus(Done)
+	f.assertLogContains("hello")
+}
+
+func TestSSHWorkdirAndEnv(t *testing.T) {
+	f := newSSHExecFixture(t)
+	d := tempdir.NewTempDirFixture(t)
+
+	c := model.ToUnixCmd(`pwd; echo "FOO is $FOO"`)
+	c.Dir = d.Path()
+	c.Env = []string{"FOO=it's bar"}
+	f.start(c)
+
+	f.waitForStatus(Done)
+	f.assertLogContains(d.Path())
+	f.assertLogContains("FOO is it's bar")
+}
+
+func TestSSHExitCode(t *testing.T) {
+	f := newSSHExecFixture(t)
+
+	f.start(model.ToUnixCmd("exit 3"))
+
+	sm := f.waitForStatus(Error)
+	assert.Equal(t, 3, sm.exitCode)
+	f.assertLogContains("exited with exit code 3")
+}
+
+func TestSSHShutdownOnCancel(t *testing.T) {
+	f := newSSHExecFixture(t)
+
+	f.start(model.ToUnixCmd(`trap 'echo "cleanup time!"; exit 1' TERM; while true; do sleep 0.1; done`))
+
+	f.waitForStatus(Running)
+	f.cancel()
+	sm := f.waitForStatus(Done)
+	assert.Equal(t, 137, sm.exitCode)
+	f.assertLogContains("cleanup time!")
+}
+
+func TestSSHTimeout(t *testing.T) {
+	f := newSSHExecFixture(t)
+
+	c := model.ToUnixCmd("sleep 10")
+	c.Timeout = 500 * time.Millisecond
+	f.start(c)
+
+	f.waitForStatus(Running)
+	sm := f.waitForStatus(Error)
+	assert.Equal(t, v1alpha1.CmdReasonTimedOut, sm.reason)
+	assert.Equal(t, 124, sm.exitCode)
+}
+
+func TestSSHStopsGrandchildren(t *testing.T) {
+	f := newSSHExecFixture(t)
+	d := tempdir.NewTempDirFixture(t)
+
+	// The background job outlives the command unless its group is killed.
+	c := model.ToUnixCmd(`(sleep 1; touch done) & echo started`)
+	c.Dir = d.Path()
+	f.start(c)
+
+	f.waitForStatus(Done)
+	f.assertLogContains("started")
+	time.Sleep(1500 * time.Millisecond)
+	assert.NoFileExists(t, d.JoinPath("done"))
+}
+
+func TestSSHStdin(t *testing.T) {
+	f := newSSHExecFixture(t)
+
+	stdin, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+	f.statusCh = f.execer.Start(f.ctx, model.ToUnixCmd(`read line && echo "got $line"`), stdin, f.testWriter, f.testWriter)
+	f.waitForStatus(Running)
+
+	_, err := w.Write([]byte("r\n"))
+	require.NoError(t, err)
+	f.waitForStatus(Done)
+	f.assertLogContains("got r")
+}
+
+func TestSSHUnknownHost(t *testing.T) {
+	f := newSSHExecFixture(t)
+	f.writeKnownHosts("otherbox", f.hostKey.PublicKey())
+
+	f.start(model.ToUnixCmd("echo hello"))
+
+	f.waitForStatus(Error)
+	f.assertLogContains("isn't in")
+}
+
+func TestSSHChangedHostKey(t *testing.T) {
+	f := newSSHExecFixture(t)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(otherKey)
+	require.NoError(t, err)
+	f.writeKnownHosts(knownhosts.Normalize(f.execer.config.Addr), signer.PublicKey())
+
+	f.start(model.ToUnixCmd("echo hello"))
+
+	f.waitForStatus(Error)
+	f.assertLogContains("doesn't match the one at")
+}
+
+func TestTargetExecer(t *testing.T) {
+	f := newSSHExecFixture(t)
+	local := NewFakeExecer()
+	container := NewFakeExecer()
+	execer := newTargetExecer(local, container)
+
+	c := model.ToUnixCmd("echo remote")
+	c.Target = &v1alpha1.CmdTarget{SSH: &v1alpha1.CmdSSHTarget{
+		Host:           "tilt@" + f.execer.config.Addr,
+		KeyFile:        f.execer.config.KeyFile,
+		KnownHostsFile: f.execer.config.KnownHostsFile,
+	}}
+	f.statusCh = execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
+	f.waitForStatus(Done)
+	f.assertLogContains("remote")
+	assert.Len(t, execer.ssh, 1)
+
+	execer.Start(f.ctx, model.ToUnixCmd("echo local"), nil, f.testWriter, f.testWriter)
+	assert.Len(t, local.starts["echo local"], 1)
+	assert.Empty(t, local.starts["echo remote"])
+
+	c = model.ToUnixCmd("echo container")
+	c.Target = &v1alpha1.CmdTarget{Container: &v1alpha1.CmdContainerTarget{Name: "api-1"}}
+	execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
+	assert.Len(t, container.starts["echo container"], 1)
+	assert.Empty(t, local.starts["echo container"])
+}
+
+func TestParseSSHHost(t *testing.T) {
+	assert.Equal(t, SSHConfig{User: "alice", Addr: "devbox:2222"}, parseSSHHost("alice@devbox:2222"))
+	assert.Equal(t, SSHConfig{User: "alice", Addr: "devbox:22"}, parseSSHHost("alice@devbox"))
+	assert.Equal(t, SSHConfig{User: "alice", Addr: "[::1]:22"}, parseSSHHost("alice@[::1]"))
+}
+
+func TestSSHHashedKnownHost(t *testing.T) {
+	f := newSSHExecFixture(t)
+	f.writeKnownHosts(knownhosts.HashHostname(knownhosts.Normalize(f.execer.config.Addr)), f.hostKey.PublicKey())
+
+	f.start(model.ToUnixCmd("echo hello"))
+
+	f.waitForStatus(Done)
+	f.assertLogContains("hello")
+}
+
+type sshExecFixture struct {
+	*processExecFixture
+	execer  *sshExecer
+	dir     string
+	hostKey ssh.Signer
+}
+
+func newSSHExecFixture(t *testing.T) *sshExecFixture {
+	dir := t.TempDir()
+	hostKey, clientKeyFile := newTestSSHServerKeys(t, dir)
+	addr := runTestSSHServer(t, hostKey, clientKeyFile)
+
+	execer := NewSSHExecer(SSHConfig{
+		Addr:           addr,
+		User:           "tilt",
+		KeyFile:        clientKeyFile,
+		KnownHostsFile: filepath.Join(dir, "known_hosts"),
+	})
+	execer.gracePeriod = time.Second
+
+	testWriter := bufsync.NewThreadSafeBuffer()
+	ctx, _, _ := testutils.ForkedCtxAndAnalyticsForTest(testWriter)
+	ctx, cancel := context.WithCancel(ctx)
+
+	f := &sshExecFixture{
+		processExecFixture: &processExecFixture{
+			t:          t,
+			ctx:        ctx,
+			cancel:     cancel,
+			testWriter: testWriter,
+		},
+		execer:  execer,
+		dir:     dir,
+		hostKey: hostKey,
+	}
+	f.writeKnownHosts(knownhosts.Normalize(addr), hostKey.PublicKey())
+	t.Cleanup(f.tearDown)
+	return f
+}
+
+func (f *sshExecFixture) start(c model.Cmd) {
+	f.statusCh = f.execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
+}
+
+func (f *sshExecFixture) writeKnownHosts(host string, key ssh.PublicKey) {
+	line := fmt.Sprintf("%s %s", host, ssh.MarshalAuthorizedKey(key))
+	err := os.WriteFile(filepath.Join(f.dir, "known_hosts"), []byte(line), 0600)
+	require.NoError(f.t, err)
+}
+
+// Returns a host key, and writes a client key that the server accepts.
+func newTestSSHServerKeys(t *testing.T, dir string) (ssh.Signer, string) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(dir, "id_ecdsa")
+	err = os.WriteFile(clientKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+	require.NoError(t, err)
+	return hostSigner, clientKeyFile
+}
+
+// Runs an SSH server that runs commands on this machine with sh.
+func runTestSSHServer(t *testing.T, hostKey ssh.Signer, clientKeyFile string) string {
+	contents, err := os.ReadFile(clientKeyFile)
+	require.NoError(t, err)
+	clientSigner, err := ssh.ParsePrivateKey(contents)
+	require.NoError(t, err)
+	clientPub := string(clientSigner.PublicKey().Marshal())
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != clientPub {
+				return nil, fmt.Errorf("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "only sessions")
+			continue
+		}
+		ch, reqs, err := newCh.Accept()
+		if err != nil {
+			continue
+		}
+		go serveTestSSHSession(ch, reqs)
+	}
+}
+
+func serveTestSSHSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+
+		var payload struct{ Command string }
+		err := ssh.Unmarshal(req.Payload, &payload)
+		if err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+
+		go func() {
+			defer func() { _ = ch.Close() }()
+
+			c := exec.Command("sh", "-c", payload.Command)
+			c.Stdout = ch
+			c.Stderr = ch.Stderr()
+
+			// Like sshd, don't wait for the input to end.
+			stdin, err := c.StdinPipe()
+			if err == nil {
+				go func() {
+					_, _ = io.Copy(stdin, ch)
+					_ = stdin.Close()
+				}()
+				err = c.Run()
+			}
+			code := 0
+			if ee, ok := err.(*exec.ExitError); ok {
+				code = ee.ExitCode()
+			} else if err != nil {
+				code = 1
+			}
+			_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(code)}))
+		}()
+	}
+}
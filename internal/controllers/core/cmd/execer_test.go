@@ -16,6 +16,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -86,6 +87,188 @@ sleep 100
 	f.assertLogContains("cleanup time")
 }
 
+func TestGracePeriodOverride(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no bash on windows")
+	}
+	f := newProcessExecFixture(t)
+	f.execer.gracePeriod = time.Minute
+
+	c := model.ToHostCmd(`trap 'echo ignoring TERM $((6*7))' TERM; echo ready $((6*7)); while true; do sleep 0.1; done`)
+	gracePeriod := 200 * time.Millisecond
+	c.GracePeriod = &gracePeriod
+	f.startCmd(c)
+	f.waitForStatus(Running)
+	f.assertLogContains("ready 42")
+	f.cancel()
+
+	// Killed after the command's grace period, not the execer's.
+	f.waitForStatus(Done)
+	f.assertLogContains("ignoring TERM 42")
+}
+
+func TestZeroGracePeriodKillsImmediately(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no bash on windows")
+	}
+	f := newProcessExecFixture(t)
+
+	c := model.ToHostCmd(`trap 'echo cleanup $((6*7)); exit 1' TERM; while true; do sleep 0.1; done`)
+	gracePeriod := time.Duration(0)
+	c.GracePeriod = &gracePeriod
+	f.startCmd(c)
+	f.waitForStatus(Running)
+	f.cancel()
+
+	f.waitForStatus(Done)
+	assert.NotContains(t, f.testWriter.String(), "cleanup 42")
+}
+
+func TestTerminationSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no signals on windows")
+	}
+	f := newProcessExecFixture(t)
+
+	// Ignores SIGTERM, so it only exits before the grace period if it gets SIGINT.
+	f.execer.gracePeriod = time.Minute
+	c := model.ToHostCmd(`trap '' TERM; trap 'echo got INT $((6*7)); exit 0' INT; echo ready $((6*7)); while true; do sleep 0.1; done`)
+	c.TerminationSignal = "SIGINT"
+	f.startCmd(c)
+	f.waitForStatus(Running)
+	f.assertLogContains("ready 42")
+	f.cancel()
+
+	f.waitForStatus(Done)
+	f.assertLogContains("got INT 42")
+}
+
+func TestTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no bash on windows")
+	}
+	f := newProcessExecFixture(t)
+
+	c := model.ToHostCmd(`trap 'echo cleanup $((6*7)); exit 0' TERM; while true; do sleep 0.1; done`)
+	c.Timeout = 500 * time.Millisecond
+	f.startCmd(c)
+	f.waitForStatus(Running)
+
+	// Stopped gracefully, but still an error.
+	sm := f.waitForStatus(Error)
+	assert.Equal(t, v1alpha1.CmdReasonTimedOut, sm.reason)
+	assert.Equal(t, 124, sm.exitCode)
+	f.assertLogContains("cleanup 42")
+	f.assertLogContains("timed out after 500ms")
+}
+
+func TestTimeoutNotReached(t *testing.T) {
+	f := newProcessExecFixture(t)
+
+	c := model.ToHostCmd("echo hello")
+	c.Timeout = time.Minute
+	f.startCmd(c)
+
+	sm := f.waitForStatus(Done)
+	assert.Equal(t, 0, sm.exitCode)
+	assert.Empty(t, sm.reason)
+}
+
+func TestReportsUsage(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("no busy loop in sh")
+	}
+	f := newProcessExecFixture(t)
+	f.execer.usageInterval = 100 * time.Millisecond
+
+	f.start(`while :; do :; done`)
+	f.waitForStatus(Running)
+
+	deadlineCh := time.After(2 * time.Second)
+	for {
+		select {
+		case sm := <-f.statusCh:
+			require.Equal(t, Running, sm.status)
+			if sm.usage != nil && sm.usage.CPUMillicores > 0 {
+				assert.Greater(t, sm.usage.MemoryBytes, int64(0))
+				return
+			}
+		case <-deadlineCh:
+			t.Fatal("Timed out waiting for usage")
+		}
+	}
+}
+
+func TestPTY(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no pseudo-terminals on windows")
+	}
+	f := newProcessExecFixture(t)
+
+	c := model.ToHostCmd(`test -t 1 && echo terminal $((6*7))`)
+	c.PTY = true
+	f.startCmd(c)
+
+	f.assertCmdSucceeds()
+	f.assertLogContains("terminal 42")
+}
+
+func TestStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no sh on windows")
+	}
+	f := newProcessExecFixture(t)
+
+	stdin, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+	c := model.ToHostCmd(`read line && echo "got $line"`)
+	c.Dir = "."
+	f.statusCh = f.execer.Start(f.ctx, c, stdin, f.testWriter, f.testWriter)
+	f.waitForStatus(Running)
+
+	_, err := w.Write([]byte("r\n"))
+	require.NoError(t, err)
+	f.assertCmdSucceeds()
+	f.assertLogContains("got r")
+}
+
+func TestStdinPTY(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no pseudo-terminals on windows")
+	}
+	f := newProcessExecFixture(t)
+
+	stdin, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+	c := model.ToHostCmd(`read line && echo "got $line"`)
+	c.Dir = "."
+	c.PTY = true
+	f.statusCh = f.execer.Start(f.ctx, c, stdin, f.testWriter, f.testWriter)
+	f.waitForStatus(Running)
+
+	_, err := w.Write([]byte("r\n"))
+	require.NoError(t, err)
+	f.assertCmdSucceeds()
+	f.assertLogContains("got r")
+}
+
+func TestSeparatesStderr(t *testing.T) {
+	f := newProcessExecFixture(t)
+
+	stdout := bufsync.NewThreadSafeBuffer()
+	stderr := bufsync.NewThreadSafeBuffer()
+	c := model.ToHostCmd("echo to-stdout && echo to-stderr 1>&2")
+	c.Dir = "."
+	f.statusCh = f.execer.Start(f.ctx, c, nil, stdout, stderr)
+	f.assertCmdSucceeds()
+
+	// The output may still be arriving after the process exits.
+	assert.Eventually(t, func() bool {
+		return strings.TrimSpace(stdout.String()) == "to-stdout" &&
+			strings.TrimSpace(stderr.String()) == "to-stderr"
+	}, time.Second, 5*time.Millisecond)
+}
+
 func TestPrintsLogs(t *testing.T) {
 	f := newProcessExecFixture(t)
 
@@ -146,6 +329,35 @@ func TestExecEmpty(t *testing.T) {
 	f.assertLogContains("empty cmd")
 }
 
+func TestEnvFile(t *testing.T) {
+	f := newProcessExecFixture(t)
+	d := tempdir.NewTempDirFixture(t)
+	d.WriteFile(".env", "GREETING=hello from the env file\n")
+
+	script := "echo $GREETING"
+	if runtime.GOOS == "windows" {
+		script = "echo %GREETING%"
+	}
+	c := model.ToHostCmd(script)
+	c.EnvFiles = []string{d.JoinPath(".env")}
+	f.startCmd(c)
+
+	f.assertCmdSucceeds()
+	f.assertLogContains("hello from the env file")
+}
+
+func TestMissingEnvFile(t *testing.T) {
+	f := newProcessExecFixture(t)
+	d := tempdir.NewTempDirFixture(t)
+
+	c := model.ToHostCmd("echo hi")
+	c.EnvFiles = []string{d.JoinPath(".env")}
+	f.startCmd(c)
+
+	f.waitForError()
+	f.assertLogContains("reading env file")
+}
+
 func TestExecCmd(t *testing.T) {
 	testCases := execTestCases()
 
@@ -221,24 +433,29 @@ func (f *processExecFixture) tearDown() {
 
 func (f *processExecFixture) startMalformedCommand() {
 	c := model.Cmd{Argv: []string{"\""}, Dir: "."}
-	f.statusCh = f.execer.Start(f.ctx, c, f.testWriter)
+	f.statusCh = f.execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
 }
 
 func (f *processExecFixture) startWithWorkdir(cmd string, workdir string) {
 	c := model.ToHostCmd(cmd)
 	c.Dir = workdir
-	f.statusCh = f.execer.Start(f.ctx, c, f.testWriter)
+	f.statusCh = f.execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
 }
 
 func (f *processExecFixture) start(cmd string) {
 	f.startWithWorkdir(cmd, ".")
 }
 
+func (f *processExecFixture) startCmd(c model.Cmd) {
+	c.Dir = "."
+	f.statusCh = f.execer.Start(f.ctx, c, nil, f.testWriter, f.testWriter)
+}
+
 func (f *processExecFixture) assertCmdSucceeds() {
 	f.waitForStatus(Done)
 }
 
-func (f *processExecFixture) waitForStatus(expectedStatus status) {
+func (f *processExecFixture) waitForStatus(expectedStatus status) statusAndMetadata {
 	deadlineCh := time.After(2 * time.Second)
 	for {
 		select {
@@ -247,15 +464,15 @@ func (f *processExecFixture) waitForStatus(expectedStatus status) {
 				f.t.Fatal("statusCh closed")
 			}
 			if expectedStatus == sm.status {
-				return
+				return sm
 			}
 			if sm.status == Error {
 				f.t.Error("Unexpected Error")
-				return
+				return sm
 			}
 			if sm.status == Done {
 				f.t.Error("Unexpected Done")
-				return
+				return sm
 			}
 		case <-deadlineCh:
 			f.t.Fatal("Timed out waiting for cmd sm")
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/metrics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Identifies a command in lifecycle metrics.
+//
+// A resource's serve_cmd gets a new Cmd (with a new name) every time the
+// resource updates, so metrics go by resource instead of by Cmd name.
+type LifecycleKey struct {
+	// The resource that the command belongs to, or the Cmd's name if it
+	// doesn't belong to one.
+	Resource string
+
+	// Which of the resource's commands it is.
+	Kind CmdKind
+}
+
+type CmdKind string
+
+const (
+	// A resource's serve_cmd.
+	CmdKindServe CmdKind = "serve"
+
+	// A command that updates a resource, like a local_resource's cmd or a
+	// custom_build's command.
+	CmdKindUpdate CmdKind = "update"
+
+	// Any other Cmd object.
+	CmdKindOther CmdKind = "cmd"
+)
+
+func lifecycleKeyForCmd(cmd *v1alpha1.Cmd) LifecycleKey {
+	resource := cmd.Annotations[v1alpha1.AnnotationManifest]
+	if resource == "" {
+		resource = cmd.Name
+	}
+
+	kind := CmdKindOther
+	switch {
+	case cmd.Annotations[local.AnnotationOwnerKind] == "CmdServer":
+		kind = CmdKindServe
+	case cmd.Annotations[v1alpha1.AnnotationManagedBy] == "local_resource",
+		cmd.Annotations[v1alpha1.AnnotationManagedBy] == "cmd_image":
+		kind = CmdKindUpdate
+	}
+	return LifecycleKey{Resource: resource, Kind: kind}
+}
+
+// Why a command stopped.
+//
+// Unlike the Reason in the Cmd's status, there are only a few of these, so
+// they're suitable for grouping metrics.
+type ExitReason string
+
+const (
+	ExitReasonSucceeded   ExitReason = "succeeded"
+	ExitReasonFailed      ExitReason = "failed"
+	ExitReasonStartFailed ExitReason = "start_failed"
+	ExitReasonKilled      ExitReason = "killed"
+	ExitReasonTimedOut    ExitReason = "timed_out"
+)
+
+// Receives the lifecycle of each run of a command, e.g., to export it as
+// metrics.
+//
+// It's called from the controller's goroutines, so it mustn't block.
+type LifecycleObserver interface {
+	// The command started running.
+	OnStart(key LifecycleKey, startedAt time.Time)
+
+	// The command became ready, this long after it started.
+	OnReady(key LifecycleKey, timeToReady time.Duration)
+
+	// The controller restarted the command because of its restart policy.
+	OnRestart(key LifecycleKey, count int32)
+
+	// The command stopped, after running this long.
+	OnExit(key LifecycleKey, reason ExitReason, runtime time.Duration)
+}
+
+// Exports command lifecycles as Prometheus metrics, served at the
+// apiserver's /metrics.
+type prometheusLifecycleObserver struct{}
+
+var _ LifecycleObserver = prometheusLifecycleObserver{}
+
+func (prometheusLifecycleObserver) OnStart(key LifecycleKey, startedAt time.Time) {
+	metrics.CmdStartTime.WithLabelValues(key.Resource, string(key.Kind)).
+		Set(float64(startedAt.UnixNano()) / float64(time.Second))
+}
+
+func (prometheusLifecycleObserver) OnReady(key LifecycleKey, timeToReady time.Duration) {
+	metrics.CmdTimeToReady.WithLabelValues(key.Resource, string(key.Kind)).Observe(timeToReady.Seconds())
+}
+
+func (prometheusLifecycleObserver) OnRestart(key LifecycleKey, count int32) {
+	metrics.CmdRestartsTotal.WithLabelValues(key.Resource, string(key.Kind)).Inc()
+}
+
+func (prometheusLifecycleObserver) OnExit(key LifecycleKey, reason ExitReason, runtime time.Duration) {
+	metrics.CmdRuntime.WithLabelValues(key.Resource, string(key.Kind), string(reason)).Observe(runtime.Seconds())
+}
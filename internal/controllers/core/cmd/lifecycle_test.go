@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/metrics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestLifecycleKeyForCmd(t *testing.T) {
+	serve := &v1alpha1.Cmd{ObjectMeta: metav1.ObjectMeta{
+		Name: "fe-serve-3",
+		Annotations: map[string]string{
+			v1alpha1.AnnotationManifest: "fe",
+			local.AnnotationOwnerKind:   "CmdServer",
+		},
+	}}
+	assert.Equal(t, LifecycleKey{Resource: "fe", Kind: CmdKindServe}, lifecycleKeyForCmd(serve))
+
+	update := &v1alpha1.Cmd{ObjectMeta: metav1.ObjectMeta{
+		Name: "fe:update",
+		Annotations: map[string]string{
+			v1alpha1.AnnotationManifest:  "fe",
+			v1alpha1.AnnotationManagedBy: "local_resource",
+		},
+	}}
+	assert.Equal(t, LifecycleKey{Resource: "fe", Kind: CmdKindUpdate}, lifecycleKeyForCmd(update))
+
+	other := &v1alpha1.Cmd{ObjectMeta: metav1.ObjectMeta{Name: "my-cmd"}}
+	assert.Equal(t, LifecycleKey{Resource: "my-cmd", Kind: CmdKindOther}, lifecycleKeyForCmd(other))
+}
+
+func TestPrometheusLifecycleObserver(t *testing.T) {
+	// The metrics are global, so use a resource name that no other test uses.
+	key := LifecycleKey{Resource: "prometheus-lifecycle", Kind: CmdKindServe}
+	o := prometheusLifecycleObserver{}
+
+	o.OnStart(key, time.Unix(1000, 0))
+	o.OnReady(key, 3*time.Second)
+	o.OnRestart(key, 1)
+	o.OnExit(key, ExitReasonFailed, time.Minute)
+
+	v, err := testutil.GetGaugeMetricValue(metrics.CmdStartTime.WithLabelValues(key.Resource, "serve"))
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, v)
+
+	v, err = testutil.GetHistogramMetricValue(metrics.CmdTimeToReady.WithLabelValues(key.Resource, "serve"))
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, v)
+
+	v, err = testutil.GetCounterMetricValue(metrics.CmdRestartsTotal.WithLabelValues(key.Resource, "serve"))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+
+	v, err = testutil.GetHistogramMetricValue(metrics.CmdRuntime.WithLabelValues(key.Resource, "serve", "failed"))
+	require.NoError(t, err)
+	assert.Equal(t, 60.0, v)
+}
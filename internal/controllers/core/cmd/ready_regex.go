@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// Matches ANSI escape codes, like colors, so that a ReadyRegex doesn't
+// need to know whether the process prints them.
+var ansiEscapeRe = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]")
+
+// Lines longer than this are dropped without being matched, so that a
+// process that never prints a newline doesn't grow the buffer forever.
+const maxReadyLineLen = 64 * 1024
+
+// Watches the output of a process for the first line that matches a
+// ReadyRegex, and reports it on a channel.
+//
+// A process has one readyMatcher, and a writer for each of its streams.
+type readyMatcher struct {
+	re      *regexp.Regexp
+	matchCh chan string
+	once    sync.Once
+}
+
+func newReadyMatcher(re *regexp.Regexp) *readyMatcher {
+	return &readyMatcher{
+		re:      re,
+		matchCh: make(chan string, 1),
+	}
+}
+
+// Wraps one stream of output. The output is passed through unchanged.
+func (m *readyMatcher) writer(w io.Writer) io.Writer {
+	return &readyLineWriter{w: w, m: m}
+}
+
+func (m *readyMatcher) match(line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	line = ansiEscapeRe.ReplaceAll(line, nil)
+	if !m.re.Match(line) {
+		return false
+	}
+	m.once.Do(func() {
+		m.matchCh <- string(line)
+	})
+	return true
+}
+
+type readyLineWriter struct {
+	w       io.Writer
+	m       *readyMatcher
+	buf     []byte
+	matched bool
+}
+
+func (w *readyLineWriter) Write(p []byte) (int, error) {
+	if !w.matched {
+		w.scan(p)
+	}
+	return w.w.Write(p)
+}
+
+func (w *readyLineWriter) scan(p []byte) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+		line := w.buf[:i+1]
+		w.buf = w.buf[i+1:]
+		if w.m.match(line) {
+			w.matched = true
+			w.buf = nil
+			return
+		}
+	}
+	if len(w.buf) > maxReadyLineLen {
+		w.buf = nil
+	}
+}
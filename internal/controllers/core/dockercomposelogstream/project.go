@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	dtypes "github.com/docker/docker/api/types"
+
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -64,31 +66,20 @@ func (r *Reconciler) runProjectWatch(pw *ProjectWatch) {
 	}()
 
 	ctx := pw.ctx
-	project := pw.project
-	ch, err := r.dcc.StreamEvents(ctx, project)
-	if err != nil {
-		// TODO(nick): Figure out where this error should be published.
-		return
-	}
-
+	stream := r.dcc.StreamEvents(ctx, pw.project)
 	for {
 		select {
-		case evtJson, ok := <-ch:
+		case evt, ok := <-stream.Events():
 			if !ok {
 				return
 			}
-			evt, err := dockercompose.EventFromJsonStr(evtJson)
-			if err != nil {
-				logger.Get(ctx).Debugf("[dcwatch] failed to unmarshal dc event '%s' with err: %v", evtJson, err)
-				continue
-			}
 
 			if evt.Type != dockercompose.TypeContainer {
 				continue
 			}
 
 			key := serviceKey{service: evt.Service, projectHash: pw.hash}
-			state, err := r.getContainerState(ctx, evt.ID)
+			state, err := r.getContainerState(ctx, pw.project, evt.ID)
 			if err != nil {
 				logger.Get(ctx).Debugf("[dcwatch]: %v", err)
 				continue
@@ -100,15 +91,65 @@ func (r *Reconciler) runProjectWatch(pw *ProjectWatch) {
 			}
 			r.mu.Unlock()
 
+		case status := <-stream.Statuses():
+			if !status.Connected {
+				logger.Get(ctx).Debugf("[dcwatch] lost connection to Docker Compose events, reconnecting: %v", status.Error)
+				continue
+			}
+			r.resyncProjectWatch(pw)
+
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// Events may have been missed while the event stream was disconnected,
+// so re-check the container of every service in the project.
+func (r *Reconciler) resyncProjectWatch(pw *ProjectWatch) {
+	ctx := pw.ctx
+
+	r.mu.Lock()
+	var specs []v1alpha1.DockerComposeLogStreamSpec
+	for _, result := range r.results {
+		if result.projectHash == pw.hash {
+			specs = append(specs, result.spec)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, spec := range specs {
+		cid, err := r.dcc.ContainerID(ctx, v1alpha1.DockerComposeServiceSpec{Service: spec.Service, Project: spec.Project})
+		if err != nil || cid == "" {
+			continue
+		}
+
+		state, err := r.getContainerState(ctx, spec.Project, string(cid))
+		if err != nil {
+			logger.Get(ctx).Debugf("[dcwatch]: %v", err)
+			continue
+		}
+
+		key := serviceKey{service: spec.Service, projectHash: pw.hash}
+		r.mu.Lock()
+		if r.recordContainerState(key, state) {
+			r.requeueForServiceKey(key)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// Inspects a container on the Docker daemon that the project runs on.
+func (r *Reconciler) containerInspect(ctx context.Context, project v1alpha1.DockerComposeProject, id string) (dtypes.ContainerJSON, error) {
+	if dockercompose.HasDockerEndpoint(project) {
+		return r.dcc.ContainerInspect(ctx, project, id)
+	}
+	return r.dc.ContainerInspect(ctx, id)
+}
+
 // Fetch the state of the given container and convert it into our internal model.
-func (r *Reconciler) getContainerState(ctx context.Context, id string) (*v1alpha1.DockerContainerState, error) {
-	containerJSON, err := r.dc.ContainerInspect(ctx, id)
+func (r *Reconciler) getContainerState(ctx context.Context, project v1alpha1.DockerComposeProject, id string) (*v1alpha1.DockerContainerState, error) {
+	containerJSON, err := r.containerInspect(ctx, project, id)
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,6 @@ package dockercomposelogstream
 
 import (
 	"context"
-	"io"
 	"sync"
 	"time"
 
@@ -18,7 +17,6 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
-	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/dockercomposeservices"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -99,7 +97,7 @@ func (r *Reconciler) reconcileContainerState(ctx context.Context, obj *v1alpha1.
 		return
 	}
 
-	state, err := r.getContainerState(ctx, string(id))
+	state, err := r.getContainerState(ctx, obj.Spec.Project, string(id))
 	if err != nil {
 		return
 	}
@@ -188,28 +186,31 @@ func (r *Reconciler) consumeLogs(watch *watch) {
 	startTime := watch.startWatchTime
 
 	for {
-		readCloser := r.dcc.StreamLogs(ctx, watch.spec)
-		actionWriter := &LogActionWriter{
-			store:        r.store,
-			manifestName: watch.manifestName,
-			since:        startTime,
+		stream := r.dcc.StreamLogs(ctx, watch.spec, startTime)
+		for entry := range stream.Entries() {
+			if !entry.Time.IsZero() {
+				startTime = entry.Time
+			}
+			r.store.Dispatch(store.NewLogAction(watch.manifestName,
+				dockercomposeservices.SpanIDForDCService(watch.manifestName), logger.InfoLvl, nil, entry.Text))
 		}
 
-		reader := runtimelog.NewHardCancelReader(ctx, readCloser)
-		_, err := io.Copy(actionWriter, reader)
-		_ = readCloser.Close()
+		err := stream.Err()
 		if err == nil || ctx.Err() != nil {
-			// stop tailing because either:
-			// 	* docker-compose logs exited naturally -> this means the container exited, so a new watcher will
-			// 	  be created once a new container is seen
+			// stop streaming because either:
+			//  * the stream ended on its own -> a new watcher will be created once a new container is seen
 			//  * context was canceled -> manifest is no longer in engine & being torn-down
 			return
 		}
 
-		// something went wrong with docker-compose, log it and re-attach, starting from the last
+		// something went wrong talking to Docker, log it and re-attach, starting from the last
 		// successfully logged timestamp
 		logger.Get(watch.ctx).Debugf("Error streaming %s logs: %v", watch.nn.Name, err)
-		startTime = actionWriter.LastLogTime()
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
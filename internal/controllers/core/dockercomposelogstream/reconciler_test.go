@@ -96,6 +96,11 @@ func TestBasicLogsFromExisting(t *testing.T) {
 	assert.Eventually(t, func() bool {
 		return strings.Contains(f.Stdout(), expected)
 	}, time.Second, 10*time.Millisecond)
+
+	calls := f.dcc.StreamLogsCalls()
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "fe", calls[0].Spec.Service)
+	}
 }
 
 func TestTwoServices(t *testing.T) {
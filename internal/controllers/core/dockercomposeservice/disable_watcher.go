@@ -133,7 +133,7 @@ func (w *DisableSubscriber) Reconcile(ctx context.Context) {
 		return strings.HasPrefix(s, "Going to remove")
 	})
 
-	err := w.dcc.Rm(ctx, toDisable, out, out)
+	err := w.dcc.Rm(ctx, toDisable, false, out, out)
 	if err != nil {
 		var namesToDisable []string
 		for _, e := range toDisable {
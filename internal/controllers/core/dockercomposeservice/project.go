@@ -2,6 +2,9 @@ package dockercomposeservice
 
 import (
 	"context"
+	"fmt"
+
+	dtypes "github.com/docker/docker/api/types"
 
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
@@ -58,43 +61,31 @@ func (r *Reconciler) runProjectWatch(pw *ProjectWatch) {
 	}()
 
 	ctx := pw.ctx
-	project := pw.project
-	ch, err := r.dcc.StreamEvents(ctx, project)
-	if err != nil {
-		// TODO(nick): Figure out where this error should be published.
-		return
-	}
-
+	stream := r.dcc.StreamEvents(ctx, pw.project)
 	for {
 		select {
-		case evtJson, ok := <-ch:
+		case evt, ok := <-stream.Events():
 			if !ok {
 				return
 			}
-			evt, err := dockercompose.EventFromJsonStr(evtJson)
-			if err != nil {
-				logger.Get(ctx).Debugf("[dcwatch] failed to unmarshal dc event '%s' with err: %v", evtJson, err)
-				continue
-			}
 
 			if evt.Type != dockercompose.TypeContainer {
 				continue
 			}
 
-			containerJSON, err := r.dc.ContainerInspect(ctx, evt.ID)
+			state, err := r.getContainerState(ctx, pw.project, evt.ID)
 			if err != nil {
 				logger.Get(ctx).Debugf("[dcwatch] inspecting container: %v", err)
 				continue
 			}
+			r.recordContainerEvent(evt, state)
 
-			if containerJSON.ContainerJSONBase == nil || containerJSON.ContainerJSONBase.State == nil {
-				logger.Get(ctx).Debugf("[dcwatch] inspecting container: no state found")
+		case status := <-stream.Statuses():
+			if !status.Connected {
+				logger.Get(ctx).Debugf("[dcwatch] lost connection to Docker Compose events, reconnecting: %v", status.Error)
 				continue
 			}
-
-			cState := containerJSON.ContainerJSONBase.State
-			dcState := dockercompose.ToContainerState(cState)
-			r.recordContainerEvent(evt, dcState)
+			r.resyncProjectWatch(pw)
 
 		case <-ctx.Done():
 			return
@@ -102,6 +93,57 @@ func (r *Reconciler) runProjectWatch(pw *ProjectWatch) {
 	}
 }
 
+// Events may have been missed while the event stream was disconnected,
+// so re-check the container of every service in the project.
+func (r *Reconciler) resyncProjectWatch(pw *ProjectWatch) {
+	ctx := pw.ctx
+
+	r.mu.Lock()
+	var specs []v1alpha1.DockerComposeServiceSpec
+	for _, result := range r.results {
+		if result.ProjectHash == pw.hash {
+			specs = append(specs, result.Spec)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, spec := range specs {
+		cid, err := r.dcc.ContainerID(ctx, spec)
+		if err != nil || cid == "" {
+			continue
+		}
+
+		state, err := r.getContainerState(ctx, spec.Project, string(cid))
+		if err != nil {
+			logger.Get(ctx).Debugf("[dcwatch] inspecting container: %v", err)
+			continue
+		}
+		r.recordContainerEvent(dockercompose.Event{ID: string(cid), Service: spec.Service}, state)
+	}
+}
+
+// Inspects a container on the Docker daemon that the project runs on.
+func (r *Reconciler) containerInspect(ctx context.Context, project v1alpha1.DockerComposeProject, id string) (dtypes.ContainerJSON, error) {
+	if dockercompose.HasDockerEndpoint(project) {
+		return r.dcc.ContainerInspect(ctx, project, id)
+	}
+	return r.dc.ContainerInspect(ctx, id)
+}
+
+// Fetch the state of the given container and convert it into our internal model.
+func (r *Reconciler) getContainerState(ctx context.Context, project v1alpha1.DockerComposeProject, id string) (*v1alpha1.DockerContainerState, error) {
+	containerJSON, err := r.containerInspect(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerJSON.ContainerJSONBase == nil || containerJSON.ContainerJSONBase.State == nil {
+		return nil, fmt.Errorf("no state found")
+	}
+
+	return dockercompose.ToContainerState(containerJSON.ContainerJSONBase.State), nil
+}
+
 // Record the container event and re-reconcile the dockercompose service.
 func (r *Reconciler) recordContainerEvent(evt dockercompose.Event, state *v1alpha1.DockerContainerState) {
 	r.mu.Lock()
@@ -320,7 +320,7 @@ func (r *Reconciler) ForceDelete(
 		// https://app.shortcut.com/windmill/story/13147/docker-compose-down-messages-for-disabled-resources-may-be-confusing
 		return strings.HasPrefix(s, "Going to remove")
 	})
-	err := r.dcc.Rm(ctx, []v1alpha1.DockerComposeServiceSpec{spec}, out, out)
+	err := r.dcc.Rm(ctx, []v1alpha1.DockerComposeServiceSpec{spec}, false, out, out)
 	if err != nil {
 		logger.Get(ctx).Errorf("Error %s: %v", reason, err)
 	}
@@ -413,7 +413,7 @@ func (r *Reconciler) forceApplyHelper(
 		return r.recordApplyError(nn, spec, imageMaps, err, startTime)
 	}
 
-	containerJSON, err := r.dc.ContainerInspect(ctx, string(cid))
+	containerJSON, err := r.containerInspect(ctx, spec.Project, string(cid))
 	if err != nil {
 		logger.Get(ctx).Debugf("Error inspecting container %s: %v", cid, err)
 	}
@@ -1,6 +1,8 @@
 package dockercomposeservice
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -188,6 +190,202 @@ func TestContainerEvent(t *testing.T) {
 		s.ManifestTargets["fe"].State.DCRuntimeState().ContainerState.Status)
 }
 
+func TestContainerHealthEvent(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "fe"}
+	obj := v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fe",
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest: "fe",
+			},
+		},
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: "fe",
+			Project: v1alpha1.DockerComposeProject{
+				YAML: "fake-yaml",
+			},
+		},
+	}
+	f.Create(&obj)
+
+	f.dc.Containers["fake-cid"] = dtypes.ContainerState{
+		Status:  "running",
+		Running: true,
+		Health:  &dtypes.Health{Status: dtypes.Starting},
+	}
+	status := f.r.ForceApply(f.Context(), nn, obj.Spec, nil, false)
+	assert.Equal(t, "", status.ApplyError)
+	assert.Equal(t, dockercompose.HealthStatusStarting, status.ContainerState.HealthStatus)
+
+	f.dc.Containers["fake-cid"] = dtypes.ContainerState{
+		Status:  "running",
+		Running: true,
+		Health:  &dtypes.Health{Status: dtypes.Healthy},
+	}
+	f.dcc.SendEvent(dockercompose.Event{
+		Type:    dockercompose.TypeContainer,
+		Action:  "health_status: healthy",
+		ID:      "fake-cid",
+		Service: "fe",
+	})
+
+	require.Eventually(t, func() bool {
+		f.MustReconcile(nn)
+		f.MustGet(nn, &obj)
+		return obj.Status.ContainerState.HealthStatus == dockercompose.HealthStatusHealthy
+	}, time.Second, 10*time.Millisecond, "container healthy")
+}
+
+func TestContainerStateAfterEventsReconnect(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "fe"}
+	obj := v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fe",
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest: "fe",
+			},
+		},
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: "fe",
+			Project: v1alpha1.DockerComposeProject{
+				YAML: "fake-yaml",
+			},
+		},
+	}
+	f.Create(&obj)
+
+	status := f.r.ForceApply(f.Context(), nn, obj.Spec, nil, false)
+	assert.Equal(t, "", status.ApplyError)
+	assert.Equal(t, true, status.ContainerState.Running)
+
+	// The container exits while the daemon restarts, so there's no event.
+	f.dc.Containers["fake-cid"] = dtypes.ContainerState{
+		Status:     "exited",
+		Running:    false,
+		ExitCode:   1,
+		StartedAt:  "2021-09-08T19:58:01.483005100Z",
+		FinishedAt: "2021-09-08T19:58:01.483005100Z",
+	}
+	f.dcc.DropEvents(errors.New("daemon restarted"))
+
+	require.Eventually(t, func() bool {
+		f.clock.Advance(time.Second)
+		f.MustReconcile(nn)
+		f.MustGet(nn, &obj)
+		return obj.Status.ContainerState.Status == "exited"
+	}, time.Second, 10*time.Millisecond, "container exited")
+
+	assert.Equal(t, "fake-cid", obj.Status.ContainerID)
+}
+
+func TestForceApplyUpError(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "fe"}
+	spec := v1alpha1.DockerComposeServiceSpec{
+		Service: "fe",
+		Project: v1alpha1.DockerComposeProject{YAML: "fake-yaml"},
+	}
+
+	f.dcc.UpError = errors.New("port is already allocated")
+	status := f.r.ForceApply(f.Context(), nn, spec, nil, false)
+	assert.Equal(t, "port is already allocated", status.ApplyError)
+	assert.Nil(t, status.ContainerState)
+
+	// The error only happens once, so trying again fixes it.
+	status = f.r.ForceApply(f.Context(), nn, spec, nil, false)
+	assert.Equal(t, "", status.ApplyError)
+	assert.Len(t, f.dcc.UpCalls(), 2)
+}
+
+func TestForceApplyContainerIDError(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "fe"}
+	spec := v1alpha1.DockerComposeServiceSpec{
+		Service: "fe",
+		Project: v1alpha1.DockerComposeProject{YAML: "fake-yaml"},
+	}
+
+	f.dcc.ContainerIDError = errors.New("no container found")
+	status := f.r.ForceApply(f.Context(), nn, spec, nil, false)
+	assert.Equal(t, "no container found", status.ApplyError)
+}
+
+func TestForceApplyCanceledWhileDaemonIsSlow(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "fe"}
+	spec := v1alpha1.DockerComposeServiceSpec{
+		Service: "fe",
+		Project: v1alpha1.DockerComposeProject{YAML: "fake-yaml"},
+	}
+
+	f.dcc.Latency = time.Minute
+	upStarted := make(chan struct{})
+	f.dcc.LatencyStarted = upStarted
+	ctx, cancel := context.WithCancel(f.Context())
+	statusCh := make(chan v1alpha1.DockerComposeServiceStatus)
+	go func() {
+		statusCh <- f.r.ForceApply(ctx, nn, spec, nil, false)
+	}()
+
+	// Wait for Up to start waiting on the daemon.
+	select {
+	case <-upStarted:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Up to start")
+	}
+	cancel()
+
+	select {
+	case status := <-statusCh:
+		assert.Equal(t, context.Canceled.Error(), status.ApplyError)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ForceApply to return")
+	}
+	assert.Len(t, f.dcc.UpCalls(), 1)
+}
+
+func TestContainerStateAfterEventsFailToConnect(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "fe"}
+	obj := v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fe",
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest: "fe",
+			},
+		},
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: "fe",
+			Project: v1alpha1.DockerComposeProject{
+				YAML: "fake-yaml",
+			},
+		},
+	}
+	f.Create(&obj)
+
+	f.dcc.StreamEventsError = errors.New("daemon not running")
+	status := f.r.ForceApply(f.Context(), nn, obj.Spec, nil, false)
+	assert.Equal(t, "", status.ApplyError)
+
+	// The container exits before the event stream connects.
+	f.dc.Containers["fake-cid"] = dtypes.ContainerState{
+		Status:     "exited",
+		Running:    false,
+		ExitCode:   1,
+		StartedAt:  "2021-09-08T19:58:01.483005100Z",
+		FinishedAt: "2021-09-08T19:58:01.483005100Z",
+	}
+
+	require.Eventually(t, func() bool {
+		f.clock.Advance(time.Second)
+		f.MustReconcile(nn)
+		f.MustGet(nn, &obj)
+		return obj.Status.ContainerState.Status == "exited"
+	}, time.Second, 10*time.Millisecond, "container exited")
+}
+
 func TestForceDelete(t *testing.T) {
 	f := newFixture(t)
 	nn := types.NamespacedName{Name: "fe"}
@@ -214,9 +412,10 @@ func TestForceDelete(t *testing.T) {
 
 type fixture struct {
 	*fake.ControllerFixture
-	r   *Reconciler
-	dc  *docker.FakeClient
-	dcc *dockercompose.FakeDCClient
+	r     *Reconciler
+	dc    *docker.FakeClient
+	dcc   *dockercompose.FakeDCClient
+	clock clockwork.FakeClock
 }
 
 func newFixture(t *testing.T) *fixture {
@@ -225,6 +424,7 @@ func newFixture(t *testing.T) *fixture {
 	dcCli.ContainerIdOutput = "fake-cid"
 	dCli := docker.NewFakeClient()
 	clock := clockwork.NewFakeClock()
+	dcCli.SetClock(clock)
 	watcher := NewDisableSubscriber(cfb.Context(), dcCli, clock)
 	r := NewReconciler(cfb.Client, dcCli, dCli, cfb.Store, v1alpha1.NewScheme(), watcher)
 
@@ -233,6 +433,7 @@ func newFixture(t *testing.T) *fixture {
 		r:                 r,
 		dc:                dCli,
 		dcc:               dcCli,
+		clock:             clock,
 	}
 }
 
@@ -0,0 +1,73 @@
+package extension
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The file next to an extension's Tiltfile that lists the permissions
+// it needs, one per line.
+const PermissionsFileName = "PERMISSIONS"
+
+// Things that an extension can ask to do.
+//
+// Extensions that list their permissions can only use the builtins
+// that their permissions cover.
+const (
+	// Run commands on the host, like with local() or local_resource().
+	PermissionExec = "exec"
+
+	// Read or change environment variables, like with os.getenv().
+	PermissionEnv = "env"
+
+	// Download code from the network, like with v1alpha1.extension_repo().
+	PermissionNetwork = "network"
+)
+
+var Permissions = []string{PermissionExec, PermissionEnv, PermissionNetwork}
+
+// Reads the permissions that the extension in dir asks for.
+//
+// Returns false if the extension doesn't list its permissions. Blank lines
+// and lines that start with # are skipped.
+func ReadPermissions(dir string) ([]string, bool, error) {
+	contents, err := os.ReadFile(filepath.Join(dir, PermissionsFileName))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := []string{}
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !IsPermission(line) {
+			return nil, false, fmt.Errorf("invalid %s file: unknown permission %q (must be one of %s)",
+				PermissionsFileName, line, strings.Join(Permissions, ", "))
+		}
+		if !seen[line] {
+			seen[line] = true
+			result = append(result, line)
+		}
+	}
+	return result, true, scanner.Err()
+}
+
+func IsPermission(p string) bool {
+	for _, known := range Permissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/blang/semver"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -139,7 +140,66 @@ func (r *Reconciler) apply(ext *v1alpha1.Extension, repo *v1alpha1.ExtensionRepo
 		return v1alpha1.ExtensionStatus{Error: fmt.Sprintf("no extension tiltfile found at %s", absPath)}
 	}
 
-	return v1alpha1.ExtensionStatus{Path: absPath}
+	return verify(ext, repo, absPath)
+}
+
+// Checks the extension's version, digest, and signature against what
+// the extension and its repo ask for, and that its permissions are valid.
+func verify(ext *v1alpha1.Extension, repo *v1alpha1.ExtensionRepo, absPath string) v1alpha1.ExtensionStatus {
+	dir := filepath.Dir(absPath)
+	status := v1alpha1.ExtensionStatus{Path: absPath}
+
+	version, hasVersion, err := readVersion(dir)
+	if err != nil {
+		return v1alpha1.ExtensionStatus{Error: fmt.Sprintf("reading version: %v", err)}
+	}
+	if hasVersion {
+		status.Version = version.String()
+	}
+
+	_, _, err = ReadPermissions(dir)
+	if err != nil {
+		return v1alpha1.ExtensionStatus{Error: fmt.Sprintf("reading permissions: %v", err)}
+	}
+
+	if ext.Spec.Version != "" {
+		rng, err := semver.ParseRange(ext.Spec.Version)
+		if err != nil {
+			return v1alpha1.ExtensionStatus{Error: fmt.Sprintf("invalid version range %q: %v", ext.Spec.Version, err)}
+		}
+		if !hasVersion {
+			return v1alpha1.ExtensionStatus{
+				Error: fmt.Sprintf("version range %q given, but the extension has no %s file", ext.Spec.Version, versionFileName),
+			}
+		}
+		if !rng(version) {
+			return v1alpha1.ExtensionStatus{
+				Error: fmt.Sprintf("version %s doesn't match version range %q", version, ext.Spec.Version),
+			}
+		}
+	}
+
+	digest, err := dirDigest(dir)
+	if err != nil {
+		return v1alpha1.ExtensionStatus{Error: fmt.Sprintf("computing digest: %v", err)}
+	}
+	status.Sha256 = digest
+
+	if ext.Spec.Sha256 != "" && !strings.EqualFold(ext.Spec.Sha256, digest) {
+		return v1alpha1.ExtensionStatus{
+			Error: fmt.Sprintf("digest mismatch: expected sha256 %s, got %s", ext.Spec.Sha256, digest),
+		}
+	}
+
+	if len(repo.Spec.TrustedKeys) > 0 {
+		err := verifySignature(dir, digest, repo.Spec.TrustedKeys)
+		if err != nil {
+			return v1alpha1.ExtensionStatus{Error: fmt.Sprintf("verifying signature: %v", err)}
+		}
+		status.Verified = true
+	}
+
+	return status
 }
 
 // Update the status. Returns true if the status changed.
@@ -1,6 +1,8 @@
 package extension
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"testing"
 
@@ -165,6 +167,172 @@ func TestExtensionBeforeRepo(t *testing.T) {
 	assert.Equal(t, "", f.Stdout())
 }
 
+func TestVersionRange(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+	f.WriteFile(f.JoinPath("my-repo", "my-ext", "VERSION"), "1.2.3\n")
+
+	nn := types.NamespacedName{Name: "ext"}
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName: "my-repo",
+			RepoPath: "my-ext",
+			Version:  ">=1.2.0 <2.0.0",
+		},
+	}
+	f.Create(&ext)
+
+	f.MustGet(nn, &ext)
+	assert.Equal(t, "", ext.Status.Error)
+	assert.Equal(t, "1.2.3", ext.Status.Version)
+
+	ext.Spec.Version = "2.x"
+	f.Update(&ext)
+	f.MustGet(nn, &ext)
+	assert.Equal(t, `version 1.2.3 doesn't match version range "2.x"`, ext.Status.Error)
+	assert.Equal(t, "", ext.Status.Path)
+}
+
+func TestVersionRangeWithoutVersion(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+
+	nn := types.NamespacedName{Name: "ext"}
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName: "my-repo",
+			RepoPath: "my-ext",
+			Version:  "1.x",
+		},
+	}
+	f.Create(&ext)
+
+	f.MustGet(nn, &ext)
+	assert.Equal(t, `version range "1.x" given, but the extension has no VERSION file`, ext.Status.Error)
+}
+
+func TestSha256(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+
+	nn := types.NamespacedName{Name: "ext"}
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName: "my-repo",
+			RepoPath: "my-ext",
+		},
+	}
+	f.Create(&ext)
+	f.MustGet(nn, &ext)
+	digest := ext.Status.Sha256
+	require.Len(t, digest, 64)
+
+	ext.Spec.Sha256 = digest
+	f.Update(&ext)
+	f.MustGet(nn, &ext)
+	assert.Equal(t, "", ext.Status.Error)
+
+	// Changing the extension changes its digest.
+	f.WriteFile(f.JoinPath("my-repo", "my-ext", "Tiltfile"), "print('goodbye-world')")
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ext)
+	assert.Contains(t, ext.Status.Error, fmt.Sprintf("digest mismatch: expected sha256 %s, got ", digest))
+	assert.Equal(t, "", ext.Status.Path)
+}
+
+func TestDigestCoversFileNames(t *testing.T) {
+	f := newFixture(t)
+	f.WriteFile(f.JoinPath("a", "Tiltfile"), "print('hello')")
+	f.WriteFile(f.JoinPath("b", "Tiltfile2"), "print('hello')")
+	f.WriteFile(f.JoinPath("c", "Tiltfile"), "print('hello')")
+	f.WriteFile(f.JoinPath("c", ".git", "HEAD"), "ref: refs/heads/main")
+	f.WriteFile(f.JoinPath("c", "extension.sig"), "signature")
+
+	a, err := dirDigest(f.JoinPath("a"))
+	require.NoError(t, err)
+	b, err := dirDigest(f.JoinPath("b"))
+	require.NoError(t, err)
+	c, err := dirDigest(f.JoinPath("c"))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, c)
+}
+
+func TestSignature(t *testing.T) {
+	f := newFixture(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	f.setupRepoWithKeys(base64.StdEncoding.EncodeToString(pub))
+
+	nn := types.NamespacedName{Name: "ext"}
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName: "my-repo",
+			RepoPath: "my-ext",
+		},
+	}
+	f.Create(&ext)
+	f.MustGet(nn, &ext)
+	assert.Equal(t, "verifying signature: not signed: repo requires a signature in extension.sig", ext.Status.Error)
+
+	digest, err := dirDigest(f.JoinPath("my-repo", "my-ext"))
+	require.NoError(t, err)
+
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	f.WriteFile(f.JoinPath("my-repo", "my-ext", "extension.sig"),
+		base64.StdEncoding.EncodeToString(ed25519.Sign(otherPriv, []byte(digest))))
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ext)
+	assert.Equal(t, "verifying signature: signature in extension.sig doesn't match any trusted key of the repo", ext.Status.Error)
+
+	f.WriteFile(f.JoinPath("my-repo", "my-ext", "extension.sig"),
+		base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(digest))))
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ext)
+	assert.Equal(t, "", ext.Status.Error)
+	assert.True(t, ext.Status.Verified)
+	assert.Equal(t, digest, ext.Status.Sha256)
+}
+
+func TestInvalidPermissions(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+	f.WriteFile(f.JoinPath("my-repo", "my-ext", "PERMISSIONS"), "# comment\nexec\nroot\n")
+
+	nn := types.NamespacedName{Name: "ext"}
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName: "my-repo",
+			RepoPath: "my-ext",
+		},
+	}
+	f.Create(&ext)
+
+	f.MustGet(nn, &ext)
+	assert.Equal(t, `reading permissions: invalid PERMISSIONS file: unknown permission "root" (must be one of exec, env, network)`, ext.Status.Error)
+
+	f.WriteFile(f.JoinPath("my-repo", "my-ext", "PERMISSIONS"), "# comment\nexec\n")
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ext)
+	assert.Equal(t, "", ext.Status.Error)
+}
+
 type fixture struct {
 	*fake.ControllerFixture
 	*tempdir.TempDirFixture
@@ -189,6 +357,11 @@ func newFixture(t *testing.T) *fixture {
 }
 
 func (f *fixture) setupRepo() *v1alpha1.ExtensionRepo {
+	return f.setupRepoWithKeys()
+}
+
+// Sets up a repo whose extensions must be signed with one of the keys.
+func (f *fixture) setupRepoWithKeys(trustedKeys ...string) *v1alpha1.ExtensionRepo {
 	p := f.JoinPath("my-repo", "my-ext", "Tiltfile")
 	f.WriteFile(p, "print('hello-world')")
 
@@ -197,7 +370,8 @@ func (f *fixture) setupRepo() *v1alpha1.ExtensionRepo {
 			Name: "my-repo",
 		},
 		Spec: v1alpha1.ExtensionRepoSpec{
-			URL: fmt.Sprintf("file://%s", f.JoinPath("my-repo")),
+			URL:         fmt.Sprintf("file://%s", f.JoinPath("my-repo")),
+			TrustedKeys: trustedKeys,
 		},
 		Status: v1alpha1.ExtensionRepoStatus{
 			Path: f.JoinPath("my-repo"),
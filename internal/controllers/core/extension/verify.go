@@ -0,0 +1,129 @@
+package extension
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// The file next to an extension's Tiltfile that holds its version.
+const versionFileName = "VERSION"
+
+// The file next to an extension's Tiltfile that holds its signature.
+const signatureFileName = "extension.sig"
+
+// The SHA-256 digest of an extension directory, in hex.
+//
+// The digest covers the path and contents of every file in the directory,
+// like the output of `sha256sum` over the sorted file list, so that renaming
+// a file changes the digest too. Git metadata and the signature file
+// are left out.
+func dirDigest(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == signatureFileName {
+			return nil
+		}
+
+		var sum string
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			sum = sha256Hex(strings.NewReader("symlink:" + filepath.ToSlash(target)))
+		case d.Type().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			sum = sha256Hex(f)
+			_ = f.Close()
+		default:
+			return nil
+		}
+
+		_, err = fmt.Fprintf(h, "%s  %s\n", sum, rel)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sha256Hex(r io.Reader) string {
+	h := sha256.New()
+	_, _ = io.Copy(h, r)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Reads the version of the extension in dir.
+// Returns false if the extension doesn't have a version.
+func readVersion(dir string) (semver.Version, bool, error) {
+	contents, err := os.ReadFile(filepath.Join(dir, versionFileName))
+	if os.IsNotExist(err) {
+		return semver.Version{}, false, nil
+	}
+	if err != nil {
+		return semver.Version{}, false, err
+	}
+
+	v, err := semver.ParseTolerant(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return semver.Version{}, false, fmt.Errorf("invalid %s file: %v", versionFileName, err)
+	}
+	return v, true, nil
+}
+
+// Checks that the extension in dir was signed by one of the keys.
+func verifySignature(dir string, digest string, keys []string) error {
+	contents, err := os.ReadFile(filepath.Join(dir, signatureFileName))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("not signed: repo requires a signature in %s", signatureFileName)
+	}
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return fmt.Errorf("invalid %s file: %v", signatureFileName, err)
+	}
+
+	for _, key := range keys {
+		pub, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			continue
+		}
+		if ed25519.Verify(ed25519.PublicKey(pub), []byte(digest), sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature in %s doesn't match any trusted key of the repo", signatureFileName)
+}
@@ -153,6 +153,15 @@ func (r *Reconciler) apply(ctx context.Context, nn types.NamespacedName, repo *v
 		r.repoStates[nn] = state
 	}
 
+	// A request to update the repo fetches it again, even if it's
+	// already been fetched during this session.
+	updateRequest := repo.Annotations[v1alpha1.AnnotationExtensionRepoUpdate]
+	if updateRequest != "" && updateRequest != state.lastUpdateRequest {
+		state.lastUpdateRequest = updateRequest
+		state.lastSuccessfulDestPath = ""
+		state.backoff = 0
+	}
+
 	// Keep track of the Result in case it contains Requeue instructions.
 	var result ctrl.Result
 	if strings.HasPrefix(repo.Spec.URL, "file://") {
@@ -351,6 +360,9 @@ type repoState struct {
 	backoff                time.Duration
 	lastSuccessfulDestPath string
 
+	// The last value of the update annotation that we've handled.
+	lastUpdateRequest string
+
 	status v1alpha1.ExtensionRepoStatus
 }
 
@@ -178,6 +178,39 @@ func TestRepoAlwaysSyncHead(t *testing.T) {
 	f.assertSteadyState(&repo)
 }
 
+func TestUpdateRequest(t *testing.T) {
+	f := newFixture(t)
+
+	key := types.NamespacedName{Name: "default"}
+	repo := v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: key.Name,
+		},
+		Spec: v1alpha1.ExtensionRepoSpec{
+			URL: "https://github.com/tilt-dev/tilt-extensions",
+		},
+	}
+	f.Create(&repo)
+	f.MustGet(key, &repo)
+	assert.Equal(t, 1, f.dlr.downloadCount)
+	assert.Equal(t, "fake-head", repo.Status.CheckoutRef)
+
+	f.dlr.headRef = "new-head"
+	metav1.SetMetaDataAnnotation(&repo.ObjectMeta, v1alpha1.AnnotationExtensionRepoUpdate, "1")
+	f.Update(&repo)
+	f.MustGet(key, &repo)
+	assert.Equal(t, 2, f.dlr.downloadCount)
+	assert.Equal(t, "new-head", repo.Status.CheckoutRef)
+
+	// The same request isn't handled twice.
+	f.MustReconcile(key)
+	assert.Equal(t, 2, f.dlr.downloadCount)
+
+	metav1.SetMetaDataAnnotation(&repo.ObjectMeta, v1alpha1.AnnotationExtensionRepoUpdate, "2")
+	f.Update(&repo)
+	assert.Equal(t, 3, f.dlr.downloadCount)
+}
+
 func TestStale(t *testing.T) {
 	f := newFixture(t)
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/jonboulle/clockwork"
 
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/watch"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -102,6 +103,7 @@ func (w *watcher) recordEvent(fsEvents []watch.FileEvent) {
 		event.SeenFiles = append(event.SeenFiles, fsEvent.Path())
 	}
 	if len(event.SeenFiles) != 0 {
+		metrics.FileWatchEventsTotal.WithLabelValues(w.name.Name).Add(float64(len(event.SeenFiles)))
 		w.status.LastEventTime = *now.DeepCopy()
 		w.status.FileEvents = append(w.status.FileEvents, event)
 		if len(w.status.FileEvents) > MaxFileEventHistory {
@@ -0,0 +1,227 @@
+package healthprobe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/tilt-dev/probe/pkg/probe"
+	"github.com/tilt-dev/probe/pkg/prober"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/controllers/indexer"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/healthprobes"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Runs each HealthProbe in the background, and writes its results to
+// its status.
+type Reconciler struct {
+	globalCtx     context.Context
+	client        ctrlclient.Client
+	st            store.RStore
+	proberManager cmd.ProberManager
+	clock         clockwork.Clock
+	requeuer      *indexer.Requeuer
+
+	mu   sync.Mutex
+	runs map[types.NamespacedName]*probeRun
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+var _ store.TearDowner = &Reconciler{}
+
+func NewReconciler(ctx context.Context, client ctrlclient.Client, st store.RStore, proberManager cmd.ProberManager, clock clockwork.Clock) *Reconciler {
+	return &Reconciler{
+		globalCtx:     ctx,
+		client:        client,
+		st:            st,
+		proberManager: proberManager,
+		clock:         clock,
+		requeuer:      indexer.NewRequeuer(),
+		runs:          make(map[types.NamespacedName]*probeRun),
+	}
+}
+
+func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.HealthProbe{}).
+		Watches(r.requeuer, handler.Funcs{})
+
+	return b, nil
+}
+
+// A probe worker running for one HealthProbe.
+type probeRun struct {
+	spec   v1alpha1.HealthProbeSpec
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	status v1alpha1.HealthProbeStatus
+}
+
+func (p *probeRun) currentStatus() v1alpha1.HealthProbeStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return *p.status.DeepCopy()
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nn := req.NamespacedName
+	obj := &v1alpha1.HealthProbe{}
+	err := r.client.Get(ctx, nn, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if apierrors.IsNotFound(err) || obj.ObjectMeta.DeletionTimestamp != nil {
+		r.stop(nn)
+		r.st.Dispatch(healthprobes.NewHealthProbeDeleteAction(nn.Name))
+		return ctrl.Result{}, nil
+	}
+
+	run, ok := r.runs[nn]
+	if !ok || !apicmp.DeepEqual(run.spec, obj.Spec) {
+		r.stop(nn)
+		run = r.start(nn, obj.Spec)
+	}
+
+	status := run.currentStatus()
+	if !apicmp.DeepEqual(obj.Status, status) {
+		update := obj.DeepCopy()
+		update.Status = status
+		err := r.client.Status().Update(ctx, update)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		obj = update
+	}
+
+	r.st.Dispatch(healthprobes.NewHealthProbeUpsertAction(obj))
+	return ctrl.Result{}, nil
+}
+
+// Starts probing with a new spec. The probe starts out not ready.
+func (r *Reconciler) start(nn types.NamespacedName, spec v1alpha1.HealthProbeSpec) *probeRun {
+	run := &probeRun{
+		spec:   *spec.DeepCopy(),
+		status: v1alpha1.HealthProbeStatus{Result: v1alpha1.HealthProbeResultUnknown},
+	}
+	r.runs[nn] = run
+
+	worker, err := cmd.ProbeWorkerFromSpec(r.proberManager, &run.spec.Probe, r.handleProbeResultFunc(nn, run))
+	if err != nil {
+		run.status.Error = fmt.Sprintf("Invalid health probe: %v", err)
+		return run
+	}
+
+	ctx, cancel := context.WithCancel(r.globalCtx)
+	run.cancel = cancel
+	go worker.Run(ctx)
+	return run
+}
+
+func (r *Reconciler) stop(nn types.NamespacedName) {
+	run, ok := r.runs[nn]
+	if !ok {
+		return
+	}
+	if run.cancel != nil {
+		run.cancel()
+	}
+	delete(r.runs, nn)
+}
+
+func (r *Reconciler) TearDown(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for nn := range r.runs {
+		r.stop(nn)
+	}
+}
+
+// Records each probe result, and writes the status when it changes.
+//
+// Ready only changes once the probe passes the success or failure
+// threshold, but the result and message are from the latest probe that
+// returned something different from the one before it. Recording every
+// probe would update the object every period.
+func (r *Reconciler) handleProbeResultFunc(nn types.NamespacedName, run *probeRun) probe.ResultFunc {
+	return func(result prober.Result, statusChanged bool, output string, err error) {
+		message := output
+		if err != nil {
+			message = err.Error()
+		}
+
+		run.mu.Lock()
+		defer run.mu.Unlock()
+
+		changed := false
+		if statusChanged {
+			ready := result == prober.Success || result == prober.Warning
+			if ready != run.status.Ready {
+				if ready {
+					run.status.LastReadyTime = apis.NewMicroTime(r.clock.Now())
+				}
+				run.status.Ready = ready
+				r.logTransition(nn, run.spec.Resource, ready, message)
+				changed = true
+			}
+		}
+
+		newResult := probeResult(result)
+		if newResult != run.status.Result {
+			run.status.Result = newResult
+			run.status.Message = message
+			changed = true
+		}
+
+		if changed {
+			r.requeuer.Add(nn)
+		}
+	}
+}
+
+// Logs to the resource's log when it starts or stops passing the probe.
+func (r *Reconciler) logTransition(nn types.NamespacedName, resource string, ready bool, message string) {
+	spanID := logstore.SpanID(fmt.Sprintf("healthprobe:%s", nn.Name))
+	ctx := store.WithManifestLogHandler(r.globalCtx, r.st, model.ManifestName(resource), spanID)
+	if ready {
+		logger.Get(ctx).Infof("[health probe %s] passing", nn.Name)
+		return
+	}
+	if message != "" {
+		logger.Get(ctx).Warnf("[health probe %s] failing: %s", nn.Name, message)
+		return
+	}
+	logger.Get(ctx).Warnf("[health probe %s] failing", nn.Name)
+}
+
+func probeResult(result prober.Result) v1alpha1.HealthProbeResult {
+	switch result {
+	case prober.Success:
+		return v1alpha1.HealthProbeResultSuccess
+	case prober.Warning:
+		return v1alpha1.HealthProbeResultWarning
+	case prober.Failure:
+		return v1alpha1.HealthProbeResultFailure
+	}
+	return v1alpha1.HealthProbeResultUnknown
+}
@@ -0,0 +1,171 @@
+package healthprobe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilt-dev/probe/pkg/prober"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/store/healthprobes"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+var probeName = types.NamespacedName{Name: "fe-health"}
+
+func TestReadyThenFailing(t *testing.T) {
+	f := newFixture(t)
+	f.createProbe(v1alpha1.Probe{
+		Handler:          v1alpha1.Handler{TCPSocket: &v1alpha1.TCPSocketAction{Port: 8080}},
+		PeriodSeconds:    1,
+		FailureThreshold: 1,
+	})
+
+	status := f.waitForStatus(func(s v1alpha1.HealthProbeStatus) bool { return s.Ready })
+	assert.Equal(t, v1alpha1.HealthProbeResultSuccess, status.Result)
+	assert.Equal(t, "ok", status.Message)
+	assert.False(t, status.LastReadyTime.IsZero())
+	assert.Equal(t, "localhost:8080", f.pm.tcpAddr())
+
+	f.pm.setResult(prober.Failure, "connection refused")
+	status = f.waitForStatus(func(s v1alpha1.HealthProbeStatus) bool { return !s.Ready })
+	assert.Equal(t, v1alpha1.HealthProbeResultFailure, status.Result)
+	assert.Equal(t, "connection refused", status.Message)
+	assert.False(t, status.LastReadyTime.IsZero())
+
+	var upsert healthprobes.HealthProbeUpsertAction
+	for _, a := range f.Store.Actions() {
+		if action, ok := a.(healthprobes.HealthProbeUpsertAction); ok {
+			upsert = action
+		}
+	}
+	require.NotNil(t, upsert.HealthProbe)
+	assert.Equal(t, "fe", upsert.HealthProbe.Spec.Resource)
+	assert.False(t, upsert.HealthProbe.Status.Ready)
+}
+
+func TestInvalidProbe(t *testing.T) {
+	f := newFixture(t)
+	f.createProbe(v1alpha1.Probe{
+		Handler: v1alpha1.Handler{HTTPGet: &v1alpha1.HTTPGetAction{Port: 0}},
+	})
+	f.MustReconcile(probeName)
+
+	status := f.status()
+	assert.False(t, status.Ready)
+	assert.Equal(t, v1alpha1.HealthProbeResultUnknown, status.Result)
+	assert.Equal(t, "Invalid health probe: port number out of range: 0", status.Error)
+}
+
+func TestDelete(t *testing.T) {
+	f := newFixture(t)
+	f.createProbe(v1alpha1.Probe{
+		Handler: v1alpha1.Handler{TCPSocket: &v1alpha1.TCPSocketAction{Port: 8080}},
+	})
+	f.MustReconcile(probeName)
+	require.Len(t, f.r.runs, 1)
+
+	f.Delete(&v1alpha1.HealthProbe{ObjectMeta: metav1.ObjectMeta{Name: probeName.Name}})
+	f.MustReconcile(probeName)
+	assert.Len(t, f.r.runs, 0)
+
+	actions := f.Store.Actions()
+	assert.Equal(t, healthprobes.NewHealthProbeDeleteAction(probeName.Name), actions[len(actions)-1])
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	r  *Reconciler
+	pm *fakeProberManager
+}
+
+func newFixture(t *testing.T) *fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	pm := &fakeProberManager{result: prober.Success, output: "ok"}
+	r := NewReconciler(cfb.Context(), cfb.Client, cfb.Store, pm, clockwork.NewRealClock())
+	return &fixture{
+		ControllerFixture: cfb.Build(r),
+		r:                 r,
+		pm:                pm,
+	}
+}
+
+func (f *fixture) createProbe(p v1alpha1.Probe) {
+	f.Create(&v1alpha1.HealthProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: probeName.Name},
+		Spec: v1alpha1.HealthProbeSpec{
+			Resource: "fe",
+			Probe:    p,
+		},
+	})
+}
+
+func (f *fixture) status() v1alpha1.HealthProbeStatus {
+	var hp v1alpha1.HealthProbe
+	f.MustGet(probeName, &hp)
+	return hp.Status
+}
+
+// Probes run in the background, so reconcile until the status catches up.
+func (f *fixture) waitForStatus(cond func(s v1alpha1.HealthProbeStatus) bool) v1alpha1.HealthProbeStatus {
+	var status v1alpha1.HealthProbeStatus
+	require.Eventually(f.T(), func() bool {
+		f.MustReconcile(probeName)
+		status = f.status()
+		return cond(status)
+	}, 5*time.Second, 50*time.Millisecond)
+	return status
+}
+
+// Returns whatever result the test sets, for every kind of probe.
+type fakeProberManager struct {
+	mu     sync.Mutex
+	result prober.Result
+	output string
+	addr   string
+}
+
+func (m *fakeProberManager) setResult(result prober.Result, output string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.result = result
+	m.output = output
+}
+
+func (m *fakeProberManager) tcpAddr() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addr
+}
+
+func (m *fakeProberManager) probe(_ context.Context) (prober.Result, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.result, m.output, nil
+}
+
+func (m *fakeProberManager) HTTPGet(u *url.URL, headers http.Header) prober.ProberFunc {
+	return m.probe
+}
+
+func (m *fakeProberManager) TCPSocket(host string, port int) prober.ProberFunc {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addr = net.JoinHostPort(host, strconv.Itoa(port))
+	return m.probe
+}
+
+func (m *fakeProberManager) Exec(name string, args ...string) prober.ProberFunc {
+	return m.probe
+}
@@ -9,7 +9,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	errorutil "k8s.io/apimachinery/pkg/util/errors"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
@@ -87,7 +89,7 @@ func (r *Reconciler) toDesiredPortForward(kd *v1alpha1.KubernetesDiscovery) (*v1
 		return nil, nil
 	}
 
-	pod := pickBestPortForwardPod(kd)
+	pod := PickBestPortForwardPod(kd)
 	if pod == nil {
 		return nil, nil
 	}
@@ -147,7 +149,7 @@ func populateContainerPorts(pft *v1alpha1.PortForwardTemplateSpec, pod *v1alpha1
 // We can only portforward to one pod at a time.
 // So pick the "best" pod to portforward to.
 // May be nil if there is no eligible pod.
-func pickBestPortForwardPod(kd *v1alpha1.KubernetesDiscovery) *v1alpha1.Pod {
+func PickBestPortForwardPod(kd *v1alpha1.KubernetesDiscovery) *v1alpha1.Pod {
 	var bestPod *v1alpha1.Pod
 	for _, pod := range kd.Status.Pods {
 		pod := pod
@@ -224,3 +226,59 @@ func warnDeprecatedImplicitForwards(ctx context.Context, kd *v1alpha1.Kubernetes
 		}
 	}
 }
+
+// Reconcile all the port forwards that follow this KD's pod, like the ones
+// that users add at runtime.
+//
+// Unlike owned port forwards, we never create or delete these. We only point
+// them at the current best pod. The KD may be nil if it's being deleted.
+func (r *Reconciler) manageFollowingPortForwards(ctx context.Context, nn types.NamespacedName, kd *v1alpha1.KubernetesDiscovery) error {
+	if kd == nil {
+		return nil
+	}
+
+	pod := PickBestPortForwardPod(kd)
+	if pod == nil {
+		return nil
+	}
+
+	var pfList v1alpha1.PortForwardList
+	err := r.ctrlClient.List(ctx, &pfList, ctrlclient.MatchingLabels{v1alpha1.LabelPortForwardDiscovery: nn.Name})
+	if err != nil {
+		return fmt.Errorf("failed to fetch PortForward objects following KubernetesDiscovery %s: %v",
+			nn.Name, err)
+	}
+
+	errs := []error{}
+	for _, existingPF := range pfList.Items {
+		if metav1.GetControllerOf(&existingPF) != nil {
+			continue
+		}
+
+		if existingPF.Spec.PodName == pod.Name &&
+			existingPF.Spec.Namespace == pod.Namespace &&
+			existingPF.Spec.Cluster == kd.Spec.Cluster {
+			continue
+		}
+
+		updatedPF := existingPF.DeepCopy()
+		updatedPF.Spec.PodName = pod.Name
+		updatedPF.Spec.Namespace = pod.Namespace
+		updatedPF.Spec.Cluster = kd.Spec.Cluster
+		err := r.ctrlClient.Update(ctx, updatedPF)
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("updating portforward %s: %v", existingPF.Name, err))
+		}
+	}
+	return errorutil.NewAggregate(errs)
+}
+
+// Reconcile the KD that a port forward follows, so that a new port forward
+// moves to the KD's current pod right away.
+func enqueueFollowedDiscovery(obj ctrlclient.Object) []reconcile.Request {
+	name := obj.GetLabels()[v1alpha1.LabelPortForwardDiscovery]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
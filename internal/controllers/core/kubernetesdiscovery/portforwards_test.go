@@ -227,3 +227,53 @@ func TestPortForwardNotForPending(t *testing.T) {
 
 	assert.True(t, f.Get(types.NamespacedName{Name: "kd-pod"}, &pf))
 }
+
+func TestPortForwardFollowsPod(t *testing.T) {
+	f := newFixture(t)
+
+	pod := f.buildPod("pod-ns", "pod", nil, nil)
+	key := types.NamespacedName{Name: "kd"}
+	kd := &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "kd"},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{
+				{
+					UID:       string(pod.UID),
+					Namespace: pod.Namespace,
+					Name:      pod.Name,
+				},
+			},
+		},
+	}
+
+	f.Create(kd)
+	f.injectK8sObjects(*kd, pod)
+	f.requireObservedPods(key, ancestorMap{pod.UID: pod.UID}, nil)
+
+	pfKey := types.NamespacedName{Name: "kd-adhoc-8080-8000"}
+	err := f.Client.Create(f.Context(), &v1alpha1.PortForward{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   pfKey.Name,
+			Labels: map[string]string{v1alpha1.LabelPortForwardDiscovery: "kd"},
+		},
+		Spec: v1alpha1.PortForwardSpec{
+			PodName:   "old-pod",
+			Namespace: "old-ns",
+			Forwards:  []v1alpha1.Forward{{LocalPort: 8000, ContainerPort: 8080}},
+		},
+	})
+	require.NoError(t, err)
+
+	f.MustReconcile(key)
+
+	var pf v1alpha1.PortForward
+	f.MustGet(pfKey, &pf)
+	assert.Equal(t, "pod", pf.Spec.PodName)
+	assert.Equal(t, "pod-ns", pf.Spec.Namespace)
+	assert.Equal(t, []v1alpha1.Forward{{LocalPort: 8000, ContainerPort: 8080}}, pf.Spec.Forwards)
+
+	// Following port forwards outlive their KD.
+	f.Delete(kd)
+	f.MustReconcile(key)
+	assert.True(t, f.Get(pfKey, &pf))
+}
@@ -111,6 +111,8 @@ func (w *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 		Owns(&v1alpha1.PortForward{}).
 		Watches(&source.Kind{Type: &v1alpha1.Cluster{}},
 			handler.EnqueueRequestsFromMapFunc(w.indexer.Enqueue)).
+		Watches(&source.Kind{Type: &v1alpha1.PortForward{}},
+			handler.EnqueueRequestsFromMapFunc(enqueueFollowedDiscovery)).
 		Watches(w.requeuer, handler.Funcs{})
 	return b, nil
 }
@@ -694,6 +696,10 @@ func (w *Reconciler) manageOwnedObjects(ctx context.Context, nn types.Namespaced
 	if err := w.manageOwnedPortForwards(ctx, nn, kd); err != nil {
 		return err
 	}
+
+	if err := w.manageFollowingPortForwards(ctx, nn, kd); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,255 @@
+package logsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/hud"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+const sendTimeout = 10 * time.Second
+
+// Sends batches of lines to where a LogSink points.
+//
+// Only the sink's send loop calls a backend, so backends don't need locks.
+type backend interface {
+	send(ctx context.Context, lines []hud.JSONLogLine) error
+	close()
+}
+
+func newBackend(spec v1alpha1.LogSinkSpec) (backend, error) {
+	switch spec.Type {
+	case v1alpha1.LogSinkTypeLoki:
+		return &lokiBackend{
+			url:    strings.TrimSuffix(spec.Endpoint, "/") + "/loki/api/v1/push",
+			labels: spec.Labels,
+			client: &http.Client{Timeout: sendTimeout},
+		}, nil
+	case v1alpha1.LogSinkTypeFluentd:
+		return &fluentdBackend{address: spec.Endpoint, labels: spec.Labels}, nil
+	case v1alpha1.LogSinkTypeFile:
+		return &fileBackend{path: spec.Endpoint, labels: spec.Labels}, nil
+	}
+	return nil, fmt.Errorf("unknown log sink type %q", spec.Type)
+}
+
+// The fields of a line, with the sink's labels added.
+//
+// If a label has the same name as one of the line's own fields,
+// the line's field wins.
+func record(line hud.JSONLogLine, labels map[string]string) map[string]string {
+	r := make(map[string]string, len(labels)+5)
+	for k, v := range labels {
+		r[k] = v
+	}
+	if line.Resource != "" {
+		r["resource"] = line.Resource
+	}
+	r["spanId"] = line.SpanID
+	r["level"] = line.Level
+	r["text"] = line.Text
+	return r
+}
+
+// Appends each line to a file as a JSON object.
+type fileBackend struct {
+	path   string
+	labels map[string]string
+	f      *os.File
+}
+
+func (b *fileBackend) send(ctx context.Context, lines []hud.JSONLogLine) error {
+	if b.f == nil {
+		err := os.MkdirAll(filepath.Dir(b.path), 0755)
+		if err != nil {
+			return err
+		}
+		b.f, err = os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		r := record(line, b.labels)
+		r["time"] = line.Time.Format(time.RFC3339Nano)
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	_, err := b.f.Write(buf.Bytes())
+	if err != nil {
+		// Reopen the file on the next try, in case it was moved away.
+		b.close()
+	}
+	return err
+}
+
+func (b *fileBackend) close() {
+	if b.f != nil {
+		_ = b.f.Close()
+		b.f = nil
+	}
+}
+
+// Pushes lines to Loki's push API.
+//
+// Each resource and level is its own stream, so Loki can index them.
+// https://grafana.com/docs/loki/latest/api/#push-log-entries-to-loki
+type lokiBackend struct {
+	url    string
+	labels map[string]string
+	client *http.Client
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (b *lokiBackend) send(ctx context.Context, lines []hud.JSONLogLine) error {
+	streams := make(map[string]*lokiStream)
+	var keys []string
+	for _, line := range lines {
+		key := line.Resource + "\x00" + line.Level
+		stream, ok := streams[key]
+		if !ok {
+			labels := map[string]string{"job": "tilt"}
+			for k, v := range b.labels {
+				labels[k] = v
+			}
+			if line.Resource != "" {
+				labels["resource"] = line.Resource
+			}
+			labels["level"] = line.Level
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(line.Time.UnixNano(), 10),
+			line.Text,
+		})
+	}
+	sort.Strings(keys)
+
+	var push lokiPush
+	for _, key := range keys {
+		push.Streams = append(push.Streams, *streams[key])
+	}
+	body, err := json.Marshal(push)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("loki push: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (b *lokiBackend) close() {}
+
+// Sends lines to a fluentd forward input, in Forward mode:
+// one message per tag, holding all of the tag's lines.
+// https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1
+//
+// Lines are tagged tilt.<resource>, or just tilt if they don't belong
+// to a resource.
+type fluentdBackend struct {
+	address string
+	labels  map[string]string
+
+	// nil until the first send, and after a send fails.
+	conn net.Conn
+}
+
+func (b *fluentdBackend) send(ctx context.Context, lines []hud.JSONLogLine) error {
+	if b.conn == nil {
+		var d net.Dialer
+		dialCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		conn, err := d.DialContext(dialCtx, "tcp", b.address)
+		cancel()
+		if err != nil {
+			return err
+		}
+		b.conn = conn
+	}
+
+	var tags []string
+	byTag := make(map[string][]hud.JSONLogLine)
+	for _, line := range lines {
+		tag := "tilt"
+		if line.Resource != "" {
+			tag = "tilt." + line.Resource
+		}
+		if _, ok := byTag[tag]; !ok {
+			tags = append(tags, tag)
+		}
+		byTag[tag] = append(byTag[tag], line)
+	}
+
+	var buf bytes.Buffer
+	for _, tag := range tags {
+		tagLines := byTag[tag]
+		e := msgpackEncoder{buf: &buf}
+		e.arrayHeader(2)
+		e.string(tag)
+		e.arrayHeader(len(tagLines))
+		for _, line := range tagLines {
+			e.arrayHeader(2)
+			e.eventTime(line.Time)
+			e.stringMap(record(line, b.labels))
+		}
+	}
+
+	_ = b.conn.SetWriteDeadline(time.Now().Add(sendTimeout))
+	_, err := b.conn.Write(buf.Bytes())
+	if err != nil {
+		// A partial write leaves the stream unreadable, so start over
+		// on a new connection.
+		b.close()
+	}
+	return err
+}
+
+func (b *fluentdBackend) close() {
+	if b.conn != nil {
+		_ = b.conn.Close()
+		b.conn = nil
+	}
+}
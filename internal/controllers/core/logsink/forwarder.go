@@ -0,0 +1,138 @@
+package logsink
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Reads new logs from the store, and hands each LogSink the lines it wants.
+//
+// The Reconciler decides which sinks run; the Forwarder feeds them.
+type Forwarder struct {
+	// Sinks outlive any one reconcile, so they run under the Forwarder's
+	// context, which is cancelled when Tilt exits.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	sinks map[types.NamespacedName]*sink
+}
+
+var _ store.SubscriberLifecycle = &Forwarder{}
+
+func NewForwarder() *Forwarder {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Forwarder{
+		ctx:    ctx,
+		cancel: cancel,
+		sinks:  make(map[types.NamespacedName]*sink),
+	}
+}
+
+// Starts a sink for the LogSink, or replaces its sink if the spec changed.
+//
+// A new sink starts from the oldest logs Tilt still has. A replaced sink
+// picks up where the old one left off, so no lines are sent twice.
+func (f *Forwarder) upsert(nn types.NamespacedName, obj *v1alpha1.LogSink) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old, ok := f.sinks[nn]
+	if ok && apicmp.DeepEqual(old.obj.Spec, obj.Spec) {
+		return nil
+	}
+
+	s, err := newSink(obj)
+	if err != nil {
+		return err
+	}
+	if ok {
+		s.checkpoint = old.checkpoint
+		s.builder = old.builder
+		delete(f.sinks, nn)
+		go old.stop()
+	}
+	f.sinks[nn] = s
+	s.start(f.ctx)
+	return nil
+}
+
+// Stops the sink of a deleted LogSink.
+func (f *Forwarder) remove(nn types.NamespacedName) {
+	f.mu.Lock()
+	s, ok := f.sinks[nn]
+	delete(f.sinks, nn)
+	f.mu.Unlock()
+
+	if ok {
+		s.add(s.builder.Flush())
+		go s.stop()
+	}
+}
+
+// The status of the LogSink's sink, if it has one.
+func (f *Forwarder) status(nn types.NamespacedName) (v1alpha1.LogSinkStatus, bool) {
+	f.mu.Lock()
+	s, ok := f.sinks[nn]
+	f.mu.Unlock()
+	if !ok {
+		return v1alpha1.LogSinkStatus{}, false
+	}
+	return s.currentStatus(), true
+}
+
+func (f *Forwarder) SetUp(ctx context.Context, st store.RStore) error {
+	return nil
+}
+
+func (f *Forwarder) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sinks) == 0 {
+		return nil
+	}
+
+	state := st.RLockState()
+	segments := make(map[*sink][]logstore.StreamSegment, len(f.sinks))
+	for _, s := range f.sinks {
+		segments[s], s.checkpoint = state.LogStore.SegmentsSince(s.checkpoint, logstore.SegmentOptions{
+			MinLevel: s.minLevel,
+		})
+	}
+	st.RUnlockState()
+
+	for s, segs := range segments {
+		s.addSegments(segs)
+	}
+	return nil
+}
+
+// Sends what's left in each sink's buffer before Tilt exits.
+func (f *Forwarder) TearDown(ctx context.Context) {
+	f.mu.Lock()
+	sinks := f.sinks
+	f.sinks = make(map[types.NamespacedName]*sink)
+	f.mu.Unlock()
+
+	for _, s := range sinks {
+		s.add(s.builder.Flush())
+	}
+	f.cancel()
+
+	var wg sync.WaitGroup
+	for _, s := range sinks {
+		wg.Add(1)
+		go func(s *sink) {
+			defer wg.Done()
+			<-s.done
+		}(s)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,95 @@
+package logsink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"time"
+)
+
+// Encodes the few msgpack types that fluentd's forward protocol needs.
+// https://github.com/msgpack/msgpack/blob/master/spec.md
+type msgpackEncoder struct {
+	buf *bytes.Buffer
+}
+
+func (e msgpackEncoder) arrayHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf.WriteByte(0x90 | byte(n))
+	case n < 1<<16:
+		e.buf.WriteByte(0xdc)
+		e.uint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xdd)
+		e.uint32(uint32(n))
+	}
+}
+
+func (e msgpackEncoder) mapHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf.WriteByte(0x80 | byte(n))
+	case n < 1<<16:
+		e.buf.WriteByte(0xde)
+		e.uint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xdf)
+		e.uint32(uint32(n))
+	}
+}
+
+func (e msgpackEncoder) string(s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		e.buf.WriteByte(0xa0 | byte(n))
+	case n < 1<<8:
+		e.buf.WriteByte(0xd9)
+		e.buf.WriteByte(byte(n))
+	case n < 1<<16:
+		e.buf.WriteByte(0xda)
+		e.uint16(uint16(n))
+	default:
+		e.buf.WriteByte(0xdb)
+		e.uint32(uint32(n))
+	}
+	e.buf.WriteString(s)
+}
+
+// Writes the map with its keys sorted, so the output is stable.
+func (e msgpackEncoder) stringMap(m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	e.mapHeader(len(keys))
+	for _, k := range keys {
+		e.string(k)
+		e.string(m[k])
+	}
+}
+
+// Writes fluentd's EventTime extension type, which keeps nanoseconds:
+// a fixext8 of type 0 holding the seconds and nanoseconds as big-endian
+// uint32s.
+func (e msgpackEncoder) eventTime(t time.Time) {
+	e.buf.WriteByte(0xd7)
+	e.buf.WriteByte(0x00)
+	e.uint32(uint32(t.Unix()))
+	e.uint32(uint32(t.Nanosecond()))
+}
+
+func (e msgpackEncoder) uint16(n uint16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], n)
+	e.buf.Write(b[:])
+}
+
+func (e msgpackEncoder) uint32(n uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], n)
+	e.buf.Write(b[:])
+}
@@ -0,0 +1,40 @@
+package logsink
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMsgpackEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := msgpackEncoder{buf: &buf}
+	e.arrayHeader(2)
+	e.string("tilt.fe")
+	e.stringMap(map[string]string{"text": "hi", "level": "info"})
+	e.eventTime(time.Unix(1, 2))
+
+	assert.Equal(t, []byte{
+		0x92,
+		0xa7, 't', 'i', 'l', 't', '.', 'f', 'e',
+		0x82,
+		0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'i', 'n', 'f', 'o',
+		0xa4, 't', 'e', 'x', 't', 0xa2, 'h', 'i',
+		0xd7, 0x00, 0, 0, 0, 1, 0, 0, 0, 2,
+	}, buf.Bytes())
+}
+
+func TestMsgpackLongString(t *testing.T) {
+	var buf bytes.Buffer
+	e := msgpackEncoder{buf: &buf}
+	e.string(strings.Repeat("a", 40))
+	e.string(strings.Repeat("b", 300))
+
+	b := buf.Bytes()
+	assert.Equal(t, []byte{0xd9, 40}, b[:2])
+	assert.Equal(t, []byte{0xda, 0x01, 0x2c}, b[42:45])
+	assert.Equal(t, 45+300, len(b))
+}
@@ -0,0 +1,73 @@
+package logsink
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// How often to copy a running sink's counters into its status.
+const statusInterval = 2 * time.Second
+
+// Starts and stops a sink for each LogSink, and reports how each
+// sink is doing.
+type Reconciler struct {
+	client    ctrlclient.Client
+	forwarder *Forwarder
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func NewReconciler(client ctrlclient.Client, forwarder *Forwarder) *Reconciler {
+	return &Reconciler{
+		client:    client,
+		forwarder: forwarder,
+	}
+}
+
+func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.LogSink{})
+
+	return b, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var obj v1alpha1.LogSink
+	err := r.client.Get(ctx, req.NamespacedName, &obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if apierrors.IsNotFound(err) || !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		r.forwarder.remove(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	var status v1alpha1.LogSinkStatus
+	err = r.forwarder.upsert(req.NamespacedName, &obj)
+	if err != nil {
+		status.Error = err.Error()
+	} else {
+		status, _ = r.forwarder.status(req.NamespacedName)
+	}
+
+	if !apicmp.DeepEqual(obj.Status, status) {
+		update := obj.DeepCopy()
+		update.Status = status
+		err := r.client.Status().Update(ctx, update)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: statusInterval}, nil
+}
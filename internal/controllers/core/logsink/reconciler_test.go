@@ -0,0 +1,323 @@
+package logsink
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var sinkName = types.NamespacedName{Name: "sink"}
+
+func TestFile(t *testing.T) {
+	f := newFixture(t)
+	path := filepath.Join(f.tmp.Path(), "logs", "tilt.log")
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:     v1alpha1.LogSinkTypeFile,
+		Endpoint: path,
+		Labels:   map[string]string{"env": "dev"},
+	})
+
+	f.log("fe", logger.InfoLvl, "hello\n")
+	f.log("", logger.InfoLvl, "global\n")
+	f.onChange()
+
+	require.Eventually(t, func() bool {
+		return len(readLines(path)) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	var first map[string]string
+	require.NoError(t, json.Unmarshal([]byte(readLines(path)[0]), &first))
+	assert.Equal(t, "fe", first["resource"])
+	assert.Equal(t, "hello", first["text"])
+	assert.Equal(t, "info", first["level"])
+	assert.Equal(t, "dev", first["env"])
+	assert.NotEmpty(t, first["time"])
+
+	f.MustReconcile(sinkName)
+	var obj v1alpha1.LogSink
+	f.MustGet(sinkName, &obj)
+	assert.Equal(t, int64(2), obj.Status.SentLines)
+	assert.Equal(t, "", obj.Status.Error)
+}
+
+func TestResourcesAndMinLevel(t *testing.T) {
+	f := newFixture(t)
+	path := filepath.Join(f.tmp.Path(), "tilt.log")
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:      v1alpha1.LogSinkTypeFile,
+		Endpoint:  path,
+		Resources: []string{"fe"},
+		MinLevel:  "warn",
+	})
+
+	f.log("fe", logger.InfoLvl, "fe info\n")
+	f.log("fe", logger.WarnLvl, "fe warn\n")
+	f.log("be", logger.WarnLvl, "be warn\n")
+	f.onChange()
+
+	require.Eventually(t, func() bool {
+		return len(readLines(path)) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Contains(t, readLines(path)[0], `"text":"fe warn"`)
+}
+
+func TestLoki(t *testing.T) {
+	f := newFixture(t)
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:     v1alpha1.LogSinkTypeLoki,
+		Endpoint: f.loki.server.URL,
+		Labels:   map[string]string{"env": "dev"},
+	})
+
+	f.log("fe", logger.InfoLvl, "one\ntwo\n")
+	f.log("fe", logger.ErrorLvl, "three\n")
+	f.onChange()
+
+	require.Eventually(t, func() bool {
+		return len(f.loki.pushes()) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	push := f.loki.pushes()[0]
+	require.Len(t, push.Streams, 2)
+	assert.Equal(t, map[string]string{"job": "tilt", "env": "dev", "resource": "fe", "level": "error"}, push.Streams[0].Stream)
+	assert.Equal(t, "three", push.Streams[0].Values[0][1])
+	assert.Equal(t, "info", push.Streams[1].Stream["level"])
+	require.Len(t, push.Streams[1].Values, 2)
+	assert.Equal(t, "one", push.Streams[1].Values[0][1])
+	assert.Equal(t, "two", push.Streams[1].Values[1][1])
+}
+
+func TestBatchSize(t *testing.T) {
+	f := newFixture(t)
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:          v1alpha1.LogSinkTypeLoki,
+		Endpoint:      f.loki.server.URL,
+		BatchSize:     2,
+		FlushInterval: &metav1.Duration{Duration: time.Hour},
+	})
+
+	f.log("fe", logger.InfoLvl, "one\ntwo\nthree\n")
+	f.onChange()
+
+	// A full batch goes right away; the rest waits for the flush interval.
+	require.Eventually(t, func() bool {
+		return len(f.loki.pushes()) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 3, f.loki.lineCount())
+}
+
+func TestEndpointDownDropsOldest(t *testing.T) {
+	f := newFixture(t)
+	f.loki.setStatus(http.StatusServiceUnavailable)
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:          v1alpha1.LogSinkTypeLoki,
+		Endpoint:      f.loki.server.URL,
+		BatchSize:     2,
+		BufferSize:    3,
+		FlushInterval: &metav1.Duration{Duration: 50 * time.Millisecond},
+	})
+
+	f.log("fe", logger.InfoLvl, "one\ntwo\nthree\nfour\nfive\n")
+	f.onChange()
+
+	require.Eventually(t, func() bool {
+		f.MustReconcile(sinkName)
+		var obj v1alpha1.LogSink
+		f.MustGet(sinkName, &obj)
+		return obj.Status.Error != ""
+	}, time.Second, 5*time.Millisecond)
+
+	var obj v1alpha1.LogSink
+	f.MustGet(sinkName, &obj)
+	assert.Contains(t, obj.Status.Error, "503")
+	assert.Equal(t, int64(2), obj.Status.DroppedLines)
+	assert.Equal(t, int32(3), obj.Status.BufferedLines)
+	assert.Equal(t, int64(0), obj.Status.SentLines)
+}
+
+func TestSpecChangeKeepsPosition(t *testing.T) {
+	f := newFixture(t)
+	path := filepath.Join(f.tmp.Path(), "tilt.log")
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:     v1alpha1.LogSinkTypeLoki,
+		Endpoint: f.loki.server.URL,
+	})
+	f.log("fe", logger.InfoLvl, "one\n")
+	f.onChange()
+	require.Eventually(t, func() bool {
+		return f.loki.lineCount() == 1
+	}, time.Second, 5*time.Millisecond)
+
+	var obj v1alpha1.LogSink
+	f.MustGet(sinkName, &obj)
+	obj.Spec.Type = v1alpha1.LogSinkTypeFile
+	obj.Spec.Endpoint = path
+	f.Update(&obj)
+
+	f.log("fe", logger.InfoLvl, "two\n")
+	f.onChange()
+	require.Eventually(t, func() bool {
+		return len(readLines(path)) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Contains(t, readLines(path)[0], `"text":"two"`)
+	assert.Equal(t, 1, f.loki.lineCount())
+}
+
+func TestDeleteStopsSink(t *testing.T) {
+	f := newFixture(t)
+	f.createSink(v1alpha1.LogSinkSpec{
+		Type:     v1alpha1.LogSinkTypeLoki,
+		Endpoint: f.loki.server.URL,
+	})
+
+	var obj v1alpha1.LogSink
+	f.MustGet(sinkName, &obj)
+	f.Delete(&obj)
+
+	_, ok := f.forwarder.status(sinkName)
+	assert.False(t, ok)
+
+	f.log("fe", logger.InfoLvl, "one\n")
+	f.onChange()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, f.loki.lineCount())
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	tmp       *tempdir.TempDirFixture
+	st        *store.TestingStore
+	forwarder *Forwarder
+	loki      *fakeLoki
+}
+
+func newFixture(t *testing.T) *fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	forwarder := NewForwarder()
+	t.Cleanup(func() {
+		forwarder.TearDown(cfb.Context())
+	})
+
+	r := NewReconciler(cfb.Client, forwarder)
+	return &fixture{
+		ControllerFixture: cfb.Build(r),
+		tmp:               tempdir.NewTempDirFixture(t),
+		st:                store.NewTestingStore(),
+		forwarder:         forwarder,
+		loki:              newFakeLoki(t),
+	}
+}
+
+func (f *fixture) createSink(spec v1alpha1.LogSinkSpec) {
+	if spec.FlushInterval == nil {
+		spec.FlushInterval = &metav1.Duration{Duration: 10 * time.Millisecond}
+	}
+	f.Create(&v1alpha1.LogSink{
+		ObjectMeta: metav1.ObjectMeta{Name: sinkName.Name},
+		Spec:       spec,
+	})
+}
+
+func (f *fixture) log(mn model.ManifestName, level logger.Level, msg string) {
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction(mn, model.LogSpanID(mn), level, nil, []byte(msg)), nil)
+	f.st.UnlockMutableState()
+}
+
+func (f *fixture) onChange() {
+	err := f.forwarder.OnChange(f.Context(), f.st, store.LegacyChangeSummary())
+	require.NoError(f.T(), err)
+}
+
+func readLines(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// A Loki server that records what it's pushed.
+type fakeLoki struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	status int
+	pushed []lokiPush
+}
+
+func newFakeLoki(t *testing.T) *fakeLoki {
+	l := &fakeLoki{status: http.StatusNoContent}
+	l.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasSuffix(req.URL.Path, "/loki/api/v1/push") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var push lokiPush
+		err := json.NewDecoder(req.Body).Decode(&push)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.status == http.StatusNoContent {
+			l.pushed = append(l.pushed, push)
+		}
+		w.WriteHeader(l.status)
+	}))
+	t.Cleanup(l.server.Close)
+	return l
+}
+
+func (l *fakeLoki) setStatus(status int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.status = status
+}
+
+func (l *fakeLoki) pushes() []lokiPush {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]lokiPush(nil), l.pushed...)
+}
+
+func (l *fakeLoki) lineCount() int {
+	count := 0
+	for _, push := range l.pushes() {
+		for _, stream := range push.Streams {
+			count += len(stream.Values)
+		}
+	}
+	return count
+}
@@ -0,0 +1,231 @@
+package logsink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/hud"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// The longest to wait before retrying a batch that failed to send.
+const maxBackoff = 30 * time.Second
+
+// How long to keep trying to send the buffered lines when a sink stops.
+const stopTimeout = 2 * time.Second
+
+// Forwards the lines of one LogSink to its backend.
+//
+// The Forwarder adds lines as Tilt logs them, and a goroutine sends them
+// in batches, so a slow endpoint never blocks the Forwarder.
+type sink struct {
+	obj      *v1alpha1.LogSink
+	minLevel logger.Level
+	backend  backend
+
+	batchSize     int
+	bufferSize    int
+	flushInterval time.Duration
+
+	// Only the Forwarder touches these, under its own lock.
+	checkpoint logstore.Checkpoint
+	builder    *hud.JSONLineBuilder
+
+	// Signals the send loop that a batch is ready.
+	wake chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	buffer []hud.JSONLogLine
+	status v1alpha1.LogSinkStatus
+}
+
+func newSink(obj *v1alpha1.LogSink) (*sink, error) {
+	b, err := newBackend(obj.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	minLevel := logger.DebugLvl
+	if obj.Spec.MinLevel != "" {
+		minLevel, err = logger.ParseLevel(obj.Spec.MinLevel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &sink{
+		obj:           obj.DeepCopy(),
+		minLevel:      minLevel,
+		backend:       b,
+		batchSize:     obj.BatchSize(),
+		bufferSize:    obj.BufferSize(),
+		flushInterval: obj.FlushInterval().Duration,
+		builder:       hud.NewJSONLineBuilder(),
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+func (s *sink) start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+}
+
+// Stops the send loop, after one last try at sending what's buffered.
+func (s *sink) stop() {
+	s.cancel()
+	<-s.done
+}
+
+// Filters the segments down to the ones this sink wants, and
+// buffers the lines they complete.
+func (s *sink) addSegments(segments []logstore.StreamSegment) {
+	wanted := make([]logstore.StreamSegment, 0, len(segments))
+	for _, seg := range segments {
+		if s.obj.WantsResource(seg.ManifestName.String()) {
+			wanted = append(wanted, seg)
+		}
+	}
+	s.add(s.builder.Add(wanted))
+}
+
+func (s *sink) add(lines []hud.JSONLogLine) {
+	if len(lines) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.buffer = append(s.buffer, lines...)
+	s.dropOverflowLocked()
+	ready := len(s.buffer) >= s.batchSize
+	s.mu.Unlock()
+
+	if ready {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Drops the oldest lines if the buffer is over its limit.
+func (s *sink) dropOverflowLocked() {
+	overflow := len(s.buffer) - s.bufferSize
+	if overflow <= 0 {
+		return
+	}
+	s.buffer = append([]hud.JSONLogLine(nil), s.buffer[overflow:]...)
+	s.status.DroppedLines += int64(overflow)
+}
+
+func (s *sink) currentStatus() v1alpha1.LogSinkStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.BufferedLines = int32(len(s.buffer))
+	return status
+}
+
+func (s *sink) run(ctx context.Context) {
+	defer close(s.done)
+	defer s.backend.close()
+
+	backoff := time.Duration(0)
+	for {
+		wait := s.flushInterval
+		if backoff > 0 {
+			wait = backoff
+		}
+		timer := time.NewTimer(wait)
+
+		// While backing off, a full batch waits for the backoff to run out.
+		wake := s.wake
+		if backoff > 0 {
+			wake = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.drain()
+			return
+		case <-wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+
+		err := s.sendAll(ctx)
+		if err != nil {
+			backoff = nextBackoff(backoff, s.flushInterval)
+		} else {
+			backoff = 0
+		}
+	}
+}
+
+func nextBackoff(backoff, flushInterval time.Duration) time.Duration {
+	if backoff == 0 {
+		backoff = flushInterval
+	}
+	backoff *= 2
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// Sends batches until the buffer is empty or a send fails.
+func (s *sink) sendAll(ctx context.Context) error {
+	for {
+		sent, err := s.sendBatch(ctx)
+		if err != nil || !sent {
+			return err
+		}
+	}
+}
+
+// Sends the oldest batch of lines. If it fails, the lines go back at the
+// front of the buffer, so the next try sends them in order.
+func (s *sink) sendBatch(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	n := len(s.buffer)
+	if n > s.batchSize {
+		n = s.batchSize
+	}
+	batch := s.buffer[:n:n]
+	s.buffer = s.buffer[n:]
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return false, nil
+	}
+
+	err := s.backend.send(ctx, batch)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.buffer = append(batch, s.buffer...)
+		s.dropOverflowLocked()
+		s.status.Error = err.Error()
+		return false, err
+	}
+	s.status.SentLines += int64(len(batch))
+	s.status.LastSentTime = apis.NowMicro()
+	s.status.Error = ""
+	return true, nil
+}
+
+// Tries to send what's left before the sink stops.
+func (s *sink) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	_ = s.sendAll(ctx)
+}
@@ -0,0 +1,8 @@
+package logsink
+
+import "github.com/google/wire"
+
+var WireSet = wire.NewSet(
+	NewForwarder,
+	NewReconciler,
+)
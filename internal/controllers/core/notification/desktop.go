@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Shows a notification on the desktop of the machine Tilt runs on.
+type DesktopNotify func(ctx context.Context, title, body string) error
+
+func ProvideDesktopNotify() DesktopNotify {
+	return ShowDesktopNotification
+}
+
+func ShowDesktopNotification(ctx context.Context, title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsNotifyScript)
+		cmd.Env = append(cmd.Environ(), "TILT_NOTIFY_TITLE="+title, "TILT_NOTIFY_BODY="+body)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("showing desktop notification: notify-send not found (install libnotify)")
+		}
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=Tilt", title, body)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("showing desktop notification: %s", msg)
+	}
+	return nil
+}
+
+// Quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// Shows a balloon tip from the notification area. Reads the text from the
+// environment, so that it never needs to be quoted for PowerShell.
+const windowsNotifyScript = `
+Add-Type -AssemblyName System.Windows.Forms
+$icon = New-Object System.Windows.Forms.NotifyIcon
+$icon.Icon = [System.Drawing.SystemIcons]::Information
+$icon.Visible = $true
+$icon.ShowBalloonTip(5000, $env:TILT_NOTIFY_TITLE, $env:TILT_NOTIFY_BODY, [System.Windows.Forms.ToolTipIcon]::None)
+Start-Sleep -Seconds 5
+$icon.Dispose()
+`
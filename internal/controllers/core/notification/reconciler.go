@@ -0,0 +1,519 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const sendTimeout = 10 * time.Second
+
+// How long to wait before retrying a message that failed to send,
+// if the Notification's minimum interval is shorter.
+const retryInterval = 10 * time.Second
+
+// The key of state changes that aren't about one resource, like
+// a run finishing.
+const allResources = ""
+
+// Watches resources for state changes, and sends messages about them
+// to each Notification that wants them.
+type Reconciler struct {
+	client     ctrlclient.Client
+	clock      clockwork.Clock
+	httpClient *http.Client
+	webURL     model.WebURL
+	desktop    DesktopNotify
+
+	mu     sync.Mutex
+	states map[types.NamespacedName]*notificationState
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func NewReconciler(client ctrlclient.Client, clock clockwork.Clock, webURL model.WebURL, desktop DesktopNotify) *Reconciler {
+	return &Reconciler{
+		client:     client,
+		clock:      clock,
+		httpClient: &http.Client{Timeout: sendTimeout},
+		webURL:     webURL,
+		desktop:    desktop,
+		states:     make(map[types.NamespacedName]*notificationState),
+	}
+}
+
+func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Notification{}).
+		Watches(&source.Kind{Type: &v1alpha1.UIResource{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAll)).
+		Watches(&source.Kind{Type: &v1alpha1.AlertRule{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAll))
+
+	return b, nil
+}
+
+// Any resource change might be one that a Notification wants to hear about.
+func (r *Reconciler) enqueueAll(obj ctrlclient.Object) []reconcile.Request {
+	var list v1alpha1.NotificationList
+	err := r.client.List(context.Background(), &list)
+	if err != nil {
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, n := range list.Items {
+		result = append(result, reconcile.Request{NamespacedName: types.NamespacedName{Name: n.Name}})
+	}
+	return result
+}
+
+// What we know about a Notification's resources.
+//
+// Only the Notification's own reconcile touches it, so it doesn't need
+// a lock of its own.
+type notificationState struct {
+	// The last state we saw of each resource.
+	resources map[string]resourceSnapshot
+
+	// State changes we haven't sent yet, by resource.
+	pending map[string][]event
+
+	// The earliest time we may send the next message about each resource.
+	nextSend map[string]time.Time
+
+	// Resources whose pending message was shown on the desktop, but
+	// failed to post to the webhook, so that retries don't show it again.
+	shownOnDesktop map[string]bool
+
+	// Whether any resource was building or waiting to build, the last
+	// time we looked.
+	busy bool
+}
+
+func newNotificationState() *notificationState {
+	return &notificationState{
+		resources:      make(map[string]resourceSnapshot),
+		pending:        make(map[string][]event),
+		nextSend:       make(map[string]time.Time),
+		shownOnDesktop: make(map[string]bool),
+	}
+}
+
+// The parts of a resource's state that we send messages about.
+type resourceSnapshot struct {
+	lastBuildFinishTime time.Time
+	lastBuildError      string
+
+	// Whether the build before the last one succeeded.
+	prevBuildOK bool
+
+	updateStatus v1alpha1.UpdateStatus
+
+	crashLooping bool
+	podName      string
+	podRestarts  int32
+	ready        metav1.ConditionStatus
+	readyReason  string
+
+	// The messages of the AlertRules firing for the resource, by rule name.
+	alerts map[string]string
+}
+
+func snapshot(uir *v1alpha1.UIResource) resourceSnapshot {
+	var s resourceSnapshot
+	if len(uir.Status.BuildHistory) > 0 {
+		b := uir.Status.BuildHistory[0]
+		s.lastBuildFinishTime = b.FinishTime.Time
+		s.lastBuildError = b.Error
+	}
+	if len(uir.Status.BuildHistory) > 1 {
+		s.prevBuildOK = uir.Status.BuildHistory[1].Error == ""
+	}
+	s.updateStatus = uir.Status.UpdateStatus
+	if info := uir.Status.K8sResourceInfo; info != nil {
+		s.crashLooping = info.PodStatus == "CrashLoopBackOff"
+		s.podName = info.PodName
+		s.podRestarts = info.PodRestarts
+	}
+	for _, c := range uir.Status.Conditions {
+		if c.Type == v1alpha1.UIResourceReady {
+			s.ready = c.Status
+			s.readyReason = c.Reason
+			if c.Message != "" {
+				s.readyReason = c.Message
+			}
+		}
+	}
+	return s
+}
+
+type event struct {
+	kind   v1alpha1.NotificationEvent
+	detail string
+
+	// The name of the AlertRule, for alert events.
+	rule string
+}
+
+// The state changes between two snapshots of a resource.
+func diff(prev, cur resourceSnapshot) []event {
+	var result []event
+	if cur.lastBuildFinishTime.After(prev.lastBuildFinishTime) && cur.lastBuildError != "" {
+		result = append(result, event{kind: v1alpha1.NotificationEventBuildFailed, detail: firstLine(cur.lastBuildError)})
+		if cur.prevBuildOK {
+			result = append(result, event{kind: v1alpha1.NotificationEventFirstFailure, detail: firstLine(cur.lastBuildError)})
+		}
+	}
+	if cur.crashLooping && !prev.crashLooping {
+		result = append(result, event{
+			kind:   v1alpha1.NotificationEventCrashLoop,
+			detail: fmt.Sprintf("pod %s, %d restarts", cur.podName, cur.podRestarts),
+		})
+	}
+	if cur.ready == metav1.ConditionTrue && prev.ready != metav1.ConditionTrue {
+		result = append(result, event{kind: v1alpha1.NotificationEventReady})
+	}
+	if cur.ready == metav1.ConditionFalse && prev.ready == metav1.ConditionTrue {
+		result = append(result, event{kind: v1alpha1.NotificationEventNotReady, detail: cur.readyReason})
+	}
+	for _, rule := range sortedKeys(cur.alerts) {
+		if _, ok := prev.alerts[rule]; !ok {
+			result = append(result, event{kind: v1alpha1.NotificationEventAlertFiring, rule: rule, detail: cur.alerts[rule]})
+		}
+	}
+	for _, rule := range sortedKeys(prev.alerts) {
+		if _, ok := cur.alerts[rule]; !ok {
+			result = append(result, event{kind: v1alpha1.NotificationEventAlertResolved, rule: rule})
+		}
+	}
+	return result
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	nn := req.NamespacedName
+	n := &v1alpha1.Notification{}
+	err := r.client.Get(ctx, nn, n)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if apierrors.IsNotFound(err) || n.ObjectMeta.DeletionTimestamp != nil {
+		r.mu.Lock()
+		delete(r.states, nn)
+		r.mu.Unlock()
+		return ctrl.Result{}, nil
+	}
+
+	r.mu.Lock()
+	state, ok := r.states[nn]
+	if !ok {
+		state = newNotificationState()
+		r.states[nn] = state
+	}
+	r.mu.Unlock()
+
+	var uirs v1alpha1.UIResourceList
+	err = r.client.List(ctx, &uirs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var rules v1alpha1.AlertRuleList
+	err = r.client.List(ctx, &rules)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.observe(n, state, uirs.Items, firingAlerts(rules.Items))
+
+	status := *n.Status.DeepCopy()
+	result := r.send(ctx, n, state, &status)
+	if !apicmp.DeepEqual(n.Status, status) {
+		update := n.DeepCopy()
+		update.Status = status
+		err := r.client.Status().Update(ctx, update)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// Records any state changes since the last reconcile.
+//
+// The first time we see a resource, we take its state as the baseline,
+// so that starting Tilt doesn't send a message about every resource.
+func (r *Reconciler) observe(n *v1alpha1.Notification, state *notificationState, uirs []v1alpha1.UIResource, alerts map[string]map[string]string) {
+	current := make(map[string]bool, len(uirs))
+	busy := false
+	var failed []string
+	for i := range uirs {
+		uir := &uirs[i]
+		name := uir.Name
+		if !n.WantsResource(name) {
+			continue
+		}
+		current[name] = true
+
+		cur := snapshot(uir)
+		cur.alerts = alerts[name]
+		prev, seen := state.resources[name]
+		state.resources[name] = cur
+		if uir.Status.DisableStatus.State == v1alpha1.DisableStateDisabled {
+			continue
+		}
+
+		switch cur.updateStatus {
+		case v1alpha1.UpdateStatusInProgress, v1alpha1.UpdateStatusPending:
+			busy = true
+		case v1alpha1.UpdateStatusError:
+			failed = append(failed, name)
+		}
+
+		if !seen {
+			continue
+		}
+
+		for _, e := range diff(prev, cur) {
+			// A failed build is already a BuildFailed message, so
+			// don't say it twice.
+			if e.kind == v1alpha1.NotificationEventFirstFailure && n.WantsEvent(v1alpha1.NotificationEventBuildFailed) {
+				continue
+			}
+			if n.WantsEvent(e.kind) {
+				state.pending[name] = append(state.pending[name], e)
+			}
+		}
+	}
+
+	if state.busy && !busy && n.WantsEvent(v1alpha1.NotificationEventRunFinished) {
+		detail := fmt.Sprintf("%d resources up to date", len(current))
+		if len(failed) > 0 {
+			sort.Strings(failed)
+			detail = fmt.Sprintf("%d of %d resources failed: %s", len(failed), len(current), strings.Join(failed, ", "))
+		}
+		state.pending[allResources] = append(state.pending[allResources], event{
+			kind:   v1alpha1.NotificationEventRunFinished,
+			detail: detail,
+		})
+	}
+	state.busy = busy
+
+	for name := range state.resources {
+		if !current[name] {
+			delete(state.resources, name)
+			delete(state.pending, name)
+			delete(state.nextSend, name)
+			delete(state.shownOnDesktop, name)
+		}
+	}
+}
+
+// Sends a message for each resource with pending state changes, unless
+// we sent one too recently. Returns when to try again.
+func (r *Reconciler) send(ctx context.Context, n *v1alpha1.Notification, state *notificationState, status *v1alpha1.NotificationStatus) ctrl.Result {
+	now := r.clock.Now()
+	interval := n.MinInterval().Duration
+	var names []string
+	for name := range state.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var requeueAfter time.Duration
+	for _, name := range names {
+		if next := state.nextSend[name]; now.Before(next) {
+			requeueAfter = earliest(requeueAfter, next.Sub(now))
+			continue
+		}
+
+		events := state.pending[name]
+		var desktopErr error
+		if n.Spec.Desktop && !state.shownOnDesktop[name] {
+			// Desktop notifications that fail usually keep failing, like when
+			// there's no notifier installed, so they aren't retried.
+			desktopErr = r.showOnDesktop(ctx, name, events)
+			if desktopErr != nil {
+				logger.Get(ctx).Debugf("notification %s: %v", n.Name, desktopErr)
+			}
+			state.shownOnDesktop[name] = true
+		}
+
+		if n.Spec.URL != "" {
+			err := r.post(ctx, n.Spec.URL, r.message(name, events))
+			if err != nil {
+				// Keep the state changes, and try again later.
+				logger.Get(ctx).Debugf("notification %s: %v", n.Name, err)
+				status.Error = err.Error()
+				wait := interval
+				if wait < retryInterval {
+					wait = retryInterval
+				}
+				state.nextSend[name] = now.Add(wait)
+				requeueAfter = earliest(requeueAfter, wait)
+				continue
+			}
+		}
+
+		delete(state.pending, name)
+		delete(state.shownOnDesktop, name)
+		state.nextSend[name] = now.Add(interval)
+		if desktopErr != nil {
+			status.Error = desktopErr.Error()
+			if n.Spec.URL == "" {
+				continue
+			}
+		} else {
+			status.Error = ""
+		}
+		status.SentCount++
+		status.LastSentTime = apis.NewMicroTime(now)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
+
+// The webhook message, with a link to each resource.
+func (r *Reconciler) message(name string, events []event) string {
+	resource := fmt.Sprintf("*%s*", name)
+	if !r.webURL.Empty() {
+		u := r.webURL
+		u.Path = fmt.Sprintf("/r/%s/overview", name)
+		resource = fmt.Sprintf("<%s|%s>", u.String(), name)
+	}
+	return strings.Join(messageLines(resource, events), "\n")
+}
+
+func (r *Reconciler) showOnDesktop(ctx context.Context, name string, events []event) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	title := "Tilt"
+	if name != allResources {
+		title = fmt.Sprintf("Tilt: %s", name)
+	}
+	return r.desktop(ctx, title, strings.Join(messageLines(name, events), "\n"))
+}
+
+// A line for each state change, which refers to the resource as resource.
+func messageLines(resource string, events []event) []string {
+	var lines []string
+	for _, e := range events {
+		var line string
+		switch e.kind {
+		case v1alpha1.NotificationEventBuildFailed:
+			line = fmt.Sprintf("Build failed for %s", resource)
+		case v1alpha1.NotificationEventCrashLoop:
+			line = fmt.Sprintf("%s is crash-looping", resource)
+		case v1alpha1.NotificationEventReady:
+			line = fmt.Sprintf("%s is ready", resource)
+		case v1alpha1.NotificationEventNotReady:
+			line = fmt.Sprintf("%s is no longer ready", resource)
+		case v1alpha1.NotificationEventAlertFiring:
+			line = fmt.Sprintf("Alert %s is firing for %s", e.rule, resource)
+		case v1alpha1.NotificationEventAlertResolved:
+			line = fmt.Sprintf("Alert %s resolved for %s", e.rule, resource)
+		case v1alpha1.NotificationEventFirstFailure:
+			line = fmt.Sprintf("%s started failing", resource)
+		case v1alpha1.NotificationEventRunFinished:
+			line = "Run finished"
+		}
+		if e.detail != "" {
+			line = fmt.Sprintf("%s: %s", line, e.detail)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// The payload that Slack incoming webhooks accept.
+type webhookPayload struct {
+	Text string `json:"text"`
+}
+
+func (r *Reconciler) post(ctx context.Context, webhookURL string, text string) error {
+	body, err := json.Marshal(webhookPayload{Text: text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		// Webhook URLs usually have a secret in them, so leave the URL
+		// out of the error.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to webhook: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("posting to webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// The messages of the firing alerts, by resource, then by rule name.
+func firingAlerts(rules []v1alpha1.AlertRule) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, rule := range rules {
+		for _, f := range rule.Status.Firing {
+			if result[f.Resource] == nil {
+				result[f.Resource] = make(map[string]string)
+			}
+			result[f.Resource][rule.Name] = f.Message
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// The shorter of two wait times, where 0 means no wait is scheduled yet.
+func earliest(cur, d time.Duration) time.Duration {
+	if cur == 0 || d < cur {
+		return d
+	}
+	return cur
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,495 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var notificationName = types.NamespacedName{Name: "slack"}
+
+func TestBuildFailed(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{})
+
+	f.finishBuild("fe", "compile error\nat main.go:3")
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{"Build failed for <http://localhost:10350/r/fe/overview|fe>: compile error"}, f.webhook.messages())
+
+	var n v1alpha1.Notification
+	f.MustGet(notificationName, &n)
+	assert.Equal(t, int32(1), n.Status.SentCount)
+	assert.Equal(t, "", n.Status.Error)
+}
+
+func TestNoMessagesForInitialState(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.finishBuild("fe", "compile error")
+	f.setReady("fe", metav1.ConditionTrue)
+
+	f.createNotification(v1alpha1.NotificationSpec{})
+	f.MustReconcile(notificationName)
+
+	assert.Empty(t, f.webhook.messages())
+}
+
+func TestReadinessChanges(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{MinInterval: &metav1.Duration{}})
+
+	f.setReady("fe", metav1.ConditionTrue)
+	f.MustReconcile(notificationName)
+	f.setReady("fe", metav1.ConditionFalse)
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{
+		"<http://localhost:10350/r/fe/overview|fe> is ready",
+		"<http://localhost:10350/r/fe/overview|fe> is no longer ready: container crashed",
+	}, f.webhook.messages())
+}
+
+func TestCrashLoop(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{})
+
+	f.updateResource("fe", func(uir *v1alpha1.UIResource) {
+		uir.Status.K8sResourceInfo = &v1alpha1.UIResourceKubernetes{
+			PodName:     "fe-abc",
+			PodStatus:   "CrashLoopBackOff",
+			PodRestarts: 4,
+		}
+	})
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{
+		"<http://localhost:10350/r/fe/overview|fe> is crash-looping: pod fe-abc, 4 restarts",
+	}, f.webhook.messages())
+}
+
+func TestAlerts(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{MinInterval: &metav1.Duration{}})
+	f.Create(&v1alpha1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "slow-builds"},
+		Spec: v1alpha1.AlertRuleSpec{
+			Metric:   v1alpha1.AlertMetricBuildDuration,
+			Duration: &metav1.Duration{Duration: 2 * time.Minute},
+		},
+	})
+
+	f.setFiring("slow-builds", []v1alpha1.AlertFiring{{
+		Resource: "fe",
+		Value:    "3m0s",
+		Message:  "last build took 3m0s, more than 2m0s",
+	}})
+	f.MustReconcile(notificationName)
+	f.setFiring("slow-builds", nil)
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{
+		"Alert slow-builds is firing for <http://localhost:10350/r/fe/overview|fe>: last build took 3m0s, more than 2m0s",
+		"Alert slow-builds resolved for <http://localhost:10350/r/fe/overview|fe>",
+	}, f.webhook.messages())
+}
+
+func TestFilters(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createResource("be")
+	f.createNotification(v1alpha1.NotificationSpec{
+		Resources: []string{"fe"},
+		Events:    []v1alpha1.NotificationEvent{v1alpha1.NotificationEventBuildFailed},
+	})
+
+	f.setReady("fe", metav1.ConditionTrue)
+	f.finishBuild("be", "compile error")
+	f.MustReconcile(notificationName)
+	assert.Empty(t, f.webhook.messages())
+
+	f.finishBuild("fe", "compile error")
+	f.MustReconcile(notificationName)
+	assert.Len(t, f.webhook.messages(), 1)
+}
+
+func TestRateLimit(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{})
+
+	f.finishBuild("fe", "error 1")
+	f.MustReconcile(notificationName)
+	assert.Len(t, f.webhook.messages(), 1)
+
+	f.clock.Advance(time.Second)
+	f.finishBuild("fe", "error 2")
+	f.MustReconcile(notificationName)
+	f.finishBuild("fe", "error 3")
+	result := f.MustReconcile(notificationName)
+	assert.Len(t, f.webhook.messages(), 1)
+	assert.InDelta(t, 59*time.Second, result.RequeueAfter, float64(10*time.Millisecond))
+
+	// The state changes in between are batched into one message.
+	f.clock.Advance(time.Minute)
+	f.MustReconcile(notificationName)
+	assert.Equal(t, []string{
+		"Build failed for <http://localhost:10350/r/fe/overview|fe>: error 1",
+		"Build failed for <http://localhost:10350/r/fe/overview|fe>: error 2\n" +
+			"Build failed for <http://localhost:10350/r/fe/overview|fe>: error 3",
+	}, f.webhook.messages())
+}
+
+func TestWebhookError(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{})
+	f.webhook.setStatus(http.StatusInternalServerError)
+
+	f.finishBuild("fe", "compile error")
+	result := f.MustReconcile(notificationName)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+
+	var n v1alpha1.Notification
+	f.MustGet(notificationName, &n)
+	assert.Equal(t, "posting to webhook: 500 Internal Server Error", n.Status.Error)
+	assert.Equal(t, int32(0), n.Status.SentCount)
+
+	// Retry once the webhook is back.
+	f.webhook.setStatus(http.StatusOK)
+	f.clock.Advance(time.Minute)
+	f.MustReconcile(notificationName)
+
+	f.MustGet(notificationName, &n)
+	assert.Equal(t, "", n.Status.Error)
+	assert.Equal(t, int32(1), n.Status.SentCount)
+	assert.Len(t, f.webhook.messages(), 1)
+}
+
+func TestFirstFailure(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{
+		Events:      []v1alpha1.NotificationEvent{v1alpha1.NotificationEventFirstFailure},
+		MinInterval: &metav1.Duration{},
+	})
+
+	f.finishBuild("fe", "")
+	f.MustReconcile(notificationName)
+	f.finishBuild("fe", "error 1")
+	f.MustReconcile(notificationName)
+	f.finishBuild("fe", "error 2")
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{
+		"<http://localhost:10350/r/fe/overview|fe> started failing: error 1",
+	}, f.webhook.messages())
+}
+
+func TestFirstFailureIsNotRepeated(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{
+		Events: []v1alpha1.NotificationEvent{
+			v1alpha1.NotificationEventBuildFailed,
+			v1alpha1.NotificationEventFirstFailure,
+		},
+	})
+
+	f.finishBuild("fe", "")
+	f.MustReconcile(notificationName)
+	f.finishBuild("fe", "error 1")
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{
+		"Build failed for <http://localhost:10350/r/fe/overview|fe>: error 1",
+	}, f.webhook.messages())
+}
+
+func TestRunFinished(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createResource("be")
+	f.createNotification(v1alpha1.NotificationSpec{
+		Events:      []v1alpha1.NotificationEvent{v1alpha1.NotificationEventRunFinished},
+		MinInterval: &metav1.Duration{},
+	})
+
+	f.setUpdateStatus("fe", v1alpha1.UpdateStatusInProgress)
+	f.setUpdateStatus("be", v1alpha1.UpdateStatusPending)
+	f.MustReconcile(notificationName)
+	f.setUpdateStatus("fe", v1alpha1.UpdateStatusOK)
+	f.MustReconcile(notificationName)
+	assert.Empty(t, f.webhook.messages())
+
+	f.setUpdateStatus("be", v1alpha1.UpdateStatusOK)
+	f.MustReconcile(notificationName)
+	assert.Equal(t, []string{"Run finished: 2 resources up to date"}, f.webhook.messages())
+
+	f.setUpdateStatus("be", v1alpha1.UpdateStatusInProgress)
+	f.MustReconcile(notificationName)
+	f.setUpdateStatus("be", v1alpha1.UpdateStatusError)
+	f.MustReconcile(notificationName)
+	assert.Equal(t, []string{
+		"Run finished: 2 resources up to date",
+		"Run finished: 1 of 2 resources failed: be",
+	}, f.webhook.messages())
+}
+
+func TestMutedResources(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createResource("be")
+	f.createNotification(v1alpha1.NotificationSpec{
+		MutedResources: []string{"be"},
+	})
+
+	f.finishBuild("be", "compile error")
+	f.MustReconcile(notificationName)
+	assert.Empty(t, f.webhook.messages())
+
+	f.finishBuild("fe", "compile error")
+	f.MustReconcile(notificationName)
+	assert.Len(t, f.webhook.messages(), 1)
+}
+
+func TestDesktop(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.Create(&v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Name: notificationName.Name},
+		Spec:       v1alpha1.NotificationSpec{Desktop: true},
+	})
+
+	f.finishBuild("fe", "compile error")
+	f.MustReconcile(notificationName)
+
+	assert.Equal(t, []string{"Tilt: fe\nBuild failed for fe: compile error"}, f.desktop.messages())
+	assert.Empty(t, f.webhook.messages())
+
+	var n v1alpha1.Notification
+	f.MustGet(notificationName, &n)
+	assert.Equal(t, int32(1), n.Status.SentCount)
+	assert.Equal(t, "", n.Status.Error)
+}
+
+func TestDesktopError(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{Desktop: true})
+	f.desktop.err = fmt.Errorf("showing desktop notification: notify-send not found (install libnotify)")
+
+	f.finishBuild("fe", "compile error")
+	f.MustReconcile(notificationName)
+
+	// The webhook still gets the message.
+	assert.Len(t, f.webhook.messages(), 1)
+
+	var n v1alpha1.Notification
+	f.MustGet(notificationName, &n)
+	assert.Equal(t, int32(1), n.Status.SentCount)
+	assert.Equal(t, "showing desktop notification: notify-send not found (install libnotify)", n.Status.Error)
+}
+
+func TestDesktopNotShownAgainOnWebhookRetry(t *testing.T) {
+	f := newFixture(t)
+	f.createResource("fe")
+	f.createNotification(v1alpha1.NotificationSpec{Desktop: true})
+	f.webhook.setStatus(http.StatusInternalServerError)
+
+	f.finishBuild("fe", "compile error")
+	f.MustReconcile(notificationName)
+
+	f.webhook.setStatus(http.StatusOK)
+	f.clock.Advance(time.Minute)
+	f.MustReconcile(notificationName)
+
+	assert.Len(t, f.webhook.messages(), 1)
+	assert.Len(t, f.desktop.messages(), 1)
+}
+
+func TestValidate(t *testing.T) {
+	n := &v1alpha1.Notification{Spec: v1alpha1.NotificationSpec{
+		URL:         "hooks.slack.com/services/xyz",
+		Events:      []v1alpha1.NotificationEvent{"Exploded"},
+		MinInterval: &metav1.Duration{Duration: -time.Second},
+	}}
+	errs := n.Validate(nil)
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "URLs must start with http(s)://")
+	assert.Contains(t, errs[1].Error(), `supported values: "BuildFailed", "CrashLoop", "Ready", "NotReady"`)
+	assert.Contains(t, errs[2].Error(), "must not be negative")
+
+	n = &v1alpha1.Notification{}
+	errs = n.Validate(nil)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "must specify a webhook URL, or show desktop notifications")
+
+	n = &v1alpha1.Notification{Spec: v1alpha1.NotificationSpec{Desktop: true}}
+	assert.Empty(t, n.Validate(nil))
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	clock   clockwork.FakeClock
+	webhook *fakeWebhook
+	desktop *fakeDesktop
+}
+
+func newFixture(t *testing.T) *fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	clock := clockwork.NewFakeClock()
+	webURL, err := url.Parse("http://localhost:10350/")
+	require.NoError(t, err)
+
+	desktop := &fakeDesktop{}
+	r := NewReconciler(cfb.Client, clock, model.WebURL(*webURL), desktop.notify)
+	webhook := newFakeWebhook(t)
+	return &fixture{
+		ControllerFixture: cfb.Build(r),
+		clock:             clock,
+		webhook:           webhook,
+		desktop:           desktop,
+	}
+}
+
+func (f *fixture) createNotification(spec v1alpha1.NotificationSpec) {
+	spec.URL = f.webhook.server.URL
+	f.Create(&v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{Name: notificationName.Name},
+		Spec:       spec,
+	})
+}
+
+func (f *fixture) createResource(name string) {
+	err := f.Client.Create(f.Context(), &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	require.NoError(f.T(), err)
+}
+
+func (f *fixture) updateResource(name string, update func(uir *v1alpha1.UIResource)) {
+	var uir v1alpha1.UIResource
+	f.MustGet(types.NamespacedName{Name: name}, &uir)
+	update(&uir)
+	err := f.Client.Status().Update(f.Context(), &uir)
+	require.NoError(f.T(), err)
+}
+
+func (f *fixture) finishBuild(name, errMsg string) {
+	f.clock.Advance(time.Millisecond)
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		now := apis.NewMicroTime(f.clock.Now())
+		uir.Status.BuildHistory = append([]v1alpha1.UIBuildTerminated{{
+			StartTime:  now,
+			FinishTime: now,
+			Error:      errMsg,
+		}}, uir.Status.BuildHistory...)
+	})
+}
+
+func (f *fixture) setReady(name string, status metav1.ConditionStatus) {
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		c := v1alpha1.UIResourceCondition{Type: v1alpha1.UIResourceReady, Status: status}
+		if status == metav1.ConditionFalse {
+			c.Reason = "ContainersNotReady"
+			c.Message = "container crashed"
+		}
+		uir.Status.Conditions = []v1alpha1.UIResourceCondition{c}
+	})
+}
+
+func (f *fixture) setUpdateStatus(name string, status v1alpha1.UpdateStatus) {
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		uir.Status.UpdateStatus = status
+	})
+}
+
+func (f *fixture) setFiring(rule string, firing []v1alpha1.AlertFiring) {
+	var r v1alpha1.AlertRule
+	f.MustGet(types.NamespacedName{Name: rule}, &r)
+	r.Status.Firing = firing
+	err := f.Client.Status().Update(f.Context(), &r)
+	require.NoError(f.T(), err)
+}
+
+type fakeWebhook struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	received []string
+}
+
+func newFakeWebhook(t *testing.T) *fakeWebhook {
+	w := &fakeWebhook{status: http.StatusOK}
+	w.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.status != http.StatusOK {
+			rw.WriteHeader(w.status)
+			return
+		}
+
+		var payload webhookPayload
+		err := json.NewDecoder(req.Body).Decode(&payload)
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		w.received = append(w.received, payload.Text)
+	}))
+	t.Cleanup(w.server.Close)
+	return w
+}
+
+func (w *fakeWebhook) setStatus(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = status
+}
+
+func (w *fakeWebhook) messages() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string{}, w.received...)
+}
+
+type fakeDesktop struct {
+	mu       sync.Mutex
+	err      error
+	received []string
+}
+
+func (d *fakeDesktop) notify(ctx context.Context, title, body string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.received = append(d.received, title+"\n"+body)
+	return nil
+}
+
+func (d *fakeDesktop) messages() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.received...)
+}
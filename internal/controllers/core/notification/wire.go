@@ -0,0 +1,8 @@
+package notification
+
+import "github.com/google/wire"
+
+var WireSet = wire.NewSet(
+	NewReconciler,
+	ProvideDesktopNotify,
+)
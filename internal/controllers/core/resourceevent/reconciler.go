@@ -0,0 +1,207 @@
+package resourceevent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The number of events to keep. When there are more, we delete the oldest.
+const maxEvents = 1000
+
+// Watches resources for state changes, and records each one
+// as a ResourceEvent.
+type Reconciler struct {
+	client ctrlclient.Client
+	clock  clockwork.Clock
+
+	mu sync.Mutex
+
+	// The last state we saw of each resource.
+	resources map[string]resourceSnapshot
+
+	// The sequence number of the last event.
+	sequence int64
+
+	// The names of the events we've created, oldest first.
+	events []string
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func NewReconciler(client ctrlclient.Client, clock clockwork.Clock) *Reconciler {
+	return &Reconciler{
+		client:    client,
+		clock:     clock,
+		resources: make(map[string]resourceSnapshot),
+	}
+}
+
+func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("resourceevent").
+		For(&v1alpha1.UIResource{})
+
+	return b, nil
+}
+
+// The parts of a resource's state that we record events about.
+type resourceSnapshot struct {
+	currentBuildStartTime time.Time
+	lastBuildFinishTime   time.Time
+	lastBuildError        string
+	podName               string
+	podRestarts           int32
+	ready                 metav1.ConditionStatus
+	readyReason           string
+}
+
+func snapshot(uir *v1alpha1.UIResource) resourceSnapshot {
+	var s resourceSnapshot
+	if uir.Status.CurrentBuild != nil {
+		s.currentBuildStartTime = uir.Status.CurrentBuild.StartTime.Time
+	}
+	if len(uir.Status.BuildHistory) > 0 {
+		b := uir.Status.BuildHistory[0]
+		s.lastBuildFinishTime = b.FinishTime.Time
+		s.lastBuildError = b.Error
+	}
+	if info := uir.Status.K8sResourceInfo; info != nil {
+		s.podName = info.PodName
+		s.podRestarts = info.PodRestarts
+	}
+	for _, c := range uir.Status.Conditions {
+		if c.Type == v1alpha1.UIResourceReady {
+			s.ready = c.Status
+			s.readyReason = c.Reason
+			if c.Message != "" {
+				s.readyReason = c.Message
+			}
+		}
+	}
+	return s
+}
+
+// The events between two snapshots of a resource, in the order they
+// most likely happened.
+//
+// Events without a Time happened just now.
+func diff(prev, cur resourceSnapshot) []v1alpha1.ResourceEventSpec {
+	var result []v1alpha1.ResourceEventSpec
+	if cur.lastBuildFinishTime.After(prev.lastBuildFinishTime) {
+		e := v1alpha1.ResourceEventSpec{
+			Type: v1alpha1.ResourceEventTypeBuildSucceeded,
+			Time: apis.NewMicroTime(cur.lastBuildFinishTime),
+		}
+		if cur.lastBuildError != "" {
+			e.Type = v1alpha1.ResourceEventTypeBuildFailed
+			e.Error = cur.lastBuildError
+		}
+		result = append(result, e)
+	}
+	if !cur.currentBuildStartTime.IsZero() && !cur.currentBuildStartTime.Equal(prev.currentBuildStartTime) {
+		result = append(result, v1alpha1.ResourceEventSpec{
+			Type: v1alpha1.ResourceEventTypeBuildStarted,
+			Time: apis.NewMicroTime(cur.currentBuildStartTime),
+		})
+	}
+	if cur.podName != "" && cur.podName == prev.podName && cur.podRestarts > prev.podRestarts {
+		result = append(result, v1alpha1.ResourceEventSpec{
+			Type:        v1alpha1.ResourceEventTypePodRestarted,
+			PodName:     cur.podName,
+			PodRestarts: cur.podRestarts,
+		})
+	}
+	if cur.ready == metav1.ConditionTrue && prev.ready != metav1.ConditionTrue {
+		result = append(result, v1alpha1.ResourceEventSpec{Type: v1alpha1.ResourceEventTypeResourceReady})
+	}
+	if cur.ready == metav1.ConditionFalse && prev.ready == metav1.ConditionTrue {
+		result = append(result, v1alpha1.ResourceEventSpec{
+			Type:   v1alpha1.ResourceEventTypeResourceNotReady,
+			Reason: cur.readyReason,
+		})
+	}
+	return result
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := req.Name
+	uir := &v1alpha1.UIResource{}
+	err := r.client.Get(ctx, req.NamespacedName, uir)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if apierrors.IsNotFound(err) || uir.ObjectMeta.DeletionTimestamp != nil {
+		// Keep the resource's events, so watchers can still see them.
+		delete(r.resources, name)
+		return ctrl.Result{}, nil
+	}
+
+	// A resource that we haven't seen before starts from an empty state,
+	// so that watchers hear about builds that were already running.
+	cur := snapshot(uir)
+	prev := r.resources[name]
+	for _, spec := range diff(prev, cur) {
+		err := r.createEvent(ctx, name, spec)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	r.resources[name] = cur
+
+	return ctrl.Result{}, r.prune(ctx)
+}
+
+func (r *Reconciler) createEvent(ctx context.Context, resource string, spec v1alpha1.ResourceEventSpec) error {
+	seq := r.sequence + 1
+	spec.Resource = resource
+	spec.Sequence = seq
+	if spec.Time.IsZero() {
+		spec.Time = apis.NewMicroTime(r.clock.Now())
+	}
+
+	event := &v1alpha1.ResourceEvent{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", resource, seq)},
+		Spec:       spec,
+	}
+	// Some resource names, like (Tiltfile), aren't valid label values.
+	if len(validation.IsValidLabelValue(resource)) == 0 {
+		event.Labels = map[string]string{v1alpha1.LabelResourceEventResource: resource}
+	}
+	err := r.client.Create(ctx, event)
+	if err != nil {
+		return fmt.Errorf("creating %s event for %s: %v", spec.Type, resource, err)
+	}
+	r.sequence = seq
+	r.events = append(r.events, event.Name)
+	return nil
+}
+
+// Deletes the oldest events, so that we only keep the most recent ones.
+func (r *Reconciler) prune(ctx context.Context) error {
+	for len(r.events) > maxEvents {
+		err := r.client.Delete(ctx, &v1alpha1.ResourceEvent{ObjectMeta: metav1.ObjectMeta{Name: r.events[0]}})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		r.events = r.events[1:]
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package resourceevent
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestBuildEvents(t *testing.T) {
+	f := newFixture(t)
+	f.Create(&v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}})
+	assert.Empty(t, f.events())
+
+	f.startBuild("fe")
+	f.finishBuild("fe", "")
+	f.startBuild("fe")
+	f.finishBuild("fe", "compile error")
+
+	events := f.events()
+	assert.Equal(t, []v1alpha1.ResourceEventType{
+		v1alpha1.ResourceEventTypeBuildStarted,
+		v1alpha1.ResourceEventTypeBuildSucceeded,
+		v1alpha1.ResourceEventTypeBuildStarted,
+		v1alpha1.ResourceEventTypeBuildFailed,
+	}, eventTypes(events))
+	for i, e := range events {
+		assert.Equal(t, int64(i+1), e.Spec.Sequence)
+		assert.Equal(t, "fe", e.Spec.Resource)
+		assert.Equal(t, "fe", e.Labels[v1alpha1.LabelResourceEventResource])
+	}
+	assert.Equal(t, "compile error", events[3].Spec.Error)
+
+	// Build events have the times from the build.
+	assert.True(t, events[3].Spec.Time.After(events[2].Spec.Time.Time))
+	assert.True(t, f.clock.Now().Equal(events[3].Spec.Time.Time))
+}
+
+func TestBuildFinishedAndRestartedBetweenReconciles(t *testing.T) {
+	f := newFixture(t)
+	f.Create(&v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}})
+
+	f.updateResource("fe", func(uir *v1alpha1.UIResource) {
+		now := apis.NowMicro()
+		uir.Status.BuildHistory = []v1alpha1.UIBuildTerminated{{StartTime: now, FinishTime: now}}
+		uir.Status.CurrentBuild = &v1alpha1.UIBuildRunning{StartTime: apis.NewMicroTime(now.Add(time.Second))}
+	})
+
+	assert.Equal(t, []v1alpha1.ResourceEventType{
+		v1alpha1.ResourceEventTypeBuildSucceeded,
+		v1alpha1.ResourceEventTypeBuildStarted,
+	}, eventTypes(f.events()))
+}
+
+func TestNoLabelForInvalidLabelValue(t *testing.T) {
+	f := newFixture(t)
+	f.Create(&v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "(Tiltfile)"}})
+	f.startBuild("(Tiltfile)")
+
+	events := f.events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "(Tiltfile)", events[0].Spec.Resource)
+	assert.Empty(t, events[0].Labels)
+}
+
+func TestReadinessEvents(t *testing.T) {
+	f := newFixture(t)
+	f.Create(&v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}})
+
+	f.setReady("fe", metav1.ConditionTrue)
+	f.setReady("fe", metav1.ConditionFalse)
+
+	events := f.events()
+	assert.Equal(t, []v1alpha1.ResourceEventType{
+		v1alpha1.ResourceEventTypeResourceReady,
+		v1alpha1.ResourceEventTypeResourceNotReady,
+	}, eventTypes(events))
+	assert.Equal(t, "container crashed", events[1].Spec.Reason)
+}
+
+func TestPodRestartedEvents(t *testing.T) {
+	f := newFixture(t)
+	f.Create(&v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}})
+
+	f.setPod("fe", "fe-abc", 0)
+	f.setPod("fe", "fe-abc", 2)
+
+	// A new pod isn't a restart.
+	f.setPod("fe", "fe-def", 0)
+
+	events := f.events()
+	require.Equal(t, []v1alpha1.ResourceEventType{v1alpha1.ResourceEventTypePodRestarted}, eventTypes(events))
+	assert.Equal(t, "fe-abc", events[0].Spec.PodName)
+	assert.Equal(t, int32(2), events[0].Spec.PodRestarts)
+}
+
+func TestDeletedResourceKeepsEvents(t *testing.T) {
+	f := newFixture(t)
+	uir := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}}
+	f.Create(uir)
+	f.startBuild("fe")
+
+	f.Delete(uir)
+	assert.Len(t, f.events(), 1)
+}
+
+func TestPruneOldEvents(t *testing.T) {
+	f := newFixture(t)
+	f.Create(&v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "fe"}})
+
+	for i := 0; i < maxEvents/2+1; i++ {
+		f.startBuild("fe")
+		f.finishBuild("fe", "")
+	}
+
+	events := f.events()
+	require.Len(t, events, maxEvents)
+	assert.Equal(t, int64(3), events[0].Spec.Sequence)
+	assert.Equal(t, int64(maxEvents+2), events[len(events)-1].Spec.Sequence)
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	clock clockwork.FakeClock
+}
+
+func newFixture(t *testing.T) *fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	clock := clockwork.NewFakeClock()
+	r := NewReconciler(cfb.Client, clock)
+	return &fixture{
+		ControllerFixture: cfb.Build(r),
+		clock:             clock,
+	}
+}
+
+func (f *fixture) updateResource(name string, update func(uir *v1alpha1.UIResource)) {
+	var uir v1alpha1.UIResource
+	f.MustGet(types.NamespacedName{Name: name}, &uir)
+	update(&uir)
+	f.UpdateStatus(&uir)
+}
+
+func (f *fixture) startBuild(name string) {
+	f.clock.Advance(time.Second)
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		uir.Status.CurrentBuild = &v1alpha1.UIBuildRunning{StartTime: apis.NewMicroTime(f.clock.Now())}
+	})
+}
+
+func (f *fixture) finishBuild(name, errMsg string) {
+	f.clock.Advance(time.Second)
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		uir.Status.BuildHistory = append([]v1alpha1.UIBuildTerminated{{
+			StartTime:  uir.Status.CurrentBuild.StartTime,
+			FinishTime: apis.NewMicroTime(f.clock.Now()),
+			Error:      errMsg,
+		}}, uir.Status.BuildHistory...)
+		uir.Status.CurrentBuild = nil
+	})
+}
+
+func (f *fixture) setReady(name string, status metav1.ConditionStatus) {
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		c := v1alpha1.UIResourceCondition{Type: v1alpha1.UIResourceReady, Status: status}
+		if status == metav1.ConditionFalse {
+			c.Reason = "ContainersNotReady"
+			c.Message = "container crashed"
+		}
+		uir.Status.Conditions = []v1alpha1.UIResourceCondition{c}
+	})
+}
+
+func (f *fixture) setPod(name, podName string, restarts int32) {
+	f.updateResource(name, func(uir *v1alpha1.UIResource) {
+		uir.Status.K8sResourceInfo = &v1alpha1.UIResourceKubernetes{PodName: podName, PodRestarts: restarts}
+	})
+}
+
+// All the events, in order.
+func (f *fixture) events() []v1alpha1.ResourceEvent {
+	var list v1alpha1.ResourceEventList
+	f.List(&list)
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Spec.Sequence < list.Items[j].Spec.Sequence
+	})
+	return list.Items
+}
+
+func eventTypes(events []v1alpha1.ResourceEvent) []v1alpha1.ResourceEventType {
+	var result []v1alpha1.ResourceEventType
+	for _, e := range events {
+		result = append(result, e.Spec.Type)
+	}
+	return result
+}
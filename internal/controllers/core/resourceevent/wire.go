@@ -0,0 +1,7 @@
+package resourceevent
+
+import "github.com/google/wire"
+
+var WireSet = wire.NewSet(
+	NewReconciler,
+)
@@ -176,6 +176,138 @@ func TestExitControlCI_Timeout(t *testing.T) {
 	f.requireDoneWithError("Timeout after 1m0s")
 }
 
+func TestExitControlCI_ReadinessTimeout(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+
+	var session v1alpha1.Session
+	f.MustGet(types.NamespacedName{Name: "Tiltfile"}, &session)
+	session.Spec.CI = &v1alpha1.SessionCISpec{
+		ReadinessTimeout: &metav1.Duration{Duration: time.Minute},
+		ResourceReadinessTimeouts: map[string]metav1.Duration{
+			"slow": {Duration: 5 * time.Minute},
+		},
+	}
+	f.Update(&session)
+
+	f.upsertBuiltManifest("fe")
+	f.upsertBuiltManifest("slow")
+
+	result, err := f.Reconcile(sessionKey)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	f.requireNotDone()
+
+	f.clock.Advance(50 * time.Second)
+	result, err = f.Reconcile(sessionKey)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, result.RequeueAfter)
+	f.requireNotDone()
+
+	f.clock.Advance(20 * time.Second)
+	f.MustReconcile(sessionKey)
+	f.requireDoneWithError("fe: not ready after 1m0s")
+}
+
+func TestExitControlCI_ResourceReadinessTimeoutOverride(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+
+	var session v1alpha1.Session
+	f.MustGet(types.NamespacedName{Name: "Tiltfile"}, &session)
+	session.Spec.CI = &v1alpha1.SessionCISpec{
+		ReadinessTimeout: &metav1.Duration{Duration: time.Minute},
+		ResourceReadinessTimeouts: map[string]metav1.Duration{
+			"slow": {Duration: 5 * time.Minute},
+		},
+	}
+	f.Update(&session)
+
+	f.upsertBuiltManifest("slow")
+
+	f.clock.Advance(2 * time.Minute)
+	result, err := f.Reconcile(sessionKey)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Minute, result.RequeueAfter)
+	f.requireNotDone()
+
+	f.clock.Advance(4 * time.Minute)
+	f.MustReconcile(sessionKey)
+	f.requireDoneWithError("slow: not ready after 5m0s")
+}
+
+func TestExitControlCI_CollectAll(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+
+	var session v1alpha1.Session
+	f.MustGet(types.NamespacedName{Name: "Tiltfile"}, &session)
+	session.Spec.CI = &v1alpha1.SessionCISpec{FailurePolicy: v1alpha1.CIFailurePolicyCollectAll}
+	f.Update(&session)
+
+	for _, name := range []model.ManifestName{"fe", "fe2", "fe3"} {
+		m := manifestbuilder.New(f, name).
+			WithK8sYAML(testyaml.SanchoYAML).
+			WithK8sPodReadiness(model.PodReadinessWait).
+			Build()
+		f.upsertManifest(m)
+	}
+	f.Store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  f.clock.Now(),
+			FinishTime: f.clock.Now(),
+			Error:      fmt.Errorf("does not compile"),
+		})
+	})
+
+	// fe failed, but keep going until the others are done.
+	f.MustReconcile(sessionKey)
+	f.requireNotDone()
+
+	f.Store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe2"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  f.clock.Now(),
+			FinishTime: f.clock.Now(),
+			Error:      fmt.Errorf("bad yaml"),
+		})
+		mt := state.ManifestTargets["fe3"]
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  f.clock.Now(),
+			FinishTime: f.clock.Now(),
+		})
+		mt.State.RuntimeState = store.NewK8sRuntimeStateWithPods(mt.Manifest, pod("pod-c", true))
+	})
+
+	f.MustReconcile(sessionKey)
+	f.requireDoneWithError("2 failures: fe2: bad yaml; fe: does not compile")
+}
+
+func TestExitControlCI_CollectAllSkipsBlockedDependents(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+
+	var session v1alpha1.Session
+	f.MustGet(types.NamespacedName{Name: "Tiltfile"}, &session)
+	session.Spec.CI = &v1alpha1.SessionCISpec{FailurePolicy: v1alpha1.CIFailurePolicyCollectAll}
+	f.Update(&session)
+
+	db := manifestbuilder.New(f, "db").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(db)
+	fe := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SanchoYAML).
+		WithResourceDeps("db").
+		Build()
+	f.upsertManifest(fe)
+
+	f.Store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["db"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  f.clock.Now(),
+			FinishTime: f.clock.Now(),
+			Error:      fmt.Errorf("does not compile"),
+		})
+	})
+
+	// fe will never start, so there's nothing left to wait for.
+	f.MustReconcile(sessionKey)
+	f.requireDoneWithError("db: does not compile")
+}
+
 func TestExitControlCI_PodRunningContainerError(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 
@@ -722,6 +854,18 @@ func (f *fixture) Path() string {
 	return f.tf.Path()
 }
 
+// Adds a Kubernetes resource that finished its first update, and has no pods yet.
+func (f *fixture) upsertBuiltManifest(mn model.ManifestName) {
+	m := manifestbuilder.New(f, mn).WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m)
+	f.Store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets[mn].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  f.clock.Now(),
+			FinishTime: f.clock.Now(),
+		})
+	})
+}
+
 func (f *fixture) upsertFailingPod(mn model.ManifestName) {
 	m := manifestbuilder.New(f, mn).WithK8sYAML(testyaml.SanchoYAML).Build()
 	f.upsertManifest(m)
@@ -3,6 +3,8 @@ package session
 import (
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -41,22 +43,19 @@ func (r *Reconciler) makeLatestStatus(session *v1alpha1.Session, result *ctrl.Re
 		return status.Targets[i].Name < status.Targets[j].Name
 	})
 
-	r.processExitCondition(session.Spec, &state, &status)
+	r.processExitCondition(session.Spec, &state, &status, result)
 
 	// If there's a global timeout, schedule a requeue.
 	ci := session.Spec.CI
 	if ci != nil && ci.Timeout != nil && ci.Timeout.Duration > 0 {
 		timeout := ci.Timeout.Duration
-		requeueAfter := timeout - r.clock.Since(session.Status.StartTime.Time)
-		if result.RequeueAfter == 0 || result.RequeueAfter > requeueAfter {
-			result.RequeueAfter = requeueAfter
-		}
+		requeueAfter(result, timeout-r.clock.Since(session.Status.StartTime.Time))
 	}
 
 	return status
 }
 
-func (r *Reconciler) processExitCondition(spec v1alpha1.SessionSpec, state *store.EngineState, status *v1alpha1.SessionStatus) {
+func (r *Reconciler) processExitCondition(spec v1alpha1.SessionSpec, state *store.EngineState, status *v1alpha1.SessionStatus, result *ctrl.Result) {
 	exitCondition := spec.ExitCondition
 	if exitCondition == v1alpha1.ExitConditionManual {
 		return
@@ -65,6 +64,15 @@ func (r *Reconciler) processExitCondition(spec v1alpha1.SessionSpec, state *stor
 		status.Error = fmt.Sprintf("unsupported exit condition: %s", exitCondition)
 	}
 
+	ci := spec.CI
+	collectAll := ci != nil && ci.FailurePolicy == v1alpha1.CIFailurePolicyCollectAll
+
+	// In CollectAll mode, we keep track of which resources have failed
+	// and which are still pending, and only exit once nothing is pending.
+	var failures []string
+	failed := make(map[string]bool)
+	pending := make(map[string]bool)
+
 	allResourcesOK := true
 	for _, res := range status.Targets {
 		if res.State.Waiting == nil && res.State.Active == nil && res.State.Terminated == nil {
@@ -76,6 +84,7 @@ func (r *Reconciler) processExitCondition(spec v1alpha1.SessionSpec, state *stor
 		if isTerminated {
 			if res.State.Terminated.GraceStatus == v1alpha1.TargetGraceTolerated {
 				allResourcesOK = false
+				markResources(pending, res)
 				continue
 			}
 
@@ -84,15 +93,68 @@ func (r *Reconciler) processExitCondition(spec v1alpha1.SessionSpec, state *stor
 				err = fmt.Sprintf("exceeded grace period: %v", err)
 			}
 
-			status.Done = true
-			status.Error = err
-			return
+			if !collectAll {
+				status.Done = true
+				status.Error = err
+				return
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", strings.Join(res.Resources, ","), err))
+			markResources(failed, res)
+			continue
 		}
 		if res.State.Waiting != nil {
 			allResourcesOK = false
+			markResources(pending, res)
 		} else if res.State.Active != nil && (!res.State.Active.Ready || res.Type == v1alpha1.TargetTypeJob) {
 			// jobs must run to completion
 			allResourcesOK = false
+			markResources(pending, res)
+		}
+	}
+
+	// Enforce per-resource readiness timeouts.
+	for _, name := range sortedKeys(pending) {
+		if failed[name] {
+			continue
+		}
+		mt, ok := state.ManifestTargets[model.ManifestName(name)]
+		if !ok {
+			continue
+		}
+		timeout := readinessTimeout(ci, name)
+		start := firstUpdateStartTime(mt.State)
+		if timeout <= 0 || start.IsZero() {
+			continue
+		}
+
+		elapsed := r.clock.Since(start)
+		if elapsed <= timeout {
+			requeueAfter(result, timeout-elapsed)
+			continue
+		}
+
+		err := fmt.Sprintf("%s: not ready after %s", name, timeout)
+		if !collectAll {
+			status.Done = true
+			status.Error = err
+			return
+		}
+		failures = append(failures, err)
+		failed[name] = true
+	}
+
+	if len(failures) > 0 {
+		stillPending := false
+		for name := range pending {
+			if !failed[name] && !dependsOnFailure(state, model.ManifestName(name), failed, make(map[model.ManifestName]bool)) {
+				stillPending = true
+				break
+			}
+		}
+		if !stillPending {
+			status.Done = true
+			status.Error = summarizeFailures(failures)
+			return
 		}
 	}
 
@@ -103,11 +165,83 @@ func (r *Reconciler) processExitCondition(spec v1alpha1.SessionSpec, state *stor
 	}
 
 	// Enforce a global timeout.
-	ci := spec.CI
 	if status.Error == "" && ci != nil && ci.Timeout != nil && ci.Timeout.Duration > 0 &&
 		r.clock.Since(status.StartTime.Time) > ci.Timeout.Duration {
 		status.Done = true
-		status.Error = fmt.Sprintf("Timeout after %s", ci.Timeout.Duration)
+		status.Error = summarizeFailures(append(failures, fmt.Sprintf("Timeout after %s", ci.Timeout.Duration)))
+	}
+}
+
+func markResources(set map[string]bool, target v1alpha1.Target) {
+	for _, name := range target.Resources {
+		set[name] = true
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// The readiness timeout for a resource, or 0 if it has none.
+func readinessTimeout(ci *v1alpha1.SessionCISpec, name string) time.Duration {
+	if ci == nil {
+		return 0
+	}
+	if timeout, ok := ci.ResourceReadinessTimeouts[name]; ok {
+		return timeout.Duration
+	}
+	if ci.ReadinessTimeout != nil {
+		return ci.ReadinessTimeout.Duration
+	}
+	return 0
+}
+
+// When the resource started its first update, or zero if it hasn't started one.
+func firstUpdateStartTime(ms *store.ManifestState) time.Time {
+	if len(ms.BuildHistory) > 0 {
+		// The most recent build is first.
+		return ms.BuildHistory[len(ms.BuildHistory)-1].StartTime
+	}
+	if ms.IsBuilding() {
+		return ms.EarliestCurrentBuild().StartTime
+	}
+	return time.Time{}
+}
+
+// Whether a resource depends on a failed resource, and so will never start.
+func dependsOnFailure(state *store.EngineState, name model.ManifestName, failed map[string]bool, visited map[model.ManifestName]bool) bool {
+	if visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	mt, ok := state.ManifestTargets[name]
+	if !ok {
+		return false
+	}
+	for _, dep := range mt.Manifest.ResourceDependencies {
+		if failed[dep.String()] || dependsOnFailure(state, dep, failed, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func summarizeFailures(failures []string) string {
+	if len(failures) == 1 {
+		return failures[0]
+	}
+	return fmt.Sprintf("%d failures: %s", len(failures), strings.Join(failures, "; "))
+}
+
+func requeueAfter(result *ctrl.Result, d time.Duration) {
+	if result.RequeueAfter == 0 || result.RequeueAfter > d {
+		result.RequeueAfter = d
 	}
 }
 
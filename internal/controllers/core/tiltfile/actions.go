@@ -5,6 +5,7 @@ import (
 
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 
+	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
@@ -34,15 +35,21 @@ type ConfigsReloadedAction struct {
 	TeamID               string
 	TelemetrySettings    model.TelemetrySettings
 	Secrets              model.SecretSet
+	RedactPatterns       model.RedactPatterns
 	DockerPruneSettings  model.DockerPruneSettings
 	AnalyticsTiltfileOpt analytics.Opt
 	VersionSettings      model.VersionSettings
 	UpdateSettings       model.UpdateSettings
 	WatchSettings        model.WatchSettings
+	GroupSettings        model.GroupSettings
 
 	// A checkpoint into the logstore when Tiltfile execution started.
 	// Useful for knowing how far back in time we have to scrub secrets.
 	CheckpointAtExecStart logstore.Checkpoint
+
+	// Images that an earlier session built, which new resources can reuse
+	// instead of building them again.
+	RestoredImages map[model.TargetID]store.ImageBuildResult
 }
 
 func (ConfigsReloadedAction) Action() {}
@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/uibutton"
 	"github.com/tilt-dev/tilt/internal/controllers/apiset"
@@ -121,6 +122,20 @@ func updateOwnedObjects(
 		}
 	}
 
+	// Keep log levels changed at runtime, unless the Tiltfile changed its levels too.
+	newConfigMaps := apiObjects.GetSetForType(&v1alpha1.ConfigMap{})
+	oldConfigMaps := existingObjects.GetSetForType(&v1alpha1.ConfigMap{})
+	for name, obj := range newConfigMaps {
+		tiltfileLevels, ok := obj.GetAnnotations()[configmap.AnnotationTiltfileLogLevels]
+		if !ok {
+			continue
+		}
+		old, ok := oldConfigMaps[name]
+		if ok && old.GetAnnotations()[configmap.AnnotationTiltfileLogLevels] == tiltfileLevels {
+			newConfigMaps[name] = old
+		}
+	}
+
 	err = updateNewObjects(ctx, client, apiObjects, existingObjects)
 	if err != nil {
 		return err
@@ -184,6 +199,11 @@ var typesWithTiltfileBuiltins = []apiset.Object{
 	&v1alpha1.UIButton{},
 	&v1alpha1.ConfigMap{},
 	&v1alpha1.KubernetesDiscovery{},
+	&v1alpha1.Notification{},
+	&v1alpha1.UIPanel{},
+	&v1alpha1.AlertRule{},
+	&v1alpha1.HealthProbe{},
+	&v1alpha1.LogSink{},
 }
 
 var typesToReconcile = append([]apiset.Object{
@@ -243,7 +263,7 @@ func toAPIObjects(
 
 		result.AddSetForType(&v1alpha1.KubernetesApply{}, toKubernetesApplyObjects(tlr, disableSources))
 		result.AddSetForType(&v1alpha1.DockerComposeService{}, toDockerComposeServiceObjects(tlr, disableSources))
-		result.AddSetForType(&v1alpha1.ConfigMap{}, toDisableConfigMaps(disableSources, tlr.EnabledManifests))
+		result.AddSetForType(&v1alpha1.ConfigMap{}, toConfigMaps(nn, tlr, disableSources))
 		result.AddSetForType(&v1alpha1.Cmd{}, toCmdObjects(tlr, disableSources))
 		result.AddSetForType(&v1alpha1.ToggleButton{}, toToggleButtons(disableSources))
 		result.AddSetForType(&v1alpha1.Cluster{}, toClusterObjects(nn, tlr, defaultK8sConnection))
@@ -327,6 +347,17 @@ func mergeDisableSource(existing *v1alpha1.DisableSource, toMerge *v1alpha1.Disa
 	return &v1alpha1.DisableSource{EveryConfigMap: cms}
 }
 
+func toConfigMaps(nn types.NamespacedName, tlr *tiltfile.TiltfileLoadResult, disableSources disableSourceMap) apiset.TypedObjectSet {
+	result := toDisableConfigMaps(disableSources, tlr.EnabledManifests)
+	cm := configmap.NewArgsSchemaConfigMap(nn.Name, tlr.ArgsSchema)
+	result[cm.Name] = cm
+	for _, m := range tlr.Manifests {
+		cm := configmap.NewLogLevelsConfigMap(m.Name, m.LogLevels)
+		result[cm.Name] = cm
+	}
+	return result
+}
+
 func toDisableConfigMaps(disableSources disableSourceMap, enabledResources []model.ManifestName) apiset.TypedObjectSet {
 	enabledResourceSet := make(map[model.ManifestName]bool)
 	for _, mn := range enabledResources {
@@ -730,13 +761,15 @@ func toUIResourceObjects(tf *v1alpha1.Tiltfile, tlr *tiltfile.TiltfileLoadResult
 			r := &v1alpha1.UIResource{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:   name,
-					Labels: m.Labels,
+					Labels: m.APILabels(),
 					Annotations: map[string]string{
 						v1alpha1.AnnotationManifest: m.Name.String(),
 					},
 				},
 			}
 
+			r.Status.Groups = m.GroupPaths()
+
 			ds := disableSources[m.Name]
 			if ds != nil {
 				r.Status.DisableStatus.State = v1alpha1.DisableStatePending
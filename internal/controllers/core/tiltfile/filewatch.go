@@ -55,6 +55,7 @@ func addGlobalIgnoresToSpec(spec *v1alpha1.FileWatchSpec, globalIgnores []model.
 		spec.Ignores = append(spec.Ignores, v1alpha1.IgnoreDef{
 			BasePath: gi.LocalPath,
 			Patterns: append([]string(nil), gi.Patterns...),
+			Reason:   gi.Description(),
 		})
 	}
 }
@@ -73,7 +73,7 @@ func TestFileWatch_IgnoreOutputsImageRefs(t *testing.T) {
 	f.RequireFileWatchSpecEqual(target.ID(), v1alpha1.FileWatchSpec{
 		WatchedPaths: []string{f.Path()},
 		Ignores: []v1alpha1.IgnoreDef{
-			{BasePath: f.Path(), Patterns: []string{"ref.txt"}},
+			{BasePath: f.Path(), Patterns: []string{"ref.txt"}, Reason: "outputs_image_ref_to"},
 		},
 	})
 }
@@ -132,6 +132,34 @@ func TestFileWatch_IgnoreWatchSettings(t *testing.T) {
 	})
 }
 
+func TestFileWatch_IgnoreWatchSettingsReason(t *testing.T) {
+	f := newFWFixture(t)
+
+	target := model.LocalTarget{
+		Name: "foo",
+		Deps: []string{"."},
+	}
+	f.SetManifestLocalTarget(target)
+
+	f.inputs.WatchSettings.Ignores = append(f.inputs.WatchSettings.Ignores, model.Dockerignore{
+		LocalPath: f.Path(),
+		Patterns:  []string{"**/*.pb.go"},
+		Source:    "watch_settings()",
+		Reason:    "generated protobuf code",
+	})
+
+	f.RequireFileWatchSpecEqual(target.ID(), v1alpha1.FileWatchSpec{
+		WatchedPaths: []string{"."},
+		Ignores: []v1alpha1.IgnoreDef{
+			{
+				BasePath: f.Path(),
+				Patterns: []string{"**/*.pb.go"},
+				Reason:   "watch_settings(): generated protobuf code",
+			},
+		},
+	})
+}
+
 func TestFileWatch_PickUpTiltIgnoreChanges(t *testing.T) {
 	f := newFWFixture(t)
 
@@ -23,7 +23,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/trigger"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
+	"github.com/tilt-dev/tilt/internal/devenv"
 	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/engine/checkpoint"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -31,6 +33,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store/tiltfiles"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
 	"github.com/tilt-dev/tilt/internal/timecmp"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -50,6 +53,12 @@ type Reconciler struct {
 	engineMode           store.EngineMode
 	loadCount            int // used to differentiate spans
 	ciTimeoutFlag        model.CITimeoutFlag
+	base                 xdg.Base
+	devEnv               devenv.Environment
+
+	// Whether we've restored the images of the last session's checkpoint.
+	// We only do it on the first successful load of the main Tiltfile.
+	restoredCheckpoint bool
 
 	runs map[types.NamespacedName]*runStatus
 
@@ -89,6 +98,8 @@ func NewReconciler(
 	k8sContextOverride k8s.KubeContextOverride,
 	k8sNamespaceOverride k8s.NamespaceOverride,
 	ciTimeoutFlag model.CITimeoutFlag,
+	base xdg.Base,
+	devEnv devenv.Environment,
 ) *Reconciler {
 	return &Reconciler{
 		st:                   st,
@@ -102,6 +113,8 @@ func NewReconciler(
 		k8sContextOverride:   k8sContextOverride,
 		k8sNamespaceOverride: k8sNamespaceOverride,
 		ciTimeoutFlag:        ciTimeoutFlag,
+		base:                 base,
+		devEnv:               devEnv,
 	}
 }
 
@@ -198,7 +211,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: run.untilRerun(time.Now())}, nil
 }
 
 // Modeled after BuildController.needsBuild and NextBuildReason(). Check to see that:
@@ -237,6 +250,8 @@ func (r *Reconciler) needsBuild(
 			reason = reason.With(model.BuildReasonFlagChangedFiles)
 		} else if timecmp.After(lastRestartEvent, lastStartTime) {
 			reason = reason.With(model.BuildReasonFlagTriggerUnknown)
+		} else if run.rerunDue(time.Now()) {
+			reason = reason.With(model.BuildReasonFlagTriggerTimer)
 		}
 	}
 
@@ -316,6 +331,12 @@ func (r *Reconciler) run(ctx context.Context, nn types.NamespacedName, tf *v1alp
 
 	tlr := r.tfl.Load(ctx, tf, run.tlr)
 
+	// Push images to the dev environment's registry, unless the Tiltfile
+	// chose one with default_registry().
+	if tlr.DefaultRegistry == nil && r.devEnv.Registry != nil {
+		tlr.DefaultRegistry = r.devEnv.Registry.DeepCopy()
+	}
+
 	// If the user is executing an empty main tiltfile, that probably means
 	// they need a tutorial. For now, we link to that tutorial, but a more interactive
 	// system might make sense here.
@@ -372,6 +393,15 @@ func (r *Reconciler) handleLoaded(
 		logger.Get(ctx).Errorf("%s", tlr.Error.Error())
 	}
 
+	var restoredImages map[model.TargetID]store.ImageBuildResult
+	if tlr.Error == nil && !r.restoredCheckpoint && nn.Name == model.MainTiltfileManifestName.String() {
+		r.restoredCheckpoint = true
+		restoredImages, err = checkpoint.Restore(ctx, r.base, tf.Spec.Path, tlr.Manifests)
+		if err != nil {
+			logger.Get(ctx).Debugf("Restoring checkpoint: %v", err)
+		}
+	}
+
 	r.st.Dispatch(ConfigsReloadedAction{
 		Name:                  entry.Name,
 		Manifests:             tlr.Manifests,
@@ -383,12 +413,15 @@ func (r *Reconciler) handleLoaded(
 		TeamID:                tlr.TeamID,
 		TelemetrySettings:     tlr.TelemetrySettings,
 		Secrets:               tlr.Secrets,
+		RedactPatterns:        tlr.RedactPatterns,
 		AnalyticsTiltfileOpt:  tlr.AnalyticsOpt,
 		DockerPruneSettings:   tlr.DockerPruneSettings,
 		CheckpointAtExecStart: entry.CheckpointAtExecStart,
 		VersionSettings:       tlr.VersionSettings,
 		UpdateSettings:        tlr.UpdateSettings,
 		WatchSettings:         tlr.WatchSettings,
+		GroupSettings:         tlr.GroupSettings,
+		RestoredImages:        restoredImages,
 	})
 
 	run, ok := r.runs[nn]
@@ -455,12 +488,21 @@ func (r *Reconciler) defaultK8sConnection() *v1alpha1.KubernetesClusterConnectio
 	}
 }
 
+// Only checks enabled resources, so that a Tiltfile where everything that
+// needs Docker is disabled never connects to it.
 func requiresDocker(tlr tiltfile.TiltfileLoadResult) bool {
-	if tlr.HasOrchestrator(model.OrchestratorDC) {
-		return true
+	enabled := make(map[model.ManifestName]bool, len(tlr.EnabledManifests))
+	for _, name := range tlr.EnabledManifests {
+		enabled[name] = true
 	}
 
 	for _, m := range tlr.Manifests {
+		if !enabled[m.Name] {
+			continue
+		}
+		if m.IsDC() {
+			return true
+		}
 		for _, iTarget := range m.ImageTargets {
 			if iTarget.IsDockerBuild() {
 				return true
@@ -501,6 +543,28 @@ type runStatus struct {
 	finishTime time.Time
 }
 
+// Whether the Tiltfile asked to be re-executed with rerun_after(),
+// and it's time to do so.
+func (rs *runStatus) rerunDue(now time.Time) bool {
+	if rs == nil || rs.step != runStepDone || rs.tlr == nil || rs.tlr.RerunAfter <= 0 {
+		return false
+	}
+	return !now.Before(rs.finishTime.Add(rs.tlr.RerunAfter))
+}
+
+// How long until the next scheduled rerun, or zero if there's nothing scheduled.
+func (rs *runStatus) untilRerun(now time.Time) time.Duration {
+	if rs == nil || rs.step != runStepDone || rs.tlr == nil || rs.tlr.RerunAfter <= 0 {
+		return 0
+	}
+	d := rs.finishTime.Add(rs.tlr.RerunAfter).Sub(now)
+	if d <= 0 {
+		// A zero RequeueAfter means "don't requeue", so make sure we come back.
+		return time.Millisecond
+	}
+	return d
+}
+
 func (rs *runStatus) TiltfileStatus() v1alpha1.TiltfileStatus {
 	switch rs.step {
 	case runStepRunning, runStepLoaded:
@@ -24,6 +24,7 @@ import (
 	configmap2 "github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/uibutton"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/devenv"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -31,7 +32,10 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
+	tiltfileconfig "github.com/tilt-dev/tilt/internal/tiltfile/config"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
@@ -184,6 +188,38 @@ func TestCluster(t *testing.T) {
 	}
 }
 
+func TestClusterDevEnvRegistry(t *testing.T) {
+	f := newFixture(t)
+	p := f.tempdir.JoinPath("Tiltfile")
+	f.r.devEnv = devenv.Environment{
+		Registry: &v1alpha1.RegistryHosting{Host: "registry.dev.example.com"},
+	}
+
+	sancho := manifestbuilder.New(f.tempdir, "sancho").
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	f.tfl.Result = tiltfile.TiltfileLoadResult{
+		Manifests: []model.Manifest{sancho},
+	}
+
+	tf := v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: model.MainTiltfileManifestName.String(),
+		},
+		Spec: v1alpha1.TiltfileSpec{
+			Path: p,
+		},
+	}
+	f.createAndWaitForLoaded(&tf)
+	assert.Equal(t, "", tf.Status.Terminated.Error)
+
+	var cl v1alpha1.Cluster
+	f.MustGet(types.NamespacedName{Name: "default"}, &cl)
+	if assert.NotNil(t, cl.Spec.DefaultRegistry) {
+		assert.Equal(t, "registry.dev.example.com", cl.Spec.DefaultRegistry.Host)
+	}
+}
+
 func TestLocalServe(t *testing.T) {
 	f := newFixture(t)
 	p := f.tempdir.JoinPath("Tiltfile")
@@ -229,7 +265,7 @@ func TestDockerMetrics(t *testing.T) {
 
 	f.tfl.Result = tiltfile.TiltfileLoadResult{
 		Manifests: []model.Manifest{sancho},
-	}
+	}.WithAllManifestsEnabled()
 
 	tf := v1alpha1.Tiltfile{
 		ObjectMeta: metav1.ObjectMeta{
@@ -253,6 +289,34 @@ func TestDockerMetrics(t *testing.T) {
 	assert.ElementsMatch(t, []analytics.CountEvent{connectEvt}, f.ma.Counts)
 }
 
+func TestDockerNotCheckedForDisabledResources(t *testing.T) {
+	f := newFixture(t)
+	p := f.tempdir.JoinPath("Tiltfile")
+
+	sanchoImage := model.MustNewImageTarget(container.MustParseSelector("sancho-image")).
+		WithDockerImage(v1alpha1.DockerImageSpec{Context: f.tempdir.Path()})
+	sancho := manifestbuilder.New(f.tempdir, "sancho").
+		WithImageTargets(sanchoImage).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+
+	f.tfl.Result = tiltfile.TiltfileLoadResult{
+		Manifests: []model.Manifest{sancho},
+	}
+
+	tf := v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-tf",
+		},
+		Spec: v1alpha1.TiltfileSpec{
+			Path: p,
+		},
+	}
+	f.createAndWaitForLoaded(&tf)
+
+	assert.Empty(t, f.ma.Counts)
+}
+
 func TestArgsChangeResetsEnabledResources(t *testing.T) {
 	f := newFixture(t)
 	p := f.tempdir.JoinPath("Tiltfile")
@@ -368,6 +432,108 @@ func TestTiltfileFailurePreservesEnabledResources(t *testing.T) {
 	f.requireEnabled(m2, false)
 }
 
+func TestArgsSchema(t *testing.T) {
+	f := newFixture(t)
+	p := f.tempdir.JoinPath("Tiltfile")
+
+	schema := tiltfileconfig.ArgsSchema{
+		Parsed:   true,
+		Settings: []tiltfileconfig.ArgSetting{{Name: "env", Type: "string"}},
+	}
+	f.tfl.Result = tiltfile.TiltfileLoadResult{ArgsSchema: schema}
+
+	tf := v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-tf",
+		},
+		Spec: v1alpha1.TiltfileSpec{
+			Path: p,
+		},
+	}
+	f.createAndWaitForLoaded(&tf)
+
+	actual, ok, err := configmap2.GetArgsSchema(f.Context(), f.Client, "my-tf")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, schema, actual)
+}
+
+func TestLogLevels(t *testing.T) {
+	f := newFixture(t)
+	p := f.tempdir.JoinPath("Tiltfile")
+
+	m := manifestbuilder.New(f.tempdir, "m").WithLocalServeCmd("hi").Build()
+	m.LogLevels = model.LogLevels{model.LogSourceRuntime: logger.WarnLvl}
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: []model.Manifest{m}}
+
+	tf := v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-tf",
+		},
+		Spec: v1alpha1.TiltfileSpec{
+			Path: p,
+		},
+	}
+	f.createAndWaitForLoaded(&tf)
+	f.requireLogLevel(m.Name, model.LogSourceRuntime, "warn")
+
+	// Change the level at runtime.
+	var cm v1alpha1.ConfigMap
+	f.MustGet(types.NamespacedName{Name: configmap2.LogLevelsName(m.Name)}, &cm)
+	cm.Data["runtime"] = "error"
+	require.NoError(t, f.Client.Update(f.Context(), &cm))
+
+	// Reloading keeps the runtime change.
+	f.reloadWithArgs("my-tf", []string{"a"})
+	f.requireLogLevel(m.Name, model.LogSourceRuntime, "error")
+
+	// Unless the Tiltfile changes the levels.
+	m.LogLevels = model.LogLevels{model.LogSourceRuntime: logger.InfoLvl}
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: []model.Manifest{m}}
+	f.reloadWithArgs("my-tf", []string{"b"})
+	f.requireLogLevel(m.Name, model.LogSourceRuntime, "info")
+}
+
+func TestRerunAfter(t *testing.T) {
+	f := newFixture(t)
+	p := f.tempdir.JoinPath("Tiltfile")
+
+	m := manifestbuilder.New(f.tempdir, "m").WithLocalServeCmd("hi").Build()
+	f.tfl.Result = tiltfile.TiltfileLoadResult{
+		Manifests:  []model.Manifest{m},
+		RerunAfter: time.Hour,
+	}
+
+	tf := v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-tf",
+		},
+		Spec: v1alpha1.TiltfileSpec{
+			Path: p,
+		},
+	}
+	f.createAndWaitForLoaded(&tf)
+
+	result := f.MustReconcile(types.NamespacedName{Name: "my-tf"})
+	assert.Greater(t, result.RequeueAfter, 59*time.Minute)
+	assert.LessOrEqual(t, result.RequeueAfter, time.Hour)
+
+	// Pretend the Tiltfile finished an hour ago.
+	f.r.mu.Lock()
+	f.r.runs[types.NamespacedName{Name: "my-tf"}].finishTime = time.Now().Add(-time.Hour)
+	f.r.mu.Unlock()
+
+	ts := time.Now()
+	f.st.ClearActions()
+	f.MustReconcile(types.NamespacedName{Name: "my-tf"})
+	f.waitForRunning("my-tf")
+	f.popQueue()
+	f.waitForTerminatedAfter("my-tf", ts)
+
+	a := f.st.WaitForAction(t, reflect.TypeOf(ConfigsReloadStartedAction{})).(ConfigsReloadStartedAction)
+	assert.Equal(t, model.BuildReasonFlagTriggerTimer, a.Reason)
+}
+
 func TestCancel(t *testing.T) {
 	f := newFixture(t)
 	p := f.tempdir.JoinPath("Tiltfile")
@@ -496,7 +662,7 @@ func newFixture(t *testing.T) *fixture {
 	st := NewTestingStore()
 	tfl := tiltfile.NewFakeTiltfileLoader()
 	d := docker.NewFakeClient()
-	r := NewReconciler(st, tfl, d, cfb.Client, v1alpha1.NewScheme(), store.EngineModeUp, "", "", 0)
+	r := NewReconciler(st, tfl, d, cfb.Client, v1alpha1.NewScheme(), store.EngineModeUp, "", "", 0, xdg.FakeBase{Dir: tf.JoinPath("xdg")}, devenv.Environment{})
 	q := workqueue.NewRateLimitingQueue(
 		workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Millisecond))
 	_ = r.requeuer.Start(context.Background(), handler.Funcs{}, q)
@@ -579,6 +745,21 @@ func (f *fixture) setArgs(name string, args []string) {
 	require.NoError(f.T(), err)
 }
 
+func (f *fixture) reloadWithArgs(name string, args []string) {
+	ts := time.Now()
+	f.setArgs(name, args)
+	f.MustReconcile(types.NamespacedName{Name: name})
+	f.waitForRunning(name)
+	f.popQueue()
+	f.waitForTerminatedAfter(name, ts)
+}
+
+func (f *fixture) requireLogLevel(mn model.ManifestName, source model.LogSource, level string) {
+	var cm v1alpha1.ConfigMap
+	f.MustGet(types.NamespacedName{Name: configmap2.LogLevelsName(mn)}, &cm)
+	require.Equal(f.T(), level, cm.Data[string(source)], "%s log level of %s", source, mn)
+}
+
 func (f *fixture) requireEnabled(m model.Manifest, isEnabled bool) {
 	var cm v1alpha1.ConfigMap
 	f.MustGet(types.NamespacedName{Name: disableConfigMapName(m)}, &cm)
@@ -93,6 +93,17 @@ func HandleConfigsReloaded(
 	// Retroactively scrub secrets
 	state.LogStore.ScrubSecretsStartingAt(newSecrets, event.CheckpointAtExecStart)
 
+	// Like secrets, patterns aren't dropped when the Tiltfile fails,
+	// so that a typo doesn't reveal what they redacted.
+	if isMainTiltfile {
+		patterns := event.RedactPatterns
+		if event.Err != nil {
+			patterns = state.LogStore.RedactPatterns().Union(patterns)
+		}
+		state.LogStore.SetRedactPatterns(patterns)
+		state.LogStore.ScrubSecretsStartingAt(patterns, event.CheckpointAtExecStart)
+	}
+
 	// Add team id if it exists, even if execution failed.
 	if isMainTiltfile && (event.TeamID != "" || event.Err == nil) {
 		state.TeamID = event.TeamID
@@ -155,6 +166,9 @@ func HandleConfigsReloaded(
 		if createNew {
 			mt = store.NewManifestTarget(m)
 		}
+		if !ok {
+			restoreImages(mt.State, m, event.RestoredImages)
+		}
 
 		configFilesThatChanged := ms.LastBuild().Edits
 		old := mt.Manifest
@@ -186,6 +200,19 @@ func HandleConfigsReloaded(
 
 	state.TiltfileConfigPaths[event.Name] = event.ConfigFiles
 
+	keepRepeats := model.ManifestNameSet{}
+	ansiModes := map[model.ManifestName]model.LogANSIMode{}
+	for _, mt := range state.Targets() {
+		if mt.Manifest.KeepRepeatedLogs {
+			keepRepeats[mt.Manifest.Name] = true
+		}
+		if mt.Manifest.LogANSI != "" {
+			ansiModes[mt.Manifest.Name] = mt.Manifest.LogANSI
+		}
+	}
+	state.LogStore.SetKeepRepeats(keepRepeats)
+	state.LogStore.SetANSIModes(ansiModes)
+
 	// Global state that's only configurable from the main manifest.
 	if isMainTiltfile {
 		state.Features = event.Features
@@ -194,5 +221,17 @@ func HandleConfigsReloaded(
 		state.AnalyticsTiltfileOpt = event.AnalyticsTiltfileOpt
 		state.UpdateSettings = event.UpdateSettings
 		state.DockerPruneSettings = event.DockerPruneSettings
+		state.GroupSettings = event.GroupSettings
+	}
+}
+
+// Seeds a new resource with the images that an earlier session built.
+func restoreImages(ms *store.ManifestState, m model.Manifest, images map[model.TargetID]store.ImageBuildResult) {
+	for _, iTarget := range m.ImageTargets {
+		result, ok := images[iTarget.ID()]
+		if !ok {
+			continue
+		}
+		ms.MutableBuildStatus(iTarget.ID()).LastResult = result
 	}
 }
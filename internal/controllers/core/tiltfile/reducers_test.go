@@ -2,12 +2,16 @@ package tiltfile
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -84,3 +88,95 @@ func TestManifestOrder(t *testing.T) {
 		[]model.ManifestName{"b", "extra-x", "d", "extra-omega", "a", "c"},
 		state.ManifestDefinitionOrder)
 }
+
+func TestKeepRepeatedLogs(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout))
+	state := store.NewState()
+	state.LogStore.SetCollapseRepeats(true)
+
+	HandleConfigsReloaded(ctx, state, ConfigsReloadedAction{
+		Name: model.MainTiltfileManifestName,
+		Manifests: []model.Manifest{
+			model.Manifest{Name: "a", KeepRepeatedLogs: true},
+			model.Manifest{Name: "b"},
+		},
+	})
+
+	for _, mn := range []model.ManifestName{"a", "a", "b", "b"} {
+		state.LogStore.Append(store.NewLogAction(mn, model.LogSpanID(mn), logger.InfoLvl, nil, []byte("ping\n")), nil)
+	}
+	assert.Equal(t, "ping\nping\n", state.LogStore.ManifestLog("a"))
+	assert.Equal(t, "ping\n", state.LogStore.ManifestLog("b"))
+}
+
+func TestLogANSI(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout))
+	state := store.NewState()
+
+	HandleConfigsReloaded(ctx, state, ConfigsReloadedAction{
+		Name: model.MainTiltfileManifestName,
+		Manifests: []model.Manifest{
+			model.Manifest{Name: "a", LogANSI: model.LogANSIStrip},
+			model.Manifest{Name: "b"},
+		},
+	})
+
+	for _, mn := range []model.ManifestName{"a", "b"} {
+		state.LogStore.Append(store.NewLogAction(mn, model.LogSpanID(mn), logger.InfoLvl, nil, []byte("\x1b[32mok\x1b[0m\n")), nil)
+	}
+	assert.Equal(t, "ok\n", state.LogStore.ManifestLog("a"))
+	assert.Equal(t, "\x1b[32mok\x1b[0m\n", state.LogStore.ManifestLog("b"))
+}
+
+func TestRedactPatterns(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout))
+	state := store.NewState()
+	token, err := model.NewRedactPattern("secret", `token=(?P<secret>\w+)`)
+	require.NoError(t, err)
+	password, err := model.NewRedactPattern("secret", `hunter\d+`)
+	require.NoError(t, err)
+
+	// Lines logged while the Tiltfile ran are redacted after the fact.
+	checkpoint := state.LogStore.Checkpoint()
+	state.LogStore.Append(store.NewLogAction("a", "a", logger.InfoLvl, nil, []byte("token=abc\n")), nil)
+	HandleConfigsReloaded(ctx, state, ConfigsReloadedAction{
+		Name:                  model.MainTiltfileManifestName,
+		RedactPatterns:        model.RedactPatterns{token},
+		CheckpointAtExecStart: checkpoint,
+	})
+	assert.Equal(t, "token=[redacted secret]\n", state.LogStore.ManifestLog("a"))
+
+	// A failed Tiltfile adds patterns, but doesn't remove them.
+	HandleConfigsReloaded(ctx, state, ConfigsReloadedAction{
+		Name:                  model.MainTiltfileManifestName,
+		RedactPatterns:        model.RedactPatterns{password},
+		Err:                   fmt.Errorf("oops"),
+		CheckpointAtExecStart: state.LogStore.Checkpoint(),
+	})
+	state.LogStore.Append(store.NewLogAction("a", "a", logger.InfoLvl, nil, []byte("token=def hunter22\n")), nil)
+	assert.Equal(t, "token=[redacted secret]\ntoken=[redacted secret] [redacted secret]\n", state.LogStore.ManifestLog("a"))
+}
+
+func TestRestoredImages(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout))
+	state := store.NewState()
+
+	iTarget := model.MustNewImageTarget(container.MustParseSelector("fe-image"))
+	fe := model.Manifest{Name: "fe"}.WithImageTarget(iTarget)
+	restored := store.NewImageBuildResultFromStatus(iTarget.ID(), v1alpha1.ImageMapStatus{Image: "fe-image:tilt-1"})
+	HandleConfigsReloaded(ctx, state, ConfigsReloadedAction{
+		Name:           model.MainTiltfileManifestName,
+		Manifests:      []model.Manifest{fe},
+		RestoredImages: map[model.TargetID]store.ImageBuildResult{iTarget.ID(): restored},
+	})
+	assert.Equal(t, restored, state.ManifestTargets["fe"].State.BuildStatus(iTarget.ID()).LastResult)
+
+	// Resources that already exist keep their own images.
+	other := store.NewImageBuildResultFromStatus(iTarget.ID(), v1alpha1.ImageMapStatus{Image: "fe-image:tilt-2"})
+	HandleConfigsReloaded(ctx, state, ConfigsReloadedAction{
+		Name:           model.MainTiltfileManifestName,
+		Manifests:      []model.Manifest{fe},
+		RestoredImages: map[model.TargetID]store.ImageBuildResult{iTarget.ID(): other},
+	})
+	assert.Equal(t, restored, state.ManifestTargets["fe"].State.BuildStatus(iTarget.ID()).LastResult)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/google/wire"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/tilt-dev/tilt/internal/controllers/core/alertrule"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmdimage"
@@ -14,12 +15,16 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/core/extension"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
+	"github.com/tilt-dev/tilt/internal/controllers/core/healthprobe"
 	"github.com/tilt-dev/tilt/internal/controllers/core/imagemap"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesdiscovery"
 	"github.com/tilt-dev/tilt/internal/controllers/core/liveupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/logsink"
+	"github.com/tilt-dev/tilt/internal/controllers/core/notification"
 	"github.com/tilt-dev/tilt/internal/controllers/core/podlogstream"
 	"github.com/tilt-dev/tilt/internal/controllers/core/portforward"
+	"github.com/tilt-dev/tilt/internal/controllers/core/resourceevent"
 	"github.com/tilt-dev/tilt/internal/controllers/core/session"
 	"github.com/tilt-dev/tilt/internal/controllers/core/tiltfile"
 	"github.com/tilt-dev/tilt/internal/controllers/core/togglebutton"
@@ -63,6 +68,11 @@ func ProvideControllers(
 	imr *imagemap.Reconciler,
 	dclsr *dockercomposelogstream.Reconciler,
 	sr *session.Reconciler,
+	nr *notification.Reconciler,
+	rer *resourceevent.Reconciler,
+	arr *alertrule.Reconciler,
+	hpr *healthprobe.Reconciler,
+	lsr *logsink.Reconciler,
 ) []Controller {
 	return []Controller{
 		fileWatch,
@@ -87,6 +97,11 @@ func ProvideControllers(
 		imr,
 		dclsr,
 		sr,
+		nr,
+		rer,
+		arr,
+		hpr,
+		lsr,
 	}
 }
 
@@ -113,6 +128,11 @@ var WireSet = wire.NewSet(
 	configmap.WireSet,
 	dockerimage.WireSet,
 	cmdimage.WireSet,
+	notification.WireSet,
+	resourceevent.WireSet,
+	alertrule.WireSet,
+	healthprobe.WireSet,
+	logsink.WireSet,
 	dockercomposeservice.WireSet,
 	imagemap.WireSet,
 	dockercomposelogstream.WireSet,
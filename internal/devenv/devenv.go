@@ -0,0 +1,153 @@
+// Package devenv provisions the environment that a project's containers run
+// in, when it isn't the cluster and Docker daemon that Tilt would use anyway.
+//
+// A backend creates the environment on `tilt up` (like a namespace for each
+// developer on a shared cluster, or an ephemeral cluster in the cloud), and
+// deletes it on `tilt down`. Tilt points its Kubernetes context, namespace,
+// image registry, and Docker daemon at whatever the backend returns.
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Environment is where a project's containers run.
+//
+// Empty fields keep Tilt's defaults.
+type Environment struct {
+	// The kubeconfig context to deploy to.
+	KubeContext string `json:"kubeContext,omitempty"`
+
+	// The namespace to deploy to.
+	Namespace string `json:"namespace,omitempty"`
+
+	// The registry to push images to, so that the environment can pull them.
+	Registry *v1alpha1.RegistryHosting `json:"registry,omitempty"`
+
+	// The Docker daemon to build images on, like "tcp://builder:2376", so
+	// that builds happen next to the environment instead of on the laptop.
+	DockerHost string `json:"dockerHost,omitempty"`
+
+	// URLs of services in the environment, like a shared gateway.
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+}
+
+type Endpoint struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Request describes whose environment to provision.
+type Request struct {
+	// The developer, like "nick".
+	User string
+
+	// The project, from the directory of its Tiltfile, like "frontend".
+	Project string
+
+	// The kubeconfig context that Tilt would use without a dev environment,
+	// if it was set with --context.
+	KubeContext string
+}
+
+// Backend creates and deletes environments.
+type Backend interface {
+	// Creates the environment, or returns it if it already exists.
+	Up(ctx context.Context, req Request) (Environment, error)
+
+	// Deletes an environment that Up returned.
+	Down(ctx context.Context, req Request, env Environment) error
+}
+
+// Backends are chosen with --dev-env.
+const (
+	// A namespace for each developer and project on the current cluster.
+	BackendNamespace = "namespace"
+
+	// "exec:<command>" runs a command that provisions the environment.
+	BackendExecPrefix = "exec:"
+)
+
+// Returns the backend for a --dev-env value.
+func NewBackend(spec string) (Backend, error) {
+	switch {
+	case spec == BackendNamespace:
+		return NewNamespaceBackend(), nil
+	case strings.HasPrefix(spec, BackendExecPrefix):
+		command := strings.TrimSpace(strings.TrimPrefix(spec, BackendExecPrefix))
+		if command == "" {
+			return nil, fmt.Errorf("dev env %q: missing command after %q", spec, BackendExecPrefix)
+		}
+		return NewExecBackend(command), nil
+	}
+	return nil, fmt.Errorf("unknown dev env %q: must be %q or %q followed by a command",
+		spec, BackendNamespace, BackendExecPrefix)
+}
+
+// Builds the request for the project of the given Tiltfile.
+//
+// The user comes from $TILT_DEV_ENV_USER, or the OS user.
+func NewRequest(tiltfilePath string, kubeContext string) (Request, error) {
+	name := os.Getenv("TILT_DEV_ENV_USER")
+	if name == "" {
+		u, err := user.Current()
+		if err != nil {
+			return Request{}, fmt.Errorf("finding the current user (set TILT_DEV_ENV_USER instead): %v", err)
+		}
+		name = u.Username
+	}
+
+	abs, err := filepath.Abs(tiltfilePath)
+	if err != nil {
+		return Request{}, err
+	}
+	return Request{
+		User:        name,
+		Project:     filepath.Base(filepath.Dir(abs)),
+		KubeContext: kubeContext,
+	}, nil
+}
+
+var invalidDNSChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Makes a string safe to use as a DNS label, like a namespace name.
+func dnsLabel(s string) string {
+	s = invalidDNSChars.ReplaceAllString(strings.ToLower(s), "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return strings.Trim(s, "-")
+}
+
+// Sets environment variables that describe the environment, so that the
+// Tiltfile and the commands that Tilt runs can find it.
+func (e Environment) Setenv() error {
+	vars := map[string]string{
+		"TILT_DEV_ENV_NAMESPACE": e.Namespace,
+		"TILT_DEV_ENV_CONTEXT":   e.KubeContext,
+	}
+	for _, ep := range e.Endpoints {
+		key := strings.ToUpper(invalidEnvChars.ReplaceAllString(ep.Name, "_"))
+		vars["TILT_DEV_ENV_ENDPOINT_"+key] = ep.URL
+	}
+	for k, v := range vars {
+		if v == "" {
+			continue
+		}
+		err := os.Setenv(k, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var invalidEnvChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
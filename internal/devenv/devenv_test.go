@@ -0,0 +1,58 @@
+package devenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBackend(t *testing.T) {
+	b, err := NewBackend("namespace")
+	require.NoError(t, err)
+	assert.IsType(t, namespaceBackend{}, b)
+
+	b, err = NewBackend("exec: ./devenv.sh")
+	require.NoError(t, err)
+	assert.Equal(t, execBackend{command: "./devenv.sh"}, b)
+
+	_, err = NewBackend("exec:")
+	assert.EqualError(t, err, `dev env "exec:": missing command after "exec:"`)
+
+	_, err = NewBackend("cloud")
+	assert.EqualError(t, err, `unknown dev env "cloud": must be "namespace" or "exec:" followed by a command`)
+}
+
+func TestNewRequest(t *testing.T) {
+	t.Setenv("TILT_DEV_ENV_USER", "nick")
+
+	req, err := NewRequest(filepath.Join("src", "frontend", "Tiltfile"), "gke-dev")
+	require.NoError(t, err)
+	assert.Equal(t, Request{User: "nick", Project: "frontend", KubeContext: "gke-dev"}, req)
+}
+
+func TestDNSLabel(t *testing.T) {
+	assert.Equal(t, "nick-frontend", dnsLabel("nick-frontend"))
+	assert.Equal(t, "nick-smith-my-app", dnsLabel("Nick.Smith-my_app"))
+	assert.Equal(t, "domain-nick", dnsLabel(`DOMAIN\nick`))
+	assert.Len(t, dnsLabel(strings.Repeat("a", 100)), 63)
+}
+
+func TestSetenv(t *testing.T) {
+	t.Setenv("TILT_DEV_ENV_NAMESPACE", "")
+	t.Setenv("TILT_DEV_ENV_CONTEXT", "")
+	t.Setenv("TILT_DEV_ENV_ENDPOINT_API_GATEWAY", "")
+
+	err := Environment{
+		Namespace: "nick-frontend",
+		Endpoints: []Endpoint{{Name: "api-gateway", URL: "https://api.dev.example.com"}},
+	}.Setenv()
+	require.NoError(t, err)
+
+	assert.Equal(t, "nick-frontend", os.Getenv("TILT_DEV_ENV_NAMESPACE"))
+	assert.Equal(t, "", os.Getenv("TILT_DEV_ENV_CONTEXT"))
+	assert.Equal(t, "https://api.dev.example.com", os.Getenv("TILT_DEV_ENV_ENDPOINT_API_GATEWAY"))
+}
@@ -0,0 +1,72 @@
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Runs a command to provision the environment, so that teams can plug in
+// whatever their remote dev platform is.
+//
+// Tilt runs `<command> up`, which must print the Environment as JSON on
+// stdout, and `<command> down`, with that JSON on stdin. Both get the
+// request in TILT_DEV_ENV_USER, TILT_DEV_ENV_PROJECT, and
+// TILT_DEV_ENV_CONTEXT. Anything the command prints on stderr goes to
+// Tilt's log.
+type execBackend struct {
+	command string
+}
+
+func NewExecBackend(command string) Backend {
+	return execBackend{command: command}
+}
+
+func (b execBackend) Up(ctx context.Context, req Request) (Environment, error) {
+	out, err := b.run(ctx, "up", req, nil)
+	if err != nil {
+		return Environment{}, err
+	}
+
+	var env Environment
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&env)
+	if err != nil {
+		return Environment{}, fmt.Errorf("reading the environment from %q: %v", b.command, err)
+	}
+	return env, nil
+}
+
+func (b execBackend) Down(ctx context.Context, req Request, env Environment) error {
+	in, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	_, err = b.run(ctx, "down", req, in)
+	return err
+}
+
+func (b execBackend) run(ctx context.Context, action string, req Request, stdin []byte) ([]byte, error) {
+	cmd := model.ToHostCmd(fmt.Sprintf("%s %s", b.command, action))
+	c := exec.CommandContext(ctx, cmd.Argv[0], cmd.Argv[1:]...)
+	c.Env = append(os.Environ(),
+		"TILT_DEV_ENV_USER="+req.User,
+		"TILT_DEV_ENV_PROJECT="+req.Project,
+		"TILT_DEV_ENV_CONTEXT="+req.KubeContext,
+	)
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stderr = logger.Get(ctx).Writer(logger.InfoLvl)
+
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running %q: %v", cmd.String(), err)
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+const devEnvScript = `
+if [ "$1" = "up" ]; then
+  echo "provisioning $TILT_DEV_ENV_USER/$TILT_DEV_ENV_PROJECT" >&2
+  cat <<JSON
+{"kubeContext": "cloud-$TILT_DEV_ENV_USER", "namespace": "$TILT_DEV_ENV_PROJECT",
+ "registry": {"host": "registry.example.com"}, "dockerHost": "tcp://builder:2376",
+ "endpoints": [{"name": "gateway", "url": "https://gateway.example.com"}]}
+JSON
+else
+  cat > "$(dirname "$0")/down.json"
+fi
+`
+
+func TestExecUpAndDown(t *testing.T) {
+	f := newExecFixture(t, devEnvScript)
+
+	env, err := f.b.Up(f.ctx, f.req)
+	require.NoError(t, err)
+	assert.Equal(t, Environment{
+		KubeContext: "cloud-nick",
+		Namespace:   "frontend",
+		Registry:    &v1alpha1.RegistryHosting{Host: "registry.example.com"},
+		DockerHost:  "tcp://builder:2376",
+		Endpoints:   []Endpoint{{Name: "gateway", URL: "https://gateway.example.com"}},
+	}, env)
+	assert.Contains(t, f.out.String(), "provisioning nick/frontend")
+
+	require.NoError(t, f.b.Down(f.ctx, f.req, env))
+	assert.JSONEq(t,
+		`{"kubeContext":"cloud-nick","namespace":"frontend","registry":{"host":"registry.example.com"},`+
+			`"dockerHost":"tcp://builder:2376","endpoints":[{"name":"gateway","url":"https://gateway.example.com"}]}`,
+		f.tmp.ReadFile("down.json"))
+}
+
+func TestExecUpBadOutput(t *testing.T) {
+	f := newExecFixture(t, `echo '{"cluster": "cloud"}'`)
+
+	_, err := f.b.Up(f.ctx, f.req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown field "cluster"`)
+}
+
+func TestExecUpFails(t *testing.T) {
+	f := newExecFixture(t, `echo "quota exceeded" >&2; exit 1`)
+
+	_, err := f.b.Up(f.ctx, f.req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit status 1")
+	assert.Contains(t, f.out.String(), "quota exceeded")
+}
+
+type execFixture struct {
+	ctx context.Context
+	out *bytes.Buffer
+	tmp *tempdir.TempDirFixture
+	b   execBackend
+	req Request
+}
+
+func newExecFixture(t *testing.T, script string) *execFixture {
+	if runtime.GOOS == "windows" {
+		t.Skip("dev env scripts in these tests need a POSIX shell")
+	}
+
+	tmp := tempdir.NewTempDirFixture(t)
+	tmp.WriteFile("devenv.sh", script)
+
+	out := bytes.NewBuffer(nil)
+	return &execFixture{
+		ctx: logger.WithLogger(context.Background(), logger.NewTestLogger(out)),
+		out: out,
+		tmp: tmp,
+		b:   execBackend{command: fmt.Sprintf("sh %s", filepath.Join(tmp.Path(), "devenv.sh"))},
+		req: Request{User: "nick", Project: "frontend"},
+	}
+}
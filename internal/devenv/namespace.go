@@ -0,0 +1,125 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Labels on the namespaces that the namespace backend creates.
+const (
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	LabelUser      = "dev.tilt.dev/user"
+	LabelProject   = "dev.tilt.dev/project"
+)
+
+// Gives each developer their own namespace for each project, on a cluster
+// that the whole team shares.
+//
+// Deploys go to the namespace, and everything else (like the registry) is
+// whatever the cluster provides.
+type namespaceBackend struct {
+	// Returns a client and the name of the context it uses.
+	newClient func(kubeContext string) (kubernetes.Interface, string, error)
+}
+
+func NewNamespaceBackend() Backend {
+	return namespaceBackend{newClient: clientForContext}
+}
+
+func clientForContext(kubeContext string) (kubernetes.Interface, string, error) {
+	clientConfig := k8s.ProvideClientConfig(k8s.KubeContextOverride(kubeContext), "")
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	if kubeContext == "" {
+		kubeContext = rawConfig.CurrentContext
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	cs, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	return cs, kubeContext, nil
+}
+
+// The namespace of a developer's project, like "nick-frontend".
+func namespaceName(req Request) string {
+	return dnsLabel(fmt.Sprintf("%s-%s", req.User, req.Project))
+}
+
+func (b namespaceBackend) Up(ctx context.Context, req Request) (Environment, error) {
+	cs, kubeContext, err := b.newClient(req.KubeContext)
+	if err != nil {
+		return Environment{}, err
+	}
+
+	name := namespaceName(req)
+	if name == "" {
+		return Environment{}, fmt.Errorf("can't make a namespace name from user %q and project %q", req.User, req.Project)
+	}
+	env := Environment{KubeContext: kubeContext, Namespace: name}
+
+	existing, err := cs.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		if existing.Labels[LabelManagedBy] != "tilt" || existing.Labels[LabelUser] != dnsLabel(req.User) {
+			return Environment{}, fmt.Errorf("namespace %q already exists, and wasn't created by Tilt for %s", name, req.User)
+		}
+		return env, nil
+	} else if !apierrors.IsNotFound(err) {
+		return Environment{}, err
+	}
+
+	_, err = cs.CoreV1().Namespaces().Create(ctx, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				LabelManagedBy: "tilt",
+				LabelUser:      dnsLabel(req.User),
+				LabelProject:   dnsLabel(req.Project),
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return Environment{}, fmt.Errorf("creating namespace %q: %v", name, err)
+	}
+	logger.Get(ctx).Infof("Created namespace %s on %s", name, kubeContext)
+	return env, nil
+}
+
+func (b namespaceBackend) Down(ctx context.Context, req Request, env Environment) error {
+	cs, _, err := b.newClient(env.KubeContext)
+	if err != nil {
+		return err
+	}
+
+	// Never delete a namespace that we didn't create.
+	ns, err := cs.CoreV1().Namespaces().Get(ctx, env.Namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if ns.Labels[LabelManagedBy] != "tilt" {
+		return fmt.Errorf("not deleting namespace %q: it wasn't created by Tilt", env.Namespace)
+	}
+
+	err = cs.CoreV1().Namespaces().Delete(ctx, env.Namespace, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting namespace %q: %v", env.Namespace, err)
+	}
+	logger.Get(ctx).Infof("Deleted namespace %s", env.Namespace)
+	return nil
+}
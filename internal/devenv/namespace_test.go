@@ -0,0 +1,109 @@
+package devenv
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestNamespaceUpCreatesNamespace(t *testing.T) {
+	f := newNamespaceFixture(t)
+
+	env, err := f.b.Up(f.ctx, f.req)
+	require.NoError(t, err)
+	assert.Equal(t, Environment{KubeContext: "shared", Namespace: "nick-frontend"}, env)
+
+	ns := f.get("nick-frontend")
+	assert.Equal(t, map[string]string{
+		LabelManagedBy: "tilt",
+		LabelUser:      "nick",
+		LabelProject:   "frontend",
+	}, ns.Labels)
+}
+
+func TestNamespaceUpReusesNamespace(t *testing.T) {
+	f := newNamespaceFixture(t)
+
+	_, err := f.b.Up(f.ctx, f.req)
+	require.NoError(t, err)
+	env, err := f.b.Up(f.ctx, f.req)
+	require.NoError(t, err)
+	assert.Equal(t, "nick-frontend", env.Namespace)
+}
+
+func TestNamespaceUpRefusesOtherNamespace(t *testing.T) {
+	f := newNamespaceFixture(t)
+	f.create("nick-frontend", nil)
+
+	_, err := f.b.Up(f.ctx, f.req)
+	assert.EqualError(t, err, `namespace "nick-frontend" already exists, and wasn't created by Tilt for nick`)
+}
+
+func TestNamespaceDown(t *testing.T) {
+	f := newNamespaceFixture(t)
+
+	env, err := f.b.Up(f.ctx, f.req)
+	require.NoError(t, err)
+	require.NoError(t, f.b.Down(f.ctx, f.req, env))
+
+	_, err = f.cs.CoreV1().Namespaces().Get(f.ctx, "nick-frontend", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// Deleting it again is a no-op.
+	assert.NoError(t, f.b.Down(f.ctx, f.req, env))
+}
+
+func TestNamespaceDownKeepsOtherNamespace(t *testing.T) {
+	f := newNamespaceFixture(t)
+	f.create("nick-frontend", nil)
+
+	err := f.b.Down(f.ctx, f.req, Environment{KubeContext: "shared", Namespace: "nick-frontend"})
+	assert.EqualError(t, err, `not deleting namespace "nick-frontend": it wasn't created by Tilt`)
+	f.get("nick-frontend")
+}
+
+type namespaceFixture struct {
+	t   *testing.T
+	ctx context.Context
+	cs  *fake.Clientset
+	b   namespaceBackend
+	req Request
+}
+
+func newNamespaceFixture(t *testing.T) *namespaceFixture {
+	cs := fake.NewSimpleClientset()
+	return &namespaceFixture{
+		t:   t,
+		ctx: logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout)),
+		cs:  cs,
+		b: namespaceBackend{newClient: func(kubeContext string) (kubernetes.Interface, string, error) {
+			if kubeContext == "" {
+				kubeContext = "shared"
+			}
+			return cs, kubeContext, nil
+		}},
+		req: Request{User: "nick", Project: "frontend"},
+	}
+}
+
+func (f *namespaceFixture) create(name string, labels map[string]string) {
+	_, err := f.cs.CoreV1().Namespaces().Create(f.ctx, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+	}, metav1.CreateOptions{})
+	require.NoError(f.t, err)
+}
+
+func (f *namespaceFixture) get(name string) *v1.Namespace {
+	ns, err := f.cs.CoreV1().Namespaces().Get(f.ctx, name, metav1.GetOptions{})
+	require.NoError(f.t, err)
+	return ns
+}
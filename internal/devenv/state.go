@@ -0,0 +1,70 @@
+package devenv
+
+import (
+	"os"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+// Bump when the file format changes, so that stale files are never read.
+const stateVersion = 1
+
+// State records the environment that `tilt up` provisioned for a project,
+// so that `tilt down` can delete it.
+type State struct {
+	Version int `json:"version"`
+
+	// The main Tiltfile of the project.
+	Tiltfile string `json:"tiltfile"`
+
+	// The --dev-env value that provisioned the environment.
+	Backend string `json:"backend"`
+
+	Request     Request     `json:"request"`
+	Environment Environment `json:"environment"`
+}
+
+// Each project has its own state, keyed by the path of its main Tiltfile.
+func statePath(base xdg.Base, tiltfilePath string) (string, error) {
+	return xdg.ProjectStateFile(base, "devenv", tiltfilePath)
+}
+
+// Returns nil if there's no environment for the project.
+func ReadState(base xdg.Base, tiltfilePath string) (*State, error) {
+	p, err := statePath(base, tiltfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var s State
+	ok, err := xdg.ReadJSONFile(p, &s)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || s.Version != stateVersion || s.Tiltfile != tiltfilePath {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func WriteState(base xdg.Base, s State) error {
+	p, err := statePath(base, s.Tiltfile)
+	if err != nil {
+		return err
+	}
+
+	s.Version = stateVersion
+	return xdg.WriteJSONFile(p, s)
+}
+
+func RemoveState(base xdg.Base, tiltfilePath string) error {
+	p, err := statePath(base, tiltfilePath)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package devenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	tmp := tempdir.NewTempDirFixture(t)
+	base := xdg.FakeBase{Dir: tmp.Path()}
+	tiltfile := tmp.JoinPath("Tiltfile")
+
+	s, err := ReadState(base, tiltfile)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	expected := State{
+		Tiltfile:    tiltfile,
+		Backend:     "namespace",
+		Request:     Request{User: "nick", Project: "frontend"},
+		Environment: Environment{KubeContext: "shared", Namespace: "nick-frontend"},
+	}
+	require.NoError(t, WriteState(base, expected))
+
+	s, err = ReadState(base, tiltfile)
+	require.NoError(t, err)
+	expected.Version = stateVersion
+	assert.Equal(t, &expected, s)
+
+	// Other projects have their own state.
+	s, err = ReadState(base, tmp.JoinPath("other", "Tiltfile"))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	require.NoError(t, RemoveState(base, tiltfile))
+	s, err = ReadState(base, tiltfile)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	// Removing it again is a no-op.
+	assert.NoError(t, RemoveState(base, tiltfile))
+}
@@ -100,6 +100,11 @@ type Client interface {
 	// Returns an ExitError if the command exits with a non-zero exit code.
 	ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error
 
+	// Runs a command in a running container, like `docker exec`, and waits
+	// for it to exit. Unlike ExecInContainer, it keeps stdout and stderr
+	// apart, and doesn't print the command.
+	Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts ExecOptions) error
+
 	ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error)
 	ImagePush(ctx context.Context, image reference.NamedTagged) (io.ReadCloser, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error)
@@ -130,6 +135,21 @@ func (e ExitError) Error() string {
 	return fmt.Sprintf("Exec command exited with status code: %d", e.ExitCode)
 }
 
+// How to connect a command that runs with Exec.
+type ExecOptions struct {
+	// If not nil, the command reads its input from Stdin until it returns
+	// EOF. Otherwise, its stdin is empty.
+	Stdin io.Reader
+
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Run the command in a pseudo-terminal of this size, which merges its
+	// stderr into its stdout.
+	TTY        bool
+	Rows, Cols uint
+}
+
 func IsExitError(err error) bool {
 	_, ok := err.(ExitError)
 	return ok
@@ -649,6 +669,74 @@ func (c *Cli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.C
 	}
 }
 
+func (c *Cli) Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts ExecOptions) error {
+	execID, err := c.ContainerExecCreate(ctx, cID.String(), types.ExecConfig{
+		Cmd:          cmd.Argv,
+		AttachStdin:  opts.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          opts.TTY,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Exec#create")
+	}
+
+	// Attaching starts the command.
+	connection, err := c.ContainerExecAttach(ctx, execID.ID, types.ExecStartCheck{Tty: opts.TTY})
+	if err != nil {
+		return errors.Wrap(err, "Exec#attach")
+	}
+	defer connection.Close()
+
+	if opts.TTY && opts.Rows > 0 && opts.Cols > 0 {
+		err := c.ContainerExecResize(ctx, execID.ID, types.ResizeOptions{Height: opts.Rows, Width: opts.Cols})
+		if err != nil {
+			logger.Get(ctx).Debugf("resize error: %v", err)
+		}
+	}
+
+	if opts.Stdin != nil {
+		// Don't wait for the input to end, because it may outlive the
+		// command.
+		go func() {
+			_, err := io.Copy(connection.Conn, opts.Stdin)
+			if err != nil {
+				logger.Get(ctx).Debugf("copy error: %v", err)
+			}
+			err = connection.CloseWrite()
+			if err != nil {
+				logger.Get(ctx).Debugf("close write error: %v", err)
+			}
+		}()
+	}
+
+	if opts.TTY {
+		_, err = io.Copy(opts.Stdout, connection.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(opts.Stdout, opts.Stderr, connection.Reader)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Exec#copy")
+	}
+
+	for {
+		inspected, err := c.ContainerExecInspect(ctx, execID.ID)
+		if err != nil {
+			return errors.Wrap(err, "Exec#inspect")
+		}
+
+		if inspected.Running {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		if inspected.ExitCode != 0 {
+			return ExitError{ExitCode: inspected.ExitCode}
+		}
+		return nil
+	}
+}
+
 func (c *Cli) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
 	if opts.Pull {
 		namedRef, ok := opts.Image.(reference.Named)
@@ -12,6 +12,7 @@ import (
 	"github.com/tilt-dev/clusterid"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type buildkitTestCase struct {
@@ -411,3 +412,20 @@ func TestProvideClusterProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestLazyClientConnectsOnFirstUse(t *testing.T) {
+	created := 0
+	fake := NewFakeClient()
+	c := newLazyClient(func() Client {
+		created++
+		return fake
+	})
+
+	// Picking the orchestrator doesn't need a connection.
+	assert.Equal(t, c, c.ForOrchestrator(model.OrchestratorK8s))
+	assert.Equal(t, 0, created)
+
+	assert.NoError(t, c.CheckConnected())
+	_ = c.ServerVersion()
+	assert.Equal(t, 1, created)
+}
@@ -11,7 +11,8 @@ type ClusterClient Client
 // The ClusterClient is the docker server from kubectl configs.
 //
 // We may need both or just one or neither, depending on what options the
-// Tiltfile has set to drive the build
+// Tiltfile has set to drive the build. Neither connects to its server until
+// someone uses it.
 func ProvideClusterCli(ctx context.Context, lEnv LocalEnv, cEnv ClusterEnv, lClient LocalClient) (ClusterClient, error) {
 	// If the Cluster Env and the LocalEnv talk to the same daemon,
 	// we can re-use the cluster client as a local client.
@@ -19,12 +20,16 @@ func ProvideClusterCli(ctx context.Context, lEnv LocalEnv, cEnv ClusterEnv, lCli
 	if Env(lEnv).DaemonHost() == Env(cEnv).DaemonHost() {
 		cClient = ClusterClient(lClient)
 	} else {
-		cClient = NewDockerClient(ctx, Env(cEnv))
+		cClient = newLazyClient(func() Client {
+			return NewDockerClient(ctx, Env(cEnv))
+		})
 	}
 
 	return cClient, nil
 }
 
 func ProvideLocalCli(ctx context.Context, lEnv LocalEnv) LocalClient {
-	return LocalClient(NewDockerClient(ctx, Env(lEnv)))
+	return newLazyClient(func() Client {
+		return NewDockerClient(ctx, Env(lEnv))
+	})
 }
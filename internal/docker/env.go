@@ -107,6 +107,18 @@ func (RealClientCreator) FromEnvMap(envMap map[string]string) (DaemonClient, err
 }
 
 func (RealClientCreator) FromCLI(ctx context.Context) (DaemonClient, error) {
+	c, err := NewClientForEndpoint("", "")
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Creates a client for a Docker host (e.g., ssh://user@host) or a Docker
+// CLI context, resolved the way the Docker CLI resolves them.
+//
+// If both are empty, uses the Docker CLI's current endpoint.
+func NewClientForEndpoint(host, dockerContext string) (*client.Client, error) {
 	dockerCli, err := command.NewDockerCli(
 		command.WithOutputStream(io.Discard),
 		command.WithErrorStream(io.Discard))
@@ -118,6 +130,10 @@ func (RealClientCreator) FromCLI(ctx context.Context) (DaemonClient, error) {
 	flagset := pflag.NewFlagSet("docker", pflag.ContinueOnError)
 	newClientOpts.Common.InstallFlags(flagset)
 	newClientOpts.Common.SetDefaultOptions(flagset)
+	if host != "" {
+		newClientOpts.Common.Hosts = []string{host}
+	}
+	newClientOpts.Common.Context = dockerContext
 
 	err = dockerCli.Initialize(newClientOpts)
 	if err != nil {
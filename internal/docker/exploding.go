@@ -56,6 +56,9 @@ func (c explodingClient) Run(ctx context.Context, opts RunConfig) (RunResult, er
 func (c explodingClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	return c.err
 }
+func (c explodingClient) Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts ExecOptions) error {
+	return c.err
+}
 func (c explodingClient) ImagePull(_ context.Context, _ reference.Named) (reference.Canonical, error) {
 	return nil, c.err
 }
@@ -236,6 +236,10 @@ func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd
 		return nil
 	}
 
+	return c.Exec(ctx, cID, cmd, ExecOptions{Stdin: in, Stdout: out, Stderr: out})
+}
+
+func (c *FakeClient) Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts ExecOptions) error {
 	execCall := ExecCall{
 		Container: cID.String(),
 		Cmd:       cmd,
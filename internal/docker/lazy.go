@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// A docker client that doesn't connect to the server until the first
+// time someone calls it.
+//
+// Tilt creates its docker clients at startup, but a project where every
+// resource that builds an image is disabled never needs to talk to Docker.
+type lazyClient struct {
+	once   sync.Once
+	create func() Client
+	client Client
+}
+
+func newLazyClient(create func() Client) *lazyClient {
+	return &lazyClient{create: create}
+}
+
+func (c *lazyClient) get() Client {
+	c.once.Do(func() {
+		c.client = c.create()
+	})
+	return c.client
+}
+
+// Only the switch client cares about the orchestrator, so there's no
+// need to connect.
+func (c *lazyClient) SetOrchestrator(orc model.Orchestrator) {
+}
+func (c *lazyClient) ForOrchestrator(orc model.Orchestrator) Client {
+	return c
+}
+func (c *lazyClient) CheckConnected() error {
+	return c.get().CheckConnected()
+}
+func (c *lazyClient) Env() Env {
+	return c.get().Env()
+}
+func (c *lazyClient) BuilderVersion() types.BuilderVersion {
+	return c.get().BuilderVersion()
+}
+func (c *lazyClient) ServerVersion() types.Version {
+	return c.get().ServerVersion()
+}
+func (c *lazyClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	return c.get().ContainerInspect(ctx, containerID)
+}
+func (c *lazyClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.get().ContainerList(ctx, options)
+}
+func (c *lazyClient) ContainerRestartNoWait(ctx context.Context, containerID string) error {
+	return c.get().ContainerRestartNoWait(ctx, containerID)
+}
+func (c *lazyClient) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
+	return c.get().Run(ctx, opts)
+}
+func (c *lazyClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	return c.get().ExecInContainer(ctx, cID, cmd, in, out)
+}
+func (c *lazyClient) Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts ExecOptions) error {
+	return c.get().Exec(ctx, cID, cmd, opts)
+}
+func (c *lazyClient) ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error) {
+	return c.get().ImagePull(ctx, ref)
+}
+func (c *lazyClient) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
+	return c.get().ImagePush(ctx, ref)
+}
+func (c *lazyClient) ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error) {
+	return c.get().ImageBuild(ctx, buildContext, options)
+}
+func (c *lazyClient) ImageTag(ctx context.Context, source, target string) error {
+	return c.get().ImageTag(ctx, source, target)
+}
+func (c *lazyClient) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	return c.get().ImageInspectWithRaw(ctx, imageID)
+}
+func (c *lazyClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return c.get().ImageList(ctx, options)
+}
+func (c *lazyClient) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	return c.get().ImageRemove(ctx, imageID, options)
+}
+func (c *lazyClient) NewVersionError(apiRequired, feature string) error {
+	return c.get().NewVersionError(apiRequired, feature)
+}
+func (c *lazyClient) BuildCachePrune(ctx context.Context, opts types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error) {
+	return c.get().BuildCachePrune(ctx, opts)
+}
+func (c *lazyClient) ContainersPrune(ctx context.Context, pruneFilters filters.Args) (types.ContainersPruneReport, error) {
+	return c.get().ContainersPrune(ctx, pruneFilters)
+}
+
+var _ Client = &lazyClient{}
@@ -91,6 +91,9 @@ func (c *switchCli) Run(ctx context.Context, opts RunConfig) (RunResult, error)
 func (c *switchCli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	return c.client(ctx).ExecInContainer(ctx, cID, cmd, in, out)
 }
+func (c *switchCli) Exec(ctx context.Context, cID container.ID, cmd model.Cmd, opts ExecOptions) error {
+	return c.client(ctx).Exec(ctx, cID, cmd, opts)
+}
 func (c *switchCli) ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error) {
 	return c.client(ctx).ImagePull(ctx, ref)
 }
@@ -0,0 +1,99 @@
+package dockercompose
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	dockertypes "github.com/docker/docker/api/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Uses the Docker Compose CLI when it's installed, and the native client,
+// which talks to the Docker Engine API directly, when it's not.
+//
+// Once the CLI is found, it sticks. Until then, each use checks for it again,
+// so that a CLI that's installed later, or that failed for some other reason,
+// is picked up.
+type autoDCClient struct {
+	cli    DockerComposeClient
+	native DockerComposeClient
+
+	// Returns an error if the CLI can't be used.
+	detectCLI func(ctx context.Context) error
+
+	mu     sync.Mutex
+	chosen DockerComposeClient
+}
+
+var _ DockerComposeClient = &autoDCClient{}
+
+func newAutoDCClient(cli, native DockerComposeClient, detectCLI func(ctx context.Context) error) *autoDCClient {
+	return &autoDCClient{cli: cli, native: native, detectCLI: detectCLI}
+}
+
+func (c *autoDCClient) client(ctx context.Context) DockerComposeClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chosen != nil {
+		return c.chosen
+	}
+
+	err := c.detectCLI(ctx)
+	if err != nil {
+		logger.Get(ctx).Debugf("Docker Compose CLI not available, so talking to Docker directly: %v", err)
+		return c.native
+	}
+	c.chosen = c.cli
+	return c.chosen
+}
+
+func (c *autoDCClient) Up(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, shouldBuild bool, stdout, stderr io.Writer) error {
+	return c.client(ctx).Up(ctx, spec, shouldBuild, stdout, stderr)
+}
+
+func (c *autoDCClient) Down(ctx context.Context, spec v1alpha1.DockerComposeProject, opts DownOptions, stdout, stderr io.Writer) error {
+	return c.client(ctx).Down(ctx, spec, opts, stdout, stderr)
+}
+
+func (c *autoDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, deleteVolumes bool, stdout, stderr io.Writer) error {
+	return c.client(ctx).Rm(ctx, specs, deleteVolumes, stdout, stderr)
+}
+
+func (c *autoDCClient) Restart(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error {
+	return c.client(ctx).Restart(ctx, spec, stdout, stderr)
+}
+
+func (c *autoDCClient) Exec(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	return c.client(ctx).Exec(ctx, spec, cmd, in, out)
+}
+
+func (c *autoDCClient) StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec, since time.Time) *LogStream {
+	return c.client(ctx).StreamLogs(ctx, spec, since)
+}
+
+func (c *autoDCClient) StreamEvents(ctx context.Context, spec v1alpha1.DockerComposeProject) *EventStream {
+	return c.client(ctx).StreamEvents(ctx, spec)
+}
+
+func (c *autoDCClient) ContainerInspect(ctx context.Context, spec v1alpha1.DockerComposeProject, id string) (dockertypes.ContainerJSON, error) {
+	return c.client(ctx).ContainerInspect(ctx, spec, id)
+}
+
+func (c *autoDCClient) Project(ctx context.Context, spec v1alpha1.DockerComposeProject) (*types.Project, error) {
+	return c.client(ctx).Project(ctx, spec)
+}
+
+func (c *autoDCClient) ContainerID(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec) (container.ID, error) {
+	return c.client(ctx).ContainerID(ctx, spec)
+}
+
+func (c *autoDCClient) Version(ctx context.Context) (string, string, error) {
+	return c.client(ctx).Version(ctx)
+}
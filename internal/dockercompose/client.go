@@ -6,22 +6,29 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/compose-spec/compose-go/loader"
 	"golang.org/x/mod/semver"
 
 	"github.com/compose-spec/compose-go/types"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/jonboulle/clockwork"
 	"github.com/pkg/errors"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 
 	compose "github.com/compose-spec/compose-go/cli"
 )
@@ -42,19 +49,46 @@ var dcProjectOptions = []compose.ProjectOptionsFn{
 
 type DockerComposeClient interface {
 	Up(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, shouldBuild bool, stdout, stderr io.Writer) error
-	Down(ctx context.Context, spec v1alpha1.DockerComposeProject, stdout, stderr io.Writer) error
-	Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error
-	StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec) io.ReadCloser
-	StreamEvents(ctx context.Context, spec v1alpha1.DockerComposeProject) (<-chan string, error)
+	Down(ctx context.Context, spec v1alpha1.DockerComposeProject, opts DownOptions, stdout, stderr io.Writer) error
+	Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, deleteVolumes bool, stdout, stderr io.Writer) error
+	Restart(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error
+	// Runs a command in the service's container, streaming its output to `out`.
+	// Returns a docker.ExitError if the command exits with a non-zero exit code.
+	Exec(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, cmd model.Cmd, in io.Reader, out io.Writer) error
+	// Streams the logs of the service's containers with the Docker Engine API,
+	// skipping lines from before since, unless since is zero.
+	StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec, since time.Time) *LogStream
+	// Streams the project's events until ctx is canceled, reconnecting when
+	// the stream drops.
+	StreamEvents(ctx context.Context, spec v1alpha1.DockerComposeProject) *EventStream
 	Project(ctx context.Context, spec v1alpha1.DockerComposeProject) (*types.Project, error)
 	ContainerID(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec) (container.ID, error)
+	// Inspects a container on the Docker daemon that the project runs on.
+	ContainerInspect(ctx context.Context, spec v1alpha1.DockerComposeProject, id string) (dockertypes.ContainerJSON, error)
 	Version(ctx context.Context) (canonicalVersion string, build string, err error)
 }
 
+// Options for taking down a project.
+type DownOptions struct {
+	// Also removes the named volumes declared in the project, and the
+	// anonymous volumes of its containers.
+	DeleteVolumes bool
+
+	// Also removes the containers of services that aren't in the project
+	// anymore.
+	RemoveOrphans bool
+
+	// How long to wait for containers to stop before killing them.
+	// If nil, uses Docker Compose's default of 10 seconds.
+	Timeout *time.Duration
+}
+
 type cmdDCClient struct {
-	env     docker.Env
-	mu      *sync.Mutex
-	initCmd *sync.Once
+	env       docker.Env
+	mu        *sync.Mutex
+	initCmd   *sync.Once
+	projects  *projectCache
+	endpoints *endpointClients
 
 	composeCmd []string
 	version    string
@@ -62,13 +96,37 @@ type cmdDCClient struct {
 	err        error
 }
 
+// Picks how to run Docker Compose projects with TILT_DOCKER_COMPOSE_BACKEND:
+//
+//   - "cli" shells out to the Docker Compose CLI.
+//   - "native" talks to the Docker Engine API directly, so the CLI doesn't
+//     need to be installed.
+//   - Anything else uses the CLI if it's installed, and talks to the Docker
+//     Engine API if it's not.
+func NewDockerComposeClient(lenv docker.LocalEnv) DockerComposeClient {
+	switch os.Getenv("TILT_DOCKER_COMPOSE_BACKEND") {
+	case "cli":
+		return newCmdDCClient(lenv)
+	case "native":
+		return newNativeDCClient(lenv)
+	}
+	env := docker.Env(lenv)
+	return newAutoDCClient(newCmdDCClient(lenv), newNativeDCClient(lenv), func(ctx context.Context) error {
+		// Not the CLI client's Version, which never checks again.
+		_, _, _, err := dcExecutableVersion(env.AsEnviron())
+		return err
+	})
+}
+
 // TODO(dmiller): we might want to make this take a path to the docker-compose config so we don't
 // have to keep passing it in.
-func NewDockerComposeClient(lenv docker.LocalEnv) DockerComposeClient {
+func newCmdDCClient(lenv docker.LocalEnv) *cmdDCClient {
 	return &cmdDCClient{
-		env:     docker.Env(lenv),
-		mu:      &sync.Mutex{},
-		initCmd: &sync.Once{},
+		env:       docker.Env(lenv),
+		mu:        &sync.Mutex{},
+		initCmd:   &sync.Once{},
+		projects:  newProjectCache(),
+		endpoints: newEndpointClients(docker.Env(lenv)),
 	}
 }
 
@@ -87,6 +145,10 @@ func (c *cmdDCClient) projectArgs(p v1alpha1.DockerComposeProject) []string {
 		result = append(result, "--env-file", p.EnvFile)
 	}
 
+	for _, profile := range p.Profiles {
+		result = append(result, "--profile", profile)
+	}
+
 	if p.YAML != "" {
 		result = append(result, "-f", "-")
 	}
@@ -106,10 +168,15 @@ func (c *cmdDCClient) Up(ctx context.Context, spec v1alpha1.DockerComposeService
 		genArgs = append(genArgs, "--verbose")
 	}
 
+	opts := upOptions(spec)
 	if shouldBuild {
 		var buildArgs = append([]string{}, genArgs...)
-		buildArgs = append(buildArgs, "build", spec.Service)
-		cmd := c.dcCommand(ctx, buildArgs)
+		buildArgs = append(buildArgs, "build")
+		for _, arg := range sortedBuildArgs(opts.BuildArgs) {
+			buildArgs = append(buildArgs, "--build-arg", arg)
+		}
+		buildArgs = append(buildArgs, spec.Service)
+		cmd := c.dcCommand(ctx, spec.Project, buildArgs)
 		cmd.Stdin = strings.NewReader(spec.Project.YAML)
 		cmd.Stdout = stdout
 		cmd.Stderr = stderr
@@ -128,15 +195,24 @@ func (c *cmdDCClient) Up(ctx context.Context, spec v1alpha1.DockerComposeService
 	// when we're waiting on another build...
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	runArgs := append([]string{}, genArgs...)
-	runArgs = append(runArgs, "up", "--no-deps")
-	// Omit --no-build for now to get v2 working.
-	// https://github.com/docker/compose/issues/8785
-	if semver.Major(c.version) != "v2" {
-		runArgs = append(runArgs, "--no-build")
+
+	// docker-compose v1 can't pull as part of `up`.
+	if opts.Pull == types.PullPolicyAlways && semver.Major(c.version) != "v2" {
+		pullArgs := append([]string{}, genArgs...)
+		pullArgs = append(pullArgs, "pull", spec.Service)
+		cmd := c.dcCommand(ctx, spec.Project, pullArgs)
+		cmd.Stdin = strings.NewReader(spec.Project.YAML)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		err := cmd.Run()
+		if err != nil {
+			return FormatError(cmd, nil, err)
+		}
 	}
-	runArgs = append(runArgs, "-d", spec.Service)
-	cmd := c.dcCommand(ctx, runArgs)
+
+	runArgs := append([]string{}, genArgs...)
+	runArgs = append(runArgs, upArgs(c.version, spec.Service, opts)...)
+	cmd := c.dcCommand(ctx, spec.Project, runArgs)
 	cmd.Stdin = strings.NewReader(spec.Project.YAML)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
@@ -144,7 +220,44 @@ func (c *cmdDCClient) Up(ctx context.Context, spec v1alpha1.DockerComposeService
 	return FormatError(cmd, nil, cmd.Run())
 }
 
-func (c *cmdDCClient) Down(ctx context.Context, p v1alpha1.DockerComposeProject, stdout, stderr io.Writer) error {
+// With deleteVolumes, also removes the project's volumes, named and anonymous.
+func upArgs(version string, service string, opts v1alpha1.DockerComposeUpOptions) []string {
+	args := []string{"up"}
+	if !opts.WithDeps {
+		args = append(args, "--no-deps")
+	}
+	// Omit --no-build for now to get v2 working.
+	// https://github.com/docker/compose/issues/8785
+	if semver.Major(version) != "v2" {
+		args = append(args, "--no-build")
+	}
+	if opts.ForceRecreate {
+		args = append(args, "--force-recreate")
+	}
+	if opts.Pull != "" && semver.Major(version) == "v2" {
+		args = append(args, "--pull", opts.Pull)
+	}
+	return append(args, "-d", service)
+}
+
+func upOptions(spec v1alpha1.DockerComposeServiceSpec) v1alpha1.DockerComposeUpOptions {
+	if spec.UpOptions == nil {
+		return v1alpha1.DockerComposeUpOptions{}
+	}
+	return *spec.UpOptions
+}
+
+// Build args as KEY=VALUE, in a deterministic order.
+func sortedBuildArgs(args map[string]string) []string {
+	result := make([]string, 0, len(args))
+	for k, v := range args {
+		result = append(result, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (c *cmdDCClient) Down(ctx context.Context, p v1alpha1.DockerComposeProject, opts DownOptions, stdout, stderr io.Writer) error {
 	// To be safe, we try not to run two docker-compose downs in parallel,
 	// because we know docker-compose up is not thread-safe.
 	c.mu.Lock()
@@ -156,7 +269,8 @@ func (c *cmdDCClient) Down(ctx context.Context, p v1alpha1.DockerComposeProject,
 	}
 
 	args = append(args, "down")
-	cmd := c.dcCommand(ctx, args)
+	args = append(args, downArgs(opts)...)
+	cmd := c.dcCommand(ctx, p, args)
 	cmd.Stdin = strings.NewReader(p.YAML)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
@@ -169,7 +283,25 @@ func (c *cmdDCClient) Down(ctx context.Context, p v1alpha1.DockerComposeProject,
 	return nil
 }
 
-func (c *cmdDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error {
+func downArgs(opts DownOptions) []string {
+	var args []string
+	if opts.DeleteVolumes {
+		args = append(args, "--volumes")
+	}
+	if opts.RemoveOrphans {
+		args = append(args, "--remove-orphans")
+	}
+	if opts.Timeout != nil {
+		// docker-compose only takes whole seconds.
+		secs := int(math.Ceil(opts.Timeout.Seconds()))
+		args = append(args, "--timeout", strconv.Itoa(secs))
+	}
+	return args
+}
+
+// With deleteVolumes, also removes the services' anonymous volumes.
+// Named volumes may be shared with other services, so they're left alone.
+func (c *cmdDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, deleteVolumes bool, stdout, stderr io.Writer) error {
 	if len(specs) == 0 {
 		return nil
 	}
@@ -196,8 +328,11 @@ func (c *cmdDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServ
 	// call `docker-compose stop --timeout $NUM`, to do the presumably slow part under a smaller
 	// timeout.
 	args = append(args, []string{"rm", "--stop", "--force"}...)
+	if deleteVolumes {
+		args = append(args, "-v")
+	}
 	args = append(args, serviceNames...)
-	cmd := c.dcCommand(ctx, args)
+	cmd := c.dcCommand(ctx, p, args)
 	cmd.Stdin = strings.NewReader(p.YAML)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
@@ -210,79 +345,171 @@ func (c *cmdDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServ
 	return nil
 }
 
-func (c *cmdDCClient) StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec) io.ReadCloser {
-	args := c.projectArgs(spec.Project)
+// Restarts the service's container in place, without rebuilding
+// or recreating it.
+func (c *cmdDCClient) Restart(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	r, w := io.Pipe()
+	args := c.projectArgs(spec.Project)
+	if logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl) {
+		args = append(args, "--verbose")
+	}
 
-	// NOTE: we can't practically remove "--no-color" due to the way that Docker Compose formats colorful lines; it
-	// 		 will wrap the entire line (including the \n) with the color codes, so you end up with something like:
-	//			\u001b[36mmyproject_my-container_1 exited with code 0\n\u001b[0m
-	// 		 where the ANSI reset (\u001b[0m) is _AFTER_ the \n, which doesn't play nice with our log segment logic
-	// 		 under some conditions - adding a final \n after stdout is closed would probably be sufficient given the
-	// 		 current pattern of how Compose colorizes stuff, but it's really not worth the headache to find out
-	args = append(args, "logs", "--no-color", "--no-log-prefix", "--timestamps", "--follow", spec.Service)
-	cmd := c.dcCommand(ctx, args)
+	args = append(args, "restart", spec.Service)
+	cmd := c.dcCommand(ctx, spec.Project, args)
 	cmd.Stdin = strings.NewReader(spec.Project.YAML)
-	cmd.Stdout = w
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
-	errBuf := bytes.Buffer{}
-	cmd.Stderr = &errBuf
+	err := cmd.Run()
+	if err != nil {
+		return FormatError(cmd, nil, err)
+	}
+
+	return nil
+}
 
-	go func() {
-		if cmdErr := cmd.Run(); cmdErr != nil {
-			_ = w.CloseWithError(fmt.Errorf("cmd `docker-compose %s` exited with error: \"%v\" (stderr: %s)",
-				strings.Join(args, " "), cmdErr, errBuf.String()))
+func (c *cmdDCClient) Exec(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	project := spec.Project
+	stdin := in
+	if project.YAML != "" {
+		if in == nil {
+			stdin = strings.NewReader(project.YAML)
 		} else {
-			_ = w.Close()
+			// The YAML would use up stdin, so pass it in a file instead.
+			f, err := os.CreateTemp("", "tilt-compose-*.yml")
+			if err != nil {
+				return errors.Wrap(err, "writing compose YAML")
+			}
+			defer func() { _ = os.Remove(f.Name()) }()
+			_, err = f.WriteString(project.YAML)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return errors.Wrap(err, "writing compose YAML")
+			}
+			project.YAML = ""
+			project.ConfigPaths = []string{f.Name()}
 		}
-	}()
-	return r
+	}
+
+	args := c.projectArgs(project)
+	args = append(args, execArgs(spec.Service, cmd)...)
+	execCmd := c.dcCommand(ctx, spec.Project, args)
+	execCmd.Stdin = stdin
+	execCmd.Stdout = out
+	execCmd.Stderr = out
+
+	err := execCmd.Run()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// docker-compose exec exits with the command's exit code.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return docker.ExitError{ExitCode: exitErr.ExitCode()}
+	}
+	return FormatError(execCmd, nil, err)
+}
+
+// The arguments of `docker-compose exec`, without a TTY, so that the output
+// can be streamed and stdin can be piped in.
+func execArgs(service string, cmd model.Cmd) []string {
+	args := []string{"exec", "-T"}
+	if cmd.Dir != "" {
+		args = append(args, "--workdir", cmd.Dir)
+	}
+	for _, e := range cmd.Env {
+		args = append(args, "--env", e)
+	}
+	args = append(args, service)
+	return append(args, cmd.Argv...)
 }
 
-func (c *cmdDCClient) StreamEvents(ctx context.Context, p v1alpha1.DockerComposeProject) (<-chan string, error) {
-	ch := make(chan string)
+// Streams logs with the Docker Engine API, rather than `docker-compose logs`,
+// so that stdout and stderr stay separate and restarts don't end the stream.
+func (c *cmdDCClient) StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec, since time.Time) *LogStream {
+	api, err := c.endpoints.api(spec.Project)
+	if err != nil {
+		return newFailedLogStream(err)
+	}
+	name, err := projectName(ctx, c, spec.Project)
+	if err != nil {
+		return newFailedLogStream(err)
+	}
+	return streamServiceLogs(ctx, api, name, spec.Service, since)
+}
 
+func (c *cmdDCClient) StreamEvents(ctx context.Context, p v1alpha1.DockerComposeProject) *EventStream {
+	return newEventStream(ctx, clockwork.NewRealClock(), func(ctx context.Context, events chan<- Event) error {
+		return c.streamEventsOnce(ctx, p, events)
+	})
+}
+
+// Runs `docker-compose events` until it exits.
+func (c *cmdDCClient) streamEventsOnce(ctx context.Context, p v1alpha1.DockerComposeProject, events chan<- Event) error {
 	args := c.projectArgs(p)
 	args = append(args, "events", "--json")
-	cmd := c.dcCommand(ctx, args)
+	cmd := c.dcCommand(ctx, p, args)
 	cmd.Stdin = strings.NewReader(p.YAML)
+	errBuf := bytes.Buffer{}
+	cmd.Stderr = &errBuf
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return ch, errors.Wrap(err, "making stdout pipe for `docker-compose events`")
+		return errors.Wrap(err, "making stdout pipe for `docker-compose events`")
 	}
 
 	err = cmd.Start()
 	if err != nil {
-		return ch, errors.Wrapf(err, "`docker-compose %s`",
+		return errors.Wrapf(err, "`docker-compose %s`",
 			strings.Join(args, " "))
 	}
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			ch <- scanner.Text()
-		}
 
-		if err := scanner.Err(); err != nil {
-			logger.Get(ctx).Infof("[DOCKER-COMPOSE WATCHER] scanning `events` output: %v", err)
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		evt, err := EventFromJsonStr(scanner.Text())
+		if err != nil {
+			logger.Get(ctx).Debugf("[DOCKER-COMPOSE WATCHER] failed to unmarshal dc event '%s' with err: %v", scanner.Text(), err)
+			continue
 		}
 
-		err = cmd.Wait()
-		if err != nil {
-			logger.Get(ctx).Infof("[DOCKER-COMPOSE WATCHER] exited with error: %v", err)
+		select {
+		case events <- evt:
+		case <-ctx.Done():
 		}
-	}()
+	}
 
-	return ch, nil
+	scanErr := scanner.Err()
+	err = cmd.Wait()
+	if err != nil {
+		return fmt.Errorf("`docker-compose %s` exited with error: \"%v\" (stderr: %s)",
+			strings.Join(args, " "), err, strings.TrimSpace(errBuf.String()))
+	}
+	if scanErr != nil {
+		return errors.Wrap(scanErr, "scanning `docker-compose events` output")
+	}
+	return nil
 }
 
 func (c *cmdDCClient) Project(ctx context.Context, spec v1alpha1.DockerComposeProject) (*types.Project, error) {
+	return c.projects.load(spec, func() (*types.Project, error) {
+		return c.loadProject(ctx, spec)
+	})
+}
+
+func (c *cmdDCClient) loadProject(ctx context.Context, spec v1alpha1.DockerComposeProject) (*types.Project, error) {
 	var proj *types.Project
 	var err error
 
 	// First, use compose-go to natively load the project.
 	if len(spec.ConfigPaths) > 0 {
-		parsed, err := c.loadProjectNative(spec)
+		parsed, err := loadProjectNative(spec)
 		if err == nil {
 			proj = parsed
 		}
@@ -311,6 +538,14 @@ func (c *cmdDCClient) ContainerID(ctx context.Context, spec v1alpha1.DockerCompo
 	return container.ID(id), nil
 }
 
+func (c *cmdDCClient) ContainerInspect(ctx context.Context, spec v1alpha1.DockerComposeProject, id string) (dockertypes.ContainerJSON, error) {
+	api, err := c.endpoints.api(spec)
+	if err != nil {
+		return dockertypes.ContainerJSON{}, err
+	}
+	return api.ContainerInspect(ctx, id)
+}
+
 // Version returns the parsed output of `docker compose version`, the canonical version and build (if present).
 //
 // NOTE: The version subcommand was added in Docker Compose v1.4.0 (released 2015-08-04), so this won't work for
@@ -333,7 +568,7 @@ func composeProjectOptions(modelProj v1alpha1.DockerComposeProject) (*compose.Pr
 	return compose.NewProjectOptions(modelProj.ConfigPaths, allProjectOptions...)
 }
 
-func (c *cmdDCClient) loadProjectNative(modelProj v1alpha1.DockerComposeProject) (*types.Project, error) {
+func loadProjectNative(modelProj v1alpha1.DockerComposeProject) (*types.Project, error) {
 	opts, err := composeProjectOptions(modelProj)
 	if err != nil {
 		return nil, err
@@ -342,7 +577,18 @@ func (c *cmdDCClient) loadProjectNative(modelProj v1alpha1.DockerComposeProject)
 	if err != nil {
 		return nil, err
 	}
-	return proj, nil
+	return applyProfiles(proj, modelProj.Profiles), nil
+}
+
+// Disables the services that aren't in one of the selected profiles.
+//
+// Without any selected profiles, every service stays enabled, so that Tilt
+// can start any of them, like `docker-compose up SERVICE` does.
+func applyProfiles(proj *types.Project, profiles []string) *types.Project {
+	if len(profiles) > 0 {
+		proj.ApplyProfiles(profiles)
+	}
+	return proj
 }
 
 func (c *cmdDCClient) loadProjectCLI(ctx context.Context, proj v1alpha1.DockerComposeProject) (*types.Project, error) {
@@ -354,7 +600,7 @@ func (c *cmdDCClient) loadProjectCLI(ctx context.Context, proj v1alpha1.DockerCo
 	// docker-compose is very inconsistent about whether it fully resolves paths or not via CLI, both between
 	// v1 and v2 as well as even different releases within v2, so set the workdir and force the loader to resolve
 	// any relative paths
-	return loader.Load(types.ConfigDetails{
+	loaded, err := loader.Load(types.ConfigDetails{
 		WorkingDir: proj.ProjectPath,
 		ConfigFiles: []types.ConfigFile{
 			{
@@ -363,6 +609,10 @@ func (c *cmdDCClient) loadProjectCLI(ctx context.Context, proj v1alpha1.DockerCo
 		},
 		// no environment specified because the CLI call will already have resolved all variables
 	}, dcLoaderOption(proj.Name))
+	if err != nil {
+		return nil, err
+	}
+	return applyProfiles(loaded, proj.Profiles), nil
 }
 
 // dcLoaderOption is used when loading Docker Compose projects via the CLI and fallback and for tests.
@@ -418,7 +668,7 @@ func (c *cmdDCClient) initDcCommand() {
 	})
 }
 
-func (c *cmdDCClient) dcCommand(ctx context.Context, args []string) *exec.Cmd {
+func (c *cmdDCClient) dcCommand(ctx context.Context, p v1alpha1.DockerComposeProject, args []string) *exec.Cmd {
 	c.initDcCommand()
 	composeCmd := c.composeCmd[0]
 	composeArgs := c.composeCmd[1:]
@@ -426,7 +676,7 @@ func (c *cmdDCClient) dcCommand(ctx context.Context, args []string) *exec.Cmd {
 		args = append(composeArgs, args...)
 	}
 	cmd := exec.CommandContext(ctx, composeCmd, args...)
-	cmd.Env = append(os.Environ(), c.env.AsEnviron()...)
+	cmd.Env = endpointEnviron(os.Environ(), c.env, p)
 	return cmd
 }
 
@@ -434,7 +684,7 @@ func (c *cmdDCClient) dcOutput(ctx context.Context, p v1alpha1.DockerComposeProj
 
 	tempArgs := c.projectArgs(p)
 	args = append(tempArgs, args...)
-	cmd := c.dcCommand(ctx, args)
+	cmd := c.dcCommand(ctx, p, args)
 	cmd.Stdin = strings.NewReader(p.YAML)
 
 	output, err := cmd.Output()
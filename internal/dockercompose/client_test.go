@@ -2,7 +2,9 @@ package dockercompose
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +14,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 // TestVariableInterpolation both ensures Tilt properly passes environment to Compose for interpolation
@@ -136,6 +139,69 @@ func TestLoadEnvFile(t *testing.T) {
 	require.Equal(t, types.ShellCommand{"foo"}, proj.Services[0].Command)
 }
 
+func TestProjectArgs(t *testing.T) {
+	c := &cmdDCClient{}
+	args := c.projectArgs(v1alpha1.DockerComposeProject{
+		Name:        "hello",
+		ProjectPath: "/app",
+		ConfigPaths: []string{"/app/docker-compose.yml"},
+		Profiles:    []string{"debug", "admin"},
+	})
+	assert.Equal(t, []string{
+		"--project-name", "hello",
+		"--project-directory", "/app",
+		"--profile", "debug",
+		"--profile", "admin",
+		"-f", "/app/docker-compose.yml",
+	}, args)
+}
+
+func TestDownArgs(t *testing.T) {
+	assert.Empty(t, downArgs(DownOptions{}))
+
+	timeout := 1500 * time.Millisecond
+	assert.Equal(t, []string{"--volumes", "--remove-orphans", "--timeout", "2"},
+		downArgs(DownOptions{DeleteVolumes: true, RemoveOrphans: true, Timeout: &timeout}))
+
+	timeout = 0
+	assert.Equal(t, []string{"--timeout", "0"}, downArgs(DownOptions{Timeout: &timeout}))
+}
+
+func TestUpArgs(t *testing.T) {
+	assert.Equal(t, []string{"up", "--no-deps", "-d", "web"},
+		upArgs("v2.10.0", "web", v1alpha1.DockerComposeUpOptions{}))
+	assert.Equal(t, []string{"up", "--no-deps", "--no-build", "-d", "web"},
+		upArgs("v1.29.2", "web", v1alpha1.DockerComposeUpOptions{}))
+
+	opts := v1alpha1.DockerComposeUpOptions{WithDeps: true, ForceRecreate: true, Pull: "always"}
+	assert.Equal(t, []string{"up", "--force-recreate", "--pull", "always", "-d", "web"},
+		upArgs("v2.10.0", "web", opts))
+
+	// docker-compose v1 pulls with a separate command.
+	assert.Equal(t, []string{"up", "--no-build", "--force-recreate", "-d", "web"},
+		upArgs("v1.29.2", "web", opts))
+}
+
+func TestSortedBuildArgs(t *testing.T) {
+	assert.Empty(t, sortedBuildArgs(nil))
+	assert.Equal(t, []string{"A=1", "B=", "C=x=y"},
+		sortedBuildArgs(map[string]string{"C": "x=y", "A": "1", "B": ""}))
+}
+
+func TestExecArgs(t *testing.T) {
+	args := execArgs("web", model.Cmd{
+		Argv: []string{"sh", "-c", "make test"},
+		Dir:  "/app",
+		Env:  []string{"CI=1"},
+	})
+	assert.Equal(t, []string{
+		"exec", "-T",
+		"--workdir", "/app",
+		"--env", "CI=1",
+		"web", "sh", "-c", "make test",
+	}, args)
+}
+
 type dcFixture struct {
 	t      testing.TB
 	ctx    context.Context
@@ -163,3 +229,56 @@ func (f *dcFixture) loadProject(composeYAML string) *types.Project {
 	require.NoError(f.t, err, "Failed to parse compose YAML")
 	return proj
 }
+
+func TestAutoClientUsesCLI(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	cli := NewFakeDockerComposeClient(t, ctx)
+	native := NewFakeDockerComposeClient(t, ctx)
+	c := newAutoDCClient(cli, native, detectFakeCLI(cli))
+
+	spec := v1alpha1.DockerComposeServiceSpec{Service: "web"}
+	require.NoError(t, c.Up(ctx, spec, false, nil, nil))
+	assert.Len(t, cli.UpCalls(), 1)
+	assert.Empty(t, native.UpCalls())
+}
+
+func TestAutoClientFallsBackToNative(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	cli := NewFakeDockerComposeClient(t, ctx)
+	cli.VersionError = fmt.Errorf(`exec: "docker-compose": executable file not found in $PATH`)
+	native := NewFakeDockerComposeClient(t, ctx)
+	c := newAutoDCClient(cli, native, detectFakeCLI(cli))
+
+	spec := v1alpha1.DockerComposeServiceSpec{Service: "web"}
+	require.NoError(t, c.Up(ctx, spec, false, nil, nil))
+	require.NoError(t, c.Up(ctx, spec, false, nil, nil))
+	assert.Empty(t, cli.UpCalls())
+	assert.Len(t, native.UpCalls(), 2)
+}
+
+func TestAutoClientRetriesDetection(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	cli := NewFakeDockerComposeClient(t, ctx)
+	cli.VersionError = fmt.Errorf("docker: 'compose' is not a docker command")
+	native := NewFakeDockerComposeClient(t, ctx)
+	c := newAutoDCClient(cli, native, detectFakeCLI(cli))
+
+	spec := v1alpha1.DockerComposeServiceSpec{Service: "web"}
+	require.NoError(t, c.Up(ctx, spec, false, nil, nil))
+	assert.Len(t, native.UpCalls(), 1)
+
+	// The failure isn't remembered, but success is.
+	cli.VersionError = nil
+	require.NoError(t, c.Up(ctx, spec, false, nil, nil))
+	cli.VersionError = fmt.Errorf("not checked again")
+	require.NoError(t, c.Up(ctx, spec, false, nil, nil))
+	assert.Len(t, cli.UpCalls(), 2)
+	assert.Len(t, native.UpCalls(), 1)
+}
+
+func detectFakeCLI(cli *FakeDCClient) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, _, err := cli.Version(ctx)
+		return err
+	}
+}
@@ -0,0 +1,91 @@
+package dockercompose
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/client"
+
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Whether the project runs on a different Docker daemon than Tilt's.
+func HasDockerEndpoint(p v1alpha1.DockerComposeProject) bool {
+	return p.DockerHost != "" || p.DockerContext != ""
+}
+
+// The environment to run the Docker Compose CLI with.
+//
+// For projects on their own Docker daemon, leaves out the env that points
+// the CLI at Tilt's daemon (e.g., from `minikube docker-env`), and selects
+// the project's host or context instead.
+func endpointEnviron(osEnviron []string, env docker.Env, p v1alpha1.DockerComposeProject) []string {
+	if !HasDockerEndpoint(p) {
+		return append(osEnviron, env.AsEnviron()...)
+	}
+
+	result := make([]string, 0, len(osEnviron)+1)
+	for _, e := range osEnviron {
+		if strings.HasPrefix(e, "DOCKER_HOST=") || strings.HasPrefix(e, "DOCKER_CONTEXT=") {
+			continue
+		}
+		result = append(result, e)
+	}
+	if p.DockerHost != "" {
+		return append(result, fmt.Sprintf("DOCKER_HOST=%s", p.DockerHost))
+	}
+	return append(result, fmt.Sprintf("DOCKER_CONTEXT=%s", p.DockerContext))
+}
+
+// Docker API clients for each Docker daemon that projects run on.
+type endpointClients struct {
+	env docker.Env
+
+	mu      sync.Mutex
+	clients map[dockerEndpoint]client.APIClient
+}
+
+type dockerEndpoint struct {
+	host    string
+	context string
+}
+
+func newEndpointClients(env docker.Env) *endpointClients {
+	return &endpointClients{env: env, clients: make(map[dockerEndpoint]client.APIClient)}
+}
+
+// The client for the project's Docker daemon.
+func (c *endpointClients) api(p v1alpha1.DockerComposeProject) (client.APIClient, error) {
+	if !HasDockerEndpoint(p) {
+		return dockerAPI(c.env)
+	}
+	if p.DockerHost != "" && p.DockerContext != "" {
+		return nil, fmt.Errorf("project %q: can't set both a Docker host and a Docker context", p.Name)
+	}
+
+	endpoint := dockerEndpoint{host: p.DockerHost, context: p.DockerContext}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if api, ok := c.clients[endpoint]; ok {
+		return api, nil
+	}
+	api, err := docker.NewClientForEndpoint(endpoint.host, endpoint.context)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the Docker daemon for project %q: %w", p.Name, err)
+	}
+	c.clients[endpoint] = api
+	return api, nil
+}
+
+func dockerAPI(env docker.Env) (client.APIClient, error) {
+	if env.Error != nil {
+		return nil, env.Error
+	}
+	api, ok := env.Client.(client.APIClient)
+	if !ok {
+		return nil, fmt.Errorf("no Docker client to run Docker Compose services with")
+	}
+	return api, nil
+}
@@ -0,0 +1,44 @@
+package dockercompose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestEndpointEnviron(t *testing.T) {
+	osEnviron := []string{"PATH=/bin", "DOCKER_HOST=tcp://ambient:2375", "DOCKER_CONTEXT=ambient"}
+	env := docker.Env{Environ: []string{"DOCKER_HOST=tcp://minikube:2376", "DOCKER_TLS_VERIFY=1"}}
+
+	assert.Equal(t, []string{
+		"PATH=/bin", "DOCKER_HOST=tcp://ambient:2375", "DOCKER_CONTEXT=ambient",
+		"DOCKER_HOST=tcp://minikube:2376", "DOCKER_TLS_VERIFY=1",
+	}, endpointEnviron(osEnviron, env, v1alpha1.DockerComposeProject{}))
+
+	assert.Equal(t, []string{"PATH=/bin", "DOCKER_HOST=ssh://me@build-box"},
+		endpointEnviron(osEnviron, env, v1alpha1.DockerComposeProject{DockerHost: "ssh://me@build-box"}))
+
+	assert.Equal(t, []string{"PATH=/bin", "DOCKER_CONTEXT=build-box"},
+		endpointEnviron(osEnviron, env, v1alpha1.DockerComposeProject{DockerContext: "build-box"}))
+}
+
+func TestEndpointClients(t *testing.T) {
+	clients := newEndpointClients(docker.Env{})
+
+	p := v1alpha1.DockerComposeProject{Name: "myproj", DockerHost: "tcp://build-box:2376"}
+	api, err := clients.api(p)
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://build-box:2376", api.DaemonHost())
+
+	again, err := clients.api(v1alpha1.DockerComposeProject{Name: "other", DockerHost: "tcp://build-box:2376"})
+	require.NoError(t, err)
+	assert.Same(t, api, again)
+
+	p.DockerContext = "build-box"
+	_, err = clients.api(p)
+	assert.EqualError(t, err, `project "myproj": can't set both a Docker host and a Docker context`)
+}
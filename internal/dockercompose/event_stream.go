@@ -0,0 +1,151 @@
+package dockercompose
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+const (
+	eventStreamMinBackoff = time.Second
+	eventStreamMaxBackoff = 30 * time.Second
+
+	// A connection that stays up this long is considered healthy.
+	eventStreamHealthyAfter = 5 * time.Second
+)
+
+// Connects to the event stream once, and sends events until the
+// connection drops or ctx is canceled.
+type eventConnectFunc func(ctx context.Context, events chan<- Event) error
+
+// The health of an event stream.
+type EventStreamStatus struct {
+	// False after the connection dropped, until a new connection has
+	// stayed up for a while.
+	Connected bool
+
+	// Why the last connection dropped.
+	Error error
+
+	// How many times the stream has connected again after dropping.
+	Reconnects int
+}
+
+// A stream of Docker Compose events that reconnects with backoff when the
+// connection drops, e.g., when the Docker daemon restarts.
+//
+// Events may be missed while the stream is disconnected, so consumers should
+// re-check any state they care about after a reconnect.
+type EventStream struct {
+	clock    clockwork.Clock
+	events   chan Event
+	statuses chan EventStreamStatus
+
+	mu     sync.Mutex
+	status EventStreamStatus
+}
+
+func newEventStream(ctx context.Context, clock clockwork.Clock, connect eventConnectFunc) *EventStream {
+	s := &EventStream{
+		clock:    clock,
+		events:   make(chan Event),
+		statuses: make(chan EventStreamStatus, 1),
+		status:   EventStreamStatus{Connected: true},
+	}
+	go s.run(ctx, connect)
+	return s
+}
+
+// Events from the project. Closed when the stream's context is canceled.
+func (s *EventStream) Events() <-chan Event {
+	return s.events
+}
+
+// Receives the latest status whenever it changes. Stale statuses that
+// nobody received are dropped.
+func (s *EventStream) Statuses() <-chan EventStreamStatus {
+	return s.statuses
+}
+
+func (s *EventStream) Status() EventStreamStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *EventStream) run(ctx context.Context, connect eventConnectFunc) {
+	defer close(s.events)
+
+	backoff := eventStreamMinBackoff
+	for {
+		done := make(chan error, 1)
+		go func() {
+			done <- connect(ctx, s.events)
+		}()
+
+		var err error
+		healthy := s.clock.After(eventStreamHealthyAfter)
+	wait:
+		for {
+			select {
+			case <-healthy:
+				healthy = nil
+				backoff = eventStreamMinBackoff
+				s.markConnected()
+			case err = <-done:
+				break wait
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("event stream ended")
+		}
+		s.markDisconnected(err)
+
+		select {
+		case <-s.clock.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		backoff *= 2
+		if backoff > eventStreamMaxBackoff {
+			backoff = eventStreamMaxBackoff
+		}
+	}
+}
+
+func (s *EventStream) markConnected() {
+	s.mu.Lock()
+	if s.status.Connected {
+		s.mu.Unlock()
+		return
+	}
+	s.status = EventStreamStatus{Connected: true, Reconnects: s.status.Reconnects + 1}
+	status := s.status
+	s.mu.Unlock()
+	s.publish(status)
+}
+
+func (s *EventStream) markDisconnected(err error) {
+	s.mu.Lock()
+	s.status = EventStreamStatus{Connected: false, Error: err, Reconnects: s.status.Reconnects}
+	status := s.status
+	s.mu.Unlock()
+	s.publish(status)
+}
+
+// Only the run loop publishes, so replacing the buffered status can't race.
+func (s *EventStream) publish(status EventStreamStatus) {
+	select {
+	case <-s.statuses:
+	default:
+	}
+	s.statuses <- status
+}
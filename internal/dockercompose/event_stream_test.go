@@ -0,0 +1,74 @@
+package dockercompose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamReconnects(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := clockwork.NewFakeClock()
+	connects := make(chan chan error)
+	attempt := 0
+	s := newEventStream(ctx, clock, func(ctx context.Context, events chan<- Event) error {
+		attempt++
+		drop := make(chan error)
+		connects <- drop
+		events <- Event{Type: TypeContainer, ID: fmt.Sprintf("container-%d", attempt)}
+		select {
+		case err := <-drop:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	drop := <-connects
+	assert.Equal(t, "container-1", (<-s.Events()).ID)
+	assert.Equal(t, EventStreamStatus{Connected: true}, s.Status())
+
+	drop <- fmt.Errorf("daemon restarted")
+	status := <-s.Statuses()
+	assert.False(t, status.Connected)
+	assert.EqualError(t, status.Error, "daemon restarted")
+
+	// Waits out the backoff before connecting again.
+	select {
+	case <-connects:
+		t.Fatal("reconnected without backing off")
+	case <-time.After(10 * time.Millisecond):
+	}
+	advanceUntil(t, clock, connects)
+	assert.Equal(t, "container-2", (<-s.Events()).ID)
+
+	// Only counts as connected once the new connection stays up.
+	status = advanceUntil(t, clock, s.Statuses())
+	assert.Equal(t, EventStreamStatus{Connected: true, Reconnects: 1}, status)
+
+	cancel()
+	_, ok := <-s.Events()
+	assert.False(t, ok)
+}
+
+func advanceUntil[T any](t *testing.T, clock clockwork.FakeClock, ch <-chan T) T {
+	t.Helper()
+	var result T
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Second)
+		select {
+		case result = <-ch:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	return result
+}
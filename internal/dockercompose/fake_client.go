@@ -2,7 +2,6 @@ package dockercompose
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -12,12 +11,15 @@ import (
 	"unicode"
 
 	"github.com/compose-spec/compose-go/loader"
-	"github.com/stretchr/testify/require"
 
 	"github.com/compose-spec/compose-go/types"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/jonboulle/clockwork"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type FakeDCClient struct {
@@ -28,17 +30,48 @@ type FakeDCClient struct {
 
 	RunLogOutput      map[string]<-chan string
 	ContainerIdOutput container.ID
-	eventJson         chan string
-	ConfigOutput      string
-	VersionOutput     string
-
-	upCalls   []UpCall
-	downCalls []DownCall
-	rmCalls   []RmCall
-	DownError error
-	RmError   error
-	RmOutput  string
-	WorkDir   string
+	// Container states for ContainerInspect, by container ID. Containers
+	// that aren't in the map are running.
+	Containers    map[string]dockertypes.ContainerState
+	events        chan Event
+	eventErrs     chan error
+	clock         clockwork.Clock
+	ConfigOutput  string
+	VersionOutput string
+	VersionError  error
+
+	upCalls         []UpCall
+	downCalls       []DownCall
+	rmCalls         []RmCall
+	restartCalls    []RestartCall
+	execCalls       []ExecCall
+	streamLogsCalls []StreamLogsCall
+	DownError       error
+	RmError         error
+	RmOutput        string
+	ExecOutput      string
+	ExecError       error
+	WorkDir         string
+
+	// Errors for the next call to each method. Like DownError, each one is
+	// only returned once.
+	UpError          error
+	ConfigError      error
+	ProjectError     error
+	ContainerIDError error
+
+	// Fails the next attempt to connect to the event stream, which then
+	// reconnects like it would after a daemon restart.
+	StreamEventsError error
+
+	// How long each call takes, as if the Docker daemon were slow. Measured
+	// with the client's clock. Calls return the context's error if it's
+	// canceled first.
+	Latency time.Duration
+
+	// If set, closed when the next call starts waiting out the Latency, so
+	// that a test can act while the call is in progress.
+	LatencyStarted chan struct{}
 }
 
 var _ DockerComposeClient = &FakeDCClient{}
@@ -51,135 +84,257 @@ type UpCall struct {
 
 // Represents a single call to Down
 type DownCall struct {
-	Proj v1alpha1.DockerComposeProject
+	Proj    v1alpha1.DockerComposeProject
+	Options DownOptions
 }
 
 type RmCall struct {
-	Specs []v1alpha1.DockerComposeServiceSpec
+	Specs         []v1alpha1.DockerComposeServiceSpec
+	DeleteVolumes bool
+}
+
+type RestartCall struct {
+	Spec v1alpha1.DockerComposeServiceSpec
+}
+
+type ExecCall struct {
+	Spec  v1alpha1.DockerComposeServiceSpec
+	Cmd   model.Cmd
+	Input string
+}
+
+// Represents a single call to StreamLogs
+type StreamLogsCall struct {
+	Spec  v1alpha1.DockerComposeLogStreamSpec
+	Since time.Time
 }
 
 func NewFakeDockerComposeClient(t *testing.T, ctx context.Context) *FakeDCClient {
 	return &FakeDCClient{
 		t:            t,
 		ctx:          ctx,
-		eventJson:    make(chan string, 100),
+		events:       make(chan Event, 100),
+		eventErrs:    make(chan error, 10),
+		clock:        clockwork.NewRealClock(),
 		RunLogOutput: make(map[string]<-chan string),
+		Containers:   make(map[string]dockertypes.ContainerState),
 	}
 }
 
+// Returns the error and clears it, so that it's only returned once.
+// Caller must hold the lock.
+func takeError(err *error) error {
+	result := *err
+	*err = nil
+	return result
+}
+
+// Waits out the Latency, unless the context is canceled first.
+func (c *FakeDCClient) wait(ctx context.Context) error {
+	c.mu.Lock()
+	latency := c.Latency
+	clock := c.clock
+	var started chan struct{}
+	if latency > 0 {
+		started = c.LatencyStarted
+		c.LatencyStarted = nil
+	}
+	c.mu.Unlock()
+
+	if latency <= 0 {
+		return nil
+	}
+	after := clock.After(latency)
+	if started != nil {
+		close(started)
+	}
+	select {
+	case <-after:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Waits out the Latency, then returns the injected error, if any.
+func (c *FakeDCClient) finish(ctx context.Context, injected error) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return injected
+}
+
 func (c *FakeDCClient) Up(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec,
 	shouldBuild bool, stdout, stderr io.Writer) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.upCalls = append(c.upCalls, UpCall{spec, shouldBuild})
-	return nil
+	err := takeError(&c.UpError)
+	c.mu.Unlock()
+
+	return c.finish(ctx, err)
 }
 
-func (c *FakeDCClient) Down(ctx context.Context, proj v1alpha1.DockerComposeProject, stdout, stderr io.Writer) error {
+func (c *FakeDCClient) Down(ctx context.Context, proj v1alpha1.DockerComposeProject, opts DownOptions, stdout, stderr io.Writer) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.downCalls = append(c.downCalls, DownCall{proj, opts})
+	err := takeError(&c.DownError)
+	c.mu.Unlock()
 
-	c.downCalls = append(c.downCalls, DownCall{proj})
-	if c.DownError != nil {
-		err := c.DownError
-		c.DownError = nil
-		return err
-	}
-	return nil
+	return c.finish(ctx, err)
 }
 
-func (c *FakeDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error {
+func (c *FakeDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, deleteVolumes bool, stdout, stderr io.Writer) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.rmCalls = append(c.rmCalls, RmCall{specs, deleteVolumes})
+	err := takeError(&c.RmError)
+	output := c.RmOutput
+	c.mu.Unlock()
 
-	c.rmCalls = append(c.rmCalls, RmCall{specs})
-	if c.RmError != nil {
-		err := c.RmError
-		c.RmError = nil
+	err = c.finish(ctx, err)
+	if err != nil {
 		return err
 	}
-
-	_, _ = fmt.Fprint(stdout, c.RmOutput)
+	_, _ = fmt.Fprint(stdout, output)
 	return nil
 }
 
-func (c *FakeDCClient) StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec) io.ReadCloser {
-	output := c.RunLogOutput[spec.Service]
-	reader, writer := io.Pipe()
-	go func() {
-		c.t.Helper()
+func (c *FakeDCClient) Restart(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error {
+	c.mu.Lock()
+	c.restartCalls = append(c.restartCalls, RestartCall{spec})
+	c.mu.Unlock()
+
+	return c.wait(ctx)
+}
 
-		if ctx.Err() != nil {
-			return
+func (c *FakeDCClient) Exec(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	var input []byte
+	if in != nil {
+		var err error
+		input, err = io.ReadAll(in)
+		if err != nil {
+			return err
 		}
+	}
+
+	c.mu.Lock()
+	c.execCalls = append(c.execCalls, ExecCall{spec, cmd, string(input)})
+	err := takeError(&c.ExecError)
+	output := c.ExecOutput
+	c.mu.Unlock()
 
-		// docker-compose always logs an "Attaching to foo, bar" at the start of a log session
-		_, err := writer.Write([]byte(fmt.Sprintf("Attaching to %s\n", spec.Service)))
-		require.NoError(c.t, err, "Failed to write to fake Docker Compose logs")
+	if ctxErr := c.wait(ctx); ctxErr != nil {
+		return ctxErr
+	}
+	_, _ = fmt.Fprint(out, output)
+	return err
+}
 
-		done := false
-		for !done {
+// Sends each line from RunLogOutput as a stdout entry, until the output is
+// closed.
+func (c *FakeDCClient) StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec, since time.Time) *LogStream {
+	c.mu.Lock()
+	c.streamLogsCalls = append(c.streamLogsCalls, StreamLogsCall{spec, since})
+	output := c.RunLogOutput[spec.Service]
+	c.mu.Unlock()
+
+	stream := &LogStream{entries: make(chan LogEntry)}
+	go func() {
+		defer close(stream.entries)
+		for {
 			select {
 			case <-ctx.Done():
-				done = true
+				return
 			case s, ok := <-output:
 				if !ok {
-					done = true
-				} else {
-					logLine := fmt.Sprintf("%s %s\n",
-						time.Now().Format(time.RFC3339Nano),
-						strings.TrimRightFunc(s, unicode.IsSpace))
-					_, err = writer.Write([]byte(logLine))
-					require.NoError(c.t, err, "Failed to write to fake Docker Compose logs")
+					return
+				}
+				entry := LogEntry{
+					ContainerID: c.ContainerIdOutput,
+					Source:      LogSourceStdout,
+					Time:        time.Now(),
+					Text:        []byte(strings.TrimRightFunc(s, unicode.IsSpace) + "\n"),
+				}
+				select {
+				case stream.entries <- entry:
+				case <-ctx.Done():
+					return
 				}
 			}
 		}
-
-		// we call docker-compose logs with --follow, so it only terminates (normally) when the container exits
-		// and it writes a message with the container exit code
-		_, err = writer.Write([]byte(fmt.Sprintf("%s exited with code 0\n", spec.Service)))
-		require.NoError(c.t, err, "Failed to write to fake Docker Compose logs")
-		require.NoError(c.t, writer.Close(), "Failed to close fake Docker Compose logs writer")
 	}()
-	return reader
+	return stream
 }
 
-func (c *FakeDCClient) StreamEvents(ctx context.Context, p v1alpha1.DockerComposeProject) (<-chan string, error) {
-	events := make(chan string, 10)
-	go func() {
+// Sets the clock that event streams use to back off and reconnect.
+func (c *FakeDCClient) SetClock(clock clockwork.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+func (c *FakeDCClient) StreamEvents(ctx context.Context, p v1alpha1.DockerComposeProject) *EventStream {
+	c.mu.Lock()
+	clock := c.clock
+	c.mu.Unlock()
+
+	return newEventStream(ctx, clock, func(ctx context.Context, events chan<- Event) error {
+		c.mu.Lock()
+		err := takeError(&c.StreamEventsError)
+		c.mu.Unlock()
+		err = c.finish(ctx, err)
+		if err != nil {
+			return err
+		}
+
 		for {
 			select {
-			case event := <-c.eventJson:
+			case evt := <-c.events:
 				select {
-				case events <- event: // send event to channel (unless it's full)
-				default:
-					panic(fmt.Sprintf("no room on events channel to send event: '%s'. Something "+
-						"is wrong (or you need to increase the buffer).", event))
+				case events <- evt:
+				case <-ctx.Done():
+					return ctx.Err()
 				}
+			case err := <-c.eventErrs:
+				return err
 			case <-ctx.Done():
-				return
+				return ctx.Err()
 			}
 		}
-	}()
+	})
+}
 
-	return events, nil
+func (c *FakeDCClient) SendEvent(evt Event) {
+	c.events <- evt
 }
 
-func (c *FakeDCClient) SendEvent(evt Event) error {
-	j, err := json.Marshal(evt)
-	if err != nil {
-		return err
-	}
-	c.eventJson <- string(j)
-	return nil
+// Drops the connection of the current event stream with the given error,
+// as if the Docker daemon restarted.
+func (c *FakeDCClient) DropEvents(err error) {
+	c.eventErrs <- err
 }
 
-func (c *FakeDCClient) Config(_ context.Context, _ []string) (string, error) {
+func (c *FakeDCClient) Config(ctx context.Context, _ []string) (string, error) {
+	c.mu.Lock()
+	err := takeError(&c.ConfigError)
+	c.mu.Unlock()
+
+	err = c.finish(ctx, err)
+	if err != nil {
+		return "", err
+	}
 	return c.ConfigOutput, nil
 }
 
-func (c *FakeDCClient) Project(_ context.Context, m v1alpha1.DockerComposeProject) (*types.Project, error) {
+func (c *FakeDCClient) Project(ctx context.Context, m v1alpha1.DockerComposeProject) (*types.Project, error) {
+	c.mu.Lock()
+	err := takeError(&c.ProjectError)
+	c.mu.Unlock()
+
+	err = c.finish(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+
 	// this is a dummy ProjectOptions that lets us use compose's logic to apply options
 	// for consistency, but we have to then pull the data out ourselves since we're calling
 	// loader.Load ourselves
@@ -206,14 +361,48 @@ func (c *FakeDCClient) Project(_ context.Context, m v1alpha1.DockerComposeProjec
 		},
 		Environment: opts.Environment,
 	}, dcLoaderOption(projectName))
-	return p, err
+	if err != nil {
+		return nil, err
+	}
+	return applyProfiles(p, m.Profiles), nil
 }
 
 func (c *FakeDCClient) ContainerID(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec) (container.ID, error) {
+	c.mu.Lock()
+	err := takeError(&c.ContainerIDError)
+	c.mu.Unlock()
+
+	err = c.finish(ctx, err)
+	if err != nil {
+		return "", err
+	}
 	return c.ContainerIdOutput, nil
 }
 
+func (c *FakeDCClient) ContainerInspect(ctx context.Context, spec v1alpha1.DockerComposeProject, id string) (dockertypes.ContainerJSON, error) {
+	if err := c.wait(ctx); err != nil {
+		return dockertypes.ContainerJSON{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.Containers[id]
+	if !ok {
+		state = docker.NewRunningContainerState()
+	}
+	return dockertypes.ContainerJSON{
+		ContainerJSONBase: &dockertypes.ContainerJSONBase{
+			ID:    id,
+			State: &state,
+		},
+	}, nil
+}
+
 func (c *FakeDCClient) Version(_ context.Context) (string, string, error) {
+	if c.VersionError != nil {
+		return "", "", c.VersionError
+	}
 	if c.VersionOutput != "" {
 		return c.VersionOutput, "tilt-fake", nil
 	}
@@ -238,3 +427,21 @@ func (c *FakeDCClient) RmCalls() []RmCall {
 	defer c.mu.Unlock()
 	return append([]RmCall{}, c.rmCalls...)
 }
+
+func (c *FakeDCClient) RestartCalls() []RestartCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]RestartCall{}, c.restartCalls...)
+}
+
+func (c *FakeDCClient) ExecCalls() []ExecCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ExecCall{}, c.execCalls...)
+}
+
+func (c *FakeDCClient) StreamLogsCalls() []StreamLogsCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]StreamLogsCall{}, c.streamLogsCalls...)
+}
@@ -0,0 +1,306 @@
+package dockercompose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/tilt-dev/tilt/internal/container"
+)
+
+// Which output stream a log line came from.
+type LogSource string
+
+const (
+	LogSourceStdout LogSource = "stdout"
+	LogSourceStderr LogSource = "stderr"
+)
+
+// A line of output from one of a service's containers.
+type LogEntry struct {
+	ContainerID container.ID
+	Source      LogSource
+
+	// When Docker received the line.
+	Time time.Time
+
+	// The line, with its trailing newline, unless the container stopped
+	// in the middle of a line.
+	Text []byte
+}
+
+// Log entries from all of a service's containers.
+//
+// Reattaches to containers when they restart or are recreated, so it only
+// ends when its context is canceled or it can't watch the service anymore.
+type LogStream struct {
+	entries chan LogEntry
+	err     error
+}
+
+func newFailedLogStream(err error) *LogStream {
+	s := &LogStream{entries: make(chan LogEntry), err: err}
+	close(s.entries)
+	return s
+}
+
+// Closed when the stream ends.
+func (s *LogStream) Entries() <-chan LogEntry {
+	return s.entries
+}
+
+// Why the stream ended, once Entries is closed. Nil if its context was
+// canceled.
+func (s *LogStream) Err() error {
+	return s.err
+}
+
+// The parts of the Docker Engine API that log streaming needs.
+type logStreamAPI interface {
+	ContainerList(ctx context.Context, options dockertypes.ContainerListOptions) ([]dockertypes.Container, error)
+	ContainerInspect(ctx context.Context, containerID string) (dockertypes.ContainerJSON, error)
+	ContainerLogs(ctx context.Context, containerID string, options dockertypes.ContainerLogsOptions) (io.ReadCloser, error)
+	Events(ctx context.Context, options dockertypes.EventsOptions) (<-chan events.Message, <-chan error)
+}
+
+type logStreamer struct {
+	api     logStreamAPI
+	project string
+	service string
+	since   time.Time
+	entries chan LogEntry
+	wg      sync.WaitGroup
+
+	mu        sync.Mutex
+	attached  map[string]bool
+	lastTimes map[string]time.Time
+}
+
+// Streams the logs of a service's containers, skipping lines from before
+// since, unless since is zero.
+func streamServiceLogs(ctx context.Context, api logStreamAPI, project, service string, since time.Time) *LogStream {
+	s := &logStreamer{
+		api:       api,
+		project:   project,
+		service:   service,
+		since:     since,
+		entries:   make(chan LogEntry),
+		attached:  make(map[string]bool),
+		lastTimes: make(map[string]time.Time),
+	}
+	stream := &LogStream{entries: s.entries}
+
+	go func() {
+		ctx, cancel := context.WithCancel(ctx)
+		err := s.run(ctx)
+		cancel()
+		s.wg.Wait()
+		stream.err = err
+		close(s.entries)
+	}()
+	return stream
+}
+
+func (s *logStreamer) labelFilters() []filters.KeyValuePair {
+	return []filters.KeyValuePair{
+		filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, s.project)),
+		filters.Arg("label", fmt.Sprintf("%s=%s", serviceLabel, s.service)),
+		filters.Arg("label", fmt.Sprintf("%s=False", oneoffLabel)),
+	}
+}
+
+func (s *logStreamer) run(ctx context.Context) error {
+	// Watch for containers starting before listing the running ones, so
+	// that none slip through in between.
+	msgs, errs := s.api.Events(ctx, dockertypes.EventsOptions{
+		Filters: filters.NewArgs(append(s.labelFilters(),
+			filters.Arg("type", "container"),
+			filters.Arg("event", "start"))...),
+	})
+
+	containers, err := s.api.ContainerList(ctx, dockertypes.ContainerListOptions{
+		Filters: filters.NewArgs(s.labelFilters()...),
+	})
+	if err != nil {
+		return fmt.Errorf("listing containers of service %q: %w", s.service, err)
+	}
+	for _, c := range containers {
+		s.attach(ctx, c.ID)
+	}
+
+	for {
+		select {
+		case msg := <-msgs:
+			s.attach(ctx, msg.Actor.ID)
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watching containers of service %q: %w", s.service, err)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Follows a container's logs, unless they're already being followed.
+func (s *logStreamer) attach(ctx context.Context, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attached[id] {
+		return
+	}
+	s.attached[id] = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.follow(ctx, id)
+
+		s.mu.Lock()
+		delete(s.attached, id)
+		s.mu.Unlock()
+
+		// If following failed, wait for the container to start again.
+		if err != nil || ctx.Err() != nil {
+			return
+		}
+
+		// The logs end when the container stops. If it already started
+		// again, its start event may have come in while we were still
+		// attached.
+		inspect, err := s.api.ContainerInspect(ctx, id)
+		if err == nil && inspect.State != nil && inspect.State.Running {
+			s.attach(ctx, id)
+		}
+	}()
+}
+
+// Sends a container's logs until it stops.
+func (s *logStreamer) follow(ctx context.Context, id string) error {
+	inspect, err := s.api.ContainerInspect(ctx, id)
+	if err != nil {
+		return fmt.Errorf("inspecting container %s: %w", id, err)
+	}
+
+	// Pick up where we left off if the container restarted.
+	s.mu.Lock()
+	since, ok := s.lastTimes[id]
+	if !ok {
+		since = s.since
+	}
+	s.mu.Unlock()
+
+	opts := dockertypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Timestamps: true,
+	}
+	if !since.IsZero() {
+		opts.Since = since.Format(time.RFC3339Nano)
+	}
+	logs, err := s.api.ContainerLogs(ctx, id, opts)
+	if err != nil {
+		return fmt.Errorf("streaming logs of container %s: %w", id, err)
+	}
+	defer func() { _ = logs.Close() }()
+
+	stdout := &logEntryWriter{ctx: ctx, s: s, id: id, source: LogSourceStdout, since: since}
+	stderr := &logEntryWriter{ctx: ctx, s: s, id: id, source: LogSourceStderr, since: since}
+
+	// Without a TTY, the Engine API multiplexes stdout and stderr.
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(stdout, logs)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, logs)
+	}
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+func (s *logStreamer) send(ctx context.Context, entry LogEntry) {
+	if !entry.Time.IsZero() {
+		s.mu.Lock()
+		s.lastTimes[string(entry.ContainerID)] = entry.Time
+		s.mu.Unlock()
+	}
+
+	select {
+	case s.entries <- entry:
+	case <-ctx.Done():
+	}
+}
+
+// Splits one of a container's output streams into entries.
+type logEntryWriter struct {
+	ctx    context.Context
+	s      *logStreamer
+	id     string
+	source LogSource
+	since  time.Time
+	buf    []byte
+}
+
+func (w *logEntryWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			return len(p), nil
+		}
+		w.emit(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Sends whatever's left of an unfinished line.
+func (w *logEntryWriter) flush() {
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *logEntryWriter) emit(line []byte) {
+	ts, text := splitLogTimestamp(line)
+
+	// Docker's `since` only has second precision, so filter out lines we
+	// already sent.
+	if !ts.IsZero() && !w.since.IsZero() && !ts.After(w.since) {
+		return
+	}
+	w.s.send(w.ctx, LogEntry{
+		ContainerID: container.ID(w.id),
+		Source:      w.source,
+		Time:        ts,
+		Text:        append([]byte{}, text...),
+	})
+}
+
+// With timestamps on, Docker starts each line with an RFC3339Nano timestamp
+// and a space, e.g.,
+//
+//	2021-09-08T18:24:24.704836400Z Hello World
+//
+// Returns a zero time and the whole line if it doesn't have one.
+func splitLogTimestamp(line []byte) (time.Time, []byte) {
+	i := bytes.IndexByte(line, ' ')
+	if i == -1 {
+		return time.Time{}, line
+	}
+	ts, err := time.Parse(time.RFC3339Nano, string(line[:i]))
+	if err != nil {
+		return time.Time{}, line
+	}
+	return ts, line[i+1:]
+}
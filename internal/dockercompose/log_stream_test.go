@@ -0,0 +1,174 @@
+package dockercompose
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamServiceLogsDemultiplexes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := newFakeLogAPI()
+	api.containers = []dockertypes.Container{{ID: "web-1"}}
+	api.logs["web-1"] <- muxed(
+		stdcopy.Stdout, "2021-09-08T19:58:00.000000000Z before since\n",
+		stdcopy.Stdout, "2021-09-08T19:58:01.000000000Z hello\n",
+		stdcopy.Stderr, "2021-09-08T19:58:02.000000000Z oh no\n",
+		stdcopy.Stdout, "2021-09-08T19:58:03.000000000Z goodbye, ",
+		stdcopy.Stdout, "world\n")
+
+	since := time.Date(2021, 9, 8, 19, 58, 0, 0, time.UTC)
+	s := streamServiceLogs(ctx, api, "myproj", "web", since)
+
+	assert.Equal(t, LogEntry{ContainerID: "web-1", Source: LogSourceStdout,
+		Time: since.Add(time.Second), Text: []byte("hello\n")}, <-s.Entries())
+	assert.Equal(t, LogEntry{ContainerID: "web-1", Source: LogSourceStderr,
+		Time: since.Add(2 * time.Second), Text: []byte("oh no\n")}, <-s.Entries())
+	assert.Equal(t, LogEntry{ContainerID: "web-1", Source: LogSourceStdout,
+		Time: since.Add(3 * time.Second), Text: []byte("goodbye, world\n")}, <-s.Entries())
+
+	cancel()
+	for range s.Entries() {
+	}
+	assert.NoError(t, s.Err())
+	assert.Equal(t, since.Format(time.RFC3339Nano), api.logOptions("web-1")[0].Since)
+}
+
+func TestStreamServiceLogsReattachesOnRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := newFakeLogAPI()
+	api.containers = []dockertypes.Container{{ID: "web-1"}}
+	api.tty["web-1"] = true
+	api.logs["web-1"] <- []byte("2021-09-08T19:58:01.000000000Z first run\n")
+
+	s := streamServiceLogs(ctx, api, "myproj", "web", time.Time{})
+	assert.Equal(t, "first run\n", string((<-s.Entries()).Text))
+
+	// Only reattaches once the container starts again, picking up where
+	// the last run left off.
+	api.logs["web-1"] <- []byte("2021-09-08T19:58:01.000000000Z first run\n" +
+		"2021-09-08T19:58:02.000000000Z second run\n")
+	api.setRunning("web-1")
+	api.events <- events.Message{Type: "container", Action: "start", Actor: events.Actor{ID: "web-1"}}
+	assert.Equal(t, "second run\n", string((<-s.Entries()).Text))
+
+	opts := api.logOptions("web-1")
+	require.Len(t, opts, 2)
+	assert.Equal(t, "", opts[0].Since)
+	assert.Equal(t, "2021-09-08T19:58:01Z", opts[1].Since)
+}
+
+func TestStreamServiceLogsEndsWhenEventsFail(t *testing.T) {
+	api := newFakeLogAPI()
+	s := streamServiceLogs(context.Background(), api, "myproj", "web", time.Time{})
+	api.errs <- io.ErrUnexpectedEOF
+
+	for range s.Entries() {
+	}
+	assert.EqualError(t, s.Err(), `watching containers of service "web": unexpected EOF`)
+}
+
+func TestSplitLogTimestamp(t *testing.T) {
+	ts, text := splitLogTimestamp([]byte("2021-09-08T18:24:24.704836400Z Hello World\n"))
+	assert.Equal(t, time.Date(2021, 9, 8, 18, 24, 24, 704836400, time.UTC), ts)
+	assert.Equal(t, "Hello World\n", string(text))
+
+	ts, text = splitLogTimestamp([]byte("no timestamp here\n"))
+	assert.True(t, ts.IsZero())
+	assert.Equal(t, "no timestamp here\n", string(text))
+}
+
+// Multiplexes pairs of streams and text the way the Engine API does.
+func muxed(parts ...interface{}) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(parts); i += 2 {
+		w := stdcopy.NewStdWriter(&buf, parts[i].(stdcopy.StdType))
+		_, _ = w.Write([]byte(parts[i+1].(string)))
+	}
+	return buf.Bytes()
+}
+
+type fakeLogAPI struct {
+	containers []dockertypes.Container
+	tty        map[string]bool
+	logs       map[string]chan []byte
+	events     chan events.Message
+	errs       chan error
+
+	mu      sync.Mutex
+	running map[string]bool
+	opts    map[string][]dockertypes.ContainerLogsOptions
+}
+
+func newFakeLogAPI() *fakeLogAPI {
+	return &fakeLogAPI{
+		tty: make(map[string]bool),
+		logs: map[string]chan []byte{
+			"web-1": make(chan []byte, 1),
+		},
+		events:  make(chan events.Message),
+		errs:    make(chan error, 1),
+		running: make(map[string]bool),
+		opts:    make(map[string][]dockertypes.ContainerLogsOptions),
+	}
+}
+
+func (f *fakeLogAPI) setRunning(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.running[id] = true
+}
+
+func (f *fakeLogAPI) logOptions(id string) []dockertypes.ContainerLogsOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]dockertypes.ContainerLogsOptions{}, f.opts[id]...)
+}
+
+func (f *fakeLogAPI) ContainerList(ctx context.Context, options dockertypes.ContainerListOptions) ([]dockertypes.Container, error) {
+	return f.containers, nil
+}
+
+func (f *fakeLogAPI) ContainerInspect(ctx context.Context, containerID string) (dockertypes.ContainerJSON, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return dockertypes.ContainerJSON{
+		ContainerJSONBase: &dockertypes.ContainerJSONBase{
+			State: &dockertypes.ContainerState{Running: f.running[containerID]},
+		},
+		Config: &container.Config{Tty: f.tty[containerID]},
+	}, nil
+}
+
+// Returns the next queued output, which ends as if the container stopped.
+func (f *fakeLogAPI) ContainerLogs(ctx context.Context, containerID string, options dockertypes.ContainerLogsOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	f.opts[containerID] = append(f.opts[containerID], options)
+	f.running[containerID] = false
+	f.mu.Unlock()
+
+	select {
+	case output := <-f.logs[containerID]:
+		return io.NopCloser(bytes.NewReader(output)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *fakeLogAPI) Events(ctx context.Context, options dockertypes.EventsOptions) (<-chan events.Message, <-chan error) {
+	return f.events, f.errs
+}
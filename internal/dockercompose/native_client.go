@@ -0,0 +1,783 @@
+package dockercompose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// A DockerComposeClient that doesn't need the Docker Compose CLI.
+//
+// It loads projects with compose-go, and creates their networks, volumes,
+// and containers with the Docker Engine API, like Compose v2 does. Errors
+// come straight from the API, instead of from the CLI's stderr.
+type nativeDCClient struct {
+	endpoints *endpointClients
+	projects  *projectCache
+
+	// Creating networks and containers isn't atomic, so only one Up or Down
+	// runs at a time, like with the CLI.
+	mu sync.Mutex
+}
+
+var _ DockerComposeClient = &nativeDCClient{}
+
+func newNativeDCClient(lenv docker.LocalEnv) *nativeDCClient {
+	return &nativeDCClient{endpoints: newEndpointClients(docker.Env(lenv)), projects: newProjectCache()}
+}
+
+func (c *nativeDCClient) api(p v1alpha1.DockerComposeProject) (client.APIClient, error) {
+	return c.endpoints.api(p)
+}
+
+func (c *nativeDCClient) Up(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, shouldBuild bool, stdout, stderr io.Writer) error {
+	api, err := c.api(spec.Project)
+	if err != nil {
+		return err
+	}
+	proj, err := c.Project(ctx, spec.Project)
+	if err != nil {
+		return err
+	}
+	svc, err := proj.GetService(spec.Service)
+	if err != nil {
+		return err
+	}
+	opts := upOptions(spec)
+
+	if opts.WithDeps {
+		err := proj.WithServices(svc.GetDependencies(), func(dep types.ServiceConfig) error {
+			return c.upService(ctx, api, proj, dep, false, false, stdout)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return c.upService(ctx, api, proj, withUpOptions(svc, opts), shouldBuild, opts.ForceRecreate, stdout)
+}
+
+func (c *nativeDCClient) upService(ctx context.Context, api client.APIClient, proj *types.Project, svc types.ServiceConfig, shouldBuild, forceRecreate bool, stdout io.Writer) error {
+	if shouldBuild && svc.Build != nil {
+		err := c.build(ctx, api, proj, svc, stdout)
+		if err != nil {
+			return err
+		}
+	}
+	imageID, err := c.ensureImage(ctx, api, proj, svc, stdout)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err = c.ensureNetworks(ctx, api, proj, svc, stdout)
+	if err != nil {
+		return err
+	}
+	err = c.ensureVolumes(ctx, api, proj, svc, stdout)
+	if err != nil {
+		return err
+	}
+	return c.ensureContainer(ctx, api, proj, svc, imageID, forceRecreate, stdout)
+}
+
+// Applies the Tiltfile's overrides to the service's config.
+func withUpOptions(svc types.ServiceConfig, opts v1alpha1.DockerComposeUpOptions) types.ServiceConfig {
+	if opts.Pull != "" {
+		svc.PullPolicy = opts.Pull
+	}
+	if len(opts.BuildArgs) > 0 && svc.Build != nil {
+		build := *svc.Build
+		build.Args = types.MappingWithEquals{}
+		for k, v := range svc.Build.Args {
+			build.Args[k] = v
+		}
+		for k, v := range opts.BuildArgs {
+			v := v
+			build.Args[k] = &v
+		}
+		svc.Build = &build
+	}
+	return svc
+}
+
+func (c *nativeDCClient) build(ctx context.Context, api client.APIClient, proj *types.Project, svc types.ServiceConfig, stdout io.Writer) error {
+	image := serviceImageName(proj, svc)
+	buildCtx := svc.Build.Context
+	dockerfile := svc.Build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if filepath.IsAbs(dockerfile) {
+		rel, err := filepath.Rel(buildCtx, dockerfile)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("building service %q: the Dockerfile must be inside the build context without the Docker Compose CLI", svc.Name)
+		}
+		dockerfile = rel
+	}
+	dockerfile = filepath.ToSlash(dockerfile)
+
+	pr, pw := io.Pipe()
+	defer func() { _ = pr.Close() }()
+	go func() {
+		_ = pw.CloseWithError(tarBuildContext(buildCtx, dockerfile, pw))
+	}()
+
+	_, _ = fmt.Fprintf(stdout, "Building image %s\n", image)
+	resp, err := api.ImageBuild(ctx, pr, dockertypes.ImageBuildOptions{
+		Tags:        []string{image},
+		Dockerfile:  dockerfile,
+		BuildArgs:   svc.Build.Args,
+		Target:      svc.Build.Target,
+		NoCache:     svc.Build.NoCache,
+		PullParent:  svc.Build.Pull,
+		Labels:      svc.Build.Labels,
+		NetworkMode: svc.Build.Network,
+		ExtraHosts:  svc.Build.ExtraHosts.AsList(),
+		Remove:      true,
+	})
+	if err != nil {
+		return fmt.Errorf("building service %q: %w", svc.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	err = jsonmessage.DisplayJSONMessagesStream(resp.Body, stdout, 0, false, nil)
+	if err != nil {
+		return fmt.Errorf("building service %q: %w", svc.Name, err)
+	}
+	return nil
+}
+
+// Makes sure the service's image exists, building or pulling it if it
+// doesn't, and returns its ID.
+func (c *nativeDCClient) ensureImage(ctx context.Context, api client.APIClient, proj *types.Project, svc types.ServiceConfig, stdout io.Writer) (string, error) {
+	image := serviceImageName(proj, svc)
+	inspect, _, err := api.ImageInspectWithRaw(ctx, image)
+	if err == nil && !shouldRepull(svc) {
+		return inspect.ID, nil
+	}
+	if err != nil && !client.IsErrNotFound(err) {
+		return "", fmt.Errorf("inspecting image %s: %w", image, err)
+	}
+
+	switch {
+	case err != nil && svc.Build != nil:
+		err = c.build(ctx, api, proj, svc, stdout)
+	case err != nil && svc.PullPolicy == types.PullPolicyNever:
+		return "", fmt.Errorf("service %q: image %s not found, and pull_policy is %q", svc.Name, image, svc.PullPolicy)
+	default:
+		err = c.pull(ctx, api, image, stdout)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	inspect, _, err = api.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("inspecting image %s: %w", image, err)
+	}
+	return inspect.ID, nil
+}
+
+func (c *nativeDCClient) pull(ctx context.Context, api client.APIClient, image string, stdout io.Writer) error {
+	_, _ = fmt.Fprintf(stdout, "Pulling image %s\n", image)
+	body, err := api.ImagePull(ctx, image, dockertypes.ImagePullOptions{})
+	if err != nil {
+		return fmt.Errorf("pulling image %s: %w", image, err)
+	}
+	defer func() { _ = body.Close() }()
+
+	err = jsonmessage.DisplayJSONMessagesStream(body, stdout, 0, false, nil)
+	if err != nil {
+		return fmt.Errorf("pulling image %s: %w", image, err)
+	}
+	return nil
+}
+
+func (c *nativeDCClient) ensureNetworks(ctx context.Context, api client.APIClient, proj *types.Project, svc types.ServiceConfig, stdout io.Writer) error {
+	for _, key := range serviceNetworkKeys(svc) {
+		cfg, ok := proj.Networks[key]
+		if !ok {
+			return fmt.Errorf("service %q refers to undefined network %q", svc.Name, key)
+		}
+
+		_, err := api.NetworkInspect(ctx, cfg.Name, dockertypes.NetworkInspectOptions{})
+		if err == nil {
+			continue
+		}
+		if !client.IsErrNotFound(err) {
+			return fmt.Errorf("inspecting network %s: %w", cfg.Name, err)
+		}
+		if cfg.External.External {
+			return fmt.Errorf("network %s declared as external, but could not be found", cfg.Name)
+		}
+
+		labels := map[string]string{}
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+		labels[projectLabel] = proj.Name
+		labels[networkLabel] = key
+
+		create := dockertypes.NetworkCreate{
+			CheckDuplicate: true,
+			Driver:         cfg.Driver,
+			Options:        cfg.DriverOpts,
+			Internal:       cfg.Internal,
+			Attachable:     cfg.Attachable,
+			EnableIPv6:     cfg.EnableIPv6,
+			Labels:         labels,
+		}
+		if cfg.Ipam.Driver != "" || len(cfg.Ipam.Config) > 0 {
+			create.IPAM = ipamConfig(cfg.Ipam)
+		}
+		_, err = api.NetworkCreate(ctx, cfg.Name, create)
+		if err != nil {
+			return fmt.Errorf("creating network %s: %w", cfg.Name, err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Network %s  Created\n", cfg.Name)
+	}
+	return nil
+}
+
+func (c *nativeDCClient) ensureVolumes(ctx context.Context, api client.APIClient, proj *types.Project, svc types.ServiceConfig, stdout io.Writer) error {
+	for _, v := range svc.Volumes {
+		if v.Type != types.VolumeTypeVolume || v.Source == "" {
+			continue
+		}
+		cfg, ok := proj.Volumes[v.Source]
+		if !ok {
+			continue
+		}
+
+		_, err := api.VolumeInspect(ctx, cfg.Name)
+		if err == nil {
+			continue
+		}
+		if !client.IsErrNotFound(err) {
+			return fmt.Errorf("inspecting volume %s: %w", cfg.Name, err)
+		}
+		if cfg.External.External {
+			return fmt.Errorf("volume %s declared as external, but could not be found", cfg.Name)
+		}
+
+		labels := map[string]string{}
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+		labels[projectLabel] = proj.Name
+		labels[volumeLabel] = v.Source
+
+		_, err = api.VolumeCreate(ctx, volume.VolumeCreateBody{
+			Name:       cfg.Name,
+			Driver:     cfg.Driver,
+			DriverOpts: cfg.DriverOpts,
+			Labels:     labels,
+		})
+		if err != nil {
+			return fmt.Errorf("creating volume %s: %w", cfg.Name, err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Volume %s  Created\n", cfg.Name)
+	}
+	return nil
+}
+
+// Starts the service's container, creating it first if it doesn't exist,
+// if it's out of date, or if forceRecreate is set.
+func (c *nativeDCClient) ensureContainer(ctx context.Context, api client.APIClient, proj *types.Project, svc types.ServiceConfig, imageID string, forceRecreate bool, stdout io.Writer) error {
+	hash, err := serviceConfigHash(svc)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.serviceContainers(ctx, api, proj.Name, svc.Name)
+	if err != nil {
+		return err
+	}
+
+	if !forceRecreate && len(existing) == 1 && existing[0].Labels[configHashLabel] == hash && existing[0].ImageID == imageID {
+		current := existing[0]
+		if current.State == "running" {
+			_, _ = fmt.Fprintf(stdout, "Container %s  Running\n", containerName(current))
+			return nil
+		}
+		err := api.ContainerStart(ctx, current.ID, dockertypes.ContainerStartOptions{})
+		if err != nil {
+			return fmt.Errorf("starting container %s: %w", containerName(current), err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Container %s  Started\n", containerName(current))
+		return nil
+	}
+
+	for _, old := range existing {
+		err := c.removeContainer(ctx, api, old, svc.StopGracePeriod, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	config, hostConfig, networkingConfig, err := serviceContainerConfig(proj, svc, hash)
+	if err != nil {
+		return err
+	}
+	name := serviceContainerName(proj, svc)
+	created, err := api.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, name)
+	if err != nil {
+		return fmt.Errorf("creating container %s: %w", name, err)
+	}
+	if len(existing) > 0 {
+		_, _ = fmt.Fprintf(stdout, "Container %s  Recreated\n", name)
+	} else {
+		_, _ = fmt.Fprintf(stdout, "Container %s  Created\n", name)
+	}
+
+	if len(networkingConfig.EndpointsConfig) > 0 {
+		for _, key := range serviceNetworkKeys(svc)[1:] {
+			netName := proj.Networks[key].Name
+			err := api.NetworkConnect(ctx, netName, created.ID, endpointSettings(svc, key))
+			if err != nil {
+				return fmt.Errorf("connecting container %s to network %s: %w", name, netName, err)
+			}
+		}
+	}
+
+	err = api.ContainerStart(ctx, created.ID, dockertypes.ContainerStartOptions{})
+	if err != nil {
+		return fmt.Errorf("starting container %s: %w", name, err)
+	}
+	_, _ = fmt.Fprintf(stdout, "Container %s  Started\n", name)
+	return nil
+}
+
+// With deleteVolumes, also removes the project's volumes, named and anonymous.
+func (c *nativeDCClient) Down(ctx context.Context, p v1alpha1.DockerComposeProject, opts DownOptions, stdout, stderr io.Writer) error {
+	api, err := c.api(p)
+	if err != nil {
+		return err
+	}
+	name, err := c.projectName(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	// Without RemoveOrphans, leave the containers of services that aren't in
+	// the project anymore. If the project can't be loaded, there's no way to
+	// tell them apart, so remove everything.
+	var services map[string]bool
+	if !opts.RemoveOrphans {
+		if proj, err := c.Project(ctx, p); err == nil {
+			services = make(map[string]bool)
+			for _, svc := range proj.AllServices() {
+				services[svc.Name] = true
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	containers, err := api.ContainerList(ctx, dockertypes.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, name))),
+	})
+	if err != nil {
+		return fmt.Errorf("listing containers: %w", err)
+	}
+	orphans := 0
+	for _, ctr := range containers {
+		if services != nil && !services[ctr.Labels[serviceLabel]] {
+			orphans++
+			continue
+		}
+		err := c.removeContainer(ctx, api, ctr, (*types.Duration)(opts.Timeout), opts.DeleteVolumes)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "Container %s  Removed\n", containerName(ctr))
+	}
+	if orphans > 0 {
+		_, _ = fmt.Fprintf(stderr, "Found %d orphan containers for this project. "+
+			"To clean them up, take the project down with remove-orphans.\n", orphans)
+	}
+
+	networks, err := api.NetworkList(ctx, dockertypes.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, name))),
+	})
+	if err != nil {
+		return fmt.Errorf("listing networks: %w", err)
+	}
+	for _, n := range networks {
+		err := api.NetworkRemove(ctx, n.ID)
+		if err != nil && !client.IsErrNotFound(err) {
+			// Orphans may still be using the network.
+			if orphans > 0 {
+				_, _ = fmt.Fprintf(stderr, "Network %s  Not removed: %v\n", n.Name, err)
+				continue
+			}
+			return fmt.Errorf("removing network %s: %w", n.Name, err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Network %s  Removed\n", n.Name)
+	}
+
+	if !opts.DeleteVolumes {
+		return nil
+	}
+	volumes, err := api.VolumeList(ctx, filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, name))))
+	if err != nil {
+		return fmt.Errorf("listing volumes: %w", err)
+	}
+	for _, v := range volumes.Volumes {
+		err := api.VolumeRemove(ctx, v.Name, false)
+		if err != nil && !client.IsErrNotFound(err) {
+			return fmt.Errorf("removing volume %s: %w", v.Name, err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Volume %s  Removed\n", v.Name)
+	}
+	return nil
+}
+
+// With deleteVolumes, also removes the services' anonymous volumes.
+// Named volumes may be shared with other services, so they're left alone.
+func (c *nativeDCClient) Rm(ctx context.Context, specs []v1alpha1.DockerComposeServiceSpec, deleteVolumes bool, stdout, stderr io.Writer) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	api, err := c.api(specs[0].Project)
+	if err != nil {
+		return err
+	}
+	name, err := c.projectName(ctx, specs[0].Project)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range specs {
+		containers, err := c.serviceContainers(ctx, api, name, s.Service)
+		if err != nil {
+			return err
+		}
+		for _, ctr := range containers {
+			err := c.removeContainer(ctx, api, ctr, nil, deleteVolumes)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(stdout, "Container %s  Removed\n", containerName(ctr))
+		}
+	}
+	return nil
+}
+
+// Restarts the service's container in place, without rebuilding
+// or recreating it.
+func (c *nativeDCClient) Restart(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, stdout, stderr io.Writer) error {
+	api, err := c.api(spec.Project)
+	if err != nil {
+		return err
+	}
+	name, err := c.projectName(ctx, spec.Project)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	containers, err := c.serviceContainers(ctx, api, name, spec.Service)
+	if err != nil {
+		return err
+	}
+	for _, ctr := range containers {
+		err := api.ContainerRestart(ctx, ctr.ID, nil)
+		if err != nil {
+			return fmt.Errorf("restarting container %s: %w", containerName(ctr), err)
+		}
+		_, _ = fmt.Fprintf(stdout, "Container %s  Restarted\n", containerName(ctr))
+	}
+	return nil
+}
+
+// Runs the command in the service's newest running container, like
+// `docker compose exec -T`.
+//
+// The Engine API can't kill an exec, so canceling ctx stops streaming, but
+// leaves the command running in the container.
+func (c *nativeDCClient) Exec(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	api, err := c.api(spec.Project)
+	if err != nil {
+		return err
+	}
+	name, err := c.projectName(ctx, spec.Project)
+	if err != nil {
+		return err
+	}
+	containers, err := c.serviceContainers(ctx, api, name, spec.Service)
+	if err != nil {
+		return err
+	}
+	var cID string
+	for _, ctr := range containers {
+		if ctr.State == "running" {
+			cID = ctr.ID
+			break
+		}
+	}
+	if cID == "" {
+		return fmt.Errorf("service %q is not running", spec.Service)
+	}
+
+	execID, err := api.ContainerExecCreate(ctx, cID, dockertypes.ExecConfig{
+		Cmd:          cmd.Argv,
+		Env:          cmd.Env,
+		WorkingDir:   cmd.Dir,
+		AttachStdin:  in != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("exec in service %q: %w", spec.Service, err)
+	}
+
+	conn, err := api.ContainerExecAttach(ctx, execID.ID, dockertypes.ExecStartCheck{})
+	if err != nil {
+		return fmt.Errorf("exec in service %q: %w", spec.Service, err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if in != nil {
+		go func() {
+			_, _ = io.Copy(conn.Conn, in)
+			_ = conn.CloseWrite()
+		}()
+	}
+
+	// Without a TTY, the Engine API multiplexes stdout and stderr.
+	_, err = stdcopy.StdCopy(out, out, conn.Reader)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("exec in service %q: %w", spec.Service, err)
+	}
+
+	for {
+		inspected, err := api.ContainerExecInspect(ctx, execID.ID)
+		if err != nil {
+			return fmt.Errorf("exec in service %q: %w", spec.Service, err)
+		}
+		if !inspected.Running {
+			if inspected.ExitCode != 0 {
+				return docker.ExitError{ExitCode: inspected.ExitCode}
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *nativeDCClient) StreamLogs(ctx context.Context, spec v1alpha1.DockerComposeLogStreamSpec, since time.Time) *LogStream {
+	api, err := c.api(spec.Project)
+	if err != nil {
+		return newFailedLogStream(err)
+	}
+	name, err := c.projectName(ctx, spec.Project)
+	if err != nil {
+		return newFailedLogStream(err)
+	}
+	return streamServiceLogs(ctx, api, name, spec.Service, since)
+}
+
+func (c *nativeDCClient) StreamEvents(ctx context.Context, p v1alpha1.DockerComposeProject) *EventStream {
+	return newEventStream(ctx, clockwork.NewRealClock(), func(ctx context.Context, events chan<- Event) error {
+		return c.streamEventsOnce(ctx, p, events)
+	})
+}
+
+// Streams events from the Docker Engine API until the connection drops.
+func (c *nativeDCClient) streamEventsOnce(ctx context.Context, p v1alpha1.DockerComposeProject, events chan<- Event) error {
+	api, err := c.api(p)
+	if err != nil {
+		return err
+	}
+	name, err := c.projectName(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	msgCh, errCh := api.Events(ctx, dockertypes.EventsOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", "container"),
+			filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, name))),
+	})
+	for {
+		select {
+		case msg := <-msgCh:
+			select {
+			case events <- eventFromMessage(msg):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
+func (c *nativeDCClient) Project(ctx context.Context, spec v1alpha1.DockerComposeProject) (*types.Project, error) {
+	return c.projects.load(spec, func() (*types.Project, error) {
+		if spec.YAML != "" {
+			return loadProjectFromYAML(spec)
+		}
+		return loadProjectNative(spec)
+	})
+}
+
+func (c *nativeDCClient) ContainerInspect(ctx context.Context, spec v1alpha1.DockerComposeProject, id string) (dockertypes.ContainerJSON, error) {
+	api, err := c.api(spec)
+	if err != nil {
+		return dockertypes.ContainerJSON{}, err
+	}
+	return api.ContainerInspect(ctx, id)
+}
+
+func (c *nativeDCClient) ContainerID(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec) (container.ID, error) {
+	api, err := c.api(spec.Project)
+	if err != nil {
+		return "", err
+	}
+	name, err := c.projectName(ctx, spec.Project)
+	if err != nil {
+		return "", err
+	}
+	containers, err := c.serviceContainers(ctx, api, name, spec.Service)
+	if err != nil || len(containers) == 0 {
+		return "", err
+	}
+	return container.ID(containers[0].ID), nil
+}
+
+// The native client has no version of its own. Callers that need the
+// version of Docker Compose should treat this as Compose not being installed.
+func (c *nativeDCClient) Version(ctx context.Context) (string, string, error) {
+	return "", "", fmt.Errorf("using Tilt's built-in Docker Compose support, without the Docker Compose CLI")
+}
+
+func (c *nativeDCClient) projectName(ctx context.Context, p v1alpha1.DockerComposeProject) (string, error) {
+	return projectName(ctx, c, p)
+}
+
+// The project's name, loading the project if the spec doesn't set one.
+func projectName(ctx context.Context, dcc DockerComposeClient, p v1alpha1.DockerComposeProject) (string, error) {
+	if p.Name != "" {
+		return p.Name, nil
+	}
+	proj, err := dcc.Project(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	return proj.Name, nil
+}
+
+// Lists the containers of a service, newest first, including ones that
+// aren't running.
+func (c *nativeDCClient) serviceContainers(ctx context.Context, api client.APIClient, project, service string) ([]dockertypes.Container, error) {
+	containers, err := api.ContainerList(ctx, dockertypes.ContainerListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, project)),
+			filters.Arg("label", fmt.Sprintf("%s=%s", serviceLabel, service)),
+			filters.Arg("label", fmt.Sprintf("%s=False", oneoffLabel))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing containers of service %q: %w", service, err)
+	}
+	return containers, nil
+}
+
+func (c *nativeDCClient) removeContainer(ctx context.Context, api client.APIClient, ctr dockertypes.Container, gracePeriod *types.Duration, deleteVolumes bool) error {
+	var timeout *time.Duration
+	if gracePeriod != nil {
+		d := time.Duration(*gracePeriod)
+		timeout = &d
+	}
+	if ctr.State == "running" {
+		err := api.ContainerStop(ctx, ctr.ID, timeout)
+		if err != nil && !client.IsErrNotFound(err) {
+			return fmt.Errorf("stopping container %s: %w", containerName(ctr), err)
+		}
+	}
+	err := api.ContainerRemove(ctx, ctr.ID, dockertypes.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: deleteVolumes,
+	})
+	if err != nil && !client.IsErrNotFound(err) {
+		return fmt.Errorf("removing container %s: %w", containerName(ctr), err)
+	}
+	return nil
+}
+
+func containerName(ctr dockertypes.Container) string {
+	if len(ctr.Names) == 0 {
+		return container.ID(ctr.ID).ShortStr()
+	}
+	return strings.TrimPrefix(ctr.Names[0], "/")
+}
+
+// Loads a project from YAML, the way the CLI does when it reads it from stdin.
+func loadProjectFromYAML(modelProj v1alpha1.DockerComposeProject) (*types.Project, error) {
+	opts, err := composeProjectOptions(modelProj)
+	if err != nil {
+		return nil, err
+	}
+	proj, err := loader.Load(types.ConfigDetails{
+		WorkingDir: modelProj.ProjectPath,
+		ConfigFiles: []types.ConfigFile{
+			{
+				Filename: filepath.Join(modelProj.ProjectPath, "docker-compose.yml"),
+				Content:  []byte(modelProj.YAML),
+			},
+		},
+		Environment: opts.Environment,
+	}, dcLoaderOption(modelProj.Name))
+	if err != nil {
+		return nil, err
+	}
+	return applyProfiles(proj, modelProj.Profiles), nil
+}
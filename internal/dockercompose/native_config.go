@@ -0,0 +1,437 @@
+package dockercompose
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/go-connections/nat"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+)
+
+// The labels that Docker Compose puts on the objects it creates. The native
+// client uses the same ones, so that it finds containers created by the CLI,
+// and vice versa.
+const (
+	projectLabel         = "com.docker.compose.project"
+	serviceLabel         = "com.docker.compose.service"
+	containerNumberLabel = "com.docker.compose.container-number"
+	oneoffLabel          = "com.docker.compose.oneoff"
+	configHashLabel      = "com.docker.compose.config-hash"
+	workingDirLabel      = "com.docker.compose.project.working_dir"
+	configFilesLabel     = "com.docker.compose.project.config_files"
+	networkLabel         = "com.docker.compose.network"
+	volumeLabel          = "com.docker.compose.volume"
+)
+
+// The name of the image for a service, matching what the Tiltfile expects.
+func serviceImageName(proj *types.Project, svc types.ServiceConfig) string {
+	if svc.Image != "" {
+		return svc.Image
+	}
+	return fmt.Sprintf("%s_%s", proj.Name, svc.Name)
+}
+
+// Whether a service's image should be pulled again even though it exists.
+// Images that the project builds aren't in a registry, so they're never
+// pulled, even with pull_policy: always.
+func shouldRepull(svc types.ServiceConfig) bool {
+	return svc.PullPolicy == types.PullPolicyAlways && svc.Build == nil
+}
+
+func serviceContainerName(proj *types.Project, svc types.ServiceConfig) string {
+	if svc.ContainerName != "" {
+		return svc.ContainerName
+	}
+	return fmt.Sprintf("%s-%s-1", proj.Name, svc.Name)
+}
+
+// Hashes the parts of the service config that end up in its container, so
+// that Up can tell whether an existing container is out of date.
+func serviceConfigHash(svc types.ServiceConfig) (string, error) {
+	svc.Build = nil
+	svc.PullPolicy = ""
+	svc.Scale = 0
+	b, err := json.Marshal(svc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// The networks that a service joins, in the order that Compose connects
+// them: highest priority first, then by name.
+func serviceNetworkKeys(svc types.ServiceConfig) []string {
+	keys := make([]string, 0, len(svc.Networks))
+	for k := range svc.Networks {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := networkPriority(svc, keys[i]), networkPriority(svc, keys[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func networkPriority(svc types.ServiceConfig, key string) int {
+	if cfg := svc.Networks[key]; cfg != nil {
+		return cfg.Priority
+	}
+	return 0
+}
+
+func endpointSettings(svc types.ServiceConfig, key string) *network.EndpointSettings {
+	settings := &network.EndpointSettings{Aliases: []string{svc.Name}}
+	cfg := svc.Networks[key]
+	if cfg == nil {
+		return settings
+	}
+	settings.Aliases = append(settings.Aliases, cfg.Aliases...)
+	if cfg.Ipv4Address != "" || cfg.Ipv6Address != "" || len(cfg.LinkLocalIPs) > 0 {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{
+			IPv4Address:  cfg.Ipv4Address,
+			IPv6Address:  cfg.Ipv6Address,
+			LinkLocalIPs: cfg.LinkLocalIPs,
+		}
+	}
+	return settings
+}
+
+// Converts a service to the config of its container.
+//
+// The container joins the service's first network when it's created. Up
+// connects it to the rest before starting it.
+//
+// Options that Compose implements outside of the container, like `deploy`,
+// `scale`, and `depends_on`, are ignored.
+func serviceContainerConfig(proj *types.Project, svc types.ServiceConfig, configHash string) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
+	labels := map[string]string{}
+	for k, v := range svc.Labels {
+		labels[k] = v
+	}
+	labels[projectLabel] = proj.Name
+	labels[serviceLabel] = svc.Name
+	labels[containerNumberLabel] = "1"
+	labels[oneoffLabel] = "False"
+	labels[configHashLabel] = configHash
+	labels[workingDirLabel] = proj.WorkingDir
+	labels[configFilesLabel] = strings.Join(proj.ComposeFiles, ",")
+
+	var env []string
+	for k, v := range svc.Environment {
+		// Variables without a value weren't set in Tilt's environment either.
+		if v != nil {
+			env = append(env, fmt.Sprintf("%s=%s", k, *v))
+		}
+	}
+	sort.Strings(env)
+
+	exposedPorts := nat.PortSet{}
+	portBindings := nat.PortMap{}
+	for _, p := range svc.Ports {
+		proto := p.Protocol
+		if proto == "" {
+			proto = "tcp"
+		}
+		port := nat.Port(fmt.Sprintf("%d/%s", p.Target, proto))
+		exposedPorts[port] = struct{}{}
+		portBindings[port] = append(portBindings[port], nat.PortBinding{HostIP: p.HostIP, HostPort: p.Published})
+	}
+	for _, e := range svc.Expose {
+		if !strings.Contains(e, "/") {
+			e += "/tcp"
+		}
+		exposedPorts[nat.Port(e)] = struct{}{}
+	}
+
+	config := &container.Config{
+		Hostname:     svc.Hostname,
+		Domainname:   svc.DomainName,
+		User:         svc.User,
+		ExposedPorts: exposedPorts,
+		Tty:          svc.Tty,
+		OpenStdin:    svc.StdinOpen,
+		AttachStdin:  svc.StdinOpen,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          env,
+		Cmd:          strslice.StrSlice(svc.Command),
+		Entrypoint:   strslice.StrSlice(svc.Entrypoint),
+		Image:        serviceImageName(proj, svc),
+		WorkingDir:   svc.WorkingDir,
+		Labels:       labels,
+		StopSignal:   svc.StopSignal,
+		Healthcheck:  healthConfig(svc.HealthCheck),
+	}
+	if svc.StopGracePeriod != nil {
+		timeout := int(time.Duration(*svc.StopGracePeriod).Seconds())
+		config.StopTimeout = &timeout
+	}
+
+	restartPolicy, err := restartPolicy(svc.Restart)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	hostConfig := &container.HostConfig{
+		PortBindings:   portBindings,
+		RestartPolicy:  restartPolicy,
+		CapAdd:         svc.CapAdd,
+		CapDrop:        svc.CapDrop,
+		DNS:            svc.DNS,
+		DNSOptions:     svc.DNSOpts,
+		DNSSearch:      svc.DNSSearch,
+		ExtraHosts:     svc.ExtraHosts.AsList(),
+		GroupAdd:       svc.GroupAdd,
+		IpcMode:        container.IpcMode(svc.Ipc),
+		PidMode:        container.PidMode(svc.Pid),
+		Privileged:     svc.Privileged,
+		ReadonlyRootfs: svc.ReadOnly,
+		SecurityOpt:    svc.SecurityOpt,
+		Sysctls:        svc.Sysctls,
+		ShmSize:        int64(svc.ShmSize),
+		Init:           svc.Init,
+		Runtime:        svc.Runtime,
+	}
+
+	if len(svc.Tmpfs) > 0 {
+		hostConfig.Tmpfs = map[string]string{}
+		for _, path := range svc.Tmpfs {
+			hostConfig.Tmpfs[path] = ""
+		}
+	}
+
+	for _, v := range svc.Volumes {
+		switch v.Type {
+		case types.VolumeTypeBind:
+			// Binds, unlike mounts, create a missing source directory, like
+			// Compose does.
+			hostConfig.Binds = append(hostConfig.Binds, v.String())
+		case types.VolumeTypeVolume:
+			m := mount.Mount{Type: mount.TypeVolume, Target: v.Target, ReadOnly: v.ReadOnly}
+			if v.Source != "" {
+				m.Source = v.Source
+				if vol, ok := proj.Volumes[v.Source]; ok && vol.Name != "" {
+					m.Source = vol.Name
+				}
+			}
+			if v.Volume != nil && v.Volume.NoCopy {
+				m.VolumeOptions = &mount.VolumeOptions{NoCopy: v.Volume.NoCopy}
+			}
+			hostConfig.Mounts = append(hostConfig.Mounts, m)
+		case types.VolumeTypeTmpfs:
+			m := mount.Mount{Type: mount.TypeTmpfs, Target: v.Target, ReadOnly: v.ReadOnly}
+			if v.Tmpfs != nil {
+				m.TmpfsOptions = &mount.TmpfsOptions{SizeBytes: int64(v.Tmpfs.Size)}
+			}
+			hostConfig.Mounts = append(hostConfig.Mounts, m)
+		case types.VolumeTypeNamedPipe:
+			hostConfig.Mounts = append(hostConfig.Mounts,
+				mount.Mount{Type: mount.TypeNamedPipe, Source: v.Source, Target: v.Target, ReadOnly: v.ReadOnly})
+		default:
+			return nil, nil, nil, fmt.Errorf("service %q: unsupported volume type %q", svc.Name, v.Type)
+		}
+	}
+
+	networkingConfig := &network.NetworkingConfig{}
+	networkMode := svc.NetworkMode
+	if networkMode == "" {
+		networkMode = svc.Net
+	}
+	if networkMode != "" {
+		if strings.HasPrefix(networkMode, "service:") {
+			return nil, nil, nil, fmt.Errorf("service %q: network_mode %q isn't supported without the Docker Compose CLI", svc.Name, networkMode)
+		}
+		hostConfig.NetworkMode = container.NetworkMode(networkMode)
+	} else if keys := serviceNetworkKeys(svc); len(keys) > 0 {
+		name := proj.Networks[keys[0]].Name
+		hostConfig.NetworkMode = container.NetworkMode(name)
+		networkingConfig.EndpointsConfig = map[string]*network.EndpointSettings{
+			name: endpointSettings(svc, keys[0]),
+		}
+	}
+
+	return config, hostConfig, networkingConfig, nil
+}
+
+func ipamConfig(cfg types.IPAMConfig) *network.IPAM {
+	ipam := &network.IPAM{Driver: cfg.Driver}
+	for _, pool := range cfg.Config {
+		ipam.Config = append(ipam.Config, network.IPAMConfig{
+			Subnet:     pool.Subnet,
+			IPRange:    pool.IPRange,
+			Gateway:    pool.Gateway,
+			AuxAddress: pool.AuxiliaryAddresses,
+		})
+	}
+	return ipam
+}
+
+func healthConfig(hc *types.HealthCheckConfig) *container.HealthConfig {
+	if hc == nil {
+		return nil
+	}
+	if hc.Disable {
+		return &container.HealthConfig{Test: []string{"NONE"}}
+	}
+	result := &container.HealthConfig{Test: hc.Test}
+	if hc.Interval != nil {
+		result.Interval = time.Duration(*hc.Interval)
+	}
+	if hc.Timeout != nil {
+		result.Timeout = time.Duration(*hc.Timeout)
+	}
+	if hc.StartPeriod != nil {
+		result.StartPeriod = time.Duration(*hc.StartPeriod)
+	}
+	if hc.Retries != nil {
+		result.Retries = int(*hc.Retries)
+	}
+	return result
+}
+
+// Parses a Compose restart policy, like "on-failure:3".
+func restartPolicy(s string) (container.RestartPolicy, error) {
+	name, count, hasCount := strings.Cut(s, ":")
+	switch name {
+	case "", "no":
+		return container.RestartPolicy{}, nil
+	case "always", "unless-stopped", "on-failure":
+	default:
+		return container.RestartPolicy{}, fmt.Errorf("invalid restart policy %q", s)
+	}
+
+	policy := container.RestartPolicy{Name: name}
+	if hasCount {
+		n, err := strconv.Atoi(count)
+		if err != nil || name != "on-failure" {
+			return container.RestartPolicy{}, fmt.Errorf("invalid restart policy %q", s)
+		}
+		policy.MaximumRetryCount = n
+	}
+	return policy, nil
+}
+
+// Converts an event from the Docker Engine to the Event that
+// `docker compose events --json` would print.
+func eventFromMessage(msg events.Message) Event {
+	attrs := msg.Actor.Attributes
+	return Event{
+		Time:    time.Unix(0, msg.TimeNano).Format(time.RFC3339Nano),
+		Type:    stringToType[msg.Type],
+		Action:  msg.Action,
+		ID:      msg.Actor.ID,
+		Service: attrs[serviceLabel],
+		Attributes: Attributes{
+			Name:  attrs["name"],
+			Image: attrs["image"],
+		},
+	}
+}
+
+// Writes the build context in dir as a tar archive, without the files that
+// its .dockerignore excludes.
+func tarBuildContext(dir string, dockerfile string, w io.Writer) error {
+	var patterns []string
+	f, err := os.Open(filepath.Join(dir, ".dockerignore"))
+	if err == nil {
+		patterns, err = dockerignore.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("reading .dockerignore: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	matcher, err := fileutils.NewPatternMatcher(patterns)
+	if err != nil {
+		return fmt.Errorf("reading .dockerignore: %v", err)
+	}
+
+	tw := tar.NewWriter(w)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// The builder needs these, even when they're ignored.
+		if rel != dockerfile && rel != ".dockerignore" {
+			ignored, err := matcher.Matches(filepath.FromSlash(rel))
+			if err != nil {
+				return err
+			}
+			if ignored {
+				if d.IsDir() && !matcher.Exclusions() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,220 @@
+package dockercompose
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestServiceContainerConfig(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	proj, err := loadProjectFromYAML(v1alpha1.DockerComposeProject{
+		Name:        "myproj",
+		ProjectPath: f.Path(),
+		YAML: `services:
+  web:
+    image: nginx
+    command: ["nginx", "-g", "daemon off;"]
+    environment:
+      GREETING: hello
+    ports:
+      - "8080:80"
+    volumes:
+      - ./html:/usr/share/nginx/html:ro
+      - data:/data
+    networks:
+      - front
+      - back
+    restart: on-failure:3
+volumes:
+  data: {}
+networks:
+  front: {}
+  back: {}
+`,
+	})
+	require.NoError(t, err)
+	svc, err := proj.GetService("web")
+	require.NoError(t, err)
+
+	config, hostConfig, networkingConfig, err := serviceContainerConfig(proj, svc, "abc123")
+	require.NoError(t, err)
+
+	assert.Equal(t, "nginx", config.Image)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, []string(config.Cmd))
+	assert.Equal(t, []string{"GREETING=hello"}, config.Env)
+	assert.Equal(t, "myproj", config.Labels[projectLabel])
+	assert.Equal(t, "web", config.Labels[serviceLabel])
+	assert.Equal(t, "abc123", config.Labels[configHashLabel])
+
+	port := nat.Port("80/tcp")
+	assert.Contains(t, config.ExposedPorts, port)
+	assert.Equal(t, []nat.PortBinding{{HostPort: "8080"}}, hostConfig.PortBindings[port])
+
+	assert.Equal(t, []string{f.JoinPath("html") + ":/usr/share/nginx/html:ro"}, hostConfig.Binds)
+	assert.Equal(t, []mount.Mount{{Type: mount.TypeVolume, Source: "myproj_data", Target: "/data"}}, hostConfig.Mounts)
+	assert.Equal(t, container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}, hostConfig.RestartPolicy)
+
+	// Joins the first network when it's created, and the rest later.
+	assert.Equal(t, container.NetworkMode("myproj_back"), hostConfig.NetworkMode)
+	require.Contains(t, networkingConfig.EndpointsConfig, "myproj_back")
+	assert.Equal(t, []string{"web"}, networkingConfig.EndpointsConfig["myproj_back"].Aliases)
+	assert.Equal(t, []string{"back", "front"}, serviceNetworkKeys(svc))
+}
+
+func TestServiceContainerConfigServiceNetworkMode(t *testing.T) {
+	proj := &types.Project{Name: "myproj"}
+	svc := types.ServiceConfig{Name: "web", Image: "nginx", NetworkMode: "service:db"}
+
+	_, _, _, err := serviceContainerConfig(proj, svc, "abc123")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `network_mode "service:db" isn't supported`)
+}
+
+func TestServiceConfigHash(t *testing.T) {
+	greeting := "hello"
+	svc := types.ServiceConfig{
+		Name:        "web",
+		Image:       "nginx",
+		Environment: types.MappingWithEquals{"GREETING": &greeting},
+	}
+	hash, err := serviceConfigHash(svc)
+	require.NoError(t, err)
+
+	// The build config only matters for building the image.
+	built := svc
+	built.Build = &types.BuildConfig{Context: "."}
+	builtHash, err := serviceConfigHash(built)
+	require.NoError(t, err)
+	assert.Equal(t, hash, builtHash)
+
+	goodbye := "goodbye"
+	changed := svc
+	changed.Environment = types.MappingWithEquals{"GREETING": &goodbye}
+	changedHash, err := serviceConfigHash(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestWithUpOptions(t *testing.T) {
+	version := "1.0"
+	svc := types.ServiceConfig{
+		Name:       "web",
+		PullPolicy: types.PullPolicyMissing,
+		Build: &types.BuildConfig{
+			Context: ".",
+			Args:    types.MappingWithEquals{"VERSION": &version},
+		},
+	}
+
+	actual := withUpOptions(svc, v1alpha1.DockerComposeUpOptions{})
+	assert.Equal(t, svc, actual)
+
+	actual = withUpOptions(svc, v1alpha1.DockerComposeUpOptions{
+		Pull:      types.PullPolicyNever,
+		BuildArgs: map[string]string{"VERSION": "2.0", "DEBUG": "1"},
+	})
+	assert.Equal(t, types.PullPolicyNever, actual.PullPolicy)
+	assert.Equal(t, "2.0", *actual.Build.Args["VERSION"])
+	assert.Equal(t, "1", *actual.Build.Args["DEBUG"])
+
+	// Leaves the original config alone.
+	assert.Equal(t, "1.0", *svc.Build.Args["VERSION"])
+	assert.Len(t, svc.Build.Args, 1)
+}
+
+func TestShouldRepull(t *testing.T) {
+	assert.False(t, shouldRepull(types.ServiceConfig{Image: "redis"}))
+	assert.True(t, shouldRepull(types.ServiceConfig{Image: "redis", PullPolicy: types.PullPolicyAlways}))
+	assert.False(t, shouldRepull(types.ServiceConfig{
+		Image:      "myproj-api",
+		PullPolicy: types.PullPolicyAlways,
+		Build:      &types.BuildConfig{Context: "."},
+	}))
+}
+
+func TestRestartPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy   string
+		expected container.RestartPolicy
+		err      bool
+	}{
+		{policy: "", expected: container.RestartPolicy{}},
+		{policy: "no", expected: container.RestartPolicy{}},
+		{policy: "always", expected: container.RestartPolicy{Name: "always"}},
+		{policy: "unless-stopped", expected: container.RestartPolicy{Name: "unless-stopped"}},
+		{policy: "on-failure:5", expected: container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 5}},
+		{policy: "always:5", err: true},
+		{policy: "sometimes", err: true},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			actual, err := restartPolicy(tc.policy)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestEventFromMessage(t *testing.T) {
+	evt := eventFromMessage(events.Message{
+		Type:   "container",
+		Action: "start",
+		Actor: events.Actor{
+			ID: "abc123",
+			Attributes: map[string]string{
+				serviceLabel: "web",
+				"name":       "myproj-web-1",
+				"image":      "nginx",
+			},
+		},
+		TimeNano: 1,
+	})
+	assert.Equal(t, TypeContainer, evt.Type)
+	assert.Equal(t, "start", evt.Action)
+	assert.Equal(t, "abc123", evt.ID)
+	assert.Equal(t, "web", evt.Service)
+	assert.Equal(t, Attributes{Name: "myproj-web-1", Image: "nginx"}, evt.Attributes)
+}
+
+func TestTarBuildContext(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("Dockerfile", "FROM alpine\n")
+	f.WriteFile(".dockerignore", "Dockerfile\nnode_modules\n*.log\n!keep.log\n")
+	f.WriteFile("main.go", "package main\n")
+	f.WriteFile("debug.log", "debug\n")
+	f.WriteFile("keep.log", "keep\n")
+	f.WriteFile("node_modules/left-pad/index.js", "\n")
+
+	var buf bytes.Buffer
+	require.NoError(t, tarBuildContext(f.Path(), "Dockerfile", &buf))
+
+	var names []string
+	tr := tar.NewReader(&buf)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, h.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{".dockerignore", "Dockerfile", "keep.log", "main.go"}, names)
+}
@@ -0,0 +1,232 @@
+package dockercompose
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gopkg.in/yaml.v3"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The files that a project's config comes from: its config files, the
+// files their services extend, the env file for interpolation, and the
+// services' env files.
+//
+// The env file for interpolation is included even if it doesn't exist,
+// because creating it changes the project.
+func ProjectFiles(spec v1alpha1.DockerComposeProject, proj *types.Project) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	workingDir := projectWorkingDir(spec)
+	visited := make(map[string]bool)
+	for _, p := range spec.ConfigPaths {
+		add(p)
+		for _, f := range extendedFiles(p, workingDir, visited) {
+			add(f)
+		}
+	}
+
+	if spec.EnvFile != "" {
+		add(spec.EnvFile)
+	} else if workingDir != "" {
+		add(filepath.Join(workingDir, ".env"))
+	}
+
+	if proj != nil {
+		for _, svc := range proj.AllServices() {
+			for _, f := range svc.EnvFile {
+				if !filepath.IsAbs(f) {
+					f = filepath.Join(proj.WorkingDir, f)
+				}
+				add(f)
+			}
+		}
+	}
+	return files
+}
+
+// Where Docker Compose looks for the default .env file.
+func projectWorkingDir(spec v1alpha1.DockerComposeProject) string {
+	if spec.ProjectPath != "" {
+		return spec.ProjectPath
+	}
+	if len(spec.ConfigPaths) > 0 {
+		return filepath.Dir(spec.ConfigPaths[0])
+	}
+	return ""
+}
+
+// The files that services in a config file extend, recursively.
+//
+// Like Docker Compose, resolves relative paths in the project's config files
+// against the project's working dir, and relative paths in extended files
+// against the extended file's dir. Paths that need interpolation are
+// skipped, because they can't be resolved without loading the project.
+func extendedFiles(path string, dir string, visited map[string]bool) []string {
+	if visited[path] {
+		return nil
+	}
+	visited[path] = true
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var config struct {
+		Services map[string]struct {
+			Extends interface{} `yaml:"extends"`
+		} `yaml:"services"`
+	}
+	if yaml.Unmarshal(contents, &config) != nil {
+		return nil
+	}
+
+	var result []string
+	for _, svc := range config.Services {
+		extends, ok := svc.Extends.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		file, ok := extends["file"].(string)
+		if !ok || file == "" || strings.Contains(file, "$") {
+			continue
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		result = append(result, file)
+		result = append(result, extendedFiles(file, filepath.Dir(file), visited)...)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Caches loaded projects, so that big projects aren't re-parsed (or
+// re-resolved by the CLI) every time they're needed.
+//
+// A cached project is reused until one of its files changes, or the
+// environment that it's interpolated with changes.
+type projectCache struct {
+	mu      sync.Mutex
+	entries map[string]projectCacheEntry
+}
+
+type projectCacheEntry struct {
+	proj  *types.Project
+	files map[string]fileStamp
+}
+
+// Enough to tell if a file changed, without reading it.
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func newProjectCache() *projectCache {
+	return &projectCache{entries: make(map[string]projectCacheEntry)}
+}
+
+// Returns the cached project for the spec if it's still up to date, and
+// otherwise loads it with load.
+//
+// Callers share the cached project, so they must not modify it.
+func (c *projectCache) load(spec v1alpha1.DockerComposeProject, load func() (*types.Project, error)) (*types.Project, error) {
+	key, err := projectCacheKey(spec)
+	if err != nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && entry.upToDate() {
+		return copyProject(entry.proj), nil
+	}
+
+	// Stamp the files before loading, so that a change in the middle of
+	// loading invalidates the entry.
+	files := make(map[string]fileStamp)
+	for _, f := range ProjectFiles(spec, nil) {
+		files[f] = stampFile(f)
+	}
+	proj, err := load()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range ProjectFiles(spec, proj) {
+		if _, ok := files[f]; !ok {
+			files[f] = stampFile(f)
+		}
+	}
+
+	c.mu.Lock()
+	c.entries[key] = projectCacheEntry{proj: proj, files: files}
+	c.mu.Unlock()
+	return copyProject(proj), nil
+}
+
+func (e projectCacheEntry) upToDate() bool {
+	for f, stamp := range e.files {
+		if stampFile(f) != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+func stampFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
+
+// Projects are interpolated with the OS environment, which the Tiltfile
+// can change with os.putenv().
+func projectCacheKey(spec v1alpha1.DockerComposeProject) (string, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	env := os.Environ()
+	sort.Strings(env)
+
+	h := sha256.New()
+	_, _ = h.Write(specJSON)
+	for _, e := range env {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(e))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// Copies the service lists, so that callers can enable or disable services
+// without affecting the cached project.
+func copyProject(proj *types.Project) *types.Project {
+	result := *proj
+	if proj.Services != nil {
+		result.Services = append(types.Services{}, proj.Services...)
+	}
+	if proj.DisabledServices != nil {
+		result.DisabledServices = append(types.Services{}, proj.DisabledServices...)
+	}
+	return &result
+}
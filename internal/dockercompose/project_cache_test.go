@@ -0,0 +1,116 @@
+package dockercompose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestProjectFiles(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("docker-compose.yml", `services:
+  web:
+    extends:
+      file: common/base.yml
+      service: base
+  db:
+    extends: web
+`)
+	f.WriteFile("common/base.yml", `services:
+  base:
+    extends:
+      file: logging.yml
+      service: logging
+`)
+	f.WriteFile("common/logging.yml", `services:
+  logging:
+    image: alpine
+`)
+
+	spec := v1alpha1.DockerComposeProject{
+		ProjectPath: f.Path(),
+		ConfigPaths: []string{f.JoinPath("docker-compose.yml")},
+	}
+	proj := &types.Project{
+		WorkingDir: f.Path(),
+		Services:   types.Services{{Name: "web", EnvFile: types.StringList{"web.env"}}},
+	}
+	assert.Equal(t, []string{
+		f.JoinPath("docker-compose.yml"),
+		f.JoinPath("common", "base.yml"),
+		f.JoinPath("common", "logging.yml"),
+		f.JoinPath(".env"),
+		f.JoinPath("web.env"),
+	}, ProjectFiles(spec, proj))
+
+	spec.EnvFile = f.JoinPath("local.env")
+	assert.Equal(t, []string{
+		f.JoinPath("docker-compose.yml"),
+		f.JoinPath("common", "base.yml"),
+		f.JoinPath("common", "logging.yml"),
+		f.JoinPath("local.env"),
+	}, ProjectFiles(spec, nil))
+}
+
+func TestProjectCacheReloadsWhenFilesChange(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("docker-compose.yml", `services:
+  web:
+    extends:
+      file: base.yml
+      service: base
+`)
+	f.WriteFile("base.yml", `services:
+  base:
+    image: alpine
+`)
+	spec := v1alpha1.DockerComposeProject{
+		Name:        "myproj",
+		ProjectPath: f.Path(),
+		ConfigPaths: []string{f.JoinPath("docker-compose.yml")},
+	}
+
+	cache := newProjectCache()
+	loads := 0
+	load := func() (*types.Project, error) {
+		loads++
+		return loadProjectNative(spec)
+	}
+
+	proj, err := cache.load(spec, load)
+	require.NoError(t, err)
+	assert.Equal(t, "alpine", proj.Services[0].Image)
+
+	// Changing the returned project doesn't change the cached one.
+	proj.Services = nil
+
+	proj, err = cache.load(spec, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, "alpine", proj.Services[0].Image)
+
+	f.WriteFile("base.yml", `services:
+  base:
+    image: busybox
+`)
+	proj, err = cache.load(spec, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+	assert.Equal(t, "busybox", proj.Services[0].Image)
+
+	// Creating the .env file can change the project, too.
+	f.WriteFile(".env", "FOO=bar\n")
+	_, err = cache.load(spec, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads)
+
+	t.Setenv("TILT_PROJECT_CACHE_TEST", "1")
+	_, err = cache.load(spec, load)
+	require.NoError(t, err)
+	assert.Equal(t, 4, loads)
+}
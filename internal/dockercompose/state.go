@@ -25,6 +25,12 @@ const ContainerStatusRemoving = "removing"
 const ContainerStatusExited = "exited"
 const ContainerStatusDead = "dead"
 
+// Health strings taken from:
+// https://godoc.org/github.com/docker/docker/api/types#Health
+const HealthStatusStarting = "starting"
+const HealthStatusHealthy = "healthy"
+const HealthStatusUnhealthy = "unhealthy"
+
 // Helper functions for dealing with ContainerState.
 const ZeroTime = "0001-01-01T00:00:00Z"
 
@@ -43,6 +49,15 @@ func (s State) RuntimeStatus() v1alpha1.RuntimeStatus {
 	if s.ContainerState.Error != "" || s.ContainerState.ExitCode != 0 {
 		return v1alpha1.RuntimeStatusError
 	}
+	// A running container with a healthcheck isn't ready until it passes.
+	if s.ContainerState.Running {
+		switch s.ContainerState.HealthStatus {
+		case HealthStatusStarting:
+			return v1alpha1.RuntimeStatusPending
+		case HealthStatusUnhealthy:
+			return v1alpha1.RuntimeStatusError
+		}
+	}
 	if s.ContainerState.Running ||
 		s.ContainerState.Status == ContainerStatusRunning ||
 		s.ContainerState.Status == ContainerStatusExited {
@@ -65,6 +80,9 @@ func (s State) RuntimeStatusError() error {
 	if s.ContainerState.ExitCode != 0 {
 		return fmt.Errorf("Container %s exited with %d", s.ContainerID, s.ContainerState.ExitCode)
 	}
+	if s.ContainerState.HealthStatus == HealthStatusUnhealthy {
+		return fmt.Errorf("Container %s is unhealthy", s.ContainerID)
+	}
 	return fmt.Errorf("Container %s error status: %s", s.ContainerID, s.ContainerState.Status)
 }
 
@@ -122,13 +140,19 @@ func ToContainerState(state *types.ContainerState) *v1alpha1.DockerContainerStat
 		}
 	}
 
+	var healthStatus string
+	if state.Health != nil {
+		healthStatus = state.Health.Status
+	}
+
 	return &v1alpha1.DockerContainerState{
-		Status:     state.Status,
-		Running:    state.Running,
-		Error:      state.Error,
-		ExitCode:   int32(state.ExitCode),
-		StartedAt:  metav1.NewMicroTime(startedAt),
-		FinishedAt: metav1.NewMicroTime(finishedAt),
+		Status:       state.Status,
+		Running:      state.Running,
+		Error:        state.Error,
+		ExitCode:     int32(state.ExitCode),
+		StartedAt:    metav1.NewMicroTime(startedAt),
+		FinishedAt:   metav1.NewMicroTime(finishedAt),
+		HealthStatus: healthStatus,
 	}
 }
 
@@ -0,0 +1,51 @@
+package dockercompose
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestRuntimeStatusHealth(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		state    v1alpha1.DockerContainerState
+		expected v1alpha1.RuntimeStatus
+	}{
+		{"no healthcheck", v1alpha1.DockerContainerState{Status: ContainerStatusRunning, Running: true}, v1alpha1.RuntimeStatusOK},
+		{"starting", v1alpha1.DockerContainerState{Status: ContainerStatusRunning, Running: true, HealthStatus: HealthStatusStarting}, v1alpha1.RuntimeStatusPending},
+		{"healthy", v1alpha1.DockerContainerState{Status: ContainerStatusRunning, Running: true, HealthStatus: HealthStatusHealthy}, v1alpha1.RuntimeStatusOK},
+		{"unhealthy", v1alpha1.DockerContainerState{Status: ContainerStatusRunning, Running: true, HealthStatus: HealthStatusUnhealthy}, v1alpha1.RuntimeStatusError},
+		{"exited while unhealthy", v1alpha1.DockerContainerState{Status: ContainerStatusExited, HealthStatus: HealthStatusUnhealthy}, v1alpha1.RuntimeStatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := State{ContainerID: "abc123"}.WithContainerState(tc.state)
+			assert.Equal(t, tc.expected, s.RuntimeStatus())
+			assert.Equal(t, tc.expected == v1alpha1.RuntimeStatusOK, s.HasEverBeenReadyOrSucceeded())
+		})
+	}
+}
+
+func TestRuntimeStatusErrorUnhealthy(t *testing.T) {
+	s := State{ContainerID: "abc123"}.WithContainerState(v1alpha1.DockerContainerState{
+		Status:       ContainerStatusRunning,
+		Running:      true,
+		HealthStatus: HealthStatusUnhealthy,
+	})
+	assert.EqualError(t, s.RuntimeStatusError(), "Container abc123 is unhealthy")
+}
+
+func TestToContainerStateHealth(t *testing.T) {
+	state := ToContainerState(&types.ContainerState{
+		Status:  ContainerStatusRunning,
+		Running: true,
+		Health:  &types.Health{Status: HealthStatusStarting},
+	})
+	assert.Equal(t, HealthStatusStarting, state.HealthStatus)
+
+	state = ToContainerState(&types.ContainerState{Status: ContainerStatusRunning, Running: true})
+	assert.Equal(t, "", state.HealthStatus)
+}
@@ -0,0 +1,75 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/tilt-dev/tilt/internal/docker"
+)
+
+// Checks that Tilt can talk to Docker, with an API version it can build with.
+//
+// clientErr is the error from creating the client, if any.
+// dockerContext and dockerHost are what the Docker CLI would use,
+// so that we can point out when they disagree.
+func DockerCheck(name string, client docker.Client, clientErr error, dockerContext string, dockerHost string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			err := clientErr
+			if err == nil {
+				err = client.CheckConnected()
+			}
+			return checkDocker(client, err, dockerContext, dockerHost)
+		},
+	}
+}
+
+func checkDocker(client docker.Client, err error, dockerContext string, dockerHost string) Result {
+	if err != nil {
+		return fail(
+			"Make sure Docker is running. If you use Docker contexts, run 'docker context ls' "+
+				"to check which one is active.",
+			"Could not connect to Docker (context %q): %v", dockerContext, err)
+	}
+
+	v := client.ServerVersion()
+	if dockerHost != "" && dockerContext != "default" {
+		return warn(
+			"Unset DOCKER_HOST, or run 'docker context use default'.",
+			"DOCKER_HOST=%s overrides the Docker context %q, so Tilt and the docker CLI may use different daemons",
+			dockerHost, dockerContext)
+	}
+	if !docker.SupportsBuildkit(v, client.Env()) {
+		return warn(
+			"Upgrade Docker to use BuildKit, which Tilt builds faster with.",
+			"Docker %s (API %s) doesn't support BuildKit", v.Version, v.APIVersion)
+	}
+	return pass("Context %q, Docker %s, API %s", dockerContext, v.Version, v.APIVersion)
+}
+
+// The current Docker CLI context, picked the same way the docker CLI does.
+func DockerContextName(getenv func(string) string, homeDir string) string {
+	if name := getenv("DOCKER_CONTEXT"); name != "" {
+		return name
+	}
+
+	configDir := getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		configDir = filepath.Join(homeDir, ".docker")
+	}
+	b, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if err != nil {
+		return "default"
+	}
+
+	var config struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if json.Unmarshal(b, &config) != nil || config.CurrentContext == "" {
+		return "default"
+	}
+	return config.CurrentContext
+}
@@ -0,0 +1,47 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/docker"
+)
+
+func TestDockerNotConnected(t *testing.T) {
+	r := checkDocker(nil, fmt.Errorf("connection refused"), "desktop-linux", "")
+	assert.Equal(t, StatusFail, r.Status)
+	assert.Equal(t, `Could not connect to Docker (context "desktop-linux"): connection refused`, r.Message)
+}
+
+func TestDockerHostOverridesContext(t *testing.T) {
+	r := checkDocker(docker.NewFakeClient(), nil, "desktop-linux", "tcp://localhost:2375")
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Contains(t, r.Message, `DOCKER_HOST=tcp://localhost:2375 overrides the Docker context "desktop-linux"`)
+}
+
+func TestDockerWithoutBuildkit(t *testing.T) {
+	r := checkDocker(docker.NewFakeClient(), nil, "default", "")
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Contains(t, r.Message, "doesn't support BuildKit")
+}
+
+func TestDockerContextName(t *testing.T) {
+	home := t.TempDir()
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	assert.Equal(t, "default", DockerContextName(getenv, home))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".docker"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".docker", "config.json"),
+		[]byte(`{"currentContext": "colima"}`), 0644))
+	assert.Equal(t, "colima", DockerContextName(getenv, home))
+
+	env["DOCKER_CONTEXT"] = "remote"
+	assert.Equal(t, "remote", DockerContextName(getenv, home))
+}
@@ -0,0 +1,70 @@
+// Package doctor checks the environment for common problems that keep
+// Tilt from working, and suggests how to fix each one.
+package doctor
+
+import (
+	"context"
+	"fmt"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+
+	// The check doesn't apply here, e.g., inotify limits on macOS.
+	StatusSkip Status = "skip"
+)
+
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+
+	// What to do about a warning or failure.
+	Remediation string `json:"remediation,omitempty"`
+}
+
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) Result
+}
+
+// Runs each check in order.
+func Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		r := c.Run(ctx)
+		r.Name = c.Name
+		results = append(results, r)
+	}
+	return results
+}
+
+// Whether any of the results failed.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func pass(format string, a ...interface{}) Result {
+	return Result{Status: StatusPass, Message: fmt.Sprintf(format, a...)}
+}
+
+func skip(format string, a ...interface{}) Result {
+	return Result{Status: StatusSkip, Message: fmt.Sprintf(format, a...)}
+}
+
+func warn(remediation string, format string, a ...interface{}) Result {
+	return Result{Status: StatusWarn, Message: fmt.Sprintf(format, a...), Remediation: remediation}
+}
+
+func fail(remediation string, format string, a ...interface{}) Result {
+	return Result{Status: StatusFail, Message: fmt.Sprintf(format, a...), Remediation: remediation}
+}
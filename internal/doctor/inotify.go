@@ -0,0 +1,62 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Below these limits, Tilt may not be able to watch all the files in a
+// medium-sized project, especially with other editors and tools
+// watching files too.
+const (
+	minInotifyWatches   = 65536
+	minInotifyInstances = 256
+)
+
+// Checks the inotify limits on Linux, which bound how many files Tilt can watch.
+func InotifyCheck(procDir string) Check {
+	return Check{
+		Name: "inotify limits",
+		Run: func(ctx context.Context) Result {
+			if runtime.GOOS != "linux" {
+				return skip("Only applies to Linux")
+			}
+			return checkInotify(procDir)
+		},
+	}
+}
+
+func checkInotify(procDir string) Result {
+	watches, err := readProcInt(procDir, "sys/fs/inotify/max_user_watches")
+	if err != nil {
+		return warn("", "Could not read max_user_watches: %v", err)
+	}
+	instances, err := readProcInt(procDir, "sys/fs/inotify/max_user_instances")
+	if err != nil {
+		return warn("", "Could not read max_user_instances: %v", err)
+	}
+
+	msg := fmt.Sprintf("max_user_watches=%d, max_user_instances=%d", watches, instances)
+	if watches < minInotifyWatches || instances < minInotifyInstances {
+		return warn(
+			"Raise the limits with:\n"+
+				"  sudo sysctl fs.inotify.max_user_watches=524288 fs.inotify.max_user_instances=512\n"+
+				"To keep them after a reboot, add them to /etc/sysctl.conf.",
+			"%s; Tilt may run out of file watches (want at least %d and %d)",
+			msg, minInotifyWatches, minInotifyInstances)
+	}
+	return pass("%s", msg)
+}
+
+func readProcInt(procDir, path string) (int, error) {
+	b, err := os.ReadFile(filepath.Join(procDir, path))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
@@ -0,0 +1,40 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInotifyLimits(t *testing.T) {
+	dir := writeInotifyLimits(t, "524288", "512")
+	r := checkInotify(dir)
+	assert.Equal(t, StatusPass, r.Status)
+	assert.Equal(t, "max_user_watches=524288, max_user_instances=512", r.Message)
+}
+
+func TestInotifyLimitsTooLow(t *testing.T) {
+	dir := writeInotifyLimits(t, "8192", "128")
+	r := checkInotify(dir)
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Contains(t, r.Message, "max_user_watches=8192, max_user_instances=128; Tilt may run out of file watches")
+	assert.Contains(t, r.Remediation, "sudo sysctl fs.inotify.max_user_watches=524288")
+}
+
+func TestInotifyLimitsMissing(t *testing.T) {
+	r := checkInotify(t.TempDir())
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Contains(t, r.Message, "Could not read max_user_watches")
+}
+
+func writeInotifyLimits(t *testing.T, watches, instances string) string {
+	dir := t.TempDir()
+	inotifyDir := filepath.Join(dir, "sys", "fs", "inotify")
+	require.NoError(t, os.MkdirAll(inotifyDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(inotifyDir, "max_user_watches"), []byte(watches+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(inotifyDir, "max_user_instances"), []byte(instances+"\n"), 0644))
+	return dir
+}
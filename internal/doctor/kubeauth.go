@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Warn when credentials expire sooner than this.
+const credentialExpiryWarning = 24 * time.Hour
+
+// Checks that the credentials for the current Kubernetes context
+// haven't expired, and that the cluster accepts them.
+//
+// serverErr is the error from talking to the cluster, if any.
+func KubeAuthCheck(config *api.Config, serverErr error, now func() time.Time) Check {
+	return Check{
+		Name: "Kubernetes credentials",
+		Run: func(ctx context.Context) Result {
+			return checkKubeAuth(config, serverErr, now())
+		},
+	}
+}
+
+func checkKubeAuth(config *api.Config, serverErr error, now time.Time) Result {
+	if config == nil {
+		return skip("No Kubernetes config")
+	}
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return skip("No current Kubernetes context")
+	}
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return fail("Check the users in your kubeconfig with 'kubectl config view'.",
+			"Context %q refers to missing user %q", config.CurrentContext, kubeContext.AuthInfo)
+	}
+
+	reauth := fmt.Sprintf("Log in to the cluster again to refresh the credentials for user %q.", kubeContext.AuthInfo)
+	if serverErr != nil && (apierrors.IsUnauthorized(serverErr) || apierrors.IsForbidden(serverErr)) {
+		return fail(reauth, "The cluster rejected the credentials: %v", serverErr)
+	}
+
+	expiry, source, err := credentialExpiry(authInfo)
+	if err != nil {
+		return warn("", "Could not read %s: %v", source, err)
+	}
+	if expiry.IsZero() {
+		if source == "" {
+			return pass("User %q", kubeContext.AuthInfo)
+		}
+		return pass("User %q authenticates with %s", kubeContext.AuthInfo, source)
+	}
+
+	remaining := expiry.Sub(now)
+	if remaining <= 0 {
+		return fail(reauth, "The %s for user %q expired at %s",
+			source, kubeContext.AuthInfo, expiry.Format(time.RFC3339))
+	}
+	if remaining < credentialExpiryWarning {
+		return warn(reauth, "The %s for user %q expires in %s",
+			source, kubeContext.AuthInfo, remaining.Round(time.Minute))
+	}
+	return pass("The %s for user %q expires at %s",
+		source, kubeContext.AuthInfo, expiry.Format(time.RFC3339))
+}
+
+// Returns when the user's credentials expire, and what kind they are.
+// The expiry is zero if it can't be known ahead of time.
+func credentialExpiry(authInfo *api.AuthInfo) (time.Time, string, error) {
+	switch {
+	case len(authInfo.ClientCertificateData) > 0:
+		t, err := certExpiry(authInfo.ClientCertificateData)
+		return t, "client certificate", err
+	case authInfo.ClientCertificate != "":
+		b, err := os.ReadFile(authInfo.ClientCertificate)
+		if err != nil {
+			return time.Time{}, "client certificate", err
+		}
+		t, err := certExpiry(b)
+		return t, "client certificate", err
+	case authInfo.Token != "":
+		return tokenExpiry(authInfo.Token), "token", nil
+	case authInfo.TokenFile != "":
+		b, err := os.ReadFile(authInfo.TokenFile)
+		if err != nil {
+			return time.Time{}, "token", err
+		}
+		return tokenExpiry(strings.TrimSpace(string(b))), "token", nil
+	case authInfo.Exec != nil:
+		return time.Time{}, fmt.Sprintf("exec plugin %q", authInfo.Exec.Command), nil
+	case authInfo.AuthProvider != nil:
+		return time.Time{}, fmt.Sprintf("auth provider %q", authInfo.AuthProvider.Name), nil
+	}
+	return time.Time{}, "", nil
+}
+
+func certExpiry(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("not a PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// Reads the expiry of a JWT bearer token. Other tokens don't say when
+// they expire, so they get a zero time.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
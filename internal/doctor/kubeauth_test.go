@@ -0,0 +1,87 @@
+package doctor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var kubeAuthNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestKubeAuthCertificate(t *testing.T) {
+	config := kubeConfigWithUser(&api.AuthInfo{ClientCertificateData: testCert(t, kubeAuthNow.Add(30*24*time.Hour))})
+	r := checkKubeAuth(config, nil, kubeAuthNow)
+	assert.Equal(t, StatusPass, r.Status)
+	assert.Equal(t, `The client certificate for user "me" expires at 2024-03-31T12:00:00Z`, r.Message)
+}
+
+func TestKubeAuthCertificateExpiringSoon(t *testing.T) {
+	config := kubeConfigWithUser(&api.AuthInfo{ClientCertificateData: testCert(t, kubeAuthNow.Add(2*time.Hour))})
+	r := checkKubeAuth(config, nil, kubeAuthNow)
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Equal(t, `The client certificate for user "me" expires in 2h0m0s`, r.Message)
+}
+
+func TestKubeAuthTokenExpired(t *testing.T) {
+	config := kubeConfigWithUser(&api.AuthInfo{Token: testJWT(kubeAuthNow.Add(-time.Hour))})
+	r := checkKubeAuth(config, nil, kubeAuthNow)
+	assert.Equal(t, StatusFail, r.Status)
+	assert.Equal(t, `The token for user "me" expired at 2024-03-01T11:00:00Z`, r.Message)
+	assert.Contains(t, r.Remediation, "Log in to the cluster again")
+}
+
+func TestKubeAuthExecPlugin(t *testing.T) {
+	config := kubeConfigWithUser(&api.AuthInfo{Exec: &api.ExecConfig{Command: "gke-gcloud-auth-plugin"}})
+	r := checkKubeAuth(config, nil, kubeAuthNow)
+	assert.Equal(t, StatusPass, r.Status)
+	assert.Equal(t, `User "me" authenticates with exec plugin "gke-gcloud-auth-plugin"`, r.Message)
+}
+
+func TestKubeAuthRejected(t *testing.T) {
+	config := kubeConfigWithUser(&api.AuthInfo{Token: "opaque"})
+	r := checkKubeAuth(config, apierrors.NewUnauthorized("bad token"), kubeAuthNow)
+	assert.Equal(t, StatusFail, r.Status)
+	assert.Equal(t, "The cluster rejected the credentials: bad token", r.Message)
+}
+
+func kubeConfigWithUser(authInfo *api.AuthInfo) *api.Config {
+	return &api.Config{
+		CurrentContext: "dev",
+		Contexts:       map[string]*api.Context{"dev": {AuthInfo: "me", Cluster: "dev"}},
+		AuthInfos:      map[string]*api.AuthInfo{"me": authInfo},
+	}
+}
+
+func testCert(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "me"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return header + "." + payload + ".sig"
+}
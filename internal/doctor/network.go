@@ -0,0 +1,164 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Skew beyond these makes certificates and tokens look expired or not yet valid.
+const (
+	clockSkewWarning = 30 * time.Second
+	clockSkewFailure = 5 * time.Minute
+)
+
+// Checks that the cluster's local registry, where Tilt pushes images,
+// answers Docker Registry API requests.
+func RegistryCheck(client *http.Client, registry *v1alpha1.RegistryHosting) Check {
+	return Check{
+		Name: "Registry reachability",
+		Run: func(ctx context.Context) Result {
+			return checkRegistry(ctx, client, registry)
+		},
+	}
+}
+
+func checkRegistry(ctx context.Context, client *http.Client, registry *v1alpha1.RegistryHosting) Result {
+	if registry == nil || container.IsEmptyRegistry(registry) {
+		return skip("The cluster has no local registry")
+	}
+
+	host := registry.Host
+	var lastErr error
+	for _, scheme := range []string{"https", "http"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/v2/", scheme, host), nil)
+		if err != nil {
+			return fail("", "Invalid registry host %q: %v", host, err)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		_ = res.Body.Close()
+
+		// 401 means the registry is up, and wants credentials.
+		if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusUnauthorized {
+			msg := fmt.Sprintf("Registry %s is up", host)
+			if fromCluster := registry.HostFromContainerRuntime; fromCluster != "" && fromCluster != host {
+				msg += fmt.Sprintf(" (the cluster pulls from %s)", fromCluster)
+			}
+			return pass("%s", msg)
+		}
+		lastErr = fmt.Errorf("%s", res.Status)
+	}
+
+	return fail(
+		"Make sure the registry container is running, and that its port is published on this machine.",
+		"Could not reach registry %s: %v", host, lastErr)
+}
+
+// Checks that the clock on this machine agrees with the cluster's,
+// by comparing it to the Date header of a request to the API server.
+func ClockSkewCheck(client *http.Client, serverURL string, now func() time.Time) Check {
+	return Check{
+		Name: "Clock skew",
+		Run: func(ctx context.Context) Result {
+			return checkClockSkew(ctx, client, serverURL, now)
+		},
+	}
+}
+
+func checkClockSkew(ctx context.Context, client *http.Client, serverURL string, now func() time.Time) Result {
+	if client == nil || serverURL == "" {
+		return skip("No Kubernetes cluster")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL+"/version", nil)
+	if err != nil {
+		return fail("", "Invalid cluster URL %q: %v", serverURL, err)
+	}
+	start := now()
+	res, err := client.Do(req)
+	if err != nil {
+		return warn("", "Could not reach the cluster: %v", err)
+	}
+	_ = res.Body.Close()
+	end := now()
+
+	serverTime, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		return skip("The cluster didn't say what time it is")
+	}
+
+	// The Date header is rounded to the second, so compare it to the
+	// middle of the request, and ignore anything within a second.
+	localTime := start.Add(end.Sub(start) / 2)
+	skew := serverTime.Sub(localTime)
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+
+	msg := fmt.Sprintf("The cluster's clock is %s %s this machine's", abs.Round(time.Second), aheadOrBehind(skew))
+	remediation := "Sync this machine's clock (e.g., enable NTP), and restart the cluster's VM if it runs in one."
+	switch {
+	case abs <= time.Second:
+		return pass("The cluster's clock agrees with this machine's")
+	case abs >= clockSkewFailure:
+		return fail(remediation, "%s; certificates and tokens may look expired", msg)
+	case abs >= clockSkewWarning:
+		return warn(remediation, "%s", msg)
+	}
+	return pass("%s", msg)
+}
+
+func aheadOrBehind(skew time.Duration) string {
+	if skew > 0 {
+		return "ahead of"
+	}
+	return "behind"
+}
+
+// Checks that Tilt's web server port is free.
+func PortCheck(client *http.Client, host string, port int) Check {
+	return Check{
+		Name: "Web UI port",
+		Run: func(ctx context.Context) Result {
+			return checkPort(ctx, client, host, port)
+		},
+	}
+}
+
+func checkPort(ctx context.Context, client *http.Client, host string, port int) Result {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	l, err := net.Listen("tcp", addr)
+	if err == nil {
+		_ = l.Close()
+		return pass("Port %d is free", port)
+	}
+
+	otherPort := "run Tilt on another port with --port or TILT_PORT."
+
+	// If it's Tilt, the port is probably fine, it's just busy.
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/api/websocket_token", addr), nil)
+	if reqErr == nil {
+		res, reqErr := client.Do(req)
+		if reqErr == nil {
+			_ = res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return warn("Stop the other Tilt with Ctrl-C, or "+otherPort,
+					"Tilt is already running on port %d", port)
+			}
+		}
+	}
+
+	return fail("Stop the process using the port, or "+otherPort,
+		"Port %d is in use by another process: %v", port, err)
+}
@@ -0,0 +1,102 @@
+package doctor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestRegistryUp(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v2/", req.URL.Path)
+	}))
+	defer s.Close()
+
+	host := strings.TrimPrefix(s.URL, "http://")
+	r := checkRegistry(context.Background(), s.Client(), &v1alpha1.RegistryHosting{
+		Host:                     host,
+		HostFromContainerRuntime: "kind-registry:5000",
+	})
+	assert.Equal(t, StatusPass, r.Status)
+	assert.Equal(t, "Registry "+host+" is up (the cluster pulls from kind-registry:5000)", r.Message)
+}
+
+func TestRegistryDown(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	host := strings.TrimPrefix(s.URL, "http://")
+	r := checkRegistry(context.Background(), s.Client(), &v1alpha1.RegistryHosting{Host: host})
+	assert.Equal(t, StatusFail, r.Status)
+	assert.Equal(t, "Could not reach registry "+host+": 404 Not Found", r.Message)
+}
+
+func TestRegistryNone(t *testing.T) {
+	r := checkRegistry(context.Background(), http.DefaultClient, &v1alpha1.RegistryHosting{})
+	assert.Equal(t, StatusSkip, r.Status)
+}
+
+func TestClockSkew(t *testing.T) {
+	serverTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+	}))
+	defer s.Close()
+
+	for _, tc := range []struct {
+		local   time.Time
+		status  Status
+		message string
+	}{
+		{serverTime, StatusPass, "The cluster's clock agrees with this machine's"},
+		{serverTime.Add(-10 * time.Second), StatusPass, "The cluster's clock is 10s ahead of this machine's"},
+		{serverTime.Add(time.Minute), StatusWarn, "The cluster's clock is 1m0s behind this machine's"},
+		{serverTime.Add(-time.Hour), StatusFail, "The cluster's clock is 1h0m0s ahead of this machine's; certificates and tokens may look expired"},
+	} {
+		local := tc.local
+		r := checkClockSkew(context.Background(), s.Client(), s.URL, func() time.Time { return local })
+		assert.Equal(t, tc.status, r.Status)
+		assert.Equal(t, tc.message, r.Message)
+	}
+}
+
+func TestPortFree(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	r := checkPort(context.Background(), http.DefaultClient, "localhost", port)
+	assert.Equal(t, StatusPass, r.Status)
+}
+
+func TestPortUsedByTilt(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/api/websocket_token", req.URL.Path)
+	}))
+	defer s.Close()
+
+	port := s.Listener.Addr().(*net.TCPAddr).Port
+	r := checkPort(context.Background(), s.Client(), "127.0.0.1", port)
+	assert.Equal(t, StatusWarn, r.Status)
+	assert.Contains(t, r.Message, "Tilt is already running")
+}
+
+func TestPortUsedByOther(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	port := s.Listener.Addr().(*net.TCPAddr).Port
+	r := checkPort(context.Background(), s.Client(), "127.0.0.1", port)
+	assert.Equal(t, StatusFail, r.Status)
+	assert.Contains(t, r.Message, "is in use by another process")
+}
@@ -134,7 +134,8 @@ func waitingOnDependencies(state store.EngineState, mt *store.ManifestTarget) []
 	var waitingOn []model.TargetID
 	for _, mn := range mt.Manifest.ResourceDependencies {
 		ms, ok := state.ManifestState(mn)
-		if !ok || ms == nil || ms.RuntimeState == nil || !ms.RuntimeState.HasEverBeenReadyOrSucceeded() {
+		if !ok || ms == nil || ms.RuntimeState == nil || !ms.RuntimeState.HasEverBeenReadyOrSucceeded() ||
+			!ms.HealthProbesEverReady() {
 			waitingOn = append(waitingOn, mn.TargetID())
 		}
 	}
@@ -117,6 +117,31 @@ func TestK8sDependsOnLocal(t *testing.T) {
 	_ = k8s2
 }
 
+func TestDependsOnHealthProbe(t *testing.T) {
+	f := newTestFixture(t)
+
+	_ = f.upsertK8sManifest("k8s1", withResourceDeps("local1"))
+	local1 := f.upsertLocalManifest("local1")
+	local1.State.HealthProbes = map[string]v1alpha1.HealthProbeStatus{"local1-health": {}}
+
+	local1.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	lrs := local1.State.LocalRuntimeState()
+	lrs.LastReadyOrSucceededTime = time.Now()
+	local1.State.RuntimeState = lrs
+
+	// The dependency isn't ready until its probes pass.
+	f.assertHold("k8s1", store.HoldReasonWaitingForDep, model.ManifestName("local1").TargetID())
+
+	local1.State.HealthProbes["local1-health"] = v1alpha1.HealthProbeStatus{
+		Ready:         true,
+		LastReadyTime: metav1.NewMicroTime(time.Now()),
+	}
+	f.assertNextTargetToBuild("k8s1")
+}
+
 func TestLocalDependsOnNonWorkloadK8s(t *testing.T) {
 	f := newTestFixture(t)
 
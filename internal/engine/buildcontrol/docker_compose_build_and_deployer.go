@@ -176,6 +176,11 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		imageMapSet[nn] = im.DeepCopy()
 	}
 
+	err = restoreReusedImageMaps(ctx, bd.ctrlClient, iTargets, reused, imageMapSet)
+	if err != nil {
+		return store.BuildResultSet{}, err
+	}
+
 	err = q.RunBuilds(func(target model.TargetSpec, depResults []store.ImageBuildResult) (store.ImageBuildResult, error) {
 		iTarget, ok := target.(model.ImageTarget)
 		if !ok {
@@ -121,6 +121,11 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		imageMapSet[nn] = im.DeepCopy()
 	}
 
+	err = restoreReusedImageMaps(ctx, ibd.ctrlClient, iTargets, reused, imageMapSet)
+	if err != nil {
+		return store.BuildResultSet{}, err
+	}
+
 	err = q.RunBuilds(func(target model.TargetSpec, depResults []store.ImageBuildResult) (store.ImageBuildResult, error) {
 		iTarget, ok := target.(model.ImageTarget)
 		if !ok {
@@ -203,3 +208,34 @@ func (ibd *ImageBuildAndDeployer) delete(ctx context.Context, k8sTarget model.K8
 	kTargetNN := types.NamespacedName{Name: k8sTarget.ID().Name.String()}
 	return ibd.r.ForceDelete(ctx, kTargetNN, k8sTarget.KubernetesApplySpec, cluster, "force update")
 }
+
+// An image that we reuse may come from a checkpoint of an earlier session,
+// so its ImageMap doesn't know about it yet.
+func restoreReusedImageMaps(
+	ctx context.Context,
+	client ctrlclient.Client,
+	iTargets []model.ImageTarget,
+	reused store.ImageBuildResultSet,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) error {
+	for _, iTarget := range iTargets {
+		result, ok := reused[iTarget.ID()]
+		if !ok {
+			continue
+		}
+
+		nn := types.NamespacedName{Name: iTarget.ImageMapName()}
+		im, ok := imageMaps[nn]
+		if !ok || im.Status.Image != "" {
+			continue
+		}
+
+		update := im.DeepCopy()
+		update.Status = *result.ImageMapStatus.DeepCopy()
+		err := client.Status().Update(ctx, update)
+		if err != nil {
+			return err
+		}
+		imageMaps[nn] = update
+	}
+	return nil
+}
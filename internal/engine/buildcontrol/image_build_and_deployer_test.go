@@ -989,6 +989,26 @@ func TestTwoManifestsWithCommonImagePrebuilt(t *testing.T) {
 		"STEP 1/4 — Loading cached images\n     - gcr.io/common:tilt-prebuilt")
 }
 
+func TestCachedImageFromEarlierSessionUpdatesImageMap(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+
+	m1, _ := NewManifestsWithCommonAncestor(f)
+	iTarget1 := m1.ImageTargets[0]
+	prebuilt1 := store.NewImageBuildResultSingleRef(iTarget1.ID(),
+		container.MustParseNamedTagged("gcr.io/common:tilt-prebuilt"))
+
+	stateSet := store.BuildStateSet{}
+	stateSet[iTarget1.ID()] = store.NewBuildState(prebuilt1, nil, nil)
+
+	_, err := f.BuildAndDeploy(BuildTargets(m1), stateSet)
+	require.NoError(t, err)
+
+	var im v1alpha1.ImageMap
+	err = f.ctrlClient.Get(f.ctx, ktypes.NamespacedName{Name: iTarget1.ImageMapName()}, &im)
+	require.NoError(t, err)
+	assert.Equal(t, "gcr.io/common:tilt-prebuilt", im.Status.Image)
+}
+
 func TestTwoManifestsWithTwoCommonAncestors(t *testing.T) {
 	f := newIBDFixture(t, clusterid.ProductGKE)
 
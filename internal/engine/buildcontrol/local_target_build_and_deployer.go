@@ -70,6 +70,9 @@ func (bd *LocalTargetBuildAndDeployer) BuildAndDeploy(ctx context.Context, st st
 			model.ArgListToString(cmd.Spec.Args), err)
 	} else if status.Terminated == nil {
 		return store.BuildResultSet{}, DontFallBackErrorf("Command didn't terminate")
+	} else if status.Terminated.Reason == v1alpha1.CmdReasonTimedOut {
+		return store.BuildResultSet{}, DontFallBackErrorf("Command %q timed out after %s",
+			model.ArgListToString(cmd.Spec.Args), cmd.Spec.Timeout.Duration)
 	} else if status.Terminated.ExitCode != 0 {
 		return store.BuildResultSet{}, DontFallBackErrorf("Command %q failed: %v",
 			model.ArgListToString(cmd.Spec.Args), status.Terminated.Reason)
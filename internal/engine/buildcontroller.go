@@ -16,6 +16,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
@@ -25,12 +26,16 @@ const BuildControlSource = "buildcontrol"
 
 type BuildController struct {
 	b                  buildcontrol.BuildAndDeployer
+	tracer             tracer.PipelineTracer
 	buildsStartedCount int // used to synchronize with state
 	disabledForTesting bool
 
 	// CancelFuncs for in-progress builds
 	mu           sync.Mutex
 	stopBuildFns map[model.ManifestName]context.CancelFunc
+
+	// Updates that we're still tracing
+	traces map[model.ManifestName]*pipelineTrace
 }
 
 type buildEntry struct {
@@ -40,16 +45,20 @@ type buildEntry struct {
 	filesChanged  []string
 	buildReason   model.BuildReason
 	spanID        logstore.SpanID
+
+	firstFileChangeTime time.Time
 }
 
 func (e buildEntry) Name() model.ManifestName       { return e.name }
 func (e buildEntry) FilesChanged() []string         { return e.filesChanged }
 func (e buildEntry) BuildReason() model.BuildReason { return e.buildReason }
 
-func NewBuildController(b buildcontrol.BuildAndDeployer) *BuildController {
+func NewBuildController(b buildcontrol.BuildAndDeployer, tracer tracer.PipelineTracer) *BuildController {
 	return &BuildController{
 		b:            b,
+		tracer:       tracer,
 		stopBuildFns: make(map[model.ManifestName]context.CancelFunc),
+		traces:       make(map[model.ManifestName]*pipelineTrace),
 	}
 }
 
@@ -95,6 +104,8 @@ func (c *BuildController) needsBuild(ctx context.Context, st store.RStore) (buil
 		buildStateSet: buildStateSet,
 		filesChanged:  append(ms.ConfigFilesThatCausedChange, buildStateSet.FilesChanged()...),
 		spanID:        SpanIDForBuildLog(c.buildsStartedCount),
+
+		firstFileChangeTime: firstFileChangeTime(ms, targets),
 	}, true
 }
 
@@ -108,6 +119,7 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 	}
 
 	c.cleanUpCanceledBuilds(st)
+	c.observePipelineReadiness(st)
 
 	if c.disabledForTesting {
 		return nil
@@ -117,9 +129,10 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 		return nil
 	}
 
+	startTime := time.Now()
 	st.Dispatch(buildcontrols.BuildStartedAction{
 		ManifestName:       entry.name,
-		StartTime:          time.Now(),
+		StartTime:          startTime,
 		FilesChanged:       entry.filesChanged,
 		Reason:             entry.buildReason,
 		SpanID:             entry.spanID,
@@ -129,6 +142,7 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 
 	go func() {
 		ctx = c.buildContext(ctx, entry, st)
+		ctx = c.startPipelineTrace(ctx, entry, startTime)
 		defer c.cleanupBuildContext(entry.name)
 
 		buildcontrols.LogBuildEntry(ctx, buildcontrols.BuildEntry{
@@ -141,6 +155,7 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 		if ctx.Err() == context.Canceled {
 			err = errors.New("build canceled")
 		}
+		c.finishPipelineBuild(entry.name, startTime, err)
 		st.Dispatch(buildcontrols.NewBuildCompleteAction(entry.name, BuildControlSource, entry.spanID, result, err))
 	}()
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils/configmap"
 	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
 	"github.com/tilt-dev/tilt/internal/testutils/podbuilder"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/internal/watch"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -54,6 +56,65 @@ func TestBuildControllerLocalResource(t *testing.T) {
 	f.assertAllBuildsConsumed()
 }
 
+func TestBuildControllerTracesUpdates(t *testing.T) {
+	f := newTestFixture(t)
+	pt, spans := tracer.NewRecordingPipelineTracer()
+	f.bc.tracer = pt
+
+	dep := f.JoinPath("stuff.json")
+	manifest := manifestbuilder.New(f, "local").
+		WithLocalResource("echo beep boop", []string{dep}).
+		Build()
+	f.Start([]model.Manifest{manifest})
+	f.nextCallComplete()
+
+	f.fsWatcher.Events <- watch.NewFileEvent(dep)
+	f.nextCallComplete()
+
+	require.Eventually(t, func() bool {
+		return len(spans.SpansNamed("update")) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	update := spans.SpansNamed("update")[1]
+	ready := spans.SpansNamed("ready")
+	require.Len(t, ready, 2)
+	assert.Equal(t, update.SpanContext.SpanID(), ready[1].Parent.SpanID())
+
+	// The update that a file change kicked off starts with the file change.
+	fileEvent := spans.SpansNamed("file-event")
+	require.Len(t, fileEvent, 1)
+	assert.Equal(t, update.SpanContext.SpanID(), fileEvent[0].Parent.SpanID())
+	assert.Equal(t, update.StartTime, fileEvent[0].StartTime)
+
+	err := f.Stop()
+	assert.NoError(t, err)
+	f.assertAllBuildsConsumed()
+}
+
+func TestBuildControllerTracesFailedUpdates(t *testing.T) {
+	f := newTestFixture(t)
+	pt, spans := tracer.NewRecordingPipelineTracer()
+	f.bc.tracer = pt
+
+	manifest := f.newManifest("fe")
+	f.SetNextBuildError(errors.New("oh no"))
+	f.Start([]model.Manifest{manifest})
+	f.nextCallComplete()
+
+	require.Eventually(t, func() bool {
+		return len(spans.SpansNamed("update")) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	update := spans.SpansNamed("update")[0]
+	assert.Equal(t, codes.Error, update.StatusCode)
+	assert.Equal(t, "oh no", update.StatusMessage)
+	assert.Empty(t, spans.SpansNamed("ready"))
+
+	err := f.Stop()
+	assert.NoError(t, err)
+	f.assertAllBuildsConsumed()
+}
+
 func TestBuildControllerManualTriggerBuildReasonInit(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
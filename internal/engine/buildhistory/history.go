@@ -0,0 +1,73 @@
+// Package buildhistory records how long each resource takes to build,
+// and persists it across Tilt sessions.
+//
+// The history is published to the API server as BuildHistory objects, so
+// that the web UI can chart dev-loop latency and teams can spot regressions.
+package buildhistory
+
+import (
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The most builds we keep for each resource.
+const MaxRecords = 500
+
+// Bump when the file format changes, so that stale files are never read.
+const version = 1
+
+type historyFile struct {
+	Version int `json:"version"`
+
+	// The main Tiltfile of the project.
+	Tiltfile string `json:"tiltfile"`
+
+	Resources map[string][]v1alpha1.BuildHistoryRecord `json:"resources"`
+}
+
+// Each project has its own history file, keyed by the path of its main Tiltfile.
+func historyPath(base xdg.Base, tiltfilePath string) (string, error) {
+	return xdg.ProjectStateFile(base, "build_history", tiltfilePath)
+}
+
+// Reads the history of a project.
+//
+// A missing or outdated file is an empty history.
+func ReadHistory(base xdg.Base, tiltfilePath string) (map[string][]v1alpha1.BuildHistoryRecord, error) {
+	p, err := historyPath(base, tiltfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var f historyFile
+	ok, err := xdg.ReadJSONFile(p, &f)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || f.Version != version || f.Tiltfile != tiltfilePath || f.Resources == nil {
+		return map[string][]v1alpha1.BuildHistoryRecord{}, nil
+	}
+	return f.Resources, nil
+}
+
+func writeHistory(base xdg.Base, tiltfilePath string, resources map[string][]v1alpha1.BuildHistoryRecord) error {
+	p, err := historyPath(base, tiltfilePath)
+	if err != nil {
+		return err
+	}
+
+	return xdg.WriteJSONFile(p, historyFile{
+		Version:   version,
+		Tiltfile:  tiltfilePath,
+		Resources: resources,
+	})
+}
+
+// Appends records, dropping the oldest ones over the limit.
+func appendRecords(existing []v1alpha1.BuildHistoryRecord, records ...v1alpha1.BuildHistoryRecord) []v1alpha1.BuildHistoryRecord {
+	result := append(existing, records...)
+	if len(result) > MaxRecords {
+		result = append([]v1alpha1.BuildHistoryRecord{}, result[len(result)-MaxRecords:]...)
+	}
+	return result
+}
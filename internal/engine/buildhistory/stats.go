@@ -0,0 +1,135 @@
+package buildhistory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How a project's dev loop performed over one week.
+type WeekStats struct {
+	// Midnight on the Monday that starts the week, in local time.
+	Start time.Time
+
+	// The number of Tilt sessions that finished a build this week.
+	Sessions int
+
+	Builds   int
+	Failures int
+
+	// Zero if there were no builds.
+	MedianBuild time.Duration
+	P90Build    time.Duration
+
+	// Builds that did a live update, and how long they took.
+	LiveUpdates      int
+	MedianLiveUpdate time.Duration
+}
+
+func (s WeekStats) FailureRate() float64 {
+	if s.Builds == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Builds)
+}
+
+// Summarizes the builds of the last n weeks, oldest first, including the
+// current week. Weeks without builds are included, so that gaps show up.
+//
+// If resource is non-empty, only counts the builds of that resource.
+func WeeklyStats(records map[string][]v1alpha1.BuildHistoryRecord, resource string, n int, now time.Time) []WeekStats {
+	if n <= 0 {
+		return nil
+	}
+
+	first := startOfWeek(now).AddDate(0, 0, -7*(n-1))
+	result := make([]WeekStats, n)
+	builds := make([][]time.Duration, n)
+	liveUpdates := make([][]time.Duration, n)
+	sessions := make([]map[time.Time]bool, n)
+	for i := range result {
+		result[i].Start = first.AddDate(0, 0, 7*i)
+		sessions[i] = make(map[time.Time]bool)
+	}
+
+	for name, rs := range records {
+		if resource != "" && name != resource {
+			continue
+		}
+		for _, r := range rs {
+			i := weekIndex(first, r.StartTime.Time)
+			if i < 0 || i >= n {
+				continue
+			}
+
+			d := r.FinishTime.Sub(r.StartTime.Time)
+			result[i].Builds++
+			builds[i] = append(builds[i], d)
+			if r.Error != "" {
+				result[i].Failures++
+			}
+			if isLiveUpdate(r) {
+				result[i].LiveUpdates++
+				liveUpdates[i] = append(liveUpdates[i], d)
+			}
+			sessions[i][r.SessionStartTime.Time] = true
+		}
+	}
+
+	for i := range result {
+		result[i].Sessions = len(sessions[i])
+		result[i].MedianBuild = percentile(builds[i], 50)
+		result[i].P90Build = percentile(builds[i], 90)
+		result[i].MedianLiveUpdate = percentile(liveUpdates[i], 50)
+	}
+	return result
+}
+
+func startOfWeek(t time.Time) time.Time {
+	t = t.Local()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.Local)
+}
+
+// The index of the week that t falls in, counting from the week
+// that starts at first. Negative if t is before first.
+func weekIndex(first time.Time, t time.Time) int {
+	start := startOfWeek(t)
+	if start.Before(first) {
+		return -1
+	}
+
+	// Step through the calendar instead of dividing durations, so that
+	// daylight saving time doesn't throw the count off.
+	i := 0
+	for d := first; d.Before(start); d = d.AddDate(0, 0, 7) {
+		i++
+	}
+	return i
+}
+
+func isLiveUpdate(r v1alpha1.BuildHistoryRecord) bool {
+	for _, bt := range r.BuildTypes {
+		if bt == string(model.BuildTypeLiveUpdate) {
+			return true
+		}
+	}
+	return false
+}
+
+// The nearest-rank percentile p of ds. Zero if ds is empty.
+func percentile(ds []time.Duration, p int) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,77 @@
+package buildhistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestWeeklyStats(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	session1 := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	session2 := time.Date(2024, 5, 13, 9, 0, 0, 0, time.Local)
+	session3 := time.Date(2024, 5, 14, 9, 0, 0, 0, time.Local)
+
+	records := map[string][]v1alpha1.BuildHistoryRecord{
+		"fe": {
+			// Too old to count.
+			record(time.Date(2024, 4, 1, 10, 0, 0, 0, time.Local), time.Second, session1, ""),
+
+			// Last week.
+			record(time.Date(2024, 5, 6, 10, 0, 0, 0, time.Local), 4*time.Second, session1, ""),
+			record(time.Date(2024, 5, 12, 23, 0, 0, 0, time.Local), 2*time.Second, session1, "compile error"),
+
+			// This week.
+			record(time.Date(2024, 5, 13, 10, 0, 0, 0, time.Local), 10*time.Second, session2, ""),
+			record(time.Date(2024, 5, 14, 10, 0, 0, 0, time.Local), time.Second, session3, "", "live-update"),
+		},
+		"be": {
+			record(time.Date(2024, 5, 14, 11, 0, 0, 0, time.Local), 3*time.Second, session3, "", "image", "live-update"),
+		},
+	}
+
+	stats := WeeklyStats(records, "", 3, now)
+	require.Len(t, stats, 3)
+
+	assert.Equal(t, time.Date(2024, 4, 29, 0, 0, 0, 0, time.Local), stats[0].Start)
+	assert.Equal(t, WeekStats{Start: stats[0].Start}, stats[0])
+
+	assert.Equal(t, time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local), stats[1].Start)
+	assert.Equal(t, 1, stats[1].Sessions)
+	assert.Equal(t, 2, stats[1].Builds)
+	assert.Equal(t, 1, stats[1].Failures)
+	assert.Equal(t, 0.5, stats[1].FailureRate())
+	assert.Equal(t, 2*time.Second, stats[1].MedianBuild)
+	assert.Equal(t, 4*time.Second, stats[1].P90Build)
+	assert.Equal(t, 0, stats[1].LiveUpdates)
+
+	assert.Equal(t, time.Date(2024, 5, 13, 0, 0, 0, 0, time.Local), stats[2].Start)
+	assert.Equal(t, 2, stats[2].Sessions)
+	assert.Equal(t, 3, stats[2].Builds)
+	assert.Equal(t, 0, stats[2].Failures)
+	assert.Equal(t, 3*time.Second, stats[2].MedianBuild)
+	assert.Equal(t, 10*time.Second, stats[2].P90Build)
+	assert.Equal(t, 2, stats[2].LiveUpdates)
+	assert.Equal(t, time.Second, stats[2].MedianLiveUpdate)
+
+	stats = WeeklyStats(records, "be", 1, now)
+	require.Len(t, stats, 1)
+	assert.Equal(t, 1, stats[0].Builds)
+	assert.Equal(t, 3*time.Second, stats[0].MedianBuild)
+}
+
+func record(start time.Time, d time.Duration, session time.Time, err string, buildTypes ...string) v1alpha1.BuildHistoryRecord {
+	return v1alpha1.BuildHistoryRecord{
+		StartTime:        metav1.NewMicroTime(start),
+		FinishTime:       metav1.NewMicroTime(start.Add(d)),
+		Error:            err,
+		BuildTypes:       buildTypes,
+		SessionStartTime: metav1.NewMicroTime(session),
+	}
+}
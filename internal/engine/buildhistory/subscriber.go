@@ -0,0 +1,228 @@
+package buildhistory
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Records completed builds, persists them, and publishes them
+// as BuildHistory objects.
+//
+// BuildHistory objects are owned by this subscriber. There's one for
+// each resource in the current Tiltfile that has ever finished a build.
+type Subscriber struct {
+	client ctrlclient.Client
+	base   xdg.Base
+
+	// The main Tiltfile whose history is loaded.
+	tiltfilePath string
+	records      map[string][]v1alpha1.BuildHistoryRecord
+
+	// The start time of the last build recorded for each resource
+	// in this session.
+	lastRecorded map[string]time.Time
+
+	// Whether the records have changed since they were last written to disk.
+	needsWrite bool
+
+	// Resources whose BuildHistory objects are out of date.
+	dirty map[string]bool
+
+	// Resources that have a BuildHistory object.
+	published map[string]bool
+}
+
+var _ store.Subscriber = &Subscriber{}
+
+func NewSubscriber(client ctrlclient.Client, base xdg.Base) *Subscriber {
+	return &Subscriber{
+		client:       client,
+		base:         base,
+		lastRecorded: make(map[string]time.Time),
+		dirty:        make(map[string]bool),
+		published:    make(map[string]bool),
+	}
+}
+
+type resourceBuilds struct {
+	name string
+
+	// Completed builds, most recent first.
+	builds []model.BuildRecord
+}
+
+func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	state := st.RLockState()
+	tiltfilePath := state.MainTiltfilePath()
+	sessionStart := state.TiltStartTime
+	var resources []resourceBuilds
+	for _, ms := range state.GetTiltfileStates() {
+		resources = append(resources, resourceBuilds{name: ms.Name.String(), builds: ms.BuildHistory})
+	}
+	for _, mt := range state.Targets() {
+		resources = append(resources, resourceBuilds{name: mt.Manifest.Name.String(), builds: mt.State.BuildHistory})
+	}
+	st.RUnlockState()
+
+	if tiltfilePath == "" {
+		return nil
+	}
+
+	if tiltfilePath != s.tiltfilePath {
+		records, err := ReadHistory(s.base, tiltfilePath)
+		if err != nil {
+			return err
+		}
+		s.tiltfilePath = tiltfilePath
+		s.records = records
+		s.needsWrite = false
+		for name := range s.published {
+			s.dirty[name] = true
+		}
+	}
+
+	current := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		current[r.name] = true
+		if s.record(r, sessionStart) {
+			s.needsWrite = true
+			s.dirty[r.name] = true
+		}
+		if !s.published[r.name] && len(s.records[r.name]) > 0 {
+			s.dirty[r.name] = true
+		}
+	}
+
+	if s.needsWrite {
+		err := writeHistory(s.base, s.tiltfilePath, s.records)
+		if err != nil {
+			return err
+		}
+		s.needsWrite = false
+	}
+
+	return s.publish(ctx, current)
+}
+
+// Records any builds that finished since the last call.
+//
+// Returns true if there were new builds.
+func (s *Subscriber) record(r resourceBuilds, sessionStart time.Time) bool {
+	last := s.lastRecorded[r.name]
+	var newRecords []v1alpha1.BuildHistoryRecord
+	for i := len(r.builds) - 1; i >= 0; i-- {
+		b := r.builds[i]
+		if b.FinishTime.IsZero() || !b.StartTime.After(last) {
+			continue
+		}
+		newRecords = append(newRecords, toRecord(b, sessionStart))
+		s.lastRecorded[r.name] = b.StartTime
+	}
+	if len(newRecords) == 0 {
+		return false
+	}
+	s.records[r.name] = appendRecords(s.records[r.name], newRecords...)
+	return true
+}
+
+func toRecord(b model.BuildRecord, sessionStart time.Time) v1alpha1.BuildHistoryRecord {
+	r := v1alpha1.BuildHistoryRecord{
+		StartTime:        metav1.NewMicroTime(b.StartTime),
+		FinishTime:       metav1.NewMicroTime(b.FinishTime),
+		Edits:            append([]string{}, b.Edits...),
+		Reason:           b.Reason.String(),
+		SessionStartTime: metav1.NewMicroTime(sessionStart),
+	}
+	if b.Error != nil {
+		r.Error = b.Error.Error()
+	}
+	for _, bt := range b.BuildTypes {
+		r.BuildTypes = append(r.BuildTypes, string(bt))
+	}
+	return r
+}
+
+// Brings the BuildHistory objects up to date with the records.
+//
+// Resources that were removed from the Tiltfile lose their object,
+// but keep their records on disk, in case they come back.
+func (s *Subscriber) publish(ctx context.Context, current map[string]bool) error {
+	errs := []error{}
+	for name := range s.published {
+		if current[name] {
+			continue
+		}
+		err := s.client.Delete(ctx, &v1alpha1.BuildHistory{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		if err != nil && !apierrors.IsNotFound(err) {
+			if isCacheNotStarted(err) {
+				return nil
+			}
+			errs = append(errs, err)
+			continue
+		}
+		delete(s.published, name)
+		delete(s.dirty, name)
+	}
+
+	for name := range s.dirty {
+		if !current[name] {
+			delete(s.dirty, name)
+			continue
+		}
+
+		err := s.upsert(ctx, name, v1alpha1.BuildHistoryStatus{Builds: s.records[name]})
+		if err != nil {
+			if isCacheNotStarted(err) {
+				return nil
+			}
+			errs = append(errs, err)
+			continue
+		}
+		s.published[name] = true
+		delete(s.dirty, name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (s *Subscriber) upsert(ctx context.Context, name string, status v1alpha1.BuildHistoryStatus) error {
+	var obj v1alpha1.BuildHistory
+	err := s.client.Get(ctx, types.NamespacedName{Name: name}, &obj)
+	if apierrors.IsNotFound(err) {
+		obj = v1alpha1.BuildHistory{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		err = s.client.Create(ctx, &obj)
+	}
+	if err != nil {
+		return err
+	}
+
+	if apicmp.DeepEqual(obj.Status, status) {
+		return nil
+	}
+	update := obj.DeepCopy()
+	update.Status = status
+	return s.client.Status().Update(ctx, update)
+}
+
+// If the cache hasn't started yet, that's OK.
+// We'll get it on the next OnChange()
+func isCacheNotStarted(err error) bool {
+	_, ok := err.(*cache.ErrCacheNotStarted)
+	return ok
+}
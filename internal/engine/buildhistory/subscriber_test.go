@@ -0,0 +1,212 @@
+package buildhistory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestRecordsCompletedBuilds(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("fe")
+
+	start := time.Unix(1000, 0)
+	f.startBuild("fe", start)
+	f.onChange()
+
+	// In-progress builds aren't recorded.
+	assert.Nil(t, f.history("fe"))
+
+	f.finishBuild("fe", start.Add(2*time.Second), fmt.Errorf("compile error"), "main.go")
+	f.onChange()
+
+	builds := f.history("fe").Status.Builds
+	require.Len(t, builds, 1)
+	assert.Equal(t, 2*time.Second, builds[0].FinishTime.Sub(builds[0].StartTime.Time))
+	assert.Equal(t, "compile error", builds[0].Error)
+	assert.Equal(t, []string{"main.go"}, builds[0].Edits)
+	assert.Equal(t, "Changed Files", builds[0].Reason)
+	assert.Equal(t, []string{"local"}, builds[0].BuildTypes)
+	assert.True(t, f.sessionStart.Equal(builds[0].SessionStartTime.Time))
+
+	// Make sure OnChange is idempotent.
+	f.onChange()
+	assert.Len(t, f.history("fe").Status.Builds, 1)
+
+	f.startBuild("fe", start.Add(time.Minute))
+	f.finishBuild("fe", start.Add(time.Minute+time.Second), nil)
+	f.onChange()
+
+	builds = f.history("fe").Status.Builds
+	require.Len(t, builds, 2)
+	assert.Equal(t, "", builds[1].Error)
+}
+
+func TestHistoryPersistsAcrossSessions(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("fe")
+	f.startBuild("fe", time.Unix(1000, 0))
+	f.finishBuild("fe", time.Unix(1003, 0), nil)
+	f.onChange()
+
+	// A new session of the same project, with a fresh store and API server.
+	f2 := newFixtureWithBase(t, f.base)
+	f2.addManifest("fe")
+	f2.onChange()
+
+	builds := f2.history("fe").Status.Builds
+	require.Len(t, builds, 1)
+	assert.Equal(t, time.Unix(1000, 0).Unix(), builds[0].StartTime.Unix())
+
+	f2.startBuild("fe", time.Unix(2000, 0))
+	f2.finishBuild("fe", time.Unix(2001, 0), nil)
+	f2.onChange()
+
+	builds = f2.history("fe").Status.Builds
+	require.Len(t, builds, 2)
+	assert.True(t, builds[0].SessionStartTime.Before(&builds[1].SessionStartTime))
+}
+
+func TestHistoryIsPerProject(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("fe")
+	f.startBuild("fe", time.Unix(1000, 0))
+	f.finishBuild("fe", time.Unix(1003, 0), nil)
+	f.onChange()
+
+	f2 := newFixtureWithBase(t, f.base)
+	f2.store.WithState(func(es *store.EngineState) {
+		es.Tiltfiles[model.MainTiltfileManifestName.String()].Spec.Path = "/other/Tiltfile"
+	})
+	f2.addManifest("fe")
+	f2.onChange()
+
+	assert.Nil(t, f2.history("fe"))
+}
+
+func TestRemovedResourceLosesObject(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("fe")
+	f.startBuild("fe", time.Unix(1000, 0))
+	f.finishBuild("fe", time.Unix(1003, 0), nil)
+	f.onChange()
+	require.NotNil(t, f.history("fe"))
+
+	f.store.WithState(func(es *store.EngineState) {
+		es.RemoveManifestTarget("fe")
+	})
+	f.onChange()
+	assert.Nil(t, f.history("fe"))
+
+	// The history comes back with the resource.
+	f.addManifest("fe")
+	f.onChange()
+	require.NotNil(t, f.history("fe"))
+	assert.Len(t, f.history("fe").Status.Builds, 1)
+}
+
+func TestHistoryLimit(t *testing.T) {
+	var records []v1alpha1.BuildHistoryRecord
+	for i := 0; i < MaxRecords+10; i++ {
+		records = appendRecords(records, v1alpha1.BuildHistoryRecord{Reason: fmt.Sprintf("%d", i)})
+	}
+	require.Len(t, records, MaxRecords)
+	assert.Equal(t, "10", records[0].Reason)
+	assert.Equal(t, fmt.Sprintf("%d", MaxRecords+9), records[MaxRecords-1].Reason)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx          context.Context
+	store        *store.TestingStore
+	tc           ctrlclient.Client
+	base         xdg.Base
+	sub          *Subscriber
+	sessionStart time.Time
+}
+
+func newFixture(t *testing.T) *fixture {
+	return newFixtureWithBase(t, xdg.FakeBase{Dir: t.TempDir()})
+}
+
+func newFixtureWithBase(t *testing.T, base xdg.Base) *fixture {
+	tc := fake.NewFakeTiltClient()
+	st := store.NewTestingStore()
+	sessionStart := time.Now().Truncate(time.Microsecond)
+	st.WithState(func(es *store.EngineState) {
+		es.TiltStartTime = sessionStart
+		es.Tiltfiles[model.MainTiltfileManifestName.String()] = &v1alpha1.Tiltfile{
+			ObjectMeta: metav1.ObjectMeta{Name: model.MainTiltfileManifestName.String()},
+			Spec:       v1alpha1.TiltfileSpec{Path: "/project/Tiltfile"},
+		}
+	})
+
+	return &fixture{
+		TempDirFixture: tempdir.NewTempDirFixture(t),
+		ctx:            context.Background(),
+		store:          st,
+		tc:             tc,
+		base:           base,
+		sub:            NewSubscriber(tc, base),
+		sessionStart:   sessionStart,
+	}
+}
+
+func (f *fixture) onChange() {
+	err := f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	require.NoError(f.T(), err)
+}
+
+func (f *fixture) addManifest(name model.ManifestName) {
+	f.store.WithState(func(es *store.EngineState) {
+		es.UpsertManifestTarget(store.NewManifestTarget(model.Manifest{Name: name}))
+	})
+}
+
+func (f *fixture) startBuild(name model.ManifestName, start time.Time) {
+	f.store.WithState(func(es *store.EngineState) {
+		ms := es.ManifestTargets[name].State
+		ms.CurrentBuilds["buildcontrol"] = model.BuildRecord{
+			StartTime:  start,
+			Reason:     model.BuildReasonFlagChangedFiles,
+			BuildTypes: []model.BuildType{model.BuildTypeLocal},
+		}
+	})
+}
+
+func (f *fixture) finishBuild(name model.ManifestName, finish time.Time, err error, edits ...string) {
+	f.store.WithState(func(es *store.EngineState) {
+		ms := es.ManifestTargets[name].State
+		b := ms.CurrentBuilds["buildcontrol"]
+		b.FinishTime = finish
+		b.Error = err
+		b.Edits = edits
+		ms.AddCompletedBuild(b)
+		delete(ms.CurrentBuilds, "buildcontrol")
+	})
+}
+
+func (f *fixture) history(name string) *v1alpha1.BuildHistory {
+	var bh v1alpha1.BuildHistory
+	err := f.tc.Get(f.ctx, types.NamespacedName{Name: name}, &bh)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(f.T(), err)
+	return &bh
+}
@@ -0,0 +1,194 @@
+// Package checkpoint periodically saves the images that each image target
+// built, so that after a crash, or a sleep that Tilt didn't survive, the
+// next `tilt up` can reuse them instead of rebuilding everything.
+//
+// Build history is persisted by the buildhistory package.
+package checkpoint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/tilt-dev/tilt/internal/ignore"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Bump when the file format changes, so that stale files are never read.
+const version = 1
+
+type checkpointFile struct {
+	Version int `json:"version"`
+
+	// The main Tiltfile of the project.
+	Tiltfile string `json:"tiltfile"`
+
+	// Keyed by image target ID.
+	Images map[string]imageCheckpoint `json:"images"`
+}
+
+type imageCheckpoint struct {
+	// A hash of the image target, so that we never reuse an image
+	// that the Tiltfile now builds differently.
+	SpecHash string `json:"specHash"`
+
+	Status v1alpha1.ImageMapStatus `json:"status"`
+}
+
+// Each project has its own checkpoint, keyed by the path of its main Tiltfile.
+func checkpointPath(base xdg.Base, tiltfilePath string) (string, error) {
+	return xdg.ProjectStateFile(base, "checkpoint", tiltfilePath)
+}
+
+// A missing or outdated file is an empty checkpoint.
+func readCheckpoint(base xdg.Base, tiltfilePath string) (map[string]imageCheckpoint, error) {
+	p, err := checkpointPath(base, tiltfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var f checkpointFile
+	ok, err := xdg.ReadJSONFile(p, &f)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || f.Version != version || f.Tiltfile != tiltfilePath || f.Images == nil {
+		return map[string]imageCheckpoint{}, nil
+	}
+	return f.Images, nil
+}
+
+func writeCheckpoint(base xdg.Base, tiltfilePath string, images map[string]imageCheckpoint) error {
+	p, err := checkpointPath(base, tiltfilePath)
+	if err != nil {
+		return err
+	}
+
+	return xdg.WriteJSONFile(p, checkpointFile{
+		Version:  version,
+		Tiltfile: tiltfilePath,
+		Images:   images,
+	})
+}
+
+func specHash(iTarget model.ImageTarget) (string, error) {
+	contents, err := json.Marshal(struct {
+		ImageMap     v1alpha1.ImageMapSpec
+		BuildType    string
+		BuildDetails model.BuildDetails
+		Ignores      []v1alpha1.IgnoreDef
+	}{
+		ImageMap:     iTarget.ImageMapSpec,
+		BuildType:    fmt.Sprintf("%T", iTarget.BuildDetails),
+		BuildDetails: iTarget.BuildDetails,
+		Ignores:      iTarget.GetFileWatchIgnores(),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Returns the images in the project's checkpoint that the given manifests
+// can reuse: the ones built from the same image target, whose files haven't
+// changed since the build started.
+//
+// Tilt wasn't watching files while it was down, so we compare modification
+// times. A directory's modification time changes when a file in it is
+// deleted, so that's caught too.
+func Restore(ctx context.Context, base xdg.Base, tiltfilePath string, manifests []model.Manifest) (map[model.TargetID]store.ImageBuildResult, error) {
+	images, err := readCheckpoint(base, tiltfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[model.TargetID]store.ImageBuildResult)
+	if len(images) == 0 {
+		return result, nil
+	}
+
+	for _, m := range manifests {
+		for _, iTarget := range m.ImageTargets {
+			id := iTarget.ID()
+			image, ok := images[id.String()]
+			if !ok || image.Status.BuildStartTime == nil {
+				continue
+			}
+
+			hash, err := specHash(iTarget)
+			if err != nil || hash != image.SpecHash {
+				continue
+			}
+
+			changed, err := changedSince(iTarget, image.Status.BuildStartTime.Time.UnixNano())
+			if err != nil {
+				logger.Get(ctx).Debugf("Not reusing image %s: %v", image.Status.Image, err)
+				continue
+			}
+			if changed {
+				continue
+			}
+			result[id] = store.NewImageBuildResultFromStatus(id, image.Status)
+		}
+	}
+	return result, nil
+}
+
+var errChanged = errors.New("changed")
+
+// Whether any file that the image target watches was modified
+// after the given time.
+func changedSince(iTarget model.ImageTarget, unixNano int64) (bool, error) {
+	filter := ignore.CreateFileChangeFilter(iTarget.GetFileWatchIgnores())
+	for _, root := range iTarget.LocalPaths() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				skip, err := filter.MatchesEntireDir(path)
+				if err != nil {
+					return err
+				}
+				if skip {
+					return filepath.SkipDir
+				}
+			} else {
+				skip, err := filter.Matches(path)
+				if err != nil {
+					return err
+				}
+				if skip {
+					return nil
+				}
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.ModTime().UnixNano() > unixNano {
+				return errChanged
+			}
+			return nil
+		})
+		if errors.Is(err, errChanged) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,167 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestCheckpointSurvivesRestart(t *testing.T) {
+	f := newFixture(t)
+	iTarget := f.imageTarget("fe-image")
+	f.addImage("fe", iTarget)
+
+	// Nothing is written until the checkpoint is flushed.
+	f.onChange()
+	assert.Empty(t, f.restore())
+
+	f.flush()
+	restored := f.restore()
+	require.Contains(t, restored, iTarget.ID())
+	assert.Equal(t, "fe-image:tilt-1", restored[iTarget.ID()].ImageMapStatus.Image)
+}
+
+func TestChangedFilesAreNotRestored(t *testing.T) {
+	f := newFixture(t)
+	iTarget := f.imageTarget("fe-image")
+	f.addImage("fe", iTarget)
+	f.onChange()
+	f.flush()
+
+	// Simulate an edit while Tilt was down.
+	f.WriteFile("fe/main.go", "package main // edited")
+	later := f.buildStart.Add(time.Minute)
+	require.NoError(t, os.Chtimes(f.JoinPath("fe/main.go"), later, later))
+	assert.Empty(t, f.restore())
+}
+
+func TestIgnoredFilesAreNotChanges(t *testing.T) {
+	f := newFixture(t)
+	iTarget := f.imageTarget("fe-image").WithIgnores([]v1alpha1.IgnoreDef{
+		{BasePath: f.JoinPath("fe/tmp")},
+	})
+	f.addImage("fe", iTarget)
+	f.onChange()
+	f.flush()
+
+	f.WriteFile("fe/tmp/scratch.txt", "scratch")
+	later := f.buildStart.Add(time.Minute)
+	require.NoError(t, os.Chtimes(f.JoinPath("fe/tmp/scratch.txt"), later, later))
+	assert.Contains(t, f.restore(), iTarget.ID())
+}
+
+func TestChangedSpecIsNotRestored(t *testing.T) {
+	f := newFixture(t)
+	iTarget := f.imageTarget("fe-image")
+	f.addImage("fe", iTarget)
+	f.onChange()
+	f.flush()
+
+	db := iTarget.BuildDetails.(model.DockerBuild)
+	db.Args = []string{"DEBUG=1"}
+	f.manifests = []model.Manifest{model.Manifest{Name: "fe"}.WithImageTarget(iTarget.WithBuildDetails(db))}
+	assert.Empty(t, f.restore())
+}
+
+func TestPendingChangesDropTheImage(t *testing.T) {
+	f := newFixture(t)
+	iTarget := f.imageTarget("fe-image")
+	f.addImage("fe", iTarget)
+	f.onChange()
+	f.flush()
+	require.NotEmpty(t, f.restore())
+
+	f.store.WithState(func(es *store.EngineState) {
+		es.ManifestTargets["fe"].State.AddPendingFileChange(iTarget.ID(), f.JoinPath("fe/main.go"), time.Now())
+	})
+	f.onChange()
+	f.flush()
+	assert.Empty(t, f.restore())
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx        context.Context
+	store      *store.TestingStore
+	base       xdg.Base
+	sub        *Subscriber
+	buildStart time.Time
+	manifests  []model.Manifest
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	base := xdg.FakeBase{Dir: f.JoinPath("xdg")}
+	st := store.NewTestingStore()
+	st.WithState(func(es *store.EngineState) {
+		es.Tiltfiles[model.MainTiltfileManifestName.String()] = &v1alpha1.Tiltfile{
+			ObjectMeta: metav1.ObjectMeta{Name: model.MainTiltfileManifestName.String()},
+			Spec:       v1alpha1.TiltfileSpec{Path: f.JoinPath("Tiltfile")},
+		}
+	})
+
+	// Files written before the build started.
+	f.WriteFile("fe/main.go", "package main")
+	f.WriteFile("fe/tmp/.keep", "")
+	earlier := time.Now().Add(-time.Hour)
+	for _, p := range []string{"fe/main.go", "fe/tmp/.keep", "fe/tmp", "fe"} {
+		require.NoError(t, os.Chtimes(f.JoinPath(p), earlier, earlier))
+	}
+
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            context.Background(),
+		store:          st,
+		base:           base,
+		sub:            NewSubscriber(base),
+		buildStart:     earlier.Add(time.Minute),
+	}
+}
+
+func (f *fixture) imageTarget(ref string) model.ImageTarget {
+	return model.MustNewImageTarget(container.MustParseSelector(ref)).
+		WithDockerImage(v1alpha1.DockerImageSpec{Context: f.JoinPath("fe")})
+}
+
+func (f *fixture) addImage(name model.ManifestName, iTarget model.ImageTarget) {
+	m := model.Manifest{Name: name}.WithImageTarget(iTarget)
+	f.manifests = append(f.manifests, m)
+
+	start := metav1.NewMicroTime(f.buildStart)
+	result := store.NewImageBuildResultFromStatus(iTarget.ID(), v1alpha1.ImageMapStatus{
+		Image:          "fe-image:tilt-1",
+		BuildStartTime: &start,
+	})
+	f.store.WithState(func(es *store.EngineState) {
+		mt := store.NewManifestTarget(m)
+		mt.State.MutableBuildStatus(iTarget.ID()).LastResult = result
+		es.UpsertManifestTarget(mt)
+	})
+}
+
+func (f *fixture) onChange() {
+	err := f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	require.NoError(f.T(), err)
+}
+
+func (f *fixture) flush() {
+	f.sub.flush(f.ctx)
+}
+
+func (f *fixture) restore() map[model.TargetID]store.ImageBuildResult {
+	result, err := Restore(f.ctx, f.base, f.JoinPath("Tiltfile"), f.manifests)
+	require.NoError(f.T(), err)
+	return result
+}
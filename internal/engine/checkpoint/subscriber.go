@@ -0,0 +1,148 @@
+package checkpoint
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// How often we write the checkpoint, if it changed.
+const checkpointInterval = 10 * time.Second
+
+// Keeps track of the images that each image target built, and writes
+// them to disk every few seconds, and when Tilt exits.
+//
+// An image leaves the checkpoint as soon as its files change. Otherwise it
+// stays, even after its resource is disabled or removed, so that a Tiltfile
+// that fails halfway doesn't lose it. Restore checks that it still matches
+// the image target.
+type Subscriber struct {
+	base xdg.Base
+
+	mu sync.Mutex
+
+	// The main Tiltfile whose checkpoint is loaded.
+	tiltfilePath string
+	images       map[string]imageCheckpoint
+
+	// Whether the images have changed since they were last written to disk.
+	dirty bool
+}
+
+var _ store.SubscriberLifecycle = &Subscriber{}
+
+func NewSubscriber(base xdg.Base) *Subscriber {
+	return &Subscriber{base: base}
+}
+
+func (s *Subscriber) SetUp(ctx context.Context, st store.RStore) error {
+	go func() {
+		ticker := time.NewTicker(checkpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.flush(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	state := st.RLockState()
+	tiltfilePath := state.MainTiltfilePath()
+	current := make(map[string]imageCheckpoint)
+	stale := make(map[string]bool)
+	for _, mt := range state.Targets() {
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			id := iTarget.ID().String()
+			status, ok := mt.State.BuildStatuses[iTarget.ID()]
+			if !ok {
+				continue
+			}
+			if len(status.PendingFileChanges) > 0 || len(status.PendingDependencyChanges) > 0 {
+				// The last image is out of date.
+				stale[id] = true
+				continue
+			}
+
+			// If there's no image yet, keep the one we have. Restore
+			// checks that it still matches.
+			result, ok := status.LastResult.(store.ImageBuildResult)
+			if !ok || result.ImageMapStatus.BuildStartTime == nil {
+				continue
+			}
+
+			hash, err := specHash(iTarget)
+			if err != nil {
+				continue
+			}
+			current[id] = imageCheckpoint{SpecHash: hash, Status: *result.ImageMapStatus.DeepCopy()}
+		}
+	}
+	st.RUnlockState()
+
+	if tiltfilePath == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tiltfilePath != s.tiltfilePath {
+		images, err := readCheckpoint(s.base, tiltfilePath)
+		if err != nil {
+			logger.Get(ctx).Debugf("Reading checkpoint: %v", err)
+			images = map[string]imageCheckpoint{}
+		}
+		s.tiltfilePath = tiltfilePath
+		s.images = images
+		s.dirty = false
+	}
+
+	for id := range stale {
+		if _, ok := s.images[id]; ok {
+			delete(s.images, id)
+			s.dirty = true
+		}
+	}
+	for id, image := range current {
+		if !apicmp.DeepEqual(s.images[id], image) {
+			s.images[id] = image
+			s.dirty = true
+		}
+	}
+	return nil
+}
+
+func (s *Subscriber) TearDown(ctx context.Context) {
+	s.flush(ctx)
+}
+
+func (s *Subscriber) flush(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return
+	}
+
+	err := writeCheckpoint(s.base, s.tiltfilePath, s.images)
+	if err != nil {
+		// Losing the checkpoint shouldn't stop Tilt.
+		logger.Get(ctx).Debugf("Writing checkpoint: %v", err)
+		return
+	}
+	s.dirty = false
+}
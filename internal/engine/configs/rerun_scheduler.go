@@ -0,0 +1,108 @@
+package configs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Adds resources to the trigger queue when the rerun
+// requested with rerun_after() in the Tiltfile is due.
+type RerunScheduler struct {
+	clock clockwork.Clock
+
+	mu        sync.Mutex
+	scheduled map[model.ManifestName]scheduledRerun
+}
+
+type scheduledRerun struct {
+	due    time.Time
+	cancel context.CancelFunc
+}
+
+func NewRerunScheduler(clock clockwork.Clock) *RerunScheduler {
+	return &RerunScheduler{
+		clock:     clock,
+		scheduled: make(map[model.ManifestName]scheduledRerun),
+	}
+}
+
+// The time each resource is due to be re-triggered.
+//
+// Resources that are disabled, updating, or already queued
+// have nothing to schedule until their update finishes.
+func (s *RerunScheduler) dueTimes(st store.RStore) map[model.ManifestName]time.Time {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	result := make(map[model.ManifestName]time.Time)
+	for _, mt := range state.Targets() {
+		if mt.Manifest.RerunAfter <= 0 {
+			continue
+		}
+
+		ms := mt.State
+		if ms.DisableState == v1alpha1.DisableStateDisabled ||
+			ms.IsBuilding() ||
+			state.ManifestInTriggerQueue(mt.Manifest.Name) {
+			continue
+		}
+
+		lastBuild := ms.LastBuild()
+		if lastBuild.Empty() {
+			continue
+		}
+
+		result[mt.Manifest.Name] = lastBuild.FinishTime.Add(mt.Manifest.RerunAfter)
+	}
+	return result
+}
+
+func (s *RerunScheduler) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	dueTimes := s.dueTimes(st)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, existing := range s.scheduled {
+		due, ok := dueTimes[name]
+		if !ok || !due.Equal(existing.due) {
+			existing.cancel()
+			delete(s.scheduled, name)
+		}
+	}
+
+	for name, due := range dueTimes {
+		if _, ok := s.scheduled[name]; ok {
+			continue
+		}
+
+		timerCtx, cancel := context.WithCancel(ctx)
+		s.scheduled[name] = scheduledRerun{due: due, cancel: cancel}
+		go s.waitAndTrigger(timerCtx, st, name, due)
+	}
+	return nil
+}
+
+func (s *RerunScheduler) waitAndTrigger(ctx context.Context, st store.RStore, name model.ManifestName, due time.Time) {
+	timer := s.clock.NewTimer(due.Sub(s.clock.Now()))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.Chan():
+		st.Dispatch(store.AppendToTriggerQueueAction{Name: name, Reason: model.BuildReasonFlagTriggerTimer})
+	}
+}
+
+var _ store.Subscriber = &RerunScheduler{}
@@ -0,0 +1,86 @@
+package configs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestRerunScheduler(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	st := store.NewTestingStore()
+	st.WithState(func(s *store.EngineState) {
+		m := model.Manifest{Name: "token-refresh", RerunAfter: time.Minute}
+		s.UpsertManifestTarget(store.NewManifestTarget(m))
+		s.UpsertManifestTarget(store.NewManifestTarget(model.Manifest{Name: "api"}))
+	})
+	for _, name := range []model.ManifestName{"token-refresh", "api"} {
+		st.WithManifestState(name, func(ms *store.ManifestState) {
+			ms.AddCompletedBuild(model.BuildRecord{StartTime: clock.Now(), FinishTime: clock.Now()})
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs := NewRerunScheduler(clock)
+	require.NoError(t, rs.OnChange(ctx, st, store.ChangeSummary{}))
+
+	clock.BlockUntil(1)
+	assert.Empty(t, st.Actions())
+
+	clock.Advance(time.Minute)
+	require.Eventually(t, func() bool {
+		return len(st.Actions()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, store.AppendToTriggerQueueAction{
+		Name:   "token-refresh",
+		Reason: model.BuildReasonFlagTriggerTimer,
+	}, st.Actions()[0])
+}
+
+func TestRerunSchedulerReschedulesAfterBuild(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	st := store.NewTestingStore()
+	st.WithState(func(s *store.EngineState) {
+		m := model.Manifest{Name: "token-refresh", RerunAfter: time.Minute}
+		s.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+	st.WithManifestState("token-refresh", func(ms *store.ManifestState) {
+		ms.AddCompletedBuild(model.BuildRecord{StartTime: clock.Now(), FinishTime: clock.Now()})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs := NewRerunScheduler(clock)
+	require.NoError(t, rs.OnChange(ctx, st, store.ChangeSummary{}))
+	clock.BlockUntil(1)
+
+	// A new build finishes before the rerun is due, so the rerun
+	// should be pushed back.
+	clock.Advance(30 * time.Second)
+	st.WithManifestState("token-refresh", func(ms *store.ManifestState) {
+		ms.AddCompletedBuild(model.BuildRecord{StartTime: clock.Now(), FinishTime: clock.Now()})
+	})
+	require.NoError(t, rs.OnChange(ctx, st, store.ChangeSummary{}))
+
+	// The canceled timer is still counted as a sleeper.
+	clock.BlockUntil(2)
+
+	clock.Advance(30 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, st.Actions())
+
+	clock.Advance(30 * time.Second)
+	require.Eventually(t, func() bool {
+		return len(st.Actions()) == 1
+	}, time.Second, time.Millisecond)
+}
@@ -30,14 +30,17 @@ type DockerPruner struct {
 	dCli docker.Client
 
 	disabledForTesting bool
-	disabledOnSetup    bool
+
+	// Whether Docker can prune, which we check just before the first prune,
+	// so that Tilt doesn't connect to Docker until something builds an image.
+	checkedDocker  bool
+	disabledDocker bool
 
 	lastPruneBuildCount int
 	lastPruneTime       time.Time
 }
 
 var _ store.Subscriber = &DockerPruner{}
-var _ store.SetUpper = &DockerPruner{}
 
 func NewDockerPruner(dCli docker.Client) *DockerPruner {
 	return &DockerPruner{dCli: dCli}
@@ -47,22 +50,25 @@ func (dp *DockerPruner) DisabledForTesting(disabled bool) {
 	dp.disabledForTesting = disabled
 }
 
-func (dp *DockerPruner) SetUp(ctx context.Context, _ store.RStore) error {
+func (dp *DockerPruner) checkDocker(ctx context.Context) {
+	if dp.checkedDocker {
+		return
+	}
+	dp.checkedDocker = true
+
 	err := dp.dCli.CheckConnected()
 	if err != nil {
 		// If Docker is not responding at all, other parts of the system will log this.
-		dp.disabledOnSetup = true
-		return nil
+		dp.disabledDocker = true
+		return
 	}
 
 	if err := dp.sufficientVersionError(); err != nil {
 		logger.Get(ctx).Infof(
 			"[Docker Prune] Docker API version too low for Tilt to run Docker Prune:\n\t%v", err,
 		)
-		dp.disabledOnSetup = true
-		return nil
+		dp.disabledDocker = true
 	}
-	return nil
 }
 
 // OnChange determines if any Tilt-built Docker images should be pruned based on settings and invokes the pruning
@@ -72,7 +78,7 @@ func (dp *DockerPruner) SetUp(ctx context.Context, _ store.RStore) error {
 // is invoked for EVERY store action change batch. Because of this, the store (un)locking is done somewhat manually,
 // so care must be taken to avoid locking issues.
 func (dp *DockerPruner) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
-	if dp.disabledForTesting || dp.disabledOnSetup || summary.IsLogOnly() {
+	if dp.disabledForTesting || dp.disabledDocker || summary.IsLogOnly() {
 		return nil
 	}
 
@@ -113,6 +119,10 @@ func (dp *DockerPruner) OnChange(ctx context.Context, st store.RStore, summary s
 		// 	is called, no pruning is going to happen, so avoid burning CPU cycles unnecessarily
 		imgSelectors := model.LocalRefSelectorsForManifests(state.Manifests(), state.Clusters)
 		st.RUnlockState()
+		dp.checkDocker(ctx)
+		if dp.disabledDocker {
+			return nil
+		}
 		dp.PruneAndRecordState(ctx, settings.MaxAge, settings.KeepRecent, imgSelectors, curBuildCount)
 		return nil
 	}
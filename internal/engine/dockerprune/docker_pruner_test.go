@@ -298,6 +298,18 @@ func TestDockerPrunerFirstRun(t *testing.T) {
 	f.assertPrune()
 }
 
+func TestDockerPrunerDockerNotConnected(t *testing.T) {
+	f := newFixture(t)
+	f.withDockerManifestAlreadyBuilt()
+	f.withBuildCount(5)
+	f.withDockerPruneSettings(true, 0, 10, 0)
+	f.dCli.CheckConnectedErr = fmt.Errorf("connection refused")
+
+	_ = f.dp.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+
+	f.assertNoPrune()
+}
+
 func TestDockerPrunerFirstRunButNoCompletedBuilds(t *testing.T) {
 	f := newFixture(t)
 	f.withDockerManifestAlreadyBuilt()
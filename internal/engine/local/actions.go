@@ -1,9 +1,12 @@
 package local
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type CmdCreateAction struct {
@@ -41,3 +44,11 @@ func (CmdDeleteAction) Action() {}
 func (a CmdDeleteAction) Summarize(s *store.ChangeSummary) {
 	s.CmdSpecs.Add(types.NamespacedName{Name: a.Name})
 }
+
+// Replaces the serve_cmd's process with a new one, without running the update.
+type RestartServeAction struct {
+	ManifestName model.ManifestName
+	Time         time.Time
+}
+
+func (RestartServeAction) Action() {}
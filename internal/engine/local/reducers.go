@@ -47,10 +47,12 @@ func updateLocalRuntimeStatus(state *store.EngineState, cmd *v1alpha1.Cmd) {
 		lrs.PID = int(cmd.Status.Running.PID)
 		lrs.StartTime = cmd.Status.Running.StartedAt.Time
 		lrs.FinishTime = time.Time{}
+		lrs.Usage = cmd.Status.Running.Usage
 
 		// Currently, Cmd is only used for servers.
-		// Make the Status OK when the readiness probe passes (if there is one).
-		if spec.ReadinessProbe == nil || cmd.Status.Ready {
+		// Make the Status OK when the readiness probe passes and the ready
+		// regex matches (if there are any).
+		if (spec.ReadinessProbe == nil && spec.ReadyRegex == "") || cmd.Status.Ready {
 			lrs.Status = v1alpha1.RuntimeStatusOK
 		} else {
 			lrs.Status = v1alpha1.RuntimeStatusPending
@@ -63,10 +65,12 @@ func updateLocalRuntimeStatus(state *store.EngineState, cmd *v1alpha1.Cmd) {
 		lrs.Status = v1alpha1.RuntimeStatusError
 		lrs.StartTime = status.Terminated.StartedAt.Time
 		lrs.FinishTime = status.Terminated.FinishedAt.Time
+		lrs.Usage = nil
 	} else {
 		lrs.Status = v1alpha1.RuntimeStatusPending
 		lrs.StartTime = time.Time{}
 		lrs.FinishTime = time.Time{}
+		lrs.Usage = nil
 	}
 
 	if lrs.Ready != cmd.Status.Ready {
@@ -102,3 +106,16 @@ func HandleCmdCreateAction(state *store.EngineState, action CmdCreateAction) {
 func HandleCmdDeleteAction(state *store.EngineState, action CmdDeleteAction) {
 	delete(state.Cmds, action.Name)
 }
+
+// Record the restart, so that the server controller replaces the command.
+func HandleRestartServeAction(state *store.EngineState, action RestartServeAction) {
+	mt, ok := state.ManifestTargets[action.ManifestName]
+	if !ok || !mt.Manifest.IsLocal() {
+		return
+	}
+
+	ms := mt.State
+	lrs := ms.LocalRuntimeState()
+	lrs.LastRestartTime = action.Time
+	ms.RuntimeState = lrs
+}
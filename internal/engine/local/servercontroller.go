@@ -118,6 +118,12 @@ func (c *ServerController) determineServers(ctx context.Context, st store.RStore
 			continue
 		}
 
+		// A restart replaces the server the same way a successful update does.
+		triggerTime := mt.State.LastSuccessfulDeployTime
+		if restartTime := mt.State.LocalRuntimeState().LastRestartTime; restartTime.After(triggerTime) {
+			triggerTime = restartTime
+		}
+
 		name := mt.Manifest.Name.String()
 		cmdServer := CmdServer{
 			TypeMeta: metav1.TypeMeta{
@@ -135,9 +141,20 @@ func (c *ServerController) determineServers(ctx context.Context, st store.RStore
 				Args:           lt.ServeCmd.Argv,
 				Dir:            lt.ServeCmd.Dir,
 				Env:            lt.ServeCmd.Env,
-				TriggerTime:    mt.State.LastSuccessfulDeployTime,
+				TriggerTime:    triggerTime,
 				ReadinessProbe: lt.ReadinessProbe,
+				ReadyRegex:     lt.ServeReadyRegex,
 				DisableSource:  lt.ServeCmdDisableSource,
+				GracePeriod:    lt.ServeCmd.GracePeriod,
+
+				TerminationSignal: lt.ServeCmd.TerminationSignal,
+				PTY:               lt.ServeCmd.PTY,
+				RestartPolicy:     lt.ServeRestartPolicy,
+				MaxRestarts:       lt.ServeMaxRestarts,
+				Stdin:             lt.ServeCmd.Stdin,
+				EnvFiles:          lt.ServeCmd.EnvFiles,
+				ExpandEnv:         lt.ServeCmd.ExpandEnv,
+				Target:            lt.ServeCmd.Target,
 			},
 		}
 
@@ -265,6 +282,19 @@ func (c *ServerController) reconcile(ctx context.Context, server CmdServer, owne
 		Dir:            server.Spec.Dir,
 		Env:            server.Spec.Env,
 		ReadinessProbe: server.Spec.ReadinessProbe,
+		ReadyRegex:     server.Spec.ReadyRegex,
+
+		TerminationSignal: server.Spec.TerminationSignal,
+		PTY:               server.Spec.PTY,
+		RestartPolicy:     server.Spec.RestartPolicy,
+		MaxRestarts:       server.Spec.MaxRestarts,
+		Stdin:             server.Spec.Stdin,
+		EnvFiles:          server.Spec.EnvFiles,
+		ExpandEnv:         server.Spec.ExpandEnv,
+		Target:            server.Spec.Target,
+	}
+	if server.Spec.GracePeriod != nil {
+		cmdSpec.GracePeriod = &metav1.Duration{Duration: *server.Spec.GracePeriod}
 	}
 
 	triggerTime := c.createdTriggerTime[name]
@@ -336,12 +366,23 @@ type CmdServerSpec struct {
 	Dir            string
 	Env            []string
 	ReadinessProbe *v1alpha1.Probe
+	ReadyRegex     string
 
 	// Kubernetes tends to represent this as a "generation" field
 	// to force an update.
 	TriggerTime time.Time
 
 	DisableSource *v1alpha1.DisableSource
+
+	GracePeriod       *time.Duration
+	TerminationSignal string
+	PTY               bool
+	RestartPolicy     v1alpha1.CmdRestartPolicy
+	MaxRestarts       int32
+	Stdin             bool
+	EnvFiles          []string
+	ExpandEnv         bool
+	Target            *v1alpha1.CmdTarget
 }
 
 type CmdServerStatus struct {
@@ -0,0 +1,269 @@
+package loghistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Whether to write the logs of each session to disk.
+type Enabled bool
+
+// A session's logs are kept in at most this many files of at most
+// maxFileSize bytes, so a chatty session can't fill the disk.
+const (
+	maxFileSize = 10 * 1000 * 1000
+	maxFiles    = 5
+)
+
+const (
+	SessionCurrent  = "current"
+	SessionPrevious = "previous"
+
+	logFileName     = "logs.jsonl"
+	sessionFileName = "session.json"
+)
+
+var ErrNoSession = errors.New("no logs saved for that session")
+
+// One log segment, as written to disk.
+type Record struct {
+	Time     time.Time          `json:"time"`
+	Resource model.ManifestName `json:"resource,omitempty"`
+	SpanID   logstore.SpanID    `json:"spanId"`
+	Level    string             `json:"level"`
+	Text     string             `json:"text"`
+	Fields   logger.Fields      `json:"fields,omitempty"`
+}
+
+// Metadata about a session whose logs were saved.
+type Session struct {
+	StartTime time.Time `json:"startTime"`
+}
+
+// The directory with the logs of one session of the Tilt at the given
+// apiserver name. Each Tilt port has its own history.
+func SessionDir(base xdg.Base, name model.APIServerName, session string) (string, error) {
+	p, err := base.StateFile(filepath.Join("log_history", string(name), session, sessionFileName))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(p), nil
+}
+
+// Where the previous session's logs are, while Tilt is running.
+type PreviousDir string
+
+func ProvidePreviousDir(base xdg.Base, name model.APIServerName) (PreviousDir, error) {
+	dir, err := SessionDir(base, name, SessionPrevious)
+	return PreviousDir(dir), err
+}
+
+// Rotates the last session's logs to the previous session, and
+// starts a new current session.
+func startSession(base xdg.Base, name model.APIServerName, startTime time.Time) (string, error) {
+	current, err := SessionDir(base, name, SessionCurrent)
+	if err != nil {
+		return "", err
+	}
+	previous, err := SessionDir(base, name, SessionPrevious)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.RemoveAll(previous)
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(current, previous)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	err = os.MkdirAll(current, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(Session{StartTime: startTime})
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(current, sessionFileName), b, 0600)
+	if err != nil {
+		return "", err
+	}
+	return current, nil
+}
+
+// The log files of a session, oldest first.
+func logFiles(dir string) []string {
+	var result []string
+	for i := maxFiles - 1; i > 0; i-- {
+		result = append(result, filepath.Join(dir, rotatedName(i)))
+	}
+	return append(result, filepath.Join(dir, logFileName))
+}
+
+func rotatedName(i int) string {
+	return fmt.Sprintf("logs.%d.jsonl", i)
+}
+
+// Reads the metadata and logs of a saved session, oldest first.
+//
+// A line cut off by a crash is skipped, so that everything before it can
+// still be read.
+func ReadSession(dir string) (Session, []Record, error) {
+	var session Session
+	b, err := os.ReadFile(filepath.Join(dir, sessionFileName))
+	if os.IsNotExist(err) {
+		return session, nil, ErrNoSession
+	}
+	if err != nil {
+		return session, nil, err
+	}
+	err = json.Unmarshal(b, &session)
+	if err != nil {
+		return session, nil, fmt.Errorf("reading %s: %v", dir, err)
+	}
+
+	var records []Record
+	for _, path := range logFiles(dir) {
+		records, err = readLogFile(path, records)
+		if err != nil {
+			return session, nil, err
+		}
+	}
+	return session, records, nil
+}
+
+func readLogFile(path string, records []Record) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var record Record
+			if json.Unmarshal(line, &record) == nil {
+				records = append(records, record)
+			}
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Appends records to the current session's log file, rotating it
+// when it gets too big.
+//
+// Not thread-safe.
+type writer struct {
+	dir     string
+	f       *os.File
+	w       *bufio.Writer
+	size    int64
+	maxSize int64
+}
+
+func newWriter(dir string) (*writer, error) {
+	w := &writer{dir: dir, maxSize: maxFileSize}
+	err := w.open()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *writer) open() error {
+	f, err := os.OpenFile(filepath.Join(w.dir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.f = f
+	w.w = bufio.NewWriter(f)
+	w.size = info.Size()
+	return nil
+}
+
+// Writes the records and flushes them, so that they survive a crash.
+func (w *writer) write(records []Record) error {
+	for _, record := range records {
+		b, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+
+		if w.size > 0 && w.size+int64(len(b)) > w.maxSize {
+			err := w.rotate()
+			if err != nil {
+				return err
+			}
+		}
+
+		n, err := w.w.Write(b)
+		w.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
+
+// Shifts each log file to the next older name, dropping the oldest.
+func (w *writer) rotate() error {
+	err := w.close()
+	if err != nil {
+		return err
+	}
+
+	files := logFiles(w.dir)
+	err = os.Remove(files[0])
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := 1; i < len(files); i++ {
+		err := os.Rename(files[i], files[i-1])
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return w.open()
+}
+
+func (w *writer) close() error {
+	err := w.w.Flush()
+	closeErr := w.f.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
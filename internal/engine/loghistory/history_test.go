@@ -0,0 +1,148 @@
+package loghistory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+func TestStartSessionRotates(t *testing.T) {
+	base := xdg.FakeBase{Dir: t.TempDir()}
+	first := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	dir, err := startSession(base, "tilt-default", first)
+	require.NoError(t, err)
+	writeRecords(t, dir, record("fe", "info", "from the first session\n"))
+
+	_, err = startSession(base, "tilt-default", second)
+	require.NoError(t, err)
+
+	previous, err := SessionDir(base, "tilt-default", SessionPrevious)
+	require.NoError(t, err)
+	s, records, err := ReadSession(previous)
+	require.NoError(t, err)
+	assert.True(t, first.Equal(s.StartTime))
+	require.Len(t, records, 1)
+	assert.Equal(t, "from the first session\n", records[0].Text)
+
+	current, err := SessionDir(base, "tilt-default", SessionCurrent)
+	require.NoError(t, err)
+	s, records, err = ReadSession(current)
+	require.NoError(t, err)
+	assert.True(t, second.Equal(s.StartTime))
+	assert.Empty(t, records)
+
+	// Each port keeps its own history.
+	other, err := SessionDir(base, "tilt-10351", SessionPrevious)
+	require.NoError(t, err)
+	_, _, err = ReadSession(other)
+	assert.Equal(t, ErrNoSession, err)
+}
+
+func TestWriterRotates(t *testing.T) {
+	base := xdg.FakeBase{Dir: t.TempDir()}
+	dir, err := startSession(base, "tilt-default", time.Now())
+	require.NoError(t, err)
+
+	w, err := newWriter(dir)
+	require.NoError(t, err)
+	w.maxSize = 200
+	for i := 0; i < 50; i++ {
+		require.NoError(t, w.write([]Record{record("fe", "info", fmt.Sprintf("line %d\n", i))}))
+	}
+	require.NoError(t, w.close())
+
+	_, records, err := ReadSession(dir)
+	require.NoError(t, err)
+
+	// Only the newest files are kept, in order.
+	require.NotEmpty(t, records)
+	assert.Less(t, len(records), 50)
+	assert.Equal(t, "line 49\n", records[len(records)-1].Text)
+	first := len(records)
+	for i, r := range records {
+		assert.Equal(t, fmt.Sprintf("line %d\n", 50-first+i), r.Text)
+	}
+	_, err = os.Stat(filepath.Join(dir, rotatedName(maxFiles)))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestReadSessionSkipsTruncatedLine(t *testing.T) {
+	base := xdg.FakeBase{Dir: t.TempDir()}
+	dir, err := startSession(base, "tilt-default", time.Now())
+	require.NoError(t, err)
+	writeRecords(t, dir, record("fe", "info", "before the crash\n"))
+
+	f, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"2024-03-01T09:00:00Z","resource":"fe","te`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, records, err := ReadSession(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "before the crash\n", records[0].Text)
+}
+
+func TestPrint(t *testing.T) {
+	now := time.Now()
+	old := record("fe", "info", "old\n")
+	old.Time = now.Add(-time.Hour)
+	records := []Record{
+		old,
+		record("fe", "info", "fe started\n"),
+		record("be", "error", "be crashed\n"),
+		record("fe", "debug", "fe details\n"),
+	}
+
+	assert.Equal(t, "old\nfe started\nERROR: be crashed\n",
+		printToString(records, PrintOptions{NoPrefix: true, MinLevel: logger.InfoLvl}))
+	assert.Equal(t, "fe started\nfe details\n",
+		printToString(records, PrintOptions{
+			NoPrefix:  true,
+			Resources: model.ManifestNameSet{"fe": true},
+			Since:     now.Add(-time.Minute),
+		}))
+	assert.Equal(t, "           be │ ERROR: be crashed\n",
+		printToString(records, PrintOptions{MinLevel: logger.ErrorLvl}))
+}
+
+func record(mn model.ManifestName, level string, text string) Record {
+	return Record{
+		Time:     time.Now(),
+		Resource: mn,
+		SpanID:   logstore.SpanID("pod:" + string(mn)),
+		Level:    level,
+		Text:     text,
+	}
+}
+
+func writeRecords(t *testing.T, dir string, records ...Record) {
+	w, err := newWriter(dir)
+	require.NoError(t, err)
+	require.NoError(t, w.write(records))
+	require.NoError(t, w.close())
+}
+
+func printToString(records []Record, opts PrintOptions) string {
+	var sb strings.Builder
+	Print(records, opts, func(lines []logstore.LogLine) {
+		for _, l := range lines {
+			sb.WriteString(l.Text)
+		}
+	})
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package loghistory
+
+import (
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Which saved logs to print.
+type PrintOptions struct {
+	// Only print logs from these resources. Prints all of them when empty.
+	Resources model.ManifestNameSet
+
+	// Only print logs at least this severe.
+	MinLevel logger.Level
+
+	// Only print logs logged at or after this time.
+	Since time.Time
+
+	// Leave out the resource name in front of each line.
+	NoPrefix bool
+
+	// Put a timestamp in front of each line. Relative timestamps count
+	// from the first line printed, unless Start is set.
+	Timestamps logstore.Timestamps
+}
+
+func (o PrintOptions) matches(r Record) bool {
+	if len(o.Resources) > 0 && !o.Resources[r.Resource] {
+		return false
+	}
+	if !o.Since.IsZero() && r.Time.Before(o.Since) {
+		return false
+	}
+	return o.MinLevel.ShouldDisplay(recordEvent{r}.Level())
+}
+
+// Rebuilds saved records into lines, formatted the same way as in the
+// terminal, and passes them to print a batch at a time.
+//
+// A session may have more logs than a LogStore keeps, so the lines are
+// printed as they're rebuilt rather than all at the end.
+func Print(records []Record, opts PrintOptions, print func(lines []logstore.LogLine)) {
+	ls := logstore.NewLogStore()
+	var checkpoint logstore.Checkpoint
+	for _, r := range records {
+		if !opts.matches(r) {
+			continue
+		}
+
+		// Secrets were scrubbed before the logs were saved.
+		ls.Append(recordEvent{r}, model.SecretSet{})
+		print(ls.ContinuingLinesWithOptions(checkpoint, logstore.LineOptions{
+			SuppressPrefix: opts.NoPrefix,
+			Timestamps:     opts.Timestamps,
+		}))
+		checkpoint = ls.Checkpoint()
+	}
+}
+
+// Returns the records that match the options as log segments, for
+// printers that don't need them rebuilt into lines.
+func Segments(records []Record, opts PrintOptions) []logstore.StreamSegment {
+	var result []logstore.StreamSegment
+	for _, r := range records {
+		if !opts.matches(r) {
+			continue
+		}
+		e := recordEvent{r}
+		result = append(result, logstore.StreamSegment{
+			LogSegment: logstore.LogSegment{
+				SpanID: e.SpanID(),
+				Time:   e.Time(),
+				Text:   e.Message(),
+				Level:  e.Level(),
+				Fields: e.Fields(),
+			},
+			ManifestName: e.ManifestName(),
+		})
+	}
+	return result
+}
+
+type recordEvent struct {
+	r Record
+}
+
+var _ logstore.LogEvent = recordEvent{}
+
+func (e recordEvent) Message() []byte                  { return []byte(e.r.Text) }
+func (e recordEvent) Time() time.Time                  { return e.r.Time }
+func (e recordEvent) Fields() logger.Fields            { return e.r.Fields }
+func (e recordEvent) ManifestName() model.ManifestName { return e.r.Resource }
+func (e recordEvent) SpanID() logstore.SpanID          { return e.r.SpanID }
+
+func (e recordEvent) Level() logger.Level {
+	level, err := logger.ParseLevel(e.r.Level)
+	if err != nil {
+		return logger.InfoLvl
+	}
+	return level
+}
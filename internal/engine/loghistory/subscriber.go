@@ -0,0 +1,93 @@
+package loghistory
+
+import (
+	"context"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Writes every log segment to disk as it's logged, so that the logs of
+// a session outlive it. When Tilt starts, the last session's logs become
+// the previous session, which `tilt logs --previous` reads.
+//
+// Logs are written after they're scrubbed of secrets and filtered by
+// log level, so the history matches what the UI showed.
+type Subscriber struct {
+	base    xdg.Base
+	name    model.APIServerName
+	enabled Enabled
+
+	// nil if history is disabled, or we couldn't write it.
+	w          *writer
+	checkpoint logstore.Checkpoint
+}
+
+var _ store.SubscriberLifecycle = &Subscriber{}
+
+func NewSubscriber(base xdg.Base, name model.APIServerName, enabled Enabled) *Subscriber {
+	return &Subscriber{base: base, name: name, enabled: enabled}
+}
+
+func (s *Subscriber) SetUp(ctx context.Context, st store.RStore) error {
+	if !s.enabled {
+		return nil
+	}
+
+	dir, err := startSession(s.base, s.name, time.Now())
+	if err == nil {
+		s.w, err = newWriter(dir)
+	}
+	if err != nil {
+		// Losing the history shouldn't stop Tilt.
+		logger.Get(ctx).Infof("Not saving log history: %v", err)
+	}
+	return nil
+}
+
+func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if s.w == nil {
+		return nil
+	}
+
+	state := st.RLockState()
+	segments, checkpoint := state.LogStore.SegmentsSince(s.checkpoint, logstore.SegmentOptions{})
+	st.RUnlockState()
+	s.checkpoint = checkpoint
+
+	if len(segments) == 0 {
+		return nil
+	}
+
+	records := make([]Record, 0, len(segments))
+	for _, seg := range segments {
+		records = append(records, Record{
+			Time:     seg.Time,
+			Resource: seg.ManifestName,
+			SpanID:   seg.SpanID,
+			Level:    seg.Level.Name(),
+			Text:     string(seg.Text),
+			Fields:   seg.Fields,
+		})
+	}
+
+	err := s.w.write(records)
+	if err != nil {
+		logger.Get(ctx).Infof("Not saving log history: %v", err)
+		_ = s.w.close()
+		s.w = nil
+	}
+	return nil
+}
+
+func (s *Subscriber) TearDown(ctx context.Context) {
+	if s.w == nil {
+		return
+	}
+	_ = s.w.close()
+	s.w = nil
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/tracer"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The trace of one update of a resource.
+//
+// The trace starts at the earliest file change that the update picked up,
+// and ends when the resource is ready (or the update fails, or another
+// update replaces it).
+type pipelineTrace struct {
+	root trace.Span
+
+	// The start time of the build, to match it up with its BuildRecord.
+	buildStartTime time.Time
+
+	// The span waiting for the resource to become ready after a successful
+	// build. Nil while the build is in progress.
+	ready trace.Span
+}
+
+// The earliest time that one of the files the update picked up changed,
+// or the zero time if there weren't any.
+func firstFileChangeTime(ms *store.ManifestState, targets []model.TargetSpec) time.Time {
+	var first time.Time
+	for _, target := range targets {
+		for _, t := range ms.BuildStatus(target.ID()).PendingFileChanges {
+			if first.IsZero() || t.Before(first) {
+				first = t
+			}
+		}
+	}
+	return first
+}
+
+// Starts tracing an update, and returns a context that the build steps
+// can add their spans to.
+func (c *BuildController) startPipelineTrace(ctx context.Context, entry buildEntry, startTime time.Time) context.Context {
+	traceStart := startTime
+	if !entry.firstFileChangeTime.IsZero() && entry.firstFileChangeTime.Before(startTime) {
+		traceStart = entry.firstFileChangeTime
+	}
+
+	_, root := c.tracer.Start(context.Background(), "update",
+		trace.WithTimestamp(traceStart),
+		trace.WithAttributes(
+			attribute.String("tilt.resource", entry.name.String()),
+			attribute.String("tilt.reason", entry.buildReason.String()),
+			attribute.Int("tilt.files_changed", len(entry.filesChanged)),
+		))
+	ctx = tracer.ContextWithPipelineSpan(ctx, root)
+
+	if traceStart.Before(startTime) {
+		// Time spent between the first file change and the build starting,
+		// e.g., waiting for more changes or for a build slot.
+		span := tracer.StartPipelineStep(ctx, "file-event", trace.WithTimestamp(traceStart))
+		span.End(trace.WithTimestamp(startTime))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if prev, ok := c.traces[entry.name]; ok {
+		prev.end("superseded by another update")
+	}
+	c.traces[entry.name] = &pipelineTrace{root: root, buildStartTime: startTime}
+	return ctx
+}
+
+// Records the result of the build. If it succeeded, we keep the trace open
+// until the resource is ready.
+func (c *BuildController) finishPipelineBuild(name model.ManifestName, startTime time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pt, ok := c.traces[name]
+	if !ok || !pt.buildStartTime.Equal(startTime) {
+		return
+	}
+
+	if err != nil {
+		pt.root.RecordError(err)
+		pt.root.SetStatus(codes.Error, err.Error())
+		pt.root.End()
+		delete(c.traces, name)
+		return
+	}
+
+	pt.ready = tracer.StartPipelineStep(tracer.ContextWithPipelineSpan(context.Background(), pt.root), "ready")
+}
+
+// Ends the traces of any resources that became ready since the last change.
+func (c *BuildController) observePipelineReadiness(st store.RStore) {
+	// Lock the state before c.mu, like cleanUpCanceledBuilds does.
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, pt := range c.traces {
+		if pt.ready == nil {
+			continue
+		}
+
+		mt, ok := state.ManifestTargets[name]
+		if !ok {
+			pt.end("resource removed")
+			delete(c.traces, name)
+			continue
+		}
+
+		// Wait until the engine has recorded the build.
+		ms := mt.State
+		if ms.IsBuilding() || !ms.LastBuild().StartTime.Equal(pt.buildStartTime) {
+			continue
+		}
+
+		switch mt.RuntimeStatus() {
+		case v1alpha1.RuntimeStatusOK, v1alpha1.RuntimeStatusNotApplicable:
+			pt.ready.End()
+			pt.root.End()
+			delete(c.traces, name)
+		case v1alpha1.RuntimeStatusError:
+			pt.ready.SetStatus(codes.Error, "resource failed to become ready")
+			pt.ready.End()
+			pt.root.SetStatus(codes.Error, "resource failed to become ready")
+			pt.root.End()
+			delete(c.traces, name)
+		}
+	}
+}
+
+// Ends the trace before the resource became ready.
+func (pt *pipelineTrace) end(reason string) {
+	if pt.ready != nil {
+		pt.ready.SetAttributes(attribute.String("tilt.unfinished", reason))
+		pt.ready.End()
+	}
+	pt.root.SetAttributes(attribute.String("tilt.unfinished", reason))
+	pt.root.End()
+}
@@ -3,12 +3,16 @@ package engine
 import (
 	"github.com/tilt-dev/tilt/internal/cloud"
 	"github.com/tilt-dev/tilt/internal/controllers"
+	"github.com/tilt-dev/tilt/internal/controllers/core/logsink"
 	"github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/buildhistory"
+	"github.com/tilt-dev/tilt/internal/engine/checkpoint"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -16,6 +20,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/store"
 )
 
@@ -51,6 +56,7 @@ func ProvideSubscribers(
 	bc *BuildController,
 	cc *configs.ConfigsController,
 	tqs *configs.TriggerQueueSubscriber,
+	rs *configs.RerunScheduler,
 	ar *analytics.AnalyticsReporter,
 	au *analytics.AnalyticsUpdater,
 	ewm *k8swatch.EventWatchManager,
@@ -62,6 +68,11 @@ func ProvideSubscribers(
 	sc *session.Controller,
 	uss *uisession.Subscriber,
 	urs *uiresource.Subscriber,
+	bhs *buildhistory.Subscriber,
+	cps *checkpoint.Subscriber,
+	ms *metrics.Subscriber,
+	lhs *loghistory.Subscriber,
+	lsf *logsink.Forwarder,
 ) []store.Subscriber {
 	apiSubscribers := ProvideSubscribersAPIOnly(hudsc, tscm, cb, ts)
 
@@ -72,6 +83,7 @@ func ProvideSubscribers(
 		bc,
 		cc,
 		tqs,
+		rs,
 		ar,
 		au,
 		ewm,
@@ -83,6 +95,11 @@ func ProvideSubscribers(
 		sc,
 		uss,
 		urs,
+		bhs,
+		cps,
+		ms,
+		lhs,
+		lsf,
 	}
 	return append(apiSubscribers, legacySubscribers...)
 }
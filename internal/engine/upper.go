@@ -10,6 +10,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/core/tiltfile"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
@@ -26,6 +27,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store/dockercomposeservices"
 	"github.com/tilt-dev/tilt/internal/store/dockerimages"
 	"github.com/tilt-dev/tilt/internal/store/filewatches"
+	"github.com/tilt-dev/tilt/internal/store/healthprobes"
 	"github.com/tilt-dev/tilt/internal/store/imagemaps"
 	"github.com/tilt-dev/tilt/internal/store/kubernetesapplys"
 	"github.com/tilt-dev/tilt/internal/store/kubernetesdiscoverys"
@@ -37,6 +39,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
 
@@ -77,6 +80,7 @@ func (u Upper) Start(
 	analyticsUserOpt analytics.Opt,
 	token token.Token,
 	cloudAddress string,
+	logRetention logstore.Retention,
 ) error {
 
 	startTime := time.Now()
@@ -98,6 +102,7 @@ func (u Upper) Start(
 		Token:            token,
 		CloudAddress:     cloudAddress,
 		TerminalMode:     initTerminalMode,
+		LogRetention:     logRetention,
 	})
 }
 
@@ -166,12 +171,18 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handleSwitchTerminalModeAction(state, action)
 	case server.OverrideTriggerModeAction:
 		handleOverrideTriggerModeAction(ctx, state, action)
+	case server.SetResourceGroupsCollapsedAction:
+		handleSetResourceGroupsCollapsedAction(state, action)
+	case server.BulkTriggerAction:
+		handleBulkTriggerAction(state, action)
 	case local.CmdCreateAction:
 		local.HandleCmdCreateAction(state, action)
 	case local.CmdUpdateStatusAction:
 		local.HandleCmdUpdateStatusAction(state, action)
 	case local.CmdDeleteAction:
 		local.HandleCmdDeleteAction(state, action)
+	case local.RestartServeAction:
+		local.HandleRestartServeAction(state, action)
 	case tiltfiles.TiltfileUpsertAction:
 		tiltfiles.HandleTiltfileUpsertAction(state, action)
 	case tiltfiles.TiltfileDeleteAction:
@@ -192,6 +203,10 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		cmdimages.HandleCmdImageUpsertAction(state, action)
 	case cmdimages.CmdImageDeleteAction:
 		cmdimages.HandleCmdImageDeleteAction(state, action)
+	case healthprobes.HealthProbeUpsertAction:
+		healthprobes.HandleHealthProbeUpsertAction(state, action)
+	case healthprobes.HealthProbeDeleteAction:
+		healthprobes.HandleHealthProbeDeleteAction(state, action)
 	case kubernetesapplys.KubernetesApplyUpsertAction:
 		kubernetesapplys.HandleKubernetesApplyUpsertAction(state, action)
 	case kubernetesapplys.KubernetesApplyDeleteAction:
@@ -232,9 +247,32 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 var UpperReducer = store.Reducer(upperReducerFn)
 
 func handleLogAction(state *store.EngineState, action store.LogAction) {
+	if !shouldKeepLog(state, action) {
+		return
+	}
 	state.LogStore.Append(action, state.Secrets)
 }
 
+// Drops logs below the resource's log level for their source.
+func shouldKeepLog(state *store.EngineState, action store.LogAction) bool {
+	mn := action.ManifestName()
+	if mn == "" {
+		return true
+	}
+	cm, ok := state.ConfigMaps[configmap.LogLevelsName(mn)]
+	if !ok {
+		return true
+	}
+	source := logstore.SourceForSpan(action.SpanID())
+	return configmap.LogLevelFor(cm, source).ShouldDisplay(action.Level())
+}
+
+func handleBulkTriggerAction(state *store.EngineState, action server.BulkTriggerAction) {
+	for _, mn := range action.ManifestNames {
+		state.AppendToTriggerQueue(mn, action.Reason)
+	}
+}
+
 func handleSwitchTerminalModeAction(state *store.EngineState, action prompt.SwitchTerminalModeAction) {
 	state.TerminalMode = action.Mode
 }
@@ -288,6 +326,9 @@ func handleInitAction(ctx context.Context, engineState *store.EngineState, actio
 	engineState.CloudAddress = action.CloudAddress
 	engineState.Token = action.Token
 	engineState.TerminalMode = action.TerminalMode
+	engineState.LogStore.SetRetention(action.LogRetention)
+	engineState.LogStore.SetCollapseRepeats(true)
+	engineState.LogStore.SetRedactPatterns(model.BuiltinRedactPatterns)
 }
 
 func handleHudExitAction(state *store.EngineState, action hud.ExitAction) {
@@ -322,6 +363,19 @@ func handleTiltCloudStatusReceivedAction(state *store.EngineState, action store.
 	state.SuggestedTiltVersion = action.SuggestedTiltVersion
 }
 
+func handleSetResourceGroupsCollapsedAction(state *store.EngineState, action server.SetResourceGroupsCollapsedAction) {
+	if state.CollapsedGroups == nil {
+		state.CollapsedGroups = make(map[string]bool)
+	}
+	for _, p := range action.Paths {
+		if action.Collapsed {
+			state.CollapsedGroups[p] = true
+		} else {
+			delete(state.CollapsedGroups, p)
+		}
+	}
+}
+
 func handleOverrideTriggerModeAction(ctx context.Context, state *store.EngineState,
 	action server.OverrideTriggerModeAction) {
 	// TODO(maia): in this implementation, overrides do NOT persist across Tiltfile loads
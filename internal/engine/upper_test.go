@@ -35,7 +35,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
 	"github.com/tilt-dev/tilt/internal/controllers"
+	configmap2 "github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	apitiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	"github.com/tilt-dev/tilt/internal/controllers/core/alertrule"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmdimage"
@@ -47,27 +49,35 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch/fsevent"
+	"github.com/tilt-dev/tilt/internal/controllers/core/healthprobe"
 	"github.com/tilt-dev/tilt/internal/controllers/core/imagemap"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesdiscovery"
 	"github.com/tilt-dev/tilt/internal/controllers/core/liveupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/logsink"
+	"github.com/tilt-dev/tilt/internal/controllers/core/notification"
 	"github.com/tilt-dev/tilt/internal/controllers/core/podlogstream"
 	apiportforward "github.com/tilt-dev/tilt/internal/controllers/core/portforward"
+	"github.com/tilt-dev/tilt/internal/controllers/core/resourceevent"
 	ctrlsession "github.com/tilt-dev/tilt/internal/controllers/core/session"
 	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/core/tiltfile"
 	"github.com/tilt-dev/tilt/internal/controllers/core/togglebutton"
 	ctrluibutton "github.com/tilt-dev/tilt/internal/controllers/core/uibutton"
 	ctrluiresource "github.com/tilt-dev/tilt/internal/controllers/core/uiresource"
 	ctrluisession "github.com/tilt-dev/tilt/internal/controllers/core/uisession"
+	"github.com/tilt-dev/tilt/internal/devenv"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/engine/buildhistory"
+	"github.com/tilt-dev/tilt/internal/engine/checkpoint"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -80,7 +90,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/openurl"
+	"github.com/tilt-dev/tilt/internal/secretstore"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
@@ -97,6 +109,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/cisettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
+	"github.com/tilt-dev/tilt/internal/tiltfile/secrets"
 	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
 	"github.com/tilt-dev/tilt/internal/tiltfile/version"
 	"github.com/tilt-dev/tilt/internal/token"
@@ -2102,7 +2115,7 @@ func TestEmptyTiltfile(t *testing.T) {
 		err := f.upper.Start(f.ctx, []string{}, model.TiltBuild{},
 			f.JoinPath("Tiltfile"), store.TerminalModeHUD,
 			analytics.OptIn, token.Token("unit test token"),
-			"nonexistent.example.com")
+			"nonexistent.example.com", logstore.Retention{})
 		closeCh <- err
 	}()
 	f.WaitUntil("build is set", func(st store.EngineState) bool {
@@ -2136,7 +2149,7 @@ func TestUpperStart(t *testing.T) {
 	go func() {
 		err := f.upper.Start(f.ctx, []string{"foo", "bar"}, model.TiltBuild{},
 			f.JoinPath("Tiltfile"), store.TerminalModeHUD,
-			analytics.OptIn, tok, cloudAddress)
+			analytics.OptIn, tok, cloudAddress, logstore.Retention{})
 		closeCh <- err
 	}()
 	f.WaitUntil("init action processed", func(state store.EngineState) bool {
@@ -2368,6 +2381,22 @@ alert-injest… │ ghij`)
 	assert.Nil(t, err)
 }
 
+func TestLogActionFilteredByLogLevels(t *testing.T) {
+	state := store.NewState()
+	mn := model.ManifestName("kafka")
+	cm := configmap2.NewLogLevelsConfigMap(mn, model.LogLevels{model.LogSourceRuntime: logger.WarnLvl})
+	state.ConfigMaps[cm.Name] = cm
+
+	podSpan := k8sconv.SpanIDForPod(mn, "kafka-0")
+	handleLogAction(state, store.NewLogAction(mn, podSpan, logger.InfoLvl, nil, []byte("chatty\n")))
+	handleLogAction(state, store.NewLogAction(mn, podSpan, logger.WarnLvl, nil, []byte("disk full\n")))
+	handleLogAction(state, store.NewLogAction(mn, SpanIDForBuildLog(1), logger.InfoLvl, nil, []byte("building\n")))
+	handleLogAction(state, store.NewLogAction("zookeeper", k8sconv.SpanIDForPod("zookeeper", "zk-0"), logger.InfoLvl, nil, []byte("zk info\n")))
+
+	assert.Equal(t, "WARNING: disk full\nbuilding\n", state.LogStore.ManifestLog(mn))
+	assert.Equal(t, "zk info\n", state.LogStore.ManifestLog("zookeeper"))
+}
+
 func TestBuildErrorLoggedOnceByUpper(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -3215,13 +3244,15 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	extPlugin := tiltextension.NewFakePlugin(
 		tiltextension.NewFakeExtRepoReconciler(f.Path()),
 		tiltextension.NewFakeExtReconciler(f.Path()))
-	ciSettingsPlugin := cisettings.NewPlugin(0)
+	ciSettingsPlugin := cisettings.NewPlugin(0, model.CISettingsFlags{})
+	secretsPlugin := secrets.NewPlugin(secretstore.NewStore(clockwork.NewFakeClock(), secretstore.DefaultTTL))
 	realTFL := tiltfile.ProvideTiltfileLoader(ta,
-		k8sContextPlugin, versionPlugin, configPlugin, extPlugin, ciSettingsPlugin,
+		k8sContextPlugin, versionPlugin, configPlugin, extPlugin, ciSettingsPlugin, secretsPlugin,
 		fakeDcc, "localhost", execer, feature.MainDefaults, env)
 	tfl := tiltfile.NewFakeTiltfileLoader()
 	cc := configs.NewConfigsController(cdc)
 	tqs := configs.NewTriggerQueueSubscriber(cdc)
+	rs := configs.NewRerunScheduler(clock)
 	serverOptions, err := server.ProvideTiltServerOptionsForTesting(ctx)
 	require.NoError(t, err)
 	webListener, err := server.ProvideWebListener("localhost", 0)
@@ -3242,7 +3273,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	lsc := local.NewServerController(cdc)
 	sr := ctrlsession.NewReconciler(cdc, st, clock)
 	sessionController := session.NewController(sr)
-	ts := hud.NewTerminalStream(hud.NewIncrementalPrinter(log), st)
+	ts := hud.NewTerminalStream(hud.NewIncrementalPrinter(log), st, hud.LogFormatText, logstore.Timestamps{})
 	tp := prompt.NewTerminalPrompt(ta, prompt.TTYOpen, openurl.BrowserOpen,
 		log, "localhost", model.WebURL{})
 	h := hud.NewFakeHud()
@@ -3266,7 +3297,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	dcds := dockercomposeservice.NewDisableSubscriber(ctx, fakeDcc, clock)
 	dcr := dockercomposeservice.NewReconciler(cdc, fakeDcc, dockerClient, st, sch, dcds)
 
-	tfr := ctrltiltfile.NewReconciler(st, tfl, dockerClient, cdc, sch, engineMode, "", "", 0)
+	tfr := ctrltiltfile.NewReconciler(st, tfl, dockerClient, cdc, sch, engineMode, "", "", 0, base, devenv.Environment{})
 	tbr := togglebutton.NewReconciler(cdc, sch)
 	extr := extension.NewReconciler(cdc, sch, ta)
 	extrr, err := extensionrepo.NewReconciler(cdc, st, base)
@@ -3287,6 +3318,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 		wsl, base, "tilt-default")
 	dclsr := dockercomposelogstream.NewReconciler(cdc, st, fakeDcc, dockerClient)
 
+	lsf := logsink.NewForwarder()
 	cb := controllers.NewControllerBuilder(tscm, controllers.ProvideControllers(
 		fwc,
 		cmds,
@@ -3310,13 +3342,18 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 		imagemap.NewReconciler(cdc, st),
 		dclsr,
 		sr,
-	))
+		notification.NewReconciler(cdc, clock, model.WebURL{}, func(context.Context, string, string) error { return nil }),
+		resourceevent.NewReconciler(cdc, clock),
+		alertrule.NewReconciler(cdc, clock),
+		healthprobe.NewReconciler(ctx, cdc, st, fpm, clock),
+		logsink.NewReconciler(cdc, lsf),
+	), nil)
 
 	dp := dockerprune.NewDockerPruner(dockerClient)
 	dp.DisabledForTesting(true)
 
 	b := newFakeBuildAndDeployer(t, kClient, fakeDcc, cdc, kar, dcr)
-	bc := NewBuildController(b)
+	bc := NewBuildController(b, tracer.NewNoopPipelineTracer())
 
 	ret := &testFixture{
 		TempDirFixture:        f,
@@ -3356,8 +3393,12 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	uss := uisession.NewSubscriber(cdc)
 	urs := uiresource.NewSubscriber(cdc)
+	bhs := buildhistory.NewSubscriber(cdc, base)
+	cps := checkpoint.NewSubscriber(base)
+	ms := metrics.NewSubscriber()
+	lhs := loghistory.NewSubscriber(base, "tilt-default", false)
 
-	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs)
+	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, rs, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs, bhs, cps, ms, lhs, lsf)
 	ret.upper, err = NewUpper(ctx, st, subs)
 	require.NoError(t, err)
 
@@ -11,12 +11,16 @@ import (
 
 	"github.com/gdamore/tcell"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/hud/view"
 	"github.com/tilt-dev/tilt/internal/openurl"
 	"github.com/tilt-dev/tilt/internal/output"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -28,16 +32,26 @@ const DefaultRefreshInterval = 100 * time.Millisecond
 // (we don't currently worry about trying to know how big a page is, and instead just support pgup/dn as "faster arrows"
 const pgUpDownCount = 20
 
+// number of arrows a turn of the mouse wheel is equivalent to
+const wheelScrollCount = 3
+
 type HeadsUpDisplay interface {
 	store.Subscriber
 
 	Run(ctx context.Context, dispatch func(action store.Action), refreshRate time.Duration) error
 }
 
+// Restarts what a resource is running, without rebuilding it.
+type ResourceRestarter interface {
+	RestartResource(ctx context.Context, mn model.ManifestName) error
+}
+
 type Hud struct {
-	r       *Renderer
-	webURL  model.WebURL
-	openurl openurl.OpenURL
+	r          *Renderer
+	webURL     model.WebURL
+	openurl    openurl.OpenURL
+	ctrlClient ctrlclient.Client
+	restarter  ResourceRestarter
 
 	currentView      view.View
 	currentViewState view.ViewState
@@ -49,12 +63,15 @@ type Hud struct {
 
 var _ HeadsUpDisplay = (*Hud)(nil)
 
-func NewHud(renderer *Renderer, webURL model.WebURL, analytics *analytics.TiltAnalytics, openurl openurl.OpenURL) HeadsUpDisplay {
+func NewHud(renderer *Renderer, webURL model.WebURL, analytics *analytics.TiltAnalytics, openurl openurl.OpenURL,
+	ctrlClient ctrlclient.Client, restarter ResourceRestarter) HeadsUpDisplay {
 	return &Hud{
-		r:       renderer,
-		webURL:  webURL,
-		a:       analytics,
-		openurl: openurl,
+		r:          renderer,
+		webURL:     webURL,
+		a:          analytics,
+		openurl:    openurl,
+		ctrlClient: ctrlClient,
+		restarter:  restarter,
 	}
 }
 
@@ -178,6 +195,20 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 				_, selected := h.selectedResource()
 				h.recordInteraction("trigger_resource")
 				dispatch(store.AppendToTriggerQueueAction{Name: selected.Name, Reason: model.BuildReasonFlagTriggerHUD})
+			case r == 'r': // [R]estart resource
+				_, selected := h.selectedResource()
+				if selected.Name == "" || selected.IsTiltfile {
+					break
+				}
+				h.recordInteraction("restart_resource")
+				go h.restartResource(ctx, selected.Name)
+			case r == 'd': // [D]isable resource
+				_, selected := h.selectedResource()
+				if selected.Name == "" || selected.IsTiltfile {
+					break
+				}
+				h.recordInteraction("disable_resource")
+				go h.disableResource(ctx, selected.Name)
 			case r == 'x':
 				h.recordInteraction("cycle_view_log_state")
 				h.currentViewState.CycleViewLogState()
@@ -245,6 +276,20 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 			go writeHeapProfile(ctx)
 		}
 
+	case *tcell.EventMouse:
+		switch ev.Buttons() {
+		case tcell.WheelUp:
+			for i := 0; i < wheelScrollCount; i++ {
+				h.activeScroller().Up()
+			}
+			h.refreshSelectedIndex()
+		case tcell.WheelDown:
+			for i := 0; i < wheelScrollCount; i++ {
+				h.activeScroller().Down()
+			}
+			h.refreshSelectedIndex()
+		}
+
 	case *tcell.EventResize:
 		// since we already refresh after the switch, don't need to do anything here
 		// just marking this as where sigwinch gets handled
@@ -254,6 +299,34 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 	return false
 }
 
+// Runs outside the event loop, because it talks to the cluster.
+func (h *Hud) restartResource(ctx context.Context, name model.ManifestName) {
+	err := h.restarter.RestartResource(ctx, name)
+	if err != nil {
+		h.showAlert(ctx, fmt.Sprintf("error restarting resource '%s': %v", name, err))
+	}
+}
+
+// Disabled resources aren't shown in the terminal UI, so they have to be
+// re-enabled from the web UI or with `tilt enable`.
+func (h *Hud) disableResource(ctx context.Context, name model.ManifestName) {
+	var uir v1alpha1.UIResource
+	err := h.ctrlClient.Get(ctx, types.NamespacedName{Name: name.String()}, &uir)
+	if err == nil {
+		err = configmap.SetResourceEnabled(ctx, h.ctrlClient, uir, false)
+	}
+	if err != nil {
+		h.showAlert(ctx, fmt.Sprintf("error disabling resource '%s': %v", name, err))
+	}
+}
+
+func (h *Hud) showAlert(ctx context.Context, msg string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.currentViewState.AlertMessage = msg
+	h.refresh(ctx)
+}
+
 func (h *Hud) isEnabled(st store.RStore) bool {
 	state := st.RLockState()
 	defer st.RUnlockState()
@@ -2,14 +2,19 @@ package hud
 
 import (
 	"bytes"
+	"context"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gdamore/tcell"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/tilt-dev/tilt/internal/hud/view"
 	"github.com/tilt-dev/tilt/internal/openurl"
 	"github.com/tilt-dev/tilt/internal/rty"
+	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -22,6 +27,43 @@ func TestRenderInit(t *testing.T) {
 	r := NewRenderer(clockForTest)
 	r.rty = rty.NewRTY(tcell.NewSimulationScreen(""), t)
 	webURL, _ := url.Parse("http://localhost:10350")
-	hud := NewHud(r, model.WebURL(*webURL), ta, openurl.BrowserOpen)
+	hud := NewHud(r, model.WebURL(*webURL), ta, openurl.BrowserOpen, nil, nil)
 	hud.(*Hud).refresh(ctx) // Ensure we render without error
 }
+
+func TestRestartKey(t *testing.T) {
+	ctx, _, ta := testutils.CtxAndAnalyticsForTest()
+
+	r := NewRenderer(time.Now)
+	r.rty = rty.NewRTY(tcell.NewSimulationScreen(""), t)
+	restarter := &fakeRestarter{}
+	hud := NewHud(r, model.WebURL{}, ta, openurl.BrowserOpen, nil, restarter).(*Hud)
+	hud.currentView = view.View{Resources: []view.Resource{
+		{Name: MainTiltfileManifestName, IsTiltfile: true},
+		{Name: "fe"},
+	}}
+	hud.currentViewState.SelectedIndex = 1
+
+	hud.handleScreenEvent(ctx, func(action store.Action) {}, tcell.NewEventKey(tcell.KeyRune, 'r', tcell.ModNone))
+	assert.Eventually(t, func() bool {
+		return restarter.restarted() == "fe"
+	}, time.Second, 10*time.Millisecond)
+}
+
+type fakeRestarter struct {
+	mu   sync.Mutex
+	name model.ManifestName
+}
+
+func (f *fakeRestarter) RestartResource(ctx context.Context, mn model.ManifestName) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.name = mn
+	return nil
+}
+
+func (f *fakeRestarter) restarted() model.ManifestName {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.name
+}
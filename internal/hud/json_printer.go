@@ -0,0 +1,153 @@
+package hud
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// How logs are printed to stdout.
+type LogFormat string
+
+const (
+	// Lines prefixed with the resource name, like the terminal UI.
+	LogFormatText LogFormat = "text"
+
+	// One JSON object per line, so tools like jq or a log shipper
+	// can tell which resource logged it.
+	LogFormatJSON LogFormat = "json"
+)
+
+var LogFormats = []LogFormat{LogFormatText, LogFormatJSON}
+
+func ParseLogFormat(s string) (LogFormat, error) {
+	for _, f := range LogFormats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid log format %q: must be one of %v", s, LogFormats)
+}
+
+// One log line in the JSON log format.
+//
+//	{"time": "2021-03-01T12:00:00Z", "resource": "api", "spanId": "pod:api-1",
+//	 "level": "info", "text": "listening on :8080"}
+//
+// Logs that don't belong to any resource leave out the resource.
+type JSONLogLine struct {
+	Time     time.Time `json:"time"`
+	Resource string    `json:"resource,omitempty"`
+	SpanID   string    `json:"spanId"`
+	Level    string    `json:"level"`
+	Text     string    `json:"text"`
+}
+
+// Assembles log segments into lines for the JSON log format.
+//
+// A segment may hold part of a line, so each span's text is held until
+// its line is complete. The line takes the time, level, and resource of
+// its first segment.
+type JSONLineBuilder struct {
+	pending map[logstore.SpanID]*JSONLogLine
+
+	// Spans with pending text, in the order their lines started.
+	order []logstore.SpanID
+}
+
+func NewJSONLineBuilder() *JSONLineBuilder {
+	return &JSONLineBuilder{
+		pending: make(map[logstore.SpanID]*JSONLogLine),
+	}
+}
+
+// Adds the segments, and returns the lines they completed.
+func (b *JSONLineBuilder) Add(segments []logstore.StreamSegment) []JSONLogLine {
+	var result []JSONLogLine
+	for _, seg := range segments {
+		text := string(seg.Text)
+		for text != "" {
+			line, ok := b.pending[seg.SpanID]
+			if !ok {
+				level := seg.Level.Name()
+				if level == "" {
+					level = "info"
+				}
+				line = &JSONLogLine{
+					Time:     seg.Time,
+					Resource: seg.ManifestName.String(),
+					SpanID:   string(seg.SpanID),
+					Level:    level,
+				}
+				b.pending[seg.SpanID] = line
+				b.order = append(b.order, seg.SpanID)
+			}
+
+			i := strings.IndexByte(text, '\n')
+			if i == -1 {
+				line.Text += text
+				break
+			}
+			line.Text += text[:i]
+			text = text[i+1:]
+			result = append(result, b.take(seg.SpanID))
+		}
+	}
+	return result
+}
+
+// Returns the lines that haven't been completed yet, like when Tilt exits.
+func (b *JSONLineBuilder) Flush() []JSONLogLine {
+	var result []JSONLogLine
+	for len(b.order) > 0 {
+		result = append(result, b.take(b.order[0]))
+	}
+	return result
+}
+
+func (b *JSONLineBuilder) take(spanID logstore.SpanID) JSONLogLine {
+	line := b.pending[spanID]
+	delete(b.pending, spanID)
+	for i, id := range b.order {
+		if id == spanID {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			break
+		}
+	}
+	return *line
+}
+
+// Prints log segments in the JSON log format.
+type JSONLinePrinter struct {
+	stdout  Stdout
+	builder *JSONLineBuilder
+}
+
+func NewJSONLinePrinter(stdout Stdout) *JSONLinePrinter {
+	return &JSONLinePrinter{
+		stdout:  stdout,
+		builder: NewJSONLineBuilder(),
+	}
+}
+
+func (p *JSONLinePrinter) Print(segments []logstore.StreamSegment) {
+	p.print(p.builder.Add(segments))
+}
+
+// Prints the lines that haven't been completed yet, like when Tilt exits.
+func (p *JSONLinePrinter) Flush() {
+	p.print(p.builder.Flush())
+}
+
+func (p *JSONLinePrinter) print(lines []JSONLogLine) {
+	for _, line := range lines {
+		b, err := json.Marshal(line)
+		if err != nil {
+			continue
+		}
+		_, _ = p.stdout.Write(append(b, '\n'))
+	}
+}
@@ -0,0 +1,68 @@
+package hud
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+func TestJSONLinePrinter(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewJSONLinePrinter(Stdout(out))
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	p.Print([]logstore.StreamSegment{
+		segment("fe", "pod:fe", logger.InfoLvl, now, "listening"),
+		segment("", "tiltfile", logger.WarnLvl, now, "slow\nreload\n"),
+	})
+	assert.Equal(t,
+		`{"time":"2024-03-01T09:00:00Z","spanId":"tiltfile","level":"warn","text":"slow"}`+"\n"+
+			`{"time":"2024-03-01T09:00:00Z","spanId":"tiltfile","level":"warn","text":"reload"}`+"\n",
+		out.String())
+
+	// The partial line is held until it's complete.
+	out.Reset()
+	p.Print([]logstore.StreamSegment{
+		segment("fe", "pod:fe", logger.InfoLvl, now.Add(time.Second), " on :8080\n"),
+	})
+	assert.Equal(t,
+		`{"time":"2024-03-01T09:00:00Z","resource":"fe","spanId":"pod:fe","level":"info","text":"listening on :8080"}`+"\n",
+		out.String())
+
+	out.Reset()
+	p.Print([]logstore.StreamSegment{
+		segment("be", "pod:be", logger.ErrorLvl, now, "crashed"),
+	})
+	assert.Equal(t, "", out.String())
+	p.Flush()
+	assert.Equal(t,
+		`{"time":"2024-03-01T09:00:00Z","resource":"be","spanId":"pod:be","level":"error","text":"crashed"}`+"\n",
+		out.String())
+}
+
+func TestParseLogFormat(t *testing.T) {
+	f, err := ParseLogFormat("json")
+	assert.NoError(t, err)
+	assert.Equal(t, LogFormatJSON, f)
+
+	_, err = ParseLogFormat("yaml")
+	assert.EqualError(t, err, `invalid log format "yaml": must be one of [text json]`)
+}
+
+func segment(mn model.ManifestName, spanID logstore.SpanID, level logger.Level, ts time.Time, text string) logstore.StreamSegment {
+	return logstore.StreamSegment{
+		LogSegment: logstore.LogSegment{
+			SpanID: spanID,
+			Time:   ts,
+			Text:   []byte(text),
+			Level:  level,
+		},
+		ManifestName: mn,
+	}
+}
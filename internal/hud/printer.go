@@ -24,6 +24,12 @@ func NewIncrementalPrinter(stdout Stdout) *IncrementalPrinter {
 	}
 }
 
+// Where the lines are printed, for printers that print
+// the same logs in another format.
+func (p *IncrementalPrinter) Stdout() Stdout {
+	return p.stdout
+}
+
 func (p *IncrementalPrinter) PrintNewline() {
 	_, _ = io.WriteString(p.stdout, "\n")
 }
@@ -190,7 +190,7 @@ func (r *Renderer) renderFooter(v view.View, keys string) rty.Component {
 }
 
 func keyLegend(v view.View, vs view.ViewState) string {
-	defaultKeys := "Browse (↓ ↑), Expand (→) ┊ (enter) log ┊ (ctrl-C) quit  "
+	defaultKeys := "Browse (↓ ↑), Expand (→) ┊ (t)rigger ┊ (r)estart ┊ (d)isable ┊ (enter) log ┊ (ctrl-C) quit  "
 	if vs.AlertMessage != "" {
 		return "Tilt (l)og ┊ (esc) close alert "
 	}
@@ -287,6 +287,7 @@ func (r *Renderer) SetUp() (chan tcell.Event, error) {
 	if err = screen.Init(); err != nil {
 		return nil, err
 	}
+	screen.EnableMouse()
 	screenEvents := make(chan tcell.Event)
 	go func() {
 		for {
@@ -11,3 +11,18 @@ type OverrideTriggerModeAction struct {
 }
 
 func (OverrideTriggerModeAction) Action() {}
+
+type SetResourceGroupsCollapsedAction struct {
+	Paths     []string
+	Collapsed bool
+}
+
+func (SetResourceGroupsCollapsedAction) Action() {}
+
+// Adds resources to the trigger queue in one step, in the given order.
+type BulkTriggerAction struct {
+	ManifestNames []model.ManifestName
+	Reason        model.BuildReason
+}
+
+func (BulkTriggerAction) Action() {}
@@ -136,9 +136,7 @@ func ProvideTiltServerOptions(
 		WithBearerToken(string(token)).
 		WithCertKey(certKey)
 
-	for _, obj := range v1alpha1.AllResourceObjects() {
-		builder = builder.WithResourceMemoryStorage(obj, "data")
-	}
+	builder = withDryRunMemoryStorage(builder, v1alpha1.AllResourceObjects(), "data")
 	builder = builder.WithOpenAPIDefinitions("tilt", tiltBuild.Version, openapi.GetOpenAPIDefinitions)
 
 	if apiPort == 0 {
@@ -15,12 +15,14 @@ import (
 	"testing"
 	"time"
 
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/apiserver"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -28,10 +30,13 @@ import (
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/testdata"
+	"github.com/tilt-dev/tilt/internal/apitoken"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -89,6 +94,43 @@ func TestAPIServerDynamicClient(t *testing.T) {
 				},
 			},
 		},
+		"Notification": map[string]interface{}{
+			"url": "https://hooks.slack.com/services/xyz",
+		},
+		"ResourceEvent": map[string]interface{}{
+			"type":     "BuildStarted",
+			"resource": "my-resource",
+		},
+		"UIPanel": map[string]interface{}{
+			"title": "Database",
+			"url":   "http://localhost:5050",
+			"location": map[string]interface{}{
+				"componentType": "Global",
+				"componentID":   "nav",
+			},
+		},
+		"UIPreferences": map[string]interface{}{
+			"user": "dev@example.com",
+			"values": map[string]interface{}{
+				"starred": `["fe"]`,
+			},
+		},
+		"AlertRule": map[string]interface{}{
+			"metric":   "BuildDuration",
+			"duration": "2m",
+		},
+		"HealthProbe": map[string]interface{}{
+			"resource": "fe",
+			"probe": map[string]interface{}{
+				"tcpSocket": map[string]interface{}{
+					"port": 8080,
+				},
+			},
+		},
+		"LogSink": map[string]interface{}{
+			"type":     "loki",
+			"endpoint": "http://localhost:3100",
+		},
 		"ToggleButton": map[string]interface{}{
 			"stateSource": map[string]interface{}{
 				"configMap": map[string]interface{}{
@@ -135,23 +177,136 @@ func TestAPIServerDynamicClient(t *testing.T) {
 	}
 }
 
+func TestAPIServerDryRun(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	cmds := f.dynamic.Resource((&v1alpha1.Cmd{}).GetGroupVersionResource())
+	cmd := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "Cmd",
+			"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+			"metadata":   map[string]interface{}{"name": "my-sleep"},
+			"spec":       map[string]interface{}{"args": []interface{}{"sleep", "100"}},
+		},
+	}
+	dryRun := []string{metav1.DryRunAll}
+
+	_, err := cmds.Create(f.ctx, cmd, metav1.CreateOptions{DryRun: dryRun})
+	require.NoError(t, err)
+	_, err = cmds.Get(f.ctx, "my-sleep", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "dry-run create saved the object: %v", err)
+
+	created, err := cmds.Create(f.ctx, cmd, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	err = unstructured.SetNestedStringSlice(created.Object, []string{"sleep", "200"}, "spec", "args")
+	require.NoError(t, err)
+	updated, err := cmds.Update(f.ctx, created, metav1.UpdateOptions{DryRun: dryRun})
+	require.NoError(t, err)
+	args, _, _ := unstructured.NestedStringSlice(updated.Object, "spec", "args")
+	assert.Equal(t, []string{"sleep", "200"}, args)
+
+	// Invalid objects are still rejected.
+	buttons := f.dynamic.Resource((&v1alpha1.UIButton{}).GetGroupVersionResource())
+	_, err = buttons.Create(f.ctx, &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "UIButton",
+			"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+			"metadata":   map[string]interface{}{"name": "my-button"},
+			"spec":       map[string]interface{}{"text": "click me"},
+		},
+	}, metav1.CreateOptions{DryRun: dryRun})
+	require.True(t, apierrors.IsInvalid(err), "expected invalid, got: %v", err)
+	assert.Contains(t, err.Error(), "spec.location.componentID: Required value")
+
+	err = cmds.Delete(f.ctx, "my-sleep", metav1.DeleteOptions{DryRun: dryRun})
+	require.NoError(t, err)
+
+	current, err := cmds.Get(f.ctx, "my-sleep", metav1.GetOptions{})
+	require.NoError(t, err)
+	args, _, _ = unstructured.NestedStringSlice(current.Object, "spec", "args")
+	assert.Equal(t, []string{"sleep", "100"}, args)
+	assert.Equal(t, created.GetResourceVersion(), current.GetResourceVersion())
+}
+
 func TestAPIServerProxy(t *testing.T) {
 	f := newAPIServerFixture(t)
 	f.start()
 
-	reqURL := fmt.Sprintf("http://%s/proxy/apis/tilt.dev/v1alpha1/uibuttons", f.webListener.Addr())
-	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, reqURL, nil)
-	require.NoError(t, err, "Failed to create request")
+	body := f.proxyGet("uibuttons")
+	// don't care about the full body of the response, but it should at least have
+	// "kind": "UIButtonList" so look for that as a magic word
+	require.Contains(t, body, "UIButtonList")
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err, "Request failed")
+func TestAPIServerProxyBuildHistories(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	body := f.proxyGet("buildhistories")
+	require.Contains(t, body, "BuildHistoryList")
+}
+
+func TestAPIServerProxyUIPanels(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	body := f.proxyGet("uipanels")
+	require.Contains(t, body, "UIPanelList")
+}
+
+func TestAPIServerProxyAlertRules(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	body := f.proxyGet("alertrules")
+	require.Contains(t, body, "AlertRuleList")
+}
+
+func TestAPIServerExtProxy(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	path := "/apis/tilt.dev/v1alpha1/uibuttons"
+	resp := f.extProxyRequest(http.MethodGet, path, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	viewer, err := f.apiTokens.Create("viewer", string(TeamRoleViewer), time.Now())
+	require.NoError(t, err)
+	resp = f.extProxyRequest(http.MethodGet, path, viewer)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "UIButtonList")
+
+	resp = f.extProxyRequest(http.MethodDelete, path+"/my-button", viewer)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	owner, err := f.apiTokens.Create("owner", string(TeamRoleOwner), time.Now())
+	require.NoError(t, err)
+	resp = f.extProxyRequest(http.MethodDelete, path+"/my-button", owner)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestAPIServerMetrics(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	metrics.BuildsTotal.WithLabelValues("metrics-fe", metrics.ResultSuccess).Inc()
+
+	config := f.serverConfig.GenericConfig.LoopbackClientConfig
+	client, err := rest.HTTPClientFor(config)
+	require.NoError(t, err)
+
+	resp, err := client.Get(config.Host + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 
 	body, err := io.ReadAll(resp.Body)
-	require.NoError(t, err, "Failed to read response body")
-	// don't care about the full body of the response, but it should at least have
-	// "kind": "UIButtonList" so look for that as a magic word
-	require.Contains(t, string(body), "UIButtonList")
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `tilt_builds_total{resource="metrics-fe",result="success"} 1`)
 }
 
 func mustCwd(t testing.TB) string {
@@ -174,6 +329,7 @@ type apiserverFixture struct {
 	webURL          model.WebURL
 	st              *store.TestingStore
 	dynamic         DynamicInterface
+	apiTokens       *apitoken.Store
 }
 
 func newAPIServerFixture(t testing.TB) *apiserverFixture {
@@ -225,6 +381,7 @@ func newAPIServerFixture(t testing.TB) *apiserverFixture {
 		webURL:          model.WebURL(*webURL),
 		st:              store.NewTestingStore(),
 		dynamic:         dynamic,
+		apiTokens:       apitoken.NewStore(xdg.FakeBase{Dir: tmpdir.Path()}),
 	}
 	return f
 }
@@ -232,10 +389,43 @@ func newAPIServerFixture(t testing.TB) *apiserverFixture {
 func (f *apiserverFixture) start() *HeadsUpServerController {
 	f.t.Helper()
 	hudsc := ProvideHeadsUpServerController(f.configAccess, "tilt-default",
-		f.webListener, f.serverConfig, &HeadsUpServer{}, assets.NewFakeServer(), f.webURL)
+		f.webListener, f.serverConfig, &HeadsUpServer{apiTokens: f.apiTokens}, assets.NewFakeServer(), f.webURL)
 	require.NoError(f.t, hudsc.SetUp(f.ctx, f.st))
 	f.t.Cleanup(func() {
 		hudsc.TearDown(f.ctx)
 	})
 	return hudsc
 }
+
+func (f *apiserverFixture) extProxyRequest(method, path, token string) *http.Response {
+	f.t.Helper()
+	reqURL := fmt.Sprintf("http://%s%s%s", f.webListener.Addr(), extAPIServerPrefix, path)
+	req, err := http.NewRequestWithContext(f.ctx, method, reqURL, nil)
+	require.NoError(f.t, err, "Failed to create request")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(f.t, err, "Request failed")
+	f.t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+	return resp
+}
+
+func (f *apiserverFixture) proxyGet(resource string) string {
+	f.t.Helper()
+	reqURL := fmt.Sprintf("http://%s/proxy/apis/tilt.dev/v1alpha1/%s", f.webListener.Addr(), resource)
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, reqURL, nil)
+	require.NoError(f.t, err, "Failed to create request")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(f.t, err, "Request failed")
+	defer resp.Body.Close()
+	require.Equal(f.t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(f.t, err, "Failed to read response body")
+	return string(body)
+}
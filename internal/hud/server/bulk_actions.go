@@ -0,0 +1,226 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Bulk actions trigger, enable, or disable many resources at once,
+// picked by name, by label, or both.
+//
+//	POST /api/bulk {"action": "trigger", "resources": [NAME...], "labels": [LABEL...]}
+//
+// The external API serves the same endpoint at /api/ext/v1/bulk.
+//
+// We act on resources in dependency order: dependencies get triggered and
+// enabled before the resources that need them, and disabled after.
+const bulkActionPath = "/api/bulk"
+
+type BulkAction string
+
+const (
+	BulkActionTrigger BulkAction = "trigger"
+	BulkActionEnable  BulkAction = "enable"
+	BulkActionDisable BulkAction = "disable"
+)
+
+type bulkActionPayload struct {
+	Action    BulkAction `json:"action"`
+	Resources []string   `json:"resources"`
+	Labels    []string   `json:"labels"`
+}
+
+type bulkActionSkip struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type bulkActionResult struct {
+	Action BulkAction `json:"action"`
+
+	// The resources we acted on, in the order we acted on them.
+	Resources []string `json:"resources"`
+
+	// Resources that were selected, but that the action doesn't apply to,
+	// like disabled resources for a trigger.
+	Skipped []bulkActionSkip `json:"skipped"`
+}
+
+// Responds with:
+// * 202/a bulkActionResult when the action was applied
+// * 400/error message on badly formed requests (e.g., an unknown action)
+// * 403/error message if the user may trigger, but not enable or disable
+// * 404/error message if a named resource doesn't exist, or nothing matched
+func (s *HeadsUpServer) HandleBulkAction(w http.ResponseWriter, req *http.Request) {
+	var payload bulkActionPayload
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		writeExtError(w, http.StatusBadRequest, fmt.Sprintf("error parsing JSON payload: %v", err))
+		return
+	}
+
+	switch payload.Action {
+	case BulkActionTrigger, BulkActionEnable, BulkActionDisable:
+	default:
+		writeExtError(w, http.StatusBadRequest, fmt.Sprintf("invalid action %q: must be one of %s, %s, or %s",
+			payload.Action, BulkActionTrigger, BulkActionEnable, BulkActionDisable))
+		return
+	}
+	if payload.Action != BulkActionTrigger {
+		id := teamIdentityFromRequest(req)
+		if !id.Role.Allows(TeamPermissionEnable) {
+			writeExtError(w, http.StatusForbidden, fmt.Sprintf("Forbidden: you have %s access, which can't %s resources", id.Role, payload.Action))
+			return
+		}
+	}
+	if len(payload.Resources) == 0 && len(payload.Labels) == 0 {
+		writeExtError(w, http.StatusBadRequest, "must specify at least one resource or label")
+		return
+	}
+
+	ctx := req.Context()
+	var list v1alpha1.UIResourceList
+	err = s.ctrlClient.List(ctx, &list)
+	if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	selected, err := selectBulkResources(list.Items, payload.Resources, payload.Labels)
+	if err != nil {
+		writeExtError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	state := s.store.RLockState()
+	order := dependencyOrder(selected, func(mn model.ManifestName) []model.ManifestName {
+		mt, ok := state.ManifestTargets[mn]
+		if !ok {
+			return nil
+		}
+		return mt.Manifest.ResourceDependencies
+	})
+	s.store.RUnlockState()
+	if payload.Action == BulkActionDisable {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	result := bulkActionResult{Action: payload.Action, Resources: []string{}, Skipped: []bulkActionSkip{}}
+	var targets []v1alpha1.UIResource
+	for _, mn := range order {
+		uir := selected[mn]
+		disabled := uir.Status.DisableStatus.State == v1alpha1.DisableStateDisabled
+		skip := ""
+		switch {
+		case payload.Action == BulkActionTrigger && disabled:
+			skip = "disabled"
+		case payload.Action != BulkActionTrigger && len(uir.Status.DisableStatus.Sources) == 0:
+			skip = fmt.Sprintf("cannot be %sd", payload.Action)
+		}
+		if skip != "" {
+			result.Skipped = append(result.Skipped, bulkActionSkip{Name: uir.Name, Reason: skip})
+			continue
+		}
+		targets = append(targets, uir)
+		result.Resources = append(result.Resources, uir.Name)
+	}
+
+	if payload.Action == BulkActionTrigger {
+		names := make([]model.ManifestName, 0, len(targets))
+		for _, uir := range targets {
+			names = append(names, model.ManifestName(uir.Name))
+		}
+		reason := model.BuildReasonFlagTriggerWeb
+		if strings.HasPrefix(req.URL.Path, ExternalAPIPrefix+"/") {
+			reason = model.BuildReasonFlagTriggerAPI
+		}
+		// One action, so that the build controller sees the whole batch
+		// at once instead of starting on the first resource alone.
+		s.store.Dispatch(BulkTriggerAction{ManifestNames: names, Reason: reason})
+	} else {
+		err := configmap.SetResourcesEnabled(ctx, s.ctrlClient, targets, payload.Action == BulkActionEnable)
+		if err != nil {
+			writeExtError(w, http.StatusInternalServerError, fmt.Sprintf("%s %v", payload.Action, err))
+			return
+		}
+	}
+
+	writeExtJSON(w, http.StatusAccepted, result)
+}
+
+// Picks the resources with any of the given names or labels.
+// Every name must exist, and the selection can't be empty.
+func selectBulkResources(resources []v1alpha1.UIResource, names []string, labels []string) (map[model.ManifestName]v1alpha1.UIResource, error) {
+	byName := make(map[string]v1alpha1.UIResource, len(resources))
+	for _, uir := range resources {
+		byName[uir.Name] = uir
+	}
+
+	selected := make(map[model.ManifestName]v1alpha1.UIResource)
+	for _, name := range names {
+		uir, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("resource %q does not exist", name)
+		}
+		selected[model.ManifestName(name)] = uir
+	}
+	for _, uir := range resources {
+		for _, label := range labels {
+			if _, ok := uir.Labels[label]; ok {
+				selected[model.ManifestName(uir.Name)] = uir
+				break
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no resources have labels %v", labels)
+	}
+	return selected, nil
+}
+
+// Sorts the selected resources so that each one comes after everything it
+// depends on, even through resources that weren't selected.
+// Resources that don't depend on each other are sorted by name.
+func dependencyOrder(selected map[model.ManifestName]v1alpha1.UIResource, deps func(mn model.ManifestName) []model.ManifestName) []model.ManifestName {
+	names := make([]model.ManifestName, 0, len(selected))
+	for mn := range selected {
+		names = append(names, mn)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	result := make([]model.ManifestName, 0, len(names))
+	visited := make(map[model.ManifestName]bool)
+	var visit func(mn model.ManifestName)
+	visit = func(mn model.ManifestName) {
+		// The Tiltfile rejects dependency cycles, so marking resources
+		// before we visit their dependencies only matters for safety.
+		if visited[mn] {
+			return
+		}
+		visited[mn] = true
+		for _, dep := range deps(mn) {
+			visit(dep)
+		}
+		if _, ok := selected[mn]; ok {
+			result = append(result, mn)
+		}
+	}
+	for _, mn := range names {
+		visit(mn)
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type bulkResponse struct {
+	Action    string   `json:"action"`
+	Resources []string `json:"resources"`
+	Skipped   []struct {
+		Name   string `json:"name"`
+		Reason string `json:"reason"`
+	} `json:"skipped"`
+}
+
+func TestBulkTriggerInDependencyOrder(t *testing.T) {
+	f := newTestFixture(t)
+	// web depends on api, which depends on db (through an unselected cache).
+	f.withManifestDeps(map[string][]string{
+		"web":   {"api"},
+		"api":   {"cache"},
+		"cache": {"db"},
+		"db":    nil,
+	})
+	f.createLabeledUIResource("web", "app", false)
+	f.createLabeledUIResource("api", "app", false)
+	f.createLabeledUIResource("db", "infra", false)
+	f.createLabeledUIResource("cache", "infra", true)
+
+	status, body := f.bulkReq(`{"action": "trigger", "resources": ["web", "db"], "labels": ["app"]}`)
+	require.Equal(t, http.StatusAccepted, status, body)
+
+	var resp bulkResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, []string{"db", "api", "web"}, resp.Resources)
+	assert.Empty(t, resp.Skipped)
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.BulkTriggerAction{}), f.getActions)
+	assert.Equal(t, server.BulkTriggerAction{
+		ManifestNames: []model.ManifestName{"db", "api", "web"},
+		Reason:        model.BuildReasonFlagTriggerWeb,
+	}, a)
+
+	// Disabled resources are skipped.
+	status, body = f.bulkReq(`{"action": "trigger", "labels": ["infra"]}`)
+	require.Equal(t, http.StatusAccepted, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, []string{"db"}, resp.Resources)
+	require.Len(t, resp.Skipped, 1)
+	assert.Equal(t, "cache", resp.Skipped[0].Name)
+	assert.Equal(t, "disabled", resp.Skipped[0].Reason)
+}
+
+func TestBulkDisableInReverseDependencyOrder(t *testing.T) {
+	f := newTestFixture(t)
+	f.withManifestDeps(map[string][]string{
+		"web": {"api"},
+		"api": nil,
+	})
+	f.createLabeledUIResource("web", "app", false)
+	f.createLabeledUIResource("api", "app", false)
+	f.createUIResource("(Tiltfile)", v1alpha1.UIResourceStatus{})
+
+	status, body := f.bulkReq(`{"action": "disable", "resources": ["(Tiltfile)"], "labels": ["app"]}`)
+	require.Equal(t, http.StatusAccepted, status, body)
+
+	var resp bulkResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, []string{"web", "api"}, resp.Resources)
+	require.Len(t, resp.Skipped, 1)
+	assert.Equal(t, "(Tiltfile)", resp.Skipped[0].Name)
+	assert.Equal(t, "true", f.configMapValue("web-disable", "isDisabled"))
+	assert.Equal(t, "true", f.configMapValue("api-disable", "isDisabled"))
+
+	status, body = f.bulkReq(`{"action": "enable", "labels": ["app"]}`)
+	require.Equal(t, http.StatusAccepted, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, []string{"api", "web"}, resp.Resources)
+	assert.Equal(t, "false", f.configMapValue("web-disable", "isDisabled"))
+	assert.Equal(t, "false", f.configMapValue("api-disable", "isDisabled"))
+}
+
+func TestBulkActionBadRequests(t *testing.T) {
+	f := newTestFixture(t)
+	f.createLabeledUIResource("web", "app", false)
+
+	for _, tc := range []struct {
+		body   string
+		status int
+	}{
+		{`{"action": "restart", "resources": ["web"]}`, http.StatusBadRequest},
+		{`{"action": "trigger"}`, http.StatusBadRequest},
+		{`{"action": "trigger", "resources": ["web"], "extra": 1}`, http.StatusBadRequest},
+		{`{"action": "trigger", "resources": ["api"]}`, http.StatusNotFound},
+		{`{"action": "trigger", "labels": ["infra"]}`, http.StatusNotFound},
+	} {
+		status, _ := f.bulkReq(tc.body)
+		assert.Equal(t, tc.status, status, tc.body)
+	}
+}
+
+func TestExtAPIBulkAction(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("web")
+	f.createLabeledUIResource("web", "app", false)
+	token := f.createAPIToken()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ext/v1/bulk", strings.NewReader(`{"action": "trigger", "labels": ["app"]}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.BulkTriggerAction{}), f.getActions)
+	assert.Equal(t, model.BuildReasonFlagTriggerAPI, a.(server.BulkTriggerAction).Reason)
+}
+
+func (f *serverFixture) withManifestDeps(deps map[string][]string) {
+	state := f.st.LockMutableStateForTesting()
+	defer f.st.UnlockMutableState()
+	for name, depNames := range deps {
+		m := model.Manifest{Name: model.ManifestName(name)}
+		for _, dep := range depNames {
+			m.ResourceDependencies = append(m.ResourceDependencies, model.ManifestName(dep))
+		}
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	}
+}
+
+func (f *serverFixture) createLabeledUIResource(name, label string, disabled bool) {
+	state := v1alpha1.DisableStateEnabled
+	if disabled {
+		state = v1alpha1.DisableStateDisabled
+	}
+	uir := &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{label: label},
+		},
+		Status: v1alpha1.UIResourceStatus{
+			DisableStatus: v1alpha1.DisableResourceStatus{
+				State: state,
+				Sources: []v1alpha1.DisableSource{
+					{ConfigMap: &v1alpha1.ConfigMapDisableSource{Name: name + "-disable", Key: "isDisabled"}},
+				},
+			},
+		},
+	}
+	require.NoError(f.t, f.ctrlClient.Create(f.ctx, uir))
+}
+
+func (f *serverFixture) bulkReq(body string) (int, string) {
+	return f.portForwardReq(http.MethodPost, "/api/bulk", body)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Sends input to the stdin of a running Cmd, like a key that tells a dev
+// server to reload.
+//
+//	POST /api/cmd/input {"name": NAME, "input": TEXT}
+//
+// The name is the name of a Cmd, or of a local resource, whose serve_cmd
+// gets the input. The Cmd needs stdin enabled.
+const cmdInputPath = "/api/cmd/input"
+
+type cmdInputPayload struct {
+	Name  string `json:"name"`
+	Input string `json:"input"`
+}
+
+// Sends input to running Cmds. Implemented by the Cmd controller.
+type CmdInputSender interface {
+	SendInput(name string, input []byte) error
+}
+
+func (s *HeadsUpServer) HandleCmdInput(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "must be POST request", http.StatusBadRequest)
+		return
+	}
+
+	var payload cmdInputPayload
+	decoder := json.NewDecoder(req.Body)
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if payload.Name == "" {
+		http.Error(w, "missing name", http.StatusBadRequest)
+		return
+	}
+
+	name, err := s.cmdForInput(req, payload.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("no cmd or local resource named %q", payload.Name), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = s.cmdInput.SendInput(name, []byte(payload.Input))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+}
+
+// Looks up the Cmd with the given name, or else the serve_cmd of the local
+// resource with that name.
+//
+// A local resource gets a new serve Cmd every time it updates, so find the
+// current one the way the ServerController does, by its owner.
+func (s *HeadsUpServer) cmdForInput(req *http.Request, name string) (string, error) {
+	var cmd v1alpha1.Cmd
+	err := s.ctrlClient.Get(req.Context(), types.NamespacedName{Name: name}, &cmd)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return name, err
+	}
+
+	var cmds v1alpha1.CmdList
+	err = s.ctrlClient.List(req.Context(), &cmds)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range cmds.Items {
+		if c.Annotations[local.AnnotationOwnerKind] != "CmdServer" ||
+			c.Annotations[local.AnnotationOwnerName] != name {
+			continue
+		}
+
+		// The ServerController deletes the old Cmd before it starts a new
+		// one.
+		if c.DeletionTimestamp != nil {
+			continue
+		}
+		return c.Name, nil
+	}
+	return "", apierrors.NewNotFound(v1alpha1.Resource("cmds"), name)
+}
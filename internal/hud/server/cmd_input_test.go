@@ -0,0 +1,116 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestCmdInput(t *testing.T) {
+	f := newTestFixture(t)
+	f.createCmd("my-cmd")
+
+	status, body := f.makeReq("/api/cmd/input", f.serv.HandleCmdInput, http.MethodPost, `{"name":"my-cmd","input":"r\n"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "", body)
+	assert.Equal(t, []string{"my-cmd: r\n"}, f.cmdInput.sent())
+}
+
+func TestCmdInputServeCmd(t *testing.T) {
+	f := newTestFixture(t)
+	f.createCmd("backend-serve-1")
+	cmdName := f.startServeCmd("frontend")
+
+	status, _ := f.makeReq("/api/cmd/input", f.serv.HandleCmdInput, http.MethodPost, `{"name":"frontend","input":"r\n"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{cmdName + ": r\n"}, f.cmdInput.sent())
+}
+
+func TestCmdInputNotFound(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.makeReq("/api/cmd/input", f.serv.HandleCmdInput, http.MethodPost, `{"name":"frontend","input":"r\n"}`)
+	require.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, `no cmd or local resource named "frontend"`)
+	assert.Empty(t, f.cmdInput.sent())
+}
+
+func TestCmdInputError(t *testing.T) {
+	f := newTestFixture(t)
+	f.createCmd("my-cmd")
+	f.cmdInput.err = fmt.Errorf(`cmd "my-cmd" isn't running`)
+
+	status, body := f.makeReq("/api/cmd/input", f.serv.HandleCmdInput, http.MethodPost, `{"name":"my-cmd","input":"r\n"}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, `cmd "my-cmd" isn't running`, strings.TrimSpace(body))
+}
+
+func (f *serverFixture) createCmd(name string) {
+	err := f.ctrlClient.Create(context.Background(), &v1alpha1.Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.CmdSpec{Args: []string{"npm", "run", "dev"}, Stdin: true},
+	})
+	require.NoError(f.t, err)
+}
+
+// Starts the resource's serve_cmd the way Tilt does, and returns the name of
+// the Cmd that runs it.
+func (f *serverFixture) startServeCmd(resource string) string {
+	lt := model.NewLocalTarget(model.TargetName(resource), model.Cmd{}, model.ToHostCmd("npm run dev"), nil)
+	m := model.Manifest{Name: model.ManifestName(resource)}.WithDeployTarget(lt)
+
+	st := store.NewTestingStore()
+	state := st.LockMutableStateForTesting()
+	ms := store.NewManifestState(m)
+	ms.LastSuccessfulDeployTime = time.Now()
+	ms.AddCompletedBuild(model.BuildRecord{StartTime: ms.LastSuccessfulDeployTime, FinishTime: ms.LastSuccessfulDeployTime})
+	state.UpsertManifestTarget(&store.ManifestTarget{Manifest: m, State: ms})
+	st.UnlockMutableState()
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	sc := local.NewServerController(f.ctrlClient)
+	require.NoError(f.t, sc.OnChange(ctx, st, store.LegacyChangeSummary()))
+	for _, action := range st.Actions() {
+		if action, ok := action.(local.CmdCreateAction); ok {
+			return action.Cmd.Name
+		}
+	}
+	f.t.Fatalf("no serve cmd created for %s", resource)
+	return ""
+}
+
+type fakeCmdInput struct {
+	mu     sync.Mutex
+	inputs []string
+	err    error
+}
+
+func (c *fakeCmdInput) SendInput(name string, input []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.inputs = append(c.inputs, fmt.Sprintf("%s: %s", name, input))
+	return nil
+}
+
+func (c *fakeCmdInput) sent() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.inputs...)
+}
@@ -157,7 +157,14 @@ func (s *HeadsUpServerController) setUpHelper(ctx context.Context, st store.RSto
 		return fmt.Errorf("failed to create apiserver proxy: %v", err)
 	}
 
+	extProxyHandler, err := newExtAPIServerProxyHandler(config.GenericConfig.LoopbackClientConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create apiserver proxy: %v", err)
+	}
+
 	webRouter := mux.NewRouter()
+	webRouter.PathPrefix(extAPIServerPrefix).Handler(s.hudServer.requireAPIToken(extProxyHandler))
+	webRouter.PathPrefix(diagnosticsPath).Handler(s.hudServer.Router())
 	webRouter.PathPrefix("/debug").Handler(http.DefaultServeMux) // for /debug/pprof
 	// the path prefix here must be kept in sync with the prefix configured in the proxy handler
 	// (it needs to know what to strip before forwarding the request)
@@ -166,7 +173,7 @@ func (s *HeadsUpServerController) setUpHelper(ctx context.Context, st store.RSto
 
 	s.webServer = &http.Server{
 		Addr:    s.webListener.Addr().String(),
-		Handler: webRouter,
+		Handler: s.hudServer.teamAuth.wrap(webRouter),
 
 		// blackhole any server errors
 		ErrorLog: log.New(io.Discard, "", 0),
@@ -202,11 +209,26 @@ func (s *HeadsUpServerController) addToAPIServerConfig() error {
 	if s.configAccess == nil {
 		return nil
 	}
+	return AddToAPIServerConfig(s.configAccess, s.apiServerName, s.apiServerConfig.GenericConfig.LoopbackClientConfig)
+}
+
+// Remove this API server's configs into the user settings directory.
+//
+// Usually shows up as ~/.windmill/config or ~/.tilt-dev/config.
+func (s *HeadsUpServerController) removeFromAPIServerConfig() error {
+	if s.configAccess == nil {
+		return nil
+	}
+	return RemoveFromAPIServerConfig(s.configAccess, s.apiServerName)
+}
 
+// Adds a context for the named API server, so that CLI commands
+// like `tilt get` can find it.
+func AddToAPIServerConfig(configAccess clientcmd.ConfigAccess, apiServerName model.APIServerName, clientConfig *rest.Config) error {
 	var newConfig *clientcmdapi.Config
-	err := filelock.WithRLock(s.configAccess, func() error {
+	err := filelock.WithRLock(configAccess, func() error {
 		var e error
-		newConfig, e = s.configAccess.GetStartingConfig()
+		newConfig, e = configAccess.GetStartingConfig()
 		return e
 	})
 	if err != nil {
@@ -214,12 +236,11 @@ func (s *HeadsUpServerController) addToAPIServerConfig() error {
 	}
 	newConfig = newConfig.DeepCopy()
 
-	clientConfig := s.apiServerConfig.GenericConfig.LoopbackClientConfig
-	if err := model.ValidateAPIServerName(s.apiServerName); err != nil {
+	if err := model.ValidateAPIServerName(apiServerName); err != nil {
 		return err
 	}
 
-	name := string(s.apiServerName)
+	name := string(apiServerName)
 	newConfig.Contexts[name] = &clientcmdapi.Context{
 		Cluster:  name,
 		AuthInfo: name,
@@ -233,42 +254,36 @@ func (s *HeadsUpServerController) addToAPIServerConfig() error {
 		CertificateAuthorityData: clientConfig.TLSClientConfig.CAData,
 	}
 
-	return s.modifyConfig(*newConfig)
+	return modifyConfig(configAccess, *newConfig)
 }
 
-// Remove this API server's configs into the user settings directory.
-//
-// Usually shows up as ~/.windmill/config or ~/.tilt-dev/config.
-func (s *HeadsUpServerController) removeFromAPIServerConfig() error {
-	if s.configAccess == nil {
-		return nil
-	}
-
+// Removes the context that AddToAPIServerConfig added.
+func RemoveFromAPIServerConfig(configAccess clientcmd.ConfigAccess, apiServerName model.APIServerName) error {
 	var newConfig *clientcmdapi.Config
-	err := filelock.WithRLock(s.configAccess, func() error {
+	err := filelock.WithRLock(configAccess, func() error {
 		var e error
-		newConfig, e = s.configAccess.GetStartingConfig()
+		newConfig, e = configAccess.GetStartingConfig()
 		return e
 	})
 	if err != nil {
 		return err
 	}
 	newConfig = newConfig.DeepCopy()
-	if err := model.ValidateAPIServerName(s.apiServerName); err != nil {
+	if err := model.ValidateAPIServerName(apiServerName); err != nil {
 		return err
 	}
 
-	name := string(s.apiServerName)
+	name := string(apiServerName)
 	delete(newConfig.Contexts, name)
 	delete(newConfig.AuthInfos, name)
 	delete(newConfig.Clusters, name)
 
-	return s.modifyConfig(*newConfig)
+	return modifyConfig(configAccess, *newConfig)
 }
 
-func (s *HeadsUpServerController) modifyConfig(config clientcmdapi.Config) error {
-	return filelock.WithLock(s.configAccess, func() error {
-		return clientcmd.ModifyConfig(s.configAccess, config, true)
+func modifyConfig(configAccess clientcmd.ConfigAccess, config clientcmdapi.Config) error {
+	return filelock.WithLock(configAccess, func() error {
+		return clientcmd.ModifyConfig(configAccess, config, true)
 	})
 }
 
@@ -286,6 +301,9 @@ func newAPIServerProxyHandler(config *rest.Config) (http.Handler, error) {
 		},
 		AcceptPaths: []*regexp.Regexp{
 			regexp.MustCompile(`^/apis/tilt\.dev/\w+/uibuttons`),
+			regexp.MustCompile(`^/apis/tilt\.dev/\w+/buildhistories`),
+			regexp.MustCompile(`^/apis/tilt\.dev/\w+/uipanels`),
+			regexp.MustCompile(`^/apis/tilt\.dev/\w+/alertrules`),
 		},
 	}
 
@@ -293,5 +311,21 @@ func newAPIServerProxyHandler(config *rest.Config) (http.Handler, error) {
 	return proxy.NewProxyHandler(apiServerProxyPrefix, fs, config, 0, false)
 }
 
+// Proxies every apiserver path, unlike the web UI's proxy. Callers must
+// check the API token first.
+func newExtAPIServerProxyHandler(config *rest.Config) (http.Handler, error) {
+	// The kubectl proxy won't strip a prefix that starts with /api, so strip it here.
+	proxyHandler, err := proxy.NewProxyHandler("/", nil, config, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	return http.StripPrefix(extAPIServerPrefix, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// The rest transport won't add the apiserver's own token
+		// if the request already has one.
+		req.Header.Del("Authorization")
+		proxyHandler.ServeHTTP(w, req)
+	})), nil
+}
+
 var _ store.SetUpper = &HeadsUpServerController{}
 var _ store.TearDowner = &HeadsUpServerController{}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	goruntime "runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/store"
+)
+
+// Runtime diagnostics, for figuring out why Tilt is slow or stuck.
+//
+// Like /debug/pprof, these need full control of the session,
+// because profiles and engine state can hold secrets.
+const diagnosticsPath = "/debug/diagnostics"
+
+// A zip of diagnostics, profiles, and state to attach to bug reports.
+//
+//	GET /debug/diagnostics/bundle?cpu_seconds=N
+//
+// cpu_seconds is how long to profile the CPU for. Defaults to 5.
+// Set to 0 to skip the CPU profile.
+const diagnosticsBundlePath = "/debug/diagnostics/bundle"
+
+const defaultBundleCPUSeconds = 5
+const maxBundleCPUSeconds = 60
+
+type Diagnostics struct {
+	Time       time.Time              `json:"time"`
+	Goroutines int                    `json:"goroutines"`
+	Memory     DiagnosticsMemory      `json:"memory"`
+	Store      store.Stats            `json:"store"`
+	Watches    DiagnosticsWatches     `json:"watches"`
+	Queues     []DiagnosticsWorkQueue `json:"queues"`
+}
+
+type DiagnosticsMemory struct {
+	HeapAllocBytes uint64 `json:"heapAllocBytes"`
+	HeapInuseBytes uint64 `json:"heapInuseBytes"`
+	SysBytes       uint64 `json:"sysBytes"`
+	NumGC          uint32 `json:"numGC"`
+}
+
+// Clients following Tilt's state.
+type DiagnosticsWatches struct {
+	// Browser tabs, session recordings, and query watches.
+	Websockets   int   `json:"websockets"`
+	LogStreams   int64 `json:"logStreams"`
+	QueryWatches int64 `json:"queryWatches"`
+}
+
+// The work queue of a reconciler.
+type DiagnosticsWorkQueue struct {
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+	Adds  int    `json:"adds"`
+}
+
+// Counts the streams the server has open.
+type streamCounts struct {
+	logStreams   atomic.Int64
+	queryWatches atomic.Int64
+}
+
+func (s *HeadsUpServer) HandleDiagnostics(w http.ResponseWriter, req *http.Request) {
+	writeExtJSON(w, http.StatusOK, s.diagnostics())
+}
+
+func (s *HeadsUpServer) diagnostics() Diagnostics {
+	var mem goruntime.MemStats
+	goruntime.ReadMemStats(&mem)
+
+	return Diagnostics{
+		Time:       time.Now(),
+		Goroutines: goruntime.NumGoroutine(),
+		Memory: DiagnosticsMemory{
+			HeapAllocBytes: mem.HeapAlloc,
+			HeapInuseBytes: mem.HeapInuse,
+			SysBytes:       mem.Sys,
+			NumGC:          mem.NumGC,
+		},
+		Store: s.store.Stats(),
+		Watches: DiagnosticsWatches{
+			Websockets:   s.wsList.Len(),
+			LogStreams:   s.streams.logStreams.Load(),
+			QueryWatches: s.streams.queryWatches.Load(),
+		},
+		Queues: workQueueDiagnostics(),
+	}
+}
+
+// Reads the reconciler work queues from the controller-runtime metrics.
+func workQueueDiagnostics() []DiagnosticsWorkQueue {
+	families, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		return nil
+	}
+
+	queues := make(map[string]*DiagnosticsWorkQueue)
+	for _, family := range families {
+		var set func(q *DiagnosticsWorkQueue, v float64)
+		switch family.GetName() {
+		case "workqueue_depth":
+			set = func(q *DiagnosticsWorkQueue, v float64) { q.Depth = int(v) }
+		case "workqueue_adds_total":
+			set = func(q *DiagnosticsWorkQueue, v float64) { q.Adds = int(v) }
+		default:
+			continue
+		}
+
+		for _, m := range family.GetMetric() {
+			name := ""
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "name" {
+					name = label.GetValue()
+				}
+			}
+			q, ok := queues[name]
+			if !ok {
+				q = &DiagnosticsWorkQueue{Name: name}
+				queues[name] = q
+			}
+			set(q, m.GetGauge().GetValue()+m.GetCounter().GetValue())
+		}
+	}
+
+	result := []DiagnosticsWorkQueue{}
+	for _, q := range queues {
+		result = append(result, *q)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (s *HeadsUpServer) HandleDiagnosticsBundle(w http.ResponseWriter, req *http.Request) {
+	cpuSeconds := defaultBundleCPUSeconds
+	if v := req.URL.Query().Get("cpu_seconds"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxBundleCPUSeconds {
+			http.Error(w, fmt.Sprintf("invalid cpu_seconds %q: must be between 0 and %d", v, maxBundleCPUSeconds),
+				http.StatusBadRequest)
+			return
+		}
+		cpuSeconds = n
+	}
+
+	// Build the zip in memory, so that a failure can still be reported
+	// with an error status.
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	b := &diagnosticsBundle{zw: zw}
+
+	b.add("diagnostics.json", func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(s.diagnostics())
+	})
+	b.add("goroutine.txt", func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	})
+	b.add("heap.pprof", func(w io.Writer) error {
+		return pprof.Lookup("heap").WriteTo(w, 0)
+	})
+	if cpuSeconds > 0 {
+		b.add("cpu.pprof", func(w io.Writer) error {
+			err := pprof.StartCPUProfile(w)
+			if err != nil {
+				return err
+			}
+			select {
+			case <-time.After(time.Duration(cpuSeconds) * time.Second):
+			case <-req.Context().Done():
+			}
+			pprof.StopCPUProfile()
+			return nil
+		})
+	}
+	b.add("engine.json", func(w io.Writer) error {
+		state := s.store.RLockState()
+		defer s.store.RUnlockState()
+		return store.CreateEngineStateEncoder(w).Encode(state)
+	})
+	b.add("view.json", func(w io.Writer) error {
+		view, err := webview.CompleteView(req.Context(), s.ctrlClient, s.store)
+		if err != nil {
+			return err
+		}
+		return (&runtime.JSONPb{}).NewEncoder(w).Encode(view)
+	})
+	b.addErrors()
+
+	err := zw.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error writing diagnostics bundle: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("tilt-diagnostics-%s.zip", time.Now().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// Collects what it can. A part that fails is left out,
+// and its error is written to errors.txt instead.
+type diagnosticsBundle struct {
+	zw     *zip.Writer
+	errors []string
+}
+
+func (b *diagnosticsBundle) add(name string, write func(w io.Writer) error) {
+	part := &bytes.Buffer{}
+	err := write(part)
+	if err == nil {
+		var f io.Writer
+		f, err = b.zw.Create(name)
+		if err == nil {
+			_, err = f.Write(part.Bytes())
+		}
+	}
+	if err != nil {
+		b.errors = append(b.errors, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
+func (b *diagnosticsBundle) addErrors() {
+	if len(b.errors) == 0 {
+		return
+	}
+	f, err := b.zw.Create("errors.txt")
+	if err != nil {
+		return
+	}
+	for _, e := range b.errors {
+		_, _ = fmt.Fprintln(f, e)
+	}
+}
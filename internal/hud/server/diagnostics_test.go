@@ -0,0 +1,56 @@
+package server_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+)
+
+func TestDiagnostics(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.portForwardReq(http.MethodGet, "/debug/diagnostics", "")
+	require.Equal(t, http.StatusOK, status, body)
+
+	var d server.Diagnostics
+	require.NoError(t, json.Unmarshal([]byte(body), &d))
+	assert.Greater(t, d.Goroutines, 0)
+	assert.Greater(t, d.Memory.SysBytes, uint64(0))
+	assert.Equal(t, 0, d.Watches.Websockets)
+	assert.NotNil(t, d.Queues)
+}
+
+func TestDiagnosticsBundle(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("fe")
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/diagnostics/bundle?cpu_seconds=0", nil)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/zip", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), `attachment; filename="tilt-diagnostics-`)
+
+	zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"diagnostics.json", "goroutine.txt", "heap.pprof", "engine.json", "view.json"}, names)
+}
+
+func TestDiagnosticsBundleBadParams(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.portForwardReq(http.MethodGet, "/debug/diagnostics/bundle?cpu_seconds=600", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "must be between 0 and 60")
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/util/dryrun"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	builderrest "github.com/tilt-dev/tilt-apiserver/pkg/server/builder/rest"
+	"github.com/tilt-dev/tilt-apiserver/pkg/storage/filepath"
+)
+
+// Registers in-memory storage for the resources, like
+// builder.WithResourceMemoryStorage, except that the storage honors dryRun.
+//
+// The filepath storage ignores dryRun, so `tilt edit --dry-run` and
+// `tilt apply --dry-run=server` would otherwise save the object.
+func withDryRunMemoryStorage(b *builder.Server, objs []resource.Object, path string) *builder.Server {
+	fs := filepath.NewMemoryFS()
+	for _, obj := range objs {
+		ws := filepath.NewWatchSet()
+		b = b.WithResourceAndHandler(obj, dryRunStorageProvider(obj, path, fs, ws, false))
+
+		// Same as WithResourceMemoryStorage, create the status subresource
+		// if the object has one.
+		if _, ok := obj.(resource.ObjectWithStatusSubResource); ok {
+			b = b.WithSubResourceAndHandler(obj, "status", dryRunStorageProvider(obj, path, fs, ws, true))
+		}
+	}
+	return b
+}
+
+func dryRunStorageProvider(obj resource.Object, path string, fs filepath.FS, ws *filepath.WatchSet, status bool) builderrest.ResourceHandlerProvider {
+	return func(scheme *runtime.Scheme, getter generic.RESTOptionsGetter) (rest.Storage, error) {
+		var strategy builderrest.Strategy = builderrest.DefaultStrategy{
+			Object:      obj,
+			ObjectTyper: scheme,
+		}
+		if status {
+			strategy = builderrest.StatusSubResourceStrategy{Strategy: strategy}
+		}
+
+		storage, err := filepath.NewJSONFilepathStorageProvider(obj, path, fs, ws, strategy)(scheme, getter)
+		if err != nil {
+			return nil, err
+		}
+
+		s := &dryRunREST{
+			standardStorage: storage.(standardStorage),
+			strategy:        strategy,
+			groupResource:   obj.GetGroupVersionResource().GroupResource(),
+		}
+		if status {
+			// Status resources only support get and update.
+			return &dryRunStatusREST{Updater: s, Getter: s}, nil
+		}
+		return s, nil
+	}
+}
+
+type standardStorage interface {
+	rest.StandardStorage
+	rest.Scoper
+	rest.ShortNamesProvider
+}
+
+// Wraps the filepath storage, and for dry-run requests, runs the same
+// defaulting and validation as a real request but doesn't write anything.
+type dryRunREST struct {
+	standardStorage
+	strategy      builderrest.Strategy
+	groupResource schema.GroupResource
+}
+
+var _ standardStorage = &dryRunREST{}
+
+func (s *dryRunREST) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	if !dryrun.IsDryRun(options.DryRun) {
+		return s.standardStorage.Create(ctx, obj, createValidation, options)
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	rest.FillObjectMetaSystemFields(accessor)
+
+	err = rest.BeforeCreate(s.strategy, ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if createValidation != nil {
+		err = createValidation(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.Get(ctx, accessor.GetName(), &metav1.GetOptions{})
+	if err == nil {
+		return nil, apierrors.NewAlreadyExists(s.groupResource, accessor.GetName())
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *dryRunREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	if !dryrun.IsDryRun(options.DryRun) {
+		return s.standardStorage.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+	}
+
+	old, err := s.Get(ctx, name, &metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && forceAllowCreate {
+		obj, err := objInfo.UpdatedObject(ctx, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		obj, err = s.Create(ctx, obj, createValidation, &metav1.CreateOptions{DryRun: options.DryRun})
+		return obj, err == nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	obj, err := objInfo.UpdatedObject(ctx, old)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Same optimistic concurrency check as the filepath storage.
+	oldMeta, err := meta.Accessor(old)
+	if err != nil {
+		return nil, false, err
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	if oldMeta.GetResourceVersion() != objMeta.GetResourceVersion() {
+		return nil, false, apierrors.NewConflict(s.groupResource, name, errors.New(registry.OptimisticLockErrorMsg))
+	}
+
+	err = rest.BeforeUpdate(s.strategy, ctx, obj, old)
+	if err != nil {
+		return nil, false, err
+	}
+	if updateValidation != nil {
+		err = updateValidation(ctx, obj, old)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return obj, false, nil
+}
+
+func (s *dryRunREST) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	if !dryrun.IsDryRun(options.DryRun) {
+		return s.standardStorage.Delete(ctx, name, deleteValidation, options)
+	}
+
+	obj, err := s.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	if deleteValidation != nil {
+		err = deleteValidation(ctx, obj)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return obj, true, nil
+}
+
+func (s *dryRunREST) DeleteCollection(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *metainternalversion.ListOptions) (runtime.Object, error) {
+	if !dryrun.IsDryRun(options.DryRun) {
+		return s.standardStorage.DeleteCollection(ctx, deleteValidation, options, listOptions)
+	}
+	return s.List(ctx, listOptions)
+}
+
+type dryRunStatusREST struct {
+	rest.Updater
+	rest.Getter
+}
+
+func (s *dryRunStatusREST) Destroy() {
+	// Nothing to clean up.
+}
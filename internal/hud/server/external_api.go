@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/apitoken"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The external API lets scripts and editor plugins drive Tilt,
+// even from other machines (with `tilt up --host`).
+//
+// Unlike the endpoints the web UI uses, every request must carry
+// an API token created with `tilt token create`.
+const ExternalAPIPrefix = "/api/ext/v1"
+
+// Proxies the whole apiserver, so that `tilt attach` can point CLI commands
+// like `tilt get` at a session on another machine.
+const extAPIServerPrefix = ExternalAPIPrefix + "/apiserver"
+
+type externalResource struct {
+	Name          string                 `json:"name"`
+	Labels        []string               `json:"labels,omitempty"`
+	Disabled      bool                   `json:"disabled"`
+	Queued        bool                   `json:"queued"`
+	UpdateStatus  v1alpha1.UpdateStatus  `json:"updateStatus"`
+	RuntimeStatus v1alpha1.RuntimeStatus `json:"runtimeStatus"`
+}
+
+type externalResourceList struct {
+	Resources []externalResource `json:"resources"`
+}
+
+type externalError struct {
+	Error string `json:"error"`
+}
+
+func (s *HeadsUpServer) registerExternalAPI(r *mux.Router) {
+	ext := r.PathPrefix(ExternalAPIPrefix).Subrouter()
+	ext.Use(s.requireAPIToken)
+	ext.HandleFunc("/resources", s.ExtListResources).Methods(http.MethodGet)
+	ext.HandleFunc("/resources/{name}", s.ExtGetResource).Methods(http.MethodGet)
+	ext.HandleFunc("/resources/{name}/trigger", s.ExtTriggerResource).Methods(http.MethodPost)
+	ext.HandleFunc("/resources/{name}/enable", s.ExtEnableResource).Methods(http.MethodPost)
+	ext.HandleFunc("/resources/{name}/disable", s.ExtDisableResource).Methods(http.MethodPost)
+	ext.HandleFunc(extLogsPath, s.ExtStreamLogs).Methods(http.MethodGet)
+	ext.HandleFunc("/ready", s.ExtReady).Methods(http.MethodGet)
+	ext.HandleFunc("/bulk", s.HandleBulkAction).Methods(http.MethodPost)
+	ext.HandleFunc("/graph", s.HandleGraph).Methods(http.MethodGet)
+	ext.HandleFunc(extQueryPath, s.ExtQuery).Methods(http.MethodGet)
+}
+
+func (s *HeadsUpServer) requireAPIToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		secret, ok := bearerToken(req)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeExtError(w, http.StatusUnauthorized, "missing API token. Create one with 'tilt token create'")
+			return
+		}
+
+		token, ok, err := s.apiTokens.Verify(secret)
+		if err != nil {
+			writeExtError(w, http.StatusInternalServerError, fmt.Sprintf("verifying API token: %v", err))
+			return
+		}
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeExtError(w, http.StatusUnauthorized, "invalid API token")
+			return
+		}
+
+		id := tokenIdentity(token)
+		if !id.Role.Allows(permissionForRequest(req)) {
+			writeExtError(w, http.StatusForbidden, fmt.Sprintf("API token %q has %s access", token.Name, id.Role))
+			return
+		}
+
+		ctx := context.WithValue(req.Context(), teamIdentityKey{}, id)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// Tokens created before tokens had roles have full control.
+func tokenIdentity(token apitoken.Token) TeamIdentity {
+	role := TeamRole(token.Role)
+	if role == "" {
+		role = TeamRoleOwner
+	}
+	return TeamIdentity{Name: token.Name, Role: role}
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	auth := req.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func (s *HeadsUpServer) ExtListResources(w http.ResponseWriter, req *http.Request) {
+	var list v1alpha1.UIResourceList
+	err := s.ctrlClient.List(req.Context(), &list)
+	if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := externalResourceList{Resources: []externalResource{}}
+	for _, uir := range list.Items {
+		result.Resources = append(result.Resources, toExternalResource(uir))
+	}
+	writeExtJSON(w, http.StatusOK, result)
+}
+
+func (s *HeadsUpServer) ExtGetResource(w http.ResponseWriter, req *http.Request) {
+	uir, ok := s.extResource(w, req)
+	if !ok {
+		return
+	}
+	writeExtJSON(w, http.StatusOK, toExternalResource(uir))
+}
+
+// Responds with:
+// * 202 when the resource was added to the trigger queue
+// * 404 when the resource doesn't exist
+// * 409 when the resource is disabled
+func (s *HeadsUpServer) ExtTriggerResource(w http.ResponseWriter, req *http.Request) {
+	name, ok := resourceNameVar(w, req)
+	if !ok {
+		return
+	}
+
+	mn := model.ManifestName(name)
+	state := s.store.RLockState()
+	ms, ok := state.ManifestState(mn)
+	s.store.RUnlockState()
+
+	if !ok {
+		writeExtError(w, http.StatusNotFound, fmt.Sprintf("resource %q does not exist", mn))
+		return
+	}
+	if ms != nil && ms.DisableState == v1alpha1.DisableStateDisabled {
+		writeExtError(w, http.StatusConflict, fmt.Sprintf("resource %q is currently disabled", mn))
+		return
+	}
+
+	s.store.Dispatch(store.AppendToTriggerQueueAction{Name: mn, Reason: model.BuildReasonFlagTriggerAPI})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Reports whether every enabled resource is ready, with a reason for
+// each one that isn't. Meant as a gate for CI pipelines.
+//
+// Responds with:
+// * 200 when all resources are ready
+// * 503 when any resource is still pending or has an error
+func (s *HeadsUpServer) ExtReady(w http.ResponseWriter, req *http.Request) {
+	var list v1alpha1.UIResourceList
+	err := s.ctrlClient.List(req.Context(), &list)
+	if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	readiness := webview.AggregateReadiness(list.Items)
+	status := http.StatusOK
+	if !readiness.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeExtJSON(w, status, readiness)
+}
+
+func (s *HeadsUpServer) ExtEnableResource(w http.ResponseWriter, req *http.Request) {
+	s.extSetEnabled(w, req, true)
+}
+
+func (s *HeadsUpServer) ExtDisableResource(w http.ResponseWriter, req *http.Request) {
+	s.extSetEnabled(w, req, false)
+}
+
+func (s *HeadsUpServer) extSetEnabled(w http.ResponseWriter, req *http.Request, enable bool) {
+	uir, ok := s.extResource(w, req)
+	if !ok {
+		return
+	}
+
+	if len(uir.Status.DisableStatus.Sources) == 0 {
+		writeExtError(w, http.StatusBadRequest, fmt.Sprintf("%s cannot be enabled or disabled", uir.Name))
+		return
+	}
+
+	err := configmap.SetResourceEnabled(req.Context(), s.ctrlClient, uir, enable)
+	if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Looks up the UIResource named in the URL, and writes an error if there isn't one.
+func (s *HeadsUpServer) extResource(w http.ResponseWriter, req *http.Request) (v1alpha1.UIResource, bool) {
+	name, ok := resourceNameVar(w, req)
+	if !ok {
+		return v1alpha1.UIResource{}, false
+	}
+
+	var uir v1alpha1.UIResource
+	err := s.ctrlClient.Get(req.Context(), types.NamespacedName{Name: name}, &uir)
+	if apierrors.IsNotFound(err) {
+		writeExtError(w, http.StatusNotFound, fmt.Sprintf("resource %q does not exist", name))
+		return v1alpha1.UIResource{}, false
+	} else if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return v1alpha1.UIResource{}, false
+	}
+	return uir, true
+}
+
+// The router uses encoded paths, so names like "(Tiltfile)" arrive escaped.
+func resourceNameVar(w http.ResponseWriter, req *http.Request) (string, bool) {
+	name, err := url.PathUnescape(mux.Vars(req)["name"])
+	if err != nil {
+		writeExtError(w, http.StatusBadRequest, fmt.Sprintf("invalid resource name: %v", err))
+		return "", false
+	}
+	return name, true
+}
+
+func toExternalResource(uir v1alpha1.UIResource) externalResource {
+	var labels []string
+	for l := range uir.Labels {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	return externalResource{
+		Name:          uir.Name,
+		Labels:        labels,
+		Disabled:      uir.Status.DisableStatus.State == v1alpha1.DisableStateDisabled,
+		Queued:        uir.Status.Queued,
+		UpdateStatus:  uir.Status.UpdateStatus,
+		RuntimeStatus: uir.Status.RuntimeStatus,
+	}
+}
+
+func writeExtJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeExtError(w http.ResponseWriter, status int, msg string) {
+	writeExtJSON(w, status, externalError{Error: msg})
+}
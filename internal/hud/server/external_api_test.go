@@ -0,0 +1,208 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestExtAPIRequiresToken(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.makeExtReq(http.MethodGet, "/resources", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Contains(t, body, "missing API token")
+
+	status, body = f.makeExtReq(http.MethodGet, "/resources", "tilt_nope")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Contains(t, body, "invalid API token")
+}
+
+func TestExtAPIRevokedToken(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	require.NoError(t, f.apiTokens.Revoke("test"))
+
+	status, _ := f.makeExtReq(http.MethodGet, "/resources", token)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestExtAPITokenRoles(t *testing.T) {
+	f := newTestFixture(t)
+	f.withDummyManifests("fe")
+	f.createUIResource("fe", v1alpha1.UIResourceStatus{})
+	token := f.createAPITokenWithRole("viewer")
+
+	status, _ := f.makeExtReq(http.MethodGet, "/resources/fe", token)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body := f.makeExtReq(http.MethodPost, "/resources/fe/trigger", token)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, `API token \"test\" has viewer access`)
+
+	require.NoError(t, f.apiTokens.Revoke("test"))
+	token = f.createAPITokenWithRole("operator")
+	status, body = f.makeExtReq(http.MethodPost, "/resources/fe/trigger", token)
+	assert.Equal(t, http.StatusAccepted, status, body)
+
+	status, body = f.makeExtReq(http.MethodPost, "/resources/fe/disable", token)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, `API token \"test\" has operator access`)
+}
+
+func TestExtAPIListResources(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	require.NoError(t, f.ctrlClient.Create(f.ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "foo",
+			Labels: map[string]string{"backend": "backend"},
+		},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  v1alpha1.UpdateStatusOK,
+			RuntimeStatus: v1alpha1.RuntimeStatusError,
+		},
+	}))
+
+	status, body := f.makeExtReq(http.MethodGet, "/resources", token)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.JSONEq(t, `{"resources": [
+  {"name": "foo", "labels": ["backend"], "disabled": false, "queued": false, "updateStatus": "ok", "runtimeStatus": "error"}
+]}`, body)
+}
+
+func TestExtAPIGetResource(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.createUIResource("(Tiltfile)", v1alpha1.UIResourceStatus{UpdateStatus: v1alpha1.UpdateStatusInProgress})
+
+	status, body := f.makeExtReq(http.MethodGet, "/resources/%28Tiltfile%29", token)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"updateStatus":"in_progress"`)
+
+	status, body = f.makeExtReq(http.MethodGet, "/resources/bar", token)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"error": "resource \"bar\" does not exist"}`, body)
+}
+
+func TestExtAPIReady(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.createUIResource("(Tiltfile)", v1alpha1.UIResourceStatus{
+		UpdateStatus:  v1alpha1.UpdateStatusOK,
+		RuntimeStatus: v1alpha1.RuntimeStatusNotApplicable,
+	})
+	f.createUIResource("fe", v1alpha1.UIResourceStatus{
+		UpdateStatus:  v1alpha1.UpdateStatusOK,
+		RuntimeStatus: v1alpha1.RuntimeStatusPending,
+	})
+
+	status, body := f.makeExtReq(http.MethodGet, "/ready", token)
+	require.Equal(t, http.StatusServiceUnavailable, status, body)
+	assert.JSONEq(t, `{"status": "pending", "resources": [
+  {"name": "(Tiltfile)", "status": "ready"},
+  {"name": "fe", "status": "pending", "reason": "RuntimePending"}
+]}`, body)
+
+	var fe v1alpha1.UIResource
+	require.NoError(t, f.ctrlClient.Get(f.ctx, types.NamespacedName{Name: "fe"}, &fe))
+	fe.Status.RuntimeStatus = v1alpha1.RuntimeStatusOK
+	require.NoError(t, f.ctrlClient.Status().Update(f.ctx, &fe))
+
+	status, body = f.makeExtReq(http.MethodGet, "/ready", token)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"status":"ready"`)
+}
+
+func TestExtAPITrigger(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo")
+	token := f.createAPIToken()
+
+	status, body := f.makeExtReq(http.MethodPost, "/resources/foo/trigger", token)
+	require.Equal(t, http.StatusAccepted, status, body)
+
+	a := store.WaitForAction(t, reflect.TypeOf(store.AppendToTriggerQueueAction{}), f.getActions)
+	assert.Equal(t, store.AppendToTriggerQueueAction{
+		Name:   "foo",
+		Reason: model.BuildReasonFlagTriggerAPI,
+	}, a)
+
+	status, _ = f.makeExtReq(http.MethodPost, "/resources/bar/trigger", token)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestExtAPIDisableAndEnable(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.createUIResource("foo", v1alpha1.UIResourceStatus{
+		DisableStatus: v1alpha1.DisableResourceStatus{
+			Sources: []v1alpha1.DisableSource{
+				{ConfigMap: &v1alpha1.ConfigMapDisableSource{Name: "foo-disable", Key: "isDisabled"}},
+			},
+		},
+	})
+
+	status, body := f.makeExtReq(http.MethodPost, "/resources/foo/disable", token)
+	require.Equal(t, http.StatusAccepted, status, body)
+	assert.Equal(t, "true", f.configMapValue("foo-disable", "isDisabled"))
+
+	status, body = f.makeExtReq(http.MethodPost, "/resources/foo/enable", token)
+	require.Equal(t, http.StatusAccepted, status, body)
+	assert.Equal(t, "false", f.configMapValue("foo-disable", "isDisabled"))
+}
+
+func TestExtAPIDisableNoSources(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.createUIResource("(Tiltfile)", v1alpha1.UIResourceStatus{})
+
+	status, body := f.makeExtReq(http.MethodPost, "/resources/%28Tiltfile%29/disable", token)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "(Tiltfile) cannot be enabled or disabled")
+}
+
+func (f *serverFixture) createAPIToken() string {
+	return f.createAPITokenWithRole("")
+}
+
+func (f *serverFixture) createAPITokenWithRole(role string) string {
+	token, err := f.apiTokens.Create("test", role, time.Now())
+	require.NoError(f.t, err)
+	return token
+}
+
+func (f *serverFixture) createUIResource(name string, status v1alpha1.UIResourceStatus) {
+	uir := &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     status,
+	}
+	require.NoError(f.t, f.ctrlClient.Create(f.ctx, uir))
+}
+
+func (f *serverFixture) configMapValue(name, key string) string {
+	var cm v1alpha1.ConfigMap
+	require.NoError(f.t, f.ctrlClient.Get(f.ctx, types.NamespacedName{Name: name}, &cm))
+	return cm.Data[key]
+}
+
+func (f *serverFixture) makeExtReq(method, path, token string) (int, string) {
+	req := httptest.NewRequest(method, "/api/ext/v1"+path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	return rr.Code, rr.Body.String()
+}
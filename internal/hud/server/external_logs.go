@@ -0,0 +1,402 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Streams logs to external tools, like IDE log panes and `tilt logs`.
+//
+//	GET /api/logs?resource=NAME&label=LABEL&level=LEVEL&since=CHECKPOINT&sinceSeconds=N&follow=BOOL
+//	GET /api/ext/v1/logs?resource=NAME&label=LABEL&level=LEVEL&since=CHECKPOINT&sinceSeconds=N&follow=BOOL
+//
+// All parameters are optional:
+//   - resource: only stream logs from this resource. May be repeated.
+//     Logs that don't belong to any resource have an empty resource name.
+//   - label: only stream logs from resources with this label. May be repeated.
+//     Combined with resource, streams logs from either. Labels are matched
+//     against the resources that exist when the stream starts.
+//   - level: only stream logs at least this severe.
+//     One of debug, verbose, info, warn, or error.
+//   - since: only stream logs after this checkpoint. Defaults to 0,
+//     which streams all the logs Tilt still has.
+//   - sinceSeconds: only stream logs from the last N seconds, like
+//     `kubectl logs --since`. Combined with since, both must match.
+//   - follow: when false, close the stream after sending the existing logs.
+//     Defaults to true.
+//
+// By default, the logs are streamed as server-sent events, with the
+// checkpoint as the event ID, so that clients reconnecting with
+// a Last-Event-ID header resume where they left off.
+//
+// On the external API, a request that asks to upgrade to a websocket gets
+// each log segment as a separate JSON text message instead.
+//
+// Each event is a log segment, which may hold part of a line or several lines:
+//
+//	{"checkpoint": 12, "resource": "api", "spanId": "pod:api-1", "level": "info",
+//	 "time": "2021-03-01T12:00:00Z", "text": "listening on :8080\n"}
+//
+// To resume, pass the checkpoint of the last segment received as since.
+//
+// Server-sent event streams have a Tilt-Start-Time header with when
+// Tilt started, in RFC 3339 format, for clients that show how long after
+// the start each line was logged.
+const logsPath = "/api/logs"
+const extLogsPath = "/logs"
+const startTimeHeader = "Tilt-Start-Time"
+
+type externalLogEvent struct {
+	Checkpoint logstore.Checkpoint `json:"checkpoint"`
+	Resource   string              `json:"resource,omitempty"`
+	SpanID     string              `json:"spanId"`
+	Level      string              `json:"level"`
+	Time       time.Time           `json:"time"`
+	Text       string              `json:"text"`
+}
+
+var extLogLevels = []struct {
+	name  string
+	level logger.Level
+}{
+	{"debug", logger.DebugLvl},
+	{"verbose", logger.VerboseLvl},
+	{"info", logger.InfoLvl},
+	{"warn", logger.WarnLvl},
+	{"error", logger.ErrorLvl},
+}
+
+func extLogLevelName(level logger.Level) string {
+	for _, l := range extLogLevels {
+		if l.level == level {
+			return l.name
+		}
+	}
+	return "info"
+}
+
+func parseExtLogLevel(name string) (logger.Level, error) {
+	if name == "" {
+		return logger.NoneLvl, nil
+	}
+	var names []string
+	for _, l := range extLogLevels {
+		if strings.EqualFold(l.name, name) {
+			return l.level, nil
+		}
+		names = append(names, l.name)
+	}
+	return logger.NoneLvl, fmt.Errorf("invalid level %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+type extLogsRequest struct {
+	opts      logstore.SegmentOptions
+	labels    []string
+	since     logstore.Checkpoint
+	sinceTime time.Time
+	follow    bool
+}
+
+func parseExtLogsRequest(req *http.Request) (extLogsRequest, error) {
+	query := req.URL.Query()
+	result := extLogsRequest{follow: true}
+
+	if resources := query["resource"]; len(resources) > 0 {
+		result.opts.ManifestNames = make(model.ManifestNameSet, len(resources))
+		for _, r := range resources {
+			result.opts.ManifestNames[model.ManifestName(r)] = true
+		}
+	}
+
+	result.labels = query["label"]
+
+	level, err := parseExtLogLevel(query.Get("level"))
+	if err != nil {
+		return extLogsRequest{}, err
+	}
+	result.opts.MinLevel = level
+
+	// Browsers send Last-Event-ID when an event stream reconnects,
+	// and it's more recent than the since in the original URL.
+	since := query.Get("since")
+	if lastID := req.Header.Get("Last-Event-ID"); lastID != "" {
+		since = lastID
+	}
+	if since != "" {
+		n, err := strconv.Atoi(since)
+		if err != nil || n < 0 {
+			return extLogsRequest{}, fmt.Errorf("invalid since %q: must be a checkpoint from a previous log event", since)
+		}
+		result.since = logstore.Checkpoint(n)
+	}
+
+	if sinceSeconds := query.Get("sinceSeconds"); sinceSeconds != "" {
+		n, err := strconv.Atoi(sinceSeconds)
+		if err != nil || n <= 0 {
+			return extLogsRequest{}, fmt.Errorf("invalid sinceSeconds %q: must be a positive number of seconds", sinceSeconds)
+		}
+		result.sinceTime = time.Now().Add(-time.Duration(n) * time.Second)
+	}
+
+	if follow := query.Get("follow"); follow != "" {
+		result.follow, err = strconv.ParseBool(follow)
+		if err != nil {
+			return extLogsRequest{}, fmt.Errorf("invalid follow %q: must be true or false", follow)
+		}
+	}
+	return result, nil
+}
+
+// The external API authenticates with tokens rather than cookies,
+// so it's safe to accept websockets from any origin.
+var extUpgrader = websocket.Upgrader{
+	ReadBufferSize:    1024,
+	WriteBufferSize:   1024,
+	EnableCompression: false,
+	CheckOrigin:       func(req *http.Request) bool { return true },
+}
+
+func (s *HeadsUpServer) HandleLogs(w http.ResponseWriter, req *http.Request) {
+	s.serveLogs(w, req, false)
+}
+
+func (s *HeadsUpServer) ExtStreamLogs(w http.ResponseWriter, req *http.Request) {
+	// The external API authenticates with tokens rather than cookies,
+	// so only it may stream over a websocket.
+	s.serveLogs(w, req, true)
+}
+
+func (s *HeadsUpServer) serveLogs(w http.ResponseWriter, req *http.Request, allowWebsocket bool) {
+	r, err := parseExtLogsRequest(req)
+	if err != nil {
+		writeExtError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(r.labels) > 0 {
+		status, err := s.selectLogLabels(req.Context(), &r)
+		if err != nil {
+			writeExtError(w, status, err.Error())
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if allowWebsocket && websocket.IsWebSocketUpgrade(req) {
+		s.extStreamLogsWebsocket(ctx, cancel, w, req, r)
+		return
+	}
+	s.extStreamLogsSSE(ctx, w, r)
+}
+
+// Adds the resources with the requested labels to the resources to stream.
+// Returns the status code to respond with if there aren't any.
+func (s *HeadsUpServer) selectLogLabels(ctx context.Context, r *extLogsRequest) (int, error) {
+	var list v1alpha1.UIResourceList
+	err := s.ctrlClient.List(ctx, &list)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if r.opts.ManifestNames == nil {
+		r.opts.ManifestNames = make(model.ManifestNameSet)
+	}
+	found := false
+	for _, uir := range list.Items {
+		for _, label := range r.labels {
+			if _, ok := uir.Labels[label]; ok {
+				r.opts.ManifestNames[model.ManifestName(uir.Name)] = true
+				found = true
+				break
+			}
+		}
+	}
+
+	// An empty set streams everything, so a label that
+	// matches nothing has to be an error.
+	if !found && len(r.opts.ManifestNames) == 0 {
+		return http.StatusNotFound, fmt.Errorf("no resources with label %s", strings.Join(r.labels, " or "))
+	}
+	return http.StatusOK, nil
+}
+
+func (s *HeadsUpServer) extStreamLogsSSE(ctx context.Context, w http.ResponseWriter, r extLogsRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeExtError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	state := s.store.RLockState()
+	startTime := state.TiltStartTime
+	s.store.RUnlockState()
+	if !startTime.IsZero() {
+		w.Header().Set(startTimeHeader, startTime.Format(time.RFC3339Nano))
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	err := s.streamLogs(ctx, r, func(events []externalLogEvent) error {
+		for _, e := range events {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", e.Checkpoint, data)
+			if err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("streaming logs: %v", err)
+	}
+}
+
+func (s *HeadsUpServer) extStreamLogsWebsocket(ctx context.Context, cancel context.CancelFunc, w http.ResponseWriter, req *http.Request, r extLogsRequest) {
+	conn, err := extUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// The upgrader has already written an error response.
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Consume control messages, and stop streaming when the client goes away.
+	go func() {
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err = s.streamLogs(ctx, r, func(events []externalLogEvent) error {
+		for _, e := range events {
+			err := conn.WriteJSON(e)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("streaming logs: %v", err)
+		return
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+}
+
+// Sends the logs matching the request until the context is done,
+// or until the existing logs are sent if the request doesn't follow.
+func (s *HeadsUpServer) streamLogs(ctx context.Context, r extLogsRequest, send func([]externalLogEvent) error) error {
+	s.streams.logStreams.Add(1)
+	defer s.streams.logStreams.Add(-1)
+
+	sub := newLogStreamSubscriber()
+	if r.follow {
+		// Subscribe before the first read, so that we don't miss any logs in between.
+		err := s.store.AddSubscriber(ctx, sub)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = s.store.RemoveSubscriber(context.Background(), sub)
+		}()
+	}
+
+	checkpoint := r.since
+	for {
+		state := s.store.RLockState()
+		segments, next := state.LogStore.SegmentsSince(checkpoint, r.opts)
+		s.store.RUnlockState()
+		checkpoint = next
+
+		events := make([]externalLogEvent, 0, len(segments))
+		for _, seg := range segments {
+			if seg.Time.Before(r.sinceTime) {
+				continue
+			}
+			events = append(events, externalLogEvent{
+				Checkpoint: seg.Checkpoint,
+				Resource:   seg.ManifestName.String(),
+				SpanID:     string(seg.SpanID),
+				Level:      extLogLevelName(seg.Level),
+				Time:       seg.Time,
+				Text:       string(seg.Text),
+			})
+		}
+		if len(events) > 0 {
+			err := send(events)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !r.follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.notify:
+		}
+	}
+}
+
+// Wakes up a log stream when there are new logs.
+type logStreamSubscriber struct {
+	notify chan struct{}
+}
+
+var _ store.Subscriber = &logStreamSubscriber{}
+
+func newLogStreamSubscriber() *logStreamSubscriber {
+	return &logStreamSubscriber{notify: make(chan struct{}, 1)}
+}
+
+func (l *logStreamSubscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if !summary.Log {
+		return nil
+	}
+
+	select {
+	case l.notify <- struct{}{}:
+	default:
+		// The stream is already due to wake up.
+	}
+	return nil
+}
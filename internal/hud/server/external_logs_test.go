@@ -0,0 +1,402 @@
+package server_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/hud"
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+type extLogEvent struct {
+	Checkpoint int    `json:"checkpoint"`
+	Resource   string `json:"resource"`
+	Level      string `json:"level"`
+	Text       string `json:"text"`
+}
+
+func TestExtLogsFiltered(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.appendLog("fe", logger.InfoLvl, "fe info\n")
+	f.appendLog("be", logger.InfoLvl, "be info\n")
+	f.appendLog("fe", logger.WarnLvl, "fe warn\n")
+	f.appendLog("", logger.ErrorLvl, "global error\n")
+
+	status, body := f.makeExtReq(http.MethodGet, "/logs?follow=false&resource=fe&level=warn", token)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t,
+		"id: 3\nevent: log\ndata: ",
+		body[:strings.Index(body, "{")])
+
+	events := parseSSEEvents(t, body)
+	require.Len(t, events, 1)
+	assert.Equal(t, extLogEvent{Checkpoint: 3, Resource: "fe", Level: "warn", Text: "fe warn\n"}, events[0])
+
+	// Logs without a resource are selected with an empty resource name.
+	_, body = f.makeExtReq(http.MethodGet, "/logs?follow=false&resource=&resource=be", token)
+	events = parseSSEEvents(t, body)
+	require.Len(t, events, 2)
+	assert.Equal(t, "be info\n", events[0].Text)
+	assert.Equal(t, "global error\n", events[1].Text)
+}
+
+func TestExtLogsSince(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.appendLog("fe", logger.InfoLvl, "line1\n")
+	f.appendLog("fe", logger.InfoLvl, "line2\n")
+	f.appendLog("fe", logger.InfoLvl, "line3\n")
+
+	_, body := f.makeExtReq(http.MethodGet, "/logs?follow=false&since=2", token)
+	events := parseSSEEvents(t, body)
+	require.Len(t, events, 1)
+	assert.Equal(t, "line3\n", events[0].Text)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ext/v1/logs?follow=false&since=0", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Last-Event-ID", "1")
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	events = parseSSEEvents(t, rr.Body.String())
+	require.Len(t, events, 2)
+	assert.Equal(t, "line2\n", events[0].Text)
+}
+
+func TestExtLogsSinceSeconds(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.appendLogAt(time.Now().Add(-time.Hour), "fe", "old\n")
+	f.appendLogAt(time.Now().Add(-time.Second), "fe", "new\n")
+
+	_, body := f.makeExtReq(http.MethodGet, "/logs?follow=false&sinceSeconds=60", token)
+	events := parseSSEEvents(t, body)
+	require.Len(t, events, 1)
+	assert.Equal(t, "new\n", events[0].Text)
+}
+
+func TestExtLogsBadParams(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+
+	status, body := f.makeExtReq(http.MethodGet, "/logs?level=loud", token)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, `invalid level \"loud\"`)
+
+	status, _ = f.makeExtReq(http.MethodGet, "/logs?since=-1", token)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = f.makeExtReq(http.MethodGet, "/logs?sinceSeconds=5m", token)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, `invalid sinceSeconds \"5m\"`)
+
+	status, _ = f.makeExtReq(http.MethodGet, "/logs", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestExtLogsSSEFollow(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.appendLog("fe", logger.InfoLvl, "line1\n")
+
+	srv := httptest.NewServer(f.serv.Router())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/ext/v1/logs", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan extLogEvent)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var e extLogEvent
+			if json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e) == nil {
+				events <- e
+			}
+		}
+		close(events)
+	}()
+
+	assert.Equal(t, "line1\n", nextExtLogEvent(t, events).Text)
+
+	f.appendLog("fe", logger.InfoLvl, "line2\n")
+	assert.Equal(t, extLogEvent{Checkpoint: 2, Resource: "fe", Level: "info", Text: "line2\n"},
+		nextExtLogEvent(t, events))
+}
+
+func TestExtLogsWebsocket(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.appendLog("fe", logger.InfoLvl, "line1\n")
+	f.appendLog("be", logger.InfoLvl, "be line\n")
+
+	srv := httptest.NewServer(f.serv.Router())
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/ext/v1/logs?resource=fe"
+	_, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err, "websocket requires a token")
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var e extLogEvent
+	require.NoError(t, conn.ReadJSON(&e))
+	assert.Equal(t, extLogEvent{Checkpoint: 1, Resource: "fe", Level: "info", Text: "line1\n"}, e)
+
+	f.appendLog("be", logger.InfoLvl, "be line 2\n")
+	f.appendLog("fe", logger.InfoLvl, "line2\n")
+	require.NoError(t, conn.ReadJSON(&e))
+	assert.Equal(t, extLogEvent{Checkpoint: 4, Resource: "fe", Level: "info", Text: "line2\n"}, e)
+}
+
+func TestLogsLabel(t *testing.T) {
+	f := newTestFixture(t)
+	f.createLabeledUIResource("fe", "web", false)
+	f.createLabeledUIResource("be", "api", false)
+	f.appendLog("fe", logger.InfoLvl, "fe info\n")
+	f.appendLog("be", logger.InfoLvl, "be info\n")
+	f.appendLog("db", logger.InfoLvl, "db info\n")
+
+	// The web UI's logs endpoint doesn't need an API token.
+	status, body := f.portForwardReq(http.MethodGet, "/api/logs?follow=false&label=web", "")
+	require.Equal(t, http.StatusOK, status, body)
+	events := parseSSEEvents(t, body)
+	require.Len(t, events, 1)
+	assert.Equal(t, "fe info\n", events[0].Text)
+
+	// Labels and resources are combined.
+	_, body = f.portForwardReq(http.MethodGet, "/api/logs?follow=false&label=web&resource=db", "")
+	events = parseSSEEvents(t, body)
+	require.Len(t, events, 2)
+	assert.Equal(t, "db info\n", events[1].Text)
+
+	status, body = f.portForwardReq(http.MethodGet, "/api/logs?follow=false&label=cache", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "no resources with label cache")
+}
+
+func TestStreamLogs(t *testing.T) {
+	f := newTestFixture(t)
+	f.createLabeledUIResource("fe", "web", false)
+	f.appendLog("fe", logger.InfoLvl, "fe info\n")
+	f.appendLog("fe", logger.WarnLvl, "fe warn\n")
+	f.appendLog("be", logger.WarnLvl, "be warn\n")
+
+	srv := httptest.NewServer(f.serv.Router())
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	out := bytes.NewBuffer(nil)
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{Level: "warn"},
+		hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	assert.Equal(t, "           fe │ WARNING: fe warn\n           be │ WARNING: be warn\n", out.String())
+
+	out.Reset()
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{Labels: []string{"web"}, NoPrefix: true},
+		hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	assert.Equal(t, "fe info\nWARNING: fe warn\n", out.String())
+
+	out.Reset()
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{Resources: []string{"be"}, Format: hud.LogFormatJSON},
+		hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	var line hud.JSONLogLine
+	require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "be", line.Resource)
+	assert.Equal(t, "warn", line.Level)
+	assert.Equal(t, "be warn", line.Text)
+
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{Level: "loud"},
+		hud.NewIncrementalPrinter(out))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid level "loud"`)
+}
+
+func TestStreamLogsTimestamps(t *testing.T) {
+	f := newTestFixture(t)
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	state := f.st.LockMutableStateForTesting()
+	state.TiltStartTime = start
+	f.st.UnlockMutableState()
+	f.appendLogAt(start.Add(2500*time.Millisecond), "fe", "listening\n")
+
+	srv := httptest.NewServer(f.serv.Router())
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	out := bytes.NewBuffer(nil)
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{
+		NoPrefix:   true,
+		Timestamps: logstore.Timestamps{Mode: logstore.TimestampModeAbsolute, Location: time.UTC},
+	}, hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	assert.Equal(t, "2021-03-01T12:00:02.500Z listening\n", out.String())
+
+	// Relative timestamps count from when the server's Tilt started,
+	// not from the first line streamed.
+	out.Reset()
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{
+		NoPrefix:   true,
+		Timestamps: logstore.Timestamps{Mode: logstore.TimestampModeRelative},
+	}, hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	assert.Equal(t, "   +2.500s listening\n", out.String())
+}
+
+// Servers from before the log stream only have the web UI's websocket.
+func TestStreamLogsFromOlderServer(t *testing.T) {
+	now := timestamppb.Now()
+	view := &proto_webview.View{
+		UiResources: []*v1alpha1.UIResource{
+			{ObjectMeta: metav1.ObjectMeta{Name: "fe", Labels: map[string]string{"web": "web"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "be"}},
+		},
+		LogList: &proto_webview.LogList{
+			Spans: map[string]*proto_webview.LogSpan{
+				"fe": {ManifestName: "fe"},
+				"be": {ManifestName: "be"},
+			},
+			Segments: []*proto_webview.LogSegment{
+				{SpanId: "fe", Text: "fe info\n", Level: proto_webview.LogLevel_INFO, Time: now},
+				{SpanId: "fe", Text: "fe warn\n", Level: proto_webview.LogLevel_WARN, Time: now},
+				{SpanId: "be", Text: "be warn\n", Level: proto_webview.LogLevel_WARN, Time: now},
+				{SpanId: "be", Text: "be old\n", Level: proto_webview.LogLevel_WARN, Time: timestamppb.New(time.Now().Add(-time.Hour))},
+			},
+			ToCheckpoint: 4,
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws/view", func(w http.ResponseWriter, req *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, req, nil)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+		msg, err := (&jsonpb.Marshaler{}).MarshalToString(view)
+		require.NoError(t, err)
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	out := bytes.NewBuffer(nil)
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{Level: "warn", Since: time.Minute},
+		hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	assert.Equal(t, "           fe │ WARNING: fe warn\n           be │ WARNING: be warn\n", out.String())
+
+	out.Reset()
+	err = server.StreamLogs(ctx, model.WebURL(*u), server.LogStreamOptions{Labels: []string{"web"}, NoPrefix: true},
+		hud.NewIncrementalPrinter(out))
+	require.NoError(t, err)
+	assert.Equal(t, "fe info\nWARNING: fe warn\n", out.String())
+}
+
+func (f *serverFixture) appendLog(mn model.ManifestName, level logger.Level, msg string) {
+	var action store.LogAction
+	if mn == "" {
+		action = store.NewGlobalLogAction(level, []byte(msg))
+	} else {
+		action = store.NewLogAction(mn, model.LogSpanID(mn), level, nil, []byte(msg))
+	}
+
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(action, nil)
+	f.st.UnlockMutableState()
+	f.st.NotifySubscribers(f.ctx, store.ChangeSummary{Log: true})
+}
+
+func parseSSEEvents(t *testing.T, body string) []extLogEvent {
+	var result []extLogEvent
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var e extLogEvent
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+		result = append(result, e)
+	}
+	return result
+}
+
+func nextExtLogEvent(t *testing.T, events chan extLogEvent) extLogEvent {
+	select {
+	case e, ok := <-events:
+		require.True(t, ok, "log stream closed")
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for log event")
+		return extLogEvent{}
+	}
+}
+
+func (f *serverFixture) appendLogAt(ts time.Time, mn model.ManifestName, msg string) {
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(timedLogEvent{
+		LogAction: store.NewLogAction(mn, model.LogSpanID(mn), logger.InfoLvl, nil, []byte(msg)),
+		ts:        ts,
+	}, nil)
+	f.st.UnlockMutableState()
+}
+
+// A log line from the past.
+type timedLogEvent struct {
+	store.LogAction
+	ts time.Time
+}
+
+func (e timedLogEvent) Time() time.Time { return e.ts }
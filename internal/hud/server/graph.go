@@ -0,0 +1,28 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The dependency graph of all resources, for the web UI's graph view.
+// The external API serves the same graph at /api/ext/v1/graph.
+const graphPath = "/api/graph"
+
+// Responds with a webview.ResourceGraph.
+func (s *HeadsUpServer) HandleGraph(w http.ResponseWriter, req *http.Request) {
+	var list v1alpha1.UIResourceList
+	err := s.ctrlClient.List(req.Context(), &list)
+	if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	state := s.store.RLockState()
+	manifests := state.Manifests()
+	s.store.RUnlockState()
+
+	writeExtJSON(w, http.StatusOK, webview.BuildResourceGraph(manifests, list.Items))
+}
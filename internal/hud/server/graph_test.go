@@ -0,0 +1,53 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+)
+
+func TestGraph(t *testing.T) {
+	f := newTestFixture(t)
+	f.withManifestDeps(map[string][]string{
+		"web": {"api"},
+		"api": nil,
+	})
+	f.createLabeledUIResource("web", "app", false)
+	f.createLabeledUIResource("api", "app", false)
+
+	status, body := f.portForwardReq(http.MethodGet, "/api/graph", "")
+	require.Equal(t, http.StatusOK, status, body)
+
+	var graph webview.ResourceGraph
+	require.NoError(t, json.Unmarshal([]byte(body), &graph))
+	assert.ElementsMatch(t, []string{"resource:api", "resource:web"},
+		[]string{graph.Nodes[0].ID, graph.Nodes[1].ID})
+	assert.Equal(t, []webview.GraphEdge{
+		{From: "resource:api", To: "resource:web", Kind: webview.GraphEdgeResourceDep, Critical: true},
+	}, graph.Edges)
+
+	// Neither resource has finished its first update.
+	assert.Equal(t, []string{"api", "web"}, graph.CriticalPath)
+}
+
+func TestExtAPIGraph(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("web")
+	token := f.createAPIToken()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ext/v1/graph", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var graph webview.ResourceGraph
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &graph))
+	require.Len(t, graph.Nodes, 1)
+	assert.Equal(t, "web", graph.Nodes[0].Name)
+}
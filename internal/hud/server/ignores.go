@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+
+	"github.com/tilt-dev/tilt/internal/ignore"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The effective ignore rules of every FileWatch, for debugging
+// "why didn't my change trigger an update?"
+//
+//	GET /api/ignores
+//	GET /api/ignores?path=ABSPATH
+//
+// With a path, only the FileWatches that watch that path are included,
+// along with the rule (if any) that ignores a change to it.
+const ignoresPath = "/api/ignores"
+
+type IgnoresView struct {
+	FileWatches []FileWatchIgnores `json:"fileWatches"`
+}
+
+type FileWatchIgnores struct {
+	Name    string               `json:"name"`
+	Ignores []v1alpha1.IgnoreDef `json:"ignores"`
+
+	// Only set when the request asks about a path.
+	Match *IgnoreMatch `json:"match,omitempty"`
+}
+
+type IgnoreMatch struct {
+	Path    string `json:"path"`
+	Ignored bool   `json:"ignored"`
+
+	// The rule that ignores the path, if it came from the FileWatch spec.
+	Rule *v1alpha1.IgnoreDef `json:"rule,omitempty"`
+
+	// True if the path is ignored because it looks like an editor temp file.
+	Ephemeral bool `json:"ephemeral,omitempty"`
+}
+
+// Responds with:
+// * 200/an IgnoresView
+// * 400/error message if the path isn't absolute
+func (s *HeadsUpServer) HandleIgnores(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Query().Get("path")
+	if path != "" && !filepath.IsAbs(path) {
+		writeExtError(w, http.StatusBadRequest, fmt.Sprintf("path %q must be absolute", path))
+		return
+	}
+
+	var list v1alpha1.FileWatchList
+	err := s.ctrlClient.List(req.Context(), &list)
+	if err != nil {
+		writeExtError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeExtJSON(w, http.StatusOK, buildIgnoresView(list.Items, path))
+}
+
+func buildIgnoresView(fws []v1alpha1.FileWatch, path string) IgnoresView {
+	sort.Slice(fws, func(i, j int) bool {
+		return fws[i].Name < fws[j].Name
+	})
+
+	view := IgnoresView{FileWatches: []FileWatchIgnores{}}
+	for _, fw := range fws {
+		item := FileWatchIgnores{Name: fw.Name, Ignores: fw.Spec.Ignores}
+		if item.Ignores == nil {
+			item.Ignores = []v1alpha1.IgnoreDef{}
+		}
+
+		if path != "" {
+			if !ospath.IsChildOfOne(fw.Spec.WatchedPaths, path) {
+				continue
+			}
+			item.Match = matchIgnores(fw.Spec.Ignores, path)
+		}
+		view.FileWatches = append(view.FileWatches, item)
+	}
+	return view
+}
+
+// Checks the same rules, in the same order, as CreateFileChangeFilter.
+func matchIgnores(ignores []v1alpha1.IgnoreDef, path string) *IgnoreMatch {
+	if ig, ok := ignore.FindMatchingIgnore(ignores, path); ok {
+		return &IgnoreMatch{Path: path, Ignored: true, Rule: &ig}
+	}
+
+	isEphemeral, _ := ignore.EphemeralPathMatcher.Matches(path)
+	if isEphemeral {
+		return &IgnoreMatch{Path: path, Ignored: true, Ephemeral: true}
+	}
+	return &IgnoreMatch{Path: path}
+}
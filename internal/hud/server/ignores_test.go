@@ -0,0 +1,79 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestIgnores(t *testing.T) {
+	f := newTestFixture(t)
+	rule := v1alpha1.IgnoreDef{
+		BasePath: "/src/fe",
+		Patterns: []string{"**/*.pb.go"},
+		Reason:   "watch_settings(): generated protobuf code",
+	}
+	f.createFileWatch("local-fe", []string{"/src/fe"}, rule)
+	f.createFileWatch("local-be", []string{"/src/be"})
+
+	view := f.getIgnores("")
+	require.Len(t, view.FileWatches, 2)
+	assert.Equal(t, "local-be", view.FileWatches[0].Name)
+	assert.Equal(t, []v1alpha1.IgnoreDef{}, view.FileWatches[0].Ignores)
+	assert.Equal(t, "local-fe", view.FileWatches[1].Name)
+	assert.Equal(t, []v1alpha1.IgnoreDef{rule}, view.FileWatches[1].Ignores)
+	assert.Nil(t, view.FileWatches[1].Match)
+
+	view = f.getIgnores("/src/fe/api/api.pb.go")
+	require.Len(t, view.FileWatches, 1)
+	assert.Equal(t, &server.IgnoreMatch{Path: "/src/fe/api/api.pb.go", Ignored: true, Rule: &rule},
+		view.FileWatches[0].Match)
+
+	view = f.getIgnores("/src/fe/.main.go.swp")
+	require.Len(t, view.FileWatches, 1)
+	assert.Equal(t, &server.IgnoreMatch{Path: "/src/fe/.main.go.swp", Ignored: true, Ephemeral: true},
+		view.FileWatches[0].Match)
+
+	view = f.getIgnores("/src/fe/main.go")
+	require.Len(t, view.FileWatches, 1)
+	assert.Equal(t, &server.IgnoreMatch{Path: "/src/fe/main.go"}, view.FileWatches[0].Match)
+
+	view = f.getIgnores("/src/db/main.go")
+	assert.Empty(t, view.FileWatches)
+}
+
+func TestIgnoresRelativePath(t *testing.T) {
+	f := newTestFixture(t)
+	status, body := f.portForwardReq(http.MethodGet, "/api/ignores?path=src%2Fmain.go", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, `path \"src/main.go\" must be absolute`)
+}
+
+func (f *serverFixture) createFileWatch(name string, paths []string, ignores ...v1alpha1.IgnoreDef) {
+	err := f.ctrlClient.Create(f.ctx, &v1alpha1.FileWatch{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.FileWatchSpec{WatchedPaths: paths, Ignores: ignores},
+	})
+	require.NoError(f.t, err)
+}
+
+func (f *serverFixture) getIgnores(path string) server.IgnoresView {
+	query := url.Values{}
+	if path != "" {
+		query.Set("path", path)
+	}
+	status, body := f.portForwardReq(http.MethodGet, "/api/ignores?"+query.Encode(), "")
+	require.Equal(f.t, http.StatusOK, status, body)
+
+	var view server.IgnoresView
+	require.NoError(f.t, json.Unmarshal([]byte(body), &view))
+	return view
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Loads the logs saved from the last time Tilt ran, as plain text,
+// oldest first.
+//
+//	GET /api/logs/previous?resource=NAME&level=warn&timestamps=relative&timezone=utc
+//
+// resource may be repeated. Without it, returns the logs of every resource.
+//
+// timestamps puts a timestamp in front of each line. One of none, absolute,
+// or relative to when the session started. timezone is the time zone of
+// absolute timestamps, utc or local (to the server). Defaults to utc.
+//
+// Only works when Tilt saves its logs to disk (tilt up --log-history).
+const previousLogsPath = "/api/logs/previous"
+
+func (s *HeadsUpServer) HandlePreviousLogs(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	level, err := parseExtLogLevel(query.Get("level"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	timestamps, err := logstore.ParseTimestamps(query.Get("timestamps"), query.Get("timezone"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := loghistory.PrintOptions{
+		Resources:  model.ManifestNameSet{},
+		MinLevel:   level,
+		Timestamps: timestamps,
+	}
+	for _, r := range query["resource"] {
+		opts.Resources[model.ManifestName(r)] = true
+	}
+
+	session, records, err := loghistory.ReadSession(string(s.previousLogsDir))
+	if errors.Is(err, loghistory.ErrNoSession) {
+		http.Error(w, "no logs saved from the last time Tilt ran", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	opts.Timestamps.Start = session.StartTime
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "Logs from the Tilt session started at %s\n\n", session.StartTime.Format("2006-01-02 15:04:05"))
+	loghistory.Print(records, opts, func(lines []logstore.LogLine) {
+		for _, line := range lines {
+			_, _ = bw.WriteString(line.Text)
+		}
+	})
+
+	// The writer keeps the first error it hit.
+	err = bw.Flush()
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("rendering previous logs: %v", err)
+	}
+}
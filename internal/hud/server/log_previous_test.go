@@ -0,0 +1,86 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
+)
+
+func TestPreviousLogs(t *testing.T) {
+	f := newTestFixture(t)
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.Local)
+	f.writePreviousSession(start, []loghistory.Record{
+		{Time: start, Resource: "fe", SpanID: "pod:fe", Level: "info", Text: "fe started\n"},
+		{Time: start, Resource: "be", SpanID: "pod:be", Level: "info", Text: "be started\n"},
+		{Time: start, Resource: "fe", SpanID: "pod:fe", Level: "warn", Text: "fe is slow\n"},
+	})
+
+	code, body := f.getPreviousLogs("?resource=fe")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, "Logs from the Tilt session started at 2024-03-01 09:30:00\n\n"+
+		"           fe │ fe started\n"+
+		"           fe │ WARNING: fe is slow\n", body)
+
+	code, body = f.getPreviousLogs("?level=warn")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, "fe is slow")
+	assert.NotContains(t, body, "started\n")
+
+	code, _ = f.getPreviousLogs("?level=loud")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestPreviousLogsTimestamps(t *testing.T) {
+	f := newTestFixture(t)
+	start := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	f.writePreviousSession(start, []loghistory.Record{
+		{Time: start.Add(time.Minute), Resource: "fe", SpanID: "pod:fe", Level: "info", Text: "fe started\n"},
+	})
+
+	code, body := f.getPreviousLogs("?timestamps=absolute")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, "\n2024-03-01T09:31:00.000Z            fe │ fe started\n")
+
+	code, body = f.getPreviousLogs("?timestamps=relative")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Contains(t, body, "\n  +60.000s            fe │ fe started\n")
+
+	code, _ = f.getPreviousLogs("?timestamps=absolute&timezone=mars")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestPreviousLogsNoSession(t *testing.T) {
+	f := newTestFixture(t)
+	code, _ := f.getPreviousLogs("")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func (f *serverFixture) writePreviousSession(start time.Time, records []loghistory.Record) {
+	b, err := json.Marshal(loghistory.Session{StartTime: start})
+	require.NoError(f.t, err)
+	require.NoError(f.t, os.WriteFile(filepath.Join(f.previousLogsDir, "session.json"), b, 0600))
+
+	var logs []byte
+	for _, r := range records {
+		b, err := json.Marshal(r)
+		require.NoError(f.t, err)
+		logs = append(append(logs, b...), '\n')
+	}
+	require.NoError(f.t, os.WriteFile(filepath.Join(f.previousLogsDir, "logs.jsonl"), logs, 0600))
+}
+
+func (f *serverFixture) getPreviousLogs(query string) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/previous"+query, nil)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	return rr.Code, rr.Body.String()
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Searches the logs of all resources, line-by-line.
+//
+//	GET /api/logs/search?q=REGEX&resource=NAME&level=LEVEL&since=TIME&until=TIME&limit=N
+//
+// All parameters are optional:
+//   - q: only return lines matching this regular expression (RE2 syntax).
+//     Prefix it with (?i) for a case-insensitive search.
+//   - resource: only search logs from this resource. May be repeated.
+//     Logs that don't belong to any resource have an empty resource name.
+//   - level: only return lines at least this severe.
+//     One of debug, verbose, info, warn, or error.
+//   - since, until: only return lines logged in this time range, in RFC3339 format.
+//   - limit: the most lines to return. When more lines match, the most
+//     recent ones are returned. Defaults to 1000.
+//
+// Unlike searching in the browser, this searches all the logs Tilt still has,
+// not only the ones the web UI has rendered.
+const logSearchPath = "/api/logs/search"
+
+const defaultLogSearchLimit = 1000
+const maxLogSearchLimit = 10000
+
+type logSearchResponse struct {
+	Matches []logSearchMatch `json:"matches"`
+
+	// Whether more lines matched than the limit.
+	Truncated bool `json:"truncated"`
+}
+
+type logSearchMatch struct {
+	Checkpoint logstore.Checkpoint `json:"checkpoint"`
+	Resource   string              `json:"resource,omitempty"`
+	SpanID     string              `json:"spanId"`
+	Level      string              `json:"level"`
+	Time       time.Time           `json:"time"`
+	Text       string              `json:"text"`
+
+	// Where the pattern matched in the text, as [start, end) offsets
+	// in UTF-16 code units, so that JavaScript can slice the text directly.
+	Ranges [][2]int `json:"ranges,omitempty"`
+}
+
+func parseLogSearchRequest(req *http.Request) (logstore.SearchOptions, error) {
+	query := req.URL.Query()
+	opts := logstore.SearchOptions{Limit: defaultLogSearchLimit}
+
+	if q := query.Get("q"); q != "" {
+		pattern, err := regexp.Compile(q)
+		if err != nil {
+			return logstore.SearchOptions{}, fmt.Errorf("invalid q: %v", err)
+		}
+		opts.Pattern = pattern
+	}
+
+	if resources := query["resource"]; len(resources) > 0 {
+		opts.ManifestNames = make(model.ManifestNameSet, len(resources))
+		for _, r := range resources {
+			opts.ManifestNames[model.ManifestName(r)] = true
+		}
+	}
+
+	level, err := parseExtLogLevel(query.Get("level"))
+	if err != nil {
+		return logstore.SearchOptions{}, err
+	}
+	opts.MinLevel = level
+
+	for _, param := range []struct {
+		name string
+		t    *time.Time
+	}{{"since", &opts.Since}, {"until", &opts.Until}} {
+		v := query.Get(param.name)
+		if v == "" {
+			continue
+		}
+		*param.t, err = time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return logstore.SearchOptions{}, fmt.Errorf("invalid %s %q: must be an RFC3339 time", param.name, v)
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > maxLogSearchLimit {
+			return logstore.SearchOptions{}, fmt.Errorf("invalid limit %q: must be between 1 and %d", limit, maxLogSearchLimit)
+		}
+		opts.Limit = n
+	}
+	return opts, nil
+}
+
+func (s *HeadsUpServer) HandleLogSearch(w http.ResponseWriter, req *http.Request) {
+	opts, err := parseLogSearchRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := s.store.RLockState()
+	matches, truncated := state.LogStore.Search(opts)
+	s.store.RUnlockState()
+
+	resp := logSearchResponse{
+		Matches:   make([]logSearchMatch, 0, len(matches)),
+		Truncated: truncated,
+	}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, logSearchMatch{
+			Checkpoint: m.Checkpoint,
+			Resource:   m.ManifestName.String(),
+			SpanID:     string(m.SpanID),
+			Level:      extLogLevelName(m.Level),
+			Time:       m.Time,
+			Text:       m.Text,
+			Ranges:     utf16Ranges(m.Text, m.Ranges),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("rendering log search: %v", err)
+	}
+}
+
+// Converts byte offsets in text to UTF-16 offsets.
+func utf16Ranges(text string, ranges [][2]int) [][2]int {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	result := make([][2]int, 0, len(ranges))
+	byteOffset, utf16Offset := 0, 0
+	advance := func(to int) int {
+		for byteOffset < to {
+			r, size := utf8.DecodeRuneInString(text[byteOffset:])
+			byteOffset += size
+			utf16Offset += len(utf16.Encode([]rune{r}))
+		}
+		return utf16Offset
+	}
+	for _, r := range ranges {
+		start := advance(r[0])
+		end := advance(r[1])
+		result = append(result, [2]int{start, end})
+	}
+	return result
+}
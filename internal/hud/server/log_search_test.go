@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+type logSearchResult struct {
+	Matches []struct {
+		Checkpoint int      `json:"checkpoint"`
+		Resource   string   `json:"resource"`
+		Level      string   `json:"level"`
+		Text       string   `json:"text"`
+		Ranges     [][2]int `json:"ranges"`
+	} `json:"matches"`
+	Truncated bool `json:"truncated"`
+}
+
+func TestLogSearch(t *testing.T) {
+	f := newTestFixture(t)
+	f.appendLog("fe", logger.InfoLvl, "GET /health 200\n")
+	f.appendLog("be", logger.WarnLvl, "slow query: 2.3s\n")
+	f.appendLog("fe", logger.ErrorLvl, "GET /api 500\n")
+
+	status, result := f.searchLogs(url.Values{"q": {`GET /\w+ [45]\d\d`}})
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "fe", result.Matches[0].Resource)
+	assert.Equal(t, "error", result.Matches[0].Level)
+	assert.Equal(t, "GET /api 500", result.Matches[0].Text)
+	assert.Equal(t, [][2]int{{0, 12}}, result.Matches[0].Ranges)
+	assert.Equal(t, 2, result.Matches[0].Checkpoint)
+
+	_, result = f.searchLogs(url.Values{"level": {"warn"}, "resource": {"be"}})
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "slow query: 2.3s", result.Matches[0].Text)
+
+	_, result = f.searchLogs(url.Values{"limit": {"2"}})
+	assert.True(t, result.Truncated)
+	assert.Len(t, result.Matches, 2)
+}
+
+func TestLogSearchRangesAreUTF16(t *testing.T) {
+	f := newTestFixture(t)
+	f.appendLog("fe", logger.InfoLvl, "🚀 deployed\n")
+
+	_, result := f.searchLogs(url.Values{"q": {"deployed"}})
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, [][2]int{{3, 11}}, result.Matches[0].Ranges)
+}
+
+func TestLogSearchBadParams(t *testing.T) {
+	f := newTestFixture(t)
+
+	for _, params := range []url.Values{
+		{"q": {"("}},
+		{"level": {"loud"}},
+		{"since": {"yesterday"}},
+		{"limit": {"0"}},
+	} {
+		status, _ := f.searchLogs(params)
+		assert.Equal(t, http.StatusBadRequest, status, params.Encode())
+	}
+}
+
+func (f *serverFixture) searchLogs(params url.Values) (int, logSearchResult) {
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/search?"+params.Encode(), nil)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+
+	var result logSearchResult
+	if rr.Code == http.StatusOK {
+		require.NoError(f.t, json.Unmarshal(rr.Body.Bytes(), &result))
+	}
+	return rr.Code, result
+}
+
+func TestTruncatedLogs(t *testing.T) {
+	f := newTestFixture(t)
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.SetRetention(logstore.Retention{MaxBytesPerManifest: 20, SpillDir: t.TempDir()})
+	f.st.UnlockMutableState()
+
+	for i := 0; i < 10; i++ {
+		f.appendLog("fe", logger.InfoLvl, fmt.Sprintf("line %d\n", i))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/truncated?resource=fe", nil)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "line 0\nline 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\n", rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/logs/truncated?resource=be", nil)
+	rr = httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Loads the logs of a resource that Tilt truncated to save memory,
+// as plain text, oldest first.
+//
+//	GET /api/logs/truncated?resource=NAME
+//
+// Logs that don't belong to any resource have an empty resource name.
+//
+// Only works when Tilt saves truncated logs to disk (tilt up --log-spill).
+const truncatedLogsPath = "/api/logs/truncated"
+
+func (s *HeadsUpServer) HandleTruncatedLogs(w http.ResponseWriter, req *http.Request) {
+	mn := model.ManifestName(req.URL.Query().Get("resource"))
+
+	state := s.store.RLockState()
+	spill := state.LogStore.Spill()
+	s.store.RUnlockState()
+
+	if spill == nil || !spill.HasManifest(mn) {
+		http.Error(w, fmt.Sprintf("no truncated logs saved for resource %q", mn), http.StatusNotFound)
+		return
+	}
+
+	// Reading from disk may be slow, so don't hold the state lock.
+	text, err := spill.ManifestLog(mn)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err = io.WriteString(w, text)
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("rendering truncated logs: %v", err)
+	}
+}
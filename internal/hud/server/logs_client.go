@@ -0,0 +1,348 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/hud"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// This file defines the client side of the log stream, for `tilt logs`.
+
+// Options for printing the logs of a running Tilt with StreamLogs.
+type LogStreamOptions struct {
+	// Keep printing new logs until the context is done.
+	Follow bool
+
+	// Only print logs from these resources, or from resources with these labels.
+	Resources []string
+	Labels    []string
+
+	// Only print logs at least this severe. One of debug, verbose, info, warn, or error.
+	Level string
+
+	// Only print logs from the last Since. Prints all the logs Tilt still has when zero.
+	Since time.Duration
+
+	// Leave out the resource name in front of each line.
+	NoPrefix bool
+
+	// Put a timestamp in front of each line. Relative timestamps count
+	// from when the running Tilt started.
+	Timestamps logstore.Timestamps
+
+	// Print each line as text or as JSON. Defaults to text.
+	Format hud.LogFormat
+}
+
+// Prints logs from the log stream of a running Tilt.
+func StreamLogs(ctx context.Context, url model.WebURL, opts LogStreamOptions, printer *hud.IncrementalPrinter) error {
+	url.Path = logsPath
+	query := neturl.Values{}
+	query.Set("follow", strconv.FormatBool(opts.Follow))
+	for _, r := range opts.Resources {
+		query.Add("resource", r)
+	}
+	for _, l := range opts.Labels {
+		query.Add("label", l)
+	}
+	if opts.Level != "" {
+		query.Set("level", opts.Level)
+	}
+	if opts.Since > 0 {
+		// Round up, so that we don't leave out the oldest logs asked for.
+		seconds := int((opts.Since + time.Second - 1) / time.Second)
+		query.Set("sinceSeconds", strconv.Itoa(seconds))
+	}
+	wsURL := url
+	url.RawQuery = query.Encode()
+	logger.Get(ctx).Debugf("connecting to %s", url.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "connecting to %s", url.String())
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// Servers from before the log stream existed serve the web UI
+	// instead, for any path they don't know.
+	if resp.StatusCode == http.StatusNotFound ||
+		(resp.StatusCode == http.StatusOK && !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")) {
+		_ = resp.Body.Close()
+		return streamLogsFromWebsocket(ctx, wsURL, opts, printer)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var e externalError
+		err := json.NewDecoder(resp.Body).Decode(&e)
+		if err != nil || e.Error == "" {
+			return fmt.Errorf("streaming logs: %s", resp.Status)
+		}
+		return errors.New(e.Error)
+	}
+
+	// Servers from before timestamps existed don't send their start time,
+	// so relative timestamps count from the first line instead.
+	start, err := time.Parse(time.RFC3339Nano, resp.Header.Get(startTimeHeader))
+	if err == nil && opts.Timestamps.Start.IsZero() {
+		opts.Timestamps.Start = start
+	}
+
+	p := newLogStreamPrinter(opts, printer)
+	defer p.flush()
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var e externalLogEvent
+		err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e)
+		if err != nil {
+			return errors.Wrap(err, "parsing log event")
+		}
+		p.print(e)
+	}
+
+	err = scanner.Err()
+	if err != nil && ctx.Err() == nil {
+		return errors.Wrap(err, "streaming logs")
+	}
+	return nil
+}
+
+// Rebuilds streamed log segments into lines, so that they're
+// printed the same way as in the terminal.
+type logStreamPrinter struct {
+	logstore   *logstore.LogStore
+	checkpoint logstore.Checkpoint
+	noPrefix   bool
+	timestamps logstore.Timestamps
+	printer    *hud.IncrementalPrinter
+
+	// Only set in the JSON log format, which doesn't need the logstore.
+	json *hud.JSONLinePrinter
+}
+
+func newLogStreamPrinter(opts LogStreamOptions, printer *hud.IncrementalPrinter) *logStreamPrinter {
+	p := &logStreamPrinter{
+		logstore:   logstore.NewLogStore(),
+		noPrefix:   opts.NoPrefix,
+		timestamps: opts.Timestamps,
+		printer:    printer,
+	}
+	if opts.Format == hud.LogFormatJSON {
+		p.json = hud.NewJSONLinePrinter(printer.Stdout())
+	}
+	return p
+}
+
+func (p *logStreamPrinter) print(e externalLogEvent) {
+	if p.json != nil {
+		le := streamedLogEvent{event: e}
+		p.json.Print([]logstore.StreamSegment{{
+			LogSegment: logstore.LogSegment{
+				SpanID: le.SpanID(),
+				Time:   le.Time(),
+				Text:   le.Message(),
+				Level:  le.Level(),
+			},
+			ManifestName: le.ManifestName(),
+		}})
+		return
+	}
+
+	// The server has already removed secrets.
+	p.logstore.Append(streamedLogEvent{event: e}, model.SecretSet{})
+	p.printer.Print(p.logstore.ContinuingLinesWithOptions(p.checkpoint, logstore.LineOptions{
+		SuppressPrefix: p.noPrefix,
+		Timestamps:     p.timestamps,
+	}))
+	p.checkpoint = p.logstore.Checkpoint()
+}
+
+// Prints the last line, even if the stream ended before it was complete.
+func (p *logStreamPrinter) flush() {
+	if p.json != nil {
+		p.json.Flush()
+	}
+}
+
+// A log event read back from the log stream.
+type streamedLogEvent struct {
+	event externalLogEvent
+}
+
+var _ logstore.LogEvent = streamedLogEvent{}
+
+func (e streamedLogEvent) Message() []byte       { return []byte(e.event.Text) }
+func (e streamedLogEvent) Time() time.Time       { return e.event.Time }
+func (e streamedLogEvent) Fields() logger.Fields { return nil }
+
+func (e streamedLogEvent) ManifestName() model.ManifestName {
+	return model.ManifestName(e.event.Resource)
+}
+
+func (e streamedLogEvent) SpanID() logstore.SpanID {
+	return logstore.SpanID(e.event.SpanID)
+}
+
+func (e streamedLogEvent) Level() logger.Level {
+	level, err := parseExtLogLevel(e.event.Level)
+	if err != nil || level == logger.NoneLvl {
+		return logger.InfoLvl
+	}
+	return level
+}
+
+// Older servers only send logs to the web UI's websocket,
+// and can't filter them, so we filter them here instead.
+func streamLogsFromWebsocket(ctx context.Context, url model.WebURL, opts LogStreamOptions, printer *hud.IncrementalPrinter) error {
+	p := newLogStreamPrinter(opts, printer)
+	defer p.flush()
+	h, err := newWebsocketLogHandler(opts, p)
+	if err != nil {
+		return err
+	}
+
+	url.Scheme = "ws"
+	url.Path = "/ws/view"
+	logger.Get(ctx).Debugf("connecting to %s", url.String())
+
+	conn, _, err := websocket.DefaultDialer.Dial(url.String(), nil)
+	if err != nil {
+		return errors.Wrapf(err, "dialing websocket %s", url.String())
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return newWebsocketReader(conn, opts.Follow, h).Listen(ctx)
+}
+
+// Turns the log segments in web UI views into log stream events.
+type websocketLogHandler struct {
+	resources model.ManifestNameSet
+	labels    []string
+	minLevel  logger.Level
+	since     time.Time
+	printer   *logStreamPrinter
+
+	// The checkpoint of the last segment the server sent, so that we don't
+	// print logs twice when the server re-sends them.
+	//
+	// This is the server's checkpoint, so it can't be compared to ours.
+	serverWatermark int32
+}
+
+var _ ViewHandler = &websocketLogHandler{}
+
+func newWebsocketLogHandler(opts LogStreamOptions, printer *logStreamPrinter) (*websocketLogHandler, error) {
+	level, err := parseExtLogLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &websocketLogHandler{
+		resources: make(model.ManifestNameSet, len(opts.Resources)),
+		labels:    opts.Labels,
+		minLevel:  level,
+		printer:   printer,
+	}
+	for _, r := range opts.Resources {
+		h.resources[model.ManifestName(r)] = true
+	}
+	if opts.Since > 0 {
+		h.since = time.Now().Add(-opts.Since)
+	}
+	return h, nil
+}
+
+func (h *websocketLogHandler) Handle(v *proto_webview.View) error {
+	if v == nil {
+		return nil
+	}
+
+	// Labels are matched against resources as they appear.
+	for _, uir := range v.UiResources {
+		for _, label := range h.labels {
+			if _, ok := uir.Labels[label]; ok {
+				h.resources[model.ManifestName(uir.Name)] = true
+				break
+			}
+		}
+	}
+
+	if v.LogList == nil || v.LogList.FromCheckpoint == -1 {
+		// The server has no new logs to send.
+		return nil
+	}
+
+	segments := v.LogList.Segments
+	if v.LogList.FromCheckpoint < h.serverWatermark {
+		// The server is re-sending some logs we already have, so slice them off.
+		skip := int(h.serverWatermark - v.LogList.FromCheckpoint)
+		if skip > len(segments) {
+			skip = len(segments)
+		}
+		segments = segments[skip:]
+	}
+	h.serverWatermark = v.LogList.ToCheckpoint
+
+	filtered := len(h.resources) > 0 || len(h.labels) > 0
+	for _, seg := range segments {
+		span, ok := v.LogList.Spans[seg.SpanId]
+		if !ok {
+			continue
+		}
+		if filtered && !h.resources[model.ManifestName(span.ManifestName)] {
+			continue
+		}
+
+		level := logger.InfoLvl
+		for _, l := range extLogLevels {
+			if l.level.ToProtoID() == int32(seg.Level) {
+				level = l.level
+			}
+		}
+		if !h.minLevel.ShouldDisplay(level) {
+			continue
+		}
+
+		ts := seg.Time.AsTime()
+		if ts.Before(h.since) {
+			continue
+		}
+
+		h.printer.print(externalLogEvent{
+			Resource: span.ManifestName,
+			SpanID:   seg.SpanId,
+			Level:    extLogLevelName(level),
+			Time:     ts,
+			Text:     seg.Text,
+		})
+	}
+	return nil
+}
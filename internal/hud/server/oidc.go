@@ -0,0 +1,252 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"golang.org/x/oauth2"
+)
+
+// Signs users in with an OpenID Connect provider, using the
+// authorization code flow.
+//
+// Discovery, signing keys, and ID token checks are all handled by go-oidc.
+type oidcProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+type oidcClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified *bool  `json:"email_verified"`
+}
+
+// What we remember about a sign-in between sending the user
+// to the provider and getting them back.
+type oidcState struct {
+	State   string `json:"state"`
+	Next    string `json:"next"`
+	Expires int64  `json:"expires"`
+}
+
+func newOIDCProvider(config OIDCConfig, client *http.Client) *oidcProvider {
+	return &oidcProvider{config: config, client: client}
+}
+
+// Looks up the provider the first time someone signs in, so that a
+// provider that's down doesn't stop Tilt from starting.
+func (p *oidcProvider) discover() (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	// The provider fetches its signing keys with this context for as
+	// long as Tilt runs, so it can't be a request context.
+	ctx := oidc.ClientContext(context.Background(), p.client)
+	provider, err := oidc.NewProvider(ctx, p.config.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("discovering OIDC provider: %v", err)
+	}
+	p.provider = provider
+	return provider, nil
+}
+
+func (p *oidcProvider) oauth2Config(redirectURL string) (*oauth2.Config, error) {
+	provider, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  redirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}, nil
+}
+
+// Checks that the ID token was signed by the provider for this client,
+// and returns its claims.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, rawIDToken string, nonce string, now func() time.Time) (oidcClaims, error) {
+	provider, err := p.discover()
+	if err != nil {
+		return oidcClaims{}, err
+	}
+
+	verifier := provider.Verifier(&oidc.Config{ClientID: p.config.ClientID, Now: now})
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return oidcClaims{}, fmt.Errorf("invalid ID token: %v", err)
+	}
+
+	var claims oidcClaims
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return oidcClaims{}, fmt.Errorf("invalid ID token: %v", err)
+	}
+
+	// go-oidc leaves the nonce to us.
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return oidcClaims{}, fmt.Errorf("invalid ID token: nonce doesn't match")
+	}
+	return claims, nil
+}
+
+// Decides what a user signed in with OIDC may do, if anything.
+func (c OIDCConfig) roleForEmail(email string) (TeamRole, bool) {
+	if emailMatches(c.OwnerEmails, email) {
+		return TeamRoleOwner, true
+	}
+	if emailMatches(c.OperatorEmails, email) {
+		return TeamRoleOperator, true
+	}
+	if emailMatches(c.ViewerEmails, email) {
+		return TeamRoleViewer, true
+	}
+	return "", false
+}
+
+func emailMatches(patterns []string, email string) bool {
+	email = strings.ToLower(email)
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if strings.HasPrefix(p, "@") {
+			if strings.HasSuffix(email, p) {
+				return true
+			}
+		} else if p == email {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *teamAuth) oidcRedirectURL(req *http.Request) string {
+	if a.config.OIDC.RedirectURL != "" {
+		return a.config.OIDC.RedirectURL
+	}
+	scheme := "http"
+	if isSecureRequest(req) {
+		scheme = "https"
+	}
+	u := url.URL{Scheme: scheme, Host: req.Host, Path: teamOIDCCallbackPath}
+	return u.String()
+}
+
+func (a *teamAuth) serveOIDCLogin(w http.ResponseWriter, req *http.Request) {
+	if a.oidc == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	config, err := a.oidc.oauth2Config(a.oidcRedirectURL(req))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	b := make([]byte, 16)
+	_, err = rand.Read(b)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("generating state: %v", err), http.StatusInternalServerError)
+		return
+	}
+	expires := a.now().Add(teamOIDCStateTTL)
+	state := oidcState{
+		State:   base64.RawURLEncoding.EncodeToString(b),
+		Next:    safeNext(req.URL.Query().Get("next")),
+		Expires: expires.Unix(),
+	}
+	value, err := a.sign(state)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("signing state: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, req, teamOIDCStateCookieName, value, expires)
+
+	// The state doubles as the nonce, which ties the ID token to this sign-in.
+	authURL := config.AuthCodeURL(state.State, oauth2.SetAuthURLParam("nonce", state.State))
+	http.Redirect(w, req, authURL, http.StatusFound)
+}
+
+func (a *teamAuth) serveOIDCCallback(w http.ResponseWriter, req *http.Request) {
+	if a.oidc == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	var state oidcState
+	cookie, err := req.Cookie(teamOIDCStateCookieName)
+	if err == nil {
+		err = a.verify(cookie.Value, &state)
+	}
+	if err != nil || !a.now().Before(time.Unix(state.Expires, 0)) {
+		http.Error(w, "Sign-in expired. Try again.", http.StatusBadRequest)
+		return
+	}
+	a.clearCookie(w, req, teamOIDCStateCookieName)
+
+	query := req.URL.Query()
+	if errMsg := query.Get("error"); errMsg != "" {
+		http.Error(w, fmt.Sprintf("Sign-in failed: %s %s", errMsg, query.Get("error_description")), http.StatusUnauthorized)
+		return
+	}
+	if query.Get("state") != state.State {
+		http.Error(w, "Sign-in failed: state doesn't match", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.WithValue(req.Context(), oauth2.HTTPClient, a.oidc.client)
+	config, err := a.oidc.oauth2Config(a.oidcRedirectURL(req))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	token, err := config.Exchange(ctx, query.Get("code"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Sign-in failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		http.Error(w, "Sign-in failed: provider didn't return an ID token", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := a.oidc.verifyIDToken(ctx, rawIDToken, state.State, a.now)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Sign-in failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+	if claims.Email == "" || (claims.EmailVerified != nil && !*claims.EmailVerified) {
+		http.Error(w, "Sign-in failed: provider didn't return a verified email", http.StatusForbidden)
+		return
+	}
+
+	role, ok := a.config.OIDC.roleForEmail(claims.Email)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Forbidden: %s may not view this Tilt session", claims.Email), http.StatusForbidden)
+		return
+	}
+
+	a.startSession(w, req, TeamIdentity{Name: claims.Email, Role: role})
+	http.Redirect(w, req, state.Next, http.StatusFound)
+}
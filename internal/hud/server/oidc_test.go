@@ -0,0 +1,246 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func TestOIDCSignInAsViewer(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	f := newOIDCFixture(t, p)
+
+	session := f.signIn("dev@example.com", "/r/fe/overview")
+	rr := f.request(http.MethodGet, "/api/view", remoteAddr, func(req *http.Request) { req.AddCookie(session) })
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, TeamIdentity{Name: "dev@example.com", Role: TeamRoleViewer}, f.lastIdentity)
+
+	rr = f.request(http.MethodPost, "/api/trigger", remoteAddr, func(req *http.Request) { req.AddCookie(session) })
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestOIDCSignInAsOwner(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	f := newOIDCFixture(t, p)
+
+	session := f.signIn("lead@example.com", "/")
+	rr := f.request(http.MethodPost, "/api/trigger", remoteAddr, func(req *http.Request) { req.AddCookie(session) })
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, TeamIdentity{Name: "lead@example.com", Role: TeamRoleOwner}, f.lastIdentity)
+}
+
+func TestOIDCSignInAsOperator(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	f := newOIDCFixture(t, p)
+
+	session := f.signIn("oncall@example.com", "/")
+	withSession := func(req *http.Request) { req.AddCookie(session) }
+	rr := f.request(http.MethodPost, "/api/trigger", remoteAddr, withSession)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, TeamIdentity{Name: "oncall@example.com", Role: TeamRoleOperator}, f.lastIdentity)
+
+	rr = f.request(http.MethodPost, "/api/set_tiltfile_args", remoteAddr, withSession)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "you have operator access")
+}
+
+func TestOIDCSignInRejectsUnknownEmail(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	f := newOIDCFixture(t, p)
+
+	rr := f.callback("stranger@elsewhere.com")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "stranger@elsewhere.com may not view")
+}
+
+func TestOIDCSignInRejectsWrongAudience(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	p.audience = "some-other-app"
+	f := newOIDCFixture(t, p)
+
+	rr := f.callback("dev@example.com")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), `expected audience "tilt"`)
+}
+
+func TestOIDCSignInRejectsWrongNonce(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	p.nonce = "replayed"
+	f := newOIDCFixture(t, p)
+
+	rr := f.callback("dev@example.com")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "nonce doesn't match")
+}
+
+func TestOIDCSignInRejectsUntrustedSigner(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	var err error
+	p.signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f := newOIDCFixture(t, p)
+
+	rr := f.callback("dev@example.com")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid ID token")
+}
+
+func TestOIDCCallbackRequiresState(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	f := newOIDCFixture(t, p)
+
+	rr := f.request(http.MethodGet, teamOIDCCallbackPath+"?code=abc&state=xyz", remoteAddr, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestEmailMatches(t *testing.T) {
+	patterns := []string{"Lead@Example.com", "@corp.example.com"}
+	assert.True(t, emailMatches(patterns, "lead@example.com"))
+	assert.True(t, emailMatches(patterns, "dev@corp.example.com"))
+	assert.False(t, emailMatches(patterns, "dev@example.com"))
+	assert.False(t, emailMatches(patterns, "dev@notcorp.example.com"))
+}
+
+type fakeOIDCProvider struct {
+	t      *testing.T
+	server *httptest.Server
+
+	// The key the provider publishes.
+	publishedKey *rsa.PrivateKey
+
+	// The key the provider signs ID tokens with.
+	signingKey *rsa.PrivateKey
+
+	audience string
+
+	// Overrides the nonce in ID tokens, if set.
+	nonce string
+
+	// What the last authorization request asked for.
+	lastNonce string
+	email     string
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &fakeOIDCProvider{t: t, publishedKey: key, signingKey: key, audience: "tilt"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{
+				Key:       &p.publishedKey.PublicKey,
+				KeyID:     "key-1",
+				Algorithm: "RS256",
+				Use:       "sig",
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		nonce := p.lastNonce
+		if p.nonce != "" {
+			nonce = p.nonce
+		}
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":   p.server.URL,
+			"sub":   "user-1",
+			"aud":   p.audience,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+			"nonce": nonce,
+			"email": p.email,
+		})
+		token.Header["kid"] = "key-1"
+		idToken, err := token.SignedString(p.signingKey)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+type oidcFixture struct {
+	*teamAuthFixture
+	provider *fakeOIDCProvider
+}
+
+func newOIDCFixture(t *testing.T, p *fakeOIDCProvider) *oidcFixture {
+	a := newTestTeamAuth(t, TeamAuthConfig{
+		OIDC: OIDCConfig{
+			IssuerURL:      p.server.URL,
+			ClientID:       "tilt",
+			ClientSecret:   "shh",
+			ViewerEmails:   []string{"@example.com"},
+			OperatorEmails: []string{"oncall@example.com"},
+			OwnerEmails:    []string{"lead@example.com"},
+		},
+	})
+	return &oidcFixture{teamAuthFixture: newTeamAuthFixture(t, a), provider: p}
+}
+
+// Starts a sign-in, then follows the provider's redirect back to Tilt,
+// signed in as the given email.
+func (f *oidcFixture) callback(email string) *httptest.ResponseRecorder {
+	return f.callbackWithNext(email, "/")
+}
+
+func (f *oidcFixture) callbackWithNext(email, next string) *httptest.ResponseRecorder {
+	rr := f.request(http.MethodGet, teamOIDCLoginPath+"?"+url.Values{"next": {next}}.Encode(), remoteAddr, nil)
+	require.Equal(f.t, http.StatusFound, rr.Code)
+
+	authURL, err := url.Parse(rr.Header().Get("Location"))
+	require.NoError(f.t, err)
+	assert.Equal(f.t, f.provider.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(f.t, "http://example.com/auth/oidc/callback", authURL.Query().Get("redirect_uri"))
+
+	state := authURL.Query().Get("state")
+	f.provider.lastNonce = authURL.Query().Get("nonce")
+	f.provider.email = email
+
+	var stateCookie *http.Cookie
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == teamOIDCStateCookieName {
+			stateCookie = c
+		}
+	}
+	require.NotNil(f.t, stateCookie)
+
+	callbackURL := teamOIDCCallbackPath + "?" + url.Values{"code": {"abc"}, "state": {state}}.Encode()
+	return f.request(http.MethodGet, callbackURL, remoteAddr, func(req *http.Request) {
+		req.AddCookie(stateCookie)
+	})
+}
+
+func (f *oidcFixture) signIn(email, next string) *http.Cookie {
+	rr := f.callbackWithNext(email, next)
+	require.Equal(f.t, http.StatusFound, rr.Code, rr.Body.String())
+	assert.Equal(f.t, next, rr.Header().Get("Location"))
+	return sessionCookie(f.t, rr)
+}
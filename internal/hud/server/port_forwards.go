@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesdiscovery"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Ad-hoc port forwards are the ones users add at runtime from the web UI,
+// rather than declaring them in the Tiltfile.
+//
+//	GET    /api/port_forwards?resource=NAME
+//	POST   /api/port_forwards {"resource": NAME, "containerPort": N, "localPort": N}
+//	DELETE /api/port_forwards/NAME
+//
+// Each one follows the current pod of its resource, and lasts until it's
+// deleted or Tilt exits.
+const portForwardsPath = "/api/port_forwards"
+
+type portForwardPayload struct {
+	Resource      string `json:"resource"`
+	ContainerPort int32  `json:"containerPort"`
+	LocalPort     int32  `json:"localPort"`
+}
+
+func (s *HeadsUpServer) registerPortForwardRoutes(r *mux.Router) {
+	r.HandleFunc(portForwardsPath, s.HandleListPortForwards).Methods(http.MethodGet)
+	r.HandleFunc(portForwardsPath, s.HandleCreatePortForward).Methods(http.MethodPost)
+	r.HandleFunc(portForwardsPath+"/{name}", s.HandleDeletePortForward).Methods(http.MethodDelete)
+}
+
+// Responds with a PortForwardList of the ad-hoc port forwards,
+// optionally only the ones for a single resource.
+func (s *HeadsUpServer) HandleListPortForwards(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	var list v1alpha1.PortForwardList
+	err := s.ctrlClient.List(ctx, &list, ctrlclient.HasLabels{v1alpha1.LabelPortForwardDiscovery})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("listing port forwards: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resource := req.URL.Query().Get("resource")
+	items := []v1alpha1.PortForward{}
+	for _, pf := range list.Items {
+		if resource != "" && pf.Annotations[v1alpha1.AnnotationManifest] != resource {
+			continue
+		}
+		items = append(items, pf)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	list.Items = items
+
+	writeExtJSON(w, http.StatusOK, &list)
+}
+
+// Responds with:
+// * 201/the new PortForward on success
+// * 400/error message on badly formed requests (e.g., invalid ports)
+// * 404/error message if the resource doesn't have a running pod
+// * 409/error message if the same port forward already exists
+func (s *HeadsUpServer) HandleCreatePortForward(w http.ResponseWriter, req *http.Request) {
+	var payload portForwardPayload
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if payload.Resource == "" {
+		http.Error(w, "must specify a resource", http.StatusBadRequest)
+		return
+	}
+	if payload.ContainerPort <= 0 || payload.ContainerPort > 65535 {
+		http.Error(w, fmt.Sprintf("invalid container port %d: must be between 1 and 65535", payload.ContainerPort), http.StatusBadRequest)
+		return
+	}
+	// A local port of 0 picks a free port.
+	if payload.LocalPort < 0 || payload.LocalPort > 65535 {
+		http.Error(w, fmt.Sprintf("invalid local port %d: must be between 0 and 65535", payload.LocalPort), http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Context()
+	kd, err := s.discoveryForResource(ctx, payload.Resource)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("looking up resource %s: %v", payload.Resource, err), http.StatusInternalServerError)
+		return
+	}
+	if kd == nil {
+		http.Error(w, fmt.Sprintf("resource %s is not a Kubernetes resource", payload.Resource), http.StatusNotFound)
+		return
+	}
+	if errs := validation.IsValidLabelValue(kd.Name); len(errs) > 0 {
+		http.Error(w, fmt.Sprintf("resource %s can't have port forwards: %v", payload.Resource, errs), http.StatusBadRequest)
+		return
+	}
+
+	pod := kubernetesdiscovery.PickBestPortForwardPod(kd)
+	if pod == nil {
+		http.Error(w, fmt.Sprintf("resource %s has no running pod", payload.Resource), http.StatusNotFound)
+		return
+	}
+
+	pf := &v1alpha1.PortForward{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-adhoc-%d-%d", kd.Name, payload.ContainerPort, payload.LocalPort),
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest: payload.Resource,
+			},
+			Labels: map[string]string{
+				v1alpha1.LabelPortForwardDiscovery: kd.Name,
+			},
+		},
+		Spec: v1alpha1.PortForwardSpec{
+			PodName:   pod.Name,
+			Namespace: pod.Namespace,
+			Cluster:   kd.Spec.Cluster,
+			Forwards: []v1alpha1.Forward{
+				{ContainerPort: payload.ContainerPort, LocalPort: payload.LocalPort},
+			},
+		},
+	}
+	err = s.ctrlClient.Create(ctx, pf)
+	if apierrors.IsAlreadyExists(err) {
+		http.Error(w, fmt.Sprintf("port forward %s already exists", pf.Name), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("creating port forward: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeExtJSON(w, http.StatusCreated, pf)
+}
+
+// Deletes an ad-hoc port forward. Port forwards from the Tiltfile
+// can only be removed by editing the Tiltfile.
+func (s *HeadsUpServer) HandleDeletePortForward(w http.ResponseWriter, req *http.Request) {
+	name, err := url.PathUnescape(mux.Vars(req)["name"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid port forward name: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Context()
+	var pf v1alpha1.PortForward
+	err = s.ctrlClient.Get(ctx, types.NamespacedName{Name: name}, &pf)
+	if apierrors.IsNotFound(err) || (err == nil && pf.Labels[v1alpha1.LabelPortForwardDiscovery] == "") {
+		http.Error(w, fmt.Sprintf("no ad-hoc port forward named %s", name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("fetching port forward: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	err = s.ctrlClient.Delete(ctx, &pf)
+	if err != nil && !apierrors.IsNotFound(err) {
+		http.Error(w, fmt.Sprintf("deleting port forward: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Finds the KubernetesDiscovery that watches the pods of a resource.
+// Returns nil if the resource doesn't have one.
+func (s *HeadsUpServer) discoveryForResource(ctx context.Context, resource string) (*v1alpha1.KubernetesDiscovery, error) {
+	var list v1alpha1.KubernetesDiscoveryList
+	err := s.ctrlClient.List(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if list.Items[i].Annotations[v1alpha1.AnnotationManifest] == resource {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestCreatePortForward(t *testing.T) {
+	f := newTestFixture(t)
+	f.createDiscovery("fe", "fe-abc")
+
+	status, body := f.portForwardReq(http.MethodPost, "/api/port_forwards",
+		`{"resource": "fe", "containerPort": 8080, "localPort": 8000}`)
+	require.Equal(t, http.StatusCreated, status, body)
+
+	var pf v1alpha1.PortForward
+	require.NoError(t, f.ctrlClient.Get(f.ctx, types.NamespacedName{Name: "fe-adhoc-8080-8000"}, &pf))
+	assert.Equal(t, "fe-abc", pf.Spec.PodName)
+	assert.Equal(t, "default", pf.Spec.Namespace)
+	assert.Equal(t, []v1alpha1.Forward{{ContainerPort: 8080, LocalPort: 8000}}, pf.Spec.Forwards)
+	assert.Equal(t, "fe", pf.Annotations[v1alpha1.AnnotationManifest])
+	assert.Equal(t, "fe", pf.Labels[v1alpha1.LabelPortForwardDiscovery])
+
+	status, _ = f.portForwardReq(http.MethodPost, "/api/port_forwards",
+		`{"resource": "fe", "containerPort": 8080, "localPort": 8000}`)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestCreatePortForwardBadRequests(t *testing.T) {
+	f := newTestFixture(t)
+	f.createDiscovery("fe", "")
+
+	for _, tc := range []struct {
+		body   string
+		status int
+	}{
+		{`{"resource": "fe", "containerPort": 8080, "extra": 1}`, http.StatusBadRequest},
+		{`{"containerPort": 8080}`, http.StatusBadRequest},
+		{`{"resource": "fe", "containerPort": 0}`, http.StatusBadRequest},
+		{`{"resource": "fe", "containerPort": 8080, "localPort": 70000}`, http.StatusBadRequest},
+		{`{"resource": "be", "containerPort": 8080}`, http.StatusNotFound},
+		{`{"resource": "fe", "containerPort": 8080}`, http.StatusNotFound},
+	} {
+		status, _ := f.portForwardReq(http.MethodPost, "/api/port_forwards", tc.body)
+		assert.Equal(t, tc.status, status, tc.body)
+	}
+}
+
+func TestListAndDeletePortForwards(t *testing.T) {
+	f := newTestFixture(t)
+	f.createDiscovery("fe", "fe-abc")
+	f.createDiscovery("be", "be-abc")
+	for _, body := range []string{
+		`{"resource": "fe", "containerPort": 8080}`,
+		`{"resource": "be", "containerPort": 5432, "localPort": 5432}`,
+	} {
+		status, resp := f.portForwardReq(http.MethodPost, "/api/port_forwards", body)
+		require.Equal(t, http.StatusCreated, status, resp)
+	}
+
+	// Port forwards from the Tiltfile aren't listed, and can't be deleted.
+	err := f.ctrlClient.Create(f.ctx, &v1alpha1.PortForward{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe-fe-abc"},
+		Spec: v1alpha1.PortForwardSpec{
+			PodName:  "fe-abc",
+			Forwards: []v1alpha1.Forward{{ContainerPort: 80, LocalPort: 80}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"be-adhoc-5432-5432", "fe-adhoc-8080-0"}, f.listPortForwards(""))
+	assert.Equal(t, []string{"fe-adhoc-8080-0"}, f.listPortForwards("fe"))
+
+	status, _ := f.portForwardReq(http.MethodDelete, "/api/port_forwards/fe-fe-abc", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body := f.portForwardReq(http.MethodDelete, "/api/port_forwards/fe-adhoc-8080-0", "")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, []string{"be-adhoc-5432-5432"}, f.listPortForwards(""))
+}
+
+func (f *serverFixture) createDiscovery(resource, podName string) {
+	kd := &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        resource,
+			Annotations: map[string]string{v1alpha1.AnnotationManifest: resource},
+		},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{{Namespace: "default"}},
+		},
+	}
+	require.NoError(f.t, f.ctrlClient.Create(f.ctx, kd))
+	if podName == "" {
+		return
+	}
+
+	kd.Status.Pods = []v1alpha1.Pod{{
+		Name:      podName,
+		Namespace: "default",
+		Phase:     string(v1.PodRunning),
+	}}
+	require.NoError(f.t, f.ctrlClient.Status().Update(f.ctx, kd))
+}
+
+func (f *serverFixture) portForwardReq(method, path, body string) (int, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	return rr.Code, rr.Body.String()
+}
+
+func (f *serverFixture) listPortForwards(resource string) []string {
+	status, body := f.portForwardReq(http.MethodGet, "/api/port_forwards?resource="+resource, "")
+	require.Equal(f.t, http.StatusOK, status, body)
+
+	var list v1alpha1.PortForwardList
+	require.NoError(f.t, json.Unmarshal([]byte(body), &list))
+	var names []string
+	for _, pf := range list.Items {
+		names = append(names, pf.Name)
+	}
+	return names
+}
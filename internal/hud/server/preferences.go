@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The web UI saves its view options here, so that they follow the user
+// across browsers.
+//
+//	GET    /api/preferences       -> {"user": USER, "values": {NAME: JSON}}
+//	PUT    /api/preferences/NAME  (body: any JSON value)
+//	DELETE /api/preferences/NAME
+//
+// Every user only sees their own preferences. In team mode, saving them
+// takes edit access, like any other write.
+const preferencesPath = "/api/preferences"
+
+// Users who didn't sign in with their own name share preferences.
+const localPreferencesUser = "local"
+const viewerPreferencesUser = "viewer"
+
+// Large enough for any view option, small enough that one user can't
+// fill up the apiserver.
+const maxPreferenceBytes = 64 * 1024
+
+type preferencesPayload struct {
+	User   string                     `json:"user"`
+	Values map[string]json.RawMessage `json:"values"`
+}
+
+func (s *HeadsUpServer) registerPreferencesRoutes(r *mux.Router) {
+	r.HandleFunc(preferencesPath, s.HandleGetPreferences).Methods(http.MethodGet)
+	r.HandleFunc(preferencesPath+"/{name}", s.HandleSetPreference).Methods(http.MethodPut)
+	r.HandleFunc(preferencesPath+"/{name}", s.HandleDeletePreference).Methods(http.MethodDelete)
+}
+
+// Who the preferences of a request belong to.
+func preferencesUser(id TeamIdentity) string {
+	if id.Name != "" {
+		return id.Name
+	}
+	if id.Role == TeamRoleOwner {
+		return localPreferencesUser
+	}
+	return viewerPreferencesUser
+}
+
+// User names (like emails) aren't valid object names, so we hash them.
+func preferencesObjectName(user string) string {
+	sum := sha256.Sum256([]byte(user))
+	return "user-" + hex.EncodeToString(sum[:])[:16]
+}
+
+func (s *HeadsUpServer) HandleGetPreferences(w http.ResponseWriter, req *http.Request) {
+	user := preferencesUser(teamIdentityFromRequest(req))
+	var prefs v1alpha1.UIPreferences
+	err := s.ctrlClient.Get(req.Context(), types.NamespacedName{Name: preferencesObjectName(user)}, &prefs)
+	if err != nil && !apierrors.IsNotFound(err) {
+		http.Error(w, fmt.Sprintf("fetching preferences: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	payload := preferencesPayload{User: user, Values: map[string]json.RawMessage{}}
+	for name, value := range prefs.Spec.Values {
+		payload.Values[name] = json.RawMessage(value)
+	}
+	writeExtJSON(w, http.StatusOK, payload)
+}
+
+func (s *HeadsUpServer) HandleSetPreference(w http.ResponseWriter, req *http.Request) {
+	name, err := url.PathUnescape(mux.Vars(req)["name"])
+	if err != nil || name == "" {
+		http.Error(w, "invalid preference name", http.StatusBadRequest)
+		return
+	}
+
+	value, err := io.ReadAll(io.LimitReader(req.Body, maxPreferenceBytes+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("reading preference: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(value) > maxPreferenceBytes {
+		http.Error(w, fmt.Sprintf("preference %s is too large: must be at most %d bytes", name, maxPreferenceBytes), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !json.Valid(value) {
+		http.Error(w, fmt.Sprintf("preference %s must be JSON", name), http.StatusBadRequest)
+		return
+	}
+
+	user := preferencesUser(teamIdentityFromRequest(req))
+	err = s.updatePreferences(req.Context(), user, func(values map[string]string) {
+		values[name] = string(value)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("saving preference %s: %v", name, err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HeadsUpServer) HandleDeletePreference(w http.ResponseWriter, req *http.Request) {
+	name, err := url.PathUnescape(mux.Vars(req)["name"])
+	if err != nil || name == "" {
+		http.Error(w, "invalid preference name", http.StatusBadRequest)
+		return
+	}
+
+	user := preferencesUser(teamIdentityFromRequest(req))
+	err = s.updatePreferences(req.Context(), user, func(values map[string]string) {
+		delete(values, name)
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("deleting preference %s: %v", name, err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Applies a change to the user's preferences, creating them if needed.
+//
+// Two browser tabs can save at the same time, so we retry on conflicts.
+func (s *HeadsUpServer) updatePreferences(ctx context.Context, user string, update func(values map[string]string)) error {
+	nn := types.NamespacedName{Name: preferencesObjectName(user)}
+	for i := 0; ; i++ {
+		var prefs v1alpha1.UIPreferences
+		err := s.ctrlClient.Get(ctx, nn, &prefs)
+		if apierrors.IsNotFound(err) {
+			prefs = v1alpha1.UIPreferences{
+				ObjectMeta: metav1.ObjectMeta{Name: nn.Name},
+				Spec: v1alpha1.UIPreferencesSpec{
+					User:   user,
+					Values: map[string]string{},
+				},
+			}
+			update(prefs.Spec.Values)
+			err = s.ctrlClient.Create(ctx, &prefs)
+		} else if err == nil {
+			if prefs.Spec.Values == nil {
+				prefs.Spec.Values = map[string]string{}
+			}
+			update(prefs.Spec.Values)
+			err = s.ctrlClient.Update(ctx, &prefs)
+		}
+
+		retry := apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+		if !retry || i >= 2 {
+			return err
+		}
+	}
+}
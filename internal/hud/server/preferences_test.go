@@ -0,0 +1,73 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+type preferencesResponse struct {
+	User   string                     `json:"user"`
+	Values map[string]json.RawMessage `json:"values"`
+}
+
+func TestPreferences(t *testing.T) {
+	f := newTestFixture(t)
+
+	prefs := f.getPreferences()
+	assert.Equal(t, "local", prefs.User)
+	assert.Empty(t, prefs.Values)
+
+	status, body := f.portForwardReq(http.MethodPut, "/api/preferences/starred", `["fe","be"]`)
+	require.Equal(t, http.StatusOK, status, body)
+	status, body = f.portForwardReq(http.MethodPut, "/api/preferences/groups", `{"frontend":{"expanded":false}}`)
+	require.Equal(t, http.StatusOK, status, body)
+	status, body = f.portForwardReq(http.MethodPut, "/api/preferences/starred", `["fe"]`)
+	require.Equal(t, http.StatusOK, status, body)
+
+	prefs = f.getPreferences()
+	assert.Equal(t, map[string]json.RawMessage{
+		"starred": json.RawMessage(`["fe"]`),
+		"groups":  json.RawMessage(`{"frontend":{"expanded":false}}`),
+	}, prefs.Values)
+
+	var list v1alpha1.UIPreferencesList
+	require.NoError(t, f.ctrlClient.List(f.ctx, &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "local", list.Items[0].Spec.User)
+
+	status, body = f.portForwardReq(http.MethodDelete, "/api/preferences/starred", "")
+	require.Equal(t, http.StatusOK, status, body)
+	prefs = f.getPreferences()
+	assert.Equal(t, map[string]json.RawMessage{
+		"groups": json.RawMessage(`{"frontend":{"expanded":false}}`),
+	}, prefs.Values)
+}
+
+func TestPreferencesBadRequests(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, _ := f.portForwardReq(http.MethodPut, "/api/preferences/starred", `["fe"`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	big := `"` + strings.Repeat("a", 64*1024) + `"`
+	status, _ = f.portForwardReq(http.MethodPut, "/api/preferences/starred", big)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+	assert.Empty(t, f.getPreferences().Values)
+}
+
+func (f *serverFixture) getPreferences() preferencesResponse {
+	status, body := f.portForwardReq(http.MethodGet, "/api/preferences", "")
+	require.Equal(f.t, http.StatusOK, status, body)
+
+	var prefs preferencesResponse
+	require.NoError(f.t, json.Unmarshal([]byte(body), &prefs))
+	return prefs
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Lets dashboards fetch only the parts of the UI state they need,
+// and follow changes as small patches rather than full views.
+//
+//	GET /api/query?fields=PATHS&resource=NAME&watch=BOOL
+//	GET /api/ext/v1/query?fields=PATHS&resource=NAME&watch=BOOL
+//
+// All parameters are optional:
+//   - fields: comma-separated paths into each UIResource, like
+//     metadata.name,status.runtimeStatus,status.buildHistory.error.
+//     May be repeated. Defaults to the whole object.
+//   - resource: only query this resource. May be repeated.
+//   - watch: when true, keep the response open and send a patch
+//     each time the selected fields change. Defaults to false.
+//
+// The response is the selected fields of each resource, by name:
+//
+//	{"resources": {"api": {"status": {"runtimeStatus": "ok"}}}}
+//
+// When watching, the response is a stream of JSON merge patches (RFC 7386).
+// The first patch is the complete result. Applying each patch in turn keeps
+// a copy of the result up to date. A deleted resource is patched to null.
+//
+// Patches are streamed as server-sent events named "patch". On the external
+// API, a request that asks to upgrade to a websocket gets each patch as a
+// separate JSON text message instead.
+const queryPath = "/api/query"
+const extQueryPath = "/query"
+
+type queryRequest struct {
+	selector  webview.FieldSelector
+	resources map[string]bool
+	watch     bool
+}
+
+func parseQueryRequest(req *http.Request) (queryRequest, error) {
+	query := req.URL.Query()
+	result := queryRequest{}
+
+	selector, err := webview.ParseFieldSelector(query["fields"])
+	if err != nil {
+		return queryRequest{}, err
+	}
+	result.selector = selector
+
+	if resources := query["resource"]; len(resources) > 0 {
+		result.resources = make(map[string]bool, len(resources))
+		for _, r := range resources {
+			result.resources[r] = true
+		}
+	}
+
+	if watch := query.Get("watch"); watch != "" {
+		result.watch, err = strconv.ParseBool(watch)
+		if err != nil {
+			return queryRequest{}, fmt.Errorf("invalid watch %q: must be true or false", watch)
+		}
+	}
+	return result, nil
+}
+
+func (s *HeadsUpServer) HandleQuery(w http.ResponseWriter, req *http.Request) {
+	s.serveQuery(w, req, false)
+}
+
+func (s *HeadsUpServer) ExtQuery(w http.ResponseWriter, req *http.Request) {
+	// The external API authenticates with tokens rather than cookies,
+	// so only it may watch over a websocket.
+	s.serveQuery(w, req, true)
+}
+
+func (s *HeadsUpServer) serveQuery(w http.ResponseWriter, req *http.Request, allowWebsocket bool) {
+	r, err := parseQueryRequest(req)
+	if err != nil {
+		writeExtError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !r.watch {
+		result, err := s.query(req.Context(), r)
+		if err != nil {
+			writeExtError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeExtJSON(w, http.StatusOK, result)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if allowWebsocket && websocket.IsWebSocketUpgrade(req) {
+		s.watchQueryWebsocket(ctx, cancel, w, req, r)
+		return
+	}
+	s.watchQuerySSE(ctx, w, r)
+}
+
+func (s *HeadsUpServer) query(ctx context.Context, r queryRequest) (webview.QueryResult, error) {
+	var list v1alpha1.UIResourceList
+	err := s.ctrlClient.List(ctx, &list)
+	if err != nil {
+		return webview.QueryResult{}, err
+	}
+
+	resources := list.Items
+	if r.resources != nil {
+		resources = nil
+		for _, uir := range list.Items {
+			if r.resources[uir.Name] {
+				resources = append(resources, uir)
+			}
+		}
+	}
+	return webview.BuildQueryResult(resources, r.selector)
+}
+
+func (s *HeadsUpServer) watchQuerySSE(ctx context.Context, w http.ResponseWriter, r queryRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeExtError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	err := s.watchQuery(ctx, r, func(patch map[string]interface{}) error {
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "event: patch\ndata: %s\n\n", data)
+		if err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("watching query: %v", err)
+	}
+}
+
+func (s *HeadsUpServer) watchQueryWebsocket(ctx context.Context, cancel context.CancelFunc, w http.ResponseWriter, req *http.Request, r queryRequest) {
+	conn, err := extUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// The upgrader has already written an error response.
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Consume control messages, and stop watching when the client goes away.
+	go func() {
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err = s.watchQuery(ctx, r, func(patch map[string]interface{}) error {
+		return conn.WriteJSON(patch)
+	})
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("watching query: %v", err)
+		return
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+}
+
+// Sends the complete result, then a patch each time it changes,
+// until the context is done.
+//
+// The watch listens for the same updates as the web UI,
+// so patches are batched the same way.
+func (s *HeadsUpServer) watchQuery(ctx context.Context, r queryRequest, send func(map[string]interface{}) error) error {
+	s.streams.queryWatches.Add(1)
+	defer s.streams.queryWatches.Add(-1)
+
+	conn := newQueryNotifyConn(ctx)
+	ws := NewWebsocketSubscriber(ctx, s.ctrlClient, s.store, conn)
+	ws.headless = true
+
+	// Subscribe before the first query, so that we don't miss any updates in between.
+	s.wsList.Add(ws)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ws.Stream(ctx)
+	}()
+	defer func() {
+		<-done
+		s.wsList.Remove(ws)
+	}()
+
+	var last webview.QueryResult
+	for {
+		result, err := s.query(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		patch := result.PatchFrom(last)
+		if last.Resources == nil {
+			// Always send the first result, even when it's empty.
+			patch = map[string]interface{}{"resources": result.Resources}
+		}
+		if patch != nil {
+			err := send(patch)
+			if err != nil {
+				return err
+			}
+		}
+		last = result
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-conn.notify:
+		}
+	}
+}
+
+// A fake websocket that wakes up a query watch whenever the web UI
+// would get an update. The update itself is thrown away.
+type queryNotifyConn struct {
+	ctx    context.Context
+	notify chan struct{}
+}
+
+var _ WebsocketConn = &queryNotifyConn{}
+
+func newQueryNotifyConn(ctx context.Context) *queryNotifyConn {
+	return &queryNotifyConn{ctx: ctx, notify: make(chan struct{}, 1)}
+}
+
+// Nothing ever reads from a watch, so block until it stops.
+func (c *queryNotifyConn) NextReader() (int, io.Reader, error) {
+	<-c.ctx.Done()
+	return 0, nil, c.ctx.Err()
+}
+
+func (c *queryNotifyConn) Close() error {
+	return nil
+}
+
+func (c *queryNotifyConn) NextWriter(messageType int) (io.WriteCloser, error) {
+	return queryNotifyWriter{notify: c.notify}, nil
+}
+
+type queryNotifyWriter struct {
+	notify chan struct{}
+}
+
+func (w queryNotifyWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w queryNotifyWriter) Close() error {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+		// The watch is already due to wake up.
+	}
+	return nil
+}
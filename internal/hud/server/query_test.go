@@ -0,0 +1,112 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestQuery(t *testing.T) {
+	f := newTestFixture(t)
+	f.createUIResource("fe", v1alpha1.UIResourceStatus{
+		RuntimeStatus: v1alpha1.RuntimeStatusOK,
+		UpdateStatus:  v1alpha1.UpdateStatusError,
+	})
+	f.createUIResource("be", v1alpha1.UIResourceStatus{
+		RuntimeStatus: v1alpha1.RuntimeStatusPending,
+	})
+
+	status, body := f.portForwardReq(http.MethodGet, "/api/query?fields=status.runtimeStatus", "")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.JSONEq(t, `{"resources": {
+  "fe": {"status": {"runtimeStatus": "ok"}},
+  "be": {"status": {"runtimeStatus": "pending"}}
+}}`, body)
+
+	status, body = f.portForwardReq(http.MethodGet, "/api/query?fields=metadata.name&fields=status.updateStatus&resource=fe", "")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.JSONEq(t, `{"resources": {
+  "fe": {"metadata": {"name": "fe"}, "status": {"updateStatus": "error"}}
+}}`, body)
+}
+
+func TestQueryBadParams(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.portForwardReq(http.MethodGet, "/api/query?fields=status.", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "empty path segment")
+
+	status, body = f.portForwardReq(http.MethodGet, "/api/query?watch=maybe", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, `invalid watch \"maybe\"`)
+
+	status, _ = f.makeExtReq(http.MethodGet, "/query", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestExtQueryWatch(t *testing.T) {
+	f := newTestFixture(t)
+	token := f.createAPIToken()
+	f.createUIResource("fe", v1alpha1.UIResourceStatus{RuntimeStatus: v1alpha1.RuntimeStatusPending})
+
+	srv := httptest.NewServer(f.serv.Router())
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/ext/v1/query?fields=status.runtimeStatus&watch=true"
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var patch map[string]interface{}
+	require.NoError(t, conn.ReadJSON(&patch))
+	assert.Equal(t, map[string]interface{}{
+		"resources": map[string]interface{}{
+			"fe": map[string]interface{}{"status": map[string]interface{}{"runtimeStatus": "pending"}},
+		},
+	}, patch)
+
+	// Changes outside the selected fields don't send a patch.
+	f.updateUIResource("fe", func(uir *v1alpha1.UIResource) {
+		uir.Status.UpdateStatus = v1alpha1.UpdateStatusInProgress
+	})
+	f.updateUIResource("fe", func(uir *v1alpha1.UIResource) {
+		uir.Status.RuntimeStatus = v1alpha1.RuntimeStatusOK
+	})
+
+	patch = nil
+	require.NoError(t, conn.ReadJSON(&patch))
+	assert.Equal(t, map[string]interface{}{
+		"resources": map[string]interface{}{
+			"fe": map[string]interface{}{"status": map[string]interface{}{"runtimeStatus": "ok"}},
+		},
+	}, patch)
+}
+
+// Updates the resource, and tells websockets about it like the UIResource reconciler does.
+func (f *serverFixture) updateUIResource(name string, update func(uir *v1alpha1.UIResource)) {
+	var uir v1alpha1.UIResource
+	nn := types.NamespacedName{Name: name}
+	require.NoError(f.t, f.ctrlClient.Get(f.ctx, nn, &uir))
+	update(&uir)
+	require.NoError(f.t, f.ctrlClient.Status().Update(f.ctx, &uir))
+
+	f.wsList.ForEach(func(ws *server.WebsocketSubscriber) {
+		ws.SendUIResourceUpdate(f.ctx, nn, uir.DeepCopy())
+	})
+}
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/snapshots"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Records every view update the web UI would receive to a file,
+// so that the session can be played back later with `tilt replay`.
+//
+// The recorder is just another websocket subscriber, so it sees the same
+// object transitions and log spans as a browser tab. It also watches every
+// apiserver type, to record the objects the web UI never sees.
+type SessionRecorder struct {
+	st         *store.Store
+	ctrlClient ctrlclient.Client
+	wsList     *WebsocketList
+
+	// The controller client reads from a cache, which can't watch,
+	// so objects are watched with a separate client.
+	newWatchClient func() (ctrlclient.WithWatch, error)
+}
+
+func NewSessionRecorder(st *store.Store, ctrlClient ctrlclient.Client, wsList *WebsocketList, config *APIServerConfig) *SessionRecorder {
+	return &SessionRecorder{
+		st:         st,
+		ctrlClient: ctrlClient,
+		wsList:     wsList,
+		newWatchClient: func() (ctrlclient.WithWatch, error) {
+			return ctrlclient.NewWithWatch(config.GenericConfig.LoopbackClientConfig,
+				ctrlclient.Options{Scheme: v1alpha1.NewScheme()})
+		},
+	}
+}
+
+// Starts recording to the file at path.
+//
+// Recording stops when ctx is canceled. The returned func waits for the
+// last frames to be written and closes the file.
+func (r *SessionRecorder) Start(ctx context.Context, path string) (func(), error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating session recording: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.record(ctx, snapshots.NewRecordingWriter(f))
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		err := f.Close()
+		if err != nil {
+			logger.Get(ctx).Infof("error closing session recording: %v", err)
+		}
+	}, nil
+}
+
+func (r *SessionRecorder) record(ctx context.Context, w *snapshots.RecordingWriter) {
+	err := r.waitForClient(ctx)
+	if err != nil {
+		return
+	}
+
+	var objectsDone sync.WaitGroup
+	watchClient, err := r.newWatchClient()
+	if err != nil {
+		logger.Get(ctx).Infof("session recording: not recording API objects: %v", err)
+	} else {
+		objects := &objectRecorder{client: watchClient, w: w, seen: make(map[string]string)}
+		for _, list := range v1alpha1.AllResourceLists() {
+			list := list.(ctrlclient.ObjectList)
+			objectsDone.Add(1)
+			go func() {
+				defer objectsDone.Done()
+				objects.watchType(ctx, list)
+			}()
+		}
+	}
+	defer objectsDone.Wait()
+
+	conn := &recordingConn{ctx: ctx, w: w}
+	ws := NewWebsocketSubscriber(ctx, r.ctrlClient, r.st, conn)
+	ws.headless = true
+	r.wsList.Add(ws)
+	_ = r.st.AddSubscriber(ctx, ws)
+
+	ws.Stream(ctx)
+
+	_ = r.st.RemoveSubscriber(context.Background(), ws)
+	r.wsList.Remove(ws)
+
+	// Catch anything that changed since the last frame, so the recording
+	// ends with the session's final logs.
+	view := ws.toViewUpdate()
+	if view != nil {
+		ws.sendView(ctx, view)
+	}
+}
+
+// The apiserver starts after the recorder, so wait until we can read from it.
+func (r *SessionRecorder) waitForClient(ctx context.Context) error {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		err := r.ctrlClient.List(ctx, &v1alpha1.UISessionList{})
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Records every transition of every object of one type.
+type objectRecorder struct {
+	client ctrlclient.WithWatch
+	w      *snapshots.RecordingWriter
+
+	mu sync.Mutex
+
+	// The resource version we last recorded, by kind/name.
+	seen map[string]string
+}
+
+// Watches until ctx is canceled, re-watching whenever the apiserver
+// closes the watch.
+func (r *objectRecorder) watchType(ctx context.Context, listType ctrlclient.ObjectList) {
+	for ctx.Err() == nil {
+		err := r.watchOnce(ctx, listType)
+		if err != nil && ctx.Err() == nil {
+			logger.Get(ctx).Debugf("session recording: watching %T: %v", listType, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+func (r *objectRecorder) watchOnce(ctx context.Context, listType ctrlclient.ObjectList) error {
+	kind := strings.TrimSuffix(reflect.TypeOf(listType).Elem().Name(), "List")
+
+	// List first, so that we start from the current state, and catch
+	// anything deleted while we weren't watching.
+	list := listType.DeepCopyObject().(ctrlclient.ObjectList)
+	err := r.client.List(ctx, list)
+	if err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(items))
+	for _, item := range items {
+		obj := item.(ctrlclient.Object)
+		existing[obj.GetName()] = true
+		r.record(ctx, kind, obj, false)
+	}
+	r.recordMissing(ctx, kind, existing)
+
+	watcher, err := r.client.Watch(ctx, listType.DeepCopyObject().(ctrlclient.ObjectList),
+		&ctrlclient.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: list.GetResourceVersion()}})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				r.record(ctx, kind, event.Object.(ctrlclient.Object), false)
+			case watch.Deleted:
+				r.record(ctx, kind, event.Object.(ctrlclient.Object), true)
+			case watch.Error:
+				return apierrors.FromObject(event.Object)
+			}
+		}
+	}
+}
+
+func (r *objectRecorder) record(ctx context.Context, kind string, obj ctrlclient.Object, deleted bool) {
+	key := kind + "/" + obj.GetName()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if deleted {
+		if _, ok := r.seen[key]; !ok {
+			return
+		}
+		delete(r.seen, key)
+		r.write(ctx, snapshots.RecordedObject{Kind: kind, Name: obj.GetName(), Deleted: true})
+		return
+	}
+
+	if r.seen[key] == obj.GetResourceVersion() {
+		return
+	}
+	r.seen[key] = obj.GetResourceVersion()
+
+	obj = obj.DeepCopyObject().(ctrlclient.Object)
+	obj.GetObjectKind().SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(kind))
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		logger.Get(ctx).Debugf("session recording: encoding %s: %v", key, err)
+		return
+	}
+	r.write(ctx, snapshots.RecordedObject{Kind: kind, Name: obj.GetName(), Object: encoded})
+}
+
+// Records deletes for the objects of a kind that we've seen, but that
+// aren't in the given set anymore.
+func (r *objectRecorder) recordMissing(ctx context.Context, kind string, existing map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix := kind + "/"
+	for key := range r.seen {
+		name := strings.TrimPrefix(key, prefix)
+		if !strings.HasPrefix(key, prefix) || existing[name] {
+			continue
+		}
+		delete(r.seen, key)
+		r.write(ctx, snapshots.RecordedObject{Kind: kind, Name: name, Deleted: true})
+	}
+}
+
+// Must hold the lock, so that object frames are written in the order we saw them.
+func (r *objectRecorder) write(ctx context.Context, obj snapshots.RecordedObject) {
+	err := r.w.WriteObject(time.Now(), obj)
+	if err != nil {
+		logger.Get(ctx).Debugf("session recording: writing %s/%s: %v", obj.Kind, obj.Name, err)
+	}
+}
+
+// A fake websocket that writes each message to the recording as a frame.
+type recordingConn struct {
+	ctx context.Context
+	w   *snapshots.RecordingWriter
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ WebsocketConn = &recordingConn{}
+
+// Nothing ever reads from a recording, so block until recording stops.
+func (c *recordingConn) NextReader() (int, io.Reader, error) {
+	<-c.ctx.Done()
+	return 0, nil, c.ctx.Err()
+}
+
+func (c *recordingConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *recordingConn) NextWriter(messageType int) (io.WriteCloser, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, fmt.Errorf("recording closed")
+	}
+	return &frameWriter{w: c.w}, nil
+}
+
+// Buffers one message, and writes it as a frame on Close.
+type frameWriter struct {
+	w   *snapshots.RecordingWriter
+	buf bytes.Buffer
+}
+
+func (f *frameWriter) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+func (f *frameWriter) Close() error {
+	return f.w.WriteFrame(time.Now(), f.buf.Bytes())
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tilt-dev/tilt/internal/snapshots"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestSessionRecorder(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	st, _ := store.NewStoreWithFakeReducer()
+	_ = st.SetUpSubscribersForTesting(ctx)
+
+	// The fake client can watch, so it stands in for both clients.
+	ctrlClient := fakeclient.NewClientBuilder().WithScheme(v1alpha1.NewScheme()).Build()
+	require.NoError(t, ctrlClient.Create(ctx, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe"},
+	}))
+	cmd := &v1alpha1.Cmd{ObjectMeta: metav1.ObjectMeta{Name: "fe-serve"}}
+	require.NoError(t, ctrlClient.Create(ctx, cmd))
+
+	wsList := NewWebsocketList()
+	path := filepath.Join(t.TempDir(), "session.ndjson")
+	recorder := NewSessionRecorder(st, ctrlClient, wsList, nil)
+	recorder.newWatchClient = func() (ctrlclient.WithWatch, error) {
+		return ctrlClient, nil
+	}
+	stop, err := recorder.Start(ctx, path)
+	require.NoError(t, err)
+
+	// Wait for the recorder to subscribe, then send it an update.
+	var ws *WebsocketSubscriber
+	require.Eventually(t, func() bool {
+		wsList.ForEach(func(w *WebsocketSubscriber) { ws = w })
+		return ws != nil
+	}, time.Second, 10*time.Millisecond)
+
+	ws.SendUIResourceUpdate(ctx, types.NamespacedName{Name: "be"}, &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "be"},
+	})
+	writeLogAndNotify(ctx, st)
+	require.Eventually(t, func() bool {
+		return len(readTestRecording(t, path).Frames) >= 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Objects the web UI doesn't see get recorded too.
+	require.Eventually(t, func() bool {
+		return hasRecordedObject(readTestRecording(t, path), "Cmd/fe-serve", false)
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, ctrlClient.Delete(ctx, cmd))
+	require.Eventually(t, func() bool {
+		return hasRecordedObject(readTestRecording(t, path), "Cmd/fe-serve", true)
+	}, time.Second, 10*time.Millisecond)
+
+	stop()
+
+	recording := readTestRecording(t, path)
+	var objects []string
+	for _, obj := range recording.ObjectsAt(len(recording.Frames) - 1) {
+		objects = append(objects, obj.Kind+"/"+obj.Name)
+	}
+	assert.Contains(t, objects, "UIResource/fe")
+	assert.NotContains(t, objects, "Cmd/fe-serve")
+
+	view := recording.ViewAt(len(recording.Frames) - 1)
+	require.Len(t, view.UiResources, 2)
+	assert.Equal(t, "be", view.UiResources[0].Name)
+	assert.Equal(t, "fe", view.UiResources[1].Name)
+	require.Len(t, view.LogList.Segments, 1)
+	assert.Equal(t, "test", view.LogList.Segments[0].Text)
+
+	wsList.ForEach(func(w *WebsocketSubscriber) {
+		t.Errorf("recorder still subscribed after stop")
+	})
+}
+
+func hasRecordedObject(recording *snapshots.Recording, key string, deleted bool) bool {
+	for _, frame := range recording.Frames {
+		for _, obj := range frame.Objects {
+			if obj.Kind+"/"+obj.Name == key && obj.Deleted == deleted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func readTestRecording(t *testing.T, path string) *snapshots.Recording {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	recording, err := snapshots.ReadRecording(f)
+	if err != nil {
+		return &snapshots.Recording{}
+	}
+	return recording
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Restarts what a resource is running, without rebuilding it.
+//
+//	POST /api/restart {"manifest_names": [NAME]}
+//
+// What a restart means depends on the resource:
+//   - a local resource replaces its serve_cmd process
+//   - a Kubernetes resource deletes its pods, so that their controller
+//     recreates them from the same spec
+//   - a Docker Compose resource runs `docker compose restart`
+const restartPath = "/api/restart"
+
+type restartPayload struct {
+	ManifestNames []string `json:"manifest_names"`
+}
+
+// What the handler needs to know about a resource to restart it,
+// copied out of the engine state so that we don't hold the lock
+// while talking to the cluster.
+type restartTarget struct {
+	name     model.ManifestName
+	disabled bool
+	serve    bool
+	pods     []v1alpha1.Pod
+	dc       *v1alpha1.DockerComposeServiceSpec
+}
+
+func (s *HeadsUpServer) HandleRestart(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "must be POST request", http.StatusBadRequest)
+		return
+	}
+
+	var payload restartPayload
+	decoder := json.NewDecoder(req.Body)
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(payload.ManifestNames) != 1 {
+		http.Error(w, fmt.Sprintf("/api/restart currently supports exactly one manifest name, got %d", len(payload.ManifestNames)), http.StatusBadRequest)
+		return
+	}
+
+	mn := model.ManifestName(payload.ManifestNames[0])
+	target, ok := s.restartTarget(mn)
+	if !ok {
+		http.Error(w, fmt.Sprintf("resource %q does not exist", mn), http.StatusNotFound)
+		return
+	}
+	if target.disabled {
+		_, _ = fmt.Fprintf(w, "resource %q is currently disabled", mn)
+		return
+	}
+
+	status, err := s.restart(req.Context(), target)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+}
+
+// Restarts the resource for the terminal UI.
+func (s *HeadsUpServer) RestartResource(ctx context.Context, mn model.ManifestName) error {
+	target, ok := s.restartTarget(mn)
+	if !ok {
+		return fmt.Errorf("resource %q does not exist", mn)
+	}
+	if target.disabled {
+		return fmt.Errorf("resource %q is currently disabled", mn)
+	}
+	_, err := s.restart(ctx, target)
+	return err
+}
+
+func (s *HeadsUpServer) restartTarget(mn model.ManifestName) (restartTarget, bool) {
+	state := s.store.RLockState()
+	defer s.store.RUnlockState()
+
+	mt, ok := state.ManifestTargets[mn]
+	if !ok {
+		return restartTarget{}, false
+	}
+
+	target := restartTarget{
+		name:     mn,
+		disabled: mt.State.DisableState == v1alpha1.DisableStateDisabled,
+	}
+	m := mt.Manifest
+	switch {
+	case m.IsLocal():
+		target.serve = !m.LocalTarget().ServeCmd.Empty()
+	case m.IsK8s():
+		target.pods = append(target.pods, mt.State.K8sRuntimeState().FilteredPods...)
+	case m.IsDC():
+		spec := m.DockerComposeTarget().Spec
+		target.dc = &spec
+	}
+	return target, true
+}
+
+func (s *HeadsUpServer) restart(ctx context.Context, target restartTarget) (int, error) {
+	// Restarts happen outside the engine, so send the log lines
+	// to the resource's log ourselves.
+	ctx = logger.WithLogger(ctx, logger.NewLogger(logger.InfoLvl, io.Discard))
+	ctx = store.WithManifestLogHandler(ctx, s.store, target.name, model.LogSpanID(fmt.Sprintf("restart:%s", target.name)))
+
+	l := logger.Get(ctx)
+	switch {
+	case target.serve:
+		l.Infof("Restarting serve_cmd")
+		s.store.Dispatch(local.RestartServeAction{ManifestName: target.name, Time: time.Now()})
+
+	case len(target.pods) > 0:
+		var entities []k8s.K8sEntity
+		for _, pod := range target.pods {
+			entities = append(entities, k8s.NewK8sEntity(&v1.Pod{
+				TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			}))
+		}
+		l.Infof("Restarting pods")
+		err := s.k8sClient.Delete(ctx, entities, false)
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("restarting %s: %v", target.name, err)
+		}
+
+	case target.dc != nil:
+		l.Infof("Restarting container")
+		out := logger.NewMutexWriter(l.Writer(logger.InfoLvl))
+		err := s.dcClient.Restart(ctx, *target.dc, out, out)
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("restarting %s: %v", target.name, err)
+		}
+
+	default:
+		return http.StatusBadRequest, fmt.Errorf("resource %q has nothing running to restart", target.name)
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestRestartServeCmd(t *testing.T) {
+	f := newTestFixture(t)
+	f.withManifest(model.Manifest{Name: "server"}.WithDeployTarget(
+		model.NewLocalTarget("server", model.Cmd{}, model.ToHostCmd("./serve.sh"), nil)))
+
+	status, body := f.makeReq("/api/restart", f.serv.HandleRestart, http.MethodPost, `{"manifest_names":["server"]}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "", body)
+
+	a := store.WaitForAction(t, reflect.TypeOf(local.RestartServeAction{}), f.getActions)
+	assert.Equal(t, model.ManifestName("server"), a.(local.RestartServeAction).ManifestName)
+}
+
+func TestRestartPods(t *testing.T) {
+	f := newTestFixture(t)
+	m := model.Manifest{Name: "fe"}.WithDeployTarget(model.NewK8sTargetForTesting(""))
+	f.withManifest(m)
+	state := f.st.LockMutableStateForTesting()
+	state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeStateWithPods(m,
+		v1alpha1.Pod{Name: "fe-1", Namespace: "default"},
+		v1alpha1.Pod{Name: "fe-2", Namespace: "default"})
+	f.st.UnlockMutableState()
+
+	status, body := f.makeReq("/api/restart", f.serv.HandleRestart, http.MethodPost, `{"manifest_names":["fe"]}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "", body)
+	assert.Contains(t, f.k8sClient.DeletedYaml, "name: fe-1")
+	assert.Contains(t, f.k8sClient.DeletedYaml, "name: fe-2")
+}
+
+func TestRestartDockerCompose(t *testing.T) {
+	f := newTestFixture(t)
+	f.withManifest(model.Manifest{Name: "redis"}.WithDeployTarget(model.DockerComposeTarget{
+		Name: "redis",
+		Spec: v1alpha1.DockerComposeServiceSpec{Service: "redis"},
+	}))
+
+	status, body := f.makeReq("/api/restart", f.serv.HandleRestart, http.MethodPost, `{"manifest_names":["redis"]}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "", body)
+
+	calls := f.dcClient.RestartCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "redis", calls[0].Spec.Service)
+}
+
+func TestRestartNothingRunning(t *testing.T) {
+	f := newTestFixture(t)
+	f.withManifest(model.Manifest{Name: "build-only"}.WithDeployTarget(
+		model.NewLocalTarget("build-only", model.ToHostCmd("make"), model.Cmd{}, nil)))
+
+	status, body := f.makeReq("/api/restart", f.serv.HandleRestart, http.MethodPost, `{"manifest_names":["build-only"]}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "resource \"build-only\" has nothing running to restart\n", body)
+}
+
+func TestRestartNoManifestWithName(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.makeReq("/api/restart", f.serv.HandleRestart, http.MethodPost, `{"manifest_names":["foo"]}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "resource \"foo\" does not exist\n", body)
+}
+
+func (f *serverFixture) withManifest(m model.Manifest) {
+	state := f.st.LockMutableStateForTesting()
+	defer f.st.UnlockMutableState()
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+}
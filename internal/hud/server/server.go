@@ -21,7 +21,11 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/apitoken"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
 	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/tiltfiles"
 	"github.com/tilt-dev/tilt/pkg/assets"
@@ -57,6 +61,11 @@ type overrideTriggerModePayload struct {
 	TriggerMode   int      `json:"trigger_mode"`
 }
 
+type resourceGroupsPayload struct {
+	Paths     []string `json:"paths"`
+	Collapsed bool     `json:"collapsed"`
+}
+
 type HeadsUpServer struct {
 	ctx        context.Context
 	store      *store.Store
@@ -64,6 +73,14 @@ type HeadsUpServer struct {
 	a          *tiltanalytics.TiltAnalytics
 	wsList     *WebsocketList
 	ctrlClient ctrlclient.Client
+	apiTokens  *apitoken.Store
+	teamAuth   *teamAuth
+	streams    streamCounts
+	k8sClient  k8s.Client
+	dcClient   dockercompose.DockerComposeClient
+	cmdInput   CmdInputSender
+
+	previousLogsDir loghistory.PreviousDir
 }
 
 func ProvideHeadsUpServer(
@@ -72,7 +89,18 @@ func ProvideHeadsUpServer(
 	assetServer assets.Server,
 	analytics *tiltanalytics.TiltAnalytics,
 	wsList *WebsocketList,
-	ctrlClient ctrlclient.Client) (*HeadsUpServer, error) {
+	ctrlClient ctrlclient.Client,
+	apiTokens *apitoken.Store,
+	teamAuthConfig TeamAuthConfig,
+	k8sClient k8s.Client,
+	dcClient dockercompose.DockerComposeClient,
+	cmdInput CmdInputSender,
+	previousLogsDir loghistory.PreviousDir) (*HeadsUpServer, error) {
+	teamAuth, err := newTeamAuth(teamAuthConfig, apiTokens)
+	if err != nil {
+		return nil, err
+	}
+
 	r := mux.NewRouter().UseEncodedPath()
 	s := &HeadsUpServer{
 		ctx:        ctx,
@@ -81,19 +109,45 @@ func ProvideHeadsUpServer(
 		a:          analytics,
 		wsList:     wsList,
 		ctrlClient: ctrlClient,
+		apiTokens:  apiTokens,
+		teamAuth:   teamAuth,
+		k8sClient:  k8sClient,
+		dcClient:   dcClient,
+		cmdInput:   cmdInput,
+
+		previousLogsDir: previousLogsDir,
 	}
 
 	r.HandleFunc("/api/view", s.ViewJSON)
 	r.HandleFunc("/api/dump/engine", s.DumpEngineJSON)
+	r.HandleFunc(diagnosticsPath, s.HandleDiagnostics).Methods("GET")
+	r.HandleFunc(diagnosticsBundlePath, s.HandleDiagnosticsBundle).Methods("GET")
 	r.HandleFunc("/api/analytics", s.HandleAnalytics)
 	r.HandleFunc("/api/analytics_opt", s.HandleAnalyticsOpt)
 	r.HandleFunc("/api/trigger", s.HandleTrigger)
+	r.HandleFunc(restartPath, s.HandleRestart)
+	r.HandleFunc(cmdInputPath, s.HandleCmdInput).Methods("POST")
 	r.HandleFunc("/api/override/trigger_mode", s.HandleOverrideTriggerMode)
+	r.HandleFunc("/api/resource_groups", s.HandleResourceGroups).Methods("POST")
+	r.HandleFunc(bulkActionPath, s.HandleBulkAction).Methods("POST")
+	r.HandleFunc(graphPath, s.HandleGraph).Methods("GET")
+	r.HandleFunc(ignoresPath, s.HandleIgnores).Methods("GET")
+	r.HandleFunc(queryPath, s.HandleQuery).Methods("GET")
+	r.HandleFunc(logsPath, s.HandleLogs).Methods("GET")
+	r.HandleFunc(logSearchPath, s.HandleLogSearch).Methods("GET")
+	r.HandleFunc(truncatedLogsPath, s.HandleTruncatedLogs).Methods("GET")
+	r.HandleFunc(previousLogsPath, s.HandlePreviousLogs).Methods("GET")
+	// Used by 'tilt snapshot create', so that snapshots don't need a browser.
+	r.HandleFunc("/api/snapshot", s.SnapshotJSON).Methods("GET")
 	// this endpoint is only used for testing snapshots in development
 	r.HandleFunc("/api/snapshot/{snapshot_id}", s.SnapshotJSON)
 	r.HandleFunc("/api/websocket_token", s.WebsocketToken)
+	r.HandleFunc("/api/whoami", s.HandleWhoAmI).Methods("GET")
 	r.HandleFunc("/ws/view", s.ViewWebsocket)
 	r.HandleFunc("/api/set_tiltfile_args", s.HandleSetTiltfileArgs).Methods("POST")
+	s.registerPortForwardRoutes(r)
+	s.registerPreferencesRoutes(r)
+	s.registerExternalAPI(r)
 
 	r.PathPrefix("/").Handler(s.cookieWrapper(assetServer))
 
@@ -110,9 +164,12 @@ func (fh funcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (s *HeadsUpServer) cookieWrapper(handler http.Handler) http.Handler {
 	return funcHandler{f: func(w http.ResponseWriter, r *http.Request) {
-		state := s.store.RLockState()
-		http.SetCookie(w, &http.Cookie{Name: TiltTokenCookieName, Value: string(state.Token), Path: "/"})
-		s.store.RUnlockState()
+		// Viewers only get to watch this session, not act as its owner.
+		if !teamIdentityFromRequest(r).ReadOnly() {
+			state := s.store.RLockState()
+			http.SetCookie(w, &http.Cookie{Name: TiltTokenCookieName, Value: string(state.Token), Path: "/"})
+			s.store.RUnlockState()
+		}
 		handler.ServeHTTP(w, r)
 	}}
 }
@@ -309,6 +366,29 @@ func (s *HeadsUpServer) HandleOverrideTriggerMode(w http.ResponseWriter, req *ht
 	})
 }
 
+// Collapses or expands resource groups in the UI.
+func (s *HeadsUpServer) HandleResourceGroups(w http.ResponseWriter, req *http.Request) {
+	var payload resourceGroupsPayload
+
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(payload.Paths) == 0 {
+		http.Error(w, "must specify at least one group path", http.StatusBadRequest)
+		return
+	}
+
+	s.store.Dispatch(SetResourceGroupsCollapsedAction{
+		Paths:     payload.Paths,
+		Collapsed: payload.Collapsed,
+	})
+}
+
 func (s *HeadsUpServer) WebsocketToken(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
 	_, _ = w.Write([]byte(websocketCSRFToken.String()))
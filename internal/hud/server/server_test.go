@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,15 +20,21 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/apitoken"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/engine/loghistory"
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/hud/view"
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
 
@@ -38,6 +45,14 @@ func TestHandleAnalyticsEmptyRequest(t *testing.T) {
 	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
 }
 
+func TestWhoAmIWithoutTeamMode(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.makeReq("/api/whoami", f.serv.HandleWhoAmI, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"role":"owner","readOnly":false,"canTrigger":true}`, body)
+}
+
 func TestHandleAnalyticsRecordsIncr(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -120,6 +135,22 @@ func TestHandleAnalyticsOptMalformedPayload(t *testing.T) {
 	require.Contains(t, respBody, "error parsing JSON")
 }
 
+func TestSnapshotJSON(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("fe")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/snapshot", nil)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var snapshot proto_webview.Snapshot
+	err := (&runtime.JSONPb{}).Unmarshal(rr.Body.Bytes(), &snapshot)
+	require.NoError(t, err)
+	assert.NotNil(t, snapshot.CreatedAt)
+	assert.NotNil(t, snapshot.View)
+}
+
 func TestHandleTriggerNoManifestWithName(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -285,6 +316,37 @@ func TestHandleOverrideTriggerModeDispatchesEvent(t *testing.T) {
 	assert.Equal(t, expected, action)
 }
 
+func TestHandleResourceGroupsDispatchesEvent(t *testing.T) {
+	f := newTestFixture(t)
+
+	payload := `{"paths":["payments", "payments/api"], "collapsed": true}`
+	status, _ := f.makeReq("/api/resource_groups", f.serv.HandleResourceGroups, http.MethodPost, payload)
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.SetResourceGroupsCollapsedAction{}), f.getActions)
+	assert.Equal(t, server.SetResourceGroupsCollapsedAction{
+		Paths:     []string{"payments", "payments/api"},
+		Collapsed: true,
+	}, a)
+}
+
+func TestHandleResourceGroupsNoPaths(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, respBody := f.makeReq("/api/resource_groups", f.serv.HandleResourceGroups, http.MethodPost, `{"collapsed": true}`)
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "must specify at least one group path")
+	store.AssertNoActionOfType(t, reflect.TypeOf(server.SetResourceGroupsCollapsedAction{}), f.getActions)
+}
+
+func TestHandleResourceGroupsMalformedPayload(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, respBody := f.makeReq("/api/resource_groups", f.serv.HandleResourceGroups, http.MethodPost, `{"paths": "payments"}`)
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "error parsing JSON")
+}
+
 func TestSetTiltfileArgs(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -320,8 +382,15 @@ type serverFixture struct {
 	ta           *tiltanalytics.TiltAnalytics
 	st           *store.Store
 	ctrlClient   ctrlclient.Client
+	wsList       *server.WebsocketList
+	apiTokens    *apitoken.Store
+	k8sClient    *k8s.FakeK8sClient
+	dcClient     *dockercompose.FakeDCClient
+	cmdInput     *fakeCmdInput
 	getActions   func() []store.Action
 	snapshotHTTP *fakeHTTPClient
+
+	previousLogsDir string
 }
 
 func newTestFixture(t *testing.T) *serverFixture {
@@ -341,7 +410,12 @@ func newTestFixture(t *testing.T) *serverFixture {
 
 	ctx := context.Background()
 
-	serv, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta, wsl, ctrlClient)
+	apiTokens := apitoken.NewStore(xdg.FakeBase{Dir: t.TempDir()})
+	k8sClient := k8s.NewFakeK8sClient(t)
+	dcClient := dockercompose.NewFakeDockerComposeClient(t, ctx)
+	cmdInput := &fakeCmdInput{}
+	previousLogsDir := t.TempDir()
+	serv, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta, wsl, ctrlClient, apiTokens, server.TeamAuthConfig{}, k8sClient, dcClient, cmdInput, loghistory.PreviousDir(previousLogsDir))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -354,8 +428,15 @@ func newTestFixture(t *testing.T) *serverFixture {
 		ta:           ta,
 		st:           st,
 		ctrlClient:   ctrlClient,
+		wsList:       wsl,
+		apiTokens:    apiTokens,
+		k8sClient:    k8sClient,
+		dcClient:     dcClient,
+		cmdInput:     cmdInput,
 		getActions:   getActions,
 		snapshotHTTP: snapshotHTTP,
+
+		previousLogsDir: previousLogsDir,
 	}
 }
 
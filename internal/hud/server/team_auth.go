@@ -0,0 +1,519 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/apitoken"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Team mode lets teammates watch a Tilt session from other machines
+// (with `tilt up --host`) without giving them control of it.
+//
+// Everyone must sign in, either with a shared viewer token or with an
+// OpenID Connect provider, unless LocalOwner is set. What they can do then
+// depends on their role:
+//
+//   - Viewers can see resources and logs.
+//   - Operators can also trigger builds and click buttons.
+//   - Owners can also enable and disable resources, change settings,
+//     and edit API objects.
+type TeamAuthConfig struct {
+	// A shared secret that grants read-only access.
+	ViewerToken string
+
+	// Gives requests from this machine full control without signing in,
+	// like when team mode is off. Off by default, because a reverse proxy
+	// on this machine that doesn't set X-Forwarded-For would give everyone
+	// behind it full control.
+	LocalOwner bool
+
+	OIDC OIDCConfig
+}
+
+func (c TeamAuthConfig) Enabled() bool {
+	return c.ViewerToken != "" || c.OIDC.Enabled()
+}
+
+type OIDCConfig struct {
+	// The issuer URL of the provider, e.g., https://accounts.google.com
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// Where the provider sends users back after they sign in.
+	// Defaults to /auth/oidc/callback on the host the user requested,
+	// which is wrong when Tilt is behind a reverse proxy.
+	RedirectURL string
+
+	// Emails that may watch the session. An entry that starts with @
+	// allows everyone in that domain, e.g., @example.com
+	ViewerEmails []string
+
+	// Emails that may also trigger builds, in the same format.
+	OperatorEmails []string
+
+	// Emails that get full control of the session, in the same format.
+	OwnerEmails []string
+}
+
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+func (c OIDCConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.ClientID == "" {
+		return fmt.Errorf("OIDC issuer %q requires a client ID", c.IssuerURL)
+	}
+	if len(c.ViewerEmails) == 0 && len(c.OperatorEmails) == 0 && len(c.OwnerEmails) == 0 {
+		return fmt.Errorf("OIDC issuer %q requires at least one allowed viewer, operator, or owner email", c.IssuerURL)
+	}
+	return nil
+}
+
+type TeamRole string
+
+const (
+	TeamRoleOwner    TeamRole = "owner"
+	TeamRoleOperator TeamRole = "operator"
+	TeamRoleViewer   TeamRole = "viewer"
+)
+
+// Something a request does to the session.
+type TeamPermission string
+
+const (
+	// Read resources, logs, and API objects.
+	TeamPermissionView TeamPermission = "view"
+
+	// Trigger builds and click buttons.
+	TeamPermissionTrigger TeamPermission = "trigger"
+
+	// Enable and disable resources.
+	TeamPermissionEnable TeamPermission = "enable"
+
+	// Change settings and create, edit, or delete API objects.
+	TeamPermissionEdit TeamPermission = "edit"
+)
+
+func ParseTeamRole(s string) (TeamRole, error) {
+	switch r := TeamRole(s); r {
+	case TeamRoleOwner, TeamRoleOperator, TeamRoleViewer:
+		return r, nil
+	}
+	return "", fmt.Errorf("invalid role %q: must be one of %s, %s, or %s",
+		s, TeamRoleViewer, TeamRoleOperator, TeamRoleOwner)
+}
+
+func (r TeamRole) Allows(p TeamPermission) bool {
+	switch r {
+	case TeamRoleOwner:
+		return true
+	case TeamRoleOperator:
+		return p == TeamPermissionView || p == TeamPermissionTrigger
+	case TeamRoleViewer:
+		return p == TeamPermissionView
+	}
+	return false
+}
+
+// Who made a request, as seen by team mode.
+type TeamIdentity struct {
+	// The signed-in user, e.g., their email. Empty for local requests
+	// and shared-token viewers.
+	Name string   `json:"name,omitempty"`
+	Role TeamRole `json:"role"`
+}
+
+// Whether the user can't change the session's settings or API objects.
+// They may still be able to trigger builds.
+func (id TeamIdentity) ReadOnly() bool {
+	return !id.Role.Allows(TeamPermissionEdit)
+}
+
+const teamAuthPathPrefix = "/auth/"
+const teamLoginPath = "/auth/login"
+const teamLogoutPath = "/auth/logout"
+const teamOIDCLoginPath = "/auth/oidc/login"
+const teamOIDCCallbackPath = "/auth/oidc/callback"
+
+const teamSessionCookieName = "Tilt-Session"
+const teamOIDCStateCookieName = "Tilt-OIDC-State"
+
+const teamSessionTTL = 12 * time.Hour
+const teamOIDCStateTTL = 10 * time.Minute
+
+type teamIdentityKey struct{}
+
+// Returns who made the request. Requests that never went through team mode
+// (because it's off) have full control.
+func teamIdentityFromRequest(req *http.Request) TeamIdentity {
+	id, ok := req.Context().Value(teamIdentityKey{}).(TeamIdentity)
+	if !ok {
+		return TeamIdentity{Role: TeamRoleOwner}
+	}
+	return id
+}
+
+type teamAuth struct {
+	config TeamAuthConfig
+	oidc   *oidcProvider
+
+	// Lets `tilt attach` and scripts sign in with an API token instead
+	// of a session cookie.
+	apiTokens *apitoken.Store
+
+	// Signs session cookies. Generated on startup, so that restarting Tilt
+	// signs everyone out.
+	sessionKey []byte
+	now        func() time.Time
+}
+
+func newTeamAuth(config TeamAuthConfig, apiTokens *apitoken.Store) (*teamAuth, error) {
+	if !config.Enabled() {
+		return nil, nil
+	}
+	err := config.OIDC.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("generating session key: %v", err)
+	}
+
+	a := &teamAuth{
+		config:     config,
+		apiTokens:  apiTokens,
+		sessionKey: key,
+		now:        time.Now,
+	}
+	if config.OIDC.Enabled() {
+		a.oidc = newOIDCProvider(config.OIDC, http.DefaultClient)
+	}
+	return a, nil
+}
+
+// Puts all requests to the web server behind team mode.
+// When team mode is off, returns the handler unchanged.
+func (a *teamAuth) wrap(next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, teamAuthPathPrefix) {
+			a.serveAuth(w, req)
+			return
+		}
+
+		// The external API checks its own tokens.
+		if strings.HasPrefix(req.URL.Path, ExternalAPIPrefix+"/") {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		id, ok := a.identify(req)
+		if !ok {
+			a.requireLogin(w, req)
+			return
+		}
+
+		if !id.Role.Allows(permissionForRequest(req)) {
+			http.Error(w, fmt.Sprintf("Forbidden: you have %s access to this Tilt session", id.Role), http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(req.Context(), teamIdentityKey{}, id)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+func (a *teamAuth) identify(req *http.Request) (TeamIdentity, bool) {
+	if a.config.LocalOwner && isLocalRequest(req) {
+		return TeamIdentity{Role: TeamRoleOwner}, true
+	}
+
+	if secret, ok := bearerToken(req); ok && a.apiTokens != nil {
+		token, ok, err := a.apiTokens.Verify(secret)
+		if err != nil || !ok {
+			return TeamIdentity{}, false
+		}
+		return tokenIdentity(token), true
+	}
+
+	cookie, err := req.Cookie(teamSessionCookieName)
+	if err != nil {
+		return TeamIdentity{}, false
+	}
+
+	var session teamSession
+	err = a.verify(cookie.Value, &session)
+	if err != nil || !a.now().Before(time.Unix(session.Expires, 0)) {
+		return TeamIdentity{}, false
+	}
+	return session.Identity, true
+}
+
+// Whether the request came from this machine, and not through a reverse
+// proxy that says where it came from.
+func isLocalRequest(req *http.Request) bool {
+	if req.Header.Get("X-Forwarded-For") != "" || req.Header.Get("Forwarded") != "" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Matches updates to the status of a UIButton through either apiserver proxy,
+// which is how the web UI clicks buttons.
+var uiButtonStatusPath = regexp.MustCompile(`^(` + apiServerProxyPrefix + `|` + regexp.QuoteMeta(extAPIServerPrefix) +
+	`)/apis/tilt\.dev/\w+/uibuttons/[^/]+/status$`)
+
+// Decides what a request does to the session, so that we can check
+// whether the user's role allows it.
+//
+// Reads are views, except for the engine dump and the profiler, which
+// expose more than the web UI does. Changing your UI preferences is an
+// edit, like any other write. Triggering a
+// build or clicking a button is a trigger. Everything else, like changing
+// settings or editing API objects through the apiserver proxy, is an edit.
+// So is typing into a running command, which can do anything the command
+// can.
+//
+// Enabling and disabling resources through the external API needs its own
+// permission. Bulk actions only need a trigger here, and check the action
+// they were asked to do themselves.
+func permissionForRequest(req *http.Request) TeamPermission {
+	path := req.URL.Path
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		if path == "/api/dump/engine" || strings.HasPrefix(path, "/debug/") {
+			return TeamPermissionEdit
+		}
+		return TeamPermissionView
+	case http.MethodPost:
+		if path == "/api/trigger" || path == restartPath || path == bulkActionPath || path == ExternalAPIPrefix+"/bulk" {
+			return TeamPermissionTrigger
+		}
+		if strings.HasPrefix(path, ExternalAPIPrefix+"/resources/") {
+			switch {
+			case strings.HasSuffix(path, "/trigger"):
+				return TeamPermissionTrigger
+			case strings.HasSuffix(path, "/enable"), strings.HasSuffix(path, "/disable"):
+				return TeamPermissionEnable
+			}
+		}
+	case http.MethodPut:
+		if uiButtonStatusPath.MatchString(path) {
+			return TeamPermissionTrigger
+		}
+	}
+	return TeamPermissionEdit
+}
+
+func (a *teamAuth) requireLogin(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet && strings.Contains(req.Header.Get("Accept"), "text/html") {
+		loginURL := url.URL{Path: teamLoginPath, RawQuery: url.Values{"next": {req.URL.RequestURI()}}.Encode()}
+		http.Redirect(w, req, loginURL.String(), http.StatusFound)
+		return
+	}
+	http.Error(w, "Unauthorized: sign in at "+teamLoginPath, http.StatusUnauthorized)
+}
+
+func (a *teamAuth) serveAuth(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case teamLoginPath:
+		a.serveLogin(w, req)
+	case teamLogoutPath:
+		a.clearCookie(w, req, teamSessionCookieName)
+		http.Redirect(w, req, teamLoginPath, http.StatusFound)
+	case teamOIDCLoginPath:
+		a.serveOIDCLogin(w, req)
+	case teamOIDCCallbackPath:
+		a.serveOIDCCallback(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Sign in to Tilt</title></head>
+<body style="font-family: sans-serif; max-width: 24em; margin: 4em auto;">
+<h1>Sign in to Tilt</h1>
+{{if .Error}}<p style="color: #c00;">{{.Error}}</p>{{end}}
+{{if .TokenEnabled}}
+<form method="POST" action="{{.LoginPath}}">
+<input type="hidden" name="next" value="{{.Next}}">
+<label>Viewer token <input type="password" name="token" autofocus></label>
+<button type="submit">Sign in</button>
+</form>
+{{end}}
+{{if .OIDCURL}}<p><a href="{{.OIDCURL}}">Sign in with single sign-on</a></p>{{end}}
+</body>
+</html>
+`))
+
+func (a *teamAuth) serveLogin(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		a.renderLogin(w, req, safeNext(req.URL.Query().Get("next")), "", http.StatusOK)
+	case http.MethodPost:
+		next := safeNext(req.FormValue("next"))
+		token := req.FormValue("token")
+		if a.config.ViewerToken == "" ||
+			subtle.ConstantTimeCompare([]byte(token), []byte(a.config.ViewerToken)) != 1 {
+			a.renderLogin(w, req, next, "Invalid viewer token", http.StatusUnauthorized)
+			return
+		}
+		a.startSession(w, req, TeamIdentity{Role: TeamRoleViewer})
+		http.Redirect(w, req, next, http.StatusFound)
+	default:
+		http.Error(w, "must be GET or POST request", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *teamAuth) renderLogin(w http.ResponseWriter, req *http.Request, next string, errMsg string, status int) {
+	oidcURL := ""
+	if a.oidc != nil {
+		u := url.URL{Path: teamOIDCLoginPath, RawQuery: url.Values{"next": {next}}.Encode()}
+		oidcURL = u.String()
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := loginTemplate.Execute(w, map[string]interface{}{
+		"Error":        errMsg,
+		"TokenEnabled": a.config.ViewerToken != "",
+		"LoginPath":    teamLoginPath,
+		"Next":         next,
+		"OIDCURL":      oidcURL,
+	})
+	if err != nil {
+		logger.Get(req.Context()).Debugf("rendering login page: %v", err)
+	}
+}
+
+// Only redirect to paths on this server after sign-in.
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+type teamSession struct {
+	Identity TeamIdentity `json:"identity"`
+	Expires  int64        `json:"expires"`
+}
+
+func (a *teamAuth) startSession(w http.ResponseWriter, req *http.Request, id TeamIdentity) {
+	expires := a.now().Add(teamSessionTTL)
+	value, err := a.sign(teamSession{Identity: id, Expires: expires.Unix()})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("starting session: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, req, teamSessionCookieName, value, expires)
+}
+
+func (a *teamAuth) setCookie(w http.ResponseWriter, req *http.Request, name, value string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (a *teamAuth) clearCookie(w http.ResponseWriter, req *http.Request, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func isSecureRequest(req *http.Request) bool {
+	return req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// Encodes v as JSON with an HMAC, so that clients can hold on to it
+// but can't forge it.
+func (a *teamAuth) sign(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, a.sessionKey)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (a *teamAuth) verify(value string, v interface{}) error {
+	encodedPayload, encodedSig, ok := strings.Cut(value, ".")
+	if !ok {
+		return fmt.Errorf("malformed value")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return fmt.Errorf("malformed payload: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, a.sessionKey)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return json.Unmarshal(payload, v)
+}
+
+// Tells the web UI who's signed in, so that it can hide the controls
+// they can't use.
+func (s *HeadsUpServer) HandleWhoAmI(w http.ResponseWriter, req *http.Request) {
+	id := teamIdentityFromRequest(req)
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(struct {
+		TeamIdentity
+		ReadOnly   bool `json:"readOnly"`
+		CanTrigger bool `json:"canTrigger"`
+	}{id, id.ReadOnly(), id.Role.Allows(TeamPermissionTrigger)})
+	if err != nil {
+		logger.Get(s.ctx).Verbosef("rendering whoami: %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/apitoken"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+func TestTeamAuthDisabled(t *testing.T) {
+	a, err := newTeamAuth(TeamAuthConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	f := newTeamAuthFixture(t, a)
+	rr := f.request(http.MethodPost, "/api/trigger", remoteAddr, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, TeamRoleOwner, f.lastIdentity.Role)
+}
+
+func TestTeamAuthLocalRequestsMustSignIn(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret"}))
+
+	rr := f.request(http.MethodPost, "/api/trigger", "127.0.0.1:5555", nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, f.called)
+}
+
+func TestTeamAuthLocalOwner(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret", LocalOwner: true}))
+
+	rr := f.request(http.MethodPost, "/api/trigger", "127.0.0.1:5555", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.False(t, f.lastIdentity.ReadOnly())
+
+	rr = f.request(http.MethodPost, "/api/trigger", "[::1]:5555", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestTeamAuthForwardedRequestsAreRemote(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret", LocalOwner: true}))
+
+	rr := f.request(http.MethodGet, "/api/view", "127.0.0.1:5555", func(req *http.Request) {
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestTeamAuthAPIToken(t *testing.T) {
+	tokens := apitoken.NewStore(xdg.FakeBase{Dir: t.TempDir()})
+	operator, err := tokens.Create("laptop", string(TeamRoleOperator), time.Now())
+	require.NoError(t, err)
+
+	a, err := newTeamAuth(TeamAuthConfig{ViewerToken: "secret"}, tokens)
+	require.NoError(t, err)
+	f := newTeamAuthFixture(t, a)
+
+	rr := f.request(http.MethodPost, "/api/trigger", remoteAddr, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+operator)
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, TeamIdentity{Name: "laptop", Role: TeamRoleOperator}, f.lastIdentity)
+
+	rr = f.request(http.MethodPost, "/api/set_tiltfile_args", remoteAddr, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+operator)
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	f.called = false
+	rr = f.request(http.MethodPost, cmdInputPath, remoteAddr, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+operator)
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.False(t, f.called)
+
+	rr = f.request(http.MethodGet, "/api/view", remoteAddr, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer tilt_wrong")
+	})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestTeamAuthRequiresLogin(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret"}))
+
+	rr := f.request(http.MethodGet, "/r/fe/overview", remoteAddr, func(req *http.Request) {
+		req.Header.Set("Accept", "text/html")
+	})
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/login?next=%2Fr%2Ffe%2Foverview", rr.Header().Get("Location"))
+
+	rr = f.request(http.MethodGet, "/api/view", remoteAddr, nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, f.called)
+
+	rr = f.request(http.MethodGet, teamLoginPath, remoteAddr, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Viewer token")
+	assert.NotContains(t, rr.Body.String(), "single sign-on")
+}
+
+func TestTeamAuthViewerToken(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret"}))
+
+	rr := f.login("wrong", "/")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Invalid viewer token")
+	assert.Empty(t, rr.Result().Cookies())
+
+	rr = f.login("secret", "/r/fe/overview")
+	require.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/r/fe/overview", rr.Header().Get("Location"))
+	session := sessionCookie(t, rr)
+	assert.True(t, session.HttpOnly)
+
+	withSession := func(req *http.Request) { req.AddCookie(session) }
+	rr = f.request(http.MethodGet, "/api/view", remoteAddr, withSession)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, TeamIdentity{Role: TeamRoleViewer}, f.lastIdentity)
+
+	rr = f.request(http.MethodGet, "/proxy/apis/tilt.dev/v1alpha1/uibuttons", remoteAddr, withSession)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestTeamAuthViewersAreReadOnly(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret"}))
+	session := sessionCookie(t, f.login("secret", "/"))
+	withSession := func(req *http.Request) { req.AddCookie(session) }
+
+	for _, tc := range []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/trigger"},
+		{http.MethodPost, "/api/set_tiltfile_args"},
+		{http.MethodPut, "/proxy/apis/tilt.dev/v1alpha1/uibuttons/restart/status"},
+		{http.MethodGet, "/api/dump/engine"},
+		{http.MethodGet, "/debug/pprof/"},
+		{http.MethodPut, "/api/preferences/theme"},
+	} {
+		f.called = false
+		rr := f.request(tc.method, tc.path, remoteAddr, withSession)
+		assert.Equal(t, http.StatusForbidden, rr.Code, "%s %s", tc.method, tc.path)
+		assert.False(t, f.called, "%s %s", tc.method, tc.path)
+	}
+}
+
+func TestTeamAuthRejectsBadSessions(t *testing.T) {
+	a := newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret"})
+	f := newTeamAuthFixture(t, a)
+
+	forged := &http.Cookie{Name: teamSessionCookieName, Value: "eyJpZGVudGl0eSI6eyJyb2xlIjoib3duZXIifX0.AAAA"}
+	rr := f.request(http.MethodGet, "/api/view", remoteAddr, func(req *http.Request) { req.AddCookie(forged) })
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	session := sessionCookie(t, f.login("secret", "/"))
+	a.now = func() time.Time { return time.Now().Add(teamSessionTTL + time.Minute) }
+	rr = f.request(http.MethodGet, "/api/view", remoteAddr, func(req *http.Request) { req.AddCookie(session) })
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestTeamAuthExternalAPIUsesItsOwnTokens(t *testing.T) {
+	f := newTeamAuthFixture(t, newTestTeamAuth(t, TeamAuthConfig{ViewerToken: "secret"}))
+
+	rr := f.request(http.MethodPost, ExternalAPIPrefix+"/resources/fe/trigger", remoteAddr, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, f.called)
+}
+
+func TestSafeNext(t *testing.T) {
+	assert.Equal(t, "/r/fe/overview?term=x", safeNext("/r/fe/overview?term=x"))
+	assert.Equal(t, "/", safeNext(""))
+	assert.Equal(t, "/", safeNext("https://evil.example.com/"))
+	assert.Equal(t, "/", safeNext("//evil.example.com/"))
+	assert.Equal(t, "/", safeNext("/\\evil.example.com/"))
+}
+
+func TestOIDCConfigValidate(t *testing.T) {
+	assert.NoError(t, OIDCConfig{}.Validate())
+
+	err := OIDCConfig{IssuerURL: "https://accounts.example.com"}.Validate()
+	assert.EqualError(t, err, `OIDC issuer "https://accounts.example.com" requires a client ID`)
+
+	err = OIDCConfig{IssuerURL: "https://accounts.example.com", ClientID: "tilt"}.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at least one allowed viewer, operator, or owner email")
+
+	err = OIDCConfig{IssuerURL: "https://accounts.example.com", ClientID: "tilt", OperatorEmails: []string{"@example.com"}}.Validate()
+	assert.NoError(t, err)
+}
+
+func TestPermissionForRequest(t *testing.T) {
+	for _, tc := range []struct {
+		method string
+		path   string
+		want   TeamPermission
+	}{
+		{http.MethodGet, "/api/view", TeamPermissionView},
+		{http.MethodGet, "/proxy/apis/tilt.dev/v1alpha1/uibuttons", TeamPermissionView},
+		{http.MethodGet, "/api/dump/engine", TeamPermissionEdit},
+		{http.MethodGet, "/debug/pprof/", TeamPermissionEdit},
+		{http.MethodPost, "/api/trigger", TeamPermissionTrigger},
+		{http.MethodPost, "/api/bulk", TeamPermissionTrigger},
+		{http.MethodPut, "/proxy/apis/tilt.dev/v1alpha1/uibuttons/restart/status", TeamPermissionTrigger},
+		{http.MethodPost, ExternalAPIPrefix + "/resources/fe/trigger", TeamPermissionTrigger},
+		{http.MethodPost, ExternalAPIPrefix + "/resources/fe/enable", TeamPermissionEnable},
+		{http.MethodPost, ExternalAPIPrefix + "/resources/fe/disable", TeamPermissionEnable},
+		{http.MethodPost, ExternalAPIPrefix + "/bulk", TeamPermissionTrigger},
+		{http.MethodPut, "/proxy/apis/tilt.dev/v1alpha1/uibuttons/restart", TeamPermissionEdit},
+		{http.MethodDelete, "/proxy/apis/tilt.dev/v1alpha1/buildhistories/fe", TeamPermissionEdit},
+		{http.MethodPost, "/api/set_tiltfile_args", TeamPermissionEdit},
+		{http.MethodPost, "/api/port_forwards", TeamPermissionEdit},
+		{http.MethodPost, "/api/cmd/input", TeamPermissionEdit},
+		{http.MethodGet, "/api/preferences", TeamPermissionView},
+		{http.MethodPut, "/api/preferences/theme", TeamPermissionEdit},
+		{http.MethodDelete, "/api/preferences/theme", TeamPermissionEdit},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		assert.Equal(t, tc.want, permissionForRequest(req), "%s %s", tc.method, tc.path)
+	}
+}
+
+func TestPreferencesUser(t *testing.T) {
+	assert.Equal(t, "local", preferencesUser(TeamIdentity{Role: TeamRoleOwner}))
+	assert.Equal(t, "viewer", preferencesUser(TeamIdentity{Role: TeamRoleViewer}))
+	assert.Equal(t, "dev@example.com", preferencesUser(TeamIdentity{Name: "dev@example.com", Role: TeamRoleViewer}))
+	assert.NotEqual(t,
+		preferencesObjectName("dev@example.com"),
+		preferencesObjectName("ops@example.com"))
+}
+
+func TestTeamRoles(t *testing.T) {
+	assert.True(t, TeamRoleViewer.Allows(TeamPermissionView))
+	assert.False(t, TeamRoleViewer.Allows(TeamPermissionTrigger))
+	assert.True(t, TeamRoleOperator.Allows(TeamPermissionTrigger))
+	assert.False(t, TeamRoleOperator.Allows(TeamPermissionEnable))
+	assert.True(t, TeamRoleOwner.Allows(TeamPermissionEnable))
+	assert.False(t, TeamRoleOperator.Allows(TeamPermissionEdit))
+	assert.True(t, TeamRoleOwner.Allows(TeamPermissionEdit))
+	assert.False(t, TeamRole("admin").Allows(TeamPermissionView))
+
+	_, err := ParseTeamRole("admin")
+	assert.EqualError(t, err, `invalid role "admin": must be one of viewer, operator, or owner`)
+}
+
+const remoteAddr = "203.0.113.7:5555"
+
+type teamAuthFixture struct {
+	t            *testing.T
+	handler      http.Handler
+	called       bool
+	lastIdentity TeamIdentity
+}
+
+func newTeamAuthFixture(t *testing.T, a *teamAuth) *teamAuthFixture {
+	f := &teamAuthFixture{t: t}
+	f.handler = a.wrap(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		f.called = true
+		f.lastIdentity = teamIdentityFromRequest(req)
+	}))
+	return f
+}
+
+func newTestTeamAuth(t *testing.T, config TeamAuthConfig) *teamAuth {
+	a, err := newTeamAuth(config, nil)
+	require.NoError(t, err)
+	return a
+}
+
+func (f *teamAuthFixture) request(method, path, remoteAddr string, modify func(req *http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if modify != nil {
+		modify(req)
+	}
+	rr := httptest.NewRecorder()
+	f.handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func (f *teamAuthFixture) login(token, next string) *httptest.ResponseRecorder {
+	form := url.Values{"token": {token}, "next": {next}}
+	req := httptest.NewRequest(http.MethodPost, teamLoginPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = remoteAddr
+	rr := httptest.NewRecorder()
+	f.handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func sessionCookie(t *testing.T, rr *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == teamSessionCookieName {
+			return c
+		}
+	}
+	t.Fatalf("no session cookie in response")
+	return nil
+}
@@ -73,6 +73,10 @@ type WebsocketSubscriber struct {
 
 	tiltStartTime    *timestamppb.Timestamp
 	clientCheckpoint logstore.Checkpoint
+
+	// Set when the subscriber doesn't send to a browser,
+	// like a session recording or a query watch.
+	headless bool
 }
 
 type WebsocketConn interface {
@@ -186,6 +190,11 @@ func (ws *WebsocketSubscriber) SendUISessionUpdate(ctx context.Context, uiSessio
 // If a session update triggered an analytics nudge, record it so that we don't
 // nudge again.
 func (ws *WebsocketSubscriber) onSessionUpdateSent(ctx context.Context, uiSession *v1alpha1.UISession) {
+	if ws.headless {
+		// No one sees the nudge.
+		return
+	}
+
 	state := ws.st.RLockState()
 	surfaced := !state.AnalyticsNudgeSurfaced
 	ws.st.RUnlockState()
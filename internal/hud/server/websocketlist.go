@@ -30,6 +30,12 @@ func (l *WebsocketList) Remove(w *WebsocketSubscriber) {
 	}
 }
 
+func (l *WebsocketList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.items)
+}
+
 // Operate on all websockets in the list.
 //
 // While the ForEach is running, the list may not be modified.
@@ -19,4 +19,5 @@ var WireSet = wire.NewSet(
 	ProvideHeadsUpServer,
 	ProvideHeadsUpServerController,
 	NewWebsocketList,
+	NewSessionRecorder,
 )
@@ -11,10 +11,20 @@ type TerminalStream struct {
 	ProcessedLogs logstore.Checkpoint
 	printer       *IncrementalPrinter
 	store         store.RStore
+
+	// Timestamps in front of each line, in the text log format.
+	timestamps logstore.Timestamps
+
+	// Only set in the JSON log format.
+	jsonPrinter *JSONLinePrinter
 }
 
-func NewTerminalStream(printer *IncrementalPrinter, store store.RStore) *TerminalStream {
-	return &TerminalStream{printer: printer, store: store}
+func NewTerminalStream(printer *IncrementalPrinter, store store.RStore, format LogFormat, timestamps logstore.Timestamps) *TerminalStream {
+	h := &TerminalStream{printer: printer, store: store, timestamps: timestamps}
+	if format == LogFormatJSON {
+		h.jsonPrinter = NewJSONLinePrinter(printer.Stdout())
+	}
+	return h
 }
 
 // TODO(nick): We should change this API so that TearDown gets
@@ -26,6 +36,11 @@ func (h *TerminalStream) TearDown(ctx context.Context) {
 
 	_ = h.OnChange(ctx, h.store, store.LegacyChangeSummary())
 
+	if h.jsonPrinter != nil {
+		h.jsonPrinter.Flush()
+		return
+	}
+
 	state := h.store.RLockState()
 	uncompleted := state.LogStore.IsLastSegmentUncompleted()
 	h.store.RUnlockState()
@@ -46,8 +61,24 @@ func (h *TerminalStream) OnChange(ctx context.Context, st store.RStore, _ store.
 		return nil
 	}
 
+	if h.jsonPrinter != nil {
+		state := st.RLockState()
+		segments, checkpoint := state.LogStore.SegmentsSince(h.ProcessedLogs, logstore.SegmentOptions{})
+		st.RUnlockState()
+
+		h.jsonPrinter.Print(segments)
+		h.ProcessedLogs = checkpoint
+		return nil
+	}
+
 	state := st.RLockState()
-	lines := state.LogStore.ContinuingLines(h.ProcessedLogs)
+	timestamps := h.timestamps
+	if timestamps.Mode == logstore.TimestampModeRelative {
+		timestamps.Start = state.TiltStartTime
+	}
+	lines := state.LogStore.ContinuingLinesWithOptions(h.ProcessedLogs, logstore.LineOptions{
+		Timestamps: timestamps,
+	})
 	checkpoint := state.LogStore.Checkpoint()
 	st.RUnlockState()
 
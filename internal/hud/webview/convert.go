@@ -182,9 +182,31 @@ func ToUISession(s store.EngineState) *v1alpha1.UISession {
 
 	status.TiltfileKey = s.MainTiltfilePath()
 
+	status.ResourceGroups = toUIResourceGroups(s)
+
 	return ret
 }
 
+func toUIResourceGroups(s store.EngineState) []v1alpha1.UIResourceGroup {
+	var paths []string
+	for _, m := range s.Manifests() {
+		paths = append(paths, m.GroupPaths()...)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	ordered := model.OrderGroupPaths(paths, s.GroupSettings)
+	result := make([]v1alpha1.UIResourceGroup, 0, len(ordered))
+	for _, p := range ordered {
+		result = append(result, v1alpha1.UIResourceGroup{
+			Path:      p,
+			Collapsed: s.CollapsedGroups[p],
+		})
+	}
+	return result
+}
+
 // Converts an EngineState into a list of UIResources.
 // The order of the list is non-deterministic.
 func ToUIResourceList(state store.EngineState, disableSources map[string][]v1alpha1.DisableSource) ([]*v1alpha1.UIResource, error) {
@@ -282,7 +304,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 	r := &v1alpha1.UIResource{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   mn.String(),
-			Labels: mt.Manifest.Labels,
+			Labels: mt.Manifest.APILabels(),
 		},
 		Status: v1alpha1.UIResourceStatus{
 			LastDeployTime:    lastDeploy,
@@ -296,6 +318,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 			Queued:            s.ManifestInTriggerQueue(mn),
 			DisableStatus:     drs,
 			Waiting:           holdToWaiting(hold),
+			Groups:            mt.Manifest.GroupPaths(),
 		},
 	}
 
@@ -425,7 +448,10 @@ func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource)
 
 	if mt.Manifest.IsLocal() {
 		lState := mt.State.LocalRuntimeState()
-		r.Status.LocalResourceInfo = &v1alpha1.UIResourceLocal{PID: int64(lState.PID)}
+		r.Status.LocalResourceInfo = &v1alpha1.UIResourceLocal{
+			PID:   int64(lState.PID),
+			Usage: lState.Usage.DeepCopy(),
+		}
 	}
 	if mt.Manifest.IsK8s() {
 		kState := mt.State.K8sRuntimeState()
@@ -237,6 +237,36 @@ func TestFeatureFlags(t *testing.T) {
 	})
 }
 
+func TestResourceGroups(t *testing.T) {
+	state := newState(nil)
+	state.GroupSettings = model.GroupSettings{Order: []string{"payments", "payments/db"}}
+	state.CollapsedGroups = map[string]bool{"payments/api": true}
+
+	m := fooManifest.WithLabels(map[string]string{
+		"payments/api": "payments/api",
+		"payments/db":  "payments/db",
+		"frontend":     "frontend",
+	})
+	targ := store.NewManifestTarget(m)
+	targ.State = &store.ManifestState{}
+	state.UpsertManifestTarget(targ)
+
+	v := completeProtoView(t, *state)
+	assert.Equal(t, []v1alpha1.UIResourceGroup{
+		{Path: "payments"},
+		{Path: "payments/db"},
+		{Path: "payments/api", Collapsed: true},
+		{Path: "frontend"},
+	}, v.UiSession.Status.ResourceGroups)
+
+	for _, r := range v.UiResources {
+		if r.Name == "foo" {
+			assert.Equal(t, map[string]string{"payments": "payments", "frontend": "frontend"}, r.Labels)
+			assert.Equal(t, []string{"frontend", "payments/api", "payments/db"}, r.Status.Groups)
+		}
+	}
+}
+
 func TestReadinessCheckFailing(t *testing.T) {
 	m := model.Manifest{
 		Name: "foo",
@@ -0,0 +1,323 @@
+package webview
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type GraphNodeKind string
+
+const (
+	GraphNodeResource GraphNodeKind = "resource"
+	GraphNodeImage    GraphNodeKind = "image"
+)
+
+type GraphEdgeKind string
+
+const (
+	// A resource_deps entry in the Tiltfile.
+	GraphEdgeResourceDep GraphEdgeKind = "resource_dep"
+
+	// A depends_on entry in a Docker Compose file.
+	GraphEdgeComposeDependsOn GraphEdgeKind = "compose_depends_on"
+
+	// An image that a resource deploys.
+	GraphEdgeImage GraphEdgeKind = "image"
+
+	// An image that another image builds on.
+	GraphEdgeImageDep GraphEdgeKind = "image_dep"
+)
+
+type GraphNode struct {
+	ID   string        `json:"id"`
+	Kind GraphNodeKind `json:"kind"`
+	Name string        `json:"name"`
+
+	// For images, the resources that deploy them.
+	Resources []string `json:"resources,omitempty"`
+
+	// Only set for resources.
+	UpdateStatus  v1alpha1.UpdateStatus  `json:"updateStatus,omitempty"`
+	RuntimeStatus v1alpha1.RuntimeStatus `json:"runtimeStatus,omitempty"`
+	Readiness     ReadinessStatus        `json:"readiness,omitempty"`
+
+	// Whether the resource is on the critical path of the current update.
+	Critical bool `json:"critical,omitempty"`
+}
+
+// An edge points from a dependency to the node that depends on it,
+// in the order things happen.
+type GraphEdge struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Kind     GraphEdgeKind `json:"kind"`
+	Critical bool          `json:"critical,omitempty"`
+}
+
+type ResourceGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+
+	// The chain of resources that the rest of the current update is waiting
+	// on, dependencies first. Empty when every resource is ready.
+	CriticalPath []string `json:"criticalPath"`
+}
+
+func resourceNodeID(mn model.ManifestName) string {
+	return fmt.Sprintf("resource:%s", mn)
+}
+
+func imageNodeID(id model.TargetID) string {
+	return fmt.Sprintf("image:%s", id.Name)
+}
+
+// Builds the dependency graph of the resources in the Tiltfile, with
+// statuses from their UIResources.
+func BuildResourceGraph(manifests []model.Manifest, resources []v1alpha1.UIResource) ResourceGraph {
+	uiResources := make(map[string]v1alpha1.UIResource, len(resources))
+	for _, r := range resources {
+		uiResources[r.Name] = r
+	}
+
+	graph := ResourceGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}, CriticalPath: []string{}}
+	images := make(map[string]*GraphNode)
+	var imageIDs []string
+	resourceDeps := make(map[model.ManifestName][]model.ManifestName)
+
+	for _, m := range manifests {
+		node := GraphNode{
+			ID:   resourceNodeID(m.Name),
+			Kind: GraphNodeResource,
+			Name: m.Name.String(),
+		}
+		if r, ok := uiResources[m.Name.String()]; ok {
+			node.UpdateStatus = r.Status.UpdateStatus
+			node.RuntimeStatus = r.Status.RuntimeStatus
+			node.Readiness = UIResourceReadiness(r).Status
+		}
+		graph.Nodes = append(graph.Nodes, node)
+
+		composeDeps := composeDependsOn(m)
+		for _, dep := range m.ResourceDependencies {
+			kind := GraphEdgeResourceDep
+			if composeDeps[dep.String()] {
+				kind = GraphEdgeComposeDependsOn
+			}
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From: resourceNodeID(dep),
+				To:   node.ID,
+				Kind: kind,
+			})
+		}
+		resourceDeps[m.Name] = m.ResourceDependencies
+
+		for _, iTarget := range m.ImageTargets {
+			id := imageNodeID(iTarget.ID())
+			image, ok := images[id]
+			if !ok {
+				image = &GraphNode{
+					ID:   id,
+					Kind: GraphNodeImage,
+					Name: iTarget.ImageMapSpec.Selector,
+				}
+				images[id] = image
+				imageIDs = append(imageIDs, id)
+
+				for _, depID := range iTarget.DependencyIDs() {
+					graph.Edges = append(graph.Edges, GraphEdge{
+						From: imageNodeID(depID),
+						To:   id,
+						Kind: GraphEdgeImageDep,
+					})
+				}
+			}
+			image.Resources = append(image.Resources, m.Name.String())
+		}
+
+		// Base images are built along the way, but only the images
+		// the resource deploys point at it.
+		for _, iTarget := range m.ImageTargets {
+			if isBaseImage(m, iTarget.ID()) {
+				continue
+			}
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From: imageNodeID(iTarget.ID()),
+				To:   node.ID,
+				Kind: GraphEdgeImage,
+			})
+		}
+	}
+
+	for _, id := range imageIDs {
+		graph.Nodes = append(graph.Nodes, *images[id])
+	}
+
+	path := criticalPath(manifests, uiResources, resourceDeps)
+	onPath := make(map[string]bool, len(path))
+	for i, mn := range path {
+		graph.CriticalPath = append(graph.CriticalPath, mn.String())
+		onPath[resourceNodeID(mn)] = true
+		if i > 0 {
+			for j, e := range graph.Edges {
+				if e.From == resourceNodeID(path[i-1]) && e.To == resourceNodeID(mn) {
+					graph.Edges[j].Critical = true
+				}
+			}
+		}
+	}
+	for i, n := range graph.Nodes {
+		graph.Nodes[i].Critical = onPath[n.ID]
+	}
+
+	return graph
+}
+
+// Whether another image in the manifest builds on this one.
+func isBaseImage(m model.Manifest, id model.TargetID) bool {
+	for _, iTarget := range m.ImageTargets {
+		for _, depID := range iTarget.DependencyIDs() {
+			if depID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The services that a Docker Compose service lists under depends_on.
+//
+// The Tiltfile merges them into the resource deps, so we read them back
+// out of the service config to tell them apart.
+func composeDependsOn(m model.Manifest) map[string]bool {
+	result := make(map[string]bool)
+	dcTarget, ok := m.DeployTarget.(model.DockerComposeTarget)
+	if !ok || dcTarget.ServiceYAML == "" {
+		return result
+	}
+
+	var svc struct {
+		DependsOn interface{} `json:"depends_on"`
+	}
+	err := yaml.Unmarshal([]byte(dcTarget.ServiceYAML), &svc)
+	if err != nil {
+		return result
+	}
+
+	// depends_on is either a list of names, or a map from names to conditions.
+	switch deps := svc.DependsOn.(type) {
+	case []interface{}:
+		for _, dep := range deps {
+			if name, ok := dep.(string); ok {
+				result[name] = true
+			}
+		}
+	case map[string]interface{}:
+		for name := range deps {
+			result[name] = true
+		}
+	}
+	return result
+}
+
+// Finds the longest chain of resources that aren't ready yet, weighted by
+// how long each one took to build last time.
+//
+// Resources that are ready don't hold anything up, so the current update
+// can't finish any sooner than this chain does.
+func criticalPath(manifests []model.Manifest, resources map[string]v1alpha1.UIResource,
+	deps map[model.ManifestName][]model.ManifestName) []model.ManifestName {
+	pending := make(map[model.ManifestName]bool)
+	var names []model.ManifestName
+	for _, m := range manifests {
+		r, ok := resources[m.Name.String()]
+		if !ok {
+			continue
+		}
+		if UIResourceReadiness(r).Status == ReadinessPending {
+			pending[m.Name] = true
+			names = append(names, m.Name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	type chain struct {
+		cost time.Duration
+		len  int
+		prev model.ManifestName
+	}
+	best := make(map[model.ManifestName]chain)
+	visiting := make(map[model.ManifestName]bool)
+	var visit func(mn model.ManifestName) chain
+	visit = func(mn model.ManifestName) chain {
+		if c, ok := best[mn]; ok {
+			return c
+		}
+		c := chain{cost: lastBuildDuration(resources[mn.String()]), len: 1}
+		if visiting[mn] {
+			// A cycle. The Tiltfile rejects these, so just stop here.
+			return c
+		}
+		visiting[mn] = true
+		var bestDep chain
+		for _, dep := range deps[mn] {
+			if !pending[dep] {
+				continue
+			}
+			dc := visit(dep)
+			if bestDep.len == 0 || longerChain(dc.cost, dc.len, bestDep.cost, bestDep.len) {
+				bestDep = dc
+				c.prev = dep
+			}
+		}
+		c.cost += bestDep.cost
+		c.len += bestDep.len
+		visiting[mn] = false
+		best[mn] = c
+		return c
+	}
+
+	var end model.ManifestName
+	var endChain chain
+	for _, mn := range names {
+		c := visit(mn)
+		if endChain.len == 0 || longerChain(c.cost, c.len, endChain.cost, endChain.len) {
+			end = mn
+			endChain = c
+		}
+	}
+	if end == "" {
+		return nil
+	}
+
+	var path []model.ManifestName
+	for mn := end; mn != ""; mn = best[mn].prev {
+		path = append([]model.ManifestName{mn}, path...)
+	}
+	return path
+}
+
+func longerChain(cost time.Duration, n int, otherCost time.Duration, otherN int) bool {
+	if cost != otherCost {
+		return cost > otherCost
+	}
+	return n > otherN
+}
+
+func lastBuildDuration(r v1alpha1.UIResource) time.Duration {
+	if len(r.Status.BuildHistory) == 0 {
+		return 0
+	}
+	b := r.Status.BuildHistory[0]
+	if b.StartTime.IsZero() || b.FinishTime.IsZero() {
+		return 0
+	}
+	return b.FinishTime.Sub(b.StartTime.Time)
+}
@@ -0,0 +1,128 @@
+package webview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func graphImage(ref string, deps ...string) model.ImageTarget {
+	return model.MustNewImageTarget(container.MustParseSelector(ref)).
+		WithBuildDetails(model.DockerBuild{}).
+		WithImageMapDeps(deps)
+}
+
+func graphResource(name string, update v1alpha1.UpdateStatus, runtime v1alpha1.RuntimeStatus, build time.Duration) v1alpha1.UIResource {
+	r := readinessResource(name, update, runtime)
+	if build > 0 {
+		start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		r.Status.BuildHistory = []v1alpha1.UIBuildTerminated{{
+			StartTime:  metav1.NewMicroTime(start),
+			FinishTime: metav1.NewMicroTime(start.Add(build)),
+		}}
+	}
+	return r
+}
+
+func TestResourceGraphEdges(t *testing.T) {
+	base := graphImage("base")
+	api := graphImage("api", base.ImageMapName())
+	db := model.Manifest{Name: "db"}.WithDeployTarget(model.K8sTarget{})
+	server := model.Manifest{
+		Name:                 "server",
+		ResourceDependencies: []model.ManifestName{"db"},
+	}.WithImageTargets([]model.ImageTarget{base, api}).WithDeployTarget(model.K8sTarget{})
+
+	graph := BuildResourceGraph([]model.Manifest{db, server}, nil)
+
+	assert.Equal(t, []GraphNode{
+		{ID: "resource:db", Kind: GraphNodeResource, Name: "db"},
+		{ID: "resource:server", Kind: GraphNodeResource, Name: "server"},
+		{ID: "image:base", Kind: GraphNodeImage, Name: "base", Resources: []string{"server"}},
+		{ID: "image:api", Kind: GraphNodeImage, Name: "api", Resources: []string{"server"}},
+	}, graph.Nodes)
+	assert.Equal(t, []GraphEdge{
+		{From: "resource:db", To: "resource:server", Kind: GraphEdgeResourceDep},
+		{From: "image:base", To: "image:api", Kind: GraphEdgeImageDep},
+		{From: "image:api", To: "resource:server", Kind: GraphEdgeImage},
+	}, graph.Edges)
+	assert.Empty(t, graph.CriticalPath)
+}
+
+func TestResourceGraphComposeDependsOn(t *testing.T) {
+	redis := model.Manifest{Name: "redis"}.WithDeployTarget(model.DockerComposeTarget{
+		Name:        "redis",
+		ServiceYAML: "image: redis\n",
+	})
+	setup := model.Manifest{Name: "setup"}.WithDeployTarget(model.K8sTarget{})
+	web := model.Manifest{
+		Name:                 "web",
+		ResourceDependencies: []model.ManifestName{"redis", "setup"},
+	}.WithDeployTarget(model.DockerComposeTarget{
+		Name:        "web",
+		ServiceYAML: "image: web\ndepends_on:\n  redis:\n    condition: service_started\n",
+	})
+
+	graph := BuildResourceGraph([]model.Manifest{redis, setup, web}, nil)
+
+	assert.Equal(t, []GraphEdge{
+		{From: "resource:redis", To: "resource:web", Kind: GraphEdgeComposeDependsOn},
+		{From: "resource:setup", To: "resource:web", Kind: GraphEdgeResourceDep},
+	}, graph.Edges)
+}
+
+func TestComposeDependsOnList(t *testing.T) {
+	m := model.Manifest{Name: "web"}.WithDeployTarget(model.DockerComposeTarget{
+		Name:        "web",
+		ServiceYAML: "depends_on:\n- redis\n- db\n",
+	})
+	assert.Equal(t, map[string]bool{"redis": true, "db": true}, composeDependsOn(m))
+}
+
+func TestResourceGraphCriticalPath(t *testing.T) {
+	// db -> migrate -> server is the slowest chain still in progress.
+	// cache is pending too, but it's quick, and frontend is already up.
+	manifests := []model.Manifest{
+		{Name: "db"},
+		{Name: "cache"},
+		{Name: "migrate", ResourceDependencies: []model.ManifestName{"db"}},
+		{Name: "frontend"},
+		{Name: "server", ResourceDependencies: []model.ManifestName{"migrate", "cache", "frontend"}},
+	}
+	resources := []v1alpha1.UIResource{
+		graphResource("db", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusPending, 10*time.Second),
+		graphResource("cache", v1alpha1.UpdateStatusInProgress, v1alpha1.RuntimeStatusPending, time.Second),
+		graphResource("migrate", v1alpha1.UpdateStatusPending, v1alpha1.RuntimeStatusNotApplicable, 5*time.Second),
+		graphResource("frontend", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusOK, 30*time.Second),
+		graphResource("server", v1alpha1.UpdateStatusPending, v1alpha1.RuntimeStatusPending, 2*time.Second),
+	}
+
+	graph := BuildResourceGraph(manifests, resources)
+
+	assert.Equal(t, []string{"db", "migrate", "server"}, graph.CriticalPath)
+
+	var critical []string
+	for _, n := range graph.Nodes {
+		if n.Critical {
+			critical = append(critical, n.Name)
+		}
+	}
+	assert.Equal(t, []string{"db", "migrate", "server"}, critical)
+
+	var criticalEdges []GraphEdge
+	for _, e := range graph.Edges {
+		if e.Critical {
+			criticalEdges = append(criticalEdges, e)
+		}
+	}
+	assert.Equal(t, []GraphEdge{
+		{From: "resource:db", To: "resource:migrate", Kind: GraphEdgeResourceDep, Critical: true},
+		{From: "resource:migrate", To: "resource:server", Kind: GraphEdgeResourceDep, Critical: true},
+	}, criticalEdges)
+}
@@ -0,0 +1,198 @@
+package webview
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Selects fields from an object's JSON representation.
+//
+// Each field is a dot-separated path, like "status.runtimeStatus".
+// Selecting a field selects everything under it. Paths that run into
+// a list select from each element of the list, so "status.buildHistory.error"
+// selects the error of every build.
+//
+// An empty selector selects the whole object.
+type FieldSelector struct {
+	tree fieldTree
+}
+
+// A nil tree selects everything under it.
+type fieldTree map[string]fieldTree
+
+func ParseFieldSelector(fields []string) (FieldSelector, error) {
+	var tree fieldTree
+	for _, field := range fields {
+		for _, path := range strings.Split(field, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+
+			parts := strings.Split(path, ".")
+			for _, part := range parts {
+				if part == "" {
+					return FieldSelector{}, fmt.Errorf("invalid field %q: empty path segment", path)
+				}
+			}
+
+			if tree == nil {
+				tree = fieldTree{}
+			}
+			tree.add(parts)
+		}
+	}
+	return FieldSelector{tree: tree}, nil
+}
+
+func (t fieldTree) add(parts []string) {
+	child, ok := t[parts[0]]
+	if ok && child == nil {
+		// Already selecting everything under this field.
+		return
+	}
+	if len(parts) == 1 {
+		t[parts[0]] = nil
+		return
+	}
+	if child == nil {
+		child = fieldTree{}
+		t[parts[0]] = child
+	}
+	child.add(parts[1:])
+}
+
+// Returns the selected fields of the object, as decoded JSON.
+func (s FieldSelector) Select(obj interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]interface{}
+	err = json.Unmarshal(b, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, ok := s.tree.project(decoded)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return selected.(map[string]interface{}), nil
+}
+
+// Returns false if nothing in the value was selected.
+func (t fieldTree) project(value interface{}) (interface{}, bool) {
+	if t == nil {
+		return value, true
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(t))
+		for key, child := range t {
+			v, ok := value[key]
+			if !ok {
+				continue
+			}
+			projected, ok := child.project(v)
+			if ok {
+				result[key] = projected
+			}
+		}
+		return result, true
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, v := range value {
+			projected, ok := t.project(v)
+			if ok {
+				result = append(result, projected)
+			}
+		}
+		return result, true
+	}
+
+	// The path continues past a plain value.
+	return nil, false
+}
+
+// The result of a UI query: the selected fields of each resource, by name.
+type QueryResult struct {
+	Resources map[string]map[string]interface{} `json:"resources"`
+}
+
+func BuildQueryResult(resources []v1alpha1.UIResource, selector FieldSelector) (QueryResult, error) {
+	result := QueryResult{Resources: make(map[string]map[string]interface{}, len(resources))}
+	for _, r := range resources {
+		selected, err := selector.Select(r)
+		if err != nil {
+			return QueryResult{}, fmt.Errorf("selecting fields of %s: %v", r.Name, err)
+		}
+		result.Resources[r.Name] = selected
+	}
+	return result, nil
+}
+
+// Returns a JSON merge patch (RFC 7386) that turns the old result into the new one,
+// or nil if they're the same.
+func (r QueryResult) PatchFrom(old QueryResult) map[string]interface{} {
+	resources := make(map[string]interface{})
+	for name := range old.Resources {
+		if _, ok := r.Resources[name]; !ok {
+			resources[name] = nil
+		}
+	}
+	for name, obj := range r.Resources {
+		oldObj, ok := old.Resources[name]
+		if !ok {
+			resources[name] = obj
+			continue
+		}
+		patch := mergePatch(oldObj, obj)
+		if len(patch) > 0 {
+			resources[name] = patch
+		}
+	}
+
+	if len(resources) == 0 {
+		return nil
+	}
+	return map[string]interface{}{"resources": resources}
+}
+
+func mergePatch(old, new map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			result[key] = nil
+		}
+	}
+	for key, newValue := range new {
+		oldValue, ok := old[key]
+		if !ok {
+			result[key] = newValue
+			continue
+		}
+
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			patch := mergePatch(oldMap, newMap)
+			if len(patch) > 0 {
+				result[key] = patch
+			}
+			continue
+		}
+
+		// Lists and plain values are replaced whole.
+		if !reflect.DeepEqual(oldValue, newValue) {
+			result[key] = newValue
+		}
+	}
+	return result
+}
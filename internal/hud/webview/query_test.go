@@ -0,0 +1,123 @@
+package webview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestFieldSelectorSelect(t *testing.T) {
+	uir := v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe"},
+		Status: v1alpha1.UIResourceStatus{
+			RuntimeStatus: v1alpha1.RuntimeStatusOK,
+			UpdateStatus:  v1alpha1.UpdateStatusError,
+			BuildHistory: []v1alpha1.UIBuildTerminated{
+				{Error: "oops", SpanID: "build:2"},
+				{SpanID: "build:1"},
+			},
+		},
+	}
+
+	selector, err := ParseFieldSelector([]string{"metadata.name,status.runtimeStatus", "status.buildHistory.error"})
+	require.NoError(t, err)
+	selected, err := selector.Select(uir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "fe"},
+		"status": map[string]interface{}{
+			"runtimeStatus": "ok",
+			"buildHistory": []interface{}{
+				map[string]interface{}{"error": "oops"},
+				map[string]interface{}{},
+			},
+		},
+	}, selected)
+
+	// A field selects everything under it, even when a deeper path was selected first.
+	selector, err = ParseFieldSelector([]string{"status.buildHistory.error,status"})
+	require.NoError(t, err)
+	selected, err = selector.Select(uir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status"}, keys(selected))
+	assert.Equal(t, "error", selected["status"].(map[string]interface{})["updateStatus"])
+
+	// Paths past a plain value select nothing.
+	selector, err = ParseFieldSelector([]string{"metadata.name.first"})
+	require.NoError(t, err)
+	selected, err = selector.Select(uir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"metadata": map[string]interface{}{}}, selected)
+}
+
+func TestParseFieldSelectorErrors(t *testing.T) {
+	_, err := ParseFieldSelector([]string{"status..runtimeStatus"})
+	assert.EqualError(t, err, `invalid field "status..runtimeStatus": empty path segment`)
+}
+
+func TestQueryResultPatchFrom(t *testing.T) {
+	selector, err := ParseFieldSelector([]string{"status.runtimeStatus,status.updateStatus"})
+	require.NoError(t, err)
+
+	old, err := BuildQueryResult([]v1alpha1.UIResource{
+		queryUIResource("fe", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusInProgress),
+		queryUIResource("be", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK),
+	}, selector)
+	require.NoError(t, err)
+
+	assert.Nil(t, old.PatchFrom(old))
+
+	new, err := BuildQueryResult([]v1alpha1.UIResource{
+		queryUIResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusInProgress),
+		queryUIResource("db", v1alpha1.RuntimeStatusPending, ""),
+	}, selector)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"resources": map[string]interface{}{
+			"fe": map[string]interface{}{
+				"status": map[string]interface{}{"runtimeStatus": "ok"},
+			},
+			"db": map[string]interface{}{
+				"status": map[string]interface{}{"runtimeStatus": "pending"},
+			},
+			"be": nil,
+		},
+	}, new.PatchFrom(old))
+
+	// Fields that go away are patched to null.
+	newer, err := BuildQueryResult([]v1alpha1.UIResource{
+		queryUIResource("fe", v1alpha1.RuntimeStatusOK, ""),
+		queryUIResource("db", v1alpha1.RuntimeStatusPending, ""),
+	}, selector)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"resources": map[string]interface{}{
+			"fe": map[string]interface{}{
+				"status": map[string]interface{}{"updateStatus": nil},
+			},
+		},
+	}, newer.PatchFrom(new))
+}
+
+func queryUIResource(name string, runtime v1alpha1.RuntimeStatus, update v1alpha1.UpdateStatus) v1alpha1.UIResource {
+	return v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1alpha1.UIResourceStatus{
+			RuntimeStatus: runtime,
+			UpdateStatus:  update,
+		},
+	}
+}
+
+func keys(m map[string]interface{}) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
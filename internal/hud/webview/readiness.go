@@ -0,0 +1,130 @@
+package webview
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// ReadinessStatus summarizes whether a resource (or a whole Tilt session)
+// is ready, for tools like CI pipelines that need a single yes or no.
+type ReadinessStatus string
+
+const (
+	ReadinessReady   ReadinessStatus = "ready"
+	ReadinessPending ReadinessStatus = "pending"
+	ReadinessError   ReadinessStatus = "error"
+
+	// Resources that aren't expected to run, like disabled resources and
+	// manual resources that haven't been triggered. They don't count
+	// towards the aggregate status.
+	ReadinessSkipped ReadinessStatus = "skipped"
+)
+
+type ResourceReadiness struct {
+	Name    string          `json:"name"`
+	Status  ReadinessStatus `json:"status"`
+	Reason  string          `json:"reason,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+type Readiness struct {
+	// Ready if every resource is ready or skipped, error if any resource
+	// has an error, and pending otherwise.
+	Status    ReadinessStatus     `json:"status"`
+	Resources []ResourceReadiness `json:"resources"`
+}
+
+func (r Readiness) Ready() bool {
+	return r.Status == ReadinessReady
+}
+
+// Resources that aren't ready, in name order.
+func (r Readiness) NotReady() []ResourceReadiness {
+	result := []ResourceReadiness{}
+	for _, res := range r.Resources {
+		if res.Status == ReadinessPending || res.Status == ReadinessError {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+// Combines the readiness of all resources into one status.
+//
+// A session with no resources is pending, because the Tiltfile
+// hasn't created them yet.
+func AggregateReadiness(resources []v1alpha1.UIResource) Readiness {
+	result := Readiness{Status: ReadinessPending, Resources: []ResourceReadiness{}}
+	anyPending := len(resources) == 0
+	anyError := false
+	for _, r := range resources {
+		rr := UIResourceReadiness(r)
+		switch rr.Status {
+		case ReadinessPending:
+			anyPending = true
+		case ReadinessError:
+			anyError = true
+		}
+		result.Resources = append(result.Resources, rr)
+	}
+	sort.Slice(result.Resources, func(i, j int) bool {
+		return result.Resources[i].Name < result.Resources[j].Name
+	})
+
+	if anyError {
+		result.Status = ReadinessError
+	} else if !anyPending {
+		result.Status = ReadinessReady
+	}
+	return result
+}
+
+func UIResourceReadiness(r v1alpha1.UIResource) ResourceReadiness {
+	s := r.Status
+	result := ResourceReadiness{Name: r.Name}
+	if s.DisableStatus.State == v1alpha1.DisableStateDisabled {
+		result.Status = ReadinessSkipped
+		result.Reason = "Disabled"
+		return result
+	}
+
+	if s.UpdateStatus == v1alpha1.UpdateStatusNone &&
+		(s.RuntimeStatus == v1alpha1.RuntimeStatusNone || s.RuntimeStatus == v1alpha1.RuntimeStatusNotApplicable) {
+		result.Status = ReadinessSkipped
+		result.Reason = "NotTriggered"
+		return result
+	}
+
+	c := UIResourceReadyCondition(s)
+	if c.Status == metav1.ConditionTrue {
+		result.Status = ReadinessReady
+		return result
+	}
+
+	result.Reason = c.Reason
+	switch c.Reason {
+	case "UpdateError":
+		result.Status = ReadinessError
+		if len(s.BuildHistory) > 0 {
+			result.Message = s.BuildHistory[0].Error
+		}
+	case "RuntimeError":
+		result.Status = ReadinessError
+		if s.K8sResourceInfo != nil {
+			result.Message = s.K8sResourceInfo.PodStatusMessage
+			if result.Message == "" {
+				result.Message = s.K8sResourceInfo.PodStatus
+			}
+		}
+	default:
+		result.Status = ReadinessPending
+		if s.Waiting != nil {
+			result.Message = fmt.Sprintf("waiting: %s", s.Waiting.Reason)
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package webview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func readinessResource(name string, update v1alpha1.UpdateStatus, runtime v1alpha1.RuntimeStatus) v1alpha1.UIResource {
+	return v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1alpha1.UIResourceStatus{
+			UpdateStatus:  update,
+			RuntimeStatus: runtime,
+		},
+	}
+}
+
+func TestAggregateReadinessEmpty(t *testing.T) {
+	r := AggregateReadiness(nil)
+	assert.Equal(t, ReadinessPending, r.Status)
+	assert.False(t, r.Ready())
+}
+
+func TestAggregateReadinessAllReady(t *testing.T) {
+	disabled := readinessResource("disabled", v1alpha1.UpdateStatusPending, v1alpha1.RuntimeStatusPending)
+	disabled.Status.DisableStatus.State = v1alpha1.DisableStateDisabled
+
+	r := AggregateReadiness([]v1alpha1.UIResource{
+		readinessResource("server", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusOK),
+		readinessResource("(Tiltfile)", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusNotApplicable),
+		readinessResource("manual", v1alpha1.UpdateStatusNone, v1alpha1.RuntimeStatusNone),
+		disabled,
+	})
+	assert.True(t, r.Ready())
+	assert.Equal(t, []ResourceReadiness{
+		{Name: "(Tiltfile)", Status: ReadinessReady},
+		{Name: "disabled", Status: ReadinessSkipped, Reason: "Disabled"},
+		{Name: "manual", Status: ReadinessSkipped, Reason: "NotTriggered"},
+		{Name: "server", Status: ReadinessReady},
+	}, r.Resources)
+	assert.Empty(t, r.NotReady())
+}
+
+func TestAggregateReadinessPending(t *testing.T) {
+	waiting := readinessResource("waiting", v1alpha1.UpdateStatusPending, v1alpha1.RuntimeStatusPending)
+	waiting.Status.Waiting = &v1alpha1.UIResourceStateWaiting{Reason: "waiting-on-dependencies"}
+
+	r := AggregateReadiness([]v1alpha1.UIResource{
+		readinessResource("server", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusOK),
+		readinessResource("starting", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusPending),
+		waiting,
+	})
+	assert.Equal(t, ReadinessPending, r.Status)
+	assert.Equal(t, []ResourceReadiness{
+		{Name: "starting", Status: ReadinessPending, Reason: "RuntimePending"},
+		{Name: "waiting", Status: ReadinessPending, Reason: "UpdatePending", Message: "waiting: waiting-on-dependencies"},
+	}, r.NotReady())
+}
+
+func TestAggregateReadinessError(t *testing.T) {
+	broken := readinessResource("broken", v1alpha1.UpdateStatusError, v1alpha1.RuntimeStatusNotApplicable)
+	broken.Status.BuildHistory = []v1alpha1.UIBuildTerminated{{Error: "exit status 1"}}
+
+	crashing := readinessResource("crashing", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusError)
+	crashing.Status.K8sResourceInfo = &v1alpha1.UIResourceKubernetes{PodStatus: "CrashLoopBackOff"}
+
+	r := AggregateReadiness([]v1alpha1.UIResource{
+		broken,
+		crashing,
+		readinessResource("starting", v1alpha1.UpdateStatusOK, v1alpha1.RuntimeStatusPending),
+	})
+	assert.Equal(t, ReadinessError, r.Status)
+	assert.Equal(t, []ResourceReadiness{
+		{Name: "broken", Status: ReadinessError, Reason: "UpdateError", Message: "exit status 1"},
+		{Name: "crashing", Status: ReadinessError, Reason: "RuntimeError", Message: "CrashLoopBackOff"},
+		{Name: "starting", Status: ReadinessPending, Reason: "RuntimePending"},
+	}, r.NotReady())
+}
@@ -26,23 +26,40 @@ func CreateFileChangeFilter(ignores []v1alpha1.IgnoreDef) model.PathMatcher {
 func ToMatchersBestEffort(ignores []v1alpha1.IgnoreDef) []model.PathMatcher {
 	var ignoreMatchers []model.PathMatcher
 	for _, ignoreDef := range ignores {
-		if len(ignoreDef.Patterns) != 0 {
-			m, err := dockerignore.NewDockerPatternMatcher(
-				ignoreDef.BasePath,
-				append([]string{}, ignoreDef.Patterns...))
-			if err == nil {
-				ignoreMatchers = append(ignoreMatchers, m)
-			}
-		} else {
-			m, err := NewDirectoryMatcher(ignoreDef.BasePath)
-			if err == nil {
-				ignoreMatchers = append(ignoreMatchers, m)
-			}
+		m, err := toMatcher(ignoreDef)
+		if err == nil {
+			ignoreMatchers = append(ignoreMatchers, m)
 		}
 	}
 	return ignoreMatchers
 }
 
+func toMatcher(ignoreDef v1alpha1.IgnoreDef) (model.PathMatcher, error) {
+	if len(ignoreDef.Patterns) != 0 {
+		return dockerignore.NewDockerPatternMatcher(
+			ignoreDef.BasePath,
+			append([]string{}, ignoreDef.Patterns...))
+	}
+	return NewDirectoryMatcher(ignoreDef.BasePath)
+}
+
+// Find the first ignore that matches the given path, skipping ignores that are ill-formed.
+//
+// Useful for explaining why a file change didn't trigger an update.
+func FindMatchingIgnore(ignores []v1alpha1.IgnoreDef, path string) (v1alpha1.IgnoreDef, bool) {
+	for _, ignoreDef := range ignores {
+		m, err := toMatcher(ignoreDef)
+		if err != nil {
+			continue
+		}
+		matches, err := m.Matches(path)
+		if err == nil && matches {
+			return ignoreDef, true
+		}
+	}
+	return v1alpha1.IgnoreDef{}, false
+}
+
 type DirectoryMatcher struct {
 	dir string
 }
@@ -138,3 +138,25 @@ func TestIgnores(t *testing.T) {
 		})
 	}
 }
+
+func TestFindMatchingIgnore(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+
+	ignores := []v1alpha1.IgnoreDef{
+		{BasePath: f.JoinPath(".git"), Reason: "git metadata"},
+		{BasePath: f.Path(), Patterns: []string{"**/*.pb.go"}, Reason: "generated code"},
+	}
+
+	ig, ok := FindMatchingIgnore(ignores, f.JoinPath("api", "api.pb.go"))
+	if assert.True(t, ok) {
+		assert.Equal(t, "generated code", ig.Reason)
+	}
+
+	ig, ok = FindMatchingIgnore(ignores, f.JoinPath(".git", "index"))
+	if assert.True(t, ok) {
+		assert.Equal(t, "git metadata", ig.Reason)
+	}
+
+	_, ok = FindMatchingIgnore(ignores, f.JoinPath("api", "api.go"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	yamlEncoder "sigs.k8s.io/yaml"
+)
+
+var strictSerializer = kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, scheme.Scheme, scheme.Scheme,
+	kjson.SerializerOptions{Yaml: true, Strict: true})
+
+// Checks YAML against the schemas of the built-in Kubernetes kinds.
+//
+// ParseYAML is lenient (like kubectl with --validate=false), so a field with
+// a typo in it is silently dropped. This finds those fields, without a cluster.
+//
+// Kinds that client-go doesn't know about (like custom resources) are skipped.
+func ValidateYAML(yaml string) []error {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReader(strings.NewReader(yaml)))
+
+	var result []error
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return append(result, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var header struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+		err = yamlEncoder.Unmarshal(doc, &header)
+		if err != nil {
+			result = append(result, err)
+			continue
+		}
+
+		gvk := schema.FromAPIVersionAndKind(header.APIVersion, header.Kind)
+		if !scheme.Scheme.Recognizes(gvk) {
+			continue
+		}
+
+		_, _, err = strictSerializer.Decode(doc, nil, nil)
+		if err == nil {
+			continue
+		}
+
+		strictErr, ok := runtime.AsStrictDecodingError(err)
+		if !ok {
+			result = append(result, fmt.Errorf("%s %q: %v", header.Kind, header.Metadata.Name, err))
+			continue
+		}
+		for _, e := range strictErr.Errors() {
+			result = append(result, fmt.Errorf("%s %q: %v", header.Kind, header.Metadata.Name, e))
+		}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+func TestValidateYAMLValid(t *testing.T) {
+	assert.Empty(t, ValidateYAML(testyaml.SanchoYAML))
+	assert.Empty(t, ValidateYAML(testyaml.TracerYAML))
+}
+
+func TestValidateYAMLUnknownField(t *testing.T) {
+	yaml := `apiVersion: v1
+kind: Service
+metadata:
+  name: fe
+spec:
+  prots:
+  - port: 80
+---
+apiVersion: example.com/v1
+kind: Frobber
+metadata:
+  name: frob
+spec:
+  anything: goes
+`
+	errs := ValidateYAML(yaml)
+	require.Len(t, errs, 1)
+	assert.Equal(t, `Service "fe": unknown field "spec.prots"`, errs[0].Error())
+}
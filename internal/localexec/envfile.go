@@ -0,0 +1,101 @@
+package localexec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/dotenv"
+	"github.com/compose-spec/compose-go/template"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// ResolveEnv reads the cmd's EnvFiles into its Env and, if the cmd asks
+// for it, expands the variables in its Argv and Env.
+//
+// Variables are looked up in environ (usually the environment that Tilt
+// runs with), then in the env files, then in Env, with the last value
+// winning. The returned cmd has no EnvFiles, and doesn't ask for expansion,
+// so it's safe to resolve twice.
+func ResolveEnv(cmd model.Cmd, environ []string) (model.Cmd, error) {
+	if len(cmd.EnvFiles) == 0 && !cmd.ExpandEnv {
+		return cmd, nil
+	}
+
+	vars := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+		vars[k] = v
+	}
+
+	// Unset variables expand to the empty string, like in a shell. (If the
+	// lookup reported them as unset, every expansion would log a warning.)
+	lookup := func(k string) (string, bool) {
+		return vars[k], true
+	}
+
+	var env []string
+	for _, f := range cmd.EnvFiles {
+		path := f
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cmd.Dir, path)
+		}
+		fileVars, err := readEnvFile(path, lookup)
+		if err != nil {
+			return model.Cmd{}, fmt.Errorf("reading env file %s: %v", f, err)
+		}
+
+		keys := make([]string, 0, len(fileVars))
+		for k := range fileVars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			env = append(env, k+"="+fileVars[k])
+			vars[k] = fileVars[k]
+		}
+	}
+
+	for _, kv := range cmd.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		if cmd.ExpandEnv {
+			var err error
+			v, err = template.Substitute(v, lookup)
+			if err != nil {
+				return model.Cmd{}, fmt.Errorf("expanding env %s: %v", k, err)
+			}
+		}
+		env = append(env, k+"="+v)
+		vars[k] = v
+	}
+
+	argv := cmd.Argv
+	if cmd.ExpandEnv {
+		argv = make([]string, len(cmd.Argv))
+		for i, arg := range cmd.Argv {
+			expanded, err := template.Substitute(arg, lookup)
+			if err != nil {
+				return model.Cmd{}, fmt.Errorf("expanding args: %v", err)
+			}
+			argv[i] = expanded
+		}
+	}
+
+	cmd.Argv = argv
+	cmd.Env = env
+	cmd.EnvFiles = nil
+	cmd.ExpandEnv = false
+	return cmd, nil
+}
+
+func readEnvFile(path string, lookup dotenv.LookupFn) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return dotenv.ParseWithLookup(f, lookup)
+}
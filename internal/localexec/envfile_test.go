@@ -0,0 +1,84 @@
+package localexec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestResolveEnvFiles(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile(".env", `
+# comments are ignored
+DB_HOST=localhost
+DB_URL="postgres://${DB_USER}@${DB_HOST}/app"
+PORT=3000
+`)
+	f.WriteFile(".env.local", "PORT=4000\n")
+
+	cmd := model.Cmd{
+		Argv:     []string{"./server"},
+		Dir:      f.Path(),
+		Env:      []string{"DEBUG=1", "PORT=5000"},
+		EnvFiles: []string{".env", f.JoinPath(".env.local")},
+	}
+	resolved, err := ResolveEnv(cmd, []string{"DB_USER=alice"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"./server"}, resolved.Argv)
+	assert.Equal(t, []string{
+		"DB_HOST=localhost",
+		"DB_URL=postgres://alice@localhost/app",
+		"PORT=3000",
+		"PORT=4000",
+		"DEBUG=1",
+		"PORT=5000",
+	}, resolved.Env)
+	assert.Nil(t, resolved.EnvFiles)
+}
+
+func TestResolveEnvMissingFile(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+
+	cmd := model.Cmd{Argv: []string{"./server"}, Dir: f.Path(), EnvFiles: []string{".env"}}
+	_, err := ResolveEnv(cmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading env file .env")
+}
+
+func TestResolveEnvExpand(t *testing.T) {
+	cmd := model.Cmd{
+		Argv:      []string{"./server", "--port=${PORT}", "--name=$NAME", "--price=$$5", "--missing=${MISSING:-none}"},
+		Env:       []string{"NAME=web-$USER"},
+		ExpandEnv: true,
+	}
+	resolved, err := ResolveEnv(cmd, []string{"PORT=8080", "USER=alice"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"./server", "--port=8080", "--name=web-alice", "--price=$5", "--missing=none"}, resolved.Argv)
+	assert.Equal(t, []string{"NAME=web-alice"}, resolved.Env)
+	assert.False(t, resolved.ExpandEnv)
+}
+
+func TestResolveEnvNoExpand(t *testing.T) {
+	cmd := model.Cmd{Argv: []string{"sh", "-c", "echo $PORT"}, Env: []string{"A=$B"}}
+	resolved, err := ResolveEnv(cmd, []string{"PORT=8080", "B=c"})
+	require.NoError(t, err)
+	assert.Equal(t, cmd, resolved)
+}
+
+func TestExecCmdExpandsTiltEnv(t *testing.T) {
+	env := DefaultEnv(8000, "tilt.local")
+	env.environ = func() []string { return nil }
+	l := logger.NewTestLogger(bytes.NewBuffer(nil))
+
+	cmd, err := env.ExecCmd(model.Cmd{Argv: []string{"curl", "http://${TILT_HOST}:${TILT_PORT}/api/view"}, ExpandEnv: true}, l)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"curl", "http://tilt.local:8000/api/view"}, cmd.Args)
+}
@@ -53,6 +53,8 @@ func (e *Env) Add(k, v string) {
 // have command specific environment overrides applied, and finally, additional conditional
 // environment to improve logging output.
 //
+// The command's env files are read, and its variables expanded, with ResolveEnv.
+//
 // NOTE: To avoid confusion with ExecCmdContext, this method accepts a logger instance
 // directly rather than using logger.Get(ctx); the returned exec.Cmd from this function
 // will NOT be associated with any context.
@@ -60,13 +62,19 @@ func (e *Env) ExecCmd(cmd model.Cmd, l logger.Logger) (*exec.Cmd, error) {
 	if len(cmd.Argv) == 0 {
 		return nil, errors.New("empty cmd")
 	}
+	execEnv := e.execEnv(l)
+	cmd, err := ResolveEnv(cmd, execEnv)
+	if err != nil {
+		return nil, err
+	}
 	c := exec.Command(cmd.Argv[0], cmd.Argv[1:]...)
-	e.populateExecCmd(c, cmd, l)
+	c.Dir = cmd.Dir
+	c.Env = append(execEnv, cmd.Env...)
 	return c, nil
 }
 
-func (e *Env) populateExecCmd(c *exec.Cmd, cmd model.Cmd, l logger.Logger) {
-	c.Dir = cmd.Dir
+// The environment of every command, before the command's own env.
+func (e *Env) execEnv(l logger.Logger) []string {
 	// env precedence: parent process (i.e. tilt) -> logger -> command
 	// dupes are left for Go stdlib to handle (API guarantees last wins)
 	execEnv := e.environ()
@@ -75,9 +83,7 @@ func (e *Env) populateExecCmd(c *exec.Cmd, cmd model.Cmd, l logger.Logger) {
 	for _, kv := range e.pairs {
 		execEnv = addEnvIfNotPresent(execEnv, kv.Key, kv.Value)
 	}
-
-	execEnv = append(execEnv, cmd.Env...)
-	c.Env = execEnv
+	return execEnv
 }
 
 type kvPair struct {
@@ -2,10 +2,10 @@ package localexec
 
 import (
 	"bytes"
-	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -15,9 +15,9 @@ func TestDefaultEnv(t *testing.T) {
 	env := DefaultEnv(8000, "tilt.local")
 	env.environ = func() []string { return nil }
 	l := logger.NewTestLogger(bytes.NewBuffer(nil))
-	cmd := &exec.Cmd{}
 	cmdModel := model.Cmd{Argv: []string{"x"}, Env: []string{"x=y"}}
-	env.populateExecCmd(cmd, cmdModel, l)
+	cmd, err := env.ExecCmd(cmdModel, l)
+	require.NoError(t, err)
 	assert.Equal(t, cmd.Env, []string{
 		"LINES=24",
 		"COLUMNS=80",
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// The controller-runtime metrics we re-export.
+//
+// controller-runtime and the apiserver both register some client-go metrics
+// under the same names, so we pick out the reconciler metrics rather than
+// serving everything.
+var controllerRuntimePrefixes = []string{
+	"controller_runtime_",
+	"workqueue_",
+}
+
+// Re-exports the reconciler metrics in controller-runtime's registry
+// (reconcile counts, durations, and queue depths), so that they're served
+// with the rest of Tilt's metrics.
+//
+// It's an unchecked collector: it doesn't know which metrics it will
+// collect until it gathers them.
+type controllerRuntimeCollector struct {
+	gatherer prometheus.Gatherer
+}
+
+var _ prometheus.Collector = controllerRuntimeCollector{}
+
+func newControllerRuntimeCollector(gatherer prometheus.Gatherer) controllerRuntimeCollector {
+	return controllerRuntimeCollector{gatherer: gatherer}
+}
+
+func (c controllerRuntimeCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c controllerRuntimeCollector) Collect(ch chan<- prometheus.Metric) {
+	// Gather returns whatever it could gather, even on error.
+	families, _ := c.gatherer.Gather()
+	for _, family := range families {
+		if !hasControllerRuntimePrefix(family.GetName()) {
+			continue
+		}
+		for _, m := range family.Metric {
+			labelNames := make([]string, 0, len(m.Label))
+			for _, l := range m.Label {
+				labelNames = append(labelNames, l.GetName())
+			}
+			desc := prometheus.NewDesc(family.GetName(), family.GetHelp(), labelNames, nil)
+			ch <- gatheredMetric{desc: desc, metric: m}
+		}
+	}
+}
+
+func hasControllerRuntimePrefix(name string) bool {
+	for _, p := range controllerRuntimePrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// A metric that was already gathered from another registry.
+type gatheredMetric struct {
+	desc   *prometheus.Desc
+	metric *dto.Metric
+}
+
+func (m gatheredMetric) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+func (m gatheredMetric) Write(out *dto.Metric) error {
+	out.Label = m.metric.Label
+	out.Counter = m.metric.Counter
+	out.Gauge = m.metric.Gauge
+	out.Summary = m.metric.Summary
+	out.Untyped = m.metric.Untyped
+	out.Histogram = m.metric.Histogram
+	out.TimestampMs = m.metric.TimestampMs
+	return nil
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControllerRuntimeCollector(t *testing.T) {
+	source := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_depth",
+		Help: "Current depth of workqueue",
+	}, []string{"name"})
+	reconciles := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_total",
+		Help: "Total number of reconciliations per controller",
+	}, []string{"controller", "result"})
+	requests := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "rest_client_requests_total",
+		Help: "Number of HTTP requests",
+	})
+	source.MustRegister(depth, reconciles, requests)
+
+	depth.WithLabelValues("filewatch").Set(3)
+	reconciles.WithLabelValues("filewatch", "success").Add(7)
+	requests.Inc()
+
+	dest := prometheus.NewRegistry()
+	dest.MustRegister(newControllerRuntimeCollector(source))
+
+	// Only the reconciler metrics come through.
+	families, err := dest.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 2)
+	assert.Equal(t, "controller_runtime_reconcile_total", families[0].GetName())
+	assert.Equal(t, 7.0, families[0].Metric[0].GetCounter().GetValue())
+	assert.Equal(t, "workqueue_depth", families[1].GetName())
+	assert.Equal(t, 3.0, families[1].Metric[0].GetGauge().GetValue())
+	assert.Equal(t, "filewatch", families[1].Metric[0].Label[0].GetValue())
+}
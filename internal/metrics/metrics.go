@@ -0,0 +1,182 @@
+// Package metrics defines the Prometheus metrics that Tilt exposes
+// about the dev loop.
+//
+// They're registered with the Kubernetes component-base registry, so the
+// Tilt apiserver serves them at /metrics alongside its own metrics,
+// behind the same authentication as the rest of the API.
+package metrics
+
+import (
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "tilt"
+
+// Labels
+const (
+	LabelResource   = "resource"
+	LabelResult     = "result"
+	LabelFileWatch  = "filewatch"
+	LabelAlertRule  = "alert_rule"
+	LabelCmdKind    = "cmd_kind"
+	LabelExitReason = "exit_reason"
+)
+
+// Values of LabelResult
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Builds take anywhere from a fraction of a second (a live update)
+// to tens of minutes (a big image build).
+var buildDurationBuckets = k8smetrics.ExponentialBuckets(0.1, 2, 15)
+
+// Commands run for anywhere from a second (a failing update command) to
+// days (a serve_cmd left running).
+var cmdRuntimeBuckets = k8smetrics.ExponentialBuckets(1, 4, 10)
+
+var (
+	BuildsTotal = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace:      namespace,
+			Name:           "builds_total",
+			Help:           "Number of finished builds, by resource and result.",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelResult},
+	)
+
+	BuildDuration = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Namespace:      namespace,
+			Name:           "build_duration_seconds",
+			Help:           "How long builds took, by resource and result.",
+			Buckets:        buildDurationBuckets,
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelResult},
+	)
+
+	LiveUpdateDuration = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Namespace:      namespace,
+			Name:           "live_update_duration_seconds",
+			Help:           "How long builds that live-updated a running container took, by resource and result.",
+			Buckets:        buildDurationBuckets,
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelResult},
+	)
+
+	ResourceReady = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace:      namespace,
+			Name:           "resource_ready",
+			Help:           "Whether the resource is running and ready (1) or not (0).",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource},
+	)
+
+	TimeToReady = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Namespace:      namespace,
+			Name:           "time_to_ready_seconds",
+			Help:           "How long resources took to become ready after a build started, by resource.",
+			Buckets:        buildDurationBuckets,
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource},
+	)
+
+	AlertFiring = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace:      namespace,
+			Name:           "alert_firing",
+			Help:           "Whether the AlertRule is firing for the resource (1). Only firing alerts have a series.",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelAlertRule, LabelResource},
+	)
+
+	CmdStartTime = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace:      namespace,
+			Name:           "cmd_start_time_seconds",
+			Help:           "Unix time when the resource's command last started, by resource and kind of command.",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelCmdKind},
+	)
+
+	CmdTimeToReady = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Namespace:      namespace,
+			Name:           "cmd_time_to_ready_seconds",
+			Help:           "How long commands took to become ready after they started, by resource and kind of command.",
+			Buckets:        buildDurationBuckets,
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelCmdKind},
+	)
+
+	CmdRuntime = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Namespace:      namespace,
+			Name:           "cmd_runtime_seconds",
+			Help:           "How long commands ran before they exited, by resource, kind of command, and why they exited.",
+			Buckets:        cmdRuntimeBuckets,
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelCmdKind, LabelExitReason},
+	)
+
+	CmdRestartsTotal = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace:      namespace,
+			Name:           "cmd_restarts_total",
+			Help:           "Number of times commands were restarted by their restart policy, by resource and kind of command.",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelResource, LabelCmdKind},
+	)
+
+	FileWatchEventsTotal = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace:      namespace,
+			Name:           "file_watch_events_total",
+			Help:           "Number of changed files seen, by FileWatch.",
+			StabilityLevel: k8smetrics.ALPHA,
+		},
+		[]string{LabelFileWatch},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(
+		BuildsTotal,
+		BuildDuration,
+		LiveUpdateDuration,
+		ResourceReady,
+		TimeToReady,
+		AlertFiring,
+		CmdStartTime,
+		CmdTimeToReady,
+		CmdRuntime,
+		CmdRestartsTotal,
+		FileWatchEventsTotal,
+	)
+
+	// The reconcilers' own metrics live in controller-runtime's registry.
+	// Serve them too: they have each controller's queue depth
+	// (workqueue_depth), how long objects wait in its queue
+	// (workqueue_queue_duration_seconds), and how many of its workers
+	// are busy (controller_runtime_active_workers), for tuning
+	// --controller-workers.
+	//
+	//nolint:staticcheck // SA1019 - there's no replacement for raw collectors.
+	legacyregistry.RawMustRegister(newControllerRuntimeCollector(ctrlmetrics.Registry))
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Keeps the build and readiness metrics up to date with the engine state.
+type Subscriber struct {
+	// The start time of the last build observed for each resource.
+	lastObserved map[string]time.Time
+
+	// Resources that have a readiness gauge, and whether they were ready.
+	ready map[string]bool
+
+	// The start time of the build that each resource last became ready
+	// after, so that a pod recovering from a crash isn't counted.
+	readyAfter map[string]time.Time
+
+	now func() time.Time
+}
+
+var _ store.Subscriber = &Subscriber{}
+
+func NewSubscriber() *Subscriber {
+	return &Subscriber{
+		lastObserved: make(map[string]time.Time),
+		ready:        make(map[string]bool),
+		readyAfter:   make(map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+type resourceState struct {
+	name string
+
+	// Completed builds, most recent first.
+	builds []model.BuildRecord
+
+	// Whether the resource has a runtime, and it's ready. Nil for
+	// resources that don't have a runtime, like the Tiltfile.
+	ready *bool
+
+	// When the current build started, or the last one, if none is running.
+	buildStart time.Time
+}
+
+func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	state := st.RLockState()
+	var resources []resourceState
+	for _, ms := range state.GetTiltfileStates() {
+		resources = append(resources, resourceState{name: ms.Name.String(), builds: ms.BuildHistory})
+	}
+	for _, mt := range state.Targets() {
+		ready := isReady(mt)
+		buildStart := mt.State.LastBuild().StartTime
+		if mt.State.IsBuilding() {
+			buildStart = mt.State.EarliestCurrentBuild().StartTime
+		}
+		resources = append(resources, resourceState{
+			name:       mt.Manifest.Name.String(),
+			builds:     mt.State.BuildHistory,
+			ready:      &ready,
+			buildStart: buildStart,
+		})
+	}
+	st.RUnlockState()
+
+	current := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		s.observeBuilds(r)
+		if r.ready != nil {
+			current[r.name] = true
+			s.observeReady(r)
+			s.ready[r.name] = *r.ready
+			value := 0.0
+			if *r.ready {
+				value = 1
+			}
+			ResourceReady.WithLabelValues(r.name).Set(value)
+		}
+	}
+
+	for name := range s.ready {
+		if !current[name] {
+			ResourceReady.Delete(map[string]string{LabelResource: name})
+			delete(s.ready, name)
+			delete(s.readyAfter, name)
+		}
+	}
+	return nil
+}
+
+// Resources without a runtime (like a local_resource without a serve_cmd)
+// are ready once they've updated successfully.
+func isReady(mt *store.ManifestTarget) bool {
+	triggerMode := mt.Manifest.TriggerMode
+	switch mt.State.RuntimeStatus(triggerMode) {
+	case v1alpha1.RuntimeStatusOK:
+		return true
+	case v1alpha1.RuntimeStatusNotApplicable:
+		return mt.State.UpdateStatus(triggerMode) == v1alpha1.UpdateStatusOK
+	}
+	return false
+}
+
+// Records how long a resource took to become ready, the first time it's
+// ready after a build starts.
+func (s *Subscriber) observeReady(r resourceState) {
+	if !*r.ready || s.ready[r.name] || r.buildStart.IsZero() {
+		return
+	}
+	if !r.buildStart.After(s.readyAfter[r.name]) {
+		return
+	}
+	s.readyAfter[r.name] = r.buildStart
+	TimeToReady.WithLabelValues(r.name).Observe(s.now().Sub(r.buildStart).Seconds())
+}
+
+// Counts any builds that finished since the last call.
+func (s *Subscriber) observeBuilds(r resourceState) {
+	last := s.lastObserved[r.name]
+	for i := len(r.builds) - 1; i >= 0; i-- {
+		b := r.builds[i]
+		if b.FinishTime.IsZero() || !b.StartTime.After(last) {
+			continue
+		}
+		s.lastObserved[r.name] = b.StartTime
+
+		result := ResultSuccess
+		if b.Error != nil {
+			result = ResultError
+		}
+		duration := b.Duration().Seconds()
+		BuildsTotal.WithLabelValues(r.name, result).Inc()
+		BuildDuration.WithLabelValues(r.name, result).Observe(duration)
+		if b.HasBuildType(model.BuildTypeLiveUpdate) {
+			LiveUpdateDuration.WithLabelValues(r.name, result).Observe(duration)
+		}
+	}
+}
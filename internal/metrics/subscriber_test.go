@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The metrics are global, so each test uses its own resource names.
+
+func TestCountsBuilds(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("counts-fe")
+
+	start := time.Unix(1000, 0)
+	f.addBuild("counts-fe", start, 2*time.Second, nil, model.BuildTypeImage, model.BuildTypeK8s)
+	f.onChange()
+
+	assert.Equal(t, 1.0, f.buildCount("counts-fe", ResultSuccess))
+	assert.Equal(t, 0.0, f.buildCount("counts-fe", ResultError))
+	assert.Equal(t, 2.0, f.durationSum(BuildDuration, "counts-fe", ResultSuccess))
+
+	// Make sure OnChange is idempotent.
+	f.onChange()
+	assert.Equal(t, 1.0, f.buildCount("counts-fe", ResultSuccess))
+
+	f.addBuild("counts-fe", start.Add(time.Minute), time.Second, fmt.Errorf("oops"), model.BuildTypeImage)
+	f.onChange()
+	assert.Equal(t, 1.0, f.buildCount("counts-fe", ResultSuccess))
+	assert.Equal(t, 1.0, f.buildCount("counts-fe", ResultError))
+}
+
+func TestLiveUpdateDuration(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("lu-fe")
+
+	f.addBuild("lu-fe", time.Unix(1000, 0), 5*time.Second, nil, model.BuildTypeImage)
+	f.addBuild("lu-fe", time.Unix(2000, 0), 500*time.Millisecond, nil, model.BuildTypeLiveUpdate)
+	f.onChange()
+
+	assert.Equal(t, 2.0, f.buildCount("lu-fe", ResultSuccess))
+	assert.Equal(t, uint64(1), f.durationCount(LiveUpdateDuration, "lu-fe", ResultSuccess))
+	assert.Equal(t, 0.5, f.durationSum(LiveUpdateDuration, "lu-fe", ResultSuccess))
+}
+
+func TestResourceReady(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("ready-server")
+	f.setRuntimeStatus("ready-server", v1alpha1.RuntimeStatusPending)
+	f.addManifest("ready-job")
+	f.onChange()
+
+	assert.Equal(t, 0.0, f.ready("ready-server"))
+	assert.Equal(t, 0.0, f.ready("ready-job"))
+
+	f.setRuntimeStatus("ready-server", v1alpha1.RuntimeStatusOK)
+	f.setRuntimeStatus("ready-job", v1alpha1.RuntimeStatusNotApplicable)
+	f.addBuild("ready-job", time.Unix(1000, 0), time.Second, nil, model.BuildTypeLocal)
+	f.onChange()
+
+	assert.Equal(t, 1.0, f.ready("ready-server"))
+	assert.Equal(t, 1.0, f.ready("ready-job"))
+}
+
+func TestTimeToReady(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("ttr-fe")
+	start := time.Unix(1000, 0)
+	f.addBuild("ttr-fe", start, 2*time.Second, nil, model.BuildTypeImage)
+	f.setRuntimeStatus("ttr-fe", v1alpha1.RuntimeStatusPending)
+	f.onChange()
+	assert.Equal(t, uint64(0), f.timeToReadyCount("ttr-fe"))
+
+	f.sub.now = func() time.Time { return start.Add(5 * time.Second) }
+	f.setRuntimeStatus("ttr-fe", v1alpha1.RuntimeStatusOK)
+	f.onChange()
+	assert.Equal(t, uint64(1), f.timeToReadyCount("ttr-fe"))
+	assert.Equal(t, 5.0, f.timeToReadySum("ttr-fe"))
+
+	// A pod recovering from a crash isn't counted, because there was no build.
+	f.setRuntimeStatus("ttr-fe", v1alpha1.RuntimeStatusError)
+	f.onChange()
+	f.setRuntimeStatus("ttr-fe", v1alpha1.RuntimeStatusOK)
+	f.onChange()
+	assert.Equal(t, uint64(1), f.timeToReadyCount("ttr-fe"))
+}
+
+func TestRemovedResourcesLoseReadiness(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("removed-fe")
+	f.setRuntimeStatus("removed-fe", v1alpha1.RuntimeStatusOK)
+	f.onChange()
+	assert.True(t, f.hasReadySeries("removed-fe"))
+
+	f.store.WithState(func(es *store.EngineState) {
+		es.RemoveManifestTarget("removed-fe")
+	})
+	f.onChange()
+	assert.False(t, f.hasReadySeries("removed-fe"))
+}
+
+type fixture struct {
+	t     *testing.T
+	ctx   context.Context
+	store *store.TestingStore
+	sub   *Subscriber
+}
+
+func newFixture(t *testing.T) *fixture {
+	return &fixture{
+		t:     t,
+		ctx:   context.Background(),
+		store: store.NewTestingStore(),
+		sub:   NewSubscriber(),
+	}
+}
+
+func (f *fixture) onChange() {
+	err := f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	require.NoError(f.t, err)
+}
+
+func (f *fixture) addManifest(name model.ManifestName) {
+	f.store.WithState(func(es *store.EngineState) {
+		m := model.Manifest{Name: name, TriggerMode: model.TriggerModeAuto}
+		es.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+}
+
+func (f *fixture) setRuntimeStatus(name model.ManifestName, status v1alpha1.RuntimeStatus) {
+	f.store.WithState(func(es *store.EngineState) {
+		es.ManifestTargets[name].State.RuntimeState = store.LocalRuntimeState{Status: status}
+	})
+}
+
+func (f *fixture) addBuild(name model.ManifestName, start time.Time, duration time.Duration, err error, buildTypes ...model.BuildType) {
+	f.store.WithState(func(es *store.EngineState) {
+		es.ManifestTargets[name].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  start,
+			FinishTime: start.Add(duration),
+			Error:      err,
+			BuildTypes: buildTypes,
+		})
+	})
+}
+
+func (f *fixture) buildCount(name, result string) float64 {
+	v, err := testutil.GetCounterMetricValue(BuildsTotal.WithLabelValues(name, result))
+	require.NoError(f.t, err)
+	return v
+}
+
+func (f *fixture) durationSum(h *k8smetrics.HistogramVec, name, result string) float64 {
+	v, err := testutil.GetHistogramMetricValue(h.WithLabelValues(name, result))
+	require.NoError(f.t, err)
+	return v
+}
+
+func (f *fixture) durationCount(h *k8smetrics.HistogramVec, name, result string) uint64 {
+	v, err := testutil.GetHistogramMetricCount(h.WithLabelValues(name, result))
+	require.NoError(f.t, err)
+	return v
+}
+
+func (f *fixture) ready(name string) float64 {
+	v, err := testutil.GetGaugeMetricValue(ResourceReady.WithLabelValues(name))
+	require.NoError(f.t, err)
+	return v
+}
+
+func (f *fixture) timeToReadyCount(name string) uint64 {
+	v, err := testutil.GetHistogramMetricCount(TimeToReady.WithLabelValues(name))
+	require.NoError(f.t, err)
+	return v
+}
+
+func (f *fixture) timeToReadySum(name string) float64 {
+	v, err := testutil.GetHistogramMetricValue(TimeToReady.WithLabelValues(name))
+	require.NoError(f.t, err)
+	return v
+}
+
+func (f *fixture) hasReadySeries(name string) bool {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	require.NoError(f.t, err)
+	for _, family := range families {
+		if family.GetName() != "tilt_resource_ready" {
+			continue
+		}
+		for _, m := range family.Metric {
+			if testutil.LabelsMatch(m, map[string]string{LabelResource: name}) {
+				return true
+			}
+		}
+	}
+	return false
+}
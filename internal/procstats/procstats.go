@@ -0,0 +1,64 @@
+// Package procstats measures how much CPU and memory a command's processes
+// use, so that Tilt can show which commands are slowing down the machine.
+package procstats
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnsupported means this OS can't measure processes.
+var ErrUnsupported = errors.New("measuring processes is not supported on this OS")
+
+// Usage is the resources used by a group of processes.
+type Usage struct {
+	// CPU time, user and system, since the processes started.
+	CPUTime time.Duration
+
+	// Resident memory, in bytes.
+	RSS uint64
+}
+
+func (u Usage) add(other Usage) Usage {
+	return Usage{
+		CPUTime: u.CPUTime + other.CPUTime,
+		RSS:     u.RSS + other.RSS,
+	}
+}
+
+// Sampler turns samples of a group's Usage into its current CPU use.
+type Sampler struct {
+	pid      int
+	last     Usage
+	lastTime time.Time
+}
+
+func NewSampler(pid int) *Sampler {
+	return &Sampler{pid: pid}
+}
+
+// Sample measures the group, and returns its CPU use since the last sample,
+// in thousandths of a core, with its current memory.
+//
+// The first sample only sets the baseline, so returns ok=false.
+func (s *Sampler) Sample(now time.Time) (millicores int64, rss uint64, ok bool, err error) {
+	usage, err := GroupUsage(s.pid)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	last, lastTime := s.last, s.lastTime
+	s.last, s.lastTime = usage, now
+	if lastTime.IsZero() {
+		return 0, 0, false, nil
+	}
+
+	elapsed := now.Sub(lastTime)
+	cpu := usage.CPUTime - last.CPUTime
+	if elapsed > 0 && cpu > 0 {
+		// Processes that exit take their CPU time with them, so cpu can be
+		// negative; report that as idle.
+		millicores = int64(cpu) * 1000 / int64(elapsed)
+	}
+	return millicores, usage.RSS, true, nil
+}
@@ -0,0 +1,91 @@
+package procstats
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GroupUsage measures the process group that pid leads.
+//
+// macOS only reports the usage of other processes through libproc, which
+// needs cgo, so ask ps instead.
+func GroupUsage(pid int) (Usage, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pgid=,rss=,time=").Output()
+	if err != nil {
+		return Usage{}, fmt.Errorf("running ps: %v", err)
+	}
+	return parsePS(out, pid)
+}
+
+// Sums the usage of the processes in the group from ps output, with the
+// columns pgid, rss (in KiB), and time.
+func parsePS(out []byte, pgid int) (Usage, error) {
+	var total Usage
+	found := false
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		p, err := strconv.Atoi(fields[0])
+		if err != nil || p != pgid {
+			continue
+		}
+
+		rss, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return Usage{}, fmt.Errorf("parsing ps rss %q: %v", fields[1], err)
+		}
+		cpuTime, err := parseCPUTime(fields[2])
+		if err != nil {
+			return Usage{}, err
+		}
+		found = true
+		total = total.add(Usage{CPUTime: cpuTime, RSS: rss * 1024})
+	}
+	if !found {
+		return Usage{}, fmt.Errorf("no processes in group %d", pgid)
+	}
+	return total, nil
+}
+
+// Parses a CPU time from ps, like "1:02.50", "1:02:03", or "2-01:02:03".
+func parseCPUTime(s string) (time.Duration, error) {
+	var d time.Duration
+	rest := s
+	if i := strings.Index(rest, "-"); i != -1 {
+		days, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("parsing ps time %q: %v", s, err)
+		}
+		d += time.Duration(days) * 24 * time.Hour
+		rest = rest[i+1:]
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("parsing ps time %q: unexpected format", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing ps time %q: %v", s, err)
+	}
+	d += time.Duration(seconds * float64(time.Second))
+
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("parsing ps time %q: %v", s, err)
+		}
+		d += time.Duration(n) * unit
+		unit = time.Hour
+	}
+	return d, nil
+}
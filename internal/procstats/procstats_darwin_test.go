@@ -0,0 +1,40 @@
+package procstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePS(t *testing.T) {
+	out := []byte(`    1  12000   1:02.50
+  500   2048   0:01.25
+  500   1024   0:00.75
+  501   4096   0:09.00
+`)
+	usage, err := parsePS(out, 500)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{CPUTime: 2 * time.Second, RSS: 3 * 1024 * 1024}, usage)
+
+	_, err = parsePS(out, 502)
+	assert.Error(t, err)
+}
+
+func TestParseCPUTime(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"0:01.25":    1250 * time.Millisecond,
+		"61:02.50":   61*time.Minute + 2500*time.Millisecond,
+		"1:02:03":    time.Hour + 2*time.Minute + 3*time.Second,
+		"2-01:02:03": 49*time.Hour + 2*time.Minute + 3*time.Second,
+	} {
+		d, err := parseCPUTime(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, expected, d, s)
+		}
+	}
+
+	_, err := parseCPUTime("soon")
+	assert.Error(t, err)
+}
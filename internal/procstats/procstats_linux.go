@@ -0,0 +1,105 @@
+package procstats
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// The unit of the CPU times in /proc/<pid>/stat. Linux always reports them
+// in USER_HZ, which is 100 on every architecture.
+const userHZ = 100
+
+// GroupUsage measures the process group that pid leads.
+func GroupUsage(pid int) (Usage, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return Usage{}, err
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	var total Usage
+	found := false
+	for _, e := range entries {
+		p, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join("/proc", e.Name(), "stat"))
+		if err != nil {
+			// The process exited.
+			continue
+		}
+		pgrp, usage, err := parseStat(contents, pageSize)
+		if err != nil {
+			return Usage{}, fmt.Errorf("reading stats of process %d: %v", p, err)
+		}
+		if pgrp != pid {
+			continue
+		}
+		found = true
+		total = total.add(usage)
+	}
+	if !found {
+		return Usage{}, fmt.Errorf("no processes in group %d", pid)
+	}
+	return total, nil
+}
+
+// Parses the process group and usage from the contents of /proc/<pid>/stat.
+//
+// See proc(5) for the format.
+func parseStat(contents []byte, pageSize uint64) (int, Usage, error) {
+	// The command name is in parentheses, and may contain spaces or
+	// parentheses itself, so start after the last one.
+	i := bytes.LastIndexByte(contents, ')')
+	if i == -1 {
+		return 0, Usage{}, fmt.Errorf("malformed stat: %q", contents)
+	}
+	fields := bytes.Fields(contents[i+1:])
+
+	// Fields are numbered from 1 in proc(5), and these start at field 3.
+	const (
+		pgrpField   = 5 - 3
+		utimeField  = 14 - 3
+		stimeField  = 15 - 3
+		cutimeField = 16 - 3
+		cstimeField = 17 - 3
+		rssField    = 24 - 3
+	)
+	if len(fields) <= rssField {
+		return 0, Usage{}, fmt.Errorf("malformed stat: %q", contents)
+	}
+
+	pgrp, err := strconv.Atoi(string(fields[pgrpField]))
+	if err != nil {
+		return 0, Usage{}, err
+	}
+
+	// Include the time of children that the process has waited for, so that
+	// the group's CPU time doesn't drop when one of them exits.
+	var ticks uint64
+	for _, f := range []int{utimeField, stimeField, cutimeField, cstimeField} {
+		t, err := strconv.ParseUint(string(fields[f]), 10, 64)
+		if err != nil {
+			return 0, Usage{}, err
+		}
+		ticks += t
+	}
+
+	rss, err := strconv.ParseInt(string(fields[rssField]), 10, 64)
+	if err != nil {
+		return 0, Usage{}, err
+	}
+	if rss < 0 {
+		rss = 0
+	}
+
+	return pgrp, Usage{
+		CPUTime: time.Duration(ticks) * time.Second / userHZ,
+		RSS:     uint64(rss) * pageSize,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package procstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStat(t *testing.T) {
+	// The command name has spaces and parentheses.
+	stat := []byte("1234 (my (cool) server) S 1 1200 1200 0 -1 4194560 100 0 0 0 250 50 10 5 20 0 1 0 100 12345678 300 18446744073709551615\n")
+	pgrp, usage, err := parseStat(stat, 4096)
+	require.NoError(t, err)
+	assert.Equal(t, 1200, pgrp)
+	assert.Equal(t, Usage{CPUTime: 3150 * time.Millisecond, RSS: 300 * 4096}, usage)
+
+	_, _, err = parseStat([]byte("1234 (truncated) S 1"), 4096)
+	assert.Error(t, err)
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+// +build !windows,!linux,!darwin
+
+package procstats
+
+func GroupUsage(pid int) (Usage, error) {
+	return Usage{}, ErrUnsupported
+}
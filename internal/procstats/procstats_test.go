@@ -0,0 +1,45 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package procstats
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupUsage(t *testing.T) {
+	// A busy parent with a busy child in the same group.
+	c := exec.Command("sh", "-c", "(while :; do :; done) & while :; do :; done")
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		_ = syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+		_ = c.Wait()
+	})
+
+	s := NewSampler(c.Process.Pid)
+	_, _, ok, err := s.Sample(time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok, "the first sample is the baseline")
+
+	require.Eventually(t, func() bool {
+		millicores, rss, ok, err := s.Sample(time.Now())
+		require.NoError(t, err)
+		require.True(t, ok)
+		return millicores > 0 && rss > 0
+	}, 5*time.Second, 200*time.Millisecond)
+}
+
+func TestGroupUsageNoProcesses(t *testing.T) {
+	c := exec.Command("true")
+	require.NoError(t, c.Run())
+
+	_, err := GroupUsage(c.Process.Pid)
+	assert.Error(t, err)
+}
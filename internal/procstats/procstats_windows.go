@@ -0,0 +1,112 @@
+package procstats
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetProcessMemoryInfo = windows.NewLazySystemDLL("psapi.dll").NewProc("GetProcessMemoryInfo")
+
+// PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// GroupUsage measures pid and its descendants, because Windows doesn't put
+// them in a process group.
+func GroupUsage(pid int) (Usage, error) {
+	children, err := childProcesses()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	total, err := processUsage(uint32(pid))
+	if err != nil {
+		return Usage{}, err
+	}
+
+	queue := children[uint32(pid)]
+	seen := map[uint32]bool{uint32(pid): true}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		usage, err := processUsage(p)
+		if err != nil {
+			// The process exited.
+			continue
+		}
+		total = total.add(usage)
+		queue = append(queue, children[p]...)
+	}
+	return total, nil
+}
+
+// Returns the children of each process.
+func childProcesses() (map[uint32][]uint32, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = windows.CloseHandle(snapshot) }()
+
+	children := make(map[uint32][]uint32)
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	err = windows.Process32First(snapshot, &entry)
+	for err == nil {
+		children[entry.ParentProcessID] = append(children[entry.ParentProcessID], entry.ProcessID)
+		err = windows.Process32Next(snapshot, &entry)
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return nil, err
+	}
+	return children, nil
+}
+
+func processUsage(pid uint32) (Usage, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return Usage{}, fmt.Errorf("opening process %d: %v", pid, err)
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+
+	var creation, exit, kernel, user windows.Filetime
+	err = windows.GetProcessTimes(h, &creation, &exit, &kernel, &user)
+	if err != nil {
+		return Usage{}, fmt.Errorf("reading times of process %d: %v", pid, err)
+	}
+
+	var mem processMemoryCounters
+	mem.cb = uint32(unsafe.Sizeof(mem))
+	r, _, err := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&mem)), uintptr(mem.cb))
+	if r == 0 {
+		return Usage{}, fmt.Errorf("reading memory of process %d: %v", pid, err)
+	}
+
+	return Usage{
+		CPUTime: filetimeDuration(kernel) + filetimeDuration(user),
+		RSS:     uint64(mem.WorkingSetSize),
+	}, nil
+}
+
+// Converts a Filetime that holds a duration, in units of 100ns.
+func filetimeDuration(ft windows.Filetime) time.Duration {
+	return time.Duration(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) * 100
+}
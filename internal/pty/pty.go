@@ -0,0 +1,32 @@
+// Package pty runs commands in a pseudo-terminal, so that tools which check
+// whether they're writing to a terminal print colors and progress output.
+package pty
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ErrUnsupported means this OS can't run a command in a pseudo-terminal.
+var ErrUnsupported = errors.New("pseudo-terminals are not supported on this OS")
+
+// Winsize is the size of a terminal, in characters.
+type Winsize struct {
+	Rows uint16
+	Cols uint16
+}
+
+// The size of a terminal when Tilt isn't running in one.
+var DefaultWinsize = Winsize{Rows: 24, Cols: 80}
+
+// Returns the size of the terminal that Tilt is running in, or the default
+// size if it isn't running in one.
+func TerminalSize() Winsize {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || cols <= 0 || rows <= 0 {
+		return DefaultWinsize
+	}
+	return Winsize{Rows: uint16(rows), Cols: uint16(cols)}
+}
@@ -0,0 +1,52 @@
+package pty
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const Supported = true
+
+func open() (ptmx *os.File, tty *os.File, err error) {
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	ptmx = os.NewFile(uintptr(fd), "/dev/ptmx")
+
+	err = ioctl(fd, unix.TIOCPTYGRANT, 0)
+	if err == nil {
+		err = ioctl(fd, unix.TIOCPTYUNLK, 0)
+	}
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, fmt.Errorf("unlocking pty: %v", err)
+	}
+
+	buf := make([]byte, 128)
+	err = ioctl(fd, unix.TIOCPTYGNAME, uintptr(unsafe.Pointer(&buf[0])))
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, fmt.Errorf("getting pty name: %v", err)
+	}
+
+	name := string(buf[:bytes.IndexByte(buf, 0)])
+	tty, err = os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, err
+	}
+	return ptmx, tty, nil
+}
+
+func ioctl(fd int, req uint, arg uintptr) error {
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(req), arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package pty
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+const Supported = true
+
+func open() (ptmx *os.File, tty *os.File, err error) {
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	ptmx = os.NewFile(uintptr(fd), "/dev/ptmx")
+
+	err = unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0)
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, fmt.Errorf("unlocking pty: %v", err)
+	}
+	n, err := unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, fmt.Errorf("getting pty number: %v", err)
+	}
+
+	name := fmt.Sprintf("/dev/pts/%d", n)
+	tty, err = os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, nil, err
+	}
+	return ptmx, tty, nil
+}
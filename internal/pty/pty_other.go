@@ -0,0 +1,13 @@
+//go:build !windows && !linux && !darwin
+// +build !windows,!linux,!darwin
+
+package pty
+
+import "os"
+
+// Only Linux and macOS have been taught to open a pseudo-terminal.
+const Supported = false
+
+func open() (*os.File, *os.File, error) {
+	return nil, nil, ErrUnsupported
+}
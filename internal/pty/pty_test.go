@@ -0,0 +1,28 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package pty
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart(t *testing.T) {
+	c := exec.Command("sh", "-c", "test -t 1 && echo is a terminal; stty size")
+	ptmx, err := Start(c, Winsize{Rows: 40, Cols: 120})
+	require.NoError(t, err)
+	defer func() { _ = ptmx.Close() }()
+
+	out := &bytes.Buffer{}
+	_, _ = io.Copy(out, ptmx)
+	require.NoError(t, c.Wait())
+
+	assert.Contains(t, out.String(), "is a terminal")
+	assert.Contains(t, out.String(), "40 120")
+}
@@ -0,0 +1,80 @@
+//go:build !windows
+// +build !windows
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Starts the command with a new pseudo-terminal as its stdin, stdout, and
+// stderr, and returns the terminal's controller. Reading from it returns
+// the command's output.
+//
+// The command runs in a new session, so it gets its own process group like
+// commands started with procutil.SetOptNewProcessGroup.
+func Start(c *exec.Cmd, size Winsize) (*os.File, error) {
+	ptmx, tty, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tty.Close() }()
+
+	err = Resize(ptmx, size)
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+
+	c.Stdin = tty
+	c.Stdout = tty
+	c.Stderr = tty
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	c.SysProcAttr.Setsid = true
+	c.SysProcAttr.Setctty = true
+	c.SysProcAttr.Ctty = 0 // stdin, in the child
+
+	err = c.Start()
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+	return ptmx, nil
+}
+
+func Resize(ptmx *os.File, size Winsize) error {
+	return unix.IoctlSetWinsize(int(ptmx.Fd()), unix.TIOCSWINSZ, &unix.Winsize{
+		Row: size.Rows,
+		Col: size.Cols,
+	})
+}
+
+// Resizes the pseudo-terminal whenever the terminal that Tilt is running in
+// is resized, until the returned function is called.
+func NotifyResize(ptmx *os.File) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = Resize(ptmx, TerminalSize())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
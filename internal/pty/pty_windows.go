@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Windows has ConPTY, but os/exec can't attach a pseudo console to a
+// process yet, so commands run without one.
+const Supported = false
+
+func Start(c *exec.Cmd, size Winsize) (*os.File, error) {
+	return nil, ErrUnsupported
+}
+
+func Resize(ptmx *os.File, size Winsize) error {
+	return ErrUnsupported
+}
+
+func NotifyResize(ptmx *os.File) (stop func()) {
+	return func() {}
+}
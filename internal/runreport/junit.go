@@ -0,0 +1,116 @@
+package runreport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+)
+
+// The JUnit XML format, as understood by most CI systems.
+// Each resource is a test case.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Writes the report as JUnit XML.
+//
+// Resources with errors are failures. So are resources that were still
+// pending when a failed run ended, because that's usually why it failed
+// (for example, a timeout).
+func WriteJUnit(w io.Writer, report Report) error {
+	suite := junitTestSuite{Name: "tilt"}
+	var total float64
+	for _, r := range report.Resources {
+		tc := junitTestCase{Name: r.Name, ClassName: "tilt"}
+		if r.LastBuild != nil {
+			tc.Time = formatSeconds(r.LastBuild.DurationSeconds)
+			total += r.LastBuild.DurationSeconds
+		} else {
+			tc.Time = formatSeconds(0)
+		}
+
+		switch {
+		case r.Status == webview.ReadinessError ||
+			(r.Status == webview.ReadinessPending && report.Result == ResultFailure):
+			message := r.Reason
+			if r.Message != "" {
+				message = fmt.Sprintf("%s: %s", r.Reason, r.Message)
+			}
+			tc.Failure = &junitFailure{
+				Message: message,
+				Type:    r.Reason,
+				Text:    r.Logs.Excerpt,
+			}
+			tc.SystemOut = fmt.Sprintf("See the full logs with: %s", r.Logs.Command)
+			suite.Failures++
+		case r.Status == webview.ReadinessSkipped:
+			tc.Skipped = &junitSkipped{Message: r.Reason}
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Tests = len(suite.TestCases)
+	suite.Time = formatSeconds(total)
+
+	suites := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(suites)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func formatSeconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
@@ -0,0 +1,185 @@
+// Package runreport summarizes how each resource did in a Tilt run,
+// for CI systems that want structured results rather than logs.
+package runreport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// The number of log lines to include for each failed resource.
+const excerptLines = 20
+
+type Result string
+
+const (
+	ResultSuccess Result = "success"
+	ResultFailure Result = "failure"
+)
+
+type Report struct {
+	Result Result `json:"result"`
+
+	// Why the run failed, if it did.
+	Error     string     `json:"error,omitempty"`
+	Resources []Resource `json:"resources"`
+}
+
+type Resource struct {
+	Name          string                  `json:"name"`
+	Labels        []string                `json:"labels,omitempty"`
+	Status        webview.ReadinessStatus `json:"status"`
+	Reason        string                  `json:"reason,omitempty"`
+	Message       string                  `json:"message,omitempty"`
+	UpdateStatus  v1alpha1.UpdateStatus   `json:"updateStatus,omitempty"`
+	RuntimeStatus v1alpha1.RuntimeStatus  `json:"runtimeStatus,omitempty"`
+	BuildCount    int                     `json:"buildCount"`
+	LastBuild     *Build                  `json:"lastBuild,omitempty"`
+	Logs          Logs                    `json:"logs"`
+}
+
+type Build struct {
+	StartTime       time.Time `json:"startTime"`
+	FinishTime      time.Time `json:"finishTime"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Error           string    `json:"error,omitempty"`
+	Warnings        []string  `json:"warnings,omitempty"`
+}
+
+// Where to find the logs that explain a resource's status.
+type Logs struct {
+	// The log span of the last build, or of the runtime if that's what failed.
+	SpanID string `json:"spanId,omitempty"`
+
+	// A command that prints the resource's logs while Tilt is running.
+	Command string `json:"command"`
+
+	// The end of the span's logs. Only included for resources with errors.
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// Builds a report from the resources in the API server
+// and the logs in the engine state.
+type Reporter struct {
+	st     store.RStore
+	client ctrlclient.Client
+}
+
+func NewReporter(st store.RStore, client ctrlclient.Client) *Reporter {
+	return &Reporter{
+		st:     st,
+		client: client,
+	}
+}
+
+// Reports on the run that ended with runErr.
+func (r *Reporter) Report(ctx context.Context, runErr error) (Report, error) {
+	var list v1alpha1.UIResourceList
+	err := r.client.List(ctx, &list)
+	if err != nil {
+		return Report{}, fmt.Errorf("listing resources: %v", err)
+	}
+
+	state := r.st.RLockState()
+	defer r.st.RUnlockState()
+	return NewReport(list.Items, state.LogStore, runErr), nil
+}
+
+func NewReport(resources []v1alpha1.UIResource, logs *logstore.LogStore, runErr error) Report {
+	report := Report{Result: ResultSuccess, Resources: []Resource{}}
+	if runErr != nil {
+		report.Result = ResultFailure
+		report.Error = runErr.Error()
+	}
+
+	for _, uir := range resources {
+		// The Tiltfile resource is reported like any other,
+		// because a Tiltfile error fails the run.
+		report.Resources = append(report.Resources, newResource(uir, logs))
+	}
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Name < report.Resources[j].Name
+	})
+	return report
+}
+
+func newResource(uir v1alpha1.UIResource, logs *logstore.LogStore) Resource {
+	readiness := webview.UIResourceReadiness(uir)
+	s := uir.Status
+	result := Resource{
+		Name:          uir.Name,
+		Labels:        resourceLabels(uir),
+		Status:        readiness.Status,
+		Reason:        readiness.Reason,
+		Message:       readiness.Message,
+		UpdateStatus:  s.UpdateStatus,
+		RuntimeStatus: s.RuntimeStatus,
+		BuildCount:    len(s.BuildHistory),
+		Logs:          Logs{Command: fmt.Sprintf("tilt logs %s", uir.Name)},
+	}
+
+	if len(s.BuildHistory) > 0 {
+		last := s.BuildHistory[0]
+		result.LastBuild = &Build{
+			StartTime:       last.StartTime.Time,
+			FinishTime:      last.FinishTime.Time,
+			DurationSeconds: last.FinishTime.Sub(last.StartTime.Time).Seconds(),
+			Error:           last.Error,
+			Warnings:        last.Warnings,
+		}
+		result.Logs.SpanID = last.SpanID
+	}
+	if readiness.Reason == "RuntimeError" && s.K8sResourceInfo != nil && s.K8sResourceInfo.SpanID != "" {
+		result.Logs.SpanID = s.K8sResourceInfo.SpanID
+	}
+
+	if readiness.Status == webview.ReadinessError && logs != nil {
+		if result.Logs.SpanID != "" {
+			result.Logs.Excerpt = logs.TailSpan(excerptLines, logstore.SpanID(result.Logs.SpanID))
+		} else {
+			// Local servers don't have a span of their own,
+			// so fall back to the end of the resource's logs.
+			result.Logs.Excerpt = tailLines(logs.ManifestLog(model.ManifestName(uir.Name)), excerptLines)
+		}
+	}
+	return result
+}
+
+func resourceLabels(uir v1alpha1.UIResource) []string {
+	var labels []string
+	for l := range uir.Labels {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func tailLines(s string, n int) string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "")
+}
+
+func WriteJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,158 @@
+package runreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+func TestReport(t *testing.T) {
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	logs := logstore.NewLogStore()
+	for i := 0; i < 30; i++ {
+		logs.Append(store.NewLogAction("be", "build:be-1", logger.InfoLvl, nil,
+			[]byte(fmt.Sprintf("step %d\n", i))), nil)
+	}
+
+	resources := []v1alpha1.UIResource{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "fe", Labels: map[string]string{"web": "web"}},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusOK,
+				BuildHistory: []v1alpha1.UIBuildTerminated{{
+					StartTime:  metav1.NewMicroTime(start),
+					FinishTime: metav1.NewMicroTime(start.Add(1500 * time.Millisecond)),
+					SpanID:     "build:fe-1",
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "be"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusError,
+				RuntimeStatus: v1alpha1.RuntimeStatusNotApplicable,
+				BuildHistory: []v1alpha1.UIBuildTerminated{{
+					StartTime:  metav1.NewMicroTime(start),
+					FinishTime: metav1.NewMicroTime(start.Add(time.Second)),
+					Error:      "exit status 1",
+					SpanID:     "build:be-1",
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "manual"},
+			Status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusNone,
+				RuntimeStatus: v1alpha1.RuntimeStatusNone,
+			},
+		},
+	}
+
+	report := NewReport(resources, logs, fmt.Errorf("be: exit status 1"))
+	assert.Equal(t, ResultFailure, report.Result)
+	assert.Equal(t, "be: exit status 1", report.Error)
+	require.Len(t, report.Resources, 3)
+
+	be := report.Resources[0]
+	assert.Equal(t, "be", be.Name)
+	assert.Equal(t, webview.ReadinessError, be.Status)
+	assert.Equal(t, "UpdateError", be.Reason)
+	assert.Equal(t, "exit status 1", be.Message)
+	assert.Equal(t, 1.0, be.LastBuild.DurationSeconds)
+	assert.Equal(t, "build:be-1", be.Logs.SpanID)
+	assert.Equal(t, "tilt logs be", be.Logs.Command)
+	assert.Contains(t, be.Logs.Excerpt, "step 29\n")
+	assert.Contains(t, be.Logs.Excerpt, "step 10\n")
+	assert.NotContains(t, be.Logs.Excerpt, "step 9\n")
+
+	fe := report.Resources[1]
+	assert.Equal(t, webview.ReadinessReady, fe.Status)
+	assert.Equal(t, []string{"web"}, fe.Labels)
+	assert.Equal(t, 1.5, fe.LastBuild.DurationSeconds)
+	assert.Equal(t, "", fe.Logs.Excerpt)
+
+	manual := report.Resources[2]
+	assert.Equal(t, webview.ReadinessSkipped, manual.Status)
+	assert.Nil(t, manual.LastBuild)
+
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, WriteJSON(out, report))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+}
+
+func TestReportSuccess(t *testing.T) {
+	report := NewReport(nil, logstore.NewLogStore(), nil)
+	assert.Equal(t, ResultSuccess, report.Result)
+	assert.Equal(t, "", report.Error)
+	assert.Equal(t, []Resource{}, report.Resources)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	report := Report{
+		Result: ResultFailure,
+		Resources: []Resource{
+			{
+				Name:      "be",
+				Status:    webview.ReadinessError,
+				Reason:    "UpdateError",
+				Message:   "exit status 1",
+				LastBuild: &Build{DurationSeconds: 1},
+				Logs:      Logs{Command: "tilt logs be", Excerpt: "go: build failed\n"},
+			},
+			{
+				Name:      "fe",
+				Status:    webview.ReadinessReady,
+				LastBuild: &Build{DurationSeconds: 1.5},
+				Logs:      Logs{Command: "tilt logs fe"},
+			},
+			{
+				Name:   "db",
+				Status: webview.ReadinessPending,
+				Reason: "RuntimePending",
+				Logs:   Logs{Command: "tilt logs db"},
+			},
+			{
+				Name:   "manual",
+				Status: webview.ReadinessSkipped,
+				Reason: "NotTriggered",
+				Logs:   Logs{Command: "tilt logs manual"},
+			},
+		},
+	}
+
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, WriteJUnit(out, report))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="tilt" tests="4" failures="2" skipped="1" time="2.500">
+  <testsuite name="tilt" tests="4" failures="2" skipped="1" time="2.500">
+    <testcase name="be" classname="tilt" time="1.000">
+      <failure message="UpdateError: exit status 1" type="UpdateError">go: build failed&#xA;</failure>
+      <system-out>See the full logs with: tilt logs be</system-out>
+    </testcase>
+    <testcase name="fe" classname="tilt" time="1.500"></testcase>
+    <testcase name="db" classname="tilt" time="0.000">
+      <failure message="RuntimePending" type="RuntimePending"></failure>
+      <system-out>See the full logs with: tilt logs db</system-out>
+    </testcase>
+    <testcase name="manual" classname="tilt" time="0.000">
+      <skipped message="NotTriggered"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, out.String())
+}
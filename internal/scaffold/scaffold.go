@@ -0,0 +1,278 @@
+// Package scaffold generates a starter Tiltfile, Dockerfile, and deploy
+// manifests for an existing project, based on a template.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Helm charts use {{ }} for their own templates, so we use
+// different delimiters to keep the two apart.
+const (
+	leftDelim  = "[["
+	rightDelim = "]]"
+)
+
+// A Template describes a kind of project that Tilt knows how to scaffold.
+type Template struct {
+	Name        string
+	Description string
+
+	// The port the app listens on, if the project doesn't say otherwise.
+	DefaultPort int
+
+	// Files whose presence suggests the template is a good fit for a project.
+	Markers []string
+
+	// The generated files, as text/templates rendered against a Project
+	// with [[ ]] delimiters. Output paths are the template paths with any
+	// .tmpl suffix removed.
+	Files fs.FS
+
+	// Fills in template-specific details by looking at the project directory.
+	Inspect func(p *Project) error
+}
+
+// Whether the project in dir looks like a good fit for this template.
+func (t Template) Detect(dir string) bool {
+	for _, m := range t.Markers {
+		if fileExists(filepath.Join(dir, m)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Project holds everything templates know about the project being scaffolded.
+type Project struct {
+	Dir  string
+	Name string
+	Port int
+
+	// Go projects.
+	GoVersion     string
+	GoMainPackage string
+
+	// Node projects.
+	NodeVersion string
+	NodeCommand []string
+
+	// Python projects.
+	PythonVersion   string
+	PythonCommand   []string
+	HasRequirements bool
+}
+
+// Options override what we'd otherwise infer from the project.
+type Options struct {
+	Name string
+	Port int
+
+	// Overwrite files that already exist.
+	Force bool
+}
+
+// Result lists the generated files, relative to the project directory.
+type Result struct {
+	Created []string
+
+	// Files that already existed, and were left alone.
+	Skipped []string
+}
+
+var registry = map[string]Template{}
+
+// Register makes a template available to `tilt init`.
+func Register(t Template) {
+	registry[t.Name] = t
+}
+
+// Templates returns all registered templates, sorted by name.
+func Templates() []Template {
+	result := make([]Template, 0, len(registry))
+	for _, t := range registry {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func Lookup(name string) (Template, error) {
+	t, ok := registry[name]
+	if !ok {
+		return Template{}, fmt.Errorf("unknown template %q. Available templates: %s", name, strings.Join(templateNames(), ", "))
+	}
+	return t, nil
+}
+
+// Detect returns the first template that looks like a good fit for the project in dir.
+func Detect(dir string) (Template, error) {
+	for _, t := range Templates() {
+		if t.Detect(dir) {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("could not detect a project type in %s. Choose a template: %s", dir, strings.Join(templateNames(), ", "))
+}
+
+func templateNames() []string {
+	var names []string
+	for _, t := range Templates() {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// Inspect builds the Project that a template will be rendered against.
+func Inspect(dir string, t Template, opts Options) (Project, error) {
+	p := Project{
+		Dir:  dir,
+		Name: sanitizeName(filepath.Base(dir)),
+		Port: t.DefaultPort,
+	}
+	if opts.Port != 0 {
+		p.Port = opts.Port
+	}
+
+	if t.Inspect != nil {
+		err := t.Inspect(&p)
+		if err != nil {
+			return Project{}, err
+		}
+	}
+
+	if opts.Name != "" {
+		p.Name = sanitizeName(opts.Name)
+	}
+	if p.Name == "" {
+		return Project{}, fmt.Errorf("could not infer a project name from %s. Use --name to set one", dir)
+	}
+	return p, nil
+}
+
+// Generate renders the template into the project directory.
+//
+// Existing files are never overwritten unless opts.Force is set.
+func Generate(dir string, t Template, opts Options) (Result, error) {
+	p, err := Inspect(dir, t, opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	rendered, err := render(t, p)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, f := range rendered {
+		dest := filepath.Join(dir, filepath.FromSlash(f.path))
+		if !opts.Force && fileExists(dest) {
+			result.Skipped = append(result.Skipped, f.path)
+			continue
+		}
+
+		err := os.MkdirAll(filepath.Dir(dest), 0755)
+		if err != nil {
+			return result, err
+		}
+		err = os.WriteFile(dest, f.contents, 0644)
+		if err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, f.path)
+	}
+	return result, nil
+}
+
+type renderedFile struct {
+	path     string
+	contents []byte
+}
+
+func render(t Template, p Project) ([]renderedFile, error) {
+	var result []renderedFile
+	err := fs.WalkDir(t.Files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		contents, err := fs.ReadFile(t.Files, name)
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := template.New(name).
+			Delims(leftDelim, rightDelim).
+			Funcs(template.FuncMap{"quoteList": quoteList}).
+			Option("missingkey=error").
+			Parse(string(contents))
+		if err != nil {
+			return fmt.Errorf("template %s: %v", t.Name, err)
+		}
+
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, p)
+		if err != nil {
+			return fmt.Errorf("template %s: %v", t.Name, err)
+		}
+
+		result = append(result, renderedFile{
+			path:     strings.TrimSuffix(name, ".tmpl"),
+			contents: buf.Bytes(),
+		})
+		return nil
+	})
+	return result, err
+}
+
+// Formats a list of strings as a JSON-style list, for Dockerfile CMDs.
+func quoteList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, fmt.Sprintf("%q", item))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Project names become Kubernetes object names, Compose service names,
+// and image names, so they need to be valid DNS labels.
+func sanitizeName(name string) string {
+	name = strings.ToLower(name)
+	name = invalidNameChars.ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 63 {
+		name = strings.Trim(name[:63], "-")
+	}
+	return name
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+func mustSub(dir string) fs.FS {
+	sub, err := fs.Sub(templateFS, path.Join("templates", dir))
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
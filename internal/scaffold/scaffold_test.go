@@ -0,0 +1,148 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/tiltfile/lint"
+)
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		marker   string
+		expected string
+	}{
+		{"go.mod", "go-k8s"},
+		{"package.json", "node-compose"},
+		{"requirements.txt", "python-helm"},
+		{"pyproject.toml", "python-helm"},
+	} {
+		t.Run(tc.marker, func(t *testing.T) {
+			f := tempdir.NewTempDirFixture(t)
+			f.WriteFile(tc.marker, "")
+
+			tmpl, err := Detect(f.Path())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, tmpl.Name)
+		})
+	}
+}
+
+func TestDetectNothing(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+
+	_, err := Detect(f.Path())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Choose a template: go-k8s, node-compose, python-helm")
+}
+
+func TestLookupUnknown(t *testing.T) {
+	_, err := Lookup("rust-nomad")
+	require.EqualError(t, err, `unknown template "rust-nomad". Available templates: go-k8s, node-compose, python-helm`)
+}
+
+func TestGoK8s(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("go.mod", "module github.com/example/Hello_Server\n\ngo 1.18\n")
+	f.WriteFile("cmd/hello-server/main.go", "package main\n")
+
+	result := generate(t, f, "go-k8s", Options{})
+	assert.Equal(t, []string{"Dockerfile", "Tiltfile", "kubernetes.yaml"}, result.Created)
+
+	assertFileContains(t, f, "Dockerfile", "FROM golang:1.18 AS build")
+	assertFileContains(t, f, "Dockerfile", "go build -o /out/hello-server ./cmd/hello-server")
+	assertFileContains(t, f, "Tiltfile", "k8s_resource('hello-server', port_forwards=8080)")
+	assertFileContains(t, f, "kubernetes.yaml", "image: hello-server")
+	assertLints(t, f)
+}
+
+func TestNodeCompose(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("package.json", `{
+  "name": "@example/web",
+  "scripts": {"start": "node server.js"},
+  "engines": {"node": ">=16.0.0"}
+}`)
+
+	generate(t, f, "node-compose", Options{Port: 8000})
+
+	assertFileContains(t, f, "Dockerfile", "FROM node:16")
+	assertFileContains(t, f, "Dockerfile", `CMD ["npm", "start"]`)
+	assertFileContains(t, f, "docker-compose.yml", `- "8000:8000"`)
+	assertFileContains(t, f, "Tiltfile", "docker_build('web', '.',")
+	assertLints(t, f)
+}
+
+func TestPythonHelm(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("requirements.txt", "flask\n")
+	f.WriteFile("main.py", "")
+
+	result := generate(t, f, "python-helm", Options{Name: "My API"})
+	assert.ElementsMatch(t, []string{
+		"Dockerfile",
+		"Tiltfile",
+		"chart/Chart.yaml",
+		"chart/templates/deployment.yaml",
+		"chart/templates/service.yaml",
+		"chart/values.yaml",
+	}, result.Created)
+
+	assertFileContains(t, f, "Dockerfile", "RUN pip install --no-cache-dir -r requirements.txt")
+	assertFileContains(t, f, "Dockerfile", `CMD ["python", "main.py"]`)
+	assertFileContains(t, f, "Tiltfile", "run('pip install -r requirements.txt', trigger='requirements.txt'),")
+	assertFileContains(t, f, "Tiltfile", "k8s_yaml(helm('chart', name='my-api'))")
+	assertFileContains(t, f, "chart/values.yaml", "repository: my-api")
+
+	// Helm's own template syntax is left alone.
+	assertFileContains(t, f, "chart/templates/deployment.yaml", "name: {{ .Release.Name }}")
+	assertLints(t, f)
+}
+
+func TestExistingFilesSkipped(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("go.mod", "module hello\n")
+	f.WriteFile("Dockerfile", "FROM scratch\n")
+
+	result := generate(t, f, "go-k8s", Options{})
+	assert.Equal(t, []string{"Tiltfile", "kubernetes.yaml"}, result.Created)
+	assert.Equal(t, []string{"Dockerfile"}, result.Skipped)
+	assertFileContains(t, f, "Dockerfile", "FROM scratch")
+
+	result = generate(t, f, "go-k8s", Options{Force: true})
+	assert.Equal(t, []string{"Dockerfile", "Tiltfile", "kubernetes.yaml"}, result.Created)
+	assertFileContains(t, f, "Dockerfile", "FROM golang:")
+}
+
+func generate(t *testing.T, f *tempdir.TempDirFixture, name string, opts Options) Result {
+	tmpl, err := Lookup(name)
+	require.NoError(t, err)
+
+	result, err := Generate(f.Path(), tmpl, opts)
+	require.NoError(t, err)
+	return result
+}
+
+func assertFileContains(t *testing.T, f *tempdir.TempDirFixture, path, expected string) {
+	t.Helper()
+	contents, err := os.ReadFile(f.JoinPath(filepath.FromSlash(path)))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), expected)
+}
+
+// The generated Tiltfile should only call built-ins correctly,
+// and only reference files that exist.
+func assertLints(t *testing.T, f *tempdir.TempDirFixture) {
+	t.Helper()
+	linter, err := lint.ProvideLinter()
+	require.NoError(t, err)
+
+	diags, err := linter.Lint(f.JoinPath("Tiltfile"))
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+}
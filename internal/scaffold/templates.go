@@ -0,0 +1,143 @@
+package scaffold
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	Register(Template{
+		Name:        "go-k8s",
+		Description: "A Go service deployed to Kubernetes",
+		DefaultPort: 8080,
+		Markers:     []string{"go.mod"},
+		Files:       mustSub("go-k8s"),
+		Inspect:     inspectGo,
+	})
+	Register(Template{
+		Name:        "node-compose",
+		Description: "A Node.js service run with Docker Compose",
+		DefaultPort: 3000,
+		Markers:     []string{"package.json"},
+		Files:       mustSub("node-compose"),
+		Inspect:     inspectNode,
+	})
+	Register(Template{
+		Name:        "python-helm",
+		Description: "A Python service deployed to Kubernetes with a Helm chart",
+		DefaultPort: 8000,
+		Markers:     []string{"requirements.txt", "pyproject.toml", "setup.py"},
+		Files:       mustSub("python-helm"),
+		Inspect:     inspectPython,
+	})
+}
+
+var goDirective = regexp.MustCompile(`^go\s+(\d+\.\d+)`)
+
+func inspectGo(p *Project) error {
+	p.GoVersion = "1.19"
+	p.GoMainPackage = "."
+
+	f, err := os.Open(filepath.Join(p.Dir, "go.mod"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		defer func() { _ = f.Close() }()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "module ") {
+				module := strings.TrimSpace(strings.TrimPrefix(line, "module "))
+				if name := sanitizeName(path.Base(module)); name != "" {
+					p.Name = name
+				}
+			} else if m := goDirective.FindStringSubmatch(line); m != nil {
+				p.GoVersion = m[1]
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	// If the main package isn't at the root, prefer the one that matches
+	// the project name, then the only one there is.
+	if !fileExists(filepath.Join(p.Dir, "main.go")) {
+		mains, err := filepath.Glob(filepath.Join(p.Dir, "cmd", "*", "main.go"))
+		if err != nil {
+			return err
+		}
+		for _, m := range mains {
+			name := filepath.Base(filepath.Dir(m))
+			if name == p.Name || len(mains) == 1 {
+				p.GoMainPackage = "./cmd/" + name
+				break
+			}
+		}
+	}
+	return nil
+}
+
+type packageJSON struct {
+	Name    string            `json:"name"`
+	Main    string            `json:"main"`
+	Scripts map[string]string `json:"scripts"`
+	Engines map[string]string `json:"engines"`
+}
+
+var majorVersion = regexp.MustCompile(`\d+`)
+
+func inspectNode(p *Project) error {
+	p.NodeVersion = "18"
+	p.NodeCommand = []string{"node", "index.js"}
+
+	contents, err := os.ReadFile(filepath.Join(p.Dir, "package.json"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var pkg packageJSON
+	err = json.Unmarshal(contents, &pkg)
+	if err != nil {
+		return err
+	}
+
+	// Scoped packages look like @org/name.
+	if name := sanitizeName(path.Base(pkg.Name)); name != "" {
+		p.Name = name
+	}
+	if v := majorVersion.FindString(pkg.Engines["node"]); v != "" {
+		p.NodeVersion = v
+	}
+	if pkg.Scripts["start"] != "" {
+		p.NodeCommand = []string{"npm", "start"}
+	} else if pkg.Main != "" {
+		p.NodeCommand = []string{"node", pkg.Main}
+	}
+	return nil
+}
+
+func inspectPython(p *Project) error {
+	p.PythonVersion = "3.11"
+	p.HasRequirements = fileExists(filepath.Join(p.Dir, "requirements.txt"))
+
+	switch {
+	case fileExists(filepath.Join(p.Dir, "manage.py")):
+		p.PythonCommand = []string{"python", "manage.py", "runserver", fmt.Sprintf("0.0.0.0:%d", p.Port)}
+	case fileExists(filepath.Join(p.Dir, "main.py")):
+		p.PythonCommand = []string{"python", "main.py"}
+	default:
+		p.PythonCommand = []string{"python", "app.py"}
+	}
+	return nil
+}
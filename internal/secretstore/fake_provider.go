@@ -0,0 +1,55 @@
+package secretstore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type FakeProvider struct {
+	name string
+
+	mu      sync.Mutex
+	secrets map[string]string
+	ttl     time.Duration
+	gets    int
+}
+
+var _ Provider = &FakeProvider{}
+
+func NewFakeProvider(name string) *FakeProvider {
+	return &FakeProvider{name: name, secrets: make(map[string]string)}
+}
+
+func (p *FakeProvider) Name() string { return p.name }
+
+func (p *FakeProvider) Set(key, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets[key] = value
+}
+
+// Sets the TTL returned with every secret.
+func (p *FakeProvider) SetTTL(ttl time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ttl = ttl
+}
+
+// The number of times Get has been called.
+func (p *FakeProvider) Gets() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.gets
+}
+
+func (p *FakeProvider) Get(ctx context.Context, key string) (Secret, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gets++
+	value, ok := p.secrets[key]
+	if !ok {
+		return Secret{}, ErrNotFound
+	}
+	return Secret{Value: []byte(value), TTL: p.ttl}, nil
+}
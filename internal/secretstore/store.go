@@ -0,0 +1,151 @@
+// Package secretstore lets Tiltfiles read secrets from external secret stores
+// (like Vault) without writing them to disk or into the Tiltfile itself.
+//
+// Every secret value that passes through a Store is registered for redaction,
+// so it's scrubbed from logs even if the Tiltfile prints it.
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How long to cache secrets whose provider doesn't specify a TTL.
+const DefaultTTL = 5 * time.Minute
+
+var ErrNotFound = errors.New("secret not found")
+
+// Provider fetches secrets from an external secret store.
+//
+// Implement this interface to connect Tilt to your own secret store.
+type Provider interface {
+	// A short, unique name for the provider (e.g., "vault").
+	//
+	// Tiltfiles select a provider by name, and the name appears in
+	// the redaction marker in logs.
+	Name() string
+
+	// Fetches the secret with the given key.
+	//
+	// Returns an error wrapping ErrNotFound if the secret doesn't exist.
+	Get(ctx context.Context, key string) (Secret, error)
+}
+
+type Secret struct {
+	Value []byte
+
+	// How long the value may be cached before it's fetched again.
+	//
+	// If zero, the Store's default TTL is used.
+	TTL time.Duration
+}
+
+type cacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Store fetches secrets from a set of providers, caching them until their TTL expires.
+//
+// A Store is meant to live as long as the Tilt process, so that secrets
+// are re-used across Tiltfile reloads.
+type Store struct {
+	clock      clockwork.Clock
+	defaultTTL time.Duration
+	providers  map[string]Provider
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewStore(clock clockwork.Clock, defaultTTL time.Duration, providers ...Provider) *Store {
+	s := &Store{
+		clock:      clock,
+		defaultTTL: defaultTTL,
+		providers:  make(map[string]Provider, len(providers)),
+		entries:    make(map[string]cacheEntry),
+	}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+	}
+	return s
+}
+
+// The default Store, with all the providers built into Tilt.
+func ProvideStore() *Store {
+	return NewStore(clockwork.NewRealClock(), DefaultTTL, NewVaultProviderFromEnv())
+}
+
+// Fetches a secret, and registers it for redaction in the given secret set.
+//
+// If providerName is empty and the Store only has one provider, that provider is used.
+func (s *Store) Get(ctx context.Context, providerName string, key string, redact model.SecretSet) ([]byte, error) {
+	if redact == nil {
+		return nil, fmt.Errorf("internal error: secrets must be registered for redaction")
+	}
+
+	p, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := p.Name() + "\x00" + key
+	s.mu.Lock()
+	entry, ok := s.entries[cacheKey]
+	s.mu.Unlock()
+
+	if !ok || !s.clock.Now().Before(entry.expiresAt) {
+		secret, err := p.Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: fetching secret %q: %w", p.Name(), key, err)
+		}
+
+		ttl := secret.TTL
+		if ttl <= 0 {
+			ttl = s.defaultTTL
+		}
+		entry = cacheEntry{value: secret.Value, expiresAt: s.clock.Now().Add(ttl)}
+
+		s.mu.Lock()
+		s.entries[cacheKey] = entry
+		s.mu.Unlock()
+	}
+
+	redact.AddSecret(p.Name(), key, entry.value)
+	return entry.value, nil
+}
+
+func (s *Store) provider(name string) (Provider, error) {
+	if name == "" {
+		if len(s.providers) == 1 {
+			for _, p := range s.providers {
+				return p, nil
+			}
+		}
+		return nil, fmt.Errorf("must specify a secret provider (one of: %s)", s.providerNames())
+	}
+
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown secret provider %q (must be one of: %s)", name, s.providerNames())
+	}
+	return p, nil
+}
+
+func (s *Store) providerNames() string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
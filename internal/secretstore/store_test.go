@@ -0,0 +1,105 @@
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestGetRegistersSecret(t *testing.T) {
+	p := NewFakeProvider("fake")
+	p.Set("db-password", "hunter22")
+	s := NewStore(clockwork.NewFakeClock(), DefaultTTL, p)
+
+	redact := model.SecretSet{}
+	val, err := s.Get(context.Background(), "", "db-password", redact)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter22", string(val))
+	assert.Equal(t, "the password is [redacted secret fake:db-password]",
+		string(redact.Scrub([]byte("the password is hunter22"))))
+}
+
+func TestGetRequiresRedaction(t *testing.T) {
+	p := NewFakeProvider("fake")
+	p.Set("db-password", "hunter22")
+	s := NewStore(clockwork.NewFakeClock(), DefaultTTL, p)
+
+	_, err := s.Get(context.Background(), "", "db-password", nil)
+	require.Error(t, err)
+	assert.Equal(t, 0, p.Gets())
+}
+
+func TestCachedUntilDefaultTTL(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := NewFakeProvider("fake")
+	p.Set("db-password", "hunter22")
+	s := NewStore(clock, time.Minute, p)
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "", "db-password", model.SecretSet{})
+	require.NoError(t, err)
+
+	p.Set("db-password", "hunter23")
+	clock.Advance(59 * time.Second)
+	val, err := s.Get(ctx, "", "db-password", model.SecretSet{})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter22", string(val))
+	assert.Equal(t, 1, p.Gets())
+
+	clock.Advance(time.Second)
+	val, err = s.Get(ctx, "", "db-password", model.SecretSet{})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter23", string(val))
+	assert.Equal(t, 2, p.Gets())
+}
+
+func TestProviderTTL(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	p := NewFakeProvider("fake")
+	p.Set("db-password", "hunter22")
+	p.SetTTL(10 * time.Second)
+	s := NewStore(clock, time.Hour, p)
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "", "db-password", model.SecretSet{})
+	require.NoError(t, err)
+
+	clock.Advance(10 * time.Second)
+	_, err = s.Get(ctx, "", "db-password", model.SecretSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Gets())
+}
+
+func TestNotFound(t *testing.T) {
+	s := NewStore(clockwork.NewFakeClock(), DefaultTTL, NewFakeProvider("fake"))
+	_, err := s.Get(context.Background(), "", "db-password", model.SecretSet{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), `fake: fetching secret "db-password"`)
+}
+
+func TestSelectProvider(t *testing.T) {
+	a := NewFakeProvider("a")
+	a.Set("key", "value-a")
+	b := NewFakeProvider("b")
+	b.Set("key", "value-b")
+	s := NewStore(clockwork.NewFakeClock(), DefaultTTL, a, b)
+	ctx := context.Background()
+
+	val, err := s.Get(ctx, "b", "key", model.SecretSet{})
+	require.NoError(t, err)
+	assert.Equal(t, "value-b", string(val))
+
+	_, err = s.Get(ctx, "", "key", model.SecretSet{})
+	require.EqualError(t, err, "must specify a secret provider (one of: a, b)")
+
+	_, err = s.Get(ctx, "c", "key", model.SecretSet{})
+	require.EqualError(t, err, `unknown secret provider "c" (must be one of: a, b)`)
+}
@@ -0,0 +1,164 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// The KV field read when the key doesn't specify one.
+	vaultDefaultField = "value"
+
+	// The KV v2 mount read when TILT_VAULT_MOUNT isn't set.
+	vaultDefaultMount = "secret"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault KV v2 secrets engine.
+//
+// Keys have the form "path/to/secret#field". If the field is omitted,
+// the "value" field is read.
+type VaultProvider struct {
+	addr      string
+	token     string
+	namespace string
+	mount     string
+	client    *http.Client
+}
+
+var _ Provider = &VaultProvider{}
+
+func NewVaultProvider(addr, token, namespace, mount string, client *http.Client) *VaultProvider {
+	if mount == "" {
+		mount = vaultDefaultMount
+	}
+	return &VaultProvider{
+		addr:      strings.TrimSuffix(addr, "/"),
+		token:     token,
+		namespace: namespace,
+		mount:     strings.Trim(mount, "/"),
+		client:    client,
+	}
+}
+
+// Configures Vault with the same environment variables as the vault CLI.
+//
+// If VAULT_TOKEN isn't set, falls back to the token that `vault login`
+// writes to ~/.vault-token.
+func NewVaultProviderFromEnv() *VaultProvider {
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			contents, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+			if err == nil {
+				token = strings.TrimSpace(string(contents))
+			}
+		}
+	}
+	return NewVaultProvider(
+		os.Getenv("VAULT_ADDR"),
+		token,
+		os.Getenv("VAULT_NAMESPACE"),
+		os.Getenv("TILT_VAULT_MOUNT"),
+		&http.Client{Timeout: 30 * time.Second},
+	)
+}
+
+func (p *VaultProvider) Name() string { return "vault" }
+
+type vaultKVResponse struct {
+	LeaseDuration int `json:"lease_duration"`
+	Data          struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+func (p *VaultProvider) Get(ctx context.Context, key string) (Secret, error) {
+	if p.addr == "" {
+		return Secret{}, fmt.Errorf("VAULT_ADDR not set")
+	}
+	if p.token == "" {
+		return Secret{}, fmt.Errorf("VAULT_TOKEN not set, and no token found in ~/.vault-token")
+	}
+
+	secretPath, field := key, vaultDefaultField
+	if i := strings.LastIndex(key, "#"); i != -1 {
+		secretPath, field = key[:i], key[i+1:]
+	}
+	secretPath = strings.Trim(secretPath, "/")
+	if secretPath == "" || field == "" {
+		return Secret{}, fmt.Errorf("malformed key %q: expected path/to/secret#field", key)
+	}
+
+	u := fmt.Sprintf("%s/v1/%s/data/%s", p.addr, p.mount, escapePath(secretPath))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Secret{}, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Secret{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("vault returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var kv vaultKVResponse
+	err = json.Unmarshal(body, &kv)
+	if err != nil {
+		return Secret{}, fmt.Errorf("decoding vault response: %v", err)
+	}
+
+	val, ok := kv.Data.Data[field]
+	if !ok {
+		return Secret{}, fmt.Errorf("field %q: %w", field, ErrNotFound)
+	}
+
+	var data []byte
+	if s, ok := val.(string); ok {
+		data = []byte(s)
+	} else {
+		data, err = json.Marshal(val)
+		if err != nil {
+			return Secret{}, err
+		}
+	}
+
+	return Secret{
+		Value: data,
+		TTL:   time.Duration(kv.LeaseDuration) * time.Second,
+	}, nil
+}
+
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
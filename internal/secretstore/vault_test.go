@@ -0,0 +1,81 @@
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultGet(t *testing.T) {
+	p := newFakeVault(t)
+
+	s, err := p.Get(context.Background(), "myapp/db")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter22", string(s.Value))
+	assert.Equal(t, 30*time.Second, s.TTL)
+
+	s, err = p.Get(context.Background(), "myapp/db#username")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", string(s.Value))
+
+	s, err = p.Get(context.Background(), "myapp/db#port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", string(s.Value))
+}
+
+func TestVaultNotFound(t *testing.T) {
+	p := newFakeVault(t)
+
+	_, err := p.Get(context.Background(), "myapp/cache")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = p.Get(context.Background(), "myapp/db#missing")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestVaultUnauthorized(t *testing.T) {
+	p := newFakeVault(t)
+	p.token = "wrong"
+
+	_, err := p.Get(context.Background(), "myapp/db")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403 Forbidden")
+}
+
+func TestVaultNotConfigured(t *testing.T) {
+	p := NewVaultProvider("", "", "", "", http.DefaultClient)
+	_, err := p.Get(context.Background(), "myapp/db")
+	require.EqualError(t, err, "VAULT_ADDR not set")
+}
+
+func newFakeVault(t *testing.T) *VaultProvider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/myapp/db" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"errors":[]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{
+  "lease_duration": 30,
+  "data": {
+    "data": {"value": "hunter22", "username": "admin", "port": 5432},
+    "metadata": {"version": 1}
+  }
+}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewVaultProvider(server.URL, "root", "", "kv", server.Client())
+}
@@ -0,0 +1,340 @@
+package snapshots
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// A session recording is the stream of views that Tilt sent to the web UI,
+// along with every change to an apiserver object. It's written by
+// `tilt up --record` and played back by `tilt replay`.
+//
+// The file is newline-delimited JSON, one frame per line. Most frames are
+// view updates: the first holds the complete view, and every one after it
+// holds only the objects and log segments that changed. The rest each hold
+// one create, update, or delete of an apiserver object, of any type.
+type recordedFrame struct {
+	Time   time.Time       `json:"time"`
+	View   json.RawMessage `json:"view,omitempty"`
+	Object *RecordedObject `json:"object,omitempty"`
+}
+
+// One transition of an apiserver object.
+type RecordedObject struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted,omitempty"`
+
+	// The object as JSON, with its apiVersion and kind.
+	// Empty for deletes.
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+func (o RecordedObject) key() string {
+	return o.Kind + "/" + o.Name
+}
+
+// Appends frames to a session recording.
+type RecordingWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewRecordingWriter(w io.Writer) *RecordingWriter {
+	return &RecordingWriter{w: w}
+}
+
+// Writes one view update. The view must be encoded with runtime.JSONPb.
+func (rw *RecordingWriter) WriteFrame(t time.Time, view []byte) error {
+	return rw.write(recordedFrame{Time: t, View: view})
+}
+
+// Writes one object transition.
+func (rw *RecordingWriter) WriteObject(t time.Time, obj RecordedObject) error {
+	return rw.write(recordedFrame{Time: t, Object: &obj})
+}
+
+func (rw *RecordingWriter) write(frame recordedFrame) error {
+	line, err := json.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("encoding frame: %v", err)
+	}
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	_, err = rw.w.Write(append(line, '\n'))
+	return err
+}
+
+// One view update, and the object transitions that came after it,
+// up until the next view update.
+type Frame struct {
+	Time    time.Time
+	View    *proto_webview.View
+	Objects []RecordedObject
+}
+
+// How many frames apart we keep a complete copy of the session state,
+// so that jumping to a frame only replays the frames since the last one.
+const keyframeInterval = 100
+
+type Recording struct {
+	Frames []Frame
+
+	// The parts of the first view that updates never change.
+	base *proto_webview.View
+
+	// keyframes[k] is the state after frame k*keyframeInterval.
+	keyframes []*recordingState
+}
+
+func ReadRecording(r io.Reader) (*Recording, error) {
+	result := &Recording{}
+	jsEncoder := &runtime.JSONPb{}
+
+	// Objects that changed before the first view are part of the first frame.
+	var pending []RecordedObject
+
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) > 0 {
+			var frame recordedFrame
+			decodeErr := json.Unmarshal(trimmed, &frame)
+			if decodeErr != nil {
+				return nil, fmt.Errorf("reading recording line %d: %v", lineNum, decodeErr)
+			}
+
+			if frame.Object != nil {
+				if len(result.Frames) == 0 {
+					pending = append(pending, *frame.Object)
+				} else {
+					last := &result.Frames[len(result.Frames)-1]
+					last.Objects = append(last.Objects, *frame.Object)
+				}
+			} else {
+				view := &proto_webview.View{}
+				decodeErr = jsEncoder.Unmarshal(frame.View, view)
+				if decodeErr != nil {
+					return nil, fmt.Errorf("reading recording line %d: %v", lineNum, decodeErr)
+				}
+				result.Frames = append(result.Frames, Frame{Time: frame.Time, View: view, Objects: pending})
+				pending = nil
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if len(result.Frames) == 0 {
+		return nil, fmt.Errorf("recording is empty")
+	}
+
+	base := proto.Clone(result.Frames[0].View).(*proto_webview.View)
+	base.UiSession = nil
+	base.UiResources = nil
+	base.UiButtons = nil
+	base.Clusters = nil
+	base.LogList = nil
+	result.base = base
+
+	state := newRecordingState()
+	for i, frame := range result.Frames {
+		state.apply(frame)
+		if i%keyframeInterval == 0 {
+			result.keyframes = append(result.keyframes, state.clone())
+		}
+	}
+	return result, nil
+}
+
+// The complete state of the session after some frame.
+//
+// Objects in the maps are shared with the frames, and must not be modified.
+type recordingState struct {
+	session      *v1alpha1.UISession
+	resources    map[string]*v1alpha1.UIResource
+	buttons      map[string]*v1alpha1.UIButton
+	clusters     map[string]*v1alpha1.Cluster
+	spans        map[string]*proto_webview.LogSpan
+	segments     []*proto_webview.LogSegment
+	toCheckpoint int32
+	objects      map[string]RecordedObject
+}
+
+func newRecordingState() *recordingState {
+	return &recordingState{
+		resources: make(map[string]*v1alpha1.UIResource),
+		buttons:   make(map[string]*v1alpha1.UIButton),
+		clusters:  make(map[string]*v1alpha1.Cluster),
+		spans:     make(map[string]*proto_webview.LogSpan),
+		objects:   make(map[string]RecordedObject),
+	}
+}
+
+func (s *recordingState) clone() *recordingState {
+	result := &recordingState{
+		session:      s.session,
+		resources:    make(map[string]*v1alpha1.UIResource, len(s.resources)),
+		buttons:      make(map[string]*v1alpha1.UIButton, len(s.buttons)),
+		clusters:     make(map[string]*v1alpha1.Cluster, len(s.clusters)),
+		spans:        make(map[string]*proto_webview.LogSpan, len(s.spans)),
+		segments:     append([]*proto_webview.LogSegment(nil), s.segments...),
+		toCheckpoint: s.toCheckpoint,
+		objects:      make(map[string]RecordedObject, len(s.objects)),
+	}
+	for k, v := range s.resources {
+		result.resources[k] = v
+	}
+	for k, v := range s.buttons {
+		result.buttons[k] = v
+	}
+	for k, v := range s.clusters {
+		result.clusters[k] = v
+	}
+	for k, v := range s.spans {
+		result.spans[k] = v
+	}
+	for k, v := range s.objects {
+		result.objects[k] = v
+	}
+	return result
+}
+
+func (s *recordingState) apply(frame Frame) {
+	update := frame.View
+	if update.UiSession != nil {
+		s.session = update.UiSession
+	}
+	for _, obj := range update.UiResources {
+		if obj.DeletionTimestamp != nil {
+			delete(s.resources, obj.Name)
+			continue
+		}
+		s.resources[obj.Name] = obj
+	}
+	for _, obj := range update.UiButtons {
+		if obj.DeletionTimestamp != nil {
+			delete(s.buttons, obj.Name)
+			continue
+		}
+		s.buttons[obj.Name] = obj
+	}
+	for _, obj := range update.Clusters {
+		if obj.DeletionTimestamp != nil {
+			delete(s.clusters, obj.Name)
+			continue
+		}
+		s.clusters[obj.Name] = obj
+	}
+
+	if update.LogList != nil {
+		for id, span := range update.LogList.Spans {
+			s.spans[id] = span
+		}
+		s.segments = append(s.segments, update.LogList.Segments...)
+		s.toCheckpoint = update.LogList.ToCheckpoint
+	}
+
+	for _, obj := range frame.Objects {
+		if obj.Deleted {
+			delete(s.objects, obj.key())
+			continue
+		}
+		s.objects[obj.key()] = obj
+	}
+}
+
+// Replays the frames since the nearest keyframe.
+func (r *Recording) stateAt(i int) *recordingState {
+	k := i / keyframeInterval
+	state := r.keyframes[k].clone()
+	for _, frame := range r.Frames[k*keyframeInterval+1 : i+1] {
+		state.apply(frame)
+	}
+	return state
+}
+
+// Rebuilds the complete view as the web UI saw it after frame i.
+func (r *Recording) ViewAt(i int) *proto_webview.View {
+	state := r.stateAt(i)
+	view := proto.Clone(r.base).(*proto_webview.View)
+	view.UiSession = state.session
+
+	for _, obj := range state.resources {
+		view.UiResources = append(view.UiResources, obj)
+	}
+	sort.Slice(view.UiResources, func(i, j int) bool {
+		return view.UiResources[i].Name < view.UiResources[j].Name
+	})
+
+	for _, obj := range state.buttons {
+		view.UiButtons = append(view.UiButtons, obj)
+	}
+	sort.Slice(view.UiButtons, func(i, j int) bool {
+		return view.UiButtons[i].Name < view.UiButtons[j].Name
+	})
+
+	for _, obj := range state.clusters {
+		view.Clusters = append(view.Clusters, obj)
+	}
+	sort.Slice(view.Clusters, func(i, j int) bool {
+		return view.Clusters[i].Name < view.Clusters[j].Name
+	})
+
+	view.LogList = &proto_webview.LogList{
+		Spans:        state.spans,
+		Segments:     state.segments,
+		ToCheckpoint: state.toCheckpoint,
+	}
+	view.IsComplete = true
+	return view
+}
+
+// Every apiserver object that existed after frame i, sorted by kind and name.
+func (r *Recording) ObjectsAt(i int) []RecordedObject {
+	state := r.stateAt(i)
+	result := make([]RecordedObject, 0, len(state.objects))
+	for _, obj := range state.objects {
+		result = append(result, obj)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Encodes the view after frame i as a snapshot, in the same format as
+// `tilt snapshot create`.
+func (r *Recording) SnapshotAt(i int) ([]byte, error) {
+	snapshot := &proto_webview.Snapshot{
+		View:      r.ViewAt(i),
+		CreatedAt: timestamppb.New(r.Frames[i].Time),
+	}
+
+	jsEncoder := &runtime.JSONPb{}
+	return jsEncoder.Marshal(snapshot)
+}
@@ -0,0 +1,240 @@
+package snapshots
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+var start = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRecordingViewAt(t *testing.T) {
+	recording := testRecording(t)
+	require.Len(t, recording.Frames, 3)
+	assert.Equal(t, start.Add(2*time.Second), recording.Frames[2].Time.UTC())
+
+	view := recording.ViewAt(0)
+	assert.Equal(t, []string{"be", "fe"}, resourceNames(view))
+	assert.Equal(t, []string{"fe starting"}, logTexts(view))
+
+	view = recording.ViewAt(1)
+	assert.Equal(t, []string{"be", "fe"}, resourceNames(view))
+	assert.Equal(t, v1alpha1.RuntimeStatusError, view.UiResources[1].Status.RuntimeStatus)
+	assert.Equal(t, []string{"fe starting", "fe crashed"}, logTexts(view))
+	assert.Equal(t, int32(2), view.LogList.ToCheckpoint)
+	assert.True(t, view.IsComplete)
+
+	view = recording.ViewAt(2)
+	assert.Equal(t, []string{"fe"}, resourceNames(view))
+	assert.Equal(t, "v2", view.UiSession.Status.RunningTiltBuild.Version)
+
+	// Playing back must not change the frames themselves.
+	view = recording.ViewAt(0)
+	assert.Equal(t, []string{"be", "fe"}, resourceNames(view))
+	assert.Equal(t, []string{"fe starting"}, logTexts(view))
+}
+
+func TestRecordingObjectsAt(t *testing.T) {
+	recording := testRecording(t)
+
+	objects := recording.ObjectsAt(0)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "Cmd", objects[0].Kind)
+	assert.Equal(t, "fe-serve", objects[0].Name)
+	assert.JSONEq(t, `{"kind":"Cmd","metadata":{"name":"fe-serve"},"status":{"running":{}}}`, string(objects[0].Object))
+
+	objects = recording.ObjectsAt(1)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "Cmd", objects[0].Kind)
+	assert.Equal(t, "FileWatch", objects[1].Kind)
+
+	objects = recording.ObjectsAt(2)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "FileWatch", objects[0].Kind)
+}
+
+func TestRecordingKeyframes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewRecordingWriter(buf)
+	frames := 2*keyframeInterval + 10
+	for i := 0; i < frames; i++ {
+		writeFrame(t, w, start.Add(time.Duration(i)*time.Second), &proto_webview.View{
+			UiResources: []*v1alpha1.UIResource{uiResource(fmt.Sprintf("r%03d", i), v1alpha1.RuntimeStatusOK)},
+			LogList: &proto_webview.LogList{
+				Segments:     []*proto_webview.LogSegment{{SpanId: "fe", Text: fmt.Sprintf("line %d", i)}},
+				ToCheckpoint: int32(i + 1),
+			},
+		})
+	}
+	recording, err := ReadRecording(buf)
+	require.NoError(t, err)
+	require.Len(t, recording.keyframes, 3)
+
+	for _, i := range []int{0, keyframeInterval - 1, keyframeInterval, keyframeInterval + 1, frames - 1} {
+		view := recording.ViewAt(i)
+		assert.Len(t, view.UiResources, i+1)
+		assert.Len(t, view.LogList.Segments, i+1)
+		assert.Equal(t, fmt.Sprintf("line %d", i), view.LogList.Segments[i].Text)
+		assert.Equal(t, int32(i+1), view.LogList.ToCheckpoint)
+	}
+
+	// Building a view from a keyframe must not change the keyframe.
+	assert.Len(t, recording.ViewAt(keyframeInterval).UiResources, keyframeInterval+1)
+}
+
+func TestReadRecordingErrors(t *testing.T) {
+	_, err := ReadRecording(bytes.NewBufferString(""))
+	assert.EqualError(t, err, "recording is empty")
+
+	_, err = ReadRecording(bytes.NewBufferString("{\"time\":\"2021-01-01T00:00:00Z\",\"view\":{}}\nnot json\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "reading recording line 2")
+	}
+}
+
+func TestReplayServer(t *testing.T) {
+	rs := &replayServer{recording: testRecording(t)}
+	h := rs.handler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/replay", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var frames replayFrames
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &frames))
+	require.Len(t, frames.Frames, 3)
+	assert.Equal(t, "1", frames.Frames[1].ID)
+	assert.Equal(t, start.Add(time.Second), frames.Frames[1].Time.UTC())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/snapshot/1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	snapshot := &proto_webview.Snapshot{}
+	require.NoError(t, (&runtime.JSONPb{}).Unmarshal(w.Body.Bytes(), snapshot))
+	assert.Equal(t, []string{"be", "fe"}, resourceNames(snapshot.View))
+	assert.Equal(t, start.Add(time.Second), snapshot.CreatedAt.AsTime())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/snapshot/local", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	snapshot = &proto_webview.Snapshot{}
+	require.NoError(t, (&runtime.JSONPb{}).Unmarshal(w.Body.Bytes(), snapshot))
+	assert.Equal(t, []string{"fe"}, resourceNames(snapshot.View))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/snapshot/3", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/replay/objects/1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var objects replayObjects
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &objects))
+	require.Len(t, objects.Objects, 2)
+	assert.Equal(t, "fe-serve", objects.Objects[0].Name)
+}
+
+// A session where fe crashes, and then be is deleted.
+// Its Cmd starts before the first view, and is deleted at the end.
+func testRecording(t *testing.T) *Recording {
+	buf := &bytes.Buffer{}
+	w := NewRecordingWriter(buf)
+
+	require.NoError(t, w.WriteObject(start, RecordedObject{
+		Kind:   "Cmd",
+		Name:   "fe-serve",
+		Object: json.RawMessage(`{"kind":"Cmd","metadata":{"name":"fe-serve"},"status":{"running":{}}}`),
+	}))
+
+	writeFrame(t, w, start, &proto_webview.View{
+		UiSession: uiSession("v1"),
+		UiResources: []*v1alpha1.UIResource{
+			uiResource("fe", v1alpha1.RuntimeStatusPending),
+			uiResource("be", v1alpha1.RuntimeStatusOK),
+		},
+		LogList: &proto_webview.LogList{
+			Spans:        map[string]*proto_webview.LogSpan{"fe": {ManifestName: "fe"}},
+			Segments:     []*proto_webview.LogSegment{{SpanId: "fe", Text: "fe starting"}},
+			ToCheckpoint: 1,
+		},
+	})
+	writeFrame(t, w, start.Add(time.Second), &proto_webview.View{
+		UiResources: []*v1alpha1.UIResource{
+			uiResource("fe", v1alpha1.RuntimeStatusError),
+		},
+		LogList: &proto_webview.LogList{
+			Spans:          map[string]*proto_webview.LogSpan{"fe": {ManifestName: "fe"}},
+			Segments:       []*proto_webview.LogSegment{{SpanId: "fe", Text: "fe crashed"}},
+			FromCheckpoint: 1,
+			ToCheckpoint:   2,
+		},
+	})
+
+	require.NoError(t, w.WriteObject(start.Add(time.Second), RecordedObject{
+		Kind:   "FileWatch",
+		Name:   "fe-watch",
+		Object: json.RawMessage(`{"kind":"FileWatch","metadata":{"name":"fe-watch"}}`),
+	}))
+
+	deleted := uiResource("be", v1alpha1.RuntimeStatusOK)
+	now := metav1.NewTime(start)
+	deleted.DeletionTimestamp = &now
+	writeFrame(t, w, start.Add(2*time.Second), &proto_webview.View{
+		UiSession:   uiSession("v2"),
+		UiResources: []*v1alpha1.UIResource{deleted},
+	})
+	require.NoError(t, w.WriteObject(start.Add(2*time.Second), RecordedObject{Kind: "Cmd", Name: "fe-serve", Deleted: true}))
+
+	recording, err := ReadRecording(buf)
+	require.NoError(t, err)
+	return recording
+}
+
+func writeFrame(t *testing.T, w *RecordingWriter, ts time.Time, view *proto_webview.View) {
+	b, err := (&runtime.JSONPb{}).Marshal(view)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteFrame(ts, b))
+}
+
+func uiSession(version string) *v1alpha1.UISession {
+	return &v1alpha1.UISession{
+		ObjectMeta: metav1.ObjectMeta{Name: "Tiltfile"},
+		Status: v1alpha1.UISessionStatus{
+			RunningTiltBuild: v1alpha1.TiltBuild{Version: version},
+		},
+	}
+}
+
+func uiResource(name string, status v1alpha1.RuntimeStatus) *v1alpha1.UIResource {
+	return &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.UIResourceStatus{RuntimeStatus: status},
+	}
+}
+
+func resourceNames(view *proto_webview.View) []string {
+	var result []string
+	for _, r := range view.UiResources {
+		result = append(result, r.Name)
+	}
+	return result
+}
+
+func logTexts(view *proto_webview.View) []string {
+	var result []string
+	for _, s := range view.LogList.Segments {
+		result = append(result, s.Text)
+	}
+	return result
+}
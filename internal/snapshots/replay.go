@@ -0,0 +1,166 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/assets"
+	"github.com/tilt-dev/tilt/pkg/model"
+	pkgsnapshot "github.com/tilt-dev/tilt/pkg/snapshot"
+)
+
+// Serves a session recording in the read-only snapshot UI.
+//
+// Each frame of the recording is a snapshot, with the frame index as its
+// ID. The "local" snapshot is the end of the session.
+//
+// The apiserver objects after each frame are served as JSON at
+// /api/replay/objects/ID, for digging into what the web UI doesn't show.
+func ServeReplay(ctx context.Context, l net.Listener, recording *Recording) error {
+	last, err := recording.SnapshotAt(len(recording.Frames) - 1)
+	if err != nil {
+		return err
+	}
+
+	var snapshot map[string]interface{}
+	err = json.Unmarshal(last, &snapshot)
+	if err != nil {
+		return err
+	}
+
+	version, err := pkgsnapshot.GetVersionFromSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	rs, err := newReplayServer(recording, version)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = rs.server.Shutdown(context.Background())
+	}()
+
+	err = rs.serve(l)
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+type replayServer struct {
+	assetServer assets.Server
+	recording   *Recording
+	server      http.Server
+}
+
+func newReplayServer(recording *Recording, version string) (*replayServer, error) {
+	result := &replayServer{recording: recording}
+
+	s, err := assets.NewProdServer(assets.ProdAssetBucket, model.WebVersion(version))
+	if err != nil {
+		return result, err
+	}
+	result.assetServer = s
+
+	return result, nil
+}
+
+func (rs *replayServer) serve(l net.Listener) error {
+	rs.server = http.Server{Handler: rs.handler()}
+	return rs.server.Serve(l)
+}
+
+func (rs *replayServer) handler() http.Handler {
+	m := http.NewServeMux()
+
+	m.HandleFunc("/api/replay", rs.framesJSONHandler)
+	m.HandleFunc("/api/replay/objects/", rs.objectsJSONHandler)
+	m.HandleFunc("/api/snapshot/", rs.snapshotJSONHandler)
+	if rs.assetServer != nil {
+		m.HandleFunc("/", rs.assetServer.ServeHTTP)
+	}
+	return m
+}
+
+type replayFrame struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+}
+
+type replayFrames struct {
+	Frames []replayFrame `json:"frames"`
+}
+
+// Lists the frames of the recording, so the UI can step through them.
+func (rs *replayServer) framesJSONHandler(w http.ResponseWriter, req *http.Request) {
+	result := replayFrames{}
+	for i, frame := range rs.recording.Frames {
+		result.Frames = append(result.Frames, replayFrame{ID: strconv.Itoa(i), Time: frame.Time})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(result)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error writing frames to http response: %v", err.Error()), http.StatusInternalServerError)
+	}
+}
+
+type replayObjects struct {
+	Objects []RecordedObject `json:"objects"`
+}
+
+func (rs *replayServer) objectsJSONHandler(w http.ResponseWriter, req *http.Request) {
+	i, ok := rs.frameIndex(w, strings.TrimPrefix(req.URL.Path, "/api/replay/objects/"))
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(replayObjects{Objects: rs.recording.ObjectsAt(i)})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error writing objects to http response: %v", err.Error()), http.StatusInternalServerError)
+	}
+}
+
+func (rs *replayServer) snapshotJSONHandler(w http.ResponseWriter, req *http.Request) {
+	i, ok := rs.frameIndex(w, strings.TrimPrefix(req.URL.Path, "/api/snapshot/"))
+	if !ok {
+		return
+	}
+
+	snapshot, err := rs.recording.SnapshotAt(i)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error building snapshot: %v", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(snapshot)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error writing snapshot to http response: %v", err.Error()), http.StatusInternalServerError)
+	}
+}
+
+// Parses a frame ID, where "local" is the last frame.
+// Writes a 404 if there's no such frame.
+func (rs *replayServer) frameIndex(w http.ResponseWriter, id string) (int, bool) {
+	if id == "local" {
+		return len(rs.recording.Frames) - 1, true
+	}
+
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 || i >= len(rs.recording.Frames) {
+		http.Error(w, fmt.Sprintf("no frame %q in recording", id), http.StatusNotFound)
+		return 0, false
+	}
+	return i, true
+}
@@ -62,6 +62,15 @@ func NewImageBuildResult(id model.TargetID, localRef, clusterRef reference.Named
 	}
 }
 
+// For image targets whose result we already know, like from a
+// checkpoint of an earlier session.
+func NewImageBuildResultFromStatus(id model.TargetID, status v1alpha1.ImageMapStatus) ImageBuildResult {
+	return ImageBuildResult{
+		id:             id,
+		ImageMapStatus: status,
+	}
+}
+
 // When localRef == ClusterRef
 func NewImageBuildResultSingleRef(id model.TargetID, ref reference.NamedTagged) ImageBuildResult {
 	return NewImageBuildResult(id, ref, ref)
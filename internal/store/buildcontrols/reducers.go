@@ -251,8 +251,8 @@ func HandleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 		lrs := ms.LocalRuntimeState()
 		if err == nil {
 			lt := mt.Manifest.LocalTarget()
-			if lt.ReadinessProbe == nil {
-				// only update the succeeded time if there's no readiness probe
+			if lt.ReadinessProbe == nil && lt.ServeReadyRegex == "" {
+				// only update the succeeded time if there's no readiness check
 				lrs.LastReadyOrSucceededTime = time.Now()
 			}
 			if lt.ServeCmd.Empty() {
@@ -82,6 +82,14 @@ type EngineState struct {
 	SuggestedTiltVersion string
 	VersionSettings      model.VersionSettings
 
+	// How resource groups are displayed in the UI.
+	GroupSettings model.GroupSettings
+
+	// Resource groups that the user collapsed in the UI, by path.
+	// Kept here rather than in the browser, so that every tab
+	// shares it and it survives reloads.
+	CollapsedGroups map[string]bool
+
 	// Analytics Info
 	AnalyticsEnvOpt        analytics.Opt
 	AnalyticsUserOpt       analytics.Opt // changes to this field will propagate into the TiltAnalytics subscriber + we'll record them as user choice
@@ -129,6 +137,7 @@ type EngineState struct {
 	ImageMaps             map[string]*v1alpha1.ImageMap             `json:"-"`
 	DockerImages          map[string]*v1alpha1.DockerImage          `json:"-"`
 	CmdImages             map[string]*v1alpha1.CmdImage             `json:"-"`
+	HealthProbes          map[string]*v1alpha1.HealthProbe          `json:"-"`
 }
 
 func (e *EngineState) MainTiltfilePath() string {
@@ -215,6 +224,32 @@ func (e *EngineState) UpsertManifestTarget(mt *ManifestTarget) {
 		e.ManifestDefinitionOrder = append(e.ManifestDefinitionOrder, mn)
 	}
 	e.ManifestTargets[mn] = mt
+	e.syncHealthProbes(mt.State)
+}
+
+// Copies the statuses of the HealthProbes into the states of the
+// manifests they probe.
+func (e *EngineState) SyncHealthProbes() {
+	for _, mt := range e.ManifestTargets {
+		e.syncHealthProbes(mt.State)
+	}
+}
+
+func (e *EngineState) syncHealthProbes(ms *ManifestState) {
+	if ms == nil {
+		return
+	}
+	var probes map[string]v1alpha1.HealthProbeStatus
+	for name, hp := range e.HealthProbes {
+		if hp.Spec.Resource != ms.Name.String() {
+			continue
+		}
+		if probes == nil {
+			probes = make(map[string]v1alpha1.HealthProbeStatus)
+		}
+		probes[name] = hp.Status
+	}
+	ms.HealthProbes = probes
 }
 
 func (e *EngineState) RemoveManifestTarget(mn model.ManifestName) {
@@ -490,6 +525,9 @@ type ManifestState struct {
 	TriggerReason model.BuildReason
 
 	DisableState v1alpha1.DisableState
+
+	// The statuses of the HealthProbes for this manifest, by probe name.
+	HealthProbes map[string]v1alpha1.HealthProbeStatus
 }
 
 func NewState() *EngineState {
@@ -536,6 +574,7 @@ func NewState() *EngineState {
 	ret.ImageMaps = make(map[string]*v1alpha1.ImageMap)
 	ret.DockerImages = make(map[string]*v1alpha1.DockerImage)
 	ret.CmdImages = make(map[string]*v1alpha1.CmdImage)
+	ret.HealthProbes = make(map[string]*v1alpha1.HealthProbe)
 
 	return ret
 }
@@ -819,9 +858,48 @@ func (ms *ManifestState) RuntimeStatus(triggerMode model.TriggerMode) v1alpha1.R
 			runStatus = v1alpha1.RuntimeStatusPending
 		}
 	}
+	return ms.healthProbeRuntimeStatus(runStatus)
+}
+
+// Folds the HealthProbes for this manifest into its runtime status.
+//
+// A probe that hasn't passed yet keeps the resource pending, and a probe
+// that passed before but is failing now is an error. A resource with no
+// runtime of its own (like a local resource without a serve_cmd) is OK
+// once all its probes pass.
+func (ms *ManifestState) healthProbeRuntimeStatus(runStatus v1alpha1.RuntimeStatus) v1alpha1.RuntimeStatus {
+	if len(ms.HealthProbes) == 0 ||
+		runStatus == v1alpha1.RuntimeStatusError ||
+		runStatus == v1alpha1.RuntimeStatusPending ||
+		runStatus == v1alpha1.RuntimeStatusNone {
+		return runStatus
+	}
+
+	for _, hp := range ms.HealthProbes {
+		if hp.Ready {
+			continue
+		}
+		if !hp.LastReadyTime.IsZero() {
+			return v1alpha1.RuntimeStatusError
+		}
+		runStatus = v1alpha1.RuntimeStatusPending
+	}
+	if runStatus == v1alpha1.RuntimeStatusNotApplicable || runStatus == v1alpha1.RuntimeStatusUnknown {
+		return v1alpha1.RuntimeStatusOK
+	}
 	return runStatus
 }
 
+// Whether every HealthProbe for this manifest has passed at least once.
+func (ms *ManifestState) HealthProbesEverReady() bool {
+	for _, hp := range ms.HealthProbes {
+		if hp.LastReadyTime.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
 var _ model.TargetStatus = &ManifestState{}
 
 func ManifestTargetEndpoints(mt *ManifestTarget) (endpoints []model.Link) {
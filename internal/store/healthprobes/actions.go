@@ -0,0 +1,23 @@
+package healthprobes
+
+import "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+
+type HealthProbeUpsertAction struct {
+	HealthProbe *v1alpha1.HealthProbe
+}
+
+func NewHealthProbeUpsertAction(obj *v1alpha1.HealthProbe) HealthProbeUpsertAction {
+	return HealthProbeUpsertAction{HealthProbe: obj}
+}
+
+func (HealthProbeUpsertAction) Action() {}
+
+type HealthProbeDeleteAction struct {
+	Name string
+}
+
+func NewHealthProbeDeleteAction(n string) HealthProbeDeleteAction {
+	return HealthProbeDeleteAction{Name: n}
+}
+
+func (HealthProbeDeleteAction) Action() {}
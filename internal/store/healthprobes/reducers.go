@@ -0,0 +1,17 @@
+package healthprobes
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+)
+
+func HandleHealthProbeUpsertAction(state *store.EngineState, action HealthProbeUpsertAction) {
+	obj := action.HealthProbe
+	n := obj.Name
+	state.HealthProbes[n] = obj
+	state.SyncHealthProbes()
+}
+
+func HandleHealthProbeDeleteAction(state *store.EngineState, action HealthProbeDeleteAction) {
+	delete(state.HealthProbes, action.Name)
+	state.SyncHealthProbes()
+}
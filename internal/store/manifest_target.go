@@ -68,7 +68,7 @@ func (mt *ManifestTarget) UpdateStatus() v1alpha1.UpdateStatus {
 // Compute the runtime status for the whole Manifest.
 func (mt *ManifestTarget) RuntimeStatus() v1alpha1.RuntimeStatus {
 	m := mt.Manifest
-	if m.IsLocal() && m.LocalTarget().ServeCmd.Empty() {
+	if m.IsLocal() && m.LocalTarget().ServeCmd.Empty() && len(mt.State.HealthProbes) == 0 {
 		return v1alpha1.RuntimeStatusNotApplicable
 	}
 	return mt.State.RuntimeStatus(m.TriggerMode)
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -44,3 +46,60 @@ func TestK8sRuntimeStatus(t *testing.T) {
 	assert.Equal(t, v1alpha1.UpdateStatusNone, mt.UpdateStatus())
 	assert.Equal(t, v1alpha1.RuntimeStatusNone, mt.RuntimeStatus())
 }
+
+func TestLocalHealthProbeRuntimeStatus(t *testing.T) {
+	m := model.Manifest{Name: "db-migrate"}.WithDeployTarget(
+		model.NewLocalTarget("db-migrate", model.ToHostCmd("make migrate"), model.Cmd{}, nil))
+	state := NewState()
+	mt := NewManifestTarget(m)
+	state.UpsertManifestTarget(mt)
+	mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	assert.Equal(t, v1alpha1.RuntimeStatusNotApplicable, mt.RuntimeStatus())
+
+	hp := &v1alpha1.HealthProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-health"},
+		Spec:       v1alpha1.HealthProbeSpec{Resource: "db-migrate"},
+	}
+	state.HealthProbes[hp.Name] = hp
+	state.SyncHealthProbes()
+	assert.Equal(t, v1alpha1.RuntimeStatusPending, mt.RuntimeStatus())
+	assert.False(t, mt.State.HealthProbesEverReady())
+
+	hp.Status = v1alpha1.HealthProbeStatus{Ready: true, LastReadyTime: apis.NowMicro()}
+	state.SyncHealthProbes()
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, mt.RuntimeStatus())
+	assert.True(t, mt.State.HealthProbesEverReady())
+
+	hp.Status.Ready = false
+	state.SyncHealthProbes()
+	assert.Equal(t, v1alpha1.RuntimeStatusError, mt.RuntimeStatus())
+
+	delete(state.HealthProbes, hp.Name)
+	state.SyncHealthProbes()
+	assert.Equal(t, v1alpha1.RuntimeStatusNotApplicable, mt.RuntimeStatus())
+}
+
+func TestK8sHealthProbeRuntimeStatus(t *testing.T) {
+	m := model.Manifest{Name: "fe"}.WithDeployTarget(model.NewK8sTargetForTesting(""))
+	state := NewState()
+	state.HealthProbes["fe-health"] = &v1alpha1.HealthProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe-health"},
+		Spec:       v1alpha1.HealthProbeSpec{Resource: "fe"},
+	}
+
+	// Probes for a resource apply to it as soon as it's added.
+	mt := NewManifestTarget(m)
+	state.UpsertManifestTarget(mt)
+	assert.Len(t, mt.State.HealthProbes, 1)
+
+	mt.State.RuntimeState = K8sRuntimeState{
+		HasEverDeployedSuccessfully: true,
+		PodReadinessMode:            model.PodReadinessIgnore,
+	}
+	assert.Equal(t, v1alpha1.RuntimeStatusPending, mt.RuntimeStatus())
+
+	state.HealthProbes["fe-health"].Status.Ready = true
+	state.HealthProbes["fe-health"].Status.LastReadyTime = apis.NowMicro()
+	state.SyncHealthProbes()
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, mt.RuntimeStatus())
+}
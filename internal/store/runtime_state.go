@@ -47,6 +47,13 @@ type LocalRuntimeState struct {
 	SpanID                   model.LogSpanID
 	LastReadyOrSucceededTime time.Time
 	Ready                    bool
+
+	// The last time the user asked to restart the serve_cmd
+	// without running the update.
+	LastRestartTime time.Time
+
+	// The resources that the running serve_cmd uses, if it's been measured.
+	Usage *v1alpha1.CmdResourceUsage
 }
 
 var _ RuntimeState = LocalRuntimeState{}
@@ -83,6 +83,19 @@ func NewStoreWithFakeReducer() (st *Store, getActions func() []Action) {
 	return NewStore(reducer, false), getActions
 }
 
+// Counts for runtime diagnostics.
+type Stats struct {
+	Subscribers   int `json:"subscribers"`
+	QueuedActions int `json:"queuedActions"`
+}
+
+func (s *Store) Stats() Stats {
+	return Stats{
+		Subscribers:   s.subscribers.len(),
+		QueuedActions: s.actionQueue.len(),
+	}
+}
+
 func (s *Store) StateMutex() *sync.RWMutex {
 	return &s.stateMu
 }
@@ -263,6 +276,12 @@ func (q *actionQueue) add(action Action) {
 	q.actions = append(q.actions, action)
 }
 
+func (q *actionQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.actions)
+}
+
 func (q *actionQueue) drain() []Action {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -94,6 +94,12 @@ func (l *subscriberList) Remove(ctx context.Context, s Subscriber) error {
 	return fmt.Errorf("Subscriber not found: %T: %+v", s, s)
 }
 
+func (l *subscriberList) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.subscribers)
+}
+
 func (l *subscriberList) SetUp(ctx context.Context, st RStore) error {
 	l.mu.Lock()
 	subscribers := append([]*subscriberEntry{}, l.subscribers...)
@@ -243,7 +243,7 @@ def docker_build(ref: str,
   """
   pass
 
-def docker_compose(configPaths: Union[str, Blob, List[Union[str, Blob]]], env_file: str = None, project_name: str = "") -> None:
+def docker_compose(configPaths: Union[str, Blob, List[Union[str, Blob]]], env_file: str = None, project_name: str = "", profiles: Union[str, List[str]] = [], down_volumes: bool = False, down_remove_orphans: bool = False, down_timeout_secs: int = None, docker_host: str = "", docker_context: str = "") -> None:
   """Run containers with Docker Compose.
 
   Tilt will read your Docker Compose YAML and separate out the services.
@@ -255,6 +255,8 @@ def docker_compose(configPaths: Union[str, Blob, List[Union[str, Blob]]], env_fi
 
   Tilt will watch your Docker Compose YAML and reload if it changes.
 
+  Tilt runs the project with the Docker Compose CLI if it's installed, and otherwise talks to the Docker Engine API directly, so the CLI isn't required. To pick one, set the ``TILT_DOCKER_COMPOSE_BACKEND`` environment variable before ``tilt up``: ``cli`` always uses the CLI, and ``native`` never does.
+
   For more info, see `the guide to Tilt with Docker Compose <docker_compose.html>`_.
 
   Examples:
@@ -275,12 +277,33 @@ def docker_compose(configPaths: Union[str, Blob, List[Union[str, Blob]]], env_fi
     services = {'app': {'environment': {'DEBUG': 'true'}}}
     docker_compose(['docker-compose.yml', encode_yaml({'services': services})])
 
+    # Only the services without a profile, plus the ones in the 'debug' profile
+    docker_compose('./docker-compose.yml', profiles=['debug'])
+
+    # Run the project on a remote Docker daemon
+    docker_compose('./docker-compose.yml', docker_host='ssh://me@build-box')
+
   Args:
     configPaths: Path(s) and/or Blob(s) to Docker Compose yaml files or content.
     env_file: Path to env file to use; defaults to ``.env`` in current directory.
     project_name: The Docker Compose project name. If unspecified, uses either the
       name of the directory containing the first compose file, or, in the case of
       inline YAML, the current Tiltfile's directory name.
+    profiles: Docker Compose profiles to enable. Services without a profile are
+      always enabled. If unspecified, services from every profile are enabled.
+      Calling ``docker_compose()`` again for the same project adds to its profiles.
+    down_volumes: Whether ``tilt down`` deletes the project's volumes, like ``docker compose down --volumes``.
+      ``tilt down --delete-volumes`` turns this on for one run.
+    down_remove_orphans: Whether ``tilt down`` removes the containers of services that aren't in the project anymore,
+      like ``docker compose down --remove-orphans``. ``tilt down --remove-orphans`` turns this on for one run.
+    down_timeout_secs: How long ``tilt down`` waits for containers to stop before killing them.
+      Defaults to Docker Compose's default of 10 seconds. ``tilt down --stop-timeout`` overrides this.
+    docker_host: The Docker daemon to run the project on, like ``DOCKER_HOST`` (e.g., ``tcp://host:2376``
+      or ``ssh://user@host``). If unspecified, uses the same Docker daemon as the rest of Tilt.
+      Images that Tilt builds with ``docker_build()`` are still built on Tilt's Docker daemon,
+      so services that use them need a daemon that can pull them.
+    docker_context: The Docker context to run the project on, like ``DOCKER_CONTEXT``.
+      Can't be set together with ``docker_host``.
   """
 
 
@@ -432,7 +455,11 @@ def dc_resource(name: str,
                 labels: Union[str, List[str]] = [],
                 auto_init: bool = True,
                 project_name: str = "",
-                new_name: str = "") -> None:
+                new_name: str = "",
+                with_deps: bool = False,
+                force_recreate: bool = False,
+                pull: str = "",
+                build_args: Dict[str, str] = {}) -> None:
   """Configures the Docker Compose resource of the given name. Note: Tilt does an amount of resource configuration
   for you(for more info, see `Tiltfile Concepts: Resources <tiltfile_concepts.html#resources>`_); you only need
   to invoke this function if you want to configure your resource beyond what Tilt does automatically.
@@ -445,16 +472,61 @@ def dc_resource(name: str,
       See the `Resource Dependencies docs <resource_dependencies.html>`_.
     links: one or more links to be associated with this resource in the UI. For more info, see
       `Accessing Resource Endpoints <accessing_resource_endpoints.html#arbitrary-links>`_.
-    labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
+    labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. A label may also be a path of nested groups separated by ``/`` (e.g. ``payments/api``), where each group follows the same rules. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
     auto_init: whether this resource runs on ``tilt up``. Defaults to ``True``. For more info, see the
       `Manual Update Control docs <manual_update_control.html>`_.
     project_name: The Docker Compose project name to match the corresponding project loaded by
       ``docker_compose``, if necessary for disambiguation.
     new_name: If non-empty, will be used as the new name for this resource.
+    with_deps: If ``True``, Docker Compose also starts the services this one ``depends_on``.
+      By default, Tilt starts each service on its own (``--no-deps``).
+    force_recreate: If ``True``, recreates the service's container on every update, even if
+      its config and image haven't changed.
+    pull: When to pull the service's image: ``"always"``, ``"missing"``, or ``"never"``.
+      Overrides the service's ``pull_policy``.
+    build_args: Build args for the service's image, merged over the ``args`` in its ``build``
+      section. Only used when Docker Compose builds the image.
   """
 
   pass
 
+class DockerComposeService:
+  """A service loaded by :meth:`docker_compose`. Returned by :meth:`dc_services`.
+
+  Reading an attribute returns a copy, which can't be modified. To change the
+  service, call one of its methods. Changes are saved to a Docker Compose
+  override file, so they follow Compose's usual merge rules (e.g., ``ports``
+  are added to the existing ports, not replaced).
+
+  Attributes:
+    name (str): The name of the Tilt resource.
+    project (str): The Docker Compose project name.
+    image (str): The service's image, if it has one.
+    labels (Dict[str, str]): The service's container labels.
+    content (Dict[str, Any]): The whole service, as loaded by Docker Compose.
+  """
+  def patch(self, patch: Dict[str, Any]) -> None:
+    """Merges ``patch`` into the service. Fields can't be removed."""
+    pass
+
+  def set_label(self, key: str, value: str) -> None:
+    """Adds a container label to the service."""
+    pass
+
+def dc_services(project_name: str = "") -> List[DockerComposeService]:
+  """Returns the services loaded by :meth:`docker_compose` so far, so that you can read or modify them.
+
+  For example, to label every service's containers: ::
+
+    docker_compose('docker-compose.yml')
+    for svc in dc_services():
+      svc.set_label('team', 'web')
+
+  Args:
+    project_name: If non-empty, only return services in this Docker Compose project.
+  """
+  pass
+
 def k8s_resource(workload: str = "", new_name: str = "",
                  port_forwards: Union[str, int, PortForward, List[Union[str, int, PortForward]]] = [],
                  extra_pod_selectors: Union[Dict[str, str], List[Dict[str, str]]] = [],
@@ -561,7 +633,7 @@ def k8s_resource(workload: str = "", new_name: str = "",
       thinks a resource has pods.
     links: one or more links to be associated with this resource in the UI. For more info, see
       `Accessing Resource Endpoints <accessing_resource_endpoints.html#arbitrary-links>`_.
-    labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
+    labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. A label may also be a path of nested groups separated by ``/`` (e.g. ``payments/api``), where each group follows the same rules. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
     discovery_strategy: Possible values: '', 'default', 'selectors-only'. When '' or 'default', Tilt both uses `extra_pod_selectors` and traces k8s owner references to identify this resource's pods. When 'selectors-only', Tilt uses only `extra_pod_selectors`.
   """
   pass
@@ -606,6 +678,58 @@ def filter_yaml(yaml: Union[str, List[str], Blob], labels: dict=None, name: str=
   """
   pass
 
+class K8sObject:
+  """A Kubernetes object registered with :meth:`k8s_yaml`. Returned by :meth:`k8s_objects`.
+
+  Reading an attribute returns a copy, which can't be modified. To change the
+  object, call one of its methods.
+
+  Attributes:
+    api_version (str): The object's apiVersion (e.g., ``"apps/v1"``)
+    kind (str): The object's kind (e.g., ``"Deployment"``)
+    name (str): The object's name
+    namespace (str): The object's namespace, or ``""`` if it doesn't have one
+    labels (Dict[str, str]): The object's labels
+    annotations (Dict[str, str]): The object's annotations
+    content (Dict[str, Any]): The whole object, without its status
+  """
+  def patch(self, patch: Dict[str, Any]) -> None:
+    """Merges ``patch`` into the object, following `JSON Merge Patch <https://datatracker.ietf.org/doc/html/rfc7386>`_
+    rules: dicts are merged, ``None`` removes a field, and everything else (including lists) is replaced.
+
+    The object's apiVersion, kind, name, and namespace can't be changed.
+    """
+    pass
+
+  def set_label(self, key: str, value: str) -> None:
+    """Adds a label to the object's metadata."""
+    pass
+
+  def set_annotation(self, key: str, value: str) -> None:
+    """Adds an annotation to the object's metadata."""
+    pass
+
+def k8s_objects(labels: dict=None, name: str=None, namespace: str=None, kind: str=None, api_version: str=None) -> List[K8sObject]:
+  """Returns the Kubernetes objects registered with :meth:`k8s_yaml` so far, so that you can read or modify them
+  without editing YAML strings.
+
+  Takes the same filters as :meth:`filter_yaml`.
+
+  For example, to label every Deployment: ::
+
+    k8s_yaml('all.yaml')
+    for obj in k8s_objects(kind='Deployment'):
+      obj.set_label('team', 'web')
+
+  Args:
+    labels: return only objects matching these labels.
+    name: Case-insensitive regexp specifying the ``metadata.name`` property of objects to match
+    namespace: Case-insensitive regexp specifying the ``metadata.namespace`` property of objects to match
+    kind: Case-insensitive regexp specifying the kind of objects to match (e.g. "Service", "Deployment", etc.).
+    api_version: Case-insensitive regexp specifying the apiVersion for `kind`, (e.g., "apps/v1")
+  """
+  pass
+
 def include(path: str):
   """Execute another Tiltfile.
 
@@ -671,9 +795,25 @@ def local(command: Union[str, List[str]],
           echo_off: bool = False,
           env: Dict[str, str] = {},
           dir: str = "",
-          stdin: Union[str, Blob, None] = None) -> Blob:
+          stdin: Union[str, Blob, None] = None,
+          cache_key: str = "",
+          cache_inputs: Union[str, List[str]] = []) -> Blob:
   """Runs a command on the *host* machine, waits for it to finish, and returns its stdout as a ``Blob``
 
+  Expensive commands (like ``helm dependency build`` or code generation) can
+  be cached across Tiltfile reloads by setting ``cache_key`` or ``cache_inputs``.
+  The cache is keyed on the command, its env, dir, and stdin, the ``cache_key``,
+  and the contents of the ``cache_inputs``. If all of these match a previous
+  successful run, the command is skipped and its previous stdout is returned,
+  even if that run was on another branch or from another Tiltfile in the project.
+
+  .. code-block:: python
+
+    local('helm dependency build ./chart', cache_inputs=['chart/Chart.yaml', 'chart/Chart.lock'])
+
+  Cached results are stored under ``.tilt/cache/local`` next to the main Tiltfile.
+  It's safe to delete this directory at any time.
+
   Args:
     command: Command to run. If a string, executed with ``sh -c`` on macOS/Linux, or ``cmd /S /C`` on Windows;
       if a list, will be passed to the operating system as program name and args.
@@ -685,6 +825,8 @@ def local(command: Union[str, List[str]],
     env: Environment variables to pass to the executed ``command``. Values specified here will override any variables passed to the Tilt parent process.
     dir: Working directory for ``command``. Defaults to the Tiltfile's location.
     stdin: If not ``None``, will be written to ``command``'s stdin.
+    cache_key: If set, cache the output of ``command``. Change the key (e.g., to a version string) to invalidate the cache.
+    cache_inputs: Files or directories that ``command`` depends on. If set, cache the output of ``command`` until their contents change.
   """
   pass
 
@@ -721,7 +863,7 @@ def kustomize(pathToDir: str, kustomize_bin: str = None, flags: List[str] = [])
   """
   pass
 
-def helm(pathToChartDir: str, name: str = "", namespace: str = "", values: Union[str, List[str]]=[], set: Union[str, List[str]]=[], kube_version: str = "") -> Blob:
+def helm(pathToChartDir: str, name: str = "", namespace: str = "", values: Union[str, Dict[str, Any], List[Union[str, Dict[str, Any]]]]=[], set: Union[str, List[str]]=[], kube_version: str = "") -> Blob:
   """Run `helm template <https://docs.helm.sh/helm/#helm-template>`_ on a given directory that contains a chart and return the fully rendered YAML as a Blob
   Chart directory is watched (See ``watch_file``).
 
@@ -731,7 +873,7 @@ def helm(pathToChartDir: str, name: str = "", namespace: str = "", values: Union
     pathToChartDir: Path to the directory locally (absolute, or relative to the location of the Tiltfile).
     name: The release name. Equivalent to the helm `--name` flag
     namespace: The namespace to deploy the chart to. Equivalent to the helm `--namespace` flag
-    values: Specify one or more values files (in addition to the `values.yaml` file in the chart). Equivalent to the Helm ``--values`` or ``-f`` flags (`see docs <https://helm.sh/docs/chart_template_guide/#values-files>`_).
+    values: Specify one or more values files (in addition to the `values.yaml` file in the chart). Equivalent to the Helm ``--values`` or ``-f`` flags (`see docs <https://helm.sh/docs/chart_template_guide/#values-files>`_). Each value may also be a dict of values, like those built with :meth:`helm_values.merge`.
     set: Specify one or more values. Equivalent to the Helm ``--set`` flag.
     kube_version: Specify for which kubernetes version template will be generated. Equivalent to the Helm ``--kube-version`` flag.
 """
@@ -816,7 +958,7 @@ def encode_json(obj: StructuredDataType) -> Blob:
   """
   pass
 
-def read_json(path: str, default: str = None) -> StructuredDataType:
+def read_json(path: str, default: StructuredDataType = None) -> StructuredDataType:
   """
   Reads the file at `path` and deserializes its contents as JSON
 
@@ -1120,7 +1262,23 @@ def local_resource(name: str,
                    readiness_probe: Probe = None,
                    dir: str = "",
                    serve_dir: str = "",
-                   labels: List[str] = []) -> None:
+                   labels: List[str] = [],
+                   timeout: str = None,
+                   serve_grace_period: str = None,
+                   serve_termination_signal: str = "SIGTERM",
+                   serve_pty: bool = False,
+                   serve_restart_policy: str = "never",
+                   serve_max_restarts: int = 0,
+                   serve_stdin: bool = False,
+                   serve_ready_regex: str = "",
+                   env_file: Union[str, List[str]] = [],
+                   serve_env_file: Union[str, List[str]] = [],
+                   expand_env: bool = False,
+                   serve_ssh_host: str = "",
+                   serve_ssh_key_file: str = "",
+                   serve_ssh_forward_agent: bool = False,
+                   serve_container: str = "",
+                   serve_dc_resource: str = "") -> None:
   """Configures one or more commands to run on the *host* machine (not in a remote cluster).
 
   By default, Tilt performs an update on local resources on ``tilt up`` and whenever any of their ``deps`` change.
@@ -1147,7 +1305,7 @@ def local_resource(name: str,
       `Manual Update Control docs <manual_update_control.html>`_.
     serve_cmd: Tilt will run this command on update and expect it to not exit. If a string, executed with
       ``sh -c`` on macOS/Linux, or ``cmd /S /C`` on Windows; if a list, will be passed to the operating
-      system as program name and args.
+      system as program name and args. Its stderr appears in the logs as warnings.
     cmd_bat: If non-empty and on Windows, takes precedence over ``cmd``. Ignored on other platforms.
       If a string, executed as a Windows batch command executed with ``cmd /S /C``; if a list, will be passed to
       the operating system as program name and args.
@@ -1162,7 +1320,23 @@ def local_resource(name: str,
     readiness_probe: Optional readiness probe to use for determining ``serve_cmd`` health state. Fore more info, see the :meth:`probe` function.
     dir: Working directory for ``cmd``. Defaults to the Tiltfile directory.
     serve_dir: Working directory for ``serve_cmd``. Defaults to the Tiltfile directory.
-    labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
+    labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. A label may also be a path of nested groups separated by ``/`` (e.g. ``payments/api``), where each group follows the same rules. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
+    timeout: How long ``cmd`` may run before Tilt stops it and fails the update, like ``"10m"``, so that a hung script doesn't block the resource forever. Tilt stops it the same way as ``serve_cmd``, by sending ``SIGTERM`` and then ``SIGKILL`` 30s later. Defaults to no timeout.
+    serve_grace_period: How long to wait for ``serve_cmd`` to exit when Tilt stops or restarts it, before killing it, like ``"2m"``. Defaults to ``"30s"``. Use ``"0s"`` to kill it immediately.
+    serve_termination_signal: The signal that asks ``serve_cmd`` to stop, for servers that shut down cleanly on a different signal (like nginx on ``"SIGQUIT"``, or many Node dev servers on ``"SIGINT"``). One of ``SIGTERM``, ``SIGINT``, ``SIGQUIT``, ``SIGHUP``, ``SIGUSR1``, or ``SIGUSR2``. If the process hasn't exited after ``serve_grace_period``, Tilt kills it with ``SIGKILL``. Ignored on Windows.
+    serve_pty: If ``True``, runs ``serve_cmd`` in a pseudo-terminal, so that tools which turn off colors and progress output when they aren't writing to a terminal (like jest, webpack, or cargo) print them in Tilt's logs. The terminal is the size of the one Tilt runs in, or 80x24, and ``serve_cmd``'s stderr is merged into its stdout. Ignored on Windows.
+    serve_restart_policy: Whether to start ``serve_cmd`` again when it exits, so that a crashing dev server comes back on its own. One of ``"always"``, ``"on-failure"`` (only when it exits with a non-zero exit code), or ``"never"``. Tilt waits 1s before the first restart, and doubles the wait each time, up to 1m. The count starts over whenever Tilt restarts ``serve_cmd`` for an update.
+    serve_max_restarts: The most times to restart ``serve_cmd`` under ``serve_restart_policy`` before giving up. Defaults to ``0``, which means no limit.
+    serve_stdin: If ``True``, keeps the stdin of ``serve_cmd`` open, so that you can send it input with ``tilt input``, like a key that tells a dev server to reload. Otherwise, ``serve_cmd`` reads an empty stdin.
+    serve_ready_regex: A regular expression that a line of ``serve_cmd``'s output must match before the resource is ready, like ``"listening on port [0-9]+"``, for servers that take a while to start. Colors and other ANSI escape codes are removed before matching. With a ``readiness_probe`` too, the probe starts once a line matches, so you can wait for a log line and then check a port with ``probe(tcp_socket=...)``.
+    env_file: Path, or list of paths, to files of ``KEY=VALUE`` lines (the dotenv format) to add to the environment of ``cmd``, so that it doesn't need ``sh -c 'source .env && ...'``. Values can refer to other variables, like ``${HOME}``. Later files take precedence, and ``env`` takes precedence over them all. The files are read each time ``cmd`` runs; add them to ``deps`` to run it again when they change.
+    serve_env_file: Like ``env_file``, for ``serve_cmd``.
+    expand_env: If ``True``, expands ``$VAR`` and ``${VAR}`` in ``cmd``, ``serve_cmd``, ``env``, and ``serve_env`` with Tilt's environment and the env files before running them, even without a shell (like a command given as a list, or on Windows). Unset variables expand to the empty string, and ``$$`` is a literal ``$``.
+    serve_ssh_host: If set, runs ``serve_cmd`` on this host over SSH instead of on this machine, for teams whose code lives on a shared dev box. Like ``"devbox"`` or ``"alice@devbox:2222"``; the user defaults to yours, and the port to 22. ``serve_cmd`` runs in the same directory as it would here, so the code should be at the same path on the host, and only ``serve_env`` is sent, not Tilt's environment. The host's key must be in ``~/.ssh/known_hosts``. Stopping ``serve_cmd`` stops everything it started on the host.
+    serve_ssh_key_file: A private key to log in to ``serve_ssh_host`` with. Keys in your SSH agent are tried too.
+    serve_ssh_forward_agent: If ``True``, forwards your SSH agent to ``serve_ssh_host``, so that ``serve_cmd`` can use your keys, like to pull from git.
+    serve_container: If set, runs ``serve_cmd`` in this running container, like ``docker exec``, instead of on this machine. The name or ID of a container on Tilt's Docker daemon; the container needs ``sh``. ``serve_cmd`` runs in the container's working directory, not ``serve_dir``, and gets the container's environment plus ``serve_env``. Output to stderr is kept separate, and ``serve_stdin`` and ``serve_pty`` work like they do here. Can't be combined with ``serve_ssh_host``.
+    serve_dc_resource: Like ``serve_container``, but runs ``serve_cmd`` in the current container of this Docker Compose resource, which is looked up every time ``serve_cmd`` starts. Add the resource to ``resource_deps`` so that its container is up first. Projects that set their own Docker host or context aren't supported.
   """
   pass
 
@@ -1244,14 +1418,35 @@ def struct(**kwargs) -> Any:
   """
 
 
-def secret_settings(disable_scrub: bool = False) -> None:
+def secret_settings(disable_scrub: bool = False, redact: Union[str, List[str]] = [], builtin_redactions: bool = True) -> None:
   """Configures Tilt's handling of Kubernetes Secrets. By default, Tilt scrubs
   the text of any Secrets from the logs; e.g. if Tilt applies a Secret with contents
   'mysecurepassword', Tilt redacts this string if ever it appears in the logs,
   to prevent users from accidentally sharing sensitive information in snapshots etc.
 
+  Values read with ``secrets.get()`` are always scrubbed.
+
+  Tilt also redacts text that looks like a credential, even when it doesn't know
+  the value: AWS access keys, AWS secret keys, and JWTs. Add your own patterns
+  with ``redact``:
+
+  .. code-block:: python
+
+    # Redact session tokens, but keep the name of the variable
+    secret_settings(redact=['SESSION_TOKEN=(?P<secret>\\S+)'])
+
+  Text is redacted when it's logged, before it's saved to disk, sent to a log sink,
+  or shown anywhere. A pattern only matches text that's logged in one piece.
+
+  Each call only changes the settings it's passed. Patterns from several calls add up.
+
   Args:
     disable_scrub: if True, Tilt will *not* scrub secrets from logs.
+    redact: regular expressions (in `Go syntax <https://golang.org/s/re2syntax>`_)
+      of text to redact from logs. If a pattern has a group named ``secret``,
+      only the group is redacted.
+    builtin_redactions: if False, Tilt will *not* redact text that looks like
+      a credential, unless it matches a pattern in ``redact``.
 """
 
 
@@ -1271,25 +1466,95 @@ def update_settings(
       Accepts a list of image names, or '*' to suppress warnings for all images.
 """
 
+def resource_group_settings(order: List[str]=None) -> None:
+  """Configures how resource groups are shown in the Web UI.
+
+  .. code-block:: python
+
+    local_resource('api', 'make api', labels=['payments/api'])
+    local_resource('db', 'make db', labels=['payments/storage'])
+    resource_group_settings(order=['payments', 'payments/storage'])
+
+  Args:
+    order: groups to list first, in this order. Groups that aren't listed come after, in alphabetical order.
+      The order applies among sibling groups, so nested groups are listed by their full path (e.g. ``payments/storage``).
+"""
+
+def rerun_after(duration: Union[str, int, float], resource: str='') -> None:
+  """Asks Tilt to re-execute the Tiltfile, or a resource, after a delay.
+
+  Use this for workflows that need to refresh periodically, like
+  fetching a short-lived token, instead of sleeping in a loop in a ``local_resource``.
+
+  .. code-block:: python
+
+    local_resource('registry-token', './refresh-token.sh')
+    rerun_after('45m', resource='registry-token')
+
+  Without ``resource``, the whole Tiltfile is re-executed ``duration`` after it
+  finishes loading. With ``resource``, that resource is re-triggered ``duration``
+  after each of its updates finishes.
+
+  If called more than once for the same target, the shortest duration wins.
+
+  Args:
+    duration: How long to wait. A duration string like ``'5m'``, or a number of seconds. Must be at least 1s.
+    resource: Name of the resource to re-trigger. If empty, re-executes the Tiltfile.
+  """
+
+def resource_budget(resource: str, build_time: str='', time_to_ready: str='', message: str='') -> None:
+  """Sets how long a resource is allowed to take, to keep the dev loop fast
+  as services accumulate.
+
+  .. code-block:: python
+
+    resource_budget('api', build_time='30s', time_to_ready='1m',
+                    message='ask #platform for help speeding this up')
+
+  When the resource goes over budget, the web UI shows a badge on it, and
+  Tilt sets the ``tilt_alert_firing`` metric. Budgets are :meth:`v1alpha1.alert_rule`
+  objects named ``<resource>-budget-build-time`` and ``<resource>-budget-time-to-ready``,
+  so notifications can post them with the ``AlertFiring`` event.
+
+  If called more than once for the same resource, the last call wins.
+
+  Args:
+    resource: Name of the resource.
+    build_time: The longest a build may take, like ``'30s'``.
+    time_to_ready: The longest the resource may take to become ready after a build starts, like ``'1m'``.
+    message: A message to show when the resource goes over budget, like what to do about it.
+  """
+
 def ci_settings(
     k8s_grace_period: str='',
-    timeout: str='') -> None:
+    timeout: str='',
+    readiness_timeout: str='',
+    resource_timeouts: Dict[str, str]={},
+    fail_fast: Optional[bool]=None) -> None:
   """Configures 'tilt ci' mode.
 
   Args:
     k8s_grace_period: Grace period given for Kubernetes resources to recover after they start failing. A duration string.
     timeout: Timeout for the whole CI pipeline. A duration string. Defaults to '30m'.
+    readiness_timeout: Timeout for each resource to become ready, measured from when its first update starts. A duration string.
+    resource_timeouts: Readiness timeouts for individual resources, as a dict of resource names to duration strings. Overrides ``readiness_timeout``.
+    fail_fast: If True (the default), exit as soon as a resource fails. If False, wait until every resource is ready or has failed, and report all the failures.
   """
 
-def watch_settings(ignore: Union[str, List[str]]) -> None:
+def watch_settings(ignore: Union[str, List[str]], reason: str = "") -> None:
   """Configures global watches.
 
-  May be called multiple times to add more ignore patterns.
+  May be called multiple times to add more ignore patterns. Helper functions
+  can each add their own patterns, with a reason attached for debugging.
+
+  Run ``tilt alpha ignores <path>`` to see which ignore (and reason) prevented a
+  file change from triggering an update.
 
   Args:
     ignore: A string or list of strings that should not trigger updates. Equivalent to adding
       patterns to .tiltignore. Relative patterns are evaluated relative to the current working dir.
       See `Debugging File Changes <file_changes.html>`_ for more details.
+    reason: A human-readable explanation of why these patterns are ignored.
   """
 
 
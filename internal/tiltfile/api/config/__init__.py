@@ -127,6 +127,39 @@ def set_enabled_resources(resources: List[str]) -> None:
                  all.
     """
 
+def enable_if(resources: Union[str, List[str]], condition: str) -> None:
+    """
+    Enables the given resources only when ``condition`` is true.
+
+    Use this instead of wrapping resource definitions in ``if`` blocks. The
+    resources are always registered, so they still show up in the UI and can
+    be enabled by hand, but they start disabled when the condition is false.
+
+    ``condition`` is a Starlark expression. It's evaluated after the Tiltfile
+    finishes executing (and again on every reload, e.g., after ``tilt args``),
+    with these values in scope:
+
+    - ``args``: the dict returned by the last call to :meth:`parse`
+      (empty if :meth:`parse` wasn't called).
+    - ``cluster``: a struct with the ``context``, ``namespace``, and ``product``
+      (e.g., ``kind``, ``docker-desktop``) of the current cluster.
+
+    Resources requested by name (on the command line, or with
+    :meth:`set_enabled_resources`) are enabled regardless of their conditions.
+
+    .. code-block:: python
+
+      config.define_string_list('profile')
+      config.parse()
+
+      config.enable_if(['api', 'worker'], "'backend' in args.get('profile', [])")
+      config.enable_if('registry-proxy', "cluster.product != 'kind'")
+
+    Args:
+      resources: The names of the resources the condition applies to.
+      condition: A Starlark expression. The resources are enabled if it's true.
+    """
+
 def clear_enabled_resources() -> None:
     """
     Tells Tilt that all resources should be disabled. This allows the user to manually enable only the resources they want once Tilt is running.
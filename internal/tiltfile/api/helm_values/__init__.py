@@ -0,0 +1,70 @@
+from typing import Any, Dict, List, Union
+
+def read(paths: Union[str, List[str]]) -> Dict[str, Any]:
+  """
+  Reads one or more Helm values files, and merges them in order, the same way
+  Helm merges multiple ``--values`` files.
+
+  Each file is watched, so editing it reloads the Tiltfile.
+
+  .. code-block:: python
+
+    values = helm_values.read(['./chart/values.yaml', './dev/values-dev.yaml'])
+
+  Args:
+    paths: Paths to YAML values files (absolute, or relative to the location of the Tiltfile).
+
+  Returns:
+    The merged values.
+  """
+  pass
+
+def merge(*values: Dict[str, Any]) -> Dict[str, Any]:
+  """
+  Deep-merges dicts of Helm values, with later values taking precedence.
+
+  Dicts are merged recursively. Lists and scalars are replaced. A value of
+  ``None`` deletes the key, as ``null`` does in Helm.
+
+  Unlike Helm, an override must have the same type as the value it replaces
+  (ints and floats are interchangeable), so that a mistake like setting
+  ``image.tag`` to a number fails when the Tiltfile loads.
+
+  The inputs are not modified. Pass the result to :meth:`helm` with ``values=``.
+
+  .. code-block:: python
+
+    values = helm_values.merge(
+      helm_values.read('./chart/values.yaml'),
+      {'image': {'tag': 'dev'}, 'replicaCount': 1})
+    k8s_yaml(helm('./chart', values=values))
+
+  Args:
+    values: Dicts of values to merge.
+
+  Returns:
+    The merged values.
+  """
+  pass
+
+def set(values: Dict[str, Any], path: str, value: Any) -> Dict[str, Any]:
+  """
+  Overrides a single value, like Helm's ``--set``, but with a typed value
+  instead of a string.
+
+  The override is type-checked like :meth:`merge`. The input is not modified.
+
+  .. code-block:: python
+
+    values = helm_values.set(values, 'image.tag', 'dev')
+    values = helm_values.set(values, 'ingress.hosts', ['localhost'])
+
+  Args:
+    values: The values to override.
+    path: A dotted path to the key to set (e.g., ``image.tag``).
+    value: The new value.
+
+  Returns:
+    The values, with the override applied.
+  """
+  pass
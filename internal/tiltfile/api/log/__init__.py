@@ -0,0 +1,155 @@
+from typing import Any
+
+def debug(msg: str, **fields: Any) -> None:
+  """
+  Logs a message that's only shown when Tilt runs with ``--debug``.
+
+  See :meth:`info` for how fields are logged.
+
+  Args:
+    msg: The message to log.
+    fields: Extra key/value pairs to attach to the message.
+  """
+  pass
+
+def info(msg: str, **fields: Any) -> None:
+  """
+  Logs a message in the Tiltfile's load logs.
+
+  Unlike ``print()``, structured fields are appended to the message as
+  ``key=value`` pairs, and attached to the log line so that tools reading
+  Tilt's logs can filter on them.
+
+  .. code-block:: python
+
+    log.info('deploying', service='api', replicas=3)
+    # deploying replicas=3 service=api
+
+  Args:
+    msg: The message to log.
+    fields: Extra key/value pairs to attach to the message.
+  """
+  pass
+
+def warn(msg: str, **fields: Any) -> None:
+  """
+  Logs a warning, which shows up in the Tiltfile's warnings in the UI.
+
+  See :meth:`info` for how fields are logged.
+
+  Args:
+    msg: The message to log.
+    fields: Extra key/value pairs to attach to the message.
+  """
+  pass
+
+def error(msg: str, **fields: Any) -> None:
+  """
+  Logs an error. Unlike ``fail()``, this does not stop Tiltfile execution.
+
+  See :meth:`info` for how fields are logged.
+
+  Args:
+    msg: The message to log.
+    fields: Extra key/value pairs to attach to the message.
+  """
+  pass
+
+def section(title: str) -> None:
+  """
+  Starts a new section of the Tiltfile's load logs.
+
+  Sections are rendered as headers, so that it's easy to find your way
+  around the load logs of a big Tiltfile.
+
+  .. code-block:: python
+
+    log.section('Backend services')
+
+  Args:
+    title: The section header.
+  """
+  pass
+
+def resource_level(resource: str, level: str, source: str='all') -> None:
+  """
+  Sets the minimum level of logs to keep for a resource.
+
+  Use it to turn down a chatty resource without losing the other
+  resources' logs. Logs below the level are dropped when they're logged,
+  so they don't show up in the UI, ``tilt logs``, or snapshots.
+
+  .. code-block:: python
+
+    # Only keep warnings and errors from the pods
+    log.resource_level('kafka', 'warn', source='runtime')
+
+  Tilt stores the levels in the ``<resource>-log-levels`` ConfigMap,
+  so you can change them while Tilt is running:
+
+  .. code-block:: bash
+
+    tilt edit configmap kafka-log-levels
+
+  Changes made while Tilt is running are kept until the Tiltfile
+  changes the resource's levels.
+
+  Args:
+    resource: The name of the resource.
+    level: One of ``debug``, ``verbose``, ``info``, ``warn``, or ``error``.
+    source: Which logs to filter: ``build`` (image builds and deploys),
+      ``runtime`` (pod, ``serve_cmd``, and Docker Compose logs), ``system``
+      (everything else Tilt logs about the resource, like Kubernetes events),
+      or ``all``.
+  """
+  pass
+
+def keep_repeats(resource: str) -> None:
+  """
+  Keeps every line a resource logs.
+
+  By default, when a resource logs the same line over and over (like
+  health check requests, or a client retrying a connection), Tilt keeps
+  the first one, and collapses the repeats into one line with a count:
+
+  .. code-block:: text
+
+    GET /healthz 200
+    GET /healthz 200 (×12)
+
+  While the line keeps repeating, the count is shown every 30 seconds.
+
+  Use ``keep_repeats`` for a resource where each repeated line matters.
+
+  .. code-block:: python
+
+    log.keep_repeats('load-test')
+
+  Args:
+    resource: The name of the resource.
+  """
+  pass
+
+def ansi(resource: str, mode: str) -> None:
+  """
+  Sets what to do with the ANSI escape codes (like colors) in a resource's logs.
+
+  Some tools print so many escape codes that they garble the logs when
+  they're saved to a file or sent to a log sink. The codes are handled
+  when Tilt stores the logs, so the web UI, ``tilt logs``, snapshots, and
+  log sinks all get the same text.
+
+  .. code-block:: python
+
+    # Plain text, for a tool that colors everything
+    log.ansi('webpack', 'strip')
+
+  Args:
+    resource: The name of the resource.
+    mode: One of ``preserve`` (keep the codes, the default), ``strip``
+      (remove them), or ``html`` (escape the logs as HTML, and turn colors
+      and text styles into ``<span>`` elements with classes like
+      ``ansi-red-fg`` and ``ansi-bold``, for tools that render HTML).
+      Colors outside the 16 basic ones are shown in the default color.
+  """
+  pass
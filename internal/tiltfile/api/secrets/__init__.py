@@ -0,0 +1,30 @@
+def get(key: str, provider: str = "") -> str:
+  """
+  Reads a secret from an external secret store.
+
+  The secret is always scrubbed from Tilt's logs, even if scrubbing is
+  disabled with :meth:`secret_settings`. Secrets are cached, and re-read from
+  the store when their TTL expires (by default, after 5 minutes).
+
+  Currently supports `HashiCorp Vault <https://www.vaultproject.io/>`_ KV v2,
+  configured with the same environment variables as the ``vault`` CLI
+  (``VAULT_ADDR``, ``VAULT_TOKEN``, and ``VAULT_NAMESPACE``). If ``VAULT_TOKEN``
+  is unset, reads the token written by ``vault login``. Set ``TILT_VAULT_MOUNT``
+  to read from a mount other than ``secret``.
+
+  Vault keys have the form ``path/to/secret#field``. If the field is omitted,
+  reads the ``value`` field.
+
+  .. code-block:: python
+
+    pw = secrets.get('myapp/db#password')
+    local_resource('migrate', cmd='DB_PASSWORD=%s ./migrate.sh' % pw)
+
+  Args:
+    key: The key of the secret in the store.
+    provider: The secret store to read from (e.g., ``vault``). May be omitted if Tilt only has one provider.
+
+  Returns:
+    The secret value.
+  """
+  pass
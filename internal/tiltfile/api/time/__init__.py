@@ -0,0 +1,33 @@
+def now() -> float:
+  """
+  Returns the current time, in seconds since the Unix epoch.
+
+  Useful for checking whether a cached credential has expired.
+  There's no ``time.sleep()``; to poll or refresh, use :meth:`api.rerun_after`.
+  """
+  pass
+
+def parse_duration(duration: str) -> float:
+  """
+  Parses a duration string, like ``'1m30s'``, into a number of seconds.
+
+  Accepts the same formats as Go's `time.ParseDuration <https://pkg.go.dev/time#ParseDuration>`_.
+
+  Args:
+    duration: The duration to parse.
+  """
+  pass
+
+def format(t: float) -> str:
+  """
+  Formats a time, in seconds since the Unix epoch, as an RFC 3339 timestamp in UTC.
+
+  .. code-block:: python
+
+    print(time.format(time.now()))
+    # 2021-03-01T00:00:00Z
+
+  Args:
+    t: The time to format, e.g., from :meth:`now`.
+  """
+  pass
@@ -4,6 +4,38 @@ from typing import Dict, List, Optional
 # DO NOT EDIT MANUALLY
 
 
+class CmdContainerTarget:
+  """CmdContainerTarget says which container a process runs in.
+
+The process runs in the container's working directory, not the Cmd's dir,
+and gets only the container's env and the Cmd's own env.
+
+Exactly one field must be set.
+"""
+  pass
+
+
+
+class CmdSSHTarget:
+  """CmdSSHTarget says how to reach the host that a process runs on over SSH.
+
+The process runs in the same directory as it would on this machine, so
+the code should be at the same path on the remote host. Only the Cmd's own
+env is sent, not Tilt's.
+"""
+  pass
+
+
+
+class CmdTarget:
+  """CmdTarget says where to run a process other than on this machine.
+
+Exactly one field must be set.
+"""
+  pass
+
+
+
 class ConfigMapDisableSource:
   """Specifies a ConfigMap to control a DisableSource
 """
@@ -199,6 +231,46 @@ class UITextInputSpec:
   pass
 
 
+def alert_rule(
+  name: str,
+  labels: Dict[str, str] = None,
+  annotations: Dict[str, str] = None,
+  metric: str = "",
+  duration: Optional[str] = None,
+  count: int = 0,
+  window: Optional[str] = None,
+  resources: List[str] = None,
+  message: str = "",
+):
+  """
+  AlertRule fires an alert when a resource crosses a threshold, like
+  "a build took longer than 2m" or "the pod restarted more than 3 times
+  in 10m".
+
+  Firing alerts show up as badges in the web UI. Notifications can post
+  them to a webhook with the AlertFiring and AlertResolved events.
+
+  Args:
+    name: The name in the Object metadata.
+    labels: A set of key/value pairs in the Object metadata for grouping objects.
+    annotations: A set of key/value pairs in the Object metadata for attaching data to objects.
+    metric: The measurement to check: one of "BuildDuration", "PodRestarts",
+      "BuildFailures", or "TimeToReady".
+    duration: For BuildDuration and TimeToReady, fires when the resource takes
+      longer than this, like "2m".
+    count: For PodRestarts and BuildFailures, fires when there are more than
+      this many during the window.
+    window: For PodRestarts and BuildFailures, how far back to count, like "10m".
+      
+      Defaults to 10 minutes.
+      
+    resources: The names of the resources to check.
+      
+      If empty, checks all resources.
+      
+    message: A message to show with the alert, like what to do about it.
+"""
+  pass
 def cmd(
   name: str,
   labels: Dict[str, str] = None,
@@ -210,6 +282,17 @@ def cmd(
   restart_on: Optional[RestartOnSpec] = None,
   start_on: Optional[StartOnSpec] = None,
   disable_source: Optional[DisableSource] = None,
+  grace_period: Optional[str] = None,
+  termination_signal: str = "",
+  pty: bool = False,
+  restart_policy: str = "",
+  max_restarts: int = 0,
+  timeout: Optional[str] = None,
+  stdin: bool = False,
+  ready_regex: str = "",
+  env_files: List[str] = None,
+  expand_env: bool = False,
+  target: Optional[CmdTarget] = None,
 ):
   """
   Cmd represents a process on the host machine.
@@ -254,6 +337,72 @@ def cmd(
       StartOn is satisfied.
     disable_source: Specifies how to disable this.
       
+    grace_period: How long to wait for the process to exit after asking it to stop,
+      before killing it, like "2m".
+      
+      Defaults to 30s, the same as Kubernetes. Zero kills the process
+      immediately.
+      
+    termination_signal: The signal that asks the process to stop, like SIGINT or SIGQUIT, for
+      servers that don't shut down cleanly on SIGTERM.
+      
+      Tilt sends it to the process group, then sends SIGKILL if the process
+      hasn't exited after the grace period. Defaults to SIGTERM. Ignored on
+      Windows.
+      
+    pty: Run the process in a pseudo-terminal, so that tools which disable
+      colors and progress output when they aren't writing to a terminal
+      print them anyway.
+      
+      The terminal has the size of the terminal that Tilt runs in, or 80x24.
+      Stderr is merged into stdout. Ignored on Windows.
+      
+    restart_policy: Whether to start the process again after it exits, for servers that
+      should stay up. One of "Always", "OnFailure", or "Never".
+      
+      Tilt waits before each restart, starting at 1s and doubling up to 1m.
+      Any change to the spec, or a StartOn or RestartOn trigger, resets the
+      count. Defaults to Never.
+      
+    max_restarts: The most times to restart the process under the RestartPolicy before
+      giving up. Zero means no limit.
+      
+    timeout: How long the process may run before Tilt stops it, so that a hung
+      script doesn't block its resource forever, like "10m".
+      
+      Tilt stops the process the same way as on any other stop, then reports
+      it as terminated with the reason TimedOut. Nil or zero means no
+      timeout.
+      
+    stdin: Keep the process's stdin open, so that users can send it input, like
+      a key that tells a dev server to reload.
+      
+      Without it, the process's stdin is empty.
+      
+    ready_regex: A regular expression that a line of the process's output must match
+      before the process is ready, like "listening on port [0-9]+", for
+      servers that take a while to start listening.
+      
+      Lines are matched without colors or other ANSI escape codes. With a
+      ReadinessProbe too, the probe starts once a line matches.
+      
+    env_files: Files of KEY=VALUE lines, in the dotenv format, to add to the process
+      environment, so that commands don't need a shell to source them.
+      
+      Relative paths are relative to Dir. Values can refer to variables in
+      Tilt's environment or earlier in the file, like ${HOME}. Later files,
+      then Env, take precedence.
+      
+    expand_env: Expand $VAR and ${VAR} in Args and Env before running the process,
+      with Tilt's environment, the EnvFiles, and Env, like a shell would.
+      
+      Unset variables expand to the empty string. Use $$ for a literal $.
+      
+    target: Where to run the process, for processes that shouldn't run on this
+      machine, like servers that run on a shared dev box.
+      
+      Nil runs the process on this machine.
+      
 """
   pass
 def config_map(
@@ -283,6 +432,8 @@ def extension(
   repo_name: str = "",
   repo_path: str = "",
   args: List[str] = None,
+  version: str = "",
+  sha256: str = "",
 ):
   """
   Extension defines an extension that's evaluated on Tilt startup.
@@ -305,6 +456,18 @@ def extension(
       By default, a list of arguments indicates the list of services in the tiltfile
       that should be enabled.
       
+    version: A semantic version range that the extension must satisfy,
+      like ">=1.2.0 <2.0.0" or "1.x".
+      
+      Extensions declare their version in a VERSION file next to
+      their Tiltfile.
+      
+    sha256: The SHA-256 digest of the extension directory, in hex.
+      
+      If set, the extension won't load if its contents don't match,
+      so that an extension can be pinned to code that's been reviewed.
+      The digest of a loaded extension is in its status.
+      
 """
   pass
 def extension_repo(
@@ -313,6 +476,7 @@ def extension_repo(
   annotations: Dict[str, str] = None,
   url: str = "",
   ref: str = "",
+  trusted_keys: List[str] = None,
 ):
   """
   ExtensionRepo specifies a repo or folder where a set of extensions live.
@@ -328,6 +492,13 @@ def extension_repo(
       file: URLs that point to a location on disk.
     ref: A reference to sync the repo to. If empty, Tilt will always update
       the repo to the latest version.
+    trusted_keys: Ed25519 public keys, in base64, that extensions in the repo
+      must be signed with.
+      
+      If set, each extension must have an extension.sig file next to its
+      Tiltfile that holds a base64 signature of its SHA-256 digest (in hex),
+      made with one of these keys. Extensions that aren't signed won't load.
+      
 """
   pass
 def file_watch(
@@ -350,6 +521,33 @@ def file_watch(
     ignores: Ignores are optional rules to filter out a subset of changes matched by WatchedPaths.
     disable_source: Specifies how to disable this.
       
+"""
+  pass
+def health_probe(
+  name: str,
+  labels: Dict[str, str] = None,
+  annotations: Dict[str, str] = None,
+  resource: str = "",
+  probe: Optional[Probe] = None,
+):
+  """
+  HealthProbe periodically checks the health of a resource with an HTTP,
+  TCP, or exec probe.
+
+  Unlike the readiness probe on a local_resource serve_cmd, a HealthProbe
+  can be attached to any resource, including Kubernetes and Docker Compose
+  resources. The resource isn't ready until all its probes pass, and
+  a probe that starts failing puts the resource in an error state.
+
+  Args:
+    name: The name in the Object metadata.
+    labels: A set of key/value pairs in the Object metadata for grouping objects.
+    annotations: A set of key/value pairs in the Object metadata for attaching data to objects.
+    resource: The name of the resource whose health the probe checks.
+    probe: How to check the resource's health.
+      
+      Exactly one of exec, http_get, or tcp_socket must be set.
+      
 """
   pass
 def kubernetes_apply(
@@ -494,6 +692,124 @@ def kubernetes_discovery(
       If no template is specified, the controller will stream all
       pod logs available from the apiserver.
       
+"""
+  pass
+def log_sink(
+  name: str,
+  labels: Dict[str, str] = None,
+  annotations: Dict[str, str] = None,
+  type: str = "",
+  endpoint: str = "",
+  resources: List[str] = None,
+  min_level: str = "",
+  sink_labels: Dict[str, str] = None,
+  batch_size: int = 0,
+  flush_interval: Optional[str] = None,
+  buffer_size: int = 0,
+):
+  """
+  LogSink forwards the logs of some resources to somewhere outside Tilt,
+  like a Loki server, a fluentd forward input, or a file, so that the logs
+  of many dev environments can be collected in one place.
+
+  Lines are sent in batches. If the endpoint is slow or down, lines are
+  buffered up to a limit, then the oldest are dropped, so that a stuck
+  endpoint never slows Tilt down.
+
+  Args:
+    name: The name in the Object metadata.
+    labels: A set of key/value pairs in the Object metadata for grouping objects.
+    annotations: A set of key/value pairs in the Object metadata for attaching data to objects.
+    type: Where to forward logs: one of "loki", "fluentd", or "file".
+    endpoint: Where the sink is.
+      
+      For loki, the base URL of the Loki server, like http://localhost:3100.
+      
+      For fluentd, the host:port of a forward input, like localhost:24224.
+      
+      For file, the absolute path of the file to append to.
+      
+    resources: The names of the resources to forward logs from.
+      
+      If empty, forwards the logs of all resources, and the logs
+      that don't belong to any resource.
+      
+    min_level: Only forward lines at least this severe: one of "debug", "verbose",
+      "info", "warn", or "error".
+      
+      If empty, forwards every line that Tilt logs.
+      
+    sink_labels: Labels to add to every line, like the name of the developer or
+      the environment.
+      
+      For loki, these are stream labels. For fluentd and file, these are
+      fields of each record.
+      
+    batch_size: The most lines to send at once. Defaults to 100.
+    flush_interval: How long to wait for a batch to fill before sending it anyway, like "5s".
+      Defaults to 1s.
+    buffer_size: The most lines to hold while the endpoint is slow or down.
+      When the buffer is full, the oldest lines are dropped.
+      Defaults to 10000.
+"""
+  pass
+def notification(
+  name: str,
+  labels: Dict[str, str] = None,
+  annotations: Dict[str, str] = None,
+  url: str = "",
+  events: List[str] = None,
+  resources: List[str] = None,
+  min_interval: Optional[str] = None,
+  desktop: bool = False,
+  muted_resources: List[str] = None,
+):
+  """
+  Notification posts a message to a webhook, or shows a desktop notification,
+  when a resource changes state, like when a build fails or a container
+  starts crash-looping.
+
+  The webhook message is a JSON object with a "text" field, which Slack
+  incoming webhooks (and many other chat tools) accept.
+
+  Args:
+    name: The name in the Object metadata.
+    labels: A set of key/value pairs in the Object metadata for grouping objects.
+    annotations: A set of key/value pairs in the Object metadata for attaching data to objects.
+    url: The webhook URL to POST messages to.
+      
+      Required, unless desktop is set.
+      
+    events: The state changes to send messages for: one or more of "BuildFailed",
+      "CrashLoop", "Ready", "NotReady", "AlertFiring", "AlertResolved",
+      "FirstFailure" (a build failed, and the build before it succeeded), and
+      "RunFinished" (all the resources finished building, like when a tilt ci
+      run would finish).
+      
+      If empty, sends messages for every state change except FirstFailure
+      and RunFinished.
+      
+    resources: The names of the resources to send messages for.
+      
+      If empty, sends messages for all resources.
+      
+    min_interval: The minimum time between messages about the same resource, like "5m".
+      
+      State changes in between are batched into the next message, so
+      that a flapping resource doesn't flood the channel.
+      
+      Defaults to 1 minute. Set to "0s" to send every state change right away.
+      
+    desktop: Whether to show messages as desktop notifications on the machine
+      that Tilt runs on.
+      
+      Uses osascript on macOS, notify-send on Linux, and PowerShell on Windows.
+      
+    muted_resources: The names of the resources to never send messages for.
+      
+      Use it to mute noisy resources without listing all the others
+      in resources.
+      
 """
   pass
 def ui_button(
@@ -538,6 +854,111 @@ def ui_button(
       confirm before taking action
       
     inputs: Any inputs for this button.
+"""
+  pass
+def ui_panel(
+  name: str,
+  labels: Dict[str, str] = None,
+  annotations: Dict[str, str] = None,
+  location: UIComponentLocation = None,
+  title: str = "",
+  url: str = "",
+  markdown: str = "",
+  order: int = 0,
+):
+  """
+  UIPanel adds a tab to the web UI, next to a resource's logs or
+  on the overview of all resources.
+
+  The tab either embeds another web page (like a database admin UI or
+  a feature flag toggler) or renders Markdown.
+
+  Args:
+    name: The name in the Object metadata.
+    labels: A set of key/value pairs in the Object metadata for grouping objects.
+    annotations: A set of key/value pairs in the Object metadata for attaching data to objects.
+    location: Location associates the panel with a resource, or with the
+      overview of all resources if the ComponentType is Global.
+    title: Title is the text on the panel's tab.
+    url: URL of a web page to embed in the panel, in an iframe.
+      
+      Exactly one of URL and Markdown must be set.
+      
+    markdown: Markdown to render in the panel.
+      
+      Exactly one of URL and Markdown must be set.
+      
+    order: Where the tab goes, relative to other panels in the same location.
+      Tabs with lower numbers go first. Ties are sorted by title.
+      
+"""
+  pass
+
+def cmd_container_target(
+  name: str = "",
+  docker_compose_service: str = "",
+) -> CmdContainerTarget:
+  """
+  CmdContainerTarget says which container a process runs in.
+  
+  The process runs in the container's working directory, not the Cmd's dir,
+  and gets only the container's env and the Cmd's own env.
+  
+  Exactly one field must be set.
+
+  Args:
+    name: The name or ID of a container on Tilt's Docker daemon.
+      
+    docker_compose_service: The name of a DockerComposeService object. The process runs in the
+      service's current container, which is looked up every time the process
+      starts.
+      
+"""
+  pass
+
+def cmd_ssh_target(
+  host: str = "",
+  key_file: str = "",
+  known_hosts_file: str = "",
+  forward_agent: bool = False,
+) -> CmdSSHTarget:
+  """
+  CmdSSHTarget says how to reach the host that a process runs on over SSH.
+  
+  The process runs in the same directory as it would on this machine, so
+  the code should be at the same path on the remote host. Only the Cmd's own
+  env is sent, not Tilt's.
+
+  Args:
+    host: The host and, optionally, the user and port, like
+      "alice@devbox:2222". The user defaults to the local user, and the
+      port to 22.
+    key_file: A private key to log in with. Keys in the local SSH agent are tried
+      too.
+      
+    known_hosts_file: The known_hosts file that verifies the host's key. Defaults to
+      ~/.ssh/known_hosts.
+      
+    forward_agent: Forward the local SSH agent, so that the process can use the local
+      keys, like to pull from git.
+      
+"""
+  pass
+
+def cmd_target(
+  ssh: Optional[CmdSSHTarget] = None,
+  container: Optional[CmdContainerTarget] = None,
+) -> CmdTarget:
+  """
+  CmdTarget says where to run a process other than on this machine.
+  
+  Exactly one field must be set.
+
+  Args:
+    ssh: Run the process on a remote host over SSH.
+      
+    container: Run the process in a running container, like `docker exec`.
+      
 """
   pass
 
@@ -656,6 +1077,7 @@ def handler(
 def ignore_def(
   base_path: str = "",
   patterns: List[str] = None,
+  reason: str = "",
 ) -> IgnoreDef:
   """
   Describes sets of file paths that the FileWatch should ignore.
@@ -668,6 +1090,9 @@ def ignore_def(
     patterns: Patterns are dockerignore style rules. Absolute-style patterns will be rooted to the BasePath.
       
       See https://docs.docker.com/engine/reference/builder/#dockerignore-file.
+    reason: Reason is a human-readable explanation of where this ignore came from and why it exists (e.g., ".tiltignore" or "generated protobuf code").
+      
+      Used when debugging why a file change did not trigger an update.
 """
   pass
 
@@ -0,0 +1,163 @@
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The label on the AlertRules that enforce a budget, with the resource
+// as its value.
+const LabelBudget = "tilt.dev/budget"
+
+// Budget is how long a resource is allowed to take.
+type Budget struct {
+	// If non-zero, the longest a build may take.
+	BuildTime time.Duration
+
+	// If non-zero, the longest the resource may take to become ready
+	// after a build starts.
+	TimeToReady time.Duration
+
+	// Shown with the alert when the resource goes over budget.
+	Message string
+}
+
+// Settings record the budgets set with resource_budget().
+type Settings struct {
+	Budgets map[model.ManifestName]Budget
+}
+
+// Returns the AlertRules that flag resources that go over budget.
+//
+// Returns an error if a budget was set for a resource that doesn't exist.
+func (s Settings) AlertRules(manifests []model.Manifest) ([]*v1alpha1.AlertRule, error) {
+	exists := make(map[model.ManifestName]bool, len(manifests))
+	for _, m := range manifests {
+		exists[m.Name] = true
+	}
+
+	var unknown []string
+	var result []*v1alpha1.AlertRule
+	for name, b := range s.Budgets {
+		if !exists[name] {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		if b.BuildTime > 0 {
+			result = append(result, alertRule(name, "build-time", v1alpha1.AlertMetricBuildDuration, b.BuildTime, b.Message))
+		}
+		if b.TimeToReady > 0 {
+			result = append(result, alertRule(name, "time-to-ready", v1alpha1.AlertMetricTimeToReady, b.TimeToReady, b.Message))
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("resource_budget: unknown resources: %s", strings.Join(unknown, ", "))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func alertRule(name model.ManifestName, kind string, metric v1alpha1.AlertMetric, d time.Duration, message string) *v1alpha1.AlertRule {
+	return &v1alpha1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-budget-%s", name, kind),
+			Labels: map[string]string{LabelBudget: name.String()},
+		},
+		Spec: v1alpha1.AlertRuleSpec{
+			Metric:    metric,
+			Duration:  &metav1.Duration{Duration: d},
+			Resources: []string{name.String()},
+			Message:   message,
+		},
+	}
+}
+
+// Implements resource_budget(), for flagging resources that make
+// the dev loop slow.
+//
+// Budgets are AlertRules, so they show up as badges in the web UI,
+// in the tilt_alert_firing metric, and in notifications.
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (p Plugin) NewState() interface{} {
+	return Settings{}
+}
+
+func (p Plugin) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("resource_budget", p.resourceBudget)
+}
+
+func (p Plugin) resourceBudget(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var resource string
+	var buildTime, timeToReady value.Duration
+	var message string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"resource", &resource,
+		"build_time?", &buildTime,
+		"time_to_ready?", &timeToReady,
+		"message?", &message); err != nil {
+		return nil, err
+	}
+
+	if resource == "" {
+		return nil, fmt.Errorf("%s: resource must not be empty", fn.Name())
+	}
+	if buildTime.AsDuration() < 0 || timeToReady.AsDuration() < 0 {
+		return nil, fmt.Errorf("%s: durations must not be negative", fn.Name())
+	}
+	if buildTime.IsZero() && timeToReady.IsZero() {
+		return nil, fmt.Errorf("%s: set build_time, time_to_ready, or both", fn.Name())
+	}
+
+	// Calling resource_budget() again for the same resource replaces
+	// its budget.
+	err := starkit.SetState(thread, func(settings Settings) Settings {
+		budgets := make(map[model.ManifestName]Budget, len(settings.Budgets)+1)
+		for k, v := range settings.Budgets {
+			budgets[k] = v
+		}
+		budgets[model.ManifestName(resource)] = Budget{
+			BuildTime:   buildTime.AsDuration(),
+			TimeToReady: timeToReady.AsDuration(),
+			Message:     message,
+		}
+		settings.Budgets = budgets
+		return settings
+	})
+	return starlark.None, err
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) Settings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (Settings, error) {
+	var state Settings
+	err := m.Load(&state)
+	return state, err
+}
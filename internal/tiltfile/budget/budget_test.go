@@ -0,0 +1,78 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestBudgets(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_budget('fe', build_time='30s')
+resource_budget('be', time_to_ready='2m', message='too slow')
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	settings := MustState(result)
+	assert.Equal(t, map[model.ManifestName]Budget{
+		"fe": {BuildTime: 30 * time.Second},
+		"be": {TimeToReady: 2 * time.Minute, Message: "too slow"},
+	}, settings.Budgets)
+
+	rules, err := settings.AlertRules([]model.Manifest{{Name: "fe"}, {Name: "be"}})
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "be-budget-time-to-ready", rules[0].Name)
+	assert.Equal(t, v1alpha1.AlertMetricTimeToReady, rules[0].Spec.Metric)
+	assert.Equal(t, "be", rules[0].Labels[LabelBudget])
+	assert.Equal(t, "fe-budget-build-time", rules[1].Name)
+	assert.Equal(t, v1alpha1.AlertMetricBuildDuration, rules[1].Spec.Metric)
+	assert.Equal(t, []string{"fe"}, rules[1].Spec.Resources)
+}
+
+func TestLastBudgetWins(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_budget('fe', build_time='30s', time_to_ready='1m')
+resource_budget('fe', build_time='10s')
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, Budget{BuildTime: 10 * time.Second}, MustState(result).Budgets["fe"])
+}
+
+func TestBudgetNeedsALimit(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_budget('fe')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resource_budget: set build_time, time_to_ready, or both")
+}
+
+func TestBudgetBadDuration(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_budget('fe', build_time='soon')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "build_time")
+}
+
+func newFixture(t testing.TB) *starkit.Fixture {
+	return starkit.NewFixture(t, NewPlugin())
+}
@@ -1,6 +1,7 @@
 package cisettings
 
 import (
+	"fmt"
 	"time"
 
 	"go.starlark.net/starlark"
@@ -15,19 +16,34 @@ import (
 
 // Implements functions for dealing with ci settings.
 type Plugin struct {
-	ciTimeoutFlag model.CITimeoutFlag
+	ciTimeoutFlag   model.CITimeoutFlag
+	ciSettingsFlags model.CISettingsFlags
 }
 
-func NewPlugin(ciTimeoutFlag model.CITimeoutFlag) Plugin {
+func NewPlugin(ciTimeoutFlag model.CITimeoutFlag, ciSettingsFlags model.CISettingsFlags) Plugin {
 	return Plugin{
-		ciTimeoutFlag: ciTimeoutFlag,
+		ciTimeoutFlag:   ciTimeoutFlag,
+		ciSettingsFlags: ciSettingsFlags,
 	}
 }
 
 func (e Plugin) NewState() interface{} {
-	return &v1alpha1.SessionCISpec{
+	settings := &v1alpha1.SessionCISpec{
 		Timeout: &metav1.Duration{Duration: time.Duration(e.ciTimeoutFlag)},
 	}
+	if e.ciSettingsFlags.ReadinessTimeout != 0 {
+		settings.ReadinessTimeout = &metav1.Duration{Duration: e.ciSettingsFlags.ReadinessTimeout}
+	}
+	for name, timeout := range e.ciSettingsFlags.ResourceTimeouts {
+		if settings.ResourceReadinessTimeouts == nil {
+			settings.ResourceReadinessTimeouts = make(map[string]metav1.Duration)
+		}
+		settings.ResourceReadinessTimeouts[name] = metav1.Duration{Duration: timeout}
+	}
+	if e.ciSettingsFlags.CollectAllFailures {
+		settings.FailurePolicy = v1alpha1.CIFailurePolicyCollectAll
+	}
+	return settings
 }
 
 func (e Plugin) OnStart(env *starkit.Environment) error {
@@ -37,12 +53,27 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 func (e *Plugin) ciSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var k8sGracePeriod value.Duration = -1
 	var timeout value.Duration = -1
+	var readinessTimeout value.Duration = -1
+	var resourceTimeouts value.StringStringMap
+	var failFast value.Optional[starlark.Bool]
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 		"k8s_grace_period?", &k8sGracePeriod,
-		"timeout?", &timeout); err != nil {
+		"timeout?", &timeout,
+		"readiness_timeout?", &readinessTimeout,
+		"resource_timeouts?", &resourceTimeouts,
+		"fail_fast?", &failFast); err != nil {
 		return nil, err
 	}
 
+	parsedResourceTimeouts := make(map[string]metav1.Duration, len(resourceTimeouts))
+	for name, s := range resourceTimeouts {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: resource_timeouts[%q]: %v", fn.Name(), name, err)
+		}
+		parsedResourceTimeouts[name] = metav1.Duration{Duration: d}
+	}
+
 	err := starkit.SetState(thread, func(settings *v1alpha1.SessionCISpec) *v1alpha1.SessionCISpec {
 		if k8sGracePeriod != -1 {
 			settings = settings.DeepCopy()
@@ -52,6 +83,26 @@ func (e *Plugin) ciSettings(thread *starlark.Thread, fn *starlark.Builtin, args
 			settings = settings.DeepCopy()
 			settings.Timeout = &metav1.Duration{Duration: time.Duration(timeout)}
 		}
+		if readinessTimeout != -1 {
+			settings = settings.DeepCopy()
+			settings.ReadinessTimeout = &metav1.Duration{Duration: time.Duration(readinessTimeout)}
+		}
+		if len(parsedResourceTimeouts) > 0 {
+			settings = settings.DeepCopy()
+			if settings.ResourceReadinessTimeouts == nil {
+				settings.ResourceReadinessTimeouts = make(map[string]metav1.Duration)
+			}
+			for name, d := range parsedResourceTimeouts {
+				settings.ResourceReadinessTimeouts[name] = d
+			}
+		}
+		if failFast.IsSet {
+			settings = settings.DeepCopy()
+			settings.FailurePolicy = v1alpha1.CIFailurePolicyFailFast
+			if !failFast.Value {
+				settings.FailurePolicy = v1alpha1.CIFailurePolicyCollectAll
+			}
+		}
 		return settings
 	})
 
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -70,6 +72,73 @@ ci_settings()
 	require.Equal(t, 3*time.Minute, ci.Timeout.Duration)
 }
 
+func TestReadinessTimeouts(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+ci_settings(readiness_timeout='2m', resource_timeouts={'db': '5m'})
+ci_settings(resource_timeouts={'api': '3m'})
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	ci, err := GetState(result)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Minute, ci.ReadinessTimeout.Duration)
+	require.Equal(t, map[string]metav1.Duration{
+		"db":  {Duration: 5 * time.Minute},
+		"api": {Duration: 3 * time.Minute},
+	}, ci.ResourceReadinessTimeouts)
+}
+
+func TestResourceTimeoutInvalid(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+ci_settings(resource_timeouts={'db': 'soon'})
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `resource_timeouts["db"]: time: invalid duration "soon"`)
+}
+
+func TestFailFast(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+ci_settings(fail_fast=False)
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	ci, err := GetState(result)
+	require.NoError(t, err)
+	require.Equal(t, v1alpha1.CIFailurePolicyCollectAll, ci.FailurePolicy)
+}
+
+func TestSettingsFlags(t *testing.T) {
+	f := starkit.NewFixture(t, NewPlugin(model.CITimeoutFlag(model.CITimeoutDefault), model.CISettingsFlags{
+		ReadinessTimeout:   time.Minute,
+		ResourceTimeouts:   map[string]time.Duration{"db": 5 * time.Minute},
+		CollectAllFailures: true,
+	}))
+	f.File("Tiltfile", `
+ci_settings(resource_timeouts={'api': '3m'})
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	ci, err := GetState(result)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, ci.ReadinessTimeout.Duration)
+	require.Equal(t, map[string]metav1.Duration{
+		"db":  {Duration: 5 * time.Minute},
+		"api": {Duration: 3 * time.Minute},
+	}, ci.ResourceReadinessTimeouts)
+	require.Equal(t, v1alpha1.CIFailurePolicyCollectAll, ci.FailurePolicy)
+}
+
 func newFixture(t testing.TB) *starkit.Fixture {
-	return starkit.NewFixture(t, NewPlugin(model.CITimeoutFlag(model.CITimeoutDefault)))
+	return starkit.NewFixture(t, NewPlugin(model.CITimeoutFlag(model.CITimeoutDefault), model.CISettingsFlags{}))
 }
@@ -21,6 +21,11 @@ type Settings struct {
 
 	configParseCalled bool
 
+	// The result of the most recent config.parse(), for evaluating enable_if() conditions.
+	parsedArgs starlark.Value
+
+	enableConditions []enableCondition
+
 	// if parse has been called, the directory containing the Tiltfile that called it
 	seenWorkingDirectory string
 }
@@ -62,6 +67,7 @@ func (e *Plugin) OnStart(env *starkit.Environment) error {
 	}{
 		{"config.set_enabled_resources", setEnabledResources},
 		{"config.clear_enabled_resources", clearEnabledResources},
+		{"config.enable_if", enableIf},
 		{"config.parse", e.parse},
 		{"config.define_string_list", configSettingDefinitionBuiltin(func() configValue {
 			return &stringList{}
@@ -153,5 +159,13 @@ func (e *Plugin) parse(thread *starlark.Thread, fn *starlark.Builtin, args starl
 		return starlark.None, err
 	}
 
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		settings.parsedArgs = ret
+		return settings
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
 	return ret, nil
 }
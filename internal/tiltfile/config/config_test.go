@@ -60,7 +60,7 @@ config.parse()`
 			require.NoError(t, err)
 
 			manifests := []model.Manifest{{Name: "a"}, {Name: "b"}}
-			actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests)
+			actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests, nil)
 			require.NoError(t, err)
 
 			require.Equal(t, tc.expectedResources, actual)
@@ -79,7 +79,7 @@ func TestClearEnabledResources(t *testing.T) {
 	require.NoError(t, err)
 
 	manifests := []model.Manifest{{Name: "a"}, {Name: "b"}}
-	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests)
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests, nil)
 	require.NoError(t, err)
 
 	require.Len(t, actual, 0)
@@ -294,7 +294,7 @@ config.set_enabled_resources(config.parse()['resources'])
 	require.NoError(t, err)
 
 	manifests := []model.Manifest{{Name: "foo"}, {Name: "bar"}, {Name: "baz"}}
-	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests)
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests, nil)
 	require.NoError(t, err)
 	require.Equal(t, []model.ManifestName{"foo", "bar"}, actual)
 }
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/tilt-dev/tilt/internal/sliceutils"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// A condition that decides whether a set of resources is enabled by default.
+//
+// Conditions are Starlark expressions, evaluated after the Tiltfile finishes
+// executing, so that they see the final config args and cluster.
+type enableCondition struct {
+	resources []model.ManifestName
+	expr      string
+	pos       syntax.Position
+}
+
+func enableIf(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var resources value.StringOrStringList
+	var expr string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"resources", &resources,
+		"condition", &expr,
+	)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	if len(resources.Values) == 0 {
+		return starlark.None, fmt.Errorf("%s: resources must not be empty", fn.Name())
+	}
+
+	// Check the syntax now, so that errors point at the call site.
+	_, err = syntax.ParseExpr(fn.Name(), expr, 0)
+	if err != nil {
+		return starlark.None, fmt.Errorf("%s: invalid condition %q: %v", fn.Name(), expr, err)
+	}
+
+	cond := enableCondition{expr: expr, pos: thread.CallFrame(1).Pos}
+	for _, r := range resources.Values {
+		cond.resources = append(cond.resources, model.ManifestName(r))
+	}
+
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		settings.enableConditions = append(append([]enableCondition{}, settings.enableConditions...), cond)
+		return settings
+	})
+	return starlark.None, err
+}
+
+// Evaluates the enable_if() conditions, and removes any resources whose condition is false.
+//
+// `env` contains any values the conditions may refer to besides `args`.
+func (s Settings) applyEnableConditions(enabled []model.ManifestName, manifests []model.Manifest, env starlark.StringDict) ([]model.ManifestName, error) {
+	if len(s.enableConditions) == 0 {
+		return enabled, nil
+	}
+
+	predeclared := starlark.StringDict{}
+	for k, v := range env {
+		predeclared[k] = v
+	}
+	args := s.parsedArgs
+	if args == nil {
+		args = starlark.NewDict(0)
+	}
+	predeclared["args"] = args
+
+	known := make(map[model.ManifestName]bool, len(manifests))
+	for _, m := range manifests {
+		known[m.Name] = true
+	}
+
+	disabled := make(map[model.ManifestName]bool)
+	for _, cond := range s.enableConditions {
+		var unknownNames []string
+		for _, r := range cond.resources {
+			if !known[r] {
+				unknownNames = append(unknownNames, string(r))
+			}
+		}
+		if len(unknownNames) > 0 {
+			return nil, fmt.Errorf("%s: config.enable_if: unknown resources: %s",
+				cond.pos, sliceutils.QuotedStringList(unknownNames))
+		}
+
+		thread := &starlark.Thread{Name: "config.enable_if"}
+		result, err := starlark.Eval(thread, cond.pos.Filename(), cond.expr, predeclared)
+		if err != nil {
+			return nil, fmt.Errorf("%s: config.enable_if: evaluating %q: %v", cond.pos, cond.expr, err)
+		}
+
+		if !bool(result.Truth()) {
+			for _, r := range cond.resources {
+				disabled[r] = true
+			}
+		}
+	}
+
+	var result []model.ManifestName
+	for _, mn := range enabled {
+		if !disabled[mn] {
+			result = append(result, mn)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var enableIfManifests = []model.Manifest{{Name: "frontend"}, {Name: "backend"}, {Name: "db"}}
+
+func TestEnableIfArgs(t *testing.T) {
+	f := NewFixture(t, []string{"--profile", "frontend"}, "")
+	f.File("Tiltfile", `
+config.define_string_list('profile')
+config.parse()
+config.enable_if('frontend', "'frontend' in args.get('profile', [])")
+config.enable_if(['backend', 'db'], "'backend' in args.get('profile', [])")
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), enableIfManifests, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []model.ManifestName{"frontend"}, actual)
+}
+
+func TestEnableIfNoParse(t *testing.T) {
+	f := NewFixture(t, nil, "")
+	f.File("Tiltfile", `
+config.enable_if('db', "args.get('db', False)")
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), enableIfManifests, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []model.ManifestName{"frontend", "backend"}, actual)
+}
+
+func TestEnableIfEnv(t *testing.T) {
+	f := NewFixture(t, nil, "")
+	f.File("Tiltfile", `
+config.enable_if('db', "product == 'kind'")
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), enableIfManifests,
+		starlark.StringDict{"product": starlark.String("kind")})
+	require.NoError(t, err)
+	assert.Equal(t, []model.ManifestName{"frontend", "backend", "db"}, actual)
+}
+
+func TestEnableIfExplicitRequestWins(t *testing.T) {
+	f := NewFixture(t, []string{"db"}, "")
+	f.File("Tiltfile", `
+config.enable_if('db', "False")
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), enableIfManifests, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []model.ManifestName{"db"}, actual)
+}
+
+func TestEnableIfUnknownResource(t *testing.T) {
+	f := NewFixture(t, nil, "")
+	f.File("Tiltfile", `
+config.enable_if('dbb', "False")
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	_, err = MustState(result).EnabledResources(f.Tiltfile(), enableIfManifests, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Tiltfile:2:17: config.enable_if: unknown resources: "dbb"`)
+}
+
+func TestEnableIfSyntaxError(t *testing.T) {
+	f := NewFixture(t, nil, "")
+	f.File("Tiltfile", `
+config.enable_if('db', "args[")
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `config.enable_if: invalid condition "args["`)
+}
+
+func TestEnableIfEvalError(t *testing.T) {
+	f := NewFixture(t, nil, "")
+	f.File("Tiltfile", `
+config.enable_if('db', "cluster.product == 'kind'")
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	_, err = MustState(result).EnabledResources(f.Tiltfile(), enableIfManifests, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `config.enable_if: evaluating "cluster.product == 'kind'": `)
+	assert.Contains(t, err.Error(), `undefined: cluster`)
+}
@@ -60,7 +60,9 @@ func clearEnabledResources(thread *starlark.Thread, fn *starlark.Builtin, args s
 }
 
 // for the given args and list of full manifests, figure out which manifests the user actually selected
-func (s Settings) EnabledResources(tf *v1alpha1.Tiltfile, manifests []model.Manifest) ([]model.ManifestName, error) {
+//
+// `conditionEnv` holds the values (besides `args`) that config.enable_if() conditions may refer to.
+func (s Settings) EnabledResources(tf *v1alpha1.Tiltfile, manifests []model.Manifest, conditionEnv starlark.StringDict) ([]model.ManifestName, error) {
 	if s.disableAll {
 		return nil, nil
 	}
@@ -82,7 +84,16 @@ func (s Settings) EnabledResources(tf *v1alpha1.Tiltfile, manifests []model.Mani
 		}
 	}
 
-	return match(manifests, requestedManifests)
+	enabled, err := match(manifests, requestedManifests)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resources the user asked for by name are enabled, whatever their conditions say.
+	if len(requestedManifests) > 0 {
+		return enabled, nil
+	}
+	return s.applyEnableConditions(enabled, manifests, conditionEnv)
 }
 
 // add `manifestToAdd` and all of its transitive deps to `result`
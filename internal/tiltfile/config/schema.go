@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// The settings a Tiltfile defines with config.define_*().
+//
+// Tilt publishes this, so that `tilt args` can check new args
+// before the Tiltfile reloads with them.
+type ArgsSchema struct {
+	// Whether the Tiltfile called config.parse(). If it didn't,
+	// the args are the names of the resources to enable.
+	Parsed bool `json:"parsed"`
+
+	Settings []ArgSetting `json:"settings,omitempty"`
+}
+
+type ArgSetting struct {
+	Name string `json:"name"`
+
+	// One of string, list[string], bool, or object.
+	Type string `json:"type"`
+
+	Usage string `json:"usage,omitempty"`
+
+	// Whether the setting takes the positional args.
+	Positional bool `json:"positional,omitempty"`
+}
+
+var settingTypes = map[string]func() configValue{
+	"string":       func() configValue { return &stringSetting{} },
+	"list[string]": func() configValue { return &stringList{} },
+	"bool":         func() configValue { return &boolSetting{} },
+	"object":       func() configValue { return &objectSetting{} },
+}
+
+func (s Settings) ArgsSchema() ArgsSchema {
+	schema := ArgsSchema{Parsed: s.configParseCalled}
+	for name, def := range s.configDef.configSettings {
+		schema.Settings = append(schema.Settings, ArgSetting{
+			Name:       name,
+			Type:       def.newValue().Type(),
+			Usage:      def.usage,
+			Positional: name == s.configDef.positionalSettingName,
+		})
+	}
+	sort.Slice(schema.Settings, func(i, j int) bool {
+		return schema.Settings[i].Name < schema.Settings[j].Name
+	})
+	return schema
+}
+
+// Returns the setting with the given name, if there is one.
+func (s ArgsSchema) Setting(name string) (ArgSetting, bool) {
+	for _, setting := range s.Settings {
+		if setting.Name == name {
+			return setting, true
+		}
+	}
+	return ArgSetting{}, false
+}
+
+// Returns the setting that takes the positional args, if there is one.
+func (s ArgsSchema) PositionalSetting() (ArgSetting, bool) {
+	for _, setting := range s.Settings {
+		if setting.Positional {
+			return setting, true
+		}
+	}
+	return ArgSetting{}, false
+}
+
+// Checks args the same way config.parse() will, with the same errors.
+//
+// Args for a Tiltfile that doesn't call config.parse() are resource names,
+// which this doesn't check.
+func (s ArgsSchema) Validate(args []string) error {
+	if !s.Parsed {
+		return nil
+	}
+
+	cd := ConfigDef{configSettings: make(map[string]configSetting)}
+	for _, setting := range s.Settings {
+		newValue, ok := settingTypes[setting.Type]
+		if !ok {
+			return fmt.Errorf("setting %s has unknown type %q", setting.Name, setting.Type)
+		}
+		cd.configSettings[setting.Name] = configSetting{newValue: newValue, usage: setting.Usage}
+		if setting.Positional {
+			cd.positionalSettingName = setting.Name
+		}
+	}
+
+	_, _, err := cd.incorporateArgs(nil, args)
+	return err
+}
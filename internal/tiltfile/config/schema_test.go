@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgsSchema(t *testing.T) {
+	f := NewFixture(t, nil, "")
+
+	f.File("Tiltfile", `
+config.define_string_list('to-run', args=True)
+config.define_bool('debug', usage='Run with the debugger attached')
+config.define_string('env')
+config.parse()
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	schema := MustState(result).ArgsSchema()
+	assert.Equal(t, ArgsSchema{
+		Parsed: true,
+		Settings: []ArgSetting{
+			{Name: "debug", Type: "bool", Usage: "Run with the debugger attached"},
+			{Name: "env", Type: "string"},
+			{Name: "to-run", Type: "list[string]", Positional: true},
+		},
+	}, schema)
+
+	assert.NoError(t, schema.Validate([]string{"fe", "be", "--debug", "--env=staging"}))
+
+	err = schema.Validate([]string{"--enf=staging"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid Tiltfile config args: unknown flag: --enf")
+	}
+}
+
+func TestArgsSchemaNotParsed(t *testing.T) {
+	f := NewFixture(t, nil, "")
+
+	f.File("Tiltfile", `
+config.define_string('env')
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	schema := MustState(result).ArgsSchema()
+	assert.False(t, schema.Parsed)
+	assert.NoError(t, schema.Validate([]string{"--anything"}))
+}
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/compose-spec/compose-go/loader"
 	"github.com/compose-spec/compose-go/types"
@@ -43,6 +44,11 @@ type dcResourceSet struct {
 	services     map[string]*dcService
 	serviceNames []string
 	resOptions   map[string]*dcResourceOptions
+	down         model.DockerComposeDownSettings
+
+	// Patches from dc_services(), by service name, and the override file they're saved to.
+	overrides    map[string]interface{}
+	overridePath string
 }
 
 type dcResourceMap map[string]*dcResourceSet
@@ -62,17 +68,41 @@ func (dcm dcResourceMap) ServiceCount() int {
 func (s *tiltfileState) dockerCompose(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var configPaths starlark.Value
 	var projectName string
+	var profiles value.StringOrStringList
+	var downVolumes, downRemoveOrphans bool
+	var downTimeoutSecs value.Optional[starlark.Int]
+	var dockerHost, dockerContext string
 	envFile := value.NewLocalPathUnpacker(thread)
 
 	err := s.unpackArgs(fn.Name(), args, kwargs,
 		"configPaths", &configPaths,
 		"env_file?", &envFile,
 		"project_name?", &projectName,
+		"profiles?", &profiles,
+		"down_volumes?", &downVolumes,
+		"down_remove_orphans?", &downRemoveOrphans,
+		"down_timeout_secs?", &downTimeoutSecs,
+		"docker_host?", &dockerHost,
+		"docker_context?", &dockerContext,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if dockerHost != "" && dockerContext != "" {
+		return nil, fmt.Errorf("%s: docker_host and docker_context can't be set together", fn.Name())
+	}
+
+	var downTimeout *time.Duration
+	if downTimeoutSecs.IsSet {
+		secs, ok := downTimeoutSecs.Value.Int64()
+		if !ok || secs < 0 {
+			return nil, fmt.Errorf("%s: down_timeout_secs must be a non-negative integer, got %s", fn.Name(), downTimeoutSecs.Value)
+		}
+		d := time.Duration(secs) * time.Second
+		downTimeout = &d
+	}
+
 	paths := starlarkValueOrSequenceToSlice(configPaths)
 
 	if len(paths) == 0 {
@@ -80,8 +110,11 @@ func (s *tiltfileState) dockerCompose(thread *starlark.Thread, fn *starlark.Buil
 	}
 
 	project := v1alpha1.DockerComposeProject{
-		Name:    projectName,
-		EnvFile: envFile.Value,
+		Name:          projectName,
+		EnvFile:       envFile.Value,
+		Profiles:      profiles.Values,
+		DockerHost:    dockerHost,
+		DockerContext: dockerContext,
 	}
 
 	if project.EnvFile != "" {
@@ -165,14 +198,41 @@ func (s *tiltfileState) dockerCompose(thread *starlark.Thread, fn *starlark.Buil
 		if project.EnvFile != "" {
 			dc.Project.EnvFile = project.EnvFile
 		}
+		dc.Project.Profiles = sliceutils.AppendWithoutDupes(dc.Project.Profiles, project.Profiles...)
+		if project.DockerHost != "" || project.DockerContext != "" {
+			dc.Project.DockerHost = project.DockerHost
+			dc.Project.DockerContext = project.DockerContext
+		}
 		project = dc.Project
 	}
 
+	dc.down.DeleteVolumes = dc.down.DeleteVolumes || downVolumes
+	dc.down.RemoveOrphans = dc.down.RemoveOrphans || downRemoveOrphans
+	if downTimeout != nil {
+		dc.down.Timeout = downTimeout
+	}
+
 	services, err := parseDCConfig(s.ctx, s.dcCli, dc)
 	if err != nil {
 		return nil, err
 	}
 
+	// Reload when the other files the project is loaded from change, e.g.,
+	// files that services extend, or the .env file.
+	isConfigPath := make(map[string]bool)
+	for _, p := range dc.Project.ConfigPaths {
+		isConfigPath[p] = true
+	}
+	for _, f := range dockercompose.ProjectFiles(dc.Project, nil) {
+		if isConfigPath[f] {
+			continue
+		}
+		err = io.RecordReadPath(thread, io.WatchFileOnly, f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dc.services = make(map[string]*dcService)
 	dc.serviceNames = []string{}
 	for _, svc := range services {
@@ -209,6 +269,9 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 	var links links.LinkList
 	var labels value.LabelSet
 	var autoInit = value.Optional[starlark.Bool]{Value: true}
+	var withDeps, forceRecreate value.Optional[starlark.Bool]
+	var pull string
+	var buildArgs value.StringStringMap
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"name", &name,
@@ -222,6 +285,10 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		"auto_init?", &autoInit,
 		"project_name?", &projectName,
 		"new_name?", &newName,
+		"with_deps?", &withDeps,
+		"force_recreate?", &forceRecreate,
+		"pull?", &pull,
+		"build_args?", &buildArgs,
 	); err != nil {
 		return nil, err
 	}
@@ -230,6 +297,13 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		return nil, fmt.Errorf("dc_resource: `name` must not be empty")
 	}
 
+	switch pull {
+	case "", types.PullPolicyAlways, types.PullPolicyMissing, types.PullPolicyNever:
+	default:
+		return nil, fmt.Errorf("%s: pull must be one of %q, %q, or %q; got %q",
+			fn.Name(), types.PullPolicyAlways, types.PullPolicyMissing, types.PullPolicyNever, pull)
+	}
+
 	var imageRefAsStr *string
 	switch imageVal := imageVal.(type) {
 	case nil: // optional arg, this is fine
@@ -287,6 +361,19 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		options.AutoInit = autoInit
 	}
 
+	if withDeps.IsSet {
+		options.WithDeps = bool(withDeps.Value)
+	}
+	if forceRecreate.IsSet {
+		options.ForceRecreate = bool(forceRecreate.Value)
+	}
+	if pull != "" {
+		options.Pull = pull
+	}
+	for key, val := range buildArgs.AsMap() {
+		options.BuildArgs[key] = val
+	}
+
 	s.dc[projectName].resOptions[name] = options
 	svc.Options = options
 	return starlark.None, nil
@@ -383,12 +470,37 @@ type dcResourceOptions struct {
 	Labels map[string]string
 
 	resourceDeps []string
+
+	// How Docker Compose brings up the service.
+	WithDeps      bool
+	ForceRecreate bool
+	Pull          string
+	BuildArgs     map[string]string
 }
 
 func newDcResourceOptions() *dcResourceOptions {
 	return &dcResourceOptions{
-		Labels: make(map[string]string),
+		Labels:    make(map[string]string),
+		BuildArgs: make(map[string]string),
+	}
+}
+
+func (o *dcResourceOptions) upOptions() *v1alpha1.DockerComposeUpOptions {
+	if !o.WithDeps && !o.ForceRecreate && o.Pull == "" && len(o.BuildArgs) == 0 {
+		return nil
+	}
+	upOptions := &v1alpha1.DockerComposeUpOptions{
+		WithDeps:      o.WithDeps,
+		ForceRecreate: o.ForceRecreate,
+		Pull:          o.Pull,
+	}
+	if len(o.BuildArgs) > 0 {
+		upOptions.BuildArgs = make(map[string]string, len(o.BuildArgs))
+		for k, v := range o.BuildArgs {
+			upOptions.BuildArgs[k] = v
+		}
 	}
+	return upOptions
 }
 
 func (svc dcService) ImageRef() reference.Named {
@@ -487,11 +599,13 @@ func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet *dcResourc
 	dcInfo := model.DockerComposeTarget{
 		Name: model.TargetName(service.Name),
 		Spec: v1alpha1.DockerComposeServiceSpec{
-			Service: service.ServiceName,
-			Project: dcSet.Project,
+			Service:   service.ServiceName,
+			Project:   dcSet.Project,
+			UpOptions: options.upOptions(),
 		},
 		ServiceYAML: string(service.ServiceYAML),
 		Links:       options.Links,
+		Down:        dcSet.down,
 	}.WithImageMapDeps(model.FilterLiveUpdateOnly(service.ImageMapDeps, iTargets)).
 		WithPublishedPorts(service.PublishedPorts)
 
@@ -41,6 +41,7 @@ docker_build('gcr.io/fe', '.', live_update=[
 			{
 				BasePath: f.Path(),
 				Patterns: []string{"build"},
+				Reason:   f.JoinPath(".dockerignore"),
 			},
 			{
 				BasePath: f.JoinPath("Dockerfile"),
@@ -78,6 +79,7 @@ docker_build('gcr.io/fe', '.', dockerfile="Dockerfile.custom", live_update=[
 			{
 				BasePath: f.Path(),
 				Patterns: []string{"build"},
+				Reason:   f.JoinPath("Dockerfile.custom.dockerignore"),
 			},
 			{
 				BasePath: f.JoinPath("Dockerfile.custom"),
@@ -2,6 +2,7 @@ package tiltfile
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
 	tiltfile_io "github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/localcache"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -19,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
+	"sigs.k8s.io/yaml"
 
 	"github.com/tilt-dev/tilt/internal/kustomize"
 )
@@ -40,6 +44,8 @@ func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, arg
 	var commandValue, commandBatValue, commandDirValue starlark.Value
 	var commandEnv value.StringStringMap
 	var stdin value.Stringable
+	var cacheKey string
+	var cacheInputs value.StringOrStringList
 	quiet := false
 	echoOff := false
 	err := s.unpackArgs(fn.Name(), args, kwargs,
@@ -50,6 +56,8 @@ func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, arg
 		"env", &commandEnv,
 		"dir?", &commandDirValue,
 		"stdin?", &stdin,
+		"cache_key?", &cacheKey,
+		"cache_inputs?", &cacheInputs,
 	)
 	if err != nil {
 		return nil, err
@@ -69,7 +77,18 @@ func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, arg
 		s := stdin.Value
 		execOptions.stdin = &s
 	}
-	out, err := s.execLocalCmd(thread, cmd, execOptions)
+
+	var out string
+	if cacheKey != "" || len(cacheInputs.Values) > 0 {
+		var paths []string
+		for _, p := range cacheInputs.Values {
+			paths = append(paths, starkit.AbsPath(thread, p))
+		}
+		inputs := localcache.Inputs{Cmd: cmd, Stdin: stdin.Value, Key: cacheKey, Paths: paths}
+		out, err = s.execLocalCmdCached(thread, cmd, execOptions, inputs)
+	} else {
+		out, err = s.execLocalCmd(thread, cmd, execOptions)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +96,48 @@ func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, arg
 	return tiltfile_io.NewBlob(out, fmt.Sprintf("local: %s", cmd)), nil
 }
 
+// Runs a command, or returns its output from a previous run with the same inputs.
+//
+// The cache is shared by all Tiltfiles loaded by the main Tiltfile, and is
+// stored next to it. Failed commands are never cached.
+func (s *tiltfileState) execLocalCmdCached(t *starlark.Thread, cmd model.Cmd, options execCommandOptions, inputs localcache.Inputs) (string, error) {
+	tf, err := starkit.StartTiltfileFromThread(t)
+	if err != nil {
+		return "", err
+	}
+	projectDir := filepath.Dir(tf.Spec.Path)
+	cache := localcache.New(projectDir)
+	s.localCacheDir = projectDir
+
+	key, err := inputs.Hash()
+	if err != nil {
+		return "", err
+	}
+
+	out, ok, err := cache.Get(key)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if options.logCommand {
+			s.logger.Infof("%s %s [cached]", options.logCommandPrefix, cmd)
+		}
+		return out, nil
+	}
+
+	out, err = s.execLocalCmd(t, cmd, options)
+	if err != nil {
+		return "", err
+	}
+
+	err = cache.Put(key, cmd, out)
+	if err != nil {
+		// The command succeeded, so a cache failure shouldn't fail the load.
+		s.logger.Warnf("local: failed to write cache: %v", err)
+	}
+	return out, nil
+}
+
 func (s *tiltfileState) execLocalCmd(t *starlark.Thread, cmd model.Cmd, options execCommandOptions) (string, error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
 	ctx, err := starkit.ContextFromThread(t)
@@ -193,7 +254,7 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 	path := value.NewLocalPathUnpacker(thread)
 	var name string
 	var namespace string
-	var valueFiles value.StringOrStringList
+	var values starlark.Value
 	var set value.StringOrStringList
 	var kubeVersion string
 
@@ -201,7 +262,7 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		"paths", &path,
 		"name?", &name,
 		"namespace?", &namespace,
-		"values?", &valueFiles,
+		"values?", &values,
 		"set?", &set,
 		"kube_version?", &kubeVersion,
 	)
@@ -267,12 +328,12 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		cmd = append(cmd, "--kube-version", kubeVersion)
 	}
 
-	for _, valueFile := range valueFiles.Values {
+	valueFiles, err := s.helmValueFiles(thread, fn, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, valueFile := range valueFiles {
 		cmd = append(cmd, "--values", valueFile)
-		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, starkit.AbsPath(thread, valueFile))
-		if err != nil {
-			return nil, err
-		}
 	}
 	for _, setArg := range set.Values {
 		cmd = append(cmd, "--set", setArg)
@@ -320,6 +381,55 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("helm: %s", localPath)), nil
 }
 
+// Converts the `values` argument of helm() to a list of values files.
+//
+// Each value may be the path to a values file, or a dict of values
+// (e.g., from helm_values.merge()), which we write to a temp file.
+func (s *tiltfileState) helmValueFiles(thread *starlark.Thread, fn *starlark.Builtin, values starlark.Value) ([]string, error) {
+	var elems []starlark.Value
+	if d, ok := values.(*starlark.Dict); ok {
+		elems = []starlark.Value{d}
+	} else {
+		elems = value.ValueOrSequenceToSlice(values)
+	}
+
+	var result []string
+	for _, elem := range elems {
+		switch elem := elem.(type) {
+		case starlark.String:
+			valueFile := elem.GoString()
+			err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, starkit.AbsPath(thread, valueFile))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, valueFile)
+		case *starlark.Dict:
+			data, err := encoding.ConvertStarlarkToStructuredData(elem)
+			if err != nil {
+				return nil, fmt.Errorf("%s: values: %v", fn.Name(), err)
+			}
+			contents, err := yaml.Marshal(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: values: %v", fn.Name(), err)
+			}
+
+			tmpdir, err := s.tempDir()
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to store helm values")
+			}
+			valueFile := filepath.Join(tmpdir.Path(), fmt.Sprintf("values-%x.yaml", sha256.Sum256(contents)))
+			err = os.WriteFile(valueFile, contents, 0600)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to store helm values")
+			}
+			result = append(result, valueFile)
+		default:
+			return nil, fmt.Errorf("%s: values: expected a path or a dict of values, got %s", fn.Name(), elem.Type())
+		}
+	}
+	return result, nil
+}
+
 // NOTE(nick): This isn't perfect. For example, it doesn't handle chart deps
 // properly. When possible, prefer Helm 3.1's --include-crds
 func getHelmCRDs(path string) ([]string, error) {
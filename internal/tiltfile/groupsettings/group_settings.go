@@ -0,0 +1,79 @@
+package groupsettings
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Implements functions for dealing with how resource groups are displayed.
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (e Plugin) NewState() interface{} {
+	return model.GroupSettings{}
+}
+
+func (e Plugin) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("resource_group_settings", e.resourceGroupSettings)
+}
+
+func (e *Plugin) resourceGroupSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var order starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"order?", &order); err != nil {
+		return nil, err
+	}
+
+	var groups []string
+	if order != nil && order != starlark.None {
+		seq, ok := order.(starlark.Sequence)
+		if !ok {
+			return nil, fmt.Errorf("%s: for parameter \"order\": expected a list of labels, got %s", fn.Name(), order.Type())
+		}
+
+		iter := seq.Iterate()
+		defer iter.Done()
+		var item starlark.Value
+		for iter.Next(&item) {
+			var l value.LabelValue
+			err := l.Unpack(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: for parameter \"order\": %v", fn.Name(), err)
+			}
+			groups = append(groups, l.String())
+		}
+	}
+
+	err := starkit.SetState(thread, func(settings model.GroupSettings) model.GroupSettings {
+		if order != nil {
+			settings.Order = groups
+		}
+		return settings
+	})
+
+	return starlark.None, err
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) model.GroupSettings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.GroupSettings, error) {
+	var state model.GroupSettings
+	err := m.Load(&state)
+	return state, err
+}
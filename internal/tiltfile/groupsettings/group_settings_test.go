@@ -0,0 +1,65 @@
+package groupsettings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+func TestOrder(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_group_settings(order=['frontend', 'payments', 'payments/db'])
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	settings, err := GetState(result)
+	require.NoError(t, err)
+	require.Equal(t, []string{"frontend", "payments", "payments/db"}, settings.Order)
+}
+
+func TestOrderOverride(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_group_settings(order=['frontend'])
+resource_group_settings()
+resource_group_settings(order=['backend'])
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	settings, err := GetState(result)
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend"}, settings.Order)
+}
+
+func TestOrderInvalidLabel(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_group_settings(order=['payments/'])
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `resource_group_settings: for parameter "order": Invalid label "payments/"`)
+}
+
+func TestOrderNotAList(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+resource_group_settings(order=3)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected a list of labels, got int")
+}
+
+func newFixture(t testing.TB) *starkit.Fixture {
+	return starkit.NewFixture(t, NewPlugin())
+}
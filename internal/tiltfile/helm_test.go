@@ -230,6 +230,31 @@ k8s_yaml(yml)
 	assert.Contains(t, yaml, "servicePort: 1234")
 }
 
+func TestHelmValuesDict(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupHelm()
+
+	f.file("Tiltfile", `
+values = helm_values.read('./dev/helm/values-dev.yaml')
+values = helm_values.set(values, 'service.externalPort', 1234)
+yml = helm('./helm', name='rose-quartz', namespace='garnet', values=[values, {'ingress': {'enabled': True}}])
+k8s_yaml(yml)
+`)
+
+	f.load()
+
+	m := f.assertNextManifestUnresourced(
+		"rose-quartz-helloworld-chart",
+		"rose-quartz-helloworld-chart")
+	yaml := m.K8sTarget().YAML
+	assert.Contains(t, yaml, "name: nginx-dev")
+	assert.Contains(t, yaml, "port: 1234")
+	assert.Contains(t, yaml, "servicePort: 1234")
+
+	f.assertConfigFiles("./helm/", "./dev/helm/values-dev.yaml", ".tiltignore", "Tiltfile")
+}
+
 func TestHelmSetArgsMap(t *testing.T) {
 	f := newFixture(t)
 
@@ -0,0 +1,241 @@
+package helmvalues
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.starlark.net/starlark"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
+	tiltfile_io "github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+)
+
+// Implements functions for building Helm values as structured data.
+//
+// Values are merged the way Helm merges values files: dicts are merged
+// recursively, everything else is replaced, and None deletes a key.
+// Unlike Helm, an override must have the same type as the value it replaces,
+// so that typos like `replicas: "3"` fail at load time instead of at render time.
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (Plugin) OnStart(env *starkit.Environment) error {
+	for _, b := range []struct {
+		name string
+		f    starkit.Function
+	}{
+		{"helm_values.read", read},
+		{"helm_values.merge", merge},
+		{"helm_values.set", set},
+	} {
+		err := env.AddBuiltin(b.name, b.f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func read(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var paths value.StringOrStringList
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "paths", &paths); err != nil {
+		return nil, err
+	}
+
+	result := starlark.NewDict(0)
+	for _, p := range paths.Values {
+		absPath := starkit.AbsPath(thread, p)
+
+		// Records the file as a Tiltfile dependency, so that changes
+		// to any values file reload the Tiltfile.
+		contents, err := tiltfile_io.ReadFile(thread, absPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+		}
+
+		values, err := decodeValues(contents)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", fn.Name(), p, err)
+		}
+
+		result, err = mergeDicts("", result, values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", fn.Name(), p, err)
+		}
+	}
+	return result, nil
+}
+
+func merge(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: unexpected keyword arguments", fn.Name())
+	}
+
+	result := starlark.NewDict(0)
+	for i, arg := range args {
+		d, ok := arg.(*starlark.Dict)
+		if !ok {
+			return nil, fmt.Errorf("%s: argument %d: expected dict, got %s", fn.Name(), i, arg.Type())
+		}
+
+		var err error
+		result, err = mergeDicts("", result, d)
+		if err != nil {
+			return nil, fmt.Errorf("%s: argument %d: %v", fn.Name(), i, err)
+		}
+	}
+	return result, nil
+}
+
+func set(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var values *starlark.Dict
+	var path string
+	var v starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"values", &values,
+		"path", &path,
+		"value", &v); err != nil {
+		return nil, err
+	}
+
+	keys := strings.Split(path, ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("%s: invalid path %q", fn.Name(), path)
+		}
+	}
+
+	// Build a nested override dict from the path, then merge it in.
+	override := v
+	for i := len(keys) - 1; i >= 0; i-- {
+		d := starlark.NewDict(1)
+		err := d.SetKey(starlark.String(keys[i]), override)
+		if err != nil {
+			return nil, err
+		}
+		override = d
+	}
+
+	result, err := mergeDicts("", values, override.(*starlark.Dict))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return result, nil
+}
+
+func decodeValues(contents []byte) (*starlark.Dict, error) {
+	var decoded interface{}
+	err := k8syaml.Unmarshal(contents, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := encoding.ConvertStructuredDataToStarlark(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return starlark.NewDict(0), nil
+	case *starlark.Dict:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("expected a YAML map, got %s", v.Type())
+	}
+}
+
+// Returns a new dict with `override` deep-merged into `base`.
+//
+// Neither input is modified.
+func mergeDicts(path string, base, override *starlark.Dict) (*starlark.Dict, error) {
+	result := starlark.NewDict(base.Len())
+	for _, item := range base.Items() {
+		err := result.SetKey(item[0], item[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Iterate in sorted order, so that errors are deterministic.
+	items := override.Items()
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i][0].String() < items[j][0].String()
+	})
+
+	for _, item := range items {
+		k, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("%s: keys must be strings, got %s", keyPath(path, item[0].String()), item[0].Type())
+		}
+		kp := keyPath(path, k)
+		ov := item[1]
+
+		if ov == starlark.None {
+			_, _, err := result.Delete(item[0])
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		bv, found, err := result.Get(item[0])
+		if err != nil {
+			return nil, err
+		}
+		if !found || bv == starlark.None {
+			err := result.SetKey(item[0], ov)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		bd, bIsDict := bv.(*starlark.Dict)
+		od, oIsDict := ov.(*starlark.Dict)
+		if bIsDict && oIsDict {
+			merged, err := mergeDicts(kp, bd, od)
+			if err != nil {
+				return nil, err
+			}
+			err = result.SetKey(item[0], merged)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if typeName(bv) != typeName(ov) {
+			return nil, fmt.Errorf("%s: cannot override %s with %s", kp, typeName(bv), typeName(ov))
+		}
+
+		err = result.SetKey(item[0], ov)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// YAML doesn't distinguish ints from floats, so neither do we.
+func typeName(v starlark.Value) string {
+	switch v.(type) {
+	case starlark.Int, starlark.Float:
+		return "number"
+	}
+	return v.Type()
+}
+
+func keyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
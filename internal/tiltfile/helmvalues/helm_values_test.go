@@ -0,0 +1,118 @@
+package helmvalues
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+func TestReadMergesInOrder(t *testing.T) {
+	f := newFixture(t)
+	f.File("values.yaml", `
+image:
+  repository: nginx
+  tag: "1.21"
+replicas: 1
+`)
+	f.File("values-dev.yaml", `
+image:
+  tag: latest
+replicas: 2
+`)
+	f.File("Tiltfile", `
+v = helm_values.read(['values.yaml', 'values-dev.yaml'])
+print(v)
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, `{"image": {"repository": "nginx", "tag": "latest"}, "replicas": 2}`+"\n", f.PrintOutput())
+	assert.ElementsMatch(t, []string{f.JoinPath("Tiltfile"), f.JoinPath("values.yaml"), f.JoinPath("values-dev.yaml")},
+		io.MustState(result).Paths)
+}
+
+func TestMerge(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+base = {'image': {'repository': 'nginx', 'tag': '1.21'}, 'ports': [80], 'debug': True}
+merged = helm_values.merge(base, {'image': {'tag': 'latest'}, 'ports': [80, 443]}, {'debug': None})
+print(merged)
+
+# inputs are not modified
+print(base)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, `{"image": {"repository": "nginx", "tag": "latest"}, "ports": [80, 443]}
+{"image": {"repository": "nginx", "tag": "1.21"}, "ports": [80], "debug": True}
+`, f.PrintOutput())
+}
+
+func TestMergeTypeMismatch(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+helm_values.merge({'image': {'tag': '1.21'}}, {'image': {'tag': 1.21}})
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "helm_values.merge: argument 1: image.tag: cannot override string with number")
+}
+
+func TestMergeNumbers(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+print(helm_values.merge({'cpu': 1}, {'cpu': 0.5}))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, `{"cpu": 0.5}`+"\n", f.PrintOutput())
+}
+
+func TestSet(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+v = helm_values.set({'image': {'repository': 'nginx'}}, 'image.tag', 'latest')
+v = helm_values.set(v, 'ingress.hosts', ['localhost'])
+print(v)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, `{"image": {"repository": "nginx", "tag": "latest"}, "ingress": {"hosts": ["localhost"]}}`+"\n", f.PrintOutput())
+}
+
+func TestSetTypeMismatch(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+helm_values.set({'image': {'repository': 'nginx'}}, 'image', 'nginx:latest')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "helm_values.set: image: cannot override dict with string")
+}
+
+func TestReadNotAMap(t *testing.T) {
+	f := newFixture(t)
+	f.File("values.yaml", `- a`)
+	f.File("Tiltfile", `
+helm_values.read('values.yaml')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "helm_values.read: values.yaml: expected a YAML map, got list")
+}
+
+func newFixture(t testing.TB) *starkit.Fixture {
+	f := starkit.NewFixture(t, NewPlugin(), io.NewPlugin())
+	f.UseRealFS()
+	return f
+}
@@ -696,7 +696,12 @@ func (s *tiltfileState) yamlEntitiesFromSkylarkValue(thread *starlark.Thread, v
 	case nil:
 		return nil, nil
 	case io.Blob:
-		return parseYAMLFromBlob(v)
+		entities, err := parseYAMLFromBlob(v)
+		if err != nil {
+			return nil, err
+		}
+		s.warnInvalidYAML(v.Source, v.String())
+		return entities, nil
 	default:
 		yamlPath, err := value.ValueToAbsPath(thread, v)
 		if err != nil {
@@ -715,10 +720,19 @@ func (s *tiltfileState) yamlEntitiesFromSkylarkValue(thread *starlark.Thread, v
 			return entities, err
 		}
 
+		s.warnInvalidYAML(yamlPath, string(bs))
 		return entities, nil
 	}
 }
 
+// Tilt parses YAML leniently, so a field with a typo in it would otherwise
+// be dropped without a word.
+func (s *tiltfileState) warnInvalidYAML(source string, yaml string) {
+	for _, err := range k8s.ValidateYAML(yaml) {
+		s.logger.Warnf("Invalid YAML in %s: %v", source, err)
+	}
+}
+
 func convertPortForwards(val starlark.Value) ([]model.PortForward, error) {
 	if val == nil {
 		return nil, nil
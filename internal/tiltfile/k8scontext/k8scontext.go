@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
 
 	"github.com/tilt-dev/clusterid"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -30,7 +31,7 @@ func NewPlugin(context k8s.KubeContext, namespace k8s.Namespace, env clusterid.P
 }
 
 func (e Plugin) NewState() interface{} {
-	return State{context: e.context, env: e.env}
+	return State{context: e.context, namespace: e.namespace, env: e.env}
 }
 
 func (e Plugin) OnStart(env *starkit.Environment) error {
@@ -80,9 +81,10 @@ func (e Plugin) allowK8sContexts(thread *starlark.Thread, fn *starlark.Builtin,
 
 	err := starkit.SetState(thread, func(existing State) State {
 		return State{
-			context: existing.context,
-			env:     existing.env,
-			allowed: append(newContexts, existing.allowed...),
+			context:   existing.context,
+			namespace: existing.namespace,
+			env:       existing.env,
+			allowed:   append(newContexts, existing.allowed...),
 		}
 	})
 
@@ -92,15 +94,25 @@ func (e Plugin) allowK8sContexts(thread *starlark.Thread, fn *starlark.Builtin,
 var _ starkit.StatefulPlugin = &Plugin{}
 
 type State struct {
-	context k8s.KubeContext
-	env     clusterid.Product
-	allowed []k8s.KubeContext
+	context   k8s.KubeContext
+	namespace k8s.Namespace
+	env       clusterid.Product
+	allowed   []k8s.KubeContext
 }
 
 func (s State) KubeContext() k8s.KubeContext {
 	return s.context
 }
 
+// A struct describing the cluster, for evaluating config.enable_if() conditions.
+func (s State) Cluster() starlark.Value {
+	return starlarkstruct.FromStringDict(starlark.String("cluster"), starlark.StringDict{
+		"context":   starlark.String(s.context),
+		"namespace": starlark.String(s.namespace),
+		"product":   starlark.String(s.env),
+	})
+}
+
 // Returns whether we're allowed to deploy to this kubecontext.
 //
 // Checks against a manually specified list and a baked-in list
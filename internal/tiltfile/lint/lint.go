@@ -0,0 +1,624 @@
+// Package lint statically analyzes Tiltfiles without executing them.
+//
+// It catches mistakes that would otherwise only surface when Tilt loads the
+// Tiltfile against a live cluster: misspelled keyword arguments, literal
+// arguments of the wrong type, resources that can never be registered, and
+// missing files.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.starlark.net/syntax"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/internal/tiltfile"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+type Diagnostic struct {
+	Pos      syntax.Position
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Pos, d.Severity, d.Message)
+}
+
+// Built-ins that register resources (or the images/yaml that resources are assembled from).
+var resourceBuiltins = map[string]bool{
+	"custom_build":      true,
+	"dc_resource":       true,
+	"docker_build":      true,
+	"docker_compose":    true,
+	"k8s_custom_deploy": true,
+	"k8s_resource":      true,
+	"k8s_yaml":          true,
+	"local_resource":    true,
+}
+
+// Built-ins that never return.
+var terminatingBuiltins = map[string]bool{
+	"exit": true,
+	"fail": true,
+}
+
+type Linter struct {
+	sigs Signatures
+}
+
+func NewLinter(sigs Signatures) *Linter {
+	return &Linter{sigs: sigs}
+}
+
+// Creates a linter that checks calls against the Tiltfile API stubs bundled with Tilt.
+func ProvideLinter() (*Linter, error) {
+	sigs, err := ReadSignatures(tiltfile.ApiStubs())
+	if err != nil {
+		return nil, err
+	}
+	return NewLinter(sigs), nil
+}
+
+// Lint a Tiltfile, and any local Tiltfiles it loads or includes.
+//
+// Diagnostics are sorted by position.
+func (l *Linter) Lint(path string) ([]Diagnostic, error) {
+	fl := &fileLinter{linter: l, visited: make(map[string]bool)}
+	err := fl.lintFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(fl.diags, func(i, j int) bool {
+		a, b := fl.diags[i].Pos, fl.diags[j].Pos
+		if a.Filename() != b.Filename() {
+			return a.Filename() < b.Filename()
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	return fl.diags, nil
+}
+
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type fileLinter struct {
+	linter  *Linter
+	visited map[string]bool
+	diags   []Diagnostic
+
+	// State for the file currently being linted.
+	dir   string
+	bound map[string]bool
+}
+
+func (fl *fileLinter) report(pos syntax.Position, sev Severity, format string, args ...interface{}) {
+	fl.diags = append(fl.diags, Diagnostic{Pos: pos, Severity: sev, Message: fmt.Sprintf(format, args...)})
+}
+
+func (fl *fileLinter) lintFile(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if fl.visited[absPath] {
+		return nil
+	}
+	fl.visited[absPath] = true
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := syntax.Parse(path, contents, 0)
+	if err != nil {
+		var serr syntax.Error
+		if errors.As(err, &serr) {
+			fl.report(serr.Pos, SeverityError, "%s", serr.Msg)
+			return nil
+		}
+		return err
+	}
+
+	prevDir, prevBound := fl.dir, fl.bound
+	defer func() {
+		fl.dir, fl.bound = prevDir, prevBound
+	}()
+	fl.dir = filepath.Dir(path)
+	fl.bound = boundNames(f)
+
+	var children []string
+	for _, stmt := range f.Stmts {
+		if load, ok := stmt.(*syntax.LoadStmt); ok {
+			if p, ok := fl.localModulePath(load.Module.Value.(string)); ok {
+				children = append(children, p)
+			}
+		}
+	}
+
+	fl.checkStmts(f.Stmts)
+
+	syntax.Walk(f, func(n syntax.Node) bool {
+		call, ok := n.(*syntax.CallExpr)
+		if !ok {
+			return true
+		}
+		if name := fl.builtinName(call.Fn); name == "include" {
+			if s, ok := stringArg(call, 0, "path"); ok {
+				if p, ok := fl.localModulePath(s); ok {
+					children = append(children, p)
+				}
+			}
+		}
+		fl.checkCall(call)
+		return true
+	})
+
+	for _, child := range children {
+		if !ospath.IsRegularFile(child) {
+			continue
+		}
+		err := fl.lintFile(child)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolves a load() or include() path to a local file, if it is one.
+func (fl *fileLinter) localModulePath(module string) (string, bool) {
+	if strings.Contains(module, "://") || strings.HasPrefix(module, "@") {
+		return "", false
+	}
+	if filepath.IsAbs(module) {
+		return module, true
+	}
+	return filepath.Join(fl.dir, module), true
+}
+
+// Returns the fully-qualified name of the built-in being called,
+// or "" if the callee isn't a built-in (e.g., it's a user-defined function).
+func (fl *fileLinter) builtinName(fn syntax.Expr) string {
+	var parts []string
+	for {
+		switch x := fn.(type) {
+		case *syntax.Ident:
+			if fl.bound[x.Name] {
+				return ""
+			}
+			parts = append([]string{x.Name}, parts...)
+			return strings.Join(parts, ".")
+		case *syntax.DotExpr:
+			parts = append([]string{x.Name.Name}, parts...)
+			fn = x.X
+		default:
+			return ""
+		}
+	}
+}
+
+func (fl *fileLinter) checkCall(call *syntax.CallExpr) {
+	name := fl.builtinName(call.Fn)
+	sig, ok := fl.linter.sigs[name]
+	if !ok {
+		return
+	}
+
+	positional := sig.positionalParams()
+	nPositional := 0
+	for _, arg := range call.Args {
+		switch arg := arg.(type) {
+		case *syntax.UnaryExpr:
+			if arg.Op == syntax.STAR || arg.Op == syntax.STARSTAR {
+				// Can't statically analyze *args or **kwargs.
+				return
+			}
+		case *syntax.BinaryExpr:
+			if arg.Op == syntax.EQ {
+				kw := arg.X.(*syntax.Ident).Name
+				p, ok := sig.param(kw)
+				if !ok {
+					if !sig.KwArgs {
+						fl.report(arg.X.(*syntax.Ident).NamePos, SeverityError,
+							"%s: unexpected keyword argument %q%s", name, kw, suggest(kw, sig))
+					}
+					continue
+				}
+				fl.checkType(name, p, arg.Y)
+				continue
+			}
+		}
+
+		if nPositional < len(positional) {
+			fl.checkType(name, positional[nPositional], arg)
+		} else if !sig.VarArgs {
+			start, _ := arg.Span()
+			fl.report(start, SeverityError, "%s: got %d positional arguments, want at most %d",
+				name, countPositional(call), len(positional))
+			break
+		}
+		nPositional++
+	}
+
+	fl.checkFiles(name, call)
+}
+
+func countPositional(call *syntax.CallExpr) int {
+	n := 0
+	for _, arg := range call.Args {
+		if b, ok := arg.(*syntax.BinaryExpr); ok && b.Op == syntax.EQ {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// Suggest a parameter name for a misspelled keyword argument.
+func suggest(kw string, sig Signature) string {
+	best := ""
+	bestDist := len(kw)/3 + 1
+	for _, p := range sig.Params {
+		d := editDistance(kw, p.Name)
+		if d <= bestDist {
+			best, bestDist = p.Name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// Checks literal arguments against the type annotation in the stub.
+func (fl *fileLinter) checkType(fnName string, p Param, arg syntax.Expr) {
+	kind := literalKind(arg)
+	if kind == "" || p.Type == "" {
+		return
+	}
+	if !typeAccepts(p.Type, kind) {
+		start, _ := arg.Span()
+		fl.report(start, SeverityWarning, "%s: argument %q has type %s, want %s", fnName, p.Name, kind, p.Type)
+	}
+}
+
+// The Python type name of a literal expression, or "" if it's not a literal.
+func literalKind(e syntax.Expr) string {
+	switch e := e.(type) {
+	case *syntax.Literal:
+		switch e.Token {
+		case syntax.STRING:
+			return "str"
+		case syntax.INT:
+			return "int"
+		case syntax.FLOAT:
+			return "float"
+		}
+	case *syntax.ListExpr:
+		return "list"
+	case *syntax.DictExpr:
+		return "dict"
+	case *syntax.Ident:
+		if e.Name == "True" || e.Name == "False" {
+			return "bool"
+		}
+	}
+	return ""
+}
+
+// Reports whether a literal of the given kind satisfies a Python type annotation.
+//
+// Unknown types (e.g., classes like Blob) accept anything, to avoid false positives.
+func typeAccepts(typ string, kind string) bool {
+	typ = strings.TrimSpace(typ)
+	base, inner := typ, ""
+	if i := strings.Index(typ, "["); i != -1 && strings.HasSuffix(typ, "]") {
+		base, inner = typ[:i], typ[i+1:len(typ)-1]
+	}
+
+	switch base {
+	case "Union", "Optional":
+		for _, t := range splitTopLevel(inner, ',') {
+			if typeAccepts(t, kind) {
+				return true
+			}
+		}
+		return false
+	case "str":
+		return kind == "str"
+	case "int":
+		return kind == "int"
+	case "float":
+		return kind == "int" || kind == "float"
+	case "bool":
+		return kind == "bool"
+	case "List", "list":
+		return kind == "list"
+	case "Dict", "dict":
+		return kind == "dict"
+	}
+	return true
+}
+
+// Checks that files referenced by literal paths exist.
+func (fl *fileLinter) checkFiles(name string, call *syntax.CallExpr) {
+	switch name {
+	case "docker_build":
+		context, ok := stringArg(call, 1, "context")
+		if !ok {
+			return
+		}
+		contextPath := fl.abs(context)
+		if !ospath.IsDir(contextPath) {
+			fl.reportMissing(call, 1, "context", "docker_build: context directory %q does not exist", context)
+			return
+		}
+
+		if _, ok := findArg(call, -1, "dockerfile_contents"); ok {
+			return
+		}
+		if dockerfile, ok := stringArg(call, -1, "dockerfile"); ok {
+			if !ospath.IsRegularFile(fl.abs(dockerfile)) {
+				fl.reportMissing(call, -1, "dockerfile", "docker_build: dockerfile %q does not exist", dockerfile)
+			}
+		} else if _, ok := findArg(call, -1, "dockerfile"); !ok {
+			if !ospath.IsRegularFile(filepath.Join(contextPath, "Dockerfile")) {
+				fl.reportMissing(call, 1, "context", "docker_build: no Dockerfile in context directory %q", context)
+			}
+		}
+
+	case "k8s_yaml":
+		arg, ok := findArg(call, 0, "yaml")
+		if !ok {
+			return
+		}
+		for _, e := range stringLiterals(arg) {
+			path := e.Value.(string)
+			if !ospath.IsRegularFile(fl.abs(path)) {
+				fl.report(e.TokenPos, SeverityError, "k8s_yaml: file %q does not exist", path)
+			}
+		}
+	}
+}
+
+func (fl *fileLinter) reportMissing(call *syntax.CallExpr, pos int, kw string, format string, args ...interface{}) {
+	at := call.Lparen
+	if arg, ok := findArg(call, pos, kw); ok {
+		at, _ = arg.Span()
+	}
+	fl.report(at, SeverityError, format, args...)
+}
+
+func (fl *fileLinter) abs(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(fl.dir, p)
+}
+
+// Finds an argument by position, or by keyword.
+// Pass a negative position for keyword-only lookups.
+func findArg(call *syntax.CallExpr, pos int, kw string) (syntax.Expr, bool) {
+	i := 0
+	for _, arg := range call.Args {
+		if b, ok := arg.(*syntax.BinaryExpr); ok && b.Op == syntax.EQ {
+			if b.X.(*syntax.Ident).Name == kw {
+				return b.Y, true
+			}
+			continue
+		}
+		if i == pos {
+			return arg, true
+		}
+		i++
+	}
+	return nil, false
+}
+
+func stringArg(call *syntax.CallExpr, pos int, kw string) (string, bool) {
+	arg, ok := findArg(call, pos, kw)
+	if !ok {
+		return "", false
+	}
+	lit, ok := arg.(*syntax.Literal)
+	if !ok || lit.Token != syntax.STRING {
+		return "", false
+	}
+	return lit.Value.(string), true
+}
+
+// Returns the string literals in e, if e is a string literal or a list of them.
+func stringLiterals(e syntax.Expr) []*syntax.Literal {
+	switch e := e.(type) {
+	case *syntax.Literal:
+		if e.Token == syntax.STRING {
+			return []*syntax.Literal{e}
+		}
+	case *syntax.ListExpr:
+		var result []*syntax.Literal
+		for _, item := range e.List {
+			result = append(result, stringLiterals(item)...)
+		}
+		return result
+	}
+	return nil
+}
+
+// Checks a block of statements for code that can never run.
+func (fl *fileLinter) checkStmts(stmts []syntax.Stmt) {
+	for i, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *syntax.DefStmt:
+			fl.checkStmts(stmt.Body)
+		case *syntax.IfStmt:
+			fl.checkStmts(stmt.True)
+			fl.checkStmts(stmt.False)
+		case *syntax.ForStmt:
+			fl.checkStmts(stmt.Body)
+		case *syntax.WhileStmt:
+			fl.checkStmts(stmt.Body)
+		}
+
+		if fl.terminates(stmt) && i+1 < len(stmts) {
+			fl.reportUnreachable(stmts[i+1:])
+			return
+		}
+	}
+}
+
+func (fl *fileLinter) terminates(stmt syntax.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *syntax.ReturnStmt:
+		return true
+	case *syntax.BranchStmt:
+		return stmt.Token == syntax.BREAK || stmt.Token == syntax.CONTINUE
+	case *syntax.ExprStmt:
+		call, ok := stmt.X.(*syntax.CallExpr)
+		return ok && terminatingBuiltins[fl.builtinName(call.Fn)]
+	}
+	return false
+}
+
+func (fl *fileLinter) reportUnreachable(stmts []syntax.Stmt) {
+	foundResource := false
+	for _, stmt := range stmts {
+		syntax.Walk(stmt, func(n syntax.Node) bool {
+			call, ok := n.(*syntax.CallExpr)
+			if !ok {
+				return true
+			}
+			name := fl.builtinName(call.Fn)
+			if !resourceBuiltins[name] {
+				return true
+			}
+			foundResource = true
+			start, _ := call.Span()
+			if resName, ok := stringArg(call, 0, "name"); ok {
+				fl.report(start, SeverityWarning, "unreachable resource: %s(%q) is never registered", name, resName)
+			} else {
+				fl.report(start, SeverityWarning, "unreachable resource: %s() is never registered", name)
+			}
+			return true
+		})
+	}
+
+	if !foundResource {
+		start, _ := stmts[0].Span()
+		fl.report(start, SeverityWarning, "unreachable code")
+	}
+}
+
+// Collects every name the file binds (by def, assignment, load, or parameter),
+// so that user-defined functions that shadow built-ins aren't checked against
+// the built-in signatures.
+func boundNames(f *syntax.File) map[string]bool {
+	result := make(map[string]bool)
+	var bindTarget func(e syntax.Expr)
+	bindTarget = func(e syntax.Expr) {
+		switch e := e.(type) {
+		case *syntax.Ident:
+			result[e.Name] = true
+		case *syntax.TupleExpr:
+			for _, x := range e.List {
+				bindTarget(x)
+			}
+		case *syntax.ListExpr:
+			for _, x := range e.List {
+				bindTarget(x)
+			}
+		case *syntax.ParenExpr:
+			bindTarget(e.X)
+		}
+	}
+
+	syntax.Walk(f, func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.DefStmt:
+			result[n.Name.Name] = true
+			for _, p := range n.Params {
+				switch p := p.(type) {
+				case *syntax.Ident:
+					result[p.Name] = true
+				case *syntax.BinaryExpr:
+					bindTarget(p.X)
+				case *syntax.UnaryExpr:
+					if p.X != nil {
+						bindTarget(p.X)
+					}
+				}
+			}
+		case *syntax.AssignStmt:
+			bindTarget(n.LHS)
+		case *syntax.ForStmt:
+			bindTarget(n.Vars)
+		case *syntax.ForClause:
+			bindTarget(n.Vars)
+		case *syntax.LambdaExpr:
+			for _, p := range n.Params {
+				if id, ok := p.(*syntax.Ident); ok {
+					result[id.Name] = true
+				}
+			}
+		case *syntax.LoadStmt:
+			for _, to := range n.To {
+				result[to.Name] = true
+			}
+		}
+		return true
+	})
+	return result
+}
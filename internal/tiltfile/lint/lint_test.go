@@ -0,0 +1,198 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestReadSignatures(t *testing.T) {
+	l, err := ProvideLinter()
+	require.NoError(t, err)
+
+	sig, ok := l.sigs["docker_build"]
+	require.True(t, ok)
+	p, ok := sig.param("dockerfile")
+	require.True(t, ok)
+	assert.Equal(t, Param{Name: "dockerfile", Type: "str", Optional: true}, p)
+
+	sig, ok = l.sigs["k8s_kind"]
+	require.True(t, ok)
+	p, ok = sig.param("image_json_path")
+	require.True(t, ok)
+	assert.True(t, p.KeywordOnly)
+
+	_, ok = l.sigs["os.path.exists"]
+	assert.True(t, ok)
+	_, ok = l.sigs["v1alpha1.ignore_def"]
+	assert.True(t, ok)
+}
+
+func TestParseStub(t *testing.T) {
+	sigs, err := parseStub(`
+def foo(a: str,
+        b: Dict[str, str] = {"x": "y"},
+        *args,
+        c: Union[str, List[str]] = [], **kwargs) -> None:
+  """Doc"""
+
+class Bar:
+  def method(self, x) -> None:
+    pass
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []Signature{
+		{
+			Name: "foo",
+			Params: []Param{
+				{Name: "a", Type: "str"},
+				{Name: "b", Type: "Dict[str, str]", Optional: true},
+				{Name: "c", Type: "Union[str, List[str]]", Optional: true, KeywordOnly: true},
+			},
+			VarArgs: true,
+			KwArgs:  true,
+		},
+	}, sigs)
+}
+
+func TestUnknownKwarg(t *testing.T) {
+	f := newFixture(t)
+	f.file("Dockerfile", "FROM alpine")
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', '.', dockerfle='Dockerfile')
+`)
+	f.assertDiags(
+		`Tiltfile:2:33: error: docker_build: unexpected keyword argument "dockerfle" (did you mean "dockerfile"?)`,
+	)
+}
+
+func TestTooManyPositional(t *testing.T) {
+	f := newFixture(t)
+	f.file("Tiltfile", `
+k8s_context('foo')
+`)
+	f.assertDiags(
+		`Tiltfile:2:13: error: k8s_context: got 1 positional arguments, want at most 0`,
+	)
+}
+
+func TestLiteralTypes(t *testing.T) {
+	f := newFixture(t)
+	f.file("Tiltfile", `
+local_resource('foo', cmd='echo hi', auto_init='false', labels=['a'])
+`)
+	f.assertDiags(
+		`Tiltfile:2:48: warning: local_resource: argument "auto_init" has type str, want bool`,
+	)
+}
+
+func TestShadowedBuiltin(t *testing.T) {
+	f := newFixture(t)
+	f.file("Tiltfile", `
+def local_resource(name, **kwargs):
+  pass
+
+local_resource('foo', anything='goes')
+`)
+	f.assertDiags()
+}
+
+func TestMissingFiles(t *testing.T) {
+	f := newFixture(t)
+	f.file("app/Dockerfile", "FROM alpine")
+	f.file("k8s/app.yaml", "")
+	f.file("Tiltfile", `
+k8s_yaml(['k8s/app.yaml', 'k8s/db.yaml'])
+docker_build('gcr.io/app', 'app')
+docker_build('gcr.io/web', 'web')
+docker_build('gcr.io/db', '.')
+docker_build('gcr.io/api', 'app', dockerfile='api.Dockerfile')
+`)
+	f.assertDiags(
+		`Tiltfile:2:27: error: k8s_yaml: file "k8s/db.yaml" does not exist`,
+		`Tiltfile:4:28: error: docker_build: context directory "web" does not exist`,
+		`Tiltfile:5:27: error: docker_build: no Dockerfile in context directory "."`,
+		`Tiltfile:6:46: error: docker_build: dockerfile "api.Dockerfile" does not exist`,
+	)
+}
+
+func TestUnreachable(t *testing.T) {
+	f := newFixture(t)
+	f.file("Tiltfile", `
+def setup():
+  return
+  print('hi')
+
+fail('unsupported')
+local_resource('foo', cmd='echo hi')
+`)
+	f.assertDiags(
+		`Tiltfile:4:3: warning: unreachable code`,
+		`Tiltfile:7:1: warning: unreachable resource: local_resource("foo") is never registered`,
+	)
+}
+
+func TestLoadedFiles(t *testing.T) {
+	f := newFixture(t)
+	f.file("lib/Tiltfile", `
+def helper():
+  k8s_yaml('missing.yaml')
+`)
+	f.file("Tiltfile", `
+load('./lib/Tiltfile', 'helper')
+load('ext://restart_process', 'docker_build_with_restart')
+helper()
+`)
+	f.assertDiags(
+		`lib/Tiltfile:3:12: error: k8s_yaml: file "missing.yaml" does not exist`,
+	)
+}
+
+func TestSyntaxError(t *testing.T) {
+	f := newFixture(t)
+	f.file("Tiltfile", `
+local_resource('foo'
+`)
+	diags := f.lint()
+	require.Len(t, diags, 1)
+	assert.Equal(t, SeverityError, diags[0].Severity)
+	assert.True(t, HasErrors(diags))
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	t *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+	return &fixture{TempDirFixture: f, t: t}
+}
+
+func (f *fixture) file(path, contents string) {
+	f.WriteFile(path, contents)
+}
+
+func (f *fixture) lint() []Diagnostic {
+	l, err := ProvideLinter()
+	require.NoError(f.t, err)
+	diags, err := l.Lint("Tiltfile")
+	require.NoError(f.t, err)
+	return diags
+}
+
+func (f *fixture) assertDiags(expected ...string) {
+	f.t.Helper()
+	actual := []string{}
+	for _, d := range f.lint() {
+		actual = append(actual, d.String())
+	}
+	if expected == nil {
+		expected = []string{}
+	}
+	assert.Equal(f.t, expected, actual)
+}
@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Param describes a single parameter of a Tiltfile built-in,
+// as declared in the API stubs.
+type Param struct {
+	Name string
+
+	// The Python type annotation from the stub (e.g., "Union[str, List[str]]").
+	// May be empty.
+	Type string
+
+	// Whether the param has a default value.
+	Optional bool
+
+	// Whether the param may only be passed by keyword.
+	KeywordOnly bool
+}
+
+// Signature describes a Tiltfile built-in, as declared in the API stubs.
+type Signature struct {
+	// The fully-qualified name of the built-in (e.g., "docker_build" or "os.getcwd").
+	Name   string
+	Params []Param
+
+	// Whether the built-in accepts *args.
+	VarArgs bool
+
+	// Whether the built-in accepts **kwargs.
+	KwArgs bool
+}
+
+func (s Signature) param(name string) (Param, bool) {
+	for _, p := range s.Params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Param{}, false
+}
+
+func (s Signature) positionalParams() []Param {
+	var result []Param
+	for _, p := range s.Params {
+		if !p.KeywordOnly {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Signatures indexes built-in signatures by fully-qualified name.
+type Signatures map[string]Signature
+
+// Reads the signatures of all top-level functions in a tree of API stubs.
+//
+// Stubs in sub-directories are namespaced as modules, so that
+// os/path.py declares "os.path.exists".
+func ReadSignatures(stubs fs.FS) (Signatures, error) {
+	result := Signatures{}
+	err := fs.WalkDir(stubs, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".py" {
+			return nil
+		}
+
+		contents, err := fs.ReadFile(stubs, p)
+		if err != nil {
+			return err
+		}
+
+		prefix := modulePrefix(p)
+		sigs, err := parseStub(string(contents))
+		if err != nil {
+			return fmt.Errorf("parsing %s: %v", p, err)
+		}
+		for _, sig := range sigs {
+			sig.Name = prefix + sig.Name
+			result[sig.Name] = sig
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// "__init__.py" -> "", "os/__init__.py" -> "os.", "os/path.py" -> "os.path."
+func modulePrefix(p string) string {
+	p = strings.TrimSuffix(p, ".py")
+	p = strings.TrimSuffix(p, "__init__")
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return ""
+	}
+	return strings.ReplaceAll(p, "/", ".") + "."
+}
+
+// Parses all top-level function definitions out of a Python stub file.
+//
+// The stubs are not Starlark (they have type annotations), so we do
+// a simple paren-balancing parse rather than using a real parser.
+func parseStub(contents string) ([]Signature, error) {
+	var result []Signature
+	rest := "\n" + contents
+	for {
+		idx := strings.Index(rest, "\ndef ")
+		if idx == -1 {
+			return result, nil
+		}
+		rest = rest[idx+len("\ndef "):]
+
+		lparen := strings.Index(rest, "(")
+		if lparen == -1 {
+			return nil, fmt.Errorf("malformed def: %.40q", rest)
+		}
+		name := strings.TrimSpace(rest[:lparen])
+
+		rparen, err := matchingParen(rest, lparen)
+		if err != nil {
+			return nil, fmt.Errorf("def %s: %v", name, err)
+		}
+
+		sig := Signature{Name: name}
+		keywordOnly := false
+		for _, raw := range splitTopLevel(rest[lparen+1:rparen], ',') {
+			raw = strings.TrimSpace(raw)
+			switch {
+			case raw == "":
+				continue
+			case raw == "*":
+				keywordOnly = true
+				continue
+			case strings.HasPrefix(raw, "**"):
+				sig.KwArgs = true
+				continue
+			case strings.HasPrefix(raw, "*"):
+				sig.VarArgs = true
+				keywordOnly = true
+				continue
+			}
+
+			p := Param{KeywordOnly: keywordOnly}
+			decl := raw
+			if parts := splitTopLevel(raw, '='); len(parts) > 1 {
+				decl = parts[0]
+				p.Optional = true
+			}
+			if colon := strings.Index(decl, ":"); colon != -1 {
+				p.Type = strings.TrimSpace(decl[colon+1:])
+				decl = decl[:colon]
+			}
+			p.Name = strings.TrimSpace(decl)
+			sig.Params = append(sig.Params, p)
+		}
+
+		result = append(result, sig)
+		rest = rest[rparen:]
+	}
+}
+
+func matchingParen(s string, lparen int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := lparen; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("unbalanced parens")
+}
+
+// Splits s on sep, ignoring separators nested in brackets or quotes.
+func splitTopLevel(s string, sep byte) []string {
+	var result []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				result = append(result, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, s[start:])
+}
@@ -3,6 +3,8 @@ package tiltfile
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
@@ -35,6 +37,10 @@ type localResource struct {
 	labels        map[string]string
 
 	readinessProbe *v1alpha1.Probe
+
+	serveReadyRegex    string
+	serveRestartPolicy v1alpha1.CmdRestartPolicy
+	serveMaxRestarts   int32
 }
 
 func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -44,8 +50,24 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 	var triggerMode triggerMode
 	var readinessProbe probe.Probe
 	var updateCmdDirVal, serveCmdDirVal starlark.Value
+	var timeoutVal starlark.Value
+	var serveGracePeriodVal starlark.Value
+	var serveTerminationSignal string
+	var servePTY bool
+	var serveStdin bool
+	var serveReadyRegex string
+	var serveRestartPolicy string
+	var serveMaxRestarts int
+	var serveSSHHost string
+	var serveSSHForwardAgent bool
+	var serveContainer string
+	var serveDCResource string
 
 	deps := value.NewLocalPathListUnpacker(thread)
+	envFiles := value.NewLocalPathListUnpacker(thread)
+	serveEnvFiles := value.NewLocalPathListUnpacker(thread)
+	serveSSHKeyFile := value.NewLocalPathUnpacker(thread)
+	var expandEnv bool
 
 	var resourceDepsVal starlark.Sequence
 	var ignoresVal starlark.Value
@@ -78,6 +100,22 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		"readiness_probe?", &readinessProbe,
 		"dir?", &updateCmdDirVal,
 		"serve_dir?", &serveCmdDirVal,
+		"timeout?", &timeoutVal,
+		"serve_grace_period?", &serveGracePeriodVal,
+		"serve_termination_signal?", &serveTerminationSignal,
+		"serve_pty?", &servePTY,
+		"serve_restart_policy?", &serveRestartPolicy,
+		"serve_max_restarts?", &serveMaxRestarts,
+		"serve_stdin?", &serveStdin,
+		"serve_ready_regex?", &serveReadyRegex,
+		"env_file?", &envFiles,
+		"serve_env_file?", &serveEnvFiles,
+		"expand_env?", &expandEnv,
+		"serve_ssh_host?", &serveSSHHost,
+		"serve_ssh_key_file?", &serveSSHKeyFile,
+		"serve_ssh_forward_agent?", &serveSSHForwardAgent,
+		"serve_container?", &serveContainer,
+		"serve_dc_resource?", &serveDCResource,
 	); err != nil {
 		return nil, err
 	}
@@ -101,10 +139,94 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		return nil, err
 	}
 
+	updateCmd.EnvFiles = envFiles.Value
+	serveCmd.EnvFiles = serveEnvFiles.Value
+	updateCmd.ExpandEnv = expandEnv
+	serveCmd.ExpandEnv = expandEnv
+
+	if timeoutVal != nil && timeoutVal != starlark.None {
+		var timeout value.Duration
+		err := timeout.Unpack(timeoutVal)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter timeout: %v", fn.Name(), err)
+		}
+		if timeout.AsDuration() < 0 {
+			return nil, fmt.Errorf("%s: timeout must not be negative", fn.Name())
+		}
+		updateCmd.Timeout = timeout.AsDuration()
+	}
+
+	if serveGracePeriodVal != nil && serveGracePeriodVal != starlark.None {
+		var gracePeriod value.Duration
+		err := gracePeriod.Unpack(serveGracePeriodVal)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter serve_grace_period: %v", fn.Name(), err)
+		}
+		if gracePeriod.AsDuration() < 0 {
+			return nil, fmt.Errorf("%s: serve_grace_period must not be negative", fn.Name())
+		}
+		d := gracePeriod.AsDuration()
+		serveCmd.GracePeriod = &d
+	}
+
+	if serveTerminationSignal != "" {
+		serveCmd.TerminationSignal, err = terminationSignal(serveTerminationSignal)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter serve_termination_signal: %v", fn.Name(), err)
+		}
+	}
+
+	serveCmd.PTY = servePTY
+	serveCmd.Stdin = serveStdin
+
+	if serveSSHHost != "" {
+		serveCmd.Target = &v1alpha1.CmdTarget{SSH: &v1alpha1.CmdSSHTarget{
+			Host:         serveSSHHost,
+			KeyFile:      serveSSHKeyFile.Value,
+			ForwardAgent: serveSSHForwardAgent,
+		}}
+	} else if serveSSHKeyFile.Value != "" || serveSSHForwardAgent {
+		return nil, fmt.Errorf("%s: serve_ssh_key_file and serve_ssh_forward_agent need a serve_ssh_host", fn.Name())
+	}
+
+	targets := 0
+	for _, t := range []string{serveSSHHost, serveContainer, serveDCResource} {
+		if t != "" {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return nil, fmt.Errorf("%s: only one of serve_ssh_host, serve_container, and serve_dc_resource can be set", fn.Name())
+	}
+	if serveContainer != "" {
+		serveCmd.Target = &v1alpha1.CmdTarget{Container: &v1alpha1.CmdContainerTarget{Name: serveContainer}}
+	}
+	if serveDCResource != "" {
+		serveCmd.Target = &v1alpha1.CmdTarget{Container: &v1alpha1.CmdContainerTarget{DockerComposeService: serveDCResource}}
+	}
+
+	var restartPolicy v1alpha1.CmdRestartPolicy
+	if serveRestartPolicy != "" {
+		restartPolicy, err = cmdRestartPolicy(serveRestartPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter serve_restart_policy: %v", fn.Name(), err)
+		}
+	}
+	if serveMaxRestarts < 0 {
+		return nil, fmt.Errorf("%s: serve_max_restarts must not be negative", fn.Name())
+	}
+
 	if updateCmd.Empty() && serveCmd.Empty() {
 		return nil, fmt.Errorf("local_resource must have a cmd and/or a serve_cmd, but both were empty")
 	}
 
+	if serveReadyRegex != "" {
+		_, err := regexp.Compile(serveReadyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter serve_ready_regex: %v", fn.Name(), err)
+		}
+	}
+
 	probeSpec := readinessProbe.Spec()
 	if probeSpec != nil && serveCmd.Empty() {
 		s.logger.Warnf("Ignoring readiness probe for local resource %q (no serve_cmd was defined)", name)
@@ -125,6 +247,10 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		links:          links.Links,
 		labels:         labels.Values,
 		readinessProbe: probeSpec,
+
+		serveReadyRegex:    serveReadyRegex,
+		serveRestartPolicy: restartPolicy,
+		serveMaxRestarts:   int32(serveMaxRestarts),
 	}
 
 	// check for duplicate resources by name and throw error if found
@@ -137,3 +263,32 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 
 	return starlark.None, nil
 }
+
+// Accepts signal names with or without the SIG prefix, like "INT" or "SIGINT".
+func terminationSignal(name string) (string, error) {
+	sig := strings.ToUpper(name)
+	if !strings.HasPrefix(sig, "SIG") {
+		sig = "SIG" + sig
+	}
+	for _, s := range v1alpha1.CmdTerminationSignals {
+		if s == sig {
+			return sig, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported signal %q (must be one of: %s)",
+		name, strings.Join(v1alpha1.CmdTerminationSignals, ", "))
+}
+
+var cmdRestartPolicies = map[string]v1alpha1.CmdRestartPolicy{
+	"always":     v1alpha1.CmdRestartPolicyAlways,
+	"on-failure": v1alpha1.CmdRestartPolicyOnFailure,
+	"never":      v1alpha1.CmdRestartPolicyNever,
+}
+
+func cmdRestartPolicy(name string) (v1alpha1.CmdRestartPolicy, error) {
+	policy, ok := cmdRestartPolicies[name]
+	if !ok {
+		return "", fmt.Errorf("unsupported restart policy %q (must be one of: always, on-failure, never)", name)
+	}
+	return policy, nil
+}
@@ -0,0 +1,189 @@
+// Package localcache caches the output of local() commands on disk,
+// so that expensive load-time commands don't re-run on every Tiltfile reload.
+//
+// Entries are content-addressed: the key is a hash of the command and
+// everything the user says it depends on. Identical inputs map to the same
+// entry, even across branches or from different Tiltfiles in the same project.
+package localcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The cache lives under .tilt, next to the main Tiltfile.
+const relDir = ".tilt/cache/local"
+
+// Bump when the entry format or the key derivation changes,
+// so that stale entries are never read.
+const version = 1
+
+type Cache struct {
+	dir string
+}
+
+// New returns the cache for the project whose main Tiltfile is in projectDir.
+func New(projectDir string) Cache {
+	return Cache{dir: filepath.Join(projectDir, filepath.FromSlash(relDir))}
+}
+
+func (c Cache) Dir() string {
+	return c.dir
+}
+
+// Files under the cache dir should never trigger rebuilds or reloads.
+func IgnoreFor(projectDir string) model.Dockerignore {
+	return model.Dockerignore{
+		LocalPath: projectDir,
+		Patterns:  []string{filepath.Dir(filepath.FromSlash(relDir))},
+		Source:    "local() cache",
+		Reason:    "Tilt's own cache of local() results",
+	}
+}
+
+// Inputs determine the cache key for a local() call.
+type Inputs struct {
+	Cmd   model.Cmd
+	Stdin string
+
+	// A user-supplied key, e.g., a version string.
+	Key string
+
+	// Files and directories whose contents the output depends on.
+	Paths []string
+}
+
+type keyData struct {
+	Version int
+	Argv    []string
+	Dir     string
+	Env     []string
+	Stdin   string
+	Key     string
+	Paths   map[string]string
+}
+
+// Computes the content-addressed key for a set of inputs.
+func (in Inputs) Hash() (string, error) {
+	env := append([]string{}, in.Cmd.Env...)
+	sort.Strings(env)
+
+	data := keyData{
+		Version: version,
+		Argv:    in.Cmd.Argv,
+		Dir:     in.Cmd.Dir,
+		Env:     env,
+		Stdin:   in.Stdin,
+		Key:     in.Key,
+		Paths:   make(map[string]string, len(in.Paths)),
+	}
+
+	for _, p := range in.Paths {
+		h, err := hashPath(p)
+		if err != nil {
+			return "", err
+		}
+
+		// Paths are keyed relative to the working dir, so that the key
+		// doesn't depend on how the path was spelled.
+		rel, err := filepath.Rel(in.Cmd.Dir, p)
+		if err != nil {
+			rel = p
+		}
+		data.Paths[filepath.ToSlash(rel)] = h
+	}
+
+	// encoding/json sorts map keys, so this is deterministic.
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Hashes the contents of a file, or of all the files under a directory.
+func hashPath(p string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(p, path)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+
+		_, err = io.Copy(h, f)
+		if err != nil {
+			return err
+		}
+		_, _ = h.Write([]byte{0})
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hashing cache input: %v", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type entry struct {
+	Command string `json:"command"`
+	Stdout  string `json:"stdout"`
+}
+
+func (c Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the cached stdout for a key, if there is one.
+//
+// A corrupt entry is treated as a miss.
+func (c Cache) Get(key string) (string, bool, error) {
+	b, err := os.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	var e entry
+	err = json.Unmarshal(b, &e)
+	if err != nil {
+		return "", false, nil
+	}
+	return e.Stdout, true, nil
+}
+
+// Put stores the stdout of a successful command.
+//
+// Entries are written atomically, so that concurrent Tilt processes
+// never see a partial entry.
+func (c Cache) Put(key string, cmd model.Cmd, stdout string) error {
+	err := os.MkdirAll(c.dir, 0755)
+	if err != nil {
+		return err
+	}
+	return xdg.WriteJSONFile(c.path(key), entry{Command: cmd.String(), Stdout: stdout})
+}
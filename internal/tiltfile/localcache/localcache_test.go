@@ -0,0 +1,111 @@
+package localcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/dockerignore"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestGetPut(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	c := New(f.Path())
+	cmd := model.Cmd{Argv: []string{"helm", "dependency", "build"}, Dir: f.Path()}
+
+	_, ok, err := c.Get("abc")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put("abc", cmd, "Saving 1 charts\n"))
+
+	out, ok, err := c.Get("abc")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Saving 1 charts\n", out)
+}
+
+func TestCorruptEntryIsAMiss(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	c := New(f.Path())
+	f.WriteFile(filepath.Join(".tilt", "cache", "local", "abc.json"), "{")
+
+	_, ok, err := c.Get("abc")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHash(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("chart/Chart.yaml", "name: foo")
+	f.WriteFile("chart/charts/dep.yaml", "name: dep")
+
+	cmd := model.Cmd{Argv: []string{"helm", "dependency", "build"}, Dir: f.Path()}
+	base := Inputs{Cmd: cmd, Key: "v1", Paths: []string{f.JoinPath("chart")}}
+	h1 := mustHash(t, base)
+
+	assert.Equal(t, h1, mustHash(t, base), "hash should be deterministic")
+
+	withKey := base
+	withKey.Key = "v2"
+	assert.NotEqual(t, h1, mustHash(t, withKey))
+
+	withStdin := base
+	withStdin.Stdin = "hello"
+	assert.NotEqual(t, h1, mustHash(t, withStdin))
+
+	withEnv := base
+	withEnv.Cmd.Env = []string{"FOO=bar"}
+	assert.NotEqual(t, h1, mustHash(t, withEnv))
+
+	f.WriteFile("chart/charts/dep.yaml", "name: dep2")
+	assert.NotEqual(t, h1, mustHash(t, base))
+}
+
+func TestHashEnvOrder(t *testing.T) {
+	a := Inputs{Cmd: model.Cmd{Argv: []string{"make"}, Env: []string{"A=1", "B=2"}}}
+	b := Inputs{Cmd: model.Cmd{Argv: []string{"make"}, Env: []string{"B=2", "A=1"}}}
+	assert.Equal(t, mustHash(t, a), mustHash(t, b))
+}
+
+func TestHashMissingInput(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	in := Inputs{Cmd: model.Cmd{Argv: []string{"make"}, Dir: f.Path()}, Paths: []string{f.JoinPath("missing")}}
+
+	_, err := in.Hash()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hashing cache input")
+}
+
+func TestIgnoreFor(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	c := New(f.Path())
+	require.NoError(t, c.Put("abc", model.Cmd{Argv: []string{"make"}}, ""))
+
+	ignore := IgnoreFor(f.Path())
+	m, err := dockerignore.NewDockerPatternMatcher(ignore.LocalPath, ignore.Patterns)
+	require.NoError(t, err)
+
+	matches, err := m.Matches(filepath.Join(c.Dir(), "abc.json"))
+	require.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = m.Matches(f.JoinPath("Tiltfile"))
+	require.NoError(t, err)
+	assert.False(t, matches)
+
+	_, err = os.Stat(filepath.Join(c.Dir(), "abc.json"))
+	require.NoError(t, err)
+}
+
+func mustHash(t *testing.T, in Inputs) string {
+	t.Helper()
+	h, err := in.Hash()
+	require.NoError(t, err)
+	return h
+}
@@ -0,0 +1,384 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// User-defined fields are namespaced, so that they can't collide
+// with the fields Tilt uses to render logs (like progressID).
+const fieldPrefix = "tiltfile."
+
+// Settings record the log levels set with log.resource_level(), the
+// resources set with log.keep_repeats(), and the ANSI modes set with
+// log.ansi().
+type Settings struct {
+	Resources map[model.ManifestName]model.LogLevels
+
+	KeepRepeats map[model.ManifestName]bool
+
+	ANSI map[model.ManifestName]model.LogANSIMode
+}
+
+// Sets LogLevels on each manifest with a log level, KeepRepeatedLogs
+// on each manifest that keeps its repeated lines, and LogANSI on each
+// manifest with an ANSI mode.
+//
+// Returns an error if any of them was set for a resource that doesn't exist.
+func (s Settings) ApplyToManifests(manifests []model.Manifest) error {
+	var unknown []string
+	for name, levels := range s.Resources {
+		i := manifestIndex(manifests, name)
+		if i == -1 {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		manifests[i].LogLevels = levels
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("log.resource_level: unknown resources: %s", strings.Join(unknown, ", "))
+	}
+
+	for name := range s.KeepRepeats {
+		i := manifestIndex(manifests, name)
+		if i == -1 {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		manifests[i].KeepRepeatedLogs = true
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("log.keep_repeats: unknown resources: %s", strings.Join(unknown, ", "))
+	}
+
+	for name, mode := range s.ANSI {
+		i := manifestIndex(manifests, name)
+		if i == -1 {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+			continue
+		}
+		manifests[i].LogANSI = mode
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("log.ansi: unknown resources: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func manifestIndex(manifests []model.Manifest, name model.ManifestName) int {
+	for i, m := range manifests {
+		if m.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Implements the log module, for leveled, structured logging from Tiltfiles,
+// log.resource_level() for turning down chatty resources,
+// log.keep_repeats() for resources whose repeated lines matter, and
+// log.ansi() for resources whose colors get in the way.
+//
+// Logs are written to the Tiltfile's logger, so they're attributed
+// to the Tiltfile's span in the logstore, like print().
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (Plugin) NewState() interface{} {
+	return Settings{}
+}
+
+func (Plugin) OnStart(env *starkit.Environment) error {
+	for _, b := range []struct {
+		name  string
+		level logger.Level
+	}{
+		{"log.debug", logger.DebugLvl},
+		{"log.info", logger.InfoLvl},
+		{"log.warn", logger.WarnLvl},
+		{"log.error", logger.ErrorLvl},
+	} {
+		err := env.AddBuiltin(b.name, logAtLevel(b.level))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := env.AddBuiltin("log.section", section)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("log.resource_level", resourceLevel)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("log.keep_repeats", keepRepeats)
+	if err != nil {
+		return err
+	}
+	return env.AddBuiltin("log.ansi", ansi)
+}
+
+func logAtLevel(level logger.Level) starkit.Function {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		msg, fields, err := unpackMessage(fn, args, kwargs)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, err := starkit.ContextFromThread(thread)
+		if err != nil {
+			return nil, err
+		}
+
+		l := logger.Get(ctx)
+		if len(fields) > 0 {
+			l = l.WithFields(fields.namespaced())
+		}
+
+		text := msg + fields.String()
+		switch level {
+		case logger.DebugLvl:
+			l.Debugf("%s", text)
+		case logger.WarnLvl:
+			l.Warnf("%s", text)
+		case logger.ErrorLvl:
+			l.Errorf("%s", text)
+		default:
+			l.Infof("%s", text)
+		}
+		return starlark.None, nil
+	}
+}
+
+// Starts a new section of the load logs, rendered as a header.
+func section(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var title string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "title", &title)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := starkit.ContextFromThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sections are rendered like build headers.
+	logger.Get(ctx).WithFields(logger.Fields{logger.FieldNameBuildEvent: "init"}).Infof("%s", title)
+	return starlark.None, nil
+}
+
+// Sets the minimum level of logs to keep for a resource, for one
+// source of logs or all of them.
+func resourceLevel(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var resource, levelName string
+	source := "all"
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"resource", &resource,
+		"level", &levelName,
+		"source?", &source)
+	if err != nil {
+		return nil, err
+	}
+
+	if resource == "" {
+		return nil, fmt.Errorf("%s: resource must not be empty", fn.Name())
+	}
+
+	level, err := logger.ParseLevel(levelName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: for parameter \"level\": %v", fn.Name(), err)
+	}
+
+	sources, err := parseSource(source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: for parameter \"source\": %v", fn.Name(), err)
+	}
+
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		resources := make(map[model.ManifestName]model.LogLevels, len(settings.Resources)+1)
+		for k, v := range settings.Resources {
+			resources[k] = v
+		}
+
+		mn := model.ManifestName(resource)
+		levels := make(model.LogLevels, len(model.LogSources))
+		for k, v := range resources[mn] {
+			levels[k] = v
+		}
+		for _, s := range sources {
+			levels[s] = level
+		}
+		resources[mn] = levels
+		settings.Resources = resources
+		return settings
+	})
+	return starlark.None, err
+}
+
+// Keeps every line a resource logs, instead of collapsing lines
+// it logs over and over into one line with a count.
+func keepRepeats(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var resource string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"resource", &resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if resource == "" {
+		return nil, fmt.Errorf("%s: resource must not be empty", fn.Name())
+	}
+
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		keep := make(map[model.ManifestName]bool, len(settings.KeepRepeats)+1)
+		for k, v := range settings.KeepRepeats {
+			keep[k] = v
+		}
+		keep[model.ManifestName(resource)] = true
+		settings.KeepRepeats = keep
+		return settings
+	})
+	return starlark.None, err
+}
+
+// Sets what to do with the ANSI codes in a resource's logs.
+func ansi(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var resource, modeName string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"resource", &resource,
+		"mode", &modeName)
+	if err != nil {
+		return nil, err
+	}
+
+	if resource == "" {
+		return nil, fmt.Errorf("%s: resource must not be empty", fn.Name())
+	}
+
+	mode, err := model.ParseLogANSIMode(modeName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: for parameter \"mode\": %v", fn.Name(), err)
+	}
+
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		modes := make(map[model.ManifestName]model.LogANSIMode, len(settings.ANSI)+1)
+		for k, v := range settings.ANSI {
+			modes[k] = v
+		}
+		modes[model.ManifestName(resource)] = mode
+		settings.ANSI = modes
+		return settings
+	})
+	return starlark.None, err
+}
+
+func parseSource(source string) ([]model.LogSource, error) {
+	if source == "all" {
+		return model.LogSources, nil
+	}
+	names := []string{"all"}
+	for _, s := range model.LogSources {
+		if string(s) == source {
+			return []model.LogSource{s}, nil
+		}
+		names = append(names, string(s))
+	}
+	return nil, fmt.Errorf("invalid source %q: must be one of %s", source, strings.Join(names, ", "))
+}
+
+type fields logger.Fields
+
+// Formats fields as " key=value", sorted by key.
+func (f fields) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		v := f[k]
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			v = strconv.Quote(v)
+		}
+		sb.WriteString(fmt.Sprintf(" %s=%s", k, v))
+	}
+	return sb.String()
+}
+
+func (f fields) namespaced() logger.Fields {
+	result := make(logger.Fields, len(f))
+	for k, v := range f {
+		result[fieldPrefix+k] = v
+	}
+	return result
+}
+
+// The message may be passed positionally or as msg=. All other keyword args are fields.
+func unpackMessage(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (string, fields, error) {
+	var msgVal starlark.Value
+	if len(args) > 1 {
+		return "", nil, fmt.Errorf("%s: got %d positional arguments, want at most 1", fn.Name(), len(args))
+	}
+	if len(args) == 1 {
+		msgVal = args[0]
+	}
+
+	result := fields{}
+	for _, kwarg := range kwargs {
+		key := string(kwarg[0].(starlark.String))
+		if key == "msg" {
+			if msgVal != nil {
+				return "", nil, fmt.Errorf("%s: got multiple values for parameter %q", fn.Name(), key)
+			}
+			msgVal = kwarg[1]
+			continue
+		}
+		result[key] = valueString(kwarg[1])
+	}
+
+	if msgVal == nil {
+		return "", nil, fmt.Errorf("%s: missing argument for msg", fn.Name())
+	}
+	return valueString(msgVal), result, nil
+}
+
+func valueString(v starlark.Value) string {
+	if s, ok := starlark.AsString(v); ok {
+		return s
+	}
+	return v.String()
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) Settings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (Settings, error) {
+	var state Settings
+	err := m.Load(&state)
+	return state, err
+}
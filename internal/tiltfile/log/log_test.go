@@ -0,0 +1,198 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type logEntry struct {
+	level  logger.Level
+	fields logger.Fields
+	msg    string
+}
+
+type fixture struct {
+	*starkit.Fixture
+	entries []logEntry
+}
+
+func TestLevels(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.debug('debug')
+log.info('info')
+log.warn('warn')
+log.error(msg='error')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, []logEntry{
+		{level: logger.DebugLvl, msg: "debug\n"},
+		{level: logger.InfoLvl, msg: "info\n"},
+		{level: logger.WarnLvl, msg: "warn\n"},
+		{level: logger.ErrorLvl, msg: "error\n"},
+	}, f.entries)
+}
+
+func TestFields(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.info('deploying', service='api', replicas=3, reason='new image')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Len(t, f.entries, 1)
+	assert.Equal(t, `deploying reason="new image" replicas=3 service=api`+"\n", f.entries[0].msg)
+	assert.Equal(t, logger.Fields{
+		"tiltfile.replicas": "3",
+		"tiltfile.reason":   "new image",
+		"tiltfile.service":  "api",
+	}, f.entries[0].fields)
+}
+
+func TestSection(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.section('Backend')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Len(t, f.entries, 1)
+	assert.Equal(t, "Backend\n", f.entries[0].msg)
+	assert.Equal(t, "init", f.entries[0].fields[logger.FieldNameBuildEvent])
+}
+
+func TestMissingMessage(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.info(service='api')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.EqualError(t, err, "log.info: missing argument for msg")
+}
+
+func newFixture(tb testing.TB) *fixture {
+	f := &fixture{Fixture: starkit.NewFixture(tb, NewPlugin())}
+	l := logger.NewFuncLogger(false, logger.DebugLvl, func(level logger.Level, fields logger.Fields, b []byte) error {
+		f.entries = append(f.entries, logEntry{level: level, fields: fields, msg: string(b)})
+		return nil
+	})
+	f.SetContext(logger.WithLogger(context.Background(), l))
+	return f
+}
+
+func TestResourceLevel(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.resource_level('kafka', 'warn')
+log.resource_level('kafka', 'info', source='build')
+log.resource_level('api', 'ERROR', source='runtime')
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, map[model.ManifestName]model.LogLevels{
+		"kafka": {
+			model.LogSourceBuild:   logger.InfoLvl,
+			model.LogSourceRuntime: logger.WarnLvl,
+			model.LogSourceSystem:  logger.WarnLvl,
+		},
+		"api": {model.LogSourceRuntime: logger.ErrorLvl},
+	}, MustState(result).Resources)
+}
+
+func TestResourceLevelInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		tiltfile string
+		err      string
+	}{
+		{"level", "log.resource_level('kafka', 'loud')", `log.resource_level: for parameter "level": invalid level "loud"`},
+		{"source", "log.resource_level('kafka', 'warn', source='pods')", `log.resource_level: for parameter "source": invalid source "pods": must be one of all, build, runtime, system`},
+		{"resource", "log.resource_level('', 'warn')", "log.resource_level: resource must not be empty"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			f.File("Tiltfile", tc.tiltfile)
+
+			_, err := f.ExecFile("Tiltfile")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestResourceLevelApplyToManifests(t *testing.T) {
+	settings := Settings{Resources: map[model.ManifestName]model.LogLevels{
+		"kafka": {model.LogSourceRuntime: logger.WarnLvl},
+	}}
+	manifests := []model.Manifest{{Name: "api"}, {Name: "kafka"}}
+	require.NoError(t, settings.ApplyToManifests(manifests))
+	assert.Nil(t, manifests[0].LogLevels)
+	assert.Equal(t, settings.Resources["kafka"], manifests[1].LogLevels)
+
+	err := settings.ApplyToManifests([]model.Manifest{{Name: "api"}})
+	require.EqualError(t, err, `log.resource_level: unknown resources: "kafka"`)
+}
+
+func TestKeepRepeats(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.keep_repeats('kafka')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, map[model.ManifestName]bool{"kafka": true}, MustState(result).KeepRepeats)
+
+	manifests := []model.Manifest{{Name: "api"}, {Name: "kafka"}}
+	require.NoError(t, MustState(result).ApplyToManifests(manifests))
+	assert.False(t, manifests[0].KeepRepeatedLogs)
+	assert.True(t, manifests[1].KeepRepeatedLogs)
+
+	err = MustState(result).ApplyToManifests([]model.Manifest{{Name: "api"}})
+	require.EqualError(t, err, `log.keep_repeats: unknown resources: "kafka"`)
+}
+
+func TestANSI(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.ansi('webpack', 'strip')
+log.ansi('docs', 'html')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, map[model.ManifestName]model.LogANSIMode{
+		"webpack": model.LogANSIStrip,
+		"docs":    model.LogANSIHTML,
+	}, MustState(result).ANSI)
+
+	manifests := []model.Manifest{{Name: "api"}, {Name: "webpack"}, {Name: "docs"}}
+	require.NoError(t, MustState(result).ApplyToManifests(manifests))
+	assert.Equal(t, model.LogANSIMode(""), manifests[0].LogANSI)
+	assert.Equal(t, model.LogANSIStrip, manifests[1].LogANSI)
+	assert.Equal(t, model.LogANSIHTML, manifests[2].LogANSI)
+
+	err = MustState(result).ApplyToManifests([]model.Manifest{{Name: "webpack"}})
+	require.EqualError(t, err, `log.ansi: unknown resources: "docs"`)
+}
+
+func TestANSIInvalidMode(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+log.ansi('webpack', 'rainbow')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `log.ansi: for parameter "mode": invalid ANSI mode "rainbow": must be one of preserve, strip, html`)
+}
@@ -0,0 +1,519 @@
+package tiltfile
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	// See the note in docker_compose.go about marshaling compose-go types.
+	composeyaml "gopkg.in/yaml.v2"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/sliceutils"
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+)
+
+// Returns handles on the Kubernetes objects registered with k8s_yaml so far.
+func (s *tiltfileState) k8sObjects(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var metaLabels value.StringStringMap
+	var name, namespace, kind, apiVersion string
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"labels?", &metaLabels,
+		"name?", &name,
+		"namespace?", &namespace,
+		"kind?", &kind,
+		"api_version?", &apiVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := k8s.NewPartialMatchObjectSelector(apiVersion, kind, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var match []k8s.K8sEntity
+	for _, e := range s.k8sUnresourced {
+		if selector.Matches(e) {
+			match = append(match, e)
+		}
+	}
+
+	if len(metaLabels) > 0 {
+		match, _, err = k8s.FilterByMetadataLabels(match, metaLabels)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]starlark.Value, 0, len(match))
+	for _, e := range match {
+		result = append(result, &k8sObjectValue{entity: e})
+	}
+	return starlark.NewList(result), nil
+}
+
+// Returns handles on the services loaded with docker_compose so far.
+func (s *tiltfileState) dcServices(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var projectName string
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"project_name?", &projectName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectNames []string
+	for name := range s.dc {
+		if projectName == "" || name == projectName {
+			projectNames = append(projectNames, name)
+		}
+	}
+	sort.Strings(projectNames)
+
+	result := []starlark.Value{}
+	for _, name := range projectNames {
+		dc := s.dc[name]
+		for _, svcName := range dc.serviceNames {
+			result = append(result, &dcServiceValue{
+				state:   s,
+				project: name,
+				service: dc.services[svcName].ServiceName,
+			})
+		}
+	}
+	return starlark.NewList(result), nil
+}
+
+// A handle on a Kubernetes object registered with k8s_yaml.
+//
+// Reads return frozen copies. Writes replace the parsed object in place,
+// so they're seen by whichever resource ends up deploying it.
+type k8sObjectValue struct {
+	entity k8s.K8sEntity
+	frozen bool
+}
+
+var _ starlark.HasAttrs = &k8sObjectValue{}
+
+var k8sObjectValueMethods = map[string]func(o *k8sObjectValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error{
+	"patch": func(o *k8sObjectValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error {
+		var patch *starlark.Dict
+		err := starlark.UnpackArgs(fnName, args, kwargs, "patch", &patch)
+		if err != nil {
+			return err
+		}
+		data, err := encoding.ConvertStarlarkToStructuredData(patch)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fnName, err)
+		}
+		return o.apply(fnName, data.(map[string]interface{}))
+	},
+	"set_label": func(o *k8sObjectValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error {
+		var key, val string
+		err := starlark.UnpackArgs(fnName, args, kwargs, "key", &key, "value", &val)
+		if err != nil {
+			return err
+		}
+		return o.apply(fnName, map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{key: val}},
+		})
+	},
+	"set_annotation": func(o *k8sObjectValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error {
+		var key, val string
+		err := starlark.UnpackArgs(fnName, args, kwargs, "key", &key, "value", &val)
+		if err != nil {
+			return err
+		}
+		return o.apply(fnName, map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]interface{}{key: val}},
+		})
+	},
+}
+
+func (o *k8sObjectValue) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "api_version":
+		apiVersion, _ := o.entity.GVK().ToAPIVersionAndKind()
+		return starlark.String(apiVersion), nil
+	case "kind":
+		return starlark.String(o.entity.GVK().Kind), nil
+	case "name":
+		return starlark.String(o.entity.Name()), nil
+	case "namespace":
+		return starlark.String(o.entity.Meta().GetNamespace()), nil
+	case "labels":
+		return frozenStringDict(o.entity.Labels()), nil
+	case "annotations":
+		return frozenStringDict(o.entity.Annotations()), nil
+	case "content":
+		content, err := o.content()
+		if err != nil {
+			return nil, err
+		}
+		return frozenStructuredData(content)
+	}
+
+	method, ok := k8sObjectValueMethods[name]
+	if !ok {
+		return nil, nil
+	}
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		err := method(fn.Receiver().(*k8sObjectValue), fn.Name(), args, kwargs)
+		if err != nil {
+			return nil, err
+		}
+		return starlark.None, nil
+	}).BindReceiver(o), nil
+}
+
+func (o *k8sObjectValue) AttrNames() []string {
+	return []string{"annotations", "api_version", "content", "kind", "labels", "name", "namespace",
+		"patch", "set_annotation", "set_label"}
+}
+
+func (o *k8sObjectValue) String() string {
+	return fmt.Sprintf("<K8sObject %s>", newK8sObjectID(o.entity))
+}
+
+func (o *k8sObjectValue) Type() string {
+	return "K8sObject"
+}
+
+func (o *k8sObjectValue) Freeze() {
+	o.frozen = true
+}
+
+func (o *k8sObjectValue) Truth() starlark.Bool {
+	return true
+}
+
+func (o *k8sObjectValue) Hash() (uint32, error) {
+	return 0, fmt.Errorf("unhashable type: K8sObject")
+}
+
+// The object as plain structured data, without its status.
+func (o *k8sObjectValue) content() (map[string]interface{}, error) {
+	spec, err := k8s.SerializeSpecYAML([]k8s.K8sEntity{o.entity})
+	if err != nil {
+		return nil, err
+	}
+
+	var content map[string]interface{}
+	err = k8syaml.Unmarshal([]byte(spec), &content)
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// Applies a JSON merge patch (RFC 7386) to the object.
+func (o *k8sObjectValue) apply(fnName string, patch map[string]interface{}) error {
+	if o.frozen {
+		return fmt.Errorf("%s: cannot modify frozen %s", fnName, o.String())
+	}
+
+	content, err := o.content()
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(mergePatch(content, patch))
+	if err != nil {
+		return fmt.Errorf("%s: %v", fnName, err)
+	}
+
+	entities, err := k8s.ParseYAMLFromString(string(b))
+	if err != nil {
+		return fmt.Errorf("%s: %v", fnName, err)
+	}
+	if len(entities) != 1 {
+		return fmt.Errorf("%s: patch must result in exactly one object, got %d", fnName, len(entities))
+	}
+
+	updated := entities[0]
+	if updated.ToObjectReference() != o.entity.ToObjectReference() {
+		return fmt.Errorf("%s: cannot change the apiVersion, kind, name, or namespace of %s", fnName, o.String())
+	}
+
+	// Overwrite the object in place, so that every reference to it sees the update.
+	dst := reflect.ValueOf(o.entity.Obj).Elem()
+	src := reflect.ValueOf(updated.Obj).Elem()
+	if dst.Type() != src.Type() {
+		return fmt.Errorf("%s: patch changed the type of %s from %s to %s", fnName, o.String(), dst.Type(), src.Type())
+	}
+	dst.Set(src)
+	return nil
+}
+
+// A handle on a service loaded with docker_compose.
+//
+// Reads return frozen copies. Writes are saved to a Docker Compose override
+// file for the project, so they follow Compose's usual merge rules.
+type dcServiceValue struct {
+	state   *tiltfileState
+	project string
+
+	// The name of the service in the compose file, which stays the same
+	// even if dc_resource renames the Tilt resource.
+	service string
+
+	frozen bool
+}
+
+var _ starlark.HasAttrs = &dcServiceValue{}
+
+var dcServiceValueMethods = map[string]func(o *dcServiceValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error{
+	"patch": func(o *dcServiceValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error {
+		var patch *starlark.Dict
+		err := starlark.UnpackArgs(fnName, args, kwargs, "patch", &patch)
+		if err != nil {
+			return err
+		}
+		data, err := encoding.ConvertStarlarkToStructuredData(patch)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fnName, err)
+		}
+		return o.apply(fnName, data.(map[string]interface{}))
+	},
+	"set_label": func(o *dcServiceValue, fnName string, args starlark.Tuple, kwargs []starlark.Tuple) error {
+		var key, val string
+		err := starlark.UnpackArgs(fnName, args, kwargs, "key", &key, "value", &val)
+		if err != nil {
+			return err
+		}
+		return o.apply(fnName, map[string]interface{}{
+			"labels": map[string]interface{}{key: val},
+		})
+	},
+}
+
+func (o *dcServiceValue) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "name", "project", "image", "labels", "content":
+		_, svc, err := o.lookup()
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "name":
+			return starlark.String(svc.Name), nil
+		case "project":
+			return starlark.String(o.project), nil
+		case "image":
+			return starlark.String(svc.ServiceConfig.Image), nil
+		case "labels":
+			return frozenStringDict(svc.ServiceConfig.Labels), nil
+		default:
+			var content map[string]interface{}
+			err := k8syaml.Unmarshal(svc.ServiceYAML, &content)
+			if err != nil {
+				return nil, err
+			}
+			return frozenStructuredData(content)
+		}
+	}
+
+	method, ok := dcServiceValueMethods[name]
+	if !ok {
+		return nil, nil
+	}
+	return starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		err := method(fn.Receiver().(*dcServiceValue), fn.Name(), args, kwargs)
+		if err != nil {
+			return nil, err
+		}
+		return starlark.None, nil
+	}).BindReceiver(o), nil
+}
+
+func (o *dcServiceValue) AttrNames() []string {
+	return []string{"content", "image", "labels", "name", "patch", "project", "set_label"}
+}
+
+func (o *dcServiceValue) String() string {
+	return fmt.Sprintf("<DockerComposeService %s/%s>", o.project, o.service)
+}
+
+func (o *dcServiceValue) Type() string {
+	return "DockerComposeService"
+}
+
+func (o *dcServiceValue) Freeze() {
+	o.frozen = true
+}
+
+func (o *dcServiceValue) Truth() starlark.Bool {
+	return true
+}
+
+func (o *dcServiceValue) Hash() (uint32, error) {
+	return 0, fmt.Errorf("unhashable type: DockerComposeService")
+}
+
+// Services are looked up on each access, because docker_compose()
+// replaces them when it re-loads a project.
+func (o *dcServiceValue) lookup() (*dcResourceSet, *dcService, error) {
+	dc := o.state.dc[o.project]
+	if dc != nil {
+		for _, svc := range dc.services {
+			if svc.ServiceName == o.service {
+				return dc, svc, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("%s no longer exists", o.String())
+}
+
+func (o *dcServiceValue) apply(fnName string, patch map[string]interface{}) error {
+	if o.frozen {
+		return fmt.Errorf("%s: cannot modify frozen %s", fnName, o.String())
+	}
+	if hasNull(patch) {
+		return fmt.Errorf("%s: Docker Compose overrides cannot remove fields, so None is not allowed", fnName)
+	}
+
+	dc, svc, err := o.lookup()
+	if err != nil {
+		return fmt.Errorf("%s: %v", fnName, err)
+	}
+	return o.state.patchDCService(dc, svc, patch)
+}
+
+// Adds a patch to the project's override file, then re-loads the service.
+func (s *tiltfileState) patchDCService(dc *dcResourceSet, svc *dcService, patch map[string]interface{}) error {
+	if dc.overrides == nil {
+		dc.overrides = make(map[string]interface{})
+	}
+	dc.overrides[svc.ServiceName] = mergePatch(dc.overrides[svc.ServiceName], patch)
+
+	contents, err := composeyaml.Marshal(map[string]interface{}{"services": dc.overrides})
+	if err != nil {
+		return err
+	}
+
+	tmpdir, err := s.tempDir()
+	if err != nil {
+		return errors.Wrap(err, "unable to store docker compose override")
+	}
+	overridePath := filepath.Join(tmpdir.Path(), fmt.Sprintf("override-%x.yml", sha256.Sum256(contents)))
+	err = os.WriteFile(overridePath, contents, 0600)
+	if err != nil {
+		return errors.Wrap(err, "unable to store docker compose override")
+	}
+
+	// The override always goes last, so that it wins.
+	var configPaths []string
+	for _, p := range dc.configPaths {
+		if p != dc.overridePath {
+			configPaths = append(configPaths, p)
+		}
+	}
+	dc.configPaths = sliceutils.AppendWithoutDupes(configPaths, overridePath)
+	dc.Project.ConfigPaths = dc.configPaths
+	dc.overridePath = overridePath
+
+	proj, err := s.dcCli.Project(s.ctx, dc.Project)
+	if err != nil {
+		return err
+	}
+
+	var updated *dcService
+	err = proj.WithServices([]string{svc.ServiceName}, func(svcConfig types.ServiceConfig) error {
+		if svcConfig.Name != svc.ServiceName {
+			return nil
+		}
+		u, err := dockerComposeConfigToService(dc, proj.Name, svcConfig)
+		if err != nil {
+			return errors.Wrapf(err, "getting service %s", svcConfig.Name)
+		}
+		updated = &u
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if updated == nil {
+		return fmt.Errorf("service %s missing after applying override", svc.ServiceName)
+	}
+
+	// Keep the Tilt resource name and the dc_resource options.
+	svc.ServiceConfig = updated.ServiceConfig
+	svc.ServiceYAML = updated.ServiceYAML
+	svc.MountedLocalDirs = updated.MountedLocalDirs
+	svc.PublishedPorts = updated.PublishedPorts
+	svc.imageRefFromConfig = updated.imageRefFromConfig
+	return nil
+}
+
+// Applies a JSON merge patch (RFC 7386): maps are merged recursively,
+// nulls delete keys, and everything else is replaced.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+func hasNull(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for _, x := range v {
+			if hasNull(x) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, x := range v {
+			if hasNull(x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func frozenStringDict(m map[string]string) *starlark.Dict {
+	d := starlark.NewDict(len(m))
+	for k, v := range m {
+		_ = d.SetKey(starlark.String(k), starlark.String(v))
+	}
+	d.Freeze()
+	return d
+}
+
+func frozenStructuredData(data interface{}) (starlark.Value, error) {
+	v, err := encoding.ConvertStructuredDataToStarlark(data)
+	if err != nil {
+		return nil, err
+	}
+	v.Freeze()
+	return v, nil
+}
@@ -0,0 +1,138 @@
+package tiltfile
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/yaml"
+)
+
+func TestK8sObjectsPatch(t *testing.T) {
+	f := newFixture(t)
+	f.file("k8s.yaml", yaml.ConcatYAML(testyaml.DoggosDeploymentYaml, testyaml.DoggosServiceYaml))
+	f.file("Tiltfile", `
+k8s_yaml('k8s.yaml')
+for o in k8s_objects(kind='Deployment'):
+  o.set_label('team', 'web')
+  o.set_annotation('owner', 'alice')
+  o.patch({'spec': {'replicas': 3}})
+`)
+
+	f.load()
+	m := f.assertNextManifest("doggos", deployment("doggos"), service("doggos"))
+	y := m.K8sTarget().YAML
+	assert.Contains(t, y, "team: web")
+	assert.Contains(t, y, "owner: alice")
+	assert.Contains(t, y, "replicas: 3")
+
+	// Only the Deployment is labeled.
+	assert.Equal(t, 1, strings.Count(y, "team: web"))
+}
+
+func TestK8sObjectsRead(t *testing.T) {
+	f := newFixture(t)
+	f.file("k8s.yaml", yaml.ConcatYAML(testyaml.DoggosDeploymentYaml, testyaml.DoggosServiceYaml))
+	f.file("Tiltfile", `
+k8s_yaml('k8s.yaml')
+objs = k8s_objects(labels={'app': 'doggos'})
+print([(o.api_version, o.kind, o.name, o.namespace) for o in objs])
+d = k8s_objects(kind='Deployment')[0]
+print(d.labels['app'])
+print(d.content['spec']['template']['spec']['containers'][0]['image'])
+`)
+
+	f.load()
+	assert.Contains(t, f.out.String(), `[("apps/v1", "Deployment", "doggos", "the-dog-zone"), ("v1", "Service", "doggos", "")]`)
+	assert.Contains(t, f.out.String(), "doggos\n")
+	assert.Contains(t, f.out.String(), "gcr.io/windmill-public-containers/servantes/doggos\n")
+}
+
+func TestK8sObjectsReadsAreCopies(t *testing.T) {
+	f := newFixture(t)
+	f.file("k8s.yaml", testyaml.DoggosDeploymentYaml)
+	f.file("Tiltfile", `
+k8s_yaml('k8s.yaml')
+d = k8s_objects()[0]
+d.labels['team'] = 'web'
+`)
+
+	f.loadErrString("cannot insert into frozen hash table")
+}
+
+func TestK8sObjectsCannotRename(t *testing.T) {
+	f := newFixture(t)
+	f.file("k8s.yaml", testyaml.DoggosDeploymentYaml)
+	f.file("Tiltfile", `
+k8s_yaml('k8s.yaml')
+k8s_objects()[0].patch({'metadata': {'name': 'cats'}})
+`)
+
+	f.loadErrString("patch: cannot change the apiVersion, kind, name, or namespace of <K8sObject doggos:deployment:the-dog-zone:apps>")
+}
+
+func TestK8sObjectsPatchRemovesField(t *testing.T) {
+	f := newFixture(t)
+	f.file("k8s.yaml", testyaml.DoggosDeploymentYaml)
+	f.file("Tiltfile", `
+k8s_yaml('k8s.yaml')
+d = k8s_objects()[0]
+d.patch({'metadata': {'labels': {'app': None}}})
+print(sorted(d.labels.keys()))
+`)
+
+	f.load()
+	assert.Contains(t, f.out.String(), `["breed", "whosAGoodBoy"]`)
+}
+
+func TestDCServicesPatch(t *testing.T) {
+	f := newFixture(t)
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", twoServiceConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('bar', new_name='baz')
+for svc in dc_services():
+  svc.set_label('team', 'web')
+  print(svc.name, svc.project, svc.image)
+bar = [svc for svc in dc_services() if svc.name == 'baz'][0]
+bar.patch({'image': 'bar-image:v2', 'ports': ['4000:3000']})
+print(bar.content['ports'][0]['published'])
+`)
+
+	f.load()
+	assert.Contains(t, f.out.String(), "foo")
+	assert.Contains(t, f.out.String(), "baz")
+	assert.Contains(t, f.out.String(), "4000\n")
+
+	foo := f.assertNextManifest("foo")
+	assert.Contains(t, foo.DockerComposeTarget().ServiceYAML, "team: web")
+
+	baz := f.assertNextManifest("baz")
+	dcTarget := baz.DockerComposeTarget()
+	assert.Contains(t, dcTarget.ServiceYAML, "team: web")
+	assert.Contains(t, dcTarget.ServiceYAML, "image: bar-image:v2")
+	assert.Equal(t, []int{4000}, dcTarget.PublishedPorts())
+
+	// docker-compose itself sees the patches through an override file.
+	paths := dcTarget.Spec.Project.ConfigPaths
+	require.Len(t, paths, 2)
+	assert.Equal(t, f.JoinPath("docker-compose.yml"), paths[0])
+	assert.Contains(t, paths[1], "override-")
+}
+
+func TestDCServicesPatchNone(t *testing.T) {
+	f := newFixture(t)
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_services()[0].patch({'command': None})
+`)
+
+	f.loadErrString("Docker Compose overrides cannot remove fields")
+}
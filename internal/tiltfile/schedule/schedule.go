@@ -0,0 +1,196 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Reruns more frequent than this are almost certainly a mistake,
+// and would keep Tilt permanently busy.
+const MinRerunAfter = time.Second
+
+// Settings record the reruns requested with rerun_after().
+type Settings struct {
+	// If non-zero, re-execute the Tiltfile this long after it finishes.
+	Tiltfile time.Duration
+
+	// Re-trigger each resource this long after it finishes updating.
+	Resources map[model.ManifestName]time.Duration
+}
+
+// Sets RerunAfter on each manifest with a scheduled rerun.
+//
+// Returns an error if a rerun was scheduled for a resource that doesn't exist.
+func (s Settings) ApplyToManifests(manifests []model.Manifest) error {
+	var unknown []string
+	for name := range s.Resources {
+		found := false
+		for i, m := range manifests {
+			if m.Name == name {
+				manifests[i].RerunAfter = s.Resources[name]
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("rerun_after: unknown resources: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Implements time built-ins, and rerun_after() for asking the engine
+// to re-execute the Tiltfile or a resource later.
+//
+// There's deliberately no sleep(): blocking the Tiltfile blocks every
+// other update. Tiltfiles that need to poll or refresh short-lived
+// credentials should schedule a rerun instead.
+type Plugin struct {
+	now func() time.Time
+}
+
+func NewPlugin() Plugin {
+	return Plugin{now: time.Now}
+}
+
+func (p Plugin) NewState() interface{} {
+	return Settings{}
+}
+
+func (p Plugin) OnStart(env *starkit.Environment) error {
+	for _, b := range []struct {
+		name string
+		f    starkit.Function
+	}{
+		{"time.now", p.timeNow},
+		{"time.parse_duration", p.parseDuration},
+		{"time.format", p.format},
+		{"rerun_after", p.rerunAfter},
+	} {
+		err := env.AddBuiltin(b.name, b.f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Plugin) timeNow(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	return starlark.Float(float64(p.now().UnixNano()) / float64(time.Second)), nil
+}
+
+func (p Plugin) parseDuration(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var s string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "duration", &s); err != nil {
+		return nil, err
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return starlark.Float(d.Seconds()), nil
+}
+
+func (p Plugin) format(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var t starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "t", &t); err != nil {
+		return nil, err
+	}
+
+	secs, ok := starlark.AsFloat(t)
+	if !ok {
+		return nil, fmt.Errorf("%s: for parameter \"t\": got %s, want int or float", fn.Name(), t.Type())
+	}
+	ts := time.Unix(0, int64(secs*float64(time.Second))).UTC()
+	return starlark.String(ts.Format(time.RFC3339)), nil
+}
+
+func (p Plugin) rerunAfter(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var durationVal starlark.Value
+	var resource string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"duration", &durationVal,
+		"resource?", &resource); err != nil {
+		return nil, err
+	}
+
+	d, err := toDuration(durationVal)
+	if err != nil {
+		return nil, fmt.Errorf("%s: for parameter \"duration\": %v", fn.Name(), err)
+	}
+	if d < MinRerunAfter {
+		return nil, fmt.Errorf("%s: duration must be at least %s (got: %s)", fn.Name(), MinRerunAfter, d)
+	}
+
+	// If rerun_after() is called more than once for the same target,
+	// the shortest duration wins.
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		if resource == "" {
+			settings.Tiltfile = minDuration(settings.Tiltfile, d)
+			return settings
+		}
+
+		resources := make(map[model.ManifestName]time.Duration, len(settings.Resources)+1)
+		for k, v := range settings.Resources {
+			resources[k] = v
+		}
+		mn := model.ManifestName(resource)
+		resources[mn] = minDuration(resources[mn], d)
+		settings.Resources = resources
+		return settings
+	})
+	return starlark.None, err
+}
+
+// Durations may be a Go duration string ("5m"), or a number of seconds.
+func toDuration(v starlark.Value) (time.Duration, error) {
+	if s, ok := starlark.AsString(v); ok {
+		return time.ParseDuration(s)
+	}
+
+	switch v := v.(type) {
+	case starlark.Int, starlark.Float:
+		secs, _ := starlark.AsFloat(v)
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("got %s, want string or number of seconds", v.Type())
+}
+
+func minDuration(current, d time.Duration) time.Duration {
+	if current == 0 || d < current {
+		return d
+	}
+	return current
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) Settings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (Settings, error) {
+	var state Settings
+	err := m.Load(&state)
+	return state, err
+}
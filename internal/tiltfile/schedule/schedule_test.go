@@ -0,0 +1,109 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestTimeNow(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+print(int(time.now()))
+print(time.format(time.now()))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "1614556800\n2021-03-01T00:00:00Z\n", f.PrintOutput())
+}
+
+func TestParseDuration(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+print(time.parse_duration('1m30s'))
+print(time.now() + time.parse_duration('1h') > time.now())
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "90.0\nTrue\n", f.PrintOutput())
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+time.parse_duration('soon')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `time.parse_duration: time: invalid duration "soon"`)
+}
+
+func TestRerunAfter(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+rerun_after('10m')
+rerun_after(300)
+rerun_after('30s', resource='token-refresh')
+rerun_after(60, resource='token-refresh')
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, Settings{
+		Tiltfile: 5 * time.Minute,
+		Resources: map[model.ManifestName]time.Duration{
+			"token-refresh": 30 * time.Second,
+		},
+	}, MustState(result))
+}
+
+func TestRerunAfterTooShort(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+rerun_after('100ms')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rerun_after: duration must be at least 1s (got: 100ms)")
+}
+
+func TestRerunAfterInvalidType(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+rerun_after(['5m'])
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `rerun_after: for parameter "duration": got list, want string or number of seconds`)
+}
+
+func TestApplyToManifests(t *testing.T) {
+	settings := Settings{
+		Resources: map[model.ManifestName]time.Duration{
+			"token-refresh": time.Minute,
+		},
+	}
+	manifests := []model.Manifest{{Name: "api"}, {Name: "token-refresh"}}
+	require.NoError(t, settings.ApplyToManifests(manifests))
+	assert.Equal(t, time.Duration(0), manifests[0].RerunAfter)
+	assert.Equal(t, time.Minute, manifests[1].RerunAfter)
+
+	settings.Resources["tokn-refresh"] = time.Minute
+	err := settings.ApplyToManifests(manifests)
+	require.EqualError(t, err, `rerun_after: unknown resources: "tokn-refresh"`)
+}
+
+func newFixture(tb testing.TB) *starkit.Fixture {
+	now := time.Date(2021, 3, 1, 0, 0, 0, int(500*time.Millisecond), time.UTC)
+	return starkit.NewFixture(tb, Plugin{now: func() time.Time { return now }})
+}
@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/secretstore"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Implements functions for reading secrets from external secret stores.
+//
+// The plugin state is the set of secrets the Tiltfile read, which must
+// be scrubbed from logs regardless of secret_settings().
+type Plugin struct {
+	store *secretstore.Store
+}
+
+func NewPlugin(store *secretstore.Store) Plugin {
+	return Plugin{store: store}
+}
+
+func (e Plugin) NewState() interface{} {
+	return model.SecretSet{}
+}
+
+func (e Plugin) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("secrets.get", e.get)
+}
+
+func (e Plugin) get(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key, provider string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"key", &key,
+		"provider?", &provider); err != nil {
+		return nil, err
+	}
+
+	ctx, err := starkit.ContextFromThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := model.SecretSet{}
+	value, err := e.store.Get(ctx, provider, key, fetched)
+	if err != nil {
+		return nil, err
+	}
+
+	err = starkit.SetState(thread, func(secrets model.SecretSet) model.SecretSet {
+		result := model.SecretSet{}
+		result.AddAll(secrets)
+		result.AddAll(fetched)
+		return result
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.String(value), nil
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) model.SecretSet {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.SecretSet, error) {
+	var state model.SecretSet
+	err := m.Load(&state)
+	return state, err
+}
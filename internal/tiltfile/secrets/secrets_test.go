@@ -0,0 +1,49 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/secretstore"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+func TestGet(t *testing.T) {
+	f, p := newFixture(t)
+	p.Set("db-password", "hunter22")
+	p.Set("api-key", "abcdefg")
+	f.File("Tiltfile", `
+pw = secrets.get('db-password')
+key = secrets.get(key='api-key', provider='fake')
+print(len(pw))
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "8\n", f.PrintOutput())
+
+	s := MustState(result)
+	assert.Len(t, s, 2)
+	assert.Equal(t, "[redacted secret fake:db-password]", string(s["hunter22"].Replacement))
+	assert.Equal(t, "[redacted secret fake:api-key]", string(s["abcdefg"].Replacement))
+}
+
+func TestGetMissing(t *testing.T) {
+	f, _ := newFixture(t)
+	f.File("Tiltfile", `
+secrets.get('db-password')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `fake: fetching secret "db-password": secret not found`)
+}
+
+func newFixture(t testing.TB) (*starkit.Fixture, *secretstore.FakeProvider) {
+	p := secretstore.NewFakeProvider("fake")
+	store := secretstore.NewStore(clockwork.NewFakeClock(), secretstore.DefaultTTL, p)
+	return starkit.NewFixture(t, NewPlugin(store)), p
+}
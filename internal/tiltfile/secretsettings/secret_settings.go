@@ -1,14 +1,18 @@
 package secretsettings
 
 import (
+	"fmt"
+
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/pkg/model"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 )
 
-// Implements functions for dealing with k8s secret settings.
+// Implements functions for dealing with k8s secret settings, and the
+// patterns of text to redact from logs.
 type Plugin struct {
 }
 
@@ -24,15 +28,37 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 	return env.AddBuiltin("secret_settings", e.secretSettings)
 }
 
+// Each call only changes the settings it's passed, so that a Tiltfile can
+// add redaction patterns without turning secret scrubbing back on.
 func (e Plugin) secretSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var disable bool
+	var disable, builtinRedactions starlark.Value
+	var redact value.StringOrStringList
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
-		"disable_scrub", &disable); err != nil {
+		"disable_scrub?", &disable,
+		"redact?", &redact,
+		"builtin_redactions?", &builtinRedactions); err != nil {
 		return nil, err
 	}
 
+	var patterns model.RedactPatterns
+	for _, expr := range redact.Values {
+		p, err := model.NewRedactPattern("secret", expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter \"redact\": %v", fn.Name(), err)
+		}
+		patterns = append(patterns, p)
+	}
+
 	err := starkit.SetState(thread, func(settings model.SecretSettings) model.SecretSettings {
-		settings.ScrubSecrets = !disable
+		if disable != nil {
+			settings.ScrubSecrets = !bool(disable.Truth())
+		}
+		if builtinRedactions != nil {
+			settings.BuiltinRedactions = bool(builtinRedactions.Truth())
+		}
+		if len(patterns) > 0 {
+			settings.RedactPatterns = append(append(model.RedactPatterns{}, settings.RedactPatterns...), patterns...)
+		}
 		return settings
 	})
 
@@ -300,6 +300,16 @@ func (e *Environment) doLoad(t *starlark.Thread, localPath string) (starlark.Str
 	}
 	predeclared["__file__"] = starlark.String(localPath)
 
+	for _, ext := range e.plugins {
+		sandboxExt, ok := ext.(SandboxPlugin)
+		if ok {
+			err := sandboxExt.Sandbox(t, localPath, predeclared)
+			if err != nil {
+				return starlark.StringDict{}, err
+			}
+		}
+	}
+
 	return starlark.ExecFile(t, localPath, bytes, predeclared)
 }
 
@@ -233,3 +233,38 @@ print(x)
 
 	assert.Equal(t, "set([1, 2])\n", f.out.String())
 }
+
+// Takes oh.hai away from files in the sandbox directory.
+type sandboxPlugin struct {
+	dir string
+}
+
+func (p sandboxPlugin) OnStart(e *Environment) error { return nil }
+
+func (p sandboxPlugin) Sandbox(t *starlark.Thread, path string, predeclared starlark.StringDict) error {
+	if filepath.Base(filepath.Dir(path)) == p.dir {
+		ReplacePredeclared(predeclared, "oh.hai", starlark.None)
+	}
+	return nil
+}
+
+func TestSandbox(t *testing.T) {
+	e := NewPluginWithIdentifier("oh.hai")
+	f := NewFixture(t, e, sandboxPlugin{dir: "sandbox"})
+	f.File("sandbox/Tiltfile", `
+def call_hai():
+  oh.hai()
+`)
+	f.File("Tiltfile", `
+load('./sandbox/Tiltfile', 'call_hai')
+oh.hai()
+call_hai()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid call of non-function (NoneType)")
+
+	// The call from the Tiltfile still sees the original.
+	assert.Equal(t, 1, e.callCount)
+}
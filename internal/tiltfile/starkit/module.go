@@ -2,6 +2,7 @@ package starkit
 
 import (
 	"fmt"
+	"strings"
 
 	"go.starlark.net/starlark"
 )
@@ -42,3 +43,37 @@ func (m Module) String() string {
 }
 
 var _ starlark.HasAttrs = Module{}
+
+// Replaces the value with the given name, like "os.getenv", in a set of
+// predeclared values. The modules on the way to the value are copied,
+// so that other files still see the original.
+//
+// Returns false if there's no value with the name.
+func ReplacePredeclared(predeclared starlark.StringDict, name string, val starlark.Value) bool {
+	return replaceAttr(predeclared, strings.Split(name, "."), val)
+}
+
+func replaceAttr(attrs starlark.StringDict, path []string, val starlark.Value) bool {
+	current, ok := attrs[path[0]]
+	if !ok {
+		return false
+	}
+	if len(path) == 1 {
+		attrs[path[0]] = val
+		return true
+	}
+
+	m, ok := current.(Module)
+	if !ok {
+		return false
+	}
+	copied := Module{fullName: m.fullName, attrs: make(starlark.StringDict, len(m.attrs))}
+	for k, v := range m.attrs {
+		copied.attrs[k] = v
+	}
+	if !replaceAttr(copied.attrs, path[1:], val) {
+		return false
+	}
+	attrs[path[0]] = copied
+	return true
+}
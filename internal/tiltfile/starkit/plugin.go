@@ -22,6 +22,15 @@ type OnBuiltinCallPlugin interface {
 	OnBuiltinCall(name string, fn *starlark.Builtin)
 }
 
+type SandboxPlugin interface {
+	Plugin
+
+	// Called before each new Starlark file is loaded, to take away values that
+	// the file isn't allowed to use. Changes to predeclared are only seen
+	// by the file. Use ReplacePredeclared to replace values in modules.
+	Sandbox(t *starlark.Thread, path string, predeclared starlark.StringDict) error
+}
+
 // Starkit plugins are not allowed to have mutable state.
 //
 // Starlark has different ideas about mutable state than most programming languages.
@@ -0,0 +1,124 @@
+package tiltextension
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+const grantsFileName = "extension_permissions.json"
+
+// The permissions that the user has granted to extensions on this machine.
+//
+// Extensions are identified by their source, the URL of their repo and
+// their path in it, like
+//
+//	https://github.com/tilt-dev/tilt-extensions//restart_process
+//
+// so that an extension from a different repo with the same name
+// doesn't get the same permissions.
+type Grants struct {
+	// Whether extensions that don't list their permissions are sandboxed too.
+	// If false, they can use every builtin, like they could before
+	// extensions had permissions.
+	SandboxUndeclared bool `json:"sandboxUndeclared,omitempty"`
+
+	// The permissions granted to each extension source.
+	Permissions map[string][]string `json:"permissions,omitempty"`
+}
+
+// The path of the file where grants are saved.
+func GrantsPath(base xdg.Base) (string, error) {
+	return base.ConfigFile(grantsFileName)
+}
+
+// Reads the grants saved at p. Returns empty grants if there's no file.
+func ReadGrants(p string) (Grants, error) {
+	contents, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return Grants{}, nil
+	}
+	if err != nil {
+		return Grants{}, err
+	}
+
+	var g Grants
+	err = json.Unmarshal(contents, &g)
+	if err != nil {
+		return Grants{}, fmt.Errorf("reading %s: %v", p, err)
+	}
+	return g, nil
+}
+
+func WriteGrants(p string, g Grants) error {
+	contents, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, append(contents, '\n'), 0600)
+}
+
+// The source of an extension, the URL of its repo and its path in it.
+func Source(repo *v1alpha1.ExtensionRepo, repoPath string) string {
+	return strings.TrimSuffix(repo.Spec.URL, "/") + "//" + path.Clean("/" + repoPath)[1:]
+}
+
+func (g Grants) Granted(source, perm string) bool {
+	for _, p := range g.Permissions[source] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Grants) Grant(source string, perms ...string) {
+	if g.Permissions == nil {
+		g.Permissions = make(map[string][]string)
+	}
+	set := make(map[string]bool)
+	for _, p := range g.Permissions[source] {
+		set[p] = true
+	}
+	for _, p := range perms {
+		set[p] = true
+	}
+	g.Permissions[source] = sortedKeys(set)
+}
+
+// Takes away permissions from an extension source.
+// With no permissions, takes away all of them.
+func (g *Grants) Revoke(source string, perms ...string) {
+	if len(perms) == 0 {
+		delete(g.Permissions, source)
+		return
+	}
+
+	set := make(map[string]bool)
+	for _, p := range g.Permissions[source] {
+		set[p] = true
+	}
+	for _, p := range perms {
+		delete(set, p)
+	}
+	if len(set) == 0 {
+		delete(g.Permissions, source)
+		return
+	}
+	g.Permissions[source] = sortedKeys(set)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
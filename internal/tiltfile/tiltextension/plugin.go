@@ -18,6 +18,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	tiltfilev1alpha1 "github.com/tilt-dev/tilt/internal/tiltfile/v1alpha1"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -29,12 +30,17 @@ const defaultRepoName = "default"
 type Plugin struct {
 	repoReconciler ExtRepoReconciler
 	extReconciler  ExtReconciler
+
+	// Where to find the permissions the user has granted to extensions.
+	// If nil, no permissions have been granted.
+	base xdg.Base
 }
 
 func NewPlugin(repoReconciler *extensionrepo.Reconciler, extReconciler *extension.Reconciler) *Plugin {
 	return &Plugin{
 		repoReconciler: repoReconciler,
 		extReconciler:  extReconciler,
+		base:           xdg.NewTiltDevBase(),
 	}
 }
 
@@ -47,11 +53,15 @@ func NewFakePlugin(repoReconciler ExtRepoReconciler, extReconciler ExtReconciler
 
 type State struct {
 	ExtsLoaded map[string]bool
+
+	// The sandboxed extensions, by the directory they're in.
+	Sandboxes map[string]Sandbox
 }
 
 func (e Plugin) NewState() interface{} {
 	return State{
 		ExtsLoaded: make(map[string]bool),
+		Sandboxes:  make(map[string]Sandbox),
 	}
 }
 
@@ -119,6 +129,11 @@ func (e *Plugin) LocalPath(t *starlark.Thread, arg string) (localPath string, er
 		return "", fmt.Errorf("extension not resolved: %s", ext.Name)
 	}
 
+	err = e.checkPermissions(t, ext, repoResolved, extStatus.Path)
+	if err != nil {
+		return "", err
+	}
+
 	return extStatus.Path, nil
 }
 
@@ -181,6 +196,7 @@ func (e *Plugin) ensureRepo(t *starlark.Thread, objSet apiset.ObjectSet, repoNam
 
 var _ starkit.LoadInterceptor = (*Plugin)(nil)
 var _ starkit.StatefulPlugin = (*Plugin)(nil)
+var _ starkit.SandboxPlugin = (*Plugin)(nil)
 
 func MustState(model starkit.Model) State {
 	state, err := GetState(model)
@@ -8,11 +8,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/tiltfile/include"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	tiltfilev1alpha1 "github.com/tilt-dev/tilt/internal/tiltfile/v1alpha1"
+	"github.com/tilt-dev/tilt/internal/xdg"
 )
 
 func TestFetchableAlreadyPresentWorks(t *testing.T) {
@@ -191,12 +193,108 @@ include('Tiltfile.b')
 	f.assertLoadRecorded(res, "my-extension")
 }
 
+const cmdExtSource = "https://github.com/tilt-dev/tilt-extensions//cmd-ext"
+
+const cmdExtText = `
+def make_cmd():
+  v1alpha1.cmd(name='from-ext', args=['echo', 'hi'])
+  print("made cmd")
+`
+
+func TestSandboxedExtensionNeedsGrant(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://cmd-ext", "make_cmd")
+make_cmd()
+`)
+	f.writeModuleLocally("cmd-ext", cmdExtText)
+	f.writePermissions("cmd-ext", "# runs a command\nexec\n")
+
+	res := f.assertError("extension cmd-ext needs permissions that you haven't granted: exec\n" +
+		"To grant them, run:\n  tilt grant ext " + cmdExtSource + " exec")
+	f.assertNoLoadsRecorded(res)
+}
+
+func TestSandboxedExtensionWithGrant(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://cmd-ext", "make_cmd")
+make_cmd()
+`)
+	f.writeModuleLocally("cmd-ext", cmdExtText)
+	f.writePermissions("cmd-ext", "exec\n")
+	f.grant(Grants{Permissions: map[string][]string{cmdExtSource: {"exec"}}})
+
+	f.assertExecOutput("made cmd")
+}
+
+func TestSandboxedExtensionWithoutPermission(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://cmd-ext", "make_cmd")
+make_cmd()
+`)
+	f.writeModuleLocally("cmd-ext", cmdExtText)
+	f.writePermissions("cmd-ext", "")
+
+	f.assertError(`v1alpha1.cmd: extension cmd-ext doesn't have the "exec" permission`)
+}
+
+func TestSandboxOnlyAppliesToExtension(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://cmd-ext", "make_cmd")
+v1alpha1.cmd(name='from-tiltfile', args=['echo', 'hi'])
+print("made cmd from Tiltfile")
+`)
+	f.writeModuleLocally("cmd-ext", cmdExtText)
+	f.writePermissions("cmd-ext", "")
+
+	f.assertExecOutput("made cmd from Tiltfile")
+}
+
+func TestSandboxUndeclared(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://cmd-ext", "make_cmd")
+make_cmd()
+`)
+	f.writeModuleLocally("cmd-ext", cmdExtText)
+
+	// Without a PERMISSIONS file, the extension is trusted by default.
+	f.assertExecOutput("made cmd")
+
+	f.grant(Grants{SandboxUndeclared: true})
+	f.assertError(`v1alpha1.cmd: extension cmd-ext doesn't have the "exec" permission`)
+
+	f.grant(Grants{SandboxUndeclared: true, Permissions: map[string][]string{cmdExtSource: {"exec"}}})
+	f.assertExecOutput("made cmd")
+}
+
+func TestInvalidPermissions(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://cmd-ext", "make_cmd")
+`)
+	f.writeModuleLocally("cmd-ext", cmdExtText)
+	f.writePermissions("cmd-ext", "sudo\n")
+
+	f.assertError(`loading extension cmd-ext: invalid PERMISSIONS file: unknown permission "sudo" (must be one of exec, env, network)`)
+}
+
 type extensionFixture struct {
-	t     *testing.T
-	skf   *starkit.Fixture
-	tmp   *tempdir.TempDirFixture
-	extr  *FakeExtReconciler
-	extrr *FakeExtRepoReconciler
+	t      *testing.T
+	skf    *starkit.Fixture
+	tmp    *tempdir.TempDirFixture
+	extr   *FakeExtReconciler
+	extrr  *FakeExtRepoReconciler
+	plugin *Plugin
 }
 
 func newExtensionFixture(t *testing.T) *extensionFixture {
@@ -212,14 +310,24 @@ func newExtensionFixture(t *testing.T) *extensionFixture {
 	skf.UseRealFS()
 
 	return &extensionFixture{
-		t:     t,
-		skf:   skf,
-		tmp:   tmp,
-		extr:  extr,
-		extrr: extrr,
+		t:      t,
+		skf:    skf,
+		tmp:    tmp,
+		extr:   extr,
+		extrr:  extrr,
+		plugin: ext,
 	}
 }
 
+// Saves the permissions granted to extensions.
+func (f *extensionFixture) grant(grants Grants) {
+	base := xdg.FakeBase{Dir: f.tmp.JoinPath("config")}
+	f.plugin.base = base
+	p, err := GrantsPath(base)
+	require.NoError(f.t, err)
+	require.NoError(f.t, WriteGrants(p, grants))
+}
+
 func (f *extensionFixture) tiltfile(contents string) {
 	f.skf.File("Tiltfile", contents)
 }
@@ -261,6 +369,10 @@ func (f *extensionFixture) assertNoLoadsRecorded(model starkit.Model) {
 	f.assertLoadRecorded(model)
 }
 
+func (f *extensionFixture) writePermissions(name string, contents string) {
+	f.tmp.WriteFile(filepath.Join("tilt-extensions", name, "PERMISSIONS"), contents)
+}
+
 func (f *extensionFixture) writeModuleLocally(name string, contents string) {
 	f.tmp.WriteFile(filepath.Join("tilt-extensions", name, "Tiltfile"), contents)
 }
@@ -0,0 +1,209 @@
+package tiltextension
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/extension"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The permission that each builtin needs, when it's called by a sandboxed
+// extension. Builtins that aren't listed don't need any.
+var builtinPermissions = map[string]string{
+	"local":                         extension.PermissionExec,
+	"local_resource":                extension.PermissionExec,
+	"test":                          extension.PermissionExec,
+	"custom_build":                  extension.PermissionExec,
+	"helm":                          extension.PermissionExec,
+	"kustomize":                     extension.PermissionExec,
+	"k8s_custom_deploy":             extension.PermissionExec,
+	"exec_action":                   extension.PermissionExec,
+	"experimental_telemetry_cmd":    extension.PermissionExec,
+	"v1alpha1.cmd":                  extension.PermissionExec,
+	"v1alpha1.exec_action":          extension.PermissionExec,
+	"v1alpha1.kubernetes_apply_cmd": extension.PermissionExec,
+
+	"os.environ":  extension.PermissionEnv,
+	"os.getenv":   extension.PermissionEnv,
+	"os.putenv":   extension.PermissionEnv,
+	"os.unsetenv": extension.PermissionEnv,
+
+	"v1alpha1.extension_repo": extension.PermissionNetwork,
+}
+
+// An extension that can only use the builtins that its permissions cover.
+type Sandbox struct {
+	Name    string
+	Allowed []string
+}
+
+func (s Sandbox) allows(perm string) bool {
+	for _, p := range s.Allowed {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Decides whether the extension is sandboxed, and which permissions it has.
+//
+// An extension that lists its permissions can only load if the user has
+// granted all of them. An extension that doesn't is trusted with all of
+// them, unless the user has asked to sandbox those too.
+func (e *Plugin) checkPermissions(t *starlark.Thread, ext *v1alpha1.Extension, repo *v1alpha1.ExtensionRepo, tiltfilePath string) error {
+	dir := filepath.Dir(tiltfilePath)
+	perms, declared, err := extension.ReadPermissions(dir)
+	if err != nil {
+		return fmt.Errorf("loading extension %s: %v", ext.Name, err)
+	}
+
+	grants, err := e.readGrants(t)
+	if err != nil {
+		return fmt.Errorf("loading extension %s: %v", ext.Name, err)
+	}
+
+	if !declared && !grants.SandboxUndeclared {
+		return nil
+	}
+
+	source := Source(repo, ext.Spec.RepoPath)
+	allowed := []string{}
+	if declared {
+		var missing []string
+		for _, p := range perms {
+			if !grants.Granted(source, p) {
+				missing = append(missing, p)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("extension %s needs permissions that you haven't granted: %s\n"+
+				"To grant them, run:\n  tilt grant ext %s %s",
+				ext.Name, strings.Join(missing, ", "), source, strings.Join(missing, " "))
+		}
+		allowed = perms
+	} else {
+		for _, p := range extension.Permissions {
+			if grants.Granted(source, p) {
+				allowed = append(allowed, p)
+			}
+		}
+	}
+
+	return starkit.SetState(t, func(existing State) (State, error) {
+		existing.Sandboxes[dir] = Sandbox{Name: ext.Name, Allowed: allowed}
+		return existing, nil
+	})
+}
+
+func (e *Plugin) readGrants(t *starlark.Thread) (Grants, error) {
+	if e.base == nil {
+		return Grants{}, nil
+	}
+
+	p, err := GrantsPath(e.base)
+	if err != nil {
+		return Grants{}, err
+	}
+
+	// Reload the Tiltfile when the user grants permissions, if anything
+	// is watching its files.
+	m, err := starkit.ModelFromThread(t)
+	if err != nil {
+		return Grants{}, err
+	}
+	if _, err := io.GetState(m); err == nil {
+		err := io.RecordReadPath(t, io.WatchFileOnly, p)
+		if err != nil {
+			return Grants{}, err
+		}
+	}
+
+	return ReadGrants(p)
+}
+
+// Takes away the builtins that a file in a sandboxed extension doesn't
+// have permission to use.
+//
+// The builtins are taken away from the file's globals, so functions
+// that the extension exports stay sandboxed when other files call them.
+func (e *Plugin) Sandbox(t *starlark.Thread, path string, predeclared starlark.StringDict) error {
+	m, err := starkit.ModelFromThread(t)
+	if err != nil {
+		return err
+	}
+	state, err := GetState(m)
+	if err != nil {
+		return err
+	}
+
+	// Files in a nested extension belong to the innermost one.
+	var sandbox Sandbox
+	sandboxDir := ""
+	for dir, s := range state.Sandboxes {
+		if ospath.IsChild(dir, path) && len(dir) > len(sandboxDir) {
+			sandbox, sandboxDir = s, dir
+		}
+	}
+	if sandboxDir == "" {
+		return nil
+	}
+
+	for name, perm := range builtinPermissions {
+		if !sandbox.allows(perm) {
+			starkit.ReplacePredeclared(predeclared, name, deniedBuiltin{name: name, ext: sandbox.Name, perm: perm})
+		}
+	}
+	return nil
+}
+
+// Stands in for a builtin that a sandboxed extension doesn't have
+// permission to use. Any use of it fails.
+type deniedBuiltin struct {
+	name string
+	ext  string
+	perm string
+}
+
+var _ starlark.Callable = deniedBuiltin{}
+var _ starlark.Mapping = deniedBuiltin{}
+var _ starlark.HasAttrs = deniedBuiltin{}
+var _ starlark.HasSetKey = deniedBuiltin{}
+
+func (d deniedBuiltin) err() error {
+	return fmt.Errorf("%s: extension %s doesn't have the %q permission", d.name, d.ext, d.perm)
+}
+
+func (d deniedBuiltin) String() string        { return fmt.Sprintf("<denied %s>", d.name) }
+func (d deniedBuiltin) Type() string          { return "denied_builtin" }
+func (d deniedBuiltin) Freeze()               {}
+func (d deniedBuiltin) Truth() starlark.Bool  { return starlark.True }
+func (d deniedBuiltin) Hash() (uint32, error) { return 0, d.err() }
+func (d deniedBuiltin) Name() string          { return d.name }
+
+func (d deniedBuiltin) CallInternal(t *starlark.Thread, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return nil, d.err()
+}
+
+func (d deniedBuiltin) Get(k starlark.Value) (starlark.Value, bool, error) {
+	return nil, false, d.err()
+}
+
+func (d deniedBuiltin) SetKey(k, v starlark.Value) error {
+	return d.err()
+}
+
+func (d deniedBuiltin) Attr(name string) (starlark.Value, error) {
+	return nil, d.err()
+}
+
+func (d deniedBuiltin) AttrNames() []string {
+	return nil
+}
@@ -20,12 +20,18 @@ import (
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	tiltfileanalytics "github.com/tilt-dev/tilt/internal/tiltfile/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/budget"
 	"github.com/tilt-dev/tilt/internal/tiltfile/cisettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/internal/tiltfile/dockerprune"
+	"github.com/tilt-dev/tilt/internal/tiltfile/groupsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/hasher"
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
+	"github.com/tilt-dev/tilt/internal/tiltfile/localcache"
+	"github.com/tilt-dev/tilt/internal/tiltfile/log"
+	"github.com/tilt-dev/tilt/internal/tiltfile/schedule"
+	"github.com/tilt-dev/tilt/internal/tiltfile/secrets"
 	"github.com/tilt-dev/tilt/internal/tiltfile/secretsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/telemetry"
@@ -51,16 +57,22 @@ type TiltfileLoadResult struct {
 	TeamID              string
 	TelemetrySettings   model.TelemetrySettings
 	Secrets             model.SecretSet
+	RedactPatterns      model.RedactPatterns
 	Error               error
 	DockerPruneSettings model.DockerPruneSettings
 	AnalyticsOpt        wmanalytics.Opt
 	VersionSettings     model.VersionSettings
 	UpdateSettings      model.UpdateSettings
 	WatchSettings       model.WatchSettings
+	GroupSettings       model.GroupSettings
 	DefaultRegistry     *corev1alpha1.RegistryHosting
 	ObjectSet           apiset.ObjectSet
 	Hashes              hasher.Hashes
 	CISettings          *corev1alpha1.SessionCISpec
+	ArgsSchema          config.ArgsSchema
+
+	// If non-zero, re-execute the Tiltfile this long after it finishes.
+	RerunAfter time.Duration
 
 	// For diagnostic purposes only
 	BuiltinCalls []starkit.BuiltinCall `json:"-"`
@@ -102,6 +114,7 @@ func ProvideTiltfileLoader(
 	configPlugin *config.Plugin,
 	extensionPlugin *tiltextension.Plugin,
 	ciSettingsPlugin cisettings.Plugin,
+	secretsPlugin secrets.Plugin,
 	dcCli dockercompose.DockerComposeClient,
 	webHost model.WebHost,
 	execer localexec.Execer,
@@ -114,6 +127,7 @@ func ProvideTiltfileLoader(
 		configPlugin:     configPlugin,
 		extensionPlugin:  extensionPlugin,
 		ciSettingsPlugin: ciSettingsPlugin,
+		secretsPlugin:    secretsPlugin,
 		dcCli:            dcCli,
 		webHost:          webHost,
 		execer:           execer,
@@ -133,6 +147,7 @@ type tiltfileLoader struct {
 	configPlugin     *config.Plugin
 	extensionPlugin  *tiltextension.Plugin
 	ciSettingsPlugin cisettings.Plugin
+	secretsPlugin    secrets.Plugin
 	fDefaults        feature.Defaults
 	env              clusterid.Product
 }
@@ -174,7 +189,7 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	tlr.Tiltignore = tiltignore
 
 	s := newTiltfileState(ctx, tfl.dcCli, tfl.webHost, tfl.execer, tfl.k8sContextPlugin, tfl.versionPlugin,
-		tfl.configPlugin, tfl.extensionPlugin, tfl.ciSettingsPlugin, feature.FromDefaults(tfl.fDefaults))
+		tfl.configPlugin, tfl.extensionPlugin, tfl.ciSettingsPlugin, tfl.secretsPlugin, feature.FromDefaults(tfl.fDefaults))
 
 	manifests, result, err := s.loadManifests(tf)
 
@@ -186,11 +201,14 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	// execution correctly, where some state is correctly assembled but other
 	// state is not (and should be assumed empty).
 	ws, _ := watch.GetState(result)
+	if s.localCacheDir != "" {
+		ws.Ignores = append(ws.Ignores, localcache.IgnoreFor(s.localCacheDir))
+	}
 	tlr.WatchSettings = ws
 
-	// NOTE(maia): if/when add secret settings that affect the engine, add them to tlr here
 	ss, _ := secretsettings.GetState(result)
 	s.secretSettings = ss
+	tlr.RedactPatterns = ss.Redactions()
 
 	ioState, _ := io.GetState(result)
 
@@ -205,14 +223,42 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	tlr.AnalyticsOpt = aSettings.Opt
 
 	tlr.Secrets = s.extractSecrets()
+
+	// Secrets read from secret stores are always scrubbed, even if
+	// secret_settings() disables scrubbing for Kubernetes secrets.
+	storeSecrets, _ := secrets.GetState(result)
+	tlr.Secrets.AddAll(storeSecrets)
 	tlr.FeatureFlags = s.features.ToEnabled()
 	tlr.Error = err
 	tlr.Manifests = manifests
 	tlr.TeamID = s.teamID
 
+	scheduleSettings, _ := schedule.GetState(result)
+	tlr.RerunAfter = scheduleSettings.Tiltfile
+	if tlr.Error == nil {
+		tlr.Error = scheduleSettings.ApplyToManifests(tlr.Manifests)
+	}
+
+	logSettings, _ := log.GetState(result)
+	if tlr.Error == nil {
+		tlr.Error = logSettings.ApplyToManifests(tlr.Manifests)
+	}
+
 	objectSet, _ := v1alpha1.GetState(result)
+	if objectSet == nil {
+		objectSet = apiset.ObjectSet{}
+	}
 	tlr.ObjectSet = objectSet
 
+	budgetSettings, _ := budget.GetState(result)
+	if tlr.Error == nil {
+		var rules []*corev1alpha1.AlertRule
+		rules, tlr.Error = budgetSettings.AlertRules(tlr.Manifests)
+		for _, rule := range rules {
+			objectSet.Add(rule)
+		}
+	}
+
 	vs, _ := version.GetState(result)
 	tlr.VersionSettings = vs
 
@@ -222,12 +268,18 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	us, _ := updatesettings.GetState(result)
 	tlr.UpdateSettings = us
 
+	gs, _ := groupsettings.GetState(result)
+	tlr.GroupSettings = gs
+
 	ci, _ := cisettings.GetState(result)
 	tlr.CISettings = ci
 
 	configSettings, _ := config.GetState(result)
+	tlr.ArgsSchema = configSettings.ArgsSchema()
 	if tlr.Error == nil {
-		tlr.EnabledManifests, tlr.Error = configSettings.EnabledResources(tf, manifests)
+		k8sContextState, _ := k8scontext.GetState(result)
+		conditionEnv := starlark.StringDict{"cluster": k8sContextState.Cluster()}
+		tlr.EnabledManifests, tlr.Error = configSettings.EnabledResources(tf, manifests, conditionEnv)
 	}
 
 	duration := time.Since(start)
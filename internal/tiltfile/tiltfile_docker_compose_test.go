@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +14,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -118,6 +120,7 @@ func TestDockerComposeManifest(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		".env",
 		"docker-compose.yml",
 		f.JoinPath("foo", ".dockerignore"),
 	}
@@ -166,6 +169,7 @@ func TestDockerComposeServiceEnvFile(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		".env",
 		"docker-compose.yml",
 		"bar.env",
 	}
@@ -184,6 +188,156 @@ func TestDockerComposeProjectName(t *testing.T) {
 	require.Equal(t, "hello", m.DockerComposeTarget().Spec.Project.Name)
 }
 
+const profilesConfig = `services:
+  foo:
+    image: foo-image
+  debugger:
+    image: debugger-image
+    profiles: [debug]
+  admin:
+    image: admin-image
+    profiles: [admin]`
+
+func TestDockerComposeProfiles(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("docker-compose.yml", profilesConfig)
+	f.file("Tiltfile", `docker_compose('docker-compose.yml', profiles='debug')`)
+
+	f.load()
+	f.assertNumManifests(2)
+	m := f.assertDcManifest("debugger")
+	require.Equal(t, []string{"debug"}, m.DockerComposeTarget().Spec.Project.Profiles)
+	f.assertDcManifest("foo")
+}
+
+func TestDockerComposeProfilesAddUp(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("docker-compose.yml", profilesConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml', profiles=['debug'])
+docker_compose('docker-compose.yml', profiles=['admin'])
+`)
+
+	f.load()
+	f.assertNumManifests(3)
+	m := f.assertDcManifest("admin")
+	require.Equal(t, []string{"debug", "admin"}, m.DockerComposeTarget().Spec.Project.Profiles)
+}
+
+func TestDockerComposeNoProfilesEnablesEveryService(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("docker-compose.yml", profilesConfig)
+	f.file("Tiltfile", `docker_compose('docker-compose.yml')`)
+
+	f.load()
+	f.assertNumManifests(3)
+}
+
+func TestDockerComposeDownSettings(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml', down_volumes=True, down_timeout_secs=30)
+docker_compose('docker-compose.yml', down_remove_orphans=True)
+`)
+
+	f.load()
+	m := f.assertDcManifest("foo")
+	timeout := 30 * time.Second
+	assert.Equal(t, model.DockerComposeDownSettings{
+		DeleteVolumes: true,
+		RemoveOrphans: true,
+		Timeout:       &timeout,
+	}, m.DockerComposeTarget().Down)
+}
+
+func TestDockerComposeDownTimeoutNegative(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `docker_compose('docker-compose.yml', down_timeout_secs=-1)`)
+
+	f.loadErrString("down_timeout_secs must be a non-negative integer")
+}
+
+func TestDockerComposeDockerHost(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml', docker_context='build-box')
+docker_compose('docker-compose.yml', docker_host='ssh://me@build-box')
+`)
+
+	f.load()
+	m := f.assertDcManifest("foo")
+	assert.Equal(t, "ssh://me@build-box", m.DockerComposeTarget().Spec.Project.DockerHost)
+	assert.Equal(t, "", m.DockerComposeTarget().Spec.Project.DockerContext)
+}
+
+func TestDockerComposeDockerHostAndContext(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `docker_compose('docker-compose.yml', docker_host='tcp://build-box:2376', docker_context='build-box')`)
+
+	f.loadErrString("docker_host and docker_context can't be set together")
+}
+
+func TestDockerComposeUpOptions(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', with_deps=True, pull='always', build_args={'VERSION': '1.0'})
+dc_resource('foo', force_recreate=True, build_args={'DEBUG': '1'})
+`)
+
+	f.load()
+	m := f.assertDcManifest("foo")
+	assert.Equal(t, &v1alpha1.DockerComposeUpOptions{
+		WithDeps:      true,
+		ForceRecreate: true,
+		Pull:          "always",
+		BuildArgs:     map[string]string{"VERSION": "1.0", "DEBUG": "1"},
+	}, m.DockerComposeTarget().Spec.UpOptions)
+}
+
+func TestDockerComposeNoUpOptions(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `docker_compose('docker-compose.yml')`)
+
+	f.load()
+	m := f.assertDcManifest("foo")
+	assert.Nil(t, m.DockerComposeTarget().Spec.UpOptions)
+}
+
+func TestDockerComposeInvalidPull(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', pull='sometimes')
+`)
+
+	f.loadErrString(`dc_resource: pull must be one of "always", "missing", or "never"; got "sometimes"`)
+}
+
 func TestDockerComposeConflict(t *testing.T) {
 	f := newFixture(t)
 
@@ -214,6 +368,7 @@ func TestDockerComposeYAMLBlob(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		".env",
 		"docker-compose.yml",
 		f.JoinPath("foo", ".dockerignore"),
 	}
@@ -248,6 +403,24 @@ func TestDockerComposeBlobAndFileUsesFileDirForProjectPath(t *testing.T) {
 	)
 }
 
+func TestDockerComposeWatchesExtendedFiles(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("docker-compose.yml", `services:
+  bar:
+    extends:
+      file: base/docker-compose.yml
+      service: redis`)
+	f.file("base/docker-compose.yml", `services:
+  redis:
+    image: redis:alpine`)
+	f.file("Tiltfile", "docker_compose('docker-compose.yml')")
+
+	f.load("bar")
+	f.assertDcManifest("bar", noImage())
+	f.assertConfigFiles("Tiltfile", ".tiltignore", ".env", "docker-compose.yml", "base/docker-compose.yml")
+}
+
 func TestDockerComposeManifestNoDockerfile(t *testing.T) {
 	f := newFixture(t)
 
@@ -268,7 +441,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", ".env", "docker-compose.yml"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -299,7 +472,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml", "baz/.dockerignore"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", ".env", "docker-compose.yml", "baz/.dockerignore"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -332,7 +505,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml", "baz/.dockerignore"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", ".env", "docker-compose.yml", "baz/.dockerignore"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -367,6 +540,7 @@ networks:
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		".env",
 		"docker-compose.yml",
 		"baz/alternate-Dockerfile.dockerignore",
 	}
@@ -515,6 +689,7 @@ services:
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		filepath.Join("foo", ".env"),
 		filepath.Join("foo", "docker-compose.yml"),
 		filepath.Join("foo", ".dockerignore"),
 	}
@@ -772,7 +947,7 @@ fail("deliberate exit")
 
 	// Make sure that even though tiltfile execution failed, we still
 	// loaded config files correctly.
-	f.assertConfigFiles(".tiltignore", "Tiltfile", "docker-compose.yml", "foo/Dockerfile")
+	f.assertConfigFiles(".env", ".tiltignore", "Tiltfile", "docker-compose.yml", "foo/Dockerfile")
 }
 
 func TestDockerComposeDoesntSupportEntrypointOverride(t *testing.T) {
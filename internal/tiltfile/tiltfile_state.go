@@ -19,9 +19,12 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/apiset"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/tiltfile/budget"
 	"github.com/tilt-dev/tilt/internal/tiltfile/cisettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/hasher"
+	"github.com/tilt-dev/tilt/internal/tiltfile/helmvalues"
 	"github.com/tilt-dev/tilt/internal/tiltfile/links"
+	"github.com/tilt-dev/tilt/internal/tiltfile/log"
 	"github.com/tilt-dev/tilt/internal/tiltfile/print"
 	"github.com/tilt-dev/tilt/internal/tiltfile/probe"
 	"github.com/tilt-dev/tilt/internal/tiltfile/sys"
@@ -39,6 +42,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/dockerprune"
 	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
 	"github.com/tilt-dev/tilt/internal/tiltfile/git"
+	"github.com/tilt-dev/tilt/internal/tiltfile/groupsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/include"
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	tiltfile_k8s "github.com/tilt-dev/tilt/internal/tiltfile/k8s"
@@ -46,6 +50,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/loaddynamic"
 	"github.com/tilt-dev/tilt/internal/tiltfile/metrics"
 	"github.com/tilt-dev/tilt/internal/tiltfile/os"
+	"github.com/tilt-dev/tilt/internal/tiltfile/schedule"
+	"github.com/tilt-dev/tilt/internal/tiltfile/secrets"
 	"github.com/tilt-dev/tilt/internal/tiltfile/secretsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/shlex"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
@@ -86,6 +92,7 @@ type tiltfileState struct {
 	configPlugin     *config.Plugin
 	extensionPlugin  *tiltextension.Plugin
 	ciSettingsPlugin cisettings.Plugin
+	secretsPlugin    secrets.Plugin
 	features         feature.FeatureSet
 
 	// added to during execution
@@ -153,6 +160,9 @@ type tiltfileState struct {
 	// Temporary directory for storing generated artifacts during the lifetime of the tiltfile context.
 	// The directory is recursively deleted when the context is done.
 	scratchDir *fwatch.TempDir
+
+	// The project dir of the local() cache, if any local() calls were cached.
+	localCacheDir string
 }
 
 func newTiltfileState(
@@ -165,6 +175,7 @@ func newTiltfileState(
 	configPlugin *config.Plugin,
 	extensionPlugin *tiltextension.Plugin,
 	ciSettingsPlugin cisettings.Plugin,
+	secretsPlugin secrets.Plugin,
 	features feature.FeatureSet) *tiltfileState {
 	return &tiltfileState{
 		ctx:                       ctx,
@@ -176,6 +187,7 @@ func newTiltfileState(
 		configPlugin:              configPlugin,
 		extensionPlugin:           extensionPlugin,
 		ciSettingsPlugin:          ciSettingsPlugin,
+		secretsPlugin:             secretsPlugin,
 		buildIndex:                newBuildIndex(),
 		k8sObjectIndex:            tiltfile_k8s.NewState(),
 		k8sByName:                 make(map[string]*k8sResource),
@@ -226,15 +238,21 @@ func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest,
 		telemetry.NewPlugin(),
 		metrics.NewPlugin(),
 		updatesettings.NewPlugin(),
+		groupsettings.NewPlugin(),
 		s.ciSettingsPlugin,
 		secretsettings.NewPlugin(),
+		s.secretsPlugin,
 		encoding.NewPlugin(),
+		helmvalues.NewPlugin(),
 		shlex.NewPlugin(),
 		watch.NewPlugin(),
 		loaddynamic.NewPlugin(),
 		s.extensionPlugin,
 		links.NewPlugin(),
 		print.NewPlugin(),
+		log.NewPlugin(),
+		schedule.NewPlugin(),
+		budget.NewPlugin(),
 		probe.NewPlugin(),
 		tfv1alpha1.NewPlugin(),
 		hasher.NewPlugin(),
@@ -363,10 +381,12 @@ const (
 	// docker compose functions
 	dockerComposeN = "docker_compose"
 	dcResourceN    = "dc_resource"
+	dcServicesN    = "dc_services"
 
 	// k8s functions
 	k8sYamlN                    = "k8s_yaml"
 	filterYamlN                 = "filter_yaml"
+	k8sObjectsN                 = "k8s_objects"
 	k8sResourceN                = "k8s_resource"
 	portForwardN                = "port_forward"
 	k8sKindN                    = "k8s_kind"
@@ -551,8 +571,10 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{defaultRegistryN, s.defaultRegistry},
 		{dockerComposeN, s.dockerCompose},
 		{dcResourceN, s.dcResource},
+		{dcServicesN, s.dcServices},
 		{k8sYamlN, s.k8sYaml},
 		{filterYamlN, s.filterYaml},
+		{k8sObjectsN, s.k8sObjects},
 		{k8sResourceN, s.k8sResource},
 		{k8sCustomDeployN, s.k8sCustomDeploy},
 		{localResourceN, s.localResource},
@@ -1603,8 +1625,10 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 		lt := model.NewLocalTarget(model.TargetName(r.name), r.updateCmd, r.serveCmd, r.deps).
 			WithAllowParallel(r.allowParallel || r.updateCmd.Empty()).
 			WithLinks(r.links).
-			WithReadinessProbe(r.readinessProbe)
+			WithReadinessProbe(r.readinessProbe).
+			WithServeRestartPolicy(r.serveRestartPolicy, r.serveMaxRestarts)
 		lt.FileWatchIgnores = ignores
+		lt.ServeReadyRegex = r.serveReadyRegex
 
 		var mds []model.ManifestName
 		for _, md := range r.resourceDeps {
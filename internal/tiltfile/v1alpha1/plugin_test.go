@@ -179,6 +179,65 @@ v1alpha1.cmd(
 	})
 }
 
+func TestCmdProcessOptions(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.cmd(
+  name='my-cmd',
+  args=['nginx'],
+  grace_period='2m',
+  termination_signal='SIGQUIT',
+  pty=True,
+  restart_policy='OnFailure',
+  max_restarts=3,
+  timeout='1h',
+  stdin=True,
+  ready_regex='listening on [0-9]+',
+  env_files=['.env'],
+  expand_env=True)
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	cmd := set.GetSetForType(&v1alpha1.Cmd{})["my-cmd"].(*v1alpha1.Cmd)
+	require.NotNil(t, cmd)
+	require.Equal(t, &metav1.Duration{Duration: 2 * time.Minute}, cmd.Spec.GracePeriod)
+	require.Equal(t, "SIGQUIT", cmd.Spec.TerminationSignal)
+	require.True(t, cmd.Spec.PTY)
+	require.Equal(t, v1alpha1.CmdRestartPolicyOnFailure, cmd.Spec.RestartPolicy)
+	require.Equal(t, int32(3), cmd.Spec.MaxRestarts)
+	require.Equal(t, &metav1.Duration{Duration: time.Hour}, cmd.Spec.Timeout)
+	require.True(t, cmd.Spec.Stdin)
+	require.Equal(t, "listening on [0-9]+", cmd.Spec.ReadyRegex)
+	require.Equal(t, []string{f.JoinPath(".env")}, cmd.Spec.EnvFiles)
+	require.True(t, cmd.Spec.ExpandEnv)
+}
+
+func TestCmdContainerTarget(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.cmd(
+  name='my-cmd',
+  args=['npm', 'run', 'dev'],
+  target=v1alpha1.cmd_target(
+    container=v1alpha1.cmd_container_target(docker_compose_service='api')))
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	cmd := set.GetSetForType(&v1alpha1.Cmd{})["my-cmd"].(*v1alpha1.Cmd)
+	require.NotNil(t, cmd)
+	require.Equal(t, &v1alpha1.CmdTarget{
+		Container: &v1alpha1.CmdContainerTarget{DockerComposeService: "api"},
+	}, cmd.Spec.Target)
+}
+
 func TestUIButton(t *testing.T) {
 	f := newFixture(t)
 
@@ -262,6 +321,250 @@ v1alpha1.config_map(
 	})
 }
 
+func TestNotification(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.notification(
+  name='slack',
+  url='https://hooks.slack.com/services/xyz',
+  events=['BuildFailed', 'CrashLoop'],
+  resources=['fe'],
+  min_interval='5m')
+v1alpha1.notification(name='defaults', url='https://hooks.slack.com/services/abc')
+v1alpha1.notification(
+  name='desktop',
+  desktop=True,
+  events=['FirstFailure', 'RunFinished'],
+  muted_resources=['be'])
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	obj := set.GetSetForType(&v1alpha1.Notification{})["slack"].(*v1alpha1.Notification)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.NotificationSpec{
+		URL:         "https://hooks.slack.com/services/xyz",
+		Events:      []v1alpha1.NotificationEvent{v1alpha1.NotificationEventBuildFailed, v1alpha1.NotificationEventCrashLoop},
+		Resources:   []string{"fe"},
+		MinInterval: &metav1.Duration{Duration: 5 * time.Minute},
+	}, obj.Spec)
+
+	obj = set.GetSetForType(&v1alpha1.Notification{})["defaults"].(*v1alpha1.Notification)
+	require.NotNil(t, obj)
+	require.Nil(t, obj.Spec.MinInterval)
+
+	obj = set.GetSetForType(&v1alpha1.Notification{})["desktop"].(*v1alpha1.Notification)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.NotificationSpec{
+		Desktop:        true,
+		Events:         []v1alpha1.NotificationEvent{v1alpha1.NotificationEventFirstFailure, v1alpha1.NotificationEventRunFinished},
+		MutedResources: []string{"be"},
+	}, obj.Spec)
+}
+
+func TestNotificationValidation(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.notification(name='slack', url='hooks.slack.com/services/xyz')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "URLs must start with http(s)://")
+}
+
+func TestLogSink(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.log_sink(
+  name='loki',
+  type='loki',
+  endpoint='http://localhost:3100',
+  resources=['fe'],
+  min_level='warn',
+  sink_labels={'env': 'dev'},
+  batch_size=50,
+  flush_interval='5s',
+  buffer_size=1000)
+v1alpha1.log_sink(name='defaults', type='file', endpoint='/tmp/tilt.log')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	obj := set.GetSetForType(&v1alpha1.LogSink{})["loki"].(*v1alpha1.LogSink)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.LogSinkSpec{
+		Type:          v1alpha1.LogSinkTypeLoki,
+		Endpoint:      "http://localhost:3100",
+		Resources:     []string{"fe"},
+		MinLevel:      "warn",
+		Labels:        map[string]string{"env": "dev"},
+		BatchSize:     50,
+		FlushInterval: &metav1.Duration{Duration: 5 * time.Second},
+		BufferSize:    1000,
+	}, obj.Spec)
+
+	obj = set.GetSetForType(&v1alpha1.LogSink{})["defaults"].(*v1alpha1.LogSink)
+	require.NotNil(t, obj)
+	require.Nil(t, obj.Spec.FlushInterval)
+	require.Equal(t, 100, obj.BatchSize())
+}
+
+func TestLogSinkValidation(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.log_sink(name='fluentd', type='fluentd', endpoint='localhost')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "fluentd endpoints must be host:port")
+}
+
+func TestAlertRule(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.alert_rule(
+  name='slow-builds',
+  metric='BuildDuration',
+  duration='2m',
+  resources=['fe'],
+  message='check the build cache')
+v1alpha1.alert_rule(name='restarts', metric='PodRestarts', count=3, window='10m')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	obj := set.GetSetForType(&v1alpha1.AlertRule{})["slow-builds"].(*v1alpha1.AlertRule)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.AlertRuleSpec{
+		Metric:    v1alpha1.AlertMetricBuildDuration,
+		Duration:  &metav1.Duration{Duration: 2 * time.Minute},
+		Resources: []string{"fe"},
+		Message:   "check the build cache",
+	}, obj.Spec)
+
+	obj = set.GetSetForType(&v1alpha1.AlertRule{})["restarts"].(*v1alpha1.AlertRule)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.AlertRuleSpec{
+		Metric: v1alpha1.AlertMetricPodRestarts,
+		Count:  3,
+		Window: &metav1.Duration{Duration: 10 * time.Minute},
+	}, obj.Spec)
+}
+
+func TestAlertRuleValidation(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.alert_rule(name='slow-builds', metric='BuildDuration')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "BuildDuration rules need a positive duration")
+}
+
+func TestHealthProbe(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.health_probe(
+  name='fe-health',
+  resource='fe',
+  probe={'http_get': {'port': 8080, 'path': '/healthz'}, 'period_seconds': 5})
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	obj := set.GetSetForType(&v1alpha1.HealthProbe{})["fe-health"].(*v1alpha1.HealthProbe)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.HealthProbeSpec{
+		Resource: "fe",
+		Probe: v1alpha1.Probe{
+			Handler: v1alpha1.Handler{
+				HTTPGet: &v1alpha1.HTTPGetAction{Port: 8080, Path: "/healthz"},
+			},
+			PeriodSeconds: 5,
+		},
+	}, obj.Spec)
+}
+
+func TestHealthProbeValidation(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.health_probe(name='fe-health', resource='fe')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exactly one of exec, httpGet, or tcpSocket must be set")
+}
+
+func TestUIPanel(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.ui_panel(
+  name='pgadmin',
+  title='Database',
+  url='http://localhost:5050',
+  location={'component_type': 'Resource', 'component_id': 'db'},
+  order=1)
+v1alpha1.ui_panel(
+  name='readme',
+  title='README',
+  markdown='# Hello',
+  location={'component_type': 'Global', 'component_id': 'nav'})
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	obj := set.GetSetForType(&v1alpha1.UIPanel{})["pgadmin"].(*v1alpha1.UIPanel)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.UIPanelSpec{
+		Location: v1alpha1.UIComponentLocation{
+			ComponentType: v1alpha1.ComponentTypeResource,
+			ComponentID:   "db",
+		},
+		Title: "Database",
+		URL:   "http://localhost:5050",
+		Order: 1,
+	}, obj.Spec)
+
+	obj = set.GetSetForType(&v1alpha1.UIPanel{})["readme"].(*v1alpha1.UIPanel)
+	require.NotNil(t, obj)
+	require.Equal(t, "# Hello", obj.Spec.Markdown)
+}
+
+func TestUIPanelValidation(t *testing.T) {
+	f := newFixture(t)
+
+	f.File("Tiltfile", `
+v1alpha1.ui_panel(
+  name='pgadmin',
+  title='Database',
+  url='http://localhost:5050',
+  markdown='# Hello',
+  location={'component_type': 'Global'})
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Only one of URL and Markdown may be set")
+}
+
 func TestKubernetesApply(t *testing.T) {
 	f := newFixture(t)
 
@@ -18,6 +18,10 @@ import (
 func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	var err error
 
+	err = env.AddBuiltin("v1alpha1.alert_rule", p.alertRule)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.cmd", p.cmd)
 	if err != nil {
 		return err
@@ -38,6 +42,10 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.health_probe", p.healthProbe)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.kubernetes_apply", p.kubernetesApply)
 	if err != nil {
 		return err
@@ -46,10 +54,34 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.log_sink", p.logSink)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("v1alpha1.notification", p.notification)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.ui_button", p.uiButton)
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.ui_panel", p.uiPanel)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("v1alpha1.cmd_container_target", p.cmdContainerTarget)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("v1alpha1.cmd_ssh_target", p.cmdSSHTarget)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("v1alpha1.cmd_target", p.cmdTarget)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.config_map_disable_source", p.configMapDisableSource)
 	if err != nil {
 		return err
@@ -182,6 +214,17 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 	var restartOn RestartOnSpec = RestartOnSpec{t: t}
 	var startOn StartOnSpec = StartOnSpec{t: t}
 	var disableSource DisableSource = DisableSource{t: t}
+	var gracePeriod starlark.Value
+	var terminationSignal string
+	var pty bool
+	var stdin bool
+	var readyRegex string
+	var envFiles value.LocalPathList = value.NewLocalPathListUnpacker(t)
+	var expandEnv bool
+	var target CmdTarget = CmdTarget{t: t}
+	var restartPolicy string
+	var maxRestarts int
+	var timeout starlark.Value
 	var labels value.StringStringMap
 	var annotations value.StringStringMap
 	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
@@ -195,6 +238,17 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 		"restart_on?", &restartOn,
 		"start_on?", &startOn,
 		"disable_source?", &disableSource,
+		"grace_period?", &gracePeriod,
+		"termination_signal?", &terminationSignal,
+		"pty?", &pty,
+		"restart_policy?", &restartPolicy,
+		"max_restarts?", &maxRestarts,
+		"timeout?", &timeout,
+		"stdin?", &stdin,
+		"ready_regex?", &readyRegex,
+		"env_files?", &envFiles,
+		"expand_env?", &expandEnv,
+		"target?", &target,
 	)
 	if err != nil {
 		return nil, err
@@ -215,6 +269,25 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 	if disableSource.isUnpacked {
 		obj.Spec.DisableSource = (*v1alpha1.DisableSource)(&disableSource.Value)
 	}
+	obj.Spec.GracePeriod, err = unpackOptionalDuration(fn, "grace_period", gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	obj.Spec.TerminationSignal = terminationSignal
+	obj.Spec.PTY = pty
+	obj.Spec.Stdin = stdin
+	obj.Spec.ReadyRegex = readyRegex
+	obj.Spec.EnvFiles = envFiles.Value
+	obj.Spec.ExpandEnv = expandEnv
+	if target.isUnpacked {
+		obj.Spec.Target = (*v1alpha1.CmdTarget)(&target.Value)
+	}
+	obj.Spec.RestartPolicy = v1alpha1.CmdRestartPolicy(restartPolicy)
+	obj.Spec.MaxRestarts = int32(maxRestarts)
+	obj.Spec.Timeout, err = unpackOptionalDuration(fn, "timeout", timeout)
+	if err != nil {
+		return nil, err
+	}
 	obj.ObjectMeta.Labels = labels
 	obj.ObjectMeta.Annotations = annotations
 	return p.register(t, obj)
@@ -260,6 +333,8 @@ func (p Plugin) extension(t *starlark.Thread, fn *starlark.Builtin, args starlar
 		"repo_name?", &obj.Spec.RepoName,
 		"repo_path?", &obj.Spec.RepoPath,
 		"args?", &specArgs,
+		"version?", &obj.Spec.Version,
+		"sha256?", &obj.Spec.Sha256,
 	)
 	if err != nil {
 		return nil, err
@@ -277,6 +352,7 @@ func (p Plugin) extensionRepo(t *starlark.Thread, fn *starlark.Builtin, args sta
 		ObjectMeta: metav1.ObjectMeta{},
 		Spec:       v1alpha1.ExtensionRepoSpec{},
 	}
+	var trustedKeys value.StringList
 	var labels value.StringStringMap
 	var annotations value.StringStringMap
 	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
@@ -285,11 +361,13 @@ func (p Plugin) extensionRepo(t *starlark.Thread, fn *starlark.Builtin, args sta
 		"annotations?", &annotations,
 		"url?", &obj.Spec.URL,
 		"ref?", &obj.Spec.Ref,
+		"trusted_keys?", &trustedKeys,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	obj.Spec.TrustedKeys = trustedKeys
 	obj.ObjectMeta.Labels = labels
 	obj.ObjectMeta.Annotations = annotations
 	return p.register(t, obj)
@@ -438,6 +516,180 @@ func (p Plugin) kubernetesDiscovery(t *starlark.Thread, fn *starlark.Builtin, ar
 	return p.register(t, obj)
 }
 
+func (p Plugin) alertRule(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var err error
+	obj := &v1alpha1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec:       v1alpha1.AlertRuleSpec{},
+	}
+	var metric string
+	var count int
+	var duration starlark.Value
+	var window starlark.Value
+	var resources value.StringList
+	var labels value.StringStringMap
+	var annotations value.StringStringMap
+	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name", &obj.ObjectMeta.Name,
+		"labels?", &labels,
+		"annotations?", &annotations,
+		"metric?", &metric,
+		"duration?", &duration,
+		"count?", &count,
+		"window?", &window,
+		"resources?", &resources,
+		"message?", &obj.Spec.Message,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Spec.Metric = v1alpha1.AlertMetric(metric)
+	obj.Spec.Count = int32(count)
+	obj.Spec.Duration, err = unpackOptionalDuration(fn, "duration", duration)
+	if err != nil {
+		return nil, err
+	}
+	obj.Spec.Window, err = unpackOptionalDuration(fn, "window", window)
+	if err != nil {
+		return nil, err
+	}
+	obj.Spec.Resources = resources
+	obj.ObjectMeta.Labels = labels
+	obj.ObjectMeta.Annotations = annotations
+	return p.register(t, obj)
+}
+
+// Unpacks a duration like "5m", or None.
+func unpackOptionalDuration(fn *starlark.Builtin, param string, v starlark.Value) (*metav1.Duration, error) {
+	if v == nil || v == starlark.None {
+		return nil, nil
+	}
+	var d value.Duration
+	err := d.Unpack(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: for parameter %s: %v", fn.Name(), param, err)
+	}
+	return &metav1.Duration{Duration: d.AsDuration()}, nil
+}
+
+func (p Plugin) healthProbe(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var err error
+	obj := &v1alpha1.HealthProbe{
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec:       v1alpha1.HealthProbeSpec{},
+	}
+	var probe Probe = Probe{t: t}
+	var labels value.StringStringMap
+	var annotations value.StringStringMap
+	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name", &obj.ObjectMeta.Name,
+		"labels?", &labels,
+		"annotations?", &annotations,
+		"resource?", &obj.Spec.Resource,
+		"probe?", &probe,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if probe.isUnpacked {
+		obj.Spec.Probe = probe.Value
+	}
+	obj.ObjectMeta.Labels = labels
+	obj.ObjectMeta.Annotations = annotations
+	return p.register(t, obj)
+}
+
+func (p Plugin) logSink(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var err error
+	obj := &v1alpha1.LogSink{
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec:       v1alpha1.LogSinkSpec{},
+	}
+	var sinkType string
+	var resources value.StringList
+	var sinkLabels value.StringStringMap
+	var batchSize, bufferSize int
+	var flushInterval starlark.Value
+	var labels value.StringStringMap
+	var annotations value.StringStringMap
+	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name", &obj.ObjectMeta.Name,
+		"labels?", &labels,
+		"annotations?", &annotations,
+		"type?", &sinkType,
+		"endpoint?", &obj.Spec.Endpoint,
+		"resources?", &resources,
+		"min_level?", &obj.Spec.MinLevel,
+		"sink_labels?", &sinkLabels,
+		"batch_size?", &batchSize,
+		"flush_interval?", &flushInterval,
+		"buffer_size?", &bufferSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Spec.Type = v1alpha1.LogSinkType(sinkType)
+	obj.Spec.Resources = resources
+	obj.Spec.Labels = sinkLabels
+	obj.Spec.BatchSize = int32(batchSize)
+	obj.Spec.BufferSize = int32(bufferSize)
+	obj.Spec.FlushInterval, err = unpackOptionalDuration(fn, "flush_interval", flushInterval)
+	if err != nil {
+		return nil, err
+	}
+	obj.ObjectMeta.Labels = labels
+	obj.ObjectMeta.Annotations = annotations
+	return p.register(t, obj)
+}
+
+func (p Plugin) notification(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var err error
+	obj := &v1alpha1.Notification{
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec:       v1alpha1.NotificationSpec{},
+	}
+	var events value.StringList
+	var resources value.StringList
+	var minInterval starlark.Value
+	var mutedResources value.StringList
+	var labels value.StringStringMap
+	var annotations value.StringStringMap
+	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name", &obj.ObjectMeta.Name,
+		"labels?", &labels,
+		"annotations?", &annotations,
+		"url?", &obj.Spec.URL,
+		"events?", &events,
+		"resources?", &resources,
+		"min_interval?", &minInterval,
+		"desktop?", &obj.Spec.Desktop,
+		"muted_resources?", &mutedResources,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range events {
+		obj.Spec.Events = append(obj.Spec.Events, v1alpha1.NotificationEvent(e))
+	}
+	obj.Spec.Resources = resources
+	obj.Spec.MutedResources = mutedResources
+	if minInterval != nil && minInterval != starlark.None {
+		var d value.Duration
+		err = d.Unpack(minInterval)
+		if err != nil {
+			return nil, fmt.Errorf("%s: for parameter min_interval: %v", fn.Name(), err)
+		}
+		obj.Spec.MinInterval = &metav1.Duration{Duration: d.AsDuration()}
+	}
+	obj.ObjectMeta.Labels = labels
+	obj.ObjectMeta.Annotations = annotations
+	return p.register(t, obj)
+}
+
 func (p Plugin) uiButton(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var err error
 	obj := &v1alpha1.UIButton{
@@ -471,6 +723,431 @@ func (p Plugin) uiButton(t *starlark.Thread, fn *starlark.Builtin, args starlark
 	return p.register(t, obj)
 }
 
+func (p Plugin) uiPanel(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var err error
+	obj := &v1alpha1.UIPanel{
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec:       v1alpha1.UIPanelSpec{},
+	}
+	var location UIComponentLocation = UIComponentLocation{t: t}
+	var labels value.StringStringMap
+	var annotations value.StringStringMap
+	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name", &obj.ObjectMeta.Name,
+		"labels?", &labels,
+		"annotations?", &annotations,
+		"location?", &location,
+		"title?", &obj.Spec.Title,
+		"url?", &obj.Spec.URL,
+		"markdown?", &obj.Spec.Markdown,
+		"order?", &obj.Spec.Order,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Spec.Location = v1alpha1.UIComponentLocation(location.Value)
+	obj.ObjectMeta.Labels = labels
+	obj.ObjectMeta.Annotations = annotations
+	return p.register(t, obj)
+}
+
+type CmdContainerTarget struct {
+	*starlark.Dict
+	Value      v1alpha1.CmdContainerTarget
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) cmdContainerTarget(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name starlark.Value
+	var dockerComposeService starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name?", &name,
+		"docker_compose_service?", &dockerComposeService,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(2)
+
+	if name != nil {
+		err := dict.SetKey(starlark.String("name"), name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dockerComposeService != nil {
+		err := dict.SetKey(starlark.String("docker_compose_service"), dockerComposeService)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *CmdContainerTarget = &CmdContainerTarget{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *CmdContainerTarget) Unpack(v starlark.Value) error {
+	obj := v1alpha1.CmdContainerTarget{}
+
+	starlarkObj, ok := v.(*CmdContainerTarget)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "name" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Name = string(v)
+			continue
+		}
+		if key == "docker_compose_service" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.DockerComposeService = string(v)
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type CmdContainerTargetList struct {
+	*starlark.List
+	Value []v1alpha1.CmdContainerTarget
+	t     *starlark.Thread
+}
+
+func (o *CmdContainerTargetList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.CmdContainerTarget{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := CmdContainerTarget{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.CmdContainerTarget(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
+type CmdSSHTarget struct {
+	*starlark.Dict
+	Value      v1alpha1.CmdSSHTarget
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) cmdSSHTarget(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var host starlark.Value
+	var keyFile starlark.Value
+	var knownHostsFile starlark.Value
+	var forwardAgent starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"host?", &host,
+		"key_file?", &keyFile,
+		"known_hosts_file?", &knownHostsFile,
+		"forward_agent?", &forwardAgent,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(4)
+
+	if host != nil {
+		err := dict.SetKey(starlark.String("host"), host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if keyFile != nil {
+		err := dict.SetKey(starlark.String("key_file"), keyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if knownHostsFile != nil {
+		err := dict.SetKey(starlark.String("known_hosts_file"), knownHostsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if forwardAgent != nil {
+		err := dict.SetKey(starlark.String("forward_agent"), forwardAgent)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *CmdSSHTarget = &CmdSSHTarget{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *CmdSSHTarget) Unpack(v starlark.Value) error {
+	obj := v1alpha1.CmdSSHTarget{}
+
+	starlarkObj, ok := v.(*CmdSSHTarget)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "host" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Host = string(v)
+			continue
+		}
+		if key == "key_file" {
+			v := value.NewLocalPathUnpacker(o.t)
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.KeyFile = v.Value
+			continue
+		}
+		if key == "known_hosts_file" {
+			v := value.NewLocalPathUnpacker(o.t)
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.KnownHostsFile = v.Value
+			continue
+		}
+		if key == "forward_agent" {
+			v, ok := val.(starlark.Bool)
+			if !ok {
+				return fmt.Errorf("Expected bool, got: %v", val.Type())
+			}
+			obj.ForwardAgent = bool(v)
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type CmdSSHTargetList struct {
+	*starlark.List
+	Value []v1alpha1.CmdSSHTarget
+	t     *starlark.Thread
+}
+
+func (o *CmdSSHTargetList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.CmdSSHTarget{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := CmdSSHTarget{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.CmdSSHTarget(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
+type CmdTarget struct {
+	*starlark.Dict
+	Value      v1alpha1.CmdTarget
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) cmdTarget(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var sSH starlark.Value
+	var container starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"ssh?", &sSH,
+		"container?", &container,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(2)
+
+	if sSH != nil {
+		err := dict.SetKey(starlark.String("ssh"), sSH)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if container != nil {
+		err := dict.SetKey(starlark.String("container"), container)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *CmdTarget = &CmdTarget{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *CmdTarget) Unpack(v starlark.Value) error {
+	obj := v1alpha1.CmdTarget{}
+
+	starlarkObj, ok := v.(*CmdTarget)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "ssh" {
+			v := CmdSSHTarget{t: o.t}
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.SSH = (*v1alpha1.CmdSSHTarget)(&v.Value)
+			continue
+		}
+		if key == "container" {
+			v := CmdContainerTarget{t: o.t}
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.Container = (*v1alpha1.CmdContainerTarget)(&v.Value)
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type CmdTargetList struct {
+	*starlark.List
+	Value []v1alpha1.CmdTarget
+	t     *starlark.Thread
+}
+
+func (o *CmdTargetList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.CmdTarget{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := CmdTarget{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.CmdTarget(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
 type ConfigMapDisableSource struct {
 	*starlark.Dict
 	Value      v1alpha1.ConfigMapDisableSource
@@ -1424,15 +2101,17 @@ type IgnoreDef struct {
 func (p Plugin) ignoreDef(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var basePath starlark.Value
 	var patterns starlark.Value
+	var reason starlark.Value
 	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
 		"base_path?", &basePath,
 		"patterns?", &patterns,
+		"reason?", &reason,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	dict := starlark.NewDict(2)
+	dict := starlark.NewDict(3)
 
 	if basePath != nil {
 		err := dict.SetKey(starlark.String("base_path"), basePath)
@@ -1446,6 +2125,12 @@ func (p Plugin) ignoreDef(t *starlark.Thread, fn *starlark.Builtin, args starlar
 			return nil, err
 		}
 	}
+	if reason != nil {
+		err := dict.SetKey(starlark.String("reason"), reason)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var obj *IgnoreDef = &IgnoreDef{t: t}
 	err = obj.Unpack(dict)
 	if err != nil {
@@ -1493,6 +2178,14 @@ func (o *IgnoreDef) Unpack(v starlark.Value) error {
 			obj.Patterns = v
 			continue
 		}
+		if key == "reason" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Reason = string(v)
+			continue
+		}
 		return fmt.Errorf("Unexpected attribute name: %s", key)
 	}
 
@@ -6,6 +6,8 @@ import (
 
 	"go.starlark.net/starlark"
 	validation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type LabelValue string
@@ -16,21 +18,18 @@ func (lv *LabelValue) Unpack(v starlark.Value) error {
 		return fmt.Errorf("Value should be convertible to string, but is type %s", v.Type())
 	}
 
-	validationErrors := validation.IsQualifiedName(str)
-	if len(validationErrors) != 0 {
-		return fmt.Errorf("Invalid label %q: %s", str, strings.Join(validationErrors, ", "))
-	}
-
-	validLabelValueErrors := validation.IsValidLabelValue(str)
-	if len(validLabelValueErrors) != 0 {
-		return fmt.Errorf("Invalid label %q: %s", str, strings.Join(validLabelValueErrors, ", "))
-	}
+	// A label can be a path of nested groups, like "team/service/component".
+	// Each group in the path must be a valid label on its own.
+	for _, group := range strings.Split(str, model.GroupPathSeparator) {
+		validationErrors := validation.IsQualifiedName(group)
+		if len(validationErrors) != 0 {
+			return fmt.Errorf("Invalid label %q: %s", str, strings.Join(validationErrors, ", "))
+		}
 
-	// Tilt assumes prefixed labels are not added by the user and thus doesn't use them
-	// for resource grouping. For now, disallow users from adding prefixes so that they're
-	// not confused when they don't show up in resource groups.
-	if strings.Contains(str, "/") {
-		return fmt.Errorf("Invalid label %q: cannot contain /", str)
+		validLabelValueErrors := validation.IsValidLabelValue(group)
+		if len(validLabelValueErrors) != 0 {
+			return fmt.Errorf("Invalid label %q: %s", str, strings.Join(validLabelValueErrors, ", "))
+		}
 	}
 
 	*lv = LabelValue(str)
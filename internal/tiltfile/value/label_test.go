@@ -35,6 +35,24 @@ func TestLabelInvalidName(t *testing.T) {
 	require.Contains(t, err.Error(), "alphanumeric characters")
 }
 
+func TestLabelGroupPath(t *testing.T) {
+	v := LabelSet{}
+	err := v.Unpack(starlark.String("payments/api/db"))
+	require.NoError(t, err)
+
+	expected := LabelSet{Values: map[string]string{"payments/api/db": "payments/api/db"}}
+	require.Equal(t, expected, v)
+}
+
+func TestLabelGroupPathEmptyGroup(t *testing.T) {
+	v := LabelSet{}
+	err := v.Unpack(starlark.String("payments//db"))
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Invalid label "payments//db"`)
+	require.Contains(t, err.Error(), "name part must be non-empty")
+}
+
 func TestLabelInvalidType(t *testing.T) {
 	v := LabelSet{}
 	err := v.Unpack(starlark.NewDict(1))
@@ -26,8 +26,10 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 func (e Plugin) setWatchSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	err := starkit.SetState(thread, func(settings model.WatchSettings) (model.WatchSettings, error) {
 		var ignores value.StringOrStringList
+		var reason string
 		if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 			"ignore?", &ignores,
+			"reason?", &reason,
 		); err != nil {
 			return settings, err
 		}
@@ -37,6 +39,7 @@ func (e Plugin) setWatchSettings(thread *starlark.Thread, fn *starlark.Builtin,
 				LocalPath: starkit.AbsWorkingDir(thread),
 				Patterns:  ignores.Values,
 				Source:    "watch_settings()",
+				Reason:    reason,
 			})
 		}
 
@@ -49,6 +49,34 @@ load('./foo/Tiltfile', 'x')
 	}, MustState(result))
 }
 
+func TestReason(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+def ignore_generated():
+  watch_settings(ignore=['**/*.pb.go'], reason='generated protobuf code')
+
+watch_settings(ignore='foo')
+ignore_generated()
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, model.WatchSettings{
+		Ignores: []model.Dockerignore{
+			{
+				LocalPath: f.Path(),
+				Patterns:  []string{"foo"},
+				Source:    "watch_settings()",
+			},
+			{
+				LocalPath: f.Path(),
+				Patterns:  []string{"**/*.pb.go"},
+				Source:    "watch_settings()",
+				Reason:    "generated protobuf code",
+			},
+		},
+	}, MustState(result))
+}
+
 func NewFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewPlugin())
 }
@@ -3,9 +3,12 @@ package tiltfile
 import (
 	"github.com/google/wire"
 
+	"github.com/tilt-dev/tilt/internal/secretstore"
+
 	"github.com/tilt-dev/tilt/internal/tiltfile/cisettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
+	"github.com/tilt-dev/tilt/internal/tiltfile/secrets"
 	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
 	"github.com/tilt-dev/tilt/internal/tiltfile/version"
 )
@@ -17,4 +20,6 @@ var WireSet = wire.NewSet(
 	config.NewPlugin,
 	tiltextension.NewPlugin,
 	cisettings.NewPlugin,
+	secrets.NewPlugin,
+	secretstore.ProvideStore,
 )
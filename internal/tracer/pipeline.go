@@ -0,0 +1,131 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const pipelineTracerName = "tilt.dev/pipeline"
+
+// The OTLP collector that update pipeline traces are exported to,
+// as host:port, http://host:port (plaintext), or https://host:port.
+//
+// Empty if pipeline tracing is off.
+type PipelineTraceEndpoint string
+
+// PipelineTracer traces each update of a resource, from the file change
+// that triggered it to the resource becoming ready, so that teams can see
+// where their dev loop time goes in their own tracing stack.
+//
+// Unlike the usage tracer, it's off unless the user configures an endpoint.
+type PipelineTracer struct {
+	trace.Tracer
+}
+
+func NewPipelineTracer(tp trace.TracerProvider) PipelineTracer {
+	return PipelineTracer{Tracer: tp.Tracer(pipelineTracerName)}
+}
+
+// A tracer that doesn't record anything.
+func NewNoopPipelineTracer() PipelineTracer {
+	return NewPipelineTracer(trace.NewNoopTracerProvider())
+}
+
+// Creates a tracer that exports to the configured endpoint.
+//
+// The exporter also reads the standard OTEL_EXPORTER_OTLP_* environment
+// variables (like OTEL_EXPORTER_OTLP_HEADERS), for collectors that need
+// credentials.
+func ProvidePipelineTracer(ctx context.Context, endpoint PipelineTraceEndpoint) (PipelineTracer, error) {
+	if endpoint == "" {
+		return NewNoopPipelineTracer(), nil
+	}
+
+	opts, err := otlpEndpointOptions(string(endpoint))
+	if err != nil {
+		return PipelineTracer{}, err
+	}
+
+	// The exporter connects in the background, so a missing collector
+	// doesn't block startup.
+	//
+	// go.mod pins all of OpenTelemetry to v0.20 to match the apiserver
+	// (k8s.io/component-base/tracing uses this same exporter), so the SDK
+	// we actually build against predates otlptrace. When those pins are
+	// lifted, switch to otlptrace/otlptracegrpc.
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(opts...))
+	if err != nil {
+		return PipelineTracer{}, fmt.Errorf("exporting traces to %s: %v", endpoint, err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String("tilt"))),
+	)
+	go func() {
+		// Flush any buffered spans on the way out.
+		<-ctx.Done()
+		_ = tp.Shutdown(context.Background())
+	}()
+	return NewPipelineTracer(tp), nil
+}
+
+func otlpEndpointOptions(endpoint string) ([]otlpgrpc.Option, error) {
+	if !strings.Contains(endpoint, "://") {
+		return []otlpgrpc.Option{otlpgrpc.WithEndpoint(endpoint), otlpgrpc.WithInsecure()}, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: expected host:port or a URL", endpoint)
+	}
+	switch u.Scheme {
+	case "http":
+		return []otlpgrpc.Option{otlpgrpc.WithEndpoint(u.Host), otlpgrpc.WithInsecure()}, nil
+	case "https":
+		return []otlpgrpc.Option{otlpgrpc.WithEndpoint(u.Host)}, nil
+	}
+	return nil, fmt.Errorf("invalid OTLP endpoint %q: unsupported scheme %q", endpoint, u.Scheme)
+}
+
+type pipelineSpanKey struct{}
+
+// Attaches the span for the current update, so that the steps of the
+// update can add their own spans underneath it.
+//
+// We keep it under our own key rather than as the current span, so that
+// the usage tracer's spans don't get mixed up with the pipeline's.
+func ContextWithPipelineSpan(ctx context.Context, span trace.Span) context.Context {
+	return context.WithValue(ctx, pipelineSpanKey{}, span)
+}
+
+func PipelineSpanFromContext(ctx context.Context) trace.Span {
+	span, ok := ctx.Value(pipelineSpanKey{}).(trace.Span)
+	if !ok {
+		return nil
+	}
+	return span
+}
+
+// Starts a span for one step of the current update.
+//
+// If the update isn't being traced, returns a span that doesn't record
+// anything.
+func StartPipelineStep(ctx context.Context, name string, opts ...trace.SpanOption) trace.Span {
+	parent := PipelineSpanFromContext(ctx)
+	if parent == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+	_, span := parent.Tracer().Start(trace.ContextWithSpan(ctx, parent), name, opts...)
+	return span
+}
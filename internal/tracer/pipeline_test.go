@@ -0,0 +1,57 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartPipelineStepWithoutUpdate(t *testing.T) {
+	span := StartPipelineStep(context.Background(), "build")
+	assert.False(t, span.IsRecording())
+	span.End()
+}
+
+func TestStartPipelineStep(t *testing.T) {
+	pt, r := NewRecordingPipelineTracer()
+
+	_, root := pt.Start(context.Background(), "update")
+	ctx := ContextWithPipelineSpan(context.Background(), root)
+	StartPipelineStep(ctx, "build").End()
+	StartPipelineStep(ctx, "deploy").End()
+	root.End()
+
+	spans := r.Spans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "build", spans[0].Name)
+	assert.Equal(t, "deploy", spans[1].Name)
+	assert.Equal(t, "update", spans[2].Name)
+	for _, s := range spans[:2] {
+		assert.Equal(t, root.SpanContext().TraceID(), s.SpanContext.TraceID())
+		assert.Equal(t, root.SpanContext().SpanID(), s.Parent.SpanID())
+	}
+}
+
+func TestProvidePipelineTracerOff(t *testing.T) {
+	pt, err := ProvidePipelineTracer(context.Background(), "")
+	require.NoError(t, err)
+	_, span := pt.Start(context.Background(), "update")
+	assert.False(t, span.IsRecording())
+}
+
+func TestOTLPEndpointOptions(t *testing.T) {
+	for _, endpoint := range []string{"localhost:4317", "http://localhost:4317", "https://collector.example.com"} {
+		opts, err := otlpEndpointOptions(endpoint)
+		if assert.NoError(t, err, endpoint) {
+			assert.NotEmpty(t, opts, endpoint)
+		}
+	}
+
+	_, err := otlpEndpointOptions("ftp://localhost:4317")
+	assert.EqualError(t, err, `invalid OTLP endpoint "ftp://localhost:4317": unsupported scheme "ftp"`)
+
+	_, err = otlpEndpointOptions("http://")
+	assert.EqualError(t, err, `invalid OTLP endpoint "http://": expected host:port or a URL`)
+}
@@ -0,0 +1,54 @@
+package tracer
+
+import (
+	"context"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanRecorder keeps finished spans in memory, for tests.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []*sdktrace.SpanSnapshot
+}
+
+var _ sdktrace.SpanExporter = &SpanRecorder{}
+
+// Creates a pipeline tracer that records every span as soon as it ends.
+func NewRecordingPipelineTracer() (PipelineTracer, *SpanRecorder) {
+	r := &SpanRecorder{}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSyncer(r))
+	return NewPipelineTracer(tp), r
+}
+
+func (r *SpanRecorder) ExportSpans(ctx context.Context, spans []*sdktrace.SpanSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *SpanRecorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// The finished spans, in the order they ended.
+func (r *SpanRecorder) Spans() []*sdktrace.SpanSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*sdktrace.SpanSnapshot{}, r.spans...)
+}
+
+// The finished spans with the given name.
+func (r *SpanRecorder) SpansNamed(name string) []*sdktrace.SpanSnapshot {
+	var result []*sdktrace.SpanSnapshot
+	for _, s := range r.Spans() {
+		if s.Name == name {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package xdg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Returns the path of a project's JSON state file in the given state
+// subdirectory. Each project has its own file, keyed by the path of its main
+// Tiltfile.
+func ProjectStateFile(base Base, dir string, tiltfilePath string) (string, error) {
+	sum := sha256.Sum256([]byte(tiltfilePath))
+	name := hex.EncodeToString(sum[:])[:16] + ".json"
+	return base.StateFile(filepath.Join(dir, name))
+}
+
+// Reads a JSON file into v.
+//
+// Returns false if the file doesn't exist.
+func ReadJSONFile(p string, v interface{}) (bool, error) {
+	contents, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	err = json.Unmarshal(contents, v)
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %v", p, err)
+	}
+	return true, nil
+}
+
+// Writes v to a JSON file that only the user can read.
+func WriteJSONFile(p string, v interface{}) error {
+	contents, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(p, contents, 0600)
+}
+
+// Writes a file atomically, so that a crash never leaves a partial file
+// behind, and readers see either the old contents or the new.
+func WriteFileAtomic(p string, contents []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(contents)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
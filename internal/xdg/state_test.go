@@ -0,0 +1,51 @@
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFile(t *testing.T) {
+	base := FakeBase{Dir: t.TempDir()}
+	p, err := ProjectStateFile(base, "things", "/home/alice/proj/Tiltfile")
+	require.NoError(t, err)
+
+	other, err := ProjectStateFile(base, "things", "/home/alice/other/Tiltfile")
+	require.NoError(t, err)
+	assert.NotEqual(t, p, other)
+
+	var v map[string]int
+	ok, err := ReadJSONFile(p, &v)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, WriteJSONFile(p, map[string]int{"a": 1}))
+	require.NoError(t, WriteJSONFile(p, map[string]int{"b": 2}))
+
+	ok, err = ReadJSONFile(p, &v)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]int{"b": 2}, v)
+
+	// No temp files are left behind.
+	entries, err := os.ReadDir(filepath.Dir(p))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	info, err := os.Stat(p)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestReadJSONFileInvalid(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(p, []byte("{"), 0600))
+
+	var v map[string]int
+	_, err := ReadJSONFile(p, &v)
+	assert.Contains(t, err.Error(), "reading "+p)
+}
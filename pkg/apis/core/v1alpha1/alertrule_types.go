@@ -0,0 +1,270 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AlertRule fires an alert when a resource crosses a threshold, like
+// "a build took longer than 2m" or "the pod restarted more than 3 times
+// in 10m".
+//
+// Firing alerts show up as badges in the web UI. Notifications can post
+// them to a webhook with the AlertFiring and AlertResolved events.
+//
+// +k8s:openapi-gen=true
+type AlertRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   AlertRuleSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status AlertRuleStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// AlertRuleList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type AlertRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []AlertRule `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// AlertMetric is a measurement of a resource that an AlertRule checks.
+type AlertMetric string
+
+const (
+	// How long the resource's last build took, or how long the current
+	// build has been running, whichever is longer. Compared to Duration.
+	AlertMetricBuildDuration AlertMetric = "BuildDuration"
+
+	// The number of times a container in the resource's pod restarted
+	// during the Window. Compared to Count.
+	AlertMetricPodRestarts AlertMetric = "PodRestarts"
+
+	// The number of failed builds of the resource during the Window.
+	// Compared to Count.
+	AlertMetricBuildFailures AlertMetric = "BuildFailures"
+
+	// How long the resource took to become ready after its last build
+	// started, or how long it's been waiting, if it's not ready yet.
+	// Compared to Duration.
+	AlertMetricTimeToReady AlertMetric = "TimeToReady"
+)
+
+// All the metrics that an AlertRule can check.
+var AllAlertMetrics = []AlertMetric{
+	AlertMetricBuildDuration,
+	AlertMetricPodRestarts,
+	AlertMetricBuildFailures,
+	AlertMetricTimeToReady,
+}
+
+// The default window to count pod restarts and build failures in.
+var AlertRuleDefaultWindow = metav1.Duration{Duration: 10 * time.Minute}
+
+// AlertRuleSpec defines what to measure, and when to fire.
+type AlertRuleSpec struct {
+	// The measurement to check.
+	Metric AlertMetric `json:"metric" protobuf:"bytes,1,opt,name=metric,casttype=AlertMetric"`
+
+	// For BuildDuration and TimeToReady, fires when the resource takes
+	// longer than this.
+	//
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty" protobuf:"bytes,2,opt,name=duration"`
+
+	// For PodRestarts and BuildFailures, fires when there are more than
+	// this many during the Window.
+	//
+	// +optional
+	Count int32 `json:"count,omitempty" protobuf:"varint,3,opt,name=count"`
+
+	// For PodRestarts and BuildFailures, how far back to count.
+	//
+	// Defaults to 10 minutes.
+	//
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty" protobuf:"bytes,4,opt,name=window"`
+
+	// The names of the resources to check.
+	//
+	// If empty, checks all resources.
+	//
+	// +optional
+	Resources []string `json:"resources,omitempty" protobuf:"bytes,5,rep,name=resources"`
+
+	// A message to show with the alert, like what to do about it.
+	//
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
+}
+
+var _ resource.Object = &AlertRule{}
+var _ resourcestrategy.Validater = &AlertRule{}
+
+func (in *AlertRule) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *AlertRule) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *AlertRule) NamespaceScoped() bool {
+	return false
+}
+
+func (in *AlertRule) New() runtime.Object {
+	return &AlertRule{}
+}
+
+func (in *AlertRule) NewList() runtime.Object {
+	return &AlertRuleList{}
+}
+
+func (in *AlertRule) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "alertrules",
+	}
+}
+
+func (in *AlertRule) IsStorageVersion() bool {
+	return true
+}
+
+func (in *AlertRule) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+	switch in.Spec.Metric {
+	case AlertMetricBuildDuration, AlertMetricTimeToReady:
+		if in.Spec.Duration == nil || in.Spec.Duration.Duration <= 0 {
+			fieldErrors = append(fieldErrors, field.Required(
+				field.NewPath("spec.duration"),
+				fmt.Sprintf("%s rules need a positive duration", in.Spec.Metric)))
+		}
+	case AlertMetricPodRestarts, AlertMetricBuildFailures:
+		if in.Spec.Count < 0 {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.count"),
+				in.Spec.Count,
+				"must not be negative"))
+		}
+		if in.Spec.Window != nil && in.Spec.Window.Duration <= 0 {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.window"),
+				in.Spec.Window.Duration.String(),
+				"must be positive"))
+		}
+	default:
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec.metric"),
+			in.Spec.Metric,
+			alertMetricStrings()))
+	}
+	return fieldErrors
+}
+
+func alertMetricStrings() []string {
+	var result []string
+	for _, m := range AllAlertMetrics {
+		result = append(result, string(m))
+	}
+	return result
+}
+
+// Whether the rule checks the given resource.
+func (in *AlertRule) WantsResource(name string) bool {
+	if len(in.Spec.Resources) == 0 {
+		return true
+	}
+	for _, want := range in.Spec.Resources {
+		if want == name {
+			return true
+		}
+	}
+	return false
+}
+
+// How far back to count pod restarts and build failures, with the
+// default applied.
+func (in *AlertRule) Window() metav1.Duration {
+	if in.Spec.Window == nil {
+		return AlertRuleDefaultWindow
+	}
+	return *in.Spec.Window
+}
+
+var _ resource.ObjectList = &AlertRuleList{}
+
+func (in *AlertRuleList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// AlertRuleStatus defines the observed state of AlertRule
+type AlertRuleStatus struct {
+	// The resources that the alert is firing for, sorted by name.
+	//
+	// +optional
+	Firing []AlertFiring `json:"firing,omitempty" protobuf:"bytes,1,rep,name=firing"`
+}
+
+// AlertFiring is an alert firing for one resource.
+type AlertFiring struct {
+	// The name of the resource.
+	Resource string `json:"resource" protobuf:"bytes,1,opt,name=resource"`
+
+	// What the measurement was, like "2m31s" or "4".
+	Value string `json:"value" protobuf:"bytes,2,opt,name=value"`
+
+	// A description of why the alert is firing, followed by the rule's
+	// message, if any.
+	Message string `json:"message" protobuf:"bytes,3,opt,name=message"`
+
+	// When the alert started firing.
+	Since metav1.MicroTime `json:"since" protobuf:"bytes,4,opt,name=since"`
+}
+
+// AlertRule implements ObjectWithStatusSubResource interface.
+var _ resource.ObjectWithStatusSubResource = &AlertRule{}
+
+func (in *AlertRule) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+// AlertRuleStatus{} implements StatusSubResource interface.
+var _ resource.StatusSubResource = &AlertRuleStatus{}
+
+func (in AlertRuleStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*AlertRule).Status = in
+}
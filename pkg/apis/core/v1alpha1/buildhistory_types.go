@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BuildHistory records the completed builds of a resource, across Tilt sessions.
+//
+// There's one BuildHistory per resource, with the same name as the resource.
+// Tilt persists the history on disk, so that teams can compare how long
+// their dev loop takes from one session to the next.
+//
+// +k8s:openapi-gen=true
+type BuildHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   BuildHistorySpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status BuildHistoryStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// BuildHistoryList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BuildHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []BuildHistory `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// BuildHistorySpec is an empty struct.
+// BuildHistory is a record of what Tilt did, not a specification of behavior.
+type BuildHistorySpec struct {
+}
+
+var _ resource.Object = &BuildHistory{}
+var _ resourcestrategy.Validater = &BuildHistory{}
+
+func (in *BuildHistory) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *BuildHistory) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *BuildHistory) NamespaceScoped() bool {
+	return false
+}
+
+func (in *BuildHistory) New() runtime.Object {
+	return &BuildHistory{}
+}
+
+func (in *BuildHistory) NewList() runtime.Object {
+	return &BuildHistoryList{}
+}
+
+func (in *BuildHistory) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "buildhistories",
+	}
+}
+
+func (in *BuildHistory) IsStorageVersion() bool {
+	return true
+}
+
+func (in *BuildHistory) Validate(ctx context.Context) field.ErrorList {
+	return nil
+}
+
+var _ resource.ObjectList = &BuildHistoryList{}
+
+func (in *BuildHistoryList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// BuildHistoryStatus defines the observed state of BuildHistory
+type BuildHistoryStatus struct {
+	// Completed builds, oldest first.
+	//
+	// Tilt only keeps the most recent builds of each resource.
+	//
+	// +optional
+	Builds []BuildHistoryRecord `json:"builds,omitempty" protobuf:"bytes,1,rep,name=builds"`
+}
+
+// BuildHistoryRecord describes one completed build.
+//
+// A "build" is everything Tilt did to update the resource, including
+// deploying it.
+type BuildHistoryRecord struct {
+	// The time when the build started.
+	StartTime metav1.MicroTime `json:"startTime" protobuf:"bytes,1,opt,name=startTime"`
+
+	// The time when the build finished.
+	FinishTime metav1.MicroTime `json:"finishTime" protobuf:"bytes,2,opt,name=finishTime"`
+
+	// The error message, if the build failed.
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,3,opt,name=error"`
+
+	// The files whose changes triggered the build.
+	// +optional
+	Edits []string `json:"edits,omitempty" protobuf:"bytes,4,rep,name=edits"`
+
+	// Why the build happened, e.g., "Changed Files" or "Manual Trigger".
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
+
+	// The kinds of work the build did, e.g., "image", "live-update", or "k8s".
+	// +optional
+	BuildTypes []string `json:"buildTypes,omitempty" protobuf:"bytes,6,rep,name=buildTypes"`
+
+	// The start time of the Tilt session that ran the build.
+	//
+	// Use it to tell the builds of the current session from previous ones.
+	SessionStartTime metav1.MicroTime `json:"sessionStartTime" protobuf:"bytes,7,opt,name=sessionStartTime"`
+}
+
+// BuildHistory implements ObjectWithStatusSubResource interface.
+var _ resource.ObjectWithStatusSubResource = &BuildHistory{}
+
+func (in *BuildHistory) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+// BuildHistoryStatus{} implements StatusSubResource interface.
+var _ resource.StatusSubResource = &BuildHistoryStatus{}
+
+func (in BuildHistoryStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*BuildHistory).Status = in
+}
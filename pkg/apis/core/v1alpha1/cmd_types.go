@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"regexp"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -108,8 +109,198 @@ type CmdSpec struct {
 	//
 	// +optional
 	DisableSource *DisableSource `json:"disableSource,omitempty" protobuf:"bytes,7,opt,name=disableSource"`
+
+	// How long to wait for the process to exit after asking it to stop,
+	// before killing it.
+	//
+	// Defaults to 30s, the same as Kubernetes. Zero kills the process
+	// immediately.
+	//
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty" protobuf:"bytes,8,opt,name=gracePeriod"`
+
+	// The signal that asks the process to stop, like SIGINT or SIGQUIT, for
+	// servers that don't shut down cleanly on SIGTERM.
+	//
+	// Tilt sends it to the process group, then sends SIGKILL if the process
+	// hasn't exited after the grace period. Defaults to SIGTERM. Ignored on
+	// Windows.
+	//
+	// +optional
+	TerminationSignal string `json:"terminationSignal,omitempty" protobuf:"bytes,9,opt,name=terminationSignal"`
+
+	// Run the process in a pseudo-terminal, so that tools which disable
+	// colors and progress output when they aren't writing to a terminal
+	// print them anyway.
+	//
+	// The terminal has the size of the terminal that Tilt runs in, or 80x24.
+	// Stderr is merged into stdout. Ignored on Windows.
+	//
+	// +optional
+	PTY bool `json:"pty,omitempty" protobuf:"varint,10,opt,name=pty"`
+
+	// Whether to start the process again after it exits, for servers that
+	// should stay up.
+	//
+	// Tilt waits before each restart, starting at 1s and doubling up to 1m.
+	// Any change to the spec, or a StartOn or RestartOn trigger, resets the
+	// count. Defaults to Never.
+	//
+	// +optional
+	RestartPolicy CmdRestartPolicy `json:"restartPolicy,omitempty" protobuf:"bytes,11,opt,name=restartPolicy,casttype=CmdRestartPolicy"`
+
+	// The most times to restart the process under the RestartPolicy before
+	// giving up. Zero means no limit.
+	//
+	// +optional
+	MaxRestarts int32 `json:"maxRestarts,omitempty" protobuf:"varint,12,opt,name=maxRestarts"`
+
+	// How long the process may run before Tilt stops it, so that a hung
+	// script doesn't block its resource forever.
+	//
+	// Tilt stops the process the same way as on any other stop, then reports
+	// it as terminated with the reason TimedOut. Nil or zero means no
+	// timeout.
+	//
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,13,opt,name=timeout"`
+
+	// Keep the process's stdin open, so that users can send it input, like
+	// a key that tells a dev server to reload.
+	//
+	// Without it, the process's stdin is empty.
+	//
+	// +optional
+	Stdin bool `json:"stdin,omitempty" protobuf:"varint,14,opt,name=stdin"`
+
+	// A regular expression that a line of the process's output must match
+	// before the process is ready, like "listening on port [0-9]+", for
+	// servers that take a while to start listening.
+	//
+	// Lines are matched without colors or other ANSI escape codes. With a
+	// ReadinessProbe too, the probe starts once a line matches.
+	//
+	// +optional
+	ReadyRegex string `json:"readyRegex,omitempty" protobuf:"bytes,15,opt,name=readyRegex"`
+
+	// Files of KEY=VALUE lines, in the dotenv format, to add to the process
+	// environment, so that commands don't need a shell to source them.
+	//
+	// Relative paths are relative to Dir. Values can refer to variables in
+	// Tilt's environment or earlier in the file, like ${HOME}. Later files,
+	// then Env, take precedence.
+	//
+	// +optional
+	// +tilt:local-path=true
+	EnvFiles []string `json:"envFiles,omitempty" protobuf:"bytes,16,rep,name=envFiles"`
+
+	// Expand $VAR and ${VAR} in Args and Env before running the process,
+	// with Tilt's environment, the EnvFiles, and Env, like a shell would.
+	//
+	// Unset variables expand to the empty string. Use $$ for a literal $.
+	//
+	// +optional
+	ExpandEnv bool `json:"expandEnv,omitempty" protobuf:"varint,17,opt,name=expandEnv"`
+
+	// Where to run the process, for processes that shouldn't run on this
+	// machine, like servers that run on a shared dev box.
+	//
+	// Nil runs the process on this machine.
+	//
+	// +optional
+	Target *CmdTarget `json:"target,omitempty" protobuf:"bytes,18,opt,name=target"`
+}
+
+// CmdTarget says where to run a process other than on this machine.
+//
+// Exactly one field must be set.
+type CmdTarget struct {
+	// Run the process on a remote host over SSH.
+	//
+	// +optional
+	SSH *CmdSSHTarget `json:"ssh,omitempty" protobuf:"bytes,1,opt,name=ssh"`
+
+	// Run the process in a running container, like `docker exec`.
+	//
+	// +optional
+	Container *CmdContainerTarget `json:"container,omitempty" protobuf:"bytes,2,opt,name=container"`
+}
+
+// CmdContainerTarget says which container a process runs in.
+//
+// The process runs in the container's working directory, not the Cmd's dir,
+// and gets only the container's env and the Cmd's own env.
+//
+// Exactly one field must be set.
+type CmdContainerTarget struct {
+	// The name or ID of a container on Tilt's Docker daemon.
+	//
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+
+	// The name of a DockerComposeService object. The process runs in the
+	// service's current container, which is looked up every time the process
+	// starts.
+	//
+	// +optional
+	DockerComposeService string `json:"dockerComposeService,omitempty" protobuf:"bytes,2,opt,name=dockerComposeService"`
 }
 
+// CmdSSHTarget says how to reach the host that a process runs on over SSH.
+//
+// The process runs in the same directory as it would on this machine, so
+// the code should be at the same path on the remote host. Only the Cmd's own
+// env is sent, not Tilt's.
+type CmdSSHTarget struct {
+	// The host and, optionally, the user and port, like
+	// "alice@devbox:2222". The user defaults to the local user, and the
+	// port to 22.
+	Host string `json:"host" protobuf:"bytes,1,opt,name=host"`
+
+	// A private key to log in with. Keys in the local SSH agent are tried
+	// too.
+	//
+	// +optional
+	// +tilt:local-path=true
+	KeyFile string `json:"keyFile,omitempty" protobuf:"bytes,2,opt,name=keyFile"`
+
+	// The known_hosts file that verifies the host's key. Defaults to
+	// ~/.ssh/known_hosts.
+	//
+	// +optional
+	// +tilt:local-path=true
+	KnownHostsFile string `json:"knownHostsFile,omitempty" protobuf:"bytes,3,opt,name=knownHostsFile"`
+
+	// Forward the local SSH agent, so that the process can use the local
+	// keys, like to pull from git.
+	//
+	// +optional
+	ForwardAgent bool `json:"forwardAgent,omitempty" protobuf:"varint,4,opt,name=forwardAgent"`
+}
+
+// CmdRestartPolicy says when to restart a process that exited.
+type CmdRestartPolicy string
+
+const (
+	// Restart the process whenever it exits.
+	CmdRestartPolicyAlways CmdRestartPolicy = "Always"
+
+	// Restart the process only when it exits with a non-zero exit code.
+	CmdRestartPolicyOnFailure CmdRestartPolicy = "OnFailure"
+
+	// Never restart the process.
+	CmdRestartPolicyNever CmdRestartPolicy = "Never"
+)
+
+var CmdRestartPolicies = []string{
+	string(CmdRestartPolicyAlways),
+	string(CmdRestartPolicyOnFailure),
+	string(CmdRestartPolicyNever),
+}
+
+// Signals that a Cmd can be stopped with.
+var CmdTerminationSignals = []string{"SIGTERM", "SIGINT", "SIGQUIT", "SIGHUP", "SIGUSR1", "SIGUSR2"}
+
 var _ resource.Object = &Cmd{}
 var _ resourcestrategy.Validater = &Cmd{}
 
@@ -146,8 +337,64 @@ func (in *Cmd) IsStorageVersion() bool {
 }
 
 func (in *Cmd) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	var fieldErrors field.ErrorList
+	if in.Spec.GracePeriod != nil && in.Spec.GracePeriod.Duration < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec", "gracePeriod"), in.Spec.GracePeriod.Duration.String(), "must not be negative"))
+	}
+	if sig := in.Spec.TerminationSignal; sig != "" && !isCmdTerminationSignal(sig) {
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec", "terminationSignal"), sig, CmdTerminationSignals))
+	}
+	switch in.Spec.RestartPolicy {
+	case "", CmdRestartPolicyAlways, CmdRestartPolicyOnFailure, CmdRestartPolicyNever:
+	default:
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec", "restartPolicy"), in.Spec.RestartPolicy, CmdRestartPolicies))
+	}
+	if in.Spec.Timeout != nil && in.Spec.Timeout.Duration < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec", "timeout"), in.Spec.Timeout.Duration.String(), "must not be negative"))
+	}
+	if in.Spec.ReadyRegex != "" {
+		_, err := regexp.Compile(in.Spec.ReadyRegex)
+		if err != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec", "readyRegex"), in.Spec.ReadyRegex, err.Error()))
+		}
+	}
+	if in.Spec.MaxRestarts < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec", "maxRestarts"), in.Spec.MaxRestarts, "must not be negative"))
+	}
+	if in.Spec.Target != nil {
+		fieldErrors = append(fieldErrors, in.Spec.Target.validate(field.NewPath("spec", "target"))...)
+	}
+	return fieldErrors
+}
+
+func (in *CmdTarget) validate(path *field.Path) field.ErrorList {
+	var fieldErrors field.ErrorList
+	if (in.SSH == nil) == (in.Container == nil) {
+		return append(fieldErrors, field.Required(path, "must set exactly one of ssh or container"))
+	}
+	if in.SSH != nil && in.SSH.Host == "" {
+		fieldErrors = append(fieldErrors, field.Required(path.Child("ssh", "host"), "must not be empty"))
+	}
+	if in.Container != nil && (in.Container.Name == "") == (in.Container.DockerComposeService == "") {
+		fieldErrors = append(fieldErrors, field.Required(path.Child("container"),
+			"must set exactly one of name or dockerComposeService"))
+	}
+	return fieldErrors
+}
+
+func isCmdTerminationSignal(sig string) bool {
+	for _, s := range CmdTerminationSignals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
 }
 
 var _ resource.ObjectList = &CmdList{}
@@ -184,6 +431,20 @@ type CmdStatus struct {
 	// Details about whether/why this is disabled.
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
+
+	// Details about restarts under the RestartPolicy.
+	// +optional
+	Restarts *CmdRestartStatus `json:"restarts,omitempty" protobuf:"bytes,6,opt,name=restarts"`
+}
+
+// CmdRestartStatus tracks the restarts of a process under its RestartPolicy.
+type CmdRestartStatus struct {
+	// How many times the process has been restarted.
+	Count int32 `json:"count" protobuf:"varint,1,opt,name=count"`
+
+	// When the process will be restarted, if it's waiting to restart.
+	// +optional
+	NextRestartTime metav1.MicroTime `json:"nextRestartTime,omitempty" protobuf:"bytes,2,opt,name=nextRestartTime"`
 }
 
 // CmdStateWaiting is a waiting state of a local command.
@@ -200,6 +461,29 @@ type CmdStateRunning struct {
 
 	// Time at which the command was last started.
 	StartedAt metav1.MicroTime `json:"startedAt,omitempty" protobuf:"bytes,2,opt,name=startedAt"`
+
+	// Resources used by the process and its descendants, sampled every few
+	// seconds.
+	// +optional
+	Usage *CmdResourceUsage `json:"usage,omitempty" protobuf:"bytes,3,opt,name=usage"`
+
+	// The line of output that matched the ReadyRegex, once one has.
+	// +optional
+	ReadyMatch string `json:"readyMatch,omitempty" protobuf:"bytes,4,opt,name=readyMatch"`
+
+	// Time at which the command first became ready, if it has.
+	// +optional
+	ReadyAt metav1.MicroTime `json:"readyAt,omitempty" protobuf:"bytes,5,opt,name=readyAt"`
+}
+
+// CmdResourceUsage is a sample of the resources that a running command uses.
+type CmdResourceUsage struct {
+	// CPU use since the previous sample, in thousandths of a core, like 1500
+	// for one and a half cores.
+	CPUMillicores int64 `json:"cpuMillicores" protobuf:"varint,1,opt,name=cpuMillicores"`
+
+	// Resident memory, in bytes.
+	MemoryBytes int64 `json:"memoryBytes" protobuf:"varint,2,opt,name=memoryBytes"`
 }
 
 // CmdStateTerminated is a terminated state of a local command.
@@ -221,6 +505,9 @@ type CmdStateTerminated struct {
 	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
 }
 
+// The Reason of a terminated process that ran longer than its Timeout.
+const CmdReasonTimedOut = "TimedOut"
+
 // Cmd implements ObjectWithStatusSubResource interface.
 var _ resource.ObjectWithStatusSubResource = &Cmd{}
 
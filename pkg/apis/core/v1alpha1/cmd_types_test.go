@@ -0,0 +1,74 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestCmdValidate(t *testing.T) {
+	cmd := &v1alpha1.Cmd{Spec: v1alpha1.CmdSpec{
+		Args:              []string{"nginx"},
+		GracePeriod:       &metav1.Duration{Duration: time.Minute},
+		TerminationSignal: "SIGQUIT",
+		RestartPolicy:     v1alpha1.CmdRestartPolicyAlways,
+		MaxRestarts:       5,
+		Timeout:           &metav1.Duration{Duration: time.Hour},
+	}}
+	assert.Empty(t, cmd.Validate(context.Background()))
+
+	cmd.Spec.GracePeriod.Duration = -time.Second
+	cmd.Spec.TerminationSignal = "SIGSTOP"
+	cmd.Spec.RestartPolicy = "Sometimes"
+	cmd.Spec.MaxRestarts = -1
+	cmd.Spec.Timeout.Duration = -time.Hour
+	errs := cmd.Validate(context.Background())
+	if assert.Len(t, errs, 5) {
+		assert.Contains(t, errs[0].Error(), "spec.gracePeriod")
+		assert.Contains(t, errs[1].Error(), "spec.terminationSignal")
+		assert.Contains(t, errs[2].Error(), "spec.restartPolicy")
+		assert.Contains(t, errs[3].Error(), "spec.timeout")
+		assert.Contains(t, errs[4].Error(), "spec.maxRestarts")
+	}
+}
+
+func TestCmdValidateTarget(t *testing.T) {
+	cmd := &v1alpha1.Cmd{Spec: v1alpha1.CmdSpec{
+		Args:   []string{"npm", "run", "dev"},
+		Target: &v1alpha1.CmdTarget{SSH: &v1alpha1.CmdSSHTarget{Host: "alice@devbox"}},
+	}}
+	assert.Empty(t, cmd.Validate(context.Background()))
+
+	cmd.Spec.Target.SSH.Host = ""
+	errs := cmd.Validate(context.Background())
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "spec.target.ssh.host")
+	}
+
+	cmd.Spec.Target = &v1alpha1.CmdTarget{}
+	errs = cmd.Validate(context.Background())
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "spec.target")
+	}
+
+	cmd.Spec.Target = &v1alpha1.CmdTarget{Container: &v1alpha1.CmdContainerTarget{DockerComposeService: "api"}}
+	assert.Empty(t, cmd.Validate(context.Background()))
+
+	cmd.Spec.Target.Container.Name = "api-1"
+	errs = cmd.Validate(context.Background())
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "spec.target.container")
+	}
+
+	cmd.Spec.Target.SSH = &v1alpha1.CmdSSHTarget{Host: "alice@devbox"}
+	cmd.Spec.Target.Container = &v1alpha1.CmdContainerTarget{Name: "api-1"}
+	errs = cmd.Validate(context.Background())
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "exactly one of ssh or container")
+	}
+}
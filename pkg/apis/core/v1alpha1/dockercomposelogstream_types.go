@@ -98,8 +98,7 @@ func (in *DockerComposeLogStream) IsStorageVersion() bool {
 }
 
 func (in *DockerComposeLogStream) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	return in.Spec.Project.validateAsSubfield(ctx, field.NewPath("spec", "project"))
 }
 
 var _ resource.ObjectList = &DockerComposeLogStreamList{}
@@ -70,6 +70,42 @@ type DockerComposeServiceSpec struct {
 	//
 	// +optional
 	DisableSource *DisableSource `json:"disableSource,omitempty" protobuf:"bytes,4,opt,name=disableSource"`
+
+	// Options for how Docker Compose builds and starts the service.
+	//
+	// +optional
+	UpOptions *DockerComposeUpOptions `json:"upOptions,omitempty" protobuf:"bytes,5,opt,name=upOptions"`
+}
+
+// Options for how Docker Compose builds and starts a service.
+type DockerComposeUpOptions struct {
+	// Whether to also start the services this service depends on.
+	//
+	// By default, Tilt starts each service on its own, like
+	// `docker-compose up --no-deps`.
+	//
+	// +optional
+	WithDeps bool `json:"withDeps,omitempty" protobuf:"varint,1,opt,name=withDeps"`
+
+	// Whether to recreate the container, even if its configuration and
+	// image haven't changed. Passed to docker-compose as `--force-recreate`.
+	//
+	// +optional
+	ForceRecreate bool `json:"forceRecreate,omitempty" protobuf:"varint,2,opt,name=forceRecreate"`
+
+	// When to pull the service's image. Can be one of "always", "missing",
+	// or "never". Passed to docker-compose as `--pull`.
+	//
+	// If omitted, uses the service's pull_policy.
+	//
+	// +optional
+	Pull string `json:"pull,omitempty" protobuf:"bytes,3,opt,name=pull"`
+
+	// Build args that override the ones in the service's build section,
+	// when Docker Compose builds the image.
+	//
+	// +optional
+	BuildArgs map[string]string `json:"buildArgs,omitempty" protobuf:"bytes,4,rep,name=buildArgs"`
 }
 
 var _ resource.Object = &DockerComposeService{}
@@ -113,8 +149,7 @@ func (in *DockerComposeService) IsStorageVersion() bool {
 }
 
 func (in *DockerComposeService) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	return in.Spec.Project.validateAsSubfield(ctx, field.NewPath("spec", "project"))
 }
 
 var _ resource.ObjectList = &DockerComposeServiceList{}
@@ -211,6 +246,39 @@ type DockerComposeProject struct {
 
 	// Path to an env file to use. Passed to docker-compose as `--env-file FILE`.
 	EnvFile string `json:"envFile,omitempty" protobuf:"bytes,5,opt,name=envFile"`
+
+	// Profiles to enable. Passed to docker-compose as `--profile NAME`.
+	//
+	// Services without a profile are always enabled. If omitted, services
+	// from every profile are enabled.
+	//
+	// +optional
+	Profiles []string `json:"profiles,omitempty" protobuf:"bytes,6,rep,name=profiles"`
+
+	// The Docker daemon to run the project on, e.g., ssh://user@host or
+	// tcp://host:2376. Passed to docker-compose as DOCKER_HOST.
+	//
+	// If neither DockerHost nor DockerContext is set, uses Tilt's Docker daemon.
+	//
+	// +optional
+	DockerHost string `json:"dockerHost,omitempty" protobuf:"bytes,7,opt,name=dockerHost"`
+
+	// The Docker CLI context to run the project with. Passed to
+	// docker-compose as DOCKER_CONTEXT.
+	//
+	// Can't be set together with DockerHost.
+	//
+	// +optional
+	DockerContext string `json:"dockerContext,omitempty" protobuf:"bytes,8,opt,name=dockerContext"`
+}
+
+func (in *DockerComposeProject) validateAsSubfield(_ context.Context, rootField *field.Path) field.ErrorList {
+	var fieldErrors field.ErrorList
+	if in.DockerHost != "" && in.DockerContext != "" {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			rootField.Child("dockerContext"), in.DockerContext, "can't be set together with dockerHost"))
+	}
+	return fieldErrors
 }
 
 // State of a standalone container in Docker.
@@ -242,6 +310,11 @@ type DockerContainerState struct {
 	// When the container process finished.
 	// +optional
 	FinishedAt metav1.MicroTime `json:"finishedAt,omitempty" protobuf:"bytes,6,opt,name=finishedAt"`
+
+	// The result of the container's healthcheck, if it has one.
+	// Can be one of "starting", "healthy", or "unhealthy".
+	// +optional
+	HealthStatus string `json:"healthStatus,omitempty" protobuf:"bytes,7,opt,name=healthStatus"`
 }
 
 // How docker binds container ports to the host network
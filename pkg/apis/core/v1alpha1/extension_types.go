@@ -18,6 +18,11 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/blang/semver"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -74,6 +79,24 @@ type ExtensionSpec struct {
 	//
 	// +optional
 	Args []string `json:"args,omitempty" protobuf:"bytes,3,rep,name=args"`
+
+	// A semantic version range that the extension must satisfy,
+	// like ">=1.2.0 <2.0.0" or "1.x".
+	//
+	// Extensions declare their version in a VERSION file next to
+	// their Tiltfile.
+	//
+	// +optional
+	Version string `json:"version,omitempty" protobuf:"bytes,4,opt,name=version"`
+
+	// The SHA-256 digest of the extension directory, in hex.
+	//
+	// If set, the extension won't load if its contents don't match,
+	// so that an extension can be pinned to code that's been reviewed.
+	// The digest of a loaded extension is in its status.
+	//
+	// +optional
+	Sha256 string `json:"sha256,omitempty" protobuf:"bytes,5,opt,name=sha256"`
 }
 
 var _ resource.Object = &Extension{}
@@ -116,8 +139,26 @@ func (in *Extension) IsStorageVersion() bool {
 }
 
 func (in *Extension) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	var fieldErrors field.ErrorList
+	if in.Spec.Version != "" {
+		_, err := semver.ParseRange(in.Spec.Version)
+		if err != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.version"),
+				in.Spec.Version,
+				fmt.Sprintf("must be a semantic version range: %v", err)))
+		}
+	}
+	if in.Spec.Sha256 != "" {
+		b, err := hex.DecodeString(in.Spec.Sha256)
+		if err != nil || len(b) != sha256.Size {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.sha256"),
+				in.Spec.Sha256,
+				"must be a SHA-256 digest in hex"))
+		}
+	}
+	return fieldErrors
 }
 
 var _ resource.ObjectList = &ExtensionList{}
@@ -134,6 +175,21 @@ type ExtensionStatus struct {
 	// The path to the extension on disk. This location should be shared
 	// and readable by all Tilt instances.
 	Path string `json:"path,omitempty" protobuf:"bytes,2,opt,name=path"`
+
+	// The version of the extension, from its VERSION file.
+	//
+	// +optional
+	Version string `json:"version,omitempty" protobuf:"bytes,3,opt,name=version"`
+
+	// The SHA-256 digest of the extension directory, in hex.
+	//
+	// +optional
+	Sha256 string `json:"sha256,omitempty" protobuf:"bytes,4,opt,name=sha256"`
+
+	// Whether the extension was signed by one of the trusted keys of its repo.
+	//
+	// +optional
+	Verified bool `json:"verified,omitempty" protobuf:"varint,5,opt,name=verified"`
 }
 
 // Extension implements ObjectWithStatusSubResource interface.
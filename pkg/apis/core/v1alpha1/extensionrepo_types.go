@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"path/filepath"
 	strings "strings"
 
@@ -67,8 +69,23 @@ type ExtensionRepoSpec struct {
 	// the repo to the latest version.
 	// +optional
 	Ref string `json:"ref,omitempty" protobuf:"bytes,2,opt,name=ref"`
+
+	// Ed25519 public keys, in base64, that extensions in the repo
+	// must be signed with.
+	//
+	// If set, each extension must have an extension.sig file next to its
+	// Tiltfile that holds a base64 signature of its SHA-256 digest (in hex),
+	// made with one of these keys. Extensions that aren't signed won't load.
+	//
+	// +optional
+	TrustedKeys []string `json:"trustedKeys,omitempty" protobuf:"bytes,3,rep,name=trustedKeys"`
 }
 
+// Set on an ExtensionRepo to ask Tilt to fetch the latest version of the
+// repo. The value is an opaque token, usually a timestamp; each new value
+// is a new request.
+const AnnotationExtensionRepoUpdate = "tilt.dev/extensionrepo-update"
+
 var _ resource.Object = &ExtensionRepo{}
 var _ resourcestrategy.Validater = &ExtensionRepo{}
 
@@ -124,6 +141,15 @@ func (in *ExtensionRepo) Validate(ctx context.Context) field.ErrorList {
 			url,
 			"file:// URLs must be absolute (e.g., file:///home/user/repo)"))
 	}
+	for i, key := range in.Spec.TrustedKeys {
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(b) != ed25519.PublicKeySize {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.trustedKeys").Index(i),
+				key,
+				"must be an Ed25519 public key in base64"))
+		}
+	}
 	return fieldErrors
 }
 
@@ -81,6 +81,14 @@ type IgnoreDef struct {
 	//
 	// See https://docs.docker.com/engine/reference/builder/#dockerignore-file.
 	Patterns []string `json:"patterns,omitempty" protobuf:"bytes,2,rep,name=patterns"`
+
+	// Reason is a human-readable explanation of where this ignore came from
+	// and why it exists (e.g., ".tiltignore" or "generated protobuf code").
+	//
+	// Used when debugging why a file change did not trigger an update.
+	//
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,3,opt,name=reason"`
 }
 
 var _ resource.Object = &FileWatch{}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HealthProbe periodically checks the health of a resource with an HTTP,
+// TCP, or exec probe.
+//
+// Unlike the readiness probe on a local_resource serve_cmd, a HealthProbe
+// can be attached to any resource, including Kubernetes and Docker Compose
+// resources. The resource isn't ready until all its probes pass, and
+// a probe that starts failing puts the resource in an error state.
+//
+// +k8s:openapi-gen=true
+type HealthProbe struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   HealthProbeSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status HealthProbeStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// HealthProbeList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type HealthProbeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []HealthProbe `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// HealthProbeSpec defines what to probe, and which resource it belongs to.
+type HealthProbeSpec struct {
+	// The name of the resource whose health the probe checks.
+	Resource string `json:"resource" protobuf:"bytes,1,opt,name=resource"`
+
+	// How to check the resource's health.
+	//
+	// Exactly one of exec, httpGet, or tcpSocket must be set.
+	Probe Probe `json:"probe" protobuf:"bytes,2,opt,name=probe"`
+}
+
+var _ resource.Object = &HealthProbe{}
+var _ resourcestrategy.Validater = &HealthProbe{}
+
+func (in *HealthProbe) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *HealthProbe) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *HealthProbe) NamespaceScoped() bool {
+	return false
+}
+
+func (in *HealthProbe) New() runtime.Object {
+	return &HealthProbe{}
+}
+
+func (in *HealthProbe) NewList() runtime.Object {
+	return &HealthProbeList{}
+}
+
+func (in *HealthProbe) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "healthprobes",
+	}
+}
+
+func (in *HealthProbe) IsStorageVersion() bool {
+	return true
+}
+
+func (in *HealthProbe) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+	if in.Spec.Resource == "" {
+		fieldErrors = append(fieldErrors, field.Required(
+			field.NewPath("spec.resource"),
+			"a health probe needs a resource"))
+	}
+
+	probePath := field.NewPath("spec.probe")
+	handlers := 0
+	if in.Spec.Probe.Exec != nil {
+		handlers++
+		if len(in.Spec.Probe.Exec.Command) == 0 {
+			fieldErrors = append(fieldErrors, field.Required(
+				probePath.Child("exec", "command"),
+				"exec probes need a command"))
+		}
+	}
+	if in.Spec.Probe.HTTPGet != nil {
+		handlers++
+	}
+	if in.Spec.Probe.TCPSocket != nil {
+		handlers++
+	}
+	if handlers != 1 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			probePath,
+			handlers,
+			"exactly one of exec, httpGet, or tcpSocket must be set"))
+	}
+	return fieldErrors
+}
+
+var _ resource.ObjectList = &HealthProbeList{}
+
+func (in *HealthProbeList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// HealthProbeResult is the outcome of the most recent probe.
+type HealthProbeResult string
+
+const (
+	HealthProbeResultUnknown HealthProbeResult = "Unknown"
+	HealthProbeResultSuccess HealthProbeResult = "Success"
+	HealthProbeResultWarning HealthProbeResult = "Warning"
+	HealthProbeResultFailure HealthProbeResult = "Failure"
+)
+
+// HealthProbeStatus defines the observed state of HealthProbe
+type HealthProbeStatus struct {
+	// Whether the resource passes the probe, after applying the
+	// success and failure thresholds.
+	//
+	// +optional
+	Ready bool `json:"ready,omitempty" protobuf:"varint,1,opt,name=ready"`
+
+	// The result of the most recent probe, before applying the
+	// thresholds.
+	//
+	// +optional
+	Result HealthProbeResult `json:"result,omitempty" protobuf:"bytes,2,opt,name=result,casttype=HealthProbeResult"`
+
+	// The output of the probe when its result last changed, or why it
+	// failed.
+	//
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+
+	// When the probe last went from not ready to ready.
+	//
+	// Zero if the resource has never passed the probe.
+	//
+	// +optional
+	LastReadyTime metav1.MicroTime `json:"lastReadyTime,omitempty" protobuf:"bytes,4,opt,name=lastReadyTime"`
+
+	// Set if the probe can't run at all, like if the spec has
+	// an invalid port.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+}
+
+// HealthProbe implements ObjectWithStatusSubResource interface.
+var _ resource.ObjectWithStatusSubResource = &HealthProbe{}
+
+func (in *HealthProbe) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+// HealthProbeStatus{} implements StatusSubResource interface.
+var _ resource.StatusSubResource = &HealthProbeStatus{}
+
+func (in HealthProbeStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*HealthProbe).Status = in
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LogSink forwards the logs of some resources to somewhere outside Tilt,
+// like a Loki server, a fluentd forward input, or a file, so that the logs
+// of many dev environments can be collected in one place.
+//
+// Lines are sent in batches. If the endpoint is slow or down, lines are
+// buffered up to a limit, then the oldest are dropped, so that a stuck
+// endpoint never slows Tilt down.
+//
+// +k8s:openapi-gen=true
+type LogSink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   LogSinkSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status LogSinkStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// LogSinkList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type LogSinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []LogSink `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// LogSinkType is a kind of place to forward logs to.
+type LogSinkType string
+
+const (
+	// Pushes lines to a Loki server's push API, as JSON.
+	LogSinkTypeLoki LogSinkType = "loki"
+
+	// Sends lines to a fluentd (or Fluent Bit) forward input over TCP.
+	LogSinkTypeFluentd LogSinkType = "fluentd"
+
+	// Appends lines to a file, as JSON, one object per line.
+	LogSinkTypeFile LogSinkType = "file"
+)
+
+// All the types of LogSink.
+var AllLogSinkTypes = []LogSinkType{
+	LogSinkTypeLoki,
+	LogSinkTypeFluentd,
+	LogSinkTypeFile,
+}
+
+// The log levels a LogSink can filter on, least severe first.
+var LogSinkLevels = []string{"debug", "verbose", "info", "warn", "error"}
+
+const (
+	LogSinkDefaultBatchSize  = 100
+	LogSinkDefaultBufferSize = 10000
+)
+
+var LogSinkDefaultFlushInterval = metav1.Duration{Duration: time.Second}
+
+// LogSinkSpec defines where to forward logs, and which logs to forward.
+type LogSinkSpec struct {
+	// Where to forward logs: one of loki, fluentd, or file.
+	Type LogSinkType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=LogSinkType"`
+
+	// Where the sink is.
+	//
+	// For loki, the base URL of the Loki server, like http://localhost:3100.
+	//
+	// For fluentd, the host:port of a forward input, like localhost:24224.
+	//
+	// For file, the absolute path of the file to append to.
+	Endpoint string `json:"endpoint" protobuf:"bytes,2,opt,name=endpoint"`
+
+	// The names of the resources to forward logs from.
+	//
+	// If empty, forwards the logs of all resources, and the logs
+	// that don't belong to any resource.
+	//
+	// +optional
+	Resources []string `json:"resources,omitempty" protobuf:"bytes,3,rep,name=resources"`
+
+	// Only forward lines at least this severe: one of debug, verbose,
+	// info, warn, or error.
+	//
+	// If empty, forwards every line that Tilt logs.
+	//
+	// +optional
+	MinLevel string `json:"minLevel,omitempty" protobuf:"bytes,4,opt,name=minLevel"`
+
+	// Labels to add to every line, like the name of the developer or
+	// the environment.
+	//
+	// For loki, these are stream labels. For fluentd and file, these are
+	// fields of each record.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty" protobuf:"bytes,5,rep,name=labels"`
+
+	// The most lines to send at once. Defaults to 100.
+	//
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty" protobuf:"varint,6,opt,name=batchSize"`
+
+	// How long to wait for a batch to fill before sending it anyway.
+	// Defaults to 1s.
+	//
+	// +optional
+	FlushInterval *metav1.Duration `json:"flushInterval,omitempty" protobuf:"bytes,7,opt,name=flushInterval"`
+
+	// The most lines to hold while the endpoint is slow or down.
+	// When the buffer is full, the oldest lines are dropped.
+	// Defaults to 10000.
+	//
+	// +optional
+	BufferSize int32 `json:"bufferSize,omitempty" protobuf:"varint,8,opt,name=bufferSize"`
+}
+
+var _ resource.Object = &LogSink{}
+var _ resourcestrategy.Validater = &LogSink{}
+
+func (in *LogSink) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *LogSink) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *LogSink) NamespaceScoped() bool {
+	return false
+}
+
+func (in *LogSink) New() runtime.Object {
+	return &LogSink{}
+}
+
+func (in *LogSink) NewList() runtime.Object {
+	return &LogSinkList{}
+}
+
+func (in *LogSink) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "logsinks",
+	}
+}
+
+func (in *LogSink) IsStorageVersion() bool {
+	return true
+}
+
+func (in *LogSink) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+	endpoint := in.Spec.Endpoint
+	endpointPath := field.NewPath("spec.endpoint")
+	switch in.Spec.Type {
+	case LogSinkTypeLoki:
+		if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+			fieldErrors = append(fieldErrors, field.Invalid(endpointPath, endpoint,
+				"Loki URLs must start with http(s)://"))
+		}
+	case LogSinkTypeFluentd:
+		_, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(endpointPath, endpoint,
+				"fluentd endpoints must be host:port"))
+		}
+	case LogSinkTypeFile:
+		if !filepath.IsAbs(endpoint) {
+			fieldErrors = append(fieldErrors, field.Invalid(endpointPath, endpoint,
+				"file endpoints must be absolute paths"))
+		}
+	default:
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec.type"),
+			in.Spec.Type,
+			logSinkTypeStrings()))
+	}
+
+	if in.Spec.MinLevel != "" && !isLogSinkLevel(in.Spec.MinLevel) {
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec.minLevel"),
+			in.Spec.MinLevel,
+			LogSinkLevels))
+	}
+	if in.Spec.BatchSize < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec.batchSize"), in.Spec.BatchSize, "must not be negative"))
+	}
+	if in.Spec.BufferSize < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec.bufferSize"), in.Spec.BufferSize, "must not be negative"))
+	}
+	if in.Spec.FlushInterval != nil && in.Spec.FlushInterval.Duration <= 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec.flushInterval"),
+			in.Spec.FlushInterval.Duration.String(),
+			"must be positive"))
+	}
+	return fieldErrors
+}
+
+func logSinkTypeStrings() []string {
+	var result []string
+	for _, t := range AllLogSinkTypes {
+		result = append(result, string(t))
+	}
+	return result
+}
+
+func isLogSinkLevel(level string) bool {
+	for _, known := range LogSinkLevels {
+		if level == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the LogSink forwards the logs of the given resource.
+// Logs that don't belong to any resource have an empty name.
+func (in *LogSink) WantsResource(name string) bool {
+	if len(in.Spec.Resources) == 0 {
+		return true
+	}
+	for _, want := range in.Spec.Resources {
+		if want == name {
+			return true
+		}
+	}
+	return false
+}
+
+// The most lines to send at once, with the default applied.
+func (in *LogSink) BatchSize() int {
+	if in.Spec.BatchSize == 0 {
+		return LogSinkDefaultBatchSize
+	}
+	return int(in.Spec.BatchSize)
+}
+
+// The most lines to buffer, with the default applied.
+func (in *LogSink) BufferSize() int {
+	if in.Spec.BufferSize == 0 {
+		return LogSinkDefaultBufferSize
+	}
+	return int(in.Spec.BufferSize)
+}
+
+// How long to wait for a batch to fill, with the default applied.
+func (in *LogSink) FlushInterval() metav1.Duration {
+	if in.Spec.FlushInterval == nil {
+		return LogSinkDefaultFlushInterval
+	}
+	return *in.Spec.FlushInterval
+}
+
+var _ resource.ObjectList = &LogSinkList{}
+
+func (in *LogSinkList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// LogSinkStatus defines the observed state of LogSink
+type LogSinkStatus struct {
+	// The last time Tilt sent a batch of lines to the endpoint.
+	// +optional
+	LastSentTime metav1.MicroTime `json:"lastSentTime,omitempty" protobuf:"bytes,1,opt,name=lastSentTime"`
+
+	// The number of lines Tilt has sent to the endpoint.
+	// +optional
+	SentLines int64 `json:"sentLines,omitempty" protobuf:"varint,2,opt,name=sentLines"`
+
+	// The number of lines Tilt dropped because the buffer was full.
+	// +optional
+	DroppedLines int64 `json:"droppedLines,omitempty" protobuf:"varint,3,opt,name=droppedLines"`
+
+	// The number of lines waiting to be sent.
+	// +optional
+	BufferedLines int32 `json:"bufferedLines,omitempty" protobuf:"varint,4,opt,name=bufferedLines"`
+
+	// If the last batch failed to send, why.
+	//
+	// Tilt keeps the lines, and retries with a backoff.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+}
+
+// LogSink implements ObjectWithStatusSubResource interface.
+var _ resource.ObjectWithStatusSubResource = &LogSink{}
+
+func (in *LogSink) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+// LogSinkStatus{} implements StatusSubResource interface.
+var _ resource.StatusSubResource = &LogSinkStatus{}
+
+func (in LogSinkStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*LogSink).Status = in
+}
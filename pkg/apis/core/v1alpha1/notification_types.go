@@ -0,0 +1,331 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Notification posts a message to a webhook, or shows a desktop notification,
+// when a resource changes state, like when a build fails or a container
+// starts crash-looping.
+//
+// The webhook message is a JSON object with a "text" field, which Slack
+// incoming webhooks (and many other chat tools) accept.
+//
+// +k8s:openapi-gen=true
+type Notification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   NotificationSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status NotificationStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// NotificationList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Notification `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// NotificationEvent is a kind of resource state change.
+type NotificationEvent string
+
+const (
+	// A build of the resource failed.
+	NotificationEventBuildFailed NotificationEvent = "BuildFailed"
+
+	// A container of the resource is crash-looping.
+	NotificationEventCrashLoop NotificationEvent = "CrashLoop"
+
+	// The resource became ready.
+	NotificationEventReady NotificationEvent = "Ready"
+
+	// The resource stopped being ready.
+	NotificationEventNotReady NotificationEvent = "NotReady"
+
+	// An AlertRule started firing for the resource.
+	NotificationEventAlertFiring NotificationEvent = "AlertFiring"
+
+	// An AlertRule stopped firing for the resource.
+	NotificationEventAlertResolved NotificationEvent = "AlertResolved"
+
+	// A build of the resource failed, and the build before it succeeded.
+	NotificationEventFirstFailure NotificationEvent = "FirstFailure"
+
+	// All the resources finished building, and none are waiting to build,
+	// like when a tilt ci run would finish.
+	NotificationEventRunFinished NotificationEvent = "RunFinished"
+)
+
+// All the events that a Notification can watch for.
+var AllNotificationEvents = []NotificationEvent{
+	NotificationEventBuildFailed,
+	NotificationEventCrashLoop,
+	NotificationEventReady,
+	NotificationEventNotReady,
+	NotificationEventAlertFiring,
+	NotificationEventAlertResolved,
+	NotificationEventFirstFailure,
+	NotificationEventRunFinished,
+}
+
+// The events that a Notification sends messages for if it doesn't list any.
+var DefaultNotificationEvents = []NotificationEvent{
+	NotificationEventBuildFailed,
+	NotificationEventCrashLoop,
+	NotificationEventReady,
+	NotificationEventNotReady,
+	NotificationEventAlertFiring,
+	NotificationEventAlertResolved,
+}
+
+// The default minimum time between messages about a resource.
+var NotificationDefaultMinInterval = metav1.Duration{Duration: time.Minute}
+
+// NotificationSpec defines where to send messages, and which state
+// changes to send them for.
+type NotificationSpec struct {
+	// The webhook URL to POST messages to.
+	//
+	// Required, unless Desktop is set.
+	//
+	// +optional
+	URL string `json:"url,omitempty" protobuf:"bytes,1,opt,name=url"`
+
+	// The state changes to send messages for.
+	//
+	// If empty, sends messages for every state change except FirstFailure
+	// and RunFinished.
+	//
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty" protobuf:"bytes,2,rep,name=events,casttype=NotificationEvent"`
+
+	// The names of the resources to send messages for.
+	//
+	// If empty, sends messages for all resources.
+	//
+	// +optional
+	Resources []string `json:"resources,omitempty" protobuf:"bytes,3,rep,name=resources"`
+
+	// The minimum time between messages about the same resource.
+	//
+	// State changes in between are batched into the next message, so
+	// that a flapping resource doesn't flood the channel.
+	//
+	// Defaults to 1 minute. Set to 0s to send every state change right away.
+	//
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty" protobuf:"bytes,4,opt,name=minInterval"`
+
+	// Whether to show messages as desktop notifications on the machine
+	// that Tilt runs on.
+	//
+	// Uses osascript on macOS, notify-send on Linux, and PowerShell on Windows.
+	//
+	// +optional
+	Desktop bool `json:"desktop,omitempty" protobuf:"varint,5,opt,name=desktop"`
+
+	// The names of the resources to never send messages for.
+	//
+	// Use it to mute noisy resources without listing all the others
+	// in Resources.
+	//
+	// +optional
+	MutedResources []string `json:"mutedResources,omitempty" protobuf:"bytes,6,rep,name=mutedResources"`
+}
+
+var _ resource.Object = &Notification{}
+var _ resourcestrategy.Validater = &Notification{}
+
+func (in *Notification) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *Notification) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *Notification) NamespaceScoped() bool {
+	return false
+}
+
+func (in *Notification) New() runtime.Object {
+	return &Notification{}
+}
+
+func (in *Notification) NewList() runtime.Object {
+	return &NotificationList{}
+}
+
+func (in *Notification) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "notifications",
+	}
+}
+
+func (in *Notification) IsStorageVersion() bool {
+	return true
+}
+
+func (in *Notification) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+	url := in.Spec.URL
+	if url == "" && !in.Spec.Desktop {
+		fieldErrors = append(fieldErrors, field.Required(
+			field.NewPath("spec.url"),
+			"must specify a webhook URL, or show desktop notifications"))
+	} else if url != "" && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec.url"),
+			url,
+			"URLs must start with http(s)://"))
+	}
+
+	for i, e := range in.Spec.Events {
+		if !isNotificationEvent(e) {
+			fieldErrors = append(fieldErrors, field.NotSupported(
+				field.NewPath("spec.events").Index(i),
+				e,
+				notificationEventStrings()))
+		}
+	}
+
+	if in.Spec.MinInterval != nil && in.Spec.MinInterval.Duration < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec.minInterval"),
+			in.Spec.MinInterval.Duration.String(),
+			"must not be negative"))
+	}
+	return fieldErrors
+}
+
+func isNotificationEvent(e NotificationEvent) bool {
+	for _, known := range AllNotificationEvents {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+func notificationEventStrings() []string {
+	var result []string
+	for _, e := range AllNotificationEvents {
+		result = append(result, string(e))
+	}
+	return result
+}
+
+// Whether the Notification sends messages for the given state change.
+func (in *Notification) WantsEvent(e NotificationEvent) bool {
+	wanted := in.Spec.Events
+	if len(wanted) == 0 {
+		wanted = DefaultNotificationEvents
+	}
+	for _, want := range wanted {
+		if want == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the Notification sends messages about the given resource.
+func (in *Notification) WantsResource(name string) bool {
+	for _, muted := range in.Spec.MutedResources {
+		if muted == name {
+			return false
+		}
+	}
+	if len(in.Spec.Resources) == 0 {
+		return true
+	}
+	for _, want := range in.Spec.Resources {
+		if want == name {
+			return true
+		}
+	}
+	return false
+}
+
+// The minimum time between messages about the same resource, with the
+// default applied.
+func (in *Notification) MinInterval() metav1.Duration {
+	if in.Spec.MinInterval == nil {
+		return NotificationDefaultMinInterval
+	}
+	return *in.Spec.MinInterval
+}
+
+var _ resource.ObjectList = &NotificationList{}
+
+func (in *NotificationList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// NotificationStatus defines the observed state of Notification
+type NotificationStatus struct {
+	// The last time Tilt sent a message.
+	// +optional
+	LastSentTime metav1.MicroTime `json:"lastSentTime,omitempty" protobuf:"bytes,1,opt,name=lastSentTime"`
+
+	// The number of messages Tilt has sent.
+	// +optional
+	SentCount int32 `json:"sentCount,omitempty" protobuf:"varint,2,opt,name=sentCount"`
+
+	// If the last message failed to send, why.
+	//
+	// Tilt retries messages that failed to post to the webhook after
+	// the minimum interval.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,3,opt,name=error"`
+}
+
+// Notification implements ObjectWithStatusSubResource interface.
+var _ resource.ObjectWithStatusSubResource = &Notification{}
+
+func (in *Notification) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+// NotificationStatus{} implements StatusSubResource interface.
+var _ resource.StatusSubResource = &NotificationStatus{}
+
+func (in NotificationStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*Notification).Status = in
+}
@@ -52,6 +52,13 @@ type PortForwardList struct {
 	Items []PortForward `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
+// The label on a PortForward that follows the pod of a KubernetesDiscovery,
+// with the name of the KubernetesDiscovery.
+//
+// Users can add these PortForwards at runtime. When the KubernetesDiscovery
+// picks a new pod, Tilt moves the PortForward to the new pod.
+const LabelPortForwardDiscovery = "tilt.dev/discovery"
+
 // PortForwardSpec defines the desired state of PortForward
 type PortForwardSpec struct {
 	// The name of the pod to port forward to/from. Required.
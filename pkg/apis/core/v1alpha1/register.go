@@ -74,6 +74,14 @@ func AllResourceObjects() []resource.Object {
 		&Cluster{},
 		&DockerComposeService{},
 		&DockerComposeLogStream{},
+		&BuildHistory{},
+		&Notification{},
+		&ResourceEvent{},
+		&UIPanel{},
+		&UIPreferences{},
+		&AlertRule{},
+		&HealthProbe{},
+		&LogSink{},
 
 		// Hey! You! If you're adding a new top-level type, add the type object here.
 	}
@@ -102,6 +110,14 @@ func AllResourceLists() []runtime.Object {
 		&ClusterList{},
 		&DockerComposeServiceList{},
 		&DockerComposeLogStreamList{},
+		&BuildHistoryList{},
+		&NotificationList{},
+		&ResourceEventList{},
+		&UIPanelList{},
+		&UIPreferencesList{},
+		&AlertRuleList{},
+		&HealthProbeList{},
+		&LogSinkList{},
 
 		// Hey! You! If you're adding a new top-level type, add the List type here.
 	}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ResourceEvent records one state change of a resource, like a build
+// starting or a pod restarting.
+//
+// Tilt creates a ResourceEvent for each state change and never updates it,
+// so bots, dashboards, and test harnesses can watch ResourceEvents to react
+// to Tilt without polling or parsing logs.
+//
+// Each event has the label tilt.dev/resource with the name of its resource,
+// so that watchers can select the events of a single resource. (Except for
+// resources like (Tiltfile) whose names aren't valid label values.)
+//
+// Tilt only keeps the most recent events.
+//
+// +k8s:openapi-gen=true
+type ResourceEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec ResourceEventSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// ResourceEventList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ResourceEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []ResourceEvent `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// The label on each ResourceEvent with the name of its resource.
+const LabelResourceEventResource = "tilt.dev/resource"
+
+// ResourceEventType is a kind of resource state change.
+type ResourceEventType string
+
+const (
+	// A build of the resource started.
+	ResourceEventTypeBuildStarted ResourceEventType = "BuildStarted"
+
+	// A build of the resource finished without errors.
+	ResourceEventTypeBuildSucceeded ResourceEventType = "BuildSucceeded"
+
+	// A build of the resource failed. Error has the reason.
+	ResourceEventTypeBuildFailed ResourceEventType = "BuildFailed"
+
+	// The resource became ready.
+	ResourceEventTypeResourceReady ResourceEventType = "ResourceReady"
+
+	// The resource stopped being ready. Reason has the reason.
+	ResourceEventTypeResourceNotReady ResourceEventType = "ResourceNotReady"
+
+	// A container in the resource's pod restarted. PodName and
+	// PodRestarts have the pod and its total restart count.
+	ResourceEventTypePodRestarted ResourceEventType = "PodRestarted"
+)
+
+// All the kinds of state changes that Tilt records.
+var AllResourceEventTypes = []ResourceEventType{
+	ResourceEventTypeBuildStarted,
+	ResourceEventTypeBuildSucceeded,
+	ResourceEventTypeBuildFailed,
+	ResourceEventTypeResourceReady,
+	ResourceEventTypeResourceNotReady,
+	ResourceEventTypePodRestarted,
+}
+
+// ResourceEventSpec describes what happened.
+//
+// New event types and fields may be added, but the meaning of existing
+// ones won't change, so clients should ignore types they don't know.
+type ResourceEventSpec struct {
+	// The kind of state change.
+	Type ResourceEventType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=ResourceEventType"`
+
+	// The name of the resource that changed.
+	Resource string `json:"resource" protobuf:"bytes,2,opt,name=resource"`
+
+	// The order of the event among all the events of this Tilt session.
+	//
+	// Starts at 1 and increases by 1 with each event, so watchers can put
+	// events in order and notice when they've missed some.
+	Sequence int64 `json:"sequence" protobuf:"varint,3,opt,name=sequence"`
+
+	// When the state change happened.
+	Time metav1.MicroTime `json:"time" protobuf:"bytes,4,opt,name=time"`
+
+	// The error message, for BuildFailed events.
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+
+	// Why the resource isn't ready, for ResourceNotReady events.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,6,opt,name=reason"`
+
+	// The pod that restarted, for PodRestarted events.
+	// +optional
+	PodName string `json:"podName,omitempty" protobuf:"bytes,7,opt,name=podName"`
+
+	// The total number of restarts of the pod, for PodRestarted events.
+	// +optional
+	PodRestarts int32 `json:"podRestarts,omitempty" protobuf:"varint,8,opt,name=podRestarts"`
+}
+
+var _ resource.Object = &ResourceEvent{}
+var _ resourcestrategy.Validater = &ResourceEvent{}
+
+func (in *ResourceEvent) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *ResourceEvent) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *ResourceEvent) NamespaceScoped() bool {
+	return false
+}
+
+func (in *ResourceEvent) New() runtime.Object {
+	return &ResourceEvent{}
+}
+
+func (in *ResourceEvent) NewList() runtime.Object {
+	return &ResourceEventList{}
+}
+
+func (in *ResourceEvent) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "resourceevents",
+	}
+}
+
+func (in *ResourceEvent) IsStorageVersion() bool {
+	return true
+}
+
+func (in *ResourceEvent) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+	if in.Spec.Type == "" {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec.type"), "event type is required"))
+	}
+	if in.Spec.Resource == "" {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec.resource"), "resource name is required"))
+	}
+	return fieldErrors
+}
+
+var _ resource.ObjectList = &ResourceEventList{}
+
+func (in *ResourceEventList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
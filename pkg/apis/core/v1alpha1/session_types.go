@@ -74,8 +74,32 @@ type SessionCISpec struct {
 
 	// Timeout for the whole CI pipeline. Defaults to 30m.
 	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,2,opt,name=timeout"`
+
+	// Timeout for each resource to become ready, measured from when
+	// its first update starts.
+	//
+	// If omitted, resources only have to be ready before the global timeout.
+	ReadinessTimeout *metav1.Duration `json:"readinessTimeout,omitempty" protobuf:"bytes,3,opt,name=readinessTimeout"`
+
+	// Readiness timeouts for individual resources, by resource name.
+	// Overrides ReadinessTimeout.
+	ResourceReadinessTimeouts map[string]metav1.Duration `json:"resourceReadinessTimeouts,omitempty" protobuf:"bytes,4,rep,name=resourceReadinessTimeouts"`
+
+	// What to do when a resource fails. Defaults to FailFast.
+	FailurePolicy CIFailurePolicy `json:"failurePolicy,omitempty" protobuf:"bytes,5,opt,name=failurePolicy,casttype=CIFailurePolicy"`
 }
 
+type CIFailurePolicy string
+
+const (
+	// CIFailurePolicyFailFast exits on the first failure.
+	CIFailurePolicyFailFast CIFailurePolicy = "FailFast"
+
+	// CIFailurePolicyCollectAll waits until every resource has either
+	// become ready or failed, then exits with all the failures.
+	CIFailurePolicyCollectAll CIFailurePolicy = "CollectAll"
+)
+
 type ExitCondition string
 
 const (
@@ -154,6 +178,16 @@ func (in *Session) Validate(_ context.Context) field.ErrorList {
 			in.Spec.ExitCondition,
 			detailMsg.String()))
 	}
+	if ci := in.Spec.CI; ci != nil {
+		switch ci.FailurePolicy {
+		case "", CIFailurePolicyFailFast, CIFailurePolicyCollectAll:
+		default:
+			fieldErrors = append(fieldErrors, field.NotSupported(
+				field.NewPath("ci", "failurePolicy"),
+				ci.FailurePolicy,
+				[]string{string(CIFailurePolicyFailFast), string(CIFailurePolicyCollectAll)}))
+		}
+	}
 	return fieldErrors
 }
 
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UIPanel adds a tab to the web UI, next to a resource's logs or
+// on the overview of all resources.
+//
+// The tab either embeds another web page (like a database admin UI or
+// a feature flag toggler) or renders Markdown.
+//
+// +k8s:openapi-gen=true
+type UIPanel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec UIPanelSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// UIPanelList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type UIPanelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []UIPanel `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// UIPanelSpec defines the desired state of UIPanel
+type UIPanelSpec struct {
+	// Location associates the panel with a resource, or with the
+	// overview of all resources if the ComponentType is Global.
+	Location UIComponentLocation `json:"location" protobuf:"bytes,1,opt,name=location"`
+
+	// Title is the text on the panel's tab.
+	Title string `json:"title" protobuf:"bytes,2,opt,name=title"`
+
+	// URL of a web page to embed in the panel, in an iframe.
+	//
+	// Exactly one of URL and Markdown must be set.
+	//
+	// +optional
+	URL string `json:"url,omitempty" protobuf:"bytes,3,opt,name=url"`
+
+	// Markdown to render in the panel.
+	//
+	// Exactly one of URL and Markdown must be set.
+	//
+	// +optional
+	Markdown string `json:"markdown,omitempty" protobuf:"bytes,4,opt,name=markdown"`
+
+	// Where the tab goes, relative to other panels in the same location.
+	// Tabs with lower numbers go first. Ties are sorted by title.
+	//
+	// +optional
+	Order int32 `json:"order,omitempty" protobuf:"varint,5,opt,name=order"`
+}
+
+var _ resource.Object = &UIPanel{}
+var _ resourcestrategy.Validater = &UIPanel{}
+
+func (in *UIPanel) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *UIPanel) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *UIPanel) NamespaceScoped() bool {
+	return false
+}
+
+func (in *UIPanel) New() runtime.Object {
+	return &UIPanel{}
+}
+
+func (in *UIPanel) NewList() runtime.Object {
+	return &UIPanelList{}
+}
+
+func (in *UIPanel) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "uipanels",
+	}
+}
+
+func (in *UIPanel) IsStorageVersion() bool {
+	return true
+}
+
+func (in *UIPanel) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+
+	if in.Spec.Title == "" {
+		fieldErrors = append(fieldErrors, field.Required(
+			field.NewPath("spec.title"), "Panel title cannot be empty"))
+	}
+
+	locField := field.NewPath("spec.location")
+	if in.Spec.Location.ComponentType == "" {
+		fieldErrors = append(fieldErrors, field.Required(
+			locField.Child("componentType"), "Parent component type is required"))
+	} else if in.Spec.Location.ComponentType != ComponentTypeResource &&
+		in.Spec.Location.ComponentType != ComponentTypeGlobal {
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			locField.Child("componentType"), in.Spec.Location.ComponentType,
+			[]string{string(ComponentTypeResource), string(ComponentTypeGlobal)}))
+	}
+	if in.Spec.Location.ComponentType == ComponentTypeResource && in.Spec.Location.ComponentID == "" {
+		fieldErrors = append(fieldErrors, field.Required(
+			locField.Child("componentID"), "Parent component ID is required"))
+	}
+
+	url := in.Spec.URL
+	if url != "" && in.Spec.Markdown != "" {
+		fieldErrors = append(fieldErrors, field.Forbidden(
+			field.NewPath("spec.markdown"), "Only one of URL and Markdown may be set"))
+	} else if url == "" && in.Spec.Markdown == "" {
+		fieldErrors = append(fieldErrors, field.Required(
+			field.NewPath("spec.url"), "One of URL and Markdown is required"))
+	}
+	if url != "" && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec.url"), url, "URLs must start with http(s)://"))
+	}
+
+	return fieldErrors
+}
+
+var _ resource.ObjectList = &UIPanelList{}
+
+func (in *UIPanelList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UIPreferences holds one user's web UI settings, like how resources are
+// grouped, which resources are pinned, and saved log filters.
+//
+// Keeping them here instead of in the browser means they follow the user
+// to other browsers and survive clearing the browser cache.
+//
+// +k8s:openapi-gen=true
+type UIPreferences struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec UIPreferencesSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// UIPreferencesList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type UIPreferencesList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []UIPreferences `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// UIPreferencesSpec defines the desired state of UIPreferences
+type UIPreferencesSpec struct {
+	// The user these preferences belong to, e.g., their email when they
+	// signed in with single sign-on.
+	User string `json:"user" protobuf:"bytes,1,opt,name=user"`
+
+	// Preferences by name. Each value is JSON, in whatever format
+	// the web UI uses for that preference.
+	//
+	// +optional
+	Values map[string]string `json:"values,omitempty" protobuf:"bytes,2,rep,name=values"`
+}
+
+var _ resource.Object = &UIPreferences{}
+var _ resourcestrategy.Validater = &UIPreferences{}
+
+func (in *UIPreferences) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *UIPreferences) GetSpec() interface{} {
+	return in.Spec
+}
+
+func (in *UIPreferences) NamespaceScoped() bool {
+	return false
+}
+
+func (in *UIPreferences) New() runtime.Object {
+	return &UIPreferences{}
+}
+
+func (in *UIPreferences) NewList() runtime.Object {
+	return &UIPreferencesList{}
+}
+
+func (in *UIPreferences) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "uipreferences",
+	}
+}
+
+func (in *UIPreferences) IsStorageVersion() bool {
+	return true
+}
+
+func (in *UIPreferences) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+
+	if in.Spec.User == "" {
+		fieldErrors = append(fieldErrors, field.Required(
+			field.NewPath("spec.user"), "User cannot be empty"))
+	}
+
+	// Sort the names so the errors come out in a stable order.
+	names := make([]string, 0, len(in.Spec.Values))
+	for name := range in.Spec.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	valuesField := field.NewPath("spec.values")
+	for _, name := range names {
+		if name == "" {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				valuesField, name, "Preference names cannot be empty"))
+			continue
+		}
+		value := in.Spec.Values[name]
+		if !json.Valid([]byte(value)) {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				valuesField.Key(name), value, "Preference values must be JSON"))
+		}
+	}
+
+	return fieldErrors
+}
+
+var _ resource.ObjectList = &UIPreferencesList{}
+
+func (in *UIPreferencesList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
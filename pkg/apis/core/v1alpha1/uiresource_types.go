@@ -209,6 +209,15 @@ type UIResourceStatus struct {
 	//
 	// +optional
 	Conditions []UIResourceCondition `json:"conditions,omitempty" protobuf:"bytes,18,rep,name=conditions"`
+
+	// Groups lists the resource groups this resource belongs to, sorted.
+	//
+	// Each group is a path of labels separated by '/', from the outermost
+	// group to the innermost (e.g., "payments/api"). Only the outermost
+	// group of each path appears in the object's labels.
+	//
+	// +optional
+	Groups []string `json:"groups,omitempty" protobuf:"bytes,19,rep,name=groups"`
 }
 
 // UIResource implements ObjectWithStatusSubResource interface.
@@ -369,6 +378,10 @@ type UIResourceLocal struct {
 	//
 	// +optional
 	IsTest bool `json:"isTest,omitempty" protobuf:"varint,2,opt,name=isTest"`
+
+	// Resources used by the actively running local command.
+	// +optional
+	Usage *CmdResourceUsage `json:"usage,omitempty" protobuf:"bytes,3,opt,name=usage"`
 }
 
 type UIResourceStateWaiting struct {
@@ -182,6 +182,15 @@ type UISessionStatus struct {
 	// project in LocalStorage or other persistent storage.
 	// +optional
 	TiltfileKey string `json:"tiltfileKey,omitempty" protobuf:"bytes,11,opt,name=tiltfileKey"`
+
+	// ResourceGroups lists every resource group, including parent groups
+	// that have no resources of their own, in the order the UI should
+	// display them.
+	//
+	// Each group comes right before its subgroups.
+	//
+	// +optional
+	ResourceGroups []UIResourceGroup `json:"resourceGroups,omitempty" protobuf:"bytes,13,rep,name=resourceGroups"`
 }
 
 // UISession implements ObjectWithStatusSubResource interface.
@@ -217,6 +226,16 @@ type UIFeatureFlag struct {
 	Value bool `json:"value,omitempty" protobuf:"varint,2,opt,name=value"`
 }
 
+// How the UI displays a group of resources.
+type UIResourceGroup struct {
+	// The path of the group, as labels separated by '/' (e.g., "payments/api").
+	Path string `json:"path" protobuf:"bytes,1,opt,name=path"`
+
+	// Whether the user collapsed the group in the resource list.
+	// +optional
+	Collapsed bool `json:"collapsed,omitempty" protobuf:"varint,2,opt,name=collapsed"`
+}
+
 // Information about the running tilt binary.
 type TiltBuild struct {
 	// A semantic version string.
@@ -14,3 +14,18 @@ const FieldNameBuildEvent = "buildEvent"
 // progressMustPrint="1" indicates that this line must appear in the
 // output - e.g., a line that communicates that the upload finished.
 const FieldNameProgressMustPrint = "progressMustPrint"
+
+// Marks a line that says how much of a resource's log Tilt truncated.
+//
+// The value says whether the truncated logs were saved to disk
+// (and can be loaded later) or dropped.
+const FieldNameTruncated = "truncated"
+
+const (
+	TruncatedSpilled = "spilled"
+	TruncatedDropped = "dropped"
+)
+
+// ansiHTML="1" marks a line whose ANSI codes Tilt has already turned into
+// HTML, so that it's rendered as HTML rather than escaped.
+const FieldNameANSIHTML = "ansiHTML"
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+var levelNames = []struct {
+	name  string
+	level Level
+}{
+	{"debug", DebugLvl},
+	{"verbose", VerboseLvl},
+	{"info", InfoLvl},
+	{"warn", WarnLvl},
+	{"error", ErrorLvl},
+}
+
+// Returns the level with the given name, like "warn".
+func ParseLevel(name string) (Level, error) {
+	var names []string
+	for _, l := range levelNames {
+		if strings.EqualFold(l.name, name) {
+			return l.level, nil
+		}
+		names = append(names, l.name)
+	}
+	return NoneLvl, fmt.Errorf("invalid level %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+// Returns the name of the level, like "warn".
+func (l Level) Name() string {
+	for _, ln := range levelNames {
+		if ln.level == l {
+			return ln.name
+		}
+	}
+	return ""
+}
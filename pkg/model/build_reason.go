@@ -38,6 +38,12 @@ const (
 	// Building manifestA will mark imageB
 	// with changed dependencies.
 	BuildReasonFlagChangedDeps
+
+	// The Tiltfile asked for a rerun with rerun_after().
+	BuildReasonFlagTriggerTimer
+
+	// A script or editor plugin called the external HTTP API.
+	BuildReasonFlagTriggerAPI
 )
 
 func (r BuildReason) With(flag BuildReason) BuildReason {
@@ -78,6 +84,8 @@ var translations = map[BuildReason]string{
 	BuildReasonFlagTriggerUnknown:  "Unknown Trigger",
 	BuildReasonFlagTiltfileArgs:    "Tilt Args",
 	BuildReasonFlagChangedDeps:     "Dependency Updated",
+	BuildReasonFlagTriggerTimer:    "Scheduled Rerun",
+	BuildReasonFlagTriggerAPI:      "API Trigger",
 }
 
 var triggerBuildReasons = []BuildReason{
@@ -85,6 +93,8 @@ var triggerBuildReasons = []BuildReason{
 	BuildReasonFlagTriggerCLI,
 	BuildReasonFlagTriggerHUD,
 	BuildReasonFlagTriggerUnknown,
+	BuildReasonFlagTriggerTimer,
+	BuildReasonFlagTriggerAPI,
 }
 
 var allBuildReasons = []BuildReason{
@@ -98,6 +108,8 @@ var allBuildReasons = []BuildReason{
 	BuildReasonFlagChangedDeps,
 	BuildReasonFlagTriggerUnknown,
 	BuildReasonFlagTiltfileArgs,
+	BuildReasonFlagTriggerTimer,
+	BuildReasonFlagTriggerAPI,
 }
 
 func (r BuildReason) String() string {
@@ -6,3 +6,15 @@ import "time"
 type CITimeoutFlag time.Duration
 
 const CITimeoutDefault = 30 * time.Minute
+
+// Inject the flag-specified CI readiness and failure settings.
+type CISettingsFlags struct {
+	// How long each resource has to become ready. Zero means no limit.
+	ReadinessTimeout time.Duration
+
+	// Readiness timeouts for individual resources, by resource name.
+	ResourceTimeouts map[string]time.Duration
+
+	// Keep going after a resource fails, to report every failure at once.
+	CollectAllFailures bool
+}
@@ -4,12 +4,44 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
 type Cmd struct {
 	Argv []string
 	Dir  string
 	Env  []string
+
+	// How long to wait for the process to exit after asking it to stop,
+	// before killing it. Nil means the execer's default.
+	GracePeriod *time.Duration
+
+	// The signal that asks the process to stop, like "SIGINT". Empty means
+	// SIGTERM.
+	TerminationSignal string
+
+	// Run the process in a pseudo-terminal, so that it prints output like it
+	// would in a real terminal.
+	PTY bool
+
+	// How long the process may run before it's stopped. Zero means no
+	// timeout.
+	Timeout time.Duration
+
+	// Keep the process's stdin open, so that users can send it input.
+	Stdin bool
+
+	// Files of KEY=VALUE lines to add to Env, read just before the process
+	// starts. Relative paths are relative to Dir.
+	EnvFiles []string
+
+	// Expand $VAR and ${VAR} in Argv and Env before the process starts.
+	ExpandEnv bool
+
+	// Where to run the process. Nil means this machine.
+	Target *v1alpha1.CmdTarget
 }
 
 func (c Cmd) IsShellStandardForm() bool {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -17,6 +18,19 @@ type DockerComposeTarget struct {
 	publishedPorts []int
 
 	Links []Link
+
+	// How `tilt down` takes down the service's project.
+	Down DockerComposeDownSettings
+}
+
+// Settings from docker_compose() for taking down a project. The flags of
+// `tilt down` can turn on more.
+type DockerComposeDownSettings struct {
+	DeleteVolumes bool
+	RemoveOrphans bool
+
+	// If nil, uses Docker Compose's default.
+	Timeout *time.Duration
 }
 
 // TODO(nick): This is a temporary hack until we figure out how we want
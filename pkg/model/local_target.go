@@ -3,6 +3,8 @@ package model
 import (
 	"fmt"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -24,8 +26,15 @@ type LocalTarget struct {
 
 	ReadinessProbe *v1alpha1.Probe
 
+	// A line of the serve_cmd's output must match this before it's ready.
+	ServeReadyRegex string
+
 	// Move this to CmdServerSpec when we move CmdServer to API
 	ServeCmdDisableSource *v1alpha1.DisableSource
+
+	// Whether to restart the serve_cmd when it exits, and how many times.
+	ServeRestartPolicy v1alpha1.CmdRestartPolicy
+	ServeMaxRestarts   int32
 }
 
 var _ TargetSpec = LocalTarget{}
@@ -34,9 +43,14 @@ func NewLocalTarget(name TargetName, updateCmd Cmd, serveCmd Cmd, deps []string)
 	var updateCmdSpec *v1alpha1.CmdSpec
 	if !updateCmd.Empty() {
 		updateCmdSpec = &v1alpha1.CmdSpec{
-			Args: updateCmd.Argv,
-			Dir:  updateCmd.Dir,
-			Env:  updateCmd.Env,
+			Args:      updateCmd.Argv,
+			Dir:       updateCmd.Dir,
+			Env:       updateCmd.Env,
+			EnvFiles:  updateCmd.EnvFiles,
+			ExpandEnv: updateCmd.ExpandEnv,
+		}
+		if updateCmd.Timeout > 0 {
+			updateCmdSpec.Timeout = &metav1.Duration{Duration: updateCmd.Timeout}
 		}
 	}
 
@@ -83,6 +97,12 @@ func (lt LocalTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) LocalTarget
 	return lt
 }
 
+func (lt LocalTarget) WithServeRestartPolicy(policy v1alpha1.CmdRestartPolicy, maxRestarts int32) LocalTarget {
+	lt.ServeRestartPolicy = policy
+	lt.ServeMaxRestarts = maxRestarts
+	return lt
+}
+
 func (lt LocalTarget) ID() TargetID {
 	return TargetID{
 		Name: lt.Name,
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// What to do with the ANSI escape codes (like colors) in a resource's logs.
+//
+// The codes are handled when the logs are stored, so the web UI, the CLI,
+// snapshots, and log sinks all see the same text.
+type LogANSIMode string
+
+const (
+	// Keep the codes as they are. The default.
+	LogANSIPreserve LogANSIMode = "preserve"
+
+	// Remove the codes, leaving plain text.
+	LogANSIStrip LogANSIMode = "strip"
+
+	// Escape the text as HTML, and turn colors and text styles into
+	// <span> elements with classes, like <span class="ansi-red-fg">.
+	// Other codes are removed.
+	LogANSIHTML LogANSIMode = "html"
+)
+
+var LogANSIModes = []LogANSIMode{LogANSIPreserve, LogANSIStrip, LogANSIHTML}
+
+func ParseLogANSIMode(s string) (LogANSIMode, error) {
+	names := make([]string, 0, len(LogANSIModes))
+	for _, m := range LogANSIModes {
+		if string(m) == s {
+			return m, nil
+		}
+		names = append(names, string(m))
+	}
+	return "", fmt.Errorf("invalid ANSI mode %q: must be one of %s", s, strings.Join(names, ", "))
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Where a resource's logs come from, for filtering them by level.
+type LogSource string
+
+const (
+	// Logs from building and deploying the resource.
+	LogSourceBuild LogSource = "build"
+
+	// Logs from the running resource: its pods, serve_cmd, or
+	// Docker Compose service.
+	LogSourceRuntime LogSource = "runtime"
+
+	// Everything else Tilt logs about the resource, like
+	// Kubernetes events and health checks.
+	LogSourceSystem LogSource = "system"
+)
+
+var LogSources = []LogSource{LogSourceBuild, LogSourceRuntime, LogSourceSystem}
+
+// The minimum level of logs to keep from each source of a resource.
+//
+// All logs from a source with no level are kept.
+type LogLevels map[LogSource]logger.Level
+
+func (l LogLevels) ShouldKeep(source LogSource, level logger.Level) bool {
+	min, ok := l[source]
+	return !ok || min.ShouldDisplay(level)
+}
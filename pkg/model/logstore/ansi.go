@@ -0,0 +1,329 @@
+package logstore
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const esc = 0x1b
+
+// A write that ends partway through an escape sequence carries the start
+// of the sequence over to the span's next write, up to this many bytes.
+// Longer sequences are dropped.
+const maxPendingEscapeLen = 256
+
+var ansiColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Where a span is in its ANSI codes.
+type ansiState struct {
+	// The start of an escape sequence that the last write ended in.
+	pending []byte
+
+	// The text style set by the codes so far, in the html mode.
+	style ansiStyle
+}
+
+type ansiStyle struct {
+	fg, bg                       string
+	bold, dim, italic, underline bool
+}
+
+// The classes of a <span> with the style, like "ansi-red-fg ansi-bold".
+// Empty if the style is the default.
+func (st ansiStyle) classes() string {
+	var classes []string
+	if st.fg != "" {
+		classes = append(classes, fmt.Sprintf("ansi-%s-fg", st.fg))
+	}
+	if st.bg != "" {
+		classes = append(classes, fmt.Sprintf("ansi-%s-bg", st.bg))
+	}
+	for _, c := range []struct {
+		on   bool
+		name string
+	}{
+		{st.bold, "ansi-bold"},
+		{st.dim, "ansi-dim"},
+		{st.italic, "ansi-italic"},
+		{st.underline, "ansi-underline"},
+	} {
+		if c.on {
+			classes = append(classes, c.name)
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+// Updates the style with the parameters of an SGR ("Select Graphic
+// Rendition") code, like ESC[1;31m.
+//
+// Colors outside the 16 basic ones can't be named by a class, so they're
+// shown in the default color.
+func (st *ansiStyle) apply(params []int) {
+	if len(params) == 0 {
+		*st = ansiStyle{}
+		return
+	}
+
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			*st = ansiStyle{}
+		case p == 1:
+			st.bold = true
+		case p == 2:
+			st.dim = true
+		case p == 3:
+			st.italic = true
+		case p == 4:
+			st.underline = true
+		case p == 22:
+			st.bold, st.dim = false, false
+		case p == 23:
+			st.italic = false
+		case p == 24:
+			st.underline = false
+		case p >= 30 && p <= 37:
+			st.fg = ansiColorNames[p-30]
+		case p == 39:
+			st.fg = ""
+		case p >= 40 && p <= 47:
+			st.bg = ansiColorNames[p-40]
+		case p == 49:
+			st.bg = ""
+		case p >= 90 && p <= 97:
+			st.fg = "bright-" + ansiColorNames[p-90]
+		case p >= 100 && p <= 107:
+			st.bg = "bright-" + ansiColorNames[p-100]
+		case p == 38 || p == 48:
+			color, n := extendedColor(params[i+1:])
+			if p == 38 {
+				st.fg = color
+			} else {
+				st.bg = color
+			}
+			i += n
+		}
+	}
+}
+
+// Reads the color of an extended color code (the part after the 38 or 48),
+// and returns its name and how many parameters it took.
+func extendedColor(params []int) (string, int) {
+	if len(params) >= 2 && params[0] == 5 {
+		n := params[1]
+		switch {
+		case n >= 0 && n < 8:
+			return ansiColorNames[n], 2
+		case n >= 8 && n < 16:
+			return "bright-" + ansiColorNames[n-8], 2
+		}
+		return "", 2
+	}
+	if len(params) >= 4 && params[0] == 2 {
+		return "", 4
+	}
+	return "", len(params)
+}
+
+// Sets what to do with the ANSI codes in each resource's logs.
+// Resources that aren't in the map keep their codes.
+func (s *LogStore) SetANSIModes(modes map[model.ManifestName]model.LogANSIMode) {
+	s.ansiModes = modes
+}
+
+// Removes the ANSI codes from the message, or turns them into HTML,
+// depending on the mode of the span's resource.
+func (s *LogStore) convertANSI(spanID SpanID, span *Span, msg []byte) []byte {
+	mode := s.ansiModes[span.ManifestName]
+	if mode != model.LogANSIStrip && mode != model.LogANSIHTML {
+		delete(s.ansi, spanID)
+		return msg
+	}
+
+	st, ok := s.ansi[spanID]
+	if !ok && mode == model.LogANSIStrip && bytes.IndexByte(msg, esc) == -1 {
+		return msg
+	}
+	if !ok {
+		if s.ansi == nil {
+			s.ansi = make(map[SpanID]*ansiState)
+		}
+		st = &ansiState{}
+		s.ansi[spanID] = st
+	}
+	if len(st.pending) > 0 {
+		msg = append(st.pending, msg...)
+		st.pending = nil
+	}
+
+	html := mode == model.LogANSIHTML
+	var out bytes.Buffer
+	inSpan := false
+	closeSpan := func() {
+		if inSpan {
+			out.WriteString("</span>")
+			inSpan = false
+		}
+	}
+
+	for i := 0; i < len(msg); {
+		c := msg[i]
+		if c == esc {
+			n, complete := escapeLen(msg[i:])
+			if !complete {
+				if len(msg)-i <= maxPendingEscapeLen {
+					st.pending = append([]byte{}, msg[i:]...)
+				}
+				break
+			}
+			seq := msg[i : i+n]
+			if html && n >= 3 && seq[1] == '[' && seq[n-1] == 'm' {
+				params, ok := sgrParams(seq[2 : n-1])
+				if ok {
+					closeSpan()
+					st.style.apply(params)
+				}
+			}
+			i += n
+			continue
+		}
+		i++
+
+		if !html {
+			out.WriteByte(c)
+			continue
+		}
+
+		// Each line, and each write, gets its own <span>s, so that
+		// lines can be rendered on their own.
+		if c == '\n' {
+			closeSpan()
+			out.WriteByte(c)
+			continue
+		}
+		if !inSpan {
+			if classes := st.style.classes(); classes != "" {
+				fmt.Fprintf(&out, `<span class="%s">`, classes)
+				inSpan = true
+			}
+		}
+		switch c {
+		case '&':
+			out.WriteString("&amp;")
+		case '<':
+			out.WriteString("&lt;")
+		case '>':
+			out.WriteString("&gt;")
+		case '"':
+			out.WriteString("&quot;")
+		case '\'':
+			out.WriteString("&#39;")
+		default:
+			out.WriteByte(c)
+		}
+	}
+	closeSpan()
+
+	if st.pending == nil && st.style == (ansiStyle{}) {
+		delete(s.ansi, spanID)
+	}
+	return out.Bytes()
+}
+
+// Marks the fields of lines that were turned into HTML.
+func (s *LogStore) ansiFields(span *Span, fields logger.Fields) logger.Fields {
+	if s.ansiModes[span.ManifestName] != model.LogANSIHTML {
+		return fields
+	}
+	result := make(logger.Fields, len(fields)+1)
+	for k, v := range fields {
+		result[k] = v
+	}
+	result[logger.FieldNameANSIHTML] = "1"
+	return result
+}
+
+// The length of the escape sequence at the start of b, and whether
+// b holds all of it.
+//
+// A sequence that's cut short by a byte that can't be part of it ends
+// before that byte, so that a stray escape can't swallow the text after it.
+func escapeLen(b []byte) (int, bool) {
+	if len(b) < 2 {
+		return 0, false
+	}
+
+	switch b[1] {
+	case '[':
+		// Control Sequence: parameters and intermediates, then a final byte.
+		for i := 2; i < len(b); i++ {
+			c := b[i]
+			if c >= 0x40 && c <= 0x7e {
+				return i + 1, true
+			}
+			if c < 0x20 || c > 0x3f {
+				return i, true
+			}
+		}
+		return 0, false
+	case ']':
+		// Operating System Command, like a window title or a hyperlink:
+		// ends with BEL or ESC \.
+		for i := 2; i < len(b); i++ {
+			switch b[i] {
+			case 0x07:
+				return i + 1, true
+			case '\n':
+				return i, true
+			case esc:
+				if i+1 == len(b) {
+					return 0, false
+				}
+				if b[i+1] == '\\' {
+					return i + 2, true
+				}
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	// Sequences like ESC ( B take an intermediate byte and a final byte.
+	if b[1] >= 0x20 && b[1] <= 0x2f {
+		if len(b) < 3 {
+			return 0, false
+		}
+		return 3, true
+	}
+	return 2, true
+}
+
+// The parameters of an SGR code, like 1;31 in ESC[1;31m. Empty
+// parameters count as 0. Returns false for private codes, like ESC[?25m,
+// which don't change the style.
+func sgrParams(b []byte) ([]int, bool) {
+	if len(b) == 0 {
+		return nil, true
+	}
+	parts := strings.Split(strings.ReplaceAll(string(b), ":", ";"), ";")
+	result := make([]int, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			result = append(result, 0)
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, n)
+	}
+	return result, true
+}
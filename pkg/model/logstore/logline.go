@@ -93,11 +93,12 @@ func (b *logLineBuilder) buildMainLine(options logOptions) LogLine {
 	progressMustPrint := segment.Fields[logger.FieldNameProgressMustPrint] == "1"
 
 	sb := strings.Builder{}
-	if options.showManifestPrefix && span.ManifestName != "" {
-		shouldSkip := options.skipFirstLineManifestPrefix && b.isFirstLine
-		if !shouldSkip {
-			sb.WriteString(SourcePrefix(span.ManifestName))
-		}
+	shouldSkip := options.skipFirstLineManifestPrefix && b.isFirstLine
+	if !shouldSkip {
+		sb.WriteString(options.timestamps.format(time))
+	}
+	if options.showManifestPrefix && span.ManifestName != "" && !shouldSkip {
+		sb.WriteString(SourcePrefix(span.ManifestName))
 	}
 
 	if segment.Anchor {
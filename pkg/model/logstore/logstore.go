@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -70,6 +71,10 @@ func (l LogSegment) IsComplete() bool {
 	return segmentLen > 0 && l.Text[segmentLen-1] == newlineByte
 }
 
+func (l LogSegment) isTruncationMarker() bool {
+	return l.Fields[logger.FieldNameTruncated] != ""
+}
+
 func (l LogSegment) Len() int {
 	return len(l.Text)
 }
@@ -149,12 +154,85 @@ type LogStore struct {
 	// that we don't need to recompute it each time.
 	len int
 
+	// The number of bytes stored for each manifest.
+	manifestLens map[model.ManifestName]int
+
 	// Used for truncating the log. Set as a property so that we can change it
 	// for testing.
 	maxLogLengthInBytes int
 
+	// If non-zero, truncate the logs of any manifest that grows past this size,
+	// even if the whole log is under maxLogLengthInBytes.
+	maxManifestLengthInBytes int
+
 	// If the log is truncated, we need to adjust all checkpoints
 	checkpointOffset Checkpoint
+
+	// How much of each manifest's log has been truncated so far.
+	truncations map[model.ManifestName]*Truncation
+
+	// Where truncated logs are saved. If nil, they're dropped.
+	spill *Spill
+
+	// Whether to collapse lines that a span logs over and over.
+	collapseRepeats bool
+
+	// The last line of each span, if it might be repeated.
+	repeats map[SpanID]*repeat
+
+	// Resources whose repeated lines are never collapsed.
+	keepRepeats model.ManifestNameSet
+
+	// Lines longer than this are cut short.
+	maxLineLengthInBytes int
+
+	// Spans in the middle of a line, and how long the line is so far.
+	lines map[SpanID]lineState
+
+	// Spans in the middle of a run of binary output.
+	binaryRuns map[SpanID]*binaryRun
+
+	// Patterns of text to redact, on top of the secrets passed to Append.
+	redactPatterns model.RedactPatterns
+
+	// What to do with the ANSI codes in each resource's logs.
+	ansiModes map[model.ManifestName]model.LogANSIMode
+
+	// Spans whose ANSI codes are stripped or turned into HTML, and
+	// where they are in their codes.
+	ansi map[SpanID]*ansiState
+
+	// When the first segment was added. Relative timestamps count from
+	// here unless told otherwise, even after the segment is truncated.
+	startTime time.Time
+}
+
+// Retention configures how much of the log the LogStore keeps in memory.
+type Retention struct {
+	// The most bytes of logs to keep across all resources.
+	// Zero means the default of about 2MB.
+	MaxBytes int
+
+	// The most bytes of logs to keep for any one resource.
+	// Zero means resources are only limited by MaxBytes.
+	MaxBytesPerManifest int
+
+	// If set, truncated logs are compressed and saved to this directory,
+	// so that they can be loaded later. Otherwise, they're dropped.
+	SpillDir string
+
+	// The longest line to keep, in bytes. Longer lines are cut short.
+	// Zero means the default of about 10KB.
+	MaxLineLength int
+}
+
+// Truncation counts the logs of a manifest that were truncated.
+type Truncation struct {
+	Lines int
+	Bytes int
+
+	// Whether all the truncated logs were saved to disk.
+	Spilled bool
 }
 
 func NewLogStoreForTesting(msg string) *LogStore {
@@ -165,11 +243,51 @@ func NewLogStoreForTesting(msg string) *LogStore {
 
 func NewLogStore() *LogStore {
 	return &LogStore{
-		spans:               make(map[SpanID]*Span),
-		segments:            []LogSegment{},
-		len:                 0,
-		maxLogLengthInBytes: defaultMaxLogLengthInBytes,
+		spans:                make(map[SpanID]*Span),
+		segments:             []LogSegment{},
+		len:                  0,
+		manifestLens:         make(map[model.ManifestName]int),
+		maxLogLengthInBytes:  defaultMaxLogLengthInBytes,
+		maxLineLengthInBytes: defaultMaxLineLengthInBytes,
+		truncations:          make(map[model.ManifestName]*Truncation),
+	}
+}
+
+// Changes how much of the log is kept in memory, truncating it if needed.
+func (s *LogStore) SetRetention(r Retention) {
+	s.maxLogLengthInBytes = defaultMaxLogLengthInBytes
+	if r.MaxBytes > 0 {
+		s.maxLogLengthInBytes = r.MaxBytes
+	}
+	s.maxManifestLengthInBytes = r.MaxBytesPerManifest
+	s.maxLineLengthInBytes = defaultMaxLineLengthInBytes
+	if r.MaxLineLength > 0 {
+		s.maxLineLengthInBytes = r.MaxLineLength
+	}
+
+	s.spill = nil
+	if r.SpillDir != "" {
+		s.spill = NewSpill(r.SpillDir)
 	}
+
+	for mn := range s.manifestLens {
+		s.ensureMaxManifestLength(mn)
+	}
+	s.ensureMaxLength()
+}
+
+// Where truncated logs are saved. Returns nil if they're dropped.
+func (s *LogStore) Spill() *Spill {
+	return s.spill
+}
+
+// Reports how much of a manifest's log has been truncated.
+func (s *LogStore) Truncation(mn model.ManifestName) (Truncation, bool) {
+	t, ok := s.truncations[mn]
+	if !ok {
+		return Truncation{}, false
+	}
+	return *t, true
 }
 
 func (s *LogStore) Checkpoint() Checkpoint {
@@ -233,13 +351,24 @@ func (s *LogStore) nextIndexMatchingManifests(index int, mns model.ManifestNameS
 	return -1
 }
 
-func (s *LogStore) ScrubSecretsStartingAt(secrets model.SecretSet, checkpoint Checkpoint) {
+// Sets the patterns of text to redact from logs appended from now on.
+func (s *LogStore) SetRedactPatterns(patterns model.RedactPatterns) {
+	s.redactPatterns = patterns
+}
+
+func (s *LogStore) RedactPatterns() model.RedactPatterns {
+	return s.redactPatterns
+}
+
+// Scrubs secrets, or redact patterns, from the logs appended since the checkpoint.
+func (s *LogStore) ScrubSecretsStartingAt(secrets model.Scrubber, checkpoint Checkpoint) {
 	index := s.checkpointToIndex(checkpoint)
 	for i := index; i < len(s.segments); i++ {
 		s.segments[i].Text = secrets.Scrub(s.segments[i].Text)
 	}
+	s.scrubRepeats(secrets)
 
-	s.len = s.computeLen()
+	s.computeLens()
 }
 
 func (s *LogStore) Append(le LogEvent, secrets model.SecretSet) {
@@ -257,23 +386,45 @@ func (s *LogStore) Append(le LogEvent, secrets model.SecretSet) {
 		s.spans[spanID] = span
 	}
 
-	msg := secrets.Scrub(le.Message())
-	added := segmentsFromBytes(spanID, le.Time(), le.Level(), le.Fields(), msg)
+	msg := s.redactPatterns.Scrub(secrets.Scrub(le.Message()))
+	msg = s.replaceBinary(spanID, span, msg)
+	msg = s.convertANSI(spanID, span, msg)
+	added := segmentsFromBytes(spanID, le.Time(), le.Level(), s.ansiFields(span, le.Fields()), msg)
+	added = s.limitLineLength(spanID, added)
+	if s.collapseRepeats {
+		s.emitStaleRepeats(le.Time(), spanID)
+		added = s.collapseRepeated(spanID, span, added)
+	}
 	if len(added) == 0 {
 		return
 	}
 
-	level := le.Level()
-	if level.AsSevereAs(logger.WarnLvl) {
+	s.appendToSpan(span, added)
+}
+
+func (s *LogStore) appendToSpan(span *Span, added []LogSegment) {
+	if added[0].Level.AsSevereAs(logger.WarnLvl) {
 		added[0].Anchor = true
 	}
 
 	added[0].ContinuesLine = s.computeContinuesLine(added[0], span)
 
+	if s.startTime.IsZero() {
+		s.startTime = added[0].Time
+	}
 	s.segments = append(s.segments, added...)
 	span.LastSegmentIndex = len(s.segments) - 1
 
-	s.len += len(msg)
+	n := 0
+	for _, seg := range added {
+		n += seg.Len()
+	}
+	s.len += n
+	if s.manifestLens == nil {
+		s.manifestLens = make(map[model.ManifestName]int)
+	}
+	s.manifestLens[span.ManifestName] += n
+	s.ensureMaxManifestLength(span.ManifestName)
 	s.ensureMaxLength()
 }
 
@@ -381,7 +532,7 @@ func (s *LogStore) computeContinuesLine(seg LogSegment, span *Span) bool {
 }
 
 func (s *LogStore) recomputeDerivedValues() {
-	s.len = s.computeLen()
+	s.computeLens()
 
 	// Reset the last segment index so we can rebuild them from scratch.
 	for _, span := range s.spans {
@@ -484,10 +635,15 @@ func (s *LogStore) ContinuingLinesWithOptions(checkpoint Checkpoint, opts LineOp
 	if len(opts.ManifestNames) != 0 {
 		spans = tempLogStore.spansForManifests(opts.ManifestNames)
 	}
+	timestamps := opts.Timestamps
+	if timestamps.Mode == TimestampModeRelative && timestamps.Start.IsZero() {
+		timestamps.Start = s.startTime
+	}
 	result := tempLogStore.toLogLines(logOptions{
 		spans:                       spans,
 		showManifestPrefix:          !opts.SuppressPrefix,
 		skipFirstLineManifestPrefix: isSameSpanContinuation,
+		timestamps:                  timestamps,
 	})
 
 	if isSameSpanContinuation {
@@ -546,6 +702,52 @@ func (s *LogStore) ToLogList(fromCheckpoint Checkpoint) (*webview.LogList, error
 	}, nil
 }
 
+// A log segment, with the context a client needs to consume it on its own.
+type StreamSegment struct {
+	LogSegment
+
+	ManifestName model.ManifestName
+
+	// The checkpoint just after this segment. A client that has seen
+	// this segment can resume from here.
+	Checkpoint Checkpoint
+}
+
+type SegmentOptions struct {
+	ManifestNames model.ManifestNameSet // only include segments for these manifests
+	MinLevel      logger.Level          // only include segments at least this severe
+}
+
+// Returns the segments added since the given checkpoint that match the options,
+// and the checkpoint to resume from.
+//
+// Unlike ContinuingLines, segments aren't joined into lines, so a segment
+// may hold part of a line, or several lines.
+func (s *LogStore) SegmentsSince(checkpoint Checkpoint, opts SegmentOptions) ([]StreamSegment, Checkpoint) {
+	var result []StreamSegment
+	for i := s.checkpointToIndex(checkpoint); i < len(s.segments); i++ {
+		segment := s.segments[i]
+		if !opts.MinLevel.ShouldDisplay(segment.Level) {
+			continue
+		}
+
+		var mn model.ManifestName
+		if span, ok := s.spans[segment.SpanID]; ok {
+			mn = span.ManifestName
+		}
+		if len(opts.ManifestNames) != 0 && !opts.ManifestNames[mn] {
+			continue
+		}
+
+		result = append(result, StreamSegment{
+			LogSegment:   segment,
+			ManifestName: mn,
+			Checkpoint:   s.checkpointFromIndex(i + 1),
+		})
+	}
+	return result, s.Checkpoint()
+}
+
 func (s *LogStore) String() string {
 	return s.toLogString(logOptions{
 		spans:              s.spans,
@@ -655,11 +857,13 @@ type logOptions struct {
 	spans                       map[SpanID]*Span // only print logs for these spans
 	showManifestPrefix          bool
 	skipFirstLineManifestPrefix bool
+	timestamps                  Timestamps
 }
 
 type LineOptions struct {
 	ManifestNames  model.ManifestNameSet // only print logs for these manifests
 	SuppressPrefix bool
+	Timestamps     Timestamps // put a timestamp in front of each line
 }
 
 func (s *LogStore) toLogString(options logOptions) string {
@@ -747,12 +951,20 @@ func (s *LogStore) toLogLines(options logOptions) []LogLine {
 	return result
 }
 
-func (s *LogStore) computeLen() int {
-	result := 0
+// Truncation markers don't count towards the length, so that
+// they never get truncated themselves.
+func (s *LogStore) computeLens() {
+	s.len = 0
+	s.manifestLens = make(map[model.ManifestName]int)
 	for _, segment := range s.segments {
-		result += segment.Len()
+		if segment.isTruncationMarker() {
+			continue
+		}
+		s.len += segment.Len()
+		if span, ok := s.spans[segment.SpanID]; ok {
+			s.manifestLens[span.ManifestName] += segment.Len()
+		}
 	}
-	return result
 }
 
 // After a log hits its limit, we need to truncate it to keep it small
@@ -789,30 +1001,163 @@ func (s *LogStore) ensureMaxLength() {
 
 	// Lastly, go through all the segments, and truncate the manifests
 	// where we said we would.
+	bytesToKeep := make(map[model.ManifestName]int, len(manifestWeightMap))
+	for mn, weight := range manifestWeightMap {
+		bytesToKeep[mn] = weight.byteCount
+	}
+	s.truncate(bytesToKeep)
+}
+
+// Like ensureMaxLength, but only for the logs of one manifest.
+//
+// Cuts the manifest's log in half, for the same reason that we
+// truncate the whole log in big chunks.
+func (s *LogStore) ensureMaxManifestLength(mn model.ManifestName) {
+	if s.maxManifestLengthInBytes <= 0 || s.manifestLens[mn] <= s.maxManifestLengthInBytes {
+		return
+	}
+
+	bytesToKeep := make(map[model.ManifestName]int, len(s.manifestLens))
+	for name, n := range s.manifestLens {
+		bytesToKeep[name] = n
+	}
+	bytesToKeep[mn] = s.maxManifestLengthInBytes / 2
+	s.truncate(bytesToKeep)
+}
+
+// Keeps the most recent bytesToKeep[mn] bytes of each manifest's log,
+// then saves the rest to the spill (if any) and appends new
+// truncation markers for the manifests that lost logs.
+func (s *LogStore) truncate(bytesToKeep map[model.ManifestName]int) {
 	newSegments := make([]LogSegment, 0, len(s.segments)/2)
-	trimmedSegmentCount := 0
+	removed := []LogSegment{}
 	for i := len(s.segments) - 1; i >= 0; i-- {
 		segment := s.segments[i]
+		if segment.isTruncationMarker() {
+			newSegments = append(newSegments, segment)
+			continue
+		}
+
 		mn := s.spans[segment.SpanID].ManifestName
-		manifestWeightMap[mn].byteCount -= segment.Len()
-		if manifestWeightMap[mn].byteCount < 0 {
-			trimmedSegmentCount++
+		bytesToKeep[mn] -= segment.Len()
+		if bytesToKeep[mn] < 0 {
+			removed = append(removed, segment)
 			continue
 		}
 
 		newSegments = append(newSegments, segment)
 	}
 
+	if len(removed) == 0 {
+		return
+	}
+
 	reverseLogSegments(newSegments)
-	s.checkpointOffset += Checkpoint(trimmedSegmentCount)
-	s.segments = newSegments
+	reverseLogSegments(removed)
+
+	if s.spill != nil && s.spill.Err() != nil {
+		// A chunk failed to write in the background.
+		s.spill = nil
+	}
+
+	spilled := false
+	if s.spill != nil {
+		err := s.spill.write(removed, s.spans)
+		if err == nil {
+			spilled = true
+		} else {
+			// If we can't write to disk, fall back to dropping logs.
+			s.spill.setError(err)
+			s.spill = nil
+		}
+	}
+	truncated := s.recordTruncation(removed, spilled)
+
+	// The new markers replace the old markers of the same manifests.
+	kept := newSegments[:0]
+	oldMarkerCount := 0
+	for _, segment := range newSegments {
+		if segment.isTruncationMarker() && truncated[s.spans[segment.SpanID].ManifestName] {
+			oldMarkerCount++
+			continue
+		}
+		kept = append(kept, segment)
+	}
+
+	// Markers go at the end of the log, with the first one at the old
+	// checkpoint, so that clients streaming from a checkpoint see them.
+	s.checkpointOffset += Checkpoint(len(removed) + oldMarkerCount)
+	s.segments = append(kept, s.truncationMarkers(truncated)...)
 	s.recomputeDerivedValues()
 }
 
+// Adds the removed segments to the truncation counts, and returns the
+// manifests they belonged to.
+func (s *LogStore) recordTruncation(removed []LogSegment, spilled bool) map[model.ManifestName]bool {
+	if s.truncations == nil {
+		s.truncations = make(map[model.ManifestName]*Truncation)
+	}
+	truncated := make(map[model.ManifestName]bool)
+	for _, segment := range removed {
+		mn := s.spans[segment.SpanID].ManifestName
+		truncated[mn] = true
+		t, ok := s.truncations[mn]
+		if !ok {
+			t = &Truncation{Spilled: true}
+			s.truncations[mn] = t
+		}
+		t.Bytes += segment.Len()
+		if segment.IsComplete() {
+			t.Lines++
+		}
+		t.Spilled = t.Spilled && spilled
+	}
+	return truncated
+}
+
+// Creates a marker for each of the given manifests that says
+// how much of its log has been truncated so far.
+func (s *LogStore) truncationMarkers(manifests map[model.ManifestName]bool) []LogSegment {
+	names := make([]model.ManifestName, 0, len(manifests))
+	for mn := range manifests {
+		names = append(names, mn)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	markers := make([]LogSegment, 0, len(names))
+	for _, mn := range names {
+		t := s.truncations[mn]
+		spanID := SpanID(fmt.Sprintf("truncated:%s", mn))
+		if _, ok := s.spans[spanID]; !ok {
+			s.spans[spanID] = &Span{ManifestName: mn}
+		}
+
+		value := logger.TruncatedDropped
+		note := ""
+		if t.Spilled {
+			value = logger.TruncatedSpilled
+			note = ", saved to disk"
+		}
+		markers = append(markers, LogSegment{
+			SpanID: spanID,
+			Time:   time.Now(),
+			Text:   []byte(fmt.Sprintf("... %d earlier lines (%s) truncated%s ...\n", t.Lines, units.HumanSize(float64(t.Bytes)), note)),
+			Level:  logger.InfoLvl,
+			Fields: logger.Fields{logger.FieldNameTruncated: value},
+		})
+	}
+	return markers
+}
+
 // Count the number of bytes and start time in each manifest.
 func (s *LogStore) createManifestWeightMap() manifestWeightMap {
 	manifestWeightMap := manifestWeightMap{}
 	for _, segment := range s.segments {
+		if segment.isTruncationMarker() {
+			continue
+		}
 		mn := s.spans[segment.SpanID].ManifestName
 		weight, ok := manifestWeightMap[mn]
 		if !ok {
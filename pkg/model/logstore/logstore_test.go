@@ -3,12 +3,14 @@ package logstore
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -59,7 +61,7 @@ func TestLog_AppendOverLimit(t *testing.T) {
 
 	s := sb.String()
 	l.Append(newGlobalTestLogEvent(s), nil)
-	assert.Equal(t, "x\nx\nx\nx\nx\nx\nx\nx\n", l.String())
+	assert.Equal(t, "x\nx\nx\nx\nx\nx\nx\nx\n... 9 earlier lines (22B) truncated ...\n", l.String())
 }
 
 func TestLog_TruncateChattySpansFirst(t *testing.T) {
@@ -300,10 +302,247 @@ func TestContinuingStringAfterLimit(t *testing.T) {
 	assert.Equal(t, "abcdefghi\n", l.ContinuingString(c2))
 
 	l.Append(newGlobalTestLogEvent("jklmnopqr\n"), nil)
-	assert.Equal(t, "jklmnopqr\n", l.String())
-	assert.Equal(t, "jklmnopqr\n", l.ContinuingString(c1))
-	assert.Equal(t, "jklmnopqr\n", l.ContinuingString(c2))
-	assert.Equal(t, "jklmnopqr\n", l.ContinuingString(c3))
+	marker := "... 2 earlier lines (20B) truncated ...\n"
+	assert.Equal(t, "jklmnopqr\n"+marker, l.String())
+	assert.Equal(t, "jklmnopqr\n"+marker, l.ContinuingString(c1))
+	assert.Equal(t, "jklmnopqr\n"+marker, l.ContinuingString(c2))
+
+	// Clients streaming from the last checkpoint see the marker.
+	assert.Equal(t, "jklmnopqr\n"+marker, l.ContinuingString(c3))
+}
+
+func TestRetentionPerManifest(t *testing.T) {
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxBytesPerManifest: 20})
+
+	l.Append(newTestLogEvent("quiet", time.Now(), "started\n"), nil)
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("noisy", time.Now(), fmt.Sprintf("line %d\n", i)), nil)
+	}
+
+	assert.Equal(t, "started\n", l.ManifestLog("quiet"))
+	assert.Equal(t, "line 8\n... 8 earlier lines (56B) truncated ...\nline 9\n", l.ManifestLog("noisy"))
+
+	truncation, ok := l.Truncation("noisy")
+	assert.True(t, ok)
+	assert.Equal(t, Truncation{Lines: 8, Bytes: 56}, truncation)
+	_, ok = l.Truncation("quiet")
+	assert.False(t, ok)
+}
+
+func TestCollapseRepeats(t *testing.T) {
+	l := NewLogStore()
+	l.SetCollapseRepeats(true)
+
+	now := time.Now()
+	l.Append(newTestLogEvent("fe", now, "GET /healthz 200\n"), nil)
+	for i := 0; i < 3; i++ {
+		l.Append(newTestLogEvent("fe", now, "GET /healthz 200\n"), nil)
+	}
+	l.Append(newTestLogEvent("be", now, "started\n"), nil)
+	assert.Equal(t, "GET /healthz 200\n", l.ManifestLog("fe"))
+
+	l.Append(newTestLogEvent("fe", now, "GET /users 200\nGET /users 200\n"), nil)
+	assert.Equal(t, "GET /healthz 200\nGET /healthz 200 (×3)\nGET /users 200\n", l.ManifestLog("fe"))
+	assert.Equal(t, "started\n", l.ManifestLog("be"))
+}
+
+func TestCollapseRepeatsOffByDefault(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "ping\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "ping\n"), nil)
+	assert.Equal(t, "ping\nping\n", l.ManifestLog("fe"))
+}
+
+func TestCollapseRepeatsReemits(t *testing.T) {
+	l := NewLogStore()
+	l.SetCollapseRepeats(true)
+
+	start := time.Now()
+	for i := 0; i <= 4; i++ {
+		l.Append(newTestLogEvent("fe", start.Add(time.Duration(i)*10*time.Second), "ping\n"), nil)
+	}
+	assert.Equal(t, "ping\nping (×3)\n", l.ManifestLog("fe"))
+
+	// Once a line stops repeating, any other log shows the last repeats.
+	l.Append(newTestLogEvent("be", start.Add(2*time.Minute), "started\n"), nil)
+	assert.Equal(t, "ping\nping (×3)\nping (×1)\n", l.ManifestLog("fe"))
+}
+
+func TestCollapseRepeatsPartialLines(t *testing.T) {
+	l := NewLogStore()
+	l.SetCollapseRepeats(true)
+
+	now := time.Now()
+	l.Append(newTestLogEvent("fe", now, "loading"), nil)
+	l.Append(newTestLogEvent("fe", now, "loading"), nil)
+	l.Append(newTestLogEvent("fe", now, "\n"), nil)
+	assert.Equal(t, "loadingloading\n", l.ManifestLog("fe"))
+}
+
+func TestKeepRepeats(t *testing.T) {
+	l := NewLogStore()
+	l.SetCollapseRepeats(true)
+
+	now := time.Now()
+	l.Append(newTestLogEvent("fe", now, "ping\n"), nil)
+	l.Append(newTestLogEvent("fe", now, "ping\n"), nil)
+	l.SetKeepRepeats(model.ManifestNameSet{"fe": true})
+	l.Append(newTestLogEvent("fe", now, "ping\n"), nil)
+	l.Append(newTestLogEvent("fe", now, "ping\n"), nil)
+	assert.Equal(t, "ping\nping (×1)\nping\nping\n", l.ManifestLog("fe"))
+}
+
+func TestCollapseRepeatsScrubsSecrets(t *testing.T) {
+	l := NewLogStore()
+	l.SetCollapseRepeats(true)
+
+	now := time.Now()
+	l.Append(newTestLogEvent("fe", now, "token=s3cr3t\n"), nil)
+	l.Append(newTestLogEvent("fe", now, "token=s3cr3t\n"), nil)
+
+	secrets := model.SecretSet{}
+	secrets.AddSecret("token", "key", []byte("s3cr3t"))
+	l.ScrubSecretsStartingAt(secrets, 0)
+	l.Append(newTestLogEvent("fe", now, "done\n"), nil)
+	assert.NotContains(t, l.ManifestLog("fe"), "s3cr3t")
+}
+
+func TestBinaryOutput(t *testing.T) {
+	l := NewLogStore()
+	now := time.Now()
+
+	binary := []byte{0x7f, 'E', 'L', 'F', 0x02, 0x01, 0x01, 0x00, 0x00, 0x00}
+	l.Append(newTestLogEvent("fe", now, "dumping\n"), nil)
+	l.Append(newTestLogEvent("fe", now, string(binary)), nil)
+	l.Append(newTestLogEvent("fe", now, string(binary)), nil)
+	l.Append(newTestLogEvent("fe", now, string(binary)), nil)
+	l.Append(newTestLogEvent("fe", now, "done\n"), nil)
+
+	assert.Equal(t,
+		"dumping\n[binary output: 10 bytes]\n[binary output: 20 more bytes]\ndone\n",
+		l.ManifestLog("fe"))
+}
+
+func TestBinaryOutputStartsNewLine(t *testing.T) {
+	l := NewLogStore()
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "contents: "), nil)
+	l.Append(newTestLogEvent("fe", now, "\x00\x01\x02"), nil)
+	assert.Equal(t, "contents: \n[binary output: 3 bytes]\n", l.ManifestLog("fe"))
+}
+
+func TestIsBinary(t *testing.T) {
+	assert.False(t, isBinary([]byte("hello\tworld\r\n")))
+	assert.False(t, isBinary([]byte("\x1b[31merror\x1b[0m\n")))
+	assert.False(t, isBinary([]byte("héllo wörld ✓\n")))
+
+	// A character split across writes.
+	assert.False(t, isBinary([]byte("check \xe2\x9c")))
+
+	assert.True(t, isBinary([]byte("ab\x00cd")))
+	assert.False(t, isBinary([]byte("caf\xe9 cr\xe8me\n")))
+	assert.True(t, isBinary([]byte("\x03\x04\x05\x06 text")))
+	assert.True(t, isBinary([]byte("\x89PNG\r\n\x1a\n\x8f\x9e\xa3\x01\xff\xfe\xfd")))
+}
+
+func TestMaxLineLength(t *testing.T) {
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxLineLength: 10})
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "short\n"), nil)
+	l.Append(newTestLogEvent("fe", now, "0123456789abcdef\n"), nil)
+	assert.Equal(t, "short\n0123456789... [6 bytes truncated]\n", l.ManifestLog("fe"))
+}
+
+func TestMaxLineLengthAcrossWrites(t *testing.T) {
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxLineLength: 10})
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "012345"), nil)
+	l.Append(newTestLogEvent("fe", now, "6789abc"), nil)
+	l.Append(newTestLogEvent("fe", now, "defghi"), nil)
+	l.Append(newTestLogEvent("fe", now, "j\nnext\n"), nil)
+	assert.Equal(t, "0123456789... [10 bytes truncated]\nnext\n", l.ManifestLog("fe"))
+}
+
+func TestMaxLineLengthKeepsCharactersWhole(t *testing.T) {
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxLineLength: 4})
+
+	l.Append(newTestLogEvent("fe", time.Now(), "abc✓✓\n"), nil)
+	assert.Equal(t, "abc... [6 bytes truncated]\n", l.ManifestLog("fe"))
+}
+
+func TestTruncationMarkersAccumulate(t *testing.T) {
+	l := NewLogStore()
+	l.maxLogLengthInBytes = 20
+
+	for i := 0; i < 10; i++ {
+		l.Append(newGlobalTestLogEvent(fmt.Sprintf("line %d\n", i)), nil)
+	}
+
+	// Only the latest marker, with the total of all the truncations.
+	assert.Equal(t, "line 8\n... 8 earlier lines (56B) truncated ...\nline 9\n", l.String())
+}
+
+func TestTruncationSpill(t *testing.T) {
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxBytes: 20, SpillDir: t.TempDir()})
+
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("fe", time.Now(), fmt.Sprintf("line %d\n", i)), nil)
+	}
+	assert.Equal(t, "line 8\n... 8 earlier lines (56B) truncated, saved to disk ...\nline 9\n", l.ManifestLog("fe"))
+
+	spill := l.Spill()
+	require.NotNil(t, spill)
+	assert.True(t, spill.HasManifest("fe"))
+	assert.False(t, spill.HasManifest("be"))
+
+	spilled, err := spill.ManifestLog("fe")
+	require.NoError(t, err)
+	assert.Equal(t, "line 0\nline 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\n", spilled)
+}
+
+func TestTruncationSpillOverDefaultMax(t *testing.T) {
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxBytes: 1 << 20, SpillDir: t.TempDir()})
+
+	line := strings.Repeat("x", 1023) + "\n"
+	for i := 0; i < 3*1024; i++ {
+		l.Append(newTestLogEvent("fe", time.Now(), line), nil)
+	}
+
+	truncation, ok := l.Truncation("fe")
+	require.True(t, ok)
+	require.Greater(t, truncation.Bytes, defaultMaxLogLengthInBytes)
+
+	// Loading the spilled logs doesn't truncate them again.
+	spilled, err := l.Spill().ManifestLog("fe")
+	require.NoError(t, err)
+	assert.Equal(t, truncation.Bytes, len(spilled))
+	assert.NotContains(t, spilled, "truncated")
+}
+
+func TestTruncationSpillError(t *testing.T) {
+	dir := t.TempDir()
+	notADir := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(notADir, []byte("x"), 0600))
+
+	l := NewLogStore()
+	l.SetRetention(Retention{MaxBytes: 20, SpillDir: notADir})
+	spill := l.Spill()
+
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("fe", time.Now(), fmt.Sprintf("line %d\n", i)), nil)
+	}
+	assert.Equal(t, "line 8\n... 8 earlier lines (56B) truncated ...\nline 9\n", l.ManifestLog("fe"))
+	assert.Nil(t, l.Spill())
+	assert.Error(t, spill.Err())
 }
 
 func TestManifestLog(t *testing.T) {
@@ -362,6 +601,35 @@ func TestLogIncremental(t *testing.T) {
 	assert.Equal(t, int32(-1), list.ToCheckpoint)
 }
 
+func TestSegmentsSince(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "fe1\n"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "be1\n"), nil)
+	l.Append(newGlobalLevelTestLogEvent("global warning\n", logger.WarnLvl), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe2\n"), nil)
+
+	segments, next := l.SegmentsSince(0, SegmentOptions{})
+	assert.Len(t, segments, 4)
+	assert.Equal(t, Checkpoint(4), next)
+
+	segments, next = l.SegmentsSince(1, SegmentOptions{ManifestNames: model.ManifestNameSet{"fe": true}})
+	require.Len(t, segments, 1)
+	assert.Equal(t, "fe2\n", string(segments[0].Text))
+	assert.Equal(t, model.ManifestName("fe"), segments[0].ManifestName)
+	assert.Equal(t, Checkpoint(4), segments[0].Checkpoint)
+	assert.Equal(t, Checkpoint(4), next)
+
+	segments, _ = l.SegmentsSince(0, SegmentOptions{MinLevel: logger.WarnLvl})
+	require.Len(t, segments, 1)
+	assert.Equal(t, "global warning\n", string(segments[0].Text))
+	assert.Equal(t, model.ManifestName(""), segments[0].ManifestName)
+	assert.Equal(t, Checkpoint(3), segments[0].Checkpoint)
+
+	segments, next = l.SegmentsSince(4, SegmentOptions{})
+	assert.Len(t, segments, 0)
+	assert.Equal(t, Checkpoint(4), next)
+}
+
 func TestWarnings(t *testing.T) {
 	l := NewLogStore()
 	l.Append(testLogEvent{
@@ -532,6 +800,76 @@ func TestContinuingLinesWithOptionsSpans(t *testing.T) {
 	}, l.ContinuingLinesWithOptions(c1, lineOptionsWithManifests("foo")))
 }
 
+func TestContinuingLinesWithAbsoluteTimestamps(t *testing.T) {
+	l := NewLogStore()
+	c1 := l.Checkpoint()
+
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.Append(newTestLogEvent("fe", start, "hello\nwor"), nil)
+	c2 := l.Checkpoint()
+	l.Append(newTestLogEvent("fe", start.Add(time.Second), "ld\n"), nil)
+	l.Append(newTestLogEvent("", start.Add(2*time.Second), "global\n"), nil)
+
+	opts := LineOptions{Timestamps: Timestamps{Mode: TimestampModeAbsolute}}
+	assert.Equal(t,
+		"2021-03-01T12:00:00.000Z            fe │ hello\n"+
+			"2021-03-01T12:00:00.000Z            fe │ world\n"+
+			"2021-03-01T12:00:02.000Z global\n",
+		l.ContinuingStringWithOptions(c1, opts))
+
+	// A line that was started earlier doesn't get a second timestamp.
+	assert.Equal(t,
+		"ld\n"+
+			"2021-03-01T12:00:02.000Z global\n",
+		l.ContinuingStringWithOptions(c2, opts))
+
+	opts.SuppressPrefix = true
+	opts.Timestamps.Location = time.FixedZone("EST", -5*60*60)
+	assert.Equal(t, "2021-03-01T07:00:00.000-05:00 hello\n",
+		l.ContinuingLinesWithOptions(c1, opts)[0].Text)
+}
+
+func TestContinuingLinesWithRelativeTimestamps(t *testing.T) {
+	l := NewLogStore()
+	c1 := l.Checkpoint()
+
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.Append(newTestLogEvent("fe", start, "one\n"), nil)
+	l.Append(newTestLogEvent("fe", start.Add(1500*time.Millisecond), "two\n"), nil)
+	c2 := l.Checkpoint()
+	l.Append(newTestLogEvent("fe", start.Add(2*time.Minute), "three\n"), nil)
+
+	opts := LineOptions{SuppressPrefix: true, Timestamps: Timestamps{Mode: TimestampModeRelative}}
+	assert.Equal(t,
+		"   +0.000s one\n"+
+			"   +1.500s two\n"+
+			" +120.000s three\n",
+		l.ContinuingStringWithOptions(c1, opts))
+
+	// Later lines still count from the first line in the store.
+	assert.Equal(t, " +120.000s three\n", l.ContinuingStringWithOptions(c2, opts))
+
+	opts.Timestamps.Start = start.Add(-time.Second)
+	assert.Equal(t, " +121.000s three\n", l.ContinuingStringWithOptions(c2, opts))
+}
+
+func TestParseTimestamps(t *testing.T) {
+	ts, err := ParseTimestamps("", "")
+	require.NoError(t, err)
+	assert.False(t, ts.Enabled())
+
+	ts, err = ParseTimestamps("Relative", "local")
+	require.NoError(t, err)
+	assert.Equal(t, TimestampModeRelative, ts.Mode)
+	assert.Equal(t, time.Local, ts.Location)
+
+	_, err = ParseTimestamps("sometimes", "utc")
+	assert.EqualError(t, err, `invalid timestamps "sometimes": must be one of none, absolute, relative`)
+
+	_, err = ParseTimestamps("absolute", "Mars/Olympus")
+	assert.EqualError(t, err, `invalid timezone "Mars/Olympus": must be one of utc, local`)
+}
+
 func TestBuildEventInit(t *testing.T) {
 	l := NewLogStore()
 
@@ -583,3 +921,63 @@ func lineOptionsWithManifests(mns ...model.ManifestName) LineOptions {
 	}
 	return LineOptions{ManifestNames: mnSet}
 }
+
+func TestSourceForSpan(t *testing.T) {
+	assert.Equal(t, model.LogSourceBuild, SourceForSpan("build:1"))
+	assert.Equal(t, model.LogSourceBuild, SourceForSpan("kubernetesapply:fe"))
+	assert.Equal(t, model.LogSourceRuntime, SourceForSpan("pod:fe:fe-123"))
+	assert.Equal(t, model.LogSourceRuntime, SourceForSpan("localserve:2"))
+	assert.Equal(t, model.LogSourceSystem, SourceForSpan("events:fe"))
+	assert.Equal(t, model.LogSourceSystem, SourceForSpan("disabletoggle-fe"))
+}
+
+func TestANSIStrip(t *testing.T) {
+	l := NewLogStore()
+	l.SetANSIModes(map[model.ManifestName]model.LogANSIMode{"fe": model.LogANSIStrip})
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "\x1b[1;31merror\x1b[0m: \x1b]0;title\x07failed\n"), nil)
+	l.Append(newTestLogEvent("be", now, "\x1b[32mok\x1b[0m\n"), nil)
+	assert.Equal(t, "error: failed\n", l.ManifestLog("fe"))
+	assert.Equal(t, "\x1b[32mok\x1b[0m\n", l.ManifestLog("be"))
+}
+
+func TestANSIStripAcrossWrites(t *testing.T) {
+	l := NewLogStore()
+	l.SetANSIModes(map[model.ManifestName]model.LogANSIMode{"fe": model.LogANSIStrip})
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "one \x1b[3"), nil)
+	l.Append(newTestLogEvent("fe", now, "2mtwo\x1b"), nil)
+	l.Append(newTestLogEvent("fe", now, "[0m\n"), nil)
+	assert.Equal(t, "one two\n", l.ManifestLog("fe"))
+}
+
+func TestANSIHTML(t *testing.T) {
+	l := NewLogStore()
+	l.SetANSIModes(map[model.ManifestName]model.LogANSIMode{"fe": model.LogANSIHTML})
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "<b> & \x1b[1;31mred\nstill red\x1b[22;39m plain \x1b[38;5;10mgreen\x1b[0m\n"), nil)
+	assert.Equal(t,
+		"&lt;b&gt; &amp; <span class=\"ansi-red-fg ansi-bold\">red</span>\n"+
+			"<span class=\"ansi-red-fg ansi-bold\">still red</span> plain <span class=\"ansi-bright-green-fg\">green</span>\n",
+		l.ManifestLog("fe"))
+
+	segments, _ := l.SegmentsSince(0, SegmentOptions{})
+	for _, seg := range segments {
+		assert.Equal(t, "1", seg.Fields[logger.FieldNameANSIHTML])
+	}
+}
+
+func TestANSIHTMLStyleAcrossWrites(t *testing.T) {
+	l := NewLogStore()
+	l.SetANSIModes(map[model.ManifestName]model.LogANSIMode{"fe": model.LogANSIHTML})
+	now := time.Now()
+
+	l.Append(newTestLogEvent("fe", now, "\x1b[4mone"), nil)
+	l.Append(newTestLogEvent("fe", now, " two\x1b[m three\n"), nil)
+	assert.Equal(t,
+		"<span class=\"ansi-underline\">one</span><span class=\"ansi-underline\"> two</span> three\n",
+		l.ManifestLog("fe"))
+}
@@ -0,0 +1,165 @@
+package logstore
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How often to show how many times a repeated line was logged, while
+// it's still being repeated.
+const repeatEmitInterval = 30 * time.Second
+
+// A line that its span logged more than once in a row.
+//
+// The first time the line is logged, it's stored as usual. The repeats
+// aren't stored. Instead, they're counted, and shown as one line, like
+//
+//	GET /healthz 200 (×12)
+//
+// when the span logs a different line, or every repeatEmitInterval
+// while the repeats go on.
+type repeat struct {
+	line   []byte
+	level  logger.Level
+	fields logger.Fields
+
+	// Repeats since the line was last shown.
+	count int
+
+	// When the line was last shown, and last repeated.
+	emitTime time.Time
+	lastTime time.Time
+}
+
+func (r *repeat) matches(seg LogSegment) bool {
+	return seg.Level == r.level && bytes.Equal(seg.Text, r.line)
+}
+
+// A line that says how many times the line was repeated.
+func (r *repeat) summary(spanID SpanID) LogSegment {
+	text := bytes.TrimSuffix(r.line, []byte("\n"))
+	return LogSegment{
+		SpanID: spanID,
+		Time:   r.lastTime,
+		Text:   []byte(fmt.Sprintf("%s (×%d)\n", text, r.count)),
+		Level:  r.level,
+		Fields: r.fields,
+	}
+}
+
+func (r *repeat) emitted(t time.Time) {
+	r.count = 0
+	r.emitTime = t
+}
+
+// Turns collapsing of repeated lines on or off.
+//
+// Tilt turns it on for the logs it collects. It's off by default, so that
+// LogStores that rebuild logs saved elsewhere show them as they were saved.
+func (s *LogStore) SetCollapseRepeats(enabled bool) {
+	s.collapseRepeats = enabled
+	if !enabled {
+		s.repeats = nil
+	}
+}
+
+// Sets the resources whose repeated lines are kept as they are.
+func (s *LogStore) SetKeepRepeats(names model.ManifestNameSet) {
+	s.keepRepeats = names
+}
+
+// Counts the segments that repeat the span's last line instead of
+// keeping them, and returns the segments to append.
+func (s *LogStore) collapseRepeated(spanID SpanID, span *Span, segments []LogSegment) []LogSegment {
+	r, ok := s.repeats[spanID]
+	if s.keepRepeats[span.ManifestName] {
+		if !ok {
+			return segments
+		}
+		delete(s.repeats, spanID)
+		if r.count == 0 {
+			return segments
+		}
+		return append([]LogSegment{r.summary(spanID)}, segments...)
+	}
+
+	if s.repeats == nil {
+		s.repeats = make(map[SpanID]*repeat)
+	}
+
+	// Whether the next segment starts a new line.
+	startsLine := span.LastSegmentIndex == -1 || s.segments[span.LastSegmentIndex].IsComplete()
+
+	result := make([]LogSegment, 0, len(segments))
+	for _, seg := range segments {
+		if ok && r.matches(seg) {
+			r.count++
+			r.lastTime = seg.Time
+			if seg.Time.Sub(r.emitTime) >= repeatEmitInterval {
+				result = append(result, r.summary(spanID))
+				r.emitted(seg.Time)
+			}
+			continue
+		}
+
+		if ok && r.count > 0 {
+			result = append(result, r.summary(spanID))
+		}
+
+		// Progress lines are updated in place, so they're never collapsed.
+		if startsLine && seg.IsComplete() && seg.Fields[logger.FieldNameProgressID] == "" {
+			r = &repeat{
+				line:     seg.Text,
+				level:    seg.Level,
+				fields:   seg.Fields,
+				emitTime: seg.Time,
+				lastTime: seg.Time,
+			}
+			s.repeats[spanID] = r
+			ok = true
+		} else {
+			delete(s.repeats, spanID)
+			r, ok = nil, false
+		}
+
+		result = append(result, seg)
+		startsLine = seg.IsComplete()
+	}
+	return result
+}
+
+// Shows the repeats of other spans that haven't been shown in a while,
+// so that a line that stopped repeating isn't hidden until its span
+// logs again.
+func (s *LogStore) emitStaleRepeats(now time.Time, except SpanID) {
+	var stale []SpanID
+	for spanID, r := range s.repeats {
+		if spanID != except && r.count > 0 && now.Sub(r.emitTime) >= repeatEmitInterval {
+			stale = append(stale, spanID)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i] < stale[j] })
+
+	for _, spanID := range stale {
+		r := s.repeats[spanID]
+		span, ok := s.spans[spanID]
+		if !ok {
+			// The span was truncated away.
+			delete(s.repeats, spanID)
+			continue
+		}
+		s.appendToSpan(span, []LogSegment{r.summary(spanID)})
+		r.emitted(now)
+	}
+}
+
+func (s *LogStore) scrubRepeats(secrets model.Scrubber) {
+	for _, r := range s.repeats {
+		r.line = secrets.Scrub(r.line)
+	}
+}
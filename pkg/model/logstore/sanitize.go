@@ -0,0 +1,174 @@
+package logstore
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Lines longer than this are cut short, unless the Retention says otherwise.
+const defaultMaxLineLengthInBytes = 10 * 1000
+
+// Where a span is in the line it's logging, so that long lines can be
+// cut short even when they're logged a piece at a time.
+type lineState struct {
+	// Bytes kept of the line so far.
+	kept int
+
+	// Bytes dropped from the line so far.
+	dropped int
+}
+
+// Where a span is in a run of binary output.
+type binaryRun struct {
+	// Bytes of binary output since the placeholder was logged.
+	bytes int
+}
+
+// Whether the text looks like binary data rather than something
+// meant to be read, like a command that printed a file it shouldn't have.
+//
+// Like git and grep, we treat text with a NUL byte as binary. Otherwise,
+// it's binary if more than a tenth of it is control characters. Bytes
+// that aren't valid UTF-8 count for a quarter, since older programs
+// still print Latin-1.
+func isBinary(b []byte) bool {
+	total, controls, invalid := 0, 0, 0
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == 0 {
+			return true
+		}
+		if r == utf8.RuneError && size == 1 {
+			// A multi-byte character may be split across writes.
+			if len(b)-i < utf8.UTFMax && !utf8.FullRune(b[i:]) {
+				break
+			}
+			invalid++
+		} else if (r < 0x20 && !isTextControl(r)) || r == 0x7f {
+			controls++
+		}
+		total++
+		i += size
+	}
+	return (4*controls+invalid)*10 > 4*total
+}
+
+// Control characters that show up in text, like tabs, and the backspaces
+// and escape codes of progress bars.
+func isTextControl(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', '\b', 0x1b:
+		return true
+	}
+	return false
+}
+
+// Replaces binary output with a placeholder that says how much of it
+// there was.
+//
+// A run of binary output is often split across many writes, so only the
+// first write of a run gets a placeholder. When the span logs text again,
+// another placeholder counts the rest of the run.
+func (s *LogStore) replaceBinary(spanID SpanID, span *Span, msg []byte) []byte {
+	run, inRun := s.binaryRuns[spanID]
+	if isBinary(msg) {
+		if inRun {
+			run.bytes += len(msg)
+			return nil
+		}
+		if s.binaryRuns == nil {
+			s.binaryRuns = make(map[SpanID]*binaryRun)
+		}
+		s.binaryRuns[spanID] = &binaryRun{}
+		return s.onNewLine(span, fmt.Sprintf("[binary output: %d bytes]\n", len(msg)))
+	}
+
+	if !inRun {
+		return msg
+	}
+	delete(s.binaryRuns, spanID)
+	if run.bytes == 0 {
+		return msg
+	}
+	return append([]byte(fmt.Sprintf("[binary output: %d more bytes]\n", run.bytes)), msg...)
+}
+
+// Starts the text on a new line, if the span's last line isn't finished.
+func (s *LogStore) onNewLine(span *Span, text string) []byte {
+	if span.LastSegmentIndex != -1 && !s.segments[span.LastSegmentIndex].IsComplete() {
+		text = "\n" + text
+	}
+	return []byte(text)
+}
+
+// Cuts lines longer than the max line length short, and marks how much
+// was cut at the end of the line, like
+//
+//	{"data": "aGVsbG8gd29ybGQ... [1048576 bytes truncated]
+//
+// Segments that only hold bytes that were cut are dropped.
+func (s *LogStore) limitLineLength(spanID SpanID, segments []LogSegment) []LogSegment {
+	// Most lines end in the write that starts them, so only spans in
+	// the middle of a line are tracked.
+	line := s.lines[spanID]
+	defer func() {
+		if line == (lineState{}) {
+			delete(s.lines, spanID)
+			return
+		}
+		if s.lines == nil {
+			s.lines = make(map[SpanID]lineState)
+		}
+		s.lines[spanID] = line
+	}()
+
+	result := segments[:0]
+	for _, seg := range segments {
+		complete := seg.IsComplete()
+		body := seg.Text
+		if complete {
+			body = body[:len(body)-1]
+		}
+
+		room := s.maxLineLengthInBytes - line.kept
+		if line.dropped == 0 && len(body) <= room {
+			line.kept += len(body)
+			if complete {
+				line = lineState{}
+			}
+			result = append(result, seg)
+			continue
+		}
+
+		keep := body[:truncateIndex(body, room)]
+		line.kept += len(keep)
+		line.dropped += len(body) - len(keep)
+
+		text := append([]byte{}, keep...)
+		if complete {
+			text = append(text, fmt.Sprintf("... [%d bytes truncated]\n", line.dropped)...)
+			line = lineState{}
+		}
+		if len(text) == 0 {
+			continue
+		}
+		seg.Text = text
+		result = append(result, seg)
+	}
+	return result
+}
+
+// The length of the longest prefix of b that's at most n bytes,
+// without splitting a UTF-8 character.
+func truncateIndex(b []byte, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if n >= len(b) {
+		return len(b)
+	}
+	for n > 0 && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	return n
+}
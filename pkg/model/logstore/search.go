@@ -0,0 +1,150 @@
+package logstore
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type SearchOptions struct {
+	Pattern       *regexp.Regexp        // only include lines matching this pattern
+	ManifestNames model.ManifestNameSet // only include lines from these manifests
+	MinLevel      logger.Level          // only include lines at least this severe
+	Since         time.Time             // only include lines logged at or after this time
+	Until         time.Time             // only include lines logged at or before this time
+
+	// The most matches to return. When there are more, the most recent
+	// matches are returned. Zero means no limit.
+	Limit int
+}
+
+// A line that matched a search.
+type SearchMatch struct {
+	ManifestName model.ManifestName
+	SpanID       SpanID
+	Level        logger.Level
+	Time         time.Time
+
+	// The text of the line, without the trailing newline.
+	Text string
+
+	// The checkpoint of the segment where the line starts.
+	Checkpoint Checkpoint
+
+	// The byte offsets in Text of each match of the pattern.
+	Ranges [][2]int
+}
+
+type searchLine struct {
+	index int
+	first LogSegment
+	text  []byte
+}
+
+// Searches all the logs line-by-line, and returns the lines that match
+// the options, oldest first.
+//
+// Also returns whether there were more matches than the limit.
+func (s *LogStore) Search(opts SearchOptions) ([]SearchMatch, bool) {
+	var result []SearchMatch
+	emit := func(l *searchLine) {
+		match, ok := s.matchLine(l, opts)
+		if ok {
+			result = append(result, match)
+		}
+	}
+
+	// Segments from different spans interleave, so we build up
+	// a line for each span until it's complete.
+	pending := make(map[SpanID]*searchLine)
+	for i, segment := range s.segments {
+		line := pending[segment.SpanID]
+		if line != nil && !line.first.CanContinueLine(segment) {
+			emit(line)
+			line = nil
+		}
+
+		text := segment.Text
+		for len(text) > 0 {
+			if line == nil {
+				line = &searchLine{index: i, first: segment}
+			}
+			newline := bytes.IndexByte(text, newlineByte)
+			if newline == -1 {
+				line.text = append(line.text, text...)
+				break
+			}
+			line.text = append(line.text, text[:newline]...)
+			emit(line)
+			line = nil
+			text = text[newline+1:]
+		}
+
+		if line == nil {
+			delete(pending, segment.SpanID)
+		} else {
+			pending[segment.SpanID] = line
+		}
+	}
+
+	// Lines that never completed are still worth finding.
+	for _, line := range pending {
+		emit(line)
+	}
+
+	// Lines are emitted when they complete, so put them back in the order they started.
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Checkpoint < result[j].Checkpoint
+	})
+
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		return result[len(result)-opts.Limit:], true
+	}
+	return result, false
+}
+
+func (s *LogStore) matchLine(l *searchLine, opts SearchOptions) (SearchMatch, bool) {
+	if !opts.MinLevel.ShouldDisplay(l.first.Level) {
+		return SearchMatch{}, false
+	}
+	if !opts.Since.IsZero() && l.first.Time.Before(opts.Since) {
+		return SearchMatch{}, false
+	}
+	if !opts.Until.IsZero() && l.first.Time.After(opts.Until) {
+		return SearchMatch{}, false
+	}
+
+	var mn model.ManifestName
+	if span, ok := s.spans[l.first.SpanID]; ok {
+		mn = span.ManifestName
+	}
+	if len(opts.ManifestNames) != 0 && !opts.ManifestNames[mn] {
+		return SearchMatch{}, false
+	}
+
+	text := string(l.text)
+	var ranges [][2]int
+	if opts.Pattern != nil {
+		indices := opts.Pattern.FindAllStringIndex(text, -1)
+		if indices == nil {
+			return SearchMatch{}, false
+		}
+		for _, index := range indices {
+			ranges = append(ranges, [2]int{index[0], index[1]})
+		}
+	}
+
+	return SearchMatch{
+		ManifestName: mn,
+		SpanID:       l.first.SpanID,
+		Level:        l.first.Level,
+		Time:         l.first.Time,
+		Text:         text,
+		Checkpoint:   s.checkpointFromIndex(l.index),
+		Ranges:       ranges,
+	}, true
+}
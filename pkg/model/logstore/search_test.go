@@ -0,0 +1,87 @@
+package logstore
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestSearchPattern(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "listening on :8080\nerror: connection refused\n"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "Error: disk full\n"), nil)
+
+	matches, truncated := l.Search(SearchOptions{Pattern: regexp.MustCompile(`(?i)error`)})
+	assert.False(t, truncated)
+	require.Len(t, matches, 2)
+	assert.Equal(t, SearchMatch{
+		ManifestName: "fe",
+		SpanID:       "fe",
+		Level:        logger.InfoLvl,
+		Time:         matches[0].Time,
+		Text:         "error: connection refused",
+		Checkpoint:   1,
+		Ranges:       [][2]int{{0, 5}},
+	}, matches[0])
+	assert.Equal(t, "Error: disk full", matches[1].Text)
+	assert.Equal(t, model.ManifestName("be"), matches[1].ManifestName)
+}
+
+func TestSearchJoinsInterleavedSegments(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "Step 1: building"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "be ready\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), " image... done\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "still going"), nil)
+
+	matches, _ := l.Search(SearchOptions{ManifestNames: model.ManifestNameSet{"fe": true}})
+	require.Len(t, matches, 2)
+	assert.Equal(t, "Step 1: building image... done", matches[0].Text)
+	assert.Equal(t, Checkpoint(0), matches[0].Checkpoint)
+
+	// Incomplete lines are searched too.
+	assert.Equal(t, "still going", matches[1].Text)
+
+	matches, _ = l.Search(SearchOptions{Pattern: regexp.MustCompile(`building image`)})
+	require.Len(t, matches, 1)
+	assert.Equal(t, [][2]int{{8, 22}}, matches[0].Ranges)
+}
+
+func TestSearchLevelAndTime(t *testing.T) {
+	start := time.Unix(1000, 0)
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", start, "early\n"), nil)
+	l.Append(newTestLogEvent("fe", start.Add(time.Minute), "middle\n"), nil)
+	l.Append(newTestLogEvent("fe", start.Add(2*time.Minute), "late\n"), nil)
+	l.Append(newGlobalLevelTestLogEvent("careful\n", logger.WarnLvl), nil)
+
+	matches, _ := l.Search(SearchOptions{
+		ManifestNames: model.ManifestNameSet{"fe": true},
+		Since:         start.Add(time.Minute),
+		Until:         start.Add(time.Minute),
+	})
+	require.Len(t, matches, 1)
+	assert.Equal(t, "middle", matches[0].Text)
+
+	matches, _ = l.Search(SearchOptions{MinLevel: logger.WarnLvl})
+	require.Len(t, matches, 1)
+	assert.Equal(t, "careful", matches[0].Text)
+	assert.Equal(t, model.ManifestName(""), matches[0].ManifestName)
+}
+
+func TestSearchLimitKeepsMostRecent(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "line 1\nline 2\nline 3\n"), nil)
+
+	matches, truncated := l.Search(SearchOptions{Limit: 2})
+	assert.True(t, truncated)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "line 2", matches[0].Text)
+	assert.Equal(t, "line 3", matches[1].Text)
+}
@@ -0,0 +1,33 @@
+package logstore
+
+import (
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var spanSources = map[string]model.LogSource{
+	"build":           model.LogSourceBuild,
+	"tiltfile":        model.LogSourceBuild,
+	"liveupdate":      model.LogSourceBuild,
+	"dockerimage":     model.LogSourceBuild,
+	"cmdimage":        model.LogSourceBuild,
+	"imagemap":        model.LogSourceBuild,
+	"kubernetesapply": model.LogSourceBuild,
+	"dockercompose":   model.LogSourceBuild,
+	"pod":             model.LogSourceRuntime,
+	"localserve":      model.LogSourceRuntime,
+	"dc":              model.LogSourceRuntime,
+}
+
+// Returns where the logs in a span come from, based on the prefix of the
+// span ID (e.g., "pod:" spans hold a pod's logs).
+//
+// Spans that aren't build or runtime logs are system logs.
+func SourceForSpan(spanID SpanID) model.LogSource {
+	prefix, _, _ := strings.Cut(string(spanID), ":")
+	if source, ok := spanSources[prefix]; ok {
+		return source
+	}
+	return model.LogSourceSystem
+}
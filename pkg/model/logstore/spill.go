@@ -0,0 +1,230 @@
+package logstore
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// A Spill saves logs that the LogStore truncated to compressed files on disk,
+// so that the UI can load them when someone asks for them.
+//
+// Each truncation writes one gzipped chunk of JSON lines. We keep an index of
+// which manifests are in each chunk in memory, so we only read the chunks we need.
+//
+// The LogStore truncates while the engine state is locked, so chunks are
+// compressed and written in the background.
+//
+// Unlike the LogStore, a Spill is thread-safe.
+type Spill struct {
+	mu       sync.Mutex
+	dir      string
+	dirReady bool
+	chunks   []*spillChunk
+	err      error
+}
+
+type spillChunk struct {
+	path      string
+	manifests map[model.ManifestName]bool
+
+	// Closed when the chunk has been written. If writing failed,
+	// err is set before done is closed.
+	done chan struct{}
+	err  error
+}
+
+type spilledSegment struct {
+	SpanID       SpanID             `json:"spanID"`
+	ManifestName model.ManifestName `json:"manifestName"`
+	Time         time.Time          `json:"time"`
+	Level        int32              `json:"level"`
+	Text         string             `json:"text"`
+	Fields       logger.Fields      `json:"fields,omitempty"`
+}
+
+func NewSpill(dir string) *Spill {
+	return &Spill{dir: dir}
+}
+
+func (sp *Spill) Dir() string {
+	return sp.dir
+}
+
+// The error that made the LogStore stop saving logs to this spill, if any.
+func (sp *Spill) Err() error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.err
+}
+
+func (sp *Spill) setError(err error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.err == nil {
+		sp.err = err
+	}
+}
+
+// Whether any logs for the manifest were saved.
+func (sp *Spill) HasManifest(mn model.ManifestName) bool {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	for _, c := range sp.chunks {
+		if c.manifests[mn] {
+			return true
+		}
+	}
+	return false
+}
+
+// Queues the segments to be saved as a new chunk.
+//
+// Only creating the directory happens right away, so that a spill dir we
+// can't use is reported before we claim any logs were saved. Errors
+// writing the chunk itself are reported by Err() and ManifestLog().
+func (sp *Spill) write(segments []LogSegment, spans map[SpanID]*Span) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if sp.err != nil {
+		return sp.err
+	}
+
+	if !sp.dirReady {
+		err := os.MkdirAll(sp.dir, 0700)
+		if err != nil {
+			return fmt.Errorf("saving truncated logs: %v", err)
+		}
+		sp.dirReady = true
+	}
+
+	chunk := &spillChunk{
+		path:      filepath.Join(sp.dir, fmt.Sprintf("%06d.jsonl.gz", len(sp.chunks))),
+		manifests: make(map[model.ManifestName]bool),
+		done:      make(chan struct{}),
+	}
+	records := make([]spilledSegment, 0, len(segments))
+	for _, segment := range segments {
+		mn := spans[segment.SpanID].ManifestName
+		chunk.manifests[mn] = true
+		records = append(records, spilledSegment{
+			SpanID:       segment.SpanID,
+			ManifestName: mn,
+			Time:         segment.Time,
+			Level:        segment.Level.ToProtoID(),
+			Text:         string(segment.Text),
+			Fields:       segment.Fields,
+		})
+	}
+
+	sp.chunks = append(sp.chunks, chunk)
+	go sp.writeChunk(chunk, records)
+	return nil
+}
+
+func (sp *Spill) writeChunk(chunk *spillChunk, records []spilledSegment) {
+	defer close(chunk.done)
+
+	err := writeSpillChunk(chunk.path, records)
+	if err != nil {
+		chunk.err = err
+		sp.setError(err)
+	}
+}
+
+func writeSpillChunk(path string, records []spilledSegment) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("saving truncated logs: %v", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	zw := gzip.NewWriter(f)
+	encoder := json.NewEncoder(zw)
+	for _, record := range records {
+		err := encoder.Encode(record)
+		if err != nil {
+			return fmt.Errorf("saving truncated logs: %v", err)
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return fmt.Errorf("saving truncated logs: %v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("saving truncated logs: %v", err)
+	}
+	return nil
+}
+
+// Loads the saved logs of a manifest from disk, oldest first.
+//
+// Waits for any chunks of the manifest that are still being written.
+func (sp *Spill) ManifestLog(mn model.ManifestName) (string, error) {
+	sp.mu.Lock()
+	chunks := append([]*spillChunk{}, sp.chunks...)
+	sp.mu.Unlock()
+
+	sb := strings.Builder{}
+	for _, c := range chunks {
+		if !c.manifests[mn] {
+			continue
+		}
+
+		<-c.done
+		if c.err != nil {
+			return "", c.err
+		}
+
+		err := readSpillChunk(c.path, mn, &sb)
+		if err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+func readSpillChunk(path string, mn model.ManifestName, sb *strings.Builder) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading truncated logs: %v", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading truncated logs: %v", err)
+	}
+
+	decoder := json.NewDecoder(zr)
+	for {
+		var segment spilledSegment
+		err := decoder.Decode(&segment)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading truncated logs: %v", err)
+		}
+		if segment.ManifestName != mn {
+			continue
+		}
+		sb.WriteString(segment.Text)
+	}
+}
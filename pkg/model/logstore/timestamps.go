@@ -0,0 +1,90 @@
+package logstore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// How to show when each line was logged, in front of the line.
+type TimestampMode string
+
+const (
+	// Lines don't have timestamps.
+	TimestampModeNone TimestampMode = ""
+
+	// Lines start with the date and time they were logged, like
+	// 2021-03-01T12:00:00.000Z
+	TimestampModeAbsolute TimestampMode = "absolute"
+
+	// Lines start with how long after the start they were logged, like
+	// +12.345s
+	TimestampModeRelative TimestampMode = "relative"
+)
+
+const absoluteTimestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Which timestamps to put in front of each line.
+//
+// Timestamps use the time a line was logged, as recorded by the Tilt that
+// logged it, so every client that renders the same logs shows the same times.
+type Timestamps struct {
+	Mode TimestampMode
+
+	// The time zone of absolute timestamps. Defaults to UTC.
+	Location *time.Location
+
+	// What relative timestamps count from, usually when Tilt started.
+	// Defaults to the time of the first line rendered.
+	Start time.Time
+}
+
+// Parses a timestamp mode (none, absolute, or relative) and a
+// time zone (utc or local), like the ones passed on the command line.
+func ParseTimestamps(mode, timezone string) (Timestamps, error) {
+	var result Timestamps
+	switch TimestampMode(strings.ToLower(mode)) {
+	case TimestampModeNone, "none":
+	case TimestampModeAbsolute:
+		result.Mode = TimestampModeAbsolute
+	case TimestampModeRelative:
+		result.Mode = TimestampModeRelative
+	default:
+		return Timestamps{}, fmt.Errorf("invalid timestamps %q: must be one of none, absolute, relative", mode)
+	}
+
+	switch strings.ToLower(timezone) {
+	case "", "utc":
+		result.Location = time.UTC
+	case "local":
+		result.Location = time.Local
+	default:
+		return Timestamps{}, fmt.Errorf("invalid timezone %q: must be one of utc, local", timezone)
+	}
+	return result, nil
+}
+
+func (t Timestamps) Enabled() bool {
+	return t.Mode != TimestampModeNone
+}
+
+// The timestamp to put in front of a line logged at ts, including the
+// space that separates it from the line.
+func (t Timestamps) format(ts time.Time) string {
+	switch t.Mode {
+	case TimestampModeAbsolute:
+		loc := t.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		return ts.In(loc).Format(absoluteTimestampLayout) + " "
+	case TimestampModeRelative:
+		d := ts.Sub(t.Start)
+		if d < 0 {
+			d = 0
+		}
+		// Padded so that the lines stay lined up for the first few hours.
+		return fmt.Sprintf("%10s ", fmt.Sprintf("+%.3fs", d.Seconds()))
+	}
+	return ""
+}
@@ -3,7 +3,9 @@ package model
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/google/go-cmp/cmp"
@@ -62,7 +64,25 @@ type Manifest struct {
 
 	SourceTiltfile ManifestName
 
+	// Labels group resources in the UI. A label may be a path of
+	// nested groups, like "payments/api" (see GroupPathSeparator).
 	Labels map[string]string
+
+	// If non-zero, the engine re-triggers this manifest this long after
+	// each update finishes. Set with rerun_after() in the Tiltfile.
+	RerunAfter time.Duration
+
+	// The minimum level of logs to keep from each source. Set with
+	// log.resource_level() in the Tiltfile.
+	LogLevels LogLevels
+
+	// Whether to keep every line the resource logs, instead of collapsing
+	// lines it logs over and over. Set with log.keep_repeats() in the Tiltfile.
+	KeepRepeatedLogs bool
+
+	// What to do with ANSI escape codes in the resource's logs. Empty means
+	// LogANSIPreserve. Set with log.ansi() in the Tiltfile.
+	LogANSI LogANSIMode
 }
 
 func (m Manifest) ID() TargetID {
@@ -232,6 +252,35 @@ func (m Manifest) WithLabels(labels map[string]string) Manifest {
 	return m
 }
 
+// Labels for API objects, which must be valid Kubernetes labels.
+//
+// Only the top-level group of each label path is kept. See GroupPaths
+// for the full paths.
+func (m Manifest) APILabels() map[string]string {
+	if m.Labels == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m.Labels))
+	for k := range m.Labels {
+		group := TopLevelGroup(k)
+		result[group] = group
+	}
+	return result
+}
+
+// The sorted paths of the groups this manifest belongs to.
+func (m Manifest) GroupPaths() []string {
+	if len(m.Labels) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(m.Labels))
+	for k := range m.Labels {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
 func (m Manifest) Validate() error {
 	if m.Name == "" {
 		return fmt.Errorf("[validate] manifest missing name: %+v", m)
@@ -599,7 +648,10 @@ var ignoreCustomBuildDepsField = cmpopts.IgnoreFields(CustomBuild{}, "Deps")
 var ignoreLocalTargetDepsField = cmpopts.IgnoreFields(LocalTarget{}, "Deps")
 var ignoreDockerBuildCacheFrom = cmpopts.IgnoreFields(DockerBuild{}, "CacheFrom")
 var ignoreLabels = cmpopts.IgnoreFields(Manifest{}, "Labels")
+var ignoreRerunAfter = cmpopts.IgnoreFields(Manifest{}, "RerunAfter")
+var ignoreLogSettings = cmpopts.IgnoreFields(Manifest{}, "LogLevels", "KeepRepeatedLogs", "LogANSI")
 var ignoreDockerComposeProject = cmpopts.IgnoreFields(v1alpha1.DockerComposeServiceSpec{}, "Project")
+var ignoreDockerComposeDown = cmpopts.IgnoreFields(DockerComposeTarget{}, "Down")
 var ignoreRegistryFields = cmpopts.IgnoreFields(v1alpha1.RegistryHosting{}, "HostFromClusterNetwork", "Help")
 
 // ignoreLinks ignores user-defined links for the purpose of build invalidation
@@ -647,6 +699,12 @@ func equalForBuildInvalidation(x, y interface{}) bool {
 		// user-added labels don't invalidate a build
 		ignoreLabels,
 
+		// rerun schedules change when we rebuild, not what we build
+		ignoreRerunAfter,
+
+		// log settings change what we log, not what we build
+		ignoreLogSettings,
+
 		// user-added links don't invalidate a build
 		ignoreLinks,
 
@@ -655,6 +713,9 @@ func equalForBuildInvalidation(x, y interface{}) bool {
 		// a seprate ServiceYAML field.
 		ignoreDockerComposeProject,
 
+		// settings for `tilt down` don't change what we run
+		ignoreDockerComposeDown,
+
 		// the RegistryHosting spec includes informational fields (Help) as
 		// well as some unused by Tilt (HostFromClusterNetwork)
 		ignoreRegistryFields,
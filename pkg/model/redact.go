@@ -0,0 +1,111 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// Removes sensitive text, like secrets, from logs.
+type Scrubber interface {
+	Scrub(text []byte) []byte
+}
+
+var _ Scrubber = SecretSet{}
+var _ Scrubber = RedactPatterns{}
+
+// A pattern of text to redact from logs, for secrets that Tilt doesn't
+// know the value of, like a token a server prints when it starts.
+type RedactPattern struct {
+	// Shown in place of the text, like [redacted jwt].
+	Name string
+
+	// If the pattern has a group named "secret", only the group is redacted,
+	// so that the text around the secret can be kept, like the name of the
+	// variable that holds it.
+	Regexp *regexp.Regexp
+}
+
+// Compiles a pattern of text to redact.
+//
+// Patterns that match empty text would redact between every character,
+// so they're rejected.
+func NewRedactPattern(name, expr string) (RedactPattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return RedactPattern{}, fmt.Errorf("invalid pattern %q: %v", expr, err)
+	}
+	if re.MatchString("") {
+		return RedactPattern{}, fmt.Errorf("invalid pattern %q: matches empty text", expr)
+	}
+	return RedactPattern{Name: name, Regexp: re}, nil
+}
+
+func mustRedactPattern(name, expr string) RedactPattern {
+	p, err := NewRedactPattern(name, expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Patterns for well-known kinds of credentials. Tilt redacts them unless
+// the Tiltfile turns them off with secret_settings(builtin_redactions=False).
+var BuiltinRedactPatterns = RedactPatterns{
+	mustRedactPattern("aws-access-key-id", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	mustRedactPattern("aws-secret-access-key",
+		`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+]{40})\b`),
+	mustRedactPattern("jwt", `\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+}
+
+func (p RedactPattern) Scrub(text []byte) []byte {
+	matches := p.Regexp.FindAllSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	group := p.Regexp.SubexpIndex("secret")
+	replacement := []byte(fmt.Sprintf("[redacted %s]", p.Name))
+	var buf bytes.Buffer
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group != -1 {
+			start, end = m[2*group], m[2*group+1]
+			if start == -1 {
+				continue
+			}
+		}
+		buf.Write(text[last:start])
+		buf.Write(replacement)
+		last = end
+	}
+	buf.Write(text[last:])
+	return buf.Bytes()
+}
+
+// The patterns of text to redact from logs, applied in order.
+type RedactPatterns []RedactPattern
+
+func (ps RedactPatterns) Scrub(text []byte) []byte {
+	for _, p := range ps {
+		text = p.Scrub(text)
+	}
+	return text
+}
+
+// The patterns in either list, without duplicates.
+func (ps RedactPatterns) Union(other RedactPatterns) RedactPatterns {
+	result := make(RedactPatterns, 0, len(ps)+len(other))
+	seen := make(map[string]bool, len(ps)+len(other))
+	for _, list := range []RedactPatterns{ps, other} {
+		for _, p := range list {
+			key := p.Name + "\x00" + p.Regexp.String()
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, p)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// Resource labels can be paths of nested groups, separated by '/'
+// (e.g., "payments/api/db").
+const GroupPathSeparator = "/"
+
+type GroupSettings struct {
+	// Groups to show first, in this order. Groups that aren't listed come
+	// after the listed ones, in alphabetical order.
+	//
+	// Order applies among siblings, so subgroups are listed by their full
+	// path (e.g., "payments/api").
+	Order []string
+}
+
+// The top-level group of a label path.
+func TopLevelGroup(path string) string {
+	group, _, _ := strings.Cut(path, GroupPathSeparator)
+	return group
+}
+
+// Returns the paths, plus all of their parent groups, ordered for display.
+//
+// Each group comes right before its subgroups. Siblings come in the order
+// from the settings, then alphabetically.
+func OrderGroupPaths(paths []string, settings GroupSettings) []string {
+	all := make(map[string]bool)
+	for _, p := range paths {
+		segments := strings.Split(p, GroupPathSeparator)
+		for i := range segments {
+			all[strings.Join(segments[:i+1], GroupPathSeparator)] = true
+		}
+	}
+
+	pinned := make(map[string]int, len(settings.Order))
+	for i, p := range settings.Order {
+		if _, ok := pinned[p]; !ok {
+			pinned[p] = i
+		}
+	}
+
+	result := make([]string, 0, len(all))
+	for p := range all {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return groupPathLess(result[i], result[j], pinned)
+	})
+	return result
+}
+
+func groupPathLess(a, b string, pinned map[string]int) bool {
+	aSegments := strings.Split(a, GroupPathSeparator)
+	bSegments := strings.Split(b, GroupPathSeparator)
+	for i := 0; i < len(aSegments) && i < len(bSegments); i++ {
+		if aSegments[i] == bSegments[i] {
+			continue
+		}
+
+		// Compare the sibling groups where the paths diverge.
+		aGroup := strings.Join(aSegments[:i+1], GroupPathSeparator)
+		bGroup := strings.Join(bSegments[:i+1], GroupPathSeparator)
+		aIndex, aPinned := pinned[aGroup]
+		bIndex, bPinned := pinned[bGroup]
+		if aPinned && bPinned {
+			return aIndex < bIndex
+		}
+		if aPinned != bPinned {
+			return aPinned
+		}
+		return aSegments[i] < bSegments[i]
+	}
+
+	// One is a parent of the other.
+	return len(aSegments) < len(bSegments)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderGroupPathsAlphabetical(t *testing.T) {
+	paths := []string{"payments/api", "frontend", "payments/db/replica", "auth"}
+	assert.Equal(t, []string{
+		"auth",
+		"frontend",
+		"payments",
+		"payments/api",
+		"payments/db",
+		"payments/db/replica",
+	}, OrderGroupPaths(paths, GroupSettings{}))
+}
+
+func TestOrderGroupPathsPinned(t *testing.T) {
+	paths := []string{"payments/api", "payments/db", "frontend", "auth", "tools"}
+	settings := GroupSettings{Order: []string{"payments", "payments/db", "frontend", "missing"}}
+	assert.Equal(t, []string{
+		"payments",
+		"payments/db",
+		"payments/api",
+		"frontend",
+		"auth",
+		"tools",
+	}, OrderGroupPaths(paths, settings))
+}
+
+func TestManifestGroupLabels(t *testing.T) {
+	m := Manifest{Name: "api"}.WithLabels(map[string]string{
+		"payments/api": "payments/api",
+		"payments/db":  "payments/db",
+		"backend":      "backend",
+	})
+	assert.Equal(t, map[string]string{"payments": "payments", "backend": "backend"}, m.APILabels())
+	assert.Equal(t, []string{"backend", "payments/api", "payments/db"}, m.GroupPaths())
+	assert.Nil(t, Manifest{}.GroupPaths())
+}
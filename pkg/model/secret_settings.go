@@ -2,8 +2,24 @@ package model
 
 type SecretSettings struct {
 	ScrubSecrets bool // whether to scrub secrets in logs
+
+	// Whether to redact well-known kinds of credentials from logs,
+	// with BuiltinRedactPatterns.
+	BuiltinRedactions bool
+
+	// Patterns of text to redact from logs, from the Tiltfile.
+	RedactPatterns RedactPatterns
 }
 
 func DefaultSecretSettings() SecretSettings {
-	return SecretSettings{ScrubSecrets: true}
+	return SecretSettings{ScrubSecrets: true, BuiltinRedactions: true}
+}
+
+// All the patterns of text to redact from logs.
+func (s SecretSettings) Redactions() RedactPatterns {
+	var result RedactPatterns
+	if s.BuiltinRedactions {
+		result = append(result, BuiltinRedactPatterns...)
+	}
+	return append(result, s.RedactPatterns...)
 }
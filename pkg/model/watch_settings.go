@@ -1,6 +1,10 @@
 package model
 
-import "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+import (
+	"fmt"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
 
 type WatchSettings struct {
 	Ignores []Dockerignore
@@ -19,12 +23,27 @@ type Dockerignore struct {
 
 	// Patterns parsed out of the .dockerignore file.
 	Patterns []string
+
+	// An optional user-supplied explanation of why these patterns are ignored.
+	Reason string
 }
 
 func (d Dockerignore) Empty() bool {
 	return len(d.Patterns) == 0
 }
 
+// A human-readable description of this ignore, for debugging
+// why a file change didn't trigger an update.
+func (d Dockerignore) Description() string {
+	if d.Reason == "" {
+		return d.Source
+	}
+	if d.Source == "" {
+		return d.Reason
+	}
+	return fmt.Sprintf("%s: %s", d.Source, d.Reason)
+}
+
 func DockerignoresToIgnores(source []Dockerignore) []v1alpha1.IgnoreDef {
 	result := make([]v1alpha1.IgnoreDef, 0, len(source))
 	for _, s := range source {
@@ -34,6 +53,7 @@ func DockerignoresToIgnores(source []Dockerignore) []v1alpha1.IgnoreDef {
 		result = append(result, v1alpha1.IgnoreDef{
 			BasePath: s.LocalPath,
 			Patterns: s.Patterns,
+			Reason:   s.Description(),
 		})
 	}
 	return result
@@ -31,6 +31,16 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertFiring":                       schema_pkg_apis_core_v1alpha1_AlertFiring(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRule":                         schema_pkg_apis_core_v1alpha1_AlertRule(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleList":                     schema_pkg_apis_core_v1alpha1_AlertRuleList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleSpec":                     schema_pkg_apis_core_v1alpha1_AlertRuleSpec(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleStatus":                   schema_pkg_apis_core_v1alpha1_AlertRuleStatus(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistory":                      schema_pkg_apis_core_v1alpha1_BuildHistory(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryList":                  schema_pkg_apis_core_v1alpha1_BuildHistoryList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryRecord":                schema_pkg_apis_core_v1alpha1_BuildHistoryRecord(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistorySpec":                  schema_pkg_apis_core_v1alpha1_BuildHistorySpec(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryStatus":                schema_pkg_apis_core_v1alpha1_BuildHistoryStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cluster":                           schema_pkg_apis_core_v1alpha1_Cluster(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection":                 schema_pkg_apis_core_v1alpha1_ClusterConnection(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnectionStatus":           schema_pkg_apis_core_v1alpha1_ClusterConnectionStatus(ref),
@@ -38,6 +48,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterSpec":                       schema_pkg_apis_core_v1alpha1_ClusterSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterStatus":                     schema_pkg_apis_core_v1alpha1_ClusterStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cmd":                               schema_pkg_apis_core_v1alpha1_Cmd(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdContainerTarget":                schema_pkg_apis_core_v1alpha1_CmdContainerTarget(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImage":                          schema_pkg_apis_core_v1alpha1_CmdImage(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageList":                      schema_pkg_apis_core_v1alpha1_CmdImageList(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageSpec":                      schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref),
@@ -46,11 +57,15 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateWaiting":              schema_pkg_apis_core_v1alpha1_CmdImageStateWaiting(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStatus":                    schema_pkg_apis_core_v1alpha1_CmdImageStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdList":                           schema_pkg_apis_core_v1alpha1_CmdList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdResourceUsage":                  schema_pkg_apis_core_v1alpha1_CmdResourceUsage(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdRestartStatus":                  schema_pkg_apis_core_v1alpha1_CmdRestartStatus(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSSHTarget":                      schema_pkg_apis_core_v1alpha1_CmdSSHTarget(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSpec":                           schema_pkg_apis_core_v1alpha1_CmdSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateRunning":                   schema_pkg_apis_core_v1alpha1_CmdStateRunning(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateTerminated":                schema_pkg_apis_core_v1alpha1_CmdStateTerminated(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateWaiting":                   schema_pkg_apis_core_v1alpha1_CmdStateWaiting(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStatus":                         schema_pkg_apis_core_v1alpha1_CmdStatus(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdTarget":                         schema_pkg_apis_core_v1alpha1_CmdTarget(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMap":                         schema_pkg_apis_core_v1alpha1_ConfigMap(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapDisableSource":            schema_pkg_apis_core_v1alpha1_ConfigMapDisableSource(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapList":                     schema_pkg_apis_core_v1alpha1_ConfigMapList(ref),
@@ -74,6 +89,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceList":          schema_pkg_apis_core_v1alpha1_DockerComposeServiceList(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceSpec":          schema_pkg_apis_core_v1alpha1_DockerComposeServiceSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceStatus":        schema_pkg_apis_core_v1alpha1_DockerComposeServiceStatus(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeUpOptions":            schema_pkg_apis_core_v1alpha1_DockerComposeUpOptions(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerContainerState":              schema_pkg_apis_core_v1alpha1_DockerContainerState(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImage":                       schema_pkg_apis_core_v1alpha1_DockerImage(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageList":                   schema_pkg_apis_core_v1alpha1_DockerImageList(ref),
@@ -103,6 +119,10 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction":                     schema_pkg_apis_core_v1alpha1_HTTPGetAction(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPHeader":                        schema_pkg_apis_core_v1alpha1_HTTPHeader(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Handler":                           schema_pkg_apis_core_v1alpha1_Handler(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbe":                       schema_pkg_apis_core_v1alpha1_HealthProbe(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeList":                   schema_pkg_apis_core_v1alpha1_HealthProbeList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeSpec":                   schema_pkg_apis_core_v1alpha1_HealthProbeSpec(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeStatus":                 schema_pkg_apis_core_v1alpha1_HealthProbeStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef":                         schema_pkg_apis_core_v1alpha1_IgnoreDef(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMap":                          schema_pkg_apis_core_v1alpha1_ImageMap(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapList":                      schema_pkg_apis_core_v1alpha1_ImageMapList(ref),
@@ -140,6 +160,14 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed":             schema_pkg_apis_core_v1alpha1_LiveUpdateStateFailed(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStatus":                  schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync":                    schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSink":                           schema_pkg_apis_core_v1alpha1_LogSink(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkList":                       schema_pkg_apis_core_v1alpha1_LogSinkList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkSpec":                       schema_pkg_apis_core_v1alpha1_LogSinkSpec(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkStatus":                     schema_pkg_apis_core_v1alpha1_LogSinkStatus(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Notification":                      schema_pkg_apis_core_v1alpha1_Notification(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationList":                  schema_pkg_apis_core_v1alpha1_NotificationList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationSpec":                  schema_pkg_apis_core_v1alpha1_NotificationSpec(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationStatus":                schema_pkg_apis_core_v1alpha1_NotificationStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ObjectSelector":                    schema_pkg_apis_core_v1alpha1_ObjectSelector(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Pod":                               schema_pkg_apis_core_v1alpha1_Pod(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodCondition":                      schema_pkg_apis_core_v1alpha1_PodCondition(ref),
@@ -156,6 +184,9 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec":           schema_pkg_apis_core_v1alpha1_PortForwardTemplateSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe":                             schema_pkg_apis_core_v1alpha1_Probe(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting":                   schema_pkg_apis_core_v1alpha1_RegistryHosting(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEvent":                     schema_pkg_apis_core_v1alpha1_ResourceEvent(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEventList":                 schema_pkg_apis_core_v1alpha1_ResourceEventList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEventSpec":                 schema_pkg_apis_core_v1alpha1_ResourceEventSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec":                     schema_pkg_apis_core_v1alpha1_RestartOnSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Session":                           schema_pkg_apis_core_v1alpha1_Session(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionCISpec":                     schema_pkg_apis_core_v1alpha1_SessionCISpec(ref),
@@ -202,8 +233,15 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputStatus":               schema_pkg_apis_core_v1alpha1_UIHiddenInputStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputSpec":                       schema_pkg_apis_core_v1alpha1_UIInputSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputStatus":                     schema_pkg_apis_core_v1alpha1_UIInputStatus(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanel":                           schema_pkg_apis_core_v1alpha1_UIPanel(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanelList":                       schema_pkg_apis_core_v1alpha1_UIPanelList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanelSpec":                       schema_pkg_apis_core_v1alpha1_UIPanelSpec(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferences":                     schema_pkg_apis_core_v1alpha1_UIPreferences(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferencesList":                 schema_pkg_apis_core_v1alpha1_UIPreferencesList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferencesSpec":                 schema_pkg_apis_core_v1alpha1_UIPreferencesSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResource":                        schema_pkg_apis_core_v1alpha1_UIResource(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceCondition":               schema_pkg_apis_core_v1alpha1_UIResourceCondition(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceGroup":                   schema_pkg_apis_core_v1alpha1_UIResourceGroup(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceKubernetes":              schema_pkg_apis_core_v1alpha1_UIResourceKubernetes(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLink":                    schema_pkg_apis_core_v1alpha1_UIResourceLink(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceList":                    schema_pkg_apis_core_v1alpha1_UIResourceList(ref),
@@ -275,106 +313,105 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Cluster(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_AlertFiring(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Cluster defines any runtime for running containers, in the broadest sense of the word \"runtime\".",
+				Description: "AlertFiring is an alert firing for one resource.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"resource": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "The name of the resource.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "What the measurement was, like \"2m31s\" or \"4\".",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterSpec"),
+							Description: "A description of why the alert is firing, followed by the rule's message, if any.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"since": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterStatus"),
+							Description: "When the alert started firing.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
+				Required: []string{"resource", "value", "message", "since"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_AlertRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Connection spec for an existing cluster.",
+				Description: "AlertRule fires an alert when a resource crosses a threshold, like \"a build took longer than 2m\" or \"the pod restarted more than 3 times in 10m\".\n\nFiring alerts show up as badges in the web UI. Notifications can post them to a webhook with the AlertFiring and AlertResolved events.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kubernetes": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Defines connection to a Kubernetes cluster.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnection"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"docker": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Defines connection to a Docker daemon.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerClusterConnection"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnection"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_ClusterConnectionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Connection spec for an existing cluster.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"kubernetes": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Defines connection to a Kubernetes cluster.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnectionStatus"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnectionStatus"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRuleStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ClusterList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_AlertRuleList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ClusterList",
+				Description: "AlertRuleList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -404,7 +441,7 @@ func schema_pkg_apis_core_v1alpha1_ClusterList(ref common.ReferenceCallback) com
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cluster"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRule"),
 									},
 								},
 							},
@@ -415,143 +452,109 @@ func schema_pkg_apis_core_v1alpha1_ClusterList(ref common.ReferenceCallback) com
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cluster", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_ClusterSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "ClusterSpec defines how to find the cluster we're running containers on.\n\nTilt currently supports connecting to an existing Kubernetes cluster or an existing Docker Daemon (for Docker Compose).",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"connection": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Connection spec for an existing cluster.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection"),
-						},
-					},
-					"defaultRegistry": {
-						SchemaProps: spec.SchemaProps{
-							Description: "DefaultRegistry determines where images for this Cluster should be pushed/pulled from if the Cluster itself does not provide local registry hosting metadata.\n\nIf not specified, no registry rewriting will occur, and the images will be pushed/pulled to from the registry specified by the corresponding image build directive (e.g. `docker_build` or `custom_build`).",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"),
-						},
-					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertRule", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ClusterStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_AlertRuleSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ClusterStatus defines the observed state of Cluster",
+				Description: "AlertRuleSpec defines what to measure, and when to fire.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"arch": {
+					"metric": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The preferred chip architecture of the cluster.\n\nOn Kubernetes, this will correspond to the kubernetes.io/arch annotation on a node.\n\nOn Docker, this will be the Architecture of the Docker daemon.\n\nNote that many clusters support multiple chipsets. This field doesn't intend that this is the only architecture a cluster supports, only that it's one of the architectures.",
+							Description: "The measurement to check.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"error": {
+					"duration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "An unrecoverable error connecting to the cluster.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "For BuildDuration and TimeToReady, fires when the resource takes longer than this.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
-					"connectedAt": {
+					"count": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ConnectedAt indicates the time at which the cluster connection was established.\n\nConsumers can use this to detect when the underlying config has changed and refresh their client/connection accordingly.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "For PodRestarts and BuildFailures, fires when there are more than this many during the Window.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"registry": {
+					"window": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Registry describes a local registry that developer tools can connect to. A local registry allows clients to load images into the local cluster by pushing to this registry.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"),
+							Description: "For PodRestarts and BuildFailures, how far back to count.\n\nDefaults to 10 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
-					"connection": {
+					"resources": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Connection status for an existing cluster.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnectionStatus"),
+							Description: "The names of the resources to check.\n\nIf empty, checks all resources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"version": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Version is a cluster-provided, human-readable version string.",
+							Description: "A message to show with the alert, like what to do about it.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"metric"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnectionStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Cmd(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_AlertRuleStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Cmd represents a process on the host machine.\n\nWhen the process exits, we will make a best-effort attempt (within OS limitations) to kill any spawned descendant processes.",
+				Description: "AlertRuleStatus defines the observed state of AlertRule",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
-						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSpec"),
-						},
-					},
-					"status": {
+					"firing": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStatus"),
+							Description: "The resources that the alert is firing for, sorted by name.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertFiring"),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.AlertFiring"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_BuildHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdImage describes an image to build with an arbitrary shell command.",
+				Description: "BuildHistory records the completed builds of a resource, across Tilt sessions.\n\nThere's one BuildHistory per resource, with the same name as the resource. Tilt persists the history on disk, so that teams can compare how long their dev loop takes from one session to the next.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -577,28 +580,28 @@ func schema_pkg_apis_core_v1alpha1_CmdImage(ref common.ReferenceCallback) common
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistorySpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistorySpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdImageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_BuildHistoryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdImageList",
+				Description: "BuildHistoryList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -628,7 +631,7 @@ func schema_pkg_apis_core_v1alpha1_CmdImageList(ref common.ReferenceCallback) co
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImage"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistory"),
 									},
 								},
 							},
@@ -639,28 +642,41 @@ func schema_pkg_apis_core_v1alpha1_CmdImageList(ref common.ReferenceCallback) co
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImage", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistory", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_BuildHistoryRecord(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdImageSpec describes how the custom script builds images and where it puts them.",
+				Description: "BuildHistoryRecord describes one completed build.\n\nA \"build\" is everything Tilt did to update the resource, including deploying it.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"ref": {
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The named reference of the image.",
-							Default:     "",
+							Description: "The time when the build started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"finishTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time when the build finished.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The error message, if the build failed.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"args": {
+					"edits": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Command-line arguments. Must have length at least 1.",
+							Description: "The files whose changes triggered the build.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -673,16 +689,16 @@ func schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref common.ReferenceCallback) co
 							},
 						},
 					},
-					"dir": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Process working directory.\n\nIf the working directory is not specified, the command is run in the default Tilt working directory.",
+							Description: "Why the build happened, e.g., \"Changed Files\" or \"Manual Trigger\".",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"imageMaps": {
+					"buildTypes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Names of image maps that this build depends on.\n\nThe controller will watch all the image maps, and rebuild the image if any of the maps resolve to a new image.",
+							Description: "The kinds of work the build did, e.g., \"image\", \"live-update\", or \"k8s\".",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -695,185 +711,162 @@ func schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref common.ReferenceCallback) co
 							},
 						},
 					},
-					"outputMode": {
+					"sessionStartTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies where the image is built. If not specified, we assume the image was built to the local Docker image store.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The start time of the Tilt session that ran the build.\n\nUse it to tell the builds of the current session from previous ones.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"outputTag": {
+				},
+				Required: []string{"startTime", "finishTime", "sessionStartTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_BuildHistorySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BuildHistorySpec is an empty struct. BuildHistory is a record of what Tilt did, not a specification of behavior.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_BuildHistoryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BuildHistoryStatus defines the observed state of BuildHistory",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"builds": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Tag we expect the image to be built with (we use this to check that the expected image+tag has been created).\n\nIf empty, we create an expected tag at the beginning of CustomBuild (and export $EXPECTED_REF=name:expected_tag )",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"outputsImageRefTo": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Specifies a filepath where the cmd script prints the result image ref.\n\nTilt will read it out when we're done to find the image.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"cluster": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The name of the cluster we're building for.\n\nWe'll use the cluster to determine the architecture of the image to build, and the registry to build it for.\n\nIf no cluster is specified, assumes the default cluster.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"clusterNeeds": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Whether the cluster needs access to the image.\n\nIf not specified, assumes we have to push up to the cluster.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Completed builds, oldest first.\n\nTilt only keeps the most recent builds of each resource.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryRecord"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"ref"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildHistoryRecord"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdImageStateBuilding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Cluster(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdImageStateBuilding expresses that an image build is in-progress.",
+				Description: "Cluster defines any runtime for running containers, in the broadest sense of the word \"runtime\".",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason why the image is building.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"startedAt": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Time when the build started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_CmdImageStateCompleted(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "CmdImageStateCompleted expresses when the image build is finished and no new images need to be built.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"reason": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason why the image was built.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"error": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error message if the build failed.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"startedAt": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time when we started building an image.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterSpec"),
 						},
 					},
-					"finishedAt": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time when we finished building an image",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdImageStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdImageStateWaiting expresses what we're waiting on to build an image.",
+				Description: "Connection spec for an existing cluster.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"kubernetes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "(brief) reason the image build is waiting.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Defines connection to a Kubernetes cluster.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnection"),
+						},
+					},
+					"docker": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Defines connection to a Docker daemon.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerClusterConnection"),
 						},
 					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnection"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdImageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ClusterConnectionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdImageStatus describes the result of the image build.",
+				Description: "Connection spec for an existing cluster.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"ref": {
-						SchemaProps: spec.SchemaProps{
-							Description: "A fully-qualified image reference of a built image, as seen from the local network.\n\nUsually includes a name and an immutable tag.\n\nNB: If we're building to a particular registry, this may have a different hostname from the Spec `Ref` field.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"waiting": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Details about a waiting image build.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateWaiting"),
-						},
-					},
-					"building": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Details about a building image.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateBuilding"),
-						},
-					},
-					"completed": {
+					"kubernetes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a finished image build.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateCompleted"),
+							Description: "Defines connection to a Kubernetes cluster.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnectionStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateBuilding", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateCompleted", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateWaiting"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesClusterConnectionStatus"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ClusterList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdList",
+				Description: "ClusterList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -903,7 +896,7 @@ func schema_pkg_apis_core_v1alpha1_CmdList(ref common.ReferenceCallback) common.
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cmd"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cluster"),
 									},
 								},
 							},
@@ -914,180 +907,155 @@ func schema_pkg_apis_core_v1alpha1_CmdList(ref common.ReferenceCallback) common.
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cmd", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cluster", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ClusterSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdSpec defines how to run a local command.",
+				Description: "ClusterSpec defines how to find the cluster we're running containers on.\n\nTilt currently supports connecting to an existing Kubernetes cluster or an existing Docker Daemon (for Docker Compose).",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"args": {
+					"connection": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Command-line arguments. Must have length at least 1.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Connection spec for an existing cluster.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection"),
 						},
 					},
-					"dir": {
+					"defaultRegistry": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Process working directory.\n\nIf the working directory is not specified, the command is run in the default Tilt working directory.",
+							Description: "DefaultRegistry determines where images for this Cluster should be pushed/pulled from if the Cluster itself does not provide local registry hosting metadata.\n\nIf not specified, no registry rewriting will occur, and the images will be pushed/pulled to from the registry specified by the corresponding image build directive (e.g. `docker_build` or `custom_build`).",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ClusterStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterStatus defines the observed state of Cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"arch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The preferred chip architecture of the cluster.\n\nOn Kubernetes, this will correspond to the kubernetes.io/arch annotation on a node.\n\nOn Docker, this will be the Architecture of the Docker daemon.\n\nNote that many clusters support multiple chipsets. This field doesn't intend that this is the only architecture a cluster supports, only that it's one of the architectures.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"env": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Additional variables process environment.\n\nExpressed as a C-style array of strings of the form [\"KEY1=VALUE1\", \"KEY2=VALUE2\", ...].\n\nEnvironment variables are layered on top of the environment variables that Tilt runs with.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "An unrecoverable error connecting to the cluster.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"readinessProbe": {
+					"connectedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Periodic probe of service readiness.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe"),
+							Description: "ConnectedAt indicates the time at which the cluster connection was established.\n\nConsumers can use this to detect when the underlying config has changed and refresh their client/connection accordingly.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"restartOn": {
+					"registry": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Indicates objects that can trigger a restart of this command.\n\nWhen a restart is triggered, Tilt will try to gracefully shutdown any currently running process, waiting for it to exit before starting a new process. If the process doesn't shutdown within the allotted time, Tilt will kill the process abruptly.\n\nRestarts can happen even if the command is already done.\n\nLogs of the current process after the restart are discarded.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec"),
+							Description: "Registry describes a local registry that developer tools can connect to. A local registry allows clients to load images into the local cluster by pushing to this registry.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"),
 						},
 					},
-					"startOn": {
+					"connection": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Indicates objects that can trigger a start/restart of this command.\n\nRestarts behave the same as RestartOn. The key difference is that a Cmd with any StartOn triggers will not have its command run until its StartOn is satisfied.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StartOnSpec"),
+							Description: "Connection status for an existing cluster.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnectionStatus"),
 						},
 					},
-					"disableSource": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies how to disable this.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
+							Description: "Version is a cluster-provided, human-readable version string.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StartOnSpec"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnectionStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Cmd(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdStateRunning is a running state of a local command.",
+				Description: "Cmd represents a process on the host machine.\n\nWhen the process exits, we will make a best-effort attempt (within OS limitations) to kill any spawned descendant processes.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"pid": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The process id of the command.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"startedAt": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time at which the command was last started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-				Required: []string{"pid"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_CmdStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "CmdStateTerminated is a terminated state of a local command.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"pid": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The process id of the command.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"exitCode": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Exit status from the last termination of the command",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"startedAt": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time at which previous execution of the command started",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"finishedAt": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time at which the command last terminated",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSpec"),
 						},
 					},
-					"reason": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "(brief) reason the process is terminated",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStatus"),
 						},
 					},
 				},
-				Required: []string{"pid", "exitCode"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdContainerTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdStateWaiting is a waiting state of a local command.",
+				Description: "CmdContainerTarget says which container a process runs in.\n\nThe process runs in the container's working directory, not the Cmd's dir, and gets only the container's env and the Cmd's own env.\n\nExactly one field must be set.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "(brief) reason the process is not yet running.",
+							Description: "The name or ID of a container on Tilt's Docker daemon.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dockerComposeService": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of a DockerComposeService object. The process runs in the service's current container, which is looked up every time the process starts.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1098,57 +1066,58 @@ func schema_pkg_apis_core_v1alpha1_CmdStateWaiting(ref common.ReferenceCallback)
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_CmdStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CmdStatus defines the observed state of Cmd\n\nBased loosely on ContainerStatus in Kubernetes",
+				Description: "CmdImage describes an image to build with an arbitrary shell command.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"waiting": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a waiting process.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateWaiting"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"running": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a running process.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateRunning"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"terminated": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a terminated process.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateTerminated"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"ready": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies whether the command has passed its readiness probe.\n\nTerminating the command does not change its Ready state.\n\nIs always true when no readiness probe is defined.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageSpec"),
 						},
 					},
-					"disableStatus": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about whether/why this is disabled.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateWaiting", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ConfigMap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdImageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ConfigMap stores unstructured data that other controllers can read and write.\n\nUseful for sharing data from one system and subscribing to it from another.",
+				Description: "CmdImageList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -1168,265 +1137,207 @@ func schema_pkg_apis_core_v1alpha1_ConfigMap(ref common.ReferenceCallback) commo
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"data": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Data contains the configuration data. Each key must consist of alphanumeric characters, '-', '_' or '.'.",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImage"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImage", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ConfigMapDisableSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Specifies a ConfigMap to control a DisableSource",
+				Description: "CmdImageSpec describes how the custom script builds images and where it puts them.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The name of the ConfigMap",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"key": {
+					"ref": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The key where the enable/disable state is stored.",
+							Description: "The named reference of the image.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"name", "key"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_ConfigMapList(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "ConfigMapList",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"kind": {
+					"args": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Command-line arguments. Must have length at least 1.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"apiVersion": {
+					"dir": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Process working directory.\n\nIf the working directory is not specified, the command is run in the default Tilt working directory.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
-						},
-					},
-					"items": {
+					"imageMaps": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Names of image maps that this build depends on.\n\nThe controller will watch all the image maps, and rebuild the image if any of the maps resolve to a new image.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMap"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-				},
-				Required: []string{"items"},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMap", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_ConfigMapStateSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Describes how a ToggleButton's state is stored in a ConfigMap. The ConfigMap must be created separately - the ToggleButton will not automatically create it.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"name": {
+					"outputMode": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the ConfigMap",
-							Default:     "",
+							Description: "Specifies where the image is built. If not specified, we assume the image was built to the local Docker image store.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"key": {
+					"outputTag": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Key within the ConfigMap",
-							Default:     "",
+							Description: "Tag we expect the image to be built with (we use this to check that the expected image+tag has been created).\n\nIf empty, we create an expected tag at the beginning of CustomBuild (and export $EXPECTED_REF=name:expected_tag )",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"onValue": {
+					"outputsImageRefTo": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ConfigMap value corresponding to the button's \"on\" state. If not specified, \"true\" will be used.",
+							Description: "Specifies a filepath where the cmd script prints the result image ref.\n\nTilt will read it out when we're done to find the image.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"offValue": {
+					"cluster": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ConfigMap value corresponding to the button's \"off\" state If not specified, \"false\" will be used.",
+							Description: "The name of the cluster we're building for.\n\nWe'll use the cluster to determine the architecture of the image to build, and the registry to build it for.\n\nIf no cluster is specified, assumes the default cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterNeeds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the cluster needs access to the image.\n\nIf not specified, assumes we have to push up to the cluster.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name", "key"},
+				Required: []string{"ref"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Container(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdImageStateBuilding(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Container is an init or application container within a pod.\n\nThe Tilt API representation mirrors the Kubernetes API very closely. Irrelevant data is not included, and some fields might be simplified.\n\nThere might also be Tilt-specific status fields.",
+				Description: "CmdImageStateBuilding expresses that an image build is in-progress.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the name of the container as defined in Kubernetes.",
-							Default:     "",
+							Description: "The reason why the image is building.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"id": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ID is the normalized container ID (the `docker://` prefix is stripped).",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Time when the build started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"ready": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Ready is true if the container is passing readiness checks (or has none defined).",
-							Default:     false,
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"image": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_CmdImageStateCompleted(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdImageStateCompleted expresses when the image build is finished and no new images need to be built.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Image is the image the container is running.",
-							Default:     "",
+							Description: "The reason why the image was built.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"restarts": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Restarts is the number of times the container has restarted.\n\nThis includes restarts before the Tilt daemon was started if the container was already running.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "Error message if the build failed.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"state": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "State provides details about the container's current condition.",
+							Description: "Time when we started building an image.",
 							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerState"),
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"ports": {
+					"finishedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Ports are exposed ports as extracted from the Pod spec.\n\nThis is added by Tilt for convenience when managing port forwards.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: 0,
-										Type:    []string{"integer"},
-										Format:  "int32",
-									},
-								},
-							},
+							Description: "Time when we finished building an image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
-				Required: []string{"name", "id", "ready", "image", "restarts", "state", "ports"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerState"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ContainerLogStreamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdImageStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ContainerLogStreamStatus defines the current status of each individual container log stream.",
+				Description: "CmdImageStateWaiting expresses what we're waiting on to build an image.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The name of the container.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"active": {
-						SchemaProps: spec.SchemaProps{
-							Description: "True when the stream is set up and streaming logs properly.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"terminated": {
-						SchemaProps: spec.SchemaProps{
-							Description: "True when the logs are done stream and the container is terminated.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"error": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The last error message encountered while streaming.\n\nEmpty when the stream is actively streaming or successfully terminated.",
+							Description: "(brief) reason the image build is waiting.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1437,200 +1348,1047 @@ func schema_pkg_apis_core_v1alpha1_ContainerLogStreamStatus(ref common.Reference
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ContainerState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdImageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ContainerState holds a possible state of container.\n\nOnly one of its members may be specified. If none of them is specified, the default one is ContainerStateWaiting.",
+				Description: "CmdImageStatus describes the result of the image build.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
+					"ref": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A fully-qualified image reference of a built image, as seen from the local network.\n\nUsually includes a name and an immutable tag.\n\nNB: If we're building to a particular registry, this may have a different hostname from the Spec `Ref` field.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"waiting": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Waiting provides details about a container that is not yet running.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateWaiting"),
+							Description: "Details about a waiting image build.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateWaiting"),
 						},
 					},
-					"running": {
+					"building": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Running provides details about a currently executing container.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateRunning"),
+							Description: "Details about a building image.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateBuilding"),
 						},
 					},
-					"terminated": {
+					"completed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Terminated provides details about an exited container.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateTerminated"),
+							Description: "Details about a finished image build.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateCompleted"),
 						},
 					},
 				},
-				Required: []string{"waiting", "running", "terminated"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateWaiting"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateBuilding", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateCompleted", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdImageStateWaiting"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ContainerStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ContainerStateRunning is a running state of a container.",
+				Description: "CmdList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startedAt": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "StartedAt is the time the container began running.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cmd"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"startedAt"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cmd", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ContainerStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdResourceUsage(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ContainerStateTerminated is a terminated state of a container.",
+				Description: "CmdResourceUsage is a sample of the resources that a running command uses.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startedAt": {
-						SchemaProps: spec.SchemaProps{
-							Description: "StartedAt is the time the container began running.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"finishedAt": {
-						SchemaProps: spec.SchemaProps{
-							Description: "FinishedAt is the time the container stopped running.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"reason": {
+					"cpuMillicores": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reason is a (brief) reason the container stopped running.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "CPU use since the previous sample, in thousandths of a core, like 1500 for one and a half cores.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"exitCode": {
+					"memoryBytes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ExitCode is the exit status from the termination of the container.\n\nAny non-zero value indicates an error during termination.",
+							Description: "Resident memory, in bytes.",
 							Default:     0,
 							Type:        []string{"integer"},
-							Format:      "int32",
+							Format:      "int64",
 						},
 					},
 				},
-				Required: []string{"startedAt", "finishedAt", "exitCode"},
+				Required: []string{"cpuMillicores", "memoryBytes"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ContainerStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdRestartStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ContainerStateWaiting is a waiting state of a container.",
+				Description: "CmdRestartStatus tracks the restarts of a process under its RestartPolicy.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"count": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reason is a (brief) reason the container is not yet running.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "How many times the process has been restarted.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"nextRestartTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When the process will be restarted, if it's waiting to restart.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
-				Required: []string{"reason"},
+				Required: []string{"count"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DisableResourceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdSSHTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Aggregated disable status of DisableSources that belong to a resource.",
+				Description: "CmdSSHTarget says how to reach the host that a process runs on over SSH.\n\nThe process runs in the same directory as it would on this machine, so the code should be at the same path on the remote host. Only the Cmd's own env is sent, not Tilt's.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"enabledCount": {
+					"host": {
 						SchemaProps: spec.SchemaProps{
-							Description: "How many of the resource's sources are enabled.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "The host and, optionally, the user and port, like \"alice@devbox:2222\". The user defaults to the local user, and the port to 22.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"disabledCount": {
+					"keyFile": {
 						SchemaProps: spec.SchemaProps{
-							Description: "How many of the resource's sources are disabled.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "A private key to log in with. Keys in the local SSH agent are tried too.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"state": {
+					"knownHostsFile": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether this is currently disabled (if known)",
-							Default:     "",
+							Description: "The known_hosts file that verifies the host's key. Defaults to ~/.ssh/known_hosts.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"sources": {
+					"forwardAgent": {
 						SchemaProps: spec.SchemaProps{
-							Description: "All unique sources that control the resource's objects' disable status.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
-									},
-								},
-							},
+							Description: "Forward the local SSH agent, so that the process can use the local keys, like to pull from git.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"enabledCount", "disabledCount", "state", "sources"},
+				Required: []string{"host"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DisableSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_CmdSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Points at a thing that can control whether something is disabled",
+				Description: "CmdSpec defines how to run a local command.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"configMap": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Disabled by single ConfigMap value.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapDisableSource"),
-						},
-					},
-					"everyConfigMap": {
+					"args": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Disabled by multiple ConfigMap values, which must all be set to disabled to disable the object.",
+							Description: "Command-line arguments. Must have length at least 1.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"dir": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Process working directory.\n\nIf the working directory is not specified, the command is run in the default Tilt working directory.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"env": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Additional variables process environment.\n\nExpressed as a C-style array of strings of the form [\"KEY1=VALUE1\", \"KEY2=VALUE2\", ...].\n\nEnvironment variables are layered on top of the environment variables that Tilt runs with.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"readinessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Periodic probe of service readiness.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe"),
+						},
+					},
+					"restartOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indicates objects that can trigger a restart of this command.\n\nWhen a restart is triggered, Tilt will try to gracefully shutdown any currently running process, waiting for it to exit before starting a new process. If the process doesn't shutdown within the allotted time, Tilt will kill the process abruptly.\n\nRestarts can happen even if the command is already done.\n\nLogs of the current process after the restart are discarded.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec"),
+						},
+					},
+					"startOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indicates objects that can trigger a start/restart of this command.\n\nRestarts behave the same as RestartOn. The key difference is that a Cmd with any StartOn triggers will not have its command run until its StartOn is satisfied.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StartOnSpec"),
+						},
+					},
+					"disableSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies how to disable this.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
+						},
+					},
+					"gracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long to wait for the process to exit after asking it to stop, before killing it.\n\nDefaults to 30s, the same as Kubernetes. Zero kills the process immediately.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"terminationSignal": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The signal that asks the process to stop, like SIGINT or SIGQUIT, for servers that don't shut down cleanly on SIGTERM.\n\nTilt sends it to the process group, then sends SIGKILL if the process hasn't exited after the grace period. Defaults to SIGTERM. Ignored on Windows.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pty": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Run the process in a pseudo-terminal, so that tools which disable colors and progress output when they aren't writing to a terminal print them anyway.\n\nThe terminal has the size of the terminal that Tilt runs in, or 80x24. Stderr is merged into stdout. Ignored on Windows.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"restartPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to start the process again after it exits, for servers that should stay up.\n\nTilt waits before each restart, starting at 1s and doubling up to 1m. Any change to the spec, or a StartOn or RestartOn trigger, resets the count. Defaults to Never.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxRestarts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The most times to restart the process under the RestartPolicy before giving up. Zero means no limit.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the process may run before Tilt stops it, so that a hung script doesn't block its resource forever.\n\nTilt stops the process the same way as on any other stop, then reports it as terminated with the reason TimedOut. Nil or zero means no timeout.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"stdin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Keep the process's stdin open, so that users can send it input, like a key that tells a dev server to reload.\n\nWithout it, the process's stdin is empty.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"readyRegex": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A regular expression that a line of the process's output must match before the process is ready, like \"listening on port [0-9]+\", for servers that take a while to start listening.\n\nLines are matched without colors or other ANSI escape codes. With a ReadinessProbe too, the probe starts once a line matches.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"envFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Files of KEY=VALUE lines, in the dotenv format, to add to the process environment, so that commands don't need a shell to source them.\n\nRelative paths are relative to Dir. Values can refer to variables in Tilt's environment or earlier in the file, like ${HOME}. Later files, then Env, take precedence.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"expandEnv": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Expand $VAR and ${VAR} in Args and Env before running the process, with Tilt's environment, the EnvFiles, and Env, like a shell would.\n\nUnset variables expand to the empty string. Use $$ for a literal $.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Where to run the process, for processes that shouldn't run on this machine, like servers that run on a shared dev box.\n\nNil runs the process on this machine.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdTarget"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdTarget", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StartOnSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_CmdStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdStateRunning is a running state of a local command.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The process id of the command.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the command was last started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources used by the process and its descendants, sampled every few seconds.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdResourceUsage"),
+						},
+					},
+					"readyMatch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The line of output that matched the ReadyRegex, once one has.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"readyAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the command first became ready, if it has.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+				},
+				Required: []string{"pid"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdResourceUsage", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_CmdStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdStateTerminated is a terminated state of a local command.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The process id of the command.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"exitCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Exit status from the last termination of the command",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which previous execution of the command started",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the command last terminated",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "(brief) reason the process is terminated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"pid", "exitCode"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_CmdStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdStateWaiting is a waiting state of a local command.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "(brief) reason the process is not yet running.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_CmdStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdStatus defines the observed state of Cmd\n\nBased loosely on ContainerStatus in Kubernetes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"waiting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about a waiting process.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateWaiting"),
+						},
+					},
+					"running": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about a running process.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateRunning"),
+						},
+					},
+					"terminated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about a terminated process.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateTerminated"),
+						},
+					},
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies whether the command has passed its readiness probe.\n\nTerminating the command does not change its Ready state.\n\nIs always true when no readiness probe is defined.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"disableStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about whether/why this is disabled.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+						},
+					},
+					"restarts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about restarts under the RestartPolicy.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdRestartStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdRestartStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdStateWaiting", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_CmdTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdTarget says where to run a process other than on this machine.\n\nExactly one field must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ssh": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Run the process on a remote host over SSH.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSSHTarget"),
+						},
+					},
+					"container": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Run the process in a running container, like `docker exec`.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdContainerTarget"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdContainerTarget", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdSSHTarget"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ConfigMap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConfigMap stores unstructured data that other controllers can read and write.\n\nUseful for sharing data from one system and subscribing to it from another.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data contains the configuration data. Each key must consist of alphanumeric characters, '-', '_' or '.'.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ConfigMapDisableSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Specifies a ConfigMap to control a DisableSource",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the ConfigMap",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The key where the enable/disable state is stored.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "key"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ConfigMapList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConfigMapList",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMap"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMap", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ConfigMapStateSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Describes how a ToggleButton's state is stored in a ConfigMap. The ConfigMap must be created separately - the ToggleButton will not automatically create it.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the ConfigMap",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key within the ConfigMap",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"onValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMap value corresponding to the button's \"on\" state. If not specified, \"true\" will be used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"offValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMap value corresponding to the button's \"off\" state If not specified, \"false\" will be used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "key"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_Container(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Container is an init or application container within a pod.\n\nThe Tilt API representation mirrors the Kubernetes API very closely. Irrelevant data is not included, and some fields might be simplified.\n\nThere might also be Tilt-specific status fields.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the container as defined in Kubernetes.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the normalized container ID (the `docker://` prefix is stripped).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ready is true if the container is passing readiness checks (or has none defined).",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the image the container is running.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"restarts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Restarts is the number of times the container has restarted.\n\nThis includes restarts before the Tilt daemon was started if the container was already running.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State provides details about the container's current condition.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerState"),
+						},
+					},
+					"ports": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ports are exposed ports as extracted from the Pod spec.\n\nThis is added by Tilt for convenience when managing port forwards.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: 0,
+										Type:    []string{"integer"},
+										Format:  "int32",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name", "id", "ready", "image", "restarts", "state", "ports"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerState"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ContainerLogStreamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerLogStreamStatus defines the current status of each individual container log stream.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the container.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"active": {
+						SchemaProps: spec.SchemaProps{
+							Description: "True when the stream is set up and streaming logs properly.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"terminated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "True when the logs are done stream and the container is terminated.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last error message encountered while streaming.\n\nEmpty when the stream is actively streaming or successfully terminated.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ContainerState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerState holds a possible state of container.\n\nOnly one of its members may be specified. If none of them is specified, the default one is ContainerStateWaiting.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"waiting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Waiting provides details about a container that is not yet running.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateWaiting"),
+						},
+					},
+					"running": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Running provides details about a currently executing container.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateRunning"),
+						},
+					},
+					"terminated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Terminated provides details about an exited container.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateTerminated"),
+						},
+					},
+				},
+				Required: []string{"waiting", "running", "terminated"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerStateWaiting"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ContainerStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerStateRunning is a running state of a container.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StartedAt is the time the container began running.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"startedAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ContainerStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerStateTerminated is a terminated state of a container.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StartedAt is the time the container began running.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FinishedAt is the time the container stopped running.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a (brief) reason the container stopped running.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"exitCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExitCode is the exit status from the termination of the container.\n\nAny non-zero value indicates an error during termination.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"startedAt", "finishedAt", "exitCode"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ContainerStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerStateWaiting is a waiting state of a container.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason is a (brief) reason the container is not yet running.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"reason"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DisableResourceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Aggregated disable status of DisableSources that belong to a resource.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabledCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How many of the resource's sources are enabled.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"disabledCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How many of the resource's sources are disabled.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether this is currently disabled (if known)",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "All unique sources that control the resource's objects' disable status.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"enabledCount", "disabledCount", "state", "sources"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DisableSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Points at a thing that can control whether something is disabled",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disabled by single ConfigMap value.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapDisableSource"),
+						},
+					},
+					"everyConfigMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disabled by multiple ConfigMap values, which must all be set to disabled to disable the object.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -1646,65 +2404,305 @@ func schema_pkg_apis_core_v1alpha1_DisableSource(ref common.ReferenceCallback) c
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapDisableSource"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapDisableSource"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DisableStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"disabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether this is currently disabled. Deprecated in favor of `State`.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time this status was updated.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reason this status was updated.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether this is currently disabled (if known)",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"disabled", "lastUpdateTime", "reason", "state"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The docker host to use.\n\nIf not specified, will read the DOCKER_HOST env or use the default docker host.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerComposeLogStream(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DockerComposeLogStream",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DockerComposeLogStreamList",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStream"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStream", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DockerComposeLogStreamSpec defines the desired state of DockerComposeLogStream",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the service to stream from.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"project": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A specification of the project the service belongs to.\n\nEach service spec keeps its own copy of the project spec.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject"),
+						},
+					},
+				},
+				Required: []string{"service", "project"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DockerComposeLogStreamStatus defines the observed state of DockerComposeLogStream",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When we last started the log streamer.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Contains an error message when the log streamer is in an error state.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DisableStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerComposeProject(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"disabled": {
+					"configPaths": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether this is currently disabled. Deprecated in favor of `State`.",
-							Default:     false,
-							Type:        []string{"boolean"},
+							Description: "Configuration files to load.\n\nIf both ConfigPaths and ProjectPath/YAML are specified, the YAML is the source of truth, and the ConfigPaths are used to print diagnostic information.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"projectPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The base path of the docker-compose project.\n\nExpressed in docker-compose as --project-directory.\n\nWhen used on the command-line, the Docker Compose spec mandates that this must be the directory of the first yaml file.  All additional yaml files are evaluated relative to this project path.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastUpdateTime": {
+					"yaml": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The last time this status was updated.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "The docker-compose config YAML.\n\nUsually contains multiple services.\n\nIf you have multiple docker-compose.yaml files, you can combine them into a single YAML with `docker-compose -f file1.yaml -f file2.yaml config`.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"reason": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason this status was updated.",
-							Default:     "",
+							Description: "The docker-compose project name.\n\nIf omitted, the default is to use the NormalizedName of the ProjectPath base name.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"state": {
+					"envFile": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether this is currently disabled (if known)",
-							Default:     "",
+							Description: "Path to an env file to use. Passed to docker-compose as `--env-file FILE`.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"disabled", "lastUpdateTime", "reason", "state"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_DockerClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"host": {
+					"profiles": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The docker host to use.\n\nIf not specified, will read the DOCKER_HOST env or use the default docker host.",
+							Description: "Profiles to enable. Passed to docker-compose as `--profile NAME`.\n\nServices without a profile are always enabled. If omitted, services from every profile are enabled.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"dockerHost": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The Docker daemon to run the project on, e.g., ssh://user@host or tcp://host:2376. Passed to docker-compose as DOCKER_HOST.\n\nIf neither DockerHost nor DockerContext is set, uses Tilt's Docker daemon.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dockerContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The Docker CLI context to run the project with. Passed to docker-compose as DOCKER_CONTEXT.\n\nCan't be set together with DockerHost.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1715,11 +2713,11 @@ func schema_pkg_apis_core_v1alpha1_DockerClusterConnection(ref common.ReferenceC
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeLogStream(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerComposeService(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeLogStream",
+				Description: "DockerComposeService represents a container orchestrated by Docker Compose.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -1745,28 +2743,28 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeLogStream(ref common.ReferenceCa
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStreamStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerComposeServiceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeLogStreamList",
+				Description: "DockerComposeServiceList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -1796,7 +2794,7 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamList(ref common.Referen
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStream"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeService"),
 									},
 								},
 							},
@@ -1807,20 +2805,20 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamList(ref common.Referen
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeLogStream", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeService", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerComposeServiceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeLogStreamSpec defines the desired state of DockerComposeLogStream",
+				Description: "DockerComposeServiceSpec defines the desired state a Docker Compose container.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"service": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the service to stream from.",
+							Description: "The name of the service to create.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
@@ -1833,89 +2831,220 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamSpec(ref common.Referen
 							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject"),
 						},
 					},
+					"imageMaps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image maps that this deploy depends on.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"disableSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies how to disable this.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
+						},
+					},
+					"upOptions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Options for how Docker Compose builds and starts the service.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeUpOptions"),
+						},
+					},
 				},
 				Required: []string{"service", "project"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeUpOptions"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeLogStreamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerComposeServiceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeLogStreamStatus defines the observed state of DockerComposeLogStream",
+				Description: "DockerComposeServiceStatus defines the observed state of DockerComposeService, continuing to watch the container after it starts.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startedAt": {
+					"disableStatus": {
 						SchemaProps: spec.SchemaProps{
-							Description: "When we last started the log streamer.",
+							Description: "Details about whether/why this is disabled.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+						},
+					},
+					"portBindings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How docker binds container ports to the host network for this service.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerPortBinding"),
+									},
+								},
+							},
+						},
+					},
+					"containerState": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Current state of the container for this service.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerContainerState"),
+						},
+					},
+					"containerID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Current container ID.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"containerName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Current container name.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"applyError": {
+						SchemaProps: spec.SchemaProps{
+							Description: "An error bringing up the container.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastApplyStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp of when we last started bringing up this service in Docker Compose.",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"error": {
+					"lastApplyFinishTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Contains an error message when the log streamer is in an error state.",
+							Description: "Timestamp of when we last finished bringing up this service in Docker Compose.\n\nWhen populated, must be equal or after the LastApplyStartTime field.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerContainerState", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerPortBinding", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerComposeUpOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Options for how Docker Compose builds and starts a service.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"withDeps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to also start the services this service depends on.\n\nBy default, Tilt starts each service on its own, like `docker-compose up --no-deps`.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"forceRecreate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to recreate the container, even if its configuration and image haven't changed. Passed to docker-compose as `--force-recreate`.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"pull": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When to pull the service's image. Can be one of \"always\", \"missing\", or \"never\". Passed to docker-compose as `--pull`.\n\nIf omitted, uses the service's pull_policy.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"buildArgs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Build args that override the ones in the service's build section, when Docker Compose builds the image.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeProject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerContainerState(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "State of a standalone container in Docker.\n\nAn apiserver-compatible representation of this struct: https://pkg.go.dev/github.com/docker/docker/api/types#ContainerState",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"configPaths": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Configuration files to load.\n\nIf both ConfigPaths and ProjectPath/YAML are specified, the YAML is the source of truth, and the ConfigPaths are used to print diagnostic information.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "String representation of the container state. Can be one of \"created\", \"running\", \"paused\", \"restarting\", \"removing\", \"exited\", or \"dead\".",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"projectPath": {
+					"running": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The base path of the docker-compose project.\n\nExpressed in docker-compose as --project-directory.\n\nWhen used on the command-line, the Docker Compose spec mandates that this must be the directory of the first yaml file.  All additional yaml files are evaluated relative to this project path.",
-							Type:        []string{"string"},
+							Description: "Whether the container is running.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"yaml": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The docker-compose config YAML.\n\nUsually contains multiple services.\n\nIf you have multiple docker-compose.yaml files, you can combine them into a single YAML with `docker-compose -f file1.yaml -f file2.yaml config`.",
+							Description: "Whether the container is in an error state.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"exitCode": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The docker-compose project name.\n\nIf omitted, the default is to use the NormalizedName of the ProjectPath base name.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The exit code, if the container has exited.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"envFile": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Path to an env file to use. Passed to docker-compose as `--env-file FILE`.",
+							Description: "When the container process started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When the container process finished.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"healthStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The result of the container's healthcheck, if it has one. Can be one of \"starting\", \"healthy\", or \"unhealthy\".",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1923,14 +3052,16 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeProject(ref common.ReferenceCall
 				},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeService represents a container orchestrated by Docker Compose.",
+				Description: "DockerImage describes an image to build with Docker.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -1956,28 +3087,28 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeService(ref common.ReferenceCall
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeServiceStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeServiceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeServiceList",
+				Description: "DockerImageList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -2007,7 +3138,7 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeServiceList(ref common.Reference
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeService"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImage"),
 									},
 								},
 							},
@@ -2018,35 +3149,56 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeServiceList(ref common.Reference
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeService", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImage", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerComposeServiceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeServiceSpec defines the desired state a Docker Compose container.",
+				Description: "DockerImageSpec describes how to build a Docker image with `docker_build`.\n\nMost fields of this spec directly correspond to the Docker CLI.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"service": {
+					"ref": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the service to create.",
+							Description: "The named reference of the image.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"project": {
+					"dockerfileContents": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A specification of the project the service belongs to.\n\nEach service spec keeps its own copy of the project spec.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject"),
+							Description: "Dockerfile contains the complete contents of the Dockerfile.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"imageMaps": {
+					"context": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The image maps that this deploy depends on.",
+							Description: "Context specifies the Docker build context.\n\nMust be an absolute path on the local filesystem.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"contextIgnores": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ignores are filters on the Docker build context.\n\nThe DockerImage controller will NOT read ignores from .dockerignore files. Instead, all filters must be expressed in this field, which covers .dockerignore files, ignore= lists in the tiltfile, only= lists in the tiltfile, and more.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"),
+									},
+								},
+							},
+						},
+					},
+					"args": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Args specifies the build arguments to the Dockerfile.\n\nEquivalent to `--build-arg` in the docker CLI.\n\nEach item should take the form \"KEY\" or \"KEY=VALUE\".",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -2059,147 +3211,175 @@ func schema_pkg_apis_core_v1alpha1_DockerComposeServiceSpec(ref common.Reference
 							},
 						},
 					},
-					"disableSource": {
+					"target": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies how to disable this.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
+							Description: "Target specifies the name of the stage in the Dockerfile to build.\n\nEquivalent to `--target` in the docker CLI.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-				Required: []string{"service", "project"},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerComposeProject"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_DockerComposeServiceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "DockerComposeServiceStatus defines the observed state of DockerComposeService, continuing to watch the container after it starts.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"disableStatus": {
+					"sshAgentConfigs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about whether/why this is disabled.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+							Description: "Pass SSH secrets to docker so it can clone private repos.\n\nhttps://docs.docker.com/develop/develop-images/build_enhancements/#using-ssh-to-access-private-data-in-builds\n\nEquivalent to `--ssh` in the docker CLI.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"portBindings": {
+					"secrets": {
 						SchemaProps: spec.SchemaProps{
-							Description: "How docker binds container ports to the host network for this service.",
+							Description: "Pass secrets to docker.\n\nhttps://docs.docker.com/develop/develop-images/build_enhancements/#new-docker-build-secret-information\n\nEquivalent to `--secret` in the Docker CLI.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerPortBinding"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"containerState": {
+					"network": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Current state of the container for this service.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerContainerState"),
+							Description: "Set the networking mode for the RUN instructions in the docker build.\n\nEquivalent to `--network` in the Docker CLI.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"containerID": {
+					"pull": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Current container ID.",
-							Type:        []string{"string"},
+							Description: "Always attempt to pull a new version of the base image.\n\nEquivalent to `--pull` in the Docker CLI.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"containerName": {
+					"cacheFrom": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Current container name.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Images to use as cache sources.\n\nEquivalent to `--cache-from` in the Docker CLI.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"applyError": {
+					"platform": {
 						SchemaProps: spec.SchemaProps{
-							Description: "An error bringing up the container.",
+							Description: "Platform specifies architecture information for target image.\n\nhttps://docs.docker.com/desktop/multi-arch/\n\nEquivalent to `--platform` in the Docker CLI.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastApplyStartTime": {
+					"extraTags": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timestamp of when we last started bringing up this service in Docker Compose.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "By default, Tilt creates a new temporary image reference for each build. The user can also specify their own reference, to integrate with other tooling (like build IDs for Jenkins build pipelines)\n\nEquivalent to the docker build --tag flag.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"lastApplyFinishTime": {
+					"imageMaps": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timestamp of when we last finished bringing up this service in Docker Compose.\n\nWhen populated, must be equal or after the LastApplyStartTime field.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "Names of image maps that this build depends on.\n\nThe controller will watch all the image maps, rebuild the image if any of the maps resolve to a new image, and inject them into the dockerfile.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"cluster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the cluster we're building for.\n\nWe'll use the cluster to determine the architecture of the image to build, and the registry to build it for.\n\nIf no cluster is specified, assumes the default cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterNeeds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the cluster needs access to the image.\n\nIf not specified, assumes we have to push up to the cluster.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"ref"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerContainerState", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerPortBinding", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerContainerState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImageStageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "State of a standalone container in Docker.\n\nAn apiserver-compatible representation of this struct: https://pkg.go.dev/github.com/docker/docker/api/types#ContainerState",
+				Description: "DockerImageStageStatus gives detailed report of each stage of the most recent image build.\n\nMost stages are derived from Buildkit's StatusResponse https://github.com/moby/buildkit/blob/35fcb28a009d6454b2915a5c8084b25ad851cf38/api/services/control/control.proto#L108 but Tilt may synthesize its own stages for the steps it owns.\n\nStages may be executed in parallel.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Description: "String representation of the container state. Can be one of \"created\", \"running\", \"paused\", \"restarting\", \"removing\", \"exited\", or \"dead\".",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"running": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Whether the container is running.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"error": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether the container is in an error state.",
+							Description: "A human-readable name of the stage.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"exitCode": {
+					"cached": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The exit code, if the container has exited.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "Whether Buildkit was able to cache the stage based on inputs.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "When the container process started.",
-							Default:     map[string]interface{}{},
+							Description: "The timestamp when we started working on the stage.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 					"finishedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "When the container process finished.",
-							Default:     map[string]interface{}{},
+							Description: "The timetsamp when we completed the work on the stage.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error message if the stage failed. If empty, the stage succeeded.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
+				Required: []string{"name"},
 			},
 		},
 		Dependencies: []string{
@@ -2207,236 +3387,196 @@ func schema_pkg_apis_core_v1alpha1_DockerContainerState(ref common.ReferenceCall
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImageStateBuilding(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImage describes an image to build with Docker.",
+				Description: "DockerImageStateBuilding expresses that an image build is in-progress.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "The reason why the image is building.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageSpec"),
-						},
-					},
-					"status": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStatus"),
+							Description: "Time when the build started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImageStateCompleted(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageList",
+				Description: "DockerImageStateCompleted expresses when the image build is finished and no new images need to be built.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "The reason why the image was built.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Error message if the build failed.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "Time when we started building an image.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"items": {
+					"finishedAt": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImage"),
-									},
-								},
-							},
+							Description: "Time when we finished building an image",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImage", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_DockerImageStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageSpec describes how to build a Docker image with `docker_build`.\n\nMost fields of this spec directly correspond to the Docker CLI.",
+				Description: "DockerImageStateWaiting expresses what we're waiting on to build an image.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"ref": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The named reference of the image.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"dockerfileContents": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Dockerfile contains the complete contents of the Dockerfile.",
+							Description: "(brief) reason the image build is waiting.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"context": {
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerImageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DockerImageStatus defines the observed state of DockerImage",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ref": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Context specifies the Docker build context.\n\nMust be an absolute path on the local filesystem.",
+							Description: "A fully-qualified image reference of a built image, as seen from the local network.\n\nUsually includes a name and an immutable tag.\n\nNB: If we're building to a particular registry, this may have a different hostname from the Spec `Ref` field.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"contextIgnores": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Ignores are filters on the Docker build context.\n\nThe DockerImage controller will NOT read ignores from .dockerignore files. Instead, all filters must be expressed in this field, which covers .dockerignore files, ignore= lists in the tiltfile, only= lists in the tiltfile, and more.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"),
-									},
-								},
-							},
-						},
-					},
-					"args": {
+					"waiting": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Args specifies the build arguments to the Dockerfile.\n\nEquivalent to `--build-arg` in the docker CLI.\n\nEach item should take the form \"KEY\" or \"KEY=VALUE\".",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Details about a waiting image build.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateWaiting"),
 						},
 					},
-					"target": {
+					"building": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Target specifies the name of the stage in the Dockerfile to build.\n\nEquivalent to `--target` in the docker CLI.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Details about a building image.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateBuilding"),
 						},
 					},
-					"sshAgentConfigs": {
+					"completed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Pass SSH secrets to docker so it can clone private repos.\n\nhttps://docs.docker.com/develop/develop-images/build_enhancements/#using-ssh-to-access-private-data-in-builds\n\nEquivalent to `--ssh` in the docker CLI.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Details about a finished image build.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateCompleted"),
 						},
 					},
-					"secrets": {
+					"stageStatuses": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Pass secrets to docker.\n\nhttps://docs.docker.com/develop/develop-images/build_enhancements/#new-docker-build-secret-information\n\nEquivalent to `--secret` in the Docker CLI.",
+							Description: "Status information about each individual build stage of the most recent image build.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStageStatus"),
 									},
 								},
 							},
 						},
 					},
-					"network": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Set the networking mode for the RUN instructions in the docker build.\n\nEquivalent to `--network` in the Docker CLI.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"pull": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStageStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateBuilding", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateCompleted", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateWaiting"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_DockerPortBinding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "How docker binds container ports to the host network",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"containerPort": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Always attempt to pull a new version of the base image.\n\nEquivalent to `--pull` in the Docker CLI.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "The port inside the container.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"cacheFrom": {
+					"hostPort": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Images to use as cache sources.\n\nEquivalent to `--cache-from` in the Docker CLI.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "The port on the host machine where Docker running.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"platform": {
+					"hostIP": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Platform specifies architecture information for target image.\n\nhttps://docs.docker.com/desktop/multi-arch/\n\nEquivalent to `--platform` in the Docker CLI.",
+							Description: "The IP on the host machine where Docker is binding the network.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"extraTags": {
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ExecAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExecAction describes a \"run in container\" action.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"command": {
 						SchemaProps: spec.SchemaProps{
-							Description: "By default, Tilt creates a new temporary image reference for each build. The user can also specify their own reference, to integrate with other tooling (like build IDs for Jenkins build pipelines)\n\nEquivalent to the docker build --tag flag.",
+							Description: "Command is the command line to execute inside the container, the working directory for the command  is root ('/') in the container's filesystem. The command is simply exec'd, it is not run inside a shell, so traditional shell instructions ('|', etc) won't work. To use a shell, you need to explicitly call out to that shell. Exit status of 0 is treated as live/healthy and non-zero is unhealthy.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -2449,284 +3589,421 @@ func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback)
 							},
 						},
 					},
-					"imageMaps": {
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_Extension(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Extension defines an extension that's evaluated on Tilt startup.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Names of image maps that this build depends on.\n\nThe controller will watch all the image maps, rebuild the image if any of the maps resolve to a new image, and inject them into the dockerfile.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"cluster": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the cluster we're building for.\n\nWe'll use the cluster to determine the architecture of the image to build, and the registry to build it for.\n\nIf no cluster is specified, assumes the default cluster.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"clusterNeeds": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether the cluster needs access to the image.\n\nIf not specified, assumes we have to push up to the cluster.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionStatus"),
 						},
 					},
 				},
-				Required: []string{"ref"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageStageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ExtensionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageStageStatus gives detailed report of each stage of the most recent image build.\n\nMost stages are derived from Buildkit's StatusResponse https://github.com/moby/buildkit/blob/35fcb28a009d6454b2915a5c8084b25ad851cf38/api/services/control/control.proto#L108 but Tilt may synthesize its own stages for the steps it owns.\n\nStages may be executed in parallel.",
+				Description: "ExtensionList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A human-readable name of the stage.",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"cached": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether Buildkit was able to cache the stage based on inputs.",
-							Type:        []string{"boolean"},
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"startedAt": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The timestamp when we started working on the stage.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-					"finishedAt": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The timetsamp when we completed the work on the stage.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"error": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error message if the stage failed. If empty, the stage succeeded.",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Extension"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Extension", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageStateBuilding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ExtensionRepo(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageStateBuilding expresses that an image build is in-progress.",
+				Description: "ExtensionRepo specifies a repo or folder where a set of extensions live.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason why the image is building.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"startedAt": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time when the build started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageStateCompleted(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ExtensionRepoList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageStateCompleted expresses when the image build is finished and no new images need to be built.",
+				Description: "ExtensionRepoList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason why the image was built.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"error": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error message if the build failed.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"startedAt": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time when we started building an image.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"finishedAt": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time when we finished building an image",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepo"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepo", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ExtensionRepoSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageStateWaiting expresses what we're waiting on to build an image.",
+				Description: "ExtensionRepoSpec defines how to access the repo.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"url": {
 						SchemaProps: spec.SchemaProps{
-							Description: "(brief) reason the image build is waiting.",
+							Description: "The URL of the repo.\n\nAllowed: https: URLs that point to a public git repo file: URLs that point to a location on disk.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ref": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A reference to sync the repo to. If empty, Tilt will always update the repo to the latest version.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"trustedKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ed25519 public keys, in base64, that extensions in the repo must be signed with.\n\nIf set, each extension must have an extension.sig file next to its Tiltfile that holds a base64 signature of its SHA-256 digest (in hex), made with one of these keys. Extensions that aren't signed won't load.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
+				Required: []string{"url"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerImageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ExtensionRepoStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DockerImageStatus defines the observed state of DockerImage",
+				Description: "ExtensionRepoStatus defines the observed state of ExtensionRepo",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"ref": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A fully-qualified image reference of a built image, as seen from the local network.\n\nUsually includes a name and an immutable tag.\n\nNB: If we're building to a particular registry, this may have a different hostname from the Spec `Ref` field.",
+							Description: "Contains information about any problems loading the repo.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"waiting": {
+					"lastFetchedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a waiting image build.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateWaiting"),
+							Description: "The last time the repo was fetched and checked for validity.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"building": {
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The path to the repo on local disk.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"checkoutRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reference that we currently have checked out. On git, this is the commit hash. On file repos, this is empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"staleReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If StaleReason is non-empty, that indicates the repo failed to fetch, but we were successfully able to use an on-disk copy.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ExtensionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExtensionSpec defines the desired state of Extension",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"repoName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a building image.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateBuilding"),
+							Description: "RepoName specifies the ExtensionRepo object where we should find this extension.\n\nThe Extension controller should watch for changes to this repo, and may update if this repo is deleted or moved.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"completed": {
+					"repoPath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a finished image build.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateCompleted"),
+							Description: "RepoPath specifies the path to the extension directory inside the repo.\n\nOnce the repo is downloaded, this path should point to a directory with a Tiltfile as the main \"entrypoint\" of the extension.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"stageStatuses": {
+					"args": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status information about each individual build stage of the most recent image build.",
+							Description: "Arguments to the Tiltfile loaded by this extension.\n\nArguments can be positional (['a', 'b', 'c']) or flag-based ('--to-edit=a'). By default, a list of arguments indicates the list of services in the tiltfile that should be enabled.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStageStatus"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A semantic version range that the extension must satisfy, like \">=1.2.0 <2.0.0\" or \"1.x\".\n\nExtensions declare their version in a VERSION file next to their Tiltfile.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sha256": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The SHA-256 digest of the extension directory, in hex.\n\nIf set, the extension won't load if its contents don't match, so that an extension can be pinned to code that's been reviewed. The digest of a loaded extension is in its status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
+				Required: []string{"repoName", "repoPath"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStageStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateBuilding", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateCompleted", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DockerImageStateWaiting"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_DockerPortBinding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ExtensionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "How docker binds container ports to the host network",
+				Description: "ExtensionStatus defines the observed state of Extension",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"containerPort": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The port inside the container.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "Contains information about any problems loading the extension.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"hostPort": {
+					"path": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The port on the host machine where Docker running.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "The path to the extension on disk. This location should be shared and readable by all Tilt instances.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"hostIP": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The IP on the host machine where Docker is binding the network.",
+							Description: "The version of the extension, from its VERSION file.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sha256": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The SHA-256 digest of the extension directory, in hex.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"verified": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the extension was signed by one of the trusted keys of its repo.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExecAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_FileEvent(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExecAction describes a \"run in container\" action.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"command": {
+					"time": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Command is the command line to execute inside the container, the working directory for the command  is root ('/') in the container's filesystem. The command is simply exec'd, it is not run inside a shell, so traditional shell instructions ('|', etc) won't work. To use a shell, you need to explicitly call out to that shell. Exit status of 0 is treated as live/healthy and non-zero is unhealthy.",
+							Description: "Time is an approximate timestamp for a batch of file changes.\n\nThis will NOT exactly match any inode attributes (e.g. ctime, mtime) at the filesystem level and is purely informational or for use as an opaque watermark.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"seenFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SeenFiles is a list of paths which changed (create, modify, or delete).",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -2740,16 +4017,19 @@ func schema_pkg_apis_core_v1alpha1_ExecAction(ref common.ReferenceCallback) comm
 						},
 					},
 				},
+				Required: []string{"time", "seenFiles"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Extension(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_FileWatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Extension defines an extension that's evaluated on Tilt startup.",
+				Description: "FileWatch",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -2775,28 +4055,28 @@ func schema_pkg_apis_core_v1alpha1_Extension(ref common.ReferenceCallback) commo
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_FileWatchList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionList",
+				Description: "FileWatchList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -2826,7 +4106,7 @@ func schema_pkg_apis_core_v1alpha1_ExtensionList(ref common.ReferenceCallback) c
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Extension"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatch"),
 									},
 								},
 							},
@@ -2837,299 +4117,355 @@ func schema_pkg_apis_core_v1alpha1_ExtensionList(ref common.ReferenceCallback) c
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Extension", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatch", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionRepo(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_FileWatchSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionRepo specifies a repo or folder where a set of extensions live.",
+				Description: "FileWatchSpec defines the desired state of FileWatch",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
-						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"metadata": {
+					"watchedPaths": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "WatchedPaths are paths of directories or files to watch for changes to. It cannot be empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"spec": {
+					"ignores": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoSpec"),
+							Description: "Ignores are optional rules to filter out a subset of changes matched by WatchedPaths.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"),
+									},
+								},
+							},
 						},
 					},
-					"status": {
+					"disableSource": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoStatus"),
+							Description: "Specifies how to disable this.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
 						},
 					},
 				},
+				Required: []string{"watchedPaths"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepoStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionRepoList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_FileWatchStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionRepoList",
+				Description: "FileWatchStatus defines the observed state of FileWatch",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
+					"monitorStartTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "MonitorStartTime is the timestamp of when filesystem monitor was started. It is zero if the monitor has not been started yet.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"metadata": {
+					"lastEventTime": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "LastEventTime is the timestamp of the most recent file event. It is zero if no events have been seen yet.\n\nIf the specifics of which files changed are not important, this field can be used as a watermark without needing to inspect FileEvents.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"items": {
+					"fileEvents": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "FileEvents summarizes batches of file changes (create, modify, or delete) that have been seen in ascending chronological order. Only the most recent 20 events are included.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepo"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileEvent"),
 									},
 								},
 							},
 						},
 					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error is set if there is a problem with the filesystem watch. If non-empty, consumers should assume that no filesystem events will be seen and that the file watcher is in a failed state.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disableStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about whether/why this is disabled.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+						},
+					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExtensionRepo", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileEvent", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionRepoSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Forward(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionRepoSpec defines how to access the repo.",
+				Description: "Forward defines a port forward to execute on a given pod.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"url": {
+					"localPort": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The URL of the repo.\n\nAllowed: https: URLs that point to a public git repo file: URLs that point to a location on disk.",
+							Description: "The port to expose on the current machine.\n\nIf not specified (or 0), a random free port will be chosen and can be discovered via the status once established.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"containerPort": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The port on the Kubernetes pod to connect to. Required.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Optional host to bind to on the current machine.\n\nIf not explicitly specified, uses the bind host of the tilt web UI (usually localhost).",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"ref": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A reference to sync the repo to. If empty, Tilt will always update the repo to the latest version.",
+							Description: "Name to identify this port forward.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path to include as part of generated links for port forward.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"url"},
+				Required: []string{"containerPort"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionRepoStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ForwardStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionRepoStatus defines the observed state of ExtensionRepo",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"error": {
+					"localPort": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Contains information about any problems loading the repo.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "LocalPort is the port bound to on the system running Tilt.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"lastFetchedAt": {
+					"containerPort": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The last time the repo was fetched and checked for validity.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "ContainerPort is the port in the container being forwarded.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"path": {
+					"addresses": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The path to the repo on local disk.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Addresses that the forwarder is bound to.\n\nFor example, a `localhost` host will bind to 127.0.0.1 and [::1].",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"checkoutRef": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reference that we currently have checked out. On git, this is the commit hash. On file repos, this is empty.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "StartedAt is the time at which the forward was initiated.\n\nIf the forwarder is not running yet, this will be zero/empty.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"staleReason": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If StaleReason is non-empty, that indicates the repo failed to fetch, but we were successfully able to use an on-disk copy.",
+							Description: "Error is a human-readable description if a problem was encountered while initializing the forward.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"localPort", "containerPort", "addresses"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_HTTPGetAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionSpec defines the desired state of Extension",
+				Description: "HTTPGetAction describes an action based on HTTP Get requests.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"repoName": {
+					"path": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RepoName specifies the ExtensionRepo object where we should find this extension.\n\nThe Extension controller should watch for changes to this repo, and may update if this repo is deleted or moved.",
-							Default:     "",
+							Description: "Path to access on the HTTP server.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"repoPath": {
+					"port": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RepoPath specifies the path to the extension directory inside the repo.\n\nOnce the repo is downloaded, this path should point to a directory with a Tiltfile as the main \"entrypoint\" of the extension.",
-							Default:     "",
+							Description: "Name or number of the port to access on the container. Number must be in the range 1 to 65535.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host name to connect to, defaults to the pod IP. You probably want to set \"Host\" in httpHeaders instead.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"args": {
+					"scheme": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Arguments to the Tiltfile loaded by this extension.\n\nArguments can be positional (['a', 'b', 'c']) or flag-based ('--to-edit=a'). By default, a list of arguments indicates the list of services in the tiltfile that should be enabled.",
+							Description: "Scheme to use for connecting to the host. Defaults to HTTP.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"httpHeaders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Custom headers to set in the request. HTTP allows repeated headers.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPHeader"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"repoName", "repoPath"},
+				Required: []string{"port"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPHeader"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ExtensionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_HTTPHeader(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ExtensionStatus defines the observed state of Extension",
+				Description: "HTTPHeader describes a custom header to be used in HTTP probes",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"error": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Contains information about any problems loading the extension.",
+							Description: "The header field name",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"path": {
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The path to the extension on disk. This location should be shared and readable by all Tilt instances.",
+							Description: "The header field value",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name", "value"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_FileEvent(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Handler(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Handler defines a specific action that should be taken in a probe.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"time": {
+					"exec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time is an approximate timestamp for a batch of file changes.\n\nThis will NOT exactly match any inode attributes (e.g. ctime, mtime) at the filesystem level and is purely informational or for use as an opaque watermark.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "One and only one of the following should be specified. Exec specifies the action to take.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction"),
 						},
 					},
-					"seenFiles": {
+					"httpGet": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SeenFiles is a list of paths which changed (create, modify, or delete).",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "HTTPGet specifies the http request to perform.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction"),
+						},
+					},
+					"tcpSocket": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TCPSocket specifies an action involving a TCP port. TCP hooks not yet supported",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"),
 						},
 					},
 				},
-				Required: []string{"time", "seenFiles"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_FileWatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_HealthProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FileWatch",
+				Description: "HealthProbe periodically checks the health of a resource with an HTTP, TCP, or exec probe.\n\nUnlike the readiness probe on a local_resource serve_cmd, a HealthProbe can be attached to any resource, including Kubernetes and Docker Compose resources. The resource isn't ready until all its probes pass, and a probe that starts failing puts the resource in an error state.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3155,28 +4491,28 @@ func schema_pkg_apis_core_v1alpha1_FileWatch(ref common.ReferenceCallback) commo
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatchStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_FileWatchList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_HealthProbeList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FileWatchList",
+				Description: "HealthProbeList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3206,7 +4542,7 @@ func schema_pkg_apis_core_v1alpha1_FileWatchList(ref common.ReferenceCallback) c
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatch"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbe"),
 									},
 								},
 							},
@@ -3217,193 +4553,109 @@ func schema_pkg_apis_core_v1alpha1_FileWatchList(ref common.ReferenceCallback) c
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileWatch", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_FileWatchSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "FileWatchSpec defines the desired state of FileWatch",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"watchedPaths": {
-						SchemaProps: spec.SchemaProps{
-							Description: "WatchedPaths are paths of directories or files to watch for changes to. It cannot be empty.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
-						},
-					},
-					"ignores": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Ignores are optional rules to filter out a subset of changes matched by WatchedPaths.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"),
-									},
-								},
-							},
-						},
-					},
-					"disableSource": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Specifies how to disable this.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
-						},
-					},
-				},
-				Required: []string{"watchedPaths"},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.IgnoreDef"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HealthProbe", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_FileWatchStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_HealthProbeSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "FileWatchStatus defines the observed state of FileWatch",
+				Description: "HealthProbeSpec defines what to probe, and which resource it belongs to.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"monitorStartTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "MonitorStartTime is the timestamp of when filesystem monitor was started. It is zero if the monitor has not been started yet.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-					"lastEventTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "LastEventTime is the timestamp of the most recent file event. It is zero if no events have been seen yet.\n\nIf the specifics of which files changed are not important, this field can be used as a watermark without needing to inspect FileEvents.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-					"fileEvents": {
-						SchemaProps: spec.SchemaProps{
-							Description: "FileEvents summarizes batches of file changes (create, modify, or delete) that have been seen in ascending chronological order. Only the most recent 20 events are included.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileEvent"),
-									},
-								},
-							},
-						},
-					},
-					"error": {
+					"resource": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error is set if there is a problem with the filesystem watch. If non-empty, consumers should assume that no filesystem events will be seen and that the file watcher is in a failed state.",
+							Description: "The name of the resource whose health the probe checks.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"disableStatus": {
+					"probe": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about whether/why this is disabled.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+							Description: "How to check the resource's health.\n\nExactly one of exec, httpGet, or tcpSocket must be set.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe"),
 						},
 					},
 				},
+				Required: []string{"resource", "probe"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.FileEvent", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Probe"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Forward(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_HealthProbeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Forward defines a port forward to execute on a given pod.",
+				Description: "HealthProbeStatus defines the observed state of HealthProbe",
 				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"localPort": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The port to expose on the current machine.\n\nIf not specified (or 0), a random free port will be chosen and can be discovered via the status once established.",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"containerPort": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The port on the Kubernetes pod to connect to. Required.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+				Properties: map[string]spec.Schema{
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the resource passes the probe, after applying the success and failure thresholds.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"host": {
+					"result": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Optional host to bind to on the current machine.\n\nIf not explicitly specified, uses the bind host of the tilt web UI (usually localhost).",
-							Default:     "",
+							Description: "The result of the most recent probe, before applying the thresholds.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name to identify this port forward.",
+							Description: "The output of the probe when its result last changed, or why it failed.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"path": {
+					"lastReadyTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Path to include as part of generated links for port forward.",
+							Description: "When the probe last went from not ready to ready.\n\nZero if the resource has never passed the probe.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Set if the probe can't run at all, like if the spec has an invalid port.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"containerPort"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ForwardStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_IgnoreDef(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Describes sets of file paths that the FileWatch should ignore.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"localPort": {
-						SchemaProps: spec.SchemaProps{
-							Description: "LocalPort is the port bound to on the system running Tilt.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"containerPort": {
+					"basePath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ContainerPort is the port in the container being forwarded.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "BasePath is the base path for the patterns. It cannot be empty.\n\nIf no patterns are specified, everything under it will be recursively ignored.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"addresses": {
+					"patterns": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Addresses that the forwarder is bound to.\n\nFor example, a `localhost` host will bind to 127.0.0.1 and [::1].",
+							Description: "Patterns are dockerignore style rules. Absolute-style patterns will be rooted to the BasePath.\n\nSee https://docs.docker.com/engine/reference/builder/#dockerignore-file.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -3416,193 +4668,271 @@ func schema_pkg_apis_core_v1alpha1_ForwardStatus(ref common.ReferenceCallback) c
 							},
 						},
 					},
-					"startedAt": {
-						SchemaProps: spec.SchemaProps{
-							Description: "StartedAt is the time at which the forward was initiated.\n\nIf the forwarder is not running yet, this will be zero/empty.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-					"error": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error is a human-readable description if a problem was encountered while initializing the forward.",
+							Description: "Reason is a human-readable explanation of where this ignore came from and why it exists (e.g., \".tiltignore\" or \"generated protobuf code\").\n\nUsed when debugging why a file change did not trigger an update.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"localPort", "containerPort", "addresses"},
+				Required: []string{"basePath"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_HTTPGetAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ImageMap(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "HTTPGetAction describes an action based on HTTP Get requests.",
+				Description: "ImageMap expresses the mapping from an image reference to a real, pushed image in an image registry that a container runtime can access.\n\nAnother way to think about the ImageMap is that ImageMapSpec is a mutable image reference (where the image might not exist yet), but ImageMapStatus is an immutable image reference (where, if an image is specified, it always exists).\n\nImageMap does not specify how the image is built or who is responsible for building this. But any API that builds images should produce an ImageMap.\n\nFor example, a builder that builds to a local image registry might create a map from: 'my-apiserver:dev' to 'localhost:5000/my-apiserver:content-based-label'.\n\nImageMap doesn't follow the usual Kubernetes-style API semantics (where the Status is the result of running the Spec). It's closer to a ConfigMap. Though the Status does represent a real runtime result (an image in a registry).",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"path": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Path to access on the HTTP server.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"port": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name or number of the port to access on the container. Number must be in the range 1 to 65535.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"host": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Host name to connect to, defaults to the pod IP. You probably want to set \"Host\" in httpHeaders instead.",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ImageMapList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageMapList",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"scheme": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Scheme to use for connecting to the host. Defaults to HTTP.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"httpHeaders": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Custom headers to set in the request. HTTP allows repeated headers.",
-							Type:        []string{"array"},
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPHeader"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMap"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"port"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPHeader"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMap", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_HTTPHeader(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ImageMapOverrideArgs(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "HTTPHeader describes a custom header to be used in HTTP probes",
+				Description: "ImageMapArgsOverride defines args to inject when the image is injected. Only applies to types that embed a v1.Container with a Command field.\n\nhttps://pkg.go.dev/k8s.io/api/core/v1#Container",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"args": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The header field name",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "A list of args strings.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"value": {
+				},
+				Required: []string{"args"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ImageMapOverrideCommand(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageMapCommandOverride defines a command to inject when the image is injected. Only applies to types that embed a v1.Container with a Command field.\n\nhttps://pkg.go.dev/k8s.io/api/core/v1#Container",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"command": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The header field value",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "A list of command strings.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name", "value"},
+				Required: []string{"command"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Handler(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ImageMapSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Handler defines a specific action that should be taken in a probe.",
+				Description: "ImageMapSpec defines the desired state of ImageMap",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"exec": {
+					"selector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "One and only one of the following should be specified. Exec specifies the action to take.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction"),
+							Description: "A named image reference.\n\nDeployment tools expect this image reference to match an image in the YAML being deployed, and will replace that image reference.\n\nBy default, this selector will match an image if the names match (tags on both the selector and the matched reference are ignored).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"httpGet": {
+					"matchExact": {
 						SchemaProps: spec.SchemaProps{
-							Description: "HTTPGet specifies the http request to perform.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction"),
+							Description: "If specified, then tags on both the selector and the matched reference are used for matching. The selector will only match the reference if the tags match exactly.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"tcpSocket": {
+					"matchInEnvVars": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TCPSocket specifies an action involving a TCP port. TCP hooks not yet supported",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"),
+							Description: "If specified, then the selector will also match any strings in container env variables.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"overrideCommand": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the injector will replace the 'command' field in the container when it replaces the image.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideCommand"),
+						},
+					},
+					"overrideArgs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the injector will replace the 'args' field in the container when it replaces the image.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideArgs"),
 						},
 					},
 				},
+				Required: []string{"selector"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideArgs", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideCommand"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_IgnoreDef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ImageMapStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Describes sets of file paths that the FileWatch should ignore.",
+				Description: "ImageMapStatus defines the observed state of ImageMap",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"basePath": {
+					"image": {
 						SchemaProps: spec.SchemaProps{
-							Description: "BasePath is the base path for the patterns. It cannot be empty.\n\nIf no patterns are specified, everything under it will be recursively ignored.",
-							Default:     "",
+							Description: "A fully-qualified image reference, including a name and an immutable tag, as seen from the cluster container runtime that we're mapping this image to.\n\nNB: Container images often need to be referenced from different networks, including:\n\n1) The cluster container runtime 2) The local network 3) The cluster network\n\nAnd each of these cases may have distinct URLs. We might deprecate this field and only use fields of the form `ImageFromX`.\n\nFor more details on image references in different networks, see: https://github.com/kubernetes/enhancements/tree/master/keps/sig-cluster-lifecycle/generic/1755-communicating-a-local-registry#specification-for-localregistryhosting-v1",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageFromLocal": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A fully-qualified image reference, including a name and an immutable tag, as seen from the local network.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageFromCluster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A fully-qualified image reference, including a name and an immutable tag, as seen from the cluster container runtime.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"patterns": {
+					"buildStartTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Patterns are dockerignore style rules. Absolute-style patterns will be rooted to the BasePath.\n\nSee https://docs.docker.com/engine/reference/builder/#dockerignore-file.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Timestamp indicating when the image started building.\n\nIntended to be used to determine which file changes were picked up by the image build. We can assume that any file changes before this timestamp were definitely included in the image, and any file changes after this timestamp may not be included in the image.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
-				Required: []string{"basePath"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ImageMap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesApply(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMap expresses the mapping from an image reference to a real, pushed image in an image registry that a container runtime can access.\n\nAnother way to think about the ImageMap is that ImageMapSpec is a mutable image reference (where the image might not exist yet), but ImageMapStatus is an immutable image reference (where, if an image is specified, it always exists).\n\nImageMap does not specify how the image is built or who is responsible for building this. But any API that builds images should produce an ImageMap.\n\nFor example, a builder that builds to a local image registry might create a map from: 'my-apiserver:dev' to 'localhost:5000/my-apiserver:content-based-label'.\n\nImageMap doesn't follow the usual Kubernetes-style API semantics (where the Status is the result of running the Spec). It's closer to a ConfigMap. Though the Status does represent a real runtime result (an image in a registry).",
+				Description: "KubernetesApply specifies a blob of YAML to apply, and a set of ImageMaps that the YAML depends on.\n\nThe KubernetesApply controller will resolve the ImageMaps into immutable image references. The controller will process the spec YAML, then apply it to the cluster. Those processing steps might include:\n\n- Injecting the resolved image references. - Adding custom labels so that Tilt can track the progress of the apply. - Modifying image pull rules to ensure the image is pulled correctly.\n\nThe controller won't apply anything until all ImageMaps resolve to real images.\n\nThe controller will watch all the image maps, and redeploy the entire YAML if any of the maps resolve to a new image.\n\nThe status field will contain both the raw applied object, and derived fields to help other controllers figure out how to watch the apply progress.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3628,28 +4958,79 @@ func schema_pkg_apis_core_v1alpha1_ImageMap(ref common.ReferenceCallback) common
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplySpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplySpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ImageMapList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyCmd(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMapList",
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"args": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Args are the command-line arguments for the apply command. Must have length >= 1.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"dir": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Process working directory.\n\nIf not specified, will default to Tilt working directory.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"env": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Env are additional variables for the process environment.\n\nEnvironment variables are layered on top of the environment variables that Tilt runs with.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"args"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubernetesApplyList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3679,7 +5060,7 @@ func schema_pkg_apis_core_v1alpha1_ImageMapList(ref common.ReferenceCallback) co
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMap"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApply"),
 									},
 								},
 							},
@@ -3690,20 +5071,27 @@ func schema_pkg_apis_core_v1alpha1_ImageMapList(ref common.ReferenceCallback) co
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMap", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApply", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ImageMapOverrideArgs(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMapArgsOverride defines args to inject when the image is injected. Only applies to types that embed a v1.Container with a Command field.\n\nhttps://pkg.go.dev/k8s.io/api/core/v1#Container",
+				Description: "KubernetesApplySpec defines the desired state of KubernetesApply",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"args": {
+					"yaml": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of args strings.",
+							Description: "YAML to apply to the cluster.\n\nExactly one of YAML OR ApplyCmd MUST be provided.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageMaps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Names of image maps that this applier depends on.\n\nThe controller will watch all the image maps, and redeploy the entire YAML if any of the maps resolve to a new image.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -3716,139 +5104,291 @@ func schema_pkg_apis_core_v1alpha1_ImageMapOverrideArgs(ref common.ReferenceCall
 							},
 						},
 					},
+					"imageLocators": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Descriptors of how to find images in the YAML.\n\nNeeded when injecting images into CRDs.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageLocator"),
+									},
+								},
+							},
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The timeout on the apply operation.\n\nWe've had problems with both: 1) CRD apiservers that take an arbitrarily long time to apply, and 2) Infinite loops in the apimachinery So we offer the ability to set a timeout on Kubernetes apply operations.\n\nThe default timeout is 30s.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"kubernetesDiscoveryTemplateSpec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KubernetesDiscoveryTemplateSpec describes how we discover pods for resources created by this Apply.\n\nIf not specified, the KubernetesDiscovery controller will listen to all pods, and follow owner references to find the pods owned by these resources.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryTemplateSpec"),
+						},
+					},
+					"portForwardTemplateSpec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PortForwardTemplateSpec describes the data model for port forwards that KubernetesApply should set up.\n\nUnderneath the hood, we'll create a KubernetesDiscovery object that finds the pods and sets up the port-forwarding. Only one PortForward will be active at a time.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec"),
+						},
+					},
+					"podLogStreamTemplateSpec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodLogStreamTemplateSpec describes the data model for PodLogStreams that KubernetesApply should set up.\n\nUnderneath the hood, we'll create a KubernetesDiscovery object that finds the pods and sets up the pod log streams.\n\nIf no template is specified, the controller will stream all pod logs available from the apiserver.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec"),
+						},
+					},
+					"discoveryStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DiscoveryStrategy describes how we set up pod watches for the applied resources. This affects all systems that attach to pods, including PortForwards, PodLogStreams, resource readiness, and live-updates.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disableSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies how to disable this.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
+						},
+					},
+					"applyCmd": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplyCmd is a custom command to execute to deploy entities to the Kubernetes cluster.\n\nThe command must be idempotent, e.g. it must not fail if some or all entities already exist.\n\nThe ApplyCmd MUST return valid Kubernetes YAML for the entities it applied to the cluster.\n\nExactly one of YAML OR ApplyCmd MUST be provided.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyCmd"),
+						},
+					},
+					"restartOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestartOn determines external triggers that will result in an apply.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec"),
+						},
+					},
+					"deleteCmd": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeleteCmd is a custom command to execute to delete entities created by ApplyCmd and clean up any additional state.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyCmd"),
+						},
+					},
+					"cluster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cluster name to determine the Kubernetes cluster.\n\nIf not provided, \"default\" will be used.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
-				Required: []string{"args"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyCmd", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageLocator", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ImageMapOverrideCommand(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMapCommandOverride defines a command to inject when the image is injected. Only applies to types that embed a v1.Container with a Command field.\n\nhttps://pkg.go.dev/k8s.io/api/core/v1#Container",
+				Description: "KubernetesApplyStatus defines the observed state of KubernetesApply",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"command": {
+					"resultYAML": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of command strings.",
+							Description: "The result of applying the YAML to the cluster. This should contain UIDs for the applied resources.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "An error applying the YAML.\n\nIf there was an error, than ResultYAML should be empty (and vice versa).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastApplyTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp of we last finished applying this YAML to the cluster.\n\nWhen populated, must be equal or after the LastApplyStartTime field.\n\nis more consistent with how we name this in other API objects.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"lastApplyStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp of when we last started applying this YAML to the cluster.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"appliedInputHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A base64-encoded hash of all the inputs to the apply.\n\nWe added this so that more procedural code can determine whether their updates have been applied yet or not by the reconciler. But any code using it this way should note that the reconciler may \"skip\" an update (e.g., if two images get updated in quick succession before the reconciler injects them into the YAML), so a particular ApplieInputHash might never appear.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disableStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about whether/why this is disabled.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions based on the result of the apply.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"command"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"context": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the kubeconfig context to use.\n\nIf not specified, will use the default context in the kubeconfig.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The default namespace to use.\n\nIf not specified, will use the namespace in the kubeconfig.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ImageMapSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnectionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMapSpec defines the desired state of ImageMap",
+				Description: "Kubernetes-specific fields for connection status",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"selector": {
+					"context": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A named image reference.\n\nDeployment tools expect this image reference to match an image in the YAML being deployed, and will replace that image reference.\n\nBy default, this selector will match an image if the names match (tags on both the selector and the matched reference are ignored).",
+							Description: "The resolved kubeconfig context.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"matchExact": {
+					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, then tags on both the selector and the matched reference are used for matching. The selector will only match the reference if the tags match exactly.",
-							Type:        []string{"boolean"},
+							Description: "The resolved default namespace.",
+							Default:     "",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"matchInEnvVars": {
+					"cluster": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, then the selector will also match any strings in container env variables.",
-							Type:        []string{"boolean"},
+							Description: "The resolved cluster name (as determined by the kubeconfig context).",
+							Default:     "",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"overrideCommand": {
+					"product": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the injector will replace the 'command' field in the container when it replaces the image.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideCommand"),
+							Description: "The product name for this cluster.\n\nFor a complete list of possible product names, see: https://pkg.go.dev/github.com/tilt-dev/clusterid#Product",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"overrideArgs": {
+					"configPath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the injector will replace the 'args' field in the container when it replaces the image.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideArgs"),
+							Description: "The resolved config path.\n\nTilt will freeze the config and write it to a temporary directory. Subprocesses that depend on this cluster can find this file by reading the KUBECONFIG env var.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"selector"},
+				Required: []string{"context", "namespace", "cluster"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideArgs", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ImageMapOverrideCommand"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ImageMapStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscovery(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ImageMapStatus defines the observed state of ImageMap",
+				Description: "KubernetesDiscovery",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"image": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A fully-qualified image reference, including a name and an immutable tag, as seen from the cluster container runtime that we're mapping this image to.\n\nNB: Container images often need to be referenced from different networks, including:\n\n1) The cluster container runtime 2) The local network 3) The cluster network\n\nAnd each of these cases may have distinct URLs. We might deprecate this field and only use fields of the form `ImageFromX`.\n\nFor more details on image references in different networks, see: https://github.com/kubernetes/enhancements/tree/master/keps/sig-cluster-lifecycle/generic/1755-communicating-a-local-registry#specification-for-localregistryhosting-v1",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"imageFromLocal": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A fully-qualified image reference, including a name and an immutable tag, as seen from the local network.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"imageFromCluster": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A fully-qualified image reference, including a name and an immutable tag, as seen from the cluster container runtime.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"buildStartTime": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timestamp indicating when the image started building.\n\nIntended to be used to determine which file changes were picked up by the image build. We can assume that any file changes before this timestamp were definitely included in the image, and any file changes after this timestamp may not be included in the image.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoverySpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoverySpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesApply(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesApply specifies a blob of YAML to apply, and a set of ImageMaps that the YAML depends on.\n\nThe KubernetesApply controller will resolve the ImageMaps into immutable image references. The controller will process the spec YAML, then apply it to the cluster. Those processing steps might include:\n\n- Injecting the resolved image references. - Adding custom labels so that Tilt can track the progress of the apply. - Modifying image pull rules to ensure the image is pulled correctly.\n\nThe controller won't apply anything until all ImageMaps resolve to real images.\n\nThe controller will watch all the image maps, and redeploy the entire YAML if any of the maps resolve to a new image.\n\nThe status field will contain both the raw applied object, and derived fields to help other controllers figure out how to watch the apply progress.",
+				Description: "KubernetesDiscoveryList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3868,329 +5408,380 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApply(ref common.ReferenceCallback)
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplySpec"),
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"status": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyStatus"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscovery"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplySpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscovery", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesApplyCmd(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscoverySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "KubernetesDiscoverySpec defines the desired state of KubernetesDiscovery",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"args": {
+					"watches": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Args are the command-line arguments for the apply command. Must have length >= 1.",
+							Description: "Watches determine what resources are discovered.\n\nIf a discovered resource (e.g. Pod) matches the KubernetesWatchRef UID exactly, it will be reported. If a discovered resource is transitively owned by the KubernetesWatchRef UID, it will be reported.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesWatchRef"),
 									},
 								},
 							},
 						},
 					},
-					"dir": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Process working directory.\n\nIf not specified, will default to Tilt working directory.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"env": {
+					"extraSelectors": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Env are additional variables for the process environment.\n\nEnvironment variables are layered on top of the environment variables that Tilt runs with.",
+							Description: "ExtraSelectors are label selectors that will force discovery of a Pod even if it does not match the AncestorUID.\n\nThis should only be necessary in the event that a CRD creates Pods but does not set an owner reference to itself.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 									},
 								},
 							},
 						},
 					},
+					"portForwardTemplateSpec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PortForwardTemplateSpec describes the data model for port forwards that KubernetesDiscovery should set up.\n\nThe KubernetesDiscovery controller will choose a \"best\" candidate for attaching the port-forwarding. Only one PortForward will be active at a time.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec"),
+						},
+					},
+					"podLogStreamTemplateSpec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodLogStreamTemplateSpec describes the data model for PodLogStreams that KubernetesDiscovery should set up.\n\nThe KubernetesDiscovery controller will attach PodLogStream objects to all active pods it discovers.\n\nIf no template is specified, the controller will stream all pod logs available from the apiserver.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec"),
+						},
+					},
+					"cluster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cluster name to determine the Kubernetes cluster.\n\nIf not provided, \"default\" will be used.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
-				Required: []string{"args"},
+				Required: []string{"watches"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesWatchRef", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesApplyList",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "StartTime is when Kubernetes resource discovery began.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"apiVersion": {
+				},
+				Required: []string{"startTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Reason the monitor has not yet been started.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
-						},
-					},
-					"items": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApply"),
-									},
-								},
-							},
-						},
-					},
 				},
-				Required: []string{"items"},
+				Required: []string{"reason"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApply", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesApplySpec defines the desired state of KubernetesApply",
+				Description: "KubernetesDiscoveryStatus defines the observed state of KubernetesDiscovery",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"yaml": {
+					"monitorStartTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "YAML to apply to the cluster.\n\nExactly one of YAML OR ApplyCmd MUST be provided.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "MonitorStartTime is the timestamp of when Kubernetes resource discovery was started.\n\nIt is zero if discovery has not been started yet.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"imageMaps": {
+					"pods": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Names of image maps that this applier depends on.\n\nThe controller will watch all the image maps, and redeploy the entire YAML if any of the maps resolve to a new image.",
+							Description: "Pods that have been discovered based on the criteria in the spec.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Pod"),
 									},
 								},
 							},
 						},
 					},
-					"imageLocators": {
+					"waiting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Waiting contains information about why the monitor has not started.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateWaiting"),
+						},
+					},
+					"running": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Running contains information about the currently running monitor.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateRunning"),
+						},
+					},
+				},
+				Required: []string{"pods"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateWaiting", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Pod", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"extraSelectors": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Descriptors of how to find images in the YAML.\n\nNeeded when injecting images into CRDs.",
+							Description: "ExtraSelectors are label selectors that will force discovery of a Pod even if it does not match the AncestorUID.\n\nThis should only be necessary in the event that a CRD creates Pods but does not set an owner reference to itself.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageLocator"),
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 									},
 								},
 							},
 						},
 					},
-					"timeout": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The timeout on the apply operation.\n\nWe've had problems with both: 1) CRD apiservers that take an arbitrarily long time to apply, and 2) Infinite loops in the apimachinery So we offer the ability to set a timeout on Kubernetes apply operations.\n\nThe default timeout is 30s.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
-						},
-					},
-					"kubernetesDiscoveryTemplateSpec": {
-						SchemaProps: spec.SchemaProps{
-							Description: "KubernetesDiscoveryTemplateSpec describes how we discover pods for resources created by this Apply.\n\nIf not specified, the KubernetesDiscovery controller will listen to all pods, and follow owner references to find the pods owned by these resources.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryTemplateSpec"),
-						},
-					},
-					"portForwardTemplateSpec": {
-						SchemaProps: spec.SchemaProps{
-							Description: "PortForwardTemplateSpec describes the data model for port forwards that KubernetesApply should set up.\n\nUnderneath the hood, we'll create a KubernetesDiscovery object that finds the pods and sets up the port-forwarding. Only one PortForward will be active at a time.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec"),
-						},
-					},
-					"podLogStreamTemplateSpec": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesImageLocator(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Finds image references in Kubernetes YAML.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"objectSelector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PodLogStreamTemplateSpec describes the data model for PodLogStreams that KubernetesApply should set up.\n\nUnderneath the hood, we'll create a KubernetesDiscovery object that finds the pods and sets up the pod log streams.\n\nIf no template is specified, the controller will stream all pod logs available from the apiserver.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec"),
+							Description: "Selects which objects to look in.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ObjectSelector"),
 						},
 					},
-					"discoveryStrategy": {
+					"path": {
 						SchemaProps: spec.SchemaProps{
-							Description: "DiscoveryStrategy describes how we set up pod watches for the applied resources. This affects all systems that attach to pods, including PortForwards, PodLogStreams, resource readiness, and live-updates.",
+							Description: "A JSON path to the image reference field.\n\nIf Object is empty, the field should be a string.\n\nIf Object is non-empty, the field should be an object with subfields.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"disableSource": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Specifies how to disable this.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
-						},
-					},
-					"applyCmd": {
-						SchemaProps: spec.SchemaProps{
-							Description: "ApplyCmd is a custom command to execute to deploy entities to the Kubernetes cluster.\n\nThe command must be idempotent, e.g. it must not fail if some or all entities already exist.\n\nThe ApplyCmd MUST return valid Kubernetes YAML for the entities it applied to the cluster.\n\nExactly one of YAML OR ApplyCmd MUST be provided.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyCmd"),
-						},
-					},
-					"restartOn": {
+					"object": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RestartOn determines external triggers that will result in an apply.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec"),
+							Description: "A descriptor of the path and structure of an object that describes an image reference. This is a common way to describe images in CRDs, breaking them down into an object rather than an image reference string.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageObjectDescriptor"),
 						},
 					},
-					"deleteCmd": {
+				},
+				Required: []string{"objectSelector", "path"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageObjectDescriptor", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ObjectSelector"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesImageObjectDescriptor(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"repoField": {
 						SchemaProps: spec.SchemaProps{
-							Description: "DeleteCmd is a custom command to execute to delete entities created by ApplyCmd and clean up any additional state.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyCmd"),
+							Description: "The name of the field that contains the image repository.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"cluster": {
+					"tagField": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Cluster name to determine the Kubernetes cluster.\n\nIf not provided, \"default\" will be used.",
+							Description: "The name of the field that contains the image tag.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"repoField", "tagField"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesApplyCmd", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageLocator", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesApplyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_KubernetesWatchRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesApplyStatus defines the observed state of KubernetesApply",
+				Description: "KubernetesWatchRef is similar to v1.ObjectReference from the Kubernetes API and is used to determine what objects should be reported on based on discovery.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"resultYAML": {
+					"uid": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The result of applying the YAML to the cluster. This should contain UIDs for the applied resources.",
+							Description: "UID is a Kubernetes object UID.\n\nIt should either be the exact object UID or the transitive owner.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"error": {
+					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "An error applying the YAML.\n\nIf there was an error, than ResultYAML should be empty (and vice versa).",
+							Description: "Namespace is the Kubernetes namespace for discovery. Required.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastApplyTime": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timestamp of we last finished applying this YAML to the cluster.\n\nWhen populated, must be equal or after the LastApplyStartTime field.\n\nis more consistent with how we name this in other API objects.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "Name is the Kubernetes object name.\n\nThis is not directly used in discovery; it is extra metadata.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastApplyStartTime": {
+				},
+				Required: []string{"namespace"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_LiveUpdate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LiveUpdate",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timestamp of when we last started applying this YAML to the cluster.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"appliedInputHash": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A base64-encoded hash of all the inputs to the apply.\n\nWe added this so that more procedural code can determine whether their updates have been applied yet or not by the reconciler. But any code using it this way should note that the reconciler may \"skip\" an update (e.g., if two images get updated in quick succession before the reconciler injects them into the YAML), so a particular ApplieInputHash might never appear.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"disableStatus": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about whether/why this is disabled.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"conditions": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Conditions based on the result of the apply.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
-									},
-								},
-							},
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"context": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the kubeconfig context to use.\n\nIf not specified, will use the default context in the kubeconfig.",
+							Description: "One word camel-case reason why we're in a waiting state.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"namespace": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The default namespace to use.\n\nIf not specified, will use the namespace in the kubeconfig.",
+							Description: "Human-readable description of what's blocking.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -4201,16 +5792,31 @@ func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnection(ref common.Refere
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnectionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Kubernetes-specific fields for connection status",
+				Description: "LiveUpdateContainerStatus defines the observed state of the live-update syncer for a particular container.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"context": {
+					"containerName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The resolved kubeconfig context.",
+							Description: "The name of the container in the pod.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"containerID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ID of the container in the pod, in the format 'docker://<container_id>'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the pod this container belongs to.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
@@ -4218,93 +5824,162 @@ func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnectionStatus(ref common.
 					},
 					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The resolved default namespace.",
+							Description: "The namespace of the pod this container belongs to.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"cluster": {
+					"lastFileTimeSynced": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The timestamp of the most recent file update successfully synced to the container.\n\nMust match the timestamp in a FileEvent, not the time the sync was performed.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"lastExecError": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Contains any error messages from the most recent sequence of Execs.\n\nEmpty if the most recent Execs completed successfully.\n\nAn ExecError is not necessarily a failure state. For example, a linter error in the container is something we'd want to surface to the user, but not an indication that the live-updater did something wrong.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"waiting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Details about a waiting live update.\n\nA live update is waiting when the reconciler is aware of file changes that need to be synced to the container, but has decided not to sync them yet.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStateWaiting"),
+						},
+					},
+				},
+				Required: []string{"containerName", "podName", "namespace"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStateWaiting", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_LiveUpdateDockerComposeSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Specifies how to select containers to live update inside Docker Compose.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"service": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The resolved cluster name (as determined by the kubeconfig context).",
+							Description: "The name of a DockerComposeService object.\n\nFor simple projects, this is usually the same as the service name in the docker-compose.yml file. (But it doesn't necessarily have to be.)",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"product": {
+				},
+				Required: []string{"service"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_LiveUpdateExec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Runs a remote command after files have been synced to the container. Commonly used for small in-container changes (like moving files around, or restart processes).",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"args": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The product name for this cluster.\n\nFor a complete list of possible product names, see: https://pkg.go.dev/github.com/tilt-dev/clusterid#Product",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Command-line arguments to run inside the container. Must have length at least 1.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"configPath": {
+					"triggerPaths": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The resolved config path.\n\nTilt will freeze the config and write it to a temporary directory. Subprocesses that depend on this cluster can find this file by reading the KUBECONFIG env var.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "A list of relative paths that trigger this command exec.\n\nIf not specified, all file changes seen by the LiveUpdate trigger this exec.\n\nPaths are specified relative to the the BasePath of the LiveUpdate.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"context", "namespace", "cluster"},
+				Required: []string{"args"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscovery(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateKubernetesSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesDiscovery",
+				Description: "Specifies how to select containers to live update inside K8s.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"discoveryName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "The name of a KubernetesDiscovery object for finding pods.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"applyName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "ApplyName is the name of a KubernetesApply object for filtering discovered pods to prevent updating old deployments.\n\nIf not provided, no filtering will be applied and all discovered Pods will be eligible for update.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"image": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Image specifies the name of the image that we're copying files into.\n\nDetermines which containers in a pod to live-update. Matches images by name unless tag is explicitly specified.\n\nExactly one of Image, ContainerName, or ImageMapName MUST be specified.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"spec": {
+					"containerName": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoverySpec"),
+							Description: "ContainerName specifies the name of the container that we're copying files into.\n\nExactly one of Image, ContainerName, or ImageMapName MUST be specified.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"imageMapName": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStatus"),
+							Description: "ImageMapName specifies the name of an ImageMapName object to use for determining the image we're copying files into.\n\nExactly one of Image, ContainerName, or ImageMapName MUST be specified.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"discoveryName"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoverySpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesDiscoveryList",
+				Description: "LiveUpdateList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -4334,7 +6009,7 @@ func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryList(ref common.ReferenceC
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscovery"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdate"),
 									},
 								},
 							},
@@ -4345,301 +6020,265 @@ func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryList(ref common.ReferenceC
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscovery", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdate", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscoverySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesDiscoverySpec defines the desired state of KubernetesDiscovery",
+				Description: "Specifies how to select containers to live update.\n\nEvery live update must be associated with some object for finding containers. In the future, we expect there to be other types of container discovery objects (like Docker Compose container discovery).",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"watches": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Watches determine what resources are discovered.\n\nIf a discovered resource (e.g. Pod) matches the KubernetesWatchRef UID exactly, it will be reported. If a discovered resource is transitively owned by the KubernetesWatchRef UID, it will be reported.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesWatchRef"),
-									},
-								},
-							},
-						},
-					},
-					"extraSelectors": {
-						SchemaProps: spec.SchemaProps{
-							Description: "ExtraSelectors are label selectors that will force discovery of a Pod even if it does not match the AncestorUID.\n\nThis should only be necessary in the event that a CRD creates Pods but does not set an owner reference to itself.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
-									},
-								},
-							},
-						},
-					},
-					"portForwardTemplateSpec": {
-						SchemaProps: spec.SchemaProps{
-							Description: "PortForwardTemplateSpec describes the data model for port forwards that KubernetesDiscovery should set up.\n\nThe KubernetesDiscovery controller will choose a \"best\" candidate for attaching the port-forwarding. Only one PortForward will be active at a time.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec"),
-						},
-					},
-					"podLogStreamTemplateSpec": {
+					"kubernetes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PodLogStreamTemplateSpec describes the data model for PodLogStreams that KubernetesDiscovery should set up.\n\nThe KubernetesDiscovery controller will attach PodLogStream objects to all active pods it discovers.\n\nIf no template is specified, the controller will stream all pod logs available from the apiserver.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec"),
+							Description: "Finds containers in Kubernetes.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateKubernetesSelector"),
 						},
 					},
-					"cluster": {
+					"dockerCompose": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Cluster name to determine the Kubernetes cluster.\n\nIf not provided, \"default\" will be used.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Finds containers in Docker Compose.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateDockerComposeSelector"),
 						},
 					},
 				},
-				Required: []string{"watches"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesWatchRef", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamTemplateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateDockerComposeSelector", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateKubernetesSelector"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Specifies how to pull in files.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startTime": {
+					"fileWatch": {
 						SchemaProps: spec.SchemaProps{
-							Description: "StartTime is when Kubernetes resource discovery began.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "The name of a FileWatch to use as a file source.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-				Required: []string{"startTime"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"reason": {
+					"imageMap": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reason the monitor has not yet been started.",
-							Default:     "",
+							Description: "Name of the ImageMap object to watch for which file changes from this source are included in the container image.\n\nIf not provided, the live-updater will copy any file changes that it's aware of, even if they're already included in the container.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"reason"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesDiscoveryStatus defines the observed state of KubernetesDiscovery",
+				Description: "LiveUpdateSpec defines the desired state of LiveUpdate",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"monitorStartTime": {
+					"basePath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "MonitorStartTime is the timestamp of when Kubernetes resource discovery was started.\n\nIt is zero if discovery has not been started yet.",
+							Description: "An absolute local path that serves as the basis for all path calculations.\n\nRelative paths in this object are calculated relative to the base path. It cannot be empty.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies how this live-updater finds the containers that need live update.",
 							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector"),
 						},
 					},
-					"pods": {
+					"sources": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Pods that have been discovered based on the criteria in the spec.",
+							Description: "Sources of files to sync.\n\nEvery live update must be associated with at least one Source object to trigger the update. Usually, Tilt structures it so that there's a Source for each image we depend on.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Pod"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource"),
 									},
 								},
 							},
 						},
 					},
-					"waiting": {
+					"stopPaths": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Waiting contains information about why the monitor has not started.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateWaiting"),
+							Description: "A list of relative paths that will immediately stop the live-update for the current container.\n\nUsed to detect file changes that invalidate the entire container image, forcing a complete rebuild.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"running": {
+					"syncs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Running contains information about the currently running monitor.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateRunning"),
-						},
-					},
-				},
-				Required: []string{"pods"},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesDiscoveryStateWaiting", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Pod", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"extraSelectors": {
+							Description: "Specify paths that can be live-updated into the container and their destinations. Any file changes observed that do not match any of these will invalidate the container image and force a complete rebuild.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync"),
+									},
+								},
+							},
+						},
+					},
+					"execs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ExtraSelectors are label selectors that will force discovery of a Pod even if it does not match the AncestorUID.\n\nThis should only be necessary in the event that a CRD creates Pods but does not set an owner reference to itself.",
+							Description: "A list of commands to run inside the container after files are synced.\n\nNB: In some documentation, we call these 'runs'. 'exec' more clearly matches kubectl exec for remote commands.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec"),
 									},
 								},
 							},
 						},
 					},
+					"restart": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies whether Tilt should try to natively restart the container in-place after syncs and execs.\n\nNote that native restarts are only supported by Docker and Docker Compose (and NOT docker-shim or containerd, the most common Kubernetes runtimes).\n\nTo restart on live-update in Kubernetes, see the guide for how to apply extensions to add restart behavior:\n\nhttps://docs.tilt.dev/live_update_reference.html",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
+				Required: []string{"basePath", "selector"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesImageLocator(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateStateFailed(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Finds image references in Kubernetes YAML.",
+				Description: "If any of the containers are currently failing to process updates, the LiveUpdateStateFailed surfaces information about what's happening and what the live-updater is doing to fix the problem.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"objectSelector": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Selects which objects to look in.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ObjectSelector"),
+							Description: "One word camel-case reason why we've reached a failure state.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"path": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A JSON path to the image reference field.\n\nIf Object is empty, the field should be a string.\n\nIf Object is non-empty, the field should be an object with subfields.",
-							Default:     "",
+							Description: "Human-readable description of what's wrong.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"object": {
+					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A descriptor of the path and structure of an object that describes an image reference. This is a common way to describe images in CRDs, breaking them down into an object rather than an image reference string.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageObjectDescriptor"),
+							Description: "When the live-updater transitioned into a Failed state.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
-				Required: []string{"objectSelector", "path"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.KubernetesImageObjectDescriptor", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ObjectSelector"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesImageObjectDescriptor(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "LiveUpdateStatus defines the observed state of LiveUpdate",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"repoField": {
+					"containers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the field that contains the image repository.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "A list of all containers that the live-updater is currently connected to.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStatus"),
+									},
+								},
+							},
 						},
 					},
-					"tagField": {
+					"failed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the field that contains the image tag.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "If any of the containers are currently failing to process updates, the Failed state surfaces information about what's happening and what the live-updater is doing to fix the problem.\n\nIf all containers are updating successfully, Failed will be nil.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"),
 						},
 					},
 				},
-				Required: []string{"repoField", "tagField"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_KubernetesWatchRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubernetesWatchRef is similar to v1.ObjectReference from the Kubernetes API and is used to determine what objects should be reported on based on discovery.",
+				Description: "Determines how a local path maps into a container image.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"uid": {
-						SchemaProps: spec.SchemaProps{
-							Description: "UID is a Kubernetes object UID.\n\nIt should either be the exact object UID or the transitive owner.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"namespace": {
+					"localPath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Namespace is the Kubernetes namespace for discovery. Required.",
+							Description: "A relative path to local files. Required.\n\nComputed relative to the live-update BasePath.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"containerPath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the Kubernetes object name.\n\nThis is not directly used in discovery; it is extra metadata.",
+							Description: "An absolute path inside the container. Required.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"namespace"},
+				Required: []string{"localPath", "containerPath"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LogSink(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LiveUpdate",
+				Description: "LogSink forwards the logs of some resources to somewhere outside Tilt, like a Loki server, a fluentd forward input, or a file, so that the logs of many dev environments can be collected in one place.\n\nLines are sent in batches. If the endpoint is slow or down, lines are buffered up to a limit, then the oldest are dropped, so that a stuck endpoint never slows Tilt down.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -4665,237 +6304,264 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdate(ref common.ReferenceCallback) comm
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSinkStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LogSinkList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "LogSinkList",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "One word camel-case reason why we're in a waiting state.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"message": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Human-readable description of what's blocking.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSink"),
+									},
+								},
+							},
+						},
+					},
 				},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LogSink", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LogSinkSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LiveUpdateContainerStatus defines the observed state of the live-update syncer for a particular container.",
+				Description: "LogSinkSpec defines where to forward logs, and which logs to forward.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"containerName": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the container in the pod.",
+							Description: "Where to forward logs: one of loki, fluentd, or file.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"containerID": {
+					"endpoint": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The ID of the container in the pod, in the format 'docker://<container_id>'.",
+							Description: "Where the sink is.\n\nFor loki, the base URL of the Loki server, like http://localhost:3100.\n\nFor fluentd, the host:port of a forward input, like localhost:24224.\n\nFor file, the absolute path of the file to append to.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"podName": {
+					"resources": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the pod this container belongs to.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The names of the resources to forward logs from.\n\nIf empty, forwards the logs of all resources, and the logs that don't belong to any resource.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"namespace": {
+					"minLevel": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The namespace of the pod this container belongs to.",
-							Default:     "",
+							Description: "Only forward lines at least this severe: one of debug, verbose, info, warn, or error.\n\nIf empty, forwards every line that Tilt logs.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastFileTimeSynced": {
+					"labels": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The timestamp of the most recent file update successfully synced to the container.\n\nMust match the timestamp in a FileEvent, not the time the sync was performed.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "Labels to add to every line, like the name of the developer or the environment.\n\nFor loki, these are stream labels. For fluentd and file, these are fields of each record.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"lastExecError": {
+					"batchSize": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Contains any error messages from the most recent sequence of Execs.\n\nEmpty if the most recent Execs completed successfully.\n\nAn ExecError is not necessarily a failure state. For example, a linter error in the container is something we'd want to surface to the user, but not an indication that the live-updater did something wrong.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The most lines to send at once. Defaults to 100.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"waiting": {
+					"flushInterval": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a waiting live update.\n\nA live update is waiting when the reconciler is aware of file changes that need to be synced to the container, but has decided not to sync them yet.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStateWaiting"),
+							Description: "How long to wait for a batch to fill before sending it anyway. Defaults to 1s.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"bufferSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The most lines to hold while the endpoint is slow or down. When the buffer is full, the oldest lines are dropped. Defaults to 10000.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"containerName", "podName", "namespace"},
+				Required: []string{"type", "endpoint"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStateWaiting", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateDockerComposeSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_LogSinkStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Specifies how to select containers to live update inside Docker Compose.",
+				Description: "LogSinkStatus defines the observed state of LogSink",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"service": {
+					"lastSentTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of a DockerComposeService object.\n\nFor simple projects, this is usually the same as the service name in the docker-compose.yml file. (But it doesn't necessarily have to be.)",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The last time Tilt sent a batch of lines to the endpoint.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-				},
-				Required: []string{"service"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_LiveUpdateExec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Runs a remote command after files have been synced to the container. Commonly used for small in-container changes (like moving files around, or restart processes).",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"args": {
+					"sentLines": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Command-line arguments to run inside the container. Must have length at least 1.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "The number of lines Tilt has sent to the endpoint.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"triggerPaths": {
+					"droppedLines": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of relative paths that trigger this command exec.\n\nIf not specified, all file changes seen by the LiveUpdate trigger this exec.\n\nPaths are specified relative to the the BasePath of the LiveUpdate.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "The number of lines Tilt dropped because the buffer was full.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"bufferedLines": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of lines waiting to be sent.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If the last batch failed to send, why.\n\nTilt keeps the lines, and retries with a backoff.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"args"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateKubernetesSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Notification(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Specifies how to select containers to live update inside K8s.",
+				Description: "Notification posts a message to a webhook, or shows a desktop notification, when a resource changes state, like when a build fails or a container starts crash-looping.\n\nThe webhook message is a JSON object with a \"text\" field, which Slack incoming webhooks (and many other chat tools) accept.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"discoveryName": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of a KubernetesDiscovery object for finding pods.",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"applyName": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ApplyName is the name of a KubernetesApply object for filtering discovered pods to prevent updating old deployments.\n\nIf not provided, no filtering will be applied and all discovered Pods will be eligible for update.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"image": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Image specifies the name of the image that we're copying files into.\n\nDetermines which containers in a pod to live-update. Matches images by name unless tag is explicitly specified.\n\nExactly one of Image, ContainerName, or ImageMapName MUST be specified.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"containerName": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ContainerName specifies the name of the container that we're copying files into.\n\nExactly one of Image, ContainerName, or ImageMapName MUST be specified.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationSpec"),
 						},
 					},
-					"imageMapName": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ImageMapName specifies the name of an ImageMapName object to use for determining the image we're copying files into.\n\nExactly one of Image, ContainerName, or ImageMapName MUST be specified.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationStatus"),
 						},
 					},
 				},
-				Required: []string{"discoveryName"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.NotificationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_NotificationList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LiveUpdateList",
+				Description: "NotificationList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -4925,7 +6591,7 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateList(ref common.ReferenceCallback)
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdate"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Notification"),
 									},
 								},
 							},
@@ -4936,54 +6602,157 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateList(ref common.ReferenceCallback)
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdate", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Notification", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_NotificationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Specifies how to select containers to live update.\n\nEvery live update must be associated with some object for finding containers. In the future, we expect there to be other types of container discovery objects (like Docker Compose container discovery).",
+				Description: "NotificationSpec defines where to send messages, and which state changes to send them for.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kubernetes": {
+					"url": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Finds containers in Kubernetes.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateKubernetesSelector"),
+							Description: "The webhook URL to POST messages to.\n\nRequired, unless Desktop is set.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"dockerCompose": {
+					"events": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Finds containers in Docker Compose.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateDockerComposeSelector"),
+							Description: "The state changes to send messages for.\n\nIf empty, sends messages for every state change except FirstFailure and RunFinished.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The names of the resources to send messages for.\n\nIf empty, sends messages for all resources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"minInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The minimum time between messages about the same resource.\n\nState changes in between are batched into the next message, so that a flapping resource doesn't flood the channel.\n\nDefaults to 1 minute. Set to 0s to send every state change right away.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"desktop": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to show messages as desktop notifications on the machine that Tilt runs on.\n\nUses osascript on macOS, notify-send on Linux, and PowerShell on Windows.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"mutedResources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The names of the resources to never send messages for.\n\nUse it to mute noisy resources without listing all the others in Resources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateDockerComposeSelector", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateKubernetesSelector"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_NotificationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Specifies how to pull in files.",
+				Description: "NotificationStatus defines the observed state of Notification",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"fileWatch": {
+					"lastSentTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of a FileWatch to use as a file source.",
+							Description: "The last time Tilt sent a message.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"sentCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of messages Tilt has sent.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If the last message failed to send, why.\n\nTilt retries messages that failed to post to the webhook after the minimum interval.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"imageMap": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_ObjectSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Selector for any Kubernetes-style API.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"apiVersionRegexp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the ImageMap object to watch for which file changes from this source are included in the container image.\n\nIf not provided, the live-updater will copy any file changes that it's aware of, even if they're already included in the container.",
+							Description: "A regular expression apiVersion match.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kindRegexp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A regular expression kind match.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nameRegexp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A regular expression name match.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespaceRegexp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A regular expression namespace match.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -4994,364 +6763,443 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSource(ref common.ReferenceCallback
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Pod(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "LiveUpdateSpec defines the desired state of LiveUpdate",
+				Description: "Pod is a collection of containers that can run on a host.\n\nThe Tilt API representation mirrors the Kubernetes API very closely. Irrelevant data is not included, and some fields might be simplified.\n\nThere might also be Tilt-specific status fields.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"basePath": {
+					"uid": {
 						SchemaProps: spec.SchemaProps{
-							Description: "An absolute local path that serves as the basis for all path calculations.\n\nRelative paths in this object are calculated relative to the base path. It cannot be empty.",
+							Description: "UID is the unique Pod UID within the K8s cluster.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"selector": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies how this live-updater finds the containers that need live update.",
+							Description: "Name is the Pod name within the K8s cluster.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace is the Pod namespace within the K8s cluster.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"createdAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CreatedAt is when the Pod was created.",
 							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector"),
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"sources": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Sources of files to sync.\n\nEvery live update must be associated with at least one Source object to trigger the update. Usually, Tilt structures it so that there's a Source for each image we depend on.",
+							Description: "Phase is where the Pod is at in its current lifecycle.\n\nValid values for this are v1.PodPhase values from the Kubernetes API.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deleting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deleting indicates that the Pod is in the process of being removed.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions are various lifecycle conditions for this Pod.\n\nSee also v1.PodCondition in the Kubernetes API.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodCondition"),
 									},
 								},
 							},
 						},
 					},
-					"stopPaths": {
+					"initContainers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of relative paths that will immediately stop the live-update for the current container.\n\nUsed to detect file changes that invalidate the entire container image, forcing a complete rebuild.",
+							Description: "InitContainers are containers executed prior to the Pod containers being executed.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container"),
 									},
 								},
 							},
 						},
 					},
-					"syncs": {
+					"containers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify paths that can be live-updated into the container and their destinations. Any file changes observed that do not match any of these will invalidate the container image and force a complete rebuild.",
+							Description: "Containers are the containers belonging to the Pod.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container"),
 									},
 								},
 							},
 						},
 					},
-					"execs": {
+					"ancestorUID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of commands to run inside the container after files are synced.\n\nNB: In some documentation, we call these 'runs'. 'exec' more clearly matches kubectl exec for remote commands.",
+							Description: "AncestorUID is the UID from the WatchRef that matched this Pod.\n\nIf the Pod matched based on extra label selectors, this will be empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podTemplateSpecHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodTemplateSpecHash is a hash of the Pod template spec.\n\nTilt uses this to associate Pods with the build that triggered them.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is a concise description for the Pod's current state.\n\nThis is based off the status output from `kubectl get pod` and is not an \"enum-like\" value.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"errors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Errors are aggregated error messages for the Pod and its containers.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"restart": {
+					"owner": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies whether Tilt should try to natively restart the container in-place after syncs and execs.\n\nNote that native restarts are only supported by Docker and Docker Compose (and NOT docker-shim or containerd, the most common Kubernetes runtimes).\n\nTo restart on live-update in Kubernetes, see the guide for how to apply extensions to add restart behavior:\n\nhttps://docs.tilt.dev/live_update_reference.html",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Direct owner of this pod, if available.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodOwner"),
 						},
 					},
 				},
-				Required: []string{"basePath", "selector"},
+				Required: []string{"uid", "name", "namespace", "createdAt", "phase", "deleting", "containers", "status", "errors"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodCondition", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodOwner", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateStateFailed(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PodCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "If any of the containers are currently failing to process updates, the LiveUpdateStateFailed surfaces information about what's happening and what the live-updater is doing to fix the problem.",
+				Description: "PodCondition is a lifecycle condition for a Pod.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Description: "One word camel-case reason why we've reached a failure state.",
+							Description: "Type is the type of condition.\n\nValid values for this are v1.PodConditionType values from the Kubernetes API.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"message": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Human-readable description of what's wrong.",
+							Description: "Status is the current state of the condition (True, False, or Unknown).\n\nValid values for this are v1.PodConditionStatus values from the Kubernetes API.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "When the live-updater transitioned into a Failed state.",
+							Description: "LastTransitionTime is the last time the status changed.",
 							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "LiveUpdateStatus defines the observed state of LiveUpdate",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"containers": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of all containers that the live-updater is currently connected to.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStatus"),
-									},
-								},
-							},
+							Description: "Reason is a unique, one-word, CamelCase value for the cause of the last status change.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"failed": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If any of the containers are currently failing to process updates, the Failed state surfaces information about what's happening and what the live-updater is doing to fix the problem.\n\nIf all containers are updating successfully, Failed will be nil.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"),
+							Description: "Message is a human-readable description of the last status change.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"type", "status"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PodLogStream(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Determines how a local path maps into a container image.",
+				Description: "PodLogStream\n\nStreams logs from a pod on Kubernetes into the core Tilt engine.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"localPath": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A relative path to local files. Required.\n\nComputed relative to the live-update BasePath.",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"containerPath": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "An absolute path inside the container. Required.",
-							Default:     "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamStatus"),
+						},
+					},
 				},
-				Required: []string{"localPath", "containerPath"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ObjectSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PodLogStreamList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Selector for any Kubernetes-style API.",
+				Description: "PodLogStreamList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"apiVersionRegexp": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A regular expression apiVersion match.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"kindRegexp": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A regular expression kind match.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"nameRegexp": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A regular expression name match.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"namespaceRegexp": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A regular expression namespace match.",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStream"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStream", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Pod(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PodLogStreamSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Pod is a collection of containers that can run on a host.\n\nThe Tilt API representation mirrors the Kubernetes API very closely. Irrelevant data is not included, and some fields might be simplified.\n\nThere might also be Tilt-specific status fields.",
+				Description: "PodLogStreamSpec defines the desired state of PodLogStream\n\nTranslated into a PodLog query to the current Kubernetes cluster: https://pkg.go.dev/k8s.io/api/core/v1#PodLogOptions\n\nwhich Kubernetes context to use?",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"uid": {
-						SchemaProps: spec.SchemaProps{
-							Description: "UID is the unique Pod UID within the K8s cluster.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"name": {
+					"pod": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the Pod name within the K8s cluster.",
-							Default:     "",
+							Description: "The name of the pod to watch. Required.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Namespace is the Pod namespace within the K8s cluster.",
-							Default:     "",
+							Description: "The namespace of the pod to watch. Defaults to the kubecontext default namespace.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"createdAt": {
+					"sinceTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CreatedAt is when the Pod was created.",
-							Default:     map[string]interface{}{},
+							Description: "An RFC3339 timestamp from which to show logs. If this value precedes the time a pod was started, only logs since the pod start will be returned. If this value is in the future, no logs will be returned.\n\nTranslates directly to the underlying PodLogOptions.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"phase": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Phase is where the Pod is at in its current lifecycle.\n\nValid values for this are v1.PodPhase values from the Kubernetes API.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"deleting": {
+					"onlyContainers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Deleting indicates that the Pod is in the process of being removed.",
-							Default:     false,
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "The names of containers to include in the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"conditions": {
+					"ignoreContainers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Conditions are various lifecycle conditions for this Pod.\n\nSee also v1.PodCondition in the Kubernetes API.",
+							Description: "The names of containers to exclude from the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodCondition"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"initContainers": {
+					"cluster": {
 						SchemaProps: spec.SchemaProps{
-							Description: "InitContainers are containers executed prior to the Pod containers being executed.",
+							Description: "Cluster the Pod belongs to.\n\nIf not provided, \"default\" will be used.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_PodLogStreamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PodLogStreamStatus defines the observed state of PodLogStream",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"containerStatuses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A list of containers being watched.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerLogStreamStatus"),
 									},
 								},
 							},
 						},
 					},
-					"containers": {
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error message encountered while setting up the stream.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerLogStreamStatus"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_PodLogStreamTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PodLogStreamTemplateSpec describes common attributes for PodLogStreams that can be shared across pods.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"sinceTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "An RFC3339 timestamp from which to show logs. If this value precedes the time a pod was started, only logs since the pod start will be returned. If this value is in the future, no logs will be returned.\n\nTranslates directly to the underlying PodLogOptions.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"onlyContainers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Containers are the containers belonging to the Pod.",
+							Description: "The names of containers to include in the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"ancestorUID": {
-						SchemaProps: spec.SchemaProps{
-							Description: "AncestorUID is the UID from the WatchRef that matched this Pod.\n\nIf the Pod matched based on extra label selectors, this will be empty.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"podTemplateSpecHash": {
-						SchemaProps: spec.SchemaProps{
-							Description: "PodTemplateSpecHash is a hash of the Pod template spec.\n\nTilt uses this to associate Pods with the build that triggered them.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Status is a concise description for the Pod's current state.\n\nThis is based off the status output from `kubectl get pod` and is not an \"enum-like\" value.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"errors": {
+					"ignoreContainers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Errors are aggregated error messages for the Pod and its containers.",
+							Description: "The names of containers to exclude from the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -5364,67 +7212,54 @@ func schema_pkg_apis_core_v1alpha1_Pod(ref common.ReferenceCallback) common.Open
 							},
 						},
 					},
-					"owner": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Direct owner of this pod, if available.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodOwner"),
-						},
-					},
 				},
-				Required: []string{"uid", "name", "namespace", "createdAt", "phase", "deleting", "containers", "status", "errors"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodCondition", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodOwner", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PodOwner(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodCondition is a lifecycle condition for a Pod.",
+				Description: "PodOwner contains information of the direct owner of the pod, if available.\n\nTools that need to select a most relevant pod or set of pods can use this info to group pods by owner.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type is the type of condition.\n\nValid values for this are v1.PodConditionType values from the Kubernetes API.",
+							Description: "The name of the owner.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"status": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status is the current state of the condition (True, False, or Unknown).\n\nValid values for this are v1.PodConditionStatus values from the Kubernetes API.",
+							Description: "API version of the owner.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"lastTransitionTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "LastTransitionTime is the last time the status changed.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"reason": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reason is a unique, one-word, CamelCase value for the cause of the last status change.",
+							Description: "Kind of the owner More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"message": {
+					"creationTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Message is a human-readable description of the last status change.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The creation timestamp of the owner.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
 				},
-				Required: []string{"type", "status"},
+				Required: []string{"name", "apiVersion", "kind"},
 			},
 		},
 		Dependencies: []string{
@@ -5432,11 +7267,11 @@ func schema_pkg_apis_core_v1alpha1_PodCondition(ref common.ReferenceCallback) co
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodLogStream(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PortForward(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodLogStream\n\nStreams logs from a pod on Kubernetes into the core Tilt engine.",
+				Description: "PortForward",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -5462,28 +7297,28 @@ func schema_pkg_apis_core_v1alpha1_PodLogStream(ref common.ReferenceCallback) co
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStreamStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodLogStreamList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PortForwardList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodLogStreamList",
+				Description: "PortForwardList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -5513,7 +7348,7 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamList(ref common.ReferenceCallback
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStream"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForward"),
 									},
 								},
 							},
@@ -5524,62 +7359,41 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamList(ref common.ReferenceCallback
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodLogStream", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForward", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodLogStreamSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PortForwardSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodLogStreamSpec defines the desired state of PodLogStream\n\nTranslated into a PodLog query to the current Kubernetes cluster: https://pkg.go.dev/k8s.io/api/core/v1#PodLogOptions\n\nwhich Kubernetes context to use?",
+				Description: "PortForwardSpec defines the desired state of PortForward",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"pod": {
+					"podName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the pod to watch. Required.",
+							Description: "The name of the pod to port forward to/from. Required.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The namespace of the pod to watch. Defaults to the kubecontext default namespace.",
+							Description: "The namespace of the pod to port forward to/from. Defaults to the kubecontext default namespace.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"sinceTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "An RFC3339 timestamp from which to show logs. If this value precedes the time a pod was started, only logs since the pod start will be returned. If this value is in the future, no logs will be returned.\n\nTranslates directly to the underlying PodLogOptions.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"onlyContainers": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The names of containers to include in the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
-						},
-					},
-					"ignoreContainers": {
+					"forwards": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The names of containers to exclude from the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
+							Description: "One or more port forwards to execute on the given pod. Required.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"),
 									},
 								},
 							},
@@ -5587,159 +7401,201 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamSpec(ref common.ReferenceCallback
 					},
 					"cluster": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Cluster the Pod belongs to.\n\nIf not provided, \"default\" will be used.",
+							Description: "Cluster to forward ports from to the local machine.\n\nIf not specified, the default Kubernetes cluster will be used.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"podName", "forwards"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodLogStreamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PortForwardStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodLogStreamStatus defines the observed state of PodLogStream",
+				Description: "PortForwardStatus defines the observed state of PortForward",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"containerStatuses": {
+					"forwardStatuses": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of containers being watched.",
-							Type:        []string{"array"},
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerLogStreamStatus"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ForwardStatus"),
 									},
 								},
 							},
 						},
 					},
-					"error": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Error message encountered while setting up the stream.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ContainerLogStreamStatus"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ForwardStatus"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodLogStreamTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_PortForwardTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodLogStreamTemplateSpec describes common attributes for PodLogStreams that can be shared across pods.",
+				Description: "PortForwardTemplateSpec describes common attributes for PortForwards that can be shared across pods.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"sinceTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "An RFC3339 timestamp from which to show logs. If this value precedes the time a pod was started, only logs since the pod start will be returned. If this value is in the future, no logs will be returned.\n\nTranslates directly to the underlying PodLogOptions.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"onlyContainers": {
+					"forwards": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The names of containers to include in the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
+							Description: "One or more port forwards to execute on the given pod. Required.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"),
 									},
 								},
 							},
 						},
 					},
-					"ignoreContainers": {
+				},
+				Required: []string{"forwards"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_Probe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Probe describes a health check to be performed to determine whether it is alive or ready to receive traffic.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"exec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The names of containers to exclude from the stream.\n\nIf `onlyContainers` and `ignoreContainers` are not set, will watch all containers in the pod.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "One and only one of the following should be specified. Exec specifies the action to take.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction"),
+						},
+					},
+					"httpGet": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTPGet specifies the http request to perform.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction"),
+						},
+					},
+					"tcpSocket": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TCPSocket specifies an action involving a TCP port. TCP hooks not yet supported",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"),
+						},
+					},
+					"initialDelaySeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of seconds after the container has started before liveness probes are initiated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of seconds after which the probe times out. Defaults to 1 second. Minimum value is 1. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"periodSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often (in seconds) to perform the probe. Default to 10 seconds. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"successThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Minimum consecutive successes for the probe to be considered successful after having failed. Defaults to 1. Must be 1 for liveness and startup. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failureThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Minimum consecutive failures for the probe to be considered failed after having succeeded. Defaults to 3. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PodOwner(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_RegistryHosting(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PodOwner contains information of the direct owner of the pod, if available.\n\nTools that need to select a most relevant pod or set of pods can use this info to group pods by owner.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"host": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the owner.",
+							Description: "Host documents the host (hostname and port) of the registry, as seen from outside the cluster.\n\nThis is the registry host that tools outside the cluster should push images to.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"hostFromClusterNetwork": {
 						SchemaProps: spec.SchemaProps{
-							Description: "API version of the owner.",
-							Default:     "",
+							Description: "HostFromClusterNetwork documents the host (hostname and port) of the registry, as seen from networking inside the container pods.\n\nThis is the registry host that tools running on pods inside the cluster should push images to. If not set, then tools inside the cluster should assume the local registry is not available to them.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"kind": {
+					"hostFromContainerRuntime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind of the owner More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Default:     "",
+							Description: "HostFromContainerRuntime documents the host (hostname and port) of the registry, as seen from the cluster's container runtime.\n\nWhen tools apply Kubernetes objects to the cluster, this host should be used for image name fields. If not set, users of this field should use the value of Host instead.\n\nNote that it doesn't make sense semantically to define this field, but not define Host or HostFromClusterNetwork. That would imply a way to pull images without a way to push images.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"creationTimestamp": {
+					"help": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The creation timestamp of the owner.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Help contains a URL pointing to documentation for users on how to set up and configure a local registry.\n\nTools can use this to nudge users to enable the registry. When possible, the writer should use as permanent a URL as possible to prevent drift (e.g., a version control SHA).\n\nWhen image pushes to a registry host specified in one of the other fields fail, the tool should display this help URL to the user. The help URL should contain instructions on how to diagnose broken or misconfigured registries.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"singleName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SingleName uses a shared image name for _all_ Tilt-built images and relies on tags to distinguish between logically distinct images.\n\nThis is most commonly used with Amazon Elastic Container Registry (ECR), which works differently than other image registries.\n\nAn ECR host takes the form https://aws_account_id.dkr.ecr.region.amazonaws.com. Each image name in that registry must be pre-created ಠ_ಠ and assigned IAM permissions. For example: https://aws_account_id.dkr.ecr.region.amazonaws.com/my-repo (They call this a repo).\n\nFor this reason, some users using ECR prefer to push all images to a single image name (ECR repo).\n\nA recommended pattern here is to create a \"personal\" image repo for each user during development.\n\nSee: https://docs.aws.amazon.com/AmazonECR/latest/userguide/Repositories.html https://github.com/tilt-dev/tilt/issues/2419",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name", "apiVersion", "kind"},
+				Required: []string{"host"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PortForward(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ResourceEvent(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PortForward",
+				Description: "ResourceEvent records one state change of a resource, like a build starting or a pod restarting.\n\nTilt creates a ResourceEvent for each state change and never updates it, so bots, dashboards, and test harnesses can watch ResourceEvents to react to Tilt without polling or parsing logs.\n\nEach event has the label tilt.dev/resource with the name of its resource, so that watchers can select the events of a single resource. (Except for resources like (Tiltfile) whose names aren't valid label values.)\n\nTilt only keeps the most recent events.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -5765,28 +7621,22 @@ func schema_pkg_apis_core_v1alpha1_PortForward(ref common.ReferenceCallback) com
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardSpec"),
-						},
-					},
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEventSpec"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForwardStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEventSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PortForwardList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ResourceEventList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PortForwardList",
+				Description: "ResourceEventList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -5816,7 +7666,7 @@ func schema_pkg_apis_core_v1alpha1_PortForwardList(ref common.ReferenceCallback)
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForward"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEvent"),
 									},
 								},
 							},
@@ -5827,248 +7677,393 @@ func schema_pkg_apis_core_v1alpha1_PortForwardList(ref common.ReferenceCallback)
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PortForward", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ResourceEvent", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PortForwardSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ResourceEventSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PortForwardSpec defines the desired state of PortForward",
+				Description: "ResourceEventSpec describes what happened.\n\nNew event types and fields may be added, but the meaning of existing ones won't change, so clients should ignore types they don't know.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"podName": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the pod to port forward to/from. Required.",
+							Description: "The kind of state change.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"namespace": {
+					"resource": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The namespace of the pod to port forward to/from. Defaults to the kubecontext default namespace.",
+							Description: "The name of the resource that changed.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"forwards": {
+					"sequence": {
 						SchemaProps: spec.SchemaProps{
-							Description: "One or more port forwards to execute on the given pod. Required.",
+							Description: "The order of the event among all the events of this Tilt session.\n\nStarts at 1 and increases by 1 with each event, so watchers can put events in order and notice when they've missed some.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When the state change happened.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The error message, for BuildFailed events.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the resource isn't ready, for ResourceNotReady events.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The pod that restarted, for PodRestarted events.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podRestarts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The total number of restarts of the pod, for PodRestarted events.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"type", "resource", "sequence", "time"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_RestartOnSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RestartOnSpec indicates the set of objects that can trigger a restart of this object.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"fileWatches": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FileWatches that can trigger a restart.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"cluster": {
+					"uiButtons": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Cluster to forward ports from to the local machine.\n\nIf not specified, the default Kubernetes cluster will be used.",
-							Default:     "",
+							Description: "UIButtons that can trigger a restart.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_Session(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Session provides introspective data about the status of the Tilt process.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionStatus"),
+						},
+					},
 				},
-				Required: []string{"podName", "forwards"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_PortForwardStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_SessionCISpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PortForwardStatus defines the observed state of PortForward",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"forwardStatuses": {
+					"k8sGracePeriod": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
+							Description: "Grace period given for Kubernetes resources to recover after they start failing.\n\nIf omitted, and in exitCondition=ci, the session will exit as soon as we see a pod failure.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout for the whole CI pipeline. Defaults to 30m.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"readinessTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timeout for each resource to become ready, measured from when its first update starts.\n\nIf omitted, resources only have to be ready before the global timeout.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"resourceReadinessTimeouts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Readiness timeouts for individual resources, by resource name. Overrides ReadinessTimeout.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ForwardStatus"),
+										Default: 0,
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 									},
 								},
 							},
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ForwardStatus"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_PortForwardTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "PortForwardTemplateSpec describes common attributes for PortForwards that can be shared across pods.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"forwards": {
+					"failurePolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What to do when a resource fails. Defaults to FailFast.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_SessionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SessionList is a list of Session objects.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "One or more port forwards to execute on the given pod. Required.",
-							Type:        []string{"array"},
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Session"),
 									},
 								},
 							},
 						},
 					},
 				},
-				Required: []string{"forwards"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Forward"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Session", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Probe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_SessionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Probe describes a health check to be performed to determine whether it is alive or ready to receive traffic.",
+				Description: "SessionSpec defines the desired state of Session",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"exec": {
-						SchemaProps: spec.SchemaProps{
-							Description: "One and only one of the following should be specified. Exec specifies the action to take.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction"),
-						},
-					},
-					"httpGet": {
-						SchemaProps: spec.SchemaProps{
-							Description: "HTTPGet specifies the http request to perform.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction"),
-						},
-					},
-					"tcpSocket": {
-						SchemaProps: spec.SchemaProps{
-							Description: "TCPSocket specifies an action involving a TCP port. TCP hooks not yet supported",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"),
-						},
-					},
-					"initialDelaySeconds": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Number of seconds after the container has started before liveness probes are initiated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"timeoutSeconds": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Number of seconds after which the probe times out. Defaults to 1 second. Minimum value is 1. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"periodSeconds": {
+					"tiltfilePath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "How often (in seconds) to perform the probe. Default to 10 seconds. Minimum value is 1.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "TiltfilePath is the path to the Tiltfile for the run. It cannot be empty.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"successThreshold": {
+					"exitCondition": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Minimum consecutive successes for the probe to be considered successful after having failed. Defaults to 1. Must be 1 for liveness and startup. Minimum value is 1.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "ExitCondition defines the criteria for Tilt to exit.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"failureThreshold": {
+					"ci": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Minimum consecutive failures for the probe to be considered failed after having succeeded. Defaults to 3. Minimum value is 1.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "Additional settings when in exitCondition=CI.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionCISpec"),
 						},
 					},
 				},
+				Required: []string{"tiltfilePath", "exitCondition"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ExecAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.HTTPGetAction", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TCPSocketAction"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionCISpec"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_RegistryHosting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_SessionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "SessionStatus defines the observed state of Session",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"host": {
+					"pid": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Host documents the host (hostname and port) of the registry, as seen from outside the cluster.\n\nThis is the registry host that tools outside the cluster should push images to.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "PID is the process identifier for this instance of Tilt.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"hostFromClusterNetwork": {
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "HostFromClusterNetwork documents the host (hostname and port) of the registry, as seen from networking inside the container pods.\n\nThis is the registry host that tools running on pods inside the cluster should push images to. If not set, then tools inside the cluster should assume the local registry is not available to them.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "StartTime is when the Tilt engine was first started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"hostFromContainerRuntime": {
+					"targets": {
 						SchemaProps: spec.SchemaProps{
-							Description: "HostFromContainerRuntime documents the host (hostname and port) of the registry, as seen from the cluster's container runtime.\n\nWhen tools apply Kubernetes objects to the cluster, this host should be used for image name fields. If not set, users of this field should use the value of Host instead.\n\nNote that it doesn't make sense semantically to define this field, but not define Host or HostFromClusterNetwork. That would imply a way to pull images without a way to push images.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Targets are normalized representations of the servers/jobs managed by this Session.\n\nA resource from a Tiltfile might produce one or more targets. A target can also be shared across multiple resources (e.g. an image referenced by multiple K8s pods).",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Target"),
+									},
+								},
+							},
 						},
 					},
-					"help": {
+					"done": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Help contains a URL pointing to documentation for users on how to set up and configure a local registry.\n\nTools can use this to nudge users to enable the registry. When possible, the writer should use as permanent a URL as possible to prevent drift (e.g., a version control SHA).\n\nWhen image pushes to a registry host specified in one of the other fields fail, the tool should display this help URL to the user. The help URL should contain instructions on how to diagnose broken or misconfigured registries.",
-							Type:        []string{"string"},
+							Description: "Done indicates whether this Session has completed its work and is ready to exit.",
+							Default:     false,
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"singleName": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SingleName uses a shared image name for _all_ Tilt-built images and relies on tags to distinguish between logically distinct images.\n\nThis is most commonly used with Amazon Elastic Container Registry (ECR), which works differently than other image registries.\n\nAn ECR host takes the form https://aws_account_id.dkr.ecr.region.amazonaws.com. Each image name in that registry must be pre-created ಠ_ಠ and assigned IAM permissions. For example: https://aws_account_id.dkr.ecr.region.amazonaws.com/my-repo (They call this a repo).\n\nFor this reason, some users using ECR prefer to push all images to a single image name (ECR repo).\n\nA recommended pattern here is to create a \"personal\" image repo for each user during development.\n\nSee: https://docs.aws.amazon.com/AmazonECR/latest/userguide/Repositories.html https://github.com/tilt-dev/tilt/issues/2419",
+							Description: "Error is a non-empty string when the Session is Done but encountered a failure as defined by the ExitCondition from the SessionSpec.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"host"},
+				Required: []string{"pid", "startTime", "targets", "done"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Target", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_RestartOnSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_StartOnSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "RestartOnSpec indicates the set of objects that can trigger a restart of this object.",
+				Description: "StartOnSpec indicates the set of objects that can trigger a start/restart of this object.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"fileWatches": {
+					"startAfter": {
 						SchemaProps: spec.SchemaProps{
-							Description: "FileWatches that can trigger a restart.",
+							Description: "StartAfter indicates that events before this time should be ignored.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"uiButtons": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UIButtons that can trigger a start/restart.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -6081,9 +8076,45 @@ func schema_pkg_apis_core_v1alpha1_RestartOnSpec(ref common.ReferenceCallback) c
 							},
 						},
 					},
+				},
+				Required: []string{"uiButtons"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_StateSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Describes where a ToggleButton's state is stored. Exactly one type of source must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is stored in a ConfigMap.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapStateSource"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapStateSource"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_StopOnSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
 					"uiButtons": {
 						SchemaProps: spec.SchemaProps{
-							Description: "UIButtons that can trigger a restart.",
+							Description: "UIButtons that can trigger a stop.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -6097,373 +8128,419 @@ func schema_pkg_apis_core_v1alpha1_RestartOnSpec(ref common.ReferenceCallback) c
 						},
 					},
 				},
+				Required: []string{"uiButtons"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Session(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TCPSocketAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Session provides introspective data about the status of the Tilt process.",
+				Description: "TCPSocketAction describes an action based on opening a socket",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"port": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Number or name of the port to access on the container. Number must be in the range 1 to 65535.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"apiVersion": {
+					"host": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Optional: Host name to connect to, defaults to the pod IP.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionSpec"),
-						},
-					},
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionStatus"),
-						},
-					},
 				},
+				Required: []string{"port"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_SessionCISpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Target(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Target is a server or job whose execution is managed as part of this Session.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"k8sGracePeriod": {
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the target; this is auto-generated from Tiltfile resources.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the execution profile for this resource.\n\nJob targets run to completion (e.g. a build script or database migration script). Server targets run indefinitely (e.g. an HTTP server).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Grace period given for Kubernetes resources to recover after they start failing.\n\nIf omitted, and in exitCondition=ci, the session will exit as soon as we see a pod failure.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+							Description: "Resources are one or more Tiltfile resources that this target is associated with.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"timeout": {
+					"state": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timeout for the whole CI pipeline. Defaults to 30m.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+							Description: "State provides information about the current status of the target.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetState"),
 						},
 					},
 				},
+				Required: []string{"name", "type", "resources", "state"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetState"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_SessionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TargetState(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SessionList is a list of Session objects.",
+				Description: "TargetState describes the current execution status for a target.\n\nEither EXACTLY one of Waiting, Active, Disabled, or Terminated will be populated or NONE of them will be. In the event that all states are null, the target is currently inactive or disabled and should not be expected to execute.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"waiting": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Waiting being non-nil indicates that the next execution of the target has been queued but not yet started.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateWaiting"),
 						},
 					},
-					"apiVersion": {
+					"active": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Active being non-nil indicates that the target is currently executing.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateActive"),
 						},
 					},
-					"metadata": {
+					"terminated": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "Terminated being non-nil indicates that the target finished execution either normally or due to failure.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateTerminated"),
 						},
 					},
-					"items": {
+					"disabled": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Session"),
-									},
-								},
-							},
+							Description: "Disabled being non-nil indicates that the target is disabled.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateDisabled"),
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Session", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateActive", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateDisabled", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateWaiting"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_SessionSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TargetStateActive(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SessionSpec defines the desired state of Session",
+				Description: "TargetStateActive is a target that is currently running but has not yet finished.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"tiltfilePath": {
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TiltfilePath is the path to the Tiltfile for the run. It cannot be empty.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "StartTime is when execution began.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"exitCondition": {
+					"ready": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ExitCondition defines the criteria for Tilt to exit.",
-							Default:     "",
-							Type:        []string{"string"},
+							Description: "Ready indicates that the target has passed readiness checks.\n\nIf the target does not use or support readiness checks, this is always true.",
+							Default:     false,
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"ci": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Additional settings when in exitCondition=CI.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionCISpec"),
-						},
-					},
 				},
-				Required: []string{"tiltfilePath", "exitCondition"},
+				Required: []string{"startTime", "ready"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.SessionCISpec"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_SessionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TargetStateDisabled(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SessionStatus defines the observed state of Session",
+				Description: "TargetStateDisabled is a target that has been disabled.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_TargetStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TargetStateTerminated is a target that finished running, either because it completed successfully or encountered an error.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"pid": {
-						SchemaProps: spec.SchemaProps{
-							Description: "PID is the process identifier for this instance of Tilt.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int64",
-						},
-					},
 					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "StartTime is when the Tilt engine was first started.",
+							Description: "StartTime is when the target began executing.",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"targets": {
+					"finishTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Targets are normalized representations of the servers/jobs managed by this Session.\n\nA resource from a Tiltfile might produce one or more targets. A target can also be shared across multiple resources (e.g. an image referenced by multiple K8s pods).",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Target"),
-									},
-								},
-							},
+							Description: "FinishTime is when the target stopped executing.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"done": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Done indicates whether this Session has completed its work and is ready to exit.",
-							Default:     false,
-							Type:        []string{"boolean"},
+							Description: "Error is a non-empty string if the target encountered a failure during execution that caused it to stop.\n\nFor targets of type TargetTypeServer, this is always populated, as the target is expected to run indefinitely, and thus any termination is an error.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"error": {
+					"graceStatus": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error is a non-empty string when the Session is Done but encountered a failure as defined by the ExitCondition from the SessionSpec.",
+							Description: "Errors may be tolerated if the target is still within a grace period set on the session.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"pid", "startTime", "targets", "done"},
+				Required: []string{"startTime", "finishTime"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Target", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_StartOnSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TargetStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "StartOnSpec indicates the set of objects that can trigger a start/restart of this object.",
+				Description: "TargetStateWaiting is a target that has been enqueued for execution but has not yet started.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startAfter": {
-						SchemaProps: spec.SchemaProps{
-							Description: "StartAfter indicates that events before this time should be ignored.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"uiButtons": {
+					"waitReason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "UIButtons that can trigger a start/restart.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "WaitReason is a description for why the target is waiting and not yet active.\n\nThis is NOT the \"cause\" or \"trigger\" for the target being invoked.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"uiButtons"},
+				Required: []string{"waitReason"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_StateSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltBuild(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Describes where a ToggleButton's state is stored. Exactly one type of source must be set.",
+				Description: "Information about the running tilt binary.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"configMap": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "State is stored in a ConfigMap.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapStateSource"),
+							Description: "A semantic version string.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"commitSHA": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The Git digest of the commit this binary was built at.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"date": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human-readable string representing when the binary was built.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dev": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indicates whether this is a development build (true) or an official release (false).",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ConfigMapStateSource"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_StopOnSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_Tiltfile(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Tiltfile is the main way users add services to Tilt.\n\nThe Tiltfile evaluator executes the Tiltfile, then adds all the objects it creates as children of the Tiltfile object.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"uiButtons": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "UIButtons that can trigger a stop.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStatus"),
 						},
 					},
 				},
-				Required: []string{"uiButtons"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TCPSocketAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltfileList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TCPSocketAction describes an action based on opening a socket",
+				Description: "TiltfileList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"port": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number or name of the port to access on the container. Number must be in the range 1 to 65535.",
-							Default:     0,
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"host": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Optional: Host name to connect to, defaults to the pod IP.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Tiltfile"),
+									},
+								},
+							},
+						},
+					},
 				},
-				Required: []string{"port"},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Tiltfile", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Target(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltfileSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Target is a server or job whose execution is managed as part of this Session.",
+				Description: "TiltfileSpec defines the desired state of Tiltfile",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"path": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the name of the target; this is auto-generated from Tiltfile resources.",
+							Description: "The path to the Tiltfile on disk.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"type": {
+					"labels": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type is the execution profile for this resource.\n\nJob targets run to completion (e.g. a build script or database migration script). Server targets run indefinitely (e.g. an HTTP server).",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "A set of labels to apply to all objects owned by this Tiltfile.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"resources": {
+					"restartOn": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Resources are one or more Tiltfile resources that this target is associated with.",
+							Description: "Objects that can trigger a re-execution of this Tiltfile.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec"),
+						},
+					},
+					"args": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Arguments to the Tiltfile.\n\nArguments can be positional (['a', 'b', 'c']) or flag-based ('--to-edit=a'). By default, a list of arguments indicates the list of services in the tiltfile that should be enabled.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -6476,140 +8553,109 @@ func schema_pkg_apis_core_v1alpha1_Target(ref common.ReferenceCallback) common.O
 							},
 						},
 					},
-					"state": {
+					"stopOn": {
 						SchemaProps: spec.SchemaProps{
-							Description: "State provides information about the current status of the target.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetState"),
+							Description: "Objects that can stop execution of this Tiltfile.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StopOnSpec"),
 						},
 					},
 				},
-				Required: []string{"name", "type", "resources", "state"},
+				Required: []string{"path"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetState"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StopOnSpec"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TargetState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltfileStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TargetState describes the current execution status for a target.\n\nEither EXACTLY one of Waiting, Active, Disabled, or Terminated will be populated or NONE of them will be. In the event that all states are null, the target is currently inactive or disabled and should not be expected to execute.",
+				Description: "TiltfileStateRunning is a running state of a tiltfile execution.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"waiting": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Waiting being non-nil indicates that the next execution of the target has been queued but not yet started.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateWaiting"),
-						},
-					},
-					"active": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Active being non-nil indicates that the target is currently executing.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateActive"),
-						},
-					},
-					"terminated": {
+					"reasons": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Terminated being non-nil indicates that the target finished execution either normally or due to failure.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateTerminated"),
+							Description: "The reason why this tiltfile was built. May contain more than one reason.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"disabled": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Disabled being non-nil indicates that the target is disabled.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateDisabled"),
+							Description: "Time at which previous execution of the command started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateActive", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateDisabled", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TargetStateWaiting"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TargetStateActive(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltfileStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TargetStateActive is a target that is currently running but has not yet finished.",
+				Description: "TiltfileStateTerminated is a terminated state of a tiltfile execution.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startTime": {
+					"reasons": {
 						SchemaProps: spec.SchemaProps{
-							Description: "StartTime is when execution began.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "The reasons why this tiltfile was built. May contain more than one reason.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"ready": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Ready indicates that the target has passed readiness checks.\n\nIf the target does not use or support readiness checks, this is always true.",
-							Default:     false,
-							Type:        []string{"boolean"},
+							Description: "Error message if this tiltfile execution failed.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"startTime", "ready"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_TargetStateDisabled(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "TargetStateDisabled is a target that has been disabled.",
-				Type:        []string{"object"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_TargetStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "TargetStateTerminated is a target that finished running, either because it completed successfully or encountered an error.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"startTime": {
+					"startedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "StartTime is when the target began executing.",
+							Description: "Time at which previous execution of the command started.",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"finishTime": {
+					"finishedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "FinishTime is when the target stopped executing.",
+							Description: "Time at which the command last terminated.",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"error": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Error is a non-empty string if the target encountered a failure during execution that caused it to stop.\n\nFor targets of type TargetTypeServer, this is always populated, as the target is expected to run indefinitely, and thus any termination is an error.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"graceStatus": {
+					"warningCount": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Errors may be tolerated if the target is still within a grace period set on the session.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Number of warnings generated by this Tiltfile. (brief) reason the process is terminated",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"startTime", "finishTime"},
 			},
 		},
 		Dependencies: []string{
@@ -6617,74 +8663,64 @@ func schema_pkg_apis_core_v1alpha1_TargetStateTerminated(ref common.ReferenceCal
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TargetStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltfileStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TargetStateWaiting is a target that has been enqueued for execution but has not yet started.",
+				Description: "TiltfileStateWaiting is a waiting state of a tiltfile execution.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"waitReason": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "WaitReason is a description for why the target is waiting and not yet active.\n\nThis is NOT the \"cause\" or \"trigger\" for the target being invoked.",
-							Default:     "",
+							Description: "(brief) reason the tiltfile is waiting.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"waitReason"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TiltBuild(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_TiltfileStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Information about the running tilt binary.",
+				Description: "TiltfileStatus defines the observed state of Tiltfile",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"version": {
-						SchemaProps: spec.SchemaProps{
-							Description: "A semantic version string.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"commitSHA": {
+					"waiting": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The Git digest of the commit this binary was built at.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Details about a waiting tiltfile.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateWaiting"),
 						},
 					},
-					"date": {
+					"running": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A human-readable string representing when the binary was built.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Details about a running tiltfile.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateRunning"),
 						},
 					},
-					"dev": {
+					"terminated": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Indicates whether this is a development build (true) or an official release (false).",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "Details about a terminated tiltfile.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateTerminated"),
 						},
 					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateWaiting"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_Tiltfile(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ToggleButton(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Tiltfile is the main way users add services to Tilt.\n\nThe Tiltfile evaluator executes the Tiltfile, then adds all the objects it creates as children of the Tiltfile object.",
+				Description: "ToggleButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -6710,28 +8746,28 @@ func schema_pkg_apis_core_v1alpha1_Tiltfile(ref common.ReferenceCallback) common
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TiltfileList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ToggleButtonList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TiltfileList",
+				Description: "ToggleButtonList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -6761,7 +8797,7 @@ func schema_pkg_apis_core_v1alpha1_TiltfileList(ref common.ReferenceCallback) co
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Tiltfile"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButton"),
 									},
 								},
 							},
@@ -6772,162 +8808,199 @@ func schema_pkg_apis_core_v1alpha1_TiltfileList(ref common.ReferenceCallback) co
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Tiltfile", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButton", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TiltfileSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ToggleButtonSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TiltfileSpec defines the desired state of Tiltfile",
+				Description: "ToggleButtonSpec defines the desired state of ToggleButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"path": {
+					"location": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The path to the Tiltfile on disk.",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Where to display the button",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"),
 						},
 					},
-					"labels": {
+					"on": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A set of labels to apply to all objects owned by this Tiltfile.",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Config for the button when it is \"on\"",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStateSpec"),
 						},
 					},
-					"restartOn": {
+					"off": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Objects that can trigger a re-execution of this Tiltfile.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec"),
+							Description: "Config for the button when it is \"off\"",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStateSpec"),
 						},
 					},
-					"args": {
+					"defaultOn": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Arguments to the Tiltfile.\n\nArguments can be positional (['a', 'b', 'c']) or flag-based ('--to-edit=a'). By default, a list of arguments indicates the list of services in the tiltfile that should be enabled.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "If `StateSource` does not point at a valid value, the initial button state will be \"on\" or \"off\" depending on this bool",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"stopOn": {
+					"stateSource": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Objects that can stop execution of this Tiltfile.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StopOnSpec"),
+							Description: "Where the toggle button's state is stored",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StateSource"),
 						},
 					},
 				},
-				Required: []string{"path"},
+				Required: []string{"location", "on", "off", "defaultOn", "stateSource"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RestartOnSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StopOnSpec"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StateSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TiltfileStateRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ToggleButtonStateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TiltfileStateRunning is a running state of a tiltfile execution.",
+				Description: "Describes a state (on/off) of a ToggleButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reasons": {
+					"text": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reason why this tiltfile was built. May contain more than one reason.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "Text to appear on the button itself or as hover text (depending on button location).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"startedAt": {
+					"iconName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time at which previous execution of the command started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "IconName is a Material Icon to appear next to button text or on the button itself (depending on button location).\n\nValid values are icon font ligature names from the Material Icons set. See https://fonts.google.com/icons for the full list of available icons.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"iconSVG": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IconSVG is an SVG to use as the icon to appear next to button text or on the button itself (depending on button location).\n\nThis should be an <svg> element scaled for a 24x24 viewport.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requiresConfirmation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, clicking the button in this state requires a second click to confirm.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"text"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TiltfileStateTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_ToggleButtonStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TiltfileStateTerminated is a terminated state of a tiltfile execution.",
+				Description: "ToggleButtonStatus defines the observed state of ToggleButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reasons": {
+					"error": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The reasons why this tiltfile was built. May contain more than one reason.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "If healthy, empty. If non-healthy, specifies a problem the ToggleButton encountered",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"error": {
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIBoolInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Describes a boolean checkbox input field attached to a button.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"defaultValue": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Error message if this tiltfile execution failed.",
+							Description: "Whether the input is initially true or false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"trueString": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If the input's value is converted to a string, use this when the value is true. If unspecified, its string value will be `\"true\"`",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"startedAt": {
+					"falseString": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time at which previous execution of the command started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "If the input's value is converted to a string, use this when the value is false. If unspecified, its string value will be `\"false\"`",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"finishedAt": {
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIBoolInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time at which the command last terminated.",
+							Default: false,
+							Type:    []string{"boolean"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"value"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIBuildRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UIBuildRunning respresents an in-progress build/update in the user interface.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time when the build started.",
 							Default:     map[string]interface{}{},
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"warningCount": {
+					"spanID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number of warnings generated by this Tiltfile. (brief) reason the process is terminated",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "The log span where the build logs are stored in the logstore.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
@@ -6938,64 +9011,76 @@ func schema_pkg_apis_core_v1alpha1_TiltfileStateTerminated(ref common.ReferenceC
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_TiltfileStateWaiting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIBuildTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TiltfileStateWaiting is a waiting state of a tiltfile execution.",
+				Description: "UIBuildRunning respresents a finished build/update in the user interface.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"reason": {
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A non-empty string if the build failed with an error.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"warnings": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A list of warnings encountered while running the build. These warnings will also be printed to the build's log.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "(brief) reason the tiltfile is waiting.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The time when the build started.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-				},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_TiltfileStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "TiltfileStatus defines the observed state of Tiltfile",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"waiting": {
+					"finishTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a waiting tiltfile.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateWaiting"),
+							Description: "The time when the build finished.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"running": {
+					"spanID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a running tiltfile.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateRunning"),
+							Description: "The log span where the build logs are stored in the logstore.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"terminated": {
+					"isCrashRebuild": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Details about a terminated tiltfile.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateTerminated"),
+							Description: "A crash rebuild happens when Tilt live-updated a container, then the pod crashed, wiping out the live-updates. Tilt does a full build+deploy to reset the pod state to what's on disk.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltfileStateWaiting"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ToggleButton(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIButton(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ToggleButton",
+				Description: "UIButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -7021,28 +9106,28 @@ func schema_pkg_apis_core_v1alpha1_ToggleButton(ref common.ReferenceCallback) co
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonSpec"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStatus"),
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ToggleButtonList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIButtonList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ToggleButtonList",
+				Description: "UIButtonList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -7072,7 +9157,7 @@ func schema_pkg_apis_core_v1alpha1_ToggleButtonList(ref common.ReferenceCallback
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButton"),
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButton"),
 									},
 								},
 							},
@@ -7083,151 +9168,235 @@ func schema_pkg_apis_core_v1alpha1_ToggleButtonList(ref common.ReferenceCallback
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButton", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButton", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ToggleButtonSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIButtonSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ToggleButtonSpec defines the desired state of ToggleButton",
+				Description: "UIButtonSpec defines the desired state of UIButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"location": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Where to display the button",
+							Description: "Location associates the button with another component for layout.",
 							Default:     map[string]interface{}{},
 							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"),
 						},
 					},
-					"on": {
+					"text": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Config for the button when it is \"on\"",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStateSpec"),
+							Description: "Text to appear on the button itself or as hover text (depending on button location).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"off": {
+					"iconName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Config for the button when it is \"off\"",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStateSpec"),
+							Description: "IconName is a Material Icon to appear next to button text or on the button itself (depending on button location).\n\nValid values are icon font ligature names from the Material Icons set. See https://fonts.google.com/icons for the full list of available icons.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"defaultOn": {
+					"iconSVG": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If `StateSource` does not point at a valid value, the initial button state will be \"on\" or \"off\" depending on this bool",
-							Default:     false,
+							Description: "IconSVG is an SVG to use as the icon to appear next to button text or on the button itself (depending on button location).\n\nThis should be an <svg> element scaled for a 24x24 viewport.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, the button will be rendered, but with an effect indicating it's disabled. It will also be unclickable.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"stateSource": {
+					"requiresConfirmation": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Where the toggle button's state is stored",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StateSource"),
+							Description: "If true, the UI will require the user to click the button a second time to confirm before taking action",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"inputs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Any inputs for this button.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputSpec"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"location", "on", "off", "defaultOn", "stateSource"},
+				Required: []string{"location", "text"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.StateSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ToggleButtonStateSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputSpec"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ToggleButtonStateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIButtonStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Describes a state (on/off) of a ToggleButton",
+				Description: "UIButtonStatus defines the observed state of UIButton",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"text": {
+					"lastClickedAt": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Text to appear on the button itself or as hover text (depending on button location).",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "LastClickedAt is the timestamp of the last time the button was clicked.\n\nIf the button has never clicked before, this will be the zero-value/null.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"iconName": {
+					"inputs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IconName is a Material Icon to appear next to button text or on the button itself (depending on button location).\n\nValid values are icon font ligature names from the Material Icons set. See https://fonts.google.com/icons for the full list of available icons.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Status of any inputs on this button.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputStatus"),
+									},
+								},
+							},
 						},
 					},
-					"iconSVG": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIChoiceInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Describes a choice dropdown input field attached to a button",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"choices": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IconSVG is an SVG to use as the icon to appear next to button text or on the button itself (depending on button location).\n\nThis should be an <svg> element scaled for a 24x24 viewport.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The list of valid values for this field",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"requiresConfirmation": {
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIChoiceInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If true, clicking the button in this state requires a second click to confirm.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
 				},
-				Required: []string{"text"},
+				Required: []string{"value"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_ToggleButtonStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIComponentLocation(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ToggleButtonStatus defines the observed state of ToggleButton",
+				Description: "UIComponentLocation specifies where to put a UI component.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"error": {
+					"componentID": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If healthy, empty. If non-healthy, specifies a problem the ToggleButton encountered",
+							Description: "ComponentID is the identifier of the parent component to associate this component with.\n\nFor example, this is a resource name if the ComponentType is Resource.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"componentType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ComponentType is the type of the parent component.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"componentID", "componentType"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIBoolInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIComponentLocationResource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Describes a boolean checkbox input field attached to a button.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"defaultValue": {
+					"resourceName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether the input is initially true or false.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
-					"trueString": {
+				},
+				Required: []string{"resourceName"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIFeatureFlag(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Configures Tilt to enable non-default features (e.g., experimental or deprecated).\n\nThe Tilt features controlled by this are generally in an unfinished state, and not yet documented.\n\nAs a Tilt user, you don’t need to worry about this unless something else directs you to (e.g., an experimental feature doc, or a conversation with a Tilt contributor).",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If the input's value is converted to a string, use this when the value is true. If unspecified, its string value will be `\"true\"`",
+							Description: "The name of the flag.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"falseString": {
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If the input's value is converted to a string, use this when the value is false. If unspecified, its string value will be `\"false\"`",
-							Type:        []string{"string"},
+							Description: "The value of the flag.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
@@ -7237,16 +9406,17 @@ func schema_pkg_apis_core_v1alpha1_UIBoolInputSpec(ref common.ReferenceCallback)
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIBoolInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIHiddenInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Describes a hidden input field attached to a button, with a value to pass on any submit.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"value": {
 						SchemaProps: spec.SchemaProps{
-							Default: false,
-							Type:    []string{"boolean"},
+							Default: "",
+							Type:    []string{"string"},
 							Format:  "",
 						},
 					},
@@ -7257,152 +9427,135 @@ func schema_pkg_apis_core_v1alpha1_UIBoolInputStatus(ref common.ReferenceCallbac
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIBuildRunning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIHiddenInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UIBuildRunning respresents an in-progress build/update in the user interface.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startTime": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The time when the build started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-					"spanID": {
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The log span where the build logs are stored in the logstore.",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
 				},
+				Required: []string{"value"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIBuildTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UIBuildRunning respresents a finished build/update in the user interface.",
+				Description: "Defines an Input to render in the UI. If UIButton is analogous to an HTML <form>, UIInput is analogous to an HTML <input>.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"error": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A non-empty string if the build failed with an error.",
+							Description: "Name of this input. Must be unique within the UIButton.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"warnings": {
+					"label": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A list of warnings encountered while running the build. These warnings will also be printed to the build's log.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
-									},
-								},
-							},
+							Description: "A label to display next to this input in the UI.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"startTime": {
+					"text": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The time when the build started.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "A Text input that takes a string.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputSpec"),
 						},
 					},
-					"finishTime": {
+					"bool": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The time when the build finished.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
+							Description: "A Bool input that is true or false",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputSpec"),
 						},
 					},
-					"spanID": {
+					"hidden": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The log span where the build logs are stored in the logstore.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "An input that has a constant value and does not display to the user",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputSpec"),
 						},
 					},
-					"isCrashRebuild": {
+					"choice": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A crash rebuild happens when Tilt live-updated a container, then the pod crashed, wiping out the live-updates. Tilt does a full build+deploy to reset the pod state to what's on disk.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "A Choice input that takes a list of strings",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputSpec"),
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputSpec"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIButton(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UIButton",
+				Description: "The status corresponding to a UIInputSpec",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "Name of the input whose status this is. Must match the `Name` of a corresponding UIInputSpec.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"text": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The status of the input, if it's text",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputStatus"),
 						},
 					},
-					"metadata": {
+					"bool": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "The status of the input, if it's a bool",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputStatus"),
 						},
 					},
-					"spec": {
+					"hidden": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonSpec"),
+							Description: "The status of the input, if it's a hidden",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputStatus"),
 						},
 					},
-					"status": {
+					"choice": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonStatus"),
+							Description: "The status of the input, if it's a choice",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputStatus"),
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButtonStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputStatus"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIButtonList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIPanel(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UIButtonList",
+				Description: "UIPanel adds a tab to the web UI, next to a resource's logs or on the overview of all resources.\n\nThe tab either embeds another web page (like a database admin UI or a feature flag toggler) or renders Markdown.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -7422,407 +9575,249 @@ func schema_pkg_apis_core_v1alpha1_UIButtonList(ref common.ReferenceCallback) co
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"items": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButton"),
-									},
-								},
-							},
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanelSpec"),
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIButton", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanelSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIButtonSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIPanelList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UIButtonSpec defines the desired state of UIButton",
+				Description: "UIPanelList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"location": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Location associates the button with another component for layout.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"),
-						},
-					},
-					"text": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Text to appear on the button itself or as hover text (depending on button location).",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"iconName": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IconName is a Material Icon to appear next to button text or on the button itself (depending on button location).\n\nValid values are icon font ligature names from the Material Icons set. See https://fonts.google.com/icons for the full list of available icons.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"iconSVG": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IconSVG is an SVG to use as the icon to appear next to button text or on the button itself (depending on button location).\n\nThis should be an <svg> element scaled for a 24x24 viewport.\n\nIf both IconSVG and IconName are specified, IconSVG will take precedence.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"disabled": {
-						SchemaProps: spec.SchemaProps{
-							Description: "If true, the button will be rendered, but with an effect indicating it's disabled. It will also be unclickable.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"requiresConfirmation": {
-						SchemaProps: spec.SchemaProps{
-							Description: "If true, the UI will require the user to click the button a second time to confirm before taking action",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"inputs": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Any inputs for this button.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputSpec"),
-									},
-								},
-							},
-						},
-					},
-				},
-				Required: []string{"location", "text"},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputSpec"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_UIButtonStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "UIButtonStatus defines the observed state of UIButton",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"lastClickedAt": {
-						SchemaProps: spec.SchemaProps{
-							Description: "LastClickedAt is the timestamp of the last time the button was clicked.\n\nIf the button has never clicked before, this will be the zero-value/null.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
-						},
-					},
-					"inputs": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status of any inputs on this button.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputStatus"),
-									},
-								},
-							},
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIInputStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_UIChoiceInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Describes a choice dropdown input field attached to a button",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"choices": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The list of valid values for this field",
-							Type:        []string{"array"},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanel"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPanel", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIChoiceInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIPanelSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "UIPanelSpec defines the desired state of UIPanel",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"value": {
+					"location": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Description: "Location associates the panel with a resource, or with the overview of all resources if the ComponentType is Global.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"),
 						},
 					},
-				},
-				Required: []string{"value"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_UIComponentLocation(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "UIComponentLocation specifies where to put a UI component.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"componentID": {
+					"title": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ComponentID is the identifier of the parent component to associate this component with.\n\nFor example, this is a resource name if the ComponentType is Resource.",
+							Description: "Title is the text on the panel's tab.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"componentType": {
+					"url": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ComponentType is the type of the parent component.",
-							Default:     "",
+							Description: "URL of a web page to embed in the panel, in an iframe.\n\nExactly one of URL and Markdown must be set.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"componentID", "componentType"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_UIComponentLocationResource(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"resourceName": {
+					"markdown": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Description: "Markdown to render in the panel.\n\nExactly one of URL and Markdown must be set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"order": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Where the tab goes, relative to other panels in the same location. Tabs with lower numbers go first. Ties are sorted by title.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"resourceName"},
+				Required: []string{"location", "title"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIComponentLocation"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIFeatureFlag(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIPreferences(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Configures Tilt to enable non-default features (e.g., experimental or deprecated).\n\nThe Tilt features controlled by this are generally in an unfinished state, and not yet documented.\n\nAs a Tilt user, you don’t need to worry about this unless something else directs you to (e.g., an experimental feature doc, or a conversation with a Tilt contributor).",
+				Description: "UIPreferences holds one user's web UI settings, like how resources are grouped, which resources are pinned, and saved log filters.\n\nKeeping them here instead of in the browser means they follow the user to other browsers and survive clearing the browser cache.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the flag.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"value": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The value of the flag.",
-							Type:        []string{"boolean"},
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_UIHiddenInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Describes a hidden input field attached to a button, with a value to pass on any submit.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"value": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-				},
-				Required: []string{"value"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_core_v1alpha1_UIHiddenInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"value": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferencesSpec"),
 						},
 					},
 				},
-				Required: []string{"value"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferencesSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIInputSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIPreferencesList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Defines an Input to render in the UI. If UIButton is analogous to an HTML <form>, UIInput is analogous to an HTML <input>.",
+				Description: "UIPreferencesList",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of this input. Must be unique within the UIButton.",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"label": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A label to display next to this input in the UI.",
-							Default:     "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"text": {
-						SchemaProps: spec.SchemaProps{
-							Description: "A Text input that takes a string.",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputSpec"),
-						},
-					},
-					"bool": {
-						SchemaProps: spec.SchemaProps{
-							Description: "A Bool input that is true or false",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputSpec"),
-						},
-					},
-					"hidden": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "An input that has a constant value and does not display to the user",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputSpec"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"choice": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A Choice input that takes a list of strings",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputSpec"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferences"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputSpec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputSpec"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIPreferences", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_core_v1alpha1_UIInputStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_core_v1alpha1_UIPreferencesSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "The status corresponding to a UIInputSpec",
+				Description: "UIPreferencesSpec defines the desired state of UIPreferences",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"user": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the input whose status this is. Must match the `Name` of a corresponding UIInputSpec.",
+							Description: "The user these preferences belong to, e.g., their email when they signed in with single sign-on.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"text": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The status of the input, if it's text",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputStatus"),
-						},
-					},
-					"bool": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The status of the input, if it's a bool",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputStatus"),
-						},
-					},
-					"hidden": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The status of the input, if it's a hidden",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputStatus"),
-						},
-					},
-					"choice": {
+					"values": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The status of the input, if it's a choice",
-							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputStatus"),
+							Description: "Preferences by name. Each value is JSON, in whatever format the web UI uses for that preference.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"user"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBoolInputStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIChoiceInputStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIHiddenInputStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UITextInputStatus"},
 	}
 }
 
@@ -7925,6 +9920,35 @@ func schema_pkg_apis_core_v1alpha1_UIResourceCondition(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_UIResourceGroup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "How the UI displays a group of resources.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The path of the group, as labels separated by '/' (e.g., \"payments/api\").",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"collapsed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the user collapsed the group in the resource list.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_UIResourceKubernetes(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -8108,9 +10132,17 @@ func schema_pkg_apis_core_v1alpha1_UIResourceLocal(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources used by the actively running local command.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdResourceUsage"),
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.CmdResourceUsage"},
 	}
 }
 
@@ -8357,6 +10389,21 @@ func schema_pkg_apis_core_v1alpha1_UIResourceStatus(ref common.ReferenceCallback
 							},
 						},
 					},
+					"groups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Groups lists the resource groups this resource belongs to, sorted.\n\nEach group is a path of labels separated by '/', from the outermost group to the innermost (e.g., \"payments/api\"). Only the outermost group of each path appears in the object's labels.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -8604,11 +10651,25 @@ func schema_pkg_apis_core_v1alpha1_UISessionStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"resourceGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceGroups lists every resource group, including parent groups that have no resources of their own, in the order the UI should display them.\n\nEach group comes right before its subgroups.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceGroup"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltBuild", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIFeatureFlag", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.VersionSettings", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.TiltBuild", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIFeatureFlag", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceGroup", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.VersionSettings", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -4,11 +4,22 @@
 package procutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"syscall"
 )
 
+// Signals that a process can be asked to shut down with.
+var terminationSignals = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
 func SetOptNewProcessGroup(attrs *syscall.SysProcAttr) {
 	attrs.Setpgid = true
 }
@@ -22,10 +33,20 @@ func KillProcessGroup(cmd *exec.Cmd) {
 	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
 }
 
-func GracefullyShutdownProcess(p *os.Process) error {
+// Sends the named signal (like "SIGINT") to the process group, or SIGTERM
+// if the signal is empty.
+func GracefullyShutdownProcess(p *os.Process, signal string) error {
 	if p == nil {
 		return nil
 	}
 
-	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+	sig := syscall.SIGTERM
+	if signal != "" {
+		s, ok := terminationSignals[signal]
+		if !ok {
+			return fmt.Errorf("unsupported termination signal %q", signal)
+		}
+		sig = s
+	}
+	return syscall.Kill(-p.Pid, sig)
 }
@@ -19,6 +19,7 @@ func KillProcessGroup(cmd *exec.Cmd) {
 	}
 }
 
-func GracefullyShutdownProcess(p *os.Process) error {
+// Windows has no signals, so the signal is ignored.
+func GracefullyShutdownProcess(p *os.Process, signal string) error {
 	return exec.Command("TASKKILL", "/T", "/PID", fmt.Sprintf("%d", p.Pid)).Run()
 }
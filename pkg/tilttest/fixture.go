@@ -0,0 +1,123 @@
+// Package tilttest helps you write integration tests for controllers that
+// run inside Tilt.
+//
+// It wires together the same fakes that Tilt's own controller tests use:
+// an in-memory API server, a store that records actions, and fake clients
+// for local commands, Docker, Docker Compose and Kubernetes. Pass the fake
+// clients to your controller's constructor, then build a ControllerFixture
+// to create objects and reconcile them.
+//
+//	f := tilttest.NewFixture(t).WithClusters()
+//	r := NewReconciler(f.Client, f.Kubernetes, f.Execer)
+//	cf := f.Build(r)
+//	cf.Create(&v1alpha1.KubernetesApply{...})
+package tilttest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// The fakes are aliased here so that tests outside this module can name them.
+type (
+	ControllerFixture   = fake.ControllerFixture
+	Execer              = localexec.FakeExecer
+	DockerClient        = docker.FakeClient
+	DockerComposeClient = dockercompose.FakeDCClient
+	KubernetesClient    = k8s.FakeK8sClient
+)
+
+// The methods that a controller needs to run in a fixture.
+type Controller interface {
+	reconcile.Reconciler
+	CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error)
+}
+
+type Fixture struct {
+	*fake.ControllerFixtureBuilder
+	t testing.TB
+
+	Execer        *Execer
+	Docker        *DockerClient
+	DockerCompose *DockerComposeClient
+	Kubernetes    *KubernetesClient
+}
+
+func NewFixture(t *testing.T) *Fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	return &Fixture{
+		ControllerFixtureBuilder: cfb,
+		t:                        t,
+		Execer:                   localexec.NewFakeExecer(t),
+		Docker:                   docker.NewFakeClient(),
+		DockerCompose:            dockercompose.NewFakeDockerComposeClient(t, cfb.Context()),
+		Kubernetes:               k8s.NewFakeK8sClient(t),
+	}
+}
+
+// Creates the clusters that a Tiltfile creates, already connected, so that
+// controllers that deploy to them don't wait for the cluster controller.
+func (f *Fixture) WithClusters() *Fixture {
+	ctx := f.Context()
+	err := f.Client.Create(ctx, &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ClusterNameDefault},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	})
+	require.NoError(f.T(), err)
+
+	err = f.Client.Create(ctx, &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ClusterNameDocker},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Docker: &v1alpha1.DockerClusterConnection{},
+			},
+		},
+	})
+	require.NoError(f.T(), err)
+
+	f.setConnected(v1alpha1.ClusterNameDefault, v1alpha1.ClusterConnectionStatus{
+		Kubernetes: &v1alpha1.KubernetesClusterConnectionStatus{
+			Context: "default",
+		},
+	})
+	f.setConnected(v1alpha1.ClusterNameDocker, v1alpha1.ClusterConnectionStatus{})
+	return f
+}
+
+func (f *Fixture) setConnected(name string, connection v1alpha1.ClusterConnectionStatus) {
+	ctx := f.Context()
+	var cluster v1alpha1.Cluster
+	err := f.Client.Get(ctx, types.NamespacedName{Name: name}, &cluster)
+	require.NoError(f.T(), err)
+
+	cluster.Status.Arch = "amd64"
+	cluster.Status.Connection = &connection
+	err = f.Client.Status().Update(ctx, &cluster)
+	require.NoError(f.T(), err)
+}
+
+// Builds a fixture that reconciles objects with the given controller.
+func (f *Fixture) Build(c Controller) *ControllerFixture {
+	return f.ControllerFixtureBuilder.Build(c)
+}
+
+func (f *Fixture) T() testing.TB {
+	return f.t
+}
@@ -0,0 +1,47 @@
+package tilttest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
+	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/tilttest"
+)
+
+func TestApplyToFakeCluster(t *testing.T) {
+	f := tilttest.NewFixture(t).WithClusters()
+	db := build.NewDockerBuilder(f.Docker, dockerfile.Labels{})
+	r := kubernetesapply.NewReconciler(f.Client, f.Kubernetes, f.Scheme(), db, f.Store, f.Execer)
+	cf := f.Build(r)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "sancho"},
+		Spec:       v1alpha1.KubernetesApplySpec{YAML: testyaml.SanchoYAML},
+	}
+	cf.Create(&ka)
+
+	assert.Contains(t, f.Kubernetes.Yaml, "name: sancho")
+	cf.MustGet(types.NamespacedName{Name: "sancho"}, &ka)
+	assert.Contains(t, ka.Status.ResultYAML, "name: sancho")
+	assert.Empty(t, ka.Status.Error)
+}
+
+func TestClustersAreConnected(t *testing.T) {
+	f := tilttest.NewFixture(t).WithClusters()
+
+	for _, name := range []string{v1alpha1.ClusterNameDefault, v1alpha1.ClusterNameDocker} {
+		var cluster v1alpha1.Cluster
+		err := f.Client.Get(f.Context(), types.NamespacedName{Name: name}, &cluster)
+		if assert.NoError(t, err) {
+			assert.NotNil(t, cluster.Status.Connection, name)
+			assert.Empty(t, cluster.Status.Error, name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+import { render, screen } from "@testing-library/react"
+import React from "react"
+import {
+  AlertBadge,
+  firingAlertsByResource,
+  firingAlertsContext,
+} from "./AlertRules"
+import { AlertRule } from "./types"
+
+function rule(name: string, resources: string[]): AlertRule {
+  return {
+    metadata: { name },
+    spec: { metric: "BuildDuration", duration: "2m0s" },
+    status: {
+      firing: resources.map((resource) => ({
+        resource,
+        value: "3m0s",
+        message: "last build took 3m0s, more than 2m0s",
+        since: "2021-03-01T12:00:00.000000Z",
+      })),
+    },
+  }
+}
+
+describe("AlertRules", () => {
+  it("groups firing alerts by resource", () => {
+    let alerts = firingAlertsByResource([
+      rule("slow-builds", ["fe", "be"]),
+      rule("crash-loops", ["fe"]),
+      rule("quiet", []),
+    ])
+    expect(Object.keys(alerts).sort()).toEqual(["be", "fe"])
+    expect(alerts["fe"].map((a) => a.rule)).toEqual([
+      "crash-loops",
+      "slow-builds",
+    ])
+    expect(alerts["be"][0].message).toEqual(
+      "last build took 3m0s, more than 2m0s"
+    )
+  })
+
+  it("shows a badge when alerts are firing", () => {
+    let alerts = firingAlertsByResource([
+      rule("slow-builds", ["fe"]),
+      rule("crash-loops", ["fe"]),
+    ])
+    render(
+      <firingAlertsContext.Provider value={alerts}>
+        <AlertBadge resourceName="fe" />
+        <AlertBadge resourceName="be" />
+      </firingAlertsContext.Provider>
+    )
+    expect(screen.getByRole("status")).toHaveTextContent("2 alerts")
+    expect(screen.getByLabelText("2 alerts firing for fe")).toBeInTheDocument()
+    expect(screen.queryByLabelText(/firing for be/)).toBeNull()
+  })
+})
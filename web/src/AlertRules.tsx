@@ -0,0 +1,144 @@
+import React, {
+  PropsWithChildren,
+  useContext,
+  useEffect,
+  useState,
+} from "react"
+import styled from "styled-components"
+import { Color, Font, FontSize, SizeUnit } from "./style-helpers"
+import TiltTooltip from "./Tooltip"
+import { AlertRule } from "./types"
+
+// How often to check whether alerts started or stopped firing.
+const alertPollIntervalMs = 5000
+
+// An alert firing for a resource, with the name of its rule.
+export type FiringAlert = {
+  rule: string
+  value: string
+  message: string
+  since: string
+}
+
+export type FiringAlerts = { [resource: string]: FiringAlert[] }
+
+export const firingAlertsContext = React.createContext<FiringAlerts>({})
+
+async function fetchAlertRules(): Promise<AlertRule[]> {
+  const resp = await fetch("/proxy/apis/tilt.dev/v1alpha1/alertrules", {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching alert rules: ${body}`
+  }
+  const list = await resp.json()
+  return list.items || []
+}
+
+// Groups the firing alerts of every rule by resource, sorted by rule name.
+export function firingAlertsByResource(rules: AlertRule[]): FiringAlerts {
+  let result: FiringAlerts = {}
+  rules.forEach((rule) => {
+    let name = rule.metadata?.name || ""
+    ;(rule.status?.firing || []).forEach((f) => {
+      let resource = f.resource || ""
+      result[resource] = result[resource] || []
+      result[resource].push({
+        rule: name,
+        value: f.value || "",
+        message: f.message || "",
+        since: f.since || "",
+      })
+    })
+  })
+  Object.values(result).forEach((alerts) =>
+    alerts.sort((a, b) => a.rule.localeCompare(b.rule))
+  )
+  return result
+}
+
+// Keeps the firing alerts up to date for the badges below it.
+export function AlertRulesProvider(
+  props: PropsWithChildren<{ disabled?: boolean }>
+) {
+  let [alerts, setAlerts] = useState<FiringAlerts>({})
+  useEffect(() => {
+    if (props.disabled) {
+      return
+    }
+    let cancelled = false
+    let load = () => {
+      fetchAlertRules()
+        .then((rules) => {
+          if (!cancelled) {
+            setAlerts(firingAlertsByResource(rules))
+          }
+        })
+        .catch((err) => console.error(err))
+    }
+    load()
+    let interval = setInterval(load, alertPollIntervalMs)
+    return () => {
+      cancelled = true
+      clearInterval(interval)
+    }
+  }, [props.disabled])
+
+  return (
+    <firingAlertsContext.Provider value={alerts}>
+      {props.children}
+    </firingAlertsContext.Provider>
+  )
+}
+
+export function useFiringAlerts(resourceName: string): FiringAlert[] {
+  return useContext(firingAlertsContext)[resourceName] || []
+}
+
+let AlertBadgeRoot = styled.span`
+  background-color: ${Color.red};
+  border-radius: ${SizeUnit(0.5)};
+  color: ${Color.white};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  line-height: 1;
+  margin-left: ${SizeUnit(0.25)};
+  padding: 2px ${SizeUnit(0.2)};
+  white-space: nowrap;
+`
+
+let AlertList = styled.ul`
+  margin: 0;
+  padding-left: ${SizeUnit(0.5)};
+`
+
+// Shows how many alerts are firing for a resource, with their messages
+// in a tooltip. Renders nothing if none are.
+export function AlertBadge(props: { resourceName: string }) {
+  let alerts = useFiringAlerts(props.resourceName)
+  if (alerts.length === 0) {
+    return null
+  }
+
+  let title = (
+    <AlertList>
+      {alerts.map((a) => (
+        <li key={a.rule}>
+          <strong>{a.rule}</strong>: {a.message}
+        </li>
+      ))}
+    </AlertList>
+  )
+  let label = alerts.length === 1 ? "1 alert" : `${alerts.length} alerts`
+  return (
+    <TiltTooltip title={title}>
+      <AlertBadgeRoot
+        role="status"
+        aria-label={`${label} firing for ${props.resourceName}`}
+      >
+        {label}
+      </AlertBadgeRoot>
+    </TiltTooltip>
+  )
+}
@@ -51,4 +51,27 @@ describe("AppController", () => {
     expect(fakeSetHistoryLocation.mock.calls.length).toBe(1)
     expect(fakeSetHistoryLocation.mock.calls[0][0]).toBe("/snapshot/aaaaaa/foo")
   })
+  it("loads replay frames without changing the path", async () => {
+    fetchMock.mock(
+      "/api/snapshot/3",
+      JSON.stringify({
+        view: { uiResources: [] },
+        path: "/foo",
+        createdAt: "2021-01-01T00:00:03Z",
+      })
+    )
+
+    fakeOnAppChange.mockReset()
+
+    let pb = PathBuilder.forTesting("localhost", "/snapshot/local")
+    let ac = new AppController(pb, HUD)
+    ac.loadReplayFrame("3")
+
+    await flushPromises()
+    expect(fakeOnAppChange.mock.calls.length).toBe(1)
+    expect(fakeOnAppChange.mock.calls[0][0].snapshotStartTime).toBe(
+      "2021-01-01T00:00:03Z"
+    )
+    expect(fakeSetHistoryLocation.mock.calls.length).toBe(0)
+  })
 })
@@ -117,7 +117,16 @@ class AppController {
   }
 
   setStateFromSnapshot(): void {
-    let url = this.url
+    this.loadSnapshot(this.url, true)
+  }
+
+  // Shows one frame of a session served by `tilt replay`,
+  // without moving away from the page the user is on.
+  loadReplayFrame(id: string): void {
+    this.loadSnapshot(`/api/snapshot/${id}`, false)
+  }
+
+  private loadSnapshot(url: string, followPath: boolean): void {
     fetch(url)
       .then((resp) => resp.json())
       .then((data: Snapshot) => {
@@ -128,7 +137,7 @@ class AppController {
           snapshotStartTime: data.createdAt,
         })
 
-        if (data.path) {
+        if (followPath && data.path) {
           this.component.setHistoryLocation(this.pb.path(data.path))
         }
       })
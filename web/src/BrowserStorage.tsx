@@ -1,5 +1,6 @@
 import React, { Dispatch, SetStateAction, useContext } from "react"
 import { useStorageState } from "react-storage-hooks"
+import { useServerSync } from "./ServerPreferences"
 
 export type TiltfileKey = string
 export const tiltfileKeyContext = React.createContext<TiltfileKey>("unset")
@@ -33,7 +34,8 @@ export function accessorsForTesting<S>(name: string, storage: Storage) {
   }
 }
 
-// Like `useState`, but backed by localStorage and namespaced by the tiltfileKey
+// Like `useState`, but backed by localStorage and namespaced by the tiltfileKey,
+// and saved on the Tilt server so that it follows the user across browsers
 // maybeUpgradeSavedState: transforms any state read from storage - allows, e.g., filling in default values for
 //                         fields added since the state was saved
 export function usePersistentState<S>(
@@ -41,12 +43,17 @@ export function usePersistentState<S>(
   defaultValue: S,
   maybeUpgradeSavedState?: (state: S) => S
 ): [state: S, setState: Dispatch<SetStateAction<S>>] {
-  return useBrowserStorageState(
+  const tiltfileKey = useContext(tiltfileKeyContext)
+  const [state, setState] = useBrowserStorageState(
     name,
     defaultValue,
     localStorage,
     maybeUpgradeSavedState
   )
+  // Don't save anything until we know which Tiltfile it's for.
+  const serverName = tiltfileKey ? makeKey(tiltfileKey, name) : null
+  useServerSync(serverName, state, setState, defaultValue)
+  return [state, setState]
 }
 
 // Like `useState`, but backed by sessionStorage and namespaced by the tiltfileKey
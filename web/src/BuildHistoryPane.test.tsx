@@ -0,0 +1,43 @@
+import {
+  buildDurationSecs,
+  formatSecs,
+  HistoryScope,
+  percentile,
+  recordsInScope,
+} from "./BuildHistoryPane"
+
+describe("BuildHistoryPane", () => {
+  it("computes build durations", () => {
+    expect(
+      buildDurationSecs({
+        startTime: "2021-03-01T12:00:00.000000Z",
+        finishTime: "2021-03-01T12:00:02.500000Z",
+      })
+    ).toEqual(2.5)
+    expect(buildDurationSecs({})).toEqual(0)
+  })
+
+  it("computes percentiles", () => {
+    expect(percentile([], 0.5)).toEqual(0)
+    expect(percentile([3, 1, 2], 0.5)).toEqual(2)
+    expect(percentile([1, 2, 3, 4, 5, 6, 7, 8, 9, 10], 0.9)).toEqual(9)
+    expect(percentile([1, 2], 1)).toEqual(2)
+  })
+
+  it("formats durations", () => {
+    expect(formatSecs(2.54)).toEqual("2.5s")
+    expect(formatSecs(125)).toEqual("2m5s")
+  })
+
+  it("filters records to the current session", () => {
+    let records = [
+      { sessionStartTime: "2021-03-01T11:00:00.123456Z", reason: "old" },
+      { sessionStartTime: "2021-03-01T12:00:00.654321Z", reason: "new" },
+    ]
+    let start = "2021-03-01T12:00:00Z"
+    expect(
+      recordsInScope(records, HistoryScope.Session, start).map((r) => r.reason)
+    ).toEqual(["new"])
+    expect(recordsInScope(records, HistoryScope.All, start)).toEqual(records)
+  })
+})
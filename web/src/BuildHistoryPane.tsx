@@ -0,0 +1,327 @@
+import React, { useEffect, useState } from "react"
+import styled from "styled-components"
+import { AnalyticsType } from "./analytics"
+import HeaderBar from "./HeaderBar"
+import { usePathBuilder } from "./PathBuilder"
+import {
+  Color,
+  ColorAlpha,
+  ColorRGBA,
+  Font,
+  FontSize,
+  SizeUnit,
+} from "./style-helpers"
+
+type BuildHistoryPaneProps = {
+  view: Proto.webviewView
+  isSocketConnected: boolean
+}
+
+type BuildHistoryRecord = Proto.v1alpha1BuildHistoryRecord
+
+export enum HistoryScope {
+  Session = "session",
+  All = "all",
+}
+
+// How long a build took, in seconds.
+export function buildDurationSecs(r: BuildHistoryRecord): number {
+  if (!r.startTime || !r.finishTime) {
+    return 0
+  }
+  return (Date.parse(r.finishTime) - Date.parse(r.startTime)) / 1000
+}
+
+// The UISession reports its start time to the second, while build records
+// have the precise time, so match on the same second.
+export function recordsInScope(
+  records: BuildHistoryRecord[],
+  scope: HistoryScope,
+  tiltStartTime?: string
+): BuildHistoryRecord[] {
+  if (scope === HistoryScope.All || !tiltStartTime) {
+    return records
+  }
+  let sessionStart = Date.parse(tiltStartTime)
+  return records.filter((r) => {
+    if (!r.sessionStartTime) {
+      return false
+    }
+    let t = Date.parse(r.sessionStartTime)
+    return t >= sessionStart && t < sessionStart + 1000
+  })
+}
+
+// The value that p (between 0 and 1) of the values are at or below.
+export function percentile(values: number[], p: number): number {
+  if (values.length === 0) {
+    return 0
+  }
+  let sorted = [...values].sort((a, b) => a - b)
+  let i = Math.ceil(p * sorted.length) - 1
+  return sorted[Math.min(sorted.length - 1, Math.max(0, i))]
+}
+
+export function formatSecs(secs: number): string {
+  if (secs < 60) {
+    return `${secs.toFixed(1)}s`
+  }
+  let m = Math.floor(secs / 60)
+  let s = Math.round(secs % 60)
+  return `${m}m${s}s`
+}
+
+async function fetchBuildHistories(): Promise<Proto.v1alpha1BuildHistory[]> {
+  const resp = await fetch("/proxy/apis/tilt.dev/v1alpha1/buildhistories", {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching build history: ${body}`
+  }
+  const list = await resp.json()
+  return list.items || []
+}
+
+let BuildHistoryPaneRoot = styled.div`
+  display: flex;
+  flex-direction: column;
+  width: 100%;
+  height: 100vh;
+  background-color: ${Color.gray20};
+  max-height: 100%;
+`
+
+let Main = styled.div`
+  flex: 1 1 100%;
+  overflow: auto;
+  padding: ${SizeUnit(0.5)} ${SizeUnit(1)};
+  font-family: ${Font.monospace};
+  color: ${Color.gray70};
+`
+
+let Toolbar = styled.div`
+  display: flex;
+  align-items: center;
+  margin-bottom: ${SizeUnit(0.5)};
+  font-size: ${FontSize.small};
+`
+
+let ScopeButton = styled.button`
+  background-color: transparent;
+  border: 1px solid ${Color.gray50};
+  color: ${Color.gray70};
+  cursor: pointer;
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  margin-left: ${SizeUnit(0.25)};
+  padding: ${SizeUnit(0.1)} ${SizeUnit(0.3)};
+
+  &.isSelected {
+    background-color: ${Color.gray40};
+    border-color: ${Color.blue};
+  }
+`
+
+let ResourceHistory = styled.section`
+  background-color: ${Color.gray30};
+  border-radius: 6px;
+  margin-bottom: ${SizeUnit(0.5)};
+  padding: ${SizeUnit(0.3)} ${SizeUnit(0.5)};
+`
+
+let ResourceHistoryHeader = styled.header`
+  display: flex;
+  align-items: baseline;
+  justify-content: space-between;
+  font-size: ${FontSize.small};
+`
+
+let Stats = styled.span`
+  color: ${Color.grayLightest};
+  font-size: ${FontSize.smallest};
+
+  & > span {
+    margin-left: ${SizeUnit(0.5)};
+  }
+`
+
+let Message = styled.div`
+  font-size: ${FontSize.small};
+  padding: ${SizeUnit(1)};
+  text-align: center;
+`
+
+const chartHeight = 80
+const barWidth = 8
+const barGap = 2
+
+function DurationChart(props: { records: BuildHistoryRecord[] }) {
+  let durations = props.records.map(buildDurationSecs)
+  let max = Math.max(1, ...durations)
+  let width = props.records.length * (barWidth + barGap)
+  return (
+    <svg
+      width="100%"
+      height={chartHeight}
+      viewBox={`0 0 ${Math.max(width, 1)} ${chartHeight}`}
+      preserveAspectRatio="xMinYMax meet"
+      role="img"
+      aria-label="Build durations"
+    >
+      {props.records.map((r, i) => {
+        let h = Math.max(1, (durations[i] / max) * chartHeight)
+        let color = r.error ? Color.red : Color.green
+        let title = [
+          `${formatSecs(durations[i])} ${r.error ? "failed" : "succeeded"}`,
+          r.startTime ? new Date(r.startTime).toLocaleString() : "",
+          r.reason || "",
+          r.error || "",
+          ...(r.edits || []),
+        ]
+          .filter((line) => line)
+          .join("\n")
+        return (
+          <rect
+            key={i}
+            x={i * (barWidth + barGap)}
+            y={chartHeight - h}
+            width={barWidth}
+            height={h}
+            fill={color}
+          >
+            <title>{title}</title>
+          </rect>
+        )
+      })}
+      <line
+        x1={0}
+        x2={Math.max(width, 1)}
+        y1={chartHeight - (percentile(durations, 0.5) / max) * chartHeight}
+        y2={chartHeight - (percentile(durations, 0.5) / max) * chartHeight}
+        stroke={ColorRGBA(Color.white, ColorAlpha.translucent)}
+        strokeDasharray="2,2"
+      />
+    </svg>
+  )
+}
+
+export function ResourceBuildHistory(props: {
+  name: string
+  records: BuildHistoryRecord[]
+}) {
+  let durations = props.records.map(buildDurationSecs)
+  let failures = props.records.filter((r) => r.error).length
+  return (
+    <ResourceHistory aria-label={`Build history of ${props.name}`}>
+      <ResourceHistoryHeader>
+        <span>{props.name}</span>
+        <Stats>
+          <span>{props.records.length} builds</span>
+          <span>median {formatSecs(percentile(durations, 0.5))}</span>
+          <span>p90 {formatSecs(percentile(durations, 0.9))}</span>
+          <span>{failures} failed</span>
+        </Stats>
+      </ResourceHistoryHeader>
+      <DurationChart records={props.records} />
+    </ResourceHistory>
+  )
+}
+
+// Charts how long each resource takes to build, in the current session
+// and across sessions, so that teams can spot dev-loop regressions.
+export default function BuildHistoryPane(props: BuildHistoryPaneProps) {
+  let isSnapshot = usePathBuilder().isSnapshot()
+  let [scope, setScope] = useState(HistoryScope.Session)
+  let [histories, setHistories] = useState<Proto.v1alpha1BuildHistory[]>([])
+  let [error, setError] = useState("")
+
+  let resources = props.view.uiResources || []
+  let tiltStartTime = props.view.uiSession?.status?.tiltStartTime
+
+  // Re-fetch whenever a build finishes.
+  let lastBuilds = resources
+    .map((r) => r.status?.buildHistory?.[0]?.finishTime || "")
+    .join(",")
+  useEffect(() => {
+    if (isSnapshot) {
+      return
+    }
+    let cancelled = false
+    fetchBuildHistories()
+      .then((items) => {
+        if (!cancelled) {
+          setHistories(items)
+          setError("")
+        }
+      })
+      .catch((err) => {
+        if (!cancelled) {
+          setError(String(err))
+        }
+      })
+    return () => {
+      cancelled = true
+    }
+  }, [lastBuilds, isSnapshot])
+
+  let byName = new Map<string, BuildHistoryRecord[]>()
+  histories.forEach((h) => {
+    if (h.metadata?.name) {
+      byName.set(h.metadata.name, h.status?.builds || [])
+    }
+  })
+
+  let sections = resources
+    .map((r) => {
+      let name = r.metadata?.name || ""
+      let records = recordsInScope(
+        byName.get(name) || [],
+        scope,
+        tiltStartTime
+      )
+      return { name, records }
+    })
+    .filter((s) => s.records.length > 0)
+
+  let content: React.ReactNode
+  if (isSnapshot) {
+    content = <Message>Build history isn't available in snapshots.</Message>
+  } else if (error) {
+    content = <Message>{error}</Message>
+  } else if (sections.length === 0) {
+    content = <Message>No builds have finished yet.</Message>
+  } else {
+    content = sections.map((s) => (
+      <ResourceBuildHistory key={s.name} name={s.name} records={s.records} />
+    ))
+  }
+
+  return (
+    <BuildHistoryPaneRoot>
+      <HeaderBar
+        view={props.view}
+        currentPage={AnalyticsType.History}
+        isSocketConnected={props.isSocketConnected}
+      />
+      <Main>
+        <Toolbar>
+          Build durations
+          <ScopeButton
+            className={scope === HistoryScope.Session ? "isSelected" : ""}
+            onClick={() => setScope(HistoryScope.Session)}
+          >
+            This session
+          </ScopeButton>
+          <ScopeButton
+            className={scope === HistoryScope.All ? "isSelected" : ""}
+            onClick={() => setScope(HistoryScope.All)}
+          >
+            All sessions
+          </ScopeButton>
+        </Toolbar>
+        {content}
+      </Main>
+    </BuildHistoryPaneRoot>
+  )
+}
@@ -25,9 +25,11 @@ import { UIButton } from "./types"
  * The BulkApiButton supports toggle and non-toggle buttons that may require
  * confirmation.
  *
+ * Actions that resources don't have UIButtons for, like triggering an
+ * update, go through the bulk actions API instead (see `runAction`).
+ *
  * In the future, it may need to be expanded to share more of the UIButton
- * options (like specifying an icon svg or having a form with inputs), or
- * it may need to support non-UIButton bulk actions.
+ * options (like specifying an icon svg or having a form with inputs).
  */
 
 // Types
@@ -38,6 +40,10 @@ type BulkApiButtonProps = ButtonProps & {
   requiresConfirmation: boolean
   targetToggleState?: ApiButtonToggleState
   uiButtons: UIButton[]
+  // Runs the action with the bulk actions API instead of updating the
+  // uiButtons, for actions that resources don't have buttons for.
+  runAction?: () => Promise<void>
+  bulkCount?: number
 }
 
 type BulkApiButtonElementProps = ButtonProps & {
@@ -151,6 +157,22 @@ export function canBulkButtonBeToggled(
   )
 }
 
+// Triggers, enables, or disables resources in dependency order on the server.
+export async function runBulkResourceAction(
+  action: "trigger" | "enable" | "disable",
+  selection: { resources?: string[]; labels?: string[] }
+) {
+  const resp = await fetch("/api/bulk", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ action, ...selection }),
+  })
+  if (resp.status !== 202) {
+    const body = await resp.json().catch(() => ({}))
+    throw body.error || `status ${resp.status}`
+  }
+}
+
 async function bulkUpdateButtonStatus(uiButtons: UIButton[]) {
   try {
     await Promise.all(uiButtons.map((button) => updateButtonStatus(button, {})))
@@ -242,6 +264,8 @@ export function BulkApiButton(props: BulkApiButtonProps) {
     requiresConfirmation,
     onClickCallback,
     uiButtons,
+    runAction,
+    bulkCount,
     ...buttonProps
   } = props
 
@@ -250,7 +274,7 @@ export function BulkApiButton(props: BulkApiButtonProps) {
   const [loading, setLoading] = useState(false)
   const [confirming, setConfirming] = useState(false)
 
-  let buttonCount = String(uiButtons.length)
+  let buttonCount = String(bulkCount ?? uiButtons.length)
   const analyticsTags: Tags = useMemo(() => {
     let tags: Tags = {
       component: ApiButtonType.Global,
@@ -271,10 +295,9 @@ export function BulkApiButton(props: BulkApiButtonProps) {
     return tags
   }, [buttonCount, bulkAction, targetToggleState])
 
-  const bulkActionDisabled = !canBulkButtonBeToggled(
-    uiButtons,
-    targetToggleState
-  )
+  const bulkActionDisabled = runAction
+    ? false
+    : !canBulkButtonBeToggled(uiButtons, targetToggleState)
   const disabled = loading || bulkActionDisabled || false
   const buttonGroupClassName = `${disabled ? "isDisabled" : "isEnabled"} ${
     confirming ? "isConfirming" : ""
@@ -301,6 +324,11 @@ export function BulkApiButton(props: BulkApiButtonProps) {
     setLoading(true)
 
     try {
+      if (runAction) {
+        await runAction()
+        return
+      }
+
       // If there's a target toggle state, filter out buttons that
       // already have that toggle state. If they're not filtered out
       // updating them will toggle them to an unintended state.
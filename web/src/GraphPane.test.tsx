@@ -0,0 +1,54 @@
+import { graphLayers, layoutGraph, ResourceGraph } from "./GraphPane"
+
+describe("GraphPane", () => {
+  let graph: ResourceGraph = {
+    nodes: [
+      { id: "resource:web", kind: "resource", name: "web" },
+      { id: "resource:db", kind: "resource", name: "db" },
+      { id: "resource:api", kind: "resource", name: "api" },
+      { id: "image:api", kind: "image", name: "api" },
+    ],
+    edges: [
+      { from: "resource:api", to: "resource:web", kind: "resource_dep" },
+      { from: "resource:db", to: "resource:api", kind: "resource_dep" },
+      { from: "resource:db", to: "resource:web", kind: "resource_dep" },
+      { from: "image:api", to: "resource:api", kind: "image" },
+    ],
+    criticalPath: [],
+  }
+
+  it("puts nodes one column past their deepest dependency", () => {
+    expect(graphLayers(graph)).toEqual([
+      ["resource:db", "image:api"],
+      ["resource:api"],
+      ["resource:web"],
+    ])
+  })
+
+  it("lays out columns left to right", () => {
+    let positions = layoutGraph(graph)
+    expect(positions.get("resource:db")).toEqual({ x: 0, y: 0 })
+    expect(positions.get("image:api")!.x).toEqual(0)
+    expect(positions.get("image:api")!.y).toBeGreaterThan(0)
+    expect(positions.get("resource:api")!.x).toBeGreaterThan(0)
+    expect(positions.get("resource:web")!.x).toBeGreaterThan(
+      positions.get("resource:api")!.x
+    )
+  })
+
+  it("survives cycles", () => {
+    let cyclic: ResourceGraph = {
+      nodes: [
+        { id: "a", kind: "resource", name: "a" },
+        { id: "b", kind: "resource", name: "b" },
+      ],
+      edges: [
+        { from: "a", to: "b", kind: "resource_dep" },
+        { from: "b", to: "a", kind: "resource_dep" },
+      ],
+      criticalPath: [],
+    }
+    let placed = graphLayers(cyclic).reduce((n, layer) => n + layer.length, 0)
+    expect(placed).toEqual(2)
+  })
+})
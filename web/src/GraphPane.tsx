@@ -0,0 +1,355 @@
+import React, { useEffect, useState } from "react"
+import { useHistory } from "react-router"
+import styled from "styled-components"
+import { AnalyticsType } from "./analytics"
+import HeaderBar from "./HeaderBar"
+import { usePathBuilder } from "./PathBuilder"
+import {
+  Color,
+  ColorAlpha,
+  ColorRGBA,
+  Font,
+  FontSize,
+  SizeUnit,
+} from "./style-helpers"
+
+type GraphPaneProps = {
+  view: Proto.webviewView
+  isSocketConnected: boolean
+}
+
+// The resource graph, as served by /api/graph.
+//
+// Edges point from a dependency to the node that depends on it.
+export type GraphNode = {
+  id: string
+  kind: "resource" | "image"
+  name: string
+  resources?: string[]
+  updateStatus?: string
+  runtimeStatus?: string
+  readiness?: string
+  critical?: boolean
+}
+
+export type GraphEdge = {
+  from: string
+  to: string
+  kind: "resource_dep" | "compose_depends_on" | "image" | "image_dep"
+  critical?: boolean
+}
+
+export type ResourceGraph = {
+  nodes: GraphNode[]
+  edges: GraphEdge[]
+  criticalPath: string[]
+}
+
+export type NodePosition = { x: number; y: number }
+
+const nodeWidth = 160
+const nodeHeight = 32
+const columnGap = 64
+const rowGap = 16
+
+// Puts each node in a column one past the deepest of its dependencies,
+// so that edges always point left to right.
+export function graphLayers(graph: ResourceGraph): string[][] {
+  let deps = new Map<string, string[]>()
+  graph.edges.forEach((e) => {
+    deps.set(e.to, [...(deps.get(e.to) || []), e.from])
+  })
+
+  let depths = new Map<string, number>()
+  let visiting = new Set<string>()
+  let depth = (id: string): number => {
+    let known = depths.get(id)
+    if (known !== undefined) {
+      return known
+    }
+    if (visiting.has(id)) {
+      // A cycle. The Tiltfile rejects these, so just stop here.
+      return 0
+    }
+    visiting.add(id)
+    let d = 0
+    ;(deps.get(id) || []).forEach((dep) => {
+      d = Math.max(d, depth(dep) + 1)
+    })
+    visiting.delete(id)
+    depths.set(id, d)
+    return d
+  }
+
+  let layers: string[][] = []
+  graph.nodes.forEach((n) => {
+    let d = depth(n.id)
+    while (layers.length <= d) {
+      layers.push([])
+    }
+    layers[d].push(n.id)
+  })
+  return layers
+}
+
+// Where the top-left corner of each node goes.
+export function layoutGraph(graph: ResourceGraph): Map<string, NodePosition> {
+  let positions = new Map<string, NodePosition>()
+  graphLayers(graph).forEach((layer, col) => {
+    layer.forEach((id, row) => {
+      positions.set(id, {
+        x: col * (nodeWidth + columnGap),
+        y: row * (nodeHeight + rowGap),
+      })
+    })
+  })
+  return positions
+}
+
+function nodeColor(n: GraphNode): string {
+  if (n.kind === "image") {
+    return Color.purple
+  }
+  switch (n.readiness) {
+    case "ready":
+      return Color.green
+    case "error":
+      return Color.red
+    case "pending":
+      return Color.yellow
+    default:
+      return Color.gray50
+  }
+}
+
+async function fetchGraph(): Promise<ResourceGraph> {
+  const resp = await fetch("/api/graph", {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching resource graph: ${body}`
+  }
+  return await resp.json()
+}
+
+let GraphPaneRoot = styled.div`
+  display: flex;
+  flex-direction: column;
+  width: 100%;
+  height: 100vh;
+  background-color: ${Color.gray20};
+  max-height: 100%;
+`
+
+let Main = styled.div`
+  flex: 1 1 100%;
+  overflow: auto;
+  padding: ${SizeUnit(0.5)} ${SizeUnit(1)};
+  font-family: ${Font.monospace};
+  color: ${Color.gray70};
+`
+
+let Toolbar = styled.div`
+  margin-bottom: ${SizeUnit(0.5)};
+  font-size: ${FontSize.small};
+`
+
+let CriticalPath = styled.span`
+  color: ${Color.yellow};
+  margin-left: ${SizeUnit(0.5)};
+`
+
+let Message = styled.div`
+  font-size: ${FontSize.small};
+  padding: ${SizeUnit(1)};
+  text-align: center;
+`
+
+let ResourceNode = styled.g`
+  cursor: pointer;
+
+  &:hover rect {
+    stroke: ${Color.blue};
+  }
+`
+
+export function ResourceGraphView(props: { graph: ResourceGraph }) {
+  let pb = usePathBuilder()
+  let history = useHistory()
+  let graph = props.graph
+  let positions = layoutGraph(graph)
+
+  let width = 0
+  let height = 0
+  positions.forEach((p) => {
+    width = Math.max(width, p.x + nodeWidth)
+    height = Math.max(height, p.y + nodeHeight)
+  })
+
+  return (
+    <svg
+      width={width}
+      height={height}
+      viewBox={`0 0 ${Math.max(width, 1)} ${Math.max(height, 1)}`}
+      role="img"
+      aria-label="Resource dependency graph"
+    >
+      {graph.edges.map((e, i) => {
+        let from = positions.get(e.from)
+        let to = positions.get(e.to)
+        if (!from || !to) {
+          return null
+        }
+        let x1 = from.x + nodeWidth
+        let y1 = from.y + nodeHeight / 2
+        let x2 = to.x
+        let y2 = to.y + nodeHeight / 2
+        let mid = (x1 + x2) / 2
+        return (
+          <path
+            key={i}
+            d={`M${x1},${y1} C${mid},${y1} ${mid},${y2} ${x2},${y2}`}
+            fill="none"
+            stroke={
+              e.critical
+                ? Color.yellow
+                : ColorRGBA(Color.gray70, ColorAlpha.translucent)
+            }
+            strokeWidth={e.critical ? 2 : 1}
+            strokeDasharray={e.kind.startsWith("image") ? "4,2" : undefined}
+          >
+            <title>{e.kind.replace(/_/g, " ")}</title>
+          </path>
+        )
+      })}
+      {graph.nodes.map((n) => {
+        let p = positions.get(n.id)!
+        let title = [
+          n.name,
+          n.kind === "image"
+            ? `used by ${(n.resources || []).join(", ")}`
+            : `update: ${n.updateStatus || "none"}, runtime: ${
+                n.runtimeStatus || "none"
+              }`,
+          n.critical ? "on the critical path" : "",
+        ]
+          .filter((line) => line)
+          .join("\n")
+        let box = (
+          <>
+            <title>{title}</title>
+            <rect
+              x={p.x}
+              y={p.y}
+              width={nodeWidth}
+              height={nodeHeight}
+              rx={n.kind === "image" ? nodeHeight / 2 : 4}
+              fill={Color.gray30}
+              stroke={n.critical ? Color.yellow : nodeColor(n)}
+              strokeWidth={n.critical ? 2 : 1}
+            />
+            <text
+              x={p.x + nodeWidth / 2}
+              y={p.y + nodeHeight / 2}
+              dominantBaseline="middle"
+              textAnchor="middle"
+              fill={nodeColor(n)}
+              fontFamily={Font.monospace}
+              fontSize={12}
+            >
+              {n.name.length > 20 ? `${n.name.slice(0, 19)}…` : n.name}
+            </text>
+          </>
+        )
+        if (n.kind !== "resource") {
+          return <g key={n.id}>{box}</g>
+        }
+        return (
+          <ResourceNode
+            key={n.id}
+            aria-label={`Resource ${n.name}`}
+            onClick={() => history.push(pb.encpath`/r/${n.name}/overview`)}
+          >
+            {box}
+          </ResourceNode>
+        )
+      })}
+    </svg>
+  )
+}
+
+// Shows how resources and images depend on each other, and the chain of
+// resources that the current update is waiting on.
+export default function GraphPane(props: GraphPaneProps) {
+  let isSnapshot = usePathBuilder().isSnapshot()
+  let [graph, setGraph] = useState<ResourceGraph | null>(null)
+  let [error, setError] = useState("")
+
+  // Re-fetch whenever a resource's status changes.
+  let statuses = (props.view.uiResources || [])
+    .map(
+      (r) =>
+        `${r.metadata?.name}:${r.status?.updateStatus}:${r.status?.runtimeStatus}`
+    )
+    .join(",")
+  useEffect(() => {
+    if (isSnapshot) {
+      return
+    }
+    let cancelled = false
+    fetchGraph()
+      .then((graph) => {
+        if (!cancelled) {
+          setGraph(graph)
+          setError("")
+        }
+      })
+      .catch((err) => {
+        if (!cancelled) {
+          setError(String(err))
+        }
+      })
+    return () => {
+      cancelled = true
+    }
+  }, [statuses, isSnapshot])
+
+  let content: React.ReactNode
+  if (isSnapshot) {
+    content = (
+      <Message>The resource graph isn't available in snapshots.</Message>
+    )
+  } else if (error) {
+    content = <Message>{error}</Message>
+  } else if (!graph) {
+    content = <Message>Loading…</Message>
+  } else if (graph.nodes.length === 0) {
+    content = <Message>No resources yet.</Message>
+  } else {
+    content = (
+      <>
+        <Toolbar>
+          Dependencies
+          {graph.criticalPath.length > 0 ? (
+            <CriticalPath>
+              Critical path: {graph.criticalPath.join(" → ")}
+            </CriticalPath>
+          ) : null}
+        </Toolbar>
+        <ResourceGraphView graph={graph} />
+      </>
+    )
+  }
+
+  return (
+    <GraphPaneRoot>
+      <HeaderBar
+        view={props.view}
+        currentPage={AnalyticsType.Graph}
+        isSocketConnected={props.isSocketConnected}
+      />
+      <Main>{content}</Main>
+    </GraphPaneRoot>
+  )
+}
@@ -4,22 +4,28 @@ import React, { Component } from "react"
 import ReactOutlineManager from "react-outline-manager"
 import { useHistory } from "react-router"
 import { Route, RouteComponentProps, Switch } from "react-router-dom"
+import { AlertRulesProvider } from "./AlertRules"
 import { incr, navigationToTags } from "./analytics"
 import AnalyticsNudge from "./AnalyticsNudge"
 import AppController from "./AppController"
+import BuildHistoryPane from "./BuildHistoryPane"
 import { tiltfileKeyContext } from "./BrowserStorage"
 import ErrorModal from "./ErrorModal"
 import FatalErrorModal from "./FatalErrorModal"
 import { FeaturesProvider } from "./feature"
+import GraphPane from "./GraphPane"
 import HeroScreen from "./HeroScreen"
 import "./HUD.scss"
 import { HudErrorContextProvider } from "./HudErrorContext"
 import HudState from "./HudState"
 import { InterfaceVersion, useInterfaceVersion } from "./InterfaceVersion"
+import LogSearchPane from "./LogSearchPane"
 import LogStore, { LogStoreProvider } from "./LogStore"
 import OverviewResourcePane from "./OverviewResourcePane"
 import OverviewTablePane from "./OverviewTablePane"
 import PathBuilder, { PathBuilderProvider } from "./PathBuilder"
+import ReadOnlyBanner from "./ReadOnlyBanner"
+import { ServerPreferencesProvider } from "./ServerPreferences"
 import { ResourceGroupsContextProvider } from "./ResourceGroupsContext"
 import { ResourceListOptionsProvider } from "./ResourceListOptionsContext"
 import { ResourceNavProvider } from "./ResourceNav"
@@ -86,6 +92,7 @@ export default class HUD extends Component<HudProps, HudState> {
     this.setError = this.setError.bind(this)
     this.snapshotFromState = this.snapshotFromState.bind(this)
     this.getSnapshotProviderProps = this.getSnapshotProviderProps.bind(this)
+    this.loadReplayFrame = this.loadReplayFrame.bind(this)
   }
 
   componentDidMount() {
@@ -155,6 +162,10 @@ export default class HUD extends Component<HudProps, HudState> {
     })
   }
 
+  private loadReplayFrame(id: string) {
+    this.controller.loadReplayFrame(id)
+  }
+
   private getSnapshotProviderProps(): SnapshotProviderProps {
     const providerProps: SnapshotProviderProps = {
       openModal: this.handleOpenModal,
@@ -165,6 +176,7 @@ export default class HUD extends Component<HudProps, HudState> {
         tiltUpTime: this.state.view.tiltStartTime,
         createdAt: this.state.snapshotStartTime,
       }
+      providerProps.loadReplayFrame = this.loadReplayFrame
     }
 
     return providerProps
@@ -200,24 +212,29 @@ export default class HUD extends Component<HudProps, HudState> {
       resources.some((res) => res.metadata?.name === name)
     return (
       <tiltfileKeyContext.Provider value={tiltfileKey}>
-        <StarredResourcesContextProvider>
-          <ReactOutlineManager>
-            <HudErrorContextProvider setError={this.setError}>
-              <TiltSnackbarProvider>
-                <ResourceNavProvider validateResource={validateResource}>
-                  <div className={hudClasses.join(" ")}>
-                    <AnalyticsNudge needsNudge={needsNudge} />
-                    <SocketBar state={this.state.socketState} />
-                    {fatalErrorModal}
-                    {errorModal}
-                    {shareSnapshotModal}
-                    {this.renderOverviewSwitch()}
-                  </div>
-                </ResourceNavProvider>
-              </TiltSnackbarProvider>
-            </HudErrorContextProvider>
-          </ReactOutlineManager>
-        </StarredResourcesContextProvider>
+        <ServerPreferencesProvider disabled={isSnapshot}>
+          <StarredResourcesContextProvider>
+            <ReactOutlineManager>
+              <HudErrorContextProvider setError={this.setError}>
+                <TiltSnackbarProvider>
+                  <ResourceNavProvider validateResource={validateResource}>
+                    <div className={hudClasses.join(" ")}>
+                      <AnalyticsNudge needsNudge={needsNudge} />
+                      <SocketBar state={this.state.socketState} />
+                      <ReadOnlyBanner isSnapshot={isSnapshot} />
+                      {fatalErrorModal}
+                      {errorModal}
+                      {shareSnapshotModal}
+                      <AlertRulesProvider disabled={isSnapshot}>
+                        {this.renderOverviewSwitch()}
+                      </AlertRulesProvider>
+                    </div>
+                  </ResourceNavProvider>
+                </TiltSnackbarProvider>
+              </HudErrorContextProvider>
+            </ReactOutlineManager>
+          </StarredResourcesContextProvider>
+        </ServerPreferencesProvider>
       </tiltfileKeyContext.Provider>
     )
   }
@@ -235,6 +252,33 @@ export default class HUD extends Component<HudProps, HudState> {
                 <ResourceListOptionsProvider>
                   <ResourceSelectionProvider>
                     <Switch>
+                      <Route
+                        path={this.path("/history")}
+                        render={() => (
+                          <BuildHistoryPane
+                            view={this.state.view}
+                            isSocketConnected={isSocketConnected}
+                          />
+                        )}
+                      />
+                      <Route
+                        path={this.path("/graph")}
+                        render={() => (
+                          <GraphPane
+                            view={this.state.view}
+                            isSocketConnected={isSocketConnected}
+                          />
+                        )}
+                      />
+                      <Route
+                        path={this.path("/search")}
+                        render={() => (
+                          <LogSearchPane
+                            view={this.state.view}
+                            isSocketConnected={isSocketConnected}
+                          />
+                        )}
+                      />
                       <Route
                         path={this.path("/r/:name/overview")}
                         render={(_props: RouteComponentProps<any>) => (
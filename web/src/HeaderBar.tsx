@@ -1,3 +1,6 @@
+import AccountTreeIcon from "@material-ui/icons/AccountTree"
+import SearchIcon from "@material-ui/icons/Search"
+import TimelineIcon from "@material-ui/icons/Timeline"
 import React from "react"
 import { Link } from "react-router-dom"
 import styled from "styled-components"
@@ -83,6 +86,21 @@ const DetailViewIcon = styled(DetailViewSvg)`
   ${viewLinkIconMixin}
 `
 
+const HistoryViewIcon = styled(TimelineIcon)`
+  ${viewLinkIconMixin}
+  box-sizing: content-box;
+`
+
+const GraphViewIcon = styled(AccountTreeIcon)`
+  ${viewLinkIconMixin}
+  box-sizing: content-box;
+`
+
+const LogSearchViewIcon = styled(SearchIcon)`
+  ${viewLinkIconMixin}
+  box-sizing: content-box;
+`
+
 const ViewLink = styled(Link)`
   position: relative;
 
@@ -91,7 +109,7 @@ const ViewLink = styled(Link)`
       opacity: 1;
     }
 
-    ${TableViewIcon}, ${DetailViewIcon} {
+    ${TableViewIcon}, ${DetailViewIcon}, ${HistoryViewIcon}, ${GraphViewIcon}, ${LogSearchViewIcon} {
       fill: ${Color.blue};
     }
   }
@@ -107,7 +125,12 @@ const ViewLinkSection = styled.div`
 type HeaderBarProps = {
   view: Proto.webviewView
   isSocketConnected: boolean
-  currentPage?: AnalyticsType.Detail | AnalyticsType.Grid
+  currentPage?:
+    | AnalyticsType.Detail
+    | AnalyticsType.Grid
+    | AnalyticsType.History
+    | AnalyticsType.Graph
+    | AnalyticsType.LogSearch
 }
 
 export default function HeaderBar({
@@ -137,6 +160,12 @@ export default function HeaderBar({
     currentPage === AnalyticsType.Grid ? "isCurrent" : ""
   const detailViewLinkClass =
     currentPage === AnalyticsType.Detail ? "isCurrent" : ""
+  const historyViewLinkClass =
+    currentPage === AnalyticsType.History ? "isCurrent" : ""
+  const graphViewLinkClass =
+    currentPage === AnalyticsType.Graph ? "isCurrent" : ""
+  const logSearchViewLinkClass =
+    currentPage === AnalyticsType.LogSearch ? "isCurrent" : ""
 
   // TODO (lizz): Consider refactoring nav to use more semantic pattern of ul + li
   return (
@@ -167,6 +196,39 @@ export default function HeaderBar({
             />
             <ViewLinkText>Detail</ViewLinkText>
           </ViewLink>
+          <HeaderDivider role="presentation" />
+          <ViewLink
+            to={pb.encpath`/history`}
+            aria-label="Build history"
+            aria-current={currentPage === AnalyticsType.History}
+          >
+            <HistoryViewIcon
+              className={historyViewLinkClass}
+              role="presentation"
+            />
+            <ViewLinkText>History</ViewLinkText>
+          </ViewLink>
+          <HeaderDivider role="presentation" />
+          <ViewLink
+            to={pb.encpath`/graph`}
+            aria-label="Resource graph"
+            aria-current={currentPage === AnalyticsType.Graph}
+          >
+            <GraphViewIcon className={graphViewLinkClass} role="presentation" />
+            <ViewLinkText>Graph</ViewLinkText>
+          </ViewLink>
+          <HeaderDivider role="presentation" />
+          <ViewLink
+            to={pb.encpath`/search`}
+            aria-label="Log search"
+            aria-current={currentPage === AnalyticsType.LogSearch}
+          >
+            <LogSearchViewIcon
+              className={logSearchViewLinkClass}
+              role="presentation"
+            />
+            <ViewLinkText>Search</ViewLinkText>
+          </ViewLink>
         </ViewLinkSection>
         <AllResourceStatusSummary
           displayText="Resources"
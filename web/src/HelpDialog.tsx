@@ -4,6 +4,7 @@ import { ReactComponent as GithubSvg } from "./assets/svg/github.svg"
 import { ReactComponent as SlackSvg } from "./assets/svg/slack.svg"
 import FloatDialog, { HR } from "./FloatDialog"
 import { HelpSearchBar } from "./HelpSearchBar"
+import { usePathBuilder } from "./PathBuilder"
 import { AnimDuration, Color } from "./style-helpers"
 
 type props = {
@@ -78,6 +79,8 @@ function cmdOrCtrlShortcut(key: string) {
 }
 
 export default function HelpDialog(props: props) {
+  // Snapshots don't have a running Tilt to diagnose.
+  const isSnapshot = usePathBuilder().isSnapshot()
   return (
     <FloatDialog id="shortcuts" title="Help" {...props}>
       <ShortcutRow>
@@ -105,6 +108,34 @@ export default function HelpDialog(props: props) {
         </HelpLink>
         <HR />
       </ShortcutRow>
+      {isSnapshot ? null : (
+        <ShortcutRow style={{ marginBottom: "8px" }}>
+          <HelpLink
+            href="/debug/diagnostics/bundle"
+            download
+            title="Profiles and state to attach to a bug report. Takes a few seconds."
+          >
+            Collect diagnostics bundle
+          </HelpLink>
+          <HelpLink
+            href="/debug/diagnostics"
+            target="_blank"
+            rel="noopener noreferrer"
+            style={{ marginLeft: "16px" }}
+          >
+            Runtime stats
+          </HelpLink>
+          <HelpLink
+            href="/api/logs/previous"
+            target="_blank"
+            rel="noopener noreferrer"
+            title="Logs saved from the last time Tilt ran"
+            style={{ marginLeft: "16px" }}
+          >
+            Previous session's logs
+          </HelpLink>
+        </ShortcutRow>
+      )}
       <HR />
       <ShortcutRow
         style={{
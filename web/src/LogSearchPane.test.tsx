@@ -0,0 +1,50 @@
+import {
+  emptyLogSearchFilter,
+  highlightParts,
+  logSearchParams,
+} from "./LogSearchPane"
+
+describe("LogSearchPane", () => {
+  it("builds search params", () => {
+    let now = new Date("2021-03-01T12:00:00Z")
+    let params = logSearchParams(
+      {
+        query: "error|panic",
+        caseSensitive: false,
+        resources: ["fe", "be"],
+        level: "warn",
+        sinceMinutes: 5,
+      },
+      now
+    )
+    expect(params.get("q")).toEqual("(?i)error|panic")
+    expect(params.getAll("resource")).toEqual(["fe", "be"])
+    expect(params.get("level")).toEqual("warn")
+    expect(params.get("since")).toEqual("2021-03-01T11:55:00.000Z")
+  })
+
+  it("searches case-sensitively when asked", () => {
+    let params = logSearchParams(
+      { ...emptyLogSearchFilter, query: "Error", caseSensitive: true },
+      new Date()
+    )
+    expect(params.get("q")).toEqual("Error")
+    expect(params.has("since")).toBe(false)
+  })
+
+  it("highlights matches", () => {
+    expect(
+      highlightParts("GET /api 500", [
+        [0, 3],
+        [9, 12],
+      ])
+    ).toEqual([
+      { text: "GET", isMatch: true },
+      { text: " /api ", isMatch: false },
+      { text: "500", isMatch: true },
+    ])
+    expect(highlightParts("no matches")).toEqual([
+      { text: "no matches", isMatch: false },
+    ])
+  })
+})
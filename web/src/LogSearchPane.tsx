@@ -0,0 +1,465 @@
+import React, { FormEvent, useState } from "react"
+import { Link } from "react-router-dom"
+import styled from "styled-components"
+import { AnalyticsType } from "./analytics"
+import { usePersistentState } from "./BrowserStorage"
+import HeaderBar from "./HeaderBar"
+import { usePathBuilder } from "./PathBuilder"
+import { Color, Font, FontSize, SizeUnit } from "./style-helpers"
+
+type LogSearchPaneProps = {
+  view: Proto.webviewView
+  isSocketConnected: boolean
+}
+
+// The search parameters, as the user entered them.
+export type LogSearchFilter = {
+  query: string
+  caseSensitive: boolean
+  resources: string[]
+  level: string
+  // How far back to search, in minutes. 0 means all the logs Tilt has.
+  sinceMinutes: number
+}
+
+export type SavedLogSearchFilter = LogSearchFilter & { name: string }
+
+export type LogSearchMatch = {
+  checkpoint: number
+  resource?: string
+  spanId: string
+  level: string
+  time: string
+  text: string
+  ranges?: [number, number][]
+}
+
+type LogSearchResponse = {
+  matches: LogSearchMatch[]
+  truncated: boolean
+}
+
+export const emptyLogSearchFilter: LogSearchFilter = {
+  query: "",
+  caseSensitive: false,
+  resources: [],
+  level: "",
+  sinceMinutes: 0,
+}
+
+const sinceOptions = [
+  { label: "All logs", minutes: 0 },
+  { label: "Last 5 minutes", minutes: 5 },
+  { label: "Last 15 minutes", minutes: 15 },
+  { label: "Last hour", minutes: 60 },
+]
+
+const levelOptions = [
+  { label: "All levels", level: "" },
+  { label: "Warnings and errors", level: "warn" },
+  { label: "Errors", level: "error" },
+]
+
+// Builds the query string for the log search API.
+export function logSearchParams(
+  filter: LogSearchFilter,
+  now: Date
+): URLSearchParams {
+  let params = new URLSearchParams()
+  if (filter.query) {
+    let q = filter.caseSensitive ? filter.query : "(?i)" + filter.query
+    params.set("q", q)
+  }
+  filter.resources.forEach((r) => params.append("resource", r))
+  if (filter.level) {
+    params.set("level", filter.level)
+  }
+  if (filter.sinceMinutes > 0) {
+    let since = new Date(now.getTime() - filter.sinceMinutes * 60 * 1000)
+    params.set("since", since.toISOString())
+  }
+  return params
+}
+
+// Splits the text into the parts inside and outside the matched ranges.
+export function highlightParts(
+  text: string,
+  ranges: [number, number][] = []
+): { text: string; isMatch: boolean }[] {
+  let parts: { text: string; isMatch: boolean }[] = []
+  let last = 0
+  ranges.forEach(([start, end]) => {
+    if (start > last) {
+      parts.push({ text: text.slice(last, start), isMatch: false })
+    }
+    if (end > start) {
+      parts.push({ text: text.slice(start, end), isMatch: true })
+    }
+    last = Math.max(last, end)
+  })
+  if (last < text.length) {
+    parts.push({ text: text.slice(last), isMatch: false })
+  }
+  return parts
+}
+
+async function searchLogs(
+  filter: LogSearchFilter
+): Promise<LogSearchResponse> {
+  let params = logSearchParams(filter, new Date())
+  const resp = await fetch(`/api/logs/search?${params.toString()}`, {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw body.trim() || `error searching logs: ${resp.status}`
+  }
+  return await resp.json()
+}
+
+let LogSearchPaneRoot = styled.div`
+  display: flex;
+  flex-direction: column;
+  width: 100%;
+  height: 100vh;
+  background-color: ${Color.gray20};
+  max-height: 100%;
+`
+
+let Main = styled.div`
+  flex: 1 1 100%;
+  overflow: auto;
+  padding: ${SizeUnit(0.5)} ${SizeUnit(1)};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  color: ${Color.gray70};
+`
+
+let SearchForm = styled.form`
+  display: flex;
+  flex-wrap: wrap;
+  align-items: center;
+  gap: ${SizeUnit(0.25)};
+  margin-bottom: ${SizeUnit(0.25)};
+
+  input[type="text"],
+  select {
+    background-color: ${Color.gray30};
+    border: 1px solid ${Color.gray50};
+    color: ${Color.gray70};
+    font-family: ${Font.monospace};
+    font-size: ${FontSize.smallest};
+    padding: ${SizeUnit(0.1)} ${SizeUnit(0.2)};
+  }
+
+  input.query {
+    flex-grow: 1;
+    min-width: 20em;
+  }
+`
+
+let FormButton = styled.button`
+  background-color: ${Color.gray40};
+  border: 1px solid ${Color.gray50};
+  color: ${Color.gray70};
+  cursor: pointer;
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  padding: ${SizeUnit(0.1)} ${SizeUnit(0.3)};
+
+  &:disabled {
+    cursor: default;
+    opacity: 0.5;
+  }
+`
+
+let SavedFilters = styled.ul`
+  display: flex;
+  flex-wrap: wrap;
+  gap: ${SizeUnit(0.25)};
+  list-style: none;
+  margin: 0 0 ${SizeUnit(0.5)} 0;
+  padding: 0;
+`
+
+let SavedFilterChip = styled.li`
+  border: 1px solid ${Color.blueDark};
+  border-radius: 12px;
+  display: flex;
+  align-items: center;
+
+  button {
+    background: none;
+    border: none;
+    color: ${Color.gray70};
+    cursor: pointer;
+    font-family: ${Font.monospace};
+    font-size: ${FontSize.smallest};
+    padding: ${SizeUnit(0.05)} ${SizeUnit(0.25)};
+  }
+`
+
+let Status = styled.div`
+  color: ${Color.grayLightest};
+  margin: ${SizeUnit(0.25)} 0;
+
+  &.isError {
+    color: ${Color.red};
+  }
+`
+
+let MatchList = styled.ol`
+  list-style: none;
+  margin: 0;
+  padding: 0;
+`
+
+let MatchLine = styled.li`
+  display: flex;
+  white-space: pre-wrap;
+  word-break: break-all;
+  padding: 1px 0;
+
+  &.is-warn {
+    color: ${Color.yellow};
+  }
+  &.is-error {
+    color: ${Color.red};
+  }
+
+  mark {
+    background-color: ${Color.blueDark};
+    color: ${Color.white};
+  }
+`
+
+let MatchPrefix = styled.span`
+  flex-shrink: 0;
+  width: 22em;
+  color: ${Color.gray60};
+
+  a {
+    color: ${Color.blue};
+  }
+`
+
+function MatchRow(props: { match: LogSearchMatch }) {
+  let pb = usePathBuilder()
+  let m = props.match
+  let time = new Date(m.time).toLocaleTimeString()
+  let resource = m.resource ? (
+    <Link to={pb.encpath`/r/${m.resource}/overview`}>{m.resource}</Link>
+  ) : (
+    "(global)"
+  )
+  return (
+    <MatchLine className={`is-${m.level}`}>
+      <MatchPrefix>
+        {time} {resource}
+      </MatchPrefix>
+      <span>
+        {highlightParts(m.text, m.ranges).map((p, i) =>
+          p.isMatch ? <mark key={i}>{p.text}</mark> : p.text
+        )}
+      </span>
+    </MatchLine>
+  )
+}
+
+// Searches the logs of all resources on the server, so that searches cover
+// all the logs Tilt has, not just what the browser has rendered.
+export default function LogSearchPane(props: LogSearchPaneProps) {
+  let isSnapshot = usePathBuilder().isSnapshot()
+  let [filter, setFilter] = useState<LogSearchFilter>(emptyLogSearchFilter)
+  let [savedFilters, setSavedFilters] = usePersistentState<
+    SavedLogSearchFilter[]
+  >("logSearchFilters", [])
+  let [saveName, setSaveName] = useState("")
+  let [result, setResult] = useState<LogSearchResponse | null>(null)
+  let [error, setError] = useState("")
+  let [searching, setSearching] = useState(false)
+
+  let resourceNames = (props.view.uiResources || []).map(
+    (r) => r.metadata?.name || ""
+  )
+
+  let runSearch = (f: LogSearchFilter) => {
+    setSearching(true)
+    searchLogs(f)
+      .then((resp) => {
+        setResult(resp)
+        setError("")
+      })
+      .catch((err) => {
+        setResult(null)
+        setError(String(err))
+      })
+      .finally(() => setSearching(false))
+  }
+
+  let onSubmit = (e: FormEvent) => {
+    e.preventDefault()
+    runSearch(filter)
+  }
+
+  let applySaved = (saved: SavedLogSearchFilter) => {
+    let f: LogSearchFilter = {
+      query: saved.query,
+      caseSensitive: saved.caseSensitive,
+      resources: saved.resources,
+      level: saved.level,
+      sinceMinutes: saved.sinceMinutes,
+    }
+    setFilter(f)
+    runSearch(f)
+  }
+
+  let saveFilter = () => {
+    let name = saveName.trim()
+    if (!name) {
+      return
+    }
+    setSavedFilters((existing) => [
+      ...existing.filter((f) => f.name !== name),
+      { ...filter, name },
+    ])
+    setSaveName("")
+  }
+
+  let deleteSaved = (name: string) => {
+    setSavedFilters((existing) => existing.filter((f) => f.name !== name))
+  }
+
+  let status: React.ReactNode = null
+  if (isSnapshot) {
+    status = <Status>Log search isn't available in snapshots.</Status>
+  } else if (error) {
+    status = <Status className="isError">{error}</Status>
+  } else if (result) {
+    let count = result.matches.length
+    status = (
+      <Status>
+        {count} matching {count === 1 ? "line" : "lines"}
+        {result.truncated ? ` (showing the most recent ${count})` : ""}
+      </Status>
+    )
+  }
+
+  return (
+    <LogSearchPaneRoot>
+      <HeaderBar
+        view={props.view}
+        currentPage={AnalyticsType.LogSearch}
+        isSocketConnected={props.isSocketConnected}
+      />
+      <Main>
+        <SearchForm onSubmit={onSubmit} aria-label="Search logs">
+          <input
+            type="text"
+            className="query"
+            aria-label="Regular expression"
+            placeholder="Regular expression, e.g. error|panic"
+            value={filter.query}
+            onChange={(e) => setFilter({ ...filter, query: e.target.value })}
+          />
+          <label>
+            <input
+              type="checkbox"
+              checked={filter.caseSensitive}
+              onChange={(e) =>
+                setFilter({ ...filter, caseSensitive: e.target.checked })
+              }
+            />
+            Match case
+          </label>
+          <select
+            aria-label="Resources"
+            multiple
+            value={filter.resources}
+            onChange={(e) =>
+              setFilter({
+                ...filter,
+                resources: Array.from(e.target.selectedOptions).map(
+                  (o) => o.value
+                ),
+              })
+            }
+          >
+            {resourceNames.map((name) => (
+              <option key={name} value={name}>
+                {name}
+              </option>
+            ))}
+          </select>
+          <select
+            aria-label="Level"
+            value={filter.level}
+            onChange={(e) => setFilter({ ...filter, level: e.target.value })}
+          >
+            {levelOptions.map((o) => (
+              <option key={o.level} value={o.level}>
+                {o.label}
+              </option>
+            ))}
+          </select>
+          <select
+            aria-label="Time range"
+            value={filter.sinceMinutes}
+            onChange={(e) =>
+              setFilter({ ...filter, sinceMinutes: Number(e.target.value) })
+            }
+          >
+            {sinceOptions.map((o) => (
+              <option key={o.minutes} value={o.minutes}>
+                {o.label}
+              </option>
+            ))}
+          </select>
+          <FormButton type="submit" disabled={isSnapshot || searching}>
+            Search
+          </FormButton>
+          <input
+            type="text"
+            aria-label="Filter name"
+            placeholder="Name this filter"
+            value={saveName}
+            onChange={(e) => setSaveName(e.target.value)}
+          />
+          <FormButton
+            type="button"
+            disabled={!saveName.trim()}
+            onClick={saveFilter}
+          >
+            Save filter
+          </FormButton>
+        </SearchForm>
+        <SavedFilters aria-label="Saved filters">
+          {savedFilters.map((f) => (
+            <SavedFilterChip key={f.name}>
+              <button
+                type="button"
+                onClick={() => applySaved(f)}
+                disabled={isSnapshot}
+              >
+                {f.name}
+              </button>
+              <button
+                type="button"
+                aria-label={`Delete filter ${f.name}`}
+                onClick={() => deleteSaved(f.name)}
+              >
+                ×
+              </button>
+            </SavedFilterChip>
+          ))}
+        </SavedFilters>
+        {status}
+        <MatchList>
+          {(result?.matches || []).map((m, i) => (
+            <MatchRow key={`${m.checkpoint}-${i}`} match={m} />
+          ))}
+        </MatchList>
+      </Main>
+    </LogSearchPaneRoot>
+  )
+}
@@ -119,6 +119,47 @@ describe("LogStore", () => {
     expect(logLinesToString(logs.manifestLog("fe"), false)).toEqual("line2")
   })
 
+  it("marks truncation lines", () => {
+    let logs = new LogStore()
+    logs.append({
+      spans: {
+        "truncated:fe": { manifestName: "fe" },
+        fe: { manifestName: "fe" },
+      },
+      segments: [
+        {
+          spanId: "truncated:fe",
+          text: "... 8 earlier lines (56B) truncated, saved to disk ...\n",
+          time: now(),
+          fields: { truncated: "spilled" },
+        },
+        newManifestSegment("fe", "line8\n"),
+      ],
+    })
+
+    let lines = logs.manifestLog("fe")
+    expect(lines.map((l) => l.truncated)).toEqual(["spilled", undefined])
+  })
+
+  it("marks lines that Tilt turned into HTML", () => {
+    let logs = new LogStore()
+    logs.append({
+      spans: { fe: { manifestName: "fe" } },
+      segments: [
+        {
+          spanId: "fe",
+          text: '<span class="ansi-red-fg">error</span>\n',
+          time: now(),
+          fields: { ansiHTML: "1" },
+        },
+        newManifestSegment("fe", "line2\n"),
+      ],
+    })
+
+    let lines = logs.manifestLog("fe")
+    expect(lines.map((l) => l.ansiHTML)).toEqual(["1", undefined])
+  })
+
   it("handles manifest spans with no segments", () => {
     let logs = new LogStore()
     logs.append({
@@ -616,6 +616,8 @@ class LogStore implements LogAlertIndex {
           level: storedLine.level,
           manifestName: span.manifestName,
           buildEvent: storedLine.fields?.buildEvent,
+          truncated: storedLine.fields?.truncated,
+          ansiHTML: storedLine.fields?.ansiHTML,
           spanId: spanId,
           storedLineIndex: i,
         }
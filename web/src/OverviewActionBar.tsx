@@ -36,7 +36,9 @@ import { useLogStore } from "./LogStore"
 import OverviewActionBarKeyboardShortcuts from "./OverviewActionBarKeyboardShortcuts"
 import { OverviewButtonMixin } from "./OverviewButton"
 import { usePathBuilder } from "./PathBuilder"
+import PortForwardDialog from "./PortForwardDialog"
 import { resourceIsDisabled } from "./ResourceStatus"
+import { ResourceUsage } from "./ResourceUsage"
 import SrOnly from "./SrOnly"
 import {
   AnimDuration,
@@ -59,6 +61,9 @@ type OverviewActionBarProps = {
   // The current log filter.
   filterSet: FilterSet
 
+  // The resources with pods that the user can add port forwards to.
+  portForwardResources?: string[]
+
   // buttons for this resource
   buttons?: ButtonSet
 }
@@ -626,6 +631,31 @@ export function CopyButton(props: CopyButtonProps) {
   )
 }
 
+function PortForwardButton(props: {
+  resourceName: string
+  resourceNames: string[]
+}) {
+  let [anchorEl, setAnchorEl] = useState<Element | null>(null)
+
+  return (
+    <>
+      <ButtonRoot
+        onClick={(e) => setAnchorEl(e.currentTarget)}
+        analyticsName="ui.web.actionBar.portForward"
+      >
+        Forward port
+      </ButtonRoot>
+      <PortForwardDialog
+        resourceName={props.resourceName}
+        resourceNames={props.resourceNames}
+        open={!!anchorEl}
+        anchorEl={anchorEl}
+        onClose={() => setAnchorEl(null)}
+      />
+    </>
+  )
+}
+
 let ActionBarRoot = styled.div`
   background-color: ${Color.gray10};
 `
@@ -715,6 +745,7 @@ export default function OverviewActionBar(props: OverviewActionBarProps) {
 
   let endpoints = resource?.status?.endpointLinks || []
   let podId = resource?.status?.k8sResourceInfo?.podName || ""
+  let usage = resource?.status?.localResourceInfo?.usage
   const resourceName = resource
     ? resource.metadata?.name || ""
     : ResourceName.all
@@ -753,6 +784,18 @@ export default function OverviewActionBar(props: OverviewActionBarProps) {
   if (podId && !isDisabled) {
     topRowEls.push(<CopyButton podId={podId} key="copyPodId" />)
   }
+  if (usage && !isDisabled) {
+    topRowEls.push(<ResourceUsage usage={usage} key="resourceUsage" />)
+  }
+  if (podId && !isDisabled && !isSnapshot) {
+    topRowEls.push(
+      <PortForwardButton
+        resourceName={resourceName}
+        resourceNames={props.portForwardResources || [resourceName]}
+        key="portForward"
+      />
+    )
+  }
 
   const widgets = OverviewWidgets({ buttons: buttons?.default })
   if (widgets && !isDisabled) {
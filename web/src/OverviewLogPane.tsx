@@ -100,6 +100,9 @@ function newLineEl(
     classes.push("is-buildEvent")
     classes.push("is-buildEvent-fallback")
   }
+  if (line.truncated) {
+    classes.push("is-truncated")
+  }
   let span = document.createElement("span")
   span.setAttribute("data-sl-index", String(line.storedLineIndex))
   span.classList.add(...classes)
@@ -121,13 +124,32 @@ function newLineEl(
 
   // newline ensures this takes up at least one line
   let spacer = "\n"
-  code.innerHTML = anser.linkify(
-    anser.ansiToHtml(anser.escapeForHtml(text) + spacer, {
-      // Let anser colorize the html as it appears from various consoles
-      use_classes: false,
-    })
-  )
+  if (line.ansiHTML) {
+    // Tilt already escaped the line and turned its colors into classes.
+    code.innerHTML = anser.linkify(text + spacer)
+  } else {
+    code.innerHTML = anser.linkify(
+      anser.ansiToHtml(anser.escapeForHtml(text) + spacer, {
+        // Let anser colorize the html as it appears from various consoles
+        use_classes: false,
+      })
+    )
+  }
   span.appendChild(code)
+
+  if (line.truncated === "spilled") {
+    // Tilt saved the truncated logs to disk, so link to them.
+    let link = document.createElement("a")
+    link.className = "LogLine-truncatedLink"
+    link.href = `/api/logs/truncated?resource=${encodeURIComponent(
+      line.manifestName
+    )}`
+    link.target = "_blank"
+    link.rel = "noopener noreferrer"
+    link.textContent = "View truncated logs"
+    span.appendChild(link)
+  }
+
   return span
 }
 
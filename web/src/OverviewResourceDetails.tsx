@@ -13,6 +13,7 @@ type OverviewResourceDetailsProps = {
   buttons?: ButtonSet
   alerts?: Alert[]
   name: string
+  portForwardResources?: string[]
 }
 
 let OverviewResourceDetailsRoot = styled.div`
@@ -34,7 +35,7 @@ let NotFound = styled.div`
 export default function OverviewResourceDetails(
   props: OverviewResourceDetailsProps
 ) {
-  let { name, resource, alerts, buttons } = props
+  let { name, resource, alerts, buttons, portForwardResources } = props
   let manifestName = resource?.metadata?.name || ""
   let all = name === "" || name === ResourceName.all
   let notFound = !all && !manifestName
@@ -47,6 +48,7 @@ export default function OverviewResourceDetails(
         filterSet={filterSet}
         alerts={alerts}
         buttons={buttons}
+        portForwardResources={portForwardResources}
       />
       {notFound ? (
         <NotFound>No resource '{name}'</NotFound>
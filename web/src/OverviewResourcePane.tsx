@@ -15,6 +15,7 @@ import StarredResourceBar, {
 } from "./StarredResourceBar"
 import { Color, Width } from "./style-helpers"
 import { ResourceName, UIResource } from "./types"
+import { panelsForComponent, UIPanelTabs, useUIPanels } from "./UIPanels"
 
 type OverviewResourcePaneProps = {
   view: Proto.webviewView
@@ -85,12 +86,22 @@ export default function OverviewResourcePane(props: OverviewResourcePaneProps) {
     resources.forEach((r) => alerts.push(...combinedAlerts(r, logStore)))
   }
 
+  let portForwardResources = resources
+    .filter((r) => r.status?.k8sResourceInfo?.podName)
+    .map((r) => r.metadata?.name || "")
+
   const buttons = buttonsForComponent(
     props.view.uiButtons,
     ApiButtonType.Resource,
     name
   )
 
+  const panels = panelsForComponent(
+    useUIPanels(),
+    all ? ApiButtonType.Global : ApiButtonType.Resource,
+    name
+  )
+
   return (
     <OverviewResourcePaneRoot>
       <HeaderBar
@@ -108,12 +119,15 @@ export default function OverviewResourcePane(props: OverviewResourcePaneProps) {
           defaultSize={Width.sidebarDefault}
         >
           <OverviewResourceSidebar {...props} name={name} />
-          <OverviewResourceDetails
-            resource={r}
-            name={name}
-            alerts={alerts}
-            buttons={buttons}
-          />
+          <UIPanelTabs key={name} panels={panels} defaultTabLabel="Logs">
+            <OverviewResourceDetails
+              resource={r}
+              name={name}
+              alerts={alerts}
+              buttons={buttons}
+              portForwardResources={portForwardResources}
+            />
+          </UIPanelTabs>
         </SplitPane>
       </Main>
     </OverviewResourcePaneRoot>
@@ -42,6 +42,7 @@ import {
   UNLABELED_LABEL,
 } from "./labels"
 import { LogAlertIndex, useLogAlertIndex } from "./LogStore"
+import { OverviewTableGroupActions } from "./OverviewTableBulkActions"
 import {
   COLUMNS,
   ResourceTableHeaderTip,
@@ -749,6 +750,9 @@ function TableGroup(props: TableGroupProps) {
           labelText={`Status summary for ${label} group`}
           resources={tableProps.data}
         />
+        {label !== UNLABELED_LABEL && label !== TILTFILE_LABEL ? (
+          <OverviewTableGroupActions label={label} />
+        ) : null}
       </OverviewGroupSummary>
       <OverviewGroupDetails>
         <Table {...tableProps} />
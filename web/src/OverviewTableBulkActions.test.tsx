@@ -4,6 +4,7 @@ import {
   waitForElementToBeRemoved,
 } from "@testing-library/react"
 import userEvent from "@testing-library/user-event"
+import fetchMock from "fetch-mock"
 import React from "react"
 import {
   cleanupMockAnalyticsCalls,
@@ -103,6 +104,21 @@ describe("OverviewTableBulkActions", () => {
       expect(screen.queryByLabelText("Cancel Disable")).toBeTruthy()
     })
 
+    it("renders an 'Update' button that triggers the selected resources", async () => {
+      fetchMock.post("/api/bulk", { status: 202, body: {} })
+      const updateButton = screen.queryByLabelText("Trigger Update")
+      expect(updateButton).toBeTruthy()
+
+      userEvent.click(updateButton as HTMLElement)
+
+      await waitForElementToBeRemoved(screen.queryByLabelText("Trigger Update"))
+      const call = fetchMock.lastCall("/api/bulk")
+      expect(JSON.parse(String(call?.[1]?.body))).toEqual({
+        action: "trigger",
+        resources: TEST_SELECTIONS,
+      })
+    })
+
     it("clears the selected resources after a button has been clicked", async () => {
       const enableButton = screen.queryByLabelText("Trigger Enable")
       expect(enableButton).toBeTruthy()
@@ -5,7 +5,7 @@ import {
   buttonsByComponent,
   ButtonSet,
 } from "./ApiButton"
-import { BulkApiButton } from "./BulkApiButton"
+import { BulkApiButton, runBulkResourceAction } from "./BulkApiButton"
 import { useFeatures } from "./feature"
 import { useResourceSelection } from "./ResourceSelectionContext"
 import SrOnly from "./SrOnly"
@@ -17,10 +17,11 @@ type OverviewTableBulkActionsProps = {
   uiButtons?: UIButton[]
 }
 
-type ActionButtons = { [key in BulkAction]: UIButton[] }
+type ActionButtons = { [BulkAction.Disable]: UIButton[] }
 
 export enum BulkAction {
   Disable = "disable", // Enable / disable are states of the same toggle, so use a single name
+  Trigger = "trigger",
 }
 
 // Styles
@@ -95,6 +96,17 @@ export function OverviewTableBulkActions({
 
   return (
     <BulkActionMenu aria-label="Bulk resource actions">
+      <BulkApiButton
+        bulkAction={BulkAction.Trigger}
+        buttonText="Update"
+        requiresConfirmation={false}
+        uiButtons={[]}
+        bulkCount={selected.size}
+        runAction={() =>
+          runBulkResourceAction("trigger", { resources: Array.from(selected) })
+        }
+        onClickCallback={onClickCallback}
+      />
       <BulkApiButton
         bulkAction={BulkAction.Disable}
         buttonText="Enable"
@@ -115,3 +127,47 @@ export function OverviewTableBulkActions({
     </BulkActionMenu>
   )
 }
+
+const GroupActionMenu = styled.span`
+  display: flex;
+  margin-left: auto;
+  margin-right: ${SizeUnit(0.5)};
+`
+
+// Acts on every resource with a label, from the label's group header.
+export function OverviewTableGroupActions({ label }: { label: string }) {
+  const selection = { labels: [label] }
+  return (
+    // Clicks on the header expand or collapse the group, so keep
+    // clicks on the buttons from reaching it.
+    <GroupActionMenu
+      aria-label={`Bulk actions for ${label} group`}
+      onClick={(e) => e.stopPropagation()}
+      onFocus={(e) => e.stopPropagation()}
+    >
+      <BulkApiButton
+        bulkAction={BulkAction.Trigger}
+        buttonText="Update"
+        requiresConfirmation={false}
+        uiButtons={[]}
+        runAction={() => runBulkResourceAction("trigger", selection)}
+      />
+      <BulkApiButton
+        bulkAction={BulkAction.Disable}
+        buttonText="Enable"
+        requiresConfirmation={false}
+        uiButtons={[]}
+        targetToggleState={ApiButtonToggleState.On}
+        runAction={() => runBulkResourceAction("enable", selection)}
+      />
+      <BulkApiButton
+        bulkAction={BulkAction.Disable}
+        buttonText="Disable"
+        requiresConfirmation={true}
+        uiButtons={[]}
+        targetToggleState={ApiButtonToggleState.Off}
+        runAction={() => runBulkResourceAction("disable", selection)}
+      />
+    </GroupActionMenu>
+  )
+}
@@ -2,6 +2,7 @@ import React, { ChangeEvent, useCallback, useMemo, useState } from "react"
 import { CellProps, Column, HeaderProps, Row } from "react-table"
 import TimeAgo from "react-timeago"
 import styled from "styled-components"
+import { AlertBadge } from "./AlertRules"
 import { AnalyticsAction, AnalyticsType, incr, Tags } from "./analytics"
 import { ApiButton, ApiIcon, ButtonSet } from "./ApiButton"
 import { ReactComponent as CheckmarkSvg } from "./assets/svg/checkmark.svg"
@@ -402,12 +403,15 @@ export function TableNameColumn({ row }: CellProps<RowValues>) {
   const errorClass = hasError ? "has-error" : ""
   const disabledClass = rowIsDisabled(row) ? "isDisabled" : ""
   return (
-    <Name
-      className={`${errorClass} ${disabledClass}`}
-      onClick={(e) => nav.openResource(row.values.name)}
-    >
-      {row.values.name}
-    </Name>
+    <>
+      <Name
+        className={`${errorClass} ${disabledClass}`}
+        onClick={(e) => nav.openResource(row.values.name)}
+      >
+        {row.values.name}
+      </Name>
+      <AlertBadge resourceName={row.values.name} />
+    </>
   )
 }
 
@@ -1,6 +1,7 @@
 import React from "react"
 import styled from "styled-components"
 import { AnalyticsType } from "./analytics"
+import { ApiButtonType } from "./ApiButton"
 import HeaderBar from "./HeaderBar"
 import OverviewTable from "./OverviewTable"
 import { OverviewTableBulkActions } from "./OverviewTableBulkActions"
@@ -10,6 +11,7 @@ import StarredResourceBar, {
   starredResourcePropsFromView,
 } from "./StarredResourceBar"
 import { Color, SizeUnit, Width, ZIndex } from "./style-helpers"
+import { panelsForComponent, UIPanelTabs, useUIPanels } from "./UIPanels"
 
 type OverviewTablePaneProps = {
   view: Proto.webviewView
@@ -54,6 +56,7 @@ const OverviewTableResourceNameFilter = styled(ResourceNameFilter)`
 `
 
 export default function OverviewTablePane(props: OverviewTablePaneProps) {
+  const panels = panelsForComponent(useUIPanels(), ApiButtonType.Global, "")
   return (
     <OverviewTablePaneStyle>
       <OverviewTableStickyNav>
@@ -69,7 +72,9 @@ export default function OverviewTablePane(props: OverviewTablePaneProps) {
           <OverviewTableDisplayOptions resources={props.view.uiResources} />
         </OverviewTableMenu>
       </OverviewTableStickyNav>
-      <OverviewTable view={props.view} />
+      <UIPanelTabs panels={panels} defaultTabLabel="Resources">
+        <OverviewTable view={props.view} />
+      </UIPanelTabs>
     </OverviewTablePaneStyle>
   )
 }
@@ -0,0 +1,40 @@
+import { parsePort, portForwardSummary } from "./PortForwardDialog"
+
+describe("PortForwardDialog", () => {
+  it("parses ports", () => {
+    expect(parsePort("8080", false)).toEqual(8080)
+    expect(parsePort(" 80 ", false)).toEqual(80)
+    expect(parsePort("0", true)).toEqual(0)
+    expect(parsePort("0", false)).toBeNaN()
+    expect(parsePort("70000", false)).toBeNaN()
+    expect(parsePort("80a", false)).toBeNaN()
+    expect(parsePort("", false)).toBeNaN()
+  })
+
+  it("summarizes port forwards", () => {
+    let pf = {
+      metadata: {
+        name: "fe-adhoc-8080-0",
+        annotations: { "tilt.dev/resource": "fe" },
+      },
+      spec: { podName: "fe-abc", forwards: [{ containerPort: 8080 }] },
+    }
+    expect(portForwardSummary(pf)).toEqual({
+      text: "localhost:? → fe:8080",
+      error: "",
+    })
+
+    let withStatus = {
+      ...pf,
+      status: {
+        forwardStatuses: [
+          { containerPort: 8080, localPort: 53412, error: "pod not found" },
+        ],
+      },
+    }
+    expect(portForwardSummary(withStatus)).toEqual({
+      text: "localhost:53412 → fe:8080",
+      error: "pod not found",
+    })
+  })
+})
@@ -0,0 +1,272 @@
+import React, { FormEvent, useEffect, useState } from "react"
+import styled from "styled-components"
+import { ReactComponent as CloseSvg } from "./assets/svg/close.svg"
+import FloatDialog, { FloatDialogProps, HR } from "./FloatDialog"
+import { InstrumentedButton } from "./instrumentedComponents"
+import { Color, Font, FontSize, SizeUnit } from "./style-helpers"
+import { PortForward } from "./types"
+
+export type PortForwardDialogProps = {
+  // The resource to forward from, unless the user picks another one.
+  resourceName: string
+
+  // All the resources that the user can forward from.
+  resourceNames: string[]
+} & Pick<FloatDialogProps, "open" | "onClose" | "anchorEl">
+
+// How often to refresh the status of the port forwards while the dialog is open.
+const refreshIntervalMs = 2000
+
+export async function fetchPortForwards(): Promise<PortForward[]> {
+  const resp = await fetch("/api/port_forwards", {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching port forwards: ${body}`
+  }
+  const list = await resp.json()
+  return list.items || []
+}
+
+export async function createPortForward(
+  resource: string,
+  containerPort: number,
+  localPort: number
+) {
+  const resp = await fetch("/api/port_forwards", {
+    method: "POST",
+    headers: {
+      Accept: "application/json",
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify({ resource, containerPort, localPort }),
+  })
+  if (resp.status !== 201) {
+    const body = await resp.text()
+    throw body.trim()
+  }
+}
+
+export async function deletePortForward(name: string) {
+  const resp = await fetch(`/api/port_forwards/${encodeURIComponent(name)}`, {
+    method: "DELETE",
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw body.trim()
+  }
+}
+
+// Parses a port from a form field. Returns NaN if it's not a valid port.
+export function parsePort(value: string, allowZero: boolean): number {
+  if (!/^\d+$/.test(value.trim())) {
+    return NaN
+  }
+  let port = parseInt(value, 10)
+  if (port > 65535 || (port === 0 && !allowZero)) {
+    return NaN
+  }
+  return port
+}
+
+// A short description of where a port forward is listening,
+// and whether it's working.
+export function portForwardSummary(pf: PortForward): {
+  text: string
+  error: string
+} {
+  let resource = pf.metadata?.annotations?.["tilt.dev/resource"] || ""
+  let forward = pf.spec?.forwards?.[0]
+  let status = pf.status?.forwardStatuses?.find(
+    (s) => s.containerPort === forward?.containerPort
+  )
+  let localPort = status?.localPort || forward?.localPort || "?"
+  return {
+    text: `localhost:${localPort} → ${resource}:${forward?.containerPort}`,
+    error: status?.error || "",
+  }
+}
+
+let Form = styled.form`
+  display: grid;
+  grid-template-columns: auto 1fr;
+  column-gap: ${SizeUnit(0.5)};
+  row-gap: ${SizeUnit(0.25)};
+  align-items: center;
+  font-family: ${Font.monospace};
+
+  select,
+  input {
+    font-family: ${Font.monospace};
+    font-size: ${FontSize.small};
+  }
+`
+
+let SubmitButton = styled(InstrumentedButton)`
+  grid-column: 2;
+  justify-self: start;
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+  text-transform: none;
+`
+
+let ErrorText = styled.div`
+  color: ${Color.red};
+  line-height: 1.4;
+`
+
+let ForwardList = styled.ul`
+  list-style: none;
+  margin: 0;
+  padding: 0;
+`
+
+let ForwardItem = styled.li`
+  display: flex;
+  align-items: center;
+  justify-content: space-between;
+`
+
+let RemoveButton = styled(InstrumentedButton)`
+  min-width: 0;
+  padding: ${SizeUnit(0.1)};
+
+  svg {
+    fill: ${Color.gray50};
+  }
+`
+
+let Empty = styled.div`
+  color: ${Color.gray50};
+`
+
+// Lets the user add and remove port forwards to their pods at runtime,
+// without editing the Tiltfile.
+export default function PortForwardDialog(props: PortForwardDialogProps) {
+  const { open, onClose, anchorEl } = props
+  let [resource, setResource] = useState(props.resourceName)
+  let [containerPort, setContainerPort] = useState("")
+  let [localPort, setLocalPort] = useState("")
+  let [error, setError] = useState("")
+  let [forwards, setForwards] = useState<PortForward[]>([])
+
+  useEffect(() => {
+    setResource(props.resourceName)
+  }, [props.resourceName])
+
+  let refresh = () =>
+    fetchPortForwards()
+      .then(setForwards)
+      .catch((e) => setError(`${e}`))
+
+  useEffect(() => {
+    if (!open) {
+      return
+    }
+    refresh()
+    let interval = setInterval(refresh, refreshIntervalMs)
+    return () => clearInterval(interval)
+  }, [open])
+
+  let onSubmit = async (e: FormEvent) => {
+    e.preventDefault()
+    let cPort = parsePort(containerPort, false)
+    let lPort = localPort.trim() === "" ? 0 : parsePort(localPort, true)
+    if (isNaN(cPort) || isNaN(lPort)) {
+      setError("Ports must be numbers between 1 and 65535")
+      return
+    }
+
+    try {
+      await createPortForward(resource, cPort, lPort)
+      setError("")
+      setContainerPort("")
+      setLocalPort("")
+    } catch (e) {
+      setError(`${e}`)
+    }
+    refresh()
+  }
+
+  let onRemove = async (name: string) => {
+    try {
+      await deletePortForward(name)
+      setError("")
+    } catch (e) {
+      setError(`${e}`)
+    }
+    refresh()
+  }
+
+  let forwardEls = forwards.map((pf) => {
+    let name = pf.metadata?.name || ""
+    let summary = portForwardSummary(pf)
+    return (
+      <ForwardItem key={name}>
+        <div>
+          <div>{summary.text}</div>
+          {summary.error ? <ErrorText>{summary.error}</ErrorText> : null}
+        </div>
+        <RemoveButton
+          aria-label={`Remove port forward ${summary.text}`}
+          analyticsName="ui.web.portForward.remove"
+          onClick={() => onRemove(name)}
+        >
+          <CloseSvg role="presentation" />
+        </RemoveButton>
+      </ForwardItem>
+    )
+  })
+
+  return (
+    <FloatDialog
+      id="port-forward"
+      title="Forward a port"
+      open={open}
+      onClose={onClose}
+      anchorEl={anchorEl}
+    >
+      <Form onSubmit={onSubmit} aria-label="Add port forward">
+        <label htmlFor="port-forward-resource">Resource</label>
+        <select
+          id="port-forward-resource"
+          value={resource}
+          onChange={(e) => setResource(e.target.value)}
+        >
+          {props.resourceNames.map((name) => (
+            <option key={name} value={name}>
+              {name}
+            </option>
+          ))}
+        </select>
+        <label htmlFor="port-forward-container-port">Container port</label>
+        <input
+          id="port-forward-container-port"
+          inputMode="numeric"
+          placeholder="8080"
+          value={containerPort}
+          onChange={(e) => setContainerPort(e.target.value)}
+        />
+        <label htmlFor="port-forward-local-port">Local port</label>
+        <input
+          id="port-forward-local-port"
+          inputMode="numeric"
+          placeholder="any free port"
+          value={localPort}
+          onChange={(e) => setLocalPort(e.target.value)}
+        />
+        <SubmitButton type="submit" analyticsName="ui.web.portForward.add">
+          Add
+        </SubmitButton>
+      </Form>
+      {error ? <ErrorText role="alert">{error}</ErrorText> : null}
+      <HR />
+      {forwardEls.length ? (
+        <ForwardList aria-label="Port forwards">{forwardEls}</ForwardList>
+      ) : (
+        <Empty>No port forwards added in this session</Empty>
+      )}
+    </FloatDialog>
+  )
+}
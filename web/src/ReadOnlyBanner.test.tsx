@@ -0,0 +1,34 @@
+import { readOnlyMessage } from "./ReadOnlyBanner"
+
+describe("ReadOnlyBanner", () => {
+  it("says nothing to owners", () => {
+    expect(readOnlyMessage(null)).toEqual("")
+    expect(readOnlyMessage({ role: "owner", readOnly: false })).toEqual("")
+  })
+
+  it("tells viewers who they're signed in as", () => {
+    expect(
+      readOnlyMessage({
+        name: "dev@example.com",
+        role: "viewer",
+        readOnly: true,
+      })
+    ).toContain("read-only as dev@example.com")
+    expect(readOnlyMessage({ role: "viewer", readOnly: true })).toContain(
+      "read-only."
+    )
+  })
+
+  it("tells operators what they can do", () => {
+    expect(
+      readOnlyMessage({
+        name: "oncall@example.com",
+        role: "operator",
+        readOnly: true,
+        canTrigger: true,
+      })
+    ).toEqual(
+      "Signed in as oncall@example.com with operator access. You can trigger updates, but can't change settings."
+    )
+  })
+})
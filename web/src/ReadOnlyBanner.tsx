@@ -0,0 +1,74 @@
+import React, { useEffect, useState } from "react"
+import styled from "styled-components"
+import { Color, Font, FontSize, SizeUnit, ZIndex } from "./style-helpers"
+
+// Who's looking at this session, as reported by the server.
+// See TeamIdentity in internal/hud/server/team_auth.go
+export type WhoAmI = {
+  name?: string
+  role: "owner" | "operator" | "viewer"
+  readOnly: boolean
+  canTrigger?: boolean
+}
+
+type ReadOnlyBannerProps = {
+  isSnapshot: boolean
+}
+
+export function readOnlyMessage(who: WhoAmI | null): string {
+  if (!who || !who.readOnly) {
+    return ""
+  }
+  let name = who.name ? ` as ${who.name}` : ""
+  if (who.canTrigger) {
+    return `Signed in${name} with operator access. You can trigger updates, but can't change settings.`
+  }
+  return `Watching this session read-only${name}. You can't trigger updates or change settings.`
+}
+
+let BannerRoot = styled.div`
+  position: fixed;
+  z-index: ${ZIndex.SocketBar};
+  bottom: 0;
+  left: 0;
+  width: 100vw;
+  display: flex;
+  justify-content: center;
+  gap: ${SizeUnit(0.5)};
+  padding: ${SizeUnit(0.1)} ${SizeUnit(0.5)};
+  background-color: ${Color.blueDark};
+  color: ${Color.white};
+  font-family: ${Font.sansSerif};
+  font-size: ${FontSize.smallest};
+
+  a {
+    color: ${Color.white};
+  }
+`
+
+// Tells teammates who signed in to watch a session that they can't
+// control it, so the disabled buttons aren't a mystery.
+export default function ReadOnlyBanner(props: ReadOnlyBannerProps) {
+  let [who, setWho] = useState<WhoAmI | null>(null)
+
+  useEffect(() => {
+    if (props.isSnapshot) {
+      return
+    }
+    fetch("/api/whoami", { headers: { Accept: "application/json" } })
+      .then((resp) => (resp.status === 200 ? resp.json() : null))
+      .then((data) => setWho(data))
+      .catch(() => setWho(null))
+  }, [props.isSnapshot])
+
+  let message = readOnlyMessage(who)
+  if (!message) {
+    return null
+  }
+  return (
+    <BannerRoot role="status">
+      <span>{message}</span>
+      <a href="/auth/logout">Sign out</a>
+    </BannerRoot>
+  )
+}
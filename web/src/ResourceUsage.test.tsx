@@ -0,0 +1,25 @@
+import { render, screen } from "@testing-library/react"
+import React from "react"
+import { formatResourceUsage, ResourceUsage } from "./ResourceUsage"
+
+describe("ResourceUsage", () => {
+  it("formats CPU as a percent of a core, and memory in MB", () => {
+    expect(
+      formatResourceUsage({
+        cpuMillicores: "1234",
+        memoryBytes: String(150 * 1024 * 1024),
+      })
+    ).toEqual("CPU 123% · 150 MB")
+    expect(formatResourceUsage({})).toEqual("CPU 0% · 0 MB")
+  })
+
+  it("renders nothing before the first sample", () => {
+    const { container } = render(<ResourceUsage />)
+    expect(container).toBeEmptyDOMElement()
+  })
+
+  it("renders the sample", () => {
+    render(<ResourceUsage usage={{ cpuMillicores: "50", memoryBytes: "0" }} />)
+    expect(screen.getByText("CPU 5% · 0 MB")).toBeInTheDocument()
+  })
+})
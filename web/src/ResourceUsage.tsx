@@ -0,0 +1,34 @@
+import React from "react"
+import styled from "styled-components"
+import { Color, Font, FontSize } from "./style-helpers"
+import TiltTooltip from "./Tooltip"
+import { CmdResourceUsage } from "./types"
+
+const ResourceUsageRoot = styled.div`
+  color: ${Color.gray70};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+  white-space: nowrap;
+`
+
+const bytesPerMB = 1024 * 1024
+
+// Formats a sample of a local command's usage, like "CPU 12% · 143 MB".
+//
+// The API reports int64s as strings.
+export function formatResourceUsage(usage: CmdResourceUsage): string {
+  let cpuPercent = Math.round(Number(usage.cpuMillicores ?? 0) / 10)
+  let megabytes = Math.round(Number(usage.memoryBytes ?? 0) / bytesPerMB)
+  return `CPU ${cpuPercent}% · ${megabytes} MB`
+}
+
+export function ResourceUsage(props: { usage?: CmdResourceUsage }) {
+  if (!props.usage) {
+    return null
+  }
+  return (
+    <TiltTooltip title="CPU and memory used by serve_cmd and its child processes">
+      <ResourceUsageRoot>{formatResourceUsage(props.usage)}</ResourceUsageRoot>
+    </TiltTooltip>
+  )
+}
@@ -0,0 +1,67 @@
+import { act, render } from "@testing-library/react"
+import React, { useState } from "react"
+import {
+  ServerPreferences,
+  serverPreferencesContext,
+  useServerSync,
+} from "./ServerPreferences"
+
+function Starred(props: { initial: string[] }) {
+  let [starred, setStarred] = useState(props.initial)
+  useServerSync("starred", starred, setStarred, [])
+  return (
+    <button onClick={() => setStarred([...starred, "be"])}>
+      {starred.join(",")}
+    </button>
+  )
+}
+
+function renderWithPrefs(
+  values: { [name: string]: string } | null,
+  initial: string[]
+) {
+  let saved: { [name: string]: string } = {}
+  let prefs: ServerPreferences = {
+    values,
+    set: (name, value) => {
+      saved[name] = value
+    },
+  }
+  let result = render(
+    <serverPreferencesContext.Provider value={prefs}>
+      <Starred initial={initial} />
+    </serverPreferencesContext.Provider>
+  )
+  return { ...result, saved }
+}
+
+describe("useServerSync", () => {
+  it("prefers the value saved on the server", () => {
+    let { container, saved } = renderWithPrefs({ starred: `["fe"]` }, [])
+    expect(container.textContent).toEqual("fe")
+    expect(saved).toEqual({})
+  })
+
+  it("saves browser values the server doesn't have", () => {
+    let { saved } = renderWithPrefs({}, ["fe"])
+    expect(saved).toEqual({ starred: `["fe"]` })
+  })
+
+  it("doesn't save defaults", () => {
+    let { saved } = renderWithPrefs({}, [])
+    expect(saved).toEqual({})
+  })
+
+  it("saves changes", () => {
+    let { container, saved } = renderWithPrefs({}, [])
+    act(() => {
+      container.querySelector("button")!.click()
+    })
+    expect(saved).toEqual({ starred: `["be"]` })
+  })
+
+  it("does nothing until preferences load", () => {
+    let { saved } = renderWithPrefs(null, ["fe"])
+    expect(saved).toEqual({})
+  })
+})
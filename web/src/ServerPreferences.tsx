@@ -0,0 +1,124 @@
+import React, {
+  Dispatch,
+  PropsWithChildren,
+  SetStateAction,
+  useContext,
+  useEffect,
+  useRef,
+  useState,
+} from "react"
+
+// Preferences saved on the Tilt server, by name, as JSON.
+//
+// Null until they load, or if the server can't save preferences
+// (e.g., in a snapshot).
+type PreferenceValues = { [name: string]: string }
+
+export type ServerPreferences = {
+  values: PreferenceValues | null
+  set: (name: string, value: string) => void
+}
+
+export const serverPreferencesContext = React.createContext<ServerPreferences>(
+  { values: null, set: () => {} }
+)
+
+async function fetchPreferences(): Promise<PreferenceValues> {
+  const resp = await fetch("/api/preferences", {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching preferences: ${body}`
+  }
+  const data = await resp.json()
+  let values: PreferenceValues = {}
+  Object.entries(data.values || {}).forEach(([name, value]) => {
+    values[name] = JSON.stringify(value)
+  })
+  return values
+}
+
+function savePreference(name: string, value: string) {
+  fetch(`/api/preferences/${encodeURIComponent(name)}`, {
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    body: value,
+  })
+    .then(async (resp) => {
+      if (resp.status !== 200) {
+        const body = await resp.text()
+        throw `error saving preference ${name}: ${body}`
+      }
+    })
+    .catch((err) => console.error(err))
+}
+
+// Loads the user's preferences from the server, so that their view options
+// follow them across browsers.
+export function ServerPreferencesProvider(
+  props: PropsWithChildren<{ disabled?: boolean }>
+) {
+  let [values, setValues] = useState<PreferenceValues | null>(null)
+
+  useEffect(() => {
+    if (props.disabled) {
+      return
+    }
+    fetchPreferences()
+      .then((values) => setValues(values))
+      .catch((err) => console.error(err))
+  }, [props.disabled])
+
+  let set = (name: string, value: string) => {
+    setValues((values) => (values ? { ...values, [name]: value } : values))
+    savePreference(name, value)
+  }
+
+  return (
+    <serverPreferencesContext.Provider value={{ values, set }}>
+      {props.children}
+    </serverPreferencesContext.Provider>
+  )
+}
+
+// Keeps state from browser storage in sync with the server.
+//
+// When the server's preferences load, a value saved on the server wins
+// over the one in the browser. If the server doesn't have one yet, we save
+// the browser's, so that existing preferences carry over. After that,
+// every change gets saved to the server.
+export function useServerSync<S>(
+  name: string | null,
+  state: S,
+  setState: Dispatch<SetStateAction<S>>,
+  defaultValue: S
+) {
+  const prefs = useContext(serverPreferencesContext)
+  const syncedName = useRef<string | null>(null)
+  const serverValue = prefs.values && name ? prefs.values[name] : undefined
+  const localValue = JSON.stringify(state)
+
+  useEffect(() => {
+    if (!prefs.values || !name) {
+      return
+    }
+
+    if (syncedName.current !== name) {
+      syncedName.current = name
+      if (serverValue !== undefined) {
+        if (serverValue !== localValue) {
+          setState(JSON.parse(serverValue))
+        }
+        return
+      }
+      if (localValue === JSON.stringify(defaultValue)) {
+        return
+      }
+    }
+
+    if (serverValue !== localValue) {
+      prefs.set(name, localValue)
+    }
+  }, [prefs.values !== null, name, serverValue, localValue])
+}
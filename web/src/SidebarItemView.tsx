@@ -1,6 +1,7 @@
 import React, { MutableRefObject, useEffect, useRef } from "react"
 import TimeAgo from "react-timeago"
 import styled from "styled-components"
+import { AlertBadge } from "./AlertRules"
 import { Hold } from "./Hold"
 import PathBuilder from "./PathBuilder"
 import { useResourceNav } from "./ResourceNav"
@@ -186,6 +187,7 @@ let SidebarItemName = (props: { name: string }) => {
   return (
     <SidebarItemNameRoot title={props.name}>
       <SidebarItemNameTruncate>{props.name}</SidebarItemNameTruncate>
+      <AlertBadge resourceName={props.name} />
     </SidebarItemNameRoot>
   )
 }
@@ -1,5 +1,5 @@
 import moment from "moment"
-import React from "react"
+import React, { useEffect, useState } from "react"
 import styled from "styled-components"
 import { AnalyticsType } from "./analytics"
 import { usePathBuilder } from "./PathBuilder"
@@ -28,9 +28,70 @@ const SnapshotTitle = styled.span`
   text-decoration: underline;
 `
 
+const ReplayControls = styled.span`
+  margin-left: ${SizeUnit(0.5)};
+
+  input {
+    margin: 0 ${SizeUnit(0.25)};
+    vertical-align: middle;
+  }
+`
+
+// One update in a session served by `tilt replay`.
+// See replayFrame in internal/snapshots/replay.go
+export type ReplayFrame = {
+  id: string
+  time: string
+}
+
+// Lets the user step through the updates of a recorded session.
+// Snapshots that aren't recordings have no frames, so this renders nothing.
+function SnapshotReplayControls(props: { loadFrame: (id: string) => void }) {
+  const [frames, setFrames] = useState<ReplayFrame[]>([])
+  const [index, setIndex] = useState(0)
+
+  useEffect(() => {
+    fetch("/api/replay", { headers: { Accept: "application/json" } })
+      .then((resp) => (resp.status === 200 ? resp.json() : null))
+      .then((data) => {
+        let frames: ReplayFrame[] = data?.frames ?? []
+        setFrames(frames)
+        setIndex(frames.length - 1)
+      })
+      .catch(() => setFrames([]))
+  }, [])
+
+  if (frames.length < 2) {
+    return null
+  }
+
+  let onChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    let i = Number(e.target.value)
+    setIndex(i)
+    props.loadFrame(frames[i].id)
+  }
+
+  return (
+    <ReplayControls>
+      <label>
+        Replay
+        <input
+          type="range"
+          aria-label="Replay update"
+          min={0}
+          max={frames.length - 1}
+          value={index}
+          onChange={onChange}
+        />
+      </label>
+      update {index + 1} of {frames.length}
+    </ReplayControls>
+  )
+}
+
 export function SnapshotBar(props: { className?: string }) {
   const pb = usePathBuilder()
-  const { currentSnapshotTime } = useSnapshotAction()
+  const { currentSnapshotTime, loadReplayFrame } = useSnapshotAction()
 
   const isSnapshot = pb.isSnapshot()
   if (!isSnapshot) {
@@ -49,6 +110,9 @@ export function SnapshotBar(props: { className?: string }) {
   return (
     <SnapshotBanner role="status" className={props.className}>
       <SnapshotTitle>Snapshot</SnapshotTitle> {timestampDescription}
+      {loadReplayFrame ? (
+        <SnapshotReplayControls loadFrame={loadReplayFrame} />
+      ) : null}
     </SnapshotBanner>
   )
 }
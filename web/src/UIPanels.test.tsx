@@ -0,0 +1,97 @@
+import { render, screen } from "@testing-library/react"
+import userEvent from "@testing-library/user-event"
+import React from "react"
+import { ApiButtonType } from "./ApiButton"
+import { UIPanel } from "./types"
+import { panelsForComponent, parseMarkdown, UIPanelTabs } from "./UIPanels"
+
+function panel(
+  name: string,
+  componentType: string,
+  componentID: string,
+  title: string,
+  order?: number
+): UIPanel {
+  return {
+    metadata: { name },
+    spec: {
+      location: { componentType, componentID },
+      title,
+      markdown: `# ${title}`,
+      order,
+    },
+  }
+}
+
+describe("UIPanels", () => {
+  it("filters and sorts panels by location", () => {
+    let panels = [
+      panel("flags", "Resource", "api", "Flags"),
+      panel("db", "Resource", "api", "Database", 1),
+      panel("admin", "Resource", "api", "Admin"),
+      panel("other", "Resource", "web", "Other"),
+      panel("readme", "Global", "nav", "README"),
+    ]
+
+    expect(
+      panelsForComponent(panels, ApiButtonType.Resource, "api").map(
+        (p) => p.metadata?.name
+      )
+    ).toEqual(["admin", "flags", "db"])
+    expect(
+      panelsForComponent(panels, ApiButtonType.Global, "").map(
+        (p) => p.metadata?.name
+      )
+    ).toEqual(["readme"])
+  })
+
+  it("parses markdown", () => {
+    let md = [
+      "# Database",
+      "",
+      "Connect with",
+      "`psql`.",
+      "",
+      "- one",
+      "- two",
+      "```",
+      "SELECT 1;",
+      "```",
+    ].join("\n")
+    expect(parseMarkdown(md)).toEqual([
+      { type: "heading", level: 1, text: "Database" },
+      { type: "paragraph", text: "Connect with `psql`." },
+      { type: "list", items: ["one", "two"] },
+      { type: "code", text: "SELECT 1;" },
+    ])
+  })
+
+  it("renders no tab bar without panels", () => {
+    render(
+      <UIPanelTabs panels={[]} defaultTabLabel="Logs">
+        <div>logs here</div>
+      </UIPanelTabs>
+    )
+    expect(screen.queryByRole("tablist")).toBeNull()
+    expect(screen.getByText("logs here")).toBeInTheDocument()
+  })
+
+  it("switches between panels", () => {
+    render(
+      <UIPanelTabs
+        panels={[panel("db", "Resource", "api", "Database")]}
+        defaultTabLabel="Logs"
+      >
+        <div>logs here</div>
+      </UIPanelTabs>
+    )
+    userEvent.click(screen.getByRole("tab", { name: "Database" }))
+    expect(screen.queryByText("logs here")).toBeNull()
+    expect(
+      screen.getByRole("heading", { name: "Database" })
+    ).toBeInTheDocument()
+
+    userEvent.click(screen.getByRole("tab", { name: "Logs" }))
+    expect(screen.getByText("logs here")).toBeInTheDocument()
+  })
+})
@@ -0,0 +1,334 @@
+import React, { ReactNode, useEffect, useState } from "react"
+import styled from "styled-components"
+import { ApiButtonType } from "./ApiButton"
+import {
+  Color,
+  Font,
+  FontSize,
+  mixinResetButtonStyle,
+  SizeUnit,
+} from "./style-helpers"
+import { UIPanel } from "./types"
+
+// How often to check for panels that extensions added or removed.
+const panelPollIntervalMs = 5000
+
+async function fetchUIPanels(): Promise<UIPanel[]> {
+  const resp = await fetch("/proxy/apis/tilt.dev/v1alpha1/uipanels", {
+    headers: { Accept: "application/json" },
+  })
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching panels: ${body}`
+  }
+  const list = await resp.json()
+  return list.items || []
+}
+
+// The panels to show in a location, in tab order.
+//
+// Global panels show up on every overview, no matter their component ID.
+export function panelsForComponent(
+  panels: UIPanel[],
+  componentType: ApiButtonType,
+  componentID: string
+): UIPanel[] {
+  let result = panels.filter((p) => {
+    const panelType = p.spec?.location?.componentType || ""
+    if (panelType.toUpperCase() !== componentType.toUpperCase()) {
+      return false
+    }
+    return (
+      componentType === ApiButtonType.Global ||
+      p.spec?.location?.componentID === componentID
+    )
+  })
+  return result.sort((a, b) => {
+    let orderA = a.spec?.order || 0
+    let orderB = b.spec?.order || 0
+    if (orderA !== orderB) {
+      return orderA - orderB
+    }
+    return (a.spec?.title || "").localeCompare(b.spec?.title || "")
+  })
+}
+
+// Keeps the list of panels up to date.
+export function useUIPanels(): UIPanel[] {
+  let [panels, setPanels] = useState<UIPanel[]>([])
+  useEffect(() => {
+    let cancelled = false
+    let load = () => {
+      fetchUIPanels()
+        .then((panels) => {
+          if (!cancelled) {
+            setPanels(panels)
+          }
+        })
+        .catch((err) => console.error(err))
+    }
+    load()
+    let interval = setInterval(load, panelPollIntervalMs)
+    return () => {
+      cancelled = true
+      clearInterval(interval)
+    }
+  }, [])
+  return panels
+}
+
+type MarkdownBlock =
+  | { type: "heading"; level: number; text: string }
+  | { type: "paragraph"; text: string }
+  | { type: "list"; items: string[] }
+  | { type: "code"; text: string }
+
+// Splits Markdown into blocks.
+//
+// Panels are meant for short notes and instructions, so we only support
+// headings, paragraphs, bulleted lists, and code blocks. We build React
+// elements instead of HTML, so panel content can't inject scripts.
+export function parseMarkdown(markdown: string): MarkdownBlock[] {
+  let blocks: MarkdownBlock[] = []
+  let paragraph: string[] = []
+  let list: string[] = []
+  let code: string[] | null = null
+
+  let flush = () => {
+    if (paragraph.length) {
+      blocks.push({ type: "paragraph", text: paragraph.join(" ") })
+      paragraph = []
+    }
+    if (list.length) {
+      blocks.push({ type: "list", items: list })
+      list = []
+    }
+  }
+
+  markdown.split("\n").forEach((line) => {
+    if (code !== null) {
+      if (line.trim().startsWith("```")) {
+        blocks.push({ type: "code", text: code.join("\n") })
+        code = null
+      } else {
+        code.push(line)
+      }
+      return
+    }
+
+    let trimmed = line.trim()
+    let heading = trimmed.match(/^(#{1,6})\s+(.*)$/)
+    let item = trimmed.match(/^[-*]\s+(.*)$/)
+    if (trimmed.startsWith("```")) {
+      flush()
+      code = []
+    } else if (heading) {
+      flush()
+      blocks.push({
+        type: "heading",
+        level: heading[1].length,
+        text: heading[2],
+      })
+    } else if (item) {
+      if (paragraph.length) {
+        flush()
+      }
+      list.push(item[1])
+    } else if (trimmed === "") {
+      flush()
+    } else {
+      if (list.length) {
+        flush()
+      }
+      paragraph.push(trimmed)
+    }
+  })
+
+  if (code !== null) {
+    blocks.push({ type: "code", text: (code as string[]).join("\n") })
+  }
+  flush()
+  return blocks
+}
+
+// Renders inline code, bold text, and http(s) links.
+function renderInline(text: string): ReactNode[] {
+  let re = /(`[^`]+`)|(\*\*[^*]+\*\*)|(\[[^\]]+\]\(https?:\/\/[^)\s]+\))/g
+  let result: ReactNode[] = []
+  let last = 0
+  let match: RegExpExecArray | null
+  while ((match = re.exec(text)) !== null) {
+    if (match.index > last) {
+      result.push(text.slice(last, match.index))
+    }
+    let token = match[0]
+    let key = result.length
+    if (match[1]) {
+      result.push(<code key={key}>{token.slice(1, -1)}</code>)
+    } else if (match[2]) {
+      result.push(<strong key={key}>{token.slice(2, -2)}</strong>)
+    } else {
+      let link = token.match(/^\[([^\]]+)\]\(([^)]+)\)$/)!
+      result.push(
+        <a key={key} href={link[2]} target="_blank" rel="noopener noreferrer">
+          {link[1]}
+        </a>
+      )
+    }
+    last = match.index + token.length
+  }
+  if (last < text.length) {
+    result.push(text.slice(last))
+  }
+  return result
+}
+
+export function UIPanelMarkdown(props: { markdown: string }) {
+  let blocks = parseMarkdown(props.markdown)
+  return (
+    <UIPanelMarkdownRoot>
+      {blocks.map((b, i) => {
+        switch (b.type) {
+          case "heading": {
+            let Heading = `h${b.level}` as keyof JSX.IntrinsicElements
+            return <Heading key={i}>{renderInline(b.text)}</Heading>
+          }
+          case "list":
+            return (
+              <ul key={i}>
+                {b.items.map((item, j) => (
+                  <li key={j}>{renderInline(item)}</li>
+                ))}
+              </ul>
+            )
+          case "code":
+            return <pre key={i}>{b.text}</pre>
+          default:
+            return <p key={i}>{renderInline(b.text)}</p>
+        }
+      })}
+    </UIPanelMarkdownRoot>
+  )
+}
+
+let UIPanelMarkdownRoot = styled.div`
+  flex: 1;
+  overflow: auto;
+  padding: ${SizeUnit(0.5)} ${SizeUnit(1)};
+  color: ${Color.gray70};
+  font-family: ${Font.sansSerif};
+  font-size: ${FontSize.small};
+
+  a {
+    color: ${Color.blue};
+  }
+  code,
+  pre {
+    font-family: ${Font.monospace};
+  }
+  pre {
+    background-color: ${Color.gray10};
+    padding: ${SizeUnit(0.25)};
+    overflow: auto;
+  }
+`
+
+let UIPanelFrame = styled.iframe`
+  flex: 1;
+  width: 100%;
+  border: 0;
+  background-color: ${Color.white};
+`
+
+export function UIPanelContent(props: { panel: UIPanel }) {
+  let spec = props.panel.spec
+  if (spec?.url) {
+    return (
+      <UIPanelFrame
+        src={spec.url}
+        title={spec.title || props.panel.metadata?.name}
+      />
+    )
+  }
+  return <UIPanelMarkdown markdown={spec?.markdown || ""} />
+}
+
+let UIPanelTabsRoot = styled.div`
+  display: flex;
+  flex-direction: column;
+  flex: 1;
+  min-width: 0;
+  min-height: 0;
+`
+
+let UIPanelTabBar = styled.div`
+  display: flex;
+  border-bottom: 1px solid ${Color.gray40};
+  background-color: ${Color.gray10};
+`
+
+let UIPanelTab = styled.button`
+  ${mixinResetButtonStyle};
+  color: ${Color.gray60};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  padding: ${SizeUnit(0.2)} ${SizeUnit(0.5)};
+
+  &:hover {
+    color: ${Color.gray70};
+  }
+
+  &.isSelected {
+    color: ${Color.white};
+    border-bottom: 2px solid ${Color.blue};
+  }
+`
+
+type UIPanelTabsProps = {
+  panels: UIPanel[]
+  // The label of the tab that shows the children, like "Logs".
+  defaultTabLabel: string
+  children: ReactNode
+}
+
+// Puts the panels in tabs next to the default view.
+//
+// If there are no panels, there's no tab bar.
+export function UIPanelTabs(props: UIPanelTabsProps) {
+  let [selected, setSelected] = useState("")
+  let panel = props.panels.find((p) => p.metadata?.name === selected)
+  if (props.panels.length === 0) {
+    return <>{props.children}</>
+  }
+
+  return (
+    <UIPanelTabsRoot>
+      <UIPanelTabBar role="tablist" aria-label="Panels">
+        <UIPanelTab
+          role="tab"
+          aria-selected={!panel}
+          className={!panel ? "isSelected" : ""}
+          onClick={() => setSelected("")}
+        >
+          {props.defaultTabLabel}
+        </UIPanelTab>
+        {props.panels.map((p) => {
+          let name = p.metadata?.name || ""
+          let isSelected = name === panel?.metadata?.name
+          return (
+            <UIPanelTab
+              key={name}
+              role="tab"
+              aria-selected={isSelected}
+              className={isSelected ? "isSelected" : ""}
+              onClick={() => setSelected(name)}
+            >
+              {p.spec?.title || name}
+            </UIPanelTab>
+          )
+        })}
+      </UIPanelTabBar>
+      {panel ? <UIPanelContent panel={panel} /> : props.children}
+    </UIPanelTabsRoot>
+  )
+}
@@ -21,7 +21,10 @@ export enum AnalyticsType {
   Account = "account",
   Cluster = "cluster",
   Detail = "resource-detail",
+  Graph = "graph",
   Grid = "grid", // aka Table View
+  History = "build-history",
+  LogSearch = "log-search",
   Shortcut = "shortcuts",
   Unknown = "unknown",
   Update = "update",
@@ -11,11 +11,13 @@ export type SnapshotAction = {
     tiltUpTime?: string
     createdAt?: string
   }
+  // Set when viewing a snapshot, to step through a `tilt replay` session.
+  loadReplayFrame?: (id: string) => void
 }
 
 export type SnapshotProviderProps = Pick<
   SnapshotAction,
-  "openModal" | "currentSnapshotTime"
+  "openModal" | "currentSnapshotTime" | "loadReplayFrame"
 >
 
 const snapshotActionContext = React.createContext<SnapshotAction>({
@@ -41,8 +43,9 @@ export function SnapshotActionProvider(
       enabled: showSnapshot,
       openModal: openModal,
       currentSnapshotTime: props.currentSnapshotTime,
+      loadReplayFrame: props.loadReplayFrame,
     }
-  }, [showSnapshot, openModal])
+  }, [showSnapshot, openModal, props.loadReplayFrame])
 
   return (
     <snapshotActionContext.Provider value={snapshotAction}>
@@ -113,6 +113,15 @@ export type LogLine = {
   buildEvent?: string
   spanId: string
 
+  // Set on the line that says how much of a resource's log was truncated.
+  // "spilled" if the truncated logs were saved and can be loaded,
+  // "dropped" otherwise.
+  truncated?: string
+
+  // "1" if Tilt already turned the line's ANSI codes into HTML
+  // (log.ansi(resource, 'html') in the Tiltfile).
+  ansiHTML?: string
+
   // The index of this line in the LogStore StoredLine list.
   storedLineIndex: number
 }
@@ -154,6 +163,10 @@ export type UIBuild = Proto.v1alpha1UIBuildTerminated
 export type UILink = Proto.v1alpha1UIResourceLink
 export type UIButton = Proto.v1alpha1UIButton
 export type UIButtonStatus = Proto.v1alpha1UIButtonStatus
+export type UIPanel = Proto.v1alpha1UIPanel
+export type AlertRule = Proto.v1alpha1AlertRule
 export type UIInputSpec = Proto.v1alpha1UIInputSpec
 export type UIInputStatus = Proto.v1alpha1UIInputStatus
 export type Cluster = Proto.v1alpha1Cluster
+export type PortForward = Proto.v1alpha1PortForward
+export type CmdResourceUsage = Proto.v1alpha1CmdResourceUsage
@@ -414,6 +414,27 @@ declare namespace Proto {
     fatalError?: string;
     tiltStartTime?: string;
     tiltfileKey?: string;
+    /**
+     * ResourceGroups lists every resource group, including parent groups
+     * that have no resources of their own, in the order the UI should
+     * display them.
+     *
+     * Each group comes right before its subgroups.
+     *
+     * +optional
+     */
+    resourceGroups?: v1alpha1UIResourceGroup[];
+  }
+  export interface v1alpha1UIResourceGroup {
+    /**
+     * The path of the group, as labels separated by '/' (e.g., "payments/api").
+     */
+    path?: string;
+    /**
+     * Whether the user collapsed the group in the resource list.
+     * +optional
+     */
+    collapsed?: boolean;
   }
   export interface v1alpha1UISessionSpec {}
   export interface v1alpha1UISession {
@@ -484,6 +505,16 @@ declare namespace Proto {
      * +optional
      */
     conditions?: v1alpha1UIResourceCondition[];
+    /**
+     * Groups lists the resource groups this resource belongs to, sorted.
+     *
+     * Each group is a path of labels separated by '/', from the outermost
+     * group to the innermost (e.g., "payments/api"). Only the outermost
+     * group of each path appears in the object's labels.
+     *
+     * +optional
+     */
+    groups?: string[];
   }
   export interface v1alpha1UIResourceStateWaitingOnRef {
     /**
@@ -530,6 +561,103 @@ declare namespace Proto {
      * +optional
      */
     isTest?: boolean;
+    /**
+     * Resources used by the actively running local command.
+     *
+     * +optional
+     */
+    usage?: v1alpha1CmdResourceUsage;
+  }
+  export interface v1alpha1CmdResourceUsage {
+    /**
+     * CPU use since the previous sample, in thousandths of a core, like 1500
+     * for one and a half cores.
+     */
+    cpuMillicores?: string;
+    /**
+     * Resident memory, in bytes.
+     */
+    memoryBytes?: string;
+  }
+  export interface v1alpha1BuildHistoryRecord {
+    /**
+     * The time when the build started.
+     */
+    startTime?: string;
+    /**
+     * The time when the build finished.
+     */
+    finishTime?: string;
+    /**
+     * The error message, if the build failed.
+     * +optional
+     */
+    error?: string;
+    /**
+     * The files whose changes triggered the build.
+     * +optional
+     */
+    edits?: string[];
+    /**
+     * Why the build happened, e.g., "Changed Files" or "Manual Trigger".
+     * +optional
+     */
+    reason?: string;
+    /**
+     * The kinds of work the build did, e.g., "image", "live-update", or "k8s".
+     * +optional
+     */
+    buildTypes?: string[];
+    /**
+     * The start time of the Tilt session that ran the build.
+     *
+     * Use it to tell the builds of the current session from previous ones.
+     */
+    sessionStartTime?: string;
+  }
+  export interface v1alpha1BuildHistoryStatus {
+    /**
+     * Completed builds, oldest first.
+     *
+     * Tilt only keeps the most recent builds of each resource.
+     *
+     * +optional
+     */
+    builds?: v1alpha1BuildHistoryRecord[];
+  }
+  export interface v1alpha1BuildHistorySpec {}
+  export interface v1alpha1BuildHistory {
+    metadata?: v1ObjectMeta;
+    spec?: v1alpha1BuildHistorySpec;
+    status?: v1alpha1BuildHistoryStatus;
+  }
+  export interface v1alpha1Forward {
+    localPort?: number;
+    containerPort?: number;
+    host?: string;
+    name?: string;
+    path?: string;
+  }
+  export interface v1alpha1ForwardStatus {
+    localPort?: number;
+    containerPort?: number;
+    addresses?: string[];
+    startedAt?: string;
+    error?: string;
+  }
+  export interface v1alpha1PortForwardSpec {
+    podName?: string;
+    namespace?: string;
+    forwards?: v1alpha1Forward[];
+    cluster?: string;
+  }
+  export interface v1alpha1PortForwardStatus {
+    forwardStatuses?: v1alpha1ForwardStatus[];
+  }
+  export interface v1alpha1PortForward {
+    metadata?: v1ObjectMeta;
+    spec?: v1alpha1PortForwardSpec;
+    status?: v1alpha1PortForwardStatus;
   }
   export interface v1alpha1UIResourceLink {
     url?: string;
@@ -682,6 +810,39 @@ declare namespace Proto {
     spec?: v1alpha1UIButtonSpec;
     status?: v1alpha1UIButtonStatus;
   }
+  export interface v1alpha1UIPanel {
+    metadata?: v1ObjectMeta;
+    spec?: v1alpha1UIPanelSpec;
+  }
+  export interface v1alpha1UIPanelSpec {
+    location?: v1alpha1UIComponentLocation;
+    title?: string;
+    url?: string;
+    markdown?: string;
+    order?: number;
+  }
+  export interface v1alpha1AlertRule {
+    metadata?: v1ObjectMeta;
+    spec?: v1alpha1AlertRuleSpec;
+    status?: v1alpha1AlertRuleStatus;
+  }
+  export interface v1alpha1AlertRuleSpec {
+    metric?: string;
+    duration?: string;
+    count?: number;
+    window?: string;
+    resources?: string[];
+    message?: string;
+  }
+  export interface v1alpha1AlertRuleStatus {
+    firing?: v1alpha1AlertFiring[];
+  }
+  export interface v1alpha1AlertFiring {
+    resource?: string;
+    value?: string;
+    message?: string;
+    since?: string;
+  }
   export interface v1alpha1UIBuildTerminated {
     error?: string;
     warnings?: string[];